@@ -0,0 +1,286 @@
+// Command gensde writes a synthetic universe in the SDE JSONL layout, plus
+// region order books, for profiling scans on universes far larger than the
+// live one and reproducing reports from users with huge scan radii.
+//
+//	go run ./cmd/gensde -out /tmp/synth -regions 200 -systems 60 -types 20000
+//
+// The output directory can be used as a data directory: sde.Load reads
+// <out>/sde without downloading. Order books are written to
+// <out>/orders/<regionID>.json as arrays of ESI market orders.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+
+	"eve-flipper/internal/esi"
+)
+
+// ID bases keep synthetic IDs in the same ranges as live ones.
+const (
+	regionIDBase  = 10000000
+	systemIDBase  = 30000000
+	stationIDBase = 60000000
+	typeIDBase    = 100000
+	groupIDBase   = 5000
+)
+
+type options struct {
+	Out              string
+	Seed             int64
+	Regions          int
+	SystemsPerRegion int
+	ExtraGates       float64 // extra in-region gates per system
+	StationRatio     float64 // share of systems with a station
+	Types            int
+	Groups           int
+	OrdersPerRegion  int
+}
+
+func main() {
+	var opt options
+	flag.StringVar(&opt.Out, "out", "synthetic-data", "output data directory")
+	flag.Int64Var(&opt.Seed, "seed", 1, "random seed")
+	flag.IntVar(&opt.Regions, "regions", 64, "number of regions")
+	flag.IntVar(&opt.SystemsPerRegion, "systems", 80, "systems per region")
+	flag.Float64Var(&opt.ExtraGates, "extra-gates", 0.5, "extra in-region gates per system")
+	flag.Float64Var(&opt.StationRatio, "station-ratio", 0.5, "share of systems with an NPC station")
+	flag.IntVar(&opt.Types, "types", 15000, "number of market types")
+	flag.IntVar(&opt.Groups, "groups", 200, "number of item groups")
+	flag.IntVar(&opt.OrdersPerRegion, "orders", 50000, "orders per region")
+	flag.Parse()
+
+	if opt.Regions <= 0 || opt.SystemsPerRegion <= 0 || opt.Types <= 0 || opt.Groups <= 0 {
+		log.Fatal("regions, systems, types and groups must be > 0")
+	}
+	stats, err := generate(opt)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Wrote %d regions, %d systems, %d gates, %d stations, %d types, %d orders to %s\n",
+		stats.regions, stats.systems, stats.gates, stats.stations, stats.types, stats.orders, opt.Out)
+}
+
+type genStats struct {
+	regions, systems, gates, stations, types, orders int
+}
+
+// jsonlWriter writes one JSON value per line.
+type jsonlWriter struct {
+	f   *os.File
+	buf *bufio.Writer
+	enc *json.Encoder
+	err error
+}
+
+func createJSONL(dir, name string) (*jsonlWriter, error) {
+	f, err := os.Create(filepath.Join(dir, name+".jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	buf := bufio.NewWriter(f)
+	return &jsonlWriter{f: f, buf: buf, enc: json.NewEncoder(buf)}, nil
+}
+
+func (w *jsonlWriter) write(v interface{}) {
+	if w.err == nil {
+		w.err = w.enc.Encode(v)
+	}
+}
+
+func (w *jsonlWriter) close() error {
+	if err := w.buf.Flush(); err != nil && w.err == nil {
+		w.err = err
+	}
+	if err := w.f.Close(); err != nil && w.err == nil {
+		w.err = err
+	}
+	return w.err
+}
+
+func enName(name string) map[string]string {
+	return map[string]string{"en": name}
+}
+
+type gateEnd struct {
+	SolarSystemID int32 `json:"solarSystemID"`
+}
+
+type gate struct {
+	Key           int64   `json:"_key"`
+	SolarSystemID int32   `json:"solarSystemID"`
+	Destination   gateEnd `json:"destination"`
+}
+
+// generate writes the synthetic SDE and order books under opt.Out.
+func generate(opt options) (genStats, error) {
+	var st genStats
+	rng := rand.New(rand.NewSource(opt.Seed))
+	sdeDir := filepath.Join(opt.Out, "sde")
+	ordersDir := filepath.Join(opt.Out, "orders")
+	for _, dir := range []string{sdeDir, ordersDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return st, err
+		}
+	}
+
+	files := map[string]*jsonlWriter{}
+	for _, name := range []string{"mapRegions", "mapSolarSystems", "mapStargates", "npcStations", "groups", "types"} {
+		w, err := createJSONL(sdeDir, name)
+		if err != nil {
+			return st, err
+		}
+		files[name] = w
+	}
+	closeAll := func() error {
+		var first error
+		for _, w := range files {
+			if err := w.close(); err != nil && first == nil {
+				first = err
+			}
+		}
+		return first
+	}
+
+	// Universe: each region is a ring of systems with random chords, and
+	// consecutive regions are joined by one gate pair.
+	var gateKey int64
+	addGate := func(a, b int32) {
+		gateKey++
+		files["mapStargates"].write(gate{Key: gateKey, SolarSystemID: a, Destination: gateEnd{b}})
+		gateKey++
+		files["mapStargates"].write(gate{Key: gateKey, SolarSystemID: b, Destination: gateEnd{a}})
+		st.gates++
+	}
+	systemsByRegion := make([][]int32, opt.Regions)
+	stationsByRegion := make([][][2]int64, opt.Regions) // {stationID, systemID}
+	for r := 0; r < opt.Regions; r++ {
+		regionID := int32(regionIDBase + r + 1)
+		files["mapRegions"].write(map[string]interface{}{"_key": regionID, "name": enName(fmt.Sprintf("Synth Region %d", r+1))})
+		st.regions++
+		for i := 0; i < opt.SystemsPerRegion; i++ {
+			systemID := int32(systemIDBase + r*opt.SystemsPerRegion + i + 1)
+			files["mapSolarSystems"].write(map[string]interface{}{
+				"_key":     systemID,
+				"name":     enName(fmt.Sprintf("SYN-%d-%d", r+1, i+1)),
+				"regionID": regionID,
+				"security": float64(rng.Intn(21)-5) / 15, // -0.33 .. 1.0
+				"position": map[string]float64{
+					"x": (rng.Float64() - 0.5) * 1e18,
+					"y": (rng.Float64() - 0.5) * 1e17,
+					"z": (rng.Float64() - 0.5) * 1e18,
+				},
+			})
+			systemsByRegion[r] = append(systemsByRegion[r], systemID)
+			st.systems++
+			if rng.Float64() < opt.StationRatio || i == 0 {
+				stationID := int64(stationIDBase + st.stations + 1)
+				files["npcStations"].write(map[string]interface{}{
+					"_key": stationID, "solarSystemID": systemID, "ownerID": 1000035,
+				})
+				stationsByRegion[r] = append(stationsByRegion[r], [2]int64{stationID, int64(systemID)})
+				st.stations++
+			}
+		}
+		systems := systemsByRegion[r]
+		for i := 1; i < len(systems); i++ {
+			addGate(systems[i-1], systems[i])
+		}
+		if len(systems) > 2 {
+			addGate(systems[len(systems)-1], systems[0])
+			for n := int(float64(len(systems)) * opt.ExtraGates); n > 0; n-- {
+				a, b := systems[rng.Intn(len(systems))], systems[rng.Intn(len(systems))]
+				if a != b {
+					addGate(a, b)
+				}
+			}
+		}
+		if r > 0 {
+			prev := systemsByRegion[r-1]
+			addGate(prev[rng.Intn(len(prev))], systems[rng.Intn(len(systems))])
+		}
+	}
+
+	// Types spread over groups of the common market categories.
+	categories := []int32{4, 6, 7, 8, 18, 25, 43}
+	for g := 0; g < opt.Groups; g++ {
+		files["groups"].write(map[string]interface{}{
+			"_key":       groupIDBase + g + 1,
+			"name":       enName(fmt.Sprintf("Synth Group %d", g+1)),
+			"categoryID": categories[g%len(categories)],
+		})
+	}
+	basePrice := make([]float64, opt.Types)
+	for t := 0; t < opt.Types; t++ {
+		files["types"].write(map[string]interface{}{
+			"_key":          typeIDBase + t + 1,
+			"name":          enName(fmt.Sprintf("Synth Item %d", t+1)),
+			"volume":        0.01 * float64(1+rng.Intn(10000)),
+			"published":     true,
+			"marketGroupID": 1,
+			"groupID":       groupIDBase + t%opt.Groups + 1,
+		})
+		basePrice[t] = 10 * float64(1+rng.Intn(1000000))
+		st.types++
+	}
+	if err := closeAll(); err != nil {
+		return st, err
+	}
+
+	// Order books: random types at random stations of the region, buys
+	// below and sells above the type's base price.
+	var orderID int64
+	for r := 0; r < opt.Regions; r++ {
+		regionID := int32(regionIDBase + r + 1)
+		stations := stationsByRegion[r]
+		orders := make([]esi.MarketOrder, 0, opt.OrdersPerRegion)
+		for n := 0; n < opt.OrdersPerRegion; n++ {
+			t := rng.Intn(opt.Types)
+			loc := stations[rng.Intn(len(stations))]
+			isBuy := rng.Intn(2) == 0
+			price := basePrice[t] * (1.02 + rng.Float64()*0.3)
+			if isBuy {
+				price = basePrice[t] * (0.7 + rng.Float64()*0.3)
+			}
+			orderID++
+			orders = append(orders, esi.MarketOrder{
+				OrderID:      orderID,
+				TypeID:       int32(typeIDBase + t + 1),
+				LocationID:   loc[0],
+				SystemID:     int32(loc[1]),
+				Price:        float64(int64(price*100)) / 100,
+				VolumeRemain: int32(1 + rng.Intn(5000)),
+				MinVolume:    1,
+				IsBuyOrder:   isBuy,
+			})
+		}
+		if err := writeJSONFile(filepath.Join(ordersDir, fmt.Sprintf("%d.json", regionID)), orders); err != nil {
+			return st, err
+		}
+		st.orders += len(orders)
+	}
+	return st, nil
+}
+
+func writeJSONFile(path string, v interface{}) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	buf := bufio.NewWriter(f)
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		f.Close()
+		return err
+	}
+	if err := buf.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
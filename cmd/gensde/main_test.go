@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/sde"
+)
+
+func TestGenerateLoadsAsSDE(t *testing.T) {
+	dir := t.TempDir()
+	st, err := generate(options{
+		Out: dir, Seed: 7, Regions: 3, SystemsPerRegion: 5, ExtraGates: 0.5,
+		StationRatio: 0.5, Types: 20, Groups: 4, OrdersPerRegion: 30,
+	})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if st.regions != 3 || st.systems != 15 || st.types != 20 || st.orders != 90 {
+		t.Fatalf("stats = %+v", st)
+	}
+
+	data, err := sde.Load(dir)
+	if err != nil {
+		t.Fatalf("sde.Load: %v", err)
+	}
+	if len(data.Regions) != 3 || len(data.Systems) != 15 || len(data.Types) != 20 || len(data.Stations) != st.stations {
+		t.Fatalf("loaded regions=%d systems=%d types=%d stations=%d",
+			len(data.Regions), len(data.Systems), len(data.Types), len(data.Stations))
+	}
+	// Regions are chained, so the whole universe is connected.
+	if d := data.Universe.ShortestPath(30000001, 30000015); d < 0 {
+		t.Fatalf("first and last system are not connected")
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, "orders", "10000002.json"))
+	if err != nil {
+		t.Fatalf("read orders: %v", err)
+	}
+	var orders []esi.MarketOrder
+	if err := json.Unmarshal(raw, &orders); err != nil || len(orders) != 30 {
+		t.Fatalf("orders = %d (%v), want 30", len(orders), err)
+	}
+	for _, o := range orders {
+		sys, ok := data.Systems[o.SystemID]
+		if !ok || sys.RegionID != 10000002 || data.Stations[o.LocationID] == nil {
+			t.Fatalf("order %d placed outside its region: %+v", o.OrderID, o)
+		}
+	}
+}
@@ -85,6 +85,7 @@ import type {
   RouteResult,
   ScanParams,
   StationCacheMeta,
+  OrderSlotCheck,
   StationTrade,
 } from "./lib/types";
 import logo from "./assets/logo.svg";
@@ -1229,6 +1230,9 @@ function App() {
   }, [params, alertChannels, alertTelegramToken, alertTelegramChatID, alertDiscordWebhook]);
 
   const handleScan = useCallback(async () => {
+    const warnOrderSlots = (check: OrderSlotCheck) => {
+      if (check.exceeds && check.warning) addToast(check.warning, "warning", 5000);
+    };
     if (scanning) {
       const controller = scanLifecycleRef.current.currentController;
       invalidateScanRequest(scanLifecycleRef.current);
@@ -1417,6 +1421,7 @@ function App() {
           (m) => {
             meta = m;
           },
+          warnOrderSlots,
         );
         if (!isCurrentScan()) return;
         setRadiusResults(results);
@@ -1442,6 +1447,8 @@ function App() {
           (m) => {
             meta = m;
           },
+          undefined,
+          warnOrderSlots,
         );
         if (!isCurrentScan()) return;
         const normalizedRows = normalizeRegionalResults(rows as unknown[]);
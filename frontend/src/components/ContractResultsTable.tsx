@@ -97,6 +97,7 @@ const baseColumnDefs: ContractColumnDef[] = [
   { key: "LiquidationSystemName", labelKey: "colContractLiqSystem", width: "min-w-[140px]", numeric: false },
   { key: "ItemCount", labelKey: "colItems", width: "min-w-[70px]", numeric: true },
   { key: "ProfitPerJump", labelKey: "colContractPPJ", width: "min-w-[110px]", numeric: true },
+  { key: "ProfitPerHour", labelKey: "colContractPPH", width: "min-w-[110px]", numeric: true },
   { key: "Jumps", labelKey: "colContractJumps", width: "min-w-[60px]", numeric: true },
 ];
 
@@ -696,7 +697,8 @@ export function ContractResultsTable({
       col.key === "MarketValue" ||
       col.key === "Profit" ||
       col.key === "ExpectedProfit" ||
-      col.key === "ProfitPerJump"
+      col.key === "ProfitPerJump" ||
+      col.key === "ProfitPerHour"
     ) {
       return formatISK(val as number);
     }
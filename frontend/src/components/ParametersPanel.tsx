@@ -50,6 +50,18 @@ const inputClass =
 
 const PERSIST_KEY = "eve-settings-expanded:params";
 
+// Ship classes for the ISK/hour travel estimate; values match the backend
+// travel profiles. The empty class is a generic cruiser-sized hauler.
+const SHIP_CLASSES: { value: string; label: string }[] = [
+  { value: "", label: "Custom" },
+  { value: "fast_frigate", label: "Fast frigate" },
+  { value: "sunesis", label: "Sunesis" },
+  { value: "industrial", label: "Industrial" },
+  { value: "blockade_runner", label: "Blockade runner" },
+  { value: "deep_space_transport", label: "Deep space transport" },
+  { value: "freighter", label: "Freighter" },
+];
+
 // EVE Online item categories for the regional day trader category filter.
 // IDs are stable SDE constants. Labels are intentionally concise for chip display.
 const EVE_CATEGORIES: { id: number; label: string; hint: string }[] = [
@@ -176,6 +188,22 @@ export function ParametersPanel({
     onChange({ ...params, [key]: value });
   };
 
+  const shipClassField = (
+    <Field label={t("paramsShipClass")} hint={t("paramsShipClassHint")}>
+      <select
+        value={params.ship_class ?? ""}
+        onChange={(e) => set("ship_class", e.target.value)}
+        className={inputClass}
+      >
+        {SHIP_CLASSES.map((c) => (
+          <option key={c.value} value={c.value}>
+            {c.label}
+          </option>
+        ))}
+      </select>
+    </Field>
+  );
+
   const setSourceRegionMode = (mode: SourceRegionMode) => {
     if (tab !== "region") return;
     if (mode === "major_hubs") {
@@ -534,6 +562,7 @@ export function ParametersPanel({
                   <Field label={t("paramsCargo")}>
                     <NumberInput value={params.cargo_capacity} onChange={(v) => set("cargo_capacity", v)} min={0} max={CARGO_INPUT_MAX} />
                   </Field>
+                  {shipClassField}
                 </div>
               </div>
 
@@ -1050,6 +1079,8 @@ export function ParametersPanel({
                   </select>
                 </Field>
 
+                {shipClassField}
+
                 {tab === "radius" && (
                   <Field label={t("restrictToTargetMarket")} hint={t("restrictToTargetMarketHint")}>
                     <label className="h-[34px] px-2.5 py-1.5 bg-eve-input border border-eve-border rounded text-eve-text text-sm flex items-center justify-between cursor-pointer">
@@ -261,6 +261,13 @@ const baseColumnDefs: ColumnDef[] = [
     width: "min-w-[110px]",
     numeric: true,
   },
+  {
+    key: "ProfitPerHour",
+    labelKey: "colProfitPerHour",
+    width: "min-w-[110px]",
+    numeric: true,
+    tooltipKey: "colProfitPerHourHint",
+  },
   {
     key: "TotalJumps",
     labelKey: "colJumps",
@@ -899,6 +906,7 @@ function fmtCell(col: ColumnDef, row: FlipResult): string {
   if (
     col.key === "ExpectedProfit" ||
     col.key === "RealProfit" ||
+    col.key === "ProfitPerHour" ||
     col.key === "ExpectedBuyPrice" ||
     col.key === "ExpectedSellPrice"
   ) {
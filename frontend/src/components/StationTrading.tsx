@@ -1425,6 +1425,9 @@ export function StationTrading({
           (meta) => {
             resultMeta = meta;
           },
+          (check) => {
+            if (check.exceeds && check.warning) addToast(check.warning, "warning", 5000);
+          },
         );
         const normalizedResults = normalizeStationResults(res);
         setCommandRowsByKey({});
@@ -282,7 +282,7 @@ function ActiveOrdersWithDeskTab({ characterScope, orders, formatIsk, t }: Activ
           )}
 
           {deskData?.summary && (
-            <div className="grid grid-cols-2 sm:grid-cols-4 gap-3">
+            <div className={`grid grid-cols-2 gap-3 ${deskData.summary.max_orders ? "sm:grid-cols-5" : "sm:grid-cols-4"}`}>
               <StatCard
                 label={t("charTotalOrders")}
                 value={String(deskData.summary.total_orders)}
@@ -300,6 +300,14 @@ function ActiveOrdersWithDeskTab({ characterScope, orders, formatIsk, t }: Activ
                 subvalue={`${deskData.summary.unknown_eta_count} ${t("orderDeskUnknownETA").toLowerCase()}`}
               />
               <StatCard label={t("orderDeskNotional")} value={`${formatIsk(deskData.summary.total_notional)} ISK`} />
+              {!!deskData.summary.max_orders && (
+                <StatCard
+                  label={t("orderDeskOrderSlots")}
+                  value={String(deskData.summary.free_order_slots)}
+                  subvalue={`${t("orderDeskFreeSlots")} ${deskData.summary.max_orders}`}
+                  color={deskData.summary.free_order_slots > 0 ? "text-eve-profit" : "text-eve-warning"}
+                />
+              )}
             </div>
           )}
 
@@ -1,5 +1,6 @@
 import type {
   AlertHistoryEntry,
+  AllianceDashboard,
   AppConfig,
   AppStatus,
   AuthStatus,
@@ -7,12 +8,19 @@ import type {
   CharacterRoles,
   ContractDetails,
   ContractResult,
+  CorpContract,
   CorpDashboard,
+  CorpDashboardTrends,
   CorpIndustryJob,
+  CorpIndustrySchedule,
   CorpJournalEntry,
   CorpMarketOrderDetail,
   CorpMember,
+  CorpMemberAnalytics,
   CorpMiningEntry,
+  CorpMiningReport,
+  CorpStructure,
+  CorpStructureMonitor,
   DemandRegionResponse,
   DemandRegionsResponse,
   ExecutionQuote,
@@ -41,6 +49,23 @@ import type {
   OptimizerDiagnostic,
   OrderBookCleanupPlan,
   OrderBookCoverageResult,
+  MarketCoverageResult,
+  FitAppraisalResult,
+  MarketAnomalyFeed,
+  ExecutionSchedule,
+  OrderSlotCheck,
+  StationFee,
+  ShipProfile,
+  PinnedRoute,
+  RepriceStrategy,
+  BuyOrderPlan,
+  PortfolioExposureResponse,
+  WatchlistCadenceResult,
+  MarketCompetitorsResult,
+  CompetitorWatch,
+  SpeculationBasket,
+  SpeculationBasketChart,
+  SpeculationBasketInput,
   OrderBookStats,
   OrderDeskResponse,
   PaperTrade,
@@ -70,6 +95,8 @@ import type {
   TradingEdgeSummary,
   UndercutStatus,
   WatchlistItem,
+  WatchlistImportResult,
+  WatchlistExport,
   SystemDanger,
   KillSummary,
   RouteSafetySummary,
@@ -208,7 +235,14 @@ export interface AchievementPatchResponse {
 // Generic NDJSON message type
 type NdjsonGenericMessage<T> =
   | { type: "progress"; message: string }
-  | { type: "result"; data: T[]; count?: number; scan_id?: number; cache_meta?: StationCacheMeta }
+  | {
+      type: "result";
+      data: T[];
+      count?: number;
+      scan_id?: number;
+      cache_meta?: StationCacheMeta;
+      order_slots?: OrderSlotCheck | null;
+    }
   | { type: "error"; message: string };
 
 // Generic NDJSON streaming helper to eliminate code duplication
@@ -462,7 +496,8 @@ export async function scan(
   params: ScanParams,
   onProgress: (msg: string) => void,
   signal?: AbortSignal,
-  onMeta?: (meta: StationCacheMeta | undefined) => void
+  onMeta?: (meta: StationCacheMeta | undefined) => void,
+  onOrderSlots?: (check: OrderSlotCheck) => void,
 ): Promise<FlipResult[]> {
   return streamNdjson<FlipResult>(
     `${BASE}/api/scan`,
@@ -470,7 +505,10 @@ export async function scan(
     onProgress,
     signal,
     "Scan failed",
-    (msg) => onMeta?.(msg.cache_meta),
+    (msg) => {
+      onMeta?.(msg.cache_meta);
+      if (msg.order_slots) onOrderSlots?.(msg.order_slots);
+    },
   );
 }
 
@@ -478,7 +516,8 @@ export async function scanMultiRegion(
   params: ScanParams,
   onProgress: (msg: string) => void,
   signal?: AbortSignal,
-  onMeta?: (meta: StationCacheMeta | undefined) => void
+  onMeta?: (meta: StationCacheMeta | undefined) => void,
+  onOrderSlots?: (check: OrderSlotCheck) => void,
 ): Promise<FlipResult[]> {
   return streamNdjson<FlipResult>(
     `${BASE}/api/scan/multi-region`,
@@ -486,7 +525,10 @@ export async function scanMultiRegion(
     onProgress,
     signal,
     "Multi-region scan failed",
-    (msg) => onMeta?.(msg.cache_meta),
+    (msg) => {
+      onMeta?.(msg.cache_meta);
+      if (msg.order_slots) onOrderSlots?.(msg.order_slots);
+    },
   );
 }
 
@@ -496,6 +538,7 @@ export async function scanRegionalDayTrader(
   signal?: AbortSignal,
   onMeta?: (meta: StationCacheMeta | undefined) => void,
   onSummary?: (summary: { count: number; targetRegionName: string; periodDays: number }) => void,
+  onOrderSlots?: (check: OrderSlotCheck) => void,
 ): Promise<FlipResult[]> {
   return streamNdjson<FlipResult>(
     `${BASE}/api/scan/regional-day`,
@@ -505,6 +548,7 @@ export async function scanRegionalDayTrader(
     "Regional day trader scan failed",
     (msg) => {
       onMeta?.(msg.cache_meta);
+      if (msg.order_slots) onOrderSlots?.(msg.order_slots);
       const raw = msg as {
         count?: number;
         target_region_name?: string;
@@ -555,6 +599,9 @@ export async function findRoutes(
       route_minutes_per_jump: params.route_minutes_per_jump,
       route_dock_minutes: params.route_dock_minutes,
       route_safety_delay_percent: params.route_safety_delay_percent,
+      route_align_seconds: params.route_align_seconds,
+      route_warp_speed_au: params.route_warp_speed_au,
+      route_ship_profile_id: params.route_ship_profile_id,
       min_margin: params.min_margin,
       min_isk_per_jump: params.route_min_isk_per_jump,
       sales_tax_percent: params.sales_tax_percent,
@@ -613,6 +660,8 @@ export async function runFlipBacktest(params: {
   route_safety_mode?: "manual" | "auto";
   route_min_security?: number;
   route_min_cooldown_minutes?: number;
+  /** Saved ship profile for cargo, route minutes and tank. */
+  ship_profile_id?: number;
   signal?: AbortSignal;
 }): Promise<FlipBacktestResult> {
   const { signal, ...body } = params;
@@ -643,6 +692,180 @@ export async function checkOrderBookCoverage(params: {
   return handleResponse<OrderBookCoverageResult>(res);
 }
 
+export async function checkMarketCoverage(params: {
+  target_location_id: number;
+  reference_location_id?: number;
+  doctrine?: string;
+  cover_days?: number;
+  markup_percent?: number;
+  max_items?: number;
+  signal?: AbortSignal;
+}): Promise<MarketCoverageResult> {
+  const { signal, ...body } = params;
+  const res = await apiFetch(`${BASE}/api/market/coverage`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    signal,
+    body: JSON.stringify(body),
+  });
+  return handleResponse<MarketCoverageResult>(res);
+}
+
+export async function appraiseFits(params: {
+  eft: string;
+  target_location_id: number;
+  fits?: number;
+  price_source?: string;
+  sales_tax_percent?: number;
+  broker_fee_percent?: number;
+  signal?: AbortSignal;
+}): Promise<FitAppraisalResult> {
+  const { signal, ...body } = params;
+  const res = await apiFetch(`${BASE}/api/fits/appraise`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    signal,
+    body: JSON.stringify(body),
+  });
+  return handleResponse<FitAppraisalResult>(res);
+}
+
+export async function getMarketAnomalies(
+  params: { direction?: "spike" | "crash"; limit?: number } = {},
+  signal?: AbortSignal,
+): Promise<MarketAnomalyFeed> {
+  const qp = new URLSearchParams();
+  if (params.direction) qp.set("direction", params.direction);
+  if (params.limit) qp.set("limit", String(params.limit));
+  const qs = qp.toString();
+  const res = await apiFetch(`${BASE}/api/market/anomalies${qs ? `?${qs}` : ""}`, { signal });
+  return handleResponse<MarketAnomalyFeed>(res);
+}
+
+export async function getMarketCompetitors(
+  typeId: number,
+  locationId: number,
+  days = 7,
+  signal?: AbortSignal,
+): Promise<MarketCompetitorsResult> {
+  const qp = new URLSearchParams({ type_id: String(typeId), location_id: String(locationId), days: String(days) });
+  const res = await apiFetch(`${BASE}/api/market/competitors?${qp.toString()}`, { signal });
+  return handleResponse<MarketCompetitorsResult>(res);
+}
+
+export async function getCompetitorWatches(): Promise<CompetitorWatch[]> {
+  const res = await apiFetch(`${BASE}/api/market/competitors/watch`);
+  return handleResponse<CompetitorWatch[]>(res);
+}
+
+export async function watchCompetitorBook(typeId: number, locationId: number): Promise<void> {
+  const res = await apiFetch(`${BASE}/api/market/competitors/watch`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify({ type_id: typeId, location_id: locationId }),
+  });
+  await handleResponse<{ ok: boolean }>(res);
+}
+
+export async function unwatchCompetitorBook(typeId: number, locationId: number): Promise<void> {
+  const qp = new URLSearchParams({ type_id: String(typeId), location_id: String(locationId) });
+  const res = await apiFetch(`${BASE}/api/market/competitors/watch?${qp.toString()}`, { method: "DELETE" });
+  await handleResponse<{ ok: boolean }>(res);
+}
+
+export async function getWatchlistCadence(days = 3, signal?: AbortSignal): Promise<WatchlistCadenceResult> {
+  const res = await apiFetch(`${BASE}/api/watchlist/cadence?days=${days}`, { signal });
+  return handleResponse<WatchlistCadenceResult>(res);
+}
+
+export async function getStationFees(): Promise<StationFee[]> {
+  const res = await apiFetch(`${BASE}/api/fees/stations`);
+  return handleResponse<StationFee[]>(res);
+}
+
+export async function saveStationFee(fee: StationFee): Promise<StationFee> {
+  const res = await apiFetch(`${BASE}/api/fees/stations`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(fee),
+  });
+  return handleResponse<StationFee>(res);
+}
+
+export async function deleteStationFee(locationID: number): Promise<void> {
+  const res = await apiFetch(`${BASE}/api/fees/stations/${locationID}`, { method: "DELETE" });
+  await handleResponse<{ ok: boolean }>(res);
+}
+
+export async function getPinnedRoutes(): Promise<PinnedRoute[]> {
+  const res = await apiFetch(`${BASE}/api/route/pins`);
+  return handleResponse<PinnedRoute[]>(res);
+}
+
+/** Pins a path through the waypoints; gaps are filled with the shortest gate path. */
+export async function savePinnedRoute(systems: string[], note?: string): Promise<PinnedRoute> {
+  const res = await apiFetch(`${BASE}/api/route/pins`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify({ systems, note }),
+  });
+  return handleResponse<PinnedRoute>(res);
+}
+
+export async function deletePinnedRoute(fromSystemID: number, toSystemID: number): Promise<void> {
+  const res = await apiFetch(`${BASE}/api/route/pins/${fromSystemID}/${toSystemID}`, { method: "DELETE" });
+  await handleResponse<{ ok: boolean }>(res);
+}
+
+export async function getShipProfiles(): Promise<ShipProfile[]> {
+  const res = await apiFetch(`${BASE}/api/ships`);
+  return handleResponse<ShipProfile[]>(res);
+}
+
+export async function saveShipProfile(profile: ShipProfile): Promise<ShipProfile> {
+  const res = await apiFetch(`${BASE}/api/ships`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(profile),
+  });
+  return handleResponse<ShipProfile>(res);
+}
+
+export async function deleteShipProfile(id: number): Promise<void> {
+  const res = await apiFetch(`${BASE}/api/ships/${id}`, { method: "DELETE" });
+  await handleResponse<{ ok: boolean }>(res);
+}
+
+export async function getSpeculationBaskets(): Promise<SpeculationBasket[]> {
+  const res = await apiFetch(`${BASE}/api/speculation/baskets`);
+  return handleResponse<SpeculationBasket[]>(res);
+}
+
+export async function saveSpeculationBasket(
+  basket: SpeculationBasketInput,
+): Promise<{ basket: SpeculationBasket; unknown: string[] }> {
+  const res = await apiFetch(`${BASE}/api/speculation/baskets`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(basket),
+  });
+  return handleResponse<{ basket: SpeculationBasket; unknown: string[] }>(res);
+}
+
+export async function deleteSpeculationBasket(id: number): Promise<void> {
+  const res = await apiFetch(`${BASE}/api/speculation/baskets/${id}`, { method: "DELETE" });
+  await handleResponse<{ ok: boolean }>(res);
+}
+
+export async function getSpeculationBasketChart(
+  id: number,
+  days = 90,
+  signal?: AbortSignal,
+): Promise<SpeculationBasketChart> {
+  const res = await apiFetch(`${BASE}/api/speculation/baskets/${id}/chart?days=${days}`, { signal });
+  return handleResponse<SpeculationBasketChart>(res);
+}
+
 export async function getOrderBookStats(limit = 10): Promise<OrderBookStats> {
   const qp = new URLSearchParams();
   if (limit > 0) qp.set("limit", String(limit));
@@ -839,6 +1062,9 @@ export async function updateWatchlistItem(typeId: number, patch: {
   alert_enabled?: boolean;
   alert_metric?: "margin_percent" | "total_profit" | "profit_per_unit" | "daily_volume";
   alert_threshold?: number;
+  group?: string;
+  target_buy_below?: number;
+  target_sell_above?: number;
 }): Promise<WatchlistItem[]> {
   const res = await apiFetch(`${BASE}/api/watchlist/${typeId}`, {
     method: "PUT",
@@ -848,6 +1074,54 @@ export async function updateWatchlistItem(typeId: number, patch: {
   return handleResponse<WatchlistItem[]>(res);
 }
 
+export async function importWatchlist(params: {
+  content?: string;
+  scan_id?: number;
+  group?: string;
+  apply_settings?: boolean;
+  dry_run?: boolean;
+}): Promise<WatchlistImportResult> {
+  const res = await apiFetch(`${BASE}/api/watchlist/import`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(params),
+  });
+  return handleResponse<WatchlistImportResult>(res);
+}
+
+export async function exportWatchlist(group?: string): Promise<WatchlistExport> {
+  const qs = group !== undefined ? `?group=${encodeURIComponent(group)}` : "";
+  const res = await apiFetch(`${BASE}/api/watchlist/export${qs}`);
+  return handleResponse<WatchlistExport>(res);
+}
+
+export async function bulkDeleteWatchlist(body: { type_ids?: number[]; group?: string }): Promise<{ deleted: number; items: WatchlistItem[] }> {
+  const res = await apiFetch(`${BASE}/api/watchlist/bulk-delete`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(body),
+  });
+  return handleResponse<{ deleted: number; items: WatchlistItem[] }>(res);
+}
+
+export async function setWatchlistGroup(typeIds: number[], group: string): Promise<{ updated: number; items: WatchlistItem[] }> {
+  const res = await apiFetch(`${BASE}/api/watchlist/group`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify({ type_ids: typeIds, group }),
+  });
+  return handleResponse<{ updated: number; items: WatchlistItem[] }>(res);
+}
+
+export async function renameWatchlistGroup(from: string, to: string): Promise<{ updated: number; items: WatchlistItem[] }> {
+  const res = await apiFetch(`${BASE}/api/watchlist/groups/rename`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify({ from, to }),
+  });
+  return handleResponse<{ updated: number; items: WatchlistItem[] }>(res);
+}
+
 export async function getAlertHistory(typeId?: number, limit?: number, offset?: number): Promise<AlertHistoryEntry[]> {
   const params = new URLSearchParams();
   if (typeId) params.set("type_id", String(typeId));
@@ -912,6 +1186,28 @@ export async function getExecutionPlan(params: {
   return handleResponse<ExecutionPlanResult>(res);
 }
 
+export async function getExecutionSchedule(params: {
+  type_id: number;
+  region_id?: number;
+  location_id?: number;
+  quantity: number;
+  side: "buy" | "sell";
+  max_participation_pct?: number;
+  deadline_days?: number;
+  profile?: "twap" | "vwap";
+  start_date?: string;
+  signal?: AbortSignal;
+}): Promise<ExecutionSchedule> {
+  const { signal, ...body } = params;
+  const res = await apiFetch(`${BASE}/api/execution/schedule`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    signal,
+    body: JSON.stringify(body),
+  });
+  return handleResponse<ExecutionSchedule>(res);
+}
+
 export async function getExecutionQuote(params: {
   type_id: number;
   quantity: number;
@@ -975,6 +1271,30 @@ export async function getExecutionQuote(params: {
   return plan.quote;
 }
 
+export async function getBuyOrderAdvice(params: {
+  station_id: number;
+  region_id?: number;
+  capital: number;
+  horizon_days?: number;
+  max_orders?: number;
+  max_item_pct?: number;
+  fill_share_pct?: number;
+  min_cts?: number;
+  cts_profile?: string;
+  sales_tax_percent?: number;
+  broker_fee?: number;
+  signal?: AbortSignal;
+}): Promise<BuyOrderPlan> {
+  const { signal, ...body } = params;
+  const res = await apiFetch(`${BASE}/api/station/buy-advisor`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    signal,
+    body: JSON.stringify(body),
+  });
+  return handleResponse<BuyOrderPlan>(res);
+}
+
 export async function scanStation(
   params: {
     station_id?: number;
@@ -1012,7 +1332,8 @@ export async function scanStation(
   },
   onProgress: (msg: string) => void,
   signal?: AbortSignal,
-  onMeta?: (meta: StationCacheMeta | undefined) => void
+  onMeta?: (meta: StationCacheMeta | undefined) => void,
+  onOrderSlots?: (check: OrderSlotCheck) => void,
 ): Promise<StationTrade[]> {
   return streamNdjson<StationTrade>(
     `${BASE}/api/scan/station`,
@@ -1020,7 +1341,10 @@ export async function scanStation(
     onProgress,
     signal,
     "Station scan failed",
-    (msg) => onMeta?.(msg.cache_meta),
+    (msg) => {
+      onMeta?.(msg.cache_meta);
+      if (msg.order_slots) onOrderSlots?.(msg.order_slots);
+    },
   );
 }
 
@@ -1873,6 +2197,7 @@ export interface OrderDeskParams {
   salesTax?: number;
   brokerFee?: number;
   targetEtaDays?: number;
+  repriceStrategy?: RepriceStrategy;
   characterId?: CharacterScope;
 }
 
@@ -1881,6 +2206,7 @@ export async function getOrderDesk(params?: OrderDeskParams): Promise<OrderDeskR
   if (params?.salesTax != null) qp.set("sales_tax", String(params.salesTax));
   if (params?.brokerFee != null) qp.set("broker_fee", String(params.brokerFee));
   if (params?.targetEtaDays != null) qp.set("target_eta_days", String(params.targetEtaDays));
+  if (params?.repriceStrategy) qp.set("reprice_strategy", params.repriceStrategy);
   appendCharacterScope(qp, params?.characterId);
   const qs = qp.toString();
   const res = await apiFetch(`${BASE}/api/auth/orders/desk${qs ? `?${qs}` : ""}`);
@@ -1985,6 +2311,32 @@ export async function getPortfolioOptimization(days: number = 90, characterId?:
   }
 }
 
+export interface PortfolioExposureParams {
+  characterId?: CharacterScope;
+  limit?: number;
+  priceSource?: string;
+  marketSharePercent?: number;
+  volatileDrvi?: number;
+  maxItemPercent?: number;
+  maxCategoryPercent?: number;
+  slowDays?: number;
+}
+
+export async function getPortfolioExposure(params: PortfolioExposureParams = {}): Promise<PortfolioExposureResponse> {
+  const qp = new URLSearchParams();
+  if (params.limit != null) qp.set("limit", String(params.limit));
+  if (params.priceSource) qp.set("price_source", params.priceSource);
+  if (params.marketSharePercent != null) qp.set("market_share_percent", String(params.marketSharePercent));
+  if (params.volatileDrvi != null) qp.set("volatile_drvi", String(params.volatileDrvi));
+  if (params.maxItemPercent != null) qp.set("max_item_percent", String(params.maxItemPercent));
+  if (params.maxCategoryPercent != null) qp.set("max_category_percent", String(params.maxCategoryPercent));
+  if (params.slowDays != null) qp.set("slow_days", String(params.slowDays));
+  appendCharacterScope(qp, params.characterId);
+  const qs = qp.toString();
+  const res = await apiFetch(`${BASE}/api/auth/portfolio/exposure${qs ? `?${qs}` : ""}`);
+  return handleResponse<PortfolioExposureResponse>(res);
+}
+
 // --- Industry ---
 
 import type { IndustryParams, IndustryAnalysis, BuildableItem, IndustrySystem, NdjsonIndustryMessage } from "./types";
@@ -2172,9 +2524,38 @@ export async function getCorpDashboard(mode: "demo" | "live" = "demo", signal?:
   return handleResponse<CorpDashboard>(res);
 }
 
+const CORP_JOURNAL_PAGE_SIZE = 1000;
+
+/** Fetches every journal entry in the window, one archive page at a time. */
 export async function getCorpJournal(mode: "demo" | "live" = "demo", division = 1, days = 90, signal?: AbortSignal): Promise<CorpJournalEntry[]> {
-  const res = await apiFetch(`${BASE}/api/corp/journal?mode=${mode}&division=${division}&days=${days}`, { signal });
-  return handleResponse<CorpJournalEntry[]>(res);
+  const entries: CorpJournalEntry[] = [];
+  for (;;) {
+    const res = await apiFetch(
+      `${BASE}/api/corp/journal?mode=${mode}&division=${division}&days=${days}&limit=${CORP_JOURNAL_PAGE_SIZE}&offset=${entries.length}`,
+      { signal },
+    );
+    const page = await handleResponse<{ total: number; entries: CorpJournalEntry[] }>(res);
+    const rows = page.entries ?? [];
+    entries.push(...rows);
+    if (rows.length === 0 || entries.length >= page.total) {
+      return entries;
+    }
+  }
+}
+
+export async function getCorpDashboardTrends(mode: "demo" | "live" = "demo", months = 12, signal?: AbortSignal): Promise<CorpDashboardTrends> {
+  const res = await apiFetch(`${BASE}/api/corp/dashboard/trends?mode=${mode}&months=${months}`, { signal });
+  return handleResponse<CorpDashboardTrends>(res);
+}
+
+export async function getCorpAlliance(mode: "demo" | "live" | "auto" = "auto", signal?: AbortSignal): Promise<AllianceDashboard> {
+  const res = await apiFetch(`${BASE}/api/corp/alliance?mode=${mode}`, { signal });
+  return handleResponse<AllianceDashboard>(res);
+}
+
+export async function getCorpAllianceCorp(corporationId: number, mode: "demo" | "live" | "auto" = "auto", signal?: AbortSignal): Promise<CorpDashboard> {
+  const res = await apiFetch(`${BASE}/api/corp/alliance/${corporationId}?mode=${mode}`, { signal });
+  return handleResponse<CorpDashboard>(res);
 }
 
 export async function getCorpMembers(mode: "demo" | "live" = "demo", signal?: AbortSignal): Promise<CorpMember[]> {
@@ -2182,21 +2563,55 @@ export async function getCorpMembers(mode: "demo" | "live" = "demo", signal?: Ab
   return handleResponse<CorpMember[]>(res);
 }
 
+export async function getCorpMemberAnalytics(mode: "demo" | "live" = "demo", signal?: AbortSignal): Promise<CorpMemberAnalytics> {
+  const res = await apiFetch(`${BASE}/api/corp/members/analytics?mode=${mode}`, { signal });
+  return handleResponse<CorpMemberAnalytics>(res);
+}
+
 export async function getCorpOrders(mode: "demo" | "live" = "demo", signal?: AbortSignal): Promise<CorpMarketOrderDetail[]> {
   const res = await apiFetch(`${BASE}/api/corp/orders?mode=${mode}`, { signal });
   return handleResponse<CorpMarketOrderDetail[]>(res);
 }
 
+export async function getCorpContracts(mode: "demo" | "live" = "demo", signal?: AbortSignal): Promise<CorpContract[]> {
+  const res = await apiFetch(`${BASE}/api/corp/contracts?mode=${mode}`, { signal });
+  return handleResponse<CorpContract[]>(res);
+}
+
+export async function getCorpStructures(mode: "demo" | "live" = "demo", signal?: AbortSignal): Promise<CorpStructure[]> {
+  const res = await apiFetch(`${BASE}/api/corp/structures?mode=${mode}`, { signal });
+  return handleResponse<CorpStructure[]>(res);
+}
+
+export async function getCorpStructureMonitor(mode: "demo" | "live" = "demo", fuelDays?: number, signal?: AbortSignal): Promise<CorpStructureMonitor> {
+  const qp = new URLSearchParams({ mode });
+  if (fuelDays) qp.set("fuel_days", String(fuelDays));
+  const res = await apiFetch(`${BASE}/api/corp/structures/monitor?${qp.toString()}`, { signal });
+  return handleResponse<CorpStructureMonitor>(res);
+}
+
 export async function getCorpIndustryJobs(mode: "demo" | "live" = "demo", signal?: AbortSignal): Promise<CorpIndustryJob[]> {
   const res = await apiFetch(`${BASE}/api/corp/industry?mode=${mode}`, { signal });
   return handleResponse<CorpIndustryJob[]>(res);
 }
 
+export async function getCorpIndustrySchedule(mode: "demo" | "live" = "demo", horizonHours = 48, signal?: AbortSignal): Promise<CorpIndustrySchedule> {
+  const res = await apiFetch(`${BASE}/api/corp/industry/schedule?mode=${mode}&horizon_hours=${horizonHours}`, { signal });
+  return handleResponse<CorpIndustrySchedule>(res);
+}
+
 export async function getCorpMiningLedger(mode: "demo" | "live" = "demo", signal?: AbortSignal): Promise<CorpMiningEntry[]> {
   const res = await apiFetch(`${BASE}/api/corp/mining?mode=${mode}`, { signal });
   return handleResponse<CorpMiningEntry[]>(res);
 }
 
+export async function getCorpMiningReport(mode: "demo" | "live" = "demo", refineYield?: number, signal?: AbortSignal): Promise<CorpMiningReport> {
+  const qp = new URLSearchParams({ mode });
+  if (refineYield) qp.set("yield", String(refineYield));
+  const res = await apiFetch(`${BASE}/api/corp/mining/report?${qp.toString()}`, { signal });
+  return handleResponse<CorpMiningReport>(res);
+}
+
 export async function searchItems(query: string, limit = 25, signal?: AbortSignal): Promise<ItemSearchResult[]> {
   const qp = new URLSearchParams();
   qp.set("q", query);
@@ -231,6 +231,8 @@ export const en = {
     contractFiltersHint: "Scam protection settings",
     maxResults: "Results Limit",
     paramsCargo: "Cargo m³",
+    paramsShipClass: "Ship class",
+    paramsShipClassHint: "Align and warp speed used to estimate trip time for ISK/hour",
     paramsBuy: "Buy Radius",
     paramsSell: "Sell Radius",
     paramsMargin: "Margin %",
@@ -289,6 +291,8 @@ export const en = {
     colDailyProfit: "Daily Profit",
     colProfitPerUnit: "Profit/Unit",
     colProfitPerJump: "ISK/Jump",
+    colProfitPerHour: "ISK/Hour",
+    colProfitPerHourHint: "Profit per hour of trip: warp, gate and dock time for the selected ship class",
     variantChip: "⎇{index}/{total}",
     variantChipHint: "Alternative trade option for the same item",
     colJumps: "Trip Jumps",
@@ -318,6 +322,7 @@ export const en = {
     colItems: "Items",
     colContractJumps: "Jumps",
     colContractPPJ: "ISK/Jump",
+    colContractPPH: "ISK/Hour",
     foundContracts: "Found {count} contracts",
     scanContractsPrompt: "Press \"Scan\" to search for contracts",
 
@@ -1449,6 +1454,8 @@ export const en = {
     orderDeskMedianETA: "Median ETA",
     orderDeskUnknownETA: "unknown ETA",
     orderDeskNotional: "Notional",
+    orderDeskOrderSlots: "Order Slots",
+    orderDeskFreeSlots: "free of",
     orderDeskActionHold: "Hold",
     orderDeskActionReprice: "Reprice",
     orderDeskActionCancel: "Cancel",
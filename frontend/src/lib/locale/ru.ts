@@ -219,6 +219,8 @@ export const ru = {
     ctsProfileDefensive: "Защитный",
     maxResults: "Лимит результатов",
     paramsCargo: "Груз m³",
+    paramsShipClass: "Класс корабля",
+    paramsShipClassHint: "Разгон и скорость варпа для оценки времени поездки в ISK/час",
     paramsBuy: "Радиус покупки",
     paramsSell: "Радиус продажи",
     paramsMargin: "Маржа %",
@@ -289,6 +291,8 @@ export const ru = {
     colDailyProfit: "Дн. прибыль",
     colProfitPerUnit: "Прибыль/шт",
     colProfitPerJump: "ISK/прыжок",
+    colProfitPerHour: "ISK/час",
+    colProfitPerHourHint: "Прибыль за час поездки: варп, гейты и стыковки для выбранного класса корабля",
     variantChip: "⎇{index}/{total}",
     variantChipHint: "Альтернативный вариант сделки для того же предмета",
     colJumps: "Прыжки пути",
@@ -318,6 +322,7 @@ export const ru = {
     colItems: "Предметов",
     colContractJumps: "Прыжки",
     colContractPPJ: "ISK/прыжок",
+    colContractPPH: "ISK/час",
     foundContracts: "Найдено {count} контрактов",
     scanContractsPrompt: "Нажмите «Сканировать» для поиска контрактов",
 
@@ -1449,6 +1454,8 @@ export const ru = {
     orderDeskMedianETA: "Медианная ETA",
     orderDeskUnknownETA: "без ETA",
     orderDeskNotional: "Номинал",
+    orderDeskOrderSlots: "Слоты ордеров",
+    orderDeskFreeSlots: "свободно из",
     orderDeskActionHold: "Держать",
     orderDeskActionReprice: "Переставить",
     orderDeskActionCancel: "Снять",
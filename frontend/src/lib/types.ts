@@ -29,6 +29,16 @@ export interface FlipResult {
   SellOrderRemain: number;
   TotalProfit: number;
   ProfitPerJump: number;
+  /** Minutes to fly to the buy station and haul every trip (ship travel model). */
+  ExecutionMinutes?: number;
+  ProfitPerHour?: number;
+  /** ISK per load vs the ship class gank threshold; GankSafeTrips keeps loads under it. */
+  GankLoadValueISK?: number;
+  GankThresholdISK?: number;
+  GankRisk?: boolean;
+  GankSafeTrips?: number;
+  /** CargoTrips was raised to GankSafeTrips (gank_split_trips). */
+  GankSplit?: boolean;
   BuyJumps: number;
   SellJumps: number;
   TotalJumps: number;
@@ -53,6 +63,8 @@ export interface FlipResult {
   ExecutionQuote?: ExecutionQuote;
   SlippageBuyPct?: number;
   SlippageSellPct?: number;
+  /** SlippageBuyPct + SlippageSellPct; absent with naive pricing. */
+  SlippagePct?: number;
   FillTimeDays?: number;
   LiquidityScore?: number;
   LiquidityLabel?: string;
@@ -290,6 +302,427 @@ export interface OrderBookCoverageResult {
   warnings?: string[];
 }
 
+export type MarketCoverageStatus = "missing" | "understocked" | "underpriced_elsewhere" | "ok";
+
+export interface MarketCoverageLine {
+  type_id: number;
+  type_name: string;
+  volume: number;
+  doctrine_qty: number;
+  weekly_demand: number;
+  target_stock: number;
+  target_price: number;
+  reference_price: number;
+  reference_stock: number;
+  markup_percent: number;
+  flags: MarketCoverageStatus[];
+  status: MarketCoverageStatus;
+  restock_units: number;
+  restock_unit_cost: number;
+  restock_cost: number;
+  haul_volume: number;
+}
+
+export interface MarketCoverageManifestItem {
+  type_id: number;
+  type_name: string;
+  units: number;
+  unit_price: number;
+  cost: number;
+  volume: number;
+}
+
+export interface MarketCoverageResult {
+  target_location_id: number;
+  target_location_name: string;
+  reference_location_id: number;
+  reference_location_name: string;
+  cover_days: number;
+  markup_percent: number;
+  lines: MarketCoverageLine[];
+  manifest: MarketCoverageManifestItem[];
+  missing: number;
+  understocked: number;
+  underpriced_elsewhere: number;
+  manifest_cost: number;
+  haul_volume: number;
+  unknown: string[];
+  warnings: string[];
+}
+
+export interface FitAppraisalLine {
+  type_id: number;
+  name: string;
+  per_fit: number;
+  quantity: number;
+  volume: number;
+  jita_price: number;
+  target_price: number;
+  jita_cost: number;
+  target_value: number;
+}
+
+export interface FitAppraisal {
+  name: string;
+  ship_type_id: number;
+  ship_name: string;
+  lines: FitAppraisalLine[];
+  jita_cost: number;
+  target_value: number;
+  volume: number;
+  freight: number;
+  fees: number;
+  profit: number;
+  margin_percent: number;
+  missing_jita: string[];
+  missing_target: string[];
+  complete: boolean;
+  fits: number;
+  total_cost: number;
+  total_profit: number;
+  total_volume: number;
+}
+
+export interface FitAppraisalResult {
+  target_location_id: number;
+  target_location_name: string;
+  price_source: string;
+  fits: number;
+  jumps: number;
+  appraisals: FitAppraisal[];
+  shopping_list: { type_id: number; name: string; quantity: number }[];
+  multibuy: string;
+  unknown: string[];
+}
+
+export interface MarketAnomaly {
+  type_id: number;
+  type_name: string;
+  direction: "spike" | "crash";
+  price: number;
+  best_buy: number;
+  vwap: number;
+  std_dev: number;
+  z_score: number;
+  deviation_pct: number;
+  drvi: number;
+  adjusted_price: number;
+  average_price: number;
+  daily_volume: number;
+  detected_at: string;
+}
+
+export interface MarketAnomalyFeed {
+  updated_at: string;
+  region_id: number;
+  sigma: number;
+  screened: number;
+  anomalies: MarketAnomaly[];
+}
+
+export interface SpeculationBasketItem {
+  type_id: number;
+  type_name: string;
+  quantity: number;
+}
+
+export interface SpeculationBasket {
+  id: number;
+  name: string;
+  region_id: number;
+  alert_percent: number;
+  items: SpeculationBasketItem[];
+  created_at: string;
+  updated_at: string;
+  last_alert_date: string;
+}
+
+export interface SpeculationBasketInput {
+  id?: number;
+  name: string;
+  region_id?: number;
+  alert_percent?: number;
+  items?: { type_id: number; quantity?: number }[];
+  text?: string;
+  group_id?: number;
+  market_group_id?: number;
+}
+
+export interface BasketPoint {
+  date: string;
+  value: number;
+  isk_volume: number;
+  index: number;
+}
+
+export interface BasketSummary {
+  value: number;
+  change_1d_pct: number;
+  change_7d_pct: number;
+  change_30d_pct: number;
+  volume_change_7d_pct: number;
+  priced_items: number;
+  items: number;
+}
+
+export interface SpeculationBasketChart {
+  basket: SpeculationBasket;
+  days: number;
+  series: BasketPoint[];
+  summary: BasketSummary;
+}
+
+export interface CompetitionBand {
+  from_pct: number;
+  to_pct: number;
+  orders: number;
+  volume: number;
+  volume_share_pct: number;
+}
+
+export interface CompetitorSeat {
+  order_id: number;
+  price: number;
+  volume_remain: number;
+  volume_share_pct: number;
+  behind_best_pct: number;
+}
+
+export interface CompetitionAnalysis {
+  side: "sell" | "buy";
+  best_price: number;
+  orders: number;
+  total_volume: number;
+  bands: CompetitionBand[];
+  seats: CompetitorSeat[];
+  top_seat_share_pct: number;
+  hhi: number;
+  near_best_orders: number;
+  cadence: RelistCadence;
+  ci: number;
+  aggressiveness: number;
+  aggressiveness_tag: "calm" | "active" | "contested" | "cutthroat";
+}
+
+export interface MarketCompetitorsResult {
+  type_id: number;
+  type_name: string;
+  location_id: number;
+  location_name: string;
+  watched: boolean;
+  days: number;
+  sell: CompetitionAnalysis;
+  buy: CompetitionAnalysis;
+}
+
+export interface CompetitorWatch {
+  type_id: number;
+  type_name: string;
+  location_id: number;
+  location_name: string;
+  region_id: number;
+  created_at: string;
+}
+
+export interface RelistCadence {
+  side: "sell" | "buy";
+  samples: number;
+  observed_hours: number;
+  changes: number;
+  penny_changes: number;
+  median_minutes: number;
+  p25_minutes: number;
+  changes_per_hour: number;
+  penny_war: boolean;
+  attention: "babysit" | "hourly" | "daily" | "idle";
+  last_change_at?: string;
+}
+
+export interface RelistCadences {
+  location_id: number;
+  days: number;
+  sell: RelistCadence;
+  buy: RelistCadence;
+}
+
+export interface WatchlistCadenceResult {
+  location_id: number;
+  location_name: string;
+  days: number;
+  items: (RelistCadences & { type_id: number; type_name: string })[];
+}
+
+/** One day of a multi-day execution schedule. */
+export interface ScheduleDay {
+  day: number;
+  date: string;
+  expected_volume: number;
+  quantity: number;
+  cumulative_qty: number;
+  participation_pct: number;
+  impact_pct: number;
+  expected_price: number;
+  past_deadline?: boolean;
+  actual_qty: number;
+}
+
+export interface ScheduleProgress {
+  filled_qty: number;
+  filled_pct: number;
+  avg_fill_price: number;
+  expected_by_now: number;
+  behind_qty: number;
+  status: "ahead" | "on_track" | "behind" | "done";
+}
+
+/** Day-by-day TWAP/VWAP plan for a large position, with wallet progress. */
+export interface ExecutionSchedule {
+  quantity: number;
+  is_buy: boolean;
+  profile: "twap" | "vwap";
+  max_participation_pct: number;
+  deadline_days: number;
+  avg_daily_volume: number;
+  ref_price: number;
+  total_days: number;
+  deadline_met: boolean;
+  avg_impact_pct: number;
+  expected_value: number;
+  days: ScheduleDay[];
+  progress?: ScheduleProgress;
+  warnings?: string[];
+}
+
+/** Broker fee the user recorded at a station or structure; overrides the computed rate. */
+export interface StationFee {
+  location_id: number;
+  location_name: string;
+  broker_fee_percent: number;
+  /** Per-visit structure access fee; 0 = none. */
+  access_fee_isk?: number;
+  updated_at?: string;
+}
+
+export interface ShipProfile {
+  id?: number;
+  name: string;
+  ship_class: string;
+  cargo_m3: number;
+  align_seconds: number;
+  warp_speed_au: number;
+  /** Effective hit points; 0 = unknown tank. */
+  ehp: number;
+  updated_at?: string;
+}
+
+export interface PinnedRoute {
+  system_ids: number[];
+  system_names: string[];
+  jumps: number;
+  note?: string;
+}
+
+export interface BuyOrderSuggestion {
+  type_id: number;
+  type_name: string;
+  price: number;
+  quantity: number;
+  escrow: number;
+  broker_fee: number;
+  best_buy: number;
+  best_sell: number;
+  spread_pct: number;
+  cts: number;
+  fill_per_day: number;
+  days_to_fill: number;
+  profit_per_unit: number;
+  expected_profit: number;
+  daily_roi_pct: number;
+}
+
+export interface BuyOrderPlan {
+  station_id: number;
+  station_name: string;
+  capital: number;
+  total_escrow: number;
+  total_broker_fees: number;
+  total_capital: number;
+  unallocated: number;
+  expected_profit: number;
+  candidates: number;
+  orders: BuyOrderSuggestion[];
+}
+
+export interface ExposureItem {
+  type_id: number;
+  type_name: string;
+  category: string;
+  units: number;
+  asset_value: number;
+  listed_value: number;
+  buy_escrow: number;
+  value: number;
+  share_pct: number;
+  daily_volume: number;
+  liquidation_days: number; // -1 = no trade history
+  drvi: number;
+  volatile: boolean;
+}
+
+export interface ExposureCategory {
+  name: string;
+  items: number;
+  value: number;
+  share_pct: number;
+}
+
+export interface ExposureCorrelation {
+  type_a: number;
+  type_b: number;
+  name_a: string;
+  name_b: string;
+  correlation: number;
+  days: number;
+  combined_share_pct: number;
+}
+
+export type RebalanceActionKind = "trim" | "diversify" | "liquidate" | "de_risk" | "cancel_buys" | "decorrelate";
+
+export interface RebalanceAction {
+  action: RebalanceActionKind;
+  type_id?: number;
+  type_name?: string;
+  category?: string;
+  value: number;
+  reason: string;
+}
+
+export interface PortfolioExposure {
+  total_value: number;
+  asset_value: number;
+  listed_value: number;
+  buy_escrow: number;
+  hhi: number;
+  top_item_share_pct: number;
+  top_category_share_pct: number;
+  weighted_liquidation_days: number;
+  illiquid_value_pct: number;
+  volatile_value_pct: number;
+  weighted_drvi: number;
+  items: ExposureItem[];
+  categories: ExposureCategory[];
+  correlations: ExposureCorrelation[];
+  actions: RebalanceAction[];
+}
+
+export interface PortfolioExposureResponse {
+  price_source: string;
+  types_total: number;
+  types_analyzed: number;
+  warnings: string[];
+  exposure: PortfolioExposure;
+}
+
 export interface OrderBookStatsType {
   type_id: number;
   snapshot_count: number;
@@ -657,6 +1090,8 @@ export interface ContractResult {
   ItemCount: number;
   Jumps: number;
   ProfitPerJump: number;
+  ExecutionMinutes?: number;
+  ProfitPerHour?: number;
 }
 
 export interface ContractItem {
@@ -751,6 +1186,10 @@ export interface RouteResult {
   CourierProfitAfterRewardISK?: number;
   CourierRiskPremiumPercent?: number;
   CourierViable?: boolean;
+  GankLoadValueISK?: number;
+  GankThresholdISK?: number;
+  GankRisk?: boolean;
+  GankSafeTrips?: number;
 }
 
 export type NdjsonRouteMessage =
@@ -770,6 +1209,27 @@ export interface WatchlistItem {
     | "profit_per_unit"
     | "daily_volume";
   alert_threshold?: number;
+  group?: string;
+  /** Alert when a hub's lowest sell drops to this price (0 = off). */
+  target_buy_below?: number;
+  /** Alert when a hub's highest buy reaches this price (0 = off). */
+  target_sell_above?: number;
+}
+
+export interface WatchlistImportResult {
+  format: string;
+  items: WatchlistItem[];
+  added: WatchlistItem[];
+  unknown: string[];
+  settings: Record<string, number>;
+  settings_applied: boolean;
+}
+
+export interface WatchlistExport {
+  format: "eve-flipper-watchlist";
+  version: number;
+  exported_at: string;
+  items: WatchlistItem[];
 }
 
 export interface AlertHistoryEntry {
@@ -910,6 +1370,9 @@ export interface ImpactParams {
   avg_daily_volume: number;
   days_used: number;
   valid: boolean;
+  range_slope?: number;
+  range_fit_r2?: number;
+  calibrated_at?: string;
 }
 
 /** Impact estimate for a quantity: ΔP% (linear/√V) and TWAP slices. */
@@ -919,6 +1382,8 @@ export interface ImpactEstimate {
   recommended_impact_pct: number;
   recommended_impact_isk: number;
   optimal_slices: number;
+  slice_participation_pct: number;
+  twap_horizon_days: number;
   params: ImpactParams;
 }
 
@@ -1079,12 +1544,28 @@ export interface ScanParams {
   route_minutes_per_jump?: number;
   route_dock_minutes?: number;
   route_safety_delay_percent?: number;
+  route_align_seconds?: number;
+  route_warp_speed_au?: number;
+  /** Saved ship profile for route search; replaces route cargo, class, align and warp. */
+  route_ship_profile_id?: number;
+  /** Ship class for ISK/hour travel time; align/warp override the class values. */
+  ship_class?: string;
+  ship_align_seconds?: number;
+  ship_warp_speed_au?: number;
+  /** Hauler tank for gank thresholds; 0 = class default. */
+  ship_ehp?: number;
+  /** Saved ship profile; replaces cargo capacity, ship class, align, warp and tank. */
+  ship_profile_id?: number;
+  /** Split flips above the ship's gank threshold into more trips. */
+  gank_split_trips?: boolean;
   // Player structures
   include_structures?: boolean;
   /** Category filter for regional day trader. Empty = all. */
   category_ids?: number[];
   /** When true, use lowest sell order at destination as revenue price instead of highest buy order. */
   sell_order_mode?: boolean;
+  /** Flipper only: price at the best bid/ask without walking the book for UnitsToBuy (faster, ignores slippage). */
+  naive_pricing?: boolean;
   /** Flipper only: when true restrict sell-side to target_market_system only; when false allow any buy order within sell radius. Default true. */
   restrict_to_target_market?: boolean;
   /** Regional Trade only: include capped rejected rows with filter reasons for market-data debugging. */
@@ -1241,6 +1722,10 @@ export interface ItemIntelligence {
 export interface AppConfig {
   system_name: string;
   ignored_system_ids?: number[];
+  /** Systems and regions no route enters. */
+  avoid_system_ids?: number[];
+  avoid_region_ids?: number[];
+  pinned_routes?: { system_ids: number[]; note?: string }[];
   cargo_capacity: number;
   buy_radius: number;
   sell_radius: number;
@@ -1290,6 +1775,8 @@ export interface AppConfig {
   alert_telegram_token: string;
   alert_telegram_chat_id: string;
   alert_discord_webhook: string;
+  structure_alerts?: boolean;
+  structure_fuel_alert_days?: number;
   opacity: number;
   window_x: number;
   window_y: number;
@@ -1610,6 +2097,9 @@ export interface OrderDeskSummary {
   avg_eta_days: number;
   worst_eta_days: number;
   unknown_eta_count: number;
+  /** Summed skill-based order slots; absent when skills are unknown. */
+  max_orders?: number;
+  free_order_slots: number;
 }
 
 export interface OrderDeskSettings {
@@ -1617,8 +2107,11 @@ export interface OrderDeskSettings {
   broker_fee_percent: number;
   target_eta_days: number;
   warn_expiry_days: number;
+  reprice_strategy: RepriceStrategy;
 }
 
+export type RepriceStrategy = "penny" | "tick" | "smart";
+
 export interface OrderDeskOrder {
   order_id: number;
   type_id: number;
@@ -1638,6 +2131,8 @@ export interface OrderDeskOrder {
   book_available: boolean;
   best_price: number;
   suggested_price: number;
+  suggested_queue_ahead: number;
+  suggested_eta_days: number; // -1 = unknown
   undercut_amount: number;
   undercut_pct: number;
   queue_ahead_qty: number;
@@ -1656,6 +2151,34 @@ export interface OrderDeskResponse {
   summary: OrderDeskSummary;
   orders: OrderDeskOrder[];
   settings: OrderDeskSettings;
+  trading_limits?: TradingLimits[];
+}
+
+/** Order slots and remote trading ranges from a character's skills. */
+export interface TradingLimits {
+  character_id?: number;
+  character_name?: string;
+  max_orders: number;
+  open_orders: number;
+  free_slots: number;
+  trade: number;
+  retail: number;
+  wholesale: number;
+  tycoon: number;
+  remote_sell_range: string;
+  remote_buy_range: string;
+  remote_modify_range: string;
+}
+
+/** Scan rows vs the active character's free order slots. */
+export interface OrderSlotCheck {
+  max_orders: number;
+  open_orders: number;
+  free_slots: number;
+  orders_per_row: number;
+  rows_that_fit: number;
+  exceeds: boolean;
+  warning?: string;
 }
 
 export type StationCommandAction = "new_entry" | "reprice" | "hold" | "cancel";
@@ -3143,6 +3666,7 @@ export interface CorpMember {
   name: string;
   last_login: string;
   logoff_date: string;
+  joined_at?: string;
   ship_type_id: number;
   ship_name: string;
   location_id: number;
@@ -3150,6 +3674,39 @@ export interface CorpMember {
   system_name: string;
 }
 
+export interface CorpMemberActivity {
+  character_id: number;
+  name: string;
+  last_active?: string;
+  active_days_30d: number;
+  current_streak: number;
+  longest_streak: number;
+  hours: number[];
+  peak_hour: number;
+  timezone?: "US" | "AU" | "EU";
+  recent_days: number;
+  baseline_days: number;
+  churn_risk?: "high" | "medium";
+  churn_reasons?: string[];
+}
+
+export interface CorpRetentionCohort {
+  month: string;
+  size: number;
+  retention: (number | null)[];
+}
+
+export interface CorpMemberAnalytics {
+  generated_at: string;
+  window_start: string;
+  summary: MemberSummary;
+  heatmap: number[][]; // [weekday 0=Sun][UTC hour]
+  timezones: Record<string, number>;
+  at_risk: number;
+  members: CorpMemberActivity[];
+  cohorts: CorpRetentionCohort[];
+}
+
 export interface CorpMarketOrderDetail {
   order_id: number;
   character_id: number;
@@ -3167,6 +3724,53 @@ export interface CorpMarketOrderDetail {
   region_id: number;
 }
 
+export interface CorpSlotUsage {
+  kind: "manufacturing" | "science" | "reactions";
+  used: number;
+  max: number;
+  utilization: number;
+}
+
+export interface CorpMemberSlotUtilization {
+  character_id: number;
+  name: string;
+  slots_source: "skills" | "assumed";
+  slots: CorpSlotUsage[];
+  corp_jobs: number;
+  personal_jobs: number;
+  utilization: number;
+  next_completion?: string;
+}
+
+export interface CorpUpcomingJobCompletion {
+  job_id: number;
+  installer_id: number;
+  installer_name?: string;
+  activity: string;
+  product_name?: string;
+  runs: number;
+  end_date: string;
+  hours_left: number;
+  location_name?: string;
+  personal?: boolean;
+}
+
+export interface CorpIndustrySchedule {
+  generated_at: string;
+  horizon_hours: number;
+  totals: CorpSlotUsage[];
+  members: CorpMemberSlotUtilization[];
+  upcoming: CorpUpcomingJobCompletion[];
+  facilities: {
+    location_id: number;
+    location_name?: string;
+    npc_station: boolean;
+    active_jobs: number;
+    by_kind: Record<string, number>;
+  }[];
+  alerts: { severity: "warning" | "info"; character_id?: number; kind?: string; message: string }[];
+}
+
 export interface CorpIndustryJob {
   job_id: number;
   installer_id: number;
@@ -3190,6 +3794,122 @@ export interface CorpMiningEntry {
   type_id: number;
   type_name: string;
   quantity: number;
+  observer_id?: number;
+  observer_name?: string;
+}
+
+export interface CorpMiningMemberMonth {
+  character_id: number;
+  character_name: string;
+  quantity: number;
+  isk: number;
+  moon_isk: number;
+  share: number;
+}
+
+export interface CorpMiningMonth {
+  month: string;
+  quantity: number;
+  isk: number;
+  moon_isk: number;
+  members: CorpMiningMemberMonth[];
+}
+
+export interface CorpMoonExtraction {
+  observer_id: number;
+  observer_name?: string;
+  start: string;
+  end: string;
+  days: number;
+  quantity: number;
+  isk: number;
+  miners: number;
+  ores: { type_id: number; type_name: string; quantity: number; estimated_isk?: number }[];
+}
+
+export interface CorpMoonObserverSchedule {
+  observer_id: number;
+  observer_name?: string;
+  extractions: number;
+  interval_days?: number;
+  last_start: string;
+  next_expected?: string;
+}
+
+export interface CorpMiningReport {
+  refine_yield: number;
+  total_quantity: number;
+  total_isk: number;
+  moon_isk: number;
+  months: CorpMiningMonth[];
+  extractions: CorpMoonExtraction[];
+  observers: CorpMoonObserverSchedule[];
+}
+
+export interface CorpContract {
+  contract_id: number;
+  type: "item_exchange" | "courier" | "auction" | string;
+  status: string;
+  title?: string;
+  issuer_id: number;
+  issuer_name?: string;
+  acceptor_id?: number;
+  acceptor_name?: string;
+  for_corporation: boolean;
+  availability: string;
+  price?: number;
+  reward?: number;
+  collateral?: number;
+  volume?: number;
+  start_location_id?: number;
+  start_location_name?: string;
+  end_location_id?: number;
+  end_location_name?: string;
+  days_to_complete?: number;
+  date_issued: string;
+  date_expired: string;
+  date_accepted?: string;
+  date_completed?: string;
+}
+
+export interface CorpStructure {
+  structure_id: number;
+  type_id: number;
+  type_name?: string;
+  name: string;
+  system_id: number;
+  system_name?: string;
+  state: string;
+  state_timer_end?: string;
+  fuel_expires?: string;
+  reinforce_hour: number;
+  services: { name: string; state: string }[];
+}
+
+export interface CorpStructureStatus extends CorpStructure {
+  fuel_hours_left: number;
+  fuel_status: "ok" | "low" | "critical" | "empty";
+  offline_services: string[];
+  reinforced: boolean;
+  timer_hours_left?: number;
+}
+
+export interface CorpStructureAlert {
+  key: string;
+  kind: "fuel_low" | "fuel_critical" | "service_offline" | "reinforced" | "low_power";
+  severity: "warning" | "critical";
+  structure_id: number;
+  name: string;
+  message: string;
+}
+
+export interface CorpStructureMonitor {
+  fuel_alert_days: number;
+  structures: CorpStructureStatus[];
+  alerts: CorpStructureAlert[];
+  low_fuel: number;
+  reinforced: number;
+  offline_services: number;
 }
 
 export interface CorpDashboard {
@@ -3217,6 +3937,94 @@ export interface CorpDashboard {
   market_summary: MarketSummary;
 }
 
+export interface CorpTrendDelta {
+  current: number;
+  previous: number;
+  change: number;
+  change_pct: number | null;
+}
+
+export interface CorpMonthlyTrend {
+  month: string;
+  revenue: number;
+  expenses: number;
+  net_income: number;
+  transactions: number;
+  pnl_days: number;
+  closing_balance: number | null;
+  members: number | null;
+  active_members: number | null;
+}
+
+export interface CorpDashboardTrends {
+  months: CorpMonthlyTrend[];
+  month_over_month: {
+    month: string;
+    previous_month: string;
+    revenue: CorpTrendDelta | null;
+    expenses: CorpTrendDelta | null;
+    net_income: CorpTrendDelta | null;
+    balance: CorpTrendDelta | null;
+    members: CorpTrendDelta | null;
+  };
+  first_snapshot?: string;
+  snapshot_days: number;
+}
+
+export interface AllianceCorpSummary {
+  info: CorpDashboard["info"];
+  total_balance: number;
+  balance_share: number;
+  revenue_30d: number;
+  expenses_30d: number;
+  net_income_30d: number;
+  revenue_share: number;
+  members: number;
+  active_last_30d: number;
+  active_jobs: number;
+  production_value: number;
+  mining_volume_30d: number;
+  mining_isk: number;
+  section_errors?: { source: string; sections: string[]; message: string; transient: boolean }[];
+}
+
+export interface AllianceWallet extends CorpWalletDivision {
+  corporation_id: number;
+  ticker: string;
+}
+
+export interface AllianceContributor extends MemberContribution {
+  corporation_id: number;
+  ticker: string;
+}
+
+export interface AllianceSkippedCharacter {
+  character_id: number;
+  character_name: string;
+  reason: string;
+}
+
+export interface AllianceDashboard {
+  is_demo: boolean;
+  corporations: AllianceCorpSummary[];
+  wallets: AllianceWallet[];
+  total_balance: number;
+  revenue_30d: number;
+  expenses_30d: number;
+  net_income_30d: number;
+  revenue_7d: number;
+  expenses_7d: number;
+  net_income_7d: number;
+  income_by_source: IncomeSource[];
+  daily_pnl: DailyPnLEntry[];
+  top_contributors: AllianceContributor[];
+  member_summary: MemberSummary;
+  industry_summary: IndustrySummary;
+  mining_summary: MiningSummary;
+  market_summary: MarketSummary;
+  skipped?: AllianceSkippedCharacter[];
+}
+
 export interface SystemDanger {
   SystemID: number;
   SystemName: string;
@@ -1,9 +1,38 @@
-import { defineConfig } from "vite";
+import { defineConfig, type Plugin } from "vite";
 import react from "@vitejs/plugin-react";
+import fs from "fs";
 import path from "path";
+import zlib from "zlib";
+
+const compressibleExt = /\.(html|js|mjs|css|json|svg|txt|map|wasm|xml)$/;
+
+// precompress writes .gz and .br files next to each compressible output
+// file, which the Go static handler serves as-is; nothing is compressed at
+// runtime. Files under 1 KiB or that do not shrink are skipped.
+function precompress(): Plugin {
+  return {
+    name: "eve-flipper-precompress",
+    apply: "build",
+    writeBundle(options, bundle) {
+      const outDir = options.dir ?? "dist";
+      for (const fileName of Object.keys(bundle)) {
+        if (!compressibleExt.test(fileName)) continue;
+        const file = path.join(outDir, fileName);
+        const body = fs.readFileSync(file);
+        if (body.length < 1024) continue;
+        const gz = zlib.gzipSync(body, { level: zlib.constants.Z_BEST_COMPRESSION });
+        if (gz.length < body.length) fs.writeFileSync(file + ".gz", gz);
+        const br = zlib.brotliCompressSync(body, {
+          params: { [zlib.constants.BROTLI_PARAM_QUALITY]: zlib.constants.BROTLI_MAX_QUALITY },
+        });
+        if (br.length < body.length) fs.writeFileSync(file + ".br", br);
+      }
+    },
+  };
+}
 
 export default defineConfig({
-  plugins: [react()],
+  plugins: [react(), precompress()],
   resolve: {
     alias: {
       "@": path.resolve(__dirname, "./src"),
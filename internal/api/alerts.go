@@ -33,8 +33,19 @@ type AlertCheckResult struct {
 func (s *Server) CheckWatchlistAlerts(userID string, results interface{}) []AlertCheckResult {
 	watchlist := s.db.GetWatchlistForUser(userID)
 	var alerts []AlertCheckResult
+	var bandQuotes map[int32][]bandQuote
 
 	for _, item := range watchlist {
+		if item.TargetBuyBelow > 0 || item.TargetSellAbove > 0 {
+			if bandQuotes == nil {
+				bandQuotes = bandQuotesFromResults(results)
+			}
+			for _, alert := range priceBandCrossings(item, bandQuotes[item.TypeID]) {
+				if !s.alertOnCooldown(userID, alert) {
+					alerts = append(alerts, alert)
+				}
+			}
+		}
 		if !item.AlertEnabled {
 			continue
 		}
@@ -143,6 +154,7 @@ func (s *Server) SendAlert(userID string, cfg *config.Config, alert AlertCheckRe
 		log.Printf("[ALERT] Failed to save alert history: %v", err)
 		// Don't fail the alert send if history save fails
 	}
+	s.recordUsage(userID, db.UsageCategoryAlert, alert.Metric, alert.TypeName)
 
 	log.Printf("[ALERT] Sent alert for %s: %s (channels: %v)", alert.TypeName, alert.Message, channelsSent)
 	return nil
@@ -0,0 +1,247 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"eve-flipper/internal/db"
+	"eve-flipper/internal/graph"
+)
+
+const (
+	// ansiblexSearch matches the "FROM » TO - Name" naming every Ansiblex
+	// uses; ESI search needs at least three characters.
+	ansiblexSearch          = " » "
+	ansiblexImportMax       = 1000
+	ansiblexImportWorkers   = 8
+	ansiblexManualMaxGates  = 2000
+	ansiblexManualMaxLength = 256 * 1024
+)
+
+// parseAnsiblexName splits "1DQ1-A » T5ZI-S - Imperial Bridge" (also "->" or
+// ",") into source and destination system names.
+func parseAnsiblexName(name string) (from, to string, ok bool) {
+	for _, sep := range []string{"»", "->", ","} {
+		i := strings.Index(name, sep)
+		if i < 0 {
+			continue
+		}
+		from = strings.TrimSpace(name[:i])
+		to = strings.TrimSpace(name[i+len(sep):])
+		if j := strings.Index(to, " - "); j >= 0 {
+			to = strings.TrimSpace(to[:j])
+		}
+		return from, to, from != "" && to != ""
+	}
+	return "", "", false
+}
+
+// ansiblexEdges returns the user's jump gates as routing edges.
+func (s *Server) ansiblexEdges(userID string) []graph.Edge {
+	if s.db == nil {
+		return nil
+	}
+	gates, err := s.db.ListAnsiblexGatesForUser(userID)
+	if err != nil {
+		log.Printf("[API] Ansiblex gates unavailable: %v", err)
+		return nil
+	}
+	edges := make([]graph.Edge, 0, len(gates))
+	for _, g := range gates {
+		edges = append(edges, graph.Edge{From: g.FromSystemID, To: g.ToSystemID})
+	}
+	return edges
+}
+
+func (s *Server) writeAnsiblexGates(w http.ResponseWriter, userID string, extra map[string]interface{}) {
+	gates, err := s.db.ListAnsiblexGatesForUser(userID)
+	if err != nil {
+		writeError(w, 500, "failed to load ansiblex gates")
+		return
+	}
+	resp := map[string]interface{}{"gates": gates, "count": len(gates)}
+	for k, v := range extra {
+		resp[k] = v
+	}
+	writeJSON(w, resp)
+}
+
+// handleGetAnsiblexGates lists the caller's jump gates.
+func (s *Server) handleGetAnsiblexGates(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeError(w, 503, "database unavailable")
+		return
+	}
+	s.writeAnsiblexGates(w, userIDFromRequest(r), nil)
+}
+
+type ansiblexGateInput struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Name string `json:"name"`
+}
+
+type setAnsiblexGatesRequest struct {
+	Gates []ansiblexGateInput `json:"gates"`
+	// Text is a pasted list, one gate per line ("FROM » TO - Name").
+	Text string `json:"text"`
+}
+
+// handleSetAnsiblexGates replaces the caller's manually entered jump gates.
+func (s *Server) handleSetAnsiblexGates(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeError(w, 503, "database unavailable")
+		return
+	}
+	var req setAnsiblexGatesRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, ansiblexManualMaxLength)).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	inputs := req.Gates
+	for _, line := range strings.Split(req.Text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		from, to, ok := parseAnsiblexName(line)
+		if !ok {
+			writeError(w, 400, "cannot parse gate: "+line)
+			return
+		}
+		inputs = append(inputs, ansiblexGateInput{From: from, To: to, Name: line})
+	}
+	if len(inputs) > ansiblexManualMaxGates {
+		writeError(w, 400, fmt.Sprintf("too many gates (max %d)", ansiblexManualMaxGates))
+		return
+	}
+
+	gates := make([]db.AnsiblexGate, 0, len(inputs))
+	for _, in := range inputs {
+		fromID := s.systemIDByName(in.From)
+		if fromID == 0 {
+			writeError(w, 400, "unknown system: "+strings.TrimSpace(in.From))
+			return
+		}
+		toID := s.systemIDByName(in.To)
+		if toID == 0 {
+			writeError(w, 400, "unknown system: "+strings.TrimSpace(in.To))
+			return
+		}
+		gates = append(gates, db.AnsiblexGate{FromSystemID: fromID, ToSystemID: toID, Name: strings.TrimSpace(in.Name)})
+	}
+	userID := userIDFromRequest(r)
+	if err := s.db.ReplaceAnsiblexGatesForUser(userID, db.AnsiblexSourceManual, gates); err != nil {
+		writeError(w, 500, "failed to save ansiblex gates")
+		return
+	}
+	s.writeAnsiblexGates(w, userID, nil)
+}
+
+// handleClearAnsiblexGates deletes all of the caller's jump gates.
+func (s *Server) handleClearAnsiblexGates(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeError(w, 503, "database unavailable")
+		return
+	}
+	if err := s.db.ClearAnsiblexGatesForUser(userIDFromRequest(r)); err != nil {
+		writeError(w, 500, "failed to clear ansiblex gates")
+		return
+	}
+	writeJSON(w, map[string]bool{"ok": true})
+}
+
+// handleAuthImportAnsiblexGates finds the jump gates the logged-in character
+// can see through ESI structure search and replaces previously imported ones.
+func (s *Server) handleAuthImportAnsiblexGates(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeError(w, 503, "database unavailable")
+		return
+	}
+	if !s.isReady() {
+		writeError(w, 503, "SDE not loaded yet")
+		return
+	}
+	userID := userIDFromRequest(r)
+	if s.sessions == nil {
+		writeError(w, 401, "not logged in")
+		return
+	}
+	sess := s.sessions.GetForUser(userID)
+	if sess == nil {
+		writeError(w, 401, "not logged in")
+		return
+	}
+	token, err := s.sessions.EnsureValidTokenForUserCharacter(s.sso, userID, sess.CharacterID)
+	if err != nil {
+		writeError(w, 401, err.Error())
+		return
+	}
+	ids, err := s.esi.SearchStructures(sess.CharacterID, ansiblexSearch, token)
+	if err != nil {
+		writeError(w, 502, err.Error()+" (re-login may be needed for the structure search scope)")
+		return
+	}
+	if len(ids) > ansiblexImportMax {
+		ids = ids[:ansiblexImportMax]
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		gates   []db.AnsiblexGate
+		skipped int
+	)
+	sem := make(chan struct{}, ansiblexImportWorkers)
+	for _, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id int64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			name, systemID, err := s.esi.StructureDetails(id, token)
+			gate, ok := db.AnsiblexGate{}, false
+			if err == nil {
+				gate, ok = s.ansiblexGateFromStructure(id, name, systemID)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if ok {
+				gates = append(gates, gate)
+			} else {
+				skipped++
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	if err := s.db.ReplaceAnsiblexGatesForUser(userID, db.AnsiblexSourceESI, gates); err != nil {
+		writeError(w, 500, "failed to save ansiblex gates")
+		return
+	}
+	log.Printf("[API] Imported %d Ansiblex gates for %s (%d search hits skipped)", len(gates), sess.CharacterName, skipped)
+	s.writeAnsiblexGates(w, userID, map[string]interface{}{"imported": len(gates), "skipped": skipped})
+}
+
+// ansiblexGateFromStructure turns a structure found by search into a gate
+// when its name names two systems and the first is where it is anchored.
+func (s *Server) ansiblexGateFromStructure(structureID int64, name string, systemID int32) (db.AnsiblexGate, bool) {
+	from, to, ok := parseAnsiblexName(name)
+	if !ok {
+		return db.AnsiblexGate{}, false
+	}
+	fromID, toID := s.systemIDByName(from), s.systemIDByName(to)
+	if fromID == 0 || toID == 0 || (systemID != 0 && systemID != fromID) {
+		return db.AnsiblexGate{}, false
+	}
+	return db.AnsiblexGate{
+		FromSystemID: fromID,
+		ToSystemID:   toID,
+		StructureID:  structureID,
+		Name:         name,
+	}, true
+}
@@ -0,0 +1,22 @@
+package api
+
+import "testing"
+
+func TestParseAnsiblexName(t *testing.T) {
+	cases := []struct {
+		in, from, to string
+		ok           bool
+	}{
+		{"1DQ1-A » T5ZI-S - Imperial Palace Bridge", "1DQ1-A", "T5ZI-S", true},
+		{"Jita -> Perimeter", "Jita", "Perimeter", true},
+		{"Amarr, Ashab", "Amarr", "Ashab", true},
+		{"no gate here", "", "", false},
+		{" » ", "", "", false},
+	}
+	for _, tc := range cases {
+		from, to, ok := parseAnsiblexName(tc.in)
+		if ok != tc.ok || (ok && (from != tc.from || to != tc.to)) {
+			t.Errorf("parseAnsiblexName(%q) = %q, %q, %v; want %q, %q, %v", tc.in, from, to, ok, tc.from, tc.to, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/db"
+)
+
+// API mode lets external tools (spreadsheets, Discord bots) call a running
+// instance with an API key. Keys are only honoured in API mode; there,
+// clients other than loopback must present one, so a LAN-bound instance is
+// not open to anyone who can reach it. Requests carrying proxy forwarding
+// headers need a key even from loopback, since behind a reverse proxy on
+// the same host every client connects from loopback. Keys are managed from
+// the local UI.
+
+const (
+	apiKeyDefaultRatePerMinute = 60
+	apiKeyMaxRatePerMinute     = 600
+	apiKeyMaxPerUser           = 50
+	// apiKeyTouchInterval throttles last-used writes for busy keys.
+	apiKeyTouchInterval = time.Minute
+)
+
+type apiKeyContextKey struct{}
+
+func apiKeyFromContext(ctx context.Context) (db.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey{}).(db.APIKey)
+	return key, ok
+}
+
+// SetAPIMode enables API key authentication.
+func (s *Server) SetAPIMode(enabled bool) {
+	s.apiMode = enabled
+	if !enabled {
+		s.apiKeyLimits = nil
+		return
+	}
+	s.apiKeyLimits = newRequestLimiter()
+	log.Printf("[API] API mode enabled: non-local clients need an API key")
+}
+
+// requestAPIKey returns the key of an "Authorization: Bearer" or
+// "X-API-Key" header.
+func requestAPIKey(r *http.Request) string {
+	if v := strings.TrimSpace(r.Header.Get("X-API-Key")); v != "" {
+		return v
+	}
+	auth := strings.TrimSpace(r.Header.Get("Authorization"))
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
+
+func isLoopbackRemoteAddr(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return isLoopbackHost(host)
+}
+
+// isProxiedRequest reports whether a reverse proxy forwarded the request.
+func isProxiedRequest(r *http.Request) bool {
+	for _, h := range []string{"Forwarded", "X-Forwarded-For", "X-Forwarded-Host", "X-Real-Ip"} {
+		if r.Header.Get(h) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func isAPIKeyManagementPath(path string) bool {
+	return path == "/api/keys" || strings.HasPrefix(path, "/api/keys/")
+}
+
+// isAPIKeyDeniedPath reports routes no key may call: key management, and
+// EVE SSO login and character data, which stay with the app's own session.
+func isAPIKeyDeniedPath(path string) bool {
+	return isAPIKeyManagementPath(path) || path == "/api/auth" || strings.HasPrefix(path, "/api/auth/")
+}
+
+// redactConfigSecrets blanks the alert bot token and webhook URL in a
+// config returned to a key-authenticated request.
+func redactConfigSecrets(r *http.Request, cfg *config.Config) *config.Config {
+	if _, ok := apiKeyFromContext(r.Context()); !ok {
+		return cfg
+	}
+	out := *cfg
+	out.AlertTelegramToken = ""
+	out.AlertDiscordWebhook = ""
+	return &out
+}
+
+// apiKeyAllows reports whether a key's access level covers the request.
+func apiKeyAllows(key db.APIKey, r *http.Request) bool {
+	if isAPIKeyDeniedPath(r.URL.Path) {
+		return false
+	}
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return true
+	}
+	switch key.Access {
+	case db.APIKeyAccessWrite:
+		return true
+	case db.APIKeyAccessScan:
+		feature, ok := hostedQuotaFeatureForRequest(r)
+		return ok && feature == "scans"
+	}
+	return false
+}
+
+func (s *Server) apiKeyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.apiMode || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		plaintext := requestAPIKey(r)
+		if plaintext == "" {
+			if !isLoopbackRemoteAddr(r.RemoteAddr) || isProxiedRequest(r) {
+				writeError(w, http.StatusUnauthorized, "api key required")
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		if s.db == nil {
+			writeError(w, http.StatusServiceUnavailable, "database unavailable")
+			return
+		}
+		key, ok, err := s.db.LookupAPIKey(plaintext)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "invalid api key")
+			return
+		}
+		if !apiKeyAllows(key, r) {
+			writeError(w, http.StatusForbidden, "api key does not allow this request")
+			return
+		}
+		if limits := s.apiKeyLimits; limits != nil {
+			rate := requestRate{perMinute: float64(key.RatePerMinute), burst: float64(key.RatePerMinute)}
+			if ok, wait := limits.allow(rate, map[string]float64{"apikey|" + strconv.FormatInt(key.ID, 10): 1}); !ok {
+				secs := int(math.Ceil(wait.Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(secs))
+				writeError(w, http.StatusTooManyRequests, fmt.Sprintf("api key rate limit exceeded, retry in %ds", secs))
+				return
+			}
+		}
+		if last, err := time.Parse(time.RFC3339, key.LastUsedAt); err != nil || time.Since(last) > apiKeyTouchInterval {
+			if err := s.db.TouchAPIKey(key.ID); err != nil {
+				log.Printf("[API] touch api key %d: %v", key.ID, err)
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, key)))
+	})
+}
+
+// rejectAPIKeyRequest keeps keys from managing keys.
+func rejectAPIKeyRequest(w http.ResponseWriter, r *http.Request) bool {
+	if _, ok := apiKeyFromContext(r.Context()); ok {
+		writeError(w, http.StatusForbidden, "api keys are managed from the app")
+		return true
+	}
+	return false
+}
+
+func (s *Server) handleListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	if rejectAPIKeyRequest(w, r) {
+		return
+	}
+	if s.db == nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	keys, err := s.db.ListAPIKeys(userIDFromRequest(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"api_mode": s.apiMode,
+		"keys":     keys,
+	})
+}
+
+// handleCreateAPIKey creates a key. The plaintext is in the response only.
+//
+//	POST /api/keys {"name": "Sheets", "access": "read|scan|write", "rate_per_minute": 60}
+func (s *Server) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	if rejectAPIKeyRequest(w, r) {
+		return
+	}
+	if s.db == nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	var req struct {
+		Name          string `json:"name"`
+		Access        string `json:"access"`
+		RatePerMinute int    `json:"rate_per_minute"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	access := strings.ToLower(strings.TrimSpace(req.Access))
+	if access == "" {
+		access = db.APIKeyAccessRead
+	}
+	if !db.ValidAPIKeyAccess(access) {
+		writeError(w, 400, "access must be read, scan or write")
+		return
+	}
+	rate := apiKeyDefaultRatePerMinute
+	if req.RatePerMinute > 0 {
+		rate = clampInt(req.RatePerMinute, 1, apiKeyMaxRatePerMinute)
+	}
+	name := strings.TrimSpace(req.Name)
+	if len(name) > 100 {
+		name = name[:100]
+	}
+
+	userID := userIDFromRequest(r)
+	existing, err := s.db.ListAPIKeys(userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	active := 0
+	for _, k := range existing {
+		if !k.Revoked {
+			active++
+		}
+	}
+	if active >= apiKeyMaxPerUser {
+		writeError(w, http.StatusConflict, fmt.Sprintf("at most %d active api keys", apiKeyMaxPerUser))
+		return
+	}
+	key, plaintext, err := s.db.CreateAPIKey(userID, name, access, rate)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSONStatus(w, http.StatusCreated, map[string]interface{}{
+		"key":     key,
+		"api_key": plaintext,
+	})
+}
+
+func (s *Server) handleRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	if rejectAPIKeyRequest(w, r) {
+		return
+	}
+	if s.db == nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, 400, "invalid id")
+		return
+	}
+	ok, err := s.db.RevokeAPIKey(userIDFromRequest(r), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !ok {
+		writeError(w, 404, "not found")
+		return
+	}
+	writeJSON(w, map[string]bool{"ok": true})
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/db"
+)
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	database := openAPITestDB(t)
+	s := &Server{db: database}
+	s.SetAPIMode(true)
+	_, readKey, err := database.CreateAPIKey("sheets-user", "sheets", db.APIKeyAccessRead, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, scanKey, err := database.CreateAPIKey("bot-user", "bot", db.APIKeyAccessScan, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotUser string
+	h := s.apiKeyMiddleware(s.userScopeMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser = userIDFromRequest(r)
+	})))
+	serve := func(method, path, remote, header, key string) int {
+		gotUser = ""
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = remote
+		if key != "" {
+			req.Header.Set(header, key)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	const lan = "192.168.1.20:51000"
+	if code := serve(http.MethodGet, "/api/status", "127.0.0.1:51000", "", ""); code != http.StatusOK {
+		t.Fatalf("loopback without key: status %d, want 200", code)
+	}
+	if code := serve(http.MethodGet, "/api/status", lan, "", ""); code != http.StatusUnauthorized {
+		t.Fatalf("remote without key: status %d, want 401", code)
+	}
+	for _, header := range []string{"X-Forwarded-For", "Forwarded", "X-Real-IP"} {
+		if code := serve(http.MethodGet, "/api/status", "127.0.0.1:51000", header, "203.0.113.7"); code != http.StatusUnauthorized {
+			t.Fatalf("loopback via reverse proxy (%s) without key: status %d, want 401", header, code)
+		}
+	}
+	if code := serve(http.MethodGet, "/api/status", lan, "X-API-Key", "efk_bogus"); code != http.StatusUnauthorized {
+		t.Fatalf("unknown key: status %d, want 401", code)
+	}
+	if code := serve(http.MethodGet, "/api/status", lan, "Authorization", "Bearer "+readKey); code != http.StatusOK || gotUser != "sheets-user" {
+		t.Fatalf("read key GET: status %d user %q, want 200 as sheets-user", code, gotUser)
+	}
+	if code := serve(http.MethodPost, "/api/scan", lan, "X-API-Key", readKey); code != http.StatusForbidden {
+		t.Fatalf("read key scan: status %d, want 403", code)
+	}
+	serve(http.MethodGet, "/api/status", lan, "X-API-Key", readKey)
+	if code := serve(http.MethodGet, "/api/status", lan, "X-API-Key", readKey); code != http.StatusTooManyRequests {
+		t.Fatalf("read key over budget: status %d, want 429", code)
+	}
+
+	if code := serve(http.MethodPost, "/api/scan", lan, "X-API-Key", scanKey); code != http.StatusOK || gotUser != "bot-user" {
+		t.Fatalf("scan key scan: status %d user %q, want 200 as bot-user", code, gotUser)
+	}
+	if code := serve(http.MethodPost, "/api/config", lan, "X-API-Key", scanKey); code != http.StatusForbidden {
+		t.Fatalf("scan key config write: status %d, want 403", code)
+	}
+	if code := serve(http.MethodGet, "/api/keys", lan, "X-API-Key", scanKey); code != http.StatusForbidden {
+		t.Fatalf("key management via key: status %d, want 403", code)
+	}
+	for _, path := range []string{"/api/auth/login", "/api/auth/character", "/api/auth/status"} {
+		if code := serve(http.MethodGet, path, lan, "X-API-Key", scanKey); code != http.StatusForbidden {
+			t.Fatalf("GET %s via key: status %d, want 403", path, code)
+		}
+	}
+
+	s.SetAPIMode(false)
+	if code := serve(http.MethodGet, "/api/status", lan, "", ""); code != http.StatusOK {
+		t.Fatalf("api mode off: status %d, want 200", code)
+	}
+}
+
+func TestAPIKeyConfigRedactsSecrets(t *testing.T) {
+	const userID = "sheets-config"
+	database := openAPITestDB(t)
+	srv := NewServer(config.Default(), nil, database, nil, nil)
+	srv.SetAPIMode(true)
+	cfg := srv.loadConfigForUser(userID)
+	cfg.AlertTelegramToken = "123:bot-secret"
+	cfg.AlertDiscordWebhook = "https://discord.com/api/webhooks/1/secret"
+	if err := srv.saveConfigForUser(userID, cfg); err != nil {
+		t.Fatal(err)
+	}
+	_, readKey, err := database.CreateAPIKey(userID, "sheets", db.APIKeyAccessRead, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/config", nil)
+	req.RemoteAddr = "192.168.1.20:51000"
+	req.Header.Set("X-API-Key", readKey)
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/config via read key: status %d: %s", rec.Code, rec.Body.String())
+	}
+	var got config.Config
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.AlertTelegramToken != "" || got.AlertDiscordWebhook != "" {
+		t.Fatalf("read key got alert secrets: token=%q webhook=%q", got.AlertTelegramToken, got.AlertDiscordWebhook)
+	}
+	if body := rec.Body.String(); strings.Contains(body, "secret") {
+		t.Fatalf("config response leaks a secret: %s", body)
+	}
+	if saved := srv.loadConfigForUser(userID); saved.AlertTelegramToken != "123:bot-secret" {
+		t.Fatalf("redaction changed the stored config: %q", saved.AlertTelegramToken)
+	}
+}
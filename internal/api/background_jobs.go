@@ -0,0 +1,117 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"eve-flipper/internal/db"
+)
+
+// Recurring background jobs persist their schedule (and optional state) in
+// SQLite so a restart resumes where the last process left off. A job whose
+// run came due while the app was closed either runs shortly after startup
+// (catchUpRun) or is skipped with a log line (catchUpSkip).
+type catchUpPolicy int
+
+const (
+	catchUpRun catchUpPolicy = iota
+	catchUpSkip
+)
+
+// backgroundJobCatchUpDelay lets the SDE load before a missed run catches up.
+const backgroundJobCatchUpDelay = time.Minute
+
+type backgroundJob struct {
+	name     string
+	interval time.Duration
+	catchUp  catchUpPolicy
+	run      func(now time.Time)
+
+	// Optional job state kept across restarts.
+	saveState    func() string
+	restoreState func(state string)
+}
+
+// startBackgroundJob runs job every interval for the lifetime of the
+// process, resuming its persisted schedule.
+func (s *Server) startBackgroundJob(job backgroundJob) {
+	first := s.resumeBackgroundJob(job, time.Now())
+	go func() {
+		timer := time.NewTimer(time.Until(first))
+		defer timer.Stop()
+		for range timer.C {
+			now := time.Now()
+			job.run(now)
+			s.saveBackgroundJob(job, now, now.Add(job.interval))
+			timer.Reset(job.interval)
+		}
+	}()
+}
+
+// resumeBackgroundJob restores job's state and returns when it should next
+// run, applying its catch-up policy to a run missed while the app was down.
+func (s *Server) resumeBackgroundJob(job backgroundJob, now time.Time) time.Time {
+	next := now.Add(job.interval)
+	if s.db == nil {
+		return next
+	}
+	saved, ok := s.db.GetBackgroundJob(job.name)
+	if !ok {
+		s.saveBackgroundJob(job, time.Time{}, next)
+		return next
+	}
+	if job.restoreState != nil && saved.State != "" {
+		job.restoreState(saved.State)
+	}
+	switch {
+	case saved.NextRunAt.IsZero():
+	case saved.NextRunAt.After(now):
+		return saved.NextRunAt
+	case job.catchUp == catchUpRun:
+		log.Printf("[JOBS] %s missed its run due %s, catching up", job.name, saved.NextRunAt.Format(time.RFC3339))
+		return now.Add(backgroundJobCatchUpDelay)
+	default:
+		missed := int(now.Sub(saved.NextRunAt)/job.interval) + 1
+		log.Printf("[JOBS] %s skipped %d missed run(s) since %s", job.name, missed, saved.NextRunAt.Format(time.RFC3339))
+	}
+	s.saveBackgroundJob(job, saved.LastRunAt, next)
+	return next
+}
+
+func (s *Server) saveBackgroundJob(job backgroundJob, lastRun, nextRun time.Time) {
+	if s.db == nil {
+		return
+	}
+	rec := db.BackgroundJob{Name: job.name, LastRunAt: lastRun, NextRunAt: nextRun}
+	if job.saveState != nil {
+		rec.State = job.saveState()
+	}
+	if err := s.db.SaveBackgroundJob(rec); err != nil {
+		log.Printf("[JOBS] save %s: %v", job.name, err)
+	}
+}
+
+// handleListBackgroundJobs returns the persisted schedule of background jobs.
+func (s *Server) handleListBackgroundJobs(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeJSON(w, []db.BackgroundJob{})
+		return
+	}
+	writeJSON(w, s.db.ListBackgroundJobs())
+}
+
+// handleListIngestWatermarks returns the caller's wallet and journal ingest
+// positions (see db.IngestWatermark).
+func (s *Server) handleListIngestWatermarks(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeJSON(w, []db.IngestWatermark{})
+		return
+	}
+	marks, err := s.db.ListIngestWatermarksForUser(userIDFromRequest(r))
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
+	}
+	writeJSON(w, marks)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResumeBackgroundJobCatchUp(t *testing.T) {
+	database := openAPITestDB(t)
+	s := &Server{db: database}
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	job := backgroundJob{name: "test_job", interval: 30 * time.Minute, catchUp: catchUpRun}
+
+	// First start: schedule one interval out.
+	if next := s.resumeBackgroundJob(job, now); !next.Equal(now.Add(30 * time.Minute)) {
+		t.Fatalf("first start next = %v", next)
+	}
+	// Restart before the run was due: keep the saved schedule.
+	if next := s.resumeBackgroundJob(job, now.Add(10*time.Minute)); !next.Equal(now.Add(30 * time.Minute)) {
+		t.Fatalf("resumed next = %v, want saved schedule", next)
+	}
+	// Restart after the run was missed: catch up shortly after startup.
+	later := now.Add(5 * time.Hour)
+	if next := s.resumeBackgroundJob(job, later); !next.Equal(later.Add(backgroundJobCatchUpDelay)) {
+		t.Fatalf("catch-up next = %v", next)
+	}
+
+	// A skipping job waits a full interval instead.
+	skip := backgroundJob{name: "test_job", interval: 30 * time.Minute, catchUp: catchUpSkip}
+	if next := s.resumeBackgroundJob(skip, later); !next.Equal(later.Add(30 * time.Minute)) {
+		t.Fatalf("skip next = %v", next)
+	}
+	saved, ok := database.GetBackgroundJob("test_job")
+	if !ok || !saved.NextRunAt.Equal(later.Add(30*time.Minute)) {
+		t.Fatalf("saved schedule = %+v, %v", saved, ok)
+	}
+}
+
+func TestOrderDeskWatchStatePersists(t *testing.T) {
+	database := openAPITestDB(t)
+	s := &Server{db: database}
+	lastRun := time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC)
+	s.orderDeskWatch = map[string]*orderDeskWatchState{
+		"user-1": {lastRun: lastRun, recs: map[int64]string{42: "hold"}},
+	}
+	job := backgroundJob{
+		name:         "order_desk_watch",
+		interval:     orderDeskWatchTick,
+		saveState:    s.orderDeskWatchSnapshot,
+		restoreState: s.restoreOrderDeskWatch,
+	}
+	s.saveBackgroundJob(job, lastRun, lastRun.Add(orderDeskWatchTick))
+
+	restarted := &Server{db: database}
+	job.saveState, job.restoreState = restarted.orderDeskWatchSnapshot, restarted.restoreOrderDeskWatch
+	restarted.resumeBackgroundJob(job, lastRun.Add(time.Hour))
+	st := restarted.orderDeskWatch["user-1"]
+	if st == nil || !st.lastRun.Equal(lastRun) || st.recs[42] != "hold" {
+		t.Fatalf("restored state = %+v", st)
+	}
+	if jobs := database.ListBackgroundJobs(); len(jobs) != 1 || jobs[0].Name != "order_desk_watch" {
+		t.Fatalf("ListBackgroundJobs = %+v", jobs)
+	}
+}
@@ -3,11 +3,13 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"time"
 
 	"eve-flipper/internal/db"
 	"eve-flipper/internal/engine"
+	"eve-flipper/internal/graph"
 )
 
 type backtestFlipsRequest struct {
@@ -47,6 +49,9 @@ type backtestFlipsRequest struct {
 	RouteSafetyMode      string              `json:"route_safety_mode"`
 	RouteMinSecurity     float64             `json:"route_min_security"`
 	RouteMinCooldownMin  int                 `json:"route_min_cooldown_minutes"`
+	// ShipProfileID > 0 takes cargo, route minutes and tank from a saved
+	// ship profile.
+	ShipProfileID int64 `json:"ship_profile_id"`
 }
 
 func backtestParamsFromRequest(req backtestFlipsRequest) engine.FlipBacktestParams {
@@ -88,7 +93,7 @@ func backtestParamsFromRequest(req backtestFlipsRequest) engine.FlipBacktestPara
 	}
 }
 
-func (s *Server) rowsWithBacktestRouteRisk(rows []engine.FlipResult, minSec float64) []engine.FlipResult {
+func (s *Server) rowsWithBacktestRouteRisk(rows []engine.FlipResult, universe *graph.Universe, minSec, tankFactor float64) []engine.FlipResult {
 	if len(rows) == 0 || s.ganker == nil {
 		return rows
 	}
@@ -105,7 +110,8 @@ func (s *Server) rowsWithBacktestRouteRisk(rows []engine.FlipResult, minSec floa
 		key := fmt.Sprintf("%d:%d:%.2f", row.BuySystemID, row.SellSystemID, minSec)
 		summary, ok := cache[key]
 		if !ok {
-			summary.add(s.routeDangerSystems(row.BuySystemID, row.SellSystemID, minSec))
+			summary.add(s.routeDangerSystems(universe, row.BuySystemID, row.SellSystemID, minSec))
+			summary.scaleForTank(tankFactor)
 			cache[key] = summary
 		}
 		mult := routeSafetyMultiplierFromSummary(summary)
@@ -168,6 +174,24 @@ func (s *Server) handleBacktestFlips(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "rows are required")
 		return
 	}
+	userID := userIDFromRequest(r)
+	tankFactor := 1.0
+	if req.ShipProfileID > 0 {
+		profile, err := s.shipProfileForUser(userID, req.ShipProfileID)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		travel := engine.ShipTravelFor(profile.ShipClass, profile.AlignSeconds, profile.WarpSpeedAU)
+		req.CargoCapacity = profile.CargoM3
+		if req.RouteMinutesPerJump <= 0 {
+			req.RouteMinutesPerJump = math.Round(travel.MinutesPerJump()*100) / 100
+		}
+		if req.RouteDockMinutes <= 0 {
+			req.RouteDockMinutes = math.Round(travel.DockMinutes()*100) / 100
+		}
+		tankFactor = shipTankRiskFactor(profile.EHP)
+	}
 
 	params := backtestParamsFromRequest(req)
 
@@ -178,7 +202,7 @@ func (s *Server) handleBacktestFlips(w http.ResponseWriter, r *http.Request) {
 		}
 		rows := req.Rows
 		if params.CooldownMode == "route_time" && params.RouteSafetyMode == "auto" {
-			rows = s.rowsWithBacktestRouteRisk(req.Rows, req.RouteMinSecurity)
+			rows = s.rowsWithBacktestRouteRisk(req.Rows, s.userUniverse(userID), req.RouteMinSecurity, tankFactor)
 		}
 		result := engine.BuildOrderBookReplayBacktest(rows, params, s.orderBookReplayGetter())
 		writeJSON(w, result)
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"log"
+	"time"
+
+	"eve-flipper/internal/engine"
+)
+
+// brokerFeeScheduleTTL is how long a character's skills and standings are
+// reused for broker fees. Standings move slowly; an hour keeps every scan
+// from re-fetching them.
+const brokerFeeScheduleTTL = time.Hour
+
+type brokerFeeScheduleEntry struct {
+	schedule *engine.BrokerFeeSchedule
+	fetched  time.Time
+}
+
+// brokerFeeSchedule returns the broker fee schedule of the user's active
+// character with the user's recorded station and structure fees, or nil
+// when there is none of them (profit math then keeps the configured broker
+// fee).
+func (s *Server) brokerFeeSchedule(userID string) *engine.BrokerFeeSchedule {
+	return s.characterBrokerFeeSchedule(userID).WithStationFees(s.stationFeePercents(userID))
+}
+
+// characterBrokerFeeSchedule returns the NPC station broker fee schedule of
+// the user's active character, or nil when not logged in or ESI is
+// unavailable. Failed fetches are cached like schedules, so a character
+// without the standings scope is not re-fetched on every scan.
+func (s *Server) characterBrokerFeeSchedule(userID string) *engine.BrokerFeeSchedule {
+	if s.sessions == nil || s.esi == nil {
+		return nil
+	}
+	sess := s.sessions.GetForUser(userID)
+	if sess == nil {
+		return nil
+	}
+
+	s.brokerFeeMu.Lock()
+	entry, ok := s.brokerFeeCache[sess.CharacterID]
+	s.brokerFeeMu.Unlock()
+	if ok && time.Since(entry.fetched) < brokerFeeScheduleTTL {
+		return entry.schedule
+	}
+
+	token, err := s.sessions.EnsureValidTokenForUserCharacter(s.sso, userID, sess.CharacterID)
+	if err != nil {
+		log.Printf("[SCAN] Broker fee standings unavailable for %s: %v", sess.CharacterName, err)
+		return nil
+	}
+	skills, err := s.esi.GetSkills(sess.CharacterID, token)
+	if err != nil {
+		log.Printf("[SCAN] Broker fee skills unavailable for %s: %v", sess.CharacterName, err)
+		s.storeBrokerFeeSchedule(sess.CharacterID, nil)
+		return nil
+	}
+	standings, err := s.esi.GetStandings(sess.CharacterID, token)
+	if err != nil {
+		// Usually a session from before the standings scope was requested.
+		log.Printf("[SCAN] Broker fee standings unavailable for %s: %v", sess.CharacterName, err)
+		s.storeBrokerFeeSchedule(sess.CharacterID, nil)
+		return nil
+	}
+
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	schedule := engine.NewBrokerFeeSchedule(sdeData, skills, standings)
+	s.storeBrokerFeeSchedule(sess.CharacterID, schedule)
+	return schedule
+}
+
+// storeBrokerFeeSchedule caches a character's schedule for
+// brokerFeeScheduleTTL. A nil schedule records a failed skills or standings
+// fetch, so scans keep the configured broker fee without asking ESI again
+// until the entry expires.
+func (s *Server) storeBrokerFeeSchedule(characterID int64, schedule *engine.BrokerFeeSchedule) {
+	s.brokerFeeMu.Lock()
+	defer s.brokerFeeMu.Unlock()
+	if s.brokerFeeCache == nil {
+		s.brokerFeeCache = make(map[int64]brokerFeeScheduleEntry)
+	}
+	s.brokerFeeCache[characterID] = brokerFeeScheduleEntry{schedule: schedule, fetched: time.Now()}
+}
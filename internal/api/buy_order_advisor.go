@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"eve-flipper/internal/engine"
+)
+
+// buyAdvisorScanStation runs the station scan the advisor ranks; a package
+// var so tests can stub the market.
+var buyAdvisorScanStation = func(sc *engine.Scanner, params engine.StationTradeParams) ([]engine.StationTrade, error) {
+	return sc.ScanStationTrades(params, func(string) {})
+}
+
+type buyOrderAdvisorRequest struct {
+	StationID       int64   `json:"station_id"`
+	RegionID        int32   `json:"region_id"` // 0 = derived from station_id
+	Capital         float64 `json:"capital"`
+	HorizonDays     float64 `json:"horizon_days"`
+	MaxOrders       int     `json:"max_orders"` // 0 = free order slots, when known
+	MaxItemPct      float64 `json:"max_item_pct"`
+	FillSharePct    float64 `json:"fill_share_pct"`
+	MinCTS          float64 `json:"min_cts"`
+	CTSProfile      string  `json:"cts_profile"`
+	SalesTaxPercent float64 `json:"sales_tax_percent"`
+	BrokerFee       float64 `json:"broker_fee"`
+}
+
+// handleBuyOrderAdvisor scans one station and returns the buy orders to
+// place with the given capital: item, price and quantity, and the escrow
+// they lock up.
+func (s *Server) handleBuyOrderAdvisor(w http.ResponseWriter, r *http.Request) {
+	var req buyOrderAdvisorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	if !s.isReady() {
+		writeError(w, 503, "SDE not loaded yet")
+		return
+	}
+	if req.RegionID == 0 && req.StationID != 0 {
+		_, req.RegionID = s.executionLocationRegion(req.StationID)
+	}
+	if req.StationID == 0 || req.RegionID == 0 || req.Capital <= 0 {
+		writeError(w, 400, "station_id (with region_id for unknown structures) and positive capital required")
+		return
+	}
+
+	s.mu.RLock()
+	scanner := s.scanner
+	s.mu.RUnlock()
+	if scanner == nil {
+		writeError(w, 503, "station scanner not ready")
+		return
+	}
+
+	userID := userIDFromRequest(r)
+	if cfg := s.loadConfigForUser(userID); cfg != nil {
+		if req.SalesTaxPercent <= 0 {
+			req.SalesTaxPercent = cfg.SalesTaxPercent
+		}
+		if req.BrokerFee <= 0 {
+			req.BrokerFee = cfg.BrokerFeePercent
+		}
+	}
+	brokerFees := s.brokerFeeSchedule(userID)
+	if fee, ok := brokerFees.StationPercent(req.StationID); ok {
+		req.BrokerFee = fee
+	}
+	if req.MaxOrders <= 0 {
+		if limits, ok := s.activeTradingLimits(userID); ok {
+			if limits.FreeSlots == 0 {
+				writeError(w, 409, "no free order slots")
+				return
+			}
+			req.MaxOrders = limits.FreeSlots
+		}
+	}
+
+	params := engine.StationTradeParams{
+		StationIDs:       map[int64]bool{req.StationID: true},
+		RegionID:         req.RegionID,
+		SalesTaxPercent:  req.SalesTaxPercent,
+		BrokerFee:        req.BrokerFee,
+		CTSProfile:       req.CTSProfile,
+		BrokerFees:       brokerFees,
+		RoutePreferences: s.routePreferences(userID),
+		Ctx:              r.Context(),
+	}
+	if isPlayerStructure(req.StationID) && s.sessions != nil {
+		if token, err := s.sessions.EnsureValidTokenForUser(s.sso, userID); err == nil {
+			params.AccessToken = token
+			params.IncludeStructures = true
+		}
+	}
+	trades, err := buyAdvisorScanStation(scanner, params)
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			writeError(w, 499, "request canceled")
+			return
+		}
+		writeError(w, 500, err.Error())
+		return
+	}
+
+	plan := engine.AdviseBuyOrders(filterStationTradesMarketDisabled(trades), engine.BuyOrderAdvisorParams{
+		Capital:          req.Capital,
+		HorizonDays:      clampFloat64(req.HorizonDays, 0, 30),
+		MaxOrders:        clampInt(req.MaxOrders, 0, 500),
+		MaxItemPct:       req.MaxItemPct,
+		FillSharePct:     req.FillSharePct,
+		MinCTS:           clampFloat64(req.MinCTS, 0, 100),
+		SalesTaxPercent:  req.SalesTaxPercent,
+		BrokerFeePercent: req.BrokerFee,
+	})
+	plan.StationID = req.StationID
+	if plan.StationName == "" && s.esi != nil {
+		plan.StationName = s.esi.StationName(req.StationID)
+	}
+	writeJSON(w, plan)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/db"
+	"eve-flipper/internal/engine"
+)
+
+func TestHandleBuyOrderAdvisorUsesStationFee(t *testing.T) {
+	const userID = "u-buy-advisor"
+	database := openAPITestDB(t)
+	if _, err := database.SaveStationFeeForUser(userID, db.StationFee{LocationID: 60003760, BrokerFeePercent: 1.5}); err != nil {
+		t.Fatalf("SaveStationFeeForUser: %v", err)
+	}
+
+	origScan := buyAdvisorScanStation
+	buyAdvisorScanStation = func(_ *engine.Scanner, p engine.StationTradeParams) ([]engine.StationTrade, error) {
+		if !p.StationIDs[60003760] || p.RegionID != 10000002 {
+			t.Fatalf("scan params = %+v", p)
+		}
+		return []engine.StationTrade{
+			{TypeID: 34, TypeName: "Tritanium", StationID: 60003760, StationName: "Jita IV - Moon 4", BuyPrice: 4, SellPrice: 5, S2BPerDay: 1e6, CTS: 70},
+		}, nil
+	}
+	t.Cleanup(func() { buyAdvisorScanStation = origScan })
+
+	srv := NewServer(config.Default(), nil, database, nil, nil)
+	srv.ready = true
+	srv.scanner = &engine.Scanner{}
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/station/buy-advisor", bytes.NewReader([]byte(body)))
+		addSignedUserCookie(req, srv, userID)
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := post(`{"station_id": 60003760, "region_id": 10000002, "capital": 100000, "max_item_pct": 100}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var plan engine.BuyOrderPlan
+	if err := json.NewDecoder(rec.Body).Decode(&plan); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(plan.Orders) != 1 || plan.Orders[0].Price != 4.01 {
+		t.Fatalf("orders = %+v, want one at 4.01", plan.Orders)
+	}
+	// 100,000 / (4.01 * 1.015) = 24,569.4 units.
+	if o := plan.Orders[0]; o.Quantity != 24569 {
+		t.Fatalf("quantity = %d, want 24569 (station fee 1.5%%)", o.Quantity)
+	}
+
+	if rec := post(`{"station_id": 60003760, "region_id": 10000002}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("missing capital status = %d, want 400", rec.Code)
+	}
+}
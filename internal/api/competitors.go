@@ -0,0 +1,214 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"eve-flipper/internal/db"
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/esi"
+)
+
+const (
+	competitorMaxDays     = 14
+	competitorDefaultDays = 7
+)
+
+// competitorBookParams reads type_id and location_id and resolves the NPC
+// station's region. Structures are not supported: the book poller runs
+// without a character token.
+func (s *Server) competitorBookParams(w http.ResponseWriter, typeID int32, locationID int64) (int32, bool) {
+	if typeID <= 0 || locationID <= 0 {
+		writeError(w, http.StatusBadRequest, "type_id and location_id are required")
+		return 0, false
+	}
+	if isPlayerStructure(locationID) {
+		writeError(w, http.StatusBadRequest, "competitor analysis supports NPC stations only")
+		return 0, false
+	}
+	if !s.isReady() {
+		writeError(w, http.StatusServiceUnavailable, "SDE not loaded yet")
+		return 0, false
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	if _, ok := sdeData.Types[typeID]; !ok {
+		writeError(w, http.StatusBadRequest, "unknown type")
+		return 0, false
+	}
+	_, regionID := s.locationSystemRegion(sdeData, locationID)
+	if regionID == 0 {
+		writeError(w, http.StatusBadRequest, "unknown location")
+		return 0, false
+	}
+	return regionID, true
+}
+
+// locationBook returns the type's orders at one location.
+func locationBook(orders []esi.MarketOrder, locationID int64) []esi.MarketOrder {
+	out := []esi.MarketOrder{}
+	for _, o := range orders {
+		if o.LocationID == locationID {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// competitorTops returns the best price of one side of a book across the
+// stored order book snapshots since from.
+func (s *Server) competitorTops(typeID int32, locationID int64, side string, from time.Time) ([]engine.TopOfBookPoint, error) {
+	tops, err := s.db.ListOrderBookTops(typeID, locationID, side, from)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]engine.TopOfBookPoint, 0, len(tops))
+	for _, t := range tops {
+		points = append(points, engine.TopOfBookPoint{At: t.CapturedAt, Price: t.Price})
+	}
+	return points, nil
+}
+
+// handleMarketCompetitors analyzes who holds a type's book at a station:
+// orders and volume per price band, concentration, and how aggressively
+// the top is repriced. Relist frequency comes from the stored order book
+// snapshots, which the watchlist poller takes every few minutes for watched
+// books; without history the score leaves it out.
+//
+//	GET /api/market/competitors?type_id=34&location_id=60003760&days=7
+func (s *Server) handleMarketCompetitors(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	typeID64, _ := strconv.ParseInt(q.Get("type_id"), 10, 32)
+	locationID, _ := strconv.ParseInt(q.Get("location_id"), 10, 64)
+	typeID := int32(typeID64)
+	regionID, ok := s.competitorBookParams(w, typeID, locationID)
+	if !ok {
+		return
+	}
+	days := competitorDefaultDays
+	if v, err := strconv.Atoi(q.Get("days")); err == nil && v > 0 {
+		days = clampInt(v, 1, competitorMaxDays)
+	}
+
+	orders, err := s.esi.FetchRegionOrdersByType(regionID, typeID)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "failed to fetch market orders: "+err.Error())
+		return
+	}
+	now := time.Now().UTC()
+
+	userID := userIDFromRequest(r)
+	watched := false
+	if watches, err := s.db.ListCompetitorWatchesForUser(userID); err == nil {
+		for _, wt := range watches {
+			if wt.TypeID == typeID && wt.LocationID == locationID {
+				watched = true
+				break
+			}
+		}
+	}
+	if watched {
+		s.recordRegionBook(regionID, typeID, orders, now)
+	}
+	from := now.AddDate(0, 0, -days)
+	sellTops, err := s.competitorTops(typeID, locationID, "sell", from)
+	if err != nil {
+		writeError(w, 500, "failed to read order book snapshots")
+		return
+	}
+	buyTops, err := s.competitorTops(typeID, locationID, "buy", from)
+	if err != nil {
+		writeError(w, 500, "failed to read order book snapshots")
+		return
+	}
+	book := locationBook(orders, locationID)
+
+	s.mu.RLock()
+	typeName := s.sdeData.Types[typeID].Name
+	s.mu.RUnlock()
+	writeJSON(w, map[string]interface{}{
+		"type_id":       typeID,
+		"type_name":     typeName,
+		"location_id":   locationID,
+		"location_name": s.esi.StationName(locationID),
+		"watched":       watched,
+		"days":          days,
+		"sell":          engine.AnalyzeCompetition(book, sellTops, "sell", now),
+		"buy":           engine.AnalyzeCompetition(book, buyTops, "buy", now),
+	})
+}
+
+func (s *Server) handleListCompetitorWatches(w http.ResponseWriter, r *http.Request) {
+	watches, err := s.db.ListCompetitorWatchesForUser(userIDFromRequest(r))
+	if err != nil {
+		writeError(w, 500, "failed to load watched books")
+		return
+	}
+	type row struct {
+		db.CompetitorWatch
+		TypeName     string `json:"type_name"`
+		LocationName string `json:"location_name"`
+	}
+	out := make([]row, 0, len(watches))
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	for _, wt := range watches {
+		rw := row{CompetitorWatch: wt, LocationName: s.esi.StationName(wt.LocationID)}
+		if sdeData != nil {
+			if t := sdeData.Types[wt.TypeID]; t != nil {
+				rw.TypeName = t.Name
+			}
+		}
+		out = append(out, rw)
+	}
+	writeJSON(w, out)
+}
+
+// handleAddCompetitorWatch starts snapshotting a book and takes the first
+// snapshot right away.
+func (s *Server) handleAddCompetitorWatch(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TypeID     int32 `json:"type_id"`
+		LocationID int64 `json:"location_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	regionID, ok := s.competitorBookParams(w, req.TypeID, req.LocationID)
+	if !ok {
+		return
+	}
+	watch := db.CompetitorWatch{TypeID: req.TypeID, LocationID: req.LocationID, RegionID: regionID}
+	if err := s.db.AddCompetitorWatchForUser(userIDFromRequest(r), watch); err != nil {
+		writeError(w, 500, "failed to watch book")
+		return
+	}
+	if orders, err := s.esi.FetchRegionOrdersByType(regionID, req.TypeID); err == nil {
+		s.recordRegionBook(regionID, req.TypeID, orders, time.Now().UTC())
+	} else {
+		log.Printf("[API] competitor book %d@%d: %v", req.TypeID, req.LocationID, err)
+	}
+	writeJSON(w, map[string]bool{"ok": true})
+}
+
+func (s *Server) handleDeleteCompetitorWatch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	typeID, _ := strconv.ParseInt(q.Get("type_id"), 10, 32)
+	locationID, _ := strconv.ParseInt(q.Get("location_id"), 10, 64)
+	ok, err := s.db.DeleteCompetitorWatchForUser(userIDFromRequest(r), int32(typeID), locationID)
+	if err != nil {
+		writeError(w, 500, "failed to unwatch book")
+		return
+	}
+	if !ok {
+		writeError(w, 404, "book not watched")
+		return
+	}
+	writeJSON(w, map[string]bool{"ok": true})
+}
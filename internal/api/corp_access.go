@@ -0,0 +1,155 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"eve-flipper/internal/corp"
+	"eve-flipper/internal/esi"
+)
+
+// corpRolesTTL matches the ESI cache of /characters/{id}/roles/.
+const corpRolesTTL = time.Hour
+
+type corpRolesEntry struct {
+	roles     corp.CharacterRoles
+	fetchedAt time.Time
+}
+
+// corpProviderError is a corpProvider failure with its HTTP status.
+type corpProviderError struct {
+	status int
+	err    error
+}
+
+func (e *corpProviderError) Error() string { return e.err.Error() }
+func (e *corpProviderError) Unwrap() error { return e.err }
+
+func writeCorpProviderError(w http.ResponseWriter, err error) {
+	var perr *corpProviderError
+	if errors.As(err, &perr) {
+		writeError(w, perr.status, perr.Error())
+		return
+	}
+	writeError(w, 400, err.Error())
+}
+
+// isCorpDirectorRole reports whether roles grant the director-level access
+// the ESI corporation endpoints need.
+func isCorpDirectorRole(roles []string) bool {
+	for _, role := range roles {
+		if role == "Director" || role == "CEO" {
+			return true
+		}
+	}
+	return false
+}
+
+// characterCorpRoles returns the character's corporation and roles. Complete
+// answers are cached; on error the result holds whatever was fetched.
+func (s *Server) characterCorpRoles(characterID int64, token string) (corp.CharacterRoles, error) {
+	s.corpRolesMu.Lock()
+	entry, ok := s.corpRolesCache[characterID]
+	s.corpRolesMu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < corpRolesTTL {
+		return entry.roles, nil
+	}
+
+	var roles *esi.CharacterRolesResponse
+	var corpID int32
+	var rolesErr, corpErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		roles, rolesErr = s.esi.GetCharacterRoles(characterID, token)
+	}()
+	go func() {
+		defer wg.Done()
+		corpID, corpErr = s.esi.GetCharacterCorporationID(characterID)
+	}()
+	wg.Wait()
+
+	result := corp.CharacterRoles{CorporationID: corpID}
+	if rolesErr == nil && roles != nil {
+		result.Roles = roles.Roles
+		result.IsDirector = isCorpDirectorRole(roles.Roles)
+	}
+	if err := errors.Join(rolesErr, corpErr); err != nil {
+		return result, err
+	}
+	s.corpRolesMu.Lock()
+	if s.corpRolesCache == nil {
+		s.corpRolesCache = make(map[int64]corpRolesEntry)
+	}
+	s.corpRolesCache[characterID] = corpRolesEntry{roles: result, fetchedAt: time.Now()}
+	s.corpRolesMu.Unlock()
+	return result, nil
+}
+
+// liveCorpProvider returns the ESI provider for the selected character,
+// which must be a Director or the CEO of its corporation.
+func (s *Server) liveCorpProvider(r *http.Request) (corp.CorpDataProvider, error) {
+	userID := userIDFromRequest(r)
+	characterID, allScope, err := parseAuthScope(r)
+	if err != nil {
+		return nil, err
+	}
+	selectedSessions, err := s.authSessionsForScope(userID, characterID, allScope, false)
+	if err != nil {
+		status := 400
+		if strings.Contains(err.Error(), "not logged in") {
+			status = 401
+		}
+		return nil, &corpProviderError{status: status, err: err}
+	}
+	sess := selectedSessions[0]
+	token, err := s.sessions.EnsureValidTokenForUserCharacter(s.sso, userID, sess.CharacterID)
+	if err != nil {
+		return nil, &corpProviderError{status: 401, err: fmt.Errorf("not logged in: %w", err)}
+	}
+	roles, err := s.characterCorpRoles(sess.CharacterID, token)
+	if err != nil {
+		return nil, &corpProviderError{status: http.StatusBadGateway, err: fmt.Errorf("failed to check corporation roles: %w", err)}
+	}
+	if !roles.IsDirector {
+		return nil, &corpProviderError{
+			status: http.StatusForbidden,
+			err:    fmt.Errorf("%s needs the Director or CEO role for live corporation data", sess.CharacterName),
+		}
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	return corp.NewESICorpProvider(s.esi, sdeData, token, roles.CorporationID, sess.CharacterID), nil
+}
+
+func (s *Server) demoCorpData() (corp.CorpDataProvider, error) {
+	if s.demoCorpProvider == nil {
+		return nil, &corpProviderError{status: http.StatusServiceUnavailable, err: fmt.Errorf("demo data not ready (SDE still loading)")}
+	}
+	return s.demoCorpProvider, nil
+}
+
+// corpProvider picks the corporation data source from ?mode=: live (ESI,
+// Director or CEO only), demo, or auto (the default), which uses ESI when the
+// selected character may read its corporation and demo data otherwise.
+func (s *Server) corpProvider(r *http.Request) (corp.CorpDataProvider, error) {
+	switch mode := r.URL.Query().Get("mode"); mode {
+	case "live":
+		return s.liveCorpProvider(r)
+	case "demo":
+		return s.demoCorpData()
+	case "", "auto":
+		if provider, err := s.liveCorpProvider(r); err == nil {
+			return provider, nil
+		}
+		return s.demoCorpData()
+	default:
+		return nil, fmt.Errorf("unknown mode %q (want live, demo or auto)", mode)
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"eve-flipper/internal/corp"
+)
+
+func TestCorpProvider_ModeSelection(t *testing.T) {
+	s := &Server{demoCorpProvider: corp.NewDemoCorpProvider()}
+	serve := func(query string) (corp.CorpDataProvider, int) {
+		provider, err := s.corpProvider(httptest.NewRequest(http.MethodGet, "/api/corp/dashboard"+query, nil))
+		if err != nil {
+			rec := httptest.NewRecorder()
+			writeCorpProviderError(rec, err)
+			return nil, rec.Code
+		}
+		return provider, http.StatusOK
+	}
+
+	for _, query := range []string{"", "?mode=auto", "?mode=demo"} {
+		provider, code := serve(query)
+		if code != http.StatusOK || !provider.IsDemo() {
+			t.Fatalf("%q without a login: status %d, want demo data", query, code)
+		}
+	}
+	if _, code := serve("?mode=live"); code != http.StatusUnauthorized {
+		t.Fatalf("live without a login: status %d, want 401", code)
+	}
+	if _, code := serve("?mode=bogus"); code != http.StatusBadRequest {
+		t.Fatalf("unknown mode: status %d, want 400", code)
+	}
+
+	s.demoCorpProvider = nil
+	if _, code := serve("?mode=demo"); code != http.StatusServiceUnavailable {
+		t.Fatalf("demo before SDE load: status %d, want 503", code)
+	}
+}
+
+func TestIsCorpDirectorRole(t *testing.T) {
+	if isCorpDirectorRole([]string{"Accountant", "Trader"}) {
+		t.Fatal("accountant is not a director")
+	}
+	if !isCorpDirectorRole([]string{"Accountant", "Director"}) || !isCorpDirectorRole([]string{"CEO"}) {
+		t.Fatal("director and CEO must pass")
+	}
+}
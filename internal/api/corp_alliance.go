@@ -0,0 +1,176 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"eve-flipper/internal/corp"
+)
+
+// allianceCorpProvider is one corporation reachable through a logged-in
+// director or CEO.
+type allianceCorpProvider struct {
+	provider      corp.CorpDataProvider
+	corporationID int32
+	characterName string
+}
+
+// allianceCorpProviders returns one live provider per distinct corporation
+// among the user's logged-in characters that hold Director or CEO. Every
+// other character is reported as skipped.
+func (s *Server) allianceCorpProviders(userID string) ([]allianceCorpProvider, []corp.AllianceSkippedCharacter) {
+	var providers []allianceCorpProvider
+	var skipped []corp.AllianceSkippedCharacter
+	if s.sessions == nil {
+		return providers, skipped
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+
+	seen := make(map[int32]string)
+	for _, sess := range s.sessions.ListForUser(userID) {
+		skip := func(reason string) {
+			skipped = append(skipped, corp.AllianceSkippedCharacter{
+				CharacterID:   sess.CharacterID,
+				CharacterName: sess.CharacterName,
+				Reason:        reason,
+			})
+		}
+		token, err := s.sessions.EnsureValidTokenForUserCharacter(s.sso, userID, sess.CharacterID)
+		if err != nil {
+			skip("token expired, log in again")
+			continue
+		}
+		roles, err := s.characterCorpRoles(sess.CharacterID, token)
+		if err != nil {
+			skip(fmt.Sprintf("failed to check corporation roles: %v", err))
+			continue
+		}
+		if !roles.IsDirector {
+			skip("needs the Director or CEO role")
+			continue
+		}
+		if by, ok := seen[roles.CorporationID]; ok {
+			skip(fmt.Sprintf("corporation already covered by %s", by))
+			continue
+		}
+		seen[roles.CorporationID] = sess.CharacterName
+		providers = append(providers, allianceCorpProvider{
+			provider:      corp.NewESICorpProvider(s.esi, sdeData, token, roles.CorporationID, sess.CharacterID),
+			corporationID: roles.CorporationID,
+			characterName: sess.CharacterName,
+		})
+	}
+	return providers, skipped
+}
+
+// handleCorpAlliance aggregates the dashboards of every corporation the user
+// can read as a director into one alliance view with a per-corp breakdown.
+// Demo mode (and auto without any director token) rolls up the demo corp.
+//
+//	GET /api/corp/alliance?mode=auto
+func (s *Server) handleCorpAlliance(w http.ResponseWriter, r *http.Request) {
+	mode := r.URL.Query().Get("mode")
+	if mode != "" && mode != "auto" && mode != "live" && mode != "demo" {
+		writeError(w, 400, fmt.Sprintf("unknown mode %q (want live, demo or auto)", mode))
+		return
+	}
+
+	userID := userIDFromRequest(r)
+	var providers []allianceCorpProvider
+	var skipped []corp.AllianceSkippedCharacter
+	if mode != "demo" {
+		providers, skipped = s.allianceCorpProviders(userID)
+		if len(providers) == 0 && mode == "live" {
+			writeError(w, http.StatusForbidden, "no logged-in character holds the Director or CEO role")
+			return
+		}
+	}
+	if len(providers) == 0 {
+		demo, err := s.demoCorpData()
+		if err != nil {
+			writeCorpProviderError(w, err)
+			return
+		}
+		providers = []allianceCorpProvider{{provider: demo}}
+	}
+
+	dashboards := make([]*corp.CorpDashboard, len(providers))
+	errs := make([]error, len(providers))
+	var wg sync.WaitGroup
+	for i, p := range providers {
+		wg.Add(1)
+		go func(i int, p corp.CorpDataProvider) {
+			defer wg.Done()
+			dashboards[i], errs[i] = corp.BuildDashboard(p, s.corpPrices(p))
+		}(i, p.provider)
+	}
+	wg.Wait()
+
+	built := dashboards[:0]
+	for i, d := range dashboards {
+		if errs[i] != nil {
+			log.Printf("[CORP] Alliance rollup: dashboard via %s failed: %v", providers[i].characterName, errs[i])
+			skipped = append(skipped, corp.AllianceSkippedCharacter{
+				CharacterName: providers[i].characterName,
+				Reason:        fmt.Sprintf("dashboard build failed: %v", errs[i]),
+			})
+			continue
+		}
+		s.saveCorpDashboardSnapshots(userID, d)
+		built = append(built, d)
+	}
+
+	alliance := corp.BuildAllianceDashboard(built)
+	alliance.Skipped = skipped
+	writeJSON(w, alliance)
+}
+
+// handleCorpAllianceCorp returns the full dashboard of one corporation from
+// the alliance rollup, for drilling into the per-corp breakdown.
+//
+//	GET /api/corp/alliance/{corporationID}?mode=auto
+func (s *Server) handleCorpAllianceCorp(w http.ResponseWriter, r *http.Request) {
+	corpID, err := strconv.ParseInt(r.PathValue("corporationID"), 10, 32)
+	if err != nil || corpID <= 0 {
+		writeError(w, 400, "invalid corporation id")
+		return
+	}
+
+	var provider corp.CorpDataProvider
+	if r.URL.Query().Get("mode") != "demo" {
+		providers, _ := s.allianceCorpProviders(userIDFromRequest(r))
+		for _, p := range providers {
+			if p.corporationID == int32(corpID) {
+				provider = p.provider
+				break
+			}
+		}
+	}
+	if provider == nil {
+		demo, err := s.demoCorpData()
+		if err != nil {
+			writeCorpProviderError(w, err)
+			return
+		}
+		if demo.GetInfo().CorporationID != int32(corpID) {
+			writeError(w, 404, "corporation not found among your director tokens")
+			return
+		}
+		provider = demo
+	}
+
+	dashboard, err := corp.BuildDashboard(provider, s.corpPrices(provider))
+	if err != nil {
+		writeError(w, 500, fmt.Sprintf("dashboard build failed: %v", err))
+		return
+	}
+	if !provider.IsDemo() {
+		dashboard.ESIIssues = s.corpESICompatReport().IssuesFor(dashboard.Layout)
+	}
+	writeJSON(w, dashboard)
+}
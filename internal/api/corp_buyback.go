@@ -0,0 +1,139 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"eve-flipper/internal/corp"
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/pricing"
+)
+
+type buybackRefreshRequest struct {
+	Percent     float64 `json:"percent"`
+	Mode        string  `json:"mode"`
+	PriceSource string  `json:"price_source"` // live mode: "esi" (default) or "fuzzwork"
+}
+
+// handleCorpBuybackBoard returns the latest published ore buyback board along
+// with the recent rate-change history.
+func (s *Server) handleCorpBuybackBoard(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	if s.db == nil {
+		writeJSON(w, map[string]interface{}{
+			"board":   nil,
+			"changes": []interface{}{},
+		})
+		return
+	}
+
+	board, err := s.db.LatestCorpBuybackBoardForUser(userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load buyback board")
+		return
+	}
+	limit := 20
+	if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 {
+		limit = v
+	}
+	changes, err := s.db.ListCorpBuybackRateChangesForUser(userID, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load buyback history")
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"board":   board,
+		"changes": changes,
+	})
+}
+
+// handleCorpBuybackRefresh regenerates the board from current Jita buy prices
+// (or demo prices), diffs it against the last published board and stores it.
+func (s *Server) handleCorpBuybackRefresh(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+
+	var req buybackRefreshRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+	}
+	if req.Mode == "" {
+		req.Mode = r.URL.Query().Get("mode")
+	}
+	if req.PriceSource == "" {
+		req.PriceSource = r.URL.Query().Get("price_source")
+	}
+
+	var previous *corp.PriceBoard
+	if s.db != nil {
+		prev, err := s.db.LatestCorpBuybackBoardForUser(userID)
+		if err != nil {
+			log.Printf("[CORP] Failed to load previous buyback board: %v", err)
+		}
+		previous = prev
+	}
+	if req.Percent <= 0 && previous != nil {
+		req.Percent = previous.Percent
+	}
+
+	var prices corp.PriceMap
+	source := "demo"
+	if req.Mode == "live" {
+		src, err := s.priceSources.Get(req.PriceSource, pricing.SourceESI)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		typeIDs := make([]int32, len(corp.BuybackItems))
+		for i, item := range corp.BuybackItems {
+			typeIDs[i] = item.TypeID
+		}
+		prices, err = fetchJitaBuyPrices(src, typeIDs)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, fmt.Sprintf("failed to fetch Jita prices: %v", err))
+			return
+		}
+		source = "jita_buy"
+		if src.Name() != pricing.SourceESI {
+			source = "jita_buy_" + src.Name()
+		}
+	} else {
+		prices = corp.DemoJitaBuyPrices()
+	}
+
+	// Only diff against a board built from the same price source.
+	if previous != nil && previous.Source != source {
+		previous = nil
+	}
+	board := corp.BuildPriceBoard(prices, req.Percent, source, previous, time.Now())
+
+	if s.db != nil {
+		if err := s.db.SaveCorpBuybackBoardForUser(userID, board); err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to save buyback board")
+			return
+		}
+	}
+	writeJSON(w, board)
+}
+
+// fetchJitaBuyPrices returns the highest Jita 4-4 buy order per item type
+// from src. Types without buy orders in the station are omitted.
+func fetchJitaBuyPrices(src pricing.PriceSource, typeIDs []int32) (corp.PriceMap, error) {
+	quotes, err := src.Quotes(pricing.Hub{RegionID: engine.JitaRegionID, StationID: engine.JitaStationID}, typeIDs)
+	if err != nil {
+		return nil, err
+	}
+	prices := make(corp.PriceMap, len(quotes))
+	for typeID, q := range quotes {
+		if q.Buy > 0 {
+			prices[typeID] = q.Buy
+		}
+	}
+	return prices, nil
+}
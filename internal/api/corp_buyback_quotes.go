@@ -0,0 +1,359 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"eve-flipper/internal/corp"
+	"eve-flipper/internal/db"
+	"eve-flipper/internal/pricing"
+)
+
+// maxBuybackQuoteTypes bounds how many distinct types one quote may price.
+const maxBuybackQuoteTypes = 500
+
+type buybackQuoteRequest struct {
+	Items       string `json:"items"` // pasted inventory or multibuy text
+	SellerName  string `json:"seller_name"`
+	Mode        string `json:"mode"`
+	PriceSource string `json:"price_source"` // live mode: "esi" (default) or "fuzzwork"
+	Save        bool   `json:"save"`
+}
+
+type buybackAcceptRequest struct {
+	SellerID   int64  `json:"seller_id"`
+	SellerName string `json:"seller_name"`
+}
+
+// handleGetCorpBuybackRules returns the buyback percent per item category.
+func (s *Server) handleGetCorpBuybackRules(w http.ResponseWriter, r *http.Request) {
+	rules := corp.DefaultBuybackRules()
+	if s.db != nil {
+		stored, err := s.db.GetCorpBuybackRulesForUser(userIDFromRequest(r))
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to load buyback rules")
+			return
+		}
+		rules = stored
+	}
+	writeJSON(w, map[string]interface{}{
+		"rules":      rules,
+		"categories": corp.BuybackCategories,
+	})
+}
+
+// handlePutCorpBuybackRules stores the buyback percent per item category.
+// Unknown categories are dropped; a category at 0 is not bought.
+func (s *Server) handlePutCorpBuybackRules(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	var rules corp.BuybackRules
+	if err := json.NewDecoder(r.Body).Decode(&rules); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	saved, err := s.db.SaveCorpBuybackRulesForUser(userIDFromRequest(r), rules)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to save buyback rules")
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"rules":      saved,
+		"categories": corp.BuybackCategories,
+	})
+}
+
+// handleCorpBuybackQuote prices a pasted item list against the buyback
+// rules using Jita buy prices (live, or demo prices by default). With
+// "save" the quote is stored so it can later be accepted and reconciled.
+func (s *Server) handleCorpBuybackQuote(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	if sdeData == nil {
+		writeError(w, http.StatusServiceUnavailable, "SDE not loaded yet")
+		return
+	}
+
+	var req buybackQuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	items, unknown := corp.ParseBuybackItems(sdeData, req.Items)
+	if len(items) == 0 {
+		writeError(w, http.StatusBadRequest, "no known items in the pasted list")
+		return
+	}
+	if len(items) > maxBuybackQuoteTypes {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("too many item types (max %d)", maxBuybackQuoteTypes))
+		return
+	}
+	if req.Save && s.db == nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+
+	userID := userIDFromRequest(r)
+	rules := corp.DefaultBuybackRules()
+	if s.db != nil {
+		stored, err := s.db.GetCorpBuybackRulesForUser(userID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to load buyback rules")
+			return
+		}
+		rules = stored
+	}
+
+	var prices corp.PriceMap
+	source := "demo"
+	if req.Mode == "live" {
+		src, err := s.priceSources.Get(req.PriceSource, pricing.SourceESI)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		typeIDs := make([]int32, len(items))
+		for i, item := range items {
+			typeIDs[i] = item.TypeID
+		}
+		prices, err = fetchJitaBuyPrices(src, typeIDs)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, fmt.Sprintf("failed to fetch Jita prices: %v", err))
+			return
+		}
+		source = "jita_buy"
+		if src.Name() != pricing.SourceESI {
+			source = "jita_buy_" + src.Name()
+		}
+	} else {
+		prices = corp.DemoJitaBuyPrices()
+	}
+
+	quote := corp.BuildBuybackQuote(items, prices, rules, source, time.Now())
+	quote.Unknown = unknown
+
+	var id int64
+	if req.Save {
+		var err error
+		id, err = s.db.SaveCorpBuybackQuoteForUser(userID, 0, strings.TrimSpace(req.SellerName), quote)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to save buyback quote")
+			return
+		}
+	}
+	writeJSON(w, map[string]interface{}{
+		"id":    id,
+		"quote": quote,
+	})
+}
+
+// handleCorpBuybackQuotes lists stored quotes, optionally by ?status=.
+func (s *Server) handleCorpBuybackQuotes(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeJSON(w, []db.CorpBuybackQuoteRecord{})
+		return
+	}
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", db.CorpBuybackQuoted, db.CorpBuybackAccepted, db.CorpBuybackReconciled:
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown status %q", status))
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	quotes, err := s.db.ListCorpBuybackQuotesForUser(userIDFromRequest(r), status, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load buyback quotes")
+		return
+	}
+	writeJSON(w, quotes)
+}
+
+// handleCorpBuybackAccept records that the corporation accepted a saved
+// quote from a seller; the payment is matched later by reconcile.
+func (s *Server) handleCorpBuybackAccept(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid quote id")
+		return
+	}
+	var req buybackAcceptRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+	}
+
+	userID := userIDFromRequest(r)
+	rec, err := s.db.GetCorpBuybackQuoteForUser(userID, id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load buyback quote")
+		return
+	}
+	if rec == nil {
+		writeError(w, http.StatusNotFound, "quote not found")
+		return
+	}
+	if rec.Total <= 0 {
+		writeError(w, http.StatusBadRequest, "quote has nothing the corporation buys")
+		return
+	}
+	provider, err := s.corpProvider(r)
+	if err != nil {
+		writeCorpProviderError(w, err)
+		return
+	}
+	sellerName := strings.TrimSpace(req.SellerName)
+	if sellerName == "" {
+		sellerName = rec.SellerName
+	}
+	if req.SellerID <= 0 && sellerName == "" {
+		writeError(w, http.StatusBadRequest, "seller_id or seller_name is required")
+		return
+	}
+
+	ok, err := s.db.AcceptCorpBuybackQuoteForUser(userID, id, provider.GetInfo().CorporationID, req.SellerID, sellerName, time.Now())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to accept buyback quote")
+		return
+	}
+	if !ok {
+		writeError(w, http.StatusConflict, fmt.Sprintf("quote is already %s", rec.Status))
+		return
+	}
+	rec, err = s.db.GetCorpBuybackQuoteForUser(userID, id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load buyback quote")
+		return
+	}
+	writeJSON(w, rec)
+}
+
+// handleCorpBuybackReconcile matches accepted quotes to the corp wallet
+// payments that settled them, oldest quote first. The journal archive is
+// synced first when stale, the same way the journal view does.
+func (s *Server) handleCorpBuybackReconcile(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	provider, err := s.corpProvider(r)
+	if err != nil {
+		writeCorpProviderError(w, err)
+		return
+	}
+	corporationID := provider.GetInfo().CorporationID
+	userID := userIDFromRequest(r)
+
+	pending, err := s.db.ListCorpBuybackQuotesForUser(userID, db.CorpBuybackAccepted, 0)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load buyback quotes")
+		return
+	}
+	var quotes []db.CorpBuybackQuoteRecord
+	var earliest time.Time
+	for _, q := range pending {
+		if q.CorporationID != corporationID {
+			continue
+		}
+		acceptedAt, err := time.Parse(time.RFC3339, q.AcceptedAt)
+		if err != nil {
+			continue
+		}
+		if earliest.IsZero() || acceptedAt.Before(earliest) {
+			earliest = acceptedAt
+		}
+		quotes = append(quotes, q)
+	}
+	reconciled := []db.CorpBuybackQuoteRecord{}
+	if len(quotes) == 0 {
+		writeJSON(w, map[string]interface{}{"reconciled": reconciled, "pending": 0})
+		return
+	}
+	sort.Slice(quotes, func(i, j int) bool { return quotes[i].AcceptedAt < quotes[j].AcceptedAt })
+
+	lastSync, err := s.db.LatestCorpJournalSyncForUser(userID, corporationID)
+	if err != nil {
+		log.Printf("[CORP] Failed to read journal sync time: %v", err)
+	}
+	if r.URL.Query().Get("refresh") == "1" || time.Since(lastSync) > corpJournalSyncInterval {
+		if err := s.syncCorpJournal(userID, corporationID, provider); err != nil {
+			if lastSync.IsZero() {
+				writeError(w, http.StatusBadGateway, err.Error())
+				return
+			}
+			log.Printf("[CORP] Journal sync failed, reconciling against stored rows: %v", err)
+		}
+	}
+
+	var entries []corp.CorpJournalEntry
+	divisions := make(map[int64]int)
+	q := db.CorpJournalQuery{
+		CorporationID: corporationID,
+		RefTypes:      corp.BuybackPaymentRefTypes,
+		Direction:     "out",
+		Since:         earliest.Add(-24 * time.Hour).Format(time.RFC3339),
+		Limit:         1000,
+	}
+	for {
+		page, err := s.db.QueryCorpJournalForUser(userID, q)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to load corp journal")
+			return
+		}
+		for _, row := range page.Entries {
+			entries = append(entries, row.CorpJournalEntry)
+			divisions[row.ID] = row.Division
+		}
+		q.Offset += len(page.Entries)
+		if len(page.Entries) == 0 || q.Offset >= page.Total {
+			break
+		}
+	}
+
+	used, err := s.db.CorpBuybackJournalEntriesForUser(userID, corporationID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load reconciled payments")
+		return
+	}
+	now := time.Now()
+	for _, quote := range quotes {
+		acceptedAt, _ := time.Parse(time.RFC3339, quote.AcceptedAt)
+		entry, ok := corp.MatchBuybackPayment(quote.Total, quote.SellerID, quote.SellerName, acceptedAt, entries, used)
+		if !ok {
+			continue
+		}
+		done, err := s.db.ReconcileCorpBuybackQuoteForUser(userID, quote.ID, divisions[entry.ID], entry.ID, now)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to reconcile buyback quote")
+			return
+		}
+		if !done {
+			continue
+		}
+		used[entry.ID] = true
+		quote.Status = db.CorpBuybackReconciled
+		quote.JournalDivision = divisions[entry.ID]
+		quote.JournalEntryID = entry.ID
+		quote.ReconciledAt = now.UTC().Format(time.RFC3339)
+		reconciled = append(reconciled, quote)
+	}
+	writeJSON(w, map[string]interface{}{
+		"reconciled": reconciled,
+		"pending":    len(quotes) - len(reconciled),
+	})
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"eve-flipper/internal/corp"
+)
+
+// corpDashboardLayoutResponse is the saved layout plus the section catalogue
+// so clients can render toggles for sections they do not know yet.
+type corpDashboardLayoutResponse struct {
+	Layout   corp.DashboardLayout `json:"layout"`
+	Sections []string             `json:"sections"`
+	Stored   bool                 `json:"stored"`
+}
+
+func (s *Server) handleGetCorpDashboardLayout(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeJSON(w, corpDashboardLayoutResponse{Layout: corp.DefaultDashboardLayout(), Sections: corp.DashboardSections})
+		return
+	}
+	layout, stored, err := s.db.LoadCorpDashboardLayoutForUser(userIDFromRequest(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load dashboard layout")
+		return
+	}
+	writeJSON(w, corpDashboardLayoutResponse{Layout: layout, Sections: corp.DashboardSections, Stored: stored})
+}
+
+// handlePutCorpDashboardLayout saves section order and toggles. Unknown
+// section names are dropped; sections missing from order are appended.
+func (s *Server) handlePutCorpDashboardLayout(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	var layout corp.DashboardLayout
+	if err := json.NewDecoder(r.Body).Decode(&layout); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	saved, err := s.db.SaveCorpDashboardLayoutForUser(userIDFromRequest(r), layout)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to save dashboard layout")
+		return
+	}
+	writeJSON(w, corpDashboardLayoutResponse{Layout: saved, Sections: corp.DashboardSections, Stored: true})
+}
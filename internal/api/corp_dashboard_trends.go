@@ -0,0 +1,118 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"eve-flipper/internal/corp"
+)
+
+// corpSnapshotInterval is how often every director corporation's dashboard
+// snapshot is taken in the background, so trends keep a daily balance and
+// P&L even when nobody opens the dashboard.
+const corpSnapshotInterval = 24 * time.Hour
+
+// startCorpSnapshotter runs snapshotCorpDashboards every corpSnapshotInterval.
+func (s *Server) startCorpSnapshotter() {
+	s.startBackgroundJob(backgroundJob{
+		name:     "corp_dashboard_snapshot",
+		interval: corpSnapshotInterval,
+		catchUp:  catchUpRun,
+		run:      s.snapshotCorpDashboards,
+	})
+}
+
+// snapshotCorpDashboards saves a snapshot of each corporation every user can
+// read as a director.
+func (s *Server) snapshotCorpDashboards(now time.Time) {
+	if s.db == nil || s.sessions == nil || s.esi == nil || !s.isReady() {
+		return
+	}
+	for _, userID := range s.sessions.UserIDs() {
+		providers, _ := s.allianceCorpProviders(userID)
+		for _, p := range providers {
+			dashboard, err := corp.BuildDashboardWithLayout(p.provider, s.corpPrices(p.provider), trendsDashboardLayout())
+			if err != nil {
+				log.Printf("[CORP] Dashboard snapshot via %s: %v", p.characterName, err)
+				continue
+			}
+			s.saveCorpDashboardSnapshots(userID, dashboard)
+		}
+	}
+}
+
+// saveCorpDashboardSnapshots persists today's balance and member counts plus
+// the dashboard's daily P&L, so trends outlive the ESI journal window.
+// Demo dashboards are never stored.
+func (s *Server) saveCorpDashboardSnapshots(userID string, dashboard *corp.CorpDashboard) {
+	if s.db == nil || dashboard == nil || dashboard.IsDemo {
+		return
+	}
+	rows := corp.DashboardSnapshots(dashboard, time.Now().UTC())
+	if err := s.db.SaveCorpDashboardSnapshotsForUser(userID, dashboard.Info.CorporationID, rows); err != nil {
+		log.Printf("[CORP] Failed to save dashboard snapshots: %v", err)
+	}
+}
+
+// trendsDashboardLayout limits the snapshot build to the sections that feed
+// the snapshot rows.
+func trendsDashboardLayout() corp.DashboardLayout {
+	return corp.DashboardLayout{Disabled: []string{
+		corp.SectionContributors, corp.SectionIndustry, corp.SectionMining,
+		corp.SectionMarket, corp.SectionFuel, corp.SectionProjection,
+	}}.Normalize()
+}
+
+// handleCorpDashboardTrends returns monthly revenue, expenses, closing
+// balance and member counts with month-over-month deltas. Live corps read
+// the persisted snapshots (taking today's first if none exists yet); demo
+// mode rolls up the demo dashboard's 90 days.
+//
+//	GET /api/corp/dashboard/trends?months=12
+func (s *Server) handleCorpDashboardTrends(w http.ResponseWriter, r *http.Request) {
+	provider, err := s.corpProvider(r)
+	if err != nil {
+		writeCorpProviderError(w, err)
+		return
+	}
+	months := 12
+	if v, err := strconv.Atoi(r.URL.Query().Get("months")); err == nil && v > 0 {
+		months = clampInt(v, 2, 36)
+	}
+	now := time.Now().UTC()
+
+	if provider.IsDemo() || s.db == nil {
+		dashboard, err := corp.BuildDashboardWithLayout(provider, s.corpPrices(provider), trendsDashboardLayout())
+		if err != nil {
+			writeError(w, 500, err.Error())
+			return
+		}
+		writeJSON(w, corp.BuildDashboardTrends(corp.DashboardSnapshots(dashboard, now), months, now))
+		return
+	}
+
+	userID := userIDFromRequest(r)
+	corpID := provider.GetInfo().CorporationID
+	since := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(months - 1), 0).Format("2006-01-02")
+	snapshots, err := s.db.ListCorpDashboardSnapshotsForUser(userID, corpID, since)
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
+	}
+	if n := len(snapshots); n == 0 || !snapshots[n-1].Captured || snapshots[n-1].Date != now.Format("2006-01-02") {
+		dashboard, err := corp.BuildDashboardWithLayout(provider, s.corpPrices(provider), trendsDashboardLayout())
+		if err != nil {
+			writeError(w, 500, err.Error())
+			return
+		}
+		s.saveCorpDashboardSnapshots(userID, dashboard)
+		if snapshots, err = s.db.ListCorpDashboardSnapshotsForUser(userID, corpID, since); err != nil {
+			writeError(w, 500, err.Error())
+			return
+		}
+	}
+
+	writeJSON(w, corp.BuildDashboardTrends(snapshots, months, now))
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"eve-flipper/internal/corp"
+)
+
+// corpESICompatTimeout bounds the swagger download (the spec is several MB).
+const corpESICompatTimeout = time.Minute
+
+// CheckCorpESICompat validates the corp ESI routes against the live swagger
+// spec and logs any drift. Run once in the background at startup; the result
+// is served by /api/corp/esi-compat and attached to live dashboards.
+func (s *Server) CheckCorpESICompat() corp.ESICompatReport {
+	ctx, cancel := context.WithTimeout(context.Background(), corpESICompatTimeout)
+	defer cancel()
+
+	spec, err := s.esi.FetchSwaggerSpec(ctx)
+	report := corp.CheckESICompat(spec, nil, time.Now())
+	if err != nil {
+		report.Error = err.Error()
+		log.Printf("[CORP] ESI compatibility check skipped: %v", err)
+	}
+	for _, issue := range report.Issues {
+		log.Printf("[CORP] ESI compatibility %s: %s", issue.Severity, issue.Message)
+	}
+
+	s.corpESICompatMu.Lock()
+	s.corpESICompat = &report
+	s.corpESICompatMu.Unlock()
+	return report
+}
+
+// corpESICompatReport returns the startup check merged with the deprecation
+// warnings ESI has returned on corp routes since.
+func (s *Server) corpESICompatReport() corp.ESICompatReport {
+	s.corpESICompatMu.RLock()
+	stored := s.corpESICompat
+	s.corpESICompatMu.RUnlock()
+
+	runtime := corp.CheckESICompat(nil, s.esi.RouteWarnings(), time.Now())
+	if stored == nil {
+		runtime.Error = "ESI compatibility check has not completed yet"
+		return runtime
+	}
+	report := *stored
+	report.Issues = append(append([]corp.ESICompatIssue{}, stored.Issues...), runtime.Issues...)
+	return report
+}
+
+func (s *Server) handleCorpESICompat(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("refresh") == "1" {
+		s.CheckCorpESICompat()
+	}
+	writeJSON(w, s.corpESICompatReport())
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"eve-flipper/internal/corp"
+	"eve-flipper/internal/engine"
+)
+
+// handleCorpIndustrySchedule reports industry slot utilization per
+// installer, upcoming job completions and idle-capacity alerts. Characters
+// the user has logged in contribute their real slot skills and personal
+// jobs; other installers are measured against the 11-slot maximum.
+//
+//	GET /api/corp/industry/schedule?horizon_hours=48
+func (s *Server) handleCorpIndustrySchedule(w http.ResponseWriter, r *http.Request) {
+	provider, err := s.corpProvider(r)
+	if err != nil {
+		writeCorpProviderError(w, err)
+		return
+	}
+
+	jobs, err := provider.GetIndustryJobs()
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
+	}
+	members, err := provider.GetMembers()
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
+	}
+
+	horizon := 48
+	if v, err := strconv.Atoi(r.URL.Query().Get("horizon_hours")); err == nil && v > 0 {
+		horizon = clampInt(v, 1, 24*14)
+	}
+
+	var skills map[int64]map[int32]int
+	var personal map[int64][]corp.CorpIndustryJob
+	if !provider.IsDemo() {
+		skills, personal = s.grantedIndustrySlots(userIDFromRequest(r))
+	}
+
+	writeJSON(w, corp.BuildIndustrySchedule(jobs, members, skills, personal, time.Duration(horizon)*time.Hour, time.Now().UTC()))
+}
+
+// grantedIndustrySlots reads slot skills and personal industry jobs for every
+// character the user has logged in. Characters whose skills cannot be read
+// are left out and fall back to assumed slots.
+func (s *Server) grantedIndustrySlots(userID string) (map[int64]map[int32]int, map[int64][]corp.CorpIndustryJob) {
+	skills := make(map[int64]map[int32]int)
+	personal := make(map[int64][]corp.CorpIndustryJob)
+	if s.sessions == nil {
+		return skills, personal
+	}
+	for _, sess := range s.sessions.ListForUser(userID) {
+		token, err := s.sessions.EnsureValidTokenForUserCharacter(s.sso, userID, sess.CharacterID)
+		if err != nil {
+			continue
+		}
+		sheet, err := s.esi.GetSkills(sess.CharacterID, token)
+		if err != nil {
+			continue
+		}
+		levels := make(map[int32]int, len(corp.IndustrySlotSkills))
+		for _, id := range corp.IndustrySlotSkills {
+			levels[id] = engine.SkillLevel(sheet, id)
+		}
+		skills[sess.CharacterID] = levels
+
+		own, err := s.esi.GetCharacterIndustryJobs(sess.CharacterID, token, false)
+		if err != nil {
+			continue
+		}
+		for _, j := range own {
+			personal[sess.CharacterID] = append(personal[sess.CharacterID], corp.CorpIndustryJob{
+				JobID:           int32(j.JobID),
+				InstallerID:     sess.CharacterID,
+				InstallerName:   sess.CharacterName,
+				Activity:        corp.IndustryActivityName(int(j.ActivityID)),
+				BlueprintTypeID: j.BlueprintTypeID,
+				ProductTypeID:   j.ProductTypeID,
+				ProductName:     j.ProductTypeName,
+				Status:          j.Status,
+				Runs:            j.Runs,
+				StartDate:       j.StartDate,
+				EndDate:         j.EndDate,
+				LocationID:      j.FacilityID,
+				LocationName:    j.FacilityName,
+			})
+		}
+	}
+	return skills, personal
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"eve-flipper/internal/corp"
+	"eve-flipper/internal/db"
+)
+
+const (
+	corpJournalSyncInterval = time.Hour
+	// corpJournalSyncDays is how far back a sync asks the provider; ESI
+	// itself only serves the last 30 days, older entries live in the archive.
+	corpJournalSyncDays = 90
+)
+
+// handleCorpJournal serves the archived corp wallet journal with filters and
+// pagination. The archive is refreshed from the provider when stale or when
+// ?refresh=1 is passed, so browsing a large journal does not hit ESI.
+//
+//	GET /api/corp/journal?division=&since=&until=&days=&ref_type=a,b&party_id=
+//	  &party=&q=&direction=in|out&limit=&offset=
+func (s *Server) handleCorpJournal(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	provider, err := s.corpProvider(r)
+	if err != nil {
+		writeCorpProviderError(w, err)
+		return
+	}
+	info := provider.GetInfo()
+	userID := userIDFromRequest(r)
+	query := r.URL.Query()
+
+	q := db.CorpJournalQuery{
+		CorporationID: info.CorporationID,
+		Party:         query.Get("party"),
+		Search:        query.Get("q"),
+		Direction:     query.Get("direction"),
+		Since:         query.Get("since"),
+		Until:         query.Get("until"),
+	}
+	if v, err := strconv.Atoi(query.Get("division")); err == nil && v >= 1 && v <= 7 {
+		q.Division = v
+	}
+	if v := strings.TrimSpace(query.Get("ref_type")); v != "" {
+		q.RefTypes = strings.Split(v, ",")
+	}
+	if v, err := strconv.ParseInt(query.Get("party_id"), 10, 64); err == nil && v > 0 {
+		q.PartyID = v
+	}
+	if v, err := strconv.Atoi(query.Get("days")); err == nil && v > 0 && q.Since == "" {
+		q.Since = time.Now().UTC().AddDate(0, 0, -v).Format(time.RFC3339)
+	}
+	if v, err := strconv.Atoi(query.Get("limit")); err == nil && v > 0 {
+		q.Limit = v
+	}
+	if v, err := strconv.Atoi(query.Get("offset")); err == nil && v > 0 {
+		q.Offset = v
+	}
+
+	lastSync, err := s.db.LatestCorpJournalSyncForUser(userID, info.CorporationID)
+	if err != nil {
+		log.Printf("[CORP] Failed to read journal sync time: %v", err)
+	}
+	if query.Get("refresh") == "1" || time.Since(lastSync) > corpJournalSyncInterval {
+		if err := s.syncCorpJournal(userID, info.CorporationID, provider); err != nil {
+			// Serve what is already stored; only fail when there is nothing.
+			if lastSync.IsZero() {
+				writeError(w, 500, err.Error())
+				return
+			}
+			log.Printf("[CORP] Journal sync failed, serving stored rows: %v", err)
+		}
+	}
+
+	page, err := s.db.QueryCorpJournalForUser(userID, q)
+	if err != nil {
+		writeError(w, 400, err.Error())
+		return
+	}
+	writeJSON(w, page)
+}
+
+// syncCorpJournal pulls every wallet division's journal into the archive.
+// Divisions that fail are skipped; the first error is returned only if no
+// division could be stored.
+func (s *Server) syncCorpJournal(userID string, corporationID int32, provider corp.CorpDataProvider) error {
+	var firstErr error
+	stored := 0
+	for division := 1; division <= 7; division++ {
+		entries, err := provider.GetJournal(division, corpJournalSyncDays)
+		if err == nil {
+			_, err = s.db.UpsertCorpJournalForUser(userID, corporationID, division, entries)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		stored++
+	}
+	if stored == 0 {
+		return firstErr
+	}
+	return nil
+}
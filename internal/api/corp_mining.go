@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"eve-flipper/internal/corp"
+)
+
+// handleCorpMiningReport values the mining ledger (moon ores at their refined
+// output) and returns monthly per-member rollups plus the moon extraction
+// calendar derived from the observer ledgers.
+//
+//	GET /api/corp/mining/report?yield=0.8
+func (s *Server) handleCorpMiningReport(w http.ResponseWriter, r *http.Request) {
+	provider, err := s.corpProvider(r)
+	if err != nil {
+		writeCorpProviderError(w, err)
+		return
+	}
+
+	entries, err := provider.GetMiningLedger()
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
+	}
+
+	yield, _ := strconv.ParseFloat(r.URL.Query().Get("yield"), 64)
+	yield = corp.NormalizeMiningRefineYield(yield)
+
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	prices := corp.MoonOreRefinePrices(sdeData, s.corpAdjustedPrices(provider), yield)
+
+	writeJSON(w, corp.BuildMiningReport(entries, prices, yield))
+}
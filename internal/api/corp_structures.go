@@ -0,0 +1,156 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"eve-flipper/internal/corp"
+)
+
+// The structure watcher polls the structures of every corporation a user can
+// read as a director and alerts once per condition (low fuel, offline
+// service, reinforcement timer) until it clears.
+const structureWatchInterval = 30 * time.Minute
+
+// handleCorpStructureMonitor returns fuel countdowns, offline services and
+// reinforcement timers for corp structures, with the alerts the watcher
+// would deliver.
+//
+//	GET /api/corp/structures/monitor?fuel_days=7
+func (s *Server) handleCorpStructureMonitor(w http.ResponseWriter, r *http.Request) {
+	provider, err := s.corpProvider(r)
+	if err != nil {
+		writeCorpProviderError(w, err)
+		return
+	}
+
+	structures, err := provider.GetStructures()
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
+	}
+
+	fuelDays := corp.DefaultStructureFuelAlertDays
+	if cfg := s.loadConfigForUser(userIDFromRequest(r)); cfg != nil && cfg.StructureFuelAlertDays > 0 {
+		fuelDays = cfg.StructureFuelAlertDays
+	}
+	if v, err := strconv.Atoi(r.URL.Query().Get("fuel_days")); err == nil && v > 0 {
+		fuelDays = clampInt(v, 1, 60)
+	}
+
+	writeJSON(w, corp.BuildStructureMonitor(structures, fuelDays, time.Now().UTC()))
+}
+
+// startStructureWatcher runs pollStructures every structureWatchInterval.
+// Delivered alert keys are persisted so a restart does not repeat them.
+func (s *Server) startStructureWatcher() {
+	s.startBackgroundJob(backgroundJob{
+		name:         "structure_watch",
+		interval:     structureWatchInterval,
+		catchUp:      catchUpRun,
+		run:          s.pollStructures,
+		saveState:    s.structureWatchSnapshot,
+		restoreState: s.restoreStructureWatch,
+	})
+}
+
+// structureWatchSnapshot encodes the delivered alert keys for persistence.
+func (s *Server) structureWatchSnapshot() string {
+	s.structureWatchMu.Lock()
+	raw, err := json.Marshal(s.structureWatch)
+	s.structureWatchMu.Unlock()
+	if err != nil {
+		return ""
+	}
+	return string(raw)
+}
+
+// restoreStructureWatch loads state saved by structureWatchSnapshot.
+func (s *Server) restoreStructureWatch(state string) {
+	var saved map[string]map[string]bool
+	if err := json.Unmarshal([]byte(state), &saved); err != nil {
+		log.Printf("[ALERT] Structure watch state: %v", err)
+		return
+	}
+	s.structureWatchMu.Lock()
+	s.structureWatch = saved
+	s.structureWatchMu.Unlock()
+}
+
+// pollStructures checks the structures of users that enabled structure
+// alerts and sends the alerts that are new since the last poll.
+func (s *Server) pollStructures(now time.Time) {
+	if s.db == nil || s.sessions == nil || s.esi == nil || !s.isReady() {
+		return
+	}
+	for _, userID := range s.sessions.UserIDs() {
+		cfg := s.loadConfigForUser(userID)
+		if cfg == nil || !cfg.StructureAlerts {
+			continue
+		}
+		providers, _ := s.allianceCorpProviders(userID)
+		if len(providers) == 0 {
+			continue
+		}
+
+		var current []corp.StructureAlert
+		failed := false
+		for _, p := range providers {
+			structures, err := p.provider.GetStructures()
+			if err != nil {
+				log.Printf("[ALERT] Structure watch (%s): %v", p.characterName, err)
+				failed = true
+				continue
+			}
+			current = append(current, corp.BuildStructureMonitor(structures, cfg.StructureFuelAlertDays, now.UTC()).Alerts...)
+		}
+
+		s.structureWatchMu.Lock()
+		if s.structureWatch == nil {
+			s.structureWatch = make(map[string]map[string]bool)
+		}
+		fresh, keys := structureAlertTransitions(s.structureWatch[userID], current, failed)
+		s.structureWatch[userID] = keys
+		s.structureWatchMu.Unlock()
+
+		for _, a := range fresh {
+			alert := AlertCheckResult{
+				ShouldAlert: true,
+				TypeName:    a.Name,
+				Metric:      "structure_" + a.Kind,
+				Threshold:   float64(cfg.StructureFuelAlertDays),
+				Message:     a.Message,
+			}
+			if err := s.SendAlert(userID, cfg, alert, nil); err != nil {
+				log.Printf("[ALERT] Structure alert %s: %v", a.Key, err)
+			}
+		}
+	}
+}
+
+// structureAlertTransitions returns the alerts not delivered before and the
+// keys to remember. Keys that cleared are forgotten so the condition alerts
+// again if it returns, except after a partial fetch failure (keep), where
+// the missing corp's alerts must not re-fire on the next good poll.
+func structureAlertTransitions(prev map[string]bool, current []corp.StructureAlert, partial bool) ([]corp.StructureAlert, map[string]bool) {
+	keys := make(map[string]bool, len(current))
+	if partial {
+		for k := range prev {
+			keys[k] = true
+		}
+	}
+	var fresh []corp.StructureAlert
+	for _, a := range current {
+		if keys[a.Key] {
+			continue
+		}
+		keys[a.Key] = true
+		if !prev[a.Key] {
+			fresh = append(fresh, a)
+		}
+	}
+	return fresh, keys
+}
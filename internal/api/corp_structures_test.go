@@ -0,0 +1,35 @@
+package api
+
+import (
+	"testing"
+
+	"eve-flipper/internal/corp"
+)
+
+func TestStructureAlertTransitions(t *testing.T) {
+	alerts := []corp.StructureAlert{{Key: "fuel_low:1"}, {Key: "service_offline:2:Market"}}
+	fresh, keys := structureAlertTransitions(nil, alerts, false)
+	if len(fresh) != 2 || len(keys) != 2 {
+		t.Fatalf("first poll should deliver current alerts: %v", fresh)
+	}
+
+	// Low fuel escalates to critical; the offline service is still offline.
+	alerts = []corp.StructureAlert{{Key: "fuel_critical:1"}, {Key: "service_offline:2:Market"}}
+	fresh, keys = structureAlertTransitions(keys, alerts, false)
+	if len(fresh) != 1 || fresh[0].Key != "fuel_critical:1" || keys["fuel_low:1"] {
+		t.Fatalf("fresh = %v, keys = %v", fresh, keys)
+	}
+
+	// A corp that failed to load keeps its delivered keys.
+	fresh, keys = structureAlertTransitions(keys, nil, true)
+	if len(fresh) != 0 || !keys["service_offline:2:Market"] {
+		t.Fatalf("partial poll: fresh = %v, keys = %v", fresh, keys)
+	}
+
+	// Once cleared, the condition alerts again when it returns.
+	_, keys = structureAlertTransitions(keys, nil, false)
+	fresh, _ = structureAlertTransitions(keys, alerts[1:], false)
+	if len(fresh) != 1 {
+		t.Fatalf("returning condition should alert again: %v", fresh)
+	}
+}
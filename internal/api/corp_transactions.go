@@ -0,0 +1,104 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"eve-flipper/internal/corp"
+	"eve-flipper/internal/db"
+)
+
+// corpTransactionSyncInterval matches the ESI cache window for corp wallet
+// transactions; within it the persisted rows are served without refetching.
+const corpTransactionSyncInterval = time.Hour
+
+// handleCorpTransactions serves persisted corp wallet transactions with
+// filters, grouping (type, member, day) and pagination. Stored rows are
+// refreshed from the provider when stale or when ?refresh=1 is passed.
+func (s *Server) handleCorpTransactions(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	provider, err := s.corpProvider(r)
+	if err != nil {
+		writeCorpProviderError(w, err)
+		return
+	}
+	info := provider.GetInfo()
+	userID := userIDFromRequest(r)
+	query := r.URL.Query()
+
+	q := db.CorpTransactionQuery{
+		CorporationID: info.CorporationID,
+		Side:          query.Get("side"),
+		Since:         query.Get("since"),
+		Until:         query.Get("until"),
+		Search:        query.Get("q"),
+		GroupBy:       query.Get("group_by"),
+	}
+	if v, err := strconv.Atoi(query.Get("division")); err == nil && v >= 1 && v <= 7 {
+		q.Division = v
+	}
+	if v, err := strconv.ParseInt(query.Get("type_id"), 10, 32); err == nil && v > 0 {
+		q.TypeID = int32(v)
+	}
+	if v, err := strconv.ParseInt(query.Get("client_id"), 10, 64); err == nil && v > 0 {
+		q.ClientID = v
+	}
+	if v, err := strconv.Atoi(query.Get("limit")); err == nil && v > 0 {
+		q.Limit = v
+	}
+	if v, err := strconv.Atoi(query.Get("offset")); err == nil && v > 0 {
+		q.Offset = v
+	}
+
+	lastSync, err := s.db.LatestCorpTransactionSyncForUser(userID, info.CorporationID)
+	if err != nil {
+		log.Printf("[CORP] Failed to read transaction sync time: %v", err)
+	}
+	if query.Get("refresh") == "1" || time.Since(lastSync) > corpTransactionSyncInterval {
+		if err := s.syncCorpTransactions(userID, info.CorporationID, provider); err != nil {
+			// Serve what is already stored; only fail when there is nothing.
+			if lastSync.IsZero() {
+				writeError(w, 500, err.Error())
+				return
+			}
+			log.Printf("[CORP] Transaction sync failed, serving stored rows: %v", err)
+		}
+	}
+
+	page, err := s.db.QueryCorpTransactionsForUser(userID, q)
+	if err != nil {
+		writeError(w, 400, err.Error())
+		return
+	}
+	writeJSON(w, page)
+}
+
+// syncCorpTransactions pulls every wallet division from the provider into the
+// archive. Divisions that fail are skipped; the first error is returned only
+// if no division could be stored.
+func (s *Server) syncCorpTransactions(userID string, corporationID int32, provider corp.CorpDataProvider) error {
+	var firstErr error
+	stored := 0
+	for division := 1; division <= 7; division++ {
+		txns, err := provider.GetTransactions(division)
+		if err == nil {
+			err = s.db.UpsertCorpTransactionsForUser(userID, corporationID, division, txns)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		stored++
+	}
+	if stored == 0 {
+		return firstErr
+	}
+	return nil
+}
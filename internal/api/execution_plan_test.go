@@ -13,6 +13,7 @@ import (
 	"eve-flipper/internal/config"
 	"eve-flipper/internal/engine"
 	"eve-flipper/internal/esi"
+	"eve-flipper/internal/sde"
 )
 
 func TestFilterExecutionPlanOrders_StructureScopedLocation(t *testing.T) {
@@ -201,3 +202,56 @@ func TestHandleExecutionPlanQuoteTrueReturnsCanonicalQuoteFields(t *testing.T) {
 		t.Fatalf("sell cache age = %d, want about 180", quote.Cache.SellAgeSeconds)
 	}
 }
+
+func TestHandleExecutionPlanSideDerivesRegionFromLocation(t *testing.T) {
+	const (
+		typeID   = int32(34)
+		regionID = int32(10000002)
+		systemID = int32(30000142)
+		location = int64(60003760)
+	)
+
+	var fetched []string
+	origFetchRegionOrders := executionFetchRegionOrders
+	executionFetchRegionOrders = func(_ *esi.Client, region int32, orderType string) ([]esi.MarketOrder, error) {
+		fetched = append(fetched, fmt.Sprintf("%d:%s", region, orderType))
+		return []esi.MarketOrder{
+			{TypeID: typeID, SystemID: systemID, LocationID: location, Price: 5, VolumeRemain: 100, IsBuyOrder: true},
+			{TypeID: typeID, SystemID: systemID, LocationID: location, Price: 4, VolumeRemain: 100, IsBuyOrder: true},
+		}, nil
+	}
+	t.Cleanup(func() { executionFetchRegionOrders = origFetchRegionOrders })
+
+	srv := NewServer(config.Default(), &esi.Client{}, nil, nil, nil)
+	srv.sdeData = &sde.Data{
+		Stations: map[int64]*sde.Station{location: {ID: location, SystemID: systemID}},
+		Systems:  map[int32]*sde.SolarSystem{systemID: {ID: systemID, RegionID: regionID}},
+	}
+
+	body := []byte(`{"type_id": 34, "location_id": 60003760, "quantity": 150, "is_buy": true, "side": "sell"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/execution/plan", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if len(fetched) != 1 || fetched[0] != "10000002:buy" {
+		t.Fatalf("fetched = %v, want [10000002:buy]", fetched)
+	}
+	var got engine.ExecutionPlanResult
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got.BestPrice != 5 || got.TotalDepth != 200 {
+		t.Fatalf("best/depth = %v/%d, want 5/200", got.BestPrice, got.TotalDepth)
+	}
+
+	bad := httptest.NewRequest(http.MethodPost, "/api/execution/plan",
+		bytes.NewReader([]byte(`{"type_id": 34, "region_id": 10000002, "quantity": 1, "side": "hold"}`)))
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, bad)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid side status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"eve-flipper/internal/engine"
+)
+
+type executionScheduleRequest struct {
+	TypeID              int32   `json:"type_id"`
+	RegionID            int32   `json:"region_id"` // 0 = derived from location_id
+	LocationID          int64   `json:"location_id"`
+	Quantity            int64   `json:"quantity"`
+	Side                string  `json:"side"` // "buy" | "sell"
+	MaxParticipationPct float64 `json:"max_participation_pct"`
+	DeadlineDays        int     `json:"deadline_days"`
+	Profile             string  `json:"profile"`    // twap | vwap
+	StartDate           string  `json:"start_date"` // YYYY-MM-DD, default today
+}
+
+// handleExecutionSchedule plans a large position over several days. The
+// reference price is the live best price on the side being hit; progress
+// counts the user's archived wallet transactions of the type and side since
+// the start date.
+func (s *Server) handleExecutionSchedule(w http.ResponseWriter, r *http.Request) {
+	var req executionScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	isBuy, ok := executionSide(req.Side, false)
+	if !ok || strings.TrimSpace(req.Side) == "" {
+		writeError(w, 400, "side must be buy or sell")
+		return
+	}
+	if req.RegionID == 0 && req.LocationID != 0 {
+		_, req.RegionID = s.executionLocationRegion(req.LocationID)
+	}
+	if req.RegionID == 0 || req.TypeID == 0 || req.Quantity <= 0 {
+		writeError(w, 400, "region_id (or a known location_id), type_id and positive quantity required")
+		return
+	}
+	profile := strings.ToLower(strings.TrimSpace(req.Profile))
+	if profile != "" && profile != engine.ScheduleProfileTWAP && profile != engine.ScheduleProfileVWAP {
+		writeError(w, 400, "profile must be twap or vwap")
+		return
+	}
+	now := time.Now().UTC()
+	start := now.Truncate(24 * time.Hour)
+	if req.StartDate != "" {
+		t, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			writeError(w, 400, "start_date must be YYYY-MM-DD")
+			return
+		}
+		start = t
+	}
+
+	orderType := "buy"
+	if isBuy {
+		orderType = "sell"
+	}
+	orders, err := s.fetchExecutionOrders(r, req.RegionID, req.LocationID, orderType)
+	if err != nil {
+		writeError(w, executionOrdersErrorStatus(err), err.Error())
+		return
+	}
+	book := filterExecutionPlanOrders(orders, req.TypeID, 0, req.LocationID)
+	refPrice := engine.ComputeExecutionPlan(book, 1, isBuy).BestPrice
+
+	history, _ := s.cachedMarketHistory(req.RegionID, req.TypeID)
+	params := engine.CalibrateImpact(history, engine.DefaultImpactDays)
+	if s.db != nil {
+		if stored, ok := s.db.GetImpactCoefficients(req.RegionID, req.TypeID); ok {
+			params = withImpactFit(params, stored)
+		}
+	}
+
+	schedule := engine.BuildExecutionSchedule(params, history, engine.ScheduleRequest{
+		Quantity:         req.Quantity,
+		IsBuy:            isBuy,
+		MaxParticipation: clampFloat64(req.MaxParticipationPct, 0, 100) / 100,
+		DeadlineDays:     clampInt(req.DeadlineDays, 0, 365),
+		Profile:          profile,
+		Start:            start,
+		RefPrice:         refPrice,
+	})
+
+	if userID := userIDFromRequest(r); userID != "" && s.db != nil && !start.After(now) {
+		txns, err := s.db.ListArchivedWalletTransactions(userID, nil, start, 100000)
+		if err == nil {
+			var fills []engine.ScheduleFill
+			for _, tx := range txns {
+				if tx.TypeID != req.TypeID || tx.IsBuy != isBuy {
+					continue
+				}
+				at, err := time.Parse(time.RFC3339, tx.Date)
+				if err != nil {
+					continue
+				}
+				fills = append(fills, engine.ScheduleFill{At: at, Quantity: int64(tx.Quantity), Price: tx.UnitPrice})
+			}
+			engine.TrackScheduleProgress(&schedule, fills, now)
+		}
+	}
+
+	writeJSON(w, schedule)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/esi"
+)
+
+func TestHandleExecutionScheduleTracksWalletFills(t *testing.T) {
+	const userID = "u-schedule"
+	database := openAPITestDB(t)
+	now := time.Now().UTC()
+	var history []esi.HistoryEntry
+	for i := 0; i < 30; i++ {
+		history = append(history, esi.HistoryEntry{
+			Date:    now.AddDate(0, 0, i-30).Format("2006-01-02"),
+			Average: 200e6 + float64(i%4)*1e6,
+			Highest: 205e6,
+			Lowest:  195e6,
+			Volume:  40,
+		})
+	}
+	database.SetMarketHistory(10000002, 12005, history)
+	start := now.AddDate(0, 0, -2).Truncate(24 * time.Hour)
+	if _, err := database.UpsertWalletTransactionsForUser(userID, 1001, []esi.WalletTransaction{
+		{TransactionID: 1, Date: start.Add(time.Hour).Format(time.RFC3339), TypeID: 12005, LocationID: 60003760, UnitPrice: 199e6, Quantity: 6},
+		{TransactionID: 2, Date: start.Add(26 * time.Hour).Format(time.RFC3339), TypeID: 12005, LocationID: 60003760, UnitPrice: 198e6, Quantity: 5},
+		{TransactionID: 3, Date: start.Add(27 * time.Hour).Format(time.RFC3339), TypeID: 12005, LocationID: 60003760, UnitPrice: 190e6, Quantity: 3, IsBuy: true},
+	}); err != nil {
+		t.Fatalf("UpsertWalletTransactionsForUser: %v", err)
+	}
+
+	origFetchRegionOrders := executionFetchRegionOrders
+	executionFetchRegionOrders = func(_ *esi.Client, _ int32, orderType string) ([]esi.MarketOrder, error) {
+		if orderType != "buy" {
+			t.Fatalf("order type = %s, want buy (selling hits bids)", orderType)
+		}
+		return []esi.MarketOrder{
+			{TypeID: 12005, SystemID: 30000142, LocationID: 60003760, Price: 198e6, VolumeRemain: 3, IsBuyOrder: true},
+		}, nil
+	}
+	t.Cleanup(func() { executionFetchRegionOrders = origFetchRegionOrders })
+
+	srv := NewServer(config.Default(), &esi.Client{}, database, nil, nil)
+	body, _ := json.Marshal(map[string]interface{}{
+		"type_id":               12005,
+		"region_id":             10000002,
+		"quantity":              500,
+		"side":                  "sell",
+		"max_participation_pct": 20,
+		"start_date":            start.Format("2006-01-02"),
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/execution/schedule", bytes.NewReader(body))
+	addSignedUserCookie(req, srv, userID)
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var got engine.ExecutionSchedule
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got.RefPrice != 198e6 || got.TotalDays != 63 || got.Days[0].Quantity != 8 {
+		t.Fatalf("schedule = ref %v days %d first %d; want 198e6, 63, 8", got.RefPrice, got.TotalDays, got.Days[0].Quantity)
+	}
+	if got.Progress == nil || got.Progress.FilledQty != 11 {
+		t.Fatalf("progress = %+v, want 11 units sold", got.Progress)
+	}
+	if got.Days[0].ActualQty != 6 || got.Days[1].ActualQty != 5 {
+		t.Fatalf("actual qty = %d, %d; want 6, 5", got.Days[0].ActualQty, got.Days[1].ActualQty)
+	}
+
+	bad := httptest.NewRequest(http.MethodPost, "/api/execution/schedule",
+		bytes.NewReader([]byte(`{"type_id": 12005, "region_id": 10000002, "quantity": 5}`)))
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, bad)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("missing side status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"eve-flipper/internal/db"
+	"eve-flipper/internal/engine"
+)
+
+// handleExport renders flip or contract results of a stored scan, the order
+// desk or the corp wallet journal as CSV or XLSX for spreadsheet users.
+//
+//	GET /api/export/{kind}?format=csv|xlsx
+//	  kind: flips|contracts (?scan_id=), order-desk (order desk query and
+//	  auth scope), corp-journal (?division=&days=&mode=)
+//	  CSV only: decimal=comma (then ";" between fields unless delimiter=
+//	  comma|semicolon|tab says otherwise)
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	format := strings.ToLower(strings.TrimSpace(q.Get("format")))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "xlsx" {
+		writeError(w, 400, "format must be csv or xlsx")
+		return
+	}
+
+	var table exportTable
+	kind := r.PathValue("kind")
+	switch kind {
+	case "flips", "contracts":
+		if s.db == nil {
+			writeError(w, http.StatusServiceUnavailable, "database unavailable")
+			return
+		}
+		scanID, err := strconv.ParseInt(q.Get("scan_id"), 10, 64)
+		if err != nil || scanID <= 0 {
+			writeError(w, 400, "scan_id is required")
+			return
+		}
+		record := s.db.GetHistoryByID(scanID)
+		if record == nil {
+			writeError(w, 404, "not found")
+			return
+		}
+		if kind == "contracts" {
+			table = contractResultsExportTable(s.filterContractResultsMarketDisabled(s.db.GetContractResults(scanID)))
+			break
+		}
+		rows := s.db.GetFlipResults(scanID)
+		if record.Tab == "region" {
+			if dayRows := s.db.GetRegionalDayResults(scanID); len(dayRows) > 0 {
+				rows = dayRows
+			}
+		}
+		table = flipResultsExportTable(filterFlipResultsMarketDisabled(rows))
+	case "order-desk":
+		desk, ok := s.orderDeskForRequest(w, r)
+		if !ok {
+			return
+		}
+		table = orderDeskExportTable(desk.Orders)
+	case "corp-journal":
+		provider, err := s.corpProvider(r)
+		if err != nil {
+			writeCorpProviderError(w, err)
+			return
+		}
+		division := 1
+		if v, convErr := strconv.Atoi(q.Get("division")); convErr == nil && v >= 1 && v <= 7 {
+			division = v
+		}
+		days := 90
+		if v, convErr := strconv.Atoi(q.Get("days")); convErr == nil && v > 0 {
+			days = clampInt(v, 1, 90)
+		}
+		journal, err := provider.GetJournal(division, days)
+		if err != nil {
+			writeError(w, 500, err.Error())
+			return
+		}
+		table = exportTable{
+			Sheet:   "Corp journal",
+			Headers: []string{"ID", "Date", "Type", "Amount", "Balance", "First party", "Second party", "Description"},
+		}
+		for _, e := range journal {
+			table.Rows = append(table.Rows, []interface{}{
+				e.ID, e.Date, e.RefType, e.Amount, e.Balance, e.FirstPartyName, e.SecondPartyName, e.Description,
+			})
+		}
+	default:
+		writeError(w, 404, "unknown export kind")
+		return
+	}
+
+	var buf bytes.Buffer
+	filename := fmt.Sprintf("eve-flipper-%s-%s.%s", kind, time.Now().UTC().Format("20060102-150405"), format)
+	contentType := "text/csv; charset=utf-8"
+	if format == "xlsx" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		if err := writeExportXLSX(&buf, table); err != nil {
+			writeError(w, 500, err.Error())
+			return
+		}
+	} else {
+		opts := exportCSVOptions{DecimalComma: q.Get("decimal") == "comma", Delimiter: ','}
+		if opts.DecimalComma {
+			opts.Delimiter = ';'
+		}
+		switch q.Get("delimiter") {
+		case "comma":
+			opts.Delimiter = ','
+		case "semicolon":
+			opts.Delimiter = ';'
+		case "tab":
+			opts.Delimiter = '\t'
+		}
+		if opts.DecimalComma && opts.Delimiter == ',' {
+			writeError(w, 400, "decimal comma needs a semicolon or tab delimiter")
+			return
+		}
+		if err := writeExportCSV(&buf, table, opts); err != nil {
+			writeError(w, 500, err.Error())
+			return
+		}
+	}
+	s.recordUsage(userIDFromRequest(r), db.UsageCategoryExport, format, kind)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Write(buf.Bytes())
+}
+
+func flipResultsExportTable(rows []engine.FlipResult) exportTable {
+	t := exportTable{
+		Sheet: "Flips",
+		Headers: []string{
+			"Type ID", "Item", "Buy station", "Buy system", "Buy price", "Sell station", "Sell system", "Sell price",
+			"Units", "Profit/unit", "Margin %", "Total profit", "Real profit", "Daily volume", "Jumps", "Profit/jump", "Cargo m3",
+		},
+	}
+	for _, r := range rows {
+		t.Rows = append(t.Rows, []interface{}{
+			r.TypeID, r.TypeName, r.BuyStation, r.BuySystemName, r.BuyPrice, r.SellStation, r.SellSystemName, r.SellPrice,
+			r.UnitsToBuy, r.ProfitPerUnit, r.MarginPercent, r.TotalProfit, r.RealProfit, r.DailyVolume, r.TotalJumps, r.ProfitPerJump,
+			r.Volume * float64(r.UnitsToBuy),
+		})
+	}
+	return t
+}
+
+func contractResultsExportTable(rows []engine.ContractResult) exportTable {
+	t := exportTable{
+		Sheet: "Contracts",
+		Headers: []string{
+			"Contract ID", "Title", "Station", "System", "Price", "Market value", "Profit", "Margin %",
+			"Expected profit", "Sell confidence %", "Liquidation days", "Items", "Volume m3", "Jumps",
+		},
+	}
+	for _, c := range rows {
+		t.Rows = append(t.Rows, []interface{}{
+			c.ContractID, c.Title, c.StationName, c.SystemName, c.Price, c.MarketValue, c.Profit, c.MarginPercent,
+			c.ExpectedProfit, c.SellConfidence, c.EstLiquidationDays, c.ItemCount, c.Volume, c.Jumps,
+		})
+	}
+	return t
+}
+
+func orderDeskExportTable(rows []engine.OrderDeskOrder) exportTable {
+	t := exportTable{
+		Sheet: "Order desk",
+		Headers: []string{
+			"Order ID", "Type ID", "Item", "Location", "Side", "Price", "Remaining", "Total", "Notional",
+			"Position", "Best price", "Suggested price", "ETA days", "Expires", "Recommendation", "Reason",
+		},
+	}
+	for _, o := range rows {
+		side := "sell"
+		if o.IsBuyOrder {
+			side = "buy"
+		}
+		t.Rows = append(t.Rows, []interface{}{
+			o.OrderID, o.TypeID, o.TypeName, o.LocationName, side, o.Price, o.VolumeRemain, o.VolumeTotal, o.Notional,
+			o.Position, o.BestPrice, o.SuggestedPrice, o.ETADays, o.ExpiresAt, o.Recommendation, o.Reason,
+		})
+	}
+	return t
+}
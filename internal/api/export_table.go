@@ -0,0 +1,184 @@
+package api
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// exportTable is a spreadsheet-shaped export: a header row and rows of
+// string, integer, float or bool cells.
+type exportTable struct {
+	Sheet   string
+	Headers []string
+	Rows    [][]interface{}
+}
+
+// exportCSVOptions localizes CSV numbers: a decimal comma for locales
+// where spreadsheets expect "1234,56" (and then ";" between fields).
+type exportCSVOptions struct {
+	DecimalComma bool
+	Delimiter    rune
+}
+
+func formatExportFloat(v float64, decimalComma bool) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return ""
+	}
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	if decimalComma {
+		s = strings.Replace(s, ".", ",", 1)
+	}
+	return s
+}
+
+// escapeExportText keeps text that a spreadsheet would read as a formula
+// (item, character and station names are player-chosen) literal by
+// prefixing a quote.
+func escapeExportText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func formatExportCell(v interface{}, decimalComma bool) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return escapeExportText(x)
+	case bool:
+		return strconv.FormatBool(x)
+	case int:
+		return strconv.Itoa(x)
+	case int32:
+		return strconv.FormatInt(int64(x), 10)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case float64:
+		return formatExportFloat(x, decimalComma)
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+func writeExportCSV(w io.Writer, t exportTable, opts exportCSVOptions) error {
+	cw := csv.NewWriter(w)
+	if opts.Delimiter != 0 {
+		cw.Comma = opts.Delimiter
+	}
+	if err := cw.Write(t.Headers); err != nil {
+		return err
+	}
+	record := make([]string, len(t.Headers))
+	for _, row := range t.Rows {
+		for i := range record {
+			record[i] = ""
+			if i < len(row) {
+				record[i] = formatExportCell(row[i], opts.DecimalComma)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// xlsxColumnName returns the spreadsheet column letters of a 0-based index.
+func xlsxColumnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+)
+
+// writeExportXLSX writes a single-sheet workbook. Numbers are stored as
+// numbers, so the spreadsheet applies the reader's own locale.
+func writeExportXLSX(w io.Writer, t exportTable) error {
+	zw := zip.NewWriter(w)
+	put := func(name, body string) error {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(f, body)
+		return err
+	}
+	sheet := t.Sheet
+	if sheet == "" {
+		sheet = "Export"
+	}
+	var name strings.Builder
+	xml.EscapeText(&name, []byte(sheet))
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	writeRow := func(r int, cells []interface{}) {
+		fmt.Fprintf(&b, `<row r="%d">`, r)
+		for i, v := range cells {
+			ref := xlsxColumnName(i) + strconv.Itoa(r)
+			switch x := v.(type) {
+			case nil:
+				continue
+			case string:
+				fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+				xml.EscapeText(&b, []byte(escapeExportText(x)))
+				b.WriteString(`</t></is></c>`)
+			case bool:
+				bit := 0
+				if x {
+					bit = 1
+				}
+				fmt.Fprintf(&b, `<c r="%s" t="b"><v>%d</v></c>`, ref, bit)
+			default:
+				if s := formatExportCell(x, false); s != "" {
+					fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, s)
+				}
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	header := make([]interface{}, len(t.Headers))
+	for i, h := range t.Headers {
+		header[i] = h
+	}
+	writeRow(1, header)
+	for i, row := range t.Rows {
+		writeRow(i+2, row)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+
+	for _, part := range []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", workbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/worksheets/sheet1.xml", b.String()},
+	} {
+		if err := put(part.name, part.body); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
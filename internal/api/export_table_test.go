@@ -0,0 +1,73 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteExportCSVDecimalComma(t *testing.T) {
+	table := exportTable{
+		Headers: []string{"Item", "Price", "Units"},
+		Rows:    [][]interface{}{{"Tritanium; compressed", 1234.5, int32(10)}},
+	}
+	var buf bytes.Buffer
+	if err := writeExportCSV(&buf, table, exportCSVOptions{DecimalComma: true, Delimiter: ';'}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Item;Price;Units\n\"Tritanium; compressed\";1234,5;10\n"; buf.String() != want {
+		t.Fatalf("csv = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteExportXLSX(t *testing.T) {
+	table := exportTable{
+		Sheet:   "Flips",
+		Headers: []string{"Item", "Profit"},
+		Rows:    [][]interface{}{{"Tritanium <T1>", 1500.25}},
+	}
+	var buf bytes.Buffer
+	if err := writeExportXLSX(&buf, table); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			rc, _ := f.Open()
+			b, _ := io.ReadAll(rc)
+			rc.Close()
+			sheet = string(b)
+		}
+	}
+	for _, want := range []string{`<c r="A2" t="inlineStr"><is><t xml:space="preserve">Tritanium &lt;T1&gt;</t></is></c>`, `<c r="B2"><v>1500.25</v></c>`} {
+		if !strings.Contains(sheet, want) {
+			t.Fatalf("sheet missing %s:\n%s", want, sheet)
+		}
+	}
+
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 701: "ZZ", 702: "AAA"} {
+		if got := xlsxColumnName(i); got != want {
+			t.Fatalf("xlsxColumnName(%d) = %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestWriteExportCSVEscapesFormulas(t *testing.T) {
+	table := exportTable{
+		Headers: []string{"Item", "Station", "Profit"},
+		Rows:    [][]interface{}{{"=HYPERLINK(\"x\")", "@Jita", -5.0}, {"+1", "-Amarr", 3.0}},
+	}
+	var buf bytes.Buffer
+	if err := writeExportCSV(&buf, table, exportCSVOptions{Delimiter: ','}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Item,Station,Profit\n\"'=HYPERLINK(\"\"x\"\")\",'@Jita,-5\n'+1,'-Amarr,3\n"; buf.String() != want {
+		t.Fatalf("csv = %q, want %q", buf.String(), want)
+	}
+}
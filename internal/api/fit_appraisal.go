@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/pricing"
+)
+
+// fitAppraisalMaxFits bounds how many fits one paste may hold and how many
+// of each the seeding plan may size.
+const (
+	fitAppraisalMaxFits  = 20
+	fitAppraisalMaxCount = 500
+)
+
+type fitAppraisalRequest struct {
+	EFT              string   `json:"eft"`
+	TargetLocationID int64    `json:"target_location_id"`
+	Fits             int      `json:"fits"`         // fits of each doctrine to seed; default 1
+	PriceSource      string   `json:"price_source"` // Jita side: "esi" (default) or "fuzzwork"
+	SalesTaxPercent  *float64 `json:"sales_tax_percent"`
+	BrokerFeePercent *float64 `json:"broker_fee_percent"`
+}
+
+// handleAppraiseFits parses EFT fits, prices each whole fit at Jita and at
+// the target market, and returns the seeding margin per fit plus multibuy
+// text for buying N of every fit in Jita. Fees default to the user's
+// settings; freight uses the configured courier rates over the jump count.
+func (s *Server) handleAppraiseFits(w http.ResponseWriter, r *http.Request) {
+	var req fitAppraisalRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, importMaxBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if strings.TrimSpace(req.EFT) == "" {
+		writeError(w, http.StatusBadRequest, "eft is required")
+		return
+	}
+	if req.TargetLocationID <= 0 {
+		writeError(w, http.StatusBadRequest, "target_location_id is required")
+		return
+	}
+	if !s.isReady() {
+		writeError(w, http.StatusServiceUnavailable, "SDE not loaded yet")
+		return
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+
+	fits, unknown := engine.ParseEFTFits(sdeData, req.EFT)
+	if len(fits) == 0 {
+		writeError(w, http.StatusBadRequest, "no fit with a known hull found")
+		return
+	}
+	if len(fits) > fitAppraisalMaxFits {
+		writeError(w, http.StatusBadRequest, "too many fits")
+		return
+	}
+	targetSystem, targetRegion := s.locationSystemRegion(sdeData, req.TargetLocationID)
+	if targetRegion == 0 {
+		writeError(w, http.StatusBadRequest, "unknown target location")
+		return
+	}
+
+	userID := userIDFromRequest(r)
+	cfg := s.loadConfigForUser(userID)
+	params := engine.FitAppraisalParams{
+		Fits:                     1,
+		SalesTaxPercent:          cfg.SalesTaxPercent,
+		BrokerFeePercent:         cfg.BrokerFeePercent,
+		Jumps:                    -1,
+		FreightISKPerM3Jump:      cfg.FreightISKPerM3Jump,
+		FreightCollateralPercent: cfg.FreightCollateralPercent,
+	}
+	if req.Fits > 0 {
+		params.Fits = clampInt(req.Fits, 1, fitAppraisalMaxCount)
+	}
+	if req.SalesTaxPercent != nil {
+		params.SalesTaxPercent = clampFloat64(*req.SalesTaxPercent, 0, 100)
+	}
+	if req.BrokerFeePercent != nil {
+		params.BrokerFeePercent = clampFloat64(*req.BrokerFeePercent, 0, 100)
+	}
+	if universe := s.userUniverse(userID); universe != nil && targetSystem != 0 {
+		params.Jumps = universe.ShortestPath(engine.JitaSystemID, targetSystem)
+	}
+
+	typeSet := make(map[int32]bool)
+	for _, f := range fits {
+		for _, it := range f.Items {
+			typeSet[it.TypeID] = true
+		}
+	}
+	src, err := s.priceSources.Get(req.PriceSource, pricing.SourceESI)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	jita, err := src.Quotes(pricing.Hub{RegionID: engine.JitaRegionID, StationID: engine.JitaStationID}, sortedTypeIDs(typeSet))
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "failed to fetch Jita prices: "+err.Error())
+		return
+	}
+	targetOrders, err := s.fetchExecutionOrders(r, targetRegion, req.TargetLocationID, "sell")
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "target market: "+err.Error())
+		return
+	}
+	target := make(map[int32]pricing.Quote)
+	for _, o := range sellOrdersAt(targetOrders, req.TargetLocationID) {
+		if !typeSet[o.TypeID] || o.VolumeRemain <= 0 || o.Price <= 0 {
+			continue
+		}
+		if q := target[o.TypeID]; q.Sell == 0 || o.Price < q.Sell {
+			target[o.TypeID] = pricing.Quote{Buy: q.Buy, Sell: o.Price}
+		}
+	}
+
+	appraisals := engine.AppraiseFits(fits, jita, target, params)
+	var items []multibuyItem
+	for _, f := range fits {
+		for _, it := range f.Items {
+			items = append(items, multibuyItem{TypeID: it.TypeID, Name: it.Name, Quantity: it.Quantity * int64(params.Fits)})
+		}
+	}
+	list, _ := buildMultibuyList(sdeData, items)
+
+	writeJSON(w, map[string]interface{}{
+		"target_location_id":   req.TargetLocationID,
+		"target_location_name": s.esi.StationName(req.TargetLocationID),
+		"price_source":         src.Name(),
+		"fits":                 params.Fits,
+		"jumps":                params.Jumps,
+		"appraisals":           appraisals,
+		"shopping_list":        list,
+		"multibuy":             formatMultibuyLines(list, " "),
+		"unknown":              unknown,
+	})
+}
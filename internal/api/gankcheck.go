@@ -29,7 +29,7 @@ func (s *Server) handleGankCheck(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	dangers, err := s.ganker.CheckRoute(int32(fromID), int32(toID), minSec)
+	dangers, err := s.ganker.CheckRouteOn(s.userUniverse(userIDFromRequest(r)), int32(fromID), int32(toID), minSec)
 	if err != nil {
 		http.Error(w, `{"error":"route_check_failed"}`, http.StatusInternalServerError)
 		return
@@ -100,6 +100,7 @@ func (s *Server) handleGankCheckBatch(w http.ResponseWriter, r *http.Request) {
 		TotalISK float64 `json:"totalISK"`
 	}
 
+	universe := s.userUniverse(userIDFromRequest(r))
 	results := make([]RouteSummary, len(pairs))
 	var wg sync.WaitGroup
 	for i, p := range pairs {
@@ -107,7 +108,7 @@ func (s *Server) handleGankCheckBatch(w http.ResponseWriter, r *http.Request) {
 		go func(idx int, pr pair) {
 			defer wg.Done()
 			key := fmt.Sprintf("%d:%d", pr.from, pr.to)
-			systems, err := s.ganker.CheckRoute(pr.from, pr.to, minSec)
+			systems, err := s.ganker.CheckRouteOn(universe, pr.from, pr.to, minSec)
 			if err != nil || systems == nil {
 				results[idx] = RouteSummary{Key: key, Danger: "green"}
 				return
@@ -701,14 +701,23 @@ func hostedQuotaFeatureForRequest(r *http.Request) (string, bool) {
 	case path == "/api/scan",
 		path == "/api/scan/multi-region",
 		path == "/api/scan/regional-day",
+		path == "/api/scan/compare",
 		path == "/api/scan/contracts",
 		path == "/api/scan/station",
+		path == "/api/station/buy-advisor",
 		path == "/api/backtest/flips",
 		path == "/api/orderbook/coverage",
+		path == "/api/market/coverage",
+		path == "/api/fits/appraise",
 		path == "/api/route/find",
 		path == "/api/industry/analyze",
+		path == "/api/industry/ore-basket",
+		path == "/api/pi/arbitrage",
 		path == "/api/execution/plan",
+		path == "/api/execution/schedule",
 		path == "/api/demand/refresh",
+		path == "/api/corp/buyback/board/refresh",
+		path == "/api/corp/buyback/quote",
 		path == "/api/auth/station/cache/reboot",
 		path == "/api/auth/station/command",
 		path == "/api/auth/industry/coverage",
@@ -625,12 +625,20 @@ func TestHostedQuotaFeatureMappingCoversHeavyHostedPosts(t *testing.T) {
 		{http.MethodPost, "/api/scan/regional-day", "scans"},
 		{http.MethodPost, "/api/scan/contracts", "scans"},
 		{http.MethodPost, "/api/scan/station", "scans"},
+		{http.MethodPost, "/api/scan/compare", "scans"},
 		{http.MethodPost, "/api/backtest/flips", "scans"},
 		{http.MethodPost, "/api/orderbook/coverage", "scans"},
+		{http.MethodPost, "/api/market/coverage", "scans"},
+		{http.MethodPost, "/api/fits/appraise", "scans"},
 		{http.MethodPost, "/api/route/find", "scans"},
 		{http.MethodPost, "/api/industry/analyze", "scans"},
+		{http.MethodPost, "/api/industry/ore-basket", "scans"},
+		{http.MethodPost, "/api/pi/arbitrage", "scans"},
 		{http.MethodPost, "/api/execution/plan", "scans"},
+		{http.MethodPost, "/api/execution/schedule", "scans"},
+		{http.MethodPost, "/api/station/buy-advisor", "scans"},
 		{http.MethodPost, "/api/demand/refresh", "scans"},
+		{http.MethodPost, "/api/corp/buyback/quote", "scans"},
 		{http.MethodPost, "/api/auth/station/cache/reboot", "scans"},
 		{http.MethodPost, "/api/auth/station/command", "scans"},
 		{http.MethodPost, "/api/auth/industry/coverage", "scans"},
@@ -667,6 +675,7 @@ func TestHostedQuotaFeatureMappingClassifiesAllPostAPIRoutes(t *testing.T) {
 		"/api/update/apply":                          "desktop update action",
 		"/api/internal/wiki/gollum":                  "internal webhook",
 		"/api/telemetry/client":                      "telemetry ingest",
+		"/api/usage/events":                          "local usage statistics",
 		"/api/hosted/payments/request":               "billing request has dedicated payment limits",
 		"/api/hosted/payments/mark-sent":             "billing sent marker has dedicated payment limits",
 		"/api/hosted/payments/cancel":                "billing cancel has dedicated payment limits",
@@ -676,6 +685,14 @@ func TestHostedQuotaFeatureMappingClassifiesAllPostAPIRoutes(t *testing.T) {
 		"/api/alerts/test":                           "local notification test",
 		"/api/orderbook/cleanup":                     "hosted maintenance endpoint",
 		"/api/watchlist":                             "watchlist CRUD",
+		"/api/watchlist/import":                      "watchlist CRUD",
+		"/api/watchlist/bulk-delete":                 "watchlist CRUD",
+		"/api/watchlist/group":                       "watchlist CRUD",
+		"/api/watchlist/groups/rename":               "watchlist CRUD",
+		"/api/speculation/baskets":                   "speculation basket CRUD",
+		"/api/market/competitors/watch":              "competitor watch CRUD",
+		"/api/fees/stations":                         "station fee CRUD",
+		"/api/ships":                                 "ship profile CRUD",
 		"/api/scan/history/clear":                    "history cleanup",
 		"/api/auth/logout":                           "auth session action",
 		"/api/auth/character/select":                 "auth session action",
@@ -689,10 +706,21 @@ func TestHostedQuotaFeatureMappingClassifiesAllPostAPIRoutes(t *testing.T) {
 		"/api/auth/paper-trades":                     "paper-trade CRUD",
 		"/api/auth/paper-trades/reconcile":           "paper-trade CRUD",
 		"/api/auth/achievements/seen":                "achievement state",
+		"/api/auth/onboarding/seed":                  "first-run settings seed",
 		"/api/auth/industry/projects":                "industry project CRUD",
 		"/api/ui/open-market":                        "ESI UI action",
 		"/api/ui/set-waypoint":                       "ESI UI action",
 		"/api/ui/open-contract":                      "ESI UI action",
+		"/api/route/waypoints":                       "ESI UI action",
+		"/api/route/pins":                            "pinned route CRUD",
+		"/api/auth/route/ansiblex/import":            "jump gate list import",
+		"/api/route/multistop":                       "route planning over client-supplied flips",
+		"/api/scan/optimize-cargo":                   "cargo packing over stored scan results",
+		"/api/export/multibuy":                       "text formatting of a client-supplied list",
+		"/api/keys":                                  "api key CRUD",
+		"/api/scan/radius-suggestion/apply":          "local config write from stored scans",
+		"/api/corp/buyback/quotes/{id}/accept":       "buyback quote state",
+		"/api/corp/buyback/reconcile":                "matching against the archived corp journal",
 	}
 	var unclassified []string
 	for _, match := range matches {
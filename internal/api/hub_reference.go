@@ -0,0 +1,121 @@
+package api
+
+import (
+	"log"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/pricing"
+)
+
+// annotateJitaReference adds Jita 4-4 reference prices to flip results. It
+// uses the aggregate source, one request per few hundred types; a failed
+// lookup leaves results unannotated rather than failing the scan.
+func (s *Server) annotateJitaReference(results []engine.FlipResult) {
+	if len(results) == 0 {
+		return
+	}
+	quotes, ok := s.referenceQuotes(pricing.Hub{RegionID: engine.JitaRegionID, StationID: engine.JitaStationID}, flipResultTypeIDs(results))
+	if !ok {
+		return
+	}
+	engine.AnnotateHubReference(results, quotes)
+}
+
+// annotateReferenceStation compares flip results against the user's
+// reference "sell at" station.
+func (s *Server) annotateReferenceStation(cfg *config.Config, results []engine.FlipResult) {
+	if len(results) == 0 {
+		return
+	}
+	stationID, hub, ok := s.referenceStationHub(cfg)
+	if !ok {
+		return
+	}
+	if quotes, ok := s.referenceQuotes(hub, flipResultTypeIDs(results)); ok {
+		engine.AnnotateReferenceStation(results, stationID, quotes)
+	}
+}
+
+// annotateStationTradeReference is annotateReferenceStation for station
+// trading results.
+func (s *Server) annotateStationTradeReference(cfg *config.Config, results []engine.StationTrade) {
+	if len(results) == 0 {
+		return
+	}
+	stationID, hub, ok := s.referenceStationHub(cfg)
+	if !ok {
+		return
+	}
+	seen := make(map[int32]bool, len(results))
+	typeIDs := make([]int32, 0, len(results))
+	for _, r := range results {
+		if r.TypeID > 0 && !seen[r.TypeID] {
+			seen[r.TypeID] = true
+			typeIDs = append(typeIDs, r.TypeID)
+		}
+	}
+	if quotes, ok := s.referenceQuotes(hub, typeIDs); ok {
+		engine.AnnotateStationTradeReference(results, stationID, quotes)
+	}
+}
+
+// annotateContractReference is annotateReferenceStation for contract
+// results, valuing each contract's included items at the reference station.
+func (s *Server) annotateContractReference(cfg *config.Config, results []engine.ContractResult) {
+	if len(results) == 0 {
+		return
+	}
+	stationID, hub, ok := s.referenceStationHub(cfg)
+	if !ok {
+		return
+	}
+	if quotes, ok := s.referenceQuotes(hub, engine.ContractResultTypeIDs(results)); ok {
+		engine.AnnotateContractReference(results, stationID, quotes)
+	}
+}
+
+// referenceStationHub resolves the configured reference station (Jita 4-4
+// by default) to a price hub. Unknown stations are skipped.
+func (s *Server) referenceStationHub(cfg *config.Config) (int64, pricing.Hub, bool) {
+	stationID := config.DefaultReferenceStationID
+	if cfg != nil && cfg.ReferenceStationID > 0 {
+		stationID = cfg.ReferenceStationID
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	if sdeData == nil {
+		return 0, pricing.Hub{}, false
+	}
+	systemID, regionID := s.locationSystemRegion(sdeData, stationID)
+	if systemID == 0 {
+		return 0, pricing.Hub{}, false
+	}
+	return stationID, pricing.Hub{RegionID: regionID, StationID: stationID}, true
+}
+
+func (s *Server) referenceQuotes(hub pricing.Hub, typeIDs []int32) (map[int32]pricing.Quote, bool) {
+	src, err := s.priceSources.Get(pricing.SourceFuzzwork, pricing.SourceFuzzwork)
+	if err != nil {
+		return nil, false
+	}
+	quotes, err := src.Quotes(hub, typeIDs)
+	if err != nil {
+		log.Printf("[API] Reference prices for station %d unavailable: %v", hub.StationID, err)
+		return nil, false
+	}
+	return quotes, true
+}
+
+func flipResultTypeIDs(results []engine.FlipResult) []int32 {
+	seen := make(map[int32]bool, len(results))
+	typeIDs := make([]int32, 0, len(results))
+	for _, r := range results {
+		if r.TypeID > 0 && !seen[r.TypeID] {
+			seen[r.TypeID] = true
+			typeIDs = append(typeIDs, r.TypeID)
+		}
+	}
+	return typeIDs
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"log"
+	"time"
+
+	"eve-flipper/internal/engine"
+)
+
+const (
+	// impactCalibrationInterval is how often impact coefficients are refit
+	// from the market history cache.
+	impactCalibrationInterval = 24 * time.Hour
+	// impactCoefficientRetention drops fits of items whose history has not
+	// been refreshed for a month.
+	impactCoefficientRetention = 30 * 24 * time.Hour
+)
+
+func (s *Server) startImpactCalibrator() {
+	s.startBackgroundJob(backgroundJob{
+		name:     "impact_calibration",
+		interval: impactCalibrationInterval,
+		catchUp:  catchUpRun,
+		run:      s.calibrateImpactCoefficients,
+	})
+}
+
+// calibrateImpactCoefficients fits impact parameters for every history
+// series cached in the last day and persists them. Nothing is fetched; items
+// enter the calibration as scans and item views load their history.
+func (s *Server) calibrateImpactCoefficients(now time.Time) {
+	if s.db == nil {
+		return
+	}
+	keys, err := s.db.ListMarketHistoryKeys(now.Add(-24 * time.Hour))
+	if err != nil {
+		log.Printf("[JOBS] impact calibration: %v", err)
+		return
+	}
+	fitted := 0
+	for _, k := range keys {
+		params := engine.FitImpactParams(s.db.StoredMarketHistory(k.RegionID, k.TypeID), engine.ImpactFitDays)
+		if !params.Valid {
+			continue
+		}
+		if err := s.db.SaveImpactCoefficients(k.RegionID, k.TypeID, params, now); err != nil {
+			log.Printf("[JOBS] impact calibration %d@%d: %v", k.TypeID, k.RegionID, err)
+			continue
+		}
+		fitted++
+	}
+	if _, err := s.db.PruneImpactCoefficients(now.Add(-impactCoefficientRetention)); err != nil {
+		log.Printf("[JOBS] impact calibration prune: %v", err)
+	}
+	log.Printf("[JOBS] impact calibration: fitted %d of %d items", fitted, len(keys))
+}
+
+// withImpactFit carries a persisted range fit into freshly calibrated
+// params, so slicing uses the 90-day fit while σ and volume stay on the
+// requested window. Without live history the persisted params are used as
+// they are.
+func withImpactFit(live, stored engine.ImpactParams) engine.ImpactParams {
+	if !live.Valid {
+		return stored
+	}
+	live.RangeSlope = stored.RangeSlope
+	live.RangeFitR2 = stored.RangeFitR2
+	live.CalibratedAt = stored.CalibratedAt
+	return live
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/esi"
+)
+
+func TestCalibrateImpactCoefficientsPersistsFits(t *testing.T) {
+	database := openAPITestDB(t)
+	now := time.Now()
+	var history []esi.HistoryEntry
+	for i := 0; i < 20; i++ {
+		vol := int64(500 + 250*(i%5))
+		history = append(history, esi.HistoryEntry{
+			Date:    now.AddDate(0, 0, i-20).Format("2006-01-02"),
+			Average: 100 + float64(i%3),
+			Highest: 103 + float64(i%3) + float64(vol)/500,
+			Lowest:  97 + float64(i%3),
+			Volume:  vol,
+		})
+	}
+	database.SetMarketHistory(10000002, 34, history)
+	database.SetMarketHistory(10000002, 35, history[:2])
+
+	srv := NewServer(config.Default(), nil, database, nil, nil)
+	srv.calibrateImpactCoefficients(now)
+
+	got, ok := database.GetImpactCoefficients(10000002, 34)
+	if !ok || got.RangeSlope <= 0 || got.DaysUsed != 20 {
+		t.Fatalf("coefficients = %+v, %v; want a positive range fit over 20 days", got, ok)
+	}
+	if _, ok := database.GetImpactCoefficients(10000002, 35); ok {
+		t.Fatalf("expected no coefficients for a two-day history")
+	}
+}
+
+func TestWithImpactFitKeepsLiveWindow(t *testing.T) {
+	stored := engine.ImpactParams{Sigma: 0.5, AvgDailyVolume: 10, RangeSlope: 0.04, RangeFitR2: 0.7, CalibratedAt: "2026-05-01T00:00:00Z", Valid: true}
+	live := engine.ImpactParams{Sigma: 0.02, AvgDailyVolume: 1000, Valid: true}
+
+	got := withImpactFit(live, stored)
+	if got.Sigma != 0.02 || got.AvgDailyVolume != 1000 || math.Abs(got.RangeSlope-0.04) > 1e-12 || got.CalibratedAt == "" {
+		t.Fatalf("withImpactFit = %+v", got)
+	}
+	if got := withImpactFit(engine.ImpactParams{}, stored); got != stored {
+		t.Fatalf("withImpactFit without live history = %+v, want stored", got)
+	}
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"eve-flipper/internal/engine"
+)
+
+// itemDetailResponse backs the item inspector opened from any scan row.
+type itemDetailResponse struct {
+	TypeID       int32                   `json:"type_id"`
+	TypeName     string                  `json:"type_name"`
+	Volume       float64                 `json:"volume"`
+	GroupID      int32                   `json:"group_id"`
+	GroupName    string                  `json:"group_name,omitempty"`
+	CategoryID   int32                   `json:"category_id"`
+	IsContraband bool                    `json:"is_contraband,omitempty"`
+	RegionID     int32                   `json:"region_id"`
+	RegionName   string                  `json:"region_name,omitempty"`
+	StationID    int64                   `json:"station_id,omitempty"`
+	StationName  string                  `json:"station_name,omitempty"`
+	Market       engine.ItemMarketDetail `json:"market"`
+	Cadence      *relistCadences         `json:"relist_cadence,omitempty"`
+	Warnings     []string                `json:"warnings,omitempty"`
+}
+
+// handleGetTypeDetail returns the live bid/ask ladder, spread and station
+// trading metrics of {typeID} in ?region= (ID or name), optionally limited
+// to ?station=, plus its SDE attributes. Without a region, an NPC station's
+// region is used, else The Forge.
+func (s *Server) handleGetTypeDetail(w http.ResponseWriter, r *http.Request) {
+	if !s.isReady() {
+		writeError(w, http.StatusServiceUnavailable, "SDE not loaded yet")
+		return
+	}
+	typeID64, err := strconv.ParseInt(r.PathValue("typeID"), 10, 32)
+	if err != nil || typeID64 <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid type ID")
+		return
+	}
+	typeID := int32(typeID64)
+	var stationID int64
+	if raw := strings.TrimSpace(r.URL.Query().Get("station")); raw != "" {
+		stationID, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || stationID <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid station")
+			return
+		}
+	}
+
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	item, ok := sdeData.Types[typeID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "type not found")
+		return
+	}
+	resp := itemDetailResponse{
+		TypeID:       typeID,
+		TypeName:     item.Name,
+		Volume:       item.Volume,
+		GroupID:      item.GroupID,
+		CategoryID:   item.CategoryID,
+		IsContraband: item.IsContraband,
+		StationID:    stationID,
+	}
+	if group, ok := sdeData.Groups[item.GroupID]; ok {
+		resp.GroupName = group.Name
+	}
+
+	defRegion := engine.JitaRegionID
+	if st, ok := sdeData.Stations[stationID]; ok {
+		resp.StationName = st.Name
+		if sys, ok := sdeData.Systems[st.SystemID]; ok {
+			defRegion = sys.RegionID
+		}
+	} else if stationID > 0 {
+		resp.StationName = s.esi.StationName(stationID)
+	}
+	resp.RegionID, ok = resolveRegionQuery(sdeData, r.URL.Query().Get("region"), defRegion)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "unknown region")
+		return
+	}
+	if region, ok := sdeData.Regions[resp.RegionID]; ok {
+		resp.RegionName = region.Name
+	}
+
+	orders, err := s.esi.FetchRegionOrdersByTypeContext(r.Context(), resp.RegionID, typeID)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "market orders unavailable: "+err.Error())
+		return
+	}
+	history, err := s.cachedMarketHistory(resp.RegionID, typeID)
+	if err != nil {
+		resp.Warnings = append(resp.Warnings, "market history unavailable: "+err.Error())
+	}
+	resp.Market = engine.BuildItemMarketDetail(orders, history, stationID)
+
+	// Top-of-book cadence from stored snapshots, at the station or, without
+	// one, at the region's trade hub.
+	if s.db != nil {
+		cadenceStation := stationID
+		if cadenceStation == 0 {
+			cadenceStation = hubStationForRegion(resp.RegionID)
+		}
+		if cadenceStation > 0 {
+			if c, err := s.relistCadence(typeID, cadenceStation, relistCadenceDays, time.Now().UTC()); err == nil && c.Sell.Samples+c.Buy.Samples > 0 {
+				resp.Cadence = &c
+			}
+		}
+	}
+	writeJSON(w, resp)
+}
@@ -347,20 +347,18 @@ func (s *Server) handleItemIntelligence(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, resp)
 }
 
+// cachedMarketHistory reads market history through the DB cache, falling
+// back to ESI.
 func (s *Server) cachedMarketHistory(regionID, typeID int32) ([]esi.HistoryEntry, error) {
+	var cache engine.HistoryProvider
 	if s.db != nil {
-		if entries, ok := s.db.GetMarketHistory(regionID, typeID); ok {
-			return entries, nil
-		}
-	}
-	entries, err := s.esi.FetchMarketHistory(regionID, typeID)
-	if err != nil {
-		return nil, err
+		cache = s.db
 	}
-	if s.db != nil {
-		s.db.SetMarketHistory(regionID, typeID, entries)
+	var sources []engine.HistorySource
+	if s.esi != nil {
+		sources = append(sources, s.esi)
 	}
-	return entries, nil
+	return engine.LoadMarketHistory(cache, sources, regionID, typeID)
 }
 
 func summarizeItemOrders(regionID int32, regionName string, orders []esi.MarketOrder) itemMarketSummary {
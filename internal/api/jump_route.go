@@ -0,0 +1,94 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"eve-flipper/internal/engine"
+)
+
+// jitaIsotopePrice returns the lowest Jita 4-4 sell price for an isotope type
+// (falling back to the lowest sell in The Forge). Returns 0 on failure.
+func (s *Server) jitaIsotopePrice(typeID int32) float64 {
+	if typeID <= 0 {
+		typeID = engine.HeliumIsotopesTypeID
+	}
+	if s.esi == nil {
+		return 0
+	}
+	orders, err := s.esi.FetchRegionOrdersByType(engine.JitaRegionID, typeID)
+	if err != nil {
+		log.Printf("[API] Isotope price lookup failed for type %d: %v", typeID, err)
+		return 0
+	}
+	station, region := 0.0, 0.0
+	for _, o := range orders {
+		if o.IsBuyOrder || o.Price <= 0 {
+			continue
+		}
+		if region == 0 || o.Price < region {
+			region = o.Price
+		}
+		if o.LocationID == engine.JitaStationID && (station == 0 || o.Price < station) {
+			station = o.Price
+		}
+	}
+	if station > 0 {
+		return station
+	}
+	return region
+}
+
+// handleRouteJump plans a jump-drive chain between two systems.
+// GET /api/route/jump?from=Jita&to=1DQ1-A&range_ly=10&fatigue_reduction=0.9&isotopes_per_ly=3300&isotope_price=0
+func (s *Server) handleRouteJump(w http.ResponseWriter, r *http.Request) {
+	if !s.isReady() {
+		writeError(w, 503, "SDE not loaded yet")
+		return
+	}
+	q := r.URL.Query()
+	fromID := s.systemIDByName(q.Get("from"))
+	if fromID == 0 {
+		writeError(w, 400, "unknown from system: "+strings.TrimSpace(q.Get("from")))
+		return
+	}
+	toID := s.systemIDByName(q.Get("to"))
+	if toID == 0 {
+		writeError(w, 400, "unknown to system: "+strings.TrimSpace(q.Get("to")))
+		return
+	}
+
+	parseFloat := func(key string, def float64) float64 {
+		if v, err := strconv.ParseFloat(q.Get(key), 64); err == nil {
+			return v
+		}
+		return def
+	}
+	params := engine.JumpRouteParams{
+		RangeLY:          parseFloat("range_ly", engine.DefaultJumpRangeLY),
+		FatigueReduction: parseFloat("fatigue_reduction", engine.DefaultJumpFatigueReduction),
+		IsotopesPerLY:    parseFloat("isotopes_per_ly", engine.DefaultJumpIsotopesPerLY),
+		IsotopePrice:     parseFloat("isotope_price", 0),
+		RoutePreferences: s.routePreferences(userIDFromRequest(r)),
+	}
+	if params.IsotopePrice <= 0 {
+		typeID, _ := strconv.ParseInt(q.Get("isotope_type_id"), 10, 32)
+		params.IsotopePrice = s.jitaIsotopePrice(int32(typeID))
+	}
+
+	s.mu.RLock()
+	scanner := s.scanner
+	s.mu.RUnlock()
+
+	route, err := scanner.PlanJumpRoute(fromID, toID, params, nil)
+	if err != nil {
+		writeError(w, 400, err.Error())
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"route":         route,
+		"isotope_price": params.IsotopePrice,
+	})
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"log"
+	"time"
+
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/pricing"
+)
+
+// LP store offers change with patches, not with the market.
+const lpOffersTTL = 24 * time.Hour
+
+// lpStoreOffers returns the faction warfare LP store offers, cached for
+// lpOffersTTL. Corporations that fail to load are skipped; stale offers are
+// served when every fetch fails.
+func (s *Server) lpStoreOffers() []esi.LoyaltyOffer {
+	s.lpOffersMu.Lock()
+	defer s.lpOffersMu.Unlock()
+	if s.lpOffers != nil && time.Since(s.lpOffersAt) < lpOffersTTL {
+		return s.lpOffers
+	}
+	var offers []esi.LoyaltyOffer
+	for _, corpID := range engine.FactionWarfareLPCorporations {
+		batch, err := s.esi.FetchLoyaltyOffers(corpID)
+		if err != nil {
+			log.Printf("[API] LP offers: %v", err)
+			continue
+		}
+		offers = append(offers, batch...)
+	}
+	if len(offers) > 0 {
+		s.lpOffers, s.lpOffersAt = offers, time.Now()
+	}
+	return s.lpOffers
+}
+
+// annotateLPAnchors flags flip results whose prices have left the LP-store
+// conversion band, pricing required items at Jita sell.
+func (s *Server) annotateLPAnchors(results []engine.FlipResult, floorISKPerLP, ceilingISKPerLP float64) {
+	if len(results) == 0 || s.esi == nil {
+		return
+	}
+	offers := s.lpStoreOffers()
+	if len(offers) == 0 {
+		return
+	}
+	quotes := map[int32]pricing.Quote{}
+	if inputs := engine.LPAnchorInputTypes(offers); len(inputs) > 0 {
+		src, err := s.priceSources.Get(pricing.SourceFuzzwork, pricing.SourceFuzzwork)
+		if err != nil {
+			return
+		}
+		quotes, err = src.Quotes(pricing.Hub{RegionID: engine.JitaRegionID, StationID: engine.JitaStationID}, inputs)
+		if err != nil {
+			log.Printf("[API] LP anchor input prices unavailable: %v", err)
+			return
+		}
+	}
+	if ceilingISKPerLP <= 0 {
+		ceilingISKPerLP = engine.DefaultLPCeilingISKPerLP
+	}
+	anchors := engine.BuildLPAnchors(offers, quotes, floorISKPerLP, ceilingISKPerLP)
+	engine.AnnotateLPAnchors(results, anchors)
+}
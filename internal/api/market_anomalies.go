@@ -0,0 +1,230 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/pricing"
+)
+
+const (
+	marketAnomalyInterval       = time.Hour
+	marketAnomalyCandidates     = 150 // history fetches per run
+	marketAnomalyHistoryWorkers = 6
+	marketAnomalyFeedMax        = 200
+	marketAnomalyFeedTTL        = 24 * time.Hour
+)
+
+// marketAnomalyFeed is the latest anomaly scan, kept across restarts via the
+// background job state.
+type marketAnomalyFeed struct {
+	UpdatedAt string                 `json:"updated_at"`
+	RegionID  int32                  `json:"region_id"`
+	Sigma     float64                `json:"sigma"`
+	Screened  int                    `json:"screened"`
+	Anomalies []engine.MarketAnomaly `json:"anomalies"`
+}
+
+func (s *Server) startMarketAnomalyScanner() {
+	s.startBackgroundJob(backgroundJob{
+		name:         "market_anomaly_scan",
+		interval:     marketAnomalyInterval,
+		catchUp:      catchUpRun,
+		run:          s.scanMarketAnomalies,
+		saveState:    s.marketAnomalySnapshot,
+		restoreState: s.restoreMarketAnomalies,
+	})
+}
+
+// marketAnomalySnapshot encodes the feed for persistence.
+func (s *Server) marketAnomalySnapshot() string {
+	s.marketAnomalyMu.RLock()
+	raw, err := json.Marshal(s.marketAnomalies)
+	s.marketAnomalyMu.RUnlock()
+	if err != nil {
+		return ""
+	}
+	return string(raw)
+}
+
+// restoreMarketAnomalies loads state saved by marketAnomalySnapshot.
+func (s *Server) restoreMarketAnomalies(state string) {
+	var saved marketAnomalyFeed
+	if err := json.Unmarshal([]byte(state), &saved); err != nil {
+		log.Printf("[ANOMALY] Feed state: %v", err)
+		return
+	}
+	s.marketAnomalyMu.Lock()
+	s.marketAnomalies = saved
+	s.marketAnomalyMu.Unlock()
+}
+
+// regionBestQuotes returns the lowest sell and highest buy per type across
+// a region's orders.
+func regionBestQuotes(sells, buys []esi.MarketOrder) map[int32]pricing.Quote {
+	out := make(map[int32]pricing.Quote)
+	for _, o := range sells {
+		if o.VolumeRemain <= 0 || o.Price <= 0 {
+			continue
+		}
+		if q := out[o.TypeID]; q.Sell == 0 || o.Price < q.Sell {
+			q.Sell = o.Price
+			out[o.TypeID] = q
+		}
+	}
+	for _, o := range buys {
+		if o.VolumeRemain <= 0 || o.Price <= 0 {
+			continue
+		}
+		if q := out[o.TypeID]; o.Price > q.Buy {
+			q.Buy = o.Price
+			out[o.TypeID] = q
+		}
+	}
+	return out
+}
+
+// mergeMarketAnomalies puts this run's anomalies ahead of earlier ones that
+// are still under a day old, one entry per type, most extreme first.
+func mergeMarketAnomalies(prev, fresh []engine.MarketAnomaly, now time.Time) []engine.MarketAnomaly {
+	seen := make(map[int32]bool, len(fresh))
+	out := make([]engine.MarketAnomaly, 0, len(fresh)+len(prev))
+	for _, a := range fresh {
+		seen[a.TypeID] = true
+		out = append(out, a)
+	}
+	for _, a := range prev {
+		t, err := time.Parse(time.RFC3339, a.DetectedAt)
+		if seen[a.TypeID] || err != nil || now.Sub(t) > marketAnomalyFeedTTL {
+			continue
+		}
+		seen[a.TypeID] = true
+		out = append(out, a)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].DetectedAt != out[j].DetectedAt {
+			return out[i].DetectedAt > out[j].DetectedAt
+		}
+		return math.Abs(out[i].ZScore) > math.Abs(out[j].ZScore)
+	})
+	if len(out) > marketAnomalyFeedMax {
+		out = out[:marketAnomalyFeedMax]
+	}
+	return out
+}
+
+// scanMarketAnomalies compares The Forge's best prices with CCP's
+// /markets/prices averages, then confirms the furthest movers against their
+// 90-day history.
+func (s *Server) scanMarketAnomalies(now time.Time) {
+	if s.esi == nil || !s.isReady() {
+		return
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	scanner := s.scanner
+	s.mu.RUnlock()
+	if scanner == nil {
+		return
+	}
+
+	prices, err := s.esi.FetchMarketPrices()
+	if err != nil {
+		log.Printf("[ANOMALY] Market prices: %v", err)
+		return
+	}
+	sells, err := s.esi.FetchRegionOrders(engine.JitaRegionID, "sell")
+	if err != nil {
+		log.Printf("[ANOMALY] Sell orders: %v", err)
+		return
+	}
+	buys, err := s.esi.FetchRegionOrders(engine.JitaRegionID, "buy")
+	if err != nil {
+		log.Printf("[ANOMALY] Buy orders: %v", err)
+		return
+	}
+
+	params := engine.MarketAnomalyParams{}
+	candidates := engine.ScreenMarketAnomalies(prices, regionBestQuotes(sells, buys), params, marketAnomalyCandidates)
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		sem   = make(chan struct{}, marketAnomalyHistoryWorkers)
+		found []engine.MarketAnomaly
+	)
+	detectedAt := now.UTC().Format(time.RFC3339)
+	for _, c := range candidates {
+		wg.Add(1)
+		go func(c engine.MarketAnomalyCandidate) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			history, histErr := scanner.MarketHistory(engine.JitaRegionID, c.TypeID)
+			if histErr != nil {
+				return
+			}
+			a, ok := engine.DetectMarketAnomaly(sdeData, c, history, params)
+			if !ok {
+				return
+			}
+			a.DetectedAt = detectedAt
+			mu.Lock()
+			found = append(found, a)
+			mu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+
+	s.marketAnomalyMu.Lock()
+	s.marketAnomalies = marketAnomalyFeed{
+		UpdatedAt: detectedAt,
+		RegionID:  engine.JitaRegionID,
+		Sigma:     engine.DefaultAnomalySigma,
+		Screened:  len(candidates),
+		Anomalies: mergeMarketAnomalies(s.marketAnomalies.Anomalies, found, now),
+	}
+	s.marketAnomalyMu.Unlock()
+	log.Printf("[ANOMALY] %d of %d candidates flagged", len(found), len(candidates))
+}
+
+// handleMarketAnomalies serves the anomaly feed.
+//
+//	GET /api/market/anomalies?direction=spike&limit=50
+func (s *Server) handleMarketAnomalies(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit := 100
+	if v, err := strconv.Atoi(q.Get("limit")); err == nil && v > 0 {
+		limit = clampInt(v, 1, marketAnomalyFeedMax)
+	}
+	direction := q.Get("direction")
+	if direction != "" && direction != "spike" && direction != "crash" {
+		writeError(w, http.StatusBadRequest, "direction must be spike or crash")
+		return
+	}
+
+	s.marketAnomalyMu.RLock()
+	feed := s.marketAnomalies
+	s.marketAnomalyMu.RUnlock()
+
+	out := []engine.MarketAnomaly{}
+	for _, a := range feed.Anomalies {
+		if direction != "" && a.Direction != direction {
+			continue
+		}
+		out = append(out, a)
+		if len(out) == limit {
+			break
+		}
+	}
+	feed.Anomalies = out
+	writeJSON(w, feed)
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/esi"
+)
+
+func TestRegionBestQuotes(t *testing.T) {
+	sells := []esi.MarketOrder{{TypeID: 34, Price: 6, VolumeRemain: 10}, {TypeID: 34, Price: 5, VolumeRemain: 1}, {TypeID: 34, Price: 1, VolumeRemain: 0}}
+	buys := []esi.MarketOrder{{TypeID: 34, Price: 4, VolumeRemain: 10}, {TypeID: 34, Price: 4.5, VolumeRemain: 1}, {TypeID: 35, Price: 9, VolumeRemain: 1}}
+	q := regionBestQuotes(sells, buys)
+	if q[34].Sell != 5 || q[34].Buy != 4.5 || q[35].Sell != 0 || q[35].Buy != 9 {
+		t.Fatalf("quotes = %+v", q)
+	}
+}
+
+func TestMergeMarketAnomalies(t *testing.T) {
+	now := time.Date(2026, 5, 2, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) string { return now.Add(-d).Format(time.RFC3339) }
+	prev := []engine.MarketAnomaly{
+		{TypeID: 34, ZScore: 5, DetectedAt: at(time.Hour)},
+		{TypeID: 35, ZScore: 4, DetectedAt: at(time.Hour)},
+		{TypeID: 36, ZScore: 9, DetectedAt: at(30 * time.Hour)},
+	}
+	fresh := []engine.MarketAnomaly{
+		{TypeID: 35, ZScore: -3, DetectedAt: at(0)},
+		{TypeID: 37, ZScore: 6, DetectedAt: at(0)},
+	}
+	got := mergeMarketAnomalies(prev, fresh, now)
+	if len(got) != 3 || got[0].TypeID != 37 || got[1].TypeID != 35 || got[1].ZScore != -3 || got[2].TypeID != 34 {
+		t.Fatalf("merged = %+v", got)
+	}
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/sde"
+)
+
+// marketBrowseNode is a market group in the browse tree. Order counts cover
+// every type below the group and come from the cached region books.
+type marketBrowseNode struct {
+	ID         int32  `json:"id"`
+	Name       string `json:"name"`
+	HasTypes   bool   `json:"has_types"`
+	TypeCount  int    `json:"type_count"`
+	BuyOrders  int    `json:"buy_orders"`
+	SellOrders int    `json:"sell_orders"`
+}
+
+type marketBrowseType struct {
+	TypeID     int32  `json:"type_id"`
+	Name       string `json:"name"`
+	BuyOrders  int    `json:"buy_orders"`
+	SellOrders int    `json:"sell_orders"`
+}
+
+type marketBrowseMatch struct {
+	marketBrowseType
+	Path []string `json:"path"` // market group names from the root
+}
+
+// marketBrowseTree indexes the SDE market groups for one request.
+type marketBrowseTree struct {
+	data     *sde.Data
+	counts   map[int32]esi.OrderCount
+	children map[int32][]int32 // parent market group -> child groups
+	types    map[int32][]int32 // market group -> types listed in it
+	totals   map[int32]marketBrowseNode
+}
+
+func newMarketBrowseTree(data *sde.Data, counts map[int32]esi.OrderCount) *marketBrowseTree {
+	t := &marketBrowseTree{
+		data:     data,
+		counts:   counts,
+		children: make(map[int32][]int32),
+		types:    make(map[int32][]int32),
+		totals:   make(map[int32]marketBrowseNode),
+	}
+	for id, g := range data.MarketGroups {
+		parent := g.ParentID
+		if _, ok := data.MarketGroups[parent]; !ok {
+			parent = 0
+		}
+		t.children[parent] = append(t.children[parent], id)
+	}
+	for id, it := range data.Types {
+		if _, ok := data.MarketGroups[it.MarketGroup]; ok {
+			t.types[it.MarketGroup] = append(t.types[it.MarketGroup], id)
+		}
+	}
+	return t
+}
+
+// node returns the group with its subtree totals.
+func (t *marketBrowseTree) node(groupID int32) marketBrowseNode {
+	if n, ok := t.totals[groupID]; ok {
+		return n
+	}
+	g := t.data.MarketGroups[groupID]
+	n := marketBrowseNode{ID: groupID, Name: g.Name, HasTypes: g.HasTypes}
+	// Seed the memo first so a malformed parent cycle terminates.
+	t.totals[groupID] = n
+	for _, typeID := range t.types[groupID] {
+		c := t.counts[typeID]
+		n.TypeCount++
+		n.BuyOrders += c.Buy
+		n.SellOrders += c.Sell
+	}
+	for _, child := range t.children[groupID] {
+		cn := t.node(child)
+		n.TypeCount += cn.TypeCount
+		n.BuyOrders += cn.BuyOrders
+		n.SellOrders += cn.SellOrders
+	}
+	t.totals[groupID] = n
+	return n
+}
+
+// childNodes lists the non-empty child groups of parent (0 = roots) by name.
+func (t *marketBrowseTree) childNodes(parent int32) []marketBrowseNode {
+	out := []marketBrowseNode{}
+	for _, id := range t.children[parent] {
+		if n := t.node(id); n.TypeCount > 0 {
+			out = append(out, n)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func (t *marketBrowseTree) typeRow(typeID int32) marketBrowseType {
+	c := t.counts[typeID]
+	return marketBrowseType{TypeID: typeID, Name: t.data.Types[typeID].Name, BuyOrders: c.Buy, SellOrders: c.Sell}
+}
+
+// groupTypes lists the types listed directly in groupID by name.
+func (t *marketBrowseTree) groupTypes(groupID int32) []marketBrowseType {
+	out := make([]marketBrowseType, 0, len(t.types[groupID]))
+	for _, typeID := range t.types[groupID] {
+		out = append(out, t.typeRow(typeID))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// path returns the group names from the root down to groupID.
+func (t *marketBrowseTree) path(groupID int32) []string {
+	var names []string
+	for id, depth := groupID, 0; depth < 16; depth++ {
+		g, ok := t.data.MarketGroups[id]
+		if !ok {
+			break
+		}
+		names = append([]string{g.Name}, names...)
+		id = g.ParentID
+	}
+	return names
+}
+
+// search matches type names for type-ahead: prefix matches first, then
+// substring matches, each by name.
+func (t *marketBrowseTree) search(query string, limit int) []marketBrowseMatch {
+	query = strings.ToLower(query)
+	type hit struct {
+		typeID int32
+		rank   int
+	}
+	var hits []hit
+	for _, typeIDs := range t.types {
+		for _, typeID := range typeIDs {
+			name := strings.ToLower(t.data.Types[typeID].Name)
+			switch {
+			case strings.HasPrefix(name, query):
+				hits = append(hits, hit{typeID, 0})
+			case strings.Contains(name, query):
+				hits = append(hits, hit{typeID, 1})
+			}
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].rank != hits[j].rank {
+			return hits[i].rank < hits[j].rank
+		}
+		return t.data.Types[hits[i].typeID].Name < t.data.Types[hits[j].typeID].Name
+	})
+	out := make([]marketBrowseMatch, 0, min(len(hits), limit))
+	for _, h := range hits[:min(len(hits), limit)] {
+		out = append(out, marketBrowseMatch{
+			marketBrowseType: t.typeRow(h.typeID),
+			Path:             t.path(t.data.Types[h.typeID].MarketGroup),
+		})
+	}
+	return out
+}
+
+// handleMarketBrowse serves the market browse tree one level at a time:
+// without a region the regions with their cached order counts, with one
+// the child groups (and types) of group_id, or with q the matching types.
+// Order counts come from the cached region books only; nothing is fetched.
+func (s *Server) handleMarketBrowse(w http.ResponseWriter, r *http.Request) {
+	if !s.isReady() {
+		writeError(w, http.StatusServiceUnavailable, "SDE not loaded yet")
+		return
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	q := r.URL.Query()
+
+	if strings.TrimSpace(q.Get("region")) == "" {
+		type regionRow struct {
+			RegionID   int32  `json:"region_id"`
+			Name       string `json:"name"`
+			Cached     bool   `json:"cached"`
+			BuyOrders  int    `json:"buy_orders"`
+			SellOrders int    `json:"sell_orders"`
+			SnapshotAt string `json:"snapshot_at,omitempty"`
+		}
+		regions := make([]regionRow, 0, len(sdeData.Regions))
+		for id, reg := range sdeData.Regions {
+			row := regionRow{RegionID: id, Name: reg.Name}
+			if counts, updated, ok := s.esi.RegionOrderCounts(id); ok {
+				row.Cached, row.SnapshotAt = true, updated.UTC().Format(time.RFC3339)
+				for _, c := range counts {
+					row.BuyOrders += c.Buy
+					row.SellOrders += c.Sell
+				}
+			}
+			regions = append(regions, row)
+		}
+		sort.Slice(regions, func(i, j int) bool { return regions[i].Name < regions[j].Name })
+		writeJSON(w, map[string]interface{}{"regions": regions})
+		return
+	}
+
+	regionID, ok := resolveRegionQuery(sdeData, q.Get("region"), 0)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "unknown region")
+		return
+	}
+	counts, updated, cached := s.esi.RegionOrderCounts(regionID)
+	tree := newMarketBrowseTree(sdeData, counts)
+	resp := map[string]interface{}{
+		"region_id":   regionID,
+		"region_name": sdeData.Regions[regionID].Name,
+		"cached":      cached,
+	}
+	if cached {
+		resp["snapshot_at"] = updated.UTC().Format(time.RFC3339)
+	}
+
+	if query := strings.TrimSpace(q.Get("q")); query != "" {
+		if len(query) > 128 {
+			query = query[:128]
+		}
+		limit := 25
+		if n, err := strconv.Atoi(q.Get("limit")); err == nil && n > 0 {
+			limit = clampInt(n, 1, 100)
+		}
+		resp["matches"] = tree.search(query, limit)
+		writeJSON(w, resp)
+		return
+	}
+
+	groupID := int32(0)
+	if raw := strings.TrimSpace(q.Get("group_id")); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 32)
+		if _, known := sdeData.MarketGroups[int32(id)]; err != nil || !known {
+			writeError(w, http.StatusBadRequest, "unknown market group")
+			return
+		}
+		groupID = int32(id)
+		resp["group"] = tree.node(groupID)
+		resp["path"] = tree.path(groupID)
+	}
+	resp["groups"] = tree.childNodes(groupID)
+	resp["types"] = tree.groupTypes(groupID)
+	writeJSON(w, resp)
+}
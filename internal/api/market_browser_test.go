@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/sde"
+)
+
+func newMarketBrowseTestData() *sde.Data {
+	return &sde.Data{
+		Regions:      map[int32]*sde.Region{10000002: {ID: 10000002, Name: "The Forge"}},
+		RegionByName: map[string]int32{"the forge": 10000002},
+		MarketGroups: map[int32]*sde.MarketGroup{
+			533:  {ID: 533, Name: "Manufacture & Research"},
+			1031: {ID: 1031, Name: "Materials", ParentID: 533},
+			1857: {ID: 1857, Name: "Minerals", ParentID: 1031, HasTypes: true},
+			9:    {ID: 9, Name: "Ship Equipment"},
+		},
+		Types: map[int32]*sde.ItemType{
+			34: {ID: 34, Name: "Tritanium", MarketGroup: 1857},
+			35: {ID: 35, Name: "Pyerite", MarketGroup: 1857},
+			36: {ID: 36, Name: "Mexallon", MarketGroup: 1857},
+		},
+	}
+}
+
+func TestMarketBrowseTreeTotalsAndSearch(t *testing.T) {
+	tree := newMarketBrowseTree(newMarketBrowseTestData(), map[int32]esi.OrderCount{
+		34: {Buy: 3, Sell: 5},
+		35: {Sell: 2},
+	})
+
+	// Empty groups are hidden; totals roll up to the root.
+	roots := tree.childNodes(0)
+	if len(roots) != 1 || roots[0].ID != 533 {
+		t.Fatalf("roots = %+v, want Manufacture & Research only", roots)
+	}
+	if r := roots[0]; r.TypeCount != 3 || r.BuyOrders != 3 || r.SellOrders != 7 {
+		t.Fatalf("root totals = %+v", r)
+	}
+	if types := tree.groupTypes(1857); len(types) != 3 || types[0].Name != "Mexallon" || types[2].SellOrders != 5 {
+		t.Fatalf("types = %+v", types)
+	}
+
+	matches := tree.search("ri", 10)
+	if len(matches) != 2 || matches[0].Name != "Pyerite" || matches[1].Name != "Tritanium" {
+		t.Fatalf("matches = %+v", matches)
+	}
+	if p := matches[0].Path; len(p) != 3 || p[0] != "Manufacture & Research" || p[2] != "Minerals" {
+		t.Fatalf("path = %v", p)
+	}
+	if got := tree.search("trit", 10); len(got) != 1 || got[0].TypeID != 34 {
+		t.Fatalf("prefix search = %+v", got)
+	}
+}
+
+func TestHandleMarketBrowseGroupLevel(t *testing.T) {
+	srv := NewServer(config.Default(), &esi.Client{}, nil, nil, nil)
+	srv.sdeData = newMarketBrowseTestData()
+	srv.ready = true
+
+	rec := httptest.NewRecorder()
+	srv.handleMarketBrowse(rec, httptest.NewRequest(http.MethodGet, "/api/market/browse?region=The%20Forge&group_id=1031", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	var out struct {
+		RegionID int32              `json:"region_id"`
+		Cached   bool               `json:"cached"`
+		Path     []string           `json:"path"`
+		Groups   []marketBrowseNode `json:"groups"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.RegionID != 10000002 || out.Cached || len(out.Path) != 2 || len(out.Groups) != 1 || out.Groups[0].ID != 1857 {
+		t.Fatalf("response = %+v", out)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.handleMarketBrowse(rec, httptest.NewRequest(http.MethodGet, "/api/market/browse?region=The%20Forge&group_id=404", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("unknown group status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,205 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"eve-flipper/internal/corp"
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/esi"
+)
+
+const (
+	marketCoverageDefaultCoverDays = 7.0
+	marketCoverageDefaultMarkup    = 20.0
+	marketCoverageDefaultMaxItems  = 150
+	marketCoverageMaxItems         = 500
+	marketCoverageHistoryWorkers   = 6
+)
+
+type marketCoverageRequest struct {
+	TargetLocationID    int64   `json:"target_location_id"`
+	ReferenceLocationID int64   `json:"reference_location_id"` // 0 = configured reference hub
+	Doctrine            string  `json:"doctrine"`              // pasted item list; empty compares against the reference hub
+	CoverDays           float64 `json:"cover_days"`
+	MarkupPercent       float64 `json:"markup_percent"`
+	MaxItems            int     `json:"max_items"`
+}
+
+// handleMarketCoverage compares the sell orders at a target station or
+// structure with a doctrine list (or, without one, the types the reference
+// hub carries most) and flags what is missing, understocked against weekly
+// demand or cheaper at the reference, with a restock manifest priced off
+// the reference sell book. Structure markets need a logged-in character
+// with docking access.
+func (s *Server) handleMarketCoverage(w http.ResponseWriter, r *http.Request) {
+	var req marketCoverageRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, importMaxBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if req.TargetLocationID <= 0 {
+		writeError(w, http.StatusBadRequest, "target_location_id is required")
+		return
+	}
+	if !s.isReady() {
+		writeError(w, http.StatusServiceUnavailable, "SDE not loaded yet")
+		return
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	scanner := s.scanner
+	s.mu.RUnlock()
+	if scanner == nil {
+		writeError(w, http.StatusServiceUnavailable, "scanner not ready")
+		return
+	}
+
+	if req.ReferenceLocationID <= 0 {
+		req.ReferenceLocationID = s.loadConfigForUser(userIDFromRequest(r)).ReferenceStationID
+	}
+	if req.ReferenceLocationID == req.TargetLocationID {
+		writeError(w, http.StatusBadRequest, "target and reference must differ")
+		return
+	}
+	params := engine.MarketCoverageParams{CoverDays: marketCoverageDefaultCoverDays, MarkupPercent: marketCoverageDefaultMarkup}
+	if req.CoverDays > 0 {
+		params.CoverDays = clampFloat64(req.CoverDays, 1, 90)
+	}
+	if req.MarkupPercent > 0 {
+		params.MarkupPercent = clampFloat64(req.MarkupPercent, 1, 1000)
+	}
+	maxItems := marketCoverageDefaultMaxItems
+	if req.MaxItems > 0 {
+		maxItems = clampInt(req.MaxItems, 1, marketCoverageMaxItems)
+	}
+
+	_, targetRegion := s.locationSystemRegion(sdeData, req.TargetLocationID)
+	_, referenceRegion := s.locationSystemRegion(sdeData, req.ReferenceLocationID)
+	if targetRegion == 0 || referenceRegion == 0 {
+		writeError(w, http.StatusBadRequest, "unknown target or reference location")
+		return
+	}
+	targetOrders, err := s.fetchExecutionOrders(r, targetRegion, req.TargetLocationID, "sell")
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "target market: "+err.Error())
+		return
+	}
+	referenceOrders, err := s.fetchExecutionOrders(r, referenceRegion, req.ReferenceLocationID, "sell")
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "reference market: "+err.Error())
+		return
+	}
+	targetOrders = sellOrdersAt(targetOrders, req.TargetLocationID)
+	referenceOrders = sellOrdersAt(referenceOrders, req.ReferenceLocationID)
+
+	warnings := []string{}
+	var items []engine.MarketCoverageItem
+	unknown := []string{}
+	if strings.TrimSpace(req.Doctrine) != "" {
+		var parsed []corp.BuybackQuoteItem
+		parsed, unknown = corp.ParseBuybackItems(sdeData, req.Doctrine)
+		for _, it := range parsed {
+			items = append(items, engine.MarketCoverageItem{TypeID: it.TypeID, Quantity: it.Quantity})
+		}
+		if len(items) == 0 {
+			writeError(w, http.StatusBadRequest, "doctrine list names no known items")
+			return
+		}
+	} else {
+		items = referenceHubItems(referenceOrders)
+	}
+	if len(items) > maxItems {
+		warnings = append(warnings, fmt.Sprintf("only the first %d of %d items were checked", maxItems, len(items)))
+		items = items[:maxItems]
+	}
+
+	history := make(map[int32][]esi.HistoryEntry, len(items))
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, marketCoverageHistoryWorkers)
+	)
+	for _, it := range items {
+		wg.Add(1)
+		go func(typeID int32) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			entries, histErr := scanner.MarketHistory(targetRegion, typeID)
+			mu.Lock()
+			defer mu.Unlock()
+			if histErr != nil {
+				warnings = append(warnings, fmt.Sprintf("history for type %d: %v", typeID, histErr))
+				return
+			}
+			history[typeID] = entries
+		}(it.TypeID)
+	}
+	wg.Wait()
+
+	report := engine.BuildMarketCoverage(sdeData, engine.MarketCoverageInput{
+		Items:           items,
+		TargetOrders:    targetOrders,
+		ReferenceOrders: referenceOrders,
+		History:         history,
+	}, params, time.Now().UTC())
+
+	writeJSON(w, map[string]interface{}{
+		"target_location_id":      req.TargetLocationID,
+		"target_location_name":    s.esi.StationName(req.TargetLocationID),
+		"reference_location_id":   req.ReferenceLocationID,
+		"reference_location_name": s.esi.StationName(req.ReferenceLocationID),
+		"cover_days":              params.CoverDays,
+		"markup_percent":          params.MarkupPercent,
+		"lines":                   report.Lines,
+		"manifest":                report.Manifest,
+		"missing":                 report.Missing,
+		"understocked":            report.Understocked,
+		"underpriced_elsewhere":   report.Underpriced,
+		"manifest_cost":           report.ManifestCost,
+		"haul_volume":             report.HaulVolume,
+		"unknown":                 unknown,
+		"warnings":                warnings,
+	})
+}
+
+// sellOrdersAt keeps the sell orders of one station or structure; region
+// books cover every station in the region.
+func sellOrdersAt(orders []esi.MarketOrder, locationID int64) []esi.MarketOrder {
+	out := make([]esi.MarketOrder, 0, len(orders))
+	for _, o := range orders {
+		if !o.IsBuyOrder && o.LocationID == locationID {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// referenceHubItems lists the types on sale at the reference hub, the most
+// contested (by sell order count) first, as a stand-in doctrine list.
+func referenceHubItems(orders []esi.MarketOrder) []engine.MarketCoverageItem {
+	counts := make(map[int32]int)
+	for _, o := range orders {
+		if o.VolumeRemain > 0 {
+			counts[o.TypeID]++
+		}
+	}
+	items := make([]engine.MarketCoverageItem, 0, len(counts))
+	for typeID := range counts {
+		items = append(items, engine.MarketCoverageItem{TypeID: typeID})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i].TypeID, items[j].TypeID
+		if counts[a] != counts[b] {
+			return counts[a] > counts[b]
+		}
+		return a < b
+	})
+	return items
+}
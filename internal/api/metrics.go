@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"eve-flipper/internal/metrics"
+)
+
+// timedScanHandler records how long a scan handler ran, streaming included.
+func timedScanHandler(mode string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		defer func() {
+			metrics.ScanDuration.Observe(time.Since(start).Seconds(), mode)
+		}()
+		h(w, r)
+	}
+}
+
+// handleMetrics serves Prometheus metrics: ESI requests, scan durations,
+// cache hit rates and DB query timings. A hosted deployment only serves
+// them to local scrapers.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if s.isHostedDeployment() && !isLoopbackRemoteAddr(r.RemoteAddr) {
+		writeError(w, http.StatusForbidden, "metrics are only served locally")
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	metrics.WritePrometheus(w)
+}
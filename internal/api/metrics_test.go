@@ -0,0 +1,25 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"eve-flipper/internal/metrics"
+)
+
+func TestTimedScanHandler_ObservesMode(t *testing.T) {
+	before := metrics.ScanDuration.Count("metrics_test")
+	h := timedScanHandler("metrics_test", func(w http.ResponseWriter, r *http.Request) {})
+	h(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/scan", nil))
+	if got := metrics.ScanDuration.Count("metrics_test"); got != before+1 {
+		t.Fatalf("scan count = %d, want %d", got, before+1)
+	}
+
+	rec := httptest.NewRecorder()
+	(&Server{}).handleMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `eveflipper_scan_duration_seconds_count{mode="metrics_test"}`) {
+		t.Fatalf("status %d body:\n%s", rec.Code, rec.Body.String())
+	}
+}
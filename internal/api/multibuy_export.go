@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"eve-flipper/internal/sde"
+)
+
+// multibuyExportMaxItems bounds one export; the in-game multibuy window
+// takes a few hundred lines at most.
+const multibuyExportMaxItems = 1000
+
+// multibuyItem is one line of a shopping list: a scan result basket row or
+// an industry material.
+type multibuyItem struct {
+	TypeID   int32  `json:"type_id"`
+	Name     string `json:"name"`
+	Quantity int64  `json:"quantity"`
+}
+
+type multibuyExportRequest struct {
+	Items []multibuyItem `json:"items"`
+}
+
+// buildMultibuyList resolves items to their in-game names (the SDE name of
+// a known type ID, else the given name), sums duplicates in first-seen
+// order and drops rows without a name or quantity. Names no type matches
+// are returned as unknown but kept, the market window skips them itself.
+func buildMultibuyList(data *sde.Data, items []multibuyItem) (list []multibuyItem, unknown []string) {
+	list = []multibuyItem{}
+	unknown = []string{}
+	index := make(map[string]int)
+	for _, it := range items {
+		if it.Quantity <= 0 {
+			continue
+		}
+		name := strings.Join(strings.Fields(it.Name), " ")
+		typeID := it.TypeID
+		if data != nil {
+			if t, ok := data.Types[typeID]; ok && typeID > 0 {
+				name = t.Name
+			} else if id, ok := data.TypeIDByName(name); ok && data.Types[id] != nil {
+				typeID, name = id, data.Types[id].Name
+			} else if name != "" {
+				unknown = append(unknown, name)
+			}
+		}
+		if name == "" {
+			continue
+		}
+		key := strings.ToLower(name)
+		if i, ok := index[key]; ok {
+			list[i].Quantity += it.Quantity
+			continue
+		}
+		index[key] = len(list)
+		list = append(list, multibuyItem{TypeID: typeID, Name: name, Quantity: it.Quantity})
+	}
+	return list, unknown
+}
+
+// formatMultibuyLines renders "Name<sep>Quantity" lines without digit
+// grouping, which both the multibuy window and appraisal sites parse.
+func formatMultibuyLines(list []multibuyItem, sep string) string {
+	var b strings.Builder
+	for i, it := range list {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(it.Name)
+		b.WriteString(sep)
+		b.WriteString(strconv.FormatInt(it.Quantity, 10))
+	}
+	return b.String()
+}
+
+// handleExportMultibuy turns a shopping list into the in-game multibuy
+// text ("Tritanium 120000") and a tab separated blob Janice and EVEpraisal
+// accept. ?format=multibuy or ?format=appraisal returns that text alone.
+func (s *Server) handleExportMultibuy(w http.ResponseWriter, r *http.Request) {
+	var req multibuyExportRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, importMaxBytes)).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	if len(req.Items) == 0 {
+		writeError(w, 400, "items are required")
+		return
+	}
+	if len(req.Items) > multibuyExportMaxItems {
+		writeError(w, 400, "too many items")
+		return
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+
+	list, unknown := buildMultibuyList(sdeData, req.Items)
+	multibuy := formatMultibuyLines(list, " ")
+	appraisal := formatMultibuyLines(list, "\t")
+	switch strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format"))) {
+	case "multibuy":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(multibuy))
+		return
+	case "appraisal":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(appraisal))
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"items":     list,
+		"multibuy":  multibuy,
+		"appraisal": appraisal,
+		"unknown":   unknown,
+	})
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/sde"
+)
+
+func TestHandleExportMultibuy(t *testing.T) {
+	srv := NewServer(config.Default(), &esi.Client{}, nil, nil, nil)
+	srv.sdeData = &sde.Data{Types: map[int32]*sde.ItemType{
+		34: {ID: 34, Name: "Tritanium"},
+		35: {ID: 35, Name: "Pyerite"},
+	}}
+	body, _ := json.Marshal(multibuyExportRequest{Items: []multibuyItem{
+		{TypeID: 34, Quantity: 100000},
+		{Name: "  pyerite ", Quantity: 500},
+		{Name: "Tritanium", Quantity: 20000},
+		{Name: "Made Up Widget", Quantity: 3},
+		{TypeID: 35, Quantity: 0},
+	}})
+
+	rec := httptest.NewRecorder()
+	srv.handleExportMultibuy(rec, httptest.NewRequest(http.MethodPost, "/api/export/multibuy", bytes.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Multibuy  string   `json:"multibuy"`
+		Appraisal string   `json:"appraisal"`
+		Unknown   []string `json:"unknown"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Tritanium 120000\nPyerite 500\nMade Up Widget 3"; resp.Multibuy != want {
+		t.Fatalf("multibuy = %q, want %q", resp.Multibuy, want)
+	}
+	if want := "Tritanium\t120000\nPyerite\t500\nMade Up Widget\t3"; resp.Appraisal != want {
+		t.Fatalf("appraisal = %q, want %q", resp.Appraisal, want)
+	}
+	if len(resp.Unknown) != 1 || resp.Unknown[0] != "Made Up Widget" {
+		t.Fatalf("unknown = %v", resp.Unknown)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.handleExportMultibuy(rec, httptest.NewRequest(http.MethodPost, "/api/export/multibuy?format=multibuy", bytes.NewReader(body)))
+	if got := rec.Body.String(); got != resp.Multibuy {
+		t.Fatalf("plain multibuy = %q", got)
+	}
+}
@@ -0,0 +1,199 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/engine"
+)
+
+// onboardingStarterWatchlist is a handful of items that trade in volume in
+// every trade hub, so a new user's first scans and alerts have something to
+// show.
+var onboardingStarterWatchlist = []int32{
+	34,    // Tritanium
+	35,    // Pyerite
+	36,    // Mexallon
+	37,    // Isogen
+	38,    // Nocxium
+	39,    // Zydrine
+	40,    // Megacyte
+	40519, // Skill Extractor
+	40520, // Large Skill Injector
+}
+
+type onboardingSeedRequest struct {
+	// Overwrite replaces settings the user already changed; by default only
+	// untouched defaults are seeded.
+	Overwrite bool `json:"overwrite"`
+	// DryRun returns the proposed defaults without saving them.
+	DryRun bool `json:"dry_run"`
+}
+
+type onboardingSeedResponse struct {
+	FirstRun         bool                   `json:"first_run"`
+	Applied          bool                   `json:"applied"`
+	SystemName       string                 `json:"system_name,omitempty"`
+	StationName      string                 `json:"station_name,omitempty"`
+	SalesTaxPercent  float64                `json:"sales_tax_percent"`
+	BrokerFeePercent float64                `json:"broker_fee_percent"`
+	Accounting       int                    `json:"accounting"`
+	BrokerRelations  int                    `json:"broker_relations"`
+	Seeded           []string               `json:"seeded"`
+	Skipped          []string               `json:"skipped"`
+	WatchlistAdded   []config.WatchlistItem `json:"watchlist_added"`
+}
+
+func roundFeePercent(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
+
+// handleAuthOnboardingSeed seeds a new user's settings from their character:
+// home system from the current location, sales tax and broker fee from skills
+// and standings, and a starter watchlist of liquid items. Settings the user
+// already changed are left alone unless overwrite is set.
+func (s *Server) handleAuthOnboardingSeed(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	var req onboardingSeedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	if s.sessions == nil {
+		writeError(w, 401, "not logged in")
+		return
+	}
+	sess := s.sessions.GetForUser(userID)
+	if sess == nil {
+		writeError(w, 401, "not logged in")
+		return
+	}
+	token, err := s.sessions.EnsureValidTokenForUserCharacter(s.sso, userID, sess.CharacterID)
+	if err != nil {
+		writeError(w, 401, err.Error())
+		return
+	}
+
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	if sdeData == nil {
+		writeError(w, 503, "SDE not loaded yet")
+		return
+	}
+
+	cfg := s.loadConfigForUser(userID)
+	defaults := config.Default()
+	var watchlist []config.WatchlistItem
+	if s.db != nil {
+		watchlist = s.db.GetWatchlistForUser(userID)
+	}
+	resp := onboardingSeedResponse{
+		FirstRun:       cfg.SystemName == "" && len(watchlist) == 0,
+		Seeded:         []string{},
+		Skipped:        []string{},
+		WatchlistAdded: []config.WatchlistItem{},
+	}
+
+	// Home system from the character's current location.
+	var stationID int64
+	if loc, err := s.esi.GetCharacterLocation(sess.CharacterID, token); err != nil {
+		log.Printf("[ONBOARDING] Location unavailable for %s: %v", sess.CharacterName, err)
+		resp.Skipped = append(resp.Skipped, "system_name")
+	} else {
+		stationID = loc.StationID
+		if sys, ok := sdeData.Systems[loc.SolarSystemID]; ok {
+			resp.SystemName = sys.Name
+		}
+		if st, ok := sdeData.Stations[loc.StationID]; ok {
+			resp.StationName = st.Name
+		}
+		switch {
+		case resp.SystemName == "":
+			resp.Skipped = append(resp.Skipped, "system_name")
+		case req.Overwrite || cfg.SystemName == "":
+			cfg.SystemName = resp.SystemName
+			resp.Seeded = append(resp.Seeded, "system_name")
+		default:
+			resp.Skipped = append(resp.Skipped, "system_name")
+		}
+	}
+
+	// Fees from Accounting and Broker Relations; the broker fee uses the
+	// standings toward the owner of the station the character is docked in.
+	if skills, err := s.esi.GetSkills(sess.CharacterID, token); err != nil {
+		log.Printf("[ONBOARDING] Skills unavailable for %s: %v", sess.CharacterName, err)
+		resp.Skipped = append(resp.Skipped, "fees")
+	} else {
+		resp.Accounting = engine.SkillLevel(skills, engine.SkillAccounting)
+		resp.BrokerRelations = engine.SkillLevel(skills, engine.SkillBrokerRelations)
+		resp.SalesTaxPercent = roundFeePercent(engine.SalesTaxPercent(resp.Accounting))
+		brokerFee := engine.NPCBrokerFeePercent(resp.BrokerRelations, 0, 0)
+		if fee, ok := s.brokerFeeSchedule(userID).StationPercent(stationID); ok {
+			brokerFee = fee
+		}
+		resp.BrokerFeePercent = roundFeePercent(brokerFee)
+
+		feesUntouched := !cfg.SplitTradeFees &&
+			cfg.SalesTaxPercent == defaults.SalesTaxPercent &&
+			cfg.BrokerFeePercent == defaults.BrokerFeePercent
+		if req.Overwrite || feesUntouched {
+			cfg.SalesTaxPercent = resp.SalesTaxPercent
+			cfg.BrokerFeePercent = resp.BrokerFeePercent
+			cfg.SellSalesTaxPercent = resp.SalesTaxPercent
+			cfg.BuyBrokerFeePercent = resp.BrokerFeePercent
+			cfg.SellBrokerFeePercent = resp.BrokerFeePercent
+			resp.Seeded = append(resp.Seeded, "fees")
+		} else {
+			resp.Skipped = append(resp.Skipped, "fees")
+		}
+	}
+
+	// Starter watchlist, only for an empty watchlist unless overwriting.
+	var starter []config.WatchlistItem
+	if s.db != nil && (req.Overwrite || len(watchlist) == 0) {
+		now := time.Now().Format(time.RFC3339)
+		for _, typeID := range onboardingStarterWatchlist {
+			t, ok := sdeData.Types[typeID]
+			if !ok || engine.IsMarketDisabledTypeID(typeID) {
+				continue
+			}
+			starter = append(starter, config.WatchlistItem{
+				TypeID:      typeID,
+				TypeName:    t.Name,
+				AddedAt:     now,
+				AlertMetric: "margin_percent",
+			})
+		}
+	} else {
+		resp.Skipped = append(resp.Skipped, "watchlist")
+	}
+
+	if req.DryRun {
+		resp.WatchlistAdded = append(resp.WatchlistAdded, starter...)
+		writeJSON(w, resp)
+		return
+	}
+
+	if err := s.saveConfigForUser(userID, cfg); err != nil {
+		writeError(w, 500, "failed to save config")
+		return
+	}
+	for _, item := range starter {
+		if s.db.AddWatchlistItemForUser(userID, item) {
+			resp.WatchlistAdded = append(resp.WatchlistAdded, item)
+		}
+	}
+	if len(starter) > 0 {
+		resp.Seeded = append(resp.Seeded, "watchlist")
+	}
+	resp.Applied = true
+	log.Printf("[ONBOARDING] Seeded %v for %s (skipped %v)", resp.Seeded, sess.CharacterName, resp.Skipped)
+	writeJSON(w, resp)
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"log"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+// startOrderCacheRefresher keeps recently scanned region books warm between
+// scans.
+func (s *Server) startOrderCacheRefresher() {
+	s.startBackgroundJob(backgroundJob{
+		name:     "order_cache_refresh",
+		interval: esi.OrderCacheRefreshInterval,
+		catchUp:  catchUpSkip,
+		run: func(time.Time) {
+			if n := s.esi.RefreshOrderCache(); n > 0 {
+				log.Printf("[ESI] OrderCache refreshed %d region book(s) in background", n)
+			}
+		},
+	})
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"eve-flipper/internal/engine"
+)
+
+// handleAuthOrderDeskActions returns the order desk's reprice recommendations
+// grouped by station, as JSON or, with ?format=text, as plain text to paste
+// into EVE chat or notes. Takes the same query options as the order desk.
+func (s *Server) handleAuthOrderDeskActions(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format != "" && format != "json" && format != "text" {
+		writeError(w, http.StatusBadRequest, "format must be json or text")
+		return
+	}
+	desk, ok := s.orderDeskForRequest(w, r)
+	if !ok {
+		return
+	}
+	stations := engine.BuildRepriceActions(desk.Orders)
+	if format == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, engine.RenderRepriceActionsText(stations))
+		return
+	}
+	count := 0
+	for _, st := range stations {
+		count += len(st.Actions)
+	}
+	writeJSON(w, map[string]interface{}{
+		"stations": stations,
+		"count":    count,
+	})
+}
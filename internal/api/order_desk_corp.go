@@ -0,0 +1,90 @@
+package api
+
+import (
+	"log"
+
+	"eve-flipper/internal/auth"
+	"eve-flipper/internal/corp"
+	"eve-flipper/internal/esi"
+)
+
+// corpOrderRoles are the corporation roles that can read corporation market
+// orders (Director implies both).
+var corpOrderRoles = map[string]bool{
+	"Director":   true,
+	"Accountant": true,
+	"Trader":     true,
+}
+
+// corpOrdersForDesk returns the active market orders of sess's corporation
+// when the character holds a role that can read them. seenCorps keeps
+// corporations already loaded for other characters of the same user.
+func (s *Server) corpOrdersForDesk(sess *auth.Session, token string, seenCorps map[int32]bool) []esi.CharacterOrder {
+	roles, err := s.esi.GetCharacterRoles(sess.CharacterID, token)
+	if err != nil {
+		log.Printf("[AUTH] OrderDesk roles error (%s): %v", sess.CharacterName, err)
+		return nil
+	}
+	allowed := false
+	for _, role := range roles.Roles {
+		if corpOrderRoles[role] {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil
+	}
+	corpID, err := s.esi.GetCharacterCorporationID(sess.CharacterID)
+	if err != nil || corpID <= 0 || seenCorps[corpID] {
+		return nil
+	}
+	seenCorps[corpID] = true
+
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	corpOrders, err := corp.NewESICorpProvider(s.esi, sdeData, token, corpID, sess.CharacterID).GetOrders()
+	if err != nil {
+		log.Printf("[AUTH] OrderDesk corp orders error (%s): %v", sess.CharacterName, err)
+		return nil
+	}
+	return corpOrdersAsCharacterOrders(corpOrders)
+}
+
+// corpOrdersAsCharacterOrders converts corporation orders to the character
+// order shape the order desk works on.
+func corpOrdersAsCharacterOrders(in []corp.CorpMarketOrder) []esi.CharacterOrder {
+	out := make([]esi.CharacterOrder, 0, len(in))
+	for _, o := range in {
+		out = append(out, esi.CharacterOrder{
+			OrderID:      o.OrderID,
+			TypeID:       o.TypeID,
+			LocationID:   o.LocationID,
+			RegionID:     o.RegionID,
+			Price:        o.Price,
+			VolumeRemain: o.VolumeRemain,
+			VolumeTotal:  o.VolumeTotal,
+			IsBuyOrder:   o.IsBuyOrder,
+			Duration:     o.Duration,
+			Issued:       o.Issued,
+			TypeName:     o.TypeName,
+			LocationName: o.LocationName,
+		})
+	}
+	return out
+}
+
+// dedupeCharacterOrders drops repeated order IDs, keeping the first.
+func dedupeCharacterOrders(orders []esi.CharacterOrder) []esi.CharacterOrder {
+	seen := make(map[int64]bool, len(orders))
+	out := orders[:0]
+	for _, o := range orders {
+		if seen[o.OrderID] {
+			continue
+		}
+		seen[o.OrderID] = true
+		out = append(out, o)
+	}
+	return out
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"testing"
+
+	"eve-flipper/internal/corp"
+	"eve-flipper/internal/esi"
+)
+
+func TestCorpOrdersMergeIntoDesk(t *testing.T) {
+	own := []esi.CharacterOrder{{OrderID: 1, TypeID: 34}, {OrderID: 2, TypeID: 35}}
+	corpOrders := corpOrdersAsCharacterOrders([]corp.CorpMarketOrder{
+		{OrderID: 2, TypeID: 35, RegionID: 10000002, Price: 5},
+		{OrderID: 3, TypeID: 36, RegionID: 10000002, Price: 7, IsBuyOrder: true, VolumeRemain: 10},
+	})
+	if len(corpOrders) != 2 || corpOrders[1].RegionID != 10000002 || !corpOrders[1].IsBuyOrder || corpOrders[1].VolumeRemain != 10 {
+		t.Fatalf("unexpected conversion: %+v", corpOrders)
+	}
+
+	merged := dedupeCharacterOrders(append(own, corpOrders...))
+	if len(merged) != 3 {
+		t.Fatalf("merged = %d orders, want 3", len(merged))
+	}
+	for i, want := range []int64{1, 2, 3} {
+		if merged[i].OrderID != want {
+			t.Fatalf("merged[%d].OrderID = %d, want %d", i, merged[i].OrderID, want)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/esi"
+)
+
+// The order desk watcher re-evaluates each opted-in user's order desk every
+// OrderDeskAlertMinutes and alerts when an order turns from hold to reprice
+// or cancel — an undercut alarm for traders away from the keyboard.
+const (
+	orderDeskWatchTick       = time.Minute
+	orderDeskWatchMinMinutes = 2
+)
+
+// orderDeskWatchState is the last evaluated recommendation per order ID.
+type orderDeskWatchState struct {
+	lastRun time.Time
+	recs    map[int64]string
+}
+
+// startOrderDeskWatcher runs pollOrderDesks every orderDeskWatchTick. The
+// last recommendations are persisted, so orders that turned reprice or
+// cancel while the app was closed alert on the first poll after a restart.
+func (s *Server) startOrderDeskWatcher() {
+	s.startBackgroundJob(backgroundJob{
+		name:         "order_desk_watch",
+		interval:     orderDeskWatchTick,
+		catchUp:      catchUpSkip,
+		run:          s.pollOrderDesks,
+		saveState:    s.orderDeskWatchSnapshot,
+		restoreState: s.restoreOrderDeskWatch,
+	})
+}
+
+// orderDeskWatchSaved is the persisted form of orderDeskWatchState.
+type orderDeskWatchSaved struct {
+	LastRun time.Time        `json:"last_run"`
+	Recs    map[int64]string `json:"recs"`
+}
+
+// orderDeskWatchSnapshot encodes the watcher state for persistence.
+func (s *Server) orderDeskWatchSnapshot() string {
+	s.orderDeskWatchMu.Lock()
+	saved := make(map[string]orderDeskWatchSaved, len(s.orderDeskWatch))
+	for userID, st := range s.orderDeskWatch {
+		saved[userID] = orderDeskWatchSaved{LastRun: st.lastRun, Recs: st.recs}
+	}
+	s.orderDeskWatchMu.Unlock()
+	raw, err := json.Marshal(saved)
+	if err != nil {
+		return ""
+	}
+	return string(raw)
+}
+
+// restoreOrderDeskWatch loads watcher state saved by orderDeskWatchSnapshot.
+func (s *Server) restoreOrderDeskWatch(state string) {
+	var saved map[string]orderDeskWatchSaved
+	if err := json.Unmarshal([]byte(state), &saved); err != nil {
+		log.Printf("[ALERT] Order desk watch state: %v", err)
+		return
+	}
+	s.orderDeskWatchMu.Lock()
+	defer s.orderDeskWatchMu.Unlock()
+	s.orderDeskWatch = make(map[string]*orderDeskWatchState, len(saved))
+	for userID, st := range saved {
+		s.orderDeskWatch[userID] = &orderDeskWatchState{lastRun: st.LastRun, recs: st.Recs}
+	}
+}
+
+// pollOrderDesks evaluates the desks of users that enabled order desk alerts
+// and are due.
+func (s *Server) pollOrderDesks(now time.Time) {
+	if s.db == nil || s.sessions == nil || s.esi == nil || !s.isReady() {
+		return
+	}
+	for _, userID := range s.sessions.UserIDs() {
+		cfg := s.loadConfigForUser(userID)
+		if cfg == nil || !cfg.OrderDeskAlerts {
+			continue
+		}
+		interval := time.Duration(max(cfg.OrderDeskAlertMinutes, orderDeskWatchMinMinutes)) * time.Minute
+
+		s.orderDeskWatchMu.Lock()
+		if s.orderDeskWatch == nil {
+			s.orderDeskWatch = make(map[string]*orderDeskWatchState)
+		}
+		state := s.orderDeskWatch[userID]
+		due := state == nil || now.Sub(state.lastRun) >= interval
+		s.orderDeskWatchMu.Unlock()
+		if !due {
+			continue
+		}
+
+		orders, ok := s.watchedDeskOrders(userID)
+		if !ok {
+			continue
+		}
+		desk := s.computeOrderDesk(context.Background(), orders, engine.OrderDeskOptions{
+			SalesTaxPercent:  cfg.SalesTaxPercent,
+			BrokerFeePercent: 1.0,
+			TargetETADays:    3,
+			WarnExpiryDays:   2,
+		})
+
+		s.orderDeskWatchMu.Lock()
+		var prev map[int64]string
+		if state != nil {
+			prev = state.recs
+		}
+		alerts, recs := orderDeskTransitions(prev, desk.Orders)
+		s.orderDeskWatch[userID] = &orderDeskWatchState{lastRun: now, recs: recs}
+		s.orderDeskWatchMu.Unlock()
+
+		for _, o := range alerts {
+			alert := AlertCheckResult{
+				ShouldAlert:  true,
+				TypeID:       o.TypeID,
+				TypeName:     o.TypeName,
+				Metric:       "order_desk_" + o.Recommendation,
+				Threshold:    o.Price,
+				CurrentValue: o.SuggestedPrice,
+				Message:      formatOrderDeskAlert(o),
+			}
+			if err := s.SendAlert(userID, cfg, alert, nil); err != nil {
+				log.Printf("[ALERT] Order desk alert for order %d: %v", o.OrderID, err)
+			}
+		}
+	}
+}
+
+// watchedDeskOrders fetches the active orders of all of a user's characters.
+// ok is false when no character could be read, so a failed poll does not
+// reset the recorded recommendations.
+func (s *Server) watchedDeskOrders(userID string) ([]esi.CharacterOrder, bool) {
+	var orders []esi.CharacterOrder
+	ok := false
+	for _, sess := range s.sessions.ListForUser(userID) {
+		token, err := s.sessions.EnsureValidTokenForUserCharacter(s.sso, userID, sess.CharacterID)
+		if err != nil {
+			continue
+		}
+		charOrders, err := s.esi.GetCharacterOrders(sess.CharacterID, token)
+		if err != nil {
+			log.Printf("[ALERT] Order desk watch orders error (%s): %v", sess.CharacterName, err)
+			continue
+		}
+		ok = true
+		orders = append(orders, charOrders...)
+	}
+	return orders, ok
+}
+
+// orderDeskTransitions returns the orders that turned reprice or cancel since
+// prev, and the recommendations to remember for the next poll. A nil prev
+// (first poll) only seeds the state.
+func orderDeskTransitions(prev map[int64]string, orders []engine.OrderDeskOrder) ([]engine.OrderDeskOrder, map[int64]string) {
+	recs := make(map[int64]string, len(orders))
+	var alerts []engine.OrderDeskOrder
+	for _, o := range orders {
+		recs[o.OrderID] = o.Recommendation
+		if prev == nil || (o.Recommendation != "reprice" && o.Recommendation != "cancel") {
+			continue
+		}
+		if before, seen := prev[o.OrderID]; seen && before == o.Recommendation {
+			continue
+		}
+		alerts = append(alerts, o)
+	}
+	return alerts, recs
+}
+
+func formatOrderDeskAlert(o engine.OrderDeskOrder) string {
+	side := "Sell"
+	if o.IsBuyOrder {
+		side = "Buy"
+	}
+	if o.Recommendation == "reprice" {
+		return fmt.Sprintf("Undercut: %s order %s @ %s — reprice %.2f → %.2f ISK (best %.2f)",
+			side, o.TypeName, o.LocationName, o.Price, o.SuggestedPrice, o.BestPrice)
+	}
+	return fmt.Sprintf("Cancel: %s order %s @ %s at %.2f ISK — %s",
+		side, o.TypeName, o.LocationName, o.Price, o.Reason)
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"eve-flipper/internal/engine"
+)
+
+func TestOrderDeskTransitions(t *testing.T) {
+	orders := []engine.OrderDeskOrder{
+		{OrderID: 1, Recommendation: "hold"},
+		{OrderID: 2, Recommendation: "reprice"},
+	}
+	alerts, recs := orderDeskTransitions(nil, orders)
+	if len(alerts) != 0 || recs[2] != "reprice" {
+		t.Fatalf("first poll should only seed state: alerts=%v recs=%v", alerts, recs)
+	}
+
+	orders = []engine.OrderDeskOrder{
+		{OrderID: 1, Recommendation: "reprice", TypeName: "Tritanium", Price: 5.1, SuggestedPrice: 5.01, BestPrice: 5.02},
+		{OrderID: 2, Recommendation: "reprice"}, // still reprice: already alerted
+		{OrderID: 3, Recommendation: "cancel", IsBuyOrder: true},
+	}
+	alerts, _ = orderDeskTransitions(recs, orders)
+	if len(alerts) != 2 || alerts[0].OrderID != 1 || alerts[1].OrderID != 3 {
+		t.Fatalf("alerts = %+v, want orders 1 and 3", alerts)
+	}
+	if msg := formatOrderDeskAlert(alerts[0]); !strings.Contains(msg, "5.10 → 5.01") || !strings.Contains(msg, "Tritanium") {
+		t.Fatalf("reprice message = %q", msg)
+	}
+	if msg := formatOrderDeskAlert(alerts[1]); !strings.HasPrefix(msg, "Cancel: Buy order") {
+		t.Fatalf("cancel message = %q", msg)
+	}
+}
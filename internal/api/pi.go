@@ -95,8 +95,10 @@ func (s *Server) handleAuthPIPlanets(w http.ResponseWriter, r *http.Request) {
 	sdeData := s.sdeData
 	s.mu.RUnlock()
 	schematics := map[int32]*sde.PlanetSchematic{}
-	if sdeData != nil && sdeData.Industry != nil {
-		schematics = sdeData.Industry.PlanetSchematics
+	if sdeData != nil {
+		if industry := sdeData.IndustryData(); industry != nil {
+			schematics = industry.PlanetSchematics
+		}
 	}
 
 	var rows []piPlanetRow
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/pricing"
+)
+
+// piArbitrageDefaultPOCOTax is a typical player customs office tax rate.
+const piArbitrageDefaultPOCOTax = 10.0
+
+type piArbitrageHubView struct {
+	engine.TradeHub
+	Jumps int `json:"jumps"` // from the origin system; -1 without one
+}
+
+// handlePIArbitrage scans PI commodity chains across the major hubs (or
+// those within max_jumps of system_name) for profitable factory planet
+// setups and hub-to-hub PI flips.
+func (s *Server) handlePIArbitrage(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SystemName       string   `json:"system_name"`
+		MaxJumps         int      `json:"max_jumps"` // 0 = all hubs
+		Hubs             []string `json:"hubs"`
+		POCOTaxPercent   *float64 `json:"poco_tax_percent"`   // nil → 10
+		SalesTaxPercent  *float64 `json:"sales_tax_percent"`  // nil → config
+		BrokerFeePercent *float64 `json:"broker_fee_percent"` // nil → config
+		SellToBuyOrders  bool     `json:"sell_to_buy_orders"`
+		MinTier          int      `json:"min_tier"`
+		PriceSource      string   `json:"price_source"`
+		Limit            int      `json:"limit"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	if !s.isReady() {
+		writeError(w, 503, "SDE not loaded yet")
+		return
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+
+	origin := int32(0)
+	if name := strings.TrimSpace(req.SystemName); name != "" {
+		id, ok := sdeData.SystemByName[strings.ToLower(name)]
+		if !ok {
+			writeError(w, 400, "unknown system")
+			return
+		}
+		origin = id
+	}
+	wanted := make(map[string]bool, len(req.Hubs))
+	for _, h := range req.Hubs {
+		wanted[strings.ToLower(strings.TrimSpace(h))] = true
+	}
+	userID := userIDFromRequest(r)
+	universe := s.userUniverse(userID)
+	var hubs []piArbitrageHubView
+	for _, hub := range engine.MajorTradeHubs {
+		if len(wanted) > 0 && !wanted[strings.ToLower(hub.Name)] {
+			continue
+		}
+		jumps := -1
+		if origin != 0 && universe != nil {
+			jumps = universe.ShortestPath(origin, hub.SystemID)
+			if req.MaxJumps > 0 && (jumps < 0 || jumps > req.MaxJumps) {
+				continue
+			}
+		}
+		hubs = append(hubs, piArbitrageHubView{TradeHub: hub, Jumps: jumps})
+	}
+	if len(hubs) == 0 {
+		writeError(w, 400, "no trade hubs match the filter")
+		return
+	}
+
+	cfg := s.loadConfigForUser(userID)
+	params := engine.PIArbitrageParams{
+		POCOTaxPercent:   piArbitrageDefaultPOCOTax,
+		SalesTaxPercent:  cfg.SalesTaxPercent,
+		BrokerFeePercent: cfg.BrokerFeePercent,
+		SellToBuyOrders:  req.SellToBuyOrders,
+		MinTier:          clampInt(req.MinTier, 1, 4),
+		RoutePreferences: s.routePreferences(userID),
+	}
+	if req.POCOTaxPercent != nil {
+		params.POCOTaxPercent = clampFloat64(*req.POCOTaxPercent, 0, 100)
+	}
+	if req.SalesTaxPercent != nil {
+		params.SalesTaxPercent = clampFloat64(*req.SalesTaxPercent, 0, 100)
+	}
+	if req.BrokerFeePercent != nil {
+		params.BrokerFeePercent = clampFloat64(*req.BrokerFeePercent, 0, 100)
+	}
+
+	src, err := s.priceSources.Get(req.PriceSource, pricing.SourceFuzzwork)
+	if err != nil {
+		writeError(w, 400, err.Error())
+		return
+	}
+	tiers := engine.PITiers(sdeData)
+	typeIDs := make([]int32, 0, len(tiers))
+	for id := range tiers {
+		typeIDs = append(typeIDs, id)
+	}
+	sort.Slice(typeIDs, func(i, j int) bool { return typeIDs[i] < typeIDs[j] })
+	for _, hub := range hubs {
+		quotes, err := src.Quotes(pricing.Hub{RegionID: hub.RegionID, StationID: hub.StationID}, typeIDs)
+		if err != nil {
+			writeError(w, 502, fmt.Sprintf("failed to fetch %s prices: %v", hub.Name, err))
+			return
+		}
+		params.Hubs = append(params.Hubs, engine.PIHub{Name: hub.Name, SystemID: hub.SystemID, Quotes: quotes})
+	}
+
+	result := engine.ScanPIArbitrage(sdeData, params)
+	limit := 100
+	if req.Limit > 0 {
+		limit = clampInt(req.Limit, 1, 500)
+	}
+	if len(result.Factories) > limit {
+		result.Factories = result.Factories[:limit]
+	}
+	if len(result.Flips) > limit {
+		result.Flips = result.Flips[:limit]
+	}
+	writeJSON(w, map[string]interface{}{
+		"hubs":             hubs,
+		"price_source":     src.Name(),
+		"poco_tax_percent": params.POCOTaxPercent,
+		"factories":        result.Factories,
+		"flips":            result.Flips,
+	})
+}
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/pricing"
+)
+
+const (
+	exposureDefaultLimit = 100
+	exposureMaxLimit     = 300
+)
+
+// handleAuthPortfolioExposure analyses the character's assets and open
+// orders as one portfolio: ISK concentration per item and market group,
+// days to liquidate at current Jita velocity, exposure to volatile items
+// and correlated holdings, with rebalancing actions. Assets are valued at
+// the Jita best sell, orders at their own price; the `limit` most valuable
+// types are analysed.
+func (s *Server) handleAuthPortfolioExposure(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	characterID, allScope, err := parseAuthScope(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !s.isReady() {
+		writeError(w, http.StatusServiceUnavailable, "SDE not loaded yet")
+		return
+	}
+	sessions, err := s.authSessionsForScope(userID, characterID, allScope, true)
+	if err != nil {
+		if strings.Contains(err.Error(), "not logged in") {
+			writeError(w, http.StatusUnauthorized, err.Error())
+		} else {
+			writeError(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	scanner := s.scanner
+	s.mu.RUnlock()
+	if scanner == nil {
+		writeError(w, http.StatusServiceUnavailable, "scanner not ready")
+		return
+	}
+
+	q := r.URL.Query()
+	limit := exposureDefaultLimit
+	if n, convErr := strconv.Atoi(q.Get("limit")); convErr == nil && n > 0 {
+		limit = clampInt(n, 1, exposureMaxLimit)
+	}
+	queryFloat := func(key string, maxValue float64) float64 {
+		if v, convErr := strconv.ParseFloat(q.Get(key), 64); convErr == nil {
+			return clampFloat64(v, 0, maxValue)
+		}
+		return 0
+	}
+	src, err := s.priceSources.Get(q.Get("price_source"), pricing.SourceFuzzwork)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var holdings []engine.ExposureHolding
+	typeSet := make(map[int32]bool)
+	warnings := []string{}
+	add := func(h engine.ExposureHolding) {
+		if _, ok := sdeData.Types[h.TypeID]; !ok || engine.IsMarketDisabledTypeID(h.TypeID) || h.Quantity <= 0 {
+			return
+		}
+		holdings = append(holdings, h)
+		typeSet[h.TypeID] = true
+	}
+	for _, sess := range sessions {
+		token, tokenErr := s.sessions.EnsureValidTokenForUserCharacter(s.sso, userID, sess.CharacterID)
+		if tokenErr != nil {
+			warnings = append(warnings, sess.CharacterName+": "+tokenErr.Error())
+			continue
+		}
+		assets, assetsErr := s.esi.GetCharacterAssets(sess.CharacterID, token)
+		if assetsErr != nil {
+			warnings = append(warnings, sess.CharacterName+" assets: "+assetsErr.Error())
+		}
+		for _, a := range assets {
+			// Blueprint copies have no market value.
+			if !a.IsBlueprintCopy {
+				add(engine.ExposureHolding{TypeID: a.TypeID, Quantity: a.Quantity, Source: engine.ExposureAsset})
+			}
+		}
+		orders, ordersErr := s.esi.GetCharacterOrders(sess.CharacterID, token)
+		if ordersErr != nil {
+			warnings = append(warnings, sess.CharacterName+" orders: "+ordersErr.Error())
+		}
+		for _, o := range orders {
+			source := engine.ExposureSellOrder
+			if o.IsBuyOrder {
+				source = engine.ExposureBuyOrder
+			}
+			add(engine.ExposureHolding{TypeID: o.TypeID, Quantity: int64(o.VolumeRemain), Source: source, UnitPrice: o.Price})
+		}
+	}
+
+	resp := map[string]interface{}{
+		"price_source": src.Name(),
+		"types_total":  len(typeSet),
+		"warnings":     warnings,
+	}
+	var prices map[int32]float64
+	if len(typeSet) > 0 {
+		jita, quoteErr := src.Quotes(pricing.Hub{RegionID: engine.JitaRegionID, StationID: engine.JitaStationID}, sortedTypeIDs(typeSet))
+		if quoteErr != nil {
+			writeError(w, http.StatusBadGateway, fmt.Sprintf("failed to fetch Jita prices: %v", quoteErr))
+			return
+		}
+		prices = make(map[int32]float64, len(jita))
+		for typeID, quote := range jita {
+			prices[typeID] = quote.Sell
+		}
+	}
+	holdings, typeIDs := topExposureHoldings(holdings, prices, limit)
+	resp["types_analyzed"] = len(typeIDs)
+
+	history := make(map[int32][]esi.HistoryEntry, len(typeIDs))
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, sellAdvisorHistoryWorkers)
+	)
+	for _, typeID := range typeIDs {
+		wg.Add(1)
+		go func(typeID int32) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			entries, histErr := scanner.MarketHistory(engine.JitaRegionID, typeID)
+			if histErr != nil {
+				return
+			}
+			mu.Lock()
+			history[typeID] = entries
+			mu.Unlock()
+		}(typeID)
+	}
+	wg.Wait()
+
+	resp["exposure"] = engine.AnalyzePortfolioExposure(sdeData, engine.ExposureInput{
+		Holdings: holdings,
+		Prices:   prices,
+		History:  history,
+	}, engine.ExposureParams{
+		MarketSharePct: queryFloat("market_share_percent", 100),
+		VolatileDRVI:   queryFloat("volatile_drvi", 1000),
+		MaxItemPct:     queryFloat("max_item_percent", 100),
+		MaxCategoryPct: queryFloat("max_category_percent", 100),
+		SlowDays:       queryFloat("slow_days", 365),
+	})
+	writeJSON(w, resp)
+}
+
+// topExposureHoldings keeps the holdings of the limit types with the most
+// ISK in them, and returns those types.
+func topExposureHoldings(holdings []engine.ExposureHolding, prices map[int32]float64, limit int) ([]engine.ExposureHolding, []int32) {
+	value := make(map[int32]float64)
+	for _, h := range holdings {
+		unit := h.UnitPrice
+		if h.Source == engine.ExposureAsset {
+			unit = prices[h.TypeID]
+		}
+		value[h.TypeID] += unit * float64(h.Quantity)
+	}
+	typeIDs := make([]int32, 0, len(value))
+	for typeID, v := range value {
+		if v > 0 {
+			typeIDs = append(typeIDs, typeID)
+		}
+	}
+	sort.Slice(typeIDs, func(i, j int) bool {
+		if value[typeIDs[i]] != value[typeIDs[j]] {
+			return value[typeIDs[i]] > value[typeIDs[j]]
+		}
+		return typeIDs[i] < typeIDs[j]
+	})
+	if len(typeIDs) > limit {
+		typeIDs = typeIDs[:limit]
+	}
+	keep := make(map[int32]bool, len(typeIDs))
+	for _, typeID := range typeIDs {
+		keep[typeID] = true
+	}
+	out := holdings[:0]
+	for _, h := range holdings {
+		if keep[h.TypeID] {
+			out = append(out, h)
+		}
+	}
+	return out, typeIDs
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"testing"
+
+	"eve-flipper/internal/engine"
+)
+
+func TestTopExposureHoldingsKeepsLargestTypes(t *testing.T) {
+	holdings := []engine.ExposureHolding{
+		{TypeID: 1, Quantity: 10, Source: engine.ExposureAsset},
+		{TypeID: 2, Quantity: 1, Source: engine.ExposureSellOrder, UnitPrice: 500},
+		{TypeID: 3, Quantity: 5, Source: engine.ExposureBuyOrder, UnitPrice: 20},
+		{TypeID: 1, Quantity: 2, Source: engine.ExposureBuyOrder, UnitPrice: 30},
+		{TypeID: 4, Quantity: 100, Source: engine.ExposureAsset}, // unpriced
+	}
+	prices := map[int32]float64{1: 40}
+
+	got, typeIDs := topExposureHoldings(holdings, prices, 2)
+	if len(typeIDs) != 2 || typeIDs[0] != 2 || typeIDs[1] != 1 {
+		t.Fatalf("types = %v, want [2 1] (500 and 460 ISK)", typeIDs)
+	}
+	if len(got) != 3 {
+		t.Fatalf("holdings = %+v, want both type 1 rows and the type 2 order", got)
+	}
+	for _, h := range got {
+		if h.TypeID == 3 || h.TypeID == 4 {
+			t.Fatalf("kept type %d", h.TypeID)
+		}
+	}
+}
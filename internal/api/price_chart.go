@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/sde"
+)
+
+// priceChartMaxPeriod bounds ?period= (weeks); history covers about a year.
+const priceChartMaxPeriod = 26
+
+// handleGetTypeHistory serves cached market history for {typeID} in ?region=
+// (ID or name, default The Forge) as weekly candles with SMA/EMA, VWAP and
+// DRVI series over ?period= weeks (default 4).
+func (s *Server) handleGetTypeHistory(w http.ResponseWriter, r *http.Request) {
+	if !s.isReady() {
+		writeError(w, http.StatusServiceUnavailable, "SDE not loaded yet")
+		return
+	}
+	typeID64, err := strconv.ParseInt(r.PathValue("typeID"), 10, 32)
+	if err != nil || typeID64 <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid type ID")
+		return
+	}
+	typeID := int32(typeID64)
+	period := engine.DefaultChartPeriodWeeks
+	if raw := strings.TrimSpace(r.URL.Query().Get("period")); raw != "" {
+		p, err := strconv.Atoi(raw)
+		if err != nil || p <= 0 || p > priceChartMaxPeriod {
+			writeError(w, http.StatusBadRequest, "period must be 1-26 weeks")
+			return
+		}
+		period = p
+	}
+
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	item, ok := sdeData.Types[typeID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "type not found")
+		return
+	}
+	regionID, ok := resolveRegionQuery(sdeData, r.URL.Query().Get("region"), engine.JitaRegionID)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "unknown region")
+		return
+	}
+
+	history, err := s.cachedMarketHistory(regionID, typeID)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "market history unavailable: "+err.Error())
+		return
+	}
+	regionName := ""
+	if region, ok := sdeData.Regions[regionID]; ok {
+		regionName = region.Name
+	}
+	writeJSON(w, map[string]interface{}{
+		"type_id":     typeID,
+		"type_name":   item.Name,
+		"region_id":   regionID,
+		"region_name": regionName,
+		"chart":       engine.BuildPriceChart(history, period),
+	})
+}
+
+// resolveRegionQuery parses a ?region= value given as an ID or a name. An
+// empty value resolves to def; an unknown region returns false.
+func resolveRegionQuery(sdeData *sde.Data, raw string, def int32) (int32, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return def, true
+	}
+	if id, err := strconv.ParseInt(raw, 10, 32); err == nil {
+		_, ok := sdeData.Regions[int32(id)]
+		return int32(id), ok
+	}
+	id, ok := sdeData.RegionByName[strings.ToLower(raw)]
+	return id, ok
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/engine"
+)
+
+const (
+	radiusSuggestHistoryLimit = 500
+	// radiusSuggestCargoTolerance is how far a stored scan's cargo may be
+	// from the current one to count: cargo caps per-trip profit.
+	radiusSuggestCargoTolerance = 0.25
+)
+
+// radiusSuggestion analyses stored radius scans from the user's home
+// system with a similar cargo hold. It returns the number of scans used.
+func (s *Server) radiusSuggestion(cfg *config.Config, r *http.Request) (engine.RadiusSuggestion, int) {
+	q := r.URL.Query()
+	p := engine.RadiusSuggestParams{MinutesPerJump: 2, DockMinutes: 1}
+	if v, err := strconv.ParseFloat(q.Get("minutes_per_jump"), 64); err == nil && v > 0 {
+		p.MinutesPerJump = clampFloat64(v, 0.1, 60)
+	}
+	if v, err := strconv.ParseFloat(q.Get("dock_minutes"), 64); err == nil && v >= 0 {
+		p.DockMinutes = clampFloat64(v, 0, 120)
+	}
+
+	var samples []engine.RadiusScanSample
+	for _, rec := range s.db.GetHistory(radiusSuggestHistoryLimit) {
+		if rec.Tab != "radius" || !strings.EqualFold(strings.TrimSpace(rec.System), strings.TrimSpace(cfg.SystemName)) {
+			continue
+		}
+		var req scanRequest
+		if err := json.Unmarshal(rec.Params, &req); err != nil {
+			continue
+		}
+		if cfg.CargoCapacity > 0 && req.CargoCapacity > 0 {
+			if ratio := req.CargoCapacity / cfg.CargoCapacity; ratio < 1-radiusSuggestCargoTolerance || ratio > 1+radiusSuggestCargoTolerance {
+				continue
+			}
+		}
+		samples = append(samples, engine.RadiusScanSample{
+			BuyRadius:  req.BuyRadius,
+			SellRadius: req.SellRadius,
+			Results:    s.db.GetFlipResults(rec.ID),
+		})
+	}
+	return engine.SuggestScanRadius(samples, p), len(samples)
+}
+
+// handleRadiusSuggestion recommends the buy/sell radius that historically
+// maximised ISK/hour for the user's home system and cargo.
+func (s *Server) handleRadiusSuggestion(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	cfg := s.loadConfigForUser(userIDFromRequest(r))
+	suggestion, scans := s.radiusSuggestion(cfg, r)
+	writeJSON(w, map[string]interface{}{
+		"system_name":    cfg.SystemName,
+		"cargo_capacity": cfg.CargoCapacity,
+		"current":        map[string]int{"buy_radius": cfg.BuyRadius, "sell_radius": cfg.SellRadius},
+		"scans":          scans,
+		"suggestion":     suggestion,
+	})
+}
+
+// handleApplyRadiusSuggestion saves the suggested radius to the config.
+func (s *Server) handleApplyRadiusSuggestion(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	userID := userIDFromRequest(r)
+	cfg := s.loadConfigForUser(userID)
+	suggestion, _ := s.radiusSuggestion(cfg, r)
+	suggested := suggestion.Suggested
+	if suggested == nil {
+		writeError(w, http.StatusConflict, "not enough stored scans to suggest a radius")
+		return
+	}
+	cfg.BuyRadius = suggested.BuyRadius
+	cfg.SellRadius = suggested.SellRadius
+	if err := s.saveConfigForUser(userID, cfg); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, cfg)
+}
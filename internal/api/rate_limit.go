@@ -0,0 +1,298 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// When the server listens beyond localhost it is usually shared by a corp,
+// so expensive endpoints are rate limited per user and per client IP (an IP
+// may carry several users, so its budget is larger), and each user may only
+// run a few scans at once. Local desktop/loopback use is never limited.
+// Client IPs come from the connection unless it is a configured trusted
+// proxy, and a user ID minted for a request that carried none counts
+// against its IP instead, so neither limit is bypassed by forged headers or
+// dropped cookies.
+
+// requestRate is a token bucket: burst requests at once, refilling at
+// perMinute.
+type requestRate struct {
+	perMinute float64
+	burst     float64
+}
+
+var requestRateLimits = map[string]requestRate{
+	"scans":      {perMinute: 6, burst: 4},
+	"station_ai": {perMinute: 10, burst: 5},
+	"corp":       {perMinute: 60, burst: 20},
+}
+
+const (
+	// requestRateIPFactor scales a class budget for the per-IP bucket.
+	requestRateIPFactor = 4
+	// maxConcurrentScansPerUser caps scans in flight (streaming or queued
+	// async jobs) per user.
+	maxConcurrentScansPerUser = 2
+	// requestLimiterMaxBuckets triggers pruning of idle buckets.
+	requestLimiterMaxBuckets = 4096
+)
+
+type rateBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type requestLimiter struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	buckets  map[string]*rateBucket
+	inflight map[string]int
+}
+
+func newRequestLimiter() *requestLimiter {
+	return &requestLimiter{
+		now:      time.Now,
+		buckets:  make(map[string]*rateBucket),
+		inflight: make(map[string]int),
+	}
+}
+
+// allow takes one token from every key's bucket, or none if any is empty,
+// in which case it returns how long until the emptiest one refills.
+func (l *requestLimiter) allow(rate requestRate, keys map[string]float64) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if len(l.buckets) > requestLimiterMaxBuckets {
+		l.pruneLocked(now)
+	}
+	var wait time.Duration
+	for key, factor := range keys {
+		b := l.refillLocked(key, rate, factor, now)
+		if b.tokens < 1 {
+			perSec := rate.perMinute * factor / 60
+			wait = max(wait, time.Duration((1-b.tokens)/perSec*float64(time.Second)))
+		}
+	}
+	if wait > 0 {
+		return false, wait
+	}
+	for key := range keys {
+		l.buckets[key].tokens--
+	}
+	return true, 0
+}
+
+func (l *requestLimiter) refillLocked(key string, rate requestRate, factor float64, now time.Time) *rateBucket {
+	burst := rate.burst * factor
+	b := l.buckets[key]
+	if b == nil {
+		b = &rateBucket{tokens: burst, last: now}
+		l.buckets[key] = b
+		return b
+	}
+	elapsed := now.Sub(b.last).Minutes()
+	b.tokens = math.Min(burst, b.tokens+elapsed*rate.perMinute*factor)
+	b.last = now
+	return b
+}
+
+// pruneLocked drops buckets idle long enough to have refilled completely.
+func (l *requestLimiter) pruneLocked(now time.Time) {
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > 10*time.Minute {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// acquireScan reserves one of the concurrent scan slots of userID (or
+// "ip|" + IP for requests without a user).
+func (l *requestLimiter) acquireScan(userID string) (*scanSlot, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inflight[userID] >= maxConcurrentScansPerUser {
+		return nil, false
+	}
+	l.inflight[userID]++
+	slot := &scanSlot{}
+	slot.release = func() {
+		slot.once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if l.inflight[userID]--; l.inflight[userID] <= 0 {
+				delete(l.inflight, userID)
+			}
+		})
+	}
+	return slot, true
+}
+
+// scanSlot is a held concurrent scan slot. An async scan job detaches it
+// from the request so it stays held until the job finishes.
+type scanSlot struct {
+	once     sync.Once
+	release  func()
+	detached bool
+}
+
+type scanSlotContextKey struct{}
+
+func scanSlotFromContext(ctx context.Context) *scanSlot {
+	slot, _ := ctx.Value(scanSlotContextKey{}).(*scanSlot)
+	return slot
+}
+
+// requestRateClass returns the rate limit class of an expensive request.
+func requestRateClass(r *http.Request) (string, bool) {
+	if class, ok := hostedQuotaFeatureForRequest(r); ok {
+		return class, true
+	}
+	if r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/corp/") {
+		return "corp", true
+	}
+	// Prices every asset stack and pulls market history for each.
+	if r.Method == http.MethodGet && r.URL.Path == "/api/assets/sell-advisor" {
+		return "scans", true
+	}
+	// Prices assets and orders and pulls market history for each type.
+	if r.Method == http.MethodGet && r.URL.Path == "/api/auth/portfolio/exposure" {
+		return "scans", true
+	}
+	// Fetches regional order books for every line that needs a restock.
+	if r.Method == http.MethodGet && r.URL.Path == "/api/stock" {
+		return "scans", true
+	}
+	return "", false
+}
+
+// SetBindHost enables per-client rate limiting when the server listens on
+// anything other than loopback.
+func (s *Server) SetBindHost(host string) {
+	if isLoopbackHost(normalizeHost(host)) {
+		s.requestLimits = nil
+		return
+	}
+	s.requestLimits = newRequestLimiter()
+	log.Printf("[API] Listening on %s: rate limiting expensive endpoints per client", host)
+}
+
+// SetTrustedProxies sets the reverse proxies (IPs or CIDRs, comma separated)
+// whose CF-Connecting-IP, X-Real-IP and X-Forwarded-For headers name the
+// client for rate limiting.
+func (s *Server) SetTrustedProxies(list string) {
+	s.trustedProxies = nil
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			log.Printf("[API] Ignoring trusted proxy %q: %v", entry, err)
+			continue
+		}
+		s.trustedProxies = append(s.trustedProxies, network)
+	}
+}
+
+func (s *Server) isTrustedProxy(ip net.IP) bool {
+	for _, network := range s.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimitClientIP returns the client IP for rate limiting: the connection's
+// address, or the one a trusted proxy forwarded.
+func (s *Server) rateLimitClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	remote := net.ParseIP(host)
+	if remote == nil || !s.isTrustedProxy(remote) {
+		return host
+	}
+	for _, header := range []string{"CF-Connecting-IP", "X-Real-IP"} {
+		if ip := net.ParseIP(strings.TrimSpace(r.Header.Get(header))); ip != nil {
+			return ip.String()
+		}
+	}
+	// The rightmost X-Forwarded-For hop not added by a trusted proxy is the
+	// first one the client could not forge.
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(hops[i]))
+		if ip == nil {
+			break
+		}
+		if !s.isTrustedProxy(ip) {
+			return ip.String()
+		}
+	}
+	return host
+}
+
+func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limits := s.requestLimits
+		if limits == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		class, ok := requestRateClass(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// A freshly minted user ID is not an identity yet: count the
+		// request (and its scans) against the client IP alone.
+		ip := s.rateLimitClientIP(r)
+		scanKey := userIDFromRequest(r)
+		keys := map[string]float64{class + "|ip|" + ip: requestRateIPFactor}
+		if minted, _ := r.Context().Value(userIDMintedContextKey).(bool); minted {
+			scanKey = "ip|" + ip
+		} else {
+			keys[class+"|user|"+scanKey] = 1
+		}
+		if ok, wait := limits.allow(requestRateLimits[class], keys); !ok {
+			secs := int(math.Ceil(wait.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(secs))
+			writeError(w, http.StatusTooManyRequests, fmt.Sprintf("rate limit exceeded, retry in %ds", secs))
+			return
+		}
+		if class != "scans" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		slot, ok := limits.acquireScan(scanKey)
+		if !ok {
+			writeError(w, http.StatusTooManyRequests, fmt.Sprintf("at most %d scans may run at once", maxConcurrentScansPerUser))
+			return
+		}
+		defer func() {
+			if !slot.detached {
+				slot.release()
+			}
+		}()
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), scanSlotContextKey{}, slot)))
+	})
+}
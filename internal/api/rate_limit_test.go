@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSetBindHost_LoopbackDisablesLimits(t *testing.T) {
+	s := &Server{}
+	s.SetBindHost("127.0.0.1")
+	if s.requestLimits != nil {
+		t.Fatal("loopback bind must not rate limit")
+	}
+	s.SetBindHost("0.0.0.0")
+	if s.requestLimits == nil {
+		t.Fatal("non-loopback bind must rate limit")
+	}
+}
+
+func TestRequestLimiter_RefillsOverTime(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	l := newRequestLimiter()
+	l.now = func() time.Time { return now }
+	rate := requestRate{perMinute: 6, burst: 2}
+	keys := map[string]float64{"scans|user|alice": 1}
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.allow(rate, keys); !ok {
+			t.Fatalf("request %d within burst denied", i)
+		}
+	}
+	ok, wait := l.allow(rate, keys)
+	if ok || wait != 10*time.Second {
+		t.Fatalf("allow = %v wait = %v, want denied for 10s", ok, wait)
+	}
+	now = now.Add(10 * time.Second)
+	if ok, _ := l.allow(rate, keys); !ok {
+		t.Fatal("request after refill denied")
+	}
+}
+
+func TestRateLimitMiddleware_LimitsPerUser(t *testing.T) {
+	s := &Server{}
+	s.SetBindHost("0.0.0.0")
+	h := s.rateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	burst := int(requestRateLimits["scans"].burst)
+	for i := 0; i < burst; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, scanRunRequest(http.MethodPost, "/api/scan", "alice"))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d status = %d, want 200", i, rec.Code)
+		}
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, scanRunRequest(http.MethodPost, "/api/scan", "alice"))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("status = %d retry-after = %q, want 429 with Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, scanRunRequest(http.MethodPost, "/api/scan", "bob"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("other user status = %d, want 200", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, scanRunRequest(http.MethodGet, "/api/config", "alice"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("cheap endpoint status = %d, want 200", rec.Code)
+	}
+}
+
+func TestRateLimitMiddleware_AsyncJobsHoldScanSlots(t *testing.T) {
+	s := &Server{}
+	s.SetBindHost("0.0.0.0")
+	release := make(chan struct{})
+	h := s.rateLimitMiddleware(s.scanJobHandler("radius", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+
+	for i := 0; i < maxConcurrentScansPerUser; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, scanRunRequest(http.MethodPost, "/api/scan?async=1", "alice"))
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("job %d status = %d, want 202", i, rec.Code)
+		}
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, scanRunRequest(http.MethodPost, "/api/scan?async=1", "alice"))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429 while scans run", rec.Code)
+	}
+
+	close(release)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		s.requestLimits.mu.Lock()
+		n := s.requestLimits.inflight["alice"]
+		s.requestLimits.mu.Unlock()
+		if n == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d scan slots still held after jobs finished", n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRateLimitClientIP_TrustsOnlyConfiguredProxies(t *testing.T) {
+	s := &Server{}
+	req := httptest.NewRequest(http.MethodPost, "/api/scan", nil)
+	req.RemoteAddr = "198.51.100.7:5000"
+	req.Header.Set("X-Forwarded-For", "203.0.113.1")
+	req.Header.Set("CF-Connecting-IP", "203.0.113.2")
+	if got := s.rateLimitClientIP(req); got != "198.51.100.7" {
+		t.Fatalf("untrusted peer ip = %q, want the connection address", got)
+	}
+
+	s.SetTrustedProxies("10.0.0.0/8, 198.51.100.7")
+	if got := s.rateLimitClientIP(req); got != "203.0.113.2" {
+		t.Fatalf("trusted proxy ip = %q, want CF-Connecting-IP", got)
+	}
+	req.Header.Del("CF-Connecting-IP")
+	req.Header.Set("X-Forwarded-For", "1.2.3.4, 203.0.113.9, 10.1.2.3")
+	if got := s.rateLimitClientIP(req); got != "203.0.113.9" {
+		t.Fatalf("forwarded ip = %q, want the rightmost untrusted hop", got)
+	}
+}
+
+func TestRateLimitMiddleware_MintedUserIDsShareTheIPBucket(t *testing.T) {
+	s := &Server{}
+	s.SetBindHost("0.0.0.0")
+	h := s.rateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	burst := int(requestRateLimits["scans"].burst * requestRateIPFactor)
+	for i := 0; i <= burst; i++ {
+		// Every request drops its cookie and gets a new user ID, and forges
+		// a different forwarded address.
+		req := scanRunRequest(http.MethodPost, "/api/scan", fmt.Sprintf("fresh%04d", i))
+		req = req.WithContext(context.WithValue(req.Context(), userIDMintedContextKey, true))
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("203.0.113.%d", i))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if i < burst && rec.Code != http.StatusOK {
+			t.Fatalf("request %d status = %d, want 200", i, rec.Code)
+		}
+		if i == burst && rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("request past the IP burst status = %d, want 429", rec.Code)
+		}
+	}
+}
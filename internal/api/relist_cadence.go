@@ -0,0 +1,179 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/esi"
+)
+
+const (
+	// watchlistBookInterval matches ESI's five-minute order cache, the
+	// finest resolution a top-of-book change can be seen at.
+	watchlistBookInterval = 5 * time.Minute
+	relistCadenceDays     = 3
+)
+
+// relistCadences is the top-of-book cadence of both sides of a book.
+type relistCadences struct {
+	LocationID int64                `json:"location_id"`
+	Days       int                  `json:"days"`
+	Sell       engine.RelistCadence `json:"sell"`
+	Buy        engine.RelistCadence `json:"buy"`
+}
+
+// relistCadence estimates how often the best prices of a type at a location
+// changed over the last days of stored order book snapshots.
+func (s *Server) relistCadence(typeID int32, locationID int64, days int, now time.Time) (relistCadences, error) {
+	out := relistCadences{LocationID: locationID, Days: days}
+	from := now.AddDate(0, 0, -days)
+	for _, side := range []string{"sell", "buy"} {
+		tops, err := s.db.ListOrderBookTops(typeID, locationID, side, from)
+		if err != nil {
+			return out, err
+		}
+		points := make([]engine.TopOfBookPoint, 0, len(tops))
+		for _, t := range tops {
+			points = append(points, engine.TopOfBookPoint{At: t.CapturedAt, Price: t.Price})
+		}
+		c := engine.EstimateRelistCadence(points, side, now)
+		if side == "sell" {
+			out.Sell = c
+		} else {
+			out.Buy = c
+		}
+	}
+	return out, nil
+}
+
+// hubStationForRegion returns the trade hub station of a region, or 0.
+func hubStationForRegion(regionID int32) int64 {
+	for _, hub := range engine.MajorTradeHubs {
+		if hub.RegionID == regionID {
+			return hub.StationID
+		}
+	}
+	return 0
+}
+
+// watchlistBookStation is the NPC station a user's watchlist books are
+// polled at: the reference station, or Jita when that is a structure.
+func watchlistBookStation(cfg *config.Config) int64 {
+	if cfg != nil && cfg.ReferenceStationID > 0 && !isPlayerStructure(cfg.ReferenceStationID) {
+		return cfg.ReferenceStationID
+	}
+	return engine.JitaStationID
+}
+
+func (s *Server) startWatchlistBookPoller() {
+	s.startBackgroundJob(backgroundJob{
+		name:     "watchlist_book_poll",
+		interval: watchlistBookInterval,
+		catchUp:  catchUpSkip,
+		run:      s.pollWatchlistBooks,
+	})
+}
+
+// pollWatchlistBooks snapshots the regional book of every watchlist type in
+// the regions of its owners' reference stations, and of every book watched
+// for competitor analysis, so relist cadence can be estimated from
+// consecutive snapshots. Unchanged books are stored once.
+func (s *Server) pollWatchlistBooks(now time.Time) {
+	if s.db == nil || s.esi == nil || !s.isReady() {
+		return
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+
+	userIDs, err := s.db.ListWatchlistUserIDs()
+	if err != nil {
+		log.Printf("[JOBS] watchlist book poll: %v", err)
+		return
+	}
+	type book struct {
+		regionID int32
+		typeID   int32
+	}
+	books := make(map[book]bool)
+	for _, userID := range userIDs {
+		_, regionID := s.locationSystemRegion(sdeData, watchlistBookStation(s.loadConfigForUser(userID)))
+		if regionID == 0 {
+			continue
+		}
+		for _, it := range s.db.GetWatchlistForUser(userID) {
+			if !engine.IsMarketDisabledTypeID(it.TypeID) {
+				books[book{regionID, it.TypeID}] = true
+			}
+		}
+	}
+	competitorBooks, err := s.db.ListCompetitorWatchBooks()
+	if err != nil {
+		log.Printf("[JOBS] competitor books: %v", err)
+	}
+	for _, cb := range competitorBooks {
+		books[book{cb.RegionID, cb.TypeID}] = true
+	}
+	for b := range books {
+		orders, err := s.esi.FetchRegionOrdersByType(b.regionID, b.typeID)
+		if err != nil {
+			log.Printf("[JOBS] watchlist book %d in %d: %v", b.typeID, b.regionID, err)
+			continue
+		}
+		s.recordRegionBook(b.regionID, b.typeID, orders, now)
+	}
+}
+
+// recordRegionBook stores a type's regional book as an order book snapshot.
+func (s *Server) recordRegionBook(regionID, typeID int32, orders []esi.MarketOrder, now time.Time) {
+	if err := s.db.RecordMarketOrderSnapshot(esi.MarketOrderSnapshot{
+		RegionID:   regionID,
+		OrderType:  "all",
+		Source:     "watchlist_poll",
+		TypeID:     typeID,
+		CapturedAt: now.UTC(),
+		Orders:     orders,
+	}); err != nil {
+		log.Printf("[JOBS] watchlist book %d in %d: %v", typeID, regionID, err)
+	}
+}
+
+// handleWatchlistCadence reports, per watchlist item, how often the best
+// prices at the user's reference station change, so items that need
+// babysitting stand out.
+//
+//	GET /api/watchlist/cadence?days=3
+func (s *Server) handleWatchlistCadence(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	days := relistCadenceDays
+	if v, err := strconv.Atoi(r.URL.Query().Get("days")); err == nil && v > 0 {
+		days = clampInt(v, 1, 30)
+	}
+	locationID := watchlistBookStation(s.loadConfigForUser(userID))
+	now := time.Now().UTC()
+
+	type row struct {
+		TypeID   int32  `json:"type_id"`
+		TypeName string `json:"type_name"`
+		relistCadences
+	}
+	out := []row{}
+	for _, it := range s.filteredWatchlist(userID) {
+		c, err := s.relistCadence(it.TypeID, locationID, days, now)
+		if err != nil {
+			writeError(w, 500, "failed to read order book snapshots")
+			return
+		}
+		out = append(out, row{TypeID: it.TypeID, TypeName: it.TypeName, relistCadences: c})
+	}
+	writeJSON(w, map[string]interface{}{
+		"location_id":   locationID,
+		"location_name": s.esi.StationName(locationID),
+		"days":          days,
+		"items":         out,
+	})
+}
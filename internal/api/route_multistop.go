@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"eve-flipper/internal/engine"
+)
+
+const maxMultiStopInputFlips = 500
+
+// handleRouteMultiStop plans a combined shopping + delivery route over flips
+// from a previous scan, sized to the given cargo hold and capital budget.
+func (s *Server) handleRouteMultiStop(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SystemName       string              `json:"system_name"`
+		CargoCapacity    float64             `json:"cargo_capacity"`
+		Budget           float64             `json:"budget"`
+		MaxFlips         int                 `json:"max_flips"`
+		MinRouteSecurity float64             `json:"min_route_security"`
+		Flips            []engine.FlipResult `json:"flips"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	if !s.isReady() {
+		writeError(w, 503, "SDE not loaded yet")
+		return
+	}
+	req.SystemName = strings.TrimSpace(req.SystemName)
+	if req.SystemName == "" {
+		writeError(w, 400, "system_name is required")
+		return
+	}
+	if len(req.Flips) == 0 {
+		writeError(w, 400, "flips are required")
+		return
+	}
+	if len(req.Flips) > maxMultiStopInputFlips {
+		req.Flips = req.Flips[:maxMultiStopInputFlips]
+	}
+
+	s.mu.RLock()
+	scanner := s.scanner
+	s.mu.RUnlock()
+
+	route, err := scanner.OptimizeMultiStopRoute(engine.MultiStopParams{
+		StartSystemName:  req.SystemName,
+		CargoCapacity:    req.CargoCapacity,
+		Budget:           req.Budget,
+		MaxFlips:         req.MaxFlips,
+		MinRouteSecurity: req.MinRouteSecurity,
+		Flips:            req.Flips,
+		RoutePreferences: s.routePreferences(userIDFromRequest(r)),
+	})
+	if err != nil {
+		writeError(w, 400, err.Error())
+		return
+	}
+	log.Printf("[API] RouteMultiStop: system=%s flips=%d stops=%d jumps=%d (sequential %d)",
+		req.SystemName, len(route.Flips), len(route.Stops), route.TotalJumps, route.SequentialJumps)
+	writeJSON(w, route)
+}
@@ -0,0 +1,324 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/graph"
+	"eve-flipper/internal/sde"
+)
+
+// maxPinnedRoutes caps the pinned paths kept per user.
+const maxPinnedRoutes = 50
+
+// routePreferences returns the user's avoided systems, with avoided regions
+// expanded to their systems, and pinned paths.
+func (s *Server) routePreferences(userID string) graph.RoutePreferences {
+	return s.routePreferencesFromConfig(s.loadConfigForUser(userID))
+}
+
+func (s *Server) routePreferencesFromConfig(cfg *config.Config) graph.RoutePreferences {
+	var prefs graph.RoutePreferences
+	avoid := make(map[int32]bool, len(cfg.AvoidSystemIDs))
+	for _, id := range cfg.AvoidSystemIDs {
+		avoid[id] = true
+	}
+	if len(cfg.AvoidRegionIDs) > 0 {
+		s.mu.RLock()
+		sdeData := s.sdeData
+		s.mu.RUnlock()
+		if sdeData != nil && sdeData.Universe != nil {
+			regions := make(map[int32]bool, len(cfg.AvoidRegionIDs))
+			for _, id := range cfg.AvoidRegionIDs {
+				regions[id] = true
+			}
+			for systemID := range sdeData.Universe.SystemsInRegions(regions) {
+				avoid[systemID] = true
+			}
+		}
+	}
+	for id := range avoid {
+		prefs.AvoidSystems = append(prefs.AvoidSystems, id)
+	}
+	sort.Slice(prefs.AvoidSystems, func(i, j int) bool { return prefs.AvoidSystems[i] < prefs.AvoidSystems[j] })
+	for _, pin := range cfg.PinnedRoutes {
+		prefs.Pins = append(prefs.Pins, pin.SystemIDs)
+	}
+	return prefs
+}
+
+// forgetRoutePreferences drops the universe cached for old preferences
+// once a config save changes them.
+func (s *Server) forgetRoutePreferences(old graph.RoutePreferences, cfg *config.Config) {
+	if old.Empty() {
+		return
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	if sdeData == nil || sdeData.Universe == nil {
+		return
+	}
+	if !reflect.DeepEqual(old, s.routePreferencesFromConfig(cfg)) {
+		sdeData.Universe.ForgetRoutePreferences(old)
+	}
+}
+
+// userUniverse returns the gate graph with the user's route preferences
+// applied, or nil before the SDE is loaded.
+func (s *Server) userUniverse(userID string) *graph.Universe {
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	if sdeData == nil || sdeData.Universe == nil {
+		return nil
+	}
+	return sdeData.Universe.WithRoutePreferences(s.routePreferences(userID))
+}
+
+// normalizeRouteAvoidance drops unknown systems and regions from the avoid
+// lists, and pins that are not gate paths or pass through an avoided system,
+// keeping the first pin per pair of ends. Without the SDE the lists are
+// kept as they are.
+func (s *Server) normalizeRouteAvoidance(cfg *config.Config) {
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	if sdeData == nil || sdeData.Universe == nil {
+		return
+	}
+	cfg.AvoidSystemIDs = normalizeIgnoredSystemIDs(sdeData.Systems, cfg.AvoidSystemIDs)
+
+	var regions []int32
+	seenRegion := make(map[int32]bool, len(cfg.AvoidRegionIDs))
+	for _, id := range cfg.AvoidRegionIDs {
+		if _, ok := sdeData.Regions[id]; ok && !seenRegion[id] {
+			seenRegion[id] = true
+			regions = append(regions, id)
+		}
+	}
+	sort.Slice(regions, func(i, j int) bool { return regions[i] < regions[j] })
+	cfg.AvoidRegionIDs = regions
+
+	avoided := make(map[int32]bool)
+	for _, id := range s.routePreferencesFromConfig(cfg).AvoidSystems {
+		avoided[id] = true
+	}
+	var pins []config.PinnedRoute
+	seenEnds := make(map[[2]int32]bool, len(cfg.PinnedRoutes))
+	for _, pin := range cfg.PinnedRoutes {
+		if !isGatePath(sdeData.Universe, pin.SystemIDs) || passesAvoided(pin.SystemIDs, avoided) {
+			continue
+		}
+		key := pinnedRouteEnds(pin.SystemIDs)
+		if seenEnds[key] {
+			continue
+		}
+		seenEnds[key] = true
+		pin.Note = strings.TrimSpace(pin.Note)
+		pins = append(pins, pin)
+		if len(pins) >= maxPinnedRoutes {
+			break
+		}
+	}
+	cfg.PinnedRoutes = pins
+}
+
+// passesAvoided reports whether a path crosses an avoided system between its
+// ends. An avoided end still leaves the pin usable leaving that system.
+func passesAvoided(path []int32, avoided map[int32]bool) bool {
+	for i := 1; i < len(path)-1; i++ {
+		if avoided[path[i]] {
+			return true
+		}
+	}
+	return false
+}
+
+// isGatePath reports whether path has two distinct ends and each system is
+// one gate jump from the next.
+func isGatePath(u *graph.Universe, path []int32) bool {
+	if len(path) < 2 || path[0] == path[len(path)-1] {
+		return false
+	}
+	for i := 1; i < len(path); i++ {
+		adjacent := false
+		for _, n := range u.Adj[path[i-1]] {
+			if n == path[i] {
+				adjacent = true
+				break
+			}
+		}
+		if !adjacent {
+			return false
+		}
+	}
+	return true
+}
+
+// pinnedRouteEnds keys a pin by its ends regardless of direction.
+func pinnedRouteEnds(path []int32) [2]int32 {
+	a, b := path[0], path[len(path)-1]
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int32{a, b}
+}
+
+// pinnedRouteView is a pinned path as the API returns it.
+type pinnedRouteView struct {
+	SystemIDs   []int32  `json:"system_ids"`
+	SystemNames []string `json:"system_names"`
+	Jumps       int      `json:"jumps"`
+	Note        string   `json:"note,omitempty"`
+}
+
+func newPinnedRouteView(systems map[int32]*sde.SolarSystem, pin config.PinnedRoute) pinnedRouteView {
+	v := pinnedRouteView{SystemIDs: pin.SystemIDs, SystemNames: make([]string, len(pin.SystemIDs)), Jumps: len(pin.SystemIDs) - 1, Note: pin.Note}
+	for i, id := range pin.SystemIDs {
+		if sys, ok := systems[id]; ok {
+			v.SystemNames[i] = sys.Name
+		} else {
+			v.SystemNames[i] = strconv.Itoa(int(id))
+		}
+	}
+	return v
+}
+
+func (s *Server) handleListPinnedRoutes(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	var systems map[int32]*sde.SolarSystem
+	if s.sdeData != nil {
+		systems = s.sdeData.Systems
+	}
+	s.mu.RUnlock()
+	cfg := s.loadConfigForUser(userIDFromRequest(r))
+	out := make([]pinnedRouteView, 0, len(cfg.PinnedRoutes))
+	for _, pin := range cfg.PinnedRoutes {
+		out = append(out, newPinnedRouteView(systems, pin))
+	}
+	writeJSON(w, out)
+}
+
+// handleSavePinnedRoute pins a path through the given waypoints, by name or
+// ID. Waypoints that are not one gate apart are joined by the shortest gate
+// path that honours the user's avoid list. The pin replaces any pin between
+// the same two systems.
+//
+//	POST /api/route/pins {"systems": ["Jita", "Perimeter", "Urlen"], "note": "..."}
+func (s *Server) handleSavePinnedRoute(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Systems   []string `json:"systems"`
+		SystemIDs []int32  `json:"system_ids"`
+		Note      string   `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	if !s.isReady() {
+		writeError(w, http.StatusServiceUnavailable, "SDE not loaded yet")
+		return
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+
+	waypoints := append([]int32(nil), req.SystemIDs...)
+	for _, name := range req.Systems {
+		id, ok := sdeData.SystemByName[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			writeError(w, 400, fmt.Sprintf("system not found: %s", name))
+			return
+		}
+		waypoints = append(waypoints, id)
+	}
+	if len(waypoints) < 2 || waypoints[0] == waypoints[len(waypoints)-1] {
+		writeError(w, 400, "at least two waypoints with different ends are required")
+		return
+	}
+	for _, id := range waypoints {
+		if _, ok := sdeData.Systems[id]; !ok {
+			writeError(w, 400, fmt.Sprintf("unknown system id: %d", id))
+			return
+		}
+	}
+
+	userID := userIDFromRequest(r)
+	cfg := s.loadConfigForUser(userID)
+	avoidOnly := s.routePreferences(userID)
+	avoidOnly.Pins = nil
+	universe := sdeData.Universe.WithRoutePreferences(avoidOnly)
+	path := []int32{waypoints[0]}
+	for _, next := range waypoints[1:] {
+		prev := path[len(path)-1]
+		if prev == next {
+			continue
+		}
+		leg := universe.GetPath(prev, next, 0)
+		if leg == nil {
+			writeError(w, 400, fmt.Sprintf("no gate path from %s to %s outside avoided systems", sdeData.Systems[prev].Name, sdeData.Systems[next].Name))
+			return
+		}
+		path = append(path, leg[1:]...)
+	}
+	if !isGatePath(sdeData.Universe, path) {
+		writeError(w, 400, "waypoints do not form a gate path")
+		return
+	}
+
+	pin := config.PinnedRoute{SystemIDs: path, Note: strings.TrimSpace(req.Note)}
+	ends := pinnedRouteEnds(path)
+	pins := []config.PinnedRoute{pin}
+	for _, existing := range cfg.PinnedRoutes {
+		if len(existing.SystemIDs) >= 2 && pinnedRouteEnds(existing.SystemIDs) == ends {
+			continue
+		}
+		pins = append(pins, existing)
+	}
+	if len(pins) > maxPinnedRoutes {
+		writeError(w, 400, fmt.Sprintf("at most %d pinned routes", maxPinnedRoutes))
+		return
+	}
+	cfg.PinnedRoutes = pins
+	if err := s.saveConfigForUser(userID, cfg); err != nil {
+		writeError(w, 500, "failed to save pinned route")
+		return
+	}
+	writeJSON(w, newPinnedRouteView(sdeData.Systems, pin))
+}
+
+func (s *Server) handleDeletePinnedRoute(w http.ResponseWriter, r *http.Request) {
+	from, errFrom := strconv.ParseInt(r.PathValue("from"), 10, 32)
+	to, errTo := strconv.ParseInt(r.PathValue("to"), 10, 32)
+	if errFrom != nil || errTo != nil || from <= 0 || to <= 0 {
+		writeError(w, 400, "invalid system ids")
+		return
+	}
+	userID := userIDFromRequest(r)
+	cfg := s.loadConfigForUser(userID)
+	ends := pinnedRouteEnds([]int32{int32(from), int32(to)})
+	kept := make([]config.PinnedRoute, 0, len(cfg.PinnedRoutes))
+	for _, pin := range cfg.PinnedRoutes {
+		if len(pin.SystemIDs) >= 2 && pinnedRouteEnds(pin.SystemIDs) == ends {
+			continue
+		}
+		kept = append(kept, pin)
+	}
+	if len(kept) == len(cfg.PinnedRoutes) {
+		writeError(w, 404, "pinned route not found")
+		return
+	}
+	cfg.PinnedRoutes = kept
+	if err := s.saveConfigForUser(userID, cfg); err != nil {
+		writeError(w, 500, "failed to delete pinned route")
+		return
+	}
+	writeJSON(w, map[string]bool{"ok": true})
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/graph"
+	"eve-flipper/internal/sde"
+)
+
+// routePrefsTestData is a ring 1-2-3-4-1 with 5 hanging off 4; system 3
+// is in region 20, the rest in region 10.
+func routePrefsTestData() *sde.Data {
+	u := graph.NewUniverse()
+	for _, gate := range [][2]int32{{1, 2}, {2, 3}, {3, 4}, {4, 1}, {4, 5}} {
+		u.AddGate(gate[0], gate[1])
+		u.AddGate(gate[1], gate[0])
+	}
+	data := &sde.Data{
+		Universe:     u,
+		Systems:      map[int32]*sde.SolarSystem{},
+		SystemByName: map[string]int32{},
+		Regions:      map[int32]*sde.Region{10: {ID: 10}, 20: {ID: 20}},
+	}
+	names := map[int32]string{1: "Alpha", 2: "Bravo", 3: "Charlie", 4: "Delta", 5: "Echo"}
+	for id, name := range names {
+		region := int32(10)
+		if id == 3 {
+			region = 20
+		}
+		data.Systems[id] = &sde.SolarSystem{ID: id, Name: name, RegionID: region}
+		data.SystemByName[strings.ToLower(name)] = id
+		u.SetRegion(id, region)
+	}
+	return data
+}
+
+func TestRoutePinsAndAvoidance(t *testing.T) {
+	const userID = "u-route-prefs"
+	srv := NewServer(config.Default(), nil, openAPITestDB(t), nil, nil)
+	srv.sdeData = routePrefsTestData()
+	srv.ready = true
+
+	do := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		addSignedUserCookie(req, srv, userID)
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Alpha->Charlie is two jumps either way round the ring; pin the
+	// Delta side by naming it as a waypoint.
+	rec := do(http.MethodPost, "/api/route/pins", []byte(`{"systems": ["Alpha", "Delta", "Charlie"], "note": "avoid Bravo camp"}`))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("pin status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var pin pinnedRouteView
+	if err := json.NewDecoder(rec.Body).Decode(&pin); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if pin.Jumps != 2 || pin.SystemNames[1] != "Delta" {
+		t.Fatalf("pin = %+v", pin)
+	}
+	if path := srv.userUniverse(userID).GetPath(3, 1, 0); len(path) != 3 || path[1] != 4 {
+		t.Fatalf("pinned path Charlie->Alpha = %v, want via Delta", path)
+	}
+	if rec := do(http.MethodPost, "/api/route/pins", []byte(`{"systems": ["Alpha", "Nowhere"]}`)); rec.Code != http.StatusBadRequest {
+		t.Fatalf("unknown system: status = %d, want 400", rec.Code)
+	}
+
+	cfg := srv.loadConfigForUser(userID)
+	cfg.AvoidSystemIDs = []int32{2, 2, 999}
+	cfg.AvoidRegionIDs = []int32{20, 99}
+	srv.normalizeRouteAvoidance(cfg)
+	if len(cfg.AvoidSystemIDs) != 1 || len(cfg.AvoidRegionIDs) != 1 || len(cfg.PinnedRoutes) != 1 {
+		t.Fatalf("normalized config = systems %v regions %v pins %v", cfg.AvoidSystemIDs, cfg.AvoidRegionIDs, cfg.PinnedRoutes)
+	}
+	if err := srv.saveConfigForUser(userID, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	prefs := srv.routePreferences(userID)
+	if len(prefs.AvoidSystems) != 2 || prefs.AvoidSystems[0] != 2 || prefs.AvoidSystems[1] != 3 {
+		t.Fatalf("avoided systems = %v, want Bravo and region 20's Charlie", prefs.AvoidSystems)
+	}
+	universe := srv.userUniverse(userID)
+	if d := universe.ShortestPath(1, 5); d != 2 {
+		t.Fatalf("Alpha->Echo = %d, want 2", d)
+	}
+	if d := universe.ShortestPath(1, 2); d != -1 {
+		t.Fatalf("Bravo should be unreachable, got %d", d)
+	}
+	if rec := do(http.MethodPost, "/api/route/pins", []byte(`{"systems": ["Alpha", "Bravo"]}`)); rec.Code != http.StatusBadRequest {
+		t.Fatalf("pin into an avoided system: status = %d, want 400", rec.Code)
+	}
+	// Avoiding Delta later drops the pin through it on the next save.
+	stale := *cfg
+	stale.AvoidSystemIDs = []int32{2, 4}
+	srv.normalizeRouteAvoidance(&stale)
+	if len(stale.PinnedRoutes) != 0 {
+		t.Fatalf("pin through avoided Delta kept: %v", stale.PinnedRoutes)
+	}
+
+	if rec := do(http.MethodDelete, "/api/route/pins/3/1", nil); rec.Code != http.StatusOK {
+		t.Fatalf("delete status = %d", rec.Code)
+	}
+	if rec := do(http.MethodDelete, "/api/route/pins/1/3", nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("second delete status = %d, want 404", rec.Code)
+	}
+}
+
+func TestUserUniverseCachedUntilConfigSave(t *testing.T) {
+	const userID = "u-route-cache"
+	srv := NewServer(config.Default(), nil, openAPITestDB(t), nil, nil)
+	srv.sdeData = routePrefsTestData()
+	srv.ready = true
+
+	cfg := srv.loadConfigForUser(userID)
+	cfg.AvoidSystemIDs = []int32{2}
+	if err := srv.saveConfigForUser(userID, cfg); err != nil {
+		t.Fatal(err)
+	}
+	first := srv.userUniverse(userID)
+	if first == srv.sdeData.Universe {
+		t.Fatal("avoid list should give a filtered universe")
+	}
+	if again := srv.userUniverse(userID); again != first {
+		t.Fatal("filtered universe should be cached between requests")
+	}
+
+	cfg.AvoidSystemIDs = []int32{3}
+	if err := srv.saveConfigForUser(userID, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if d := srv.userUniverse(userID).ShortestPath(1, 3); d != -1 {
+		t.Fatalf("1->3 after avoiding 3 = %d, want -1", d)
+	}
+	if again := srv.sdeData.Universe.WithRoutePreferences(graph.RoutePreferences{AvoidSystems: []int32{2}}); again == first {
+		t.Fatal("the old preferences should be dropped from the cache on save")
+	}
+}
@@ -8,12 +8,16 @@ import (
 
 	"eve-flipper/internal/engine"
 	"eve-flipper/internal/gankcheck"
+	"eve-flipper/internal/graph"
 )
 
 const (
 	maxRouteHaulingRiskEnrich   = 80
 	routeHaulingRiskTotalBudget = 12 * time.Second
 	routeHaulingRiskLegBudget   = 2 * time.Second
+	// shipTankReferenceEHP is the tank the raw gank score assumes: a
+	// buffer-fitted tech 1 industrial.
+	shipTankReferenceEHP = 40_000
 )
 
 type routeRiskSegmentKey struct {
@@ -27,6 +31,8 @@ func (s *Server) enrichRouteHaulingRisk(
 	startSystemName string,
 	targetSystemName string,
 	minSec float64,
+	universe *graph.Universe,
+	tankFactor float64,
 	progress func(string),
 ) []engine.RouteResult {
 	if len(routes) == 0 || s.ganker == nil {
@@ -60,7 +66,7 @@ func (s *Server) enrichRouteHaulingRisk(
 		prevSystemID := startSystemID
 		for _, hop := range routes[i].Hops {
 			if hop.SystemID > 0 && prevSystemID > 0 && hop.SystemID != prevSystemID {
-				systems, timeout := s.routeDangerSystemsCached(prevSystemID, hop.SystemID, minSec, deadline, segmentCache)
+				systems, timeout := s.routeDangerSystemsCached(universe, prevSystemID, hop.SystemID, minSec, deadline, segmentCache)
 				if timeout {
 					timedOut = true
 					break
@@ -68,7 +74,7 @@ func (s *Server) enrichRouteHaulingRisk(
 				summary.add(systems)
 			}
 			if hop.SystemID > 0 && hop.DestSystemID > 0 && hop.SystemID != hop.DestSystemID {
-				systems, timeout := s.routeDangerSystemsCached(hop.SystemID, hop.DestSystemID, minSec, deadline, segmentCache)
+				systems, timeout := s.routeDangerSystemsCached(universe, hop.SystemID, hop.DestSystemID, minSec, deadline, segmentCache)
 				if timeout {
 					timedOut = true
 					break
@@ -83,13 +89,14 @@ func (s *Server) enrichRouteHaulingRisk(
 			break
 		}
 		if targetSystemID > 0 && prevSystemID > 0 && targetSystemID != prevSystemID {
-			systems, timeout := s.routeDangerSystemsCached(prevSystemID, targetSystemID, minSec, deadline, segmentCache)
+			systems, timeout := s.routeDangerSystemsCached(universe, prevSystemID, targetSystemID, minSec, deadline, segmentCache)
 			if timeout {
 				timedOut = true
 				break
 			}
 			summary.add(systems)
 		}
+		summary.scaleForTank(tankFactor)
 		summary.applyTo(&routes[i])
 		if progress != nil && (i+1)%20 == 0 && i+1 < limit {
 			progress(fmt.Sprintf("Scoring hauling gank risk: %d/%d routes...", i+1, limit))
@@ -101,6 +108,48 @@ func (s *Server) enrichRouteHaulingRisk(
 	return routes
 }
 
+// applyFlipRouteGankRisk scores each flip's buy->sell route with the ganker,
+// within routeHaulingRiskTotalBudget, and applies the gank thresholds and
+// ISK/hour of params' ship with it. Flips left unscored when the budget
+// runs out keep the quiet-route threshold. The tank is already in the
+// threshold, so the route score is not scaled for it.
+func (s *Server) applyFlipRouteGankRisk(rows []engine.FlipResult, params engine.ScanParams, universe *graph.Universe, progress func(string)) {
+	if len(rows) > 0 && s.ganker != nil {
+		if progress != nil {
+			progress("Scoring flip route gank risk...")
+		}
+		deadline := time.Now().Add(routeHaulingRiskTotalBudget)
+		segmentCache := make(map[routeRiskSegmentKey][]gankcheck.SystemDanger)
+		for i := range rows {
+			row := &rows[i]
+			if row.BuySystemID <= 0 || row.SellSystemID <= 0 || row.BuySystemID == row.SellSystemID {
+				row.RouteSafetyMultiplier = 1
+				row.RouteSafetyDanger = "green"
+				continue
+			}
+			systems, timeout := s.routeDangerSystemsCached(universe, row.BuySystemID, row.SellSystemID, params.MinRouteSecurity, deadline, segmentCache)
+			if timeout {
+				if progress != nil {
+					progress("Flip route gank scoring timed out; remaining flips use quiet-route thresholds.")
+				}
+				break
+			}
+			summary := routeHaulingRiskSummary{}
+			summary.add(systems)
+			row.RouteSafetyMultiplier = routeSafetyMultiplierFromSummary(summary)
+			row.RouteSafetyDanger = summary.danger
+			if row.RouteSafetyDanger == "" {
+				row.RouteSafetyDanger = "green"
+			}
+			row.RouteSafetyKills = summary.kills
+			row.RouteSafetyISK = summary.totalISK
+		}
+	}
+	travel := params.ShipTravel()
+	engine.ApplyFlipGankThresholds(rows, travel.Class, params.ShipEHP, params.GankSplitTrips)
+	engine.ApplyFlipISKPerHour(rows, travel)
+}
+
 func routeHaulingRiskLimit(count int) int {
 	if count <= 0 {
 		return 0
@@ -124,8 +173,10 @@ func (s *Server) systemIDByName(name string) int32 {
 	return s.sdeData.SystemByName[name]
 }
 
-func (s *Server) routeDangerSystems(from, to int32, minSec float64) []gankcheck.SystemDanger {
-	systems, err := s.ganker.CheckRoute(from, to, minSec)
+// routeDangerSystems checks the systems on the path from->to through
+// universe (nil = all gates).
+func (s *Server) routeDangerSystems(universe *graph.Universe, from, to int32, minSec float64) []gankcheck.SystemDanger {
+	systems, err := s.ganker.CheckRouteOn(universe, from, to, minSec)
 	if err != nil {
 		return nil
 	}
@@ -133,6 +184,7 @@ func (s *Server) routeDangerSystems(from, to int32, minSec float64) []gankcheck.
 }
 
 func (s *Server) routeDangerSystemsCached(
+	universe *graph.Universe,
 	from int32,
 	to int32,
 	minSec float64,
@@ -154,7 +206,7 @@ func (s *Server) routeDangerSystemsCached(
 	if timeout > routeHaulingRiskLegBudget {
 		timeout = routeHaulingRiskLegBudget
 	}
-	systems, timedOut := s.routeDangerSystemsWithTimeout(from, to, minSec, timeout)
+	systems, timedOut := s.routeDangerSystemsWithTimeout(universe, from, to, minSec, timeout)
 	if timedOut {
 		return nil, true
 	}
@@ -162,13 +214,13 @@ func (s *Server) routeDangerSystemsCached(
 	return systems, false
 }
 
-func (s *Server) routeDangerSystemsWithTimeout(from int32, to int32, minSec float64, timeout time.Duration) ([]gankcheck.SystemDanger, bool) {
+func (s *Server) routeDangerSystemsWithTimeout(universe *graph.Universe, from int32, to int32, minSec float64, timeout time.Duration) ([]gankcheck.SystemDanger, bool) {
 	if timeout <= 0 {
 		return nil, true
 	}
 	result := make(chan []gankcheck.SystemDanger, 1)
 	go func() {
-		result <- s.routeDangerSystems(from, to, minSec)
+		result <- s.routeDangerSystems(universe, from, to, minSec)
 	}()
 	timer := time.NewTimer(timeout)
 	defer timer.Stop()
@@ -235,6 +287,26 @@ func (s *routeHaulingRiskSummary) add(systems []gankcheck.SystemDanger) {
 	}
 }
 
+// shipTankRiskFactor scales a gank score for a hauler with ehp effective
+// hit points. Gankers need more ships for a bigger tank, so the factor falls
+// with the square root of the tank relative to shipTankReferenceEHP, within
+// [0.35, 1.5]. Unknown tanks (ehp <= 0) leave the score unchanged.
+func shipTankRiskFactor(ehp float64) float64 {
+	if ehp <= 0 || math.IsNaN(ehp) || math.IsInf(ehp, 0) {
+		return 1
+	}
+	f := math.Sqrt(shipTankReferenceEHP / ehp)
+	return math.Round(math.Max(0.35, math.Min(f, 1.5))*100) / 100
+}
+
+// scaleForTank scales the score by a shipTankRiskFactor. The danger level
+// describes the systems, not the ship, and is left alone.
+func (s *routeHaulingRiskSummary) scaleForTank(factor float64) {
+	if factor > 0 && factor != 1 {
+		s.score *= factor
+	}
+}
+
 func (s routeHaulingRiskSummary) applyTo(route *engine.RouteResult) {
 	if route == nil || !s.haveRoute {
 		return
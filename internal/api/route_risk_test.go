@@ -57,3 +57,16 @@ func TestRouteHaulingRiskLimitCapsWork(t *testing.T) {
 		t.Fatalf("limit(200) = %d, want %d", got, maxRouteHaulingRiskEnrich)
 	}
 }
+
+func TestApplyFlipRouteGankRiskUsesShipTank(t *testing.T) {
+	rows := []engine.FlipResult{{UnitsToBuy: 100, BuyPrice: 20_000_000, CargoTrips: 1}} // 2B in one load
+	s := &Server{}
+	s.applyFlipRouteGankRisk(rows, engine.ScanParams{ShipClass: "industrial"}, nil, nil)
+	if !rows[0].GankRisk || rows[0].GankThresholdISK != 1_500_000_000 {
+		t.Fatalf("class tank = %+v", rows[0])
+	}
+	s.applyFlipRouteGankRisk(rows, engine.ScanParams{ShipClass: "industrial", ShipEHP: 80_000}, nil, nil)
+	if rows[0].GankRisk || rows[0].GankThresholdISK != 3_000_000_000 {
+		t.Fatalf("doubled tank = %+v", rows[0])
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"eve-flipper/internal/engine"
+)
+
+// handleRouteSheet renders a saved route as a printable sheet:
+// ?scan_id= (route scan history ID), ?route= (index in that scan, default 0)
+// and ?format=html|md (default html).
+func (s *Server) handleRouteSheet(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	scanID, err := strconv.ParseInt(q.Get("scan_id"), 10, 64)
+	if err != nil || scanID <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid scan_id")
+		return
+	}
+	index := 0
+	if raw := strings.TrimSpace(q.Get("route")); raw != "" {
+		index, err = strconv.Atoi(raw)
+		if err != nil || index < 0 {
+			writeError(w, http.StatusBadRequest, "invalid route index")
+			return
+		}
+	}
+	format := strings.ToLower(strings.TrimSpace(q.Get("format")))
+	if format == "" {
+		format = "html"
+	}
+	if format != "html" && format != "md" && format != "markdown" {
+		writeError(w, http.StatusBadRequest, "format must be html or md")
+		return
+	}
+
+	record := s.db.GetHistoryByID(scanID)
+	if record == nil || record.Tab != "route" {
+		writeError(w, http.StatusNotFound, "route scan not found")
+		return
+	}
+	routes := s.db.GetRouteResults(scanID)
+	if index >= len(routes) {
+		writeError(w, http.StatusNotFound, "route not found")
+		return
+	}
+	route := routes[index]
+	title := fmt.Sprintf("Route from %s", record.System)
+	if len(route.Hops) > 0 && route.Hops[0].SystemName != "" {
+		title = fmt.Sprintf("Route from %s", route.Hops[0].SystemName)
+	}
+
+	if format == "html" {
+		page, err := engine.RenderRouteSheetHTML(title, route)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to render route sheet")
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, page)
+		return
+	}
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	fmt.Fprint(w, engine.RenderRouteSheetMarkdown(title, route))
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"eve-flipper/internal/db"
+	"eve-flipper/internal/esi"
+)
+
+// The accuracy evaluator re-checks the top results of flip scans some hours
+// after they ran and records whether the spread was still there. Scans older
+// than delay+window are skipped: prices that far out say little about the
+// scan itself.
+const (
+	scanAccuracyDelay       = 6 * time.Hour
+	scanAccuracyWindow      = 48 * time.Hour
+	scanAccuracyInterval    = 30 * time.Minute
+	scanAccuracyTopN        = 10
+	scanAccuracyScansPerRun = 5
+)
+
+// scanAccuracyTabs are the history tabs whose results are flips.
+var scanAccuracyTabs = []string{"radius", "region"}
+
+// startScanAccuracyEvaluator runs evaluateScanAccuracy every
+// scanAccuracyInterval. Pending scans live in the database, so a run missed
+// while the app was closed is caught up after startup.
+func (s *Server) startScanAccuracyEvaluator() {
+	s.startBackgroundJob(backgroundJob{
+		name:     "scan_accuracy",
+		interval: scanAccuracyInterval,
+		catchUp:  catchUpRun,
+		run:      s.evaluateScanAccuracy,
+	})
+}
+
+// evaluateScanAccuracy checks up to scanAccuracyScansPerRun due scans.
+func (s *Server) evaluateScanAccuracy(now time.Time) {
+	if s.db == nil || s.esi == nil || !s.isReady() {
+		return
+	}
+	due := now.Add(-scanAccuracyDelay)
+	scans := s.db.PendingAccuracyScans(scanAccuracyTabs, due.Add(-scanAccuracyWindow), due, scanAccuracyScansPerRun)
+	books := make(map[[2]int32][]esi.MarketOrder)
+	for _, scan := range scans {
+		checks := s.checkScanAccuracy(scan, books)
+		if err := s.db.SaveScanAccuracyChecks(scan.ID, scan.Tab, checks); err != nil {
+			log.Printf("[API] Scan accuracy save scan=%d: %v", scan.ID, err)
+			continue
+		}
+		log.Printf("[API] Scan accuracy: scan %d (%s) re-checked %d results", scan.ID, scan.Tab, len(checks))
+	}
+}
+
+// checkScanAccuracy re-prices the top results of scan against the live
+// books. books caches region/type order books across scans of one run.
+func (s *Server) checkScanAccuracy(scan db.ScanRecord, books map[[2]int32][]esi.MarketOrder) []db.ScanAccuracyCheck {
+	results := s.db.GetFlipResults(scan.ID)
+	if len(results) == 0 {
+		results = s.db.GetRegionalDayResults(scan.ID)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].TotalProfit > results[j].TotalProfit })
+	if len(results) > scanAccuracyTopN {
+		results = results[:scanAccuracyTopN]
+	}
+
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	if sdeData == nil {
+		return nil
+	}
+	book := func(systemID, typeID int32) []esi.MarketOrder {
+		sys, ok := sdeData.Systems[systemID]
+		if !ok {
+			return nil
+		}
+		key := [2]int32{sys.RegionID, typeID}
+		if orders, ok := books[key]; ok {
+			return orders
+		}
+		orders, err := s.esi.FetchRegionOrdersByType(sys.RegionID, typeID)
+		if err != nil {
+			log.Printf("[API] Scan accuracy orders region=%d type=%d: %v", sys.RegionID, typeID, err)
+		}
+		books[key] = orders
+		return orders
+	}
+
+	checks := make([]db.ScanAccuracyCheck, 0, len(results))
+	for _, r := range results {
+		if r.TypeID <= 0 || r.BuyPrice <= 0 || r.SellPrice <= 0 {
+			continue
+		}
+		nowBuy := bestAskInSystem(book(r.BuySystemID, r.TypeID), r.BuySystemID)
+		nowSell := bestBidInSystem(book(r.SellSystemID, r.TypeID), r.SellSystemID)
+		held, retained := spreadRetention(r.BuyPrice, r.SellPrice, nowBuy, nowSell)
+		checks = append(checks, db.ScanAccuracyCheck{
+			TypeID:         r.TypeID,
+			BuySystemID:    r.BuySystemID,
+			SellSystemID:   r.SellSystemID,
+			ScannedAt:      scan.Timestamp,
+			ScanBuyPrice:   r.BuyPrice,
+			ScanSellPrice:  r.SellPrice,
+			NowBuyPrice:    nowBuy,
+			NowSellPrice:   nowSell,
+			SpreadHeld:     held,
+			SpreadRetained: retained,
+		})
+	}
+	return checks
+}
+
+// spreadRetention reports whether a buy→sell spread still exists at the
+// current prices and what fraction of the scanned spread remains (capped at
+// 1). Missing current prices count as a vanished spread.
+func spreadRetention(scanBuy, scanSell, nowBuy, nowSell float64) (bool, float64) {
+	if nowBuy <= 0 || nowSell <= 0 || nowSell <= nowBuy {
+		return false, 0
+	}
+	scanSpread := scanSell - scanBuy
+	if scanSpread <= 0 {
+		return true, 1
+	}
+	return true, min((nowSell-nowBuy)/scanSpread, 1)
+}
+
+func bestAskInSystem(orders []esi.MarketOrder, systemID int32) float64 {
+	best := 0.0
+	for _, o := range orders {
+		if !o.IsBuyOrder && o.SystemID == systemID && (best == 0 || o.Price < best) {
+			best = o.Price
+		}
+	}
+	return best
+}
+
+func bestBidInSystem(orders []esi.MarketOrder, systemID int32) float64 {
+	best := 0.0
+	for _, o := range orders {
+		if o.IsBuyOrder && o.SystemID == systemID && o.Price > best {
+			best = o.Price
+		}
+	}
+	return best
+}
+
+// handleGetScanAccuracy returns per-mode accuracy of past scans over
+// ?days= (default 30).
+func (s *Server) handleGetScanAccuracy(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	if days <= 0 || days > 365 {
+		days = 30
+	}
+	writeJSON(w, map[string]interface{}{
+		"days":        days,
+		"delay_hours": scanAccuracyDelay.Hours(),
+		"top_n":       scanAccuracyTopN,
+		"modes":       s.db.GetScanAccuracy(days),
+	})
+}
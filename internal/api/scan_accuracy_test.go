@@ -0,0 +1,25 @@
+package api
+
+import "testing"
+
+func TestSpreadRetention(t *testing.T) {
+	cases := []struct {
+		name              string
+		scanBuy, scanSell float64
+		nowBuy, nowSell   float64
+		wantHeld          bool
+		wantRetained      float64
+	}{
+		{"unchanged", 100, 120, 100, 120, true, 1},
+		{"narrowed", 100, 120, 105, 120, true, 0.75},
+		{"widened caps at one", 100, 120, 90, 130, true, 1},
+		{"inverted", 100, 120, 121, 120, false, 0},
+		{"no bid left", 100, 120, 100, 0, false, 0},
+	}
+	for _, tc := range cases {
+		held, retained := spreadRetention(tc.scanBuy, tc.scanSell, tc.nowBuy, tc.nowSell)
+		if held != tc.wantHeld || retained != tc.wantRetained {
+			t.Errorf("%s: spreadRetention = %v, %v; want %v, %v", tc.name, held, retained, tc.wantHeld, tc.wantRetained)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"eve-flipper/internal/engine"
+)
+
+// handleOptimizeCargo packs the most profitable combination of flips from a
+// stored radius/region scan into one cargo hold and budget, and returns the
+// basket with its pickup/drop-off route. scan_id defaults to the latest scan.
+func (s *Server) handleOptimizeCargo(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ScanID           int64   `json:"scan_id"`
+		SystemName       string  `json:"system_name"`
+		CargoCapacity    float64 `json:"cargo_capacity"`
+		Budget           float64 `json:"budget"`
+		MinRouteSecurity float64 `json:"min_route_security"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	if !s.isReady() {
+		writeError(w, 503, "SDE not loaded yet")
+		return
+	}
+	if req.CargoCapacity <= 0 {
+		writeError(w, 400, "cargo_capacity is required")
+		return
+	}
+
+	if req.ScanID == 0 {
+		req.ScanID = s.db.LatestHistoryIDByTab("radius", "region")
+	}
+	record := s.db.GetHistoryByID(req.ScanID)
+	if record == nil || (record.Tab != "radius" && record.Tab != "region") {
+		writeError(w, 404, "no radius or region scan found")
+		return
+	}
+	var flips []engine.FlipResult
+	if record.Tab == "region" {
+		flips = s.db.GetRegionalDayResults(record.ID)
+	}
+	if len(flips) == 0 {
+		flips = s.db.GetFlipResults(record.ID)
+	}
+	flips = filterFlipResultsMarketDisabled(flips)
+	if len(flips) == 0 {
+		writeError(w, 404, "scan has no results")
+		return
+	}
+
+	req.SystemName = strings.TrimSpace(req.SystemName)
+	if req.SystemName == "" {
+		req.SystemName = record.System
+	}
+
+	s.mu.RLock()
+	scanner := s.scanner
+	s.mu.RUnlock()
+
+	basket, err := scanner.OptimizeCargo(engine.CargoOptimizeParams{
+		StartSystemName:  req.SystemName,
+		CargoCapacity:    req.CargoCapacity,
+		Budget:           req.Budget,
+		MinRouteSecurity: req.MinRouteSecurity,
+		Flips:            flips,
+		RoutePreferences: s.routePreferences(userIDFromRequest(r)),
+	})
+	if err != nil {
+		writeError(w, 400, err.Error())
+		return
+	}
+	log.Printf("[API] OptimizeCargo: scan=%d candidates=%d items=%d profit=%.0f cargo=%.0f/%.0f m3 jumps=%d",
+		record.ID, basket.Candidates, len(basket.Items), basket.TotalProfit, basket.CargoM3, req.CargoCapacity, basket.Route.TotalJumps)
+	writeJSON(w, map[string]interface{}{
+		"scan_id": record.ID,
+		"basket":  basket,
+	})
+}
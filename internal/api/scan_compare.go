@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"eve-flipper/internal/engine"
+)
+
+type scanCompareRequest struct {
+	A scanRequest `json:"a"`
+	B scanRequest `json:"b"`
+}
+
+// handleScanCompare runs the same radius scan with two parameter presets
+// (e.g. 5 vs 10 jumps) one after the other and streams a comparison of
+// what each finds, to show whether the longer trips of the wider preset pay.
+func (s *Server) handleScanCompare(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+
+	var req scanCompareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	paramsA, err := s.radiusScanParams(userID, req.A)
+	if err != nil {
+		writeError(w, 400, "preset a: "+err.Error())
+		return
+	}
+	paramsB, err := s.radiusScanParams(userID, req.B)
+	if err != nil {
+		writeError(w, 400, "preset b: "+err.Error())
+		return
+	}
+	if paramsA.CurrentSystemID != paramsB.CurrentSystemID {
+		writeError(w, 400, "both presets must scan from the same system")
+		return
+	}
+
+	ctx, endRun := s.beginScanRun(w, r)
+	defer endRun()
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, 500, "streaming not supported")
+		return
+	}
+	send := func(v interface{}) {
+		line, _ := json.Marshal(v)
+		fmt.Fprintf(w, "%s\n", line)
+		flusher.Flush()
+	}
+
+	s.mu.RLock()
+	scanner := s.scanner
+	s.mu.RUnlock()
+
+	userCfg := s.loadConfigForUser(userID)
+	startTime := time.Now()
+	run := func(label string, req scanRequest, params engine.ScanParams) ([]engine.FlipResult, bool) {
+		progress := func(msg string) {
+			send(map[string]string{"type": "progress", "message": label + ": " + msg})
+		}
+		results, scanErr := scanner.ScanWithContext(ctx, params, progress)
+		if scanErr != nil {
+			if isScanCanceled(scanErr) {
+				log.Printf("[API] Scan compare canceled: %v", scanErr)
+			} else {
+				log.Printf("[API] Scan compare error (%s): %v", label, scanErr)
+				send(map[string]string{"type": "error", "message": label + ": " + scanErr.Error()})
+			}
+			return nil, false
+		}
+		if req.IncludeStructures {
+			results = s.enrichStructureNames(userID, results)
+		} else {
+			results = filterFlipResultsExcludeStructures(results)
+		}
+		results = filterFlipResultsMarketDisabled(results)
+		s.annotateReferenceStation(userCfg, results)
+		return results, true
+	}
+	resultsA, ok := run("Preset A", req.A, paramsA)
+	if !ok {
+		return
+	}
+	resultsB, ok := run("Preset B", req.B, paramsB)
+	if !ok {
+		return
+	}
+	log.Printf("[API] Scan compare complete: %d vs %d results in %dms",
+		len(resultsA), len(resultsB), time.Since(startTime).Milliseconds())
+
+	send(map[string]interface{}{
+		"type": "result",
+		"data": engine.CompareScans(resultsA, resultsB),
+	})
+}
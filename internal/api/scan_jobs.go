@@ -0,0 +1,447 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scan jobs let the streaming scan endpoints run in the background: a POST
+// with ?async=1 returns a job ID immediately, and the job's NDJSON events
+// (progress, result, error) are kept so GET /api/scan/jobs/{id} can poll or
+// re-stream them, e.g. after a page reload.
+const (
+	scanJobConcurrency = 3
+	scanJobRetention   = 30 * time.Minute
+	scanJobsPerUser    = 20
+	scanJobMaxBody     = 1 << 20
+)
+
+const (
+	scanJobQueued   = "queued"
+	scanJobRunning  = "running"
+	scanJobDone     = "done"
+	scanJobFailed   = "failed"
+	scanJobCanceled = "canceled"
+)
+
+type scanJob struct {
+	id     string
+	userID string
+	kind   string
+	cancel context.CancelFunc
+
+	mu         sync.Mutex
+	status     string
+	createdAt  time.Time
+	startedAt  time.Time
+	finishedAt time.Time
+	progress   string
+	errMsg     string
+	hasResult  bool
+	events     []json.RawMessage
+	changed    chan struct{} // closed and replaced on every update
+}
+
+// scanJobView is the JSON shape of a job. Events holds NDJSON events from the
+// requested offset; Next is the offset to poll from next time.
+type scanJobView struct {
+	ID         string            `json:"id"`
+	Kind       string            `json:"kind"`
+	Status     string            `json:"status"`
+	CreatedAt  string            `json:"created_at"`
+	StartedAt  string            `json:"started_at,omitempty"`
+	FinishedAt string            `json:"finished_at,omitempty"`
+	Progress   string            `json:"progress,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	HasResult  bool              `json:"has_result"`
+	Events     []json.RawMessage `json:"events,omitempty"`
+	Next       int               `json:"next"`
+}
+
+func (j *scanJob) terminal() bool {
+	return j.status == scanJobDone || j.status == scanJobFailed || j.status == scanJobCanceled
+}
+
+// notify wakes stream readers; callers hold j.mu.
+func (j *scanJob) notify() {
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+func (j *scanJob) setStatus(status string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.terminal() {
+		return
+	}
+	j.status = status
+	switch status {
+	case scanJobRunning:
+		j.startedAt = time.Now().UTC()
+	case scanJobDone, scanJobFailed, scanJobCanceled:
+		j.finishedAt = time.Now().UTC()
+	}
+	j.notify()
+}
+
+func (j *scanJob) appendEvent(line []byte) {
+	var ev struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(line, &ev); err != nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	switch ev.Type {
+	case "progress":
+		j.progress = ev.Message
+	case "result":
+		j.hasResult = true
+	case "error":
+		j.errMsg = ev.Message
+	case "":
+		// writeError body from a handler that failed before streaming.
+		if ev.Error == "" {
+			return
+		}
+		j.errMsg = ev.Error
+		line, _ = json.Marshal(map[string]string{"type": "error", "message": ev.Error})
+	}
+	j.events = append(j.events, json.RawMessage(append([]byte(nil), line...)))
+	j.notify()
+}
+
+func (j *scanJob) view(since int) scanJobView {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	v := scanJobView{
+		ID:        j.id,
+		Kind:      j.kind,
+		Status:    j.status,
+		CreatedAt: j.createdAt.Format(time.RFC3339),
+		Progress:  j.progress,
+		Error:     j.errMsg,
+		HasResult: j.hasResult,
+		Next:      len(j.events),
+	}
+	if !j.startedAt.IsZero() {
+		v.StartedAt = j.startedAt.Format(time.RFC3339)
+	}
+	if !j.finishedAt.IsZero() {
+		v.FinishedAt = j.finishedAt.Format(time.RFC3339)
+	}
+	if since >= 0 && since < len(j.events) {
+		v.Events = append([]json.RawMessage(nil), j.events[since:]...)
+	}
+	return v
+}
+
+// scanJobWriter is the http.ResponseWriter a scan handler writes to when it
+// runs as a job. Complete NDJSON lines become job events.
+type scanJobWriter struct {
+	job    *scanJob
+	header http.Header
+	buf    bytes.Buffer
+}
+
+func (w *scanJobWriter) Header() http.Header { return w.header }
+func (w *scanJobWriter) WriteHeader(int)     {}
+func (w *scanJobWriter) Flush()              {}
+
+func (w *scanJobWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimSpace(w.buf.Next(i + 1))
+		if len(line) > 0 {
+			w.job.appendEvent(line)
+		}
+	}
+	return len(p), nil
+}
+
+// scanJobManager queues scan jobs and runs at most scanJobConcurrency at once.
+// While cooldown reports an ESI cool-down, queued jobs wait it out instead
+// of starting into a 420/429 pause.
+type scanJobManager struct {
+	mu       sync.Mutex
+	jobs     map[string]*scanJob
+	slots    chan struct{}
+	cooldown func() time.Duration
+}
+
+// add registers a queued job, or fails when the user already has
+// scanJobsPerUser jobs queued or running.
+func (m *scanJobManager) add(kind, userID string, cancel context.CancelFunc) (*scanJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.jobs == nil {
+		m.jobs = make(map[string]*scanJob)
+		m.slots = make(chan struct{}, scanJobConcurrency)
+	}
+	if unfinished := m.pruneLocked(userID); unfinished >= scanJobsPerUser {
+		return nil, fmt.Errorf("too many scan jobs in progress (max %d); wait for one to finish or cancel it", scanJobsPerUser)
+	}
+	job := &scanJob{
+		id:        generateScanRunID(),
+		userID:    userID,
+		kind:      kind,
+		cancel:    cancel,
+		status:    scanJobQueued,
+		createdAt: time.Now().UTC(),
+		changed:   make(chan struct{}),
+	}
+	m.jobs[job.id] = job
+	return job, nil
+}
+
+// pruneLocked drops finished jobs past retention and keeps each user's job
+// count bounded, returning how many of the user's jobs are still queued or
+// running. Callers hold m.mu.
+func (m *scanJobManager) pruneLocked(userID string) int {
+	cutoff := time.Now().Add(-scanJobRetention)
+	var own []*scanJob
+	unfinished := 0
+	for id, job := range m.jobs {
+		job.mu.Lock()
+		done := job.terminal()
+		expired := done && job.finishedAt.Before(cutoff)
+		job.mu.Unlock()
+		if expired {
+			delete(m.jobs, id)
+			continue
+		}
+		if job.userID == userID {
+			own = append(own, job)
+			if !done {
+				unfinished++
+			}
+		}
+	}
+	if len(own) < scanJobsPerUser {
+		return unfinished
+	}
+	sort.Slice(own, func(i, j int) bool { return own[i].createdAt.Before(own[j].createdAt) })
+	for _, job := range own[:len(own)-scanJobsPerUser+1] {
+		job.mu.Lock()
+		done := job.terminal()
+		job.mu.Unlock()
+		if done {
+			delete(m.jobs, job.id)
+		}
+	}
+	return unfinished
+}
+
+func (m *scanJobManager) get(id, userID string) *scanJob {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if job := m.jobs[id]; job != nil && job.userID == userID {
+		return job
+	}
+	return nil
+}
+
+func (m *scanJobManager) list(userID string) []scanJobView {
+	m.mu.Lock()
+	var own []*scanJob
+	for _, job := range m.jobs {
+		if job.userID == userID {
+			own = append(own, job)
+		}
+	}
+	m.mu.Unlock()
+	sort.Slice(own, func(i, j int) bool { return own[i].createdAt.After(own[j].createdAt) })
+	out := make([]scanJobView, 0, len(own))
+	for _, job := range own {
+		out = append(out, job.view(-1))
+	}
+	return out
+}
+
+// cancel stops a queued or running job owned by userID.
+func (m *scanJobManager) cancel(id, userID string) bool {
+	job := m.get(id, userID)
+	if job == nil {
+		return false
+	}
+	job.cancel()
+	return true
+}
+
+// waitCooldown blocks while ESI is cooling down, reporting the wait as job
+// progress. It returns false when ctx is done first.
+func (m *scanJobManager) waitCooldown(ctx context.Context, job *scanJob) bool {
+	if m.cooldown == nil {
+		return true
+	}
+	for {
+		d := m.cooldown()
+		if d <= 0 {
+			return true
+		}
+		line, _ := json.Marshal(map[string]string{
+			"type":    "progress",
+			"message": fmt.Sprintf("Waiting %ds for ESI cool-down...", int(d.Seconds()+0.5)),
+		})
+		job.appendEvent(line)
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		}
+	}
+}
+
+// run waits for a free slot and any ESI cool-down, then calls fn and records
+// the outcome.
+func (m *scanJobManager) run(ctx context.Context, job *scanJob, fn func()) {
+	select {
+	case m.slots <- struct{}{}:
+	case <-ctx.Done():
+		job.setStatus(scanJobCanceled)
+		return
+	}
+	defer func() { <-m.slots }()
+	defer job.cancel()
+	if !m.waitCooldown(ctx, job) {
+		job.setStatus(scanJobCanceled)
+		return
+	}
+
+	job.setStatus(scanJobRunning)
+	fn()
+
+	job.mu.Lock()
+	hasResult, errMsg := job.hasResult, job.errMsg
+	job.mu.Unlock()
+	switch {
+	case ctx.Err() != nil:
+		job.setStatus(scanJobCanceled)
+	case errMsg != "" && !hasResult:
+		job.setStatus(scanJobFailed)
+	default:
+		job.setStatus(scanJobDone)
+	}
+}
+
+// scanJobHandler runs a streaming scan handler as a background job when the
+// request has ?async=1, responding 202 with the job. Without it the handler
+// streams as before.
+func (s *Server) scanJobHandler(kind string, h http.HandlerFunc) http.HandlerFunc {
+	h = timedScanHandler(kind, h)
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch strings.ToLower(strings.TrimSpace(r.URL.Query().Get("async"))) {
+		case "1", "true", "yes":
+		default:
+			h(w, r)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, scanJobMaxBody))
+		if err != nil {
+			writeError(w, 400, "invalid body")
+			return
+		}
+
+		// The job outlives the request but keeps its values (user ID).
+		ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+		job, err := s.scanJobs.add(kind, userIDFromRequest(r), cancel)
+		if err != nil {
+			cancel()
+			writeError(w, http.StatusTooManyRequests, err.Error())
+			return
+		}
+		req := r.Clone(ctx)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.Header.Set(scanRunIDHeader, job.id)
+
+		// A rate limited scan keeps its concurrency slot until the job ends.
+		slot := scanSlotFromContext(r.Context())
+		if slot != nil {
+			slot.detached = true
+		}
+		go func() {
+			if slot != nil {
+				defer slot.release()
+			}
+			s.scanJobs.run(ctx, job, func() {
+				h(&scanJobWriter{job: job, header: make(http.Header)}, req)
+			})
+		}()
+		log.Printf("[API] Scan job %s (%s) queued", job.id, kind)
+		writeJSONStatus(w, http.StatusAccepted, job.view(-1))
+	}
+}
+
+// handleListScanJobs lists the caller's recent scan jobs, newest first.
+func (s *Server) handleListScanJobs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.scanJobs.list(userIDFromRequest(r)))
+}
+
+// handleGetScanJob returns a job with its events from ?since= (default 0).
+// With ?stream=1 it streams those events as NDJSON and follows the job until
+// it finishes.
+func (s *Server) handleGetScanJob(w http.ResponseWriter, r *http.Request) {
+	job := s.scanJobs.get(r.PathValue("id"), userIDFromRequest(r))
+	if job == nil {
+		writeError(w, 404, "scan job not found")
+		return
+	}
+	since, _ := strconv.Atoi(r.URL.Query().Get("since"))
+	if since < 0 {
+		since = 0
+	}
+	if r.URL.Query().Get("stream") != "1" {
+		writeJSON(w, job.view(since))
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, 500, "streaming not supported")
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	for {
+		job.mu.Lock()
+		events := append([]json.RawMessage(nil), job.events[min(since, len(job.events)):]...)
+		done := job.terminal()
+		changed := job.changed
+		job.mu.Unlock()
+
+		for _, ev := range events {
+			if _, err := fmt.Fprintf(w, "%s\n", ev); err != nil {
+				return
+			}
+		}
+		since += len(events)
+		flusher.Flush()
+		if done {
+			return
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func waitScanJob(t *testing.T, s *Server, id, userID string) scanJobView {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if job := s.scanJobs.get(id, userID); job != nil {
+			if v := job.view(0); v.Status != scanJobQueued && v.Status != scanJobRunning {
+				return v
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("scan job %s did not finish", id)
+	return scanJobView{}
+}
+
+func TestScanJobHandler_AsyncRunsHandlerAndKeepsEvents(t *testing.T) {
+	s := &Server{}
+	h := s.scanJobHandler("radius", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, "{\"type\":\"progress\",\"message\":\"got %s\"}\n", body)
+		fmt.Fprintf(w, "{\"type\":\"result\",\"count\":1}\n")
+	})
+
+	req := scanRunRequest(http.MethodPost, "/api/scan?async=1", "alice")
+	req.Body = io.NopCloser(strings.NewReader("x"))
+	rec := httptest.NewRecorder()
+	h(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202", rec.Code)
+	}
+	var queued scanJobView
+	if err := json.NewDecoder(rec.Body).Decode(&queued); err != nil || queued.ID == "" {
+		t.Fatalf("decode job: %v (%+v)", err, queued)
+	}
+
+	v := waitScanJob(t, s, queued.ID, "alice")
+	if v.Status != scanJobDone || !v.HasResult || v.Progress != "got x" {
+		t.Fatalf("job = %+v", v)
+	}
+	if len(v.Events) != 2 || v.Next != 2 {
+		t.Fatalf("events = %d next = %d, want 2", len(v.Events), v.Next)
+	}
+	if s.scanJobs.get(queued.ID, "mallory") != nil {
+		t.Fatal("job visible to another user")
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/scan/jobs/{id}", s.handleGetScanJob)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, scanRunRequest(http.MethodGet, "/api/scan/jobs/"+queued.ID+"?stream=1&since=1", "alice"))
+	if got := strings.TrimSpace(rec.Body.String()); got != `{"type":"result","count":1}` {
+		t.Fatalf("stream = %q", got)
+	}
+}
+
+func TestScanJobHandler_CancelQueuedAndFailed(t *testing.T) {
+	s := &Server{}
+	release := make(chan struct{})
+	block := s.scanJobHandler("region", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	var ids []string
+	for i := 0; i < scanJobConcurrency+1; i++ {
+		rec := httptest.NewRecorder()
+		block(rec, scanRunRequest(http.MethodPost, "/api/scan/multi-region?async=1", "alice"))
+		var v scanJobView
+		_ = json.NewDecoder(rec.Body).Decode(&v)
+		ids = append(ids, v.ID)
+	}
+	last := ids[len(ids)-1]
+	if !s.scanJobs.cancel(last, "alice") {
+		t.Fatal("cancel queued job failed")
+	}
+	if v := waitScanJob(t, s, last, "alice"); v.Status != scanJobCanceled {
+		t.Fatalf("queued job status = %s, want canceled", v.Status)
+	}
+	close(release)
+	for _, id := range ids[:len(ids)-1] {
+		if v := waitScanJob(t, s, id, "alice"); v.Status != scanJobDone {
+			t.Fatalf("job %s status = %s, want done", id, v.Status)
+		}
+	}
+
+	fail := s.scanJobHandler("contracts", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, 400, "invalid json")
+	})
+	rec := httptest.NewRecorder()
+	fail(rec, scanRunRequest(http.MethodPost, "/api/scan/contracts?async=1", "alice"))
+	var v scanJobView
+	_ = json.NewDecoder(rec.Body).Decode(&v)
+	if v = waitScanJob(t, s, v.ID, "alice"); v.Status != scanJobFailed || v.Error != "invalid json" {
+		t.Fatalf("failed job = %+v", v)
+	}
+}
+
+func TestScanJobHandler_WaitsOutESICooldown(t *testing.T) {
+	s := &Server{}
+	var cooling atomic.Bool
+	cooling.Store(true)
+	s.scanJobs.cooldown = func() time.Duration {
+		if cooling.Swap(false) {
+			return 20 * time.Millisecond
+		}
+		return 0
+	}
+	h := s.scanJobHandler("radius", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "{\"type\":\"result\",\"count\":0}\n")
+	})
+	rec := httptest.NewRecorder()
+	h(rec, scanRunRequest(http.MethodPost, "/api/scan?async=1", "alice"))
+	var queued scanJobView
+	_ = json.NewDecoder(rec.Body).Decode(&queued)
+
+	v := waitScanJob(t, s, queued.ID, "alice")
+	if v.Status != scanJobDone || len(v.Events) != 2 {
+		t.Fatalf("job = %+v", v)
+	}
+	if !strings.Contains(string(v.Events[0]), "ESI cool-down") {
+		t.Fatalf("first event = %s, want cool-down progress", v.Events[0])
+	}
+}
+
+func TestScanJobHandler_RejectsBeyondUnfinishedLimit(t *testing.T) {
+	s := &Server{}
+	release := make(chan struct{})
+	defer close(release)
+	block := s.scanJobHandler("radius", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	for i := 0; i < scanJobsPerUser; i++ {
+		rec := httptest.NewRecorder()
+		block(rec, scanRunRequest(http.MethodPost, "/api/scan?async=1", "alice"))
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("job %d status = %d, want 202", i, rec.Code)
+		}
+	}
+	rec := httptest.NewRecorder()
+	block(rec, scanRunRequest(http.MethodPost, "/api/scan?async=1", "alice"))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("job past the limit status = %d, want 429", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	block(rec, scanRunRequest(http.MethodPost, "/api/scan?async=1", "bob"))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("other user status = %d, want 202", rec.Code)
+	}
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"sync"
+)
+
+// scanRunIDHeader carries the ID of a streaming scan. Clients may send their
+// own ID so they can cancel before the first progress line arrives; otherwise
+// one is generated. The ID is echoed in the response either way.
+const scanRunIDHeader = "X-Scan-Run-ID"
+
+var scanRunIDRe = regexp.MustCompile(`^[A-Za-z0-9_-]{8,64}$`)
+
+type scanRun struct {
+	userID string
+	cancel context.CancelFunc
+}
+
+// scanRunRegistry tracks cancel functions of in-flight scans by run ID.
+type scanRunRegistry struct {
+	mu   sync.Mutex
+	runs map[string]scanRun
+}
+
+func generateScanRunID() string {
+	var raw [12]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw[:])
+}
+
+// add registers cancel under id, or under a fresh ID when id is invalid or
+// already taken. Returns the ID used.
+func (reg *scanRunRegistry) add(id, userID string, cancel context.CancelFunc) string {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if reg.runs == nil {
+		reg.runs = make(map[string]scanRun)
+	}
+	if _, taken := reg.runs[id]; taken || !scanRunIDRe.MatchString(id) {
+		id = generateScanRunID()
+	}
+	reg.runs[id] = scanRun{userID: userID, cancel: cancel}
+	return id
+}
+
+func (reg *scanRunRegistry) remove(id string) {
+	reg.mu.Lock()
+	delete(reg.runs, id)
+	reg.mu.Unlock()
+}
+
+// cancel stops the run if it belongs to userID.
+func (reg *scanRunRegistry) cancel(id, userID string) bool {
+	reg.mu.Lock()
+	run, ok := reg.runs[id]
+	if ok && run.userID == userID {
+		delete(reg.runs, id)
+	}
+	reg.mu.Unlock()
+	if !ok || run.userID != userID {
+		return false
+	}
+	run.cancel()
+	return true
+}
+
+// beginScanRun returns a context for a streaming scan that is canceled when
+// the client disconnects or calls DELETE /api/scan/{id}. It sets the run ID
+// header, so call it before writing the response. Call end when done.
+func (s *Server) beginScanRun(w http.ResponseWriter, r *http.Request) (ctx context.Context, end func()) {
+	ctx, cancel := context.WithCancel(r.Context())
+	id := s.scanRuns.add(r.Header.Get(scanRunIDHeader), userIDFromRequest(r), cancel)
+	w.Header().Set(scanRunIDHeader, id)
+	return ctx, func() {
+		s.scanRuns.remove(id)
+		cancel()
+	}
+}
+
+// handleCancelScan aborts an in-flight scan or scan job started by the same user.
+func (s *Server) handleCancelScan(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	userID := userIDFromRequest(r)
+	if !s.scanJobs.cancel(id, userID) && !s.scanRuns.cancel(id, userID) {
+		writeError(w, 404, "scan not found")
+		return
+	}
+	log.Printf("[API] Scan %s canceled by client", id)
+	writeJSON(w, map[string]string{"status": "canceled", "id": id})
+}
+
+// isScanCanceled reports whether err is the result of a canceled scan run.
+func isScanCanceled(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func scanRunRequest(method, target, userID string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	return req.WithContext(context.WithValue(req.Context(), userIDContextKey, userID))
+}
+
+func TestCancelScan_StopsRunOfSameUserOnly(t *testing.T) {
+	s := &Server{}
+	start := scanRunRequest(http.MethodPost, "/api/scan", "alice")
+	start.Header.Set(scanRunIDHeader, "client-run-0001")
+	rec := httptest.NewRecorder()
+	ctx, end := s.beginScanRun(rec, start)
+	defer end()
+
+	id := rec.Header().Get(scanRunIDHeader)
+	if id != "client-run-0001" {
+		t.Fatalf("run id = %q, want client-supplied id", id)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /api/scan/{id}", s.handleCancelScan)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, scanRunRequest(http.MethodDelete, "/api/scan/"+id, "mallory"))
+	if rec.Code != 404 {
+		t.Fatalf("other user cancel: status %d, want 404", rec.Code)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("run canceled by another user")
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, scanRunRequest(http.MethodDelete, "/api/scan/"+id, "alice"))
+	if rec.Code != 200 {
+		t.Fatalf("cancel: status %d, want 200", rec.Code)
+	}
+	if ctx.Err() == nil {
+		t.Fatal("run context not canceled")
+	}
+}
+
+func TestBeginScanRun_GeneratesIDForInvalidOrDuplicate(t *testing.T) {
+	s := &Server{}
+	req := scanRunRequest(http.MethodPost, "/api/scan", "alice")
+	req.Header.Set(scanRunIDHeader, "bad id!")
+	rec := httptest.NewRecorder()
+	_, end := s.beginScanRun(rec, req)
+	defer end()
+	first := rec.Header().Get(scanRunIDHeader)
+	if first == "" || first == "bad id!" {
+		t.Fatalf("run id = %q, want generated id", first)
+	}
+
+	req = scanRunRequest(http.MethodPost, "/api/scan", "alice")
+	req.Header.Set(scanRunIDHeader, first)
+	rec = httptest.NewRecorder()
+	_, end2 := s.beginScanRun(rec, req)
+	defer end2()
+	if got := rec.Header().Get(scanRunIDHeader); got == first {
+		t.Fatal("duplicate run id reused")
+	}
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"eve-flipper/internal/sde"
+)
+
+// sdeLoadTracker holds the progress of the startup SDE load so the UI can
+// show a loading screen while the SDE is downloaded and parsed on first run.
+type sdeLoadTracker struct {
+	mu       sync.Mutex
+	started  time.Time
+	progress sde.LoadProgress
+	errMsg   string
+	changed  chan struct{} // closed and replaced on every update
+}
+
+// sdeLoadView is the JSON shape of the SDE load progress. ETASec is omitted
+// until enough progress has been made to estimate it.
+type sdeLoadView struct {
+	Stage        string   `json:"stage"`
+	Percent      float64  `json:"percent"`
+	StagePercent float64  `json:"stage_percent"`
+	BytesDone    int64    `json:"bytes_done,omitempty"`
+	BytesTotal   int64    `json:"bytes_total,omitempty"`
+	ElapsedSec   float64  `json:"elapsed_sec"`
+	ETASec       *float64 `json:"eta_sec,omitempty"`
+	Done         bool     `json:"done"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// notify wakes stream readers; callers hold t.mu.
+func (t *sdeLoadTracker) notify() {
+	if t.changed != nil {
+		close(t.changed)
+	}
+	t.changed = make(chan struct{})
+}
+
+func (t *sdeLoadTracker) update(p sde.LoadProgress) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.started.IsZero() {
+		t.started = time.Now()
+	}
+	t.progress = p
+	t.errMsg = ""
+	t.notify()
+}
+
+func (t *sdeLoadTracker) fail(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.errMsg = err.Error()
+	t.notify()
+}
+
+// view returns the current progress and a channel closed on the next update.
+func (t *sdeLoadTracker) view(now time.Time) (sdeLoadView, <-chan struct{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.changed == nil {
+		t.changed = make(chan struct{})
+	}
+	v := sdeLoadView{
+		Stage:        t.progress.Stage,
+		Percent:      t.progress.Percent,
+		StagePercent: t.progress.StagePercent,
+		BytesDone:    t.progress.BytesDone,
+		BytesTotal:   t.progress.BytesTotal,
+		Error:        t.errMsg,
+	}
+	if v.Stage == "" {
+		v.Stage = "pending"
+	}
+	if !t.started.IsZero() {
+		v.ElapsedSec = now.Sub(t.started).Seconds()
+	}
+	if eta, ok := sdeLoadETA(v.ElapsedSec, v.Percent); ok && v.Error == "" {
+		v.ETASec = &eta
+	}
+	return v, t.changed
+}
+
+// sdeLoadETA extrapolates the remaining time from the overall percentage,
+// once at least 1% and 2 seconds have passed.
+func sdeLoadETA(elapsedSec, percent float64) (float64, bool) {
+	if percent < 1 || percent >= 100 || elapsedSec < 2 {
+		return 0, false
+	}
+	return elapsedSec * (100 - percent) / percent, true
+}
+
+// SetSDELoadProgress records startup SDE load progress (see sde.LoadWithProgress).
+func (s *Server) SetSDELoadProgress(p sde.LoadProgress) {
+	s.sdeLoad.update(p)
+}
+
+// SetSDELoadError records that the startup SDE load failed.
+func (s *Server) SetSDELoadError(err error) {
+	s.sdeLoad.fail(err)
+}
+
+// sdeLoadStatus is the current SDE load progress. Done means the scanner is
+// ready, which is shortly after parsing finishes.
+func (s *Server) sdeLoadStatus() sdeLoadView {
+	v, _ := s.sdeLoad.view(time.Now())
+	v.Done = s.isReady()
+	if v.Done {
+		v.Stage, v.Percent, v.StagePercent, v.ETASec = "done", 100, 100, nil
+	}
+	return v
+}
+
+// handleSDELoadStatus returns the SDE load progress. With ?stream=1 it
+// streams a progress event per update as NDJSON until loading finishes or
+// fails.
+func (s *Server) handleSDELoadStatus(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("stream") != "1" {
+		writeJSON(w, s.sdeLoadStatus())
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, 500, "streaming not supported")
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Heartbeat so elapsed/ETA keep moving during long stages.
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		_, changed := s.sdeLoad.view(time.Now())
+		v := s.sdeLoadStatus()
+		line, _ := json.Marshal(map[string]interface{}{"type": "sde_progress", "progress": v})
+		if _, err := fmt.Fprintf(w, "%s\n", line); err != nil {
+			return
+		}
+		flusher.Flush()
+		if v.Done || v.Error != "" {
+			return
+		}
+		select {
+		case <-changed:
+		case <-ticker.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
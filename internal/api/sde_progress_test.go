@@ -0,0 +1,40 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"eve-flipper/internal/sde"
+)
+
+func TestSDELoadTrackerView(t *testing.T) {
+	var tracker sdeLoadTracker
+	now := time.Now()
+
+	v, changed := tracker.view(now)
+	if v.Stage != "pending" || v.ETASec != nil {
+		t.Fatalf("initial view = %+v", v)
+	}
+
+	tracker.update(sde.LoadProgress{Stage: "download", Percent: 25, StagePercent: 45})
+	select {
+	case <-changed:
+	default:
+		t.Fatalf("update did not wake watchers")
+	}
+	tracker.mu.Lock()
+	tracker.started = now.Add(-30 * time.Second)
+	tracker.mu.Unlock()
+
+	v, _ = tracker.view(now)
+	if v.Stage != "download" || v.ETASec == nil || *v.ETASec < 89 || *v.ETASec > 91 {
+		t.Fatalf("download view = %+v, want ETA ~90s", v)
+	}
+
+	tracker.fail(errors.New("download SDE: HTTP 503"))
+	v, _ = tracker.view(now)
+	if v.Error == "" || v.ETASec != nil {
+		t.Fatalf("failed view = %+v", v)
+	}
+}
@@ -0,0 +1,249 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/pricing"
+	"eve-flipper/internal/sde"
+)
+
+const (
+	sellAdvisorDefaultLimit       = 50
+	sellAdvisorMaxLimit           = 200
+	sellAdvisorDefaultShare       = 20.0 // % of daily volume a listing captures
+	sellAdvisorDefaultHoldingCost = 0.1  // % of value per day waiting to sell
+	sellAdvisorHistoryWorkers     = 6
+)
+
+// handleAssetSellAdvisor prices the character's asset stacks where they sit
+// and in Jita, estimates days to sell from market history and recommends
+// where (and whether to list or sell now) for each stack, most valuable
+// first. Stacks are ranked by Jita value and the top `limit` analysed.
+func (s *Server) handleAssetSellAdvisor(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	characterID, allScope, err := parseAuthScope(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !s.isReady() {
+		writeError(w, http.StatusServiceUnavailable, "SDE not loaded yet")
+		return
+	}
+	sessions, err := s.authSessionsForScope(userID, characterID, allScope, true)
+	if err != nil {
+		if strings.Contains(err.Error(), "not logged in") {
+			writeError(w, http.StatusUnauthorized, err.Error())
+		} else {
+			writeError(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	scanner := s.scanner
+	s.mu.RUnlock()
+	if scanner == nil {
+		writeError(w, http.StatusServiceUnavailable, "scanner not ready")
+		return
+	}
+
+	q := r.URL.Query()
+	limit := sellAdvisorDefaultLimit
+	if n, convErr := strconv.Atoi(q.Get("limit")); convErr == nil && n > 0 {
+		limit = clampInt(n, 1, sellAdvisorMaxLimit)
+	}
+	queryPercent := func(key string, def float64) float64 {
+		if v, convErr := strconv.ParseFloat(q.Get(key), 64); convErr == nil {
+			return clampFloat64(v, 0, 100)
+		}
+		return def
+	}
+	src, err := s.priceSources.Get(q.Get("price_source"), pricing.SourceFuzzwork)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Sum sellable stacks per type and root location across characters.
+	type stackKey struct {
+		typeID     int32
+		locationID int64
+	}
+	quantities := make(map[stackKey]int64)
+	warnings := []string{}
+	for _, sess := range sessions {
+		token, tokenErr := s.sessions.EnsureValidTokenForUserCharacter(s.sso, userID, sess.CharacterID)
+		if tokenErr != nil {
+			warnings = append(warnings, sess.CharacterName+": "+tokenErr.Error())
+			continue
+		}
+		assets, assetsErr := s.esi.GetCharacterAssets(sess.CharacterID, token)
+		if assetsErr != nil {
+			warnings = append(warnings, sess.CharacterName+": "+assetsErr.Error())
+			continue
+		}
+		byItemID := make(map[int64]esi.CharacterAsset, len(assets))
+		for _, a := range assets {
+			if a.ItemID > 0 {
+				byItemID[a.ItemID] = a
+			}
+		}
+		for _, a := range assets {
+			// Assembled items and blueprint copies cannot be listed as-is.
+			if a.TypeID <= 0 || a.IsSingleton || a.IsBlueprintCopy || a.Quantity <= 0 {
+				continue
+			}
+			if _, ok := sdeData.Types[a.TypeID]; !ok {
+				continue
+			}
+			quantities[stackKey{a.TypeID, resolveAssetRootLocationID(a.LocationID, byItemID)}] += a.Quantity
+		}
+	}
+
+	var stacks []engine.SellAdvisorStack
+	typeSet := make(map[int32]bool)
+	for k, qty := range quantities {
+		systemID, regionID := s.locationSystemRegion(sdeData, k.locationID)
+		if regionID == 0 {
+			continue // ship hangars in space, unknown structures
+		}
+		stacks = append(stacks, engine.SellAdvisorStack{
+			TypeID: k.typeID, Quantity: qty, LocationID: k.locationID,
+			SystemID: systemID, RegionID: regionID,
+		})
+		typeSet[k.typeID] = true
+	}
+	resp := map[string]interface{}{
+		"price_source": src.Name(),
+		"stacks":       len(stacks),
+		"warnings":     warnings,
+	}
+	if len(stacks) == 0 {
+		resp["advice"] = []engine.SellAdvice{}
+		writeJSON(w, resp)
+		return
+	}
+
+	quotes := make(map[int64]map[int32]pricing.Quote)
+	jita, err := src.Quotes(pricing.Hub{RegionID: engine.JitaRegionID, StationID: engine.JitaStationID}, sortedTypeIDs(typeSet))
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Sprintf("failed to fetch Jita prices: %v", err))
+		return
+	}
+	quotes[engine.JitaStationID] = jita
+	sort.Slice(stacks, func(i, j int) bool {
+		vi := jita[stacks[i].TypeID].Sell * float64(stacks[i].Quantity)
+		vj := jita[stacks[j].TypeID].Sell * float64(stacks[j].Quantity)
+		if vi != vj {
+			return vi > vj
+		}
+		return stacks[i].TypeID < stacks[j].TypeID
+	})
+	if len(stacks) > limit {
+		stacks = stacks[:limit]
+	}
+
+	// Local quotes per station, history per region and type.
+	typesByLocation := make(map[int64]map[int32]bool)
+	regionOf := make(map[int64]int32)
+	historyKeys := make(map[[2]int32]bool)
+	for i := range stacks {
+		st := &stacks[i]
+		st.LocationName = s.esi.StationName(st.LocationID)
+		if typesByLocation[st.LocationID] == nil {
+			typesByLocation[st.LocationID] = make(map[int32]bool)
+		}
+		typesByLocation[st.LocationID][st.TypeID] = true
+		regionOf[st.LocationID] = st.RegionID
+		historyKeys[[2]int32{st.RegionID, st.TypeID}] = true
+		historyKeys[[2]int32{engine.JitaRegionID, st.TypeID}] = true
+	}
+	for locationID, types := range typesByLocation {
+		if locationID == engine.JitaStationID {
+			continue
+		}
+		local, quoteErr := src.Quotes(pricing.Hub{RegionID: regionOf[locationID], StationID: locationID}, sortedTypeIDs(types))
+		if quoteErr != nil {
+			warnings = append(warnings, fmt.Sprintf("%s prices: %v", s.esi.StationName(locationID), quoteErr))
+			continue
+		}
+		quotes[locationID] = local
+	}
+
+	history := make(map[int32]map[int32][]esi.HistoryEntry)
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, sellAdvisorHistoryWorkers)
+	)
+	for key := range historyKeys {
+		wg.Add(1)
+		go func(regionID, typeID int32) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			entries, histErr := scanner.MarketHistory(regionID, typeID)
+			if histErr != nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if history[regionID] == nil {
+				history[regionID] = make(map[int32][]esi.HistoryEntry)
+			}
+			history[regionID][typeID] = entries
+		}(key[0], key[1])
+	}
+	wg.Wait()
+
+	cfg := s.loadConfigForUser(userID)
+	params := engine.SellAdvisorParams{
+		SalesTaxPercent:          queryPercent("sales_tax_percent", cfg.SalesTaxPercent),
+		BrokerFeePercent:         queryPercent("broker_fee_percent", cfg.BrokerFeePercent),
+		MarketSharePercent:       queryPercent("market_share_percent", sellAdvisorDefaultShare),
+		HoldingCostPercentPerDay: queryPercent("holding_cost_percent", sellAdvisorDefaultHoldingCost),
+		FreightISKPerM3Jump:      cfg.FreightISKPerM3Jump,
+		FreightCollateralPercent: cfg.FreightCollateralPercent,
+	}
+	in := engine.SellAdvisorInput{Stacks: stacks, Quotes: quotes, History: history}
+	if universe := s.userUniverse(userID); universe != nil {
+		in.Jumps = universe.ShortestPath
+	}
+	resp["advice"] = engine.AdviseAssetSales(sdeData, in, params)
+	resp["warnings"] = warnings
+	writeJSON(w, resp)
+}
+
+// locationSystemRegion returns the system and region of an NPC station or
+// a structure whose system is known, zeros otherwise.
+func (s *Server) locationSystemRegion(sdeData *sde.Data, locationID int64) (int32, int32) {
+	systemID := int32(0)
+	if st, ok := sdeData.Stations[locationID]; ok {
+		systemID = st.SystemID
+	} else if s.esi != nil {
+		if sid, ok := s.esi.StructureSystemID(locationID); ok {
+			systemID = sid
+		}
+	}
+	if sys, ok := sdeData.Systems[systemID]; ok {
+		return systemID, sys.RegionID
+	}
+	return 0, 0
+}
+
+func sortedTypeIDs(set map[int32]bool) []int32 {
+	ids := make([]int32, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -34,7 +34,9 @@ import (
 	"eve-flipper/internal/db"
 	"eve-flipper/internal/engine"
 	"eve-flipper/internal/esi"
+	"eve-flipper/internal/evescout"
 	"eve-flipper/internal/gankcheck"
+	"eve-flipper/internal/pricing"
 	"eve-flipper/internal/sde"
 	"eve-flipper/internal/zkillboard"
 	"golang.org/x/sync/singleflight"
@@ -77,6 +79,59 @@ type Server struct {
 	// Corporation demo provider (initialized on SDE load).
 	demoCorpProvider *corp.DemoCorpProvider
 
+	// Named price sources features can choose between (see pricing.Registry).
+	priceSources pricing.Registry
+
+	// In-flight streaming scans, cancellable via DELETE /api/scan/{id}.
+	scanRuns scanRunRegistry
+	// Background scans started with ?async=1 (see scanJobHandler).
+	scanJobs scanJobManager
+	// Per-client limits on expensive endpoints; nil on loopback (see SetBindHost).
+	requestLimits *requestLimiter
+	// trustedProxies may set the client IP headers used for rate limiting.
+	trustedProxies []*net.IPNet
+	// API key auth for external tools; keys are checked only in API mode
+	// (see SetAPIMode), each with its own request budget.
+	apiMode      bool
+	apiKeyLimits *requestLimiter
+
+	// Per-character NPC broker fee schedules (see brokerFeeSchedule).
+	brokerFeeMu    sync.Mutex
+	brokerFeeCache map[int64]brokerFeeScheduleEntry
+	// Order slot limits per character, reused across scans.
+	tradingLimitsMu    sync.Mutex
+	tradingLimitsCache map[int64]tradingLimitsEntry
+
+	// Corporation roles per character (see characterCorpRoles).
+	corpRolesMu    sync.Mutex
+	corpRolesCache map[int64]corpRolesEntry
+
+	// Faction warfare LP store offers (see lpStoreOffers).
+	lpOffersMu sync.Mutex
+	lpOffers   []esi.LoyaltyOffer
+	lpOffersAt time.Time
+
+	// Startup SDE download/parse progress (see SetSDELoadProgress).
+	sdeLoad sdeLoadTracker
+
+	// Order desk undercut alarm state per user (see pollOrderDesks).
+	orderDeskWatchMu sync.Mutex
+	orderDeskWatch   map[string]*orderDeskWatchState
+
+	structureWatchMu sync.Mutex
+	structureWatch   map[string]map[string]bool // userID -> active structure alert keys
+
+	// Latest whole-market price anomaly scan (see scanMarketAnomalies).
+	marketAnomalyMu sync.RWMutex
+	marketAnomalies marketAnomalyFeed
+
+	// Live Thera/Turnur connections for scans with use_wormholes.
+	wormholes *evescout.Client
+
+	// Corp ESI route drift report (set by CheckCorpESICompat at startup).
+	corpESICompatMu sync.RWMutex
+	corpESICompat   *corp.ESICompatReport
+
 	// Gank check route danger analyzer (initialized on SDE load).
 	ganker *gankcheck.Checker
 
@@ -132,11 +187,16 @@ const industryAnalyzeMaxBodyBytes = 64 * 1024
 const industryAnalyzeMaxRuns int32 = 10000
 const industryAnalyzeMaxDepth = 20
 const industrySearchMaxLimit = 100
+const industryCostIndexMaxRadius = 40
 
 type contextKey string
 
 const userIDContextKey contextKey = "user_id"
 
+// userIDMintedContextKey marks requests whose user ID was generated for this
+// request because none came with it.
+const userIDMintedContextKey contextKey = "user_id_minted"
+
 var aiRepoPartRe = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
 
 type aiWikiCacheEntry struct {
@@ -428,6 +488,19 @@ func cloneConfig(cfg *config.Config) *config.Config {
 	if cfg.CategoryIDs != nil {
 		copied.CategoryIDs = append([]int32(nil), cfg.CategoryIDs...)
 	}
+	if cfg.AvoidSystemIDs != nil {
+		copied.AvoidSystemIDs = append([]int32(nil), cfg.AvoidSystemIDs...)
+	}
+	if cfg.AvoidRegionIDs != nil {
+		copied.AvoidRegionIDs = append([]int32(nil), cfg.AvoidRegionIDs...)
+	}
+	if cfg.PinnedRoutes != nil {
+		copied.PinnedRoutes = make([]config.PinnedRoute, len(cfg.PinnedRoutes))
+		for i, pin := range cfg.PinnedRoutes {
+			pin.SystemIDs = append([]int32(nil), pin.SystemIDs...)
+			copied.PinnedRoutes[i] = pin
+		}
+	}
 	return &copied
 }
 
@@ -589,6 +662,13 @@ func (s *Server) setUserIDCookie(w http.ResponseWriter, r *http.Request, userID
 }
 
 func (s *Server) ensureRequestUserID(w http.ResponseWriter, r *http.Request) string {
+	userID, _ := s.requestUserID(w, r)
+	return userID
+}
+
+// requestUserID is ensureRequestUserID, also reporting whether the ID was
+// minted because the request carried none.
+func (s *Server) requestUserID(w http.ResponseWriter, r *http.Request) (string, bool) {
 	headerUserID := strings.TrimSpace(r.Header.Get(userIDHeaderName))
 	if s.acceptsUserIDHeader() && isValidUserID(headerUserID) {
 		// Keep cookie in sync for browser flows; header remains source of truth.
@@ -597,16 +677,16 @@ func (s *Server) ensureRequestUserID(w http.ResponseWriter, r *http.Request) str
 		} else if cookieUserID, ok := s.parseSignedUserIDCookieValue(c.Value); !ok || cookieUserID != headerUserID {
 			s.setUserIDCookie(w, r, headerUserID)
 		}
-		return headerUserID
+		return headerUserID, false
 	}
 
 	if c, err := r.Cookie(userIDCookieName); err == nil {
 		if userID, ok := s.parseSignedUserIDCookieValue(c.Value); ok {
-			return userID
+			return userID, false
 		}
 	}
 
-	return s.setUserIDCookie(w, r, generateUserID())
+	return s.setUserIDCookie(w, r, generateUserID()), true
 }
 
 func (s *Server) acceptsUserIDHeader() bool {
@@ -655,8 +735,17 @@ func userIDFromRequest(r *http.Request) string {
 
 func (s *Server) userScopeMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		userID := s.ensureRequestUserID(w, r)
+		var userID string
+		minted := false
+		if key, ok := apiKeyFromContext(r.Context()); ok {
+			userID = key.UserID
+		} else {
+			userID, minted = s.requestUserID(w, r)
+		}
 		ctx := context.WithValue(r.Context(), userIDContextKey, userID)
+		if minted {
+			ctx = context.WithValue(ctx, userIDMintedContextKey, true)
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -698,10 +787,15 @@ func (s *Server) loadConfigForUser(userID string) *config.Config {
 }
 
 func (s *Server) saveConfigForUser(userID string, cfg *config.Config) error {
+	oldPrefs := s.routePreferences(userID)
 	if s.db != nil {
-		return s.db.SaveConfigForUser(userID, cfg)
+		if err := s.db.SaveConfigForUser(userID, cfg); err != nil {
+			return err
+		}
+	} else {
+		s.cfg = cloneConfig(cfg)
 	}
-	s.cfg = cloneConfig(cfg)
+	s.forgetRoutePreferences(oldPrefs, cfg)
 	return nil
 }
 
@@ -722,9 +816,31 @@ func NewServer(cfg *config.Config, esiClient *esi.Client, database *db.DB, ssoCo
 		appFlavor:          "classic",
 		updateHTTP:         &http.Client{Timeout: 45 * time.Second},
 		updateSkipByUser:   make(map[string]string),
+		wormholes:          evescout.NewClient(),
+		priceSources: pricing.Registry{
+			pricing.SourceESI:      &pricing.ESIOrderBook{Client: esiClient},
+			pricing.SourceFuzzwork: pricing.NewCached(pricing.NewFuzzwork(), 5*time.Minute),
+		},
 	}
 	if s.wikiRAG != nil && stationAIWikiRAGAutoStartEnabled() {
-		s.wikiRAG.Start(defaultStationAIWikiRepo)
+		s.startWikiRAGSync()
+	}
+	if esiClient != nil {
+		s.scanJobs.cooldown = esiClient.Cooldown
+		s.startOrderCacheRefresher()
+	}
+	if database != nil {
+		s.startScanAccuracyEvaluator()
+		s.startWatchlistBandWatcher()
+		s.startMarketAnomalyScanner()
+		s.startSpeculationBasketWatcher()
+		s.startWatchlistBookPoller()
+		s.startImpactCalibrator()
+	}
+	if database != nil && sessions != nil {
+		s.startOrderDeskWatcher()
+		s.startStructureWatcher()
+		s.startCorpSnapshotter()
 	}
 	return s
 }
@@ -752,6 +868,7 @@ func (s *Server) SetSDE(data *sde.Data) {
 	s.sdeData = data
 	scanner := engine.NewScanner(data, s.esi)
 	scanner.History = s.db
+	scanner.Wormholes = s.wormholes
 	s.scanner = scanner
 	s.industryAnalyzer = engine.NewIndustryAnalyzer(data, s.esi)
 
@@ -777,11 +894,15 @@ func (s *Server) isReady() bool {
 func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /api/status", s.handleStatus)
+	mux.HandleFunc("GET /api/status/sde", s.handleSDELoadStatus)
 	mux.HandleFunc("GET /api/update/check", s.handleUpdateCheck)
 	mux.HandleFunc("POST /api/update/skip", s.handleUpdateSkipForSession)
 	mux.HandleFunc("POST /api/update/apply", s.handleUpdateApply)
 	mux.HandleFunc("POST /api/internal/wiki/gollum", s.handleInternalWikiGollumWebhook)
 	mux.HandleFunc("POST /api/telemetry/client", s.handleTelemetryClient)
+	mux.HandleFunc("GET /api/usage/stats", s.handleGetUsageStats)
+	mux.HandleFunc("POST /api/usage/events", s.handleRecordUsageEvent)
+	mux.HandleFunc("DELETE /api/usage/stats", s.handleClearUsageStats)
 	mux.HandleFunc("GET /api/hosted/access", s.handleHostedAccess)
 	mux.HandleFunc("POST /api/hosted/payments/request", s.handleHostedPaymentRequest)
 	mux.HandleFunc("POST /api/hosted/payments/mark-sent", s.handleHostedPaymentMarkSent)
@@ -799,29 +920,58 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("GET /api/systems", s.handleGetSystems)
 	mux.HandleFunc("GET /api/systems/autocomplete", s.handleAutocomplete)
 	mux.HandleFunc("GET /api/regions/autocomplete", s.handleRegionAutocomplete)
-	mux.HandleFunc("POST /api/scan", s.handleScan)
-	mux.HandleFunc("POST /api/scan/multi-region", s.handleScanMultiRegion)
-	mux.HandleFunc("POST /api/scan/regional-day", s.handleScanRegionalDay)
-	mux.HandleFunc("POST /api/scan/contracts", s.handleScanContracts)
+	mux.HandleFunc("POST /api/scan", s.scanJobHandler("radius", s.handleScan))
+	mux.HandleFunc("POST /api/scan/multi-region", s.scanJobHandler("region", s.handleScanMultiRegion))
+	mux.HandleFunc("POST /api/scan/regional-day", s.scanJobHandler("regional_day", s.handleScanRegionalDay))
+	mux.HandleFunc("POST /api/scan/compare", s.scanJobHandler("compare", s.handleScanCompare))
+	mux.HandleFunc("POST /api/scan/optimize-cargo", s.handleOptimizeCargo)
+	mux.HandleFunc("POST /api/scan/contracts", s.scanJobHandler("contracts", s.handleScanContracts))
+	mux.HandleFunc("GET /api/scan/jobs", s.handleListScanJobs)
+	mux.HandleFunc("GET /api/scan/jobs/{id}", s.handleGetScanJob)
+	mux.HandleFunc("GET /api/jobs/background", s.handleListBackgroundJobs)
+	mux.HandleFunc("GET /api/jobs/ingest", s.handleListIngestWatermarks)
+	mux.HandleFunc("DELETE /api/scan/{id}", s.handleCancelScan)
 	mux.HandleFunc("POST /api/backtest/flips", s.handleBacktestFlips)
 	mux.HandleFunc("POST /api/orderbook/coverage", s.handleOrderBookCoverage)
 	mux.HandleFunc("GET /api/orderbook/stats", s.handleOrderBookStats)
 	mux.HandleFunc("POST /api/orderbook/cleanup", s.handleOrderBookCleanup)
 	mux.HandleFunc("GET /api/orderbook/snapshots", s.handleOrderBookSnapshots)
 	mux.HandleFunc("GET /api/orderbook/snapshots/{snapshotID}/levels", s.handleOrderBookLevels)
-	mux.HandleFunc("POST /api/route/find", s.handleRouteFind)
+	mux.HandleFunc("POST /api/route/find", s.scanJobHandler("route", s.handleRouteFind))
+	mux.HandleFunc("POST /api/route/multistop", s.handleRouteMultiStop)
+	mux.HandleFunc("POST /api/route/waypoints", s.handleRouteWaypoints)
+	mux.HandleFunc("GET /api/route/jump", s.handleRouteJump)
+	mux.HandleFunc("GET /api/route/wormholes", s.handleRouteWormholes)
+	mux.HandleFunc("GET /api/route/ansiblex", s.handleGetAnsiblexGates)
+	mux.HandleFunc("GET /api/route/pins", s.handleListPinnedRoutes)
+	mux.HandleFunc("POST /api/route/pins", s.handleSavePinnedRoute)
+	mux.HandleFunc("DELETE /api/route/pins/{from}/{to}", s.handleDeletePinnedRoute)
+	mux.HandleFunc("PUT /api/route/ansiblex", s.handleSetAnsiblexGates)
+	mux.HandleFunc("DELETE /api/route/ansiblex", s.handleClearAnsiblexGates)
+	mux.HandleFunc("POST /api/auth/route/ansiblex/import", s.handleAuthImportAnsiblexGates)
 	mux.HandleFunc("GET /api/watchlist", s.handleGetWatchlist)
 	mux.HandleFunc("POST /api/watchlist", s.handleAddWatchlist)
+	mux.HandleFunc("POST /api/watchlist/import", s.handleImportWatchlist)
+	mux.HandleFunc("GET /api/watchlist/cadence", s.handleWatchlistCadence)
+	mux.HandleFunc("GET /api/watchlist/export", s.handleExportWatchlist)
+	mux.HandleFunc("POST /api/watchlist/bulk-delete", s.handleBulkDeleteWatchlist)
+	mux.HandleFunc("POST /api/watchlist/group", s.handleGroupWatchlist)
+	mux.HandleFunc("POST /api/watchlist/groups/rename", s.handleRenameWatchlistGroup)
 	mux.HandleFunc("DELETE /api/watchlist/{typeID}", s.handleDeleteWatchlist)
 	mux.HandleFunc("PUT /api/watchlist/{typeID}", s.handleUpdateWatchlist)
 	mux.HandleFunc("GET /api/alerts/history", s.handleGetAlertHistory)
-	mux.HandleFunc("POST /api/scan/station", s.handleScanStation)
+	mux.HandleFunc("POST /api/scan/station", s.scanJobHandler("station", s.handleScanStation))
+	mux.HandleFunc("POST /api/station/buy-advisor", s.handleBuyOrderAdvisor)
 	mux.HandleFunc("GET /api/stations", s.handleGetStations)
 	mux.HandleFunc("GET /api/scan/history", s.handleGetHistory)
 	mux.HandleFunc("GET /api/scan/history/{id}", s.handleGetHistoryByID)
 	mux.HandleFunc("GET /api/scan/history/{id}/results", s.handleGetHistoryResults)
 	mux.HandleFunc("DELETE /api/scan/history/{id}", s.handleDeleteHistory)
 	mux.HandleFunc("POST /api/scan/history/clear", s.handleClearHistory)
+	mux.HandleFunc("GET /api/scan/radius-suggestion", s.handleRadiusSuggestion)
+	mux.HandleFunc("POST /api/scan/radius-suggestion/apply", s.handleApplyRadiusSuggestion)
+	mux.HandleFunc("GET /api/history/accuracy", s.handleGetScanAccuracy)
+	mux.HandleFunc("GET /api/route/sheet", s.handleRouteSheet)
 	// Auth
 	mux.HandleFunc("GET /api/auth/login", s.handleAuthLogin)
 	mux.HandleFunc("GET /api/auth/callback", s.handleAuthCallback)
@@ -836,9 +986,12 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("POST /api/security/vault/reset", s.handleSecurityVaultReset)
 	mux.HandleFunc("GET /api/auth/character", s.handleAuthCharacter)
 	mux.HandleFunc("GET /api/auth/location", s.handleAuthLocation)
+	mux.HandleFunc("POST /api/auth/onboarding/seed", s.handleAuthOnboardingSeed)
 	mux.HandleFunc("GET /api/auth/pi/planets", s.handleAuthPIPlanets)
+	mux.HandleFunc("POST /api/pi/arbitrage", s.handlePIArbitrage)
 	mux.HandleFunc("GET /api/auth/undercuts", s.handleAuthUndercuts)
 	mux.HandleFunc("GET /api/auth/orders/desk", s.handleAuthOrderDesk)
+	mux.HandleFunc("GET /api/auth/orders/desk/actions", s.handleAuthOrderDeskActions)
 	mux.HandleFunc("GET /api/auth/station/trade-states", s.handleAuthGetStationTradeStates)
 	mux.HandleFunc("POST /api/auth/station/trade-states/set", s.handleAuthSetStationTradeState)
 	mux.HandleFunc("POST /api/auth/station/trade-states/delete", s.handleAuthDeleteStationTradeStates)
@@ -874,6 +1027,7 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("GET /api/auth/ledger", s.handleAuthLedger)
 	mux.HandleFunc("GET /api/auth/portfolio", s.handleAuthPortfolio)
 	mux.HandleFunc("GET /api/auth/portfolio/optimize", s.handleAuthPortfolioOptimize)
+	mux.HandleFunc("GET /api/auth/portfolio/exposure", s.handleAuthPortfolioExposure)
 	mux.HandleFunc("GET /api/auth/structures", s.handleAuthStructures)
 	// UI operations (requires auth)
 	mux.HandleFunc("POST /api/ui/open-market", s.handleUIOpenMarket)
@@ -883,13 +1037,45 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("GET /api/contracts/{contract_id}/items", s.handleGetContractItems)
 	// Item intelligence
 	mux.HandleFunc("GET /api/items/search", s.handleItemSearch)
+	mux.HandleFunc("GET /api/market/browse", s.handleMarketBrowse)
+	mux.HandleFunc("GET /api/market/anomalies", s.handleMarketAnomalies)
+	mux.HandleFunc("GET /api/market/competitors", s.handleMarketCompetitors)
+	mux.HandleFunc("GET /api/market/competitors/watch", s.handleListCompetitorWatches)
+	mux.HandleFunc("POST /api/market/competitors/watch", s.handleAddCompetitorWatch)
+	mux.HandleFunc("DELETE /api/market/competitors/watch", s.handleDeleteCompetitorWatch)
+	mux.HandleFunc("GET /api/fees/stations", s.handleListStationFees)
+	mux.HandleFunc("POST /api/fees/stations", s.handleSaveStationFee)
+	mux.HandleFunc("DELETE /api/fees/stations/{location_id}", s.handleDeleteStationFee)
+	mux.HandleFunc("GET /api/ships", s.handleListShipProfiles)
+	mux.HandleFunc("POST /api/ships", s.handleSaveShipProfile)
+	mux.HandleFunc("DELETE /api/ships/{id}", s.handleDeleteShipProfile)
+	mux.HandleFunc("GET /api/speculation/baskets", s.handleListSpeculationBaskets)
+	mux.HandleFunc("POST /api/speculation/baskets", s.handleSaveSpeculationBasket)
+	mux.HandleFunc("DELETE /api/speculation/baskets/{id}", s.handleDeleteSpeculationBasket)
+	mux.HandleFunc("GET /api/speculation/baskets/{id}/chart", s.handleSpeculationBasketChart)
+	mux.HandleFunc("POST /api/market/coverage", s.handleMarketCoverage)
+	mux.HandleFunc("POST /api/fits/appraise", s.handleAppraiseFits)
+	mux.HandleFunc("GET /api/assets/sell-advisor", s.handleAssetSellAdvisor)
+	mux.HandleFunc("GET /api/stock", s.handleStock)
 	mux.HandleFunc("GET /api/items/intelligence", s.handleItemIntelligence)
+	mux.HandleFunc("GET /api/types/{typeID}/history", s.handleGetTypeHistory)
+	mux.HandleFunc("GET /api/types/{typeID}/detail", s.handleGetTypeDetail)
 	// Industry
 	mux.HandleFunc("POST /api/industry/analyze", s.handleIndustryAnalyze)
 	mux.HandleFunc("GET /api/industry/search", s.handleIndustrySearch)
+	mux.HandleFunc("GET /api/industry/decryptors", s.handleIndustryDecryptors)
 	mux.HandleFunc("GET /api/industry/systems", s.handleIndustrySystems)
+	mux.HandleFunc("GET /api/industry/cost-indices", s.handleIndustryCostIndices)
+	mux.HandleFunc("POST /api/industry/ore-basket", s.handleIndustryOreBasket)
+	mux.HandleFunc("POST /api/export/multibuy", s.handleExportMultibuy)
+	mux.HandleFunc("GET /api/export/{kind}", s.handleExport)
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+	mux.HandleFunc("GET /api/keys", s.handleListAPIKeys)
+	mux.HandleFunc("POST /api/keys", s.handleCreateAPIKey)
+	mux.HandleFunc("DELETE /api/keys/{id}", s.handleRevokeAPIKey)
 	mux.HandleFunc("GET /api/industry/status", s.handleIndustryStatus)
 	mux.HandleFunc("POST /api/execution/plan", s.handleExecutionPlan)
+	mux.HandleFunc("POST /api/execution/schedule", s.handleExecutionSchedule)
 	// Demand / War Tracker
 	mux.HandleFunc("GET /api/demand/regions", s.handleDemandRegions)
 	mux.HandleFunc("GET /api/demand/hotzones", s.handleDemandHotZones)
@@ -902,17 +1088,39 @@ func (s *Server) Handler() http.Handler {
 	// Corporation
 	mux.HandleFunc("GET /api/auth/roles", s.handleAuthRoles)
 	mux.HandleFunc("GET /api/corp/dashboard", s.handleCorpDashboard)
+	mux.HandleFunc("GET /api/corp/alliance", s.handleCorpAlliance)
+	mux.HandleFunc("GET /api/corp/alliance/{corporationID}", s.handleCorpAllianceCorp)
+	mux.HandleFunc("GET /api/corp/dashboard/trends", s.handleCorpDashboardTrends)
+	mux.HandleFunc("GET /api/corp/dashboard/layout", s.handleGetCorpDashboardLayout)
+	mux.HandleFunc("PUT /api/corp/dashboard/layout", s.handlePutCorpDashboardLayout)
+	mux.HandleFunc("GET /api/corp/esi-compat", s.handleCorpESICompat)
 	mux.HandleFunc("GET /api/corp/members", s.handleCorpMembers)
+	mux.HandleFunc("GET /api/corp/members/analytics", s.handleCorpMemberAnalytics)
+	mux.HandleFunc("GET /api/corp/members/{id}/profile", s.handleCorpMemberProfile)
 	mux.HandleFunc("GET /api/corp/wallets", s.handleCorpWallets)
 	mux.HandleFunc("GET /api/corp/journal", s.handleCorpJournal)
+	mux.HandleFunc("GET /api/corp/transactions", s.handleCorpTransactions)
 	mux.HandleFunc("GET /api/corp/orders", s.handleCorpOrders)
+	mux.HandleFunc("GET /api/corp/contracts", s.handleCorpContracts)
+	mux.HandleFunc("GET /api/corp/structures", s.handleCorpStructures)
+	mux.HandleFunc("GET /api/corp/structures/monitor", s.handleCorpStructureMonitor)
 	mux.HandleFunc("GET /api/corp/industry", s.handleCorpIndustry)
+	mux.HandleFunc("GET /api/corp/industry/schedule", s.handleCorpIndustrySchedule)
 	mux.HandleFunc("GET /api/corp/mining", s.handleCorpMining)
+	mux.HandleFunc("GET /api/corp/mining/report", s.handleCorpMiningReport)
+	mux.HandleFunc("GET /api/corp/buyback/board", s.handleCorpBuybackBoard)
+	mux.HandleFunc("POST /api/corp/buyback/board/refresh", s.handleCorpBuybackRefresh)
+	mux.HandleFunc("GET /api/corp/buyback/rules", s.handleGetCorpBuybackRules)
+	mux.HandleFunc("PUT /api/corp/buyback/rules", s.handlePutCorpBuybackRules)
+	mux.HandleFunc("POST /api/corp/buyback/quote", s.handleCorpBuybackQuote)
+	mux.HandleFunc("GET /api/corp/buyback/quotes", s.handleCorpBuybackQuotes)
+	mux.HandleFunc("POST /api/corp/buyback/quotes/{id}/accept", s.handleCorpBuybackAccept)
+	mux.HandleFunc("POST /api/corp/buyback/reconcile", s.handleCorpBuybackReconcile)
 	// Gank Check
 	mux.HandleFunc("GET /api/gankcheck", s.handleGankCheck)
 	mux.HandleFunc("GET /api/gankcheck/detail", s.handleGankCheckDetail)
 	mux.HandleFunc("GET /api/gankcheck/batch", s.handleGankCheckBatch)
-	return securityHeadersMiddleware(s.corsMiddleware(s.originGuardMiddleware(requestBodyLimitMiddleware(s.userScopeMiddleware(s.telemetryMiddleware(s.hostedQuotaMiddleware(mux)))))))
+	return securityHeadersMiddleware(s.corsMiddleware(s.originGuardMiddleware(requestBodyLimitMiddleware(s.apiKeyMiddleware(s.userScopeMiddleware(s.telemetryMiddleware(s.rateLimitMiddleware(s.hostedQuotaMiddleware(mux)))))))))
 }
 
 func corsMiddleware(next http.Handler) http.Handler {
@@ -937,7 +1145,7 @@ func corsMiddlewareWithOriginCheck(next http.Handler, isAllowed func(origin, req
 			w.Header().Set("Vary", "Origin")
 		}
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-EveFlipper-UID")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-EveFlipper-UID, Authorization, X-API-Key")
 		if r.Method == "OPTIONS" {
 			if origin != "" && allowedOrigin == "" {
 				w.WriteHeader(http.StatusForbidden)
@@ -1358,13 +1566,7 @@ func filterFlipResultsMarketDisabled(results []engine.FlipResult) []engine.FlipR
 }
 
 func flipResultKPIProfit(r engine.FlipResult) float64 {
-	if r.RealProfit > 0 {
-		return r.RealProfit
-	}
-	if r.ExpectedProfit > 0 {
-		return r.ExpectedProfit
-	}
-	return r.TotalProfit
+	return engine.FlipResultKPIProfit(r)
 }
 
 func stationTradeKPIProfit(r engine.StationTrade) float64 {
@@ -1763,6 +1965,9 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 		"sde_systems": systemCount,
 		"sde_types":   typeCount,
 		"esi_ok":      esiOK,
+		// Error-limit tracker and in-flight request counts, for debugging stuck scans.
+		"esi_rate_limit": s.esi.RateLimitState(),
+		"sde_progress":   s.sdeLoadStatus(),
 	}
 
 	// Add last successful ESI connection time if available
@@ -1776,7 +1981,7 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
 	userID := userIDFromRequest(r)
 	cfg := s.loadConfigForUser(userID)
-	writeJSON(w, cfg)
+	writeJSON(w, redactConfigSecrets(r, cfg))
 }
 
 func (s *Server) handleSetConfig(w http.ResponseWriter, r *http.Request) {
@@ -1849,6 +2054,15 @@ func (s *Server) handleSetConfig(w http.ResponseWriter, r *http.Request) {
 	if v, ok := patch["max_s2b_bfs_ratio"]; ok {
 		json.Unmarshal(v, &cfg.MaxS2BBfSRatio)
 	}
+	if v, ok := patch["max_fill_days"]; ok {
+		json.Unmarshal(v, &cfg.MaxFillDays)
+	}
+	if v, ok := patch["scan_timeout_sec"]; ok {
+		json.Unmarshal(v, &cfg.ScanTimeoutSec)
+	}
+	if v, ok := patch["max_region_failures"]; ok {
+		json.Unmarshal(v, &cfg.MaxRegionFailures)
+	}
 	if v, ok := patch["min_route_security"]; ok {
 		json.Unmarshal(v, &cfg.MinRouteSecurity)
 	}
@@ -1870,6 +2084,12 @@ func (s *Server) handleSetConfig(w http.ResponseWriter, r *http.Request) {
 	if v, ok := patch["shipping_cost_per_m3_jump"]; ok {
 		json.Unmarshal(v, &cfg.ShippingCostPerM3Jump)
 	}
+	if v, ok := patch["freight_isk_per_m3_jump"]; ok {
+		json.Unmarshal(v, &cfg.FreightISKPerM3Jump)
+	}
+	if v, ok := patch["freight_collateral_percent"]; ok {
+		json.Unmarshal(v, &cfg.FreightCollateralPercent)
+	}
 	if v, ok := patch["source_regions"]; ok {
 		json.Unmarshal(v, &cfg.SourceRegions)
 	}
@@ -1897,6 +2117,30 @@ func (s *Server) handleSetConfig(w http.ResponseWriter, r *http.Request) {
 	if v, ok := patch["alert_desktop"]; ok {
 		json.Unmarshal(v, &cfg.AlertDesktop)
 	}
+	if v, ok := patch["order_desk_alerts"]; ok {
+		json.Unmarshal(v, &cfg.OrderDeskAlerts)
+	}
+	if v, ok := patch["order_desk_alert_minutes"]; ok {
+		json.Unmarshal(v, &cfg.OrderDeskAlertMinutes)
+	}
+	if v, ok := patch["structure_alerts"]; ok {
+		json.Unmarshal(v, &cfg.StructureAlerts)
+	}
+	if v, ok := patch["structure_fuel_alert_days"]; ok {
+		json.Unmarshal(v, &cfg.StructureFuelAlertDays)
+	}
+	if v, ok := patch["reference_station_id"]; ok {
+		json.Unmarshal(v, &cfg.ReferenceStationID)
+	}
+	if v, ok := patch["avoid_system_ids"]; ok {
+		json.Unmarshal(v, &cfg.AvoidSystemIDs)
+	}
+	if v, ok := patch["avoid_region_ids"]; ok {
+		json.Unmarshal(v, &cfg.AvoidRegionIDs)
+	}
+	if v, ok := patch["pinned_routes"]; ok {
+		json.Unmarshal(v, &cfg.PinnedRoutes)
+	}
 	if v, ok := patch["alert_telegram_token"]; ok {
 		json.Unmarshal(v, &cfg.AlertTelegramToken)
 	}
@@ -1987,6 +2231,15 @@ func (s *Server) handleSetConfig(w http.ResponseWriter, r *http.Request) {
 	if cfg.MaxS2BBfSRatio < 0 {
 		cfg.MaxS2BBfSRatio = 0
 	}
+	if cfg.MaxFillDays < 0 {
+		cfg.MaxFillDays = 0
+	}
+	if cfg.ScanTimeoutSec < 0 {
+		cfg.ScanTimeoutSec = 0
+	}
+	if cfg.MaxRegionFailures < 0 {
+		cfg.MaxRegionFailures = -1
+	}
 	if cfg.MinRouteSecurity < 0 {
 		cfg.MinRouteSecurity = 0
 	} else if cfg.MinRouteSecurity > 1 {
@@ -2012,6 +2265,12 @@ func (s *Server) handleSetConfig(w http.ResponseWriter, r *http.Request) {
 	if cfg.ShippingCostPerM3Jump < 0 {
 		cfg.ShippingCostPerM3Jump = 0
 	}
+	if cfg.FreightISKPerM3Jump < 0 {
+		cfg.FreightISKPerM3Jump = 0
+	}
+	if cfg.FreightCollateralPercent < 0 {
+		cfg.FreightCollateralPercent = 0
+	}
 	if cfg.TargetMarketLocationID < 0 {
 		cfg.TargetMarketLocationID = 0
 	}
@@ -2052,6 +2311,17 @@ func (s *Server) handleSetConfig(w http.ResponseWriter, r *http.Request) {
 		}
 		cfg.CategoryIDs = clean
 	}
+	if cfg.OrderDeskAlertMinutes < orderDeskWatchMinMinutes {
+		cfg.OrderDeskAlertMinutes = orderDeskWatchMinMinutes
+	}
+	if cfg.StructureFuelAlertDays <= 0 {
+		cfg.StructureFuelAlertDays = corp.DefaultStructureFuelAlertDays
+	}
+	cfg.StructureFuelAlertDays = clampInt(cfg.StructureFuelAlertDays, 1, 60)
+	if cfg.ReferenceStationID <= 0 {
+		cfg.ReferenceStationID = config.DefaultReferenceStationID
+	}
+	s.normalizeRouteAvoidance(cfg)
 	if cfg.Opacity < 0 {
 		cfg.Opacity = 0
 	} else if cfg.Opacity > 100 {
@@ -2066,7 +2336,7 @@ func (s *Server) handleSetConfig(w http.ResponseWriter, r *http.Request) {
 		writeError(w, 500, "failed to save config")
 		return
 	}
-	writeJSON(w, cfg)
+	writeJSON(w, redactConfigSecrets(r, cfg))
 }
 
 type alertSendResult struct {
@@ -2398,6 +2668,8 @@ type scanRequest struct {
 	// Advanced filters
 	MinDailyVolume         int64    `json:"min_daily_volume"`
 	MaxInvestment          float64  `json:"max_investment"`
+	MaxBudget              float64  `json:"max_budget"`        // 0 = unlimited
+	UseWalletBudget        bool     `json:"use_wallet_budget"` // cap MaxBudget by the logged-in character's wallet
 	MinItemProfit          float64  `json:"min_item_profit"`
 	MinPeriodROI           float64  `json:"min_period_roi"`
 	MaxDOS                 float64  `json:"max_dos"`
@@ -2407,6 +2679,7 @@ type scanRequest struct {
 	MinBfSPerDay           float64  `json:"min_bfs_per_day"`
 	MinS2BBfSRatio         float64  `json:"min_s2b_bfs_ratio"`
 	MaxS2BBfSRatio         float64  `json:"max_s2b_bfs_ratio"`
+	MaxFillDays            float64  `json:"max_fill_days"`
 	AvgPricePeriod         int      `json:"avg_price_period"`
 	ShippingCostPerM3Jump  float64  `json:"shipping_cost_per_m3_jump"`
 	MinRouteSecurity       float64  `json:"min_route_security"`        // 0 = all; 0.45 = highsec only; 0.7 = min 0.7
@@ -2428,10 +2701,136 @@ type scanRequest struct {
 	CategoryIDs []int32 `json:"category_ids"`
 	// Sell-order mode: use target lowest sell price instead of highest buy order price
 	SellOrderMode bool `json:"sell_order_mode"`
+	// Flipper: price at the best bid/ask without walking the book (faster).
+	NaivePricing bool `json:"naive_pricing"`
 	// Regional diagnostic mode: include capped rejected regional-day rows with reason/status metadata.
 	RegionalDiagnosticMode bool `json:"regional_diagnostic_mode"`
 	// Player structures
 	IncludeStructures bool `json:"include_structures"`
+	// Live Thera/Turnur wormhole connections as one-jump shortcuts.
+	UseWormholes bool `json:"use_wormholes"`
+	// Route through the user's imported Ansiblex jump gates.
+	UseAnsiblex bool `json:"use_ansiblex"`
+	// Flag flips diverging from faction warfare LP-store conversion prices.
+	// ISK/LP rates <= 0 use the engine defaults.
+	LPSignal          bool    `json:"lp_signal"`
+	LPFloorISKPerLP   float64 `json:"lp_floor_isk_per_lp"`
+	LPCeilingISKPerLP float64 `json:"lp_ceiling_isk_per_lp"`
+	// Region order fetch limits: timeout of the fetch stage in seconds
+	// (0 = none) and failed regions tolerated (nil or <0 = no limit).
+	ScanTimeoutSec    int  `json:"scan_timeout_sec"`
+	MaxRegionFailures *int `json:"max_region_failures"`
+	// Jump-drive hauling: >0 range enables jump routing with isotope fuel cost.
+	JumpRangeLY          float64 `json:"jump_range_ly"`
+	JumpFatigueReduction float64 `json:"jump_fatigue_reduction"`
+	JumpIsotopesPerLY    float64 `json:"jump_isotopes_per_ly"`
+	JumpIsotopePrice     float64 `json:"jump_isotope_price"`   // 0 = Jita lowest sell
+	JumpIsotopeTypeID    int32   `json:"jump_isotope_type_id"` // 0 = Helium Isotopes
+	// Courier freight pricing: annotates flips with NetProfitAfterFreight.
+	FreightISKPerM3Jump      float64 `json:"freight_isk_per_m3_jump"`
+	FreightCollateralPercent float64 `json:"freight_collateral_percent"`
+	// Travel time for ISK/hour: ship class with optional align time and
+	// warp speed overrides.
+	ShipClass        string  `json:"ship_class"`
+	ShipAlignSeconds float64 `json:"ship_align_seconds"`
+	ShipWarpSpeedAU  float64 `json:"ship_warp_speed_au"`
+	// ShipEHP is the hauler's tank for gank thresholds; 0 = class default.
+	ShipEHP float64 `json:"ship_ehp"`
+	// ShipProfileID > 0 takes cargo capacity, ship class, align time, warp
+	// speed and tank from a saved ship profile instead.
+	ShipProfileID int64 `json:"ship_profile_id"`
+	// Split flips carrying more ISK per load than the ship's gank
+	// threshold into more trips.
+	GankSplitTrips bool `json:"gank_split_trips"`
+}
+
+// walletBudget caps budget by the active character's wallet balance. When no
+// character is logged in or ESI fails, budget is returned unchanged.
+func (s *Server) walletBudget(userID string, budget float64) float64 {
+	if s.sessions == nil {
+		return budget
+	}
+	sess := s.sessions.GetForUser(userID)
+	if sess == nil {
+		return budget
+	}
+	token, err := s.sessions.EnsureValidTokenForUserCharacter(s.sso, userID, sess.CharacterID)
+	if err != nil {
+		log.Printf("[SCAN] Wallet budget unavailable for %s: %v", sess.CharacterName, err)
+		return budget
+	}
+	balance, err := s.esi.GetWalletBalance(sess.CharacterID, token)
+	if err != nil {
+		log.Printf("[SCAN] Wallet budget unavailable for %s: %v", sess.CharacterName, err)
+		return budget
+	}
+	if s.db != nil {
+		if archiveErr := s.db.UpdateWalletArchiveBalance(userID, sess.CharacterID, balance); archiveErr != nil {
+			log.Printf("[SCAN] Wallet balance archive error (%s): %v", sess.CharacterName, archiveErr)
+		}
+	}
+	// An empty wallet affords nothing; keep the cap non-zero (0 means unlimited).
+	if balance < 1 {
+		balance = 1
+	}
+	if budget <= 0 || balance < budget {
+		return balance
+	}
+	return budget
+}
+
+// radiusScanParams parses a radius scan request and adds the user's broker
+// fees, wallet budget, Ansiblex gates and structure access token.
+func (s *Server) radiusScanParams(userID string, req scanRequest) (engine.ScanParams, error) {
+	if err := s.applyScanShipProfile(userID, &req); err != nil {
+		return engine.ScanParams{}, err
+	}
+	params, err := s.parseScanParams(req)
+	if err != nil {
+		return params, err
+	}
+	if req.UseWalletBudget {
+		params.MaxBudget = s.walletBudget(userID, params.MaxBudget)
+	}
+	params.BrokerFees = s.brokerFeeSchedule(userID)
+	params.StructureAccessFees = s.structureAccessFees(userID)
+	params.RoutePreferences = s.routePreferences(userID)
+	applyConfigFetchLimits(&params, s.loadConfigForUser(userID))
+	if params.UseAnsiblex {
+		params.AnsiblexGates = s.ansiblexEdges(userID)
+	}
+	if req.IncludeStructures && s.sessions != nil {
+		if token, tokenErr := s.sessions.EnsureValidTokenForUser(s.sso, userID); tokenErr == nil {
+			params.AccessToken = token
+		}
+	}
+	return params, nil
+}
+
+// configFetchLimits returns the user's region fetch timeout (0 = none) and
+// failed region tolerance (nil = no limit).
+func configFetchLimits(cfg *config.Config) (time.Duration, *int) {
+	if cfg == nil {
+		return 0, nil
+	}
+	var maxFailures *int
+	if cfg.MaxRegionFailures >= 0 {
+		n := cfg.MaxRegionFailures
+		maxFailures = &n
+	}
+	return time.Duration(cfg.ScanTimeoutSec) * time.Second, maxFailures
+}
+
+// applyConfigFetchLimits fills the fetch limits a scan request left unset
+// from the user's config.
+func applyConfigFetchLimits(params *engine.ScanParams, cfg *config.Config) {
+	timeout, maxFailures := configFetchLimits(cfg)
+	if params.FetchTimeout <= 0 {
+		params.FetchTimeout = timeout
+	}
+	if params.MaxRegionFailures == nil {
+		params.MaxRegionFailures = maxFailures
+	}
 }
 
 func (s *Server) parseScanParams(req scanRequest) (engine.ScanParams, error) {
@@ -2497,6 +2896,16 @@ func (s *Server) parseScanParams(req scanRequest) (engine.ScanParams, error) {
 		return engine.ScanParams{}, fmt.Errorf("system not found: %s", req.SystemName)
 	}
 
+	jumpIsotopePrice := req.JumpIsotopePrice
+	if req.JumpRangeLY > 0 && jumpIsotopePrice <= 0 {
+		jumpIsotopePrice = s.jitaIsotopePrice(req.JumpIsotopeTypeID)
+	}
+
+	var maxRegionFailures *int
+	if req.MaxRegionFailures != nil && *req.MaxRegionFailures >= 0 {
+		maxRegionFailures = req.MaxRegionFailures
+	}
+
 	return engine.ScanParams{
 		CurrentSystemID:            systemID,
 		IgnoredSystemIDs:           ignoredSystemIDs,
@@ -2513,6 +2922,7 @@ func (s *Server) parseScanParams(req scanRequest) (engine.ScanParams, error) {
 		SellSalesTaxPercent:        req.SellSalesTaxPercent,
 		MinDailyVolume:             req.MinDailyVolume,
 		MaxInvestment:              req.MaxInvestment,
+		MaxBudget:                  req.MaxBudget,
 		MinItemProfit:              req.MinItemProfit,
 		MinPeriodROI:               req.MinPeriodROI,
 		MaxDOS:                     req.MaxDOS,
@@ -2522,6 +2932,7 @@ func (s *Server) parseScanParams(req scanRequest) (engine.ScanParams, error) {
 		MinBfSPerDay:               req.MinBfSPerDay,
 		MinS2BBfSRatio:             req.MinS2BBfSRatio,
 		MaxS2BBfSRatio:             req.MaxS2BBfSRatio,
+		MaxFillDays:                req.MaxFillDays,
 		AvgPricePeriod:             req.AvgPricePeriod,
 		ShippingCostPerM3Jump:      req.ShippingCostPerM3Jump,
 		SourceRegionIDs:            sourceRegionIDs,
@@ -2539,8 +2950,24 @@ func (s *Server) parseScanParams(req scanRequest) (engine.ScanParams, error) {
 		ExcludeRigsWithShip:        req.ExcludeRigsWithShip,
 		CategoryIDs:                req.CategoryIDs,
 		SellOrderMode:              req.SellOrderMode,
+		NaivePricing:               req.NaivePricing,
 		RegionalDiagnosticMode:     req.RegionalDiagnosticMode,
 		IncludeStructures:          req.IncludeStructures,
+		UseWormholes:               req.UseWormholes,
+		UseAnsiblex:                req.UseAnsiblex,
+		JumpRangeLY:                req.JumpRangeLY,
+		JumpFatigueReduction:       req.JumpFatigueReduction,
+		JumpIsotopesPerLY:          req.JumpIsotopesPerLY,
+		JumpIsotopePrice:           jumpIsotopePrice,
+		FreightISKPerM3Jump:        req.FreightISKPerM3Jump,
+		FreightCollateralPercent:   req.FreightCollateralPercent,
+		ShipClass:                  req.ShipClass,
+		ShipAlignSeconds:           req.ShipAlignSeconds,
+		ShipWarpSpeedAU:            req.ShipWarpSpeedAU,
+		ShipEHP:                    req.ShipEHP,
+		GankSplitTrips:             req.GankSplitTrips,
+		FetchTimeout:               time.Duration(req.ScanTimeoutSec) * time.Second,
+		MaxRegionFailures:          maxRegionFailures,
 	}, nil
 }
 
@@ -2678,19 +3105,17 @@ func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	params, err := s.parseScanParams(req)
+	params, err := s.radiusScanParams(userID, req)
 	if err != nil {
 		writeError(w, 400, err.Error())
 		return
 	}
-	if req.IncludeStructures && s.sessions != nil {
-		if token, tokenErr := s.sessions.EnsureValidTokenForUser(s.sso, userID); tokenErr == nil {
-			params.AccessToken = token
-		}
-	}
 	scanTelemetry := scanRequestTelemetryProps(req)
 	s.trackScanStarted(r, "radius", scanTelemetry)
 
+	s.prefetchTradingLimits(userID)
+	ctx, endRun := s.beginScanRun(w, r)
+	defer endRun()
 	w.Header().Set("Content-Type", "application/x-ndjson")
 	w.Header().Set("Cache-Control", "no-cache")
 	flusher, ok := w.(http.Flusher)
@@ -2713,8 +3138,13 @@ func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
 
 	startTime := time.Now()
 
-	results, err := scanner.Scan(params, sendProgress)
+	params.FetchReport = &engine.RegionFetchReport{}
+	results, err := scanner.ScanWithContext(ctx, params, sendProgress)
 	if err != nil {
+		if isScanCanceled(err) {
+			log.Printf("[API] Scan canceled: %v", err)
+			return
+		}
 		log.Printf("[API] Scan error: %v", err)
 		s.trackScanFailed(r, "radius", err, scanTelemetry)
 		line, _ := json.Marshal(map[string]string{"type": "error", "message": err.Error()})
@@ -2733,6 +3163,7 @@ func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
 		results = filterFlipResultsExcludeStructures(results)
 	}
 	results = filterFlipResultsMarketDisabled(results)
+	s.applyFlipRouteGankRisk(results, params, s.userUniverse(userID), sendProgress)
 	if inventory := s.loadRegionalInventorySnapshot(
 		userID,
 		params.TargetRegionID,
@@ -2742,6 +3173,11 @@ func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
 	); inventory != nil {
 		engine.EnrichFlipResultsWithInventory(results, inventory)
 	}
+	s.annotateJitaReference(results)
+	s.annotateReferenceStation(userCfg, results)
+	if req.LPSignal {
+		s.annotateLPAnchors(results, req.LPFloorISKPerLP, req.LPCeilingISKPerLP)
+	}
 	cacheMeta := s.stationCacheMetaForFlipScan(
 		params,
 		false,
@@ -2769,11 +3205,14 @@ func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
 	go s.processWatchlistAlerts(userID, userCfg, results, scanIDPtr)
 
 	line, marshalErr := json.Marshal(map[string]interface{}{
-		"type":       "result",
-		"data":       results,
-		"count":      len(results),
-		"scan_id":    scanID,
-		"cache_meta": cacheMeta,
+		"type":            "result",
+		"data":            results,
+		"count":           len(results),
+		"scan_id":         scanID,
+		"cache_meta":      cacheMeta,
+		"partial":         params.FetchReport.Partial(),
+		"region_failures": params.FetchReport.Failures(),
+		"order_slots":     s.scanOrderSlotCheck(userID, len(results), 1),
 	})
 	if marshalErr != nil {
 		log.Printf("[API] Scan JSON marshal error: %v", marshalErr)
@@ -2795,12 +3234,26 @@ func (s *Server) handleScanMultiRegion(w http.ResponseWriter, r *http.Request) {
 		writeError(w, 400, "invalid json")
 		return
 	}
+	if err := s.applyScanShipProfile(userID, &req); err != nil {
+		writeError(w, 400, err.Error())
+		return
+	}
 
 	params, err := s.parseScanParams(req)
 	if err != nil {
 		writeError(w, 400, err.Error())
 		return
 	}
+	if req.UseWalletBudget {
+		params.MaxBudget = s.walletBudget(userID, params.MaxBudget)
+	}
+	params.BrokerFees = s.brokerFeeSchedule(userID)
+	params.StructureAccessFees = s.structureAccessFees(userID)
+	params.RoutePreferences = s.routePreferences(userID)
+	applyConfigFetchLimits(&params, s.loadConfigForUser(userID))
+	if params.UseAnsiblex {
+		params.AnsiblexGates = s.ansiblexEdges(userID)
+	}
 	if req.IncludeStructures && s.sessions != nil {
 		if token, tokenErr := s.sessions.EnsureValidTokenForUser(s.sso, userID); tokenErr == nil {
 			params.AccessToken = token
@@ -2809,6 +3262,9 @@ func (s *Server) handleScanMultiRegion(w http.ResponseWriter, r *http.Request) {
 	scanTelemetry := scanRequestTelemetryProps(req)
 	s.trackScanStarted(r, "region", scanTelemetry)
 
+	s.prefetchTradingLimits(userID)
+	ctx, endRun := s.beginScanRun(w, r)
+	defer endRun()
 	w.Header().Set("Content-Type", "application/x-ndjson")
 	w.Header().Set("Cache-Control", "no-cache")
 	flusher, ok := w.(http.Flusher)
@@ -2837,8 +3293,13 @@ func (s *Server) handleScanMultiRegion(w http.ResponseWriter, r *http.Request) {
 
 	startTime := time.Now()
 
-	results, err := scanner.ScanMultiRegion(params, sendProgress)
+	params.FetchReport = &engine.RegionFetchReport{}
+	results, err := scanner.ScanMultiRegionWithContext(ctx, params, sendProgress)
 	if err != nil {
+		if isScanCanceled(err) {
+			log.Printf("[API] ScanMultiRegion canceled: %v", err)
+			return
+		}
 		log.Printf("[API] ScanMultiRegion error: %v", err)
 		s.trackScanFailed(r, "region", err, scanTelemetry)
 		line, _ := json.Marshal(map[string]string{"type": "error", "message": err.Error()})
@@ -2857,6 +3318,7 @@ func (s *Server) handleScanMultiRegion(w http.ResponseWriter, r *http.Request) {
 		results = filterFlipResultsExcludeStructures(results)
 	}
 	results = filterFlipResultsMarketDisabled(results)
+	s.applyFlipRouteGankRisk(results, params, s.userUniverse(userID), sendProgress)
 	if inventory := s.loadRegionalInventorySnapshot(
 		userID,
 		params.TargetRegionID,
@@ -2866,6 +3328,11 @@ func (s *Server) handleScanMultiRegion(w http.ResponseWriter, r *http.Request) {
 	); inventory != nil {
 		engine.EnrichFlipResultsWithInventory(results, inventory)
 	}
+	s.annotateJitaReference(results)
+	s.annotateReferenceStation(userCfg, results)
+	if req.LPSignal {
+		s.annotateLPAnchors(results, req.LPFloorISKPerLP, req.LPCeilingISKPerLP)
+	}
 	cacheMeta := s.stationCacheMetaForFlipScan(
 		params,
 		true,
@@ -2893,11 +3360,14 @@ func (s *Server) handleScanMultiRegion(w http.ResponseWriter, r *http.Request) {
 	go s.processWatchlistAlerts(userID, userCfg, results, scanIDPtr)
 
 	line, marshalErr := json.Marshal(map[string]interface{}{
-		"type":       "result",
-		"data":       results,
-		"count":      len(results),
-		"scan_id":    scanID,
-		"cache_meta": cacheMeta,
+		"type":            "result",
+		"data":            results,
+		"count":           len(results),
+		"scan_id":         scanID,
+		"cache_meta":      cacheMeta,
+		"partial":         params.FetchReport.Partial(),
+		"region_failures": params.FetchReport.Failures(),
+		"order_slots":     s.scanOrderSlotCheck(userID, len(results), 1),
 	})
 	if marshalErr != nil {
 		log.Printf("[API] ScanMultiRegion JSON marshal error: %v", marshalErr)
@@ -2919,12 +3389,26 @@ func (s *Server) handleScanRegionalDay(w http.ResponseWriter, r *http.Request) {
 		writeError(w, 400, "invalid json")
 		return
 	}
+	if err := s.applyScanShipProfile(userID, &req); err != nil {
+		writeError(w, 400, err.Error())
+		return
+	}
 
 	params, err := s.parseScanParams(req)
 	if err != nil {
 		writeError(w, 400, err.Error())
 		return
 	}
+	if req.UseWalletBudget {
+		params.MaxBudget = s.walletBudget(userID, params.MaxBudget)
+	}
+	params.BrokerFees = s.brokerFeeSchedule(userID)
+	params.StructureAccessFees = s.structureAccessFees(userID)
+	params.RoutePreferences = s.routePreferences(userID)
+	applyConfigFetchLimits(&params, s.loadConfigForUser(userID))
+	if params.UseAnsiblex {
+		params.AnsiblexGates = s.ansiblexEdges(userID)
+	}
 	if req.IncludeStructures && s.sessions != nil {
 		if token, tokenErr := s.sessions.EnsureValidTokenForUser(s.sso, userID); tokenErr == nil {
 			params.AccessToken = token
@@ -2937,6 +3421,9 @@ func (s *Server) handleScanRegionalDay(w http.ResponseWriter, r *http.Request) {
 	scanTelemetry := scanRequestTelemetryProps(req)
 	s.trackScanStarted(r, "regional_day", scanTelemetry)
 
+	s.prefetchTradingLimits(userID)
+	ctx, endRun := s.beginScanRun(w, r)
+	defer endRun()
 	w.Header().Set("Content-Type", "application/x-ndjson")
 	w.Header().Set("Cache-Control", "no-cache")
 	flusher, ok := w.(http.Flusher)
@@ -2967,14 +3454,19 @@ func (s *Server) handleScanRegionalDay(w http.ResponseWriter, r *http.Request) {
 
 	startTime := time.Now()
 
+	params.FetchReport = &engine.RegionFetchReport{}
 	scanParams := params
 	if params.RegionalDiagnosticMode {
 		scanParams.MinMargin = 0
 		scanParams.MaxInvestment = 0
 		scanParams.MinDailyVolume = 0
 	}
-	results, err := scanner.ScanMultiRegion(scanParams, sendProgress)
+	results, err := scanner.ScanMultiRegionWithContext(ctx, scanParams, sendProgress)
 	if err != nil {
+		if isScanCanceled(err) {
+			log.Printf("[API] ScanRegionalDay canceled: %v", err)
+			return
+		}
 		log.Printf("[API] ScanRegionalDay error: %v", err)
 		s.trackScanFailed(r, "regional_day", err, scanTelemetry)
 		line, _ := json.Marshal(map[string]string{"type": "error", "message": err.Error()})
@@ -3000,6 +3492,9 @@ func (s *Server) handleScanRegionalDay(w http.ResponseWriter, r *http.Request) {
 	)
 	hubs, totalItems, targetRegionName, periodDays := scanner.BuildRegionalDayTrader(params, results, inventory, sendProgress)
 	dayRows := engine.FlattenRegionalDayHubs(hubs)
+	engine.ApplyCargoLogistics(dayRows, params.CargoCapacity)
+	s.applyFlipRouteGankRisk(dayRows, params, s.userUniverse(userID), sendProgress)
+	s.annotateReferenceStation(userCfg, dayRows)
 
 	durationMs := time.Since(startTime).Milliseconds()
 	log.Printf("[API] ScanRegionalDay complete: hubs=%d items=%d rows=%d raw=%d in %dms",
@@ -3068,6 +3563,9 @@ func (s *Server) handleScanRegionalDay(w http.ResponseWriter, r *http.Request) {
 		"cache_meta":         cacheMeta,
 		"target_region_name": targetRegionName,
 		"period_days":        periodDays,
+		"partial":            params.FetchReport.Partial(),
+		"region_failures":    params.FetchReport.Failures(),
+		"order_slots":        s.scanOrderSlotCheck(userID, len(dayRows), 1),
 	})
 	if marshalErr != nil {
 		log.Printf("[API] ScanRegionalDay JSON marshal error: %v", marshalErr)
@@ -3278,20 +3776,35 @@ func (s *Server) matchesRegionByLocationID(locationID int64, regionID int32) boo
 }
 
 func (s *Server) handleScanContracts(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
 	var req scanRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, 400, "invalid json")
 		return
 	}
+	if err := s.applyScanShipProfile(userID, &req); err != nil {
+		writeError(w, 400, err.Error())
+		return
+	}
 
 	params, err := s.parseScanParams(req)
 	if err != nil {
 		writeError(w, 400, err.Error())
 		return
 	}
+	if req.UseWalletBudget {
+		params.MaxBudget = s.walletBudget(userIDFromRequest(r), params.MaxBudget)
+	}
+	params.RoutePreferences = s.routePreferences(userID)
+	applyConfigFetchLimits(&params, s.loadConfigForUser(userID))
+	if params.UseAnsiblex {
+		params.AnsiblexGates = s.ansiblexEdges(userIDFromRequest(r))
+	}
 	scanTelemetry := scanRequestTelemetryProps(req)
 	s.trackScanStarted(r, "contracts", scanTelemetry)
 
+	ctx, endRun := s.beginScanRun(w, r)
+	defer endRun()
 	w.Header().Set("Content-Type", "application/x-ndjson")
 	w.Header().Set("Cache-Control", "no-cache")
 	flusher, ok := w.(http.Flusher)
@@ -3306,9 +3819,9 @@ func (s *Server) handleScanContracts(w http.ResponseWriter, r *http.Request) {
 	log.Printf("[API] ScanContracts starting: system=%d, buyR=%d, margin=%.1f, tax=%.1f",
 		params.CurrentSystemID, params.BuyRadius, params.MinMargin, params.SalesTaxPercent)
 
-	ctx := r.Context()
 	startTime := time.Now()
 
+	params.FetchReport = &engine.RegionFetchReport{}
 	results, err := scanner.ScanContractsWithContext(ctx, params, func(msg string) {
 		if ctx.Err() != nil {
 			return
@@ -3320,7 +3833,7 @@ func (s *Server) handleScanContracts(w http.ResponseWriter, r *http.Request) {
 		flusher.Flush()
 	})
 	if err != nil {
-		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		if isScanCanceled(err) {
 			log.Printf("[API] ScanContracts canceled: %v", err)
 			return
 		}
@@ -3337,6 +3850,7 @@ func (s *Server) handleScanContracts(w http.ResponseWriter, r *http.Request) {
 
 	durationMs := time.Since(startTime).Milliseconds()
 	results = s.filterContractResultsMarketDisabled(results)
+	s.annotateContractReference(s.loadConfigForUser(userID), results)
 	log.Printf("[API] ScanContracts complete: %d results in %dms", len(results), durationMs)
 	regionIDs := s.regionScopeForContractScan(params)
 	cacheMeta := s.stationCacheMetaForRegions(regionIDs)
@@ -3359,11 +3873,13 @@ func (s *Server) handleScanContracts(w http.ResponseWriter, r *http.Request) {
 	}
 
 	line, marshalErr := json.Marshal(map[string]interface{}{
-		"type":       "result",
-		"data":       results,
-		"count":      len(results),
-		"scan_id":    scanID,
-		"cache_meta": cacheMeta,
+		"type":            "result",
+		"data":            results,
+		"count":           len(results),
+		"scan_id":         scanID,
+		"cache_meta":      cacheMeta,
+		"partial":         params.FetchReport.Partial(),
+		"region_failures": params.FetchReport.Failures(),
 	})
 	if marshalErr != nil {
 		log.Printf("[API] ScanContracts JSON marshal error: %v", marshalErr)
@@ -3392,9 +3908,14 @@ func (s *Server) handleRouteFind(w http.ResponseWriter, r *http.Request) {
 		RouteMinutesPerJump  float64 `json:"route_minutes_per_jump"`
 		RouteDockMinutes     float64 `json:"route_dock_minutes"`
 		RouteSafetyDelayPct  float64 `json:"route_safety_delay_percent"`
+		RouteAlignSeconds    float64 `json:"route_align_seconds"`
+		RouteWarpSpeedAU     float64 `json:"route_warp_speed_au"`
+		RouteShipProfileID   int64   `json:"route_ship_profile_id"`
 		RouteMode            string  `json:"route_mode"`
 		MinMargin            float64 `json:"min_margin"`
 		MinISKPerJump        float64 `json:"min_isk_per_jump"`
+		MaxBudget            float64 `json:"max_budget"`
+		UseWalletBudget      bool    `json:"use_wallet_budget"`
 		SalesTaxPercent      float64 `json:"sales_tax_percent"`
 		BrokerFeePercent     float64 `json:"broker_fee_percent"`
 		SplitTradeFees       bool    `json:"split_trade_fees"`
@@ -3407,6 +3928,8 @@ func (s *Server) handleRouteFind(w http.ResponseWriter, r *http.Request) {
 		MinRouteSecurity     float64 `json:"min_route_security"` // 0 = all; 0.45 = highsec only; 0.7 = min 0.7
 		AllowEmptyHops       bool    `json:"allow_empty_hops"`
 		IncludeStructures    bool    `json:"include_structures"`
+		UseWormholes         bool    `json:"use_wormholes"`
+		UseAnsiblex          bool    `json:"use_ansiblex"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, 400, "invalid json")
@@ -3432,7 +3955,23 @@ func (s *Server) handleRouteFind(w http.ResponseWriter, r *http.Request) {
 	if req.MaxHops > 25 {
 		req.MaxHops = 25
 	}
+	tankFactor := 1.0
+	if req.RouteShipProfileID > 0 {
+		profile, err := s.shipProfileForUser(userID, req.RouteShipProfileID)
+		if err != nil {
+			writeError(w, 400, err.Error())
+			return
+		}
+		req.CargoCapacity = profile.CargoM3
+		req.RouteCargoCapacity = profile.CargoM3
+		req.RouteShipProfile = profile.ShipClass
+		req.RouteAlignSeconds = profile.AlignSeconds
+		req.RouteWarpSpeedAU = profile.WarpSpeedAU
+		tankFactor = shipTankRiskFactor(profile.EHP)
+	}
 
+	ctx, endRun := s.beginScanRun(w, r)
+	defer endRun()
 	w.Header().Set("Content-Type", "application/x-ndjson")
 	w.Header().Set("Cache-Control", "no-cache")
 	flusher, ok := w.(http.Flusher)
@@ -3465,9 +4004,12 @@ func (s *Server) handleRouteFind(w http.ResponseWriter, r *http.Request) {
 		RouteMinutesPerJump:     req.RouteMinutesPerJump,
 		RouteDockMinutes:        req.RouteDockMinutes,
 		RouteSafetyDelayPercent: req.RouteSafetyDelayPct,
+		RouteAlignSeconds:       req.RouteAlignSeconds,
+		RouteWarpSpeedAU:        req.RouteWarpSpeedAU,
 		RouteMode:               req.RouteMode,
 		MinMargin:               req.MinMargin,
 		MinISKPerJump:           req.MinISKPerJump,
+		MaxBudget:               req.MaxBudget,
 		SalesTaxPercent:         req.SalesTaxPercent,
 		BrokerFeePercent:        req.BrokerFeePercent,
 		SplitTradeFees:          req.SplitTradeFees,
@@ -3480,6 +4022,16 @@ func (s *Server) handleRouteFind(w http.ResponseWriter, r *http.Request) {
 		MinRouteSecurity:        req.MinRouteSecurity,
 		AllowEmptyHops:          req.AllowEmptyHops,
 		IncludeStructures:       req.IncludeStructures,
+		UseWormholes:            req.UseWormholes,
+		UseAnsiblex:             req.UseAnsiblex,
+	}
+	if req.UseWalletBudget {
+		params.MaxBudget = s.walletBudget(userID, params.MaxBudget)
+	}
+	params.BrokerFees = s.brokerFeeSchedule(userID)
+	params.RoutePreferences = s.routePreferences(userID)
+	if params.UseAnsiblex {
+		params.AnsiblexGates = s.ansiblexEdges(userID)
 	}
 
 	log.Printf(
@@ -3504,8 +4056,12 @@ func (s *Server) handleRouteFind(w http.ResponseWriter, r *http.Request) {
 	s.trackScanStarted(r, "route", routeTelemetry)
 
 	startTime := time.Now()
-	results, err := scanner.FindRoutes(params, sendProgress)
+	results, err := scanner.FindRoutesWithContext(ctx, params, sendProgress)
 	if err != nil {
+		if isScanCanceled(err) {
+			log.Printf("[API] RouteFind canceled: %v", err)
+			return
+		}
 		log.Printf("[API] RouteFind error: %v", err)
 		s.trackScanFailed(r, "route", err, routeTelemetry)
 		line, _ := json.Marshal(map[string]string{"type": "error", "message": err.Error()})
@@ -3526,7 +4082,7 @@ func (s *Server) handleRouteFind(w http.ResponseWriter, r *http.Request) {
 		results = filterRouteResultsExcludeStructures(results)
 	}
 	results = filterRouteResultsMarketDisabled(results)
-	results = s.enrichRouteHaulingRisk(results, req.SystemName, req.TargetSystemName, req.MinRouteSecurity, sendProgress)
+	results = s.enrichRouteHaulingRisk(results, req.SystemName, req.TargetSystemName, req.MinRouteSecurity, s.userUniverse(userID), tankFactor, sendProgress)
 	engine.EnrichRouteExecutionEstimatesWithProfile(results, engine.RouteExecutionProfileFromParams(params))
 	engine.SortRouteResultsByMode(results, req.RouteMode)
 	if len(results) != rawCount {
@@ -3618,6 +4174,7 @@ func (s *Server) handleAddWatchlist(w http.ResponseWriter, r *http.Request) {
 	if item.AlertThreshold > 0 && !item.AlertEnabled {
 		item.AlertEnabled = true
 	}
+	item.Group = normalizeWatchlistGroup(item.Group)
 
 	item.AddedAt = time.Now().Format(time.RFC3339)
 	inserted := s.db.AddWatchlistItemForUser(userID, item)
@@ -3675,11 +4232,25 @@ func (s *Server) handleUpdateWatchlist(w http.ResponseWriter, r *http.Request) {
 		AlertEnabled   bool    `json:"alert_enabled"`
 		AlertMetric    string  `json:"alert_metric"`
 		AlertThreshold float64 `json:"alert_threshold"`
+		Group          *string `json:"group"` // nil leaves the group unchanged
+		// Price band; nil leaves that side unchanged, 0 turns it off.
+		TargetBuyBelow  *float64 `json:"target_buy_below"`
+		TargetSellAbove *float64 `json:"target_sell_above"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+	raw, err := io.ReadAll(r.Body)
+	if err != nil || json.Unmarshal(raw, &body) != nil {
 		writeError(w, 400, "invalid json")
 		return
 	}
+	// A body with only band or group fields leaves the alert settings alone.
+	var keys map[string]json.RawMessage
+	json.Unmarshal(raw, &keys)
+	alertPatch := len(keys) == 0
+	for _, k := range []string{"alert_min_margin", "alert_enabled", "alert_metric", "alert_threshold"} {
+		if _, ok := keys[k]; ok {
+			alertPatch = true
+		}
+	}
 
 	switch body.AlertMetric {
 	case "", "margin_percent", "total_profit", "profit_per_unit", "daily_volume":
@@ -3692,6 +4263,10 @@ func (s *Server) handleUpdateWatchlist(w http.ResponseWriter, r *http.Request) {
 		writeError(w, 400, "alert_threshold must be >= 0")
 		return
 	}
+	if (body.TargetBuyBelow != nil && *body.TargetBuyBelow < 0) || (body.TargetSellAbove != nil && *body.TargetSellAbove < 0) {
+		writeError(w, 400, "price targets must be >= 0")
+		return
+	}
 
 	alertMetric := body.AlertMetric
 	if alertMetric == "" {
@@ -3707,7 +4282,34 @@ func (s *Server) handleUpdateWatchlist(w http.ResponseWriter, r *http.Request) {
 		alertEnabled = true
 	}
 
-	s.db.UpdateWatchlistItemForUser(userID, int32(id), body.AlertMinMargin, alertEnabled, alertMetric, alertThreshold)
+	if alertPatch {
+		s.db.UpdateWatchlistItemForUser(userID, int32(id), body.AlertMinMargin, alertEnabled, alertMetric, alertThreshold)
+	}
+	if body.TargetBuyBelow != nil || body.TargetSellAbove != nil {
+		var current config.WatchlistItem
+		for _, it := range s.db.GetWatchlistForUser(userID) {
+			if it.TypeID == int32(id) {
+				current = it
+				break
+			}
+		}
+		if body.TargetBuyBelow != nil {
+			current.TargetBuyBelow = *body.TargetBuyBelow
+		}
+		if body.TargetSellAbove != nil {
+			current.TargetSellAbove = *body.TargetSellAbove
+		}
+		if err := s.db.UpdateWatchlistPriceBandForUser(userID, int32(id), current.TargetBuyBelow, current.TargetSellAbove); err != nil {
+			writeError(w, 500, "failed to update price band")
+			return
+		}
+	}
+	if body.Group != nil {
+		if _, err := s.db.SetWatchlistGroupForUser(userID, []int32{int32(id)}, normalizeWatchlistGroup(*body.Group)); err != nil {
+			writeError(w, 500, "failed to update watchlist group")
+			return
+		}
+	}
 	items := s.db.GetWatchlistForUser(userID)
 	filtered := make([]config.WatchlistItem, 0, len(items))
 	for _, it := range items {
@@ -3806,6 +4408,9 @@ func (s *Server) handleScanStation(w http.ResponseWriter, r *http.Request) {
 		MaxSDS             int     `json:"max_sds"`
 		LimitBuyToPriceLow bool    `json:"limit_buy_to_price_low"`
 		FlagExtremePrices  bool    `json:"flag_extreme_prices"`
+		// Capital
+		MaxBudget       float64 `json:"max_budget"`        // 0 = unlimited
+		UseWalletBudget bool    `json:"use_wallet_budget"` // cap MaxBudget by the logged-in character's wallet
 		// Player structures
 		IncludeStructures bool    `json:"include_structures"`
 		StructureIDs      []int64 `json:"structure_ids"`
@@ -3831,6 +4436,7 @@ func (s *Server) handleScanStation(w http.ResponseWriter, r *http.Request) {
 	scanner := s.scanner
 	s.mu.RUnlock()
 
+	s.prefetchTradingLimits(userID)
 	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
 	streamAlive := true
@@ -3872,7 +4478,7 @@ func (s *Server) handleScanStation(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		runtimeMarketSystemID = systemID
-		systems := sdeData.Universe.SystemsWithinRadius(systemID, req.Radius)
+		systems := sdeData.Universe.WithRoutePreferences(s.routePreferences(userID)).SystemsWithinRadius(systemID, req.Radius)
 		for ignoredID := range ignoredSystems {
 			delete(systems, ignoredID)
 		}
@@ -3927,6 +4533,14 @@ func (s *Server) handleScanStation(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	brokerFees := s.brokerFeeSchedule(userID)
+	routePrefs := s.routePreferences(userID)
+	maxBudget := req.MaxBudget
+	if req.UseWalletBudget {
+		maxBudget = s.walletBudget(userID, maxBudget)
+	}
+	fetchTimeout, maxRegionFailures := configFetchLimits(userCfg)
+	fetchReport := &engine.RegionFetchReport{}
 	startTime := time.Now()
 
 	// Scan each region and merge results
@@ -3950,6 +4564,7 @@ func (s *Server) handleScanStation(w http.ResponseWriter, r *http.Request) {
 			BuySalesTaxPercent:   req.BuySalesTaxPercent,
 			SellSalesTaxPercent:  req.SellSalesTaxPercent,
 			MinDailyVolume:       req.MinDailyVolume,
+			MaxBudget:            maxBudget,
 			MinItemProfit:        req.MinItemProfit,
 			MinDemandPerDay:      req.MinDemandPerDay,
 			MinS2BPerDay:         req.MinS2BPerDay,
@@ -3964,7 +4579,11 @@ func (s *Server) handleScanStation(w http.ResponseWriter, r *http.Request) {
 			FlagExtremePrices:    req.FlagExtremePrices,
 			AccessToken:          accessToken,
 			IncludeStructures:    req.IncludeStructures,
+			BrokerFees:           brokerFees,
+			RoutePreferences:     routePrefs,
 			Ctx:                  ctx,
+			FetchTimeout:         fetchTimeout,
+			FetchReport:          fetchReport,
 		}
 		// In all-stations mode keep StationIDs nil so the engine evaluates full region scope.
 		if allStationsMode {
@@ -3991,6 +4610,14 @@ func (s *Server) handleScanStation(w http.ResponseWriter, r *http.Request) {
 	if ctx.Err() != nil || !streamAlive {
 		return
 	}
+	if err := fetchReport.Check(maxRegionFailures, progressFn); err != nil {
+		log.Printf("[API] ScanStation error: %v", err)
+		s.trackScanFailed(r, "station", err, scanTelemetry)
+		line, _ := json.Marshal(map[string]string{"type": "error", "message": err.Error()})
+		_, _ = fmt.Fprintf(w, "%s\n", line)
+		flusher.Flush()
+		return
+	}
 
 	durationMs := time.Since(startTime).Milliseconds()
 	log.Printf("[API] ScanStation complete: %d results in %dms", len(allResults), durationMs)
@@ -4011,6 +4638,7 @@ func (s *Server) handleScanStation(w http.ResponseWriter, r *http.Request) {
 	); inventory != nil {
 		engine.EnrichStationTradesWithInventory(allResults, inventory)
 	}
+	s.annotateStationTradeReference(userCfg, allResults)
 
 	// Calculate totals
 	topProfit := 0.0
@@ -4038,11 +4666,14 @@ func (s *Server) handleScanStation(w http.ResponseWriter, r *http.Request) {
 	go s.processWatchlistAlerts(userID, userCfg, allResults, scanIDPtr)
 
 	line, marshalErr := json.Marshal(map[string]interface{}{
-		"type":       "result",
-		"data":       allResults,
-		"count":      len(allResults),
-		"scan_id":    scanID,
-		"cache_meta": cacheMeta,
+		"type":            "result",
+		"data":            allResults,
+		"count":           len(allResults),
+		"scan_id":         scanID,
+		"cache_meta":      cacheMeta,
+		"partial":         fetchReport.Partial(),
+		"region_failures": fetchReport.Failures(),
+		"order_slots":     s.scanOrderSlotCheck(userID, len(allResults), 2),
 	})
 	if marshalErr != nil {
 		log.Printf("[API] ScanStation JSON marshal error: %v", marshalErr)
@@ -4299,11 +4930,12 @@ func filterExecutionPlanOrders(orders []esi.MarketOrder, typeID int32, systemID
 
 type executionPlanAPIRequest struct {
 	TypeID                int32   `json:"type_id"`
-	RegionID              int32   `json:"region_id"`
+	RegionID              int32   `json:"region_id"` // 0 = derived from location_id
 	SystemID              int32   `json:"system_id"`
 	LocationID            int64   `json:"location_id"` // 0 = whole region
 	Quantity              int32   `json:"quantity"`
 	IsBuy                 bool    `json:"is_buy"`
+	Side                  string  `json:"side"`        // "buy" | "sell"; overrides is_buy when set
 	ImpactDays            int     `json:"impact_days"` // 0 = use engine default (e.g. 30)
 	IncludeQuote          bool    `json:"include_quote"`
 	Quote                 bool    `json:"quote"`
@@ -4521,14 +5153,66 @@ func (s *Server) buildExecutionQuote(r *http.Request, req executionPlanAPIReques
 	return quote, nil
 }
 
+// executionSide resolves an execution request's "buy"/"sell" side, falling
+// back to isBuy when side is empty. It reports false for any other value.
+func executionSide(side string, isBuy bool) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(side)) {
+	case "":
+		return isBuy, true
+	case "buy":
+		return true, true
+	case "sell":
+		return false, true
+	}
+	return false, false
+}
+
+// executionLocationRegion returns the system and region of a station or
+// known structure, or zeros before the SDE is loaded.
+func (s *Server) executionLocationRegion(locationID int64) (int32, int32) {
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	if sdeData == nil {
+		return 0, 0
+	}
+	return s.locationSystemRegion(sdeData, locationID)
+}
+
+// executionOrdersErrorStatus maps a fetchExecutionOrders error to an HTTP
+// status.
+func executionOrdersErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "authenticated") || strings.Contains(msg, "refresh character token"):
+		return 401
+	case strings.Contains(msg, "character_id") || strings.Contains(msg, "scope"):
+		return 400
+	}
+	return 502
+}
+
 func (s *Server) handleExecutionPlan(w http.ResponseWriter, r *http.Request) {
 	var req executionPlanAPIRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, 400, "invalid json")
 		return
 	}
+	isBuy, ok := executionSide(req.Side, req.IsBuy)
+	if !ok {
+		writeError(w, 400, "side must be buy or sell")
+		return
+	}
+	req.IsBuy = isBuy
+	if req.RegionID == 0 && req.LocationID != 0 {
+		systemID, regionID := s.executionLocationRegion(req.LocationID)
+		req.RegionID = regionID
+		if req.SystemID == 0 {
+			req.SystemID = systemID
+		}
+	}
 	if req.RegionID == 0 || req.TypeID == 0 || req.Quantity <= 0 {
-		writeError(w, 400, "region_id, type_id and positive quantity required")
+		writeError(w, 400, "region_id (or a known location_id), type_id and positive quantity required")
 		return
 	}
 
@@ -4539,14 +5223,7 @@ func (s *Server) handleExecutionPlan(w http.ResponseWriter, r *http.Request) {
 	}
 	orders, err := s.fetchExecutionOrders(r, req.RegionID, req.LocationID, orderType)
 	if err != nil {
-		status := 502
-		msg := err.Error()
-		if strings.Contains(msg, "authenticated") || strings.Contains(msg, "refresh character token") {
-			status = 401
-		} else if strings.Contains(msg, "character_id") || strings.Contains(msg, "scope") {
-			status = 400
-		}
-		writeError(w, status, msg)
+		writeError(w, executionOrdersErrorStatus(err), err.Error())
 		return
 	}
 
@@ -4572,29 +5249,23 @@ func (s *Server) handleExecutionPlan(w http.ResponseWriter, r *http.Request) {
 
 	// When market history is available, add impact calibration (Amihud, σ, TWAP slices)
 	if s.db != nil {
-		history, ok := s.db.GetMarketHistory(req.RegionID, req.TypeID)
-		if !ok {
-			entries, err := s.esi.FetchMarketHistory(req.RegionID, req.TypeID)
-			if err == nil && len(entries) > 0 {
-				s.db.SetMarketHistory(req.RegionID, req.TypeID, entries)
-				history = entries
-			}
+		history, _ := s.cachedMarketHistory(req.RegionID, req.TypeID)
+		impactDays := req.ImpactDays
+		if impactDays <= 0 {
+			impactDays = engine.DefaultImpactDays
 		}
-		if len(history) >= 5 {
-			impactDays := req.ImpactDays
-			if impactDays <= 0 {
-				impactDays = engine.DefaultImpactDays
-			}
-			if impactDays > 365 {
-				impactDays = 365
-			}
-			params := engine.CalibrateImpact(history, impactDays)
-			if params.Valid {
-				// Use best price from execution plan as reference for ISK conversion
-				refPrice := result.BestPrice
-				est := engine.EstimateImpact(params, float64(req.Quantity), refPrice)
-				result.Impact = &est
-			}
+		if impactDays > 365 {
+			impactDays = 365
+		}
+		params := engine.CalibrateImpact(history, impactDays)
+		if stored, ok := s.db.GetImpactCoefficients(req.RegionID, req.TypeID); ok {
+			params = withImpactFit(params, stored)
+		}
+		if params.Valid {
+			// Use best price from execution plan as reference for ISK conversion
+			refPrice := result.BestPrice
+			est := engine.EstimateImpact(params, float64(req.Quantity), refPrice)
+			result.Impact = &est
 		}
 	}
 
@@ -4661,6 +5332,7 @@ func (s *Server) regionalDayParamsFromHistory(record *db.ScanRecord) (engine.Sca
 		SellSalesTaxPercent:    req.SellSalesTaxPercent,
 		MinDailyVolume:         req.MinDailyVolume,
 		MaxInvestment:          req.MaxInvestment,
+		MaxBudget:              req.MaxBudget,
 		MinItemProfit:          req.MinItemProfit,
 		MinPeriodROI:           req.MinPeriodROI,
 		MaxDOS:                 req.MaxDOS,
@@ -6593,7 +7265,7 @@ func (s *Server) handleAuthSyncIndustryProjectBlueprintPool(w http.ResponseWrite
 	s.mu.RLock()
 	sdeData := s.sdeData
 	s.mu.RUnlock()
-	if sdeData == nil || sdeData.Industry == nil {
+	if sdeData == nil || sdeData.IndustryData() == nil {
 		writeError(w, 503, "industry data not ready")
 		return
 	}
@@ -6654,7 +7326,7 @@ func (s *Server) handleAuthSyncIndustryProjectBlueprintPool(w http.ResponseWrite
 		if typeID <= 0 {
 			return
 		}
-		if _, ok := sdeData.Industry.Blueprints[typeID]; !ok {
+		if _, ok := sdeData.IndustryData().Blueprints[typeID]; !ok {
 			return
 		}
 		if quantity <= 0 {
@@ -7261,12 +7933,20 @@ func (s *Server) handleAuthRebootStationCache(w http.ResponseWriter, r *http.Req
 }
 
 func (s *Server) handleAuthOrderDesk(w http.ResponseWriter, r *http.Request) {
+	if desk, ok := s.orderDeskForRequest(w, r); ok {
+		writeJSON(w, desk)
+	}
+}
+
+// orderDeskForRequest loads the order desk for the request's auth scope and
+// query options. On failure it writes the error response and returns false.
+func (s *Server) orderDeskForRequest(w http.ResponseWriter, r *http.Request) (engine.OrderDeskResponse, bool) {
 	userID := userIDFromRequest(r)
 
 	characterID, allScope, err := parseAuthScope(r)
 	if err != nil {
 		writeError(w, 400, err.Error())
-		return
+		return engine.OrderDeskResponse{}, false
 	}
 	selectedSessions, err := s.authSessionsForScope(userID, characterID, allScope, true)
 	if err != nil {
@@ -7275,7 +7955,7 @@ func (s *Server) handleAuthOrderDesk(w http.ResponseWriter, r *http.Request) {
 		} else {
 			writeError(w, 400, err.Error())
 		}
-		return
+		return engine.OrderDeskResponse{}, false
 	}
 
 	salesTax := 8.0
@@ -7300,14 +7980,20 @@ func (s *Server) handleAuthOrderDesk(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// ?corp=true adds corporation orders for characters with a role that
+	// can read them.
+	includeCorp := r.URL.Query().Get("corp") == "true"
+	seenCorps := make(map[int32]bool)
+
 	var orders []esi.CharacterOrder
+	var tradingLimits []engine.TradingLimits
 	for _, sess := range selectedSessions {
 		token, tokenErr := s.sessions.EnsureValidTokenForUserCharacter(s.sso, userID, sess.CharacterID)
 		if tokenErr != nil {
 			log.Printf("[AUTH] OrderDesk token error (%s): %v", sess.CharacterName, tokenErr)
 			if !allScope {
 				writeError(w, 401, tokenErr.Error())
-				return
+				return engine.OrderDeskResponse{}, false
 			}
 			continue
 		}
@@ -7316,21 +8002,39 @@ func (s *Server) handleAuthOrderDesk(w http.ResponseWriter, r *http.Request) {
 			log.Printf("[AUTH] OrderDesk orders error (%s): %v", sess.CharacterName, fetchErr)
 			if !allScope {
 				writeError(w, 500, "failed to fetch orders: "+fetchErr.Error())
-				return
+				return engine.OrderDeskResponse{}, false
 			}
 			continue
 		}
 		orders = append(orders, charOrders...)
+		if limits, ok := s.characterTradingLimits(sess, token, len(charOrders)); ok {
+			tradingLimits = append(tradingLimits, limits)
+		}
+		if includeCorp {
+			orders = append(orders, s.corpOrdersForDesk(sess, token, seenCorps)...)
+		}
 	}
+	if includeCorp {
+		// Corp orders placed by a selected character are listed by both endpoints.
+		orders = dedupeCharacterOrders(orders)
+	}
+
+	desk := s.computeOrderDesk(r.Context(), orders, engine.OrderDeskOptions{
+		SalesTaxPercent:  salesTax,
+		BrokerFeePercent: brokerFee,
+		TargetETADays:    targetETADays,
+		WarnExpiryDays:   2,
+		RepriceStrategy:  r.URL.Query().Get("reprice_strategy"),
+	})
+	desk.SetTradingLimits(tradingLimits)
+	return desk, true
+}
 
+// computeOrderDesk names orders, loads the regional books and cached history
+// of every (region, type) they touch and runs ComputeOrderDesk.
+func (s *Server) computeOrderDesk(ctx context.Context, orders []esi.CharacterOrder, opts engine.OrderDeskOptions) engine.OrderDeskResponse {
 	if len(orders) == 0 {
-		writeJSON(w, engine.ComputeOrderDesk(nil, nil, nil, nil, engine.OrderDeskOptions{
-			SalesTaxPercent:  salesTax,
-			BrokerFeePercent: brokerFee,
-			TargetETADays:    targetETADays,
-			WarnExpiryDays:   2,
-		}))
-		return
+		return engine.ComputeOrderDesk(nil, nil, nil, nil, opts)
 	}
 
 	// Enrich names for UI readability.
@@ -7376,23 +8080,10 @@ func (s *Server) handleAuthOrderDesk(w http.ResponseWriter, r *http.Request) {
 			defer wg.Done()
 
 			sem <- struct{}{}
-			ro, fetchErr := s.esi.FetchRegionOrdersByTypeContext(r.Context(), rt.regionID, rt.typeID)
+			ro, fetchErr := s.esi.FetchRegionOrdersByTypeContext(ctx, rt.regionID, rt.typeID)
 			<-sem
 
-			var entries []esi.HistoryEntry
-			var ok bool
-			if s.db != nil {
-				entries, ok = s.db.GetMarketHistory(rt.regionID, rt.typeID)
-			}
-			if !ok {
-				fresh, histErr := s.esi.FetchMarketHistory(rt.regionID, rt.typeID)
-				if histErr == nil {
-					entries = fresh
-					if s.db != nil && len(entries) > 0 {
-						s.db.SetMarketHistory(rt.regionID, rt.typeID, entries)
-					}
-				}
-			}
+			entries, _ := s.cachedMarketHistory(rt.regionID, rt.typeID)
 
 			mu.Lock()
 			books[rt] = fetchResult{orders: ro, err: fetchErr}
@@ -7414,13 +8105,7 @@ func (s *Server) handleAuthOrderDesk(w http.ResponseWriter, r *http.Request) {
 		unavailableBooks[engine.NewOrderDeskHistoryKey(rt.regionID, rt.typeID)] = true
 	}
 
-	result := engine.ComputeOrderDesk(orders, allRegional, history, unavailableBooks, engine.OrderDeskOptions{
-		SalesTaxPercent:  salesTax,
-		BrokerFeePercent: brokerFee,
-		TargetETADays:    targetETADays,
-		WarnExpiryDays:   2,
-	})
-	writeJSON(w, result)
+	return engine.ComputeOrderDesk(orders, allRegional, history, unavailableBooks, opts)
 }
 
 func (s *Server) handleAuthStationCommand(w http.ResponseWriter, r *http.Request) {
@@ -7508,7 +8193,7 @@ func (s *Server) handleAuthStationCommand(w http.ResponseWriter, r *http.Request
 			writeError(w, 400, "unknown system")
 			return
 		}
-		systems := sdeData.Universe.SystemsWithinRadius(systemID, req.Radius)
+		systems := sdeData.Universe.WithRoutePreferences(s.routePreferences(userID)).SystemsWithinRadius(systemID, req.Radius)
 		for ignoredID := range ignoredSystems {
 			delete(systems, ignoredID)
 		}
@@ -7563,6 +8248,8 @@ func (s *Server) handleAuthStationCommand(w http.ResponseWriter, r *http.Request
 		}
 	}
 
+	brokerFees := s.brokerFeeSchedule(userID)
+	routePrefs := s.routePreferences(userID)
 	var scanResults []engine.StationTrade
 	for regionID := range regionIDs {
 		if err := r.Context().Err(); err != nil {
@@ -7598,6 +8285,8 @@ func (s *Server) handleAuthStationCommand(w http.ResponseWriter, r *http.Request
 			FlagExtremePrices:    req.FlagExtremePrices,
 			AccessToken:          accessToken,
 			IncludeStructures:    req.IncludeStructures,
+			BrokerFees:           brokerFees,
+			RoutePreferences:     routePrefs,
 			Ctx:                  r.Context(),
 		}
 		if allStationsMode {
@@ -11259,6 +11948,11 @@ func (s *Server) handleIndustryAnalyze(w http.ResponseWriter, r *http.Request) {
 		InventionChance     float64 `json:"invention_chance"`
 		DecryptorCost       float64 `json:"decryptor_cost"`
 		InventionOutputRuns int32   `json:"invention_output_runs"`
+		DecryptorTypeID     int32   `json:"decryptor_type_id"`
+		EncryptionSkill     int32   `json:"encryption_skill"`
+		ScienceSkill1       int32   `json:"science_skill_1"`
+		ScienceSkill2       int32   `json:"science_skill_2"`
+		UseInventedStats    bool    `json:"use_invented_stats"`
 	}
 
 	r.Body = http.MaxBytesReader(w, r.Body, industryAnalyzeMaxBodyBytes)
@@ -11308,6 +12002,15 @@ func (s *Server) handleIndustryAnalyze(w http.ResponseWriter, r *http.Request) {
 		req.DecryptorCost = 0
 	}
 	req.InventionOutputRuns = clampInt32(req.InventionOutputRuns, 0, 100000)
+	if req.DecryptorTypeID != 0 {
+		if _, ok := engine.DecryptorByTypeID(req.DecryptorTypeID); !ok {
+			writeError(w, 400, "unknown decryptor_type_id")
+			return
+		}
+	}
+	req.EncryptionSkill = clampInt32(req.EncryptionSkill, 0, 5)
+	req.ScienceSkill1 = clampInt32(req.ScienceSkill1, 0, 5)
+	req.ScienceSkill2 = clampInt32(req.ScienceSkill2, 0, 5)
 	req.SystemName = strings.TrimSpace(req.SystemName)
 
 	// Resolve system ID
@@ -11337,6 +12040,11 @@ func (s *Server) handleIndustryAnalyze(w http.ResponseWriter, r *http.Request) {
 		InventionChance:     req.InventionChance,
 		DecryptorCost:       req.DecryptorCost,
 		InventionOutputRuns: req.InventionOutputRuns,
+		DecryptorTypeID:     req.DecryptorTypeID,
+		EncryptionSkill:     req.EncryptionSkill,
+		ScienceSkill1:       req.ScienceSkill1,
+		ScienceSkill2:       req.ScienceSkill2,
+		UseInventedStats:    req.UseInventedStats,
 	}
 
 	// Use NDJSON streaming for progress
@@ -11379,6 +12087,11 @@ func (s *Server) handleIndustryAnalyze(w http.ResponseWriter, r *http.Request) {
 	flusher.Flush()
 }
 
+// handleIndustryDecryptors lists invention decryptors and their modifiers.
+func (s *Server) handleIndustryDecryptors(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, engine.Decryptors)
+}
+
 func (s *Server) handleIndustrySearch(w http.ResponseWriter, r *http.Request) {
 	if !s.isReady() {
 		writeError(w, 503, "SDE not loaded yet")
@@ -11471,6 +12184,142 @@ func (s *Server) handleIndustrySystems(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, result)
 }
 
+// handleIndustryCostIndices ranks systems near ?system= by their cost index
+// for ?activity= (manufacturing, reaction, invention, copying) plus
+// ?jump_weight= percentage points per jump, within ?radius= jumps.
+func (s *Server) handleIndustryCostIndices(w http.ResponseWriter, r *http.Request) {
+	if !s.isReady() {
+		writeError(w, 503, "SDE not loaded yet")
+		return
+	}
+	q := r.URL.Query()
+	activity := strings.ToLower(strings.TrimSpace(q.Get("activity")))
+	if activity == "" {
+		activity = "manufacturing"
+	}
+	if _, ok := engine.CostIndexActivity(esi.SystemCostIndices{}, activity); !ok {
+		writeError(w, 400, "activity must be manufacturing, reaction, invention or copying")
+		return
+	}
+	radius := 10
+	if v, err := strconv.Atoi(q.Get("radius")); err == nil {
+		radius = clampInt(v, 0, industryCostIndexMaxRadius)
+	}
+	jumpWeight := 0.1
+	if v, err := strconv.ParseFloat(q.Get("jump_weight"), 64); err == nil && v >= 0 {
+		jumpWeight = v
+	}
+	limit := 25
+	if v, err := strconv.Atoi(q.Get("limit")); err == nil {
+		limit = clampInt(v, 1, 200)
+	}
+
+	s.mu.RLock()
+	sdeData := s.sdeData
+	analyzer := s.industryAnalyzer
+	s.mu.RUnlock()
+
+	origin, ok := sdeData.SystemByName[strings.ToLower(strings.TrimSpace(q.Get("system")))]
+	if !ok {
+		writeError(w, 400, "unknown system")
+		return
+	}
+	cache := esi.NewIndustryCache()
+	if analyzer != nil && analyzer.IndustryCache != nil {
+		cache = analyzer.IndustryCache
+	}
+	indices, err := s.esi.GetAllSystemCostIndices(cache)
+	if err != nil {
+		writeError(w, 502, "failed to fetch cost indices: "+err.Error())
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"origin_system_id": origin,
+		"activity":         activity,
+		"radius":           radius,
+		"jump_weight":      jumpWeight,
+		"systems":          engine.RankCostIndexSystems(sdeData, indices, origin, radius, activity, jumpWeight, limit),
+	})
+}
+
+// industryOreBasketMaxMinerals bounds the basket size of /api/industry/ore-basket.
+const industryOreBasketMaxMinerals = 64
+
+// handleIndustryOreBasket finds the cheapest ore (and mineral) purchase at a
+// hub covering a mineral basket, e.g. the flat materials of an analysis.
+func (s *Server) handleIndustryOreBasket(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Minerals []struct {
+			TypeID   int32 `json:"type_id"`
+			Quantity int64 `json:"quantity"`
+		} `json:"minerals"`
+		SystemName     string  `json:"system_name"`
+		StationID      int64   `json:"station_id"`
+		YieldPercent   float64 `json:"yield_percent"`
+		CompressedOnly *bool   `json:"compressed_only"` // nil → true
+		AllowMinerals  *bool   `json:"allow_minerals"`  // nil → true
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, industryAnalyzeMaxBodyBytes)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	if !s.isReady() {
+		writeError(w, 503, "SDE not loaded yet")
+		return
+	}
+	if len(req.Minerals) == 0 {
+		writeError(w, 400, "minerals are required")
+		return
+	}
+	if len(req.Minerals) > industryOreBasketMaxMinerals {
+		writeError(w, 400, fmt.Sprintf("at most %d minerals", industryOreBasketMaxMinerals))
+		return
+	}
+	minerals := make(map[int32]int64, len(req.Minerals))
+	for _, m := range req.Minerals {
+		if m.TypeID <= 0 || m.Quantity <= 0 {
+			continue
+		}
+		minerals[m.TypeID] += min(m.Quantity, 1_000_000_000_000)
+	}
+	if len(minerals) == 0 {
+		writeError(w, 400, "minerals are required")
+		return
+	}
+
+	s.mu.RLock()
+	sdeData := s.sdeData
+	analyzer := s.industryAnalyzer
+	s.mu.RUnlock()
+	if analyzer == nil {
+		writeError(w, 503, "industry analyzer not ready")
+		return
+	}
+
+	var systemID int32
+	if name := strings.TrimSpace(req.SystemName); name != "" {
+		id, ok := sdeData.SystemByName[strings.ToLower(name)]
+		if !ok {
+			writeError(w, 400, "unknown system")
+			return
+		}
+		systemID = id
+	}
+	params := engine.OreBasketParams{
+		Minerals:       minerals,
+		Yield:          clampFloat64(req.YieldPercent, 0, 100) / 100,
+		CompressedOnly: req.CompressedOnly == nil || *req.CompressedOnly,
+		AllowMinerals:  req.AllowMinerals == nil || *req.AllowMinerals,
+	}
+	result, err := analyzer.OreBasket(params, systemID, max(req.StationID, 0))
+	if err != nil {
+		writeError(w, 502, "failed to fetch market prices: "+err.Error())
+		return
+	}
+	writeJSON(w, result)
+}
+
 func (s *Server) handleIndustryStatus(w http.ResponseWriter, r *http.Request) {
 	if !s.isReady() {
 		writeError(w, 503, "SDE not loaded yet")
@@ -11483,16 +12332,17 @@ func (s *Server) handleIndustryStatus(w http.ResponseWriter, r *http.Request) {
 
 	blueprintCount := 0
 	productCount := 0
-	if sdeData.Industry != nil {
-		blueprintCount = len(sdeData.Industry.Blueprints)
-		productCount = len(sdeData.Industry.ProductToBlueprint)
+	industry := sdeData.IndustryData()
+	if industry != nil {
+		blueprintCount = len(industry.Blueprints)
+		productCount = len(industry.ProductToBlueprint)
 	}
 
 	writeJSON(w, map[string]interface{}{
 		"blueprints_loaded":   blueprintCount,
 		"products_with_bp":    productCount,
 		"total_types":         len(sdeData.Types),
-		"industry_data_ready": sdeData.Industry != nil,
+		"industry_data_ready": industry != nil,
 	})
 }
 
@@ -12151,134 +13001,139 @@ func (s *Server) handleAuthRoles(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Fetch roles and corp ID in parallel
-	var roles *esi.CharacterRolesResponse
-	var corpID int32
-	var rolesErr, corpErr error
-	var wg sync.WaitGroup
-	wg.Add(2)
+	result, err := s.characterCorpRoles(sess.CharacterID, token)
+	if err != nil {
+		log.Printf("[CORP] Failed to fetch roles: %v", err)
+	}
 
-	go func() {
-		defer wg.Done()
-		roles, rolesErr = s.esi.GetCharacterRoles(sess.CharacterID, token)
-	}()
-	go func() {
-		defer wg.Done()
-		corpID, corpErr = s.esi.GetCharacterCorporationID(sess.CharacterID)
-	}()
-	wg.Wait()
+	writeJSON(w, result)
+}
 
-	result := corp.CharacterRoles{
-		CorporationID: corpID,
+func (s *Server) handleCorpDashboard(w http.ResponseWriter, r *http.Request) {
+	provider, err := s.corpProvider(r)
+	if err != nil {
+		writeCorpProviderError(w, err)
+		return
 	}
 
-	if rolesErr == nil && roles != nil {
-		result.Roles = roles.Roles
-		for _, role := range roles.Roles {
-			if role == "Director" || role == "CEO" {
-				result.IsDirector = true
-				break
-			}
+	layout := corp.DefaultDashboardLayout()
+	if s.db != nil {
+		saved, _, err := s.db.LoadCorpDashboardLayoutForUser(userIDFromRequest(r))
+		if err != nil {
+			log.Printf("[CORP] Failed to load dashboard layout, using default: %v", err)
 		}
+		layout = saved
 	}
-	if corpErr != nil {
-		log.Printf("[CORP] Failed to fetch corp ID: %v", corpErr)
+
+	dashboard, err := corp.BuildDashboardWithLayout(provider, s.corpPrices(provider), layout)
+	if err != nil {
+		writeError(w, 500, fmt.Sprintf("dashboard build failed: %v", err))
+		return
+	}
+	if !provider.IsDemo() {
+		dashboard.ESIIssues = s.corpESICompatReport().IssuesFor(dashboard.Layout)
 	}
+	s.saveCorpDashboardSnapshots(userIDFromRequest(r), dashboard)
 
-	writeJSON(w, result)
+	writeJSON(w, dashboard)
 }
 
-// corpProvider returns the appropriate CorpDataProvider based on the ?mode= query param.
-func (s *Server) corpProvider(r *http.Request) (corp.CorpDataProvider, error) {
-	mode := r.URL.Query().Get("mode")
-	if mode == "live" {
-		userID := userIDFromRequest(r)
-		characterID, allScope, err := parseAuthScope(r)
-		if err != nil {
-			return nil, err
-		}
-		selectedSessions, err := s.authSessionsForScope(userID, characterID, allScope, false)
-		if err != nil {
-			return nil, fmt.Errorf("not logged in: %w", err)
-		}
-		sess := selectedSessions[0]
-		token, err := s.sessions.EnsureValidTokenForUserCharacter(s.sso, userID, sess.CharacterID)
-		if err != nil {
-			return nil, fmt.Errorf("not logged in: %w", err)
-		}
-		corpID, err := s.esi.GetCharacterCorporationID(sess.CharacterID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to resolve corporation: %w", err)
-		}
-		s.mu.RLock()
-		sdeData := s.sdeData
-		s.mu.RUnlock()
-		return corp.NewESICorpProvider(s.esi, sdeData, token, corpID, sess.CharacterID), nil
+// corpPrices returns adjusted prices for ISK estimation (mining ores, industry
+// products), with moon ores valued at their refined output. Non-blocking: if
+// prices fail, callers still work with zero ISK estimates.
+func (s *Server) corpPrices(provider corp.CorpDataProvider) corp.PriceMap {
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	return corp.MoonOreRefinePrices(sdeData, s.corpAdjustedPrices(provider), corp.DefaultMiningRefineYield)
+}
+
+// corpAdjustedPrices returns the raw adjusted (or demo) prices behind corpPrices.
+func (s *Server) corpAdjustedPrices(provider corp.CorpDataProvider) corp.PriceMap {
+	if provider.IsDemo() && s.demoCorpProvider != nil {
+		return s.demoCorpProvider.DemoPrices()
+	}
+	s.mu.RLock()
+	ia := s.industryAnalyzer
+	s.mu.RUnlock()
+	if ia == nil {
+		return nil
+	}
+	adjusted, err := s.esi.GetAllAdjustedPrices(ia.IndustryCache)
+	if err != nil {
+		log.Printf("[CORP] Failed to fetch adjusted prices: %v (ISK estimates will be zero)", err)
+		return nil
 	}
-	// Default: demo mode
-	if s.demoCorpProvider == nil {
-		return nil, fmt.Errorf("demo data not ready (SDE still loading)")
+	prices := make(corp.PriceMap, len(adjusted))
+	for k, v := range adjusted {
+		prices[k] = v
 	}
-	return s.demoCorpProvider, nil
+	return prices
 }
 
-func (s *Server) handleCorpDashboard(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleCorpMembers(w http.ResponseWriter, r *http.Request) {
 	provider, err := s.corpProvider(r)
 	if err != nil {
-		writeError(w, 400, err.Error())
+		writeCorpProviderError(w, err)
 		return
 	}
 
-	// Fetch adjusted prices for ISK estimation (mining ores, industry products).
-	// Non-blocking: if prices fail, dashboard still works with zero ISK estimates.
-	var prices corp.PriceMap
-	if provider.IsDemo() && s.demoCorpProvider != nil {
-		prices = s.demoCorpProvider.DemoPrices()
-	} else {
-		s.mu.RLock()
-		ia := s.industryAnalyzer
-		s.mu.RUnlock()
-		if ia != nil {
-			if adjusted, err := s.esi.GetAllAdjustedPrices(ia.IndustryCache); err == nil {
-				prices = make(corp.PriceMap, len(adjusted))
-				for k, v := range adjusted {
-					prices[k] = v
-				}
-			} else {
-				log.Printf("[CORP] Failed to fetch adjusted prices: %v (ISK estimates will be zero)", err)
-			}
-		}
+	members, err := provider.GetMembers()
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
 	}
 
-	dashboard, err := corp.BuildDashboard(provider, prices)
+	writeJSON(w, members)
+}
+
+// handleCorpMemberAnalytics returns activity streaks, timezone heatmaps,
+// churn risk flags and join-month retention cohorts for all members.
+func (s *Server) handleCorpMemberAnalytics(w http.ResponseWriter, r *http.Request) {
+	provider, err := s.corpProvider(r)
 	if err != nil {
-		writeError(w, 500, fmt.Sprintf("dashboard build failed: %v", err))
+		writeCorpProviderError(w, err)
 		return
 	}
 
-	writeJSON(w, dashboard)
+	analytics, err := corp.BuildMemberAnalytics(provider)
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
+	}
+
+	writeJSON(w, analytics)
 }
 
-func (s *Server) handleCorpMembers(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleCorpMemberProfile(w http.ResponseWriter, r *http.Request) {
+	characterID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || characterID <= 0 {
+		writeError(w, 400, "invalid character id")
+		return
+	}
 	provider, err := s.corpProvider(r)
 	if err != nil {
-		writeError(w, 400, err.Error())
+		writeCorpProviderError(w, err)
 		return
 	}
 
-	members, err := provider.GetMembers()
+	profile, err := corp.BuildMemberProfile(provider, characterID, s.corpPrices(provider))
+	if errors.Is(err, corp.ErrMemberNotFound) {
+		writeError(w, 404, err.Error())
+		return
+	}
 	if err != nil {
 		writeError(w, 500, err.Error())
 		return
 	}
 
-	writeJSON(w, members)
+	writeJSON(w, profile)
 }
 
 func (s *Server) handleCorpWallets(w http.ResponseWriter, r *http.Request) {
 	provider, err := s.corpProvider(r)
 	if err != nil {
-		writeError(w, 400, err.Error())
+		writeCorpProviderError(w, err)
 		return
 	}
 
@@ -12291,55 +13146,58 @@ func (s *Server) handleCorpWallets(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, wallets)
 }
 
-func (s *Server) handleCorpJournal(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleCorpOrders(w http.ResponseWriter, r *http.Request) {
 	provider, err := s.corpProvider(r)
 	if err != nil {
-		writeError(w, 400, err.Error())
+		writeCorpProviderError(w, err)
 		return
 	}
 
-	division := 1
-	if d := r.URL.Query().Get("division"); d != "" {
-		if v, err := strconv.Atoi(d); err == nil && v >= 1 && v <= 7 {
-			division = v
-		}
+	orders, err := provider.GetOrders()
+	if err != nil {
+		writeError(w, 500, err.Error())
+		return
 	}
-	days := 90
-	if d := r.URL.Query().Get("days"); d != "" {
-		if v, err := strconv.Atoi(d); err == nil && v > 0 {
-			days = v
-		}
+
+	writeJSON(w, orders)
+}
+
+func (s *Server) handleCorpContracts(w http.ResponseWriter, r *http.Request) {
+	provider, err := s.corpProvider(r)
+	if err != nil {
+		writeCorpProviderError(w, err)
+		return
 	}
 
-	journal, err := provider.GetJournal(division, days)
+	contracts, err := provider.GetContracts()
 	if err != nil {
 		writeError(w, 500, err.Error())
 		return
 	}
 
-	writeJSON(w, journal)
+	writeJSON(w, contracts)
 }
 
-func (s *Server) handleCorpOrders(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleCorpStructures(w http.ResponseWriter, r *http.Request) {
 	provider, err := s.corpProvider(r)
 	if err != nil {
-		writeError(w, 400, err.Error())
+		writeCorpProviderError(w, err)
 		return
 	}
 
-	orders, err := provider.GetOrders()
+	structures, err := provider.GetStructures()
 	if err != nil {
 		writeError(w, 500, err.Error())
 		return
 	}
 
-	writeJSON(w, orders)
+	writeJSON(w, structures)
 }
 
 func (s *Server) handleCorpIndustry(w http.ResponseWriter, r *http.Request) {
 	provider, err := s.corpProvider(r)
 	if err != nil {
-		writeError(w, 400, err.Error())
+		writeCorpProviderError(w, err)
 		return
 	}
 
@@ -12355,7 +13213,7 @@ func (s *Server) handleCorpIndustry(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleCorpMining(w http.ResponseWriter, r *http.Request) {
 	provider, err := s.corpProvider(r)
 	if err != nil {
-		writeError(w, 400, err.Error())
+		writeCorpProviderError(w, err)
 		return
 	}
 
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"eve-flipper/internal/db"
+	"eve-flipper/internal/engine"
+)
+
+// shipProfileForUser loads one of the user's ship profiles, failing when it
+// does not exist.
+func (s *Server) shipProfileForUser(userID string, id int64) (*db.ShipProfile, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("ship profiles unavailable")
+	}
+	profile, err := s.db.GetShipProfileForUser(userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ship profile: %w", err)
+	}
+	if profile == nil {
+		return nil, fmt.Errorf("ship profile not found: %d", id)
+	}
+	return profile, nil
+}
+
+// applyScanShipProfile replaces the request's cargo capacity, travel and
+// tank fields with those of its ship profile, if it names one.
+func (s *Server) applyScanShipProfile(userID string, req *scanRequest) error {
+	if req.ShipProfileID <= 0 {
+		return nil
+	}
+	profile, err := s.shipProfileForUser(userID, req.ShipProfileID)
+	if err != nil {
+		return err
+	}
+	req.CargoCapacity = profile.CargoM3
+	req.ShipClass = profile.ShipClass
+	req.ShipAlignSeconds = profile.AlignSeconds
+	req.ShipWarpSpeedAU = profile.WarpSpeedAU
+	req.ShipEHP = profile.EHP
+	return nil
+}
+
+func (s *Server) handleListShipProfiles(w http.ResponseWriter, r *http.Request) {
+	profiles, err := s.db.ListShipProfilesForUser(userIDFromRequest(r))
+	if err != nil {
+		writeError(w, 500, "failed to list ship profiles")
+		return
+	}
+	writeJSON(w, profiles)
+}
+
+// handleSaveShipProfile creates a ship profile, or replaces one when id is
+// set. Missing cargo, align time and warp speed are filled in from the ship
+// class, so stored profiles are complete.
+func (s *Server) handleSaveShipProfile(w http.ResponseWriter, r *http.Request) {
+	var req db.ShipProfile
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		writeError(w, 400, "name is required")
+		return
+	}
+	if req.CargoM3 < 0 || req.AlignSeconds < 0 || req.AlignSeconds > 120 || req.WarpSpeedAU < 0 || req.WarpSpeedAU > 20 || req.EHP < 0 {
+		writeError(w, 400, "cargo_m3 and ehp must be non-negative, align_seconds within 0-120 and warp_speed_au within 0-20")
+		return
+	}
+	travel := engine.ShipTravelFor(req.ShipClass, req.AlignSeconds, req.WarpSpeedAU)
+	req.ShipClass = travel.Class
+	req.AlignSeconds = travel.AlignSeconds
+	req.WarpSpeedAU = travel.WarpSpeedAU
+	if req.CargoM3 == 0 {
+		req.CargoM3 = engine.ShipClassCargoM3(req.ShipClass)
+	}
+	if req.CargoM3 <= 0 {
+		writeError(w, 400, "cargo_m3 is required for this ship class")
+		return
+	}
+	saved, err := s.db.SaveShipProfileForUser(userIDFromRequest(r), req)
+	if err != nil {
+		writeError(w, 500, "failed to save ship profile")
+		return
+	}
+	if saved == nil {
+		writeError(w, 404, "ship profile not found")
+		return
+	}
+	writeJSON(w, saved)
+}
+
+func (s *Server) handleDeleteShipProfile(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, 400, "invalid ship profile id")
+		return
+	}
+	ok, err := s.db.DeleteShipProfileForUser(userIDFromRequest(r), id)
+	if err != nil {
+		writeError(w, 500, "failed to delete ship profile")
+		return
+	}
+	if !ok {
+		writeError(w, 404, "ship profile not found")
+		return
+	}
+	writeJSON(w, map[string]bool{"ok": true})
+}
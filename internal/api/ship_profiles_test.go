@@ -0,0 +1,104 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/db"
+	"eve-flipper/internal/gankcheck"
+)
+
+func TestShipProfilesCRUDFeedsScanRequests(t *testing.T) {
+	const userID = "u-ship-profiles"
+	database := openAPITestDB(t)
+	srv := NewServer(config.Default(), nil, database, nil, nil)
+
+	do := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		addSignedUserCookie(req, srv, userID)
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/api/ships", []byte(`{"name": "Impel", "ship_class": "Deep_Space_Transport", "ehp": 180000}`))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("save status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var saved db.ShipProfile
+	if err := json.NewDecoder(rec.Body).Decode(&saved); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if saved.ID == 0 || saved.ShipClass != "deep_space_transport" || saved.CargoM3 != 60000 || saved.AlignSeconds != 12 || saved.WarpSpeedAU != 4.5 {
+		t.Fatalf("saved profile should be filled from the class: %+v", saved)
+	}
+	if rec := do(http.MethodPost, "/api/ships", []byte(`{"name": "Mystery", "ship_class": "industrial"}`)); rec.Code != http.StatusBadRequest {
+		t.Fatalf("class without a default hold: status = %d, want 400", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/api/ships", []byte(`{"name": "Slow", "ship_class": "freighter", "warp_speed_au": 50}`)); rec.Code != http.StatusBadRequest {
+		t.Fatalf("out-of-range warp speed: status = %d, want 400", rec.Code)
+	}
+
+	var profiles []db.ShipProfile
+	if err := json.NewDecoder(do(http.MethodGet, "/api/ships", nil).Body).Decode(&profiles); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(profiles) != 1 || profiles[0].EHP != 180000 {
+		t.Fatalf("profiles = %+v", profiles)
+	}
+
+	req := scanRequest{CargoCapacity: 5000, ShipClass: "sunesis", ShipProfileID: saved.ID}
+	if err := srv.applyScanShipProfile(userID, &req); err != nil {
+		t.Fatalf("applyScanShipProfile: %v", err)
+	}
+	if req.CargoCapacity != 60000 || req.ShipClass != "deep_space_transport" || req.ShipAlignSeconds != 12 {
+		t.Fatalf("request after profile = %+v", req)
+	}
+	other := scanRequest{ShipProfileID: saved.ID}
+	if err := srv.applyScanShipProfile("someone-else", &other); err == nil {
+		t.Fatalf("another user's profile should not resolve")
+	}
+
+	path := "/api/ships/" + strconv.FormatInt(saved.ID, 10)
+	if rec := do(http.MethodDelete, path, nil); rec.Code != http.StatusOK {
+		t.Fatalf("delete status = %d", rec.Code)
+	}
+	if rec := do(http.MethodDelete, path, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("second delete status = %d, want 404", rec.Code)
+	}
+}
+
+func TestShipTankRiskFactorScalesGankScore(t *testing.T) {
+	if got := shipTankRiskFactor(0); got != 1 {
+		t.Fatalf("unknown tank factor = %v, want 1", got)
+	}
+	if got := shipTankRiskFactor(shipTankReferenceEHP); got != 1 {
+		t.Fatalf("reference tank factor = %v, want 1", got)
+	}
+	if got := shipTankRiskFactor(5_000); got != 1.5 {
+		t.Fatalf("paper tank factor = %v, want capped 1.5", got)
+	}
+	if got := shipTankRiskFactor(1_000_000); got != 0.35 {
+		t.Fatalf("freighter tank factor = %v, want floor 0.35", got)
+	}
+
+	systems := []gankcheck.SystemDanger{{SystemID: 1, DangerLevel: "yellow", KillsTotal: 3, TotalISK: 3_000_000_000, Security: 0.5}}
+	var paper, tanked routeHaulingRiskSummary
+	paper.add(systems)
+	tanked.add(systems)
+	tanked.scaleForTank(shipTankRiskFactor(160_000))
+	if tanked.score >= paper.score {
+		t.Fatalf("tanked score %v should be below %v", tanked.score, paper.score)
+	}
+	if tanked.danger != paper.danger {
+		t.Fatalf("tank should not change the danger level: %s vs %s", tanked.danger, paper.danger)
+	}
+	if routeSafetyMultiplierFromSummary(tanked) > routeSafetyMultiplierFromSummary(paper) {
+		t.Fatalf("tanked multiplier should not exceed the paper-tank one")
+	}
+}
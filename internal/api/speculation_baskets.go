@@ -0,0 +1,344 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"eve-flipper/internal/corp"
+	"eve-flipper/internal/db"
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/sde"
+)
+
+// AlertMetricBasketMove alerts on a speculation basket's daily move; the
+// threshold is the basket's alert percent.
+const AlertMetricBasketMove = "basket_move"
+
+const (
+	speculationBasketMaxItems      = 200
+	speculationBasketNameMaxLen    = 64
+	speculationBasketWatchInterval = 6 * time.Hour
+	speculationBasketChartDays     = 90
+	speculationHistoryWorkers      = 6
+)
+
+type speculationBasketRequest struct {
+	ID           int64   `json:"id"`
+	Name         string  `json:"name"`
+	RegionID     int32   `json:"region_id"`
+	AlertPercent float64 `json:"alert_percent"`
+	Items        []struct {
+		TypeID   int32 `json:"type_id"`
+		Quantity int64 `json:"quantity"`
+	} `json:"items"`
+	Text          string `json:"text"`            // pasted item list, one item per line
+	GroupID       int32  `json:"group_id"`        // add every market type of an inventory group
+	MarketGroupID int32  `json:"market_group_id"` // add every type under a market group
+}
+
+// marketGroupContains reports whether the market group leaf sits at or
+// under ancestor in the market browse tree.
+func marketGroupContains(data *sde.Data, leaf, ancestor int32) bool {
+	for id, depth := leaf, 0; id != 0 && depth < 32; depth++ {
+		if id == ancestor {
+			return true
+		}
+		g := data.MarketGroups[id]
+		if g == nil {
+			return false
+		}
+		id = g.ParentID
+	}
+	return false
+}
+
+// resolveSpeculationBasketItems merges explicit items, a pasted list and
+// group selectors into one item per type, in the order first seen.
+func resolveSpeculationBasketItems(data *sde.Data, req speculationBasketRequest) ([]db.SpeculationBasketItem, []string) {
+	var out []db.SpeculationBasketItem
+	index := make(map[int32]int)
+	add := func(typeID int32, qty int64) {
+		t := data.Types[typeID]
+		if t == nil || engine.IsMarketDisabledTypeID(typeID) {
+			return
+		}
+		if qty <= 0 {
+			qty = 1
+		}
+		if i, ok := index[typeID]; ok {
+			out[i].Quantity += qty
+			return
+		}
+		index[typeID] = len(out)
+		out = append(out, db.SpeculationBasketItem{TypeID: typeID, TypeName: t.Name, Quantity: qty})
+	}
+
+	for _, it := range req.Items {
+		add(it.TypeID, it.Quantity)
+	}
+	unknown := []string{}
+	if strings.TrimSpace(req.Text) != "" {
+		var parsed []corp.BuybackQuoteItem
+		parsed, unknown = corp.ParseBuybackItems(data, req.Text)
+		for _, it := range parsed {
+			add(it.TypeID, it.Quantity)
+		}
+	}
+	if req.GroupID > 0 || req.MarketGroupID > 0 {
+		var ids []int32
+		for id, t := range data.Types {
+			if (req.GroupID > 0 && t.GroupID == req.GroupID) ||
+				(req.MarketGroupID > 0 && marketGroupContains(data, t.MarketGroup, req.MarketGroupID)) {
+				ids = append(ids, id)
+			}
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, id := range ids {
+			add(id, 1)
+		}
+	}
+	return out, unknown
+}
+
+func basketItems(b db.SpeculationBasket) []engine.BasketItem {
+	items := make([]engine.BasketItem, 0, len(b.Items))
+	for _, it := range b.Items {
+		items = append(items, engine.BasketItem{TypeID: it.TypeID, Quantity: it.Quantity})
+	}
+	return items
+}
+
+// basketHistory fetches the region's market history of every basket item.
+func basketHistory(scanner *engine.Scanner, regionID int32, items []engine.BasketItem) map[int32][]esi.HistoryEntry {
+	history := make(map[int32][]esi.HistoryEntry, len(items))
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, speculationHistoryWorkers)
+	)
+	for _, it := range items {
+		wg.Add(1)
+		go func(typeID int32) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			entries, err := scanner.MarketHistory(regionID, typeID)
+			if err != nil || len(entries) == 0 {
+				return
+			}
+			mu.Lock()
+			history[typeID] = entries
+			mu.Unlock()
+		}(it.TypeID)
+	}
+	wg.Wait()
+	return history
+}
+
+func (s *Server) handleListSpeculationBaskets(w http.ResponseWriter, r *http.Request) {
+	baskets, err := s.db.ListSpeculationBasketsForUser(userIDFromRequest(r))
+	if err != nil {
+		writeError(w, 500, "failed to load baskets")
+		return
+	}
+	writeJSON(w, baskets)
+}
+
+// handleSaveSpeculationBasket creates a basket, or replaces one when id is
+// set. Items come from any mix of items, a pasted list and group selectors.
+func (s *Server) handleSaveSpeculationBasket(w http.ResponseWriter, r *http.Request) {
+	var req speculationBasketRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, importMaxBytes)).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	name := normalizeWatchlistGroup(req.Name)
+	if name == "" {
+		writeError(w, 400, "name is required")
+		return
+	}
+	if !s.isReady() {
+		writeError(w, http.StatusServiceUnavailable, "SDE not loaded yet")
+		return
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+
+	regionID := req.RegionID
+	if regionID == 0 {
+		regionID = engine.JitaRegionID
+	}
+	if _, ok := sdeData.Regions[regionID]; !ok {
+		writeError(w, 400, "unknown region")
+		return
+	}
+	items, unknown := resolveSpeculationBasketItems(sdeData, req)
+	if len(items) == 0 {
+		writeError(w, 400, "basket has no known market items")
+		return
+	}
+	if len(items) > speculationBasketMaxItems {
+		writeError(w, 400, fmt.Sprintf("basket is limited to %d items", speculationBasketMaxItems))
+		return
+	}
+
+	saved, err := s.db.SaveSpeculationBasketForUser(userIDFromRequest(r), db.SpeculationBasket{
+		ID:           req.ID,
+		Name:         name,
+		RegionID:     regionID,
+		AlertPercent: clampFloat64(req.AlertPercent, 0, 1000),
+		Items:        items,
+	})
+	if err != nil {
+		writeError(w, 500, "failed to save basket")
+		return
+	}
+	if saved == nil {
+		writeError(w, 404, "basket not found")
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"basket":  saved,
+		"unknown": unknown,
+	})
+}
+
+func (s *Server) handleDeleteSpeculationBasket(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, 400, "invalid basket id")
+		return
+	}
+	ok, err := s.db.DeleteSpeculationBasketForUser(userIDFromRequest(r), id)
+	if err != nil {
+		writeError(w, 500, "failed to delete basket")
+		return
+	}
+	if !ok {
+		writeError(w, 404, "basket not found")
+		return
+	}
+	writeJSON(w, map[string]bool{"ok": true})
+}
+
+// handleSpeculationBasketChart returns the basket's daily value and traded
+// ISK volume in its region, built from market history.
+//
+//	GET /api/speculation/baskets/{id}/chart?days=90
+func (s *Server) handleSpeculationBasketChart(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, 400, "invalid basket id")
+		return
+	}
+	days := speculationBasketChartDays
+	if v, err := strconv.Atoi(r.URL.Query().Get("days")); err == nil && v > 0 {
+		days = clampInt(v, 7, 365)
+	}
+	basket, err := s.db.GetSpeculationBasketForUser(userIDFromRequest(r), id)
+	if err != nil {
+		writeError(w, 500, "failed to load basket")
+		return
+	}
+	if basket == nil {
+		writeError(w, 404, "basket not found")
+		return
+	}
+	s.mu.RLock()
+	scanner := s.scanner
+	s.mu.RUnlock()
+	if scanner == nil {
+		writeError(w, http.StatusServiceUnavailable, "scanner not ready")
+		return
+	}
+
+	items := basketItems(*basket)
+	history := basketHistory(scanner, basket.RegionID, items)
+	series := engine.BuildBasketSeries(items, history, days)
+	writeJSON(w, map[string]interface{}{
+		"basket":  basket,
+		"days":    days,
+		"series":  series,
+		"summary": engine.SummarizeBasket(items, history, series),
+	})
+}
+
+func (s *Server) startSpeculationBasketWatcher() {
+	s.startBackgroundJob(backgroundJob{
+		name:     "speculation_basket_watch",
+		interval: speculationBasketWatchInterval,
+		catchUp:  catchUpSkip,
+		run:      s.pollSpeculationBaskets,
+	})
+}
+
+// pollSpeculationBaskets alerts on baskets whose latest daily move reached
+// their alert percent. Each history day alerts at most once per basket.
+func (s *Server) pollSpeculationBaskets(now time.Time) {
+	if s.db == nil || !s.isReady() {
+		return
+	}
+	s.mu.RLock()
+	scanner := s.scanner
+	s.mu.RUnlock()
+	if scanner == nil {
+		return
+	}
+	userIDs, err := s.db.ListSpeculationBasketAlertUserIDs()
+	if err != nil {
+		log.Printf("[ALERT] Speculation basket watch: %v", err)
+		return
+	}
+	for _, userID := range userIDs {
+		cfg := s.loadConfigForUser(userID)
+		if cfg == nil || (!cfg.AlertTelegram && !cfg.AlertDiscord && !cfg.AlertDesktop) {
+			continue
+		}
+		baskets, err := s.db.ListSpeculationBasketsForUser(userID)
+		if err != nil {
+			log.Printf("[ALERT] Speculation basket watch (%s): %v", userID, err)
+			continue
+		}
+		for _, b := range baskets {
+			if b.AlertPercent <= 0 || len(b.Items) == 0 {
+				continue
+			}
+			items := basketItems(b)
+			series := engine.BuildBasketSeries(items, basketHistory(scanner, b.RegionID, items), 10)
+			if len(series) < 2 {
+				continue
+			}
+			latest := series[len(series)-1].Date
+			change := engine.SummarizeBasket(items, nil, series).Change1DPct
+			if latest == b.LastAlertDate || math.Abs(change) < b.AlertPercent {
+				continue
+			}
+			alert := AlertCheckResult{
+				ShouldAlert:  true,
+				TypeName:     b.Name,
+				Metric:       AlertMetricBasketMove,
+				Threshold:    b.AlertPercent,
+				CurrentValue: change,
+				Message: fmt.Sprintf("Basket %s moved %+.1f%% on %s (%d items, %.0f ISK)",
+					b.Name, change, latest, len(b.Items), series[len(series)-1].Value),
+			}
+			if err := s.SendAlert(userID, cfg, alert, nil); err != nil {
+				log.Printf("[ALERT] Speculation basket %d: %v", b.ID, err)
+				continue
+			}
+			if err := s.db.MarkSpeculationBasketAlerted(b.ID, latest); err != nil {
+				log.Printf("[ALERT] Speculation basket %d: %v", b.ID, err)
+			}
+		}
+	}
+}
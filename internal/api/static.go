@@ -0,0 +1,140 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Static asset caching: Vite emits content-hashed file names under assets/,
+// which never change and are cached for a year. Everything else (index.html,
+// favicons) is revalidated with an ETag so new builds show up on reload.
+const (
+	staticImmutableCache = "public, max-age=31536000, immutable"
+	staticRevalidate     = "no-cache"
+)
+
+// hashedAssetName matches Vite output such as index-BzX9k2Qa.js.
+var hashedAssetName = regexp.MustCompile(`-[A-Za-z0-9_-]{8,}\.[a-z0-9]+$`)
+
+// staticAsset is a file of the frontend bundle with its encodings.
+type staticAsset struct {
+	body        []byte
+	brotli      []byte // from the bundle's .br file, if any
+	gzip        []byte // from the bundle's .gz file, if any
+	etag        string
+	contentType string
+	immutable   bool
+}
+
+// staticHandler serves an embedded frontend bundle with cache headers,
+// ETags and brotli/gzip encodings, falling back to index.html for SPA
+// routes. The encodings are the .br and .gz files the frontend build writes
+// next to each compressible asset; nothing is compressed at runtime. Assets
+// are read once, on first request; missing names are not cached, so probes
+// and deep links cannot grow the cache.
+type staticHandler struct {
+	content fs.FS
+	modTime time.Time
+
+	mu     sync.Mutex
+	assets map[string]*staticAsset
+}
+
+// NewStaticHandler serves the frontend bundle in content.
+func NewStaticHandler(content fs.FS) http.Handler {
+	return &staticHandler{content: content, modTime: time.Now(), assets: make(map[string]*staticAsset)}
+}
+
+func (h *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = "index.html"
+	}
+	asset := h.asset(name)
+	if asset == nil {
+		// SPA fallback
+		name = "index.html"
+		if asset = h.asset(name); asset == nil {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	hdr := w.Header()
+	hdr.Set("Content-Type", asset.contentType)
+	hdr.Add("Vary", "Accept-Encoding")
+	if asset.immutable {
+		hdr.Set("Cache-Control", staticImmutableCache)
+	} else {
+		hdr.Set("Cache-Control", staticRevalidate)
+	}
+
+	// Each encoding gets its own ETag so caches never mix them up.
+	body, etag := asset.body, asset.etag
+	accept := r.Header.Get("Accept-Encoding")
+	switch {
+	case asset.brotli != nil && acceptsEncoding(accept, "br"):
+		hdr.Set("Content-Encoding", "br")
+		body, etag = asset.brotli, strings.TrimSuffix(etag, `"`)+`-br"`
+	case asset.gzip != nil && acceptsEncoding(accept, "gzip"):
+		hdr.Set("Content-Encoding", "gzip")
+		body, etag = asset.gzip, strings.TrimSuffix(etag, `"`)+`-gz"`
+	}
+	hdr.Set("ETag", etag)
+	// ServeContent handles If-None-Match against the ETag and Range.
+	http.ServeContent(w, r, name, h.modTime, bytes.NewReader(body))
+}
+
+// asset loads and caches name, or returns nil when it is not a file.
+func (h *staticHandler) asset(name string) *staticAsset {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if a, ok := h.assets[name]; ok {
+		return a
+	}
+	body, err := fs.ReadFile(h.content, name)
+	if err != nil {
+		return nil
+	}
+	sum := sha256.Sum256(body)
+	ext := strings.ToLower(path.Ext(name))
+	a := &staticAsset{
+		body:        body,
+		etag:        `"` + hex.EncodeToString(sum[:8]) + `"`,
+		contentType: mime.TypeByExtension(ext),
+		immutable:   strings.HasPrefix(name, "assets/") && hashedAssetName.MatchString(name),
+	}
+	if a.contentType == "" {
+		a.contentType = http.DetectContentType(body)
+	}
+	if br, err := fs.ReadFile(h.content, name+".br"); err == nil {
+		a.brotli = br
+	}
+	if gz, err := fs.ReadFile(h.content, name+".gz"); err == nil {
+		a.gzip = gz
+	}
+	h.assets[name] = a
+	return a
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header allows enc.
+func acceptsEncoding(header, enc string) bool {
+	for _, part := range strings.Split(header, ",") {
+		token, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(token), enc) {
+			continue
+		}
+		params = strings.ReplaceAll(strings.TrimSpace(params), " ", "")
+		return params != "q=0" && params != "q=0.0" && params != "q=0.00" && params != "q=0.000"
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// staticTestFS mimics a production bundle: the build writes .gz and .br
+// files next to compressible assets.
+func staticTestFS() fstest.MapFS {
+	js := strings.Repeat("console.log('eve flipper');\n", 200)
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(js))
+	zw.Close()
+	return fstest.MapFS{
+		"index.html":                   {Data: []byte("<!doctype html><div id=app></div>")},
+		"assets/index-BzX9k2Qa.js":     {Data: []byte(js)},
+		"assets/index-BzX9k2Qa.js.gz":  {Data: gz.Bytes()},
+		"assets/style-C1d2E3f4.css":    {Data: []byte(strings.Repeat("body{margin:0}\n", 200))},
+		"assets/style-C1d2E3f4.css.br": {Data: []byte("brotli-bytes")},
+	}
+}
+
+func TestStaticHandler_CacheHeaders(t *testing.T) {
+	h := NewStaticHandler(staticTestFS())
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/assets/index-BzX9k2Qa.js", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("hashed asset status = %d", rec.Code)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != staticImmutableCache {
+		t.Fatalf("hashed asset Cache-Control = %q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rec.Header().Get("Cache-Control"); got != staticRevalidate {
+		t.Fatalf("index Cache-Control = %q", got)
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("index has no ETag")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("If-None-Match status = %d, want 304", rec.Code)
+	}
+}
+
+func TestStaticHandler_SPAFallback(t *testing.T) {
+	h := NewStaticHandler(staticTestFS())
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/station/trading", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "id=app") {
+		t.Fatalf("SPA fallback: status %d body %q", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Cache-Control"); got != staticRevalidate {
+		t.Fatalf("SPA fallback Cache-Control = %q", got)
+	}
+	sh := h.(*staticHandler)
+	if _, cached := sh.assets["station/trading"]; cached {
+		t.Fatal("missing paths must not be cached")
+	}
+}
+
+func TestStaticHandler_Encodings(t *testing.T) {
+	h := NewStaticHandler(staticTestFS())
+
+	req := httptest.NewRequest(http.MethodGet, "/assets/index-BzX9k2Qa.js", nil)
+	req.Header.Set("Accept-Encoding", "gzip;q=0")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("Content-Encoding = %q with gzip;q=0, want identity", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/assets/index-BzX9k2Qa.js", nil)
+	req.Header.Set("Accept-Encoding", "br, gzip")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if !strings.HasSuffix(rec.Header().Get("ETag"), `-gz"`) {
+		t.Fatalf("gzip ETag = %q", rec.Header().Get("ETag"))
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(rec.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	plain, _ := io.ReadAll(zr)
+	if string(plain) != string(staticTestFS()["assets/index-BzX9k2Qa.js"].Data) {
+		t.Fatalf("gzip body does not round-trip")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/assets/style-C1d2E3f4.css", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Content-Encoding"); got != "br" || rec.Body.String() != "brotli-bytes" {
+		t.Fatalf("precompressed brotli not served: encoding %q body %q", got, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/assets/style-C1d2E3f4.css", nil))
+	if rec.Header().Get("Content-Encoding") != "" || !strings.HasPrefix(rec.Body.String(), "body{") {
+		t.Fatalf("identity response expected without Accept-Encoding")
+	}
+}
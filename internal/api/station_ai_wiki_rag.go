@@ -103,19 +103,23 @@ func stationAIWikiRAGAutoStartEnabled() bool {
 	return v != "1" && v != "true" && v != "yes"
 }
 
-func (r *stationAIWikiRAG) Start(defaultRepo string) {
-	repo := sanitizeWikiRepo(defaultRepo)
+// startWikiRAGSync builds the in-memory wiki index at startup and resyncs
+// it every stationAIWikiRAGSyncInterval.
+func (s *Server) startWikiRAGSync() {
+	repo := sanitizeWikiRepo(defaultStationAIWikiRepo)
 	if repo == "" {
 		return
 	}
-	go func() {
-		_, _ = r.ensureIndex(context.Background(), repo, true)
-		ticker := time.NewTicker(stationAIWikiRAGSyncInterval)
-		defer ticker.Stop()
-		for range ticker.C {
-			_, _ = r.ensureIndex(context.Background(), repo, true)
-		}
-	}()
+	resync := func(time.Time) {
+		_, _ = s.wikiRAG.ensureIndex(context.Background(), repo, true)
+	}
+	go resync(time.Now())
+	s.startBackgroundJob(backgroundJob{
+		name:     "wiki_rag_sync",
+		interval: stationAIWikiRAGSyncInterval,
+		catchUp:  catchUpSkip,
+		run:      resync,
+	})
 }
 
 func (r *stationAIWikiRAG) Retrieve(
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"eve-flipper/internal/db"
+)
+
+// stationFees returns the user's recorded fees, or nil when unavailable.
+func (s *Server) stationFees(userID string) []db.StationFee {
+	if s.db == nil {
+		return nil
+	}
+	fees, err := s.db.ListStationFeesForUser(userID)
+	if err != nil {
+		log.Printf("[SCAN] Station fees unavailable: %v", err)
+		return nil
+	}
+	return fees
+}
+
+// stationFeePercents returns the user's recorded broker fees by location.
+func (s *Server) stationFeePercents(userID string) map[int64]float64 {
+	fees := s.stationFees(userID)
+	if len(fees) == 0 {
+		return nil
+	}
+	out := make(map[int64]float64, len(fees))
+	for _, f := range fees {
+		out[f.LocationID] = f.BrokerFeePercent
+	}
+	return out
+}
+
+// structureAccessFees returns the user's recorded per-visit structure
+// access fees by structure ID.
+func (s *Server) structureAccessFees(userID string) map[int64]float64 {
+	var out map[int64]float64
+	for _, f := range s.stationFees(userID) {
+		if f.AccessFeeISK > 0 {
+			if out == nil {
+				out = make(map[int64]float64)
+			}
+			out[f.LocationID] = f.AccessFeeISK
+		}
+	}
+	return out
+}
+
+// stationFeeLocationName names a location for the fee list. Structures are
+// looked up with the user's token; an unresolved structure stays unnamed
+// rather than storing a "Structure <id>" placeholder.
+func (s *Server) stationFeeLocationName(userID string, locationID int64) string {
+	if s.esi == nil {
+		return ""
+	}
+	if !isPlayerStructure(locationID) {
+		return s.esi.StationName(locationID)
+	}
+	token := ""
+	if s.sessions != nil {
+		token, _ = s.sessions.EnsureValidTokenForUser(s.sso, userID)
+	}
+	name := s.esi.StructureName(locationID, token)
+	if strings.HasPrefix(name, "Structure ") || strings.HasPrefix(name, "Location ") {
+		return ""
+	}
+	return name
+}
+
+func (s *Server) handleListStationFees(w http.ResponseWriter, r *http.Request) {
+	fees, err := s.db.ListStationFeesForUser(userIDFromRequest(r))
+	if err != nil {
+		writeError(w, 500, "failed to list station fees")
+		return
+	}
+	writeJSON(w, fees)
+}
+
+func (s *Server) handleSaveStationFee(w http.ResponseWriter, r *http.Request) {
+	var req db.StationFee
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	if req.LocationID <= 0 {
+		writeError(w, 400, "location_id required")
+		return
+	}
+	if req.BrokerFeePercent < 0 || req.BrokerFeePercent > 100 {
+		writeError(w, 400, "broker_fee_percent must be between 0 and 100")
+		return
+	}
+	if req.AccessFeeISK < 0 {
+		writeError(w, 400, "access_fee_isk must not be negative")
+		return
+	}
+	userID := userIDFromRequest(r)
+	req.LocationName = strings.TrimSpace(req.LocationName)
+	if req.LocationName == "" {
+		req.LocationName = s.stationFeeLocationName(userID, req.LocationID)
+	}
+	saved, err := s.db.SaveStationFeeForUser(userID, req)
+	if err != nil {
+		writeError(w, 500, "failed to save station fee")
+		return
+	}
+	writeJSON(w, saved)
+}
+
+func (s *Server) handleDeleteStationFee(w http.ResponseWriter, r *http.Request) {
+	locationID, err := strconv.ParseInt(r.PathValue("location_id"), 10, 64)
+	if err != nil || locationID <= 0 {
+		writeError(w, 400, "invalid location_id")
+		return
+	}
+	ok, err := s.db.DeleteStationFeeForUser(userIDFromRequest(r), locationID)
+	if err != nil {
+		writeError(w, 500, "failed to delete station fee")
+		return
+	}
+	if !ok {
+		writeError(w, 404, "station fee not found")
+		return
+	}
+	writeJSON(w, map[string]bool{"ok": true})
+}
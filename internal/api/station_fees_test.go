@@ -0,0 +1,55 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/db"
+)
+
+func TestStationFeesCRUDFeedsBrokerSchedule(t *testing.T) {
+	const userID = "u-station-fees"
+	database := openAPITestDB(t)
+	srv := NewServer(config.Default(), nil, database, nil, nil)
+
+	do := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		addSignedUserCookie(req, srv, userID)
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodPost, "/api/fees/stations", []byte(`{"location_id": 60003760, "location_name": "Jita IV - Moon 4", "broker_fee_percent": 1.1}`)); rec.Code != http.StatusOK {
+		t.Fatalf("save status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodPost, "/api/fees/stations", []byte(`{"location_id": 60003760, "broker_fee_percent": 120}`)); rec.Code != http.StatusBadRequest {
+		t.Fatalf("out-of-range fee status = %d, want 400", rec.Code)
+	}
+
+	rec := do(http.MethodGet, "/api/fees/stations", nil)
+	var fees []db.StationFee
+	if err := json.NewDecoder(rec.Body).Decode(&fees); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(fees) != 1 || fees[0].BrokerFeePercent != 1.1 {
+		t.Fatalf("fees = %+v", fees)
+	}
+	if got, ok := srv.brokerFeeSchedule(userID).StationPercent(60003760); !ok || got != 1.1 {
+		t.Fatalf("schedule fee at Jita = %v, %v; want 1.1", got, ok)
+	}
+
+	if rec := do(http.MethodDelete, "/api/fees/stations/60003760", nil); rec.Code != http.StatusOK {
+		t.Fatalf("delete status = %d", rec.Code)
+	}
+	if rec := do(http.MethodDelete, "/api/fees/stations/60003760", nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("second delete status = %d, want 404", rec.Code)
+	}
+	if srv.brokerFeeSchedule(userID) != nil {
+		t.Fatalf("schedule should be nil without character or recorded fees")
+	}
+}
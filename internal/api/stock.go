@@ -0,0 +1,196 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/esi"
+)
+
+const (
+	stockDefaultVelocityDays = 30
+	stockDefaultLowDays      = 7.0
+	stockDefaultCoverDays    = 14.0
+	stockDefaultMaxJumps     = 5
+	stockMaxJumps            = 15
+)
+
+// handleStock combines the character's hangar stock and open sell orders
+// per (station, type) into days of sales left at their own sales velocity,
+// and prices restocks of the low lines at the cheapest station within
+// max_jumps.
+func (s *Server) handleStock(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	characterID, allScope, err := parseAuthScope(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !s.isReady() {
+		writeError(w, http.StatusServiceUnavailable, "SDE not loaded yet")
+		return
+	}
+	sessions, err := s.authSessionsForScope(userID, characterID, allScope, true)
+	if err != nil {
+		if strings.Contains(err.Error(), "not logged in") {
+			writeError(w, http.StatusUnauthorized, err.Error())
+		} else {
+			writeError(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+
+	q := r.URL.Query()
+	params := engine.StockParams{
+		VelocityDays: stockDefaultVelocityDays,
+		LowDays:      stockDefaultLowDays,
+		CoverDays:    stockDefaultCoverDays,
+	}
+	if n, convErr := strconv.Atoi(q.Get("velocity_days")); convErr == nil && n > 0 {
+		params.VelocityDays = clampInt(n, 1, 90)
+	}
+	if v, convErr := strconv.ParseFloat(q.Get("low_days"), 64); convErr == nil {
+		params.LowDays = clampFloat64(v, 0, 90)
+	}
+	if v, convErr := strconv.ParseFloat(q.Get("cover_days"), 64); convErr == nil {
+		params.CoverDays = clampFloat64(v, 0, 180)
+	}
+	maxJumps := stockDefaultMaxJumps
+	if n, convErr := strconv.Atoi(q.Get("max_jumps")); convErr == nil {
+		maxJumps = clampInt(n, 0, stockMaxJumps)
+	}
+	since := time.Now().UTC().AddDate(0, 0, -params.VelocityDays)
+
+	type stockKey struct {
+		locationID int64
+		typeID     int32
+	}
+	positions := make(map[stockKey]*engine.StockPosition)
+	position := func(locationID int64, typeID int32) *engine.StockPosition {
+		k := stockKey{locationID, typeID}
+		if p := positions[k]; p != nil {
+			return p
+		}
+		p := &engine.StockPosition{LocationID: locationID, TypeID: typeID}
+		positions[k] = p
+		return p
+	}
+
+	// Stations the trader sells from: open sell orders and recent sales.
+	hangar := make(map[stockKey]int64)
+	txnByID := make(map[int64]esi.WalletTransaction)
+	warnings := []string{}
+	for _, sess := range sessions {
+		token, tokenErr := s.sessions.EnsureValidTokenForUserCharacter(s.sso, userID, sess.CharacterID)
+		if tokenErr != nil {
+			warnings = append(warnings, sess.CharacterName+": "+tokenErr.Error())
+			continue
+		}
+		orders, ordersErr := s.esi.GetCharacterOrders(sess.CharacterID, token)
+		if ordersErr != nil {
+			warnings = append(warnings, sess.CharacterName+": "+ordersErr.Error())
+		}
+		for _, o := range orders {
+			if o.IsBuyOrder || o.VolumeRemain <= 0 {
+				continue
+			}
+			p := position(o.LocationID, o.TypeID)
+			p.Listed += int64(o.VolumeRemain)
+			if p.ListPrice == 0 || o.Price < p.ListPrice {
+				p.ListPrice = o.Price
+			}
+		}
+		txns, txnErr := s.esi.GetWalletTransactions(sess.CharacterID, token)
+		if txnErr != nil {
+			warnings = append(warnings, sess.CharacterName+": "+txnErr.Error())
+		}
+		for _, t := range txns {
+			txnByID[t.TransactionID] = t
+		}
+		assets, assetsErr := s.esi.GetCharacterAssets(sess.CharacterID, token)
+		if assetsErr != nil {
+			warnings = append(warnings, sess.CharacterName+": "+assetsErr.Error())
+			continue
+		}
+		byItemID := make(map[int64]esi.CharacterAsset, len(assets))
+		for _, a := range assets {
+			if a.ItemID > 0 {
+				byItemID[a.ItemID] = a
+			}
+		}
+		for _, a := range assets {
+			if a.TypeID <= 0 || a.IsSingleton || a.IsBlueprintCopy || a.Quantity <= 0 {
+				continue
+			}
+			hangar[stockKey{resolveAssetRootLocationID(a.LocationID, byItemID), a.TypeID}] += a.Quantity
+		}
+	}
+	// The archive reaches further back than the ESI wallet page.
+	if s.db != nil {
+		if archived, archiveErr := s.db.ListArchivedWalletTransactions(userID, characterIDsForSessions(sessions), since, 100000); archiveErr == nil {
+			for _, t := range archived {
+				txnByID[t.TransactionID] = t
+			}
+		} else {
+			warnings = append(warnings, "transaction archive: "+archiveErr.Error())
+		}
+	}
+	for _, t := range txnByID {
+		if t.IsBuy || t.Quantity <= 0 {
+			continue
+		}
+		if date, parseErr := time.Parse(time.RFC3339, t.Date); parseErr != nil || date.Before(since) {
+			continue
+		}
+		position(t.LocationID, t.TypeID).SoldUnits += int64(t.Quantity)
+	}
+
+	list := make([]engine.StockPosition, 0, len(positions))
+	for k, p := range positions {
+		p.InHangar = hangar[k]
+		p.SystemID, p.RegionID = s.locationSystemRegion(sdeData, k.locationID)
+		list = append(list, *p)
+	}
+
+	var findSource engine.StockSourceFinder
+	if universe := s.userUniverse(userID); universe != nil {
+		findSource = func(typeID, systemID, regionID int32, units int64) (engine.StockSource, bool) {
+			if systemID == 0 {
+				return engine.StockSource{}, false
+			}
+			systems := universe.SystemsWithinRadius(systemID, maxJumps)
+			var orders []esi.MarketOrder
+			for rid := range universe.RegionsInSet(systems) {
+				regionOrders, fetchErr := s.esi.FetchRegionOrdersByType(rid, typeID)
+				if fetchErr != nil {
+					warnings = append(warnings, fmt.Sprintf("orders for type %d in region %d: %v", typeID, rid, fetchErr))
+					continue
+				}
+				orders = append(orders, regionOrders...)
+			}
+			return engine.CheapestStockSource(orders, systems, units)
+		}
+	}
+	report := engine.BuildStockReport(sdeData, list, params, findSource)
+	for i := range report.Lines {
+		report.Lines[i].LocationName = s.esi.StationName(report.Lines[i].LocationID)
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"velocity_days": params.VelocityDays,
+		"low_days":      params.LowDays,
+		"cover_days":    params.CoverDays,
+		"max_jumps":     maxJumps,
+		"lines":         report.Lines,
+		"shopping_list": report.ShoppingList,
+		"restock_cost":  report.RestockCost,
+		"warnings":      warnings,
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"eve-flipper/internal/db"
 	"eve-flipper/internal/telemetry"
 )
 
@@ -142,6 +143,9 @@ func (s *Server) trackTelemetryEvent(r *http.Request, event telemetry.Event) {
 }
 
 func (s *Server) trackScanStarted(r *http.Request, module string, props map[string]interface{}) {
+	if r != nil {
+		s.recordUsage(userIDFromRequest(r), db.UsageCategoryScan, module, "")
+	}
 	s.trackTelemetryEvent(r, telemetry.Event{
 		EventType:  "scan_started",
 		Source:     "backend",
@@ -224,6 +228,8 @@ func scanRequestTelemetryProps(req scanRequest) map[string]interface{} {
 		"min_margin":                      req.MinMargin,
 		"min_daily_volume":                req.MinDailyVolume,
 		"max_investment":                  req.MaxInvestment,
+		"max_budget":                      req.MaxBudget,
+		"use_wallet_budget":               req.UseWalletBudget,
 		"min_item_profit":                 req.MinItemProfit,
 		"avg_price_period":                req.AvgPricePeriod,
 		"min_period_roi":                  req.MinPeriodROI,
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"log"
+	"time"
+
+	"eve-flipper/internal/auth"
+	"eve-flipper/internal/engine"
+)
+
+// tradingLimitsTTL is how long a character's order slot limits are reused
+// by scans. Open orders change as the user trades, so this is much shorter
+// than the broker fee schedule's.
+const tradingLimitsTTL = 5 * time.Minute
+
+type tradingLimitsEntry struct {
+	limits  engine.TradingLimits
+	fetched time.Time
+}
+
+// characterTradingLimits computes a character's order slots from their
+// skills and caches the result. openOrders is their own open order count.
+func (s *Server) characterTradingLimits(sess *auth.Session, token string, openOrders int) (engine.TradingLimits, bool) {
+	if s.esi == nil || sess == nil {
+		return engine.TradingLimits{}, false
+	}
+	skills, err := s.esi.GetSkills(sess.CharacterID, token)
+	if err != nil {
+		log.Printf("[AUTH] Trading limits skills unavailable for %s: %v", sess.CharacterName, err)
+		return engine.TradingLimits{}, false
+	}
+	limits := engine.ComputeTradingLimits(skills, openOrders)
+	limits.CharacterID = sess.CharacterID
+	limits.CharacterName = sess.CharacterName
+
+	s.tradingLimitsMu.Lock()
+	if s.tradingLimitsCache == nil {
+		s.tradingLimitsCache = make(map[int64]tradingLimitsEntry)
+	}
+	s.tradingLimitsCache[sess.CharacterID] = tradingLimitsEntry{limits: limits, fetched: time.Now()}
+	s.tradingLimitsMu.Unlock()
+	return limits, true
+}
+
+// activeTradingLimits returns the order slot limits of the user's active
+// character, or false when not logged in or ESI is unavailable.
+func (s *Server) activeTradingLimits(userID string) (engine.TradingLimits, bool) {
+	if s.sessions == nil || s.esi == nil {
+		return engine.TradingLimits{}, false
+	}
+	sess := s.sessions.GetForUser(userID)
+	if sess == nil {
+		return engine.TradingLimits{}, false
+	}
+
+	if limits, ok := s.cachedTradingLimits(userID); ok {
+		return limits, true
+	}
+
+	token, err := s.sessions.EnsureValidTokenForUserCharacter(s.sso, userID, sess.CharacterID)
+	if err != nil {
+		return engine.TradingLimits{}, false
+	}
+	orders, err := s.esi.GetCharacterOrders(sess.CharacterID, token)
+	if err != nil {
+		log.Printf("[SCAN] Trading limits orders unavailable for %s: %v", sess.CharacterName, err)
+		return engine.TradingLimits{}, false
+	}
+	return s.characterTradingLimits(sess, token, len(orders))
+}
+
+// prefetchTradingLimits refreshes the active character's order slot limits
+// in the background when the cached ones are stale, so they are ready when
+// a scan that just started reports its results.
+func (s *Server) prefetchTradingLimits(userID string) {
+	if _, ok := s.cachedTradingLimits(userID); ok {
+		return
+	}
+	go s.activeTradingLimits(userID)
+}
+
+// cachedTradingLimits returns the active character's order slot limits if
+// they were fetched within tradingLimitsTTL. It never calls ESI.
+func (s *Server) cachedTradingLimits(userID string) (engine.TradingLimits, bool) {
+	if s.sessions == nil {
+		return engine.TradingLimits{}, false
+	}
+	sess := s.sessions.GetForUser(userID)
+	if sess == nil {
+		return engine.TradingLimits{}, false
+	}
+	s.tradingLimitsMu.Lock()
+	entry, ok := s.tradingLimitsCache[sess.CharacterID]
+	s.tradingLimitsMu.Unlock()
+	if !ok || time.Since(entry.fetched) >= tradingLimitsTTL {
+		return engine.TradingLimits{}, false
+	}
+	return entry.limits, true
+}
+
+// scanOrderSlotRows is the results table's page size: the rows a user acts
+// on after a scan.
+const scanOrderSlotRows = 100
+
+// scanOrderSlotCheck checks a scan's displayed top rows against the active
+// character's free order slots, or returns nil when the limits are not
+// cached yet (see prefetchTradingLimits).
+func (s *Server) scanOrderSlotCheck(userID string, rows, ordersPerRow int) *engine.OrderSlotCheck {
+	if rows == 0 {
+		return nil
+	}
+	limits, ok := s.cachedTradingLimits(userID)
+	if !ok {
+		return nil
+	}
+	check := engine.CheckOrderSlots(limits, min(rows, scanOrderSlotRows), ordersPerRow)
+	return &check
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"eve-flipper/internal/engine"
+)
+
+func TestScanOrderSlotCheckUsesCachedLimitsAndTopRows(t *testing.T) {
+	database := openAPITestDB(t)
+	userID := "user-order-slots"
+	srv := newAuthedIndustryTestServer(t, database, userID)
+
+	if check := srv.scanOrderSlotCheck(userID, 500, 1); check != nil {
+		t.Fatalf("check without cached limits = %+v, want nil", check)
+	}
+
+	limits := engine.TradingLimits{MaxOrders: 61, OpenOrders: 21, FreeSlots: 40}
+	srv.tradingLimitsCache = map[int64]tradingLimitsEntry{90000001: {limits: limits, fetched: time.Now()}}
+	check := srv.scanOrderSlotCheck(userID, 500, 1)
+	if check == nil || !check.Exceeds || check.RowsThatFit != 40 || !strings.Contains(check.Warning, "top 40 of 100") {
+		t.Fatalf("check = %+v", check)
+	}
+	if check := srv.scanOrderSlotCheck(userID, 30, 1); check == nil || check.Exceeds {
+		t.Fatalf("30 rows in 40 slots = %+v", check)
+	}
+
+	srv.tradingLimitsCache[90000001] = tradingLimitsEntry{limits: limits, fetched: time.Now().Add(-2 * tradingLimitsTTL)}
+	if check := srv.scanOrderSlotCheck(userID, 500, 1); check != nil {
+		t.Fatalf("check with stale limits = %+v, want nil", check)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"log"
 	"net/http"
 	"strings"
+
+	"eve-flipper/internal/engine"
 )
 
 // handleUIOpenMarket opens a market window in the EVE client for the given type_id.
@@ -168,3 +170,130 @@ func (s *Server) handleUIOpenContract(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Write([]byte(`{"success":true}`))
 }
+
+// routeWaypointResult reports the outcome of pushing one waypoint.
+type routeWaypointResult struct {
+	Index         int    `json:"index"`
+	SolarSystemID int64  `json:"solar_system_id"`
+	SystemName    string `json:"system_name,omitempty"`
+	OK            bool   `json:"ok"`
+	Error         string `json:"error,omitempty"`
+}
+
+// routeWaypointSequence flattens a route, a single flip or an explicit list of
+// systems into the ordered waypoint list, collapsing consecutive duplicates.
+func routeWaypointSequence(route *engine.RouteResult, flip *engine.FlipResult, systemIDs []int64, targetSystemID int32) []int64 {
+	var seq []int64
+	push := func(id int64) {
+		if id <= 0 {
+			return
+		}
+		if len(seq) > 0 && seq[len(seq)-1] == id {
+			return
+		}
+		seq = append(seq, id)
+	}
+	switch {
+	case route != nil && len(route.Hops) > 0:
+		for _, hop := range route.Hops {
+			push(int64(hop.SystemID))
+			push(int64(hop.DestSystemID))
+		}
+		push(int64(targetSystemID))
+	case flip != nil:
+		push(int64(flip.BuySystemID))
+		push(int64(flip.SellSystemID))
+	default:
+		for _, id := range systemIDs {
+			push(id)
+		}
+	}
+	return seq
+}
+
+// handleRouteWaypoints replaces the in-game autopilot route with every stop of
+// a route: the first waypoint clears existing ones, the rest are appended.
+// POST /api/route/waypoints
+// Body: {"route": RouteResult} | {"flip": FlipResult} | {"system_ids": [30000142, ...]}
+func (s *Server) handleRouteWaypoints(w http.ResponseWriter, r *http.Request) {
+	if s.sessions == nil {
+		http.Error(w, `{"error":"not_logged_in"}`, http.StatusUnauthorized)
+		return
+	}
+	userID := userIDFromRequest(r)
+	sess := s.sessions.GetForUser(userID)
+	if sess == nil || sess.AccessToken == "" {
+		http.Error(w, `{"error":"not_logged_in"}`, http.StatusUnauthorized)
+		return
+	}
+	token := strings.TrimSpace(sess.AccessToken)
+	if s.sso != nil {
+		refreshed, err := s.sessions.EnsureValidTokenForUserCharacter(s.sso, userID, sess.CharacterID)
+		if err != nil {
+			http.Error(w, `{"error":"not_logged_in"}`, http.StatusUnauthorized)
+			return
+		}
+		token = strings.TrimSpace(refreshed)
+	}
+	if token == "" {
+		http.Error(w, `{"error":"not_logged_in"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Route     *engine.RouteResult `json:"route"`
+		Flip      *engine.FlipResult  `json:"flip"`
+		SystemIDs []int64             `json:"system_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+		return
+	}
+
+	var targetSystemID int32
+	if req.Route != nil {
+		targetSystemID = s.systemIDByName(req.Route.TargetSystemName)
+	}
+	seq := routeWaypointSequence(req.Route, req.Flip, req.SystemIDs, targetSystemID)
+	if len(seq) == 0 {
+		http.Error(w, `{"error":"no_waypoints"}`, http.StatusBadRequest)
+		return
+	}
+	if len(seq) > 100 {
+		http.Error(w, `{"error":"too_many_waypoints"}`, http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("[API] RouteWaypoints: %d waypoints, character_id=%d", len(seq), sess.CharacterID)
+	results := make([]routeWaypointResult, 0, len(seq))
+	failed := 0
+	cleared := false
+	for i, systemID := range seq {
+		res := routeWaypointResult{Index: i, SolarSystemID: systemID}
+		s.mu.RLock()
+		if s.sdeData != nil {
+			if sys, ok := s.sdeData.Systems[int32(systemID)]; ok {
+				res.SystemName = sys.Name
+			}
+		}
+		s.mu.RUnlock()
+		// Clear the existing route with the first waypoint that is set, so
+		// a failed first call never leaves the rest appended to a stale route.
+		if err := s.esi.SetWaypoint(systemID, !cleared, false, token); err != nil {
+			log.Printf("[API] RouteWaypoints error: index=%d solar_system_id=%d, err=%v", i, systemID, err)
+			res.Error = err.Error()
+			failed++
+		} else {
+			cleared = true
+			res.OK = true
+		}
+		results = append(results, res)
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"success": failed == 0,
+		"pushed":  len(seq) - failed,
+		"failed":  failed,
+		"hops":    results,
+	})
+}
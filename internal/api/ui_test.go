@@ -0,0 +1,31 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"eve-flipper/internal/engine"
+)
+
+func TestRouteWaypointSequence_RouteCollapsesSharedSystems(t *testing.T) {
+	route := &engine.RouteResult{Hops: []engine.RouteHop{
+		{SystemID: 1, DestSystemID: 2},
+		{SystemID: 2, DestSystemID: 3},
+		{SystemID: 4, DestSystemID: 5},
+	}}
+	got := routeWaypointSequence(route, nil, nil, 9)
+	want := []int64{1, 2, 3, 4, 5, 9}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("sequence = %v, want %v", got, want)
+	}
+}
+
+func TestRouteWaypointSequence_FlipAndExplicitIDs(t *testing.T) {
+	flip := &engine.FlipResult{BuySystemID: 30000142, SellSystemID: 30002187}
+	if got := routeWaypointSequence(nil, flip, nil, 0); !reflect.DeepEqual(got, []int64{30000142, 30002187}) {
+		t.Fatalf("flip sequence = %v", got)
+	}
+	if got := routeWaypointSequence(nil, nil, []int64{7, 7, 0, 8}, 0); !reflect.DeepEqual(got, []int64{7, 8}) {
+		t.Fatalf("explicit sequence = %v", got)
+	}
+}
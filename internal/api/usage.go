@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"eve-flipper/internal/db"
+)
+
+// Local usage statistics: which features a user runs (scans by type, alerts
+// fired, exports), stored in the local database so users can review their own
+// workflow. Unlike telemetry this is always on and never leaves the machine.
+
+const usageStatsMaxDays = 180
+
+// recordUsage stores a feature use for userID; failures are only logged.
+func (s *Server) recordUsage(userID, category, name, detail string) {
+	if s == nil || s.db == nil {
+		return
+	}
+	if err := s.db.RecordUsageEventForUser(userID, category, name, detail); err != nil {
+		log.Printf("[USAGE] Failed to record %s/%s: %v", category, name, err)
+	}
+}
+
+type usageEventRequest struct {
+	Category string `json:"category"`
+	Name     string `json:"name"`
+	Detail   string `json:"detail"`
+}
+
+// handleRecordUsageEvent records a usage event the frontend observes itself,
+// such as a CSV export or a copy to clipboard.
+func (s *Server) handleRecordUsageEvent(w http.ResponseWriter, r *http.Request) {
+	var req usageEventRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4*1024)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	// Scans and alerts are recorded by the backend; clients only report exports.
+	if strings.ToLower(strings.TrimSpace(req.Category)) != db.UsageCategoryExport {
+		writeError(w, http.StatusBadRequest, "usage category is not allowed")
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	s.recordUsage(userIDFromRequest(r), req.Category, req.Name, req.Detail)
+	writeJSON(w, map[string]bool{"ok": true})
+}
+
+// handleGetUsageStats returns the caller's usage statistics for ?days=
+// (default 30).
+func (s *Server) handleGetUsageStats(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	days := 30
+	if v := strings.TrimSpace(r.URL.Query().Get("days")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid days")
+			return
+		}
+		days = min(n, usageStatsMaxDays)
+	}
+	stats, err := s.db.GetUsageStatsForUser(userIDFromRequest(r), days)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load usage statistics")
+		return
+	}
+	writeJSON(w, stats)
+}
+
+// handleClearUsageStats deletes the caller's recorded usage.
+func (s *Server) handleClearUsageStats(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	removed, err := s.db.ClearUsageEventsForUser(userIDFromRequest(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to clear usage statistics")
+		return
+	}
+	writeJSON(w, map[string]interface{}{"ok": true, "removed": removed})
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/pricing"
+)
+
+// Alert metrics of watchlist price bands; the threshold is the target price.
+const (
+	AlertMetricBuyBelow  = "buy_below"
+	AlertMetricSellAbove = "sell_above"
+)
+
+// watchlistBandInterval is how often the hub books are checked against
+// price bands between scans.
+const watchlistBandInterval = 15 * time.Minute
+
+// bandQuote is the top of one station's book for a type. Zero means no
+// orders on that side.
+type bandQuote struct {
+	LocationID  int64
+	StationName string
+	Ask         float64 // lowest sell order: what buying costs
+	Bid         float64 // highest buy order: what selling pays
+}
+
+// priceBandCrossings returns the band alerts an item's quotes trigger: the
+// cheapest station at or below TargetBuyBelow and the best-paying station
+// at or above TargetSellAbove.
+func priceBandCrossings(item config.WatchlistItem, quotes []bandQuote) []AlertCheckResult {
+	var buy, sell *bandQuote
+	for i := range quotes {
+		q := &quotes[i]
+		if item.TargetBuyBelow > 0 && q.Ask > 0 && q.Ask <= item.TargetBuyBelow && (buy == nil || q.Ask < buy.Ask) {
+			buy = q
+		}
+		if item.TargetSellAbove > 0 && q.Bid >= item.TargetSellAbove && (sell == nil || q.Bid > sell.Bid) {
+			sell = q
+		}
+	}
+	var out []AlertCheckResult
+	if buy != nil {
+		out = append(out, AlertCheckResult{
+			ShouldAlert:  true,
+			TypeID:       item.TypeID,
+			TypeName:     item.TypeName,
+			Metric:       AlertMetricBuyBelow,
+			Threshold:    item.TargetBuyBelow,
+			CurrentValue: buy.Ask,
+			Message: fmt.Sprintf("%s: sell order at %.2f ISK <= buy target %.2f ISK at %s",
+				item.TypeName, buy.Ask, item.TargetBuyBelow, buy.StationName),
+		})
+	}
+	if sell != nil {
+		out = append(out, AlertCheckResult{
+			ShouldAlert:  true,
+			TypeID:       item.TypeID,
+			TypeName:     item.TypeName,
+			Metric:       AlertMetricSellAbove,
+			Threshold:    item.TargetSellAbove,
+			CurrentValue: sell.Bid,
+			Message: fmt.Sprintf("%s: buy order at %.2f ISK >= sell target %.2f ISK at %s",
+				item.TypeName, sell.Bid, item.TargetSellAbove, sell.StationName),
+		})
+	}
+	return out
+}
+
+// bandQuotesFromResults collects the station books a scan saw, per type.
+func bandQuotesFromResults(results interface{}) map[int32][]bandQuote {
+	out := make(map[int32][]bandQuote)
+	switch r := results.(type) {
+	case []engine.FlipResult:
+		for _, f := range r {
+			ask, bid := f.BuyPrice, f.SellPrice
+			if f.BestAskPrice > 0 {
+				ask = f.BestAskPrice
+			}
+			if f.BestBidPrice > 0 {
+				bid = f.BestBidPrice
+			}
+			out[f.TypeID] = append(out[f.TypeID],
+				bandQuote{LocationID: f.BuyLocationID, StationName: f.BuyStation, Ask: ask},
+				bandQuote{LocationID: f.SellLocationID, StationName: f.SellStation, Bid: bid})
+		}
+	case []engine.StationTrade:
+		for _, t := range r {
+			out[t.TypeID] = append(out[t.TypeID],
+				bandQuote{LocationID: t.StationID, StationName: t.StationName, Ask: t.SellPrice, Bid: t.BuyPrice})
+		}
+	}
+	return out
+}
+
+// alertOnCooldown reports whether the same alert went out within
+// DefaultAlertCooldown.
+func (s *Server) alertOnCooldown(userID string, alert AlertCheckResult) bool {
+	last, err := s.db.GetLastAlertTimeForUser(userID, alert.TypeID, alert.Metric, alert.Threshold)
+	if err != nil {
+		log.Printf("[ALERT] Error checking last alert time for type %d: %v", alert.TypeID, err)
+		return true
+	}
+	return !last.IsZero() && time.Since(last) < DefaultAlertCooldown
+}
+
+func (s *Server) startWatchlistBandWatcher() {
+	s.startBackgroundJob(backgroundJob{
+		name:     "watchlist_band_watch",
+		interval: watchlistBandInterval,
+		catchUp:  catchUpSkip,
+		run:      s.pollWatchlistBands,
+	})
+}
+
+// pollWatchlistBands checks price-banded watchlist items against the major
+// trade hub books, so bands fire without a scan covering the item.
+func (s *Server) pollWatchlistBands(now time.Time) {
+	if s.db == nil || s.esi == nil || !s.isReady() {
+		return
+	}
+	src := s.priceSources[pricing.SourceESI]
+	if src == nil {
+		return
+	}
+	userIDs, err := s.db.ListWatchlistPriceBandUserIDs()
+	if err != nil {
+		log.Printf("[ALERT] Watchlist band watch: %v", err)
+		return
+	}
+
+	type userBands struct {
+		cfg   *config.Config
+		items []config.WatchlistItem
+	}
+	var users []userBands
+	typeSet := make(map[int32]bool)
+	for _, userID := range userIDs {
+		cfg := s.loadConfigForUser(userID)
+		if cfg == nil || (!cfg.AlertTelegram && !cfg.AlertDiscord && !cfg.AlertDesktop) {
+			users = append(users, userBands{})
+			continue
+		}
+		var items []config.WatchlistItem
+		for _, it := range s.db.GetWatchlistForUser(userID) {
+			if (it.TargetBuyBelow > 0 || it.TargetSellAbove > 0) && !engine.IsMarketDisabledTypeID(it.TypeID) {
+				items = append(items, it)
+				typeSet[it.TypeID] = true
+			}
+		}
+		users = append(users, userBands{cfg: cfg, items: items})
+	}
+	if len(typeSet) == 0 {
+		return
+	}
+
+	quotes := make(map[int32][]bandQuote)
+	typeIDs := sortedTypeIDs(typeSet)
+	for _, hub := range engine.MajorTradeHubs {
+		hubQuotes, err := src.Quotes(pricing.Hub{RegionID: hub.RegionID, StationID: hub.StationID}, typeIDs)
+		if err != nil {
+			log.Printf("[ALERT] Watchlist band watch (%s): %v", hub.Name, err)
+			continue
+		}
+		name := s.esi.StationName(hub.StationID)
+		if name == "" {
+			name = hub.Name
+		}
+		for typeID, q := range hubQuotes {
+			quotes[typeID] = append(quotes[typeID], bandQuote{LocationID: hub.StationID, StationName: name, Ask: q.Sell, Bid: q.Buy})
+		}
+	}
+
+	for i, u := range users {
+		for _, item := range u.items {
+			for _, alert := range priceBandCrossings(item, quotes[item.TypeID]) {
+				if s.alertOnCooldown(userIDs[i], alert) {
+					continue
+				}
+				if err := s.SendAlert(userIDs[i], u.cfg, alert, nil); err != nil {
+					log.Printf("[ALERT] Failed sending band alert for type %d: %v", alert.TypeID, err)
+				}
+			}
+		}
+	}
+}
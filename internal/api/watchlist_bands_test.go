@@ -0,0 +1,48 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/engine"
+)
+
+func TestPriceBandCrossings(t *testing.T) {
+	item := config.WatchlistItem{TypeID: 34, TypeName: "Tritanium", TargetBuyBelow: 4, TargetSellAbove: 6}
+	quotes := []bandQuote{
+		{LocationID: 1, StationName: "Jita IV - Moon 4", Ask: 3.9, Bid: 5},
+		{LocationID: 2, StationName: "Amarr VIII", Ask: 3.5, Bid: 6.2},
+		{LocationID: 3, StationName: "Dodixie IX", Bid: 6.1}, // no sell orders
+	}
+	alerts := priceBandCrossings(item, quotes)
+	if len(alerts) != 2 {
+		t.Fatalf("alerts = %+v", alerts)
+	}
+	buy, sell := alerts[0], alerts[1]
+	if buy.Metric != AlertMetricBuyBelow || buy.CurrentValue != 3.5 || buy.Threshold != 4 || !strings.Contains(buy.Message, "Amarr VIII") {
+		t.Fatalf("buy = %+v", buy)
+	}
+	if sell.Metric != AlertMetricSellAbove || sell.CurrentValue != 6.2 || !strings.Contains(sell.Message, "Amarr VIII") {
+		t.Fatalf("sell = %+v", sell)
+	}
+
+	item.TargetSellAbove = 0
+	if alerts := priceBandCrossings(item, []bandQuote{{Ask: 4.1, Bid: 100}}); len(alerts) != 0 {
+		t.Fatalf("outside the band = %+v", alerts)
+	}
+}
+
+func TestBandQuotesFromResults(t *testing.T) {
+	quotes := bandQuotesFromResults([]engine.FlipResult{{
+		TypeID: 34, BuyPrice: 4, BestAskPrice: 3.8, BuyStation: "Jita", SellPrice: 6, SellStation: "Amarr",
+	}})
+	q := quotes[34]
+	if len(q) != 2 || q[0].Ask != 3.8 || q[0].StationName != "Jita" || q[1].Bid != 6 || q[1].Ask != 0 {
+		t.Fatalf("flip quotes = %+v", q)
+	}
+	station := bandQuotesFromResults([]engine.StationTrade{{TypeID: 35, StationName: "Hek", SellPrice: 10, BuyPrice: 8}})
+	if q := station[35]; len(q) != 1 || q[0].Ask != 10 || q[0].Bid != 8 {
+		t.Fatalf("station quotes = %+v", q)
+	}
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/engine"
+)
+
+// watchlistExportFormat marks a watchlist exported by this app so the
+// importer can take groups and alert settings over verbatim.
+const watchlistExportFormat = "eve-flipper-watchlist"
+
+const watchlistGroupMaxLen = 64
+
+// normalizeWatchlistGroup collapses whitespace and caps the name length.
+func normalizeWatchlistGroup(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if utf8.RuneCountInString(name) > watchlistGroupMaxLen {
+		name = string([]rune(name)[:watchlistGroupMaxLen])
+	}
+	return name
+}
+
+func (s *Server) filteredWatchlist(userID string) []config.WatchlistItem {
+	items := s.db.GetWatchlistForUser(userID)
+	filtered := make([]config.WatchlistItem, 0, len(items))
+	for _, it := range items {
+		if engine.IsMarketDisabledTypeID(it.TypeID) {
+			continue
+		}
+		filtered = append(filtered, it)
+	}
+	return filtered
+}
+
+// watchlistGroupTypeIDs returns the type IDs of one group's items.
+func watchlistGroupTypeIDs(items []config.WatchlistItem, group string) []int32 {
+	ids := []int32{}
+	for _, it := range items {
+		if it.Group == group {
+			ids = append(ids, it.TypeID)
+		}
+	}
+	return ids
+}
+
+type watchlistBulkRequest struct {
+	TypeIDs []int32 `json:"type_ids"`
+	Group   *string `json:"group"`
+}
+
+// handleBulkDeleteWatchlist removes the listed items, or every item of a
+// group when only group is given.
+func (s *Server) handleBulkDeleteWatchlist(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	var req watchlistBulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	typeIDs := req.TypeIDs
+	if len(typeIDs) == 0 && req.Group != nil {
+		typeIDs = watchlistGroupTypeIDs(s.db.GetWatchlistForUser(userID), normalizeWatchlistGroup(*req.Group))
+	}
+	if len(typeIDs) == 0 && req.Group == nil {
+		writeError(w, 400, "type_ids or group is required")
+		return
+	}
+	deleted, err := s.db.DeleteWatchlistItemsForUser(userID, typeIDs)
+	if err != nil {
+		writeError(w, 500, "failed to delete watchlist items")
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"deleted": deleted,
+		"items":   s.filteredWatchlist(userID),
+	})
+}
+
+// handleGroupWatchlist moves the listed items into a group; an empty group
+// ungroups them.
+func (s *Server) handleGroupWatchlist(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	var req watchlistBulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	if len(req.TypeIDs) == 0 {
+		writeError(w, 400, "type_ids is required")
+		return
+	}
+	group := ""
+	if req.Group != nil {
+		group = normalizeWatchlistGroup(*req.Group)
+	}
+	updated, err := s.db.SetWatchlistGroupForUser(userID, req.TypeIDs, group)
+	if err != nil {
+		writeError(w, 500, "failed to update watchlist group")
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"updated": updated,
+		"items":   s.filteredWatchlist(userID),
+	})
+}
+
+// handleRenameWatchlistGroup renames a group, merging it into the target
+// when that group already exists.
+func (s *Server) handleRenameWatchlistGroup(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	var req struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	from, to := normalizeWatchlistGroup(req.From), normalizeWatchlistGroup(req.To)
+	if from == "" {
+		writeError(w, 400, "from is required")
+		return
+	}
+	updated, err := s.db.RenameWatchlistGroupForUser(userID, from, to)
+	if err != nil {
+		writeError(w, 500, "failed to rename watchlist group")
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"updated": updated,
+		"items":   s.filteredWatchlist(userID),
+	})
+}
+
+// watchlistExport is the shareable JSON document; POST /api/watchlist/import
+// reads it back with groups and alert settings intact.
+type watchlistExport struct {
+	Format     string                 `json:"format"`
+	Version    int                    `json:"version"`
+	ExportedAt string                 `json:"exported_at"`
+	Items      []config.WatchlistItem `json:"items"`
+}
+
+// handleExportWatchlist downloads the watchlist, or one group of it, as
+// JSON.
+//
+//	GET /api/watchlist/export?group=Minerals
+func (s *Server) handleExportWatchlist(w http.ResponseWriter, r *http.Request) {
+	items := s.filteredWatchlist(userIDFromRequest(r))
+	if q := r.URL.Query(); q.Has("group") {
+		group := normalizeWatchlistGroup(q.Get("group"))
+		kept := items[:0]
+		for _, it := range items {
+			if it.Group == group {
+				kept = append(kept, it)
+			}
+		}
+		items = kept
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="watchlist.json"`)
+	writeJSON(w, watchlistExport{
+		Format:     watchlistExportFormat,
+		Version:    1,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Items:      items,
+	})
+}
@@ -0,0 +1,507 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/sde"
+)
+
+// Importers for watchlists and settings exported by other trading tools
+// (EVE Tycoon JSON/CSV watchlists, Tradecalc CSVs) and plain item lists. The
+// formats are matched loosely by column and key names so minor export
+// changes keep working.
+
+const importMaxBytes = 2 << 20
+
+// importedItem is one item row before SDE resolution.
+type importedItem struct {
+	TypeID int32
+	Name   string
+	// Threshold is an alert margin carried over from the source tool (0 = none).
+	Threshold float64
+	// Metric and Disabled carry alert settings from our own export.
+	Metric    string
+	Disabled  bool
+	Group     string
+	BuyBelow  float64
+	SellAbove float64
+}
+
+// importedExport is a parsed tool export.
+type importedExport struct {
+	Format   string
+	Items    []importedItem
+	Settings map[string]float64 // config JSON key -> value
+}
+
+var (
+	importNameColumns      = []string{"name", "item", "item name", "itemname", "type name", "typename", "type", "product"}
+	importTypeIDColumns    = []string{"type id", "typeid", "type_id", "item id", "itemid", "id"}
+	importThresholdColumns = []string{"alert margin", "min margin", "target margin", "margin alert", "alert", "alert threshold"}
+	importGroupColumns     = []string{"group", "tag", "folder", "list"}
+	// importQuantitySuffix strips multibuy quantities ("Tritanium x 1000")
+	// from names that do not resolve as-is.
+	importQuantitySuffix = regexp.MustCompile(`(?i)\s+x?\s*[\d,.]+$`)
+)
+
+// importSettingKeys maps normalized setting labels to config keys.
+var importSettingKeys = map[string]string{
+	"sales tax":        "sales_tax_percent",
+	"salestax":         "sales_tax_percent",
+	"sales tax %":      "sales_tax_percent",
+	"tax":              "sales_tax_percent",
+	"broker fee":       "broker_fee_percent",
+	"brokerfee":        "broker_fee_percent",
+	"broker fee %":     "broker_fee_percent",
+	"brokers fee":      "broker_fee_percent",
+	"min margin":       "min_margin",
+	"minmargin":        "min_margin",
+	"minimum margin":   "min_margin",
+	"cargo":            "cargo_capacity",
+	"cargo capacity":   "cargo_capacity",
+	"cargocapacity":    "cargo_capacity",
+	"min daily volume": "min_daily_volume",
+	"mindailyvolume":   "min_daily_volume",
+}
+
+func normalizeImportKey(key string) string {
+	key = strings.ToLower(strings.TrimSpace(key))
+	key = strings.NewReplacer("_", " ", "-", " ").Replace(key)
+	// camelCase JSON keys ("brokerFee") arrive lowercased; collapse spaces too.
+	if _, ok := importSettingKeys[key]; !ok {
+		if _, ok := importSettingKeys[strings.ReplaceAll(key, " ", "")]; ok {
+			return strings.ReplaceAll(key, " ", "")
+		}
+	}
+	return key
+}
+
+func parseImportNumber(v string) (float64, bool) {
+	v = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(v), "%"))
+	v = strings.ReplaceAll(v, ",", "")
+	f, err := strconv.ParseFloat(v, 64)
+	return f, err == nil
+}
+
+// parseToolExport parses content as JSON or as a delimited/plain list.
+func parseToolExport(content string) (importedExport, error) {
+	content = strings.TrimPrefix(strings.TrimSpace(content), "\ufeff")
+	if content == "" {
+		return importedExport{}, fmt.Errorf("empty import")
+	}
+	if content[0] == '[' || content[0] == '{' {
+		return parseJSONExport(content)
+	}
+	return parseDelimitedExport(content)
+}
+
+func parseJSONExport(content string) (importedExport, error) {
+	var root interface{}
+	if err := json.Unmarshal([]byte(content), &root); err != nil {
+		return importedExport{}, fmt.Errorf("invalid json: %w", err)
+	}
+	out := importedExport{Format: "json", Settings: map[string]float64{}}
+	var list []interface{}
+	switch v := root.(type) {
+	case []interface{}:
+		list = v
+	case map[string]interface{}:
+		if f, _ := v["format"].(string); f == watchlistExportFormat {
+			out.Format = watchlistExportFormat
+		}
+		for key, val := range v {
+			switch strings.ToLower(key) {
+			case "items", "watchlist", "types", "favorites":
+				if arr, ok := val.([]interface{}); ok {
+					list = append(list, arr...)
+				}
+			case "settings", "config":
+				if obj, ok := val.(map[string]interface{}); ok {
+					collectJSONSettings(obj, out.Settings)
+				}
+			}
+		}
+		collectJSONSettings(v, out.Settings)
+	}
+	for _, entry := range list {
+		switch e := entry.(type) {
+		case float64:
+			out.Items = append(out.Items, importedItem{TypeID: int32(e)})
+		case string:
+			out.Items = append(out.Items, importedItem{Name: e})
+		case map[string]interface{}:
+			var it importedItem
+			for key, val := range e {
+				k := normalizeImportKey(key)
+				switch {
+				case containsString(importTypeIDColumns, k) || k == "typeid":
+					if f, ok := jsonNumber(val); ok {
+						it.TypeID = int32(f)
+					}
+				case containsString(importNameColumns, k):
+					if s, ok := val.(string); ok {
+						it.Name = s
+					}
+				case containsString(importThresholdColumns, k):
+					if f, ok := jsonNumber(val); ok {
+						it.Threshold = f
+					}
+				case containsString(importGroupColumns, k):
+					if s, ok := val.(string); ok {
+						it.Group = s
+					}
+				case k == "alert metric":
+					if s, ok := val.(string); ok {
+						it.Metric = s
+					}
+				case k == "alert enabled":
+					if b, ok := val.(bool); ok {
+						it.Disabled = !b
+					}
+				case k == "target buy below":
+					it.BuyBelow, _ = jsonNumber(val)
+				case k == "target sell above":
+					it.SellAbove, _ = jsonNumber(val)
+				}
+			}
+			if it.TypeID != 0 || it.Name != "" {
+				out.Items = append(out.Items, it)
+			}
+		}
+	}
+	return out, nil
+}
+
+func jsonNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case string:
+		return parseImportNumber(n)
+	}
+	return 0, false
+}
+
+func collectJSONSettings(obj map[string]interface{}, dst map[string]float64) {
+	for key, val := range obj {
+		cfgKey, ok := importSettingKeys[normalizeImportKey(key)]
+		if !ok {
+			continue
+		}
+		if f, ok := jsonNumber(val); ok {
+			dst[cfgKey] = f
+		}
+	}
+}
+
+func parseDelimitedExport(content string) (importedExport, error) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	delim := detectImportDelimiter(lines[0])
+	out := importedExport{Format: "list", Settings: map[string]float64{}}
+	if delim == 0 {
+		for _, line := range lines {
+			if name := strings.TrimSpace(line); name != "" {
+				out.Items = append(out.Items, importedItem{Name: name})
+			}
+		}
+		return out, nil
+	}
+
+	r := csv.NewReader(strings.NewReader(content))
+	r.Comma = delim
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	rows, err := r.ReadAll()
+	if err != nil {
+		return importedExport{}, fmt.Errorf("invalid csv: %w", err)
+	}
+	out.Format = "csv"
+
+	nameCol, idCol, thresholdCol, groupCol := -1, -1, -1, -1
+	for i, h := range rows[0] {
+		k := normalizeImportKey(h)
+		switch {
+		case nameCol < 0 && containsString(importNameColumns, k):
+			nameCol = i
+		case idCol < 0 && containsString(importTypeIDColumns, k):
+			idCol = i
+		case thresholdCol < 0 && containsString(importThresholdColumns, k):
+			thresholdCol = i
+		case groupCol < 0 && containsString(importGroupColumns, k):
+			groupCol = i
+		}
+	}
+	body := rows
+	if nameCol >= 0 || idCol >= 0 {
+		body = rows[1:]
+	} else {
+		nameCol = 0 // headerless: "Name<TAB>Qty" multibuy or "Key,Value" settings
+	}
+
+	for _, row := range body {
+		if len(row) == 0 {
+			continue
+		}
+		// Settings rows ("Sales Tax,3.6") in Tradecalc exports.
+		if len(row) >= 2 {
+			if cfgKey, ok := importSettingKeys[normalizeImportKey(row[0])]; ok {
+				if f, ok := parseImportNumber(row[1]); ok {
+					out.Settings[cfgKey] = f
+					continue
+				}
+			}
+		}
+		var it importedItem
+		if idCol >= 0 && idCol < len(row) {
+			if f, ok := parseImportNumber(row[idCol]); ok {
+				it.TypeID = int32(f)
+			}
+		}
+		if nameCol < len(row) {
+			it.Name = strings.TrimSpace(row[nameCol])
+		}
+		if thresholdCol >= 0 && thresholdCol < len(row) {
+			it.Threshold, _ = parseImportNumber(row[thresholdCol])
+		}
+		if groupCol >= 0 && groupCol < len(row) {
+			it.Group = row[groupCol]
+		}
+		if it.TypeID != 0 || it.Name != "" {
+			out.Items = append(out.Items, it)
+		}
+	}
+	return out, nil
+}
+
+func detectImportDelimiter(header string) rune {
+	for _, d := range []rune{'\t', ';', ','} {
+		if strings.ContainsRune(header, d) {
+			return d
+		}
+	}
+	return 0
+}
+
+func containsString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveImportedItems maps rows to SDE market types, dropping duplicates.
+func resolveImportedItems(data *sde.Data, rows []importedItem) (items []config.WatchlistItem, unknown []string) {
+	seen := make(map[int32]bool)
+	now := time.Now().Format(time.RFC3339)
+	for _, row := range rows {
+		// Names are unambiguous; an "ID" column may be something else.
+		typeID, ok := data.TypeIDByName(row.Name)
+		if !ok {
+			typeID, ok = data.TypeIDByName(importQuantitySuffix.ReplaceAllString(strings.TrimSpace(row.Name), ""))
+		}
+		if !ok {
+			typeID = row.TypeID
+		}
+		t, ok := data.Types[typeID]
+		if !ok || engine.IsMarketDisabledTypeID(typeID) {
+			label := strings.TrimSpace(row.Name)
+			if label == "" {
+				label = strconv.Itoa(int(row.TypeID))
+			}
+			unknown = append(unknown, label)
+			continue
+		}
+		if seen[typeID] {
+			continue
+		}
+		seen[typeID] = true
+		item := config.WatchlistItem{
+			TypeID:          typeID,
+			TypeName:        t.Name,
+			AddedAt:         now,
+			AlertMetric:     "margin_percent",
+			AlertThreshold:  row.Threshold,
+			Group:           normalizeWatchlistGroup(row.Group),
+			TargetBuyBelow:  max(row.BuyBelow, 0),
+			TargetSellAbove: max(row.SellAbove, 0),
+		}
+		switch row.Metric {
+		case "total_profit", "profit_per_unit", "daily_volume":
+			item.AlertMetric = row.Metric
+		}
+		// AddWatchlistItemForUser enables any item with a threshold, so a
+		// disabled alert keeps its metric but not its threshold.
+		if row.Disabled {
+			item.AlertThreshold = 0
+		}
+		items = append(items, item)
+	}
+	return items, unknown
+}
+
+type watchlistImportRequest struct {
+	Content       string `json:"content"`
+	ScanID        int64  `json:"scan_id"` // import a previous scan's result types instead of content
+	Group         string `json:"group"`   // put every imported item in this group
+	ApplySettings bool   `json:"apply_settings"`
+	DryRun        bool   `json:"dry_run"`
+}
+
+// scanResultImportRows lists the distinct types of a stored scan's results
+// in result order.
+func (s *Server) scanResultImportRows(scanID int64) ([]importedItem, error) {
+	record := s.db.GetHistoryByID(scanID)
+	if record == nil {
+		return nil, fmt.Errorf("scan %d not found", scanID)
+	}
+	var rows []importedItem
+	seen := make(map[int32]bool)
+	add := func(typeID int32, name string) {
+		if typeID > 0 && !seen[typeID] {
+			seen[typeID] = true
+			rows = append(rows, importedItem{TypeID: typeID, Name: name})
+		}
+	}
+	switch record.Tab {
+	case "station":
+		for _, t := range s.db.GetStationResults(scanID) {
+			add(t.TypeID, t.TypeName)
+		}
+	case "route":
+		for _, route := range s.db.GetRouteResults(scanID) {
+			for _, hop := range route.Hops {
+				add(hop.TypeID, hop.TypeName)
+			}
+		}
+	case "contracts":
+		return nil, fmt.Errorf("contract scans hold bundles, not single types")
+	case "region":
+		regional := s.db.GetRegionalDayResults(scanID)
+		if len(regional) == 0 {
+			regional = s.db.GetFlipResults(scanID)
+		}
+		for _, f := range regional {
+			add(f.TypeID, f.TypeName)
+		}
+	default:
+		for _, f := range s.db.GetFlipResults(scanID) {
+			add(f.TypeID, f.TypeName)
+		}
+	}
+	return rows, nil
+}
+
+// handleImportWatchlist imports a watchlist (and optionally fee/filter
+// settings) exported by another trading tool or by our own export, or the
+// item types of a previous scan.
+func (s *Server) handleImportWatchlist(w http.ResponseWriter, r *http.Request) {
+	var req watchlistImportRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, importMaxBytes)).Decode(&req); err != nil {
+		writeError(w, 400, "invalid json")
+		return
+	}
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	if sdeData == nil {
+		writeError(w, 503, "SDE not loaded yet")
+		return
+	}
+	var parsed importedExport
+	if req.ScanID > 0 {
+		rows, err := s.scanResultImportRows(req.ScanID)
+		if err != nil {
+			writeError(w, 400, err.Error())
+			return
+		}
+		parsed = importedExport{Format: "scan", Items: rows, Settings: map[string]float64{}}
+	} else {
+		var err error
+		if parsed, err = parseToolExport(req.Content); err != nil {
+			writeError(w, 400, err.Error())
+			return
+		}
+	}
+	items, unknown := resolveImportedItems(sdeData, parsed.Items)
+	if group := normalizeWatchlistGroup(req.Group); group != "" {
+		for i := range items {
+			items[i].Group = group
+		}
+	}
+	if unknown == nil {
+		unknown = []string{}
+	}
+
+	userID := userIDFromRequest(r)
+	added := []config.WatchlistItem{}
+	settingsApplied := false
+	if !req.DryRun {
+		for _, item := range items {
+			if s.db.AddWatchlistItemForUser(userID, item) {
+				added = append(added, item)
+			}
+		}
+		// Items already on the watchlist join the requested group too.
+		if group := normalizeWatchlistGroup(req.Group); group != "" {
+			typeIDs := make([]int32, len(items))
+			for i, item := range items {
+				typeIDs[i] = item.TypeID
+			}
+			if _, err := s.db.SetWatchlistGroupForUser(userID, typeIDs, group); err != nil {
+				writeError(w, 500, "failed to update watchlist group")
+				return
+			}
+		}
+		if req.ApplySettings && len(parsed.Settings) > 0 {
+			cfg := s.loadConfigForUser(userID)
+			applyImportedSettings(cfg, parsed.Settings)
+			if err := s.saveConfigForUser(userID, cfg); err != nil {
+				writeError(w, 500, "failed to save config")
+				return
+			}
+			settingsApplied = true
+		}
+	}
+	writeJSON(w, map[string]interface{}{
+		"format":           parsed.Format,
+		"items":            items,
+		"added":            added,
+		"unknown":          unknown,
+		"settings":         parsed.Settings,
+		"settings_applied": settingsApplied,
+	})
+}
+
+func applyImportedSettings(cfg *config.Config, settings map[string]float64) {
+	for key, v := range settings {
+		if v < 0 {
+			continue
+		}
+		switch key {
+		case "sales_tax_percent":
+			cfg.SalesTaxPercent = v
+			cfg.SellSalesTaxPercent = v
+		case "broker_fee_percent":
+			cfg.BrokerFeePercent = v
+			cfg.BuyBrokerFeePercent = v
+			cfg.SellBrokerFeePercent = v
+		case "min_margin":
+			cfg.MinMargin = v
+		case "cargo_capacity":
+			cfg.CargoCapacity = v
+		case "min_daily_volume":
+			cfg.MinDailyVolume = int64(v)
+		}
+	}
+}
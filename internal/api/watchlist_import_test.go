@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"eve-flipper/internal/config"
+	"eve-flipper/internal/sde"
+)
+
+func importTestSDE() *sde.Data {
+	return &sde.Data{Types: map[int32]*sde.ItemType{
+		34:    {ID: 34, Name: "Tritanium"},
+		35:    {ID: 35, Name: "Pyerite"},
+		40520: {ID: 40520, Name: "Large Skill Injector"},
+	}}
+}
+
+func TestParseToolExport_TradecalcCSVWithSettings(t *testing.T) {
+	parsed, err := parseToolExport("Item,Type ID,Alert Margin\nTritanium,34,12\nLarge Skill Injector,,\nUnknown Thing,999999,\nSales Tax,3.6%\nBroker Fee,1.5\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Format != "csv" || len(parsed.Items) != 3 {
+		t.Fatalf("parsed = %+v", parsed)
+	}
+	if parsed.Settings["sales_tax_percent"] != 3.6 || parsed.Settings["broker_fee_percent"] != 1.5 {
+		t.Fatalf("settings = %v", parsed.Settings)
+	}
+	items, unknown := resolveImportedItems(importTestSDE(), parsed.Items)
+	if len(items) != 2 || items[0].TypeID != 34 || items[0].AlertThreshold != 12 || items[1].TypeID != 40520 {
+		t.Fatalf("items = %+v", items)
+	}
+	if len(unknown) != 1 || unknown[0] != "Unknown Thing" {
+		t.Fatalf("unknown = %v", unknown)
+	}
+}
+
+func TestParseToolExport_JSONWatchlist(t *testing.T) {
+	parsed, err := parseToolExport(`{"watchlist":[{"typeId":35,"name":"Pyerite"},{"itemName":"tritanium"},34],"settings":{"brokerFee":2.1,"salesTax":"4.5"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, unknown := resolveImportedItems(importTestSDE(), parsed.Items)
+	if len(items) != 2 || len(unknown) != 0 {
+		t.Fatalf("items = %+v unknown = %v", items, unknown)
+	}
+	if parsed.Settings["broker_fee_percent"] != 2.1 || parsed.Settings["sales_tax_percent"] != 4.5 {
+		t.Fatalf("settings = %v", parsed.Settings)
+	}
+
+	cfg := config.Default()
+	applyImportedSettings(cfg, parsed.Settings)
+	if cfg.BrokerFeePercent != 2.1 || cfg.SellSalesTaxPercent != 4.5 {
+		t.Fatalf("cfg fees = %v / %v", cfg.BrokerFeePercent, cfg.SellSalesTaxPercent)
+	}
+}
+
+func TestParseToolExport_PlainMultibuyList(t *testing.T) {
+	parsed, err := parseToolExport("Tritanium x 1000\nLarge Skill Injector\n\nPyerite 250\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, unknown := resolveImportedItems(importTestSDE(), parsed.Items)
+	if len(items) != 3 || len(unknown) != 0 {
+		t.Fatalf("items = %+v unknown = %v", items, unknown)
+	}
+}
+
+func TestParseToolExport_OwnExportKeepsGroupsAndAlerts(t *testing.T) {
+	doc, err := json.Marshal(watchlistExport{
+		Format:  watchlistExportFormat,
+		Version: 1,
+		Items: []config.WatchlistItem{
+			{TypeID: 34, TypeName: "Tritanium", Group: "  Doctrine   minerals ", AlertEnabled: true, AlertMetric: "daily_volume", AlertThreshold: 5000, TargetBuyBelow: 3.5, TargetSellAbove: 6},
+			{TypeID: 35, TypeName: "Pyerite", AlertMetric: "total_profit", AlertThreshold: 10},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := parseToolExport(string(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Format != watchlistExportFormat {
+		t.Fatalf("format = %q", parsed.Format)
+	}
+	items, unknown := resolveImportedItems(importTestSDE(), parsed.Items)
+	if len(items) != 2 || len(unknown) != 0 {
+		t.Fatalf("items = %+v unknown = %v", items, unknown)
+	}
+	if it := items[0]; it.Group != "Doctrine minerals" || it.AlertMetric != "daily_volume" || it.AlertThreshold != 5000 ||
+		it.TargetBuyBelow != 3.5 || it.TargetSellAbove != 6 {
+		t.Fatalf("grouped item = %+v", it)
+	}
+	// A disabled alert must not come back enabled.
+	if it := items[1]; it.Group != "" || it.AlertMetric != "total_profit" || it.AlertThreshold != 0 {
+		t.Fatalf("ungrouped item = %+v", it)
+	}
+}
+
+func TestParseToolExport_CSVGroupColumn(t *testing.T) {
+	parsed, err := parseToolExport("Name;Tag\nTritanium;Minerals\nLarge Skill Injector;\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, _ := resolveImportedItems(importTestSDE(), parsed.Items)
+	if len(items) != 2 || items[0].Group != "Minerals" || items[1].Group != "" {
+		t.Fatalf("items = %+v", items)
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type wormholeRouteView struct {
+	From          string   `json:"from"`
+	To            string   `json:"to"`
+	GateJumps     int      `json:"gate_jumps"`     // -1 = unreachable
+	WormholeJumps int      `json:"wormhole_jumps"` // -1 = unreachable
+	Path          []string `json:"path"`
+}
+
+// handleRouteWormholes lists live Thera/Turnur connections from EVE-Scout.
+// With ?from=&to= it also compares the gate route with the route using them.
+// GET /api/route/wormholes?from=Jita&to=Amarr&min_security=0
+func (s *Server) handleRouteWormholes(w http.ResponseWriter, r *http.Request) {
+	if !s.isReady() {
+		writeError(w, 503, "SDE not loaded yet")
+		return
+	}
+	conns, err := s.wormholes.WormholeConnections(r.Context())
+	if err != nil {
+		writeError(w, 502, "wormhole connections unavailable: "+err.Error())
+		return
+	}
+
+	q := r.URL.Query()
+	resp := map[string]interface{}{
+		"connections": conns,
+		"count":       len(conns),
+	}
+	if q.Get("from") == "" && q.Get("to") == "" {
+		writeJSON(w, resp)
+		return
+	}
+	fromID := s.systemIDByName(q.Get("from"))
+	if fromID == 0 {
+		writeError(w, 400, "unknown from system: "+strings.TrimSpace(q.Get("from")))
+		return
+	}
+	toID := s.systemIDByName(q.Get("to"))
+	if toID == 0 {
+		writeError(w, 400, "unknown to system: "+strings.TrimSpace(q.Get("to")))
+		return
+	}
+	minSec, _ := strconv.ParseFloat(q.Get("min_security"), 64)
+
+	s.mu.RLock()
+	sdeData := s.sdeData
+	s.mu.RUnlock()
+	gates := s.userUniverse(userIDFromRequest(r))
+	withWH := gates.WithWormholes(conns, time.Now())
+
+	route := wormholeRouteView{
+		From:          sdeData.Systems[fromID].Name,
+		To:            sdeData.Systems[toID].Name,
+		GateJumps:     pathJumps(gates.GetPath(fromID, toID, minSec)),
+		WormholeJumps: -1,
+		Path:          []string{},
+	}
+	if path := withWH.GetPath(fromID, toID, minSec); path != nil {
+		route.WormholeJumps = pathJumps(path)
+		for _, id := range path {
+			if sys, ok := sdeData.Systems[id]; ok {
+				route.Path = append(route.Path, sys.Name)
+			} else {
+				route.Path = append(route.Path, strconv.Itoa(int(id)))
+			}
+		}
+	}
+	resp["route"] = route
+	writeJSON(w, resp)
+}
+
+func pathJumps(path []int32) int {
+	if path == nil {
+		return -1
+	}
+	return len(path) - 1
+}
@@ -251,6 +251,23 @@ func (s *SessionStore) ListForUser(userID string) []*Session {
 	return filtered
 }
 
+// UserIDs returns the users that have at least one stored character session.
+func (s *SessionStore) UserIDs() []string {
+	rows, err := s.db.Query(`SELECT DISTINCT user_id FROM auth_session ORDER BY user_id`)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	var out []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err == nil {
+			out = append(out, userID)
+		}
+	}
+	return out
+}
+
 func (s *SessionStore) querySession(query string, args ...interface{}) *Session {
 	var sess Session
 	var expiresUnix int64
@@ -9,6 +9,18 @@ type WatchlistItem struct {
 	AlertEnabled   bool    `json:"alert_enabled"`
 	AlertMetric    string  `json:"alert_metric"`    // margin_percent | total_profit | profit_per_unit | daily_volume
 	AlertThreshold float64 `json:"alert_threshold"` // threshold for selected metric
+	Group          string  `json:"group"`           // named group, "" = ungrouped
+	// Price band: alert when a station's lowest sell drops to TargetBuyBelow
+	// or its highest buy reaches TargetSellAbove (0 = off).
+	TargetBuyBelow  float64 `json:"target_buy_below"`
+	TargetSellAbove float64 `json:"target_sell_above"`
+}
+
+// PinnedRoute is a gate path the user prefers between its first and last
+// system; it replaces the computed route between them in either direction.
+type PinnedRoute struct {
+	SystemIDs []int32 `json:"system_ids"`
+	Note      string  `json:"note,omitempty"`
 }
 
 // Config holds application settings (in-memory representation).
@@ -36,8 +48,19 @@ type Config struct {
 	MinBfSPerDay     float64 `json:"min_bfs_per_day"`
 	MinS2BBfSRatio   float64 `json:"min_s2b_bfs_ratio"`
 	MaxS2BBfSRatio   float64 `json:"max_s2b_bfs_ratio"`
+	MaxFillDays      float64 `json:"max_fill_days"`
 	MinRouteSecurity float64 `json:"min_route_security"`
 
+	// Courier freight pricing for flip results (0 = disabled).
+	FreightISKPerM3Jump      float64 `json:"freight_isk_per_m3_jump"`
+	FreightCollateralPercent float64 `json:"freight_collateral_percent"`
+
+	// Region order fetch limits: ScanTimeoutSec bounds the fetch stage
+	// (0 = none); MaxRegionFailures fails the scan when more regions fail
+	// to load (-1 = no limit).
+	ScanTimeoutSec    int `json:"scan_timeout_sec"`
+	MaxRegionFailures int `json:"max_region_failures"`
+
 	// Regional day-trader parameters.
 	AvgPricePeriod         int      `json:"avg_price_period"`
 	MinPeriodROI           float64  `json:"min_period_roi"`
@@ -63,8 +86,31 @@ type Config struct {
 	WindowY             int    `json:"window_y"`
 	WindowW             int    `json:"window_w"`
 	WindowH             int    `json:"window_h"`
+
+	// Order desk undercut alarm: re-evaluate the order desk every
+	// OrderDeskAlertMinutes and alert when an order turns reprice/cancel.
+	OrderDeskAlerts       bool `json:"order_desk_alerts"`
+	OrderDeskAlertMinutes int  `json:"order_desk_alert_minutes"`
+
+	// Corp structure monitor: alert when a structure's fuel drops below
+	// StructureFuelAlertDays, a service goes offline or it gets reinforced.
+	StructureAlerts        bool `json:"structure_alerts"`
+	StructureFuelAlertDays int  `json:"structure_fuel_alert_days"`
+
+	// ReferenceStationID is the "sell at" station scan results are compared
+	// against (ReferenceDelta).
+	ReferenceStationID int64 `json:"reference_station_id"`
+
+	// Route avoidance: systems and whole regions no route enters (gank
+	// chokepoints, war target staging), and pinned preferred paths.
+	AvoidSystemIDs []int32       `json:"avoid_system_ids"`
+	AvoidRegionIDs []int32       `json:"avoid_region_ids"`
+	PinnedRoutes   []PinnedRoute `json:"pinned_routes"`
 }
 
+// DefaultReferenceStationID is Jita IV - Moon 4 - Caldari Navy Assembly Plant.
+const DefaultReferenceStationID int64 = 60003760
+
 // Default returns a Config with sensible defaults.
 func Default() *Config {
 	return &Config{
@@ -82,6 +128,7 @@ func Default() *Config {
 		MinRouteSecurity:     0.45,
 		AvgPricePeriod:       14,
 		PurchaseDemandDays:   0.5,
+		MaxRegionFailures:    -1,
 		SourceRegions: []string{
 			"The Forge",
 			"Domain",
@@ -94,5 +141,9 @@ func Default() *Config {
 		Opacity:            230,
 		WindowW:            800,
 		WindowH:            600,
+
+		OrderDeskAlertMinutes:  5,
+		StructureFuelAlertDays: 7,
+		ReferenceStationID:     DefaultReferenceStationID,
 	}
 }
@@ -0,0 +1,296 @@
+package corp
+
+import (
+	"math"
+	"sort"
+)
+
+// AllianceDashboard rolls several corporation dashboards up into one view
+// for directors holding tokens in more than one corp (or alliance execs).
+type AllianceDashboard struct {
+	IsDemo       bool                  `json:"is_demo"`
+	Corporations []AllianceCorpSummary `json:"corporations"`
+	// Combined wallets: every division of every corp, plus the totals.
+	Wallets      []AllianceWallet `json:"wallets"`
+	TotalBalance float64          `json:"total_balance"`
+	Revenue30d   float64          `json:"revenue_30d"`
+	Expenses30d  float64          `json:"expenses_30d"`
+	NetIncome30d float64          `json:"net_income_30d"`
+	Revenue7d    float64          `json:"revenue_7d"`
+	Expenses7d   float64          `json:"expenses_7d"`
+	NetIncome7d  float64          `json:"net_income_7d"`
+	// Categories and days summed across corps; percentages are recomputed
+	// against the combined income.
+	IncomeBySource  []IncomeSource        `json:"income_by_source"`
+	DailyPnL        []DailyPnLEntry       `json:"daily_pnl"`
+	TopContributors []AllianceContributor `json:"top_contributors"`
+	MemberSummary   MemberSummary         `json:"member_summary"`
+	IndustrySummary IndustrySummary       `json:"industry_summary"`
+	MiningSummary   MiningSummary         `json:"mining_summary"`
+	MarketSummary   MarketSummary         `json:"market_summary"`
+	// Logged-in characters whose tokens added no corporation (not a
+	// director, expired token, duplicate corp or failed build).
+	Skipped []AllianceSkippedCharacter `json:"skipped,omitempty"`
+}
+
+// AllianceCorpSummary is one corporation's row in the per-corp breakdown.
+type AllianceCorpSummary struct {
+	Info            CorpInfo `json:"info"`
+	TotalBalance    float64  `json:"total_balance"`
+	BalanceShare    float64  `json:"balance_share"` // percent of the combined balance
+	Revenue30d      float64  `json:"revenue_30d"`
+	Expenses30d     float64  `json:"expenses_30d"`
+	NetIncome30d    float64  `json:"net_income_30d"`
+	RevenueShare    float64  `json:"revenue_share"` // percent of the combined 30d revenue
+	Members         int      `json:"members"`
+	ActiveLast30d   int      `json:"active_last_30d"`
+	ActiveJobs      int      `json:"active_jobs"`
+	ProductionValue float64  `json:"production_value"`
+	MiningVolume30d int64    `json:"mining_volume_30d"`
+	MiningISK       float64  `json:"mining_isk"`
+	// Sections of this corp's dashboard that failed to load.
+	SectionErrors []SectionError `json:"section_errors,omitempty"`
+}
+
+// AllianceWallet is one wallet division tagged with its corporation.
+type AllianceWallet struct {
+	CorporationID int32   `json:"corporation_id"`
+	Ticker        string  `json:"ticker"`
+	Division      int     `json:"division"`
+	Name          string  `json:"name"`
+	Balance       float64 `json:"balance"`
+}
+
+// AllianceContributor is a top contributor tagged with its corporation.
+type AllianceContributor struct {
+	MemberContribution
+	CorporationID int32  `json:"corporation_id"`
+	Ticker        string `json:"ticker"`
+}
+
+// AllianceSkippedCharacter explains why a character's token was left out.
+type AllianceSkippedCharacter struct {
+	CharacterID   int64  `json:"character_id"`
+	CharacterName string `json:"character_name"`
+	Reason        string `json:"reason"`
+}
+
+// allianceTopContributors caps the cross-corp contributor list.
+const allianceTopContributors = 25
+
+// BuildAllianceDashboard aggregates per-corp dashboards. Nil entries are
+// skipped; corps are listed by balance, largest first.
+func BuildAllianceDashboard(dashboards []*CorpDashboard) AllianceDashboard {
+	a := AllianceDashboard{
+		Corporations:    []AllianceCorpSummary{},
+		Wallets:         []AllianceWallet{},
+		TopContributors: []AllianceContributor{},
+	}
+
+	sources := make(map[string]*IncomeSource)
+	days := make(map[string]*DailyPnLEntry)
+	products := make(map[int32]*ProductEntry)
+	ores := make(map[int32]*OreEntry)
+
+	for _, d := range dashboards {
+		if d == nil {
+			continue
+		}
+		a.IsDemo = (len(a.Corporations) == 0 || a.IsDemo) && d.IsDemo
+
+		a.Corporations = append(a.Corporations, AllianceCorpSummary{
+			Info:            d.Info,
+			TotalBalance:    d.TotalBalance,
+			Revenue30d:      d.Revenue30d,
+			Expenses30d:     d.Expenses30d,
+			NetIncome30d:    d.NetIncome30d,
+			Members:         d.MemberSummary.TotalMembers,
+			ActiveLast30d:   d.MemberSummary.ActiveLast30d,
+			ActiveJobs:      d.IndustrySummary.ActiveJobs,
+			ProductionValue: d.IndustrySummary.ProductionValue,
+			MiningVolume30d: d.MiningSummary.TotalVolume30d,
+			MiningISK:       d.MiningSummary.EstimatedISK,
+			SectionErrors:   d.SectionErrors,
+		})
+		for _, w := range d.Wallets {
+			a.Wallets = append(a.Wallets, AllianceWallet{
+				CorporationID: d.Info.CorporationID,
+				Ticker:        d.Info.Ticker,
+				Division:      w.Division,
+				Name:          w.Name,
+				Balance:       w.Balance,
+			})
+		}
+
+		a.TotalBalance += d.TotalBalance
+		a.Revenue30d += d.Revenue30d
+		a.Expenses30d += d.Expenses30d
+		a.NetIncome30d += d.NetIncome30d
+		a.Revenue7d += d.Revenue7d
+		a.Expenses7d += d.Expenses7d
+		a.NetIncome7d += d.NetIncome7d
+
+		for _, src := range d.IncomeBySource {
+			if s, ok := sources[src.Category]; ok {
+				s.Amount += src.Amount
+			} else {
+				merged := src
+				sources[src.Category] = &merged
+			}
+		}
+		for _, day := range d.DailyPnL {
+			e, ok := days[day.Date]
+			if !ok {
+				e = &DailyPnLEntry{Date: day.Date}
+				days[day.Date] = e
+			}
+			e.Revenue += day.Revenue
+			e.Expenses += day.Expenses
+			e.NetIncome += day.NetIncome
+			e.Transactions += day.Transactions
+		}
+		for _, c := range d.TopContributors {
+			a.TopContributors = append(a.TopContributors, AllianceContributor{
+				MemberContribution: c,
+				CorporationID:      d.Info.CorporationID,
+				Ticker:             d.Info.Ticker,
+			})
+		}
+
+		m := &a.MemberSummary
+		ms := d.MemberSummary
+		m.TotalMembers += ms.TotalMembers
+		m.ActiveLast7d += ms.ActiveLast7d
+		m.ActiveLast30d += ms.ActiveLast30d
+		m.Inactive30d += ms.Inactive30d
+		m.Miners += ms.Miners
+		m.Ratters += ms.Ratters
+		m.Traders += ms.Traders
+		m.Industrialists += ms.Industrialists
+		m.PvPers += ms.PvPers
+		m.Other += ms.Other
+
+		a.IndustrySummary.ActiveJobs += d.IndustrySummary.ActiveJobs
+		a.IndustrySummary.CompletedJobs30d += d.IndustrySummary.CompletedJobs30d
+		a.IndustrySummary.ProductionValue += d.IndustrySummary.ProductionValue
+		for _, p := range d.IndustrySummary.TopProducts {
+			if e, ok := products[p.TypeID]; ok {
+				e.Runs += p.Runs
+				e.Jobs += p.Jobs
+				e.EstimatedISK += p.EstimatedISK
+			} else {
+				merged := p
+				products[p.TypeID] = &merged
+			}
+		}
+
+		a.MiningSummary.TotalVolume30d += d.MiningSummary.TotalVolume30d
+		a.MiningSummary.EstimatedISK += d.MiningSummary.EstimatedISK
+		a.MiningSummary.ActiveMiners += d.MiningSummary.ActiveMiners
+		for _, o := range d.MiningSummary.TopOres {
+			if e, ok := ores[o.TypeID]; ok {
+				e.Quantity += o.Quantity
+				e.EstimatedISK += o.EstimatedISK
+			} else {
+				merged := o
+				ores[o.TypeID] = &merged
+			}
+		}
+
+		a.MarketSummary.ActiveBuyOrders += d.MarketSummary.ActiveBuyOrders
+		a.MarketSummary.ActiveSellOrders += d.MarketSummary.ActiveSellOrders
+		a.MarketSummary.TotalBuyValue += d.MarketSummary.TotalBuyValue
+		a.MarketSummary.TotalSellValue += d.MarketSummary.TotalSellValue
+		a.MarketSummary.UniqueTraders += d.MarketSummary.UniqueTraders
+	}
+
+	for i := range a.Corporations {
+		c := &a.Corporations[i]
+		if a.TotalBalance > 0 {
+			c.BalanceShare = math.Round(c.TotalBalance/a.TotalBalance*1000) / 10
+		}
+		if a.Revenue30d > 0 {
+			c.RevenueShare = math.Round(c.Revenue30d/a.Revenue30d*1000) / 10
+		}
+	}
+	sort.SliceStable(a.Corporations, func(i, j int) bool {
+		return a.Corporations[i].TotalBalance > a.Corporations[j].TotalBalance
+	})
+
+	totalIncome := 0.0
+	for _, s := range sources {
+		if s.Amount > 0 {
+			totalIncome += s.Amount
+		}
+	}
+	a.IncomeBySource = make([]IncomeSource, 0, len(sources))
+	for _, s := range sources {
+		s.Percent = 0
+		if totalIncome > 0 {
+			s.Percent = math.Round(math.Abs(s.Amount)/totalIncome*1000) / 10
+		}
+		a.IncomeBySource = append(a.IncomeBySource, *s)
+	}
+	sort.Slice(a.IncomeBySource, func(i, j int) bool {
+		if math.Abs(a.IncomeBySource[i].Amount) != math.Abs(a.IncomeBySource[j].Amount) {
+			return math.Abs(a.IncomeBySource[i].Amount) > math.Abs(a.IncomeBySource[j].Amount)
+		}
+		return a.IncomeBySource[i].Category < a.IncomeBySource[j].Category
+	})
+
+	a.DailyPnL = make([]DailyPnLEntry, 0, len(days))
+	for _, e := range days {
+		a.DailyPnL = append(a.DailyPnL, *e)
+	}
+	sort.Slice(a.DailyPnL, func(i, j int) bool { return a.DailyPnL[i].Date < a.DailyPnL[j].Date })
+	cumul := 0.0
+	for i := range a.DailyPnL {
+		cumul += a.DailyPnL[i].NetIncome
+		a.DailyPnL[i].Cumulative = cumul
+	}
+
+	sort.SliceStable(a.TopContributors, func(i, j int) bool {
+		return a.TopContributors[i].TotalISK > a.TopContributors[j].TotalISK
+	})
+	if len(a.TopContributors) > allianceTopContributors {
+		a.TopContributors = a.TopContributors[:allianceTopContributors]
+	}
+
+	a.IndustrySummary.TopProducts = make([]ProductEntry, 0, len(products))
+	for _, p := range products {
+		a.IndustrySummary.TopProducts = append(a.IndustrySummary.TopProducts, *p)
+	}
+	sort.Slice(a.IndustrySummary.TopProducts, func(i, j int) bool {
+		pi, pj := a.IndustrySummary.TopProducts[i], a.IndustrySummary.TopProducts[j]
+		if pi.EstimatedISK != pj.EstimatedISK {
+			return pi.EstimatedISK > pj.EstimatedISK
+		}
+		if pi.Runs != pj.Runs {
+			return pi.Runs > pj.Runs
+		}
+		return pi.TypeID < pj.TypeID
+	})
+	if len(a.IndustrySummary.TopProducts) > 10 {
+		a.IndustrySummary.TopProducts = a.IndustrySummary.TopProducts[:10]
+	}
+
+	a.MiningSummary.TopOres = make([]OreEntry, 0, len(ores))
+	for _, o := range ores {
+		a.MiningSummary.TopOres = append(a.MiningSummary.TopOres, *o)
+	}
+	sort.Slice(a.MiningSummary.TopOres, func(i, j int) bool {
+		oi, oj := a.MiningSummary.TopOres[i], a.MiningSummary.TopOres[j]
+		if oi.EstimatedISK != oj.EstimatedISK {
+			return oi.EstimatedISK > oj.EstimatedISK
+		}
+		if oi.Quantity != oj.Quantity {
+			return oi.Quantity > oj.Quantity
+		}
+		return oi.TypeID < oj.TypeID
+	})
+	if len(a.MiningSummary.TopOres) > 10 {
+		a.MiningSummary.TopOres = a.MiningSummary.TopOres[:10]
+	}
+
+	return a
+}
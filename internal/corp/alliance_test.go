@@ -0,0 +1,66 @@
+package corp
+
+import "testing"
+
+func TestBuildAllianceDashboard(t *testing.T) {
+	a := &CorpDashboard{
+		Info:           CorpInfo{CorporationID: 1, Name: "Alpha", Ticker: "ALF"},
+		Wallets:        []CorpWalletDivision{{Division: 1, Name: "Master", Balance: 100}},
+		TotalBalance:   100,
+		Revenue30d:     300,
+		IncomeBySource: []IncomeSource{{Category: "bounties", Amount: 300}},
+		DailyPnL: []DailyPnLEntry{
+			{Date: "2026-06-01", Revenue: 100, NetIncome: 100},
+			{Date: "2026-06-02", Revenue: 200, NetIncome: 200},
+		},
+		TopContributors: []MemberContribution{{CharacterID: 10, Name: "Ann", TotalISK: 50}},
+		MemberSummary:   MemberSummary{TotalMembers: 4, ActiveLast30d: 3},
+		IndustrySummary: IndustrySummary{ActiveJobs: 2, TopProducts: []ProductEntry{{TypeID: 587, Runs: 5, Jobs: 1, EstimatedISK: 10}}},
+		MiningSummary:   MiningSummary{TotalVolume30d: 1000, TopOres: []OreEntry{{TypeID: 1230, Quantity: 1000, EstimatedISK: 5}}},
+	}
+	b := &CorpDashboard{
+		Info:           CorpInfo{CorporationID: 2, Name: "Beta", Ticker: "BET"},
+		Wallets:        []CorpWalletDivision{{Division: 1, Name: "Master", Balance: 300}},
+		TotalBalance:   300,
+		Revenue30d:     100,
+		Expenses30d:    -100,
+		IncomeBySource: []IncomeSource{{Category: "bounties", Amount: 100}, {Category: "taxes", Amount: -100}},
+		DailyPnL: []DailyPnLEntry{
+			{Date: "2026-06-02", Revenue: 100, Expenses: -100},
+		},
+		TopContributors: []MemberContribution{{CharacterID: 20, Name: "Bo", TotalISK: 80}},
+		MemberSummary:   MemberSummary{TotalMembers: 6, ActiveLast30d: 2},
+		IndustrySummary: IndustrySummary{ActiveJobs: 1, TopProducts: []ProductEntry{{TypeID: 587, Runs: 3, Jobs: 1, EstimatedISK: 6}}},
+		MiningSummary:   MiningSummary{TotalVolume30d: 500, TopOres: []OreEntry{{TypeID: 1230, Quantity: 500, EstimatedISK: 2.5}}},
+	}
+
+	d := BuildAllianceDashboard([]*CorpDashboard{a, nil, b})
+
+	if d.TotalBalance != 400 || d.Revenue30d != 400 || len(d.Wallets) != 2 {
+		t.Fatalf("totals = %v / %v, wallets = %+v", d.TotalBalance, d.Revenue30d, d.Wallets)
+	}
+	if len(d.Corporations) != 2 || d.Corporations[0].Info.Ticker != "BET" || d.Corporations[0].BalanceShare != 75 || d.Corporations[1].RevenueShare != 75 {
+		t.Fatalf("corporations = %+v", d.Corporations)
+	}
+	if d.IncomeBySource[0].Category != "bounties" || d.IncomeBySource[0].Amount != 400 || d.IncomeBySource[1].Percent != 25 {
+		t.Fatalf("income = %+v", d.IncomeBySource)
+	}
+	if len(d.DailyPnL) != 2 || d.DailyPnL[1].Revenue != 300 || d.DailyPnL[1].Cumulative != 300 {
+		t.Fatalf("daily = %+v", d.DailyPnL)
+	}
+	if d.TopContributors[0].Name != "Bo" || d.TopContributors[0].Ticker != "BET" || d.TopContributors[1].CorporationID != 1 {
+		t.Fatalf("contributors = %+v", d.TopContributors)
+	}
+	if d.MemberSummary.TotalMembers != 10 || d.IndustrySummary.ActiveJobs != 3 || d.MiningSummary.TotalVolume30d != 1500 {
+		t.Fatalf("summaries = %+v %+v %+v", d.MemberSummary, d.IndustrySummary, d.MiningSummary)
+	}
+	if p := d.IndustrySummary.TopProducts; len(p) != 1 || p[0].Runs != 8 || p[0].Jobs != 2 {
+		t.Fatalf("products = %+v", p)
+	}
+	if o := d.MiningSummary.TopOres; len(o) != 1 || o[0].Quantity != 1500 || o[0].EstimatedISK != 7.5 {
+		t.Fatalf("ores = %+v", o)
+	}
+	if d.IsDemo {
+		t.Fatal("live dashboards should not roll up as demo")
+	}
+}
@@ -0,0 +1,233 @@
+package corp
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Buyback percentage bounds accepted by BuildPriceBoard.
+const (
+	DefaultBuybackPercent = 90.0
+	minBuybackPercent     = 1.0
+	maxBuybackPercent     = 150.0
+)
+
+// BuybackItem is one row of the corp ore buy program.
+type BuybackItem struct {
+	TypeID   int32
+	Name     string
+	Category string // "mineral" or "ore"
+	// DemoJitaBuy is the approximate Jita top buy used in demo mode.
+	DemoJitaBuy float64
+}
+
+// BuybackItems lists the minerals and raw ores covered by the price board.
+var BuybackItems = []BuybackItem{
+	{34, "Tritanium", "mineral", 4.1},
+	{35, "Pyerite", "mineral", 8.7},
+	{36, "Mexallon", "mineral", 52.0},
+	{37, "Isogen", "mineral", 68.0},
+	{38, "Nocxium", "mineral", 510.0},
+	{39, "Zydrine", "mineral", 980.0},
+	{40, "Megacyte", "mineral", 1850.0},
+	{11399, "Morphite", "mineral", 11500.0},
+	{1230, "Veldspar", "ore", 18.5},
+	{1228, "Scordite", "ore", 27.0},
+	{1224, "Pyroxeres", "ore", 44.0},
+	{18, "Plagioclase", "ore", 62.0},
+	{1227, "Omber", "ore", 71.0},
+	{20, "Kernite", "ore", 96.0},
+	{1226, "Jaspet", "ore", 260.0},
+	{1231, "Hemorphite", "ore", 340.0},
+	{21, "Hedbergite", "ore", 390.0},
+	{1229, "Gneiss", "ore", 420.0},
+	{1232, "Dark Ochre", "ore", 510.0},
+	{1225, "Crokite", "ore", 720.0},
+	{19, "Spodumain", "ore", 610.0},
+	{1223, "Bistot", "ore", 880.0},
+	{22, "Arkonor", "ore", 940.0},
+	{11396, "Mercoxit", "ore", 2400.0},
+}
+
+// PriceBoard is a formatted buyback price list ready to paste into the corp MOTD.
+type PriceBoard struct {
+	GeneratedAt    string           `json:"generated_at"`
+	Percent        float64          `json:"percent"`
+	Source         string           `json:"source"` // "jita_buy", "jita_buy_fuzzwork" or "demo"
+	Lines          []PriceBoardLine `json:"lines"`
+	ChangedCount   int              `json:"changed_count"`
+	RatesChangedAt string           `json:"rates_changed_at"`
+	PreviousAt     string           `json:"previous_at,omitempty"`
+	MOTD           string           `json:"motd"`
+}
+
+// PriceBoardLine is a single item on the price board.
+type PriceBoardLine struct {
+	TypeID        int32   `json:"type_id"`
+	Name          string  `json:"name"`
+	Category      string  `json:"category"`
+	JitaBuy       float64 `json:"jita_buy"`
+	BuybackPrice  float64 `json:"buyback_price"`
+	PreviousPrice float64 `json:"previous_price,omitempty"`
+	ChangePct     float64 `json:"change_pct"`
+	Changed       bool    `json:"changed"`
+}
+
+// NormalizeBuybackPercent clamps a requested percentage of Jita buy to a sane range.
+func NormalizeBuybackPercent(pct float64) float64 {
+	if pct <= 0 || math.IsNaN(pct) || math.IsInf(pct, 0) {
+		return DefaultBuybackPercent
+	}
+	if pct < minBuybackPercent {
+		return minBuybackPercent
+	}
+	if pct > maxBuybackPercent {
+		return maxBuybackPercent
+	}
+	return pct
+}
+
+// BuildPriceBoard prices every buyback item at pct% of Jita buy and compares
+// against the previously published board. Items without a Jita buy price are
+// skipped. RatesChangedAt carries over from the previous board when no rate moved.
+func BuildPriceBoard(jitaBuy PriceMap, pct float64, source string, previous *PriceBoard, now time.Time) PriceBoard {
+	pct = NormalizeBuybackPercent(pct)
+	generatedAt := now.UTC().Format(time.RFC3339)
+
+	prevPrices := make(map[int32]float64)
+	if previous != nil {
+		for _, l := range previous.Lines {
+			prevPrices[l.TypeID] = l.BuybackPrice
+		}
+	}
+
+	board := PriceBoard{
+		GeneratedAt: generatedAt,
+		Percent:     pct,
+		Source:      source,
+		Lines:       []PriceBoardLine{},
+	}
+
+	for _, item := range BuybackItems {
+		buy := jitaBuy[item.TypeID]
+		if buy <= 0 {
+			continue
+		}
+		line := PriceBoardLine{
+			TypeID:       item.TypeID,
+			Name:         item.Name,
+			Category:     item.Category,
+			JitaBuy:      buy,
+			BuybackPrice: math.Round(buy*pct) / 100,
+		}
+		if prev, ok := prevPrices[item.TypeID]; ok {
+			line.PreviousPrice = prev
+			if line.BuybackPrice != prev {
+				line.Changed = true
+				if prev > 0 {
+					line.ChangePct = math.Round((line.BuybackPrice-prev)/prev*1000) / 10
+				}
+			}
+		} else if previous != nil {
+			line.Changed = true
+		}
+		if line.Changed {
+			board.ChangedCount++
+		}
+		board.Lines = append(board.Lines, line)
+	}
+
+	switch {
+	case previous == nil:
+		board.RatesChangedAt = generatedAt
+	case board.ChangedCount > 0 || previous.Percent != pct || len(previous.Lines) != len(board.Lines):
+		board.RatesChangedAt = generatedAt
+		board.PreviousAt = previous.GeneratedAt
+	default:
+		board.RatesChangedAt = previous.RatesChangedAt
+		board.PreviousAt = previous.GeneratedAt
+	}
+
+	board.MOTD = formatPriceBoardMOTD(board)
+	return board
+}
+
+// formatPriceBoardMOTD renders the board as plain text lines that survive the
+// in-game MOTD editor (no markup, fixed-width columns).
+func formatPriceBoardMOTD(b PriceBoard) string {
+	var sb strings.Builder
+	updated := b.RatesChangedAt
+	if t, err := time.Parse(time.RFC3339, updated); err == nil {
+		updated = t.Format("2006-01-02 15:04") + " EVE"
+	}
+	fmt.Fprintf(&sb, "Ore Buyback — %s of Jita buy (updated %s)\n", formatPercent(b.Percent), updated)
+
+	for _, category := range []string{"mineral", "ore"} {
+		header := "Minerals"
+		if category == "ore" {
+			header = "Ores"
+		}
+		wroteHeader := false
+		for _, l := range b.Lines {
+			if l.Category != category {
+				continue
+			}
+			if !wroteHeader {
+				fmt.Fprintf(&sb, "\n%s:\n", header)
+				wroteHeader = true
+			}
+			fmt.Fprintf(&sb, "%-12s %12s ISK", l.Name, formatISKPrice(l.BuybackPrice))
+			if l.Changed && l.ChangePct != 0 {
+				arrow := "▲"
+				if l.ChangePct < 0 {
+					arrow = "▼"
+				}
+				fmt.Fprintf(&sb, "  %s%.1f%%", arrow, math.Abs(l.ChangePct))
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func formatPercent(pct float64) string {
+	if pct == math.Trunc(pct) {
+		return fmt.Sprintf("%.0f%%", pct)
+	}
+	return fmt.Sprintf("%.1f%%", pct)
+}
+
+// formatISKPrice formats a per-unit price with thousands separators and two decimals.
+func formatISKPrice(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i:]
+	}
+	neg := strings.HasPrefix(intPart, "-")
+	if neg {
+		intPart = intPart[1:]
+	}
+	var out []byte
+	for i := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			out = append(out, ',')
+		}
+		out = append(out, intPart[i])
+	}
+	if neg {
+		return "-" + string(out) + frac
+	}
+	return string(out) + frac
+}
+
+// DemoJitaBuyPrices returns the demo Jita buy prices for all buyback items.
+func DemoJitaBuyPrices() PriceMap {
+	prices := make(PriceMap, len(BuybackItems))
+	for _, item := range BuybackItems {
+		prices[item.TypeID] = item.DemoJitaBuy
+	}
+	return prices
+}
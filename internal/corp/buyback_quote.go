@@ -0,0 +1,306 @@
+package corp
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"eve-flipper/internal/sde"
+)
+
+// Buyback program categories. Items outside every category are "other".
+const (
+	BuybackCategoryOre     = "ore"
+	BuybackCategoryIce     = "ice"
+	BuybackCategoryMineral = "mineral"
+	BuybackCategoryMoon    = "moon"
+	BuybackCategorySalvage = "salvage"
+	BuybackCategoryPI      = "pi"
+	BuybackCategoryGas     = "gas"
+	BuybackCategoryOther   = "other"
+)
+
+// BuybackCategories lists every category a rule can be set for.
+var BuybackCategories = []string{
+	BuybackCategoryOre, BuybackCategoryIce, BuybackCategoryMineral, BuybackCategoryMoon,
+	BuybackCategorySalvage, BuybackCategoryPI, BuybackCategoryGas, BuybackCategoryOther,
+}
+
+// BuybackRules maps a category to the percent of Jita buy the corp pays.
+// A category at 0 (or missing) is not bought.
+type BuybackRules map[string]float64
+
+// DefaultBuybackRules buys raw materials near Jita buy and nothing else.
+func DefaultBuybackRules() BuybackRules {
+	return BuybackRules{
+		BuybackCategoryOre:     DefaultBuybackPercent,
+		BuybackCategoryIce:     DefaultBuybackPercent,
+		BuybackCategoryMineral: DefaultBuybackPercent,
+		BuybackCategoryMoon:    85,
+		BuybackCategorySalvage: 80,
+		BuybackCategoryPI:      85,
+		BuybackCategoryGas:     85,
+		BuybackCategoryOther:   0,
+	}
+}
+
+// NormalizeBuybackRules drops unknown categories and clamps percents; a
+// rule at or below 0 means the category is not bought.
+func NormalizeBuybackRules(rules BuybackRules) BuybackRules {
+	out := make(BuybackRules, len(BuybackCategories))
+	for _, category := range BuybackCategories {
+		pct := rules[category]
+		if pct <= 0 || math.IsNaN(pct) || math.IsInf(pct, 0) {
+			out[category] = 0
+			continue
+		}
+		out[category] = math.Min(math.Max(pct, minBuybackPercent), maxBuybackPercent)
+	}
+	return out
+}
+
+// SDE groups and categories behind the buyback categories.
+const (
+	sdeCategoryAsteroid             = 25
+	sdeCategoryPlanetaryResources   = 42
+	sdeCategoryPlanetaryCommodities = 43
+	sdeGroupMineral                 = 18
+	sdeGroupIceProduct              = 423
+	sdeGroupMoonMaterials           = 427
+	sdeGroupIce                     = 465
+	sdeGroupHarvestableCloud        = 711
+	sdeGroupSalvagedMaterials       = 754
+)
+
+// BuybackCategory classifies an item type for the buyback rules.
+func BuybackCategory(t *sde.ItemType) string {
+	if t == nil {
+		return BuybackCategoryOther
+	}
+	switch {
+	case t.GroupID == sdeGroupIce || t.GroupID == sdeGroupIceProduct:
+		return BuybackCategoryIce
+	case t.GroupID == sdeGroupMineral:
+		return BuybackCategoryMineral
+	case t.GroupID == sdeGroupMoonMaterials:
+		return BuybackCategoryMoon
+	case t.GroupID == sdeGroupSalvagedMaterials:
+		return BuybackCategorySalvage
+	case t.GroupID == sdeGroupHarvestableCloud:
+		return BuybackCategoryGas
+	case t.CategoryID == sdeCategoryAsteroid:
+		return BuybackCategoryOre
+	case t.CategoryID == sdeCategoryPlanetaryResources || t.CategoryID == sdeCategoryPlanetaryCommodities:
+		return BuybackCategoryPI
+	}
+	return BuybackCategoryOther
+}
+
+// BuybackQuoteItem is one resolved line of a pasted item list.
+type BuybackQuoteItem struct {
+	TypeID   int32
+	Name     string
+	Category string
+	Volume   float64 // m³ per unit
+	Quantity int64
+}
+
+// ParseBuybackItems reads a pasted item list: in-game inventory copies
+// ("Name<TAB>Qty<TAB>...") and multibuy-style lines ("Name 120",
+// "Name x120", "120x Name"). Quantities may carry thousands separators; a
+// line without one counts as 1. Duplicate types are summed in first-seen
+// order; lines naming no known type are returned as unknown.
+func ParseBuybackItems(data *sde.Data, text string) (items []BuybackQuoteItem, unknown []string) {
+	items = []BuybackQuoteItem{}
+	unknown = []string{}
+	if data == nil {
+		return items, unknown
+	}
+	index := make(map[int32]int)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, qty := splitBuybackLine(line)
+		if qty <= 0 {
+			continue
+		}
+		typeID, ok := data.TypeIDByName(name)
+		t := data.Types[typeID]
+		if !ok || t == nil {
+			unknown = append(unknown, name)
+			continue
+		}
+		if i, ok := index[typeID]; ok {
+			items[i].Quantity += qty
+			continue
+		}
+		index[typeID] = len(items)
+		items = append(items, BuybackQuoteItem{
+			TypeID:   typeID,
+			Name:     t.Name,
+			Category: BuybackCategory(t),
+			Volume:   t.Volume,
+			Quantity: qty,
+		})
+	}
+	return items, unknown
+}
+
+func splitBuybackLine(line string) (string, int64) {
+	if fields := strings.Split(line, "\t"); len(fields) > 1 {
+		name := strings.TrimSpace(fields[0])
+		if qty, ok := parseBuybackQuantity(fields[1]); ok {
+			return name, qty
+		}
+		return name, 1
+	}
+	words := strings.Fields(line)
+	if len(words) > 1 {
+		if qty, ok := parseBuybackQuantity(strings.TrimPrefix(strings.ToLower(words[len(words)-1]), "x")); ok {
+			return strings.Join(words[:len(words)-1], " "), qty
+		}
+		if qty, ok := parseBuybackQuantity(strings.TrimSuffix(strings.ToLower(words[0]), "x")); ok {
+			return strings.Join(words[1:], " "), qty
+		}
+	}
+	return strings.Join(words, " "), 1
+}
+
+// parseBuybackQuantity parses "1,234", "1.234", "1 234" or "1234".
+func parseBuybackQuantity(s string) (int64, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+	s = strings.NewReplacer(",", "", ".", "", " ", "", " ", "", "'", "").Replace(s)
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// Buyback quote line statuses.
+const (
+	BuybackLineAccepted  = "accepted"
+	BuybackLineNotBought = "not_bought" // the category's rule is 0
+	BuybackLineNoPrice   = "no_price"   // no Jita buy order
+)
+
+// BuybackQuoteLine prices one item of a quote.
+type BuybackQuoteLine struct {
+	TypeID    int32   `json:"type_id"`
+	Name      string  `json:"name"`
+	Category  string  `json:"category"`
+	Quantity  int64   `json:"quantity"`
+	Volume    float64 `json:"volume"` // m³ for the whole line
+	JitaBuy   float64 `json:"jita_buy"`
+	Percent   float64 `json:"percent"`
+	UnitPrice float64 `json:"unit_price"`
+	Total     float64 `json:"total"`
+	Status    string  `json:"status"`
+}
+
+// BuybackQuote is what the corp pays for a pasted item list.
+type BuybackQuote struct {
+	GeneratedAt string             `json:"generated_at"`
+	Source      string             `json:"source"` // price source, as on the price board
+	Rules       BuybackRules       `json:"rules"`
+	Lines       []BuybackQuoteLine `json:"lines"`
+	Total       float64            `json:"total"`
+	JitaValue   float64            `json:"jita_value"` // accepted lines at Jita buy
+	Volume      float64            `json:"volume"`     // accepted lines, m³
+	Rejected    int                `json:"rejected"`
+	Unknown     []string           `json:"unknown"`
+}
+
+// BuildBuybackQuote prices items at their category's percent of Jita buy,
+// rounded to whole ISK per line. Lines are ordered by value, highest first.
+func BuildBuybackQuote(items []BuybackQuoteItem, jitaBuy PriceMap, rules BuybackRules, source string, now time.Time) BuybackQuote {
+	rules = NormalizeBuybackRules(rules)
+	q := BuybackQuote{
+		GeneratedAt: now.UTC().Format(time.RFC3339),
+		Source:      source,
+		Rules:       rules,
+		Lines:       make([]BuybackQuoteLine, 0, len(items)),
+		Unknown:     []string{},
+	}
+	for _, it := range items {
+		line := BuybackQuoteLine{
+			TypeID:   it.TypeID,
+			Name:     it.Name,
+			Category: it.Category,
+			Quantity: it.Quantity,
+			Volume:   it.Volume * float64(it.Quantity),
+			JitaBuy:  jitaBuy[it.TypeID],
+			Percent:  rules[it.Category],
+		}
+		switch {
+		case line.Percent <= 0:
+			line.Status = BuybackLineNotBought
+		case line.JitaBuy <= 0:
+			line.Status = BuybackLineNoPrice
+		default:
+			line.Status = BuybackLineAccepted
+			line.UnitPrice = math.Round(line.JitaBuy*line.Percent) / 100
+			line.Total = math.Round(line.UnitPrice * float64(line.Quantity))
+			q.Total += line.Total
+			q.JitaValue += line.JitaBuy * float64(line.Quantity)
+			q.Volume += line.Volume
+		}
+		if line.Status != BuybackLineAccepted {
+			q.Rejected++
+		}
+		q.Lines = append(q.Lines, line)
+	}
+	sort.SliceStable(q.Lines, func(i, j int) bool { return q.Lines[i].Total > q.Lines[j].Total })
+	return q
+}
+
+// BuybackPaymentRefTypes are the journal entries a corp pays sellers with.
+var BuybackPaymentRefTypes = []string{"player_donation", "corporation_account_withdrawal"}
+
+// buybackPaymentTolerance is how far a payment may be off the quote total,
+// as a share of it; payers often round.
+const buybackPaymentTolerance = 0.01
+
+// MatchBuybackPayment finds the journal payment for an accepted quote: an
+// outgoing entry within tolerance of total, made no earlier than a day
+// before acceptance and, when the seller is known, to that seller. Entries
+// in used are skipped. The closest amount wins, then the earliest entry.
+func MatchBuybackPayment(total float64, sellerID int64, sellerName string, acceptedAt time.Time, entries []CorpJournalEntry, used map[int64]bool) (CorpJournalEntry, bool) {
+	if total <= 0 {
+		return CorpJournalEntry{}, false
+	}
+	earliest := acceptedAt.Add(-24 * time.Hour)
+	var best CorpJournalEntry
+	bestDiff := math.Inf(1)
+	var bestDate time.Time
+	for _, e := range entries {
+		if used[e.ID] || e.Amount >= 0 {
+			continue
+		}
+		diff := math.Abs(-e.Amount - total)
+		if diff > total*buybackPaymentTolerance {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, e.Date)
+		if err != nil || date.Before(earliest) {
+			continue
+		}
+		if sellerID > 0 && e.SecondPartyID != sellerID {
+			continue
+		}
+		if sellerID <= 0 && sellerName != "" && !strings.EqualFold(strings.TrimSpace(e.SecondPartyName), strings.TrimSpace(sellerName)) {
+			continue
+		}
+		if diff < bestDiff || (diff == bestDiff && date.Before(bestDate)) {
+			best, bestDiff, bestDate = e, diff, date
+		}
+	}
+	return best, !math.IsInf(bestDiff, 1)
+}
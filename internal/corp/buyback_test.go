@@ -0,0 +1,118 @@
+package corp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"eve-flipper/internal/sde"
+)
+
+func TestBuildPriceBoard_TracksRateChanges(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	prices := PriceMap{34: 4.00, 35: 10.00}
+
+	first := BuildPriceBoard(prices, 90, "jita_buy", nil, t0)
+	if len(first.Lines) != 2 {
+		t.Fatalf("lines = %d, want 2", len(first.Lines))
+	}
+	if first.Lines[0].BuybackPrice != 3.6 || first.Lines[1].BuybackPrice != 9 {
+		t.Fatalf("prices = %+v", first.Lines)
+	}
+	if first.RatesChangedAt != first.GeneratedAt {
+		t.Errorf("initial board should mark rates changed")
+	}
+
+	t1 := t0.Add(time.Hour)
+	same := BuildPriceBoard(prices, 90, "jita_buy", &first, t1)
+	if same.ChangedCount != 0 || same.RatesChangedAt != first.RatesChangedAt {
+		t.Errorf("unchanged refresh: changed=%d ratesChangedAt=%s", same.ChangedCount, same.RatesChangedAt)
+	}
+	if same.PreviousAt != first.GeneratedAt {
+		t.Errorf("previous_at = %s, want %s", same.PreviousAt, first.GeneratedAt)
+	}
+
+	t2 := t1.Add(time.Hour)
+	moved := BuildPriceBoard(PriceMap{34: 4.40, 35: 10.00}, 90, "jita_buy", &same, t2)
+	if moved.ChangedCount != 1 || moved.RatesChangedAt != t2.Format(time.RFC3339) {
+		t.Fatalf("moved refresh: changed=%d ratesChangedAt=%s", moved.ChangedCount, moved.RatesChangedAt)
+	}
+	trit := moved.Lines[0]
+	if !trit.Changed || trit.PreviousPrice != 3.6 || trit.ChangePct != 10 {
+		t.Errorf("tritanium line = %+v", trit)
+	}
+	if !strings.Contains(moved.MOTD, "▲10.0%") || !strings.Contains(moved.MOTD, "90% of Jita buy") {
+		t.Errorf("motd missing change marker:\n%s", moved.MOTD)
+	}
+}
+
+func TestFormatISKPrice(t *testing.T) {
+	cases := map[float64]string{
+		3.6:       "3.60",
+		1234.5:    "1,234.50",
+		1234567.1: "1,234,567.10",
+	}
+	for in, want := range cases {
+		if got := formatISKPrice(in); got != want {
+			t.Errorf("formatISKPrice(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBuybackQuote_ParsesAndPricesByCategory(t *testing.T) {
+	data := &sde.Data{
+		Types: map[int32]*sde.ItemType{
+			34:    {ID: 34, Name: "Tritanium", Volume: 0.01, GroupID: 18, CategoryID: 4},
+			1230:  {ID: 1230, Name: "Veldspar", Volume: 0.1, GroupID: 462, CategoryID: 25},
+			25595: {ID: 25595, Name: "Alloyed Tritanium Bar", Volume: 0.01, GroupID: 754, CategoryID: 4},
+			587:   {ID: 587, Name: "Rifter", Volume: 2500, GroupID: 25, CategoryID: 6},
+		},
+		TypeByName: map[string]int32{"tritanium": 34, "veldspar": 1230, "alloyed tritanium bar": 25595, "rifter": 587},
+	}
+	items, unknown := ParseBuybackItems(data, "Tritanium\t1,000\tMineral\nveldspar x500\n2 Alloyed Tritanium Bar\nTritanium 500\nRifter\nNot An Item 3\n")
+	if len(items) != 4 || items[0].Quantity != 1500 || items[1].Quantity != 500 || items[2].Quantity != 2 || items[3].Quantity != 1 {
+		t.Fatalf("items = %+v", items)
+	}
+	if len(unknown) != 1 || unknown[0] != "Not An Item" {
+		t.Fatalf("unknown = %v", unknown)
+	}
+	if items[1].Category != BuybackCategoryOre || items[2].Category != BuybackCategorySalvage || items[3].Category != BuybackCategoryOther {
+		t.Fatalf("categories = %+v", items)
+	}
+
+	rules := DefaultBuybackRules()
+	rules[BuybackCategorySalvage] = 50
+	q := BuildBuybackQuote(items, PriceMap{34: 4, 1230: 20, 587: 500000}, rules, "jita_buy", time.Now())
+	// Tritanium 1500 x 3.60 + Veldspar 500 x 18.00; the bar has no price
+	// and the Rifter is not bought.
+	if q.Total != 14400 || q.Rejected != 2 {
+		t.Fatalf("total=%v rejected=%d lines=%+v", q.Total, q.Rejected, q.Lines)
+	}
+	if q.Lines[0].Name != "Veldspar" || q.Lines[0].UnitPrice != 18 {
+		t.Fatalf("top line = %+v", q.Lines[0])
+	}
+	if q.Volume != 65 {
+		t.Fatalf("volume = %v, want 65", q.Volume)
+	}
+}
+
+func TestMatchBuybackPayment(t *testing.T) {
+	accepted := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	entries := []CorpJournalEntry{
+		{ID: 1, Date: "2026-05-08T12:00:00Z", RefType: "player_donation", Amount: -14400, SecondPartyID: 7},
+		{ID: 2, Date: "2026-05-10T13:00:00Z", RefType: "player_donation", Amount: -14400, SecondPartyID: 8},
+		{ID: 3, Date: "2026-05-10T14:00:00Z", RefType: "player_donation", Amount: -14350, SecondPartyID: 7, SecondPartyName: "Alice"},
+		{ID: 4, Date: "2026-05-10T15:00:00Z", RefType: "player_donation", Amount: -14400, SecondPartyID: 7, SecondPartyName: "Alice"},
+	}
+	e, ok := MatchBuybackPayment(14400, 7, "", accepted, entries, nil)
+	if !ok || e.ID != 4 {
+		t.Fatalf("match = %+v %v, want entry 4", e, ok)
+	}
+	e, ok = MatchBuybackPayment(14400, 0, "alice", accepted, entries, map[int64]bool{4: true})
+	if !ok || e.ID != 3 {
+		t.Fatalf("match by name = %+v %v, want entry 3", e, ok)
+	}
+	if _, ok := MatchBuybackPayment(20000, 7, "", accepted, entries, nil); ok {
+		t.Fatal("matched a payment off by more than the tolerance")
+	}
+}
@@ -15,10 +15,21 @@ type PriceMap map[int32]float64
 // BuildDashboard aggregates raw data from a CorpDataProvider into a CorpDashboard.
 // prices may be nil (ISK estimates will fall back to zero).
 func BuildDashboard(provider CorpDataProvider, prices PriceMap) (*CorpDashboard, error) {
+	return BuildDashboardWithLayout(provider, prices, DefaultDashboardLayout())
+}
+
+// BuildDashboardWithLayout is BuildDashboard restricted to the sections enabled
+// in layout. Data sources needed only by disabled sections are not fetched,
+// which keeps ESI load down for corps that only track finances.
+func BuildDashboardWithLayout(provider CorpDataProvider, prices PriceMap, layout DashboardLayout) (*CorpDashboard, error) {
+	layout = layout.Normalize()
 	info := provider.GetInfo()
 	isDemo := provider.IsDemo()
 
-	// ---- Parallel fetch of all data sources ----
+	needJournal := layout.anyEnabled(SectionFinances, SectionProjection, SectionContributors, SectionMembers)
+	needMembers := layout.anyEnabled(SectionContributors, SectionMembers)
+
+	// ---- Parallel fetch of the data sources the enabled sections need ----
 	var (
 		wallets      []CorpWalletDivision
 		walletsErr   error
@@ -27,70 +38,103 @@ func BuildDashboard(provider CorpDataProvider, prices PriceMap) (*CorpDashboard,
 		industryJobs []CorpIndustryJob
 		miningLedger []CorpMiningEntry
 		orders       []CorpMarketOrder
+		assets       []CorpAsset
+		errMu        sync.Mutex
+		sectionErrs  []SectionError
 	)
+	fail := func(source string, err error) {
+		errMu.Lock()
+		defer errMu.Unlock()
+		sectionErrs = append(sectionErrs, newSectionError(source, err))
+	}
 
 	var wg sync.WaitGroup
 
-	// Wallets
+	// Wallets (always: the balance header is not a toggleable section)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		wallets, walletsErr = provider.GetWallets()
 	}()
 
-	// Journal — fetch ALL 7 divisions in parallel and merge
-	var journalMu sync.Mutex
-	for div := 1; div <= 7; div++ {
+	// Journal — all 7 divisions, merged and deduplicated
+	if needJournal {
 		wg.Add(1)
-		go func(d int) {
+		go func() {
 			defer wg.Done()
-			entries, err := provider.GetJournal(d, 90)
-			if err != nil || len(entries) == 0 {
-				return
+			var err error
+			if allJournal, err = fetchAllJournal(provider, 90); err != nil {
+				fail(SourceJournal, err)
 			}
-			journalMu.Lock()
-			allJournal = append(allJournal, entries...)
-			journalMu.Unlock()
-		}(div)
+		}()
 	}
 
 	// Members
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		members, _ = provider.GetMembers()
-	}()
+	if needMembers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if members, err = fetchWithRetry(provider.GetMembers); err != nil {
+				fail(SourceMembers, err)
+			}
+		}()
+	}
 
 	// Industry
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		industryJobs, _ = provider.GetIndustryJobs()
-	}()
+	if layout.Enabled(SectionIndustry) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if industryJobs, err = fetchWithRetry(provider.GetIndustryJobs); err != nil {
+				fail(SourceIndustry, err)
+			}
+		}()
+	}
 
 	// Mining
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		miningLedger, _ = provider.GetMiningLedger()
-	}()
+	if layout.Enabled(SectionMining) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if miningLedger, err = fetchWithRetry(provider.GetMiningLedger); err != nil {
+				fail(SourceMining, err)
+			}
+		}()
+	}
 
 	// Orders
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		orders, _ = provider.GetOrders()
-	}()
+	if layout.Enabled(SectionMarket) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if orders, err = fetchWithRetry(provider.GetOrders); err != nil {
+				fail(SourceOrders, err)
+			}
+		}()
+	}
+
+	// Assets (structure fuel bays)
+	if layout.Enabled(SectionFuel) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if assets, err = fetchWithRetry(provider.GetAssets); err != nil {
+				fail(SourceAssets, err)
+			}
+		}()
+	}
 
 	wg.Wait()
 
 	if walletsErr != nil {
 		return nil, walletsErr
 	}
-
-	// Deduplicate journal entries (same entry may appear in multiple division fetches
-	// if the provider returns corp-wide entries). Deduplicate by entry ID.
-	allJournal = deduplicateJournal(allJournal)
+	sort.Slice(sectionErrs, func(i, j int) bool { return sectionErrs[i].Source < sectionErrs[j].Source })
 
 	totalBalance := 0.0
 	for _, w := range wallets {
@@ -101,69 +145,128 @@ func BuildDashboard(provider CorpDataProvider, prices PriceMap) (*CorpDashboard,
 	day7ago := now.AddDate(0, 0, -7).Format("2006-01-02")
 	day30ago := now.AddDate(0, 0, -30).Format("2006-01-02")
 
-	// ---- Revenue / Expenses (from aggregated journal) ----
-	var rev7, exp7, rev30, exp30 float64
-	for _, e := range allJournal {
-		if len(e.Date) < 10 {
-			continue
-		}
-		dateOnly := e.Date[:10]
-		if dateOnly >= day30ago {
-			if e.Amount > 0 {
-				rev30 += e.Amount
-			} else {
-				exp30 += e.Amount
+	d := &CorpDashboard{
+		Info:          info,
+		IsDemo:        isDemo,
+		Layout:        layout,
+		Wallets:       wallets,
+		TotalBalance:  totalBalance,
+		SectionErrors: sectionErrs,
+		journalFrom:   oldestJournalDate(allJournal),
+	}
+
+	if layout.Enabled(SectionFinances) {
+		// ---- Revenue / Expenses (from aggregated journal) ----
+		var rev7, exp7, rev30, exp30 float64
+		for _, e := range allJournal {
+			if len(e.Date) < 10 {
+				continue
 			}
-		}
-		if dateOnly >= day7ago {
-			if e.Amount > 0 {
-				rev7 += e.Amount
-			} else {
-				exp7 += e.Amount
+			dateOnly := e.Date[:10]
+			if dateOnly >= day30ago {
+				if e.Amount > 0 {
+					rev30 += e.Amount
+				} else {
+					exp30 += e.Amount
+				}
+			}
+			if dateOnly >= day7ago {
+				if e.Amount > 0 {
+					rev7 += e.Amount
+				} else {
+					exp7 += e.Amount
+				}
 			}
 		}
-	}
+		d.Revenue30d, d.Expenses30d, d.NetIncome30d = rev30, exp30, rev30+exp30
+		d.Revenue7d, d.Expenses7d, d.NetIncome7d = rev7, exp7, rev7+exp7
 
-	// ---- Income by source ----
-	incomeBySource := computeIncomeBySource(allJournal, day30ago)
+		// ---- Income by source ----
+		d.IncomeBySource = computeIncomeBySource(allJournal, day30ago)
+	}
 
-	// ---- Daily P&L ----
-	dailyPnL := computeDailyPnL(allJournal, 90, now)
+	// ---- Daily P&L (also feeds the projection) ----
+	if layout.anyEnabled(SectionFinances, SectionProjection) {
+		dailyPnL := computeDailyPnL(allJournal, 90, now)
+		if layout.Enabled(SectionFinances) {
+			d.DailyPnL = dailyPnL
+		}
+		// ---- Income projection (next 30 days, from daily P&L) ----
+		if layout.Enabled(SectionProjection) {
+			d.IncomeProjection = computeIncomeProjection(dailyPnL, projectionDays, now)
+		}
+	}
 
 	// ---- Top Contributors (from journal: who generates ISK for the corp) ----
-	topContributors := computeTopContributors(allJournal, members, day30ago)
+	if layout.Enabled(SectionContributors) {
+		d.TopContributors = computeTopContributors(allJournal, members, day30ago)
+	}
 
 	// ---- Member Summary (hybrid: journal-based categorization + ship fallback) ----
-	memberSummary := computeMemberSummary(members, allJournal, now)
+	if layout.Enabled(SectionMembers) {
+		d.MemberSummary = computeMemberSummary(members, allJournal, now)
+	}
 
 	// ---- Industry Summary (with ISK estimation) ----
-	industrySummary := computeIndustrySummary(industryJobs, prices, now)
+	if layout.Enabled(SectionIndustry) {
+		d.IndustrySummary = computeIndustrySummary(industryJobs, prices, now)
+	}
 
 	// ---- Mining Summary (with ISK estimation) ----
-	miningSummary := computeMiningSummary(miningLedger, prices)
+	if layout.Enabled(SectionMining) {
+		d.MiningSummary = computeMiningSummary(miningLedger, prices)
+	}
 
 	// ---- Market Summary ----
-	marketSummary := computeMarketSummary(orders)
-
-	return &CorpDashboard{
-		Info:            info,
-		IsDemo:          isDemo,
-		Wallets:         wallets,
-		TotalBalance:    totalBalance,
-		Revenue30d:      rev30,
-		Expenses30d:     exp30,
-		NetIncome30d:    rev30 + exp30,
-		Revenue7d:       rev7,
-		Expenses7d:      exp7,
-		NetIncome7d:     rev7 + exp7,
-		IncomeBySource:  incomeBySource,
-		DailyPnL:        dailyPnL,
-		TopContributors: topContributors,
-		MemberSummary:   memberSummary,
-		IndustrySummary: industrySummary,
-		MiningSummary:   miningSummary,
-		MarketSummary:   marketSummary,
-	}, nil
+	if layout.Enabled(SectionMarket) {
+		d.MarketSummary = computeMarketSummary(orders)
+	}
+
+	// ---- Fuel Summary (structures + starbases from assets) ----
+	if layout.Enabled(SectionFuel) {
+		d.FuelSummary = computeFuelSummary(assets, now)
+	}
+
+	return d, nil
+}
+
+// fetchAllJournal fetches all 7 wallet divisions in parallel and merges them.
+// The same entry may appear in multiple division fetches if the provider
+// returns corp-wide entries, so the result is deduplicated by entry ID.
+// Divisions that fail are skipped; an error is returned only when every
+// division failed.
+func fetchAllJournal(provider CorpDataProvider, days int) ([]CorpJournalEntry, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		all      []CorpJournalEntry
+		firstErr error
+		ok       int
+	)
+	for div := 1; div <= 7; div++ {
+		wg.Add(1)
+		go func(d int) {
+			defer wg.Done()
+			entries, err := fetchWithRetry(func() ([]CorpJournalEntry, error) {
+				return provider.GetJournal(d, days)
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			ok++
+			all = append(all, entries...)
+		}(div)
+	}
+	wg.Wait()
+	if ok == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return deduplicateJournal(all), nil
 }
 
 // deduplicateJournal removes duplicate journal entries by ID.
@@ -269,6 +372,20 @@ func computeIncomeBySource(journal []CorpJournalEntry, since string) []IncomeSou
 // Daily P&L
 // ============================================================
 
+// oldestJournalDate returns the date of the earliest entry, or "" for none.
+func oldestJournalDate(journal []CorpJournalEntry) string {
+	oldest := ""
+	for _, e := range journal {
+		if len(e.Date) < 10 {
+			continue
+		}
+		if date := e.Date[:10]; oldest == "" || date < oldest {
+			oldest = date
+		}
+	}
+	return oldest
+}
+
 func computeDailyPnL(journal []CorpJournalEntry, days int, now time.Time) []DailyPnLEntry {
 	dailyMap := make(map[string]*DailyPnLEntry)
 
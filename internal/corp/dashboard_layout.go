@@ -0,0 +1,91 @@
+package corp
+
+// Dashboard sections. Each one maps to the provider calls it needs, so a
+// disabled section is neither fetched nor computed.
+const (
+	SectionFinances     = "finances"     // revenue/expenses, income by source, daily P&L (journal)
+	SectionContributors = "contributors" // top contributors (journal + members)
+	SectionMembers      = "members"      // member summary (members + journal)
+	SectionIndustry     = "industry"     // industry jobs
+	SectionMining       = "mining"       // mining ledger
+	SectionMarket       = "market"       // corp market orders
+	SectionFuel         = "fuel"         // structure fuel (assets)
+	SectionProjection   = "projection"   // 30-day income projection (journal)
+)
+
+// DashboardSections lists every section in the default display order.
+var DashboardSections = []string{
+	SectionFinances,
+	SectionProjection,
+	SectionContributors,
+	SectionMembers,
+	SectionIndustry,
+	SectionMining,
+	SectionMarket,
+	SectionFuel,
+}
+
+// DashboardLayout is a user's dashboard preference: display order plus the
+// sections turned off. Wallet balances are always shown.
+type DashboardLayout struct {
+	Order    []string `json:"order"`
+	Disabled []string `json:"disabled"`
+}
+
+// DefaultDashboardLayout enables every section in the default order.
+func DefaultDashboardLayout() DashboardLayout {
+	return DashboardLayout{
+		Order:    append([]string(nil), DashboardSections...),
+		Disabled: []string{},
+	}
+}
+
+// Normalize drops unknown and duplicate section names, and appends any known
+// section missing from Order so newly added sections still show up.
+func (l DashboardLayout) Normalize() DashboardLayout {
+	known := make(map[string]bool, len(DashboardSections))
+	for _, s := range DashboardSections {
+		known[s] = true
+	}
+	out := DashboardLayout{Order: []string{}, Disabled: []string{}}
+	seen := make(map[string]bool, len(DashboardSections))
+	for _, s := range l.Order {
+		if known[s] && !seen[s] {
+			seen[s] = true
+			out.Order = append(out.Order, s)
+		}
+	}
+	for _, s := range DashboardSections {
+		if !seen[s] {
+			out.Order = append(out.Order, s)
+		}
+	}
+	disabled := make(map[string]bool, len(l.Disabled))
+	for _, s := range l.Disabled {
+		if known[s] && !disabled[s] {
+			disabled[s] = true
+			out.Disabled = append(out.Disabled, s)
+		}
+	}
+	return out
+}
+
+// Enabled reports whether a section should be fetched and rendered.
+func (l DashboardLayout) Enabled(section string) bool {
+	for _, s := range l.Disabled {
+		if s == section {
+			return false
+		}
+	}
+	return true
+}
+
+// anyEnabled reports whether at least one of the sections is enabled.
+func (l DashboardLayout) anyEnabled(sections ...string) bool {
+	for _, s := range sections {
+		if l.Enabled(s) {
+			return true
+		}
+	}
+	return false
+}
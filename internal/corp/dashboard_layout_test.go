@@ -0,0 +1,98 @@
+package corp
+
+import (
+	"sync"
+	"testing"
+)
+
+// countingProvider records which provider calls a dashboard build made.
+type countingProvider struct {
+	*DemoCorpProvider
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (p *countingProvider) hit(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls[name]++
+}
+
+func (p *countingProvider) GetJournal(division, days int) ([]CorpJournalEntry, error) {
+	p.hit("journal")
+	return p.DemoCorpProvider.GetJournal(division, days)
+}
+
+func (p *countingProvider) GetMembers() ([]CorpMember, error) {
+	p.hit("members")
+	return p.DemoCorpProvider.GetMembers()
+}
+
+func (p *countingProvider) GetIndustryJobs() ([]CorpIndustryJob, error) {
+	p.hit("industry")
+	return p.DemoCorpProvider.GetIndustryJobs()
+}
+
+func (p *countingProvider) GetMiningLedger() ([]CorpMiningEntry, error) {
+	p.hit("mining")
+	return p.DemoCorpProvider.GetMiningLedger()
+}
+
+func (p *countingProvider) GetOrders() ([]CorpMarketOrder, error) {
+	p.hit("orders")
+	return p.DemoCorpProvider.GetOrders()
+}
+
+func (p *countingProvider) GetAssets() ([]CorpAsset, error) {
+	p.hit("assets")
+	return p.DemoCorpProvider.GetAssets()
+}
+
+func TestBuildDashboardWithLayout_SkipsDisabledFetches(t *testing.T) {
+	p := &countingProvider{DemoCorpProvider: NewDemoCorpProvider(), calls: map[string]int{}}
+	layout := DashboardLayout{Disabled: []string{
+		SectionContributors, SectionMembers, SectionIndustry, SectionMining, SectionMarket, SectionFuel,
+	}}
+	d, err := BuildDashboardWithLayout(p, nil, layout)
+	if err != nil {
+		t.Fatalf("BuildDashboardWithLayout: %v", err)
+	}
+	if p.calls["journal"] == 0 {
+		t.Error("finances enabled but journal not fetched")
+	}
+	for _, name := range []string{"members", "industry", "mining", "orders", "assets"} {
+		if p.calls[name] != 0 {
+			t.Errorf("%s fetched %d times for a disabled section", name, p.calls[name])
+		}
+	}
+	if len(d.DailyPnL) == 0 || d.TopContributors != nil || d.IndustrySummary.ActiveJobs != 0 {
+		t.Errorf("dashboard sections not restricted: pnl=%d contributors=%v", len(d.DailyPnL), d.TopContributors)
+	}
+	if len(d.Layout.Order) != len(DashboardSections) || d.Layout.Enabled(SectionMining) {
+		t.Errorf("layout = %+v", d.Layout)
+	}
+}
+
+func TestBuildDashboardWithLayout_FinancesOffSkipsJournal(t *testing.T) {
+	p := &countingProvider{DemoCorpProvider: NewDemoCorpProvider(), calls: map[string]int{}}
+	layout := DashboardLayout{Disabled: []string{SectionFinances, SectionProjection, SectionContributors, SectionMembers}}
+	if _, err := BuildDashboardWithLayout(p, nil, layout); err != nil {
+		t.Fatalf("BuildDashboardWithLayout: %v", err)
+	}
+	if p.calls["journal"] != 0 || p.calls["members"] != 0 {
+		t.Errorf("calls = %v, want no journal/members fetches", p.calls)
+	}
+	if p.calls["industry"] != 1 || p.calls["assets"] != 1 {
+		t.Errorf("calls = %v, want enabled sections fetched once", p.calls)
+	}
+}
+
+func TestDashboardLayoutNormalize(t *testing.T) {
+	l := DashboardLayout{Order: []string{SectionFuel, "x", SectionFuel}, Disabled: []string{"x", SectionFuel, SectionFuel}}.Normalize()
+	if l.Order[0] != SectionFuel || len(l.Order) != len(DashboardSections) {
+		t.Errorf("order = %v", l.Order)
+	}
+	if len(l.Disabled) != 1 || l.Enabled(SectionFuel) || !l.Enabled(SectionFinances) {
+		t.Errorf("disabled = %v", l.Disabled)
+	}
+}
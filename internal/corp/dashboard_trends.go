@@ -0,0 +1,212 @@
+package corp
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// DashboardSnapshot is one persisted day of dashboard figures. Balance and
+// member counts exist only for days the dashboard was actually built
+// (Captured); P&L is backfilled from the 90-day journal window (HasPnL), so
+// it survives after ESI history rolls off.
+type DashboardSnapshot struct {
+	Date          string  `json:"date"` // YYYY-MM-DD
+	Captured      bool    `json:"captured"`
+	TotalBalance  float64 `json:"total_balance"`
+	Members       int     `json:"members"`
+	ActiveMembers int     `json:"active_members"` // active in the last 30 days; 0 if unknown
+	HasPnL        bool    `json:"has_pnl"`
+	Revenue       float64 `json:"revenue"`
+	Expenses      float64 `json:"expenses"` // negative
+	NetIncome     float64 `json:"net_income"`
+	Transactions  int     `json:"transactions"`
+}
+
+// DashboardSnapshots extracts the rows worth persisting from a freshly built
+// dashboard: today's balance and member counts, and the P&L of the days the
+// journal fully covers when the finances section loaded cleanly. Days up to
+// and including the oldest journal entry's are left out: they are zero fill
+// or partial, and would overwrite complete figures stored earlier.
+func DashboardSnapshots(d *CorpDashboard, now time.Time) []DashboardSnapshot {
+	if d == nil {
+		return nil
+	}
+	today := now.UTC().Format("2006-01-02")
+	rows := make(map[string]*DashboardSnapshot)
+	row := func(date string) *DashboardSnapshot {
+		r, ok := rows[date]
+		if !ok {
+			r = &DashboardSnapshot{Date: date}
+			rows[date] = r
+		}
+		return r
+	}
+
+	journalOK, membersOK := true, true
+	for _, e := range d.SectionErrors {
+		switch e.Source {
+		case SourceJournal:
+			journalOK = false
+		case SourceMembers:
+			membersOK = false
+		}
+	}
+
+	t := row(today)
+	t.Captured = true
+	t.TotalBalance = d.TotalBalance
+	t.Members = d.Info.MemberCount
+	if d.Layout.Enabled(SectionMembers) && journalOK && membersOK {
+		if d.MemberSummary.TotalMembers > 0 {
+			t.Members = d.MemberSummary.TotalMembers
+		}
+		t.ActiveMembers = d.MemberSummary.ActiveLast30d
+	}
+
+	if d.Layout.Enabled(SectionFinances) && journalOK {
+		for _, p := range d.DailyPnL {
+			if d.journalFrom == "" || p.Date <= d.journalFrom {
+				continue
+			}
+			r := row(p.Date)
+			r.HasPnL = true
+			r.Revenue = p.Revenue
+			r.Expenses = p.Expenses
+			r.NetIncome = p.NetIncome
+			r.Transactions = p.Transactions
+		}
+	}
+
+	out := make([]DashboardSnapshot, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Date < out[j].Date })
+	return out
+}
+
+// DashboardTrends rolls daily snapshots up into calendar months, newest
+// last, with a month-over-month comparison of the two most recent months.
+type DashboardTrends struct {
+	Months         []MonthlyTrend `json:"months"`
+	MonthOverMonth MonthOverMonth `json:"month_over_month"`
+	FirstSnapshot  string         `json:"first_snapshot,omitempty"`
+	SnapshotDays   int            `json:"snapshot_days"` // days with a captured balance
+}
+
+// MonthlyTrend is one calendar month. Balance and member figures are the
+// last captured values in the month and nil when none was captured.
+type MonthlyTrend struct {
+	Month          string   `json:"month"` // YYYY-MM
+	Revenue        float64  `json:"revenue"`
+	Expenses       float64  `json:"expenses"`
+	NetIncome      float64  `json:"net_income"`
+	Transactions   int      `json:"transactions"`
+	PnLDays        int      `json:"pnl_days"` // days with P&L data
+	ClosingBalance *float64 `json:"closing_balance"`
+	Members        *int     `json:"members"`
+	ActiveMembers  *int     `json:"active_members"`
+}
+
+// MonthOverMonth compares Month against PreviousMonth. A delta is nil when
+// either month lacks the figure.
+type MonthOverMonth struct {
+	Month         string      `json:"month"`
+	PreviousMonth string      `json:"previous_month"`
+	Revenue       *TrendDelta `json:"revenue"`
+	Expenses      *TrendDelta `json:"expenses"`
+	NetIncome     *TrendDelta `json:"net_income"`
+	Balance       *TrendDelta `json:"balance"`
+	Members       *TrendDelta `json:"members"`
+}
+
+// TrendDelta is a change between two months. ChangePct is nil when the
+// previous value is zero.
+type TrendDelta struct {
+	Current   float64  `json:"current"`
+	Previous  float64  `json:"previous"`
+	Change    float64  `json:"change"`
+	ChangePct *float64 `json:"change_pct"`
+}
+
+// BuildDashboardTrends buckets snapshots into the last `months` calendar
+// months ending with now's month (default 12). Months without any data are
+// still listed so charts keep a regular axis.
+func BuildDashboardTrends(snapshots []DashboardSnapshot, months int, now time.Time) DashboardTrends {
+	if months <= 0 {
+		months = 12
+	}
+	now = now.UTC()
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(months - 1), 0)
+
+	t := DashboardTrends{Months: make([]MonthlyTrend, months)}
+	index := make(map[string]int, months)
+	for i := range t.Months {
+		key := first.AddDate(0, i, 0).Format("2006-01")
+		t.Months[i].Month = key
+		index[key] = i
+	}
+
+	sorted := append([]DashboardSnapshot(nil), snapshots...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+	for _, s := range sorted {
+		if len(s.Date) < 10 {
+			continue
+		}
+		if s.Captured {
+			t.SnapshotDays++
+		}
+		if t.FirstSnapshot == "" && (s.Captured || s.HasPnL) {
+			t.FirstSnapshot = s.Date
+		}
+		i, ok := index[s.Date[:7]]
+		if !ok {
+			continue
+		}
+		m := &t.Months[i]
+		if s.HasPnL {
+			m.Revenue += s.Revenue
+			m.Expenses += s.Expenses
+			m.NetIncome += s.NetIncome
+			m.Transactions += s.Transactions
+			m.PnLDays++
+		}
+		if s.Captured {
+			balance, members := s.TotalBalance, s.Members
+			m.ClosingBalance = &balance
+			m.Members = &members
+			if s.ActiveMembers > 0 {
+				active := s.ActiveMembers
+				m.ActiveMembers = &active
+			}
+		}
+	}
+
+	if months >= 2 {
+		cur, prev := t.Months[months-1], t.Months[months-2]
+		mom := MonthOverMonth{Month: cur.Month, PreviousMonth: prev.Month}
+		if cur.PnLDays > 0 && prev.PnLDays > 0 {
+			mom.Revenue = newTrendDelta(cur.Revenue, prev.Revenue)
+			mom.Expenses = newTrendDelta(cur.Expenses, prev.Expenses)
+			mom.NetIncome = newTrendDelta(cur.NetIncome, prev.NetIncome)
+		}
+		if cur.ClosingBalance != nil && prev.ClosingBalance != nil {
+			mom.Balance = newTrendDelta(*cur.ClosingBalance, *prev.ClosingBalance)
+		}
+		if cur.Members != nil && prev.Members != nil {
+			mom.Members = newTrendDelta(float64(*cur.Members), float64(*prev.Members))
+		}
+		t.MonthOverMonth = mom
+	}
+	return t
+}
+
+func newTrendDelta(current, previous float64) *TrendDelta {
+	d := &TrendDelta{Current: current, Previous: previous, Change: current - previous}
+	if previous != 0 {
+		pct := math.Round((current-previous)/math.Abs(previous)*1000) / 10
+		d.ChangePct = &pct
+	}
+	return d
+}
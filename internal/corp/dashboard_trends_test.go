@@ -0,0 +1,80 @@
+package corp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDashboardSnapshots(t *testing.T) {
+	now := time.Date(2026, 6, 2, 15, 0, 0, 0, time.UTC)
+	d := &CorpDashboard{
+		Info:          CorpInfo{CorporationID: 1, MemberCount: 9},
+		Layout:        DefaultDashboardLayout(),
+		TotalBalance:  500,
+		MemberSummary: MemberSummary{TotalMembers: 8, ActiveLast30d: 5},
+		DailyPnL: []DailyPnLEntry{
+			{Date: "2026-05-30"},
+			{Date: "2026-05-31", Revenue: 1, NetIncome: 1, Transactions: 1},
+			{Date: "2026-06-01", Revenue: 10, Expenses: -4, NetIncome: 6, Transactions: 2},
+			{Date: "2026-06-02", Revenue: 3, NetIncome: 3, Transactions: 1},
+		},
+		// The 30th is zero fill and the 31st only partly covered.
+		journalFrom: "2026-05-31",
+	}
+
+	rows := DashboardSnapshots(d, now)
+	if len(rows) != 2 || rows[0].Captured || !rows[0].HasPnL || rows[0].NetIncome != 6 {
+		t.Fatalf("rows = %+v", rows)
+	}
+	if today := rows[1]; !today.Captured || !today.HasPnL || today.TotalBalance != 500 || today.Members != 8 || today.ActiveMembers != 5 {
+		t.Fatalf("today = %+v", today)
+	}
+
+	// A failed journal fetch must not persist zero P&L or activity.
+	d.SectionErrors = []SectionError{{Source: SourceJournal}}
+	rows = DashboardSnapshots(d, now)
+	if len(rows) != 1 || rows[0].HasPnL || rows[0].Members != 9 || rows[0].ActiveMembers != 0 {
+		t.Fatalf("rows with journal error = %+v", rows)
+	}
+}
+
+func TestBuildDashboardTrends(t *testing.T) {
+	now := time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC)
+	snaps := []DashboardSnapshot{
+		{Date: "2025-01-15", Captured: true, TotalBalance: 1}, // outside the window
+		{Date: "2026-04-30", Captured: true, TotalBalance: 100, Members: 10, HasPnL: true, Revenue: 50, Expenses: -10, NetIncome: 40},
+		{Date: "2026-05-02", HasPnL: true, Revenue: 100, Expenses: -20, NetIncome: 80, Transactions: 3},
+		{Date: "2026-05-31", Captured: true, TotalBalance: 200, Members: 12, ActiveMembers: 7},
+		{Date: "2026-05-20", Captured: true, TotalBalance: 150, Members: 11},
+		{Date: "2026-06-01", Captured: true, TotalBalance: 300, Members: 12, HasPnL: true, Revenue: 150, Expenses: -30, NetIncome: 120},
+	}
+
+	tr := BuildDashboardTrends(snaps, 3, now)
+	if len(tr.Months) != 3 || tr.Months[0].Month != "2026-04" || tr.Months[2].Month != "2026-06" {
+		t.Fatalf("months = %+v", tr.Months)
+	}
+	if tr.FirstSnapshot != "2025-01-15" || tr.SnapshotDays != 5 {
+		t.Fatalf("first = %q, days = %d", tr.FirstSnapshot, tr.SnapshotDays)
+	}
+	may := tr.Months[1]
+	if may.Revenue != 100 || may.PnLDays != 1 || *may.ClosingBalance != 200 || *may.Members != 12 || *may.ActiveMembers != 7 {
+		t.Fatalf("may = %+v", may)
+	}
+	if tr.Months[0].ActiveMembers != nil {
+		t.Fatalf("april active members should be unknown")
+	}
+
+	mom := tr.MonthOverMonth
+	if mom.Month != "2026-06" || mom.PreviousMonth != "2026-05" {
+		t.Fatalf("mom = %+v", mom)
+	}
+	if mom.Revenue == nil || mom.Revenue.Change != 50 || *mom.Revenue.ChangePct != 50 {
+		t.Fatalf("revenue delta = %+v", mom.Revenue)
+	}
+	if mom.Balance == nil || mom.Balance.Change != 100 || mom.Members.Change != 0 {
+		t.Fatalf("balance/members = %+v %+v", mom.Balance, mom.Members)
+	}
+	if *mom.Expenses.ChangePct != -50 {
+		t.Fatalf("expense change = %v", *mom.Expenses.ChangePct)
+	}
+}
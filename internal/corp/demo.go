@@ -35,6 +35,21 @@ func (d *DemoCorpProvider) DemoPrices() PriceMap {
 	for _, ore := range miningOres {
 		prices[ore.typeID] = ore.iskPerUnit
 	}
+	for _, obs := range demoMoonObservers {
+		for _, ore := range obs.ores {
+			prices[ore.typeID] = ore.iskPerUnit
+		}
+	}
+	// Minerals and moon materials, for refined moon ore values
+	for typeID, price := range map[int32]float64{
+		34: 4, 35: 10, 36: 60, 37: 150, 38: 700, 39: 1600, 40: 2200,
+		16633: 80, 16634: 60, 16635: 60, 16636: 60,
+		16637: 350, 16638: 300, 16639: 200, 16640: 230,
+		16641: 450, 16642: 900, 16643: 1500, 16644: 1200,
+		16646: 2500, 16647: 1800, 16648: 2100, 16649: 2200,
+	} {
+		prices[typeID] = price
+	}
 	// Industry product prices (approximate)
 	for _, prod := range industryProducts {
 		switch prod.productName {
@@ -488,6 +503,9 @@ func (d *DemoCorpProvider) generateMembers() []CorpMember {
 		return archetypeCasual
 	}
 
+	// Join dates use their own stream so the rest of the roster stays stable.
+	joinRng := rand.New(rand.NewSource(424242 + 50))
+
 	members := make([]CorpMember, 0, len(demoNames))
 	for i, name := range demoNames {
 		arch := pickArchetype()
@@ -502,6 +520,7 @@ func (d *DemoCorpProvider) generateMembers() []CorpMember {
 			daysAgo = 30 + rng.Intn(60) // very inactive
 		}
 		lastLogin := d.now.AddDate(0, 0, -daysAgo).Format(time.RFC3339)
+		joinedAt := d.now.AddDate(0, 0, -(daysAgo + joinRng.Intn(540))).Format(time.RFC3339)
 
 		// Pick a ship for the archetype
 		ships := archetypeShips[arch]
@@ -524,6 +543,7 @@ func (d *DemoCorpProvider) generateMembers() []CorpMember {
 			Name:        name,
 			LastLogin:   lastLogin,
 			LogoffDate:  lastLogin,
+			JoinedAt:    joinedAt,
 			ShipTypeID:  ship.typeID,
 			ShipName:    ship.name,
 			LocationID:  60003760, // simplified
@@ -635,11 +655,13 @@ func (d *DemoCorpProvider) GetIndustryJobs() ([]CorpIndustryJob, error) {
 // Mining Ledger
 // ============================================================
 
-var miningOres = []struct {
+type miningOre struct {
 	typeID     int32
 	name       string
 	iskPerUnit float64 // approximate adjusted price for demo ISK estimation
-}{
+}
+
+var miningOres = []miningOre{
 	{1230, "Veldspar", 4.5},
 	{1228, "Scordite", 8.0},
 	{1224, "Kernite", 45.0},
@@ -695,6 +717,8 @@ func (d *DemoCorpProvider) GetMiningLedger() ([]CorpMiningEntry, error) {
 		}
 	}
 
+	entries = append(entries, d.demoMoonMining(miners)...)
+
 	sort.Slice(entries, func(i, j int) bool {
 		return entries[i].Date < entries[j].Date
 	})
@@ -702,6 +726,56 @@ func (d *DemoCorpProvider) GetMiningLedger() ([]CorpMiningEntry, error) {
 	return entries, nil
 }
 
+// demoMoonObservers are two refineries with weekly and fortnightly
+// extractions, each chunk mined out over three days.
+var demoMoonObservers = []struct {
+	id        int64
+	name      string
+	cycleDays int
+	offset    int // days since the latest chunk arrived
+	ores      []miningOre
+}{
+	{1035466617946, "Void Horizons - Moon Drill Alpha", 7, 2, []miningOre{
+		{45490, "Zeolites", 60.0},
+		{45491, "Sylvite", 70.0},
+		{45494, "Cobaltite", 200.0},
+	}},
+	{1035466617947, "Void Horizons - Moon Drill Beta", 14, 5, []miningOre{
+		{45495, "Euxenite", 210.0},
+		{45501, "Chromite", 330.0},
+		{45510, "Xenotime", 750.0},
+	}},
+}
+
+func (d *DemoCorpProvider) demoMoonMining(miners []CorpMember) []CorpMiningEntry {
+	rng := rand.New(rand.NewSource(424242 + 6500))
+	var entries []CorpMiningEntry
+	for _, obs := range demoMoonObservers {
+		for arrival := obs.offset; arrival < 30; arrival += obs.cycleDays {
+			for k := 0; k < 3 && arrival-k >= 0; k++ {
+				dateStr := d.now.AddDate(0, 0, -(arrival - k)).Format("2006-01-02")
+				for _, miner := range miners {
+					if rng.Float64() < 0.5 {
+						continue
+					}
+					ore := obs.ores[rng.Intn(len(obs.ores))]
+					entries = append(entries, CorpMiningEntry{
+						CharacterID:   miner.CharacterID,
+						CharacterName: miner.Name,
+						Date:          dateStr,
+						TypeID:        ore.typeID,
+						TypeName:      ore.name,
+						Quantity:      int64(2000 + rng.Intn(20000)),
+						ObserverID:    obs.id,
+						ObserverName:  obs.name,
+					})
+				}
+			}
+		}
+	}
+	return entries
+}
+
 // ============================================================
 // Market Orders
 // ============================================================
@@ -761,3 +835,271 @@ func (d *DemoCorpProvider) GetOrders() ([]CorpMarketOrder, error) {
 
 	return orders, nil
 }
+
+// ============================================================
+// Assets (structures + fuel bays)
+// ============================================================
+
+var demoStructures = []struct {
+	itemID   int64
+	typeID   int32
+	typeName string
+	name     string
+	fuel     int32   // fuel blocks in bay
+	services []int32 // fitted service module type IDs
+}{
+	{1035466617946, 35833, "Fortizar", "Y-2ANO - Void Horizons Keep", 52_000, []int32{35892, 35894, 35878}},
+	{1035466617947, 35826, "Azbel", "Y-2ANO - Void Horizons Production Facility", 6_400, []int32{35878, 35881, 35891}},
+	{1035466617948, 35835, "Athanor", "J5A-IX - Moon Drill Alpha", 1_100, []int32{45009, 45537}},
+	{1035466617949, 35832, "Astrahus", "3-DMQT - Forward Staging", 0, nil},
+	{1035466617950, 16213, "Caldari Control Tower", "PNQY-Y - Research POS", 2_800, nil},
+}
+
+func (d *DemoCorpProvider) GetAssets() ([]CorpAsset, error) {
+	var assets []CorpAsset
+	itemID := int64(1040000000000)
+
+	for i, st := range demoStructures {
+		sys := demoSystems[i%len(demoSystems)]
+		assets = append(assets, CorpAsset{
+			ItemID:       st.itemID,
+			TypeID:       st.typeID,
+			TypeName:     st.typeName,
+			LocationID:   int64(sys.systemID),
+			LocationFlag: "AutoFit",
+			LocationType: "solar_system",
+			Quantity:     1,
+			IsSingleton:  true,
+			ItemName:     st.name,
+		})
+
+		fuelFlag := "StructureFuel"
+		if info, ok := structureHulls[st.typeID]; ok && info.kind == "starbase" {
+			fuelFlag = "Unlocked"
+		}
+		if st.fuel > 0 {
+			itemID++
+			assets = append(assets, CorpAsset{
+				ItemID:       itemID,
+				TypeID:       4051,
+				TypeName:     fuelBlockTypes[4051],
+				LocationID:   st.itemID,
+				LocationFlag: fuelFlag,
+				LocationType: "item",
+				Quantity:     st.fuel,
+			})
+		}
+
+		for slot, svcTypeID := range st.services {
+			itemID++
+			assets = append(assets, CorpAsset{
+				ItemID:       itemID,
+				TypeID:       svcTypeID,
+				TypeName:     serviceModuleFuel[svcTypeID].name,
+				LocationID:   st.itemID,
+				LocationFlag: fmt.Sprintf("ServiceSlot%d", slot),
+				LocationType: "item",
+				Quantity:     1,
+				IsSingleton:  true,
+			})
+		}
+	}
+
+	return assets, nil
+}
+
+// ============================================================
+// Contracts (couriers + item exchanges)
+// ============================================================
+
+var demoContractLocations = []struct {
+	id   int64
+	name string
+}{
+	{60003760, "Jita IV - Moon 4 - Caldari Navy Assembly Plant"},
+	{60008494, "Amarr VIII (Oris) - Emperor Family Academy"},
+	{1035466617946, "Y-2ANO - Void Horizons Keep"},
+	{1035466617947, "Y-2ANO - Void Horizons Production Facility"},
+}
+
+var demoItemExchanges = []struct {
+	title    string
+	minPrice float64
+	maxPrice float64
+	fromCorp bool // corp sells to members (doctrine ships); otherwise members sell to corp
+}{
+	{"Doctrine Muninn", 240_000_000, 280_000_000, true},
+	{"Doctrine Scimitar", 290_000_000, 330_000_000, true},
+	{"Doctrine Sabre", 60_000_000, 75_000_000, true},
+	{"Ore buyback", 20_000_000, 180_000_000, false},
+	{"Moon goo buyback", 80_000_000, 450_000_000, false},
+	{"Salvage buyback", 5_000_000, 40_000_000, false},
+	{"PI for Sotiyo", 30_000_000, 120_000_000, false},
+}
+
+func (d *DemoCorpProvider) GetContracts() ([]CorpContract, error) {
+	rng := rand.New(rand.NewSource(424242 + 8000))
+	ceo := d.members[0]
+	clamp := func(t time.Time) time.Time {
+		if t.After(d.now) {
+			return d.now
+		}
+		return t
+	}
+
+	contracts := make([]CorpContract, 0, 80)
+	for i := 0; i < 80; i++ {
+		issued := d.now.Add(-time.Duration(rng.Intn(90*24)) * time.Hour)
+		age := d.now.Sub(issued)
+		member := d.members[rng.Intn(len(d.members))]
+		c := CorpContract{
+			ContractID:  int32(190000000 + i),
+			DateIssued:  issued.Format(time.RFC3339),
+			DateExpired: issued.AddDate(0, 0, 14).Format(time.RFC3339),
+		}
+
+		var acceptor CorpMember
+		var acceptorID int64
+		var acceptorName string
+		if rng.Float64() < 0.45 {
+			// Courier between Jita / Amarr and staging, for the corp hauling service.
+			from, to := demoContractLocations[rng.Intn(2)], demoContractLocations[2+rng.Intn(2)]
+			if rng.Float64() < 0.3 {
+				from, to = to, from
+			}
+			c.Type = "courier"
+			c.Availability = "corporation"
+			c.Title = fmt.Sprintf("%s → %s", demoSystemOf(from.name), demoSystemOf(to.name))
+			c.IssuerID, c.IssuerName = member.CharacterID, member.Name
+			c.StartLocation, c.StartName = from.id, from.name
+			c.EndLocation, c.EndName = to.id, to.name
+			c.Volume = float64(5_000 + rng.Intn(315_000))
+			c.Reward = math.Round(c.Volume*(250+rng.Float64()*200)/1_000) * 1_000
+			c.Collateral = math.Round((100_000_000+rng.Float64()*2_900_000_000)/1_000_000) * 1_000_000
+			c.DaysToComplete = 3 + rng.Intn(5)
+			acceptor = d.members[rng.Intn(len(d.members))]
+			acceptorID, acceptorName = acceptor.CharacterID, acceptor.Name
+		} else {
+			item := demoItemExchanges[rng.Intn(len(demoItemExchanges))]
+			c.Type = "item_exchange"
+			c.Title = item.title
+			c.Price = math.Round((item.minPrice+rng.Float64()*(item.maxPrice-item.minPrice))/10_000) * 10_000
+			loc := demoContractLocations[2]
+			c.StartLocation, c.StartName = loc.id, loc.name
+			c.EndLocation, c.EndName = loc.id, loc.name
+			if item.fromCorp {
+				c.IssuerID, c.IssuerName = ceo.CharacterID, ceo.Name
+				c.ForCorporation = true
+				c.Availability = "corporation"
+				acceptorID, acceptorName = member.CharacterID, member.Name
+			} else {
+				c.IssuerID, c.IssuerName = member.CharacterID, member.Name
+				c.Availability = "personal"
+				acceptorID, acceptorName = 98000042, "Void Horizons" // corp ID
+			}
+		}
+
+		// Status by age: fresh contracts are still open, older ones mostly done.
+		roll := rng.Float64()
+		accepted := clamp(issued.Add(time.Duration(1+rng.Intn(20)) * time.Hour))
+		switch {
+		case age < 48*time.Hour && roll < 0.6:
+			c.Status = "outstanding"
+		case c.Type == "courier" && age < 5*24*time.Hour:
+			c.Status = "in_progress"
+			c.AcceptorID, c.AcceptorName = acceptorID, acceptorName
+			c.DateAccepted = accepted.Format(time.RFC3339)
+		case roll < 0.08:
+			c.Status = "expired"
+		case c.Type == "courier" && roll < 0.11:
+			c.Status = "failed"
+			c.AcceptorID, c.AcceptorName = acceptorID, acceptorName
+			c.DateAccepted = accepted.Format(time.RFC3339)
+		default:
+			c.Status = "finished"
+			c.AcceptorID, c.AcceptorName = acceptorID, acceptorName
+			c.DateAccepted = accepted.Format(time.RFC3339)
+			completed := accepted
+			if c.Type == "courier" {
+				completed = clamp(accepted.Add(time.Duration(6+rng.Intn(60)) * time.Hour))
+			}
+			c.DateCompleted = completed.Format(time.RFC3339)
+		}
+		contracts = append(contracts, c)
+	}
+
+	sort.Slice(contracts, func(i, j int) bool { return contracts[i].DateIssued > contracts[j].DateIssued })
+	return contracts, nil
+}
+
+// demoSystemOf returns the system name a station or structure name starts with.
+func demoSystemOf(location string) string {
+	for i, r := range location {
+		if r == ' ' {
+			return location[:i]
+		}
+	}
+	return location
+}
+
+// ============================================================
+// Structures (state, fuel expiry, services)
+// ============================================================
+
+var demoStructureStates = map[int64]struct {
+	state         string
+	timerHours    int // state timer end, hours from now; 0 = no timer
+	reinforceHour int
+}{
+	1035466617946: {"shield_vulnerable", 0, 19},
+	1035466617947: {"shield_vulnerable", 0, 19},
+	1035466617948: {"armor_reinforce", 20, 2},
+	1035466617949: {"low_power", 0, 20},
+}
+
+func (d *DemoCorpProvider) GetStructures() ([]CorpStructure, error) {
+	var structures []CorpStructure
+	for i, st := range demoStructures {
+		if info, ok := structureHulls[st.typeID]; !ok || info.kind != "upwell" {
+			continue // control towers are not listed by the structures endpoint
+		}
+		sys := demoSystems[i%len(demoSystems)]
+		for _, s := range demoSystems {
+			if demoSystemOf(st.name) == s.name {
+				sys = s
+			}
+		}
+
+		state := demoStructureStates[st.itemID]
+		s := CorpStructure{
+			StructureID:   st.itemID,
+			TypeID:        st.typeID,
+			TypeName:      st.typeName,
+			Name:          st.name,
+			SystemID:      sys.systemID,
+			SystemName:    sys.name,
+			State:         state.state,
+			ReinforceHour: state.reinforceHour,
+			Services:      []StructureService{},
+		}
+		if state.timerHours > 0 {
+			s.StateTimerEnd = d.now.Add(time.Duration(state.timerHours) * time.Hour).Format(time.RFC3339)
+		}
+
+		burn := 0.0
+		for _, svc := range st.services {
+			burn += serviceModuleFuel[svc].blocksPerHour
+			svcState := "online"
+			if st.fuel == 0 {
+				svcState = "offline"
+			}
+			s.Services = append(s.Services, StructureService{Name: serviceModuleFuel[svc].name, State: svcState})
+		}
+		if st.fuel > 0 && burn > 0 {
+			hours := float64(st.fuel) / burn
+			s.FuelExpires = d.now.Add(time.Duration(hours * float64(time.Hour))).Format(time.RFC3339)
+		}
+		structures = append(structures, s)
+	}
+	return structures, nil
+}
@@ -0,0 +1,82 @@
+package corp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDemoContracts_StatusesAndDates(t *testing.T) {
+	d := NewDemoCorpProvider()
+	contracts, err := d.GetContracts()
+	if err != nil || len(contracts) == 0 {
+		t.Fatalf("contracts = %d, err = %v", len(contracts), err)
+	}
+
+	types := make(map[string]int)
+	statuses := make(map[string]int)
+	for _, c := range contracts {
+		types[c.Type]++
+		statuses[c.Status]++
+		issued, err := time.Parse(time.RFC3339, c.DateIssued)
+		if err != nil || issued.After(d.now) {
+			t.Fatalf("contract %d issued %q", c.ContractID, c.DateIssued)
+		}
+		switch c.Status {
+		case "finished":
+			if c.AcceptorID == 0 || c.DateCompleted == "" || c.DateCompleted < c.DateAccepted {
+				t.Fatalf("finished contract without acceptance: %+v", c)
+			}
+		case "outstanding", "expired":
+			if c.AcceptorID != 0 || c.DateAccepted != "" {
+				t.Fatalf("%s contract has an acceptor: %+v", c.Status, c)
+			}
+		}
+		if c.Type == "courier" && (c.Volume <= 0 || c.Reward <= 0 || c.StartLocation == c.EndLocation) {
+			t.Fatalf("bad courier: %+v", c)
+		}
+	}
+	if types["courier"] == 0 || types["item_exchange"] == 0 || statuses["finished"] == 0 {
+		t.Fatalf("types = %v, statuses = %v", types, statuses)
+	}
+
+	again, _ := NewDemoCorpProvider().GetContracts()
+	if again[0].ContractID != contracts[0].ContractID || again[0].Price != contracts[0].Price {
+		t.Fatal("demo contracts are not deterministic")
+	}
+}
+
+func TestDemoStructures_MatchFuelBays(t *testing.T) {
+	d := NewDemoCorpProvider()
+	structures, err := d.GetStructures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(structures) != 4 {
+		t.Fatalf("structures = %+v, want the 4 Upwell hulls", structures)
+	}
+	assets, _ := d.GetAssets()
+	fuel := computeFuelSummary(assets, d.now)
+	byID := make(map[int64]StructureFuelEntry)
+	for _, e := range fuel.Structures {
+		byID[e.StructureID] = e
+	}
+
+	for _, s := range structures {
+		if s.SystemName == "" || s.SystemName != demoSystemOf(s.Name) {
+			t.Errorf("%s is in %q", s.Name, s.SystemName)
+		}
+		entry := byID[s.StructureID]
+		if s.FuelExpires == "" {
+			if entry.FuelBlocks > 0 && entry.BlocksPerHour > 0 {
+				t.Errorf("%s has fuel but no expiry", s.Name)
+			}
+			continue
+		}
+		if entry.FuelExpires != "" && entry.FuelExpires[:13] != s.FuelExpires[:13] {
+			t.Errorf("%s expires %s, fuel bay says %s", s.Name, s.FuelExpires, entry.FuelExpires)
+		}
+	}
+	if structures[3].State != "low_power" || structures[3].FuelExpires != "" {
+		t.Errorf("unfueled Astrahus = %+v", structures[3])
+	}
+}
@@ -0,0 +1,179 @@
+package corp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+// Pseudo-sections for corp routes outside the toggleable dashboard sections.
+const (
+	sectionWallets      = "wallets"
+	sectionTransactions = "transactions"
+	sectionContracts    = "contracts"
+	sectionStructures   = "structures"
+)
+
+// corpESIRoute is one ESI route ESICorpProvider reads, with the response
+// fields it decodes. Keep in sync with the structs in esi_provider.go.
+type corpESIRoute struct {
+	path     string   // swagger path template
+	sections []string // what goes empty when the route breaks
+	fields   []string
+}
+
+var corpESIRoutes = []corpESIRoute{
+	{"/corporations/{corporation_id}/", []string{sectionWallets}, []string{"name", "ticker", "member_count"}},
+	{"/corporations/{corporation_id}/wallets/", []string{sectionWallets}, []string{"division", "balance"}},
+	{"/corporations/{corporation_id}/divisions/", []string{sectionWallets}, []string{"wallet"}},
+	{"/corporations/{corporation_id}/wallets/{division}/journal/",
+		[]string{SectionFinances, SectionProjection, SectionContributors, SectionMembers},
+		[]string{"id", "date", "ref_type", "amount", "balance", "description", "first_party_id", "second_party_id"}},
+	{"/corporations/{corporation_id}/wallets/{division}/transactions/", []string{sectionTransactions},
+		[]string{"transaction_id", "date", "type_id", "quantity", "unit_price", "is_buy", "location_id", "client_id"}},
+	{"/corporations/{corporation_id}/members/", []string{SectionContributors, SectionMembers}, nil},
+	{"/corporations/{corporation_id}/membertracking/", []string{SectionMembers},
+		[]string{"character_id", "logon_date", "logoff_date", "start_date", "ship_type_id", "location_id", "system_id"}},
+	{"/corporations/{corporation_id}/industry/jobs/", []string{SectionIndustry},
+		[]string{"job_id", "installer_id", "activity_id", "blueprint_type_id", "product_type_id", "status", "runs", "start_date", "end_date", "facility_id"}},
+	{"/corporation/{corporation_id}/mining/observers/", []string{SectionMining}, []string{"observer_id"}},
+	{"/corporation/{corporation_id}/mining/observers/{observer_id}/", []string{SectionMining},
+		[]string{"character_id", "recorded_corporation_id", "type_id", "quantity", "last_updated"}},
+	{"/corporations/{corporation_id}/orders/", []string{SectionMarket},
+		[]string{"order_id", "issued_by", "type_id", "price", "volume_remain", "volume_total", "is_buy_order", "location_id", "issued", "duration", "region_id"}},
+	{"/corporations/{corporation_id}/assets/", []string{SectionFuel},
+		[]string{"item_id", "type_id", "location_id", "location_flag", "location_type", "quantity", "is_singleton"}},
+	{"/corporations/{corporation_id}/contracts/", []string{sectionContracts},
+		[]string{"contract_id", "type", "status", "title", "issuer_id", "acceptor_id", "for_corporation", "availability",
+			"price", "reward", "collateral", "volume", "start_location_id", "end_location_id", "days_to_complete",
+			"date_issued", "date_expired", "date_accepted", "date_completed"}},
+	{"/corporations/{corporation_id}/structures/", []string{sectionStructures},
+		[]string{"structure_id", "type_id", "name", "system_id", "state", "state_timer_end", "fuel_expires", "reinforce_hour", "services"}},
+}
+
+// ESICompatIssue is one actionable finding about a corp ESI route.
+type ESICompatIssue struct {
+	Route    string   `json:"route"`
+	Sections []string `json:"sections"`
+	Severity string   `json:"severity"` // "error": data is missing now; "warning": may break soon
+	Message  string   `json:"message"`
+}
+
+// ESICompatReport is the result of checking corp routes against the ESI spec.
+type ESICompatReport struct {
+	CheckedAt string           `json:"checked_at"`
+	Routes    int              `json:"routes"`
+	Issues    []ESICompatIssue `json:"issues"`
+	Error     string           `json:"error,omitempty"` // set when the spec could not be checked
+}
+
+// CheckESICompat validates every corp route against the ESI swagger spec
+// (route present, not deprecated, decoded fields still in the 200 schema) and
+// folds in Warning headers ESI returned at runtime. spec may be nil when it
+// could not be fetched; runtime warnings are still reported.
+func CheckESICompat(spec *esi.SwaggerSpec, warnings []esi.RouteWarning, now time.Time) ESICompatReport {
+	report := ESICompatReport{
+		CheckedAt: now.UTC().Format(time.RFC3339),
+		Routes:    len(corpESIRoutes),
+		Issues:    []ESICompatIssue{},
+	}
+
+	byRoute := make(map[string]corpESIRoute, len(corpESIRoutes))
+	for _, r := range corpESIRoutes {
+		byRoute[esi.NormalizeRoute(r.path)] = r
+	}
+
+	if spec != nil {
+		specPaths := make(map[string]string, len(spec.Paths))
+		for p := range spec.Paths {
+			specPaths[esi.NormalizeRoute(p)] = p
+		}
+		for _, r := range corpESIRoutes {
+			report.Issues = append(report.Issues, checkCorpESIRoute(spec, specPaths, r)...)
+		}
+	}
+
+	for _, w := range warnings {
+		r, ok := byRoute[w.Route]
+		if !ok {
+			continue
+		}
+		report.Issues = append(report.Issues, ESICompatIssue{
+			Route:    r.path,
+			Sections: r.sections,
+			Severity: "warning",
+			Message: fmt.Sprintf("ESI returned %q for %s (%s); the route is likely to be retired, update eve-flipper.",
+				w.Warning, r.path, strings.Join(r.sections, ", ")),
+		})
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		if report.Issues[i].Severity != report.Issues[j].Severity {
+			return report.Issues[i].Severity == "error"
+		}
+		return report.Issues[i].Route < report.Issues[j].Route
+	})
+	return report
+}
+
+func checkCorpESIRoute(spec *esi.SwaggerSpec, specPaths map[string]string, r corpESIRoute) []ESICompatIssue {
+	sections := strings.Join(r.sections, ", ")
+	specPath, ok := specPaths[esi.NormalizeRoute(r.path)]
+	if !ok {
+		return []ESICompatIssue{{
+			Route: r.path, Sections: r.sections, Severity: "error",
+			Message: fmt.Sprintf("ESI no longer serves %s; %s will stay empty until eve-flipper is updated.", r.path, sections),
+		}}
+	}
+	fields, op, ok := spec.ResponseFields(specPath)
+	if !ok {
+		return []ESICompatIssue{{
+			Route: r.path, Sections: r.sections, Severity: "error",
+			Message: fmt.Sprintf("ESI has no GET operation for %s; %s will stay empty until eve-flipper is updated.", r.path, sections),
+		}}
+	}
+
+	var issues []ESICompatIssue
+	if op.Deprecated {
+		issues = append(issues, ESICompatIssue{
+			Route: r.path, Sections: r.sections, Severity: "warning",
+			Message: fmt.Sprintf("ESI marks %s as deprecated; %s may stop loading when it is retired.", r.path, sections),
+		})
+	}
+	var missing []string
+	for _, f := range r.fields {
+		if !fields[f] {
+			missing = append(missing, f)
+		}
+	}
+	if len(missing) > 0 {
+		issues = append(issues, ESICompatIssue{
+			Route: r.path, Sections: r.sections, Severity: "error",
+			Message: fmt.Sprintf("ESI response for %s no longer has %s; %s will show incomplete data.",
+				r.path, strings.Join(missing, ", "), sections),
+		})
+	}
+	return issues
+}
+
+// IssuesFor returns the issues affecting data the dashboard fetches with
+// layout: wallets plus any enabled section.
+func (r ESICompatReport) IssuesFor(layout DashboardLayout) []ESICompatIssue {
+	known := make(map[string]bool, len(DashboardSections))
+	for _, s := range DashboardSections {
+		known[s] = true
+	}
+	out := []ESICompatIssue{}
+	for _, issue := range r.Issues {
+		for _, s := range issue.Sections {
+			if s == sectionWallets || (known[s] && layout.Enabled(s)) {
+				out = append(out, issue)
+				break
+			}
+		}
+	}
+	return out
+}
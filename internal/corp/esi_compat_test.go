@@ -0,0 +1,68 @@
+package corp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+// compatSpec builds a spec where every corp route is present and complete.
+func compatSpec() *esi.SwaggerSpec {
+	spec := &esi.SwaggerSpec{Paths: map[string]map[string]esi.SwaggerOperation{}}
+	for _, r := range corpESIRoutes {
+		props := map[string]esi.SwaggerSchema{}
+		for _, f := range r.fields {
+			props[f] = esi.SwaggerSchema{}
+		}
+		spec.Paths[r.path] = map[string]esi.SwaggerOperation{"get": {Responses: map[string]esi.SwaggerResponse{
+			"200": {Schema: &esi.SwaggerSchema{Type: "array", Items: &esi.SwaggerSchema{Type: "object", Properties: props}}},
+		}}}
+	}
+	return spec
+}
+
+func TestCheckESICompat_CleanSpec(t *testing.T) {
+	report := CheckESICompat(compatSpec(), nil, time.Now())
+	if len(report.Issues) != 0 || report.Routes != len(corpESIRoutes) {
+		t.Fatalf("issues = %+v", report.Issues)
+	}
+}
+
+func TestCheckESICompat_ReportsDrift(t *testing.T) {
+	spec := compatSpec()
+	delete(spec.Paths, "/corporation/{corporation_id}/mining/observers/")
+	orders := spec.Paths["/corporations/{corporation_id}/orders/"]["get"]
+	orders.Deprecated = true
+	delete(orders.Responses["200"].Schema.Items.Properties, "issued_by")
+	spec.Paths["/corporations/{corporation_id}/orders/"]["get"] = orders
+
+	warnings := []esi.RouteWarning{{Route: "/corporations/{id}/assets/", Warning: "199 - This route is deprecated"}}
+	report := CheckESICompat(spec, warnings, time.Now())
+	if len(report.Issues) != 4 {
+		t.Fatalf("issues = %d, want 4: %+v", len(report.Issues), report.Issues)
+	}
+	if report.Issues[0].Severity != "error" || report.Issues[len(report.Issues)-1].Severity != "warning" {
+		t.Errorf("errors should sort first: %+v", report.Issues)
+	}
+	var sawField bool
+	for _, issue := range report.Issues {
+		if strings.Contains(issue.Message, "issued_by") {
+			sawField = true
+		}
+	}
+	if !sawField {
+		t.Error("missing field not reported")
+	}
+
+	layout := DashboardLayout{Disabled: []string{SectionMining, SectionFuel}}
+	for _, issue := range report.IssuesFor(layout) {
+		if issue.Sections[0] == SectionMining || issue.Sections[0] == SectionFuel {
+			t.Errorf("issue for disabled section surfaced: %+v", issue)
+		}
+	}
+	if got := len(report.IssuesFor(layout)); got != 2 {
+		t.Errorf("IssuesFor = %d, want 2 (market only)", got)
+	}
+}
@@ -3,6 +3,7 @@ package corp
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 
 	"eve-flipper/internal/esi"
@@ -207,8 +208,9 @@ func (e *ESICorpProvider) GetMembers() ([]CorpMember, error) {
 	trackURL := fmt.Sprintf("https://esi.evetech.net/latest/corporations/%d/membertracking/?datasource=tranquility", e.corporationID)
 	var tracking []struct {
 		CharacterID int64  `json:"character_id"`
-		LastLogin   string `json:"start_date"` // ESI calls it start_date
+		LogonDate   string `json:"logon_date"`
 		LogoffDate  string `json:"logoff_date"`
+		StartDate   string `json:"start_date"` // joined the corp
 		ShipTypeID  int32  `json:"ship_type_id"`
 		LocationID  int64  `json:"location_id"`
 		SystemID    int32  `json:"system_id"`
@@ -231,8 +233,9 @@ func (e *ESICorpProvider) GetMembers() ([]CorpMember, error) {
 		}
 		if idx, ok := trackMap[id]; ok {
 			t := tracking[idx]
-			m.LastLogin = t.LastLogin
+			m.LastLogin = t.LogonDate
 			m.LogoffDate = t.LogoffDate
+			m.JoinedAt = t.StartDate
 			m.ShipTypeID = t.ShipTypeID
 			m.ShipName = e.typeName(t.ShipTypeID)
 			m.LocationID = t.LocationID
@@ -273,21 +276,9 @@ func (e *ESICorpProvider) GetIndustryJobs() ([]CorpIndustryJob, error) {
 	}
 	installerNames := e.resolveCharacterNames(installerIDs)
 
-	activityNames := map[int]string{
-		1: "manufacturing",
-		3: "researching_time_efficiency",
-		4: "researching_material_efficiency",
-		5: "copying",
-		8: "invention",
-		9: "reaction",
-	}
-
 	jobs := make([]CorpIndustryJob, len(raw))
 	for i, j := range raw {
-		activity := activityNames[j.ActivityID]
-		if activity == "" {
-			activity = fmt.Sprintf("activity_%d", j.ActivityID)
-		}
+		activity := IndustryActivityName(j.ActivityID)
 		jobs[i] = CorpIndustryJob{
 			JobID:           j.JobID,
 			InstallerID:     j.InstallerID,
@@ -336,14 +327,17 @@ func (e *ESICorpProvider) GetMiningLedger() ([]CorpMiningEntry, error) {
 			if err := e.client.AuthGetJSON(url, e.accessToken, &raw); err != nil {
 				return
 			}
+			observerName := e.structureName(obsID)
 			var entries []CorpMiningEntry
 			for _, r := range raw {
 				entries = append(entries, CorpMiningEntry{
-					CharacterID: r.CharacterID,
-					Date:        r.LastUpdated,
-					TypeID:      r.TypeID,
-					TypeName:    e.typeName(r.TypeID),
-					Quantity:    r.Quantity,
+					CharacterID:  r.CharacterID,
+					Date:         r.LastUpdated,
+					TypeID:       r.TypeID,
+					TypeName:     e.typeName(r.TypeID),
+					Quantity:     r.Quantity,
+					ObserverID:   obsID,
+					ObserverName: observerName,
 				})
 			}
 			mu.Lock()
@@ -427,6 +421,196 @@ func (e *ESICorpProvider) GetOrders() ([]CorpMarketOrder, error) {
 	return orders, nil
 }
 
+func (e *ESICorpProvider) GetAssets() ([]CorpAsset, error) {
+	url := fmt.Sprintf("https://esi.evetech.net/latest/corporations/%d/assets/?datasource=tranquility", e.corporationID)
+	rawPages, err := e.client.AuthGetPaginated(url, e.accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("corp assets: %w", err)
+	}
+
+	assets := make([]CorpAsset, 0, len(rawPages))
+	for _, page := range rawPages {
+		var a struct {
+			ItemID       int64  `json:"item_id"`
+			TypeID       int32  `json:"type_id"`
+			LocationID   int64  `json:"location_id"`
+			LocationFlag string `json:"location_flag"`
+			LocationType string `json:"location_type"`
+			Quantity     int32  `json:"quantity"`
+			IsSingleton  bool   `json:"is_singleton"`
+		}
+		if err := json.Unmarshal(page, &a); err != nil {
+			continue
+		}
+		assets = append(assets, CorpAsset{
+			ItemID:       a.ItemID,
+			TypeID:       a.TypeID,
+			TypeName:     e.typeName(a.TypeID),
+			LocationID:   a.LocationID,
+			LocationFlag: a.LocationFlag,
+			LocationType: a.LocationType,
+			Quantity:     a.Quantity,
+			IsSingleton:  a.IsSingleton,
+		})
+	}
+
+	// Resolve player-given names for Upwell structures only; starbases and
+	// regular items keep their type name. Each name is its own ESI call, so
+	// they are resolved concurrently.
+	var wg sync.WaitGroup
+	for i := range assets {
+		if info, ok := structureHulls[assets[i].TypeID]; ok && info.kind == "upwell" {
+			wg.Add(1)
+			go func(a *CorpAsset) {
+				defer wg.Done()
+				a.ItemName = e.structureName(a.ItemID)
+			}(&assets[i])
+		}
+	}
+	wg.Wait()
+
+	return assets, nil
+}
+
+// structureName returns the player-given name of an Upwell structure the
+// token can see, or "" when ESI only has a placeholder for it.
+func (e *ESICorpProvider) structureName(structureID int64) string {
+	name := e.client.StructureName(structureID, e.accessToken)
+	if strings.HasPrefix(name, "Structure ") || strings.HasPrefix(name, "Location ") {
+		return ""
+	}
+	return name
+}
+
+func (e *ESICorpProvider) GetContracts() ([]CorpContract, error) {
+	url := fmt.Sprintf("https://esi.evetech.net/latest/corporations/%d/contracts/?datasource=tranquility", e.corporationID)
+	rawPages, err := e.client.AuthGetPaginated(url, e.accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("corp contracts: %w", err)
+	}
+
+	contracts := make([]CorpContract, 0, len(rawPages))
+	var charIDs []int64
+	for _, page := range rawPages {
+		var c struct {
+			ContractID      int32   `json:"contract_id"`
+			Type            string  `json:"type"`
+			Status          string  `json:"status"`
+			Title           string  `json:"title"`
+			IssuerID        int64   `json:"issuer_id"`
+			AcceptorID      int64   `json:"acceptor_id"`
+			ForCorporation  bool    `json:"for_corporation"`
+			Availability    string  `json:"availability"`
+			Price           float64 `json:"price"`
+			Reward          float64 `json:"reward"`
+			Collateral      float64 `json:"collateral"`
+			Volume          float64 `json:"volume"`
+			StartLocationID int64   `json:"start_location_id"`
+			EndLocationID   int64   `json:"end_location_id"`
+			DaysToComplete  int     `json:"days_to_complete"`
+			DateIssued      string  `json:"date_issued"`
+			DateExpired     string  `json:"date_expired"`
+			DateAccepted    string  `json:"date_accepted"`
+			DateCompleted   string  `json:"date_completed"`
+		}
+		if err := json.Unmarshal(page, &c); err != nil {
+			continue
+		}
+		charIDs = append(charIDs, c.IssuerID)
+		if c.AcceptorID > 0 {
+			charIDs = append(charIDs, c.AcceptorID)
+		}
+		contract := CorpContract{
+			ContractID:     c.ContractID,
+			Type:           c.Type,
+			Status:         c.Status,
+			Title:          c.Title,
+			IssuerID:       c.IssuerID,
+			AcceptorID:     c.AcceptorID,
+			ForCorporation: c.ForCorporation,
+			Availability:   c.Availability,
+			Price:          c.Price,
+			Reward:         c.Reward,
+			Collateral:     c.Collateral,
+			Volume:         c.Volume,
+			StartLocation:  c.StartLocationID,
+			EndLocation:    c.EndLocationID,
+			DaysToComplete: c.DaysToComplete,
+			DateIssued:     c.DateIssued,
+			DateExpired:    c.DateExpired,
+			DateAccepted:   c.DateAccepted,
+			DateCompleted:  c.DateCompleted,
+		}
+		if c.StartLocationID > 0 {
+			contract.StartName = e.client.StationName(c.StartLocationID)
+		}
+		if c.EndLocationID > 0 {
+			contract.EndName = e.client.StationName(c.EndLocationID)
+		}
+		contracts = append(contracts, contract)
+	}
+
+	// Acceptors may be corporations; ResolveNames handles both.
+	names := e.resolveCharacterNames(charIDs)
+	for i := range contracts {
+		contracts[i].IssuerName = names[contracts[i].IssuerID]
+		if contracts[i].AcceptorID > 0 {
+			contracts[i].AcceptorName = names[contracts[i].AcceptorID]
+		}
+	}
+
+	return contracts, nil
+}
+
+func (e *ESICorpProvider) GetStructures() ([]CorpStructure, error) {
+	url := fmt.Sprintf("https://esi.evetech.net/latest/corporations/%d/structures/?datasource=tranquility", e.corporationID)
+	rawPages, err := e.client.AuthGetPaginated(url, e.accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("corp structures: %w", err)
+	}
+
+	structures := make([]CorpStructure, 0, len(rawPages))
+	for _, page := range rawPages {
+		var st struct {
+			StructureID   int64              `json:"structure_id"`
+			TypeID        int32              `json:"type_id"`
+			Name          string             `json:"name"`
+			SystemID      int32              `json:"system_id"`
+			State         string             `json:"state"`
+			StateTimerEnd string             `json:"state_timer_end"`
+			FuelExpires   string             `json:"fuel_expires"`
+			ReinforceHour int                `json:"reinforce_hour"`
+			Services      []StructureService `json:"services"`
+		}
+		if err := json.Unmarshal(page, &st); err != nil {
+			continue
+		}
+		structure := CorpStructure{
+			StructureID:   st.StructureID,
+			TypeID:        st.TypeID,
+			TypeName:      e.typeName(st.TypeID),
+			Name:          st.Name,
+			SystemID:      st.SystemID,
+			State:         st.State,
+			StateTimerEnd: st.StateTimerEnd,
+			FuelExpires:   st.FuelExpires,
+			ReinforceHour: st.ReinforceHour,
+			Services:      st.Services,
+		}
+		if structure.Services == nil {
+			structure.Services = []StructureService{}
+		}
+		if e.sdeData != nil {
+			if sys, ok := e.sdeData.Systems[st.SystemID]; ok {
+				structure.SystemName = sys.Name
+			}
+		}
+		structures = append(structures, structure)
+	}
+
+	return structures, nil
+}
+
 // ============================================================
 // Helpers
 // ============================================================
@@ -445,42 +629,8 @@ func (e *ESICorpProvider) resolveCharacterNames(ids []int64) map[int64]string {
 	if len(ids) == 0 {
 		return names
 	}
-
-	// Deduplicate IDs
-	seen := make(map[int64]bool)
-	var unique []int64
-	for _, id := range ids {
-		if id > 0 && !seen[id] {
-			seen[id] = true
-			unique = append(unique, id)
-		}
-	}
-
-	// ESI POST /universe/names/ accepts up to 1000 IDs per call
-	batchSize := 1000
-	for start := 0; start < len(unique); start += batchSize {
-		end := start + batchSize
-		if end > len(unique) {
-			end = len(unique)
-		}
-		batch := unique[start:end]
-
-		// Convert to int32 IDs for the endpoint (safe for character/corp/alliance IDs)
-		intIDs := make([]int32, len(batch))
-		for i, id := range batch {
-			intIDs[i] = int32(id)
-		}
-
-		url := "https://esi.evetech.net/latest/universe/names/?datasource=tranquility"
-		var results []struct {
-			ID   int64  `json:"id"`
-			Name string `json:"name"`
-		}
-		if err := e.client.PostJSON(url, intIDs, &results); err == nil {
-			for _, r := range results {
-				names[r.ID] = r.Name
-			}
-		}
+	for id, n := range e.client.ResolveNames(ids) {
+		names[id] = n.Name
 	}
 
 	// Fallback for any IDs the API didn't resolve
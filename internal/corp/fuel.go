@@ -0,0 +1,259 @@
+package corp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Fuel thresholds (days of fuel remaining) for dashboard alerts.
+const (
+	fuelLowDays      = 14.0
+	fuelCriticalDays = 3.0
+)
+
+// fuelBlockTypes are the four racial fuel blocks burned by structures and towers.
+var fuelBlockTypes = map[int32]string{
+	4051: "Nitrogen Fuel Block",
+	4246: "Hydrogen Fuel Block",
+	4247: "Helium Fuel Block",
+	4312: "Oxygen Fuel Block",
+}
+
+// structureHulls maps Upwell structure and control tower type IDs to their
+// kind and baseline fuel burn (blocks/hour). Upwell hulls burn nothing on
+// their own; consumption comes from online service modules.
+var structureHulls = map[int32]struct {
+	name          string
+	kind          string
+	blocksPerHour float64
+}{
+	35832: {"Astrahus", "upwell", 0},
+	35833: {"Fortizar", "upwell", 0},
+	35834: {"Keepstar", "upwell", 0},
+	35825: {"Raitaru", "upwell", 0},
+	35826: {"Azbel", "upwell", 0},
+	35827: {"Sotiyo", "upwell", 0},
+	35835: {"Athanor", "upwell", 0},
+	35836: {"Tatara", "upwell", 0},
+	// Large / medium / small racial control towers.
+	12235: {"Amarr Control Tower", "starbase", 40},
+	20059: {"Amarr Control Tower Medium", "starbase", 20},
+	20060: {"Amarr Control Tower Small", "starbase", 10},
+	16213: {"Caldari Control Tower", "starbase", 40},
+	20061: {"Caldari Control Tower Medium", "starbase", 20},
+	20062: {"Caldari Control Tower Small", "starbase", 10},
+	12236: {"Gallente Control Tower", "starbase", 40},
+	20063: {"Gallente Control Tower Medium", "starbase", 20},
+	20064: {"Gallente Control Tower Small", "starbase", 10},
+	16214: {"Minmatar Control Tower", "starbase", 40},
+	20065: {"Minmatar Control Tower Medium", "starbase", 20},
+	20066: {"Minmatar Control Tower Small", "starbase", 10},
+}
+
+// serviceModuleFuel lists nominal online fuel burn (blocks/hour) for Upwell
+// service modules. Hull/rig bonuses are ignored, so estimates err on the side
+// of consuming fuel faster than the structure actually does.
+var serviceModuleFuel = map[int32]struct {
+	name          string
+	blocksPerHour float64
+}{
+	35892: {"Standup Market Hub I", 40},
+	35894: {"Standup Cloning Center I", 10},
+	35878: {"Standup Manufacturing Plant I", 12},
+	35881: {"Standup Capital Shipyard I", 24},
+	35877: {"Standup Supercapital Shipyard I", 36},
+	35891: {"Standup Research Lab I", 12},
+	35886: {"Standup Invention Lab I", 12},
+	35899: {"Standup Reprocessing Facility I", 10},
+	45009: {"Standup Moon Drill I", 5},
+	45537: {"Standup Composite Reactor I", 15},
+	45538: {"Standup Hybrid Reactor I", 15},
+	45539: {"Standup Biochemical Reactor I", 15},
+}
+
+// computeFuelSummary detects structures and control towers in corp assets,
+// totals the fuel blocks in each fuel bay and estimates days of fuel left.
+func computeFuelSummary(assets []CorpAsset, now time.Time) FuelSummary {
+	s := FuelSummary{
+		Structures: []StructureFuelEntry{},
+		Alerts:     []FuelAlert{},
+	}
+	if len(assets) == 0 {
+		return s
+	}
+
+	byItemID := make(map[int64]CorpAsset, len(assets))
+	for _, a := range assets {
+		byItemID[a.ItemID] = a
+	}
+
+	entries := make(map[int64]*StructureFuelEntry)
+	entryFor := func(structureID int64) *StructureFuelEntry {
+		if e, ok := entries[structureID]; ok {
+			return e
+		}
+		e := &StructureFuelEntry{StructureID: structureID, Kind: "upwell"}
+		if hull, ok := byItemID[structureID]; ok {
+			e.TypeID = hull.TypeID
+			e.TypeName = hull.TypeName
+			e.Name = hull.ItemName
+			if info, known := structureHulls[hull.TypeID]; known {
+				e.Kind = info.kind
+				e.BlocksPerHour = info.blocksPerHour
+				if e.TypeName == "" {
+					e.TypeName = info.name
+				}
+			}
+		}
+		entries[structureID] = e
+		return e
+	}
+
+	// Register every known hull first so structures with empty fuel bays
+	// still show up (they are the most urgent ones).
+	for _, a := range assets {
+		if _, ok := structureHulls[a.TypeID]; ok {
+			entryFor(a.ItemID)
+		}
+	}
+
+	for _, a := range assets {
+		switch {
+		case a.LocationFlag == "StructureFuel":
+			if _, ok := fuelBlockTypes[a.TypeID]; ok {
+				entryFor(a.LocationID).FuelBlocks += int64(a.Quantity)
+			}
+		case strings.HasPrefix(a.LocationFlag, "ServiceSlot"):
+			e := entryFor(a.LocationID)
+			name := a.TypeName
+			if svc, ok := serviceModuleFuel[a.TypeID]; ok {
+				e.BlocksPerHour += svc.blocksPerHour
+				if name == "" {
+					name = svc.name
+				}
+			} else {
+				e.ConsumptionEst = true
+			}
+			if name == "" {
+				name = fmt.Sprintf("Type #%d", a.TypeID)
+			}
+			e.Services = append(e.Services, name)
+		default:
+			// Starbase fuel sits directly inside the control tower item.
+			if _, ok := fuelBlockTypes[a.TypeID]; !ok {
+				continue
+			}
+			hull, ok := byItemID[a.LocationID]
+			if !ok {
+				continue
+			}
+			if info, known := structureHulls[hull.TypeID]; known && info.kind == "starbase" {
+				entryFor(a.LocationID).FuelBlocks += int64(a.Quantity)
+			}
+		}
+	}
+
+	for _, e := range entries {
+		if e.TypeName == "" && e.TypeID > 0 {
+			e.TypeName = fmt.Sprintf("Type #%d", e.TypeID)
+		}
+		if e.Name == "" {
+			if e.TypeName != "" {
+				e.Name = e.TypeName
+			} else {
+				e.Name = fmt.Sprintf("Structure %d", e.StructureID)
+			}
+		}
+		sort.Strings(e.Services)
+		classifyFuelEntry(e, now)
+
+		s.TotalBlocks += e.FuelBlocks
+		switch e.Status {
+		case "critical", "empty":
+			s.CriticalCount++
+			s.Alerts = append(s.Alerts, fuelAlertFor(e, "critical"))
+		case "low":
+			s.LowFuelCount++
+			s.Alerts = append(s.Alerts, fuelAlertFor(e, "warning"))
+		}
+		s.Structures = append(s.Structures, *e)
+	}
+
+	// Most urgent first; structures without known consumption last.
+	sort.Slice(s.Structures, func(i, j int) bool {
+		a, b := s.Structures[i], s.Structures[j]
+		ai, bi := fuelStatusRank(a.Status), fuelStatusRank(b.Status)
+		if ai != bi {
+			return ai < bi
+		}
+		if a.DaysRemaining != b.DaysRemaining {
+			return a.DaysRemaining < b.DaysRemaining
+		}
+		return a.StructureID < b.StructureID
+	})
+	sort.Slice(s.Alerts, func(i, j int) bool {
+		return s.Alerts[i].DaysRemaining < s.Alerts[j].DaysRemaining
+	})
+
+	return s
+}
+
+// classifyFuelEntry fills DaysRemaining, FuelExpires and Status.
+func classifyFuelEntry(e *StructureFuelEntry, now time.Time) {
+	if e.BlocksPerHour <= 0 {
+		e.DaysRemaining = 0
+		if e.Kind == "upwell" && len(e.Services) == 0 {
+			e.Status = "low_power"
+		} else {
+			e.Status = "ok"
+		}
+		return
+	}
+	if e.FuelBlocks <= 0 {
+		e.Status = "empty"
+		return
+	}
+	hours := float64(e.FuelBlocks) / e.BlocksPerHour
+	e.DaysRemaining = math.Round(hours/24*10) / 10
+	e.FuelExpires = now.Add(time.Duration(hours * float64(time.Hour))).Format(time.RFC3339)
+	switch {
+	case e.DaysRemaining < fuelCriticalDays:
+		e.Status = "critical"
+	case e.DaysRemaining < fuelLowDays:
+		e.Status = "low"
+	default:
+		e.Status = "ok"
+	}
+}
+
+func fuelAlertFor(e *StructureFuelEntry, severity string) FuelAlert {
+	msg := fmt.Sprintf("%s has %.1f days of fuel left (%d blocks)", e.Name, e.DaysRemaining, e.FuelBlocks)
+	if e.Status == "empty" {
+		msg = fmt.Sprintf("%s fuel bay is empty — services will go offline", e.Name)
+	}
+	return FuelAlert{
+		StructureID:   e.StructureID,
+		Name:          e.Name,
+		Severity:      severity,
+		DaysRemaining: e.DaysRemaining,
+		Message:       msg,
+	}
+}
+
+func fuelStatusRank(status string) int {
+	switch status {
+	case "empty":
+		return 0
+	case "critical":
+		return 1
+	case "low":
+		return 2
+	case "ok":
+		return 3
+	default:
+		return 4
+	}
+}
@@ -0,0 +1,67 @@
+package corp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeFuelSummary_UpwellAndStarbase(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	assets := []CorpAsset{
+		{ItemID: 1, TypeID: 35832, TypeName: "Astrahus", ItemName: "Home", LocationFlag: "AutoFit"},
+		{ItemID: 10, TypeID: 4051, LocationID: 1, LocationFlag: "StructureFuel", Quantity: 480},
+		{ItemID: 11, TypeID: 35894, LocationID: 1, LocationFlag: "ServiceSlot0", Quantity: 1},
+		{ItemID: 2, TypeID: 16213, TypeName: "Caldari Control Tower", LocationFlag: "AutoFit"},
+		{ItemID: 20, TypeID: 4051, LocationID: 2, LocationFlag: "Unlocked", Quantity: 40 * 24 * 20},
+		{ItemID: 3, TypeID: 35825, TypeName: "Raitaru", LocationFlag: "AutoFit"},
+	}
+
+	s := computeFuelSummary(assets, now)
+	if len(s.Structures) != 3 {
+		t.Fatalf("structures = %d, want 3", len(s.Structures))
+	}
+
+	byID := make(map[int64]StructureFuelEntry)
+	for _, e := range s.Structures {
+		byID[e.StructureID] = e
+	}
+
+	home := byID[1]
+	if home.Name != "Home" || home.BlocksPerHour != 10 || home.FuelBlocks != 480 {
+		t.Fatalf("home = %+v", home)
+	}
+	if home.DaysRemaining != 2 || home.Status != "critical" {
+		t.Errorf("home days/status = %v/%s, want 2/critical", home.DaysRemaining, home.Status)
+	}
+
+	pos := byID[2]
+	if pos.Kind != "starbase" || pos.DaysRemaining != 20 || pos.Status != "ok" {
+		t.Errorf("pos = %+v, want starbase with 20 days ok", pos)
+	}
+
+	if byID[3].Status != "low_power" {
+		t.Errorf("raitaru status = %s, want low_power", byID[3].Status)
+	}
+
+	if s.CriticalCount != 1 || len(s.Alerts) != 1 || s.Alerts[0].StructureID != 1 {
+		t.Errorf("alerts = %+v critical=%d", s.Alerts, s.CriticalCount)
+	}
+	if s.Structures[0].StructureID != 1 {
+		t.Errorf("first structure = %d, want most urgent (1)", s.Structures[0].StructureID)
+	}
+}
+
+func TestDemoProviderFuelSummaryHasAlerts(t *testing.T) {
+	d := NewDemoCorpProvider()
+	assets, err := d.GetAssets()
+	if err != nil {
+		t.Fatalf("GetAssets: %v", err)
+	}
+	s := computeFuelSummary(assets, d.now)
+	if len(s.Structures) != len(demoStructures) {
+		t.Fatalf("structures = %d, want %d", len(s.Structures), len(demoStructures))
+	}
+	if len(s.Alerts) == 0 {
+		t.Fatal("expected low-fuel alerts in demo data")
+	}
+}
@@ -0,0 +1,396 @@
+package corp
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Industry slot kinds. Every science activity (research, copying,
+// invention) shares the science slots.
+const (
+	SlotManufacturing = "manufacturing"
+	SlotScience       = "science"
+	SlotReactions     = "reactions"
+)
+
+// IndustrySlotKinds lists slot kinds in display order.
+var IndustrySlotKinds = []string{SlotManufacturing, SlotScience, SlotReactions}
+
+// Skills granting industry slots: one slot base, plus one per level of the
+// skill and of its advanced version.
+const (
+	SkillMassProduction              = 3387
+	SkillAdvancedMassProduction      = 24625
+	SkillLaboratoryOperation         = 3406
+	SkillAdvancedLaboratoryOperation = 24624
+	SkillMassReactions               = 45748
+	SkillAdvancedMassReactions       = 45749
+	maxIndustrySlots                 = 11
+)
+
+const (
+	industryScheduleDefaultHorizon = 48 * time.Hour
+	// Idle slots only count for kinds an installer used this recently.
+	industryScheduleLookback = 30 * 24 * time.Hour
+	// NPC station IDs; anything else hosting jobs is a player structure.
+	npcStationIDMin = 60000000
+	npcStationIDMax = 64000000
+)
+
+// IndustrySlotSkills lists every skill IndustrySlotsFromSkills reads.
+var IndustrySlotSkills = []int32{
+	SkillMassProduction, SkillAdvancedMassProduction,
+	SkillLaboratoryOperation, SkillAdvancedLaboratoryOperation,
+	SkillMassReactions, SkillAdvancedMassReactions,
+}
+
+// IndustryActivityName maps an ESI activity_id to the activity names used
+// throughout the corp module.
+func IndustryActivityName(activityID int) string {
+	switch activityID {
+	case 1:
+		return "manufacturing"
+	case 3:
+		return "researching_time_efficiency"
+	case 4:
+		return "researching_material_efficiency"
+	case 5:
+		return "copying"
+	case 8:
+		return "invention"
+	case 9, 11:
+		return "reaction"
+	}
+	return fmt.Sprintf("activity_%d", activityID)
+}
+
+// IndustrySlotKind returns the slot kind a job activity occupies.
+func IndustrySlotKind(activity string) string {
+	switch activity {
+	case "manufacturing":
+		return SlotManufacturing
+	case "reaction":
+		return SlotReactions
+	}
+	return SlotScience
+}
+
+// IndustrySlotsFromSkills returns slots per kind for the given skill levels.
+func IndustrySlotsFromSkills(levels map[int32]int) map[string]int {
+	return map[string]int{
+		SlotManufacturing: 1 + levels[SkillMassProduction] + levels[SkillAdvancedMassProduction],
+		SlotScience:       1 + levels[SkillLaboratoryOperation] + levels[SkillAdvancedLaboratoryOperation],
+		SlotReactions:     1 + levels[SkillMassReactions] + levels[SkillAdvancedMassReactions],
+	}
+}
+
+// occupiesSlot reports whether a job still holds its slot: finished jobs
+// keep it until they are delivered.
+func occupiesSlot(status string) bool {
+	return status == "active" || status == "paused" || status == "ready"
+}
+
+// IndustrySchedule is the corp industry slot report.
+type IndustrySchedule struct {
+	GeneratedAt  string                  `json:"generated_at"`
+	HorizonHours int                     `json:"horizon_hours"`
+	Totals       []SlotUsage             `json:"totals"`
+	Members      []MemberSlotUtilization `json:"members"`
+	Upcoming     []UpcomingJobCompletion `json:"upcoming"`
+	Facilities   []IndustryFacilityUsage `json:"facilities"`
+	Alerts       []IndustryCapacityAlert `json:"alerts"`
+}
+
+// SlotUsage is how many slots of a kind are in use.
+type SlotUsage struct {
+	Kind        string  `json:"kind"`
+	Used        int     `json:"used"`
+	Max         int     `json:"max"`
+	Utilization float64 `json:"utilization"` // 0-1
+}
+
+// MemberSlotUtilization is one installer's slot use. SlotsSource is
+// "skills" when the member's skills were readable and "assumed" (the
+// 11-slot maximum) otherwise; only corp jobs are visible for assumed
+// members, skills-backed ones also count their personal jobs.
+type MemberSlotUtilization struct {
+	CharacterID    int64       `json:"character_id"`
+	Name           string      `json:"name"`
+	SlotsSource    string      `json:"slots_source"`
+	Slots          []SlotUsage `json:"slots"`
+	CorpJobs       int         `json:"corp_jobs"`
+	PersonalJobs   int         `json:"personal_jobs"`
+	Utilization    float64     `json:"utilization"` // over the kinds the member uses
+	NextCompletion string      `json:"next_completion,omitempty"`
+}
+
+// UpcomingJobCompletion is a job finishing within the horizon (or already
+// finished and waiting for delivery).
+type UpcomingJobCompletion struct {
+	JobID         int32   `json:"job_id"`
+	InstallerID   int64   `json:"installer_id"`
+	InstallerName string  `json:"installer_name,omitempty"`
+	Activity      string  `json:"activity"`
+	ProductName   string  `json:"product_name,omitempty"`
+	Runs          int32   `json:"runs"`
+	EndDate       string  `json:"end_date"`
+	HoursLeft     float64 `json:"hours_left"` // <= 0: ready to deliver
+	LocationName  string  `json:"location_name,omitempty"`
+	Personal      bool    `json:"personal,omitempty"`
+}
+
+// IndustryFacilityUsage counts slot-holding jobs per facility.
+type IndustryFacilityUsage struct {
+	LocationID   int64          `json:"location_id"`
+	LocationName string         `json:"location_name,omitempty"`
+	NPCStation   bool           `json:"npc_station"` // no structure rig or role bonuses
+	ActiveJobs   int            `json:"active_jobs"`
+	ByKind       map[string]int `json:"by_kind"`
+}
+
+// IndustryCapacityAlert flags idle capacity or jobs waiting on someone.
+type IndustryCapacityAlert struct {
+	Severity    string `json:"severity"` // "warning" or "info"
+	CharacterID int64  `json:"character_id,omitempty"`
+	Kind        string `json:"kind,omitempty"`
+	Message     string `json:"message"`
+}
+
+// BuildIndustrySchedule correlates corp jobs (plus the personal jobs of
+// members whose skills were granted) with each installer's slots.
+// skills maps a character to its slot skill levels; personal maps a
+// character to its own jobs. A horizon of 0 means 48 hours.
+func BuildIndustrySchedule(
+	jobs []CorpIndustryJob,
+	members []CorpMember,
+	skills map[int64]map[int32]int,
+	personal map[int64][]CorpIndustryJob,
+	horizon time.Duration,
+	now time.Time,
+) IndustrySchedule {
+	if horizon <= 0 {
+		horizon = industryScheduleDefaultHorizon
+	}
+	sched := IndustrySchedule{
+		GeneratedAt:  now.Format(time.RFC3339),
+		HorizonHours: int(horizon.Hours()),
+		Members:      []MemberSlotUtilization{},
+		Upcoming:     []UpcomingJobCompletion{},
+		Facilities:   []IndustryFacilityUsage{},
+		Alerts:       []IndustryCapacityAlert{},
+	}
+
+	names := make(map[int64]string, len(members))
+	isMember := make(map[int64]bool, len(members))
+	for _, m := range members {
+		names[m.CharacterID] = m.Name
+		isMember[m.CharacterID] = true
+	}
+
+	type memberState struct {
+		util      MemberSlotUtilization
+		used      map[string]int
+		recent    map[string]bool // kinds used in the lookback window
+		nextEnd   string
+		npcActive int
+	}
+	states := make(map[int64]*memberState)
+	state := func(id int64, name string) *memberState {
+		st := states[id]
+		if st == nil {
+			if name == "" {
+				name = names[id]
+			}
+			st = &memberState{
+				util:   MemberSlotUtilization{CharacterID: id, Name: name},
+				used:   make(map[string]int),
+				recent: make(map[string]bool),
+			}
+			states[id] = st
+		}
+		return st
+	}
+
+	facilities := make(map[int64]*IndustryFacilityUsage)
+	lookback := now.Add(-industryScheduleLookback).Format(time.RFC3339)
+	addJob := func(j CorpIndustryJob, own bool) {
+		if j.InstallerID <= 0 {
+			return
+		}
+		kind := IndustrySlotKind(j.Activity)
+		st := state(j.InstallerID, j.InstallerName)
+		if j.StartDate >= lookback || occupiesSlot(j.Status) {
+			st.recent[kind] = true
+		}
+		if !occupiesSlot(j.Status) {
+			return
+		}
+		st.used[kind]++
+		if own {
+			st.util.PersonalJobs++
+		} else {
+			st.util.CorpJobs++
+		}
+
+		if st.nextEnd == "" || j.EndDate < st.nextEnd {
+			st.nextEnd = j.EndDate
+		}
+		end, err := time.Parse(time.RFC3339, j.EndDate)
+		if err == nil && end.Sub(now) <= horizon {
+			sched.Upcoming = append(sched.Upcoming, UpcomingJobCompletion{
+				JobID:         j.JobID,
+				InstallerID:   j.InstallerID,
+				InstallerName: st.util.Name,
+				Activity:      j.Activity,
+				ProductName:   j.ProductName,
+				Runs:          j.Runs,
+				EndDate:       j.EndDate,
+				HoursLeft:     end.Sub(now).Hours(),
+				LocationName:  j.LocationName,
+				Personal:      own,
+			})
+		}
+
+		fac := facilities[j.LocationID]
+		if fac == nil {
+			fac = &IndustryFacilityUsage{
+				LocationID:   j.LocationID,
+				LocationName: j.LocationName,
+				NPCStation:   j.LocationID >= npcStationIDMin && j.LocationID < npcStationIDMax,
+				ByKind:       make(map[string]int),
+			}
+			facilities[j.LocationID] = fac
+		}
+		fac.ActiveJobs++
+		fac.ByKind[kind]++
+		if fac.NPCStation && kind != SlotScience {
+			st.npcActive++
+		}
+	}
+	for _, j := range jobs {
+		addJob(j, false)
+	}
+	for id, own := range personal {
+		if !isMember[id] {
+			continue
+		}
+		for _, j := range own {
+			j.InstallerID = id
+			addJob(j, true)
+		}
+	}
+	for id := range skills {
+		if isMember[id] {
+			state(id, "")
+		}
+	}
+
+	totals := make(map[string]*SlotUsage, len(IndustrySlotKinds))
+	for _, kind := range IndustrySlotKinds {
+		totals[kind] = &SlotUsage{Kind: kind}
+	}
+	ready := 0
+	for _, u := range sched.Upcoming {
+		if u.HoursLeft <= 0 {
+			ready++
+		}
+	}
+	npcJobs := 0
+
+	for id, st := range states {
+		slots := map[string]int{SlotManufacturing: maxIndustrySlots, SlotScience: maxIndustrySlots, SlotReactions: maxIndustrySlots}
+		st.util.SlotsSource = "assumed"
+		if levels, ok := skills[id]; ok {
+			slots = IndustrySlotsFromSkills(levels)
+			st.util.SlotsSource = "skills"
+		}
+		st.util.NextCompletion = st.nextEnd
+		npcJobs += st.npcActive
+
+		used, capacity := 0, 0
+		for _, kind := range IndustrySlotKinds {
+			u := SlotUsage{Kind: kind, Used: st.used[kind], Max: slots[kind]}
+			if u.Max > 0 {
+				u.Utilization = min(float64(u.Used)/float64(u.Max), 1)
+			}
+			st.util.Slots = append(st.util.Slots, u)
+			if !st.recent[kind] {
+				continue
+			}
+			used += u.Used
+			capacity += u.Max
+			totals[kind].Used += u.Used
+			totals[kind].Max += u.Max
+
+			idle := u.Max - u.Used
+			switch {
+			case idle <= 0:
+			case u.Used == 0:
+				sched.Alerts = append(sched.Alerts, IndustryCapacityAlert{
+					Severity:    "warning",
+					CharacterID: id,
+					Kind:        kind,
+					Message:     fmt.Sprintf("%s has no %s jobs running (used them in the last 30 days)", st.util.Name, kind),
+				})
+			case st.util.SlotsSource == "skills":
+				sched.Alerts = append(sched.Alerts, IndustryCapacityAlert{
+					Severity:    "info",
+					CharacterID: id,
+					Kind:        kind,
+					Message:     fmt.Sprintf("%s has %d of %d %s slots idle", st.util.Name, idle, u.Max, kind),
+				})
+			}
+		}
+		if capacity > 0 {
+			st.util.Utilization = float64(used) / float64(capacity)
+		}
+		sched.Members = append(sched.Members, st.util)
+	}
+
+	for _, kind := range IndustrySlotKinds {
+		t := totals[kind]
+		if t.Max > 0 {
+			t.Utilization = float64(t.Used) / float64(t.Max)
+		}
+		sched.Totals = append(sched.Totals, *t)
+	}
+	if ready > 0 {
+		sched.Alerts = append(sched.Alerts, IndustryCapacityAlert{
+			Severity: "warning",
+			Message:  fmt.Sprintf("%d finished jobs are waiting for delivery and still hold their slots", ready),
+		})
+	}
+	if npcJobs > 0 {
+		sched.Alerts = append(sched.Alerts, IndustryCapacityAlert{
+			Severity: "info",
+			Message:  fmt.Sprintf("%d manufacturing or reaction jobs run in NPC stations, without structure rig and role bonuses", npcJobs),
+		})
+	}
+
+	for _, fac := range facilities {
+		sched.Facilities = append(sched.Facilities, *fac)
+	}
+	sort.Slice(sched.Facilities, func(i, j int) bool {
+		if sched.Facilities[i].ActiveJobs != sched.Facilities[j].ActiveJobs {
+			return sched.Facilities[i].ActiveJobs > sched.Facilities[j].ActiveJobs
+		}
+		return sched.Facilities[i].LocationID < sched.Facilities[j].LocationID
+	})
+	sort.Slice(sched.Upcoming, func(i, j int) bool {
+		if sched.Upcoming[i].EndDate != sched.Upcoming[j].EndDate {
+			return sched.Upcoming[i].EndDate < sched.Upcoming[j].EndDate
+		}
+		return sched.Upcoming[i].JobID < sched.Upcoming[j].JobID
+	})
+	sort.Slice(sched.Members, func(i, j int) bool {
+		if sched.Members[i].Utilization != sched.Members[j].Utilization {
+			return sched.Members[i].Utilization < sched.Members[j].Utilization
+		}
+		return sched.Members[i].CharacterID < sched.Members[j].CharacterID
+	})
+	sort.SliceStable(sched.Alerts, func(i, j int) bool {
+		return sched.Alerts[i].Severity == "warning" && sched.Alerts[j].Severity != "warning"
+	})
+	return sched
+}
@@ -0,0 +1,80 @@
+package corp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildIndustrySchedule(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(hours int) string { return now.Add(time.Duration(hours) * time.Hour).Format(time.RFC3339) }
+	members := []CorpMember{{CharacterID: 1, Name: "Builder"}, {CharacterID: 2, Name: "Alt"}, {CharacterID: 3, Name: "Idle"}}
+	jobs := []CorpIndustryJob{
+		{JobID: 1, InstallerID: 1, Activity: "manufacturing", Status: "active", StartDate: at(-10), EndDate: at(5), LocationID: 1035000000000},
+		{JobID: 2, InstallerID: 1, Activity: "manufacturing", Status: "active", StartDate: at(-10), EndDate: at(100), LocationID: 1035000000000},
+		{JobID: 3, InstallerID: 1, Activity: "invention", Status: "ready", StartDate: at(-30), EndDate: at(-2), LocationID: 60003760},
+		{JobID: 4, InstallerID: 2, Activity: "manufacturing", Status: "active", StartDate: at(-1), EndDate: at(20), LocationID: 60003760},
+		{JobID: 5, InstallerID: 3, Activity: "reaction", Status: "delivered", StartDate: at(-100), EndDate: at(-50), LocationID: 1035000000000},
+	}
+	skills := map[int64]map[int32]int{1: {SkillMassProduction: 4, SkillAdvancedMassProduction: 1, SkillLaboratoryOperation: 1}}
+	personal := map[int64][]CorpIndustryJob{
+		1: {{JobID: 9, Activity: "manufacturing", Status: "active", StartDate: at(-1), EndDate: at(30), LocationID: 1035000000000}},
+		7: {{JobID: 10, Activity: "manufacturing", Status: "active", EndDate: at(1)}}, // not a member
+	}
+
+	sched := BuildIndustrySchedule(jobs, members, skills, personal, 0, now)
+
+	if sched.HorizonHours != 48 {
+		t.Fatalf("horizon = %d", sched.HorizonHours)
+	}
+	byID := make(map[int64]MemberSlotUtilization)
+	for _, m := range sched.Members {
+		byID[m.CharacterID] = m
+	}
+	builder := byID[1]
+	if builder.SlotsSource != "skills" || builder.CorpJobs != 3 || builder.PersonalJobs != 1 {
+		t.Fatalf("builder = %+v", builder)
+	}
+	if s := builder.Slots[0]; s.Kind != SlotManufacturing || s.Used != 3 || s.Max != 6 {
+		t.Fatalf("builder manufacturing = %+v", s)
+	}
+	if s := builder.Slots[1]; s.Used != 1 || s.Max != 2 {
+		t.Fatalf("builder science = %+v", s)
+	}
+	if alt := byID[2]; alt.SlotsSource != "assumed" || alt.Slots[0].Max != 11 {
+		t.Fatalf("alt = %+v", alt)
+	}
+
+	// Jobs 3 (ready), 1, 4 and 9 finish within 48h; job 2 does not; job 10
+	// belongs to a non-member.
+	if len(sched.Upcoming) != 4 || sched.Upcoming[0].JobID != 3 || sched.Upcoming[0].HoursLeft > 0 {
+		t.Fatalf("upcoming = %+v", sched.Upcoming)
+	}
+	if !sched.Upcoming[3].Personal {
+		t.Fatalf("last upcoming should be the personal job: %+v", sched.Upcoming[3])
+	}
+
+	if len(sched.Facilities) != 2 || sched.Facilities[0].ActiveJobs != 3 || sched.Facilities[1].NPCStation != true {
+		t.Fatalf("facilities = %+v", sched.Facilities)
+	}
+
+	var messages []string
+	for _, a := range sched.Alerts {
+		messages = append(messages, a.Severity+": "+a.Message)
+	}
+	joined := strings.Join(messages, "\n")
+	for _, want := range []string{
+		"warning: Idle has no reactions jobs running",
+		"info: Builder has 3 of 6 manufacturing slots idle",
+		"warning: 1 finished jobs are waiting for delivery",
+		"info: 1 manufacturing or reaction jobs run in NPC stations",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing alert %q in\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "Alt has") {
+		t.Errorf("partial idle alerts need real skills:\n%s", joined)
+	}
+}
@@ -0,0 +1,383 @@
+package corp
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// memberAnalyticsDays is how much journal history analytics look at.
+	memberAnalyticsDays = 90
+	// churnRecentDays is compared against the churnBaselineDays before it.
+	churnRecentDays   = 14
+	churnBaselineDays = 42
+	// timezoneMinEvents is how many timestamped events a member needs before
+	// a timezone is inferred.
+	timezoneMinEvents = 5
+)
+
+// Churn risk levels.
+const (
+	ChurnRiskHigh   = "high"
+	ChurnRiskMedium = "medium"
+)
+
+// ActivityHeatmap counts timestamped activity by weekday (0 = Sunday) and
+// UTC hour.
+type ActivityHeatmap [7][24]int
+
+// MemberAnalytics is the corp-wide member activity and retention report.
+type MemberAnalytics struct {
+	GeneratedAt string            `json:"generated_at"`
+	WindowStart string            `json:"window_start"` // journal history starts here
+	Summary     MemberSummary     `json:"summary"`
+	Heatmap     ActivityHeatmap   `json:"heatmap"`
+	Timezones   map[string]int    `json:"timezones"` // "US", "AU", "EU", "unknown" → members
+	AtRisk      int               `json:"at_risk"`
+	Members     []MemberActivity  `json:"members"`
+	Cohorts     []RetentionCohort `json:"cohorts"`
+}
+
+// MemberActivity is one member's inferred activity pattern.
+type MemberActivity struct {
+	CharacterID   int64    `json:"character_id"`
+	Name          string   `json:"name"`
+	LastActive    string   `json:"last_active,omitempty"` // YYYY-MM-DD
+	ActiveDays30d int      `json:"active_days_30d"`
+	CurrentStreak int      `json:"current_streak"` // consecutive active days up to today or yesterday
+	LongestStreak int      `json:"longest_streak"`
+	Hours         [24]int  `json:"hours"`     // timestamped events per UTC hour
+	PeakHour      int      `json:"peak_hour"` // -1 if no timestamped events
+	Timezone      string   `json:"timezone,omitempty"`
+	RecentDays    int      `json:"recent_days"`   // active days in the last 14
+	BaselineDays  float64  `json:"baseline_days"` // active days per 14 over the 42 before
+	ChurnRisk     string   `json:"churn_risk,omitempty"`
+	ChurnReasons  []string `json:"churn_reasons,omitempty"`
+}
+
+// RetentionCohort is the members who joined in one month and the share of
+// them active in each month since. Retention[0] is the join month; entries
+// for months before the activity window are null.
+type RetentionCohort struct {
+	Month     string     `json:"month"` // YYYY-MM joined
+	Size      int        `json:"size"`
+	Retention []*float64 `json:"retention"`
+}
+
+// BuildMemberAnalytics fetches members and their activity sources and
+// computes the analytics report.
+func BuildMemberAnalytics(provider CorpDataProvider) (*MemberAnalytics, error) {
+	var (
+		journal      []CorpJournalEntry
+		members      []CorpMember
+		membersErr   error
+		transactions []CorpTransaction
+		industryJobs []CorpIndustryJob
+		miningLedger []CorpMiningEntry
+		wg           sync.WaitGroup
+	)
+	wg.Add(5)
+	go func() {
+		defer wg.Done()
+		journal, _ = fetchAllJournal(provider, memberAnalyticsDays)
+	}()
+	go func() {
+		defer wg.Done()
+		members, membersErr = provider.GetMembers()
+	}()
+	go func() {
+		defer wg.Done()
+		for div := 1; div <= 7; div++ {
+			txns, err := provider.GetTransactions(div)
+			if err == nil {
+				transactions = append(transactions, txns...)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		industryJobs, _ = provider.GetIndustryJobs()
+	}()
+	go func() {
+		defer wg.Done()
+		miningLedger, _ = provider.GetMiningLedger()
+	}()
+	wg.Wait()
+
+	if membersErr != nil {
+		return nil, membersErr
+	}
+	return computeMemberAnalytics(members, journal, transactions, industryJobs, miningLedger, time.Now().UTC()), nil
+}
+
+// memberActivityLog collects one member's active days and event hours.
+type memberActivityLog struct {
+	days  map[string]bool
+	hours [24]int
+}
+
+func computeMemberAnalytics(
+	members []CorpMember,
+	journal []CorpJournalEntry,
+	transactions []CorpTransaction,
+	jobs []CorpIndustryJob,
+	mining []CorpMiningEntry,
+	now time.Time,
+) *MemberAnalytics {
+	a := &MemberAnalytics{
+		GeneratedAt: now.Format(time.RFC3339),
+		Summary:     computeMemberSummary(members, journal, now),
+		Timezones:   map[string]int{"US": 0, "AU": 0, "EU": 0, "unknown": 0},
+		Members:     []MemberActivity{},
+		Cohorts:     []RetentionCohort{},
+	}
+
+	logs := make(map[int64]*memberActivityLog, len(members))
+	for _, m := range members {
+		logs[m.CharacterID] = &memberActivityLog{days: make(map[string]bool)}
+	}
+	windowStart := now.AddDate(0, 0, -memberAnalyticsDays)
+	a.WindowStart = windowStart.Format("2006-01-02")
+	record := func(characterID int64, date string, timed bool) {
+		t, ok := parseActivityTime(date)
+		if !ok || t.After(now) {
+			return
+		}
+		if timed {
+			a.Heatmap[t.Weekday()][t.Hour()]++
+		}
+		activity := logs[characterID]
+		if activity == nil {
+			return
+		}
+		activity.days[t.Format("2006-01-02")] = true
+		if timed {
+			activity.hours[t.Hour()]++
+		}
+	}
+
+	for _, e := range journal {
+		// Either party may be the member: bounties credit the second party,
+		// contributions and taxes usually the first.
+		switch {
+		case logs[e.FirstPartyID] != nil:
+			record(e.FirstPartyID, e.Date, true)
+		case logs[e.SecondPartyID] != nil:
+			record(e.SecondPartyID, e.Date, true)
+		default:
+			record(0, e.Date, true)
+		}
+	}
+	for _, t := range transactions {
+		// Corp transactions do not name the member; they only feed the heatmap.
+		record(0, t.Date, true)
+	}
+	for _, j := range jobs {
+		record(j.InstallerID, j.StartDate, true)
+	}
+	for _, e := range mining {
+		// Ledger days carry no time of day.
+		record(e.CharacterID, e.Date, false)
+	}
+	for _, m := range members {
+		record(m.CharacterID, m.LastLogin, true)
+	}
+	today := now.Format("2006-01-02")
+	for _, m := range members {
+		act := memberActivity(m, logs[m.CharacterID], now)
+		a.Timezones[timezoneKey(act.Timezone)]++
+		if act.ChurnRisk != "" {
+			a.AtRisk++
+		}
+		a.Members = append(a.Members, act)
+	}
+	sort.Slice(a.Members, func(i, j int) bool {
+		ri, rj := churnRiskRank(a.Members[i].ChurnRisk), churnRiskRank(a.Members[j].ChurnRisk)
+		if ri != rj {
+			return ri > rj
+		}
+		if a.Members[i].ActiveDays30d != a.Members[j].ActiveDays30d {
+			return a.Members[i].ActiveDays30d > a.Members[j].ActiveDays30d
+		}
+		return a.Members[i].CharacterID < a.Members[j].CharacterID
+	})
+
+	a.Cohorts = retentionCohorts(members, logs, windowStart, today)
+	return a
+}
+
+func parseActivityTime(s string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), true
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+func memberActivity(m CorpMember, activity *memberActivityLog, now time.Time) MemberActivity {
+	act := MemberActivity{CharacterID: m.CharacterID, Name: m.Name, PeakHour: -1, Hours: activity.hours}
+
+	days := make([]string, 0, len(activity.days))
+	for day := range activity.days {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	if len(days) > 0 {
+		act.LastActive = days[len(days)-1]
+	}
+
+	day30 := now.AddDate(0, 0, -30).Format("2006-01-02")
+	recentFrom := now.AddDate(0, 0, -churnRecentDays).Format("2006-01-02")
+	baselineFrom := now.AddDate(0, 0, -(churnRecentDays + churnBaselineDays)).Format("2006-01-02")
+	baseline := 0
+	streak := 0
+	var prev time.Time
+	for _, day := range days {
+		if day > day30 {
+			act.ActiveDays30d++
+		}
+		switch {
+		case day > recentFrom:
+			act.RecentDays++
+		case day > baselineFrom:
+			baseline++
+		}
+		t, _ := time.Parse("2006-01-02", day)
+		if !prev.IsZero() && t.Sub(prev) == 24*time.Hour {
+			streak++
+		} else {
+			streak = 1
+		}
+		act.LongestStreak = max(act.LongestStreak, streak)
+		prev = t
+	}
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+	if act.LastActive >= yesterday {
+		act.CurrentStreak = streak
+	}
+	act.BaselineDays = float64(baseline) * churnRecentDays / churnBaselineDays
+
+	events := 0
+	for hour, n := range activity.hours {
+		events += n
+		if n > 0 && (act.PeakHour < 0 || n > activity.hours[act.PeakHour]) {
+			act.PeakHour = hour
+		}
+	}
+	if events >= timezoneMinEvents {
+		act.Timezone = timezoneForHour(act.PeakHour)
+	}
+
+	act.ChurnRisk, act.ChurnReasons = churnRisk(act, m, now)
+	return act
+}
+
+// timezoneForHour maps a member's busiest UTC hour to the usual EVE
+// timezone blocks.
+func timezoneForHour(hour int) string {
+	switch {
+	case hour < 8:
+		return "US"
+	case hour < 16:
+		return "AU"
+	default:
+		return "EU"
+	}
+}
+
+func timezoneKey(tz string) string {
+	if tz == "" {
+		return "unknown"
+	}
+	return tz
+}
+
+// churnRisk flags members whose activity fell off against their own
+// baseline. Members who were never active are not flagged.
+func churnRisk(act MemberActivity, m CorpMember, now time.Time) (string, []string) {
+	if act.BaselineDays < 1 {
+		return "", nil
+	}
+	var reasons []string
+	risk := ""
+	if lastLogin, err := time.Parse(time.RFC3339, m.LastLogin); err == nil {
+		if days := int(now.Sub(lastLogin).Hours() / 24); days >= churnRecentDays {
+			risk = ChurnRiskHigh
+			reasons = append(reasons, fmt.Sprintf("not logged in for %d days", days))
+		}
+	}
+	switch {
+	case act.RecentDays == 0:
+		risk = ChurnRiskHigh
+		reasons = append(reasons, fmt.Sprintf("no activity in %d days", churnRecentDays))
+	case float64(act.RecentDays) <= act.BaselineDays/2:
+		if risk == "" {
+			risk = ChurnRiskMedium
+		}
+		reasons = append(reasons, fmt.Sprintf("active %d of the last %d days, down from %.1f", act.RecentDays, churnRecentDays, act.BaselineDays))
+	}
+	return risk, reasons
+}
+
+func churnRiskRank(risk string) int {
+	switch risk {
+	case ChurnRiskHigh:
+		return 2
+	case ChurnRiskMedium:
+		return 1
+	}
+	return 0
+}
+
+// retentionCohorts groups members by join month and measures the share of
+// each cohort active in every month since, up to the current month.
+func retentionCohorts(members []CorpMember, logs map[int64]*memberActivityLog, windowStart time.Time, today string) []RetentionCohort {
+	cohorts := make(map[string][]int64)
+	for _, m := range members {
+		joined, err := time.Parse(time.RFC3339, m.JoinedAt)
+		if err != nil {
+			continue
+		}
+		month := joined.Format("2006-01")
+		cohorts[month] = append(cohorts[month], m.CharacterID)
+	}
+	firstKnown := windowStart.Format("2006-01")
+	current := today[:len("2006-01")]
+
+	out := make([]RetentionCohort, 0, len(cohorts))
+	for month, ids := range cohorts {
+		c := RetentionCohort{Month: month, Size: len(ids), Retention: []*float64{}}
+		for m := month; m <= current; m = nextMonth(m) {
+			if m < firstKnown {
+				c.Retention = append(c.Retention, nil)
+				continue
+			}
+			active := 0
+			for _, id := range ids {
+				for day := range logs[id].days {
+					if day[:len("2006-01")] == m {
+						active++
+						break
+					}
+				}
+			}
+			share := float64(active) / float64(len(ids))
+			c.Retention = append(c.Retention, &share)
+		}
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Month > out[j].Month })
+	return out
+}
+
+func nextMonth(month string) string {
+	t, err := time.Parse("2006-01", month)
+	if err != nil {
+		return "9999-12"
+	}
+	return t.AddDate(0, 1, 0).Format("2006-01")
+}
@@ -0,0 +1,75 @@
+package corp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeMemberAnalytics(t *testing.T) {
+	now := time.Date(2026, 5, 31, 23, 0, 0, 0, time.UTC)
+	day := func(daysAgo, hour int) string {
+		d := now.AddDate(0, 0, -daysAgo)
+		return time.Date(d.Year(), d.Month(), d.Day(), hour, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	}
+	members := []CorpMember{
+		{CharacterID: 1, Name: "Steady", LastLogin: day(0, 19), JoinedAt: "2026-03-10T00:00:00Z"},
+		{CharacterID: 2, Name: "Fading", LastLogin: day(20, 2), JoinedAt: "2026-03-20T00:00:00Z"},
+		{CharacterID: 3, Name: "Ghost", JoinedAt: "2025-01-01T00:00:00Z"},
+	}
+	var journal []CorpJournalEntry
+	// Steady: bounties every day for the last 10 days, in EU evenings.
+	for i := 0; i < 10; i++ {
+		journal = append(journal, CorpJournalEntry{ID: int64(i), Date: day(i, 20), RefType: "bounty_prizes", Amount: 1000, FirstPartyID: 1000125, SecondPartyID: 1})
+	}
+	// Fading: active every other day from mid-March, gone the last three weeks.
+	for i := 21; i < 80; i += 2 {
+		journal = append(journal, CorpJournalEntry{ID: int64(100 + i), Date: day(i, 3), RefType: "player_donation", Amount: 500, FirstPartyID: 2})
+	}
+	txns := []CorpTransaction{{TransactionID: 1, Date: day(1, 20)}}
+	mining := []CorpMiningEntry{{CharacterID: 1, Date: now.AddDate(0, 0, -12).Format("2006-01-02"), Quantity: 10}}
+
+	a := computeMemberAnalytics(members, journal, txns, nil, mining, now)
+
+	if a.Summary.TotalMembers != 3 {
+		t.Fatalf("summary = %+v", a.Summary)
+	}
+	// Same weekday as yesterday at 20:00: two bounties a week apart plus the transaction.
+	if got := a.Heatmap[now.AddDate(0, 0, -1).Weekday()][20]; got != 3 {
+		t.Fatalf("heatmap = %d, want 3", got)
+	}
+	if a.Timezones["EU"] != 1 || a.Timezones["US"] != 1 || a.Timezones["unknown"] != 1 {
+		t.Fatalf("timezones = %v", a.Timezones)
+	}
+
+	byID := make(map[int64]MemberActivity)
+	for _, m := range a.Members {
+		byID[m.CharacterID] = m
+	}
+	steady := byID[1]
+	if steady.CurrentStreak != 10 || steady.LongestStreak != 10 || steady.PeakHour != 20 || steady.ChurnRisk != "" {
+		t.Fatalf("steady = %+v", steady)
+	}
+	fading := byID[2]
+	if fading.ChurnRisk != ChurnRiskHigh || len(fading.ChurnReasons) != 2 || fading.CurrentStreak != 0 {
+		t.Fatalf("fading = %+v", fading)
+	}
+	if a.AtRisk != 1 || a.Members[0].CharacterID != 2 {
+		t.Fatalf("at risk = %d, first = %+v", a.AtRisk, a.Members[0])
+	}
+	if ghost := byID[3]; ghost.ChurnRisk != "" || ghost.PeakHour != -1 {
+		t.Fatalf("ghost = %+v", ghost)
+	}
+
+	if len(a.Cohorts) != 2 || a.Cohorts[0].Month != "2026-03" || a.Cohorts[0].Size != 2 {
+		t.Fatalf("cohorts = %+v", a.Cohorts)
+	}
+	// March cohort: only Fading is active in March and April, both in May.
+	march := a.Cohorts[0].Retention
+	if len(march) != 3 || *march[0] != 0.5 || *march[1] != 0.5 || *march[2] != 1 {
+		t.Fatalf("march retention = %v", march)
+	}
+	old := a.Cohorts[1]
+	if old.Month != "2025-01" || old.Retention[0] != nil || *old.Retention[len(old.Retention)-1] != 0 {
+		t.Fatalf("old cohort = %+v", old)
+	}
+}
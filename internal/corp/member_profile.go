@@ -0,0 +1,252 @@
+package corp
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	memberProfileMaxJobs     = 25
+	memberProfileMaxTimeline = 100
+)
+
+// ErrMemberNotFound is returned when a character is neither a member nor
+// appears in any corp activity.
+var ErrMemberNotFound = errors.New("member not found")
+
+// BuildMemberProfile aggregates one member's journal contributions, mining,
+// industry jobs, market orders and activity timeline.
+// prices may be nil (ISK estimates will fall back to zero).
+func BuildMemberProfile(provider CorpDataProvider, characterID int64, prices PriceMap) (*MemberProfile, error) {
+	var (
+		journal      []CorpJournalEntry
+		members      []CorpMember
+		membersErr   error
+		industryJobs []CorpIndustryJob
+		miningLedger []CorpMiningEntry
+		orders       []CorpMarketOrder
+		wg           sync.WaitGroup
+	)
+	wg.Add(5)
+	go func() {
+		defer wg.Done()
+		journal, _ = fetchAllJournal(provider, 90)
+	}()
+	go func() {
+		defer wg.Done()
+		members, membersErr = provider.GetMembers()
+	}()
+	go func() {
+		defer wg.Done()
+		industryJobs, _ = provider.GetIndustryJobs()
+	}()
+	go func() {
+		defer wg.Done()
+		miningLedger, _ = provider.GetMiningLedger()
+	}()
+	go func() {
+		defer wg.Done()
+		orders, _ = provider.GetOrders()
+	}()
+	wg.Wait()
+
+	if membersErr != nil {
+		return nil, membersErr
+	}
+	return computeMemberProfile(characterID, journal, members, industryJobs, miningLedger, orders, prices, time.Now().UTC())
+}
+
+func computeMemberProfile(
+	characterID int64,
+	journal []CorpJournalEntry,
+	members []CorpMember,
+	jobs []CorpIndustryJob,
+	mining []CorpMiningEntry,
+	orders []CorpMarketOrder,
+	prices PriceMap,
+	now time.Time,
+) (*MemberProfile, error) {
+	p := &MemberProfile{
+		Member:       CorpMember{CharacterID: characterID},
+		IndustryJobs: []CorpIndustryJob{},
+		Orders:       []CorpMarketOrder{},
+		Timeline:     []MemberActivityEvent{},
+	}
+	found := false
+	for _, m := range members {
+		if m.CharacterID == characterID {
+			p.Member = m
+			found = true
+			break
+		}
+	}
+
+	day30ago := now.AddDate(0, 0, -30).Format("2006-01-02")
+
+	// ---- Journal contributions (first party = the member generating ISK) ----
+	var own []CorpJournalEntry
+	refTypes := make(map[string]float64)
+	contrib30 := make(map[int64]float64)
+	for _, e := range journal {
+		recent := len(e.Date) >= 10 && e.Date[:10] >= day30ago
+		if recent && e.Amount > 0 {
+			contrib30[e.FirstPartyID] += e.Amount
+		}
+		if e.FirstPartyID != characterID {
+			continue
+		}
+		own = append(own, e)
+		if p.Member.Name == "" && e.FirstPartyName != "" {
+			p.Member.Name = e.FirstPartyName
+		}
+		if e.Amount > 0 {
+			p.Contribution90d += e.Amount
+			if recent {
+				p.Contribution30d += e.Amount
+				refTypes[e.RefType] += e.Amount
+			}
+		}
+	}
+	p.Category = categorizeMember(refTypes)
+	p.IncomeBySource = computeIncomeBySource(own, day30ago)
+	if p.IncomeBySource == nil {
+		p.IncomeBySource = []IncomeSource{}
+	}
+	p.DailyContributions = computeDailyPnL(own, 30, now)
+
+	if p.Contribution30d > 0 {
+		total := 0.0
+		rank := 1
+		for id, amount := range contrib30 {
+			total += amount
+			if id != characterID && amount > p.Contribution30d {
+				rank++
+			}
+		}
+		p.Rank30d = rank
+		p.ShareOfCorp30d = p.Contribution30d / total * 100
+	}
+
+	// ---- Mining, industry, market (reuse the corp-wide summaries) ----
+	var ownMining []CorpMiningEntry
+	for _, e := range mining {
+		if e.CharacterID == characterID {
+			ownMining = append(ownMining, e)
+			if p.Member.Name == "" && e.CharacterName != "" {
+				p.Member.Name = e.CharacterName
+			}
+		}
+	}
+	p.Mining = computeMiningSummary(ownMining, prices)
+
+	var ownJobs []CorpIndustryJob
+	for _, j := range jobs {
+		if j.InstallerID == characterID {
+			ownJobs = append(ownJobs, j)
+		}
+	}
+	p.Industry = computeIndustrySummary(ownJobs, prices, now)
+	sort.Slice(ownJobs, func(i, j int) bool { return ownJobs[i].StartDate > ownJobs[j].StartDate })
+	if len(ownJobs) > memberProfileMaxJobs {
+		p.IndustryJobs = append(p.IndustryJobs, ownJobs[:memberProfileMaxJobs]...)
+	} else {
+		p.IndustryJobs = append(p.IndustryJobs, ownJobs...)
+	}
+
+	for _, o := range orders {
+		if o.CharacterID == characterID {
+			p.Orders = append(p.Orders, o)
+		}
+	}
+	p.Market = computeMarketSummary(p.Orders)
+
+	if !found && len(own) == 0 && len(ownMining) == 0 && len(ownJobs) == 0 && len(p.Orders) == 0 {
+		return nil, ErrMemberNotFound
+	}
+	if p.Member.Name == "" {
+		p.Member.Name = "Unknown"
+	}
+	if p.Member.LastLogin != "" {
+		if t, err := time.Parse(time.RFC3339, p.Member.LastLogin); err == nil {
+			p.IsOnline = now.Sub(t) < 15*time.Minute
+		}
+	}
+
+	p.Timeline = memberTimeline(p, own, ownMining, ownJobs)
+	return p, nil
+}
+
+// memberTimeline merges journal entries, daily mining, industry job
+// starts/completions, order placements and the last login, newest first.
+func memberTimeline(p *MemberProfile, journal []CorpJournalEntry, mining []CorpMiningEntry, jobs []CorpIndustryJob) []MemberActivityEvent {
+	events := make([]MemberActivityEvent, 0, len(journal)+len(jobs)+len(p.Orders)+1)
+
+	for _, e := range journal {
+		label := categoryLabels[refTypeCategory[e.RefType]]
+		if label == "" {
+			label = categoryLabels["other"]
+		}
+		events = append(events, MemberActivityEvent{
+			Date:  e.Date,
+			Kind:  "journal",
+			Title: fmt.Sprintf("%s (%s)", label, strings.ReplaceAll(e.RefType, "_", " ")),
+			ISK:   e.Amount,
+		})
+	}
+
+	// Mining ledger rows are daily per ore; collapse to one event per day.
+	minedByDay := make(map[string]int64)
+	for _, e := range mining {
+		minedByDay[e.Date] += e.Quantity
+	}
+	for day, qty := range minedByDay {
+		events = append(events, MemberActivityEvent{
+			Date:  day,
+			Kind:  "mining",
+			Title: fmt.Sprintf("Mined %d units of ore", qty),
+		})
+	}
+
+	for _, j := range jobs {
+		activity := strings.ReplaceAll(j.Activity, "_", " ")
+		events = append(events, MemberActivityEvent{
+			Date:  j.StartDate,
+			Kind:  "industry",
+			Title: fmt.Sprintf("Started %s: %s ×%d", activity, j.ProductName, j.Runs),
+		})
+		if j.Status == "delivered" && j.EndDate != "" {
+			events = append(events, MemberActivityEvent{
+				Date:  j.EndDate,
+				Kind:  "industry",
+				Title: fmt.Sprintf("Delivered %s: %s ×%d", activity, j.ProductName, j.Runs),
+			})
+		}
+	}
+
+	for _, o := range p.Orders {
+		side := "sell"
+		if o.IsBuyOrder {
+			side = "buy"
+		}
+		events = append(events, MemberActivityEvent{
+			Date:  o.Issued,
+			Kind:  "order",
+			Title: fmt.Sprintf("Placed %s order: %s ×%d", side, o.TypeName, o.VolumeTotal),
+			ISK:   o.Price * float64(o.VolumeTotal),
+		})
+	}
+
+	if p.Member.LastLogin != "" {
+		events = append(events, MemberActivityEvent{Date: p.Member.LastLogin, Kind: "login", Title: "Logged in"})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Date > events[j].Date })
+	if len(events) > memberProfileMaxTimeline {
+		events = events[:memberProfileMaxTimeline]
+	}
+	return events
+}
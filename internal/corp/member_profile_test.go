@@ -0,0 +1,83 @@
+package corp
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestComputeMemberProfile_AggregatesOwnActivity(t *testing.T) {
+	now := time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
+	members := []CorpMember{
+		{CharacterID: 1, Name: "Miner Mike", LastLogin: "2026-05-20T11:55:00Z"},
+		{CharacterID: 2, Name: "Ratter Rita"},
+	}
+	journal := []CorpJournalEntry{
+		{ID: 1, Date: "2026-05-19T10:00:00Z", RefType: "bounty_prizes", Amount: 300, FirstPartyID: 2},
+		{ID: 2, Date: "2026-05-18T10:00:00Z", RefType: "moon_mining_extraction_tax", Amount: 100, FirstPartyID: 1},
+		{ID: 3, Date: "2026-03-01T10:00:00Z", RefType: "moon_mining_extraction_tax", Amount: 50, FirstPartyID: 1},
+	}
+	mining := []CorpMiningEntry{
+		{CharacterID: 1, Date: "2026-05-17", TypeID: 1230, TypeName: "Veldspar", Quantity: 1000},
+		{CharacterID: 1, Date: "2026-05-17", TypeID: 1228, TypeName: "Scordite", Quantity: 500},
+		{CharacterID: 2, Date: "2026-05-17", TypeID: 1230, Quantity: 9999},
+	}
+	jobs := []CorpIndustryJob{
+		{JobID: 1, InstallerID: 1, Activity: "manufacturing", ProductTypeID: 34, ProductName: "Widget", Status: "delivered", Runs: 2,
+			StartDate: "2026-05-10T00:00:00Z", EndDate: "2026-05-11T00:00:00Z"},
+		{JobID: 2, InstallerID: 2, Activity: "manufacturing", Status: "active"},
+	}
+	orders := []CorpMarketOrder{
+		{OrderID: 1, CharacterID: 1, TypeName: "Veldspar", Price: 10, VolumeRemain: 5, VolumeTotal: 10, Issued: "2026-05-16T00:00:00Z"},
+	}
+	prices := PriceMap{1230: 2, 1228: 4}
+
+	p, err := computeMemberProfile(1, journal, members, jobs, mining, orders, prices, now)
+	if err != nil {
+		t.Fatalf("computeMemberProfile: %v", err)
+	}
+	if p.Member.Name != "Miner Mike" || !p.IsOnline {
+		t.Errorf("member = %+v online=%v", p.Member, p.IsOnline)
+	}
+	if p.Contribution30d != 100 || p.Contribution90d != 150 {
+		t.Errorf("contribution 30d=%v 90d=%v, want 100/150", p.Contribution30d, p.Contribution90d)
+	}
+	if p.Rank30d != 2 || p.ShareOfCorp30d != 25 || p.Category != "miner" {
+		t.Errorf("rank=%d share=%v category=%s, want 2/25/miner", p.Rank30d, p.ShareOfCorp30d, p.Category)
+	}
+	if p.Mining.TotalVolume30d != 1500 || p.Mining.EstimatedISK != 4000 {
+		t.Errorf("mining = %+v, want 1500 units / 4000 ISK", p.Mining)
+	}
+	if len(p.IndustryJobs) != 1 || p.Industry.CompletedJobs30d != 1 {
+		t.Errorf("industry jobs=%d completed=%d, want 1/1", len(p.IndustryJobs), p.Industry.CompletedJobs30d)
+	}
+	if p.Market.ActiveSellOrders != 1 || p.Market.TotalSellValue != 50 {
+		t.Errorf("market = %+v", p.Market)
+	}
+	// login, journal, mining day, order, job delivered, job started, old journal
+	if len(p.Timeline) != 7 || p.Timeline[0].Kind != "login" || p.Timeline[len(p.Timeline)-1].ISK != 50 {
+		t.Fatalf("timeline = %+v", p.Timeline)
+	}
+}
+
+func TestComputeMemberProfile_UnknownCharacter(t *testing.T) {
+	_, err := computeMemberProfile(42, nil, []CorpMember{{CharacterID: 1}}, nil, nil, nil, nil, time.Now())
+	if !errors.Is(err, ErrMemberNotFound) {
+		t.Fatalf("err = %v, want ErrMemberNotFound", err)
+	}
+}
+
+func TestBuildMemberProfile_Demo(t *testing.T) {
+	d := NewDemoCorpProvider()
+	members, err := d.GetMembers()
+	if err != nil || len(members) == 0 {
+		t.Fatalf("demo members: %v", err)
+	}
+	p, err := BuildMemberProfile(d, members[0].CharacterID, d.DemoPrices())
+	if err != nil {
+		t.Fatalf("BuildMemberProfile: %v", err)
+	}
+	if p.Member.CharacterID != members[0].CharacterID || len(p.Timeline) == 0 {
+		t.Errorf("profile member=%d timeline=%d", p.Member.CharacterID, len(p.Timeline))
+	}
+}
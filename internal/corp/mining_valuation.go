@@ -0,0 +1,323 @@
+package corp
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"eve-flipper/internal/sde"
+)
+
+// DefaultMiningRefineYield is the refine yield moon ores are valued at. A
+// rigged Tatara with good skills refines around 0.87; athanors and partial
+// skills land lower.
+const DefaultMiningRefineYield = 0.80
+
+// moonExtractionGapDays splits an observer's mining days into extractions:
+// a chunk is mined out within days, so a longer quiet spell means the next
+// chunk arrived.
+const moonExtractionGapDays = 3
+
+// SDE groups of moon ores: ubiquitous, common, uncommon, rare, exceptional.
+var moonOreGroups = map[int32]bool{1884: true, 1920: true, 1921: true, 1922: true, 1923: true}
+
+// IsMoonOre reports whether t is a moon ore, including its variants and
+// compressed forms.
+func IsMoonOre(t *sde.ItemType) bool {
+	return t != nil && moonOreGroups[t.GroupID]
+}
+
+// NormalizeMiningRefineYield clamps a requested refine yield; 0 or an
+// invalid value means the default.
+func NormalizeMiningRefineYield(yield float64) float64 {
+	if yield <= 0 || math.IsNaN(yield) || math.IsInf(yield, 0) {
+		return DefaultMiningRefineYield
+	}
+	return math.Min(math.Max(yield, 0.5), 1)
+}
+
+// MoonOreRefinePrices returns a copy of prices in which every moon ore is
+// valued at its refined output (SDE typeMaterials × yield, priced from the
+// same map) instead of its own price. The raw ore price only lags the moon
+// materials that make up nearly all of its value. Ores whose outputs have
+// no price keep their own.
+func MoonOreRefinePrices(data *sde.Data, prices PriceMap, yield float64) PriceMap {
+	if data == nil || len(prices) == 0 {
+		return prices
+	}
+	ind := data.IndustryData()
+	if ind == nil {
+		return prices
+	}
+	yield = NormalizeMiningRefineYield(yield)
+	out := make(PriceMap, len(prices))
+	for typeID, p := range prices {
+		out[typeID] = p
+	}
+	for typeID, rm := range ind.Reprocessing {
+		t := data.Types[typeID]
+		if !IsMoonOre(t) {
+			continue
+		}
+		portion := float64(max(t.PortionSize, 1))
+		value := 0.0
+		for _, y := range rm.Yields {
+			value += float64(y.Quantity) * yield * prices[y.TypeID]
+		}
+		if value > 0 {
+			out[typeID] = value / portion
+		}
+	}
+	return out
+}
+
+// MiningReport rolls the mining ledger up by month and member and derives
+// the moon extraction calendar from the observers' ledgers.
+type MiningReport struct {
+	RefineYield   float64                `json:"refine_yield"`
+	TotalQuantity int64                  `json:"total_quantity"`
+	TotalISK      float64                `json:"total_isk"`
+	MoonISK       float64                `json:"moon_isk"` // mined at moon observers
+	Months        []MiningMonth          `json:"months"`
+	Extractions   []MoonExtraction       `json:"extractions"`
+	Observers     []MoonObserverSchedule `json:"observers"`
+}
+
+// MiningMonth is one calendar month of mining, newest first in a report.
+type MiningMonth struct {
+	Month    string              `json:"month"` // YYYY-MM
+	Quantity int64               `json:"quantity"`
+	ISK      float64             `json:"isk"`
+	MoonISK  float64             `json:"moon_isk"`
+	Members  []MiningMemberMonth `json:"members"`
+}
+
+// MiningMemberMonth is one member's mining in a month.
+type MiningMemberMonth struct {
+	CharacterID   int64   `json:"character_id"`
+	CharacterName string  `json:"character_name"`
+	Quantity      int64   `json:"quantity"`
+	ISK           float64 `json:"isk"`
+	MoonISK       float64 `json:"moon_isk"`
+	Share         float64 `json:"share"` // of the month's ISK, 0-1
+}
+
+// MoonExtraction is one chunk as seen in an observer's ledger: the run of
+// days it was mined on.
+type MoonExtraction struct {
+	ObserverID   int64      `json:"observer_id"`
+	ObserverName string     `json:"observer_name,omitempty"`
+	Start        string     `json:"start"` // first mining day, YYYY-MM-DD
+	End          string     `json:"end"`   // last mining day
+	Days         int        `json:"days"`
+	Quantity     int64      `json:"quantity"`
+	ISK          float64    `json:"isk"`
+	Miners       int        `json:"miners"`
+	Ores         []OreEntry `json:"ores"`
+}
+
+// MoonObserverSchedule is an observer's extraction rhythm. NextExpected is
+// the last extraction plus the median interval, once there are two.
+type MoonObserverSchedule struct {
+	ObserverID   int64  `json:"observer_id"`
+	ObserverName string `json:"observer_name,omitempty"`
+	Extractions  int    `json:"extractions"`
+	IntervalDays int    `json:"interval_days,omitempty"`
+	LastStart    string `json:"last_start"`
+	NextExpected string `json:"next_expected,omitempty"`
+}
+
+// BuildMiningReport values the ledger with prices (see MoonOreRefinePrices)
+// and rolls it up. Entries with an unparseable date are skipped.
+func BuildMiningReport(entries []CorpMiningEntry, prices PriceMap, yield float64) MiningReport {
+	report := MiningReport{
+		RefineYield: NormalizeMiningRefineYield(yield),
+		Months:      []MiningMonth{},
+		Extractions: []MoonExtraction{},
+		Observers:   []MoonObserverSchedule{},
+	}
+
+	months := make(map[string]*MiningMonth)
+	members := make(map[string]map[int64]*MiningMemberMonth)
+	observerDays := make(map[int64]map[string][]CorpMiningEntry)
+	observerNames := make(map[int64]string)
+
+	for _, e := range entries {
+		date, ok := miningEntryDate(e.Date)
+		if !ok {
+			continue
+		}
+		isk := prices[e.TypeID] * float64(e.Quantity)
+		moonISK := 0.0
+		if e.ObserverID > 0 {
+			moonISK = isk
+		}
+		report.TotalQuantity += e.Quantity
+		report.TotalISK += isk
+		report.MoonISK += moonISK
+
+		key := date.Format("2006-01")
+		m := months[key]
+		if m == nil {
+			m = &MiningMonth{Month: key}
+			months[key] = m
+			members[key] = make(map[int64]*MiningMemberMonth)
+		}
+		m.Quantity += e.Quantity
+		m.ISK += isk
+		m.MoonISK += moonISK
+		mm := members[key][e.CharacterID]
+		if mm == nil {
+			mm = &MiningMemberMonth{CharacterID: e.CharacterID, CharacterName: e.CharacterName}
+			members[key][e.CharacterID] = mm
+		}
+		mm.Quantity += e.Quantity
+		mm.ISK += isk
+		mm.MoonISK += moonISK
+
+		if e.ObserverID > 0 {
+			if observerDays[e.ObserverID] == nil {
+				observerDays[e.ObserverID] = make(map[string][]CorpMiningEntry)
+			}
+			day := date.Format("2006-01-02")
+			observerDays[e.ObserverID][day] = append(observerDays[e.ObserverID][day], e)
+			if e.ObserverName != "" {
+				observerNames[e.ObserverID] = e.ObserverName
+			}
+		}
+	}
+
+	for key, m := range months {
+		for _, mm := range members[key] {
+			if m.ISK > 0 {
+				mm.Share = mm.ISK / m.ISK
+			}
+			m.Members = append(m.Members, *mm)
+		}
+		sort.Slice(m.Members, func(i, j int) bool {
+			if m.Members[i].ISK != m.Members[j].ISK {
+				return m.Members[i].ISK > m.Members[j].ISK
+			}
+			if m.Members[i].Quantity != m.Members[j].Quantity {
+				return m.Members[i].Quantity > m.Members[j].Quantity
+			}
+			return m.Members[i].CharacterID < m.Members[j].CharacterID
+		})
+		report.Months = append(report.Months, *m)
+	}
+	sort.Slice(report.Months, func(i, j int) bool { return report.Months[i].Month > report.Months[j].Month })
+
+	for observerID, days := range observerDays {
+		extractions := moonExtractions(observerID, observerNames[observerID], days, prices)
+		report.Extractions = append(report.Extractions, extractions...)
+		report.Observers = append(report.Observers, moonObserverSchedule(observerID, observerNames[observerID], extractions))
+	}
+	sort.Slice(report.Extractions, func(i, j int) bool {
+		if report.Extractions[i].Start != report.Extractions[j].Start {
+			return report.Extractions[i].Start > report.Extractions[j].Start
+		}
+		return report.Extractions[i].ObserverID < report.Extractions[j].ObserverID
+	})
+	sort.Slice(report.Observers, func(i, j int) bool {
+		a, b := report.Observers[i], report.Observers[j]
+		if (a.NextExpected == "") != (b.NextExpected == "") {
+			return a.NextExpected != ""
+		}
+		if a.NextExpected != b.NextExpected {
+			return a.NextExpected < b.NextExpected
+		}
+		return a.ObserverID < b.ObserverID
+	})
+	return report
+}
+
+// miningEntryDate parses a ledger date; ESI sends YYYY-MM-DD.
+func miningEntryDate(s string) (time.Time, bool) {
+	if len(s) < len("2006-01-02") {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02", s[:len("2006-01-02")])
+	return t, err == nil
+}
+
+// moonExtractions groups an observer's mining days into extractions, oldest
+// first.
+func moonExtractions(observerID int64, name string, days map[string][]CorpMiningEntry, prices PriceMap) []MoonExtraction {
+	dates := make([]string, 0, len(days))
+	for day := range days {
+		dates = append(dates, day)
+	}
+	sort.Strings(dates)
+
+	var out []MoonExtraction
+	var cur *MoonExtraction
+	var miners map[int64]bool
+	var ores map[int32]*OreEntry
+	var last time.Time
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		cur.Miners = len(miners)
+		cur.Ores = make([]OreEntry, 0, len(ores))
+		for _, oe := range ores {
+			cur.Ores = append(cur.Ores, *oe)
+		}
+		sort.Slice(cur.Ores, func(i, j int) bool {
+			if cur.Ores[i].EstimatedISK != cur.Ores[j].EstimatedISK {
+				return cur.Ores[i].EstimatedISK > cur.Ores[j].EstimatedISK
+			}
+			return cur.Ores[i].TypeID < cur.Ores[j].TypeID
+		})
+		out = append(out, *cur)
+	}
+	for _, day := range dates {
+		date, _ := time.Parse("2006-01-02", day)
+		if cur == nil || date.Sub(last) > moonExtractionGapDays*24*time.Hour {
+			flush()
+			cur = &MoonExtraction{ObserverID: observerID, ObserverName: name, Start: day}
+			miners = make(map[int64]bool)
+			ores = make(map[int32]*OreEntry)
+		}
+		cur.End = day
+		cur.Days++
+		last = date
+		for _, e := range days[day] {
+			isk := prices[e.TypeID] * float64(e.Quantity)
+			cur.Quantity += e.Quantity
+			cur.ISK += isk
+			miners[e.CharacterID] = true
+			oe := ores[e.TypeID]
+			if oe == nil {
+				oe = &OreEntry{TypeID: e.TypeID, TypeName: e.TypeName}
+				ores[e.TypeID] = oe
+			}
+			oe.Quantity += e.Quantity
+			oe.EstimatedISK += isk
+		}
+	}
+	flush()
+	return out
+}
+
+func moonObserverSchedule(observerID int64, name string, extractions []MoonExtraction) MoonObserverSchedule {
+	s := MoonObserverSchedule{ObserverID: observerID, ObserverName: name, Extractions: len(extractions)}
+	if len(extractions) == 0 {
+		return s
+	}
+	s.LastStart = extractions[len(extractions)-1].Start
+	if len(extractions) < 2 {
+		return s
+	}
+	intervals := make([]int, 0, len(extractions)-1)
+	for i := 1; i < len(extractions); i++ {
+		prev, _ := time.Parse("2006-01-02", extractions[i-1].Start)
+		next, _ := time.Parse("2006-01-02", extractions[i].Start)
+		intervals = append(intervals, int(math.Round(next.Sub(prev).Hours()/24)))
+	}
+	sort.Ints(intervals)
+	s.IntervalDays = intervals[len(intervals)/2]
+	last, _ := time.Parse("2006-01-02", s.LastStart)
+	s.NextExpected = last.AddDate(0, 0, s.IntervalDays).Format("2006-01-02")
+	return s
+}
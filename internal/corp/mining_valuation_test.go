@@ -0,0 +1,86 @@
+package corp
+
+import (
+	"math"
+	"testing"
+
+	"eve-flipper/internal/sde"
+)
+
+func TestMoonOreRefinePrices(t *testing.T) {
+	ind := sde.NewIndustryData()
+	ind.Reprocessing[45494] = &sde.ReprocessingMaterial{TypeID: 45494, Yields: []sde.MaterialYield{{TypeID: 16640, Quantity: 40}}}
+	ind.Reprocessing[1230] = &sde.ReprocessingMaterial{TypeID: 1230, Yields: []sde.MaterialYield{{TypeID: 34, Quantity: 400}}}
+	data := &sde.Data{
+		Types: map[int32]*sde.ItemType{
+			45494: {ID: 45494, Name: "Cobaltite", GroupID: 1920, CategoryID: 25, PortionSize: 100},
+			1230:  {ID: 1230, Name: "Veldspar", GroupID: 462, CategoryID: 25, PortionSize: 100},
+		},
+		Industry: ind,
+	}
+	prices := PriceMap{45494: 50, 1230: 5, 16640: 250, 34: 4}
+
+	refined := MoonOreRefinePrices(data, prices, 0.8)
+	// 40 cobalt × 0.8 × 250 ISK per 100 units.
+	if got := refined[45494]; math.Abs(got-80) > 1e-9 {
+		t.Fatalf("cobaltite = %v, want 80", got)
+	}
+	if refined[1230] != 5 {
+		t.Fatalf("regular ore should keep its price, got %v", refined[1230])
+	}
+	if prices[45494] != 50 {
+		t.Fatal("input price map was modified")
+	}
+}
+
+func TestBuildMiningReport_RollupsAndExtractions(t *testing.T) {
+	prices := PriceMap{45494: 100, 1230: 5}
+	var entries []CorpMiningEntry
+	// Observer 1 chunks mined on 1-2 May, 8-9 May and 15 May; observer 2
+	// once. Bob also mines belt ore in April.
+	for _, day := range []string{"2026-05-01", "2026-05-02", "2026-05-08", "2026-05-09", "2026-05-15"} {
+		entries = append(entries,
+			CorpMiningEntry{CharacterID: 1, CharacterName: "Alice", Date: day, TypeID: 45494, Quantity: 100, ObserverID: 11, ObserverName: "Drill"},
+			CorpMiningEntry{CharacterID: 2, CharacterName: "Bob", Date: day, TypeID: 45494, Quantity: 50, ObserverID: 11, ObserverName: "Drill"},
+		)
+	}
+	entries = append(entries,
+		CorpMiningEntry{CharacterID: 2, CharacterName: "Bob", Date: "2026-05-20", TypeID: 45494, Quantity: 10, ObserverID: 12},
+		CorpMiningEntry{CharacterID: 2, CharacterName: "Bob", Date: "2026-04-30", TypeID: 1230, Quantity: 1000},
+		CorpMiningEntry{CharacterID: 2, Date: "garbage", TypeID: 1230, Quantity: 1000},
+	)
+
+	report := BuildMiningReport(entries, prices, 0)
+	if report.RefineYield != DefaultMiningRefineYield {
+		t.Fatalf("yield = %v", report.RefineYield)
+	}
+	if len(report.Months) != 2 || report.Months[0].Month != "2026-05" {
+		t.Fatalf("months = %+v", report.Months)
+	}
+	may := report.Months[0]
+	if may.ISK != 76000 || may.MoonISK != 76000 || may.Members[0].CharacterName != "Alice" || may.Members[0].ISK != 50000 {
+		t.Fatalf("may = %+v", may)
+	}
+	if april := report.Months[1]; april.ISK != 5000 || april.MoonISK != 0 || april.Members[0].Share != 1 {
+		t.Fatalf("april = %+v", april)
+	}
+
+	if len(report.Extractions) != 4 {
+		t.Fatalf("extractions = %+v", report.Extractions)
+	}
+	if e := report.Extractions[1]; e.ObserverID != 11 || e.Start != "2026-05-15" || e.Days != 1 || e.Miners != 2 {
+		t.Fatalf("latest drill extraction = %+v", e)
+	}
+	if e := report.Extractions[3]; e.Start != "2026-05-01" || e.End != "2026-05-02" || e.Quantity != 300 || e.ISK != 30000 {
+		t.Fatalf("first extraction = %+v", e)
+	}
+	if len(report.Observers) != 2 {
+		t.Fatalf("observers = %+v", report.Observers)
+	}
+	if o := report.Observers[0]; o.ObserverID != 11 || o.IntervalDays != 7 || o.NextExpected != "2026-05-22" {
+		t.Fatalf("drill schedule = %+v", o)
+	}
+	if o := report.Observers[1]; o.ObserverID != 12 || o.NextExpected != "" || o.LastStart != "2026-05-20" {
+		t.Fatalf("single-extraction schedule = %+v", o)
+	}
+}
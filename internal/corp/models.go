@@ -52,6 +52,7 @@ type CorpMember struct {
 	// From membertracking (requires Director or equivalent)
 	LastLogin  string `json:"last_login,omitempty"`  // ISO 8601
 	LogoffDate string `json:"logoff_date,omitempty"` // ISO 8601
+	JoinedAt   string `json:"joined_at,omitempty"`   // ISO 8601, when the member joined the corp
 	ShipTypeID int32  `json:"ship_type_id,omitempty"`
 	ShipName   string `json:"ship_name,omitempty"` // enriched from SDE
 	LocationID int64  `json:"location_id,omitempty"`
@@ -86,6 +87,8 @@ type CorpMiningEntry struct {
 	TypeID        int32  `json:"type_id"`
 	TypeName      string `json:"type_name,omitempty"` // enriched from SDE
 	Quantity      int64  `json:"quantity"`            // units mined
+	ObserverID    int64  `json:"observer_id,omitempty"`
+	ObserverName  string `json:"observer_name,omitempty"` // enriched
 }
 
 // CorpMarketOrder mirrors ESI GET /corporations/{id}/orders/.
@@ -106,6 +109,67 @@ type CorpMarketOrder struct {
 	RegionID      int32   `json:"region_id"`
 }
 
+// CorpAsset mirrors ESI GET /corporations/{id}/assets/.
+type CorpAsset struct {
+	ItemID       int64  `json:"item_id"`
+	TypeID       int32  `json:"type_id"`
+	TypeName     string `json:"type_name,omitempty"` // enriched from SDE
+	LocationID   int64  `json:"location_id"`
+	LocationFlag string `json:"location_flag"` // StructureFuel, ServiceSlot0, CorpSAG1, ...
+	LocationType string `json:"location_type"` // station, solar_system, item, other
+	Quantity     int32  `json:"quantity"`
+	IsSingleton  bool   `json:"is_singleton"`
+	ItemName     string `json:"item_name,omitempty"` // enriched for structures (player-given name)
+}
+
+// CorpContract mirrors ESI GET /corporations/{id}/contracts/.
+type CorpContract struct {
+	ContractID     int32   `json:"contract_id"`
+	Type           string  `json:"type"`   // item_exchange, courier, auction
+	Status         string  `json:"status"` // outstanding, in_progress, finished, expired, ...
+	Title          string  `json:"title,omitempty"`
+	IssuerID       int64   `json:"issuer_id"`
+	IssuerName     string  `json:"issuer_name,omitempty"` // enriched
+	AcceptorID     int64   `json:"acceptor_id,omitempty"`
+	AcceptorName   string  `json:"acceptor_name,omitempty"` // enriched
+	ForCorporation bool    `json:"for_corporation"`
+	Availability   string  `json:"availability"` // public, personal, corporation, alliance
+	Price          float64 `json:"price,omitempty"`
+	Reward         float64 `json:"reward,omitempty"`
+	Collateral     float64 `json:"collateral,omitempty"`
+	Volume         float64 `json:"volume,omitempty"` // m3, couriers
+	StartLocation  int64   `json:"start_location_id,omitempty"`
+	StartName      string  `json:"start_location_name,omitempty"` // enriched
+	EndLocation    int64   `json:"end_location_id,omitempty"`
+	EndName        string  `json:"end_location_name,omitempty"` // enriched
+	DaysToComplete int     `json:"days_to_complete,omitempty"`
+	DateIssued     string  `json:"date_issued"`
+	DateExpired    string  `json:"date_expired"`
+	DateAccepted   string  `json:"date_accepted,omitempty"`
+	DateCompleted  string  `json:"date_completed,omitempty"`
+}
+
+// CorpStructure mirrors ESI GET /corporations/{id}/structures/.
+type CorpStructure struct {
+	StructureID   int64              `json:"structure_id"`
+	TypeID        int32              `json:"type_id"`
+	TypeName      string             `json:"type_name,omitempty"` // enriched from SDE
+	Name          string             `json:"name"`
+	SystemID      int32              `json:"system_id"`
+	SystemName    string             `json:"system_name,omitempty"` // enriched from SDE
+	State         string             `json:"state"`                 // shield_vulnerable, armor_reinforce, hull_reinforce, low_power, ...
+	StateTimerEnd string             `json:"state_timer_end,omitempty"`
+	FuelExpires   string             `json:"fuel_expires,omitempty"` // empty when unfueled
+	ReinforceHour int                `json:"reinforce_hour"`
+	Services      []StructureService `json:"services"`
+}
+
+// StructureService is one service module fitted to a structure.
+type StructureService struct {
+	Name  string `json:"name"`
+	State string `json:"state"` // online, offline, cleanup
+}
+
 // ============================================================
 // Dashboard aggregated response
 // ============================================================
@@ -115,6 +179,9 @@ type CorpDashboard struct {
 	Info    CorpInfo             `json:"info"`
 	IsDemo  bool                 `json:"is_demo"`
 	Wallets []CorpWalletDivision `json:"wallets"`
+	// Section order and toggles used to build this dashboard; disabled
+	// sections are left at their zero values.
+	Layout DashboardLayout `json:"layout"`
 	// Aggregated financials
 	TotalBalance float64 `json:"total_balance"`
 	Revenue30d   float64 `json:"revenue_30d"`
@@ -137,6 +204,20 @@ type CorpDashboard struct {
 	MiningSummary MiningSummary `json:"mining_summary"`
 	// Market orders summary
 	MarketSummary MarketSummary `json:"market_summary"`
+	// Structure / starbase fuel status (from corp assets)
+	FuelSummary FuelSummary `json:"fuel_summary"`
+	// Net income projection for the next 30 days
+	IncomeProjection IncomeProjection `json:"income_projection"`
+	// Data sources that failed to load; their sections are left empty.
+	SectionErrors []SectionError `json:"section_errors,omitempty"`
+	// ESI route drift affecting the fetched sections (live mode only), so an
+	// empty section comes with a reason instead of silently showing nothing.
+	ESIIssues []ESICompatIssue `json:"esi_issues,omitempty"`
+
+	// journalFrom is the date (YYYY-MM-DD) of the oldest journal entry
+	// fetched. ESI keeps about 30 days, so DailyPnL before it is zero fill
+	// and the day itself is usually only partly covered.
+	journalFrom string
 }
 
 // IncomeSource represents a category of income/expense.
@@ -157,6 +238,32 @@ type DailyPnLEntry struct {
 	Transactions int     `json:"transactions"` // journal entry count
 }
 
+// IncomeProjection is a forward net income estimate with an uncertainty band.
+type IncomeProjection struct {
+	Days           int               `json:"days"`
+	Method         string            `json:"method"`
+	Confidence     float64           `json:"confidence"`   // band coverage, percent
+	HistoryDays    int               `json:"history_days"` // completed days used for the fit
+	BaselineDaily  float64           `json:"baseline_daily"`
+	DailyStdDev    float64           `json:"daily_std_dev"`
+	WeekdayOffsets []float64         `json:"weekday_offsets"` // indexed by weekday, 0 = Sunday
+	TotalExpected  float64           `json:"total_expected"`
+	TotalLow       float64           `json:"total_low"`
+	TotalHigh      float64           `json:"total_high"`
+	Daily          []ProjectionPoint `json:"daily"`
+}
+
+// ProjectionPoint is one projected day.
+type ProjectionPoint struct {
+	Date          string  `json:"date"` // YYYY-MM-DD
+	Expected      float64 `json:"expected"`
+	Low           float64 `json:"low"`
+	High          float64 `json:"high"`
+	CumulativeExp float64 `json:"cumulative_expected"`
+	CumulativeLow float64 `json:"cumulative_low"`
+	CumulativeHi  float64 `json:"cumulative_high"`
+}
+
 // MemberContribution represents a member's economic contribution.
 type MemberContribution struct {
 	CharacterID int64   `json:"character_id"`
@@ -211,7 +318,7 @@ type OreEntry struct {
 	TypeID       int32   `json:"type_id"`
 	TypeName     string  `json:"type_name"`
 	Quantity     int64   `json:"quantity"`
-	EstimatedISK float64 `json:"estimated_isk,omitempty"` // quantity × adjusted price
+	EstimatedISK float64 `json:"estimated_isk,omitempty"` // quantity × adjusted price (moon ores: refined value)
 }
 
 // MarketSummary holds aggregated market order stats.
@@ -223,9 +330,70 @@ type MarketSummary struct {
 	UniqueTraders    int     `json:"unique_traders"`
 }
 
+// FuelSummary holds fuel bay status for corp-owned structures and starbases.
+type FuelSummary struct {
+	Structures    []StructureFuelEntry `json:"structures"`
+	TotalBlocks   int64                `json:"total_blocks"`
+	LowFuelCount  int                  `json:"low_fuel_count"`
+	CriticalCount int                  `json:"critical_count"`
+	Alerts        []FuelAlert          `json:"alerts"`
+}
+
+// StructureFuelEntry is the fuel status of one structure or control tower.
+type StructureFuelEntry struct {
+	StructureID    int64    `json:"structure_id"`
+	Name           string   `json:"name"`
+	TypeID         int32    `json:"type_id"`
+	TypeName       string   `json:"type_name"`
+	Kind           string   `json:"kind"`        // upwell | starbase
+	FuelBlocks     int64    `json:"fuel_blocks"` // fuel blocks currently in the fuel bay
+	BlocksPerHour  float64  `json:"blocks_per_hour"`
+	DaysRemaining  float64  `json:"days_remaining"` // 0 when consumption is unknown
+	FuelExpires    string   `json:"fuel_expires,omitempty"`
+	Services       []string `json:"services,omitempty"` // fitted service modules
+	Status         string   `json:"status"`             // ok | low | critical | empty | low_power
+	ConsumptionEst bool     `json:"consumption_estimated"`
+}
+
+// FuelAlert is a low-fuel warning raised for a structure.
+type FuelAlert struct {
+	StructureID   int64   `json:"structure_id"`
+	Name          string  `json:"name"`
+	Severity      string  `json:"severity"` // warning | critical
+	DaysRemaining float64 `json:"days_remaining"`
+	Message       string  `json:"message"`
+}
+
 // CharacterRoles holds a character's corporation roles.
 type CharacterRoles struct {
 	Roles         []string `json:"roles"`
 	IsDirector    bool     `json:"is_director"`
 	CorporationID int32    `json:"corporation_id"`
 }
+
+// MemberProfile is the drill-down for one member (GET /api/corp/members/{id}/profile).
+type MemberProfile struct {
+	Member             CorpMember            `json:"member"`
+	Category           string                `json:"category"`  // miner, ratter, trader, industrialist, other
+	IsOnline           bool                  `json:"is_online"` // recently active
+	Contribution30d    float64               `json:"contribution_30d"`
+	Contribution90d    float64               `json:"contribution_90d"`
+	ShareOfCorp30d     float64               `json:"share_of_corp_30d"` // percent of all member contributions
+	Rank30d            int                   `json:"rank_30d"`          // 1 = top contributor, 0 = no contributions
+	IncomeBySource     []IncomeSource        `json:"income_by_source"`  // last 30 days
+	DailyContributions []DailyPnLEntry       `json:"daily_contributions"`
+	Mining             MiningSummary         `json:"mining"`
+	Industry           IndustrySummary       `json:"industry"`
+	Market             MarketSummary         `json:"market"`
+	IndustryJobs       []CorpIndustryJob     `json:"industry_jobs"` // most recent first
+	Orders             []CorpMarketOrder     `json:"orders"`
+	Timeline           []MemberActivityEvent `json:"timeline"` // newest first
+}
+
+// MemberActivityEvent is one entry in a member's activity timeline.
+type MemberActivityEvent struct {
+	Date  string  `json:"date"`
+	Kind  string  `json:"kind"` // journal, mining, industry, order, login
+	Title string  `json:"title"`
+	ISK   float64 `json:"isk,omitempty"`
+}
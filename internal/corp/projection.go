@@ -0,0 +1,109 @@
+package corp
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	projectionDays         = 30
+	projectionTrailingDays = 28
+	projectionMinHistory   = 14
+	// z-score for an 80% two-sided band.
+	projectionBandZ = 1.2816
+)
+
+// computeIncomeProjection projects daily net income for the next `days` days
+// from DailyPnL: a trailing average baseline plus an additive weekday offset.
+// The band width comes from the spread of historical residuals; cumulative
+// bands widen with sqrt(n) assuming independent daily errors.
+func computeIncomeProjection(daily []DailyPnLEntry, days int, now time.Time) IncomeProjection {
+	p := IncomeProjection{
+		Days:           days,
+		Method:         "trailing_avg_weekday",
+		Confidence:     80,
+		WeekdayOffsets: make([]float64, 7),
+		Daily:          []ProjectionPoint{},
+	}
+
+	// Today is still in progress — fit on completed days only.
+	today := now.Format("2006-01-02")
+	history := make([]DailyPnLEntry, 0, len(daily))
+	for _, d := range daily {
+		if d.Date < today {
+			history = append(history, d)
+		}
+	}
+	p.HistoryDays = len(history)
+	if len(history) < projectionMinHistory || days <= 0 {
+		return p
+	}
+
+	// Overall mean and per-weekday means over the whole window.
+	var total float64
+	var wdSum [7]float64
+	var wdCount [7]int
+	weekdays := make([]time.Weekday, len(history))
+	for i, d := range history {
+		t, err := time.Parse("2006-01-02", d.Date)
+		if err != nil {
+			continue
+		}
+		wd := t.Weekday()
+		weekdays[i] = wd
+		total += d.NetIncome
+		wdSum[wd] += d.NetIncome
+		wdCount[wd]++
+	}
+	mean := total / float64(len(history))
+	for wd := 0; wd < 7; wd++ {
+		if wdCount[wd] > 0 {
+			p.WeekdayOffsets[wd] = wdSum[wd]/float64(wdCount[wd]) - mean
+		}
+	}
+
+	// Baseline: trailing average of the most recent completed days.
+	start := len(history) - projectionTrailingDays
+	if start < 0 {
+		start = 0
+	}
+	var recent float64
+	for _, d := range history[start:] {
+		recent += d.NetIncome
+	}
+	p.BaselineDaily = recent / float64(len(history)-start)
+
+	// Residual spread against the in-sample seasonal model.
+	var ss float64
+	for i, d := range history {
+		resid := d.NetIncome - (mean + p.WeekdayOffsets[weekdays[i]])
+		ss += resid * resid
+	}
+	sigma := 0.0
+	if len(history) > 1 {
+		sigma = math.Sqrt(ss / float64(len(history)-1))
+	}
+	p.DailyStdDev = sigma
+
+	var cumExpected float64
+	for i := 1; i <= days; i++ {
+		date := now.AddDate(0, 0, i)
+		expected := p.BaselineDaily + p.WeekdayOffsets[date.Weekday()]
+		cumExpected += expected
+		cumWidth := projectionBandZ * sigma * math.Sqrt(float64(i))
+		p.Daily = append(p.Daily, ProjectionPoint{
+			Date:          date.Format("2006-01-02"),
+			Expected:      expected,
+			Low:           expected - projectionBandZ*sigma,
+			High:          expected + projectionBandZ*sigma,
+			CumulativeExp: cumExpected,
+			CumulativeLow: cumExpected - cumWidth,
+			CumulativeHi:  cumExpected + cumWidth,
+		})
+	}
+	last := p.Daily[len(p.Daily)-1]
+	p.TotalExpected = last.CumulativeExp
+	p.TotalLow = last.CumulativeLow
+	p.TotalHigh = last.CumulativeHi
+	return p
+}
@@ -0,0 +1,51 @@
+package corp
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestComputeIncomeProjection_WeekdaySeasonality(t *testing.T) {
+	now := time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC) // Sunday
+	var daily []DailyPnLEntry
+	for d := 56; d >= 0; d-- {
+		date := now.AddDate(0, 0, -d)
+		net := 100.0
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			net = 200
+		}
+		daily = append(daily, DailyPnLEntry{Date: date.Format("2006-01-02"), NetIncome: net})
+	}
+
+	p := computeIncomeProjection(daily, 30, now)
+	if p.HistoryDays != 56 {
+		t.Fatalf("HistoryDays = %d, want 56 (today excluded)", p.HistoryDays)
+	}
+	if len(p.Daily) != 30 {
+		t.Fatalf("Daily = %d points, want 30", len(p.Daily))
+	}
+	// Perfectly periodic history: weekend days project to 200, weekdays to 100.
+	for _, pt := range p.Daily {
+		d, _ := time.Parse("2006-01-02", pt.Date)
+		want := 100.0
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			want = 200
+		}
+		if math.Abs(pt.Expected-want) > 1e-6 {
+			t.Fatalf("%s expected = %v, want %v", pt.Date, pt.Expected, want)
+		}
+	}
+	if p.DailyStdDev > 1e-6 || p.TotalLow != p.TotalExpected {
+		t.Errorf("noise-free history should give zero band, got sigma=%v", p.DailyStdDev)
+	}
+}
+
+func TestComputeIncomeProjection_NeedsHistory(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	daily := []DailyPnLEntry{{Date: "2026-02-28", NetIncome: 10}}
+	p := computeIncomeProjection(daily, 30, now)
+	if len(p.Daily) != 0 || p.TotalExpected != 0 {
+		t.Fatalf("expected empty projection with 1 day of history, got %+v", p)
+	}
+}
@@ -30,6 +30,16 @@ type CorpDataProvider interface {
 	// GetOrders returns active corporation market orders.
 	GetOrders() ([]CorpMarketOrder, error)
 
+	// GetAssets returns corporation assets (used for structure fuel bays).
+	GetAssets() ([]CorpAsset, error)
+
+	// GetContracts returns contracts issued by or to the corporation.
+	GetContracts() ([]CorpContract, error)
+
+	// GetStructures returns corp-owned Upwell structures with fuel and
+	// service state.
+	GetStructures() ([]CorpStructure, error)
+
 	// IsDemo returns true if this provider serves synthetic demo data.
 	IsDemo() bool
 }
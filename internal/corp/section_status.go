@@ -0,0 +1,125 @@
+package corp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Data sources fetched by the dashboard, used to report partial failures.
+const (
+	SourceJournal  = "journal"
+	SourceMembers  = "members"
+	SourceIndustry = "industry"
+	SourceMining   = "mining"
+	SourceOrders   = "orders"
+	SourceAssets   = "assets"
+)
+
+// sourceInfo describes a data source for error messages and which dashboard
+// sections go empty without it.
+var sourceInfo = map[string]struct {
+	label    string
+	sections []string
+}{
+	SourceJournal:  {"Wallet journal", []string{SectionFinances, SectionProjection, SectionContributors, SectionMembers}},
+	SourceMembers:  {"Member list", []string{SectionContributors, SectionMembers}},
+	SourceIndustry: {"Industry jobs", []string{SectionIndustry}},
+	SourceMining:   {"Mining ledger", []string{SectionMining}},
+	SourceOrders:   {"Market orders", []string{SectionMarket}},
+	SourceAssets:   {"Corporation assets", []string{SectionFuel}},
+}
+
+// SectionError reports a data source that could not be fetched, so the UI
+// can say why a section is empty instead of showing zeros.
+type SectionError struct {
+	Source    string   `json:"source"`
+	Sections  []string `json:"sections"`
+	Status    int      `json:"status,omitempty"` // ESI HTTP status, 0 if unknown
+	Transient bool     `json:"transient"`        // retried; may succeed on refresh
+	Message   string   `json:"message"`          // e.g. "Mining ledger unavailable (403: missing role)"
+	Detail    string   `json:"detail,omitempty"` // raw error
+}
+
+// Retry policy for transient dashboard fetch failures. ESI requests already
+// retry individually; this covers whole-source failures such as a failed
+// first page of a paginated endpoint.
+var (
+	sectionFetchAttempts   = 2
+	sectionFetchRetryDelay = time.Second
+)
+
+var esiStatusRe = regexp.MustCompile(`ESI (?:paginated |POST )?(\d{3})`)
+
+// esiErrorStatus extracts the HTTP status from an esi.Client error message.
+func esiErrorStatus(err error) int {
+	if m := esiStatusRe.FindStringSubmatch(err.Error()); m != nil {
+		status, _ := strconv.Atoi(m[1])
+		return status
+	}
+	return 0
+}
+
+// isTransientFetchError reports whether retrying may help: ESI throttling,
+// server errors and network failures. Auth and role errors are permanent.
+func isTransientFetchError(err error) bool {
+	status := esiErrorStatus(err)
+	if status == 0 {
+		var netErr net.Error
+		return errors.As(err, &netErr)
+	}
+	return status == 420 || status == 429 || status >= 500
+}
+
+// fetchWithRetry calls fetch, retrying transient failures.
+func fetchWithRetry[T any](fetch func() ([]T, error)) ([]T, error) {
+	var lastErr error
+	for attempt := 0; attempt < sectionFetchAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(sectionFetchRetryDelay * time.Duration(attempt))
+		}
+		out, err := fetch()
+		if err == nil {
+			return out, nil
+		}
+		lastErr = err
+		if !isTransientFetchError(err) {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+// newSectionError builds the user-facing status for a failed source.
+func newSectionError(source string, err error) SectionError {
+	info := sourceInfo[source]
+	status := esiErrorStatus(err)
+	reason := "request failed"
+	switch {
+	case status == 401:
+		reason = "token expired or scope missing"
+	case status == 403:
+		reason = "missing role"
+	case status == 404:
+		reason = "not found"
+	case status == 420 || status == 429:
+		reason = "ESI rate limited"
+	case status >= 500:
+		reason = "ESI unavailable"
+	}
+	msg := fmt.Sprintf("%s unavailable (%s)", info.label, reason)
+	if status > 0 {
+		msg = fmt.Sprintf("%s unavailable (%d: %s)", info.label, status, reason)
+	}
+	return SectionError{
+		Source:    source,
+		Sections:  info.sections,
+		Status:    status,
+		Transient: isTransientFetchError(err),
+		Message:   msg,
+		Detail:    err.Error(),
+	}
+}
@@ -0,0 +1,75 @@
+package corp
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyProvider fails selected sources: mining with a permanent role error,
+// orders with one transient 503 before succeeding.
+type flakyProvider struct {
+	*DemoCorpProvider
+	orderCalls  int32
+	miningCalls int32
+}
+
+func (p *flakyProvider) GetMiningLedger() ([]CorpMiningEntry, error) {
+	atomic.AddInt32(&p.miningCalls, 1)
+	return nil, fmt.Errorf("mining observers: %w", errors.New(`ESI 403: {"error":"Character does not have required role(s)"}`))
+}
+
+func (p *flakyProvider) GetOrders() ([]CorpMarketOrder, error) {
+	if atomic.AddInt32(&p.orderCalls, 1) == 1 {
+		return nil, errors.New("corp orders: ESI paginated 503: service unavailable")
+	}
+	return p.DemoCorpProvider.GetOrders()
+}
+
+func (p *flakyProvider) GetJournal(division, days int) ([]CorpJournalEntry, error) {
+	return nil, errors.New("corp journal: ESI paginated 401: token is expired")
+}
+
+func TestBuildDashboard_ReportsSectionErrorsAndRetries(t *testing.T) {
+	defer func(d int) { sectionFetchAttempts = d }(sectionFetchAttempts)
+	defer func(d time.Duration) { sectionFetchRetryDelay = d }(sectionFetchRetryDelay)
+	sectionFetchRetryDelay = 0
+
+	p := &flakyProvider{DemoCorpProvider: NewDemoCorpProvider()}
+	d, err := BuildDashboard(p, nil)
+	if err != nil {
+		t.Fatalf("BuildDashboard: %v", err)
+	}
+	if len(d.SectionErrors) != 2 {
+		t.Fatalf("section errors = %+v, want journal + mining", d.SectionErrors)
+	}
+	journal, mining := d.SectionErrors[0], d.SectionErrors[1]
+	if journal.Source != SourceJournal || journal.Status != 401 || journal.Transient {
+		t.Errorf("journal error = %+v", journal)
+	}
+	if mining.Message != "Mining ledger unavailable (403: missing role)" || mining.Sections[0] != SectionMining {
+		t.Errorf("mining error = %+v", mining)
+	}
+	if p.miningCalls != 1 {
+		t.Errorf("permanent error retried: %d calls", p.miningCalls)
+	}
+	if p.orderCalls != 2 || d.MarketSummary.ActiveSellOrders+d.MarketSummary.ActiveBuyOrders == 0 {
+		t.Errorf("transient orders failure not retried: calls=%d market=%+v", p.orderCalls, d.MarketSummary)
+	}
+}
+
+func TestIsTransientFetchError(t *testing.T) {
+	cases := map[string]bool{
+		"corp wallets: ESI 420: error limited": true,
+		"ESI paginated 502: bad gateway":       true,
+		"ESI 403: forbidden":                   false,
+		"json: cannot unmarshal":               false,
+	}
+	for msg, want := range cases {
+		if got := isTransientFetchError(errors.New(msg)); got != want {
+			t.Errorf("isTransientFetchError(%q) = %v, want %v", msg, got, want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package corp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// DefaultStructureFuelAlertDays is the fuel threshold below which a structure
+// raises a low-fuel alert.
+const DefaultStructureFuelAlertDays = 7
+
+// Structure monitor alert kinds; with the structure ID (and service or timer)
+// they form the alert key used to deliver each condition once.
+const (
+	StructureAlertFuelLow        = "fuel_low"
+	StructureAlertFuelCritical   = "fuel_critical"
+	StructureAlertServiceOffline = "service_offline"
+	StructureAlertReinforced     = "reinforced"
+	StructureAlertLowPower       = "low_power"
+)
+
+// StructureMonitor is the fuel, service and timer status of corp structures.
+type StructureMonitor struct {
+	FuelAlertDays int               `json:"fuel_alert_days"`
+	Structures    []StructureStatus `json:"structures"` // most urgent first
+	Alerts        []StructureAlert  `json:"alerts"`
+	LowFuel       int               `json:"low_fuel"`
+	Reinforced    int               `json:"reinforced"`
+	OfflineCount  int               `json:"offline_services"`
+}
+
+// StructureStatus is one structure with derived countdowns.
+type StructureStatus struct {
+	CorpStructure
+	FuelHoursLeft   float64  `json:"fuel_hours_left"` // -1 when unfueled
+	FuelStatus      string   `json:"fuel_status"`     // ok, low, critical, empty
+	OfflineServices []string `json:"offline_services"`
+	Reinforced      bool     `json:"reinforced"`
+	TimerHoursLeft  float64  `json:"timer_hours_left,omitempty"` // until StateTimerEnd
+}
+
+// StructureAlert is one condition worth notifying about.
+type StructureAlert struct {
+	Key         string `json:"key"`
+	Kind        string `json:"kind"`
+	Severity    string `json:"severity"` // warning | critical
+	StructureID int64  `json:"structure_id"`
+	Name        string `json:"name"`
+	Message     string `json:"message"`
+}
+
+// reinforcedStates are ESI structure states with a running reinforcement timer.
+var reinforcedStates = map[string]bool{
+	"armor_reinforce":  true,
+	"hull_reinforce":   true,
+	"armor_vulnerable": true,
+	"hull_vulnerable":  true,
+}
+
+// BuildStructureMonitor derives fuel countdowns, offline services and
+// reinforcement timers. Fuel below fuelAlertDays is low; below a third of it
+// (at least one day) it is critical.
+func BuildStructureMonitor(structures []CorpStructure, fuelAlertDays int, now time.Time) StructureMonitor {
+	if fuelAlertDays <= 0 {
+		fuelAlertDays = DefaultStructureFuelAlertDays
+	}
+	lowHours := float64(fuelAlertDays) * 24
+	criticalHours := math.Max(24, lowHours/3)
+
+	m := StructureMonitor{
+		FuelAlertDays: fuelAlertDays,
+		Structures:    make([]StructureStatus, 0, len(structures)),
+		Alerts:        []StructureAlert{},
+	}
+	for _, st := range structures {
+		s := StructureStatus{CorpStructure: st, FuelHoursLeft: -1, FuelStatus: "ok", OfflineServices: []string{}}
+		alert := func(kind, severity, key, format string, args ...interface{}) {
+			m.Alerts = append(m.Alerts, StructureAlert{
+				Key:         fmt.Sprintf("%s:%d%s", kind, st.StructureID, key),
+				Kind:        kind,
+				Severity:    severity,
+				StructureID: st.StructureID,
+				Name:        st.Name,
+				Message:     st.Name + ": " + fmt.Sprintf(format, args...),
+			})
+		}
+
+		if t, err := time.Parse(time.RFC3339, st.FuelExpires); err == nil {
+			s.FuelHoursLeft = math.Max(0, math.Round(t.Sub(now).Hours()*10)/10)
+			switch {
+			case s.FuelHoursLeft <= 0:
+				s.FuelStatus = "empty"
+			case s.FuelHoursLeft < criticalHours:
+				s.FuelStatus = "critical"
+			case s.FuelHoursLeft < lowHours:
+				s.FuelStatus = "low"
+			}
+		} else if len(st.Services) > 0 {
+			s.FuelStatus = "empty"
+		}
+		switch s.FuelStatus {
+		case "low":
+			m.LowFuel++
+			alert(StructureAlertFuelLow, "warning", "", "fuel runs out in %s", formatStructureHours(s.FuelHoursLeft))
+		case "critical":
+			m.LowFuel++
+			alert(StructureAlertFuelCritical, "critical", "", "fuel runs out in %s", formatStructureHours(s.FuelHoursLeft))
+		case "empty":
+			m.LowFuel++
+			alert(StructureAlertFuelCritical, "critical", "", "out of fuel")
+		}
+
+		for _, svc := range st.Services {
+			if svc.State != "online" {
+				s.OfflineServices = append(s.OfflineServices, svc.Name)
+				m.OfflineCount++
+				alert(StructureAlertServiceOffline, "warning", ":"+svc.Name, "%s is %s", svc.Name, svc.State)
+			}
+		}
+
+		if reinforcedStates[st.State] {
+			s.Reinforced = true
+			m.Reinforced++
+			if t, err := time.Parse(time.RFC3339, st.StateTimerEnd); err == nil {
+				s.TimerHoursLeft = math.Max(0, math.Round(t.Sub(now).Hours()*10)/10)
+				alert(StructureAlertReinforced, "critical", ":"+st.StateTimerEnd, "%s, timer ends in %s (%s)",
+					structureStateLabel(st.State), formatStructureHours(s.TimerHoursLeft), t.UTC().Format("2006-01-02 15:04 UTC"))
+			} else {
+				alert(StructureAlertReinforced, "critical", "", "%s", structureStateLabel(st.State))
+			}
+		} else if st.State == "low_power" {
+			alert(StructureAlertLowPower, "warning", "", "in low power")
+		}
+
+		m.Structures = append(m.Structures, s)
+	}
+
+	sort.SliceStable(m.Structures, func(i, j int) bool {
+		a, b := m.Structures[i], m.Structures[j]
+		if a.Reinforced != b.Reinforced {
+			return a.Reinforced
+		}
+		ah, bh := a.FuelHoursLeft, b.FuelHoursLeft
+		if ah < 0 {
+			ah = math.MaxFloat64
+		}
+		if bh < 0 {
+			bh = math.MaxFloat64
+		}
+		if ah != bh {
+			return ah < bh
+		}
+		return a.Name < b.Name
+	})
+	sort.SliceStable(m.Alerts, func(i, j int) bool {
+		return m.Alerts[i].Severity == "critical" && m.Alerts[j].Severity != "critical"
+	})
+	return m
+}
+
+func structureStateLabel(state string) string {
+	switch state {
+	case "armor_reinforce":
+		return "armor reinforced"
+	case "hull_reinforce":
+		return "hull reinforced"
+	case "armor_vulnerable":
+		return "armor vulnerable"
+	case "hull_vulnerable":
+		return "hull vulnerable"
+	}
+	return state
+}
+
+func formatStructureHours(hours float64) string {
+	if hours >= 48 {
+		return fmt.Sprintf("%.1f days", hours/24)
+	}
+	return fmt.Sprintf("%.0fh", hours)
+}
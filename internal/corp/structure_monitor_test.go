@@ -0,0 +1,70 @@
+package corp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildStructureMonitor(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	in := func(hours int) string { return now.Add(time.Duration(hours) * time.Hour).Format(time.RFC3339) }
+	structures := []CorpStructure{
+		{StructureID: 1, Name: "Keep", State: "shield_vulnerable", FuelExpires: in(30 * 24),
+			Services: []StructureService{{Name: "Market", State: "online"}}},
+		{StructureID: 2, Name: "Factory", State: "shield_vulnerable", FuelExpires: in(5 * 24),
+			Services: []StructureService{{Name: "Manufacturing", State: "online"}, {Name: "Research", State: "offline"}}},
+		{StructureID: 3, Name: "Drill", State: "armor_reinforce", StateTimerEnd: in(20), FuelExpires: in(30),
+			Services: []StructureService{{Name: "Moon Drill", State: "online"}}},
+		{StructureID: 4, Name: "Staging", State: "low_power", Services: []StructureService{}},
+		{StructureID: 5, Name: "Dead", State: "shield_vulnerable", Services: []StructureService{{Name: "Cloning", State: "offline"}}},
+	}
+
+	m := BuildStructureMonitor(structures, 0, now)
+	if m.FuelAlertDays != DefaultStructureFuelAlertDays || m.LowFuel != 3 || m.Reinforced != 1 || m.OfflineCount != 2 {
+		t.Fatalf("summary = %+v", m)
+	}
+	if m.Structures[0].Name != "Drill" || m.Structures[0].TimerHoursLeft != 20 || m.Structures[0].FuelStatus != "critical" {
+		t.Fatalf("first = %+v", m.Structures[0])
+	}
+	byName := make(map[string]StructureStatus)
+	for _, s := range m.Structures {
+		byName[s.Name] = s
+	}
+	if s := byName["Factory"]; s.FuelStatus != "low" || s.FuelHoursLeft != 120 || len(s.OfflineServices) != 1 {
+		t.Fatalf("factory = %+v", s)
+	}
+	if s := byName["Dead"]; s.FuelStatus != "empty" || s.FuelHoursLeft != -1 {
+		t.Fatalf("dead = %+v", s)
+	}
+	if s := byName["Staging"]; s.FuelStatus != "ok" {
+		t.Fatalf("unfitted staging structure = %+v", s)
+	}
+
+	keys := make(map[string]StructureAlert)
+	for _, a := range m.Alerts {
+		keys[a.Key] = a
+	}
+	for _, want := range []string{"fuel_low:2", "service_offline:2:Research", "reinforced:3:" + in(20), "fuel_critical:3", "low_power:4", "fuel_critical:5", "service_offline:5:Cloning"} {
+		if _, ok := keys[want]; !ok {
+			t.Errorf("missing alert %q in %v", want, m.Alerts)
+		}
+	}
+	if a := keys["reinforced:3:"+in(20)]; !strings.Contains(a.Message, "armor reinforced, timer ends in 20h") {
+		t.Errorf("reinforce message = %q", a.Message)
+	}
+	if m.Alerts[0].Severity != "critical" {
+		t.Errorf("critical alerts should come first: %+v", m.Alerts)
+	}
+
+	// At a 2-day threshold the 5-day structure is fine and 30h is only low.
+	m = BuildStructureMonitor(structures, 2, now)
+	if s := m.Structures[0]; s.Name != "Drill" || s.FuelStatus != "low" {
+		t.Fatalf("30h at a 2-day threshold = %+v", s)
+	}
+	for _, s := range m.Structures {
+		if s.Name == "Factory" && s.FuelStatus != "ok" {
+			t.Fatalf("factory at a 2-day threshold = %+v", s)
+		}
+	}
+}
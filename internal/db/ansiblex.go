@@ -0,0 +1,92 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// Ansiblex gate sources.
+const (
+	AnsiblexSourceManual = "manual"
+	AnsiblexSourceESI    = "esi"
+)
+
+// AnsiblexGate is a jump bridge a user can route through. Each row is one
+// direction; routing treats it as a two-way link.
+type AnsiblexGate struct {
+	FromSystemID int32  `json:"from_system_id"`
+	ToSystemID   int32  `json:"to_system_id"`
+	StructureID  int64  `json:"structure_id,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Source       string `json:"source"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+// ListAnsiblexGatesForUser returns the user's jump bridges.
+func (d *DB) ListAnsiblexGatesForUser(userID string) ([]AnsiblexGate, error) {
+	rows, err := d.sql.Query(`
+		SELECT from_system_id, to_system_id, structure_id, name, source, updated_at
+		FROM ansiblex_gates
+		WHERE user_id = ?
+		ORDER BY name ASC, from_system_id ASC, to_system_id ASC`,
+		normalizeUserID(userID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []AnsiblexGate{}
+	for rows.Next() {
+		var g AnsiblexGate
+		if err := rows.Scan(&g.FromSystemID, &g.ToSystemID, &g.StructureID, &g.Name, &g.Source, &g.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
+
+// ReplaceAnsiblexGatesForUser replaces the user's gates from source, leaving
+// gates from other sources (e.g. manual entries on an ESI import) in place.
+// A gate already stored from another source is overwritten.
+func (d *DB) ReplaceAnsiblexGatesForUser(userID, source string, gates []AnsiblexGate) error {
+	if source != AnsiblexSourceManual && source != AnsiblexSourceESI {
+		return fmt.Errorf("unknown ansiblex source %q", source)
+	}
+	userID = normalizeUserID(userID)
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	tx, err := d.sql.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM ansiblex_gates WHERE user_id = ? AND source = ?", userID, source); err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`
+		INSERT OR REPLACE INTO ansiblex_gates
+			(user_id, from_system_id, to_system_id, structure_id, name, source, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, g := range gates {
+		if g.FromSystemID == 0 || g.ToSystemID == 0 || g.FromSystemID == g.ToSystemID {
+			continue
+		}
+		if _, err := stmt.Exec(userID, g.FromSystemID, g.ToSystemID, g.StructureID, g.Name, source, now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ClearAnsiblexGatesForUser deletes all of the user's gates.
+func (d *DB) ClearAnsiblexGatesForUser(userID string) error {
+	_, err := d.sql.Exec("DELETE FROM ansiblex_gates WHERE user_id = ?", normalizeUserID(userID))
+	return err
+}
@@ -0,0 +1,44 @@
+package db
+
+import "testing"
+
+func TestAnsiblexGates_ReplaceKeepsOtherSources(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	manual := []AnsiblexGate{{FromSystemID: 1, ToSystemID: 2, Name: "A » B"}}
+	if err := d.ReplaceAnsiblexGatesForUser("u1", AnsiblexSourceManual, manual); err != nil {
+		t.Fatal(err)
+	}
+	esiGates := []AnsiblexGate{
+		{FromSystemID: 3, ToSystemID: 4, StructureID: 1_000_000_000_001, Name: "C » D - Bridge"},
+		{FromSystemID: 5, ToSystemID: 5}, // ignored
+	}
+	if err := d.ReplaceAnsiblexGatesForUser("u1", AnsiblexSourceESI, esiGates); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ReplaceAnsiblexGatesForUser("u1", AnsiblexSourceESI, esiGates[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	gates, err := d.ListAnsiblexGatesForUser("u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gates) != 2 {
+		t.Fatalf("got %d gates, want 2: %+v", len(gates), gates)
+	}
+	if other, _ := d.ListAnsiblexGatesForUser("u2"); len(other) != 0 {
+		t.Fatalf("gates leaked to another user: %+v", other)
+	}
+	if err := d.ReplaceAnsiblexGatesForUser("u1", "bogus", nil); err == nil {
+		t.Fatal("expected error for unknown source")
+	}
+
+	if err := d.ClearAnsiblexGatesForUser("u1"); err != nil {
+		t.Fatal(err)
+	}
+	if gates, _ := d.ListAnsiblexGatesForUser("u1"); len(gates) != 0 {
+		t.Fatalf("gates after clear = %+v", gates)
+	}
+}
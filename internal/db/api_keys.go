@@ -0,0 +1,157 @@
+package db
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// API key access levels, each including the previous one.
+const (
+	APIKeyAccessRead  = "read"  // GET requests only
+	APIKeyAccessScan  = "scan"  // also run scans
+	APIKeyAccessWrite = "write" // any request except key management
+)
+
+// APIKeyPrefix starts every plaintext API key.
+const APIKeyPrefix = "efk_"
+
+// APIKey is a stored API key. Only a hash of the key is kept; the plaintext
+// is returned once, when the key is created.
+type APIKey struct {
+	ID            int64  `json:"id"`
+	UserID        string `json:"-"`
+	Name          string `json:"name"`
+	Prefix        string `json:"prefix"`
+	Access        string `json:"access"`
+	RatePerMinute int    `json:"rate_per_minute"`
+	CreatedAt     string `json:"created_at"`
+	LastUsedAt    string `json:"last_used_at,omitempty"`
+	Revoked       bool   `json:"revoked"`
+}
+
+// ValidAPIKeyAccess reports whether access is a known access level.
+func ValidAPIKeyAccess(access string) bool {
+	switch access {
+	case APIKeyAccessRead, APIKeyAccessScan, APIKeyAccessWrite:
+		return true
+	}
+	return false
+}
+
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(plaintext)))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateAPIKey stores a new key for the user and returns it together with
+// its plaintext.
+func (d *DB) CreateAPIKey(userID, name, access string, ratePerMinute int) (APIKey, string, error) {
+	if !ValidAPIKeyAccess(access) {
+		return APIKey{}, "", fmt.Errorf("unknown api key access %q", access)
+	}
+	if ratePerMinute <= 0 {
+		return APIKey{}, "", fmt.Errorf("rate per minute must be positive")
+	}
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return APIKey{}, "", err
+	}
+	plaintext := APIKeyPrefix + hex.EncodeToString(raw)
+	key := APIKey{
+		UserID:        normalizeUserID(userID),
+		Name:          strings.TrimSpace(name),
+		Prefix:        plaintext[:len(APIKeyPrefix)+6],
+		Access:        access,
+		RatePerMinute: ratePerMinute,
+		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+	}
+	res, err := d.sql.Exec(`
+		INSERT INTO api_keys (user_id, name, key_hash, prefix, access, rate_per_minute, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		key.UserID, key.Name, hashAPIKey(plaintext), key.Prefix, key.Access, key.RatePerMinute, key.CreatedAt,
+	)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+	if key.ID, err = res.LastInsertId(); err != nil {
+		return APIKey{}, "", err
+	}
+	return key, plaintext, nil
+}
+
+const apiKeyColumns = `id, user_id, name, prefix, access, rate_per_minute, created_at, last_used_at, revoked`
+
+func scanAPIKey(row interface{ Scan(...interface{}) error }) (APIKey, error) {
+	var k APIKey
+	var revoked int
+	err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.Access, &k.RatePerMinute, &k.CreatedAt, &k.LastUsedAt, &revoked)
+	k.Revoked = revoked != 0
+	return k, err
+}
+
+// ListAPIKeys returns the user's keys, newest first.
+func (d *DB) ListAPIKeys(userID string) ([]APIKey, error) {
+	rows, err := d.sql.Query(`
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE user_id = ?
+		ORDER BY id DESC`,
+		normalizeUserID(userID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []APIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, k)
+	}
+	return out, rows.Err()
+}
+
+// RevokeAPIKey revokes one of the user's keys. It reports whether the key
+// existed.
+func (d *DB) RevokeAPIKey(userID string, id int64) (bool, error) {
+	res, err := d.sql.Exec("UPDATE api_keys SET revoked = 1 WHERE user_id = ? AND id = ?", normalizeUserID(userID), id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// LookupAPIKey returns the active key matching plaintext.
+func (d *DB) LookupAPIKey(plaintext string) (APIKey, bool, error) {
+	if !strings.HasPrefix(strings.TrimSpace(plaintext), APIKeyPrefix) {
+		return APIKey{}, false, nil
+	}
+	k, err := scanAPIKey(d.sql.QueryRow(`
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE key_hash = ? AND revoked = 0`,
+		hashAPIKey(plaintext),
+	))
+	if err == sql.ErrNoRows {
+		return APIKey{}, false, nil
+	}
+	if err != nil {
+		return APIKey{}, false, err
+	}
+	return k, true, nil
+}
+
+// TouchAPIKey records that a key was just used.
+func (d *DB) TouchAPIKey(id int64) error {
+	_, err := d.sql.Exec("UPDATE api_keys SET last_used_at = ? WHERE id = ?", time.Now().UTC().Format(time.RFC3339), id)
+	return err
+}
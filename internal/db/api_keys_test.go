@@ -0,0 +1,60 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAPIKeys_CreateLookupRevoke(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	key, plaintext, err := d.CreateAPIKey("u1", " Sheets ", APIKeyAccessRead, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(plaintext, APIKeyPrefix) || !strings.HasPrefix(plaintext, key.Prefix) {
+		t.Fatalf("plaintext %q does not match prefix %q", plaintext, key.Prefix)
+	}
+	if key.Name != "Sheets" {
+		t.Fatalf("name = %q, want trimmed", key.Name)
+	}
+
+	got, ok, err := d.LookupAPIKey(plaintext)
+	if err != nil || !ok {
+		t.Fatalf("lookup: ok=%v err=%v", ok, err)
+	}
+	if got.ID != key.ID || got.UserID != "u1" || got.Access != APIKeyAccessRead || got.RatePerMinute != 30 {
+		t.Fatalf("lookup returned %+v", got)
+	}
+	if _, ok, _ := d.LookupAPIKey(plaintext + "x"); ok {
+		t.Fatal("wrong key matched")
+	}
+	if err := d.TouchAPIKey(key.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if other, _ := d.ListAPIKeys("u2"); len(other) != 0 {
+		t.Fatalf("keys leaked to another user: %+v", other)
+	}
+	if ok, _ := d.RevokeAPIKey("u2", key.ID); ok {
+		t.Fatal("another user revoked the key")
+	}
+	if ok, err := d.RevokeAPIKey("u1", key.ID); err != nil || !ok {
+		t.Fatalf("revoke: ok=%v err=%v", ok, err)
+	}
+	if _, ok, _ := d.LookupAPIKey(plaintext); ok {
+		t.Fatal("revoked key still matched")
+	}
+	keys, err := d.ListAPIKeys("u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || !keys[0].Revoked || keys[0].LastUsedAt == "" {
+		t.Fatalf("list = %+v", keys)
+	}
+
+	if _, _, err := d.CreateAPIKey("u1", "", "admin", 30); err == nil {
+		t.Fatal("expected error for unknown access")
+	}
+}
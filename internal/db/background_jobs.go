@@ -0,0 +1,71 @@
+package db
+
+import "time"
+
+// BackgroundJob is the persisted schedule of a recurring background job, so
+// it can resume after a restart instead of starting its interval over.
+type BackgroundJob struct {
+	Name      string    `json:"name"`
+	LastRunAt time.Time `json:"last_run_at"`
+	NextRunAt time.Time `json:"next_run_at"`
+	State     string    `json:"-"` // job-specific JSON
+}
+
+// GetBackgroundJob returns the saved schedule of a job.
+func (d *DB) GetBackgroundJob(name string) (BackgroundJob, bool) {
+	var job BackgroundJob
+	var lastRun, nextRun string
+	err := d.sql.QueryRow(
+		`SELECT name, last_run_at, next_run_at, state FROM background_jobs WHERE name = ?`, name,
+	).Scan(&job.Name, &lastRun, &nextRun, &job.State)
+	if err != nil {
+		return BackgroundJob{}, false
+	}
+	job.LastRunAt, _ = time.Parse(time.RFC3339, lastRun)
+	job.NextRunAt, _ = time.Parse(time.RFC3339, nextRun)
+	return job, true
+}
+
+// SaveBackgroundJob upserts a job's schedule and state.
+func (d *DB) SaveBackgroundJob(job BackgroundJob) error {
+	_, err := d.sql.Exec(`
+		INSERT INTO background_jobs (name, last_run_at, next_run_at, state, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			last_run_at = excluded.last_run_at,
+			next_run_at = excluded.next_run_at,
+			state = excluded.state,
+			updated_at = excluded.updated_at`,
+		job.Name, formatJobTime(job.LastRunAt), formatJobTime(job.NextRunAt), job.State,
+		time.Now().UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// ListBackgroundJobs returns all saved job schedules by name.
+func (d *DB) ListBackgroundJobs() []BackgroundJob {
+	rows, err := d.sql.Query(`SELECT name, last_run_at, next_run_at FROM background_jobs ORDER BY name`)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	out := []BackgroundJob{}
+	for rows.Next() {
+		var job BackgroundJob
+		var lastRun, nextRun string
+		if err := rows.Scan(&job.Name, &lastRun, &nextRun); err != nil {
+			continue
+		}
+		job.LastRunAt, _ = time.Parse(time.RFC3339, lastRun)
+		job.NextRunAt, _ = time.Parse(time.RFC3339, nextRun)
+		out = append(out, job)
+	}
+	return out
+}
+
+func formatJobTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
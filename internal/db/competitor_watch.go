@@ -0,0 +1,69 @@
+package db
+
+import "time"
+
+// CompetitorWatch is a type and NPC station whose book is snapshotted.
+type CompetitorWatch struct {
+	TypeID     int32  `json:"type_id"`
+	LocationID int64  `json:"location_id"`
+	RegionID   int32  `json:"region_id"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// AddCompetitorWatchForUser starts snapshotting a book for the user.
+func (d *DB) AddCompetitorWatchForUser(userID string, w CompetitorWatch) error {
+	userID = normalizeUserID(userID)
+	_, err := d.sql.Exec(`
+		INSERT OR IGNORE INTO competitor_watch (user_id, type_id, location_id, region_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, userID, w.TypeID, w.LocationID, w.RegionID, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+// DeleteCompetitorWatchForUser stops snapshotting a book for the user. It
+// reports false if the user was not watching it.
+func (d *DB) DeleteCompetitorWatchForUser(userID string, typeID int32, locationID int64) (bool, error) {
+	userID = normalizeUserID(userID)
+	res, err := d.sql.Exec(`DELETE FROM competitor_watch WHERE user_id = ? AND type_id = ? AND location_id = ?`,
+		userID, typeID, locationID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ListCompetitorWatchesForUser returns the user's watched books, newest
+// first.
+func (d *DB) ListCompetitorWatchesForUser(userID string) ([]CompetitorWatch, error) {
+	userID = normalizeUserID(userID)
+	return d.queryCompetitorWatches(`
+		SELECT type_id, location_id, region_id, created_at FROM competitor_watch
+		WHERE user_id = ? ORDER BY created_at DESC, type_id
+	`, userID)
+}
+
+// ListCompetitorWatchBooks returns every watched book once, across users.
+func (d *DB) ListCompetitorWatchBooks() ([]CompetitorWatch, error) {
+	return d.queryCompetitorWatches(`
+		SELECT type_id, location_id, MIN(region_id), MIN(created_at) FROM competitor_watch
+		GROUP BY type_id, location_id ORDER BY type_id, location_id
+	`)
+}
+
+func (d *DB) queryCompetitorWatches(query string, args ...interface{}) ([]CompetitorWatch, error) {
+	rows, err := d.sql.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []CompetitorWatch{}
+	for rows.Next() {
+		var w CompetitorWatch
+		if err := rows.Scan(&w.TypeID, &w.LocationID, &w.RegionID, &w.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,32 @@
+package db
+
+import "testing"
+
+func TestCompetitorWatch_SharesBooksAcrossUsers(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	const jita = 60003760
+	if err := d.AddCompetitorWatchForUser("alice", CompetitorWatch{TypeID: 34, LocationID: jita, RegionID: 10000002}); err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	if err := d.AddCompetitorWatchForUser("bob", CompetitorWatch{TypeID: 34, LocationID: jita, RegionID: 10000002}); err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	if books, err := d.ListCompetitorWatchBooks(); err != nil || len(books) != 1 {
+		t.Fatalf("books = %+v, %v", books, err)
+	}
+
+	if ok, err := d.DeleteCompetitorWatchForUser("alice", 34, jita); err != nil || !ok {
+		t.Fatalf("unwatch = %v, %v", ok, err)
+	}
+	if ok, err := d.DeleteCompetitorWatchForUser("alice", 34, jita); err != nil || ok {
+		t.Fatalf("second unwatch = %v, %v", ok, err)
+	}
+	if watches, err := d.ListCompetitorWatchesForUser("bob"); err != nil || len(watches) != 1 || watches[0].RegionID != 10000002 {
+		t.Fatalf("bob watches = %+v, %v", watches, err)
+	}
+	if books, err := d.ListCompetitorWatchBooks(); err != nil || len(books) != 1 {
+		t.Fatalf("books after unwatch = %+v, %v", books, err)
+	}
+}
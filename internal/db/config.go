@@ -129,6 +129,9 @@ func (d *DB) LoadConfigForUser(userID string) *config.Config {
 	cfg.MinBfSPerDay = parseFloat("min_bfs_per_day", cfg.MinBfSPerDay)
 	cfg.MinS2BBfSRatio = parseFloat("min_s2b_bfs_ratio", cfg.MinS2BBfSRatio)
 	cfg.MaxS2BBfSRatio = parseFloat("max_s2b_bfs_ratio", cfg.MaxS2BBfSRatio)
+	cfg.MaxFillDays = parseFloat("max_fill_days", cfg.MaxFillDays)
+	cfg.ScanTimeoutSec = parseInt("scan_timeout_sec", cfg.ScanTimeoutSec)
+	cfg.MaxRegionFailures = parseInt("max_region_failures", cfg.MaxRegionFailures)
 	cfg.MinRouteSecurity = parseFloat("min_route_security", cfg.MinRouteSecurity)
 	cfg.AvgPricePeriod = parseInt("avg_price_period", cfg.AvgPricePeriod)
 	cfg.MinPeriodROI = parseFloat("min_period_roi", cfg.MinPeriodROI)
@@ -136,6 +139,8 @@ func (d *DB) LoadConfigForUser(userID string) *config.Config {
 	cfg.MinDemandPerDay = parseFloat("min_demand_per_day", cfg.MinDemandPerDay)
 	cfg.PurchaseDemandDays = parseFloat("purchase_demand_days", cfg.PurchaseDemandDays)
 	cfg.ShippingCostPerM3Jump = parseFloat("shipping_cost_per_m3_jump", cfg.ShippingCostPerM3Jump)
+	cfg.FreightISKPerM3Jump = parseFloat("freight_isk_per_m3_jump", cfg.FreightISKPerM3Jump)
+	cfg.FreightCollateralPercent = parseFloat("freight_collateral_percent", cfg.FreightCollateralPercent)
 	if v, ok := m["source_regions"]; ok {
 		var regions []string
 		if err := json.Unmarshal([]byte(v), &regions); err == nil {
@@ -159,6 +164,29 @@ func (d *DB) LoadConfigForUser(userID string) *config.Config {
 	cfg.AlertTelegram = parseBool("alert_telegram", cfg.AlertTelegram)
 	cfg.AlertDiscord = parseBool("alert_discord", cfg.AlertDiscord)
 	cfg.AlertDesktop = parseBool("alert_desktop", cfg.AlertDesktop)
+	cfg.OrderDeskAlerts = parseBool("order_desk_alerts", cfg.OrderDeskAlerts)
+	cfg.OrderDeskAlertMinutes = parseInt("order_desk_alert_minutes", cfg.OrderDeskAlertMinutes)
+	cfg.StructureAlerts = parseBool("structure_alerts", cfg.StructureAlerts)
+	cfg.StructureFuelAlertDays = parseInt("structure_fuel_alert_days", cfg.StructureFuelAlertDays)
+	cfg.ReferenceStationID = parseInt64("reference_station_id", cfg.ReferenceStationID)
+	if v, ok := m["avoid_system_ids"]; ok {
+		var ids []int32
+		if err := json.Unmarshal([]byte(v), &ids); err == nil {
+			cfg.AvoidSystemIDs = ids
+		}
+	}
+	if v, ok := m["avoid_region_ids"]; ok {
+		var ids []int32
+		if err := json.Unmarshal([]byte(v), &ids); err == nil {
+			cfg.AvoidRegionIDs = ids
+		}
+	}
+	if v, ok := m["pinned_routes"]; ok {
+		var pins []config.PinnedRoute
+		if err := json.Unmarshal([]byte(v), &pins); err == nil {
+			cfg.PinnedRoutes = pins
+		}
+	}
 	if v, ok := m["alert_telegram_token"]; ok {
 		cfg.AlertTelegramToken = v
 	}
@@ -198,52 +226,77 @@ func (d *DB) SaveConfigForUser(userID string, cfg *config.Config) error {
 	if b, err := json.Marshal(cfg.CategoryIDs); err == nil {
 		categoryIDsJSON = string(b)
 	}
+	avoidSystemsJSON := "[]"
+	if b, err := json.Marshal(cfg.AvoidSystemIDs); err == nil && cfg.AvoidSystemIDs != nil {
+		avoidSystemsJSON = string(b)
+	}
+	avoidRegionsJSON := "[]"
+	if b, err := json.Marshal(cfg.AvoidRegionIDs); err == nil && cfg.AvoidRegionIDs != nil {
+		avoidRegionsJSON = string(b)
+	}
+	pinnedRoutesJSON := "[]"
+	if b, err := json.Marshal(cfg.PinnedRoutes); err == nil && cfg.PinnedRoutes != nil {
+		pinnedRoutesJSON = string(b)
+	}
 
 	pairs := map[string]string{
-		"system_name":               cfg.SystemName,
-		"ignored_system_ids":        ignoredSystemsJSON,
-		"cargo_capacity":            fmt.Sprintf("%g", cfg.CargoCapacity),
-		"buy_radius":                strconv.Itoa(cfg.BuyRadius),
-		"sell_radius":               strconv.Itoa(cfg.SellRadius),
-		"min_margin":                fmt.Sprintf("%g", cfg.MinMargin),
-		"sales_tax_percent":         fmt.Sprintf("%g", cfg.SalesTaxPercent),
-		"broker_fee_percent":        fmt.Sprintf("%g", cfg.BrokerFeePercent),
-		"split_trade_fees":          strconv.FormatBool(cfg.SplitTradeFees),
-		"buy_broker_fee_percent":    fmt.Sprintf("%g", cfg.BuyBrokerFeePercent),
-		"sell_broker_fee_percent":   fmt.Sprintf("%g", cfg.SellBrokerFeePercent),
-		"buy_sales_tax_percent":     fmt.Sprintf("%g", cfg.BuySalesTaxPercent),
-		"sell_sales_tax_percent":    fmt.Sprintf("%g", cfg.SellSalesTaxPercent),
-		"min_daily_volume":          strconv.FormatInt(cfg.MinDailyVolume, 10),
-		"max_investment":            fmt.Sprintf("%g", cfg.MaxInvestment),
-		"min_item_profit":           fmt.Sprintf("%g", cfg.MinItemProfit),
-		"min_s2b_per_day":           fmt.Sprintf("%g", cfg.MinS2BPerDay),
-		"min_bfs_per_day":           fmt.Sprintf("%g", cfg.MinBfSPerDay),
-		"min_s2b_bfs_ratio":         fmt.Sprintf("%g", cfg.MinS2BBfSRatio),
-		"max_s2b_bfs_ratio":         fmt.Sprintf("%g", cfg.MaxS2BBfSRatio),
-		"min_route_security":        fmt.Sprintf("%g", cfg.MinRouteSecurity),
-		"avg_price_period":          strconv.Itoa(cfg.AvgPricePeriod),
-		"min_period_roi":            fmt.Sprintf("%g", cfg.MinPeriodROI),
-		"max_dos":                   fmt.Sprintf("%g", cfg.MaxDOS),
-		"min_demand_per_day":        fmt.Sprintf("%g", cfg.MinDemandPerDay),
-		"purchase_demand_days":      fmt.Sprintf("%g", cfg.PurchaseDemandDays),
-		"shipping_cost_per_m3_jump": fmt.Sprintf("%g", cfg.ShippingCostPerM3Jump),
-		"source_regions":            sourceRegionsJSON,
-		"target_region":             cfg.TargetRegion,
-		"target_market_system":      cfg.TargetMarketSystem,
-		"target_market_location_id": strconv.FormatInt(cfg.TargetMarketLocationID, 10),
-		"category_ids":              categoryIDsJSON,
-		"sell_order_mode":           strconv.FormatBool(cfg.SellOrderMode),
-		"alert_telegram":            strconv.FormatBool(cfg.AlertTelegram),
-		"alert_discord":             strconv.FormatBool(cfg.AlertDiscord),
-		"alert_desktop":             strconv.FormatBool(cfg.AlertDesktop),
-		"alert_telegram_token":      cfg.AlertTelegramToken,
-		"alert_telegram_chat_id":    cfg.AlertTelegramChatID,
-		"alert_discord_webhook":     cfg.AlertDiscordWebhook,
-		"opacity":                   strconv.Itoa(cfg.Opacity),
-		"window_x":                  strconv.Itoa(cfg.WindowX),
-		"window_y":                  strconv.Itoa(cfg.WindowY),
-		"window_w":                  strconv.Itoa(cfg.WindowW),
-		"window_h":                  strconv.Itoa(cfg.WindowH),
+		"system_name":                cfg.SystemName,
+		"ignored_system_ids":         ignoredSystemsJSON,
+		"cargo_capacity":             fmt.Sprintf("%g", cfg.CargoCapacity),
+		"buy_radius":                 strconv.Itoa(cfg.BuyRadius),
+		"sell_radius":                strconv.Itoa(cfg.SellRadius),
+		"min_margin":                 fmt.Sprintf("%g", cfg.MinMargin),
+		"sales_tax_percent":          fmt.Sprintf("%g", cfg.SalesTaxPercent),
+		"broker_fee_percent":         fmt.Sprintf("%g", cfg.BrokerFeePercent),
+		"split_trade_fees":           strconv.FormatBool(cfg.SplitTradeFees),
+		"buy_broker_fee_percent":     fmt.Sprintf("%g", cfg.BuyBrokerFeePercent),
+		"sell_broker_fee_percent":    fmt.Sprintf("%g", cfg.SellBrokerFeePercent),
+		"buy_sales_tax_percent":      fmt.Sprintf("%g", cfg.BuySalesTaxPercent),
+		"sell_sales_tax_percent":     fmt.Sprintf("%g", cfg.SellSalesTaxPercent),
+		"min_daily_volume":           strconv.FormatInt(cfg.MinDailyVolume, 10),
+		"max_investment":             fmt.Sprintf("%g", cfg.MaxInvestment),
+		"min_item_profit":            fmt.Sprintf("%g", cfg.MinItemProfit),
+		"min_s2b_per_day":            fmt.Sprintf("%g", cfg.MinS2BPerDay),
+		"min_bfs_per_day":            fmt.Sprintf("%g", cfg.MinBfSPerDay),
+		"min_s2b_bfs_ratio":          fmt.Sprintf("%g", cfg.MinS2BBfSRatio),
+		"max_s2b_bfs_ratio":          fmt.Sprintf("%g", cfg.MaxS2BBfSRatio),
+		"max_fill_days":              fmt.Sprintf("%g", cfg.MaxFillDays),
+		"scan_timeout_sec":           strconv.Itoa(cfg.ScanTimeoutSec),
+		"max_region_failures":        strconv.Itoa(cfg.MaxRegionFailures),
+		"min_route_security":         fmt.Sprintf("%g", cfg.MinRouteSecurity),
+		"avg_price_period":           strconv.Itoa(cfg.AvgPricePeriod),
+		"min_period_roi":             fmt.Sprintf("%g", cfg.MinPeriodROI),
+		"max_dos":                    fmt.Sprintf("%g", cfg.MaxDOS),
+		"min_demand_per_day":         fmt.Sprintf("%g", cfg.MinDemandPerDay),
+		"purchase_demand_days":       fmt.Sprintf("%g", cfg.PurchaseDemandDays),
+		"shipping_cost_per_m3_jump":  fmt.Sprintf("%g", cfg.ShippingCostPerM3Jump),
+		"freight_isk_per_m3_jump":    fmt.Sprintf("%g", cfg.FreightISKPerM3Jump),
+		"freight_collateral_percent": fmt.Sprintf("%g", cfg.FreightCollateralPercent),
+		"source_regions":             sourceRegionsJSON,
+		"target_region":              cfg.TargetRegion,
+		"target_market_system":       cfg.TargetMarketSystem,
+		"target_market_location_id":  strconv.FormatInt(cfg.TargetMarketLocationID, 10),
+		"category_ids":               categoryIDsJSON,
+		"sell_order_mode":            strconv.FormatBool(cfg.SellOrderMode),
+		"alert_telegram":             strconv.FormatBool(cfg.AlertTelegram),
+		"alert_discord":              strconv.FormatBool(cfg.AlertDiscord),
+		"alert_desktop":              strconv.FormatBool(cfg.AlertDesktop),
+		"alert_telegram_token":       cfg.AlertTelegramToken,
+		"alert_telegram_chat_id":     cfg.AlertTelegramChatID,
+		"alert_discord_webhook":      cfg.AlertDiscordWebhook,
+		"order_desk_alerts":          strconv.FormatBool(cfg.OrderDeskAlerts),
+		"order_desk_alert_minutes":   strconv.Itoa(cfg.OrderDeskAlertMinutes),
+		"structure_alerts":           strconv.FormatBool(cfg.StructureAlerts),
+		"structure_fuel_alert_days":  strconv.Itoa(cfg.StructureFuelAlertDays),
+		"reference_station_id":       strconv.FormatInt(cfg.ReferenceStationID, 10),
+		"avoid_system_ids":           avoidSystemsJSON,
+		"avoid_region_ids":           avoidRegionsJSON,
+		"pinned_routes":              pinnedRoutesJSON,
+		"opacity":                    strconv.Itoa(cfg.Opacity),
+		"window_x":                   strconv.Itoa(cfg.WindowX),
+		"window_y":                   strconv.Itoa(cfg.WindowY),
+		"window_w":                   strconv.Itoa(cfg.WindowW),
+		"window_h":                   strconv.Itoa(cfg.WindowH),
 	}
 
 	storedPairs := make(map[string]string, len(pairs))
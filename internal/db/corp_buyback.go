@@ -0,0 +1,110 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"eve-flipper/internal/corp"
+)
+
+// maxBuybackBoardsPerUser bounds the stored refresh history per user.
+const maxBuybackBoardsPerUser = 200
+
+// SaveCorpBuybackBoardForUser stores a generated price board as the latest published one.
+func (d *DB) SaveCorpBuybackBoardForUser(userID string, board corp.PriceBoard) error {
+	userID = normalizeUserID(userID)
+	payload, err := json.Marshal(board)
+	if err != nil {
+		return fmt.Errorf("marshal buyback board: %w", err)
+	}
+	createdAt := board.GeneratedAt
+	if createdAt == "" {
+		createdAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	changed := 0
+	if board.RatesChangedAt == board.GeneratedAt {
+		changed = 1
+	}
+
+	tx, err := d.sql.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO corp_buyback_boards (user_id, percent, source, changed, payload_json, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, userID, board.Percent, board.Source, changed, string(payload), createdAt); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		DELETE FROM corp_buyback_boards
+		WHERE user_id = ? AND id NOT IN (
+			SELECT id FROM corp_buyback_boards WHERE user_id = ? ORDER BY id DESC LIMIT ?
+		)
+	`, userID, userID, maxBuybackBoardsPerUser); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// LatestCorpBuybackBoardForUser returns the most recently published board, or nil if none.
+func (d *DB) LatestCorpBuybackBoardForUser(userID string) (*corp.PriceBoard, error) {
+	userID = normalizeUserID(userID)
+	var payload string
+	err := d.sql.QueryRow(`
+		SELECT payload_json FROM corp_buyback_boards
+		WHERE user_id = ?
+		ORDER BY id DESC
+		LIMIT 1
+	`, userID).Scan(&payload)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var board corp.PriceBoard
+	if err := json.Unmarshal([]byte(payload), &board); err != nil {
+		return nil, fmt.Errorf("decode buyback board: %w", err)
+	}
+	return &board, nil
+}
+
+// CorpBuybackRateChange is one refresh that moved at least one buyback rate.
+type CorpBuybackRateChange struct {
+	Percent   float64 `json:"percent"`
+	Source    string  `json:"source"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// ListCorpBuybackRateChangesForUser returns refreshes that changed rates, newest first.
+func (d *DB) ListCorpBuybackRateChangesForUser(userID string, limit int) ([]CorpBuybackRateChange, error) {
+	userID = normalizeUserID(userID)
+	if limit <= 0 || limit > maxBuybackBoardsPerUser {
+		limit = 20
+	}
+	rows, err := d.sql.Query(`
+		SELECT percent, source, created_at FROM corp_buyback_boards
+		WHERE user_id = ? AND changed = 1
+		ORDER BY id DESC
+		LIMIT ?
+	`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []CorpBuybackRateChange{}
+	for rows.Next() {
+		var c CorpBuybackRateChange
+		if err := rows.Scan(&c.Percent, &c.Source, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
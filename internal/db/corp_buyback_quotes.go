@@ -0,0 +1,257 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"eve-flipper/internal/corp"
+)
+
+// Buyback quote lifecycle: quoted → accepted → reconciled against a journal payment.
+const (
+	CorpBuybackQuoted     = "quoted"
+	CorpBuybackAccepted   = "accepted"
+	CorpBuybackReconciled = "reconciled"
+)
+
+// maxBuybackQuotesPerUser bounds the unaccepted quote history per user;
+// accepted and reconciled quotes are kept as program records.
+const maxBuybackQuotesPerUser = 500
+
+// CorpBuybackQuoteRecord is a stored buyback quote and its payment state.
+type CorpBuybackQuoteRecord struct {
+	ID              int64              `json:"id"`
+	CorporationID   int32              `json:"corporation_id,omitempty"`
+	SellerID        int64              `json:"seller_id,omitempty"`
+	SellerName      string             `json:"seller_name,omitempty"`
+	Total           float64            `json:"total"`
+	Source          string             `json:"source"`
+	Status          string             `json:"status"`
+	CreatedAt       string             `json:"created_at"`
+	AcceptedAt      string             `json:"accepted_at,omitempty"`
+	JournalDivision int                `json:"journal_division,omitempty"`
+	JournalEntryID  int64              `json:"journal_entry_id,omitempty"`
+	ReconciledAt    string             `json:"reconciled_at,omitempty"`
+	Quote           *corp.BuybackQuote `json:"quote,omitempty"`
+}
+
+// GetCorpBuybackRulesForUser returns the user's buyback rules, or the
+// defaults if none were saved.
+func (d *DB) GetCorpBuybackRulesForUser(userID string) (corp.BuybackRules, error) {
+	userID = normalizeUserID(userID)
+	var payload string
+	err := d.sql.QueryRow(`SELECT rules_json FROM corp_buyback_rules WHERE user_id = ?`, userID).Scan(&payload)
+	if err == sql.ErrNoRows {
+		return corp.DefaultBuybackRules(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rules corp.BuybackRules
+	if err := json.Unmarshal([]byte(payload), &rules); err != nil {
+		return nil, fmt.Errorf("decode buyback rules: %w", err)
+	}
+	return corp.NormalizeBuybackRules(rules), nil
+}
+
+// SaveCorpBuybackRulesForUser normalizes and stores the user's buyback rules.
+func (d *DB) SaveCorpBuybackRulesForUser(userID string, rules corp.BuybackRules) (corp.BuybackRules, error) {
+	userID = normalizeUserID(userID)
+	rules = corp.NormalizeBuybackRules(rules)
+	payload, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("marshal buyback rules: %w", err)
+	}
+	_, err = d.sql.Exec(`
+		INSERT INTO corp_buyback_rules (user_id, rules_json, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET rules_json = excluded.rules_json, updated_at = excluded.updated_at
+	`, userID, string(payload), time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// SaveCorpBuybackQuoteForUser stores a generated quote as "quoted" and
+// returns its ID.
+func (d *DB) SaveCorpBuybackQuoteForUser(userID string, corporationID int32, sellerName string, quote corp.BuybackQuote) (int64, error) {
+	userID = normalizeUserID(userID)
+	payload, err := json.Marshal(quote)
+	if err != nil {
+		return 0, fmt.Errorf("marshal buyback quote: %w", err)
+	}
+	createdAt := quote.GeneratedAt
+	if createdAt == "" {
+		createdAt = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	tx, err := d.sql.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		INSERT INTO corp_buyback_quotes (user_id, corporation_id, seller_name, total, source, status, quote_json, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, userID, corporationID, sellerName, quote.Total, quote.Source, CorpBuybackQuoted, string(payload), createdAt)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`
+		DELETE FROM corp_buyback_quotes
+		WHERE user_id = ? AND status = ? AND id NOT IN (
+			SELECT id FROM corp_buyback_quotes WHERE user_id = ? AND status = ? ORDER BY id DESC LIMIT ?
+		)
+	`, userID, CorpBuybackQuoted, userID, CorpBuybackQuoted, maxBuybackQuotesPerUser); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+const corpBuybackQuoteColumns = `
+	id, corporation_id, seller_id, seller_name, total, source, status, created_at,
+	accepted_at, journal_division, journal_entry_id, reconciled_at, quote_json`
+
+func scanCorpBuybackQuote(row interface{ Scan(...interface{}) error }) (CorpBuybackQuoteRecord, error) {
+	var rec CorpBuybackQuoteRecord
+	var payload string
+	if err := row.Scan(
+		&rec.ID,
+		&rec.CorporationID,
+		&rec.SellerID,
+		&rec.SellerName,
+		&rec.Total,
+		&rec.Source,
+		&rec.Status,
+		&rec.CreatedAt,
+		&rec.AcceptedAt,
+		&rec.JournalDivision,
+		&rec.JournalEntryID,
+		&rec.ReconciledAt,
+		&payload,
+	); err != nil {
+		return rec, err
+	}
+	var quote corp.BuybackQuote
+	if err := json.Unmarshal([]byte(payload), &quote); err != nil {
+		return rec, fmt.Errorf("decode buyback quote %d: %w", rec.ID, err)
+	}
+	rec.Quote = &quote
+	return rec, nil
+}
+
+// GetCorpBuybackQuoteForUser returns one stored quote, or nil if the user has no such quote.
+func (d *DB) GetCorpBuybackQuoteForUser(userID string, id int64) (*CorpBuybackQuoteRecord, error) {
+	userID = normalizeUserID(userID)
+	rec, err := scanCorpBuybackQuote(d.sql.QueryRow(`
+		SELECT `+corpBuybackQuoteColumns+` FROM corp_buyback_quotes
+		WHERE user_id = ? AND id = ?
+	`, userID, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// ListCorpBuybackQuotesForUser returns stored quotes, newest first,
+// optionally limited to one status.
+func (d *DB) ListCorpBuybackQuotesForUser(userID, status string, limit int) ([]CorpBuybackQuoteRecord, error) {
+	userID = normalizeUserID(userID)
+	if limit <= 0 || limit > maxBuybackQuotesPerUser {
+		limit = 50
+	}
+	query := `SELECT ` + corpBuybackQuoteColumns + ` FROM corp_buyback_quotes WHERE user_id = ?`
+	args := []interface{}{userID}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := d.sql.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	quotes := []CorpBuybackQuoteRecord{}
+	for rows.Next() {
+		rec, err := scanCorpBuybackQuote(rows)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, rec)
+	}
+	return quotes, rows.Err()
+}
+
+// AcceptCorpBuybackQuoteForUser marks a quoted quote as accepted for the
+// corporation and seller. It reports false if the quote is missing or was
+// already accepted.
+func (d *DB) AcceptCorpBuybackQuoteForUser(userID string, id int64, corporationID int32, sellerID int64, sellerName string, at time.Time) (bool, error) {
+	userID = normalizeUserID(userID)
+	res, err := d.sql.Exec(`
+		UPDATE corp_buyback_quotes
+		SET status = ?, corporation_id = ?, seller_id = ?, seller_name = ?, accepted_at = ?
+		WHERE user_id = ? AND id = ? AND status = ?
+	`, CorpBuybackAccepted, corporationID, sellerID, sellerName, at.UTC().Format(time.RFC3339),
+		userID, id, CorpBuybackQuoted)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ReconcileCorpBuybackQuoteForUser links an accepted quote to the journal
+// entry that paid it. It reports false if the quote is not awaiting payment.
+func (d *DB) ReconcileCorpBuybackQuoteForUser(userID string, id int64, division int, entryID int64, at time.Time) (bool, error) {
+	userID = normalizeUserID(userID)
+	res, err := d.sql.Exec(`
+		UPDATE corp_buyback_quotes
+		SET status = ?, journal_division = ?, journal_entry_id = ?, reconciled_at = ?
+		WHERE user_id = ? AND id = ? AND status = ?
+	`, CorpBuybackReconciled, division, entryID, at.UTC().Format(time.RFC3339),
+		userID, id, CorpBuybackAccepted)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// CorpBuybackJournalEntriesForUser returns the journal entry IDs already
+// matched to reconciled quotes of the corporation.
+func (d *DB) CorpBuybackJournalEntriesForUser(userID string, corporationID int32) (map[int64]bool, error) {
+	userID = normalizeUserID(userID)
+	rows, err := d.sql.Query(`
+		SELECT journal_entry_id FROM corp_buyback_quotes
+		WHERE user_id = ? AND corporation_id = ? AND status = ?
+	`, userID, corporationID, CorpBuybackReconciled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	used := make(map[int64]bool)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		used[id] = true
+	}
+	return used, rows.Err()
+}
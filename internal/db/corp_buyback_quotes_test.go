@@ -0,0 +1,65 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"eve-flipper/internal/corp"
+)
+
+func TestCorpBuybackQuoteLifecycle(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	userID := "buyback-user"
+	rules, err := d.GetCorpBuybackRulesForUser(userID)
+	if err != nil || rules[corp.BuybackCategoryOre] != corp.DefaultBuybackPercent {
+		t.Fatalf("default rules = %v, %v", rules, err)
+	}
+	saved, err := d.SaveCorpBuybackRulesForUser(userID, corp.BuybackRules{corp.BuybackCategoryOre: 95, "bogus": 50})
+	if err != nil {
+		t.Fatalf("SaveCorpBuybackRulesForUser: %v", err)
+	}
+	if _, ok := saved["bogus"]; ok || saved[corp.BuybackCategoryPI] != 0 {
+		t.Fatalf("saved rules not normalized: %v", saved)
+	}
+	if rules, _ := d.GetCorpBuybackRulesForUser(userID); rules[corp.BuybackCategoryOre] != 95 {
+		t.Fatalf("reloaded rules = %v", rules)
+	}
+
+	quote := corp.BuybackQuote{GeneratedAt: "2026-05-10T12:00:00Z", Source: "jita_buy", Total: 14400}
+	id, err := d.SaveCorpBuybackQuoteForUser(userID, 0, "Alice", quote)
+	if err != nil {
+		t.Fatalf("SaveCorpBuybackQuoteForUser: %v", err)
+	}
+	if rec, _ := d.GetCorpBuybackQuoteForUser("someone-else", id); rec != nil {
+		t.Fatal("quote leaked to another user")
+	}
+
+	if ok, err := d.ReconcileCorpBuybackQuoteForUser(userID, id, 1, 77, time.Now()); err != nil || ok {
+		t.Fatalf("reconciled an unaccepted quote: %v %v", ok, err)
+	}
+	if ok, err := d.AcceptCorpBuybackQuoteForUser(userID, id, 98000042, 7, "Alice", time.Now()); err != nil || !ok {
+		t.Fatalf("accept = %v %v", ok, err)
+	}
+	if ok, _ := d.AcceptCorpBuybackQuoteForUser(userID, id, 98000042, 7, "Alice", time.Now()); ok {
+		t.Fatal("accepted a quote twice")
+	}
+	if ok, err := d.ReconcileCorpBuybackQuoteForUser(userID, id, 1, 77, time.Now()); err != nil || !ok {
+		t.Fatalf("reconcile = %v %v", ok, err)
+	}
+
+	rec, err := d.GetCorpBuybackQuoteForUser(userID, id)
+	if err != nil || rec == nil {
+		t.Fatalf("GetCorpBuybackQuoteForUser: %v %v", rec, err)
+	}
+	if rec.Status != CorpBuybackReconciled || rec.JournalEntryID != 77 || rec.SellerID != 7 || rec.Quote.Total != 14400 {
+		t.Fatalf("record = %+v", rec)
+	}
+	if used, _ := d.CorpBuybackJournalEntriesForUser(userID, 98000042); !used[77] {
+		t.Fatalf("journal entry 77 not marked used: %v", used)
+	}
+	if list, _ := d.ListCorpBuybackQuotesForUser(userID, CorpBuybackAccepted, 0); len(list) != 0 {
+		t.Fatalf("accepted list = %+v, want empty", list)
+	}
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"eve-flipper/internal/corp"
+)
+
+// corpDashboardLayoutKey is the config key holding the JSON-encoded
+// corp.DashboardLayout. It is kept out of config.Config so scan settings
+// saves never touch it.
+const corpDashboardLayoutKey = "corp_dashboard_layout"
+
+// LoadCorpDashboardLayoutForUser returns the saved corp dashboard layout.
+// ok is false (with the default layout) when nothing has been saved yet.
+func (d *DB) LoadCorpDashboardLayoutForUser(userID string) (layout corp.DashboardLayout, ok bool, err error) {
+	userID = normalizeUserID(userID)
+	var raw string
+	err = d.sql.QueryRow("SELECT value FROM config WHERE user_id = ? AND key = ?", userID, corpDashboardLayoutKey).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return corp.DefaultDashboardLayout(), false, nil
+	}
+	if err != nil {
+		return corp.DefaultDashboardLayout(), false, err
+	}
+	if err := json.Unmarshal([]byte(raw), &layout); err != nil {
+		return corp.DefaultDashboardLayout(), false, err
+	}
+	return layout.Normalize(), true, nil
+}
+
+// SaveCorpDashboardLayoutForUser normalizes and stores the layout, returning
+// what was saved.
+func (d *DB) SaveCorpDashboardLayoutForUser(userID string, layout corp.DashboardLayout) (corp.DashboardLayout, error) {
+	userID = normalizeUserID(userID)
+	layout = layout.Normalize()
+	raw, err := json.Marshal(layout)
+	if err != nil {
+		return layout, err
+	}
+	_, err = d.sql.Exec("INSERT OR REPLACE INTO config (user_id, key, value) VALUES (?, ?, ?)", userID, corpDashboardLayoutKey, string(raw))
+	return layout, err
+}
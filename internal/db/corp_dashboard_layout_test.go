@@ -0,0 +1,51 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"eve-flipper/internal/corp"
+)
+
+func TestCorpDashboardLayout_RoundTripPerUser(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	layout, ok, err := d.LoadCorpDashboardLayoutForUser("alice")
+	if err != nil || ok {
+		t.Fatalf("initial load ok=%v err=%v, want default", ok, err)
+	}
+	if !reflect.DeepEqual(layout, corp.DefaultDashboardLayout()) {
+		t.Fatalf("initial layout = %+v, want default", layout)
+	}
+
+	saved, err := d.SaveCorpDashboardLayoutForUser("alice", corp.DashboardLayout{
+		Order:    []string{corp.SectionMining, "bogus", corp.SectionFinances, corp.SectionMining},
+		Disabled: []string{corp.SectionFuel, corp.SectionMarket, "bogus"},
+	})
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if saved.Order[0] != corp.SectionMining || saved.Order[1] != corp.SectionFinances || len(saved.Order) != len(corp.DashboardSections) {
+		t.Errorf("saved order = %v", saved.Order)
+	}
+	if !reflect.DeepEqual(saved.Disabled, []string{corp.SectionFuel, corp.SectionMarket}) {
+		t.Errorf("saved disabled = %v", saved.Disabled)
+	}
+
+	got, ok, err := d.LoadCorpDashboardLayoutForUser("alice")
+	if err != nil || !ok || !reflect.DeepEqual(got, saved) {
+		t.Fatalf("reload = %+v ok=%v err=%v, want %+v", got, ok, err, saved)
+	}
+	if _, ok, _ := d.LoadCorpDashboardLayoutForUser("bob"); ok {
+		t.Error("layout leaked to another user")
+	}
+
+	// Saving scan settings must not clobber the layout.
+	if err := d.SaveConfigForUser("alice", d.LoadConfigForUser("alice")); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	if got, ok, _ := d.LoadCorpDashboardLayoutForUser("alice"); !ok || !reflect.DeepEqual(got, saved) {
+		t.Errorf("layout after config save = %+v ok=%v", got, ok)
+	}
+}
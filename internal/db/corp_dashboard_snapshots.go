@@ -0,0 +1,108 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"eve-flipper/internal/corp"
+)
+
+// SaveCorpDashboardSnapshotsForUser upserts daily dashboard rows. A row only
+// overwrites the balance/member columns when it was captured and the P&L
+// columns when it carries P&L from at least as many transactions as the
+// stored row, so backfilled days keep their captured balances and vice
+// versa, and a day seen with fewer journal entries never replaces a fuller
+// one.
+func (d *DB) SaveCorpDashboardSnapshotsForUser(userID string, corporationID int32, rows []corp.DashboardSnapshot) error {
+	userID = normalizeUserID(userID)
+	if corporationID <= 0 {
+		return fmt.Errorf("invalid corporation id")
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	tx, err := d.sql.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO corp_dashboard_snapshots (
+			user_id, corporation_id, date,
+			captured, total_balance, members, active_members,
+			has_pnl, revenue, expenses, net_income, transactions, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, corporation_id, date) DO UPDATE SET
+			captured       = MAX(captured, excluded.captured),
+			total_balance  = CASE WHEN excluded.captured = 1 THEN excluded.total_balance ELSE total_balance END,
+			members        = CASE WHEN excluded.captured = 1 THEN excluded.members ELSE members END,
+			active_members = CASE WHEN excluded.captured = 1 THEN excluded.active_members ELSE active_members END,
+			has_pnl        = MAX(has_pnl, excluded.has_pnl),
+			revenue        = CASE WHEN excluded.has_pnl = 1 AND (has_pnl = 0 OR excluded.transactions >= transactions) THEN excluded.revenue ELSE revenue END,
+			expenses       = CASE WHEN excluded.has_pnl = 1 AND (has_pnl = 0 OR excluded.transactions >= transactions) THEN excluded.expenses ELSE expenses END,
+			net_income     = CASE WHEN excluded.has_pnl = 1 AND (has_pnl = 0 OR excluded.transactions >= transactions) THEN excluded.net_income ELSE net_income END,
+			transactions   = CASE WHEN excluded.has_pnl = 1 AND (has_pnl = 0 OR excluded.transactions >= transactions) THEN excluded.transactions ELSE transactions END,
+			updated_at     = excluded.updated_at
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, r := range rows {
+		if _, err := time.Parse("2006-01-02", r.Date); err != nil {
+			continue
+		}
+		if !r.Captured && !r.HasPnL {
+			continue
+		}
+		captured, hasPnL := 0, 0
+		if r.Captured {
+			captured = 1
+		}
+		if r.HasPnL {
+			hasPnL = 1
+		}
+		if _, err := stmt.Exec(
+			userID, corporationID, r.Date,
+			captured, r.TotalBalance, r.Members, r.ActiveMembers,
+			hasPnL, r.Revenue, r.Expenses, r.NetIncome, r.Transactions, now,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListCorpDashboardSnapshotsForUser returns the corporation's snapshots on or
+// after since (YYYY-MM-DD, empty for all), oldest first.
+func (d *DB) ListCorpDashboardSnapshotsForUser(userID string, corporationID int32, since string) ([]corp.DashboardSnapshot, error) {
+	userID = normalizeUserID(userID)
+	rows, err := d.sql.Query(`
+		SELECT date, captured, total_balance, members, active_members,
+		       has_pnl, revenue, expenses, net_income, transactions
+		FROM corp_dashboard_snapshots
+		WHERE user_id = ? AND corporation_id = ? AND date >= ?
+		ORDER BY date
+	`, userID, corporationID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []corp.DashboardSnapshot{}
+	for rows.Next() {
+		var s corp.DashboardSnapshot
+		var captured, hasPnL int
+		if err := rows.Scan(&s.Date, &captured, &s.TotalBalance, &s.Members, &s.ActiveMembers,
+			&hasPnL, &s.Revenue, &s.Expenses, &s.NetIncome, &s.Transactions); err != nil {
+			return nil, err
+		}
+		s.Captured, s.HasPnL = captured == 1, hasPnL == 1
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"testing"
+
+	"eve-flipper/internal/corp"
+)
+
+func TestCorpDashboardSnapshots_MergeCapturedAndPnL(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	if err := d.SaveCorpDashboardSnapshotsForUser("alice", 7, []corp.DashboardSnapshot{
+		{Date: "2026-06-01", Captured: true, TotalBalance: 100, Members: 5, HasPnL: true, Revenue: 10, NetIncome: 10},
+		{Date: "bogus", Captured: true},
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	// A later view backfills the final P&L for the 1st without losing its
+	// captured balance, and captures the 2nd.
+	if err := d.SaveCorpDashboardSnapshotsForUser("alice", 7, []corp.DashboardSnapshot{
+		{Date: "2026-06-01", HasPnL: true, Revenue: 25, Expenses: -5, NetIncome: 20, Transactions: 4},
+		{Date: "2026-06-02", Captured: true, TotalBalance: 120, Members: 6},
+	}); err != nil {
+		t.Fatalf("save again: %v", err)
+	}
+
+	got, err := d.ListCorpDashboardSnapshotsForUser("alice", 7, "")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("rows = %+v", got)
+	}
+	first := got[0]
+	if !first.Captured || first.TotalBalance != 100 || first.Members != 5 || !first.HasPnL || first.Revenue != 25 || first.Transactions != 4 {
+		t.Fatalf("merged row = %+v", first)
+	}
+	if second := got[1]; !second.Captured || second.HasPnL || second.TotalBalance != 120 {
+		t.Fatalf("second row = %+v", second)
+	}
+
+	// A view whose journal only partly covers the 1st keeps the fuller row.
+	if err := d.SaveCorpDashboardSnapshotsForUser("alice", 7, []corp.DashboardSnapshot{
+		{Date: "2026-06-01", HasPnL: true, Revenue: 5, NetIncome: 5, Transactions: 1},
+	}); err != nil {
+		t.Fatalf("save partial: %v", err)
+	}
+	if got, _ := d.ListCorpDashboardSnapshotsForUser("alice", 7, ""); got[0].Revenue != 25 || got[0].Transactions != 4 {
+		t.Fatalf("partial day overwrote stored P&L: %+v", got[0])
+	}
+
+	if since, _ := d.ListCorpDashboardSnapshotsForUser("alice", 7, "2026-06-02"); len(since) != 1 {
+		t.Fatalf("since filter = %+v", since)
+	}
+	if other, _ := d.ListCorpDashboardSnapshotsForUser("bob", 7, ""); len(other) != 0 {
+		t.Fatalf("bob sees alice's snapshots: %+v", other)
+	}
+	if err := d.SaveCorpDashboardSnapshotsForUser("alice", 0, []corp.DashboardSnapshot{{Date: "2026-06-01", Captured: true}}); err == nil {
+		t.Fatal("expected error for missing corporation id")
+	}
+}
@@ -0,0 +1,212 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"eve-flipper/internal/corp"
+)
+
+const (
+	corpJournalDefaultLimit = 100
+	corpJournalMaxLimit     = 1000
+)
+
+// CorpJournalQuery filters and pages the archived corp wallet journal.
+type CorpJournalQuery struct {
+	CorporationID int32
+	Division      int      // 0 = all divisions
+	RefTypes      []string // any of these ref types; empty = all
+	PartyID       int64    // first or second party; 0 = anyone
+	Party         string   // substring match on either party name
+	Search        string   // substring match on the description
+	Direction     string   // "in", "out" or "" for both
+	Since         string   // inclusive lower bound, YYYY-MM-DD or RFC3339
+	Until         string   // inclusive upper bound, YYYY-MM-DD or RFC3339
+	Limit         int
+	Offset        int
+}
+
+// CorpJournalRow is one archived journal entry with its wallet division.
+type CorpJournalRow struct {
+	corp.CorpJournalEntry
+	Division int `json:"division"`
+}
+
+// CorpJournalRefTypeTotal sums the filtered entries of one ref type.
+type CorpJournalRefTypeTotal struct {
+	RefType  string  `json:"ref_type"`
+	Count    int     `json:"count"`
+	Income   float64 `json:"income"`
+	Expenses float64 `json:"expenses"` // negative
+}
+
+// CorpJournalPage is one page of entries. Total, the income/expense sums and
+// RefTypes cover the whole filter.
+type CorpJournalPage struct {
+	Total    int                       `json:"total"`
+	Limit    int                       `json:"limit"`
+	Offset   int                       `json:"offset"`
+	Income   float64                   `json:"income"`
+	Expenses float64                   `json:"expenses"`
+	Net      float64                   `json:"net"`
+	Entries  []CorpJournalRow          `json:"entries"`
+	RefTypes []CorpJournalRefTypeTotal `json:"ref_types"`
+}
+
+// LatestCorpJournalSyncForUser returns when the corporation's journal was
+// last synced, or the zero time if it never was.
+func (d *DB) LatestCorpJournalSyncForUser(userID string, corporationID int32) (time.Time, error) {
+	return d.latestIngestSync(normalizeUserID(userID), IngestSourceCorpJournal, int64(corporationID))
+}
+
+// QueryCorpJournalForUser returns a filtered page of the archived corp
+// journal, newest first, with totals per ref type.
+func (d *DB) QueryCorpJournalForUser(userID string, q CorpJournalQuery) (CorpJournalPage, error) {
+	userID = normalizeUserID(userID)
+	if q.Limit <= 0 {
+		q.Limit = corpJournalDefaultLimit
+	}
+	if q.Limit > corpJournalMaxLimit {
+		q.Limit = corpJournalMaxLimit
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	page := CorpJournalPage{
+		Limit:    q.Limit,
+		Offset:   q.Offset,
+		Entries:  []CorpJournalRow{},
+		RefTypes: []CorpJournalRefTypeTotal{},
+	}
+
+	where, args, err := corpJournalWhere(userID, q)
+	if err != nil {
+		return page, err
+	}
+
+	rows, err := d.sql.Query(`
+		SELECT ref_type, COUNT(*),
+		       COALESCE(SUM(CASE WHEN amount > 0 THEN amount ELSE 0 END), 0),
+		       COALESCE(SUM(CASE WHEN amount < 0 THEN amount ELSE 0 END), 0)
+		FROM corp_journal
+		WHERE `+where+`
+		GROUP BY ref_type
+		ORDER BY ABS(SUM(amount)) DESC, ref_type`,
+		args...,
+	)
+	if err != nil {
+		return page, err
+	}
+	for rows.Next() {
+		var t CorpJournalRefTypeTotal
+		if err := rows.Scan(&t.RefType, &t.Count, &t.Income, &t.Expenses); err != nil {
+			rows.Close()
+			return page, err
+		}
+		page.Total += t.Count
+		page.Income += t.Income
+		page.Expenses += t.Expenses
+		page.RefTypes = append(page.RefTypes, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return page, err
+	}
+	page.Net = page.Income + page.Expenses
+
+	rows, err = d.sql.Query(`
+		SELECT division, entry_id, date, ref_type, amount, balance, description,
+		       first_party_id, first_party_name, second_party_id, second_party_name
+		FROM corp_journal
+		WHERE `+where+`
+		ORDER BY date DESC, entry_id DESC
+		LIMIT ? OFFSET ?`,
+		append(append([]interface{}{}, args...), q.Limit, q.Offset)...,
+	)
+	if err != nil {
+		return page, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var row CorpJournalRow
+		if err := rows.Scan(
+			&row.Division,
+			&row.ID,
+			&row.Date,
+			&row.RefType,
+			&row.Amount,
+			&row.Balance,
+			&row.Description,
+			&row.FirstPartyID,
+			&row.FirstPartyName,
+			&row.SecondPartyID,
+			&row.SecondPartyName,
+		); err != nil {
+			return page, err
+		}
+		page.Entries = append(page.Entries, row)
+	}
+	return page, rows.Err()
+}
+
+func corpJournalWhere(userID string, q CorpJournalQuery) (string, []interface{}, error) {
+	where := "user_id = ? AND corporation_id = ?"
+	args := []interface{}{userID, q.CorporationID}
+	if q.Division > 0 {
+		where += " AND division = ?"
+		args = append(args, q.Division)
+	}
+	var refTypes []string
+	for _, rt := range q.RefTypes {
+		if rt = strings.TrimSpace(rt); rt != "" {
+			refTypes = append(refTypes, rt)
+		}
+	}
+	if len(refTypes) > 0 {
+		where += " AND ref_type IN (?" + strings.Repeat(", ?", len(refTypes)-1) + ")"
+		for _, rt := range refTypes {
+			args = append(args, rt)
+		}
+	}
+	if q.PartyID > 0 {
+		where += " AND (first_party_id = ? OR second_party_id = ?)"
+		args = append(args, q.PartyID, q.PartyID)
+	}
+	if party := strings.TrimSpace(q.Party); party != "" {
+		where += " AND (first_party_name LIKE ? OR second_party_name LIKE ?)"
+		pattern := "%" + party + "%"
+		args = append(args, pattern, pattern)
+	}
+	if search := strings.TrimSpace(q.Search); search != "" {
+		where += " AND description LIKE ?"
+		args = append(args, "%"+search+"%")
+	}
+	switch q.Direction {
+	case "":
+	case "in":
+		where += " AND amount > 0"
+	case "out":
+		where += " AND amount < 0"
+	default:
+		return "", nil, fmt.Errorf("unsupported direction %q", q.Direction)
+	}
+	if q.Since != "" {
+		bound, err := corpTransactionDateBound(q.Since, false)
+		if err != nil {
+			return "", nil, err
+		}
+		where += " AND date >= ?"
+		args = append(args, bound)
+	}
+	if q.Until != "" {
+		bound, err := corpTransactionDateBound(q.Until, true)
+		if err != nil {
+			return "", nil, err
+		}
+		where += " AND date < ?"
+		args = append(args, bound)
+	}
+	return where, args, nil
+}
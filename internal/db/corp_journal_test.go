@@ -0,0 +1,79 @@
+package db
+
+import (
+	"testing"
+
+	"eve-flipper/internal/corp"
+)
+
+func TestCorpJournalFilterAndPage(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	userID := "corp-journal-user"
+	corpID := int32(98000042)
+	if _, err := d.UpsertCorpJournalForUser(userID, corpID, 1, []corp.CorpJournalEntry{
+		{ID: 1, Date: "2026-05-01T10:00:00Z", RefType: "bounty_prizes", Amount: 1000, FirstPartyID: 1000125, FirstPartyName: "CONCORD", SecondPartyID: 7, SecondPartyName: "Alice"},
+		{ID: 2, Date: "2026-05-02T10:00:00Z", RefType: "market_escrow", Amount: -400, Description: "Market escrow", FirstPartyID: 8, FirstPartyName: "Bob"},
+		{ID: 3, Date: "2026-05-03T10:00:00Z", RefType: "bounty_prizes", Amount: 500, FirstPartyID: 1000125, SecondPartyID: 8, SecondPartyName: "Bob"},
+	}); err != nil {
+		t.Fatalf("UpsertCorpJournalForUser: %v", err)
+	}
+	if _, err := d.UpsertCorpJournalForUser(userID, corpID, 2, []corp.CorpJournalEntry{
+		{ID: 1, Date: "2026-05-04T10:00:00Z", RefType: "corporation_account_withdrawal", Amount: -50, FirstPartyID: 7, FirstPartyName: "Alice"},
+	}); err != nil {
+		t.Fatalf("division 2 upsert: %v", err)
+	}
+	if synced, err := d.LatestCorpJournalSyncForUser(userID, corpID); err != nil || synced.IsZero() {
+		t.Fatalf("LatestCorpJournalSyncForUser = %v, %v", synced, err)
+	}
+
+	page, err := d.QueryCorpJournalForUser(userID, CorpJournalQuery{CorporationID: corpID, Limit: 2})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if page.Total != 4 || len(page.Entries) != 2 {
+		t.Fatalf("total=%d entries=%d, want 4/2", page.Total, len(page.Entries))
+	}
+	if e := page.Entries[0]; e.Division != 2 || e.ID != 1 {
+		t.Fatalf("newest entry = %+v, want division 2 entry 1", e)
+	}
+	if page.Income != 1500 || page.Expenses != -450 || page.Net != 1050 {
+		t.Fatalf("sums income=%v expenses=%v net=%v", page.Income, page.Expenses, page.Net)
+	}
+	if len(page.RefTypes) != 3 || page.RefTypes[0].RefType != "bounty_prizes" || page.RefTypes[0].Count != 2 {
+		t.Fatalf("ref types = %+v", page.RefTypes)
+	}
+
+	bob, err := d.QueryCorpJournalForUser(userID, CorpJournalQuery{CorporationID: corpID, PartyID: 8, Division: 1})
+	if err != nil {
+		t.Fatalf("party query: %v", err)
+	}
+	if bob.Total != 2 {
+		t.Fatalf("party 8 total = %d, want 2", bob.Total)
+	}
+	bounties, err := d.QueryCorpJournalForUser(userID, CorpJournalQuery{
+		CorporationID: corpID,
+		RefTypes:      []string{"bounty_prizes", "agent_mission_reward"},
+		Party:         "ali",
+		Since:         "2026-05-01",
+		Until:         "2026-05-02",
+	})
+	if err != nil {
+		t.Fatalf("filtered query: %v", err)
+	}
+	if bounties.Total != 1 || bounties.Entries[0].ID != 1 {
+		t.Fatalf("filtered = %+v, want entry 1 only", bounties.Entries)
+	}
+	out, err := d.QueryCorpJournalForUser(userID, CorpJournalQuery{CorporationID: corpID, Direction: "out", Search: "escrow"})
+	if err != nil || out.Total != 1 {
+		t.Fatalf("direction+search total=%d err=%v, want 1", out.Total, err)
+	}
+
+	if _, err := d.QueryCorpJournalForUser(userID, CorpJournalQuery{CorporationID: corpID, Direction: "sideways"}); err == nil {
+		t.Fatal("expected error for unknown direction")
+	}
+	if other, _ := d.QueryCorpJournalForUser("someone-else", CorpJournalQuery{CorporationID: corpID}); other.Total != 0 {
+		t.Fatalf("journal leaked to another user: %d", other.Total)
+	}
+}
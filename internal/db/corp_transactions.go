@@ -0,0 +1,397 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"eve-flipper/internal/corp"
+)
+
+const (
+	corpTransactionDefaultLimit = 100
+	corpTransactionMaxLimit     = 1000
+)
+
+// CorpTransactionQuery filters, groups and pages persisted corp wallet transactions.
+type CorpTransactionQuery struct {
+	CorporationID int32
+	Division      int    // 0 = all divisions
+	TypeID        int32  // 0 = any type
+	ClientID      int64  // 0 = any counterparty
+	Side          string // "buy", "sell" or "" for both
+	Since         string // inclusive lower bound, YYYY-MM-DD or RFC3339
+	Until         string // inclusive upper bound, YYYY-MM-DD or RFC3339
+	Search        string // substring match on type or client name
+	GroupBy       string // "", "type", "member" or "day"
+	Limit         int
+	Offset        int
+}
+
+// CorpTransactionRow is one persisted transaction with its wallet division.
+type CorpTransactionRow struct {
+	corp.CorpTransaction
+	Division int `json:"division"`
+}
+
+// CorpTransactionGroup aggregates transactions sharing a type, member or day.
+type CorpTransactionGroup struct {
+	Key            string  `json:"key"`
+	Label          string  `json:"label"`
+	Count          int     `json:"count"`
+	QuantityBought int64   `json:"quantity_bought"`
+	QuantitySold   int64   `json:"quantity_sold"`
+	BoughtISK      float64 `json:"bought_isk"`
+	SoldISK        float64 `json:"sold_isk"`
+	NetISK         float64 `json:"net_isk"`
+	FirstDate      string  `json:"first_date"`
+	LastDate       string  `json:"last_date"`
+}
+
+// CorpTransactionPage is one page of rows (or groups when GroupBy is set).
+// Total counts rows or groups across all pages; Summary covers the whole filter.
+type CorpTransactionPage struct {
+	GroupBy      string                 `json:"group_by"`
+	Total        int                    `json:"total"`
+	Limit        int                    `json:"limit"`
+	Offset       int                    `json:"offset"`
+	Transactions []CorpTransactionRow   `json:"transactions"`
+	Groups       []CorpTransactionGroup `json:"groups"`
+	Summary      CorpTransactionGroup   `json:"summary"`
+}
+
+// UpsertCorpTransactionsForUser stores one division's transaction page from the
+// corp provider. Rows already seen keep their first_seen_at and are only
+// rewritten when something changed.
+func (d *DB) UpsertCorpTransactionsForUser(userID string, corporationID int32, division int, txns []corp.CorpTransaction) error {
+	userID = normalizeUserID(userID)
+	if corporationID <= 0 || division < 1 || division > 7 {
+		return fmt.Errorf("invalid corp transaction archive scope")
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	tx, err := d.sql.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	watermark, err := ingestWatermarkTx(tx, userID, IngestSourceCorpTransactions, int64(corporationID), division)
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`
+		INSERT INTO corp_transactions (
+			user_id, corporation_id, division, transaction_id, date, type_id, type_name,
+			quantity, unit_price, is_buy, location_id, location_name, client_id, client_name,
+			first_seen_at, last_seen_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, corporation_id, division, transaction_id) DO UPDATE SET
+			date = excluded.date,
+			type_id = excluded.type_id,
+			quantity = excluded.quantity,
+			unit_price = excluded.unit_price,
+			is_buy = excluded.is_buy,
+			location_id = excluded.location_id,
+			client_id = excluded.client_id,
+			type_name = CASE WHEN excluded.type_name != '' THEN excluded.type_name ELSE corp_transactions.type_name END,
+			location_name = CASE WHEN excluded.location_name != '' THEN excluded.location_name ELSE corp_transactions.location_name END,
+			client_name = CASE WHEN excluded.client_name != '' THEN excluded.client_name ELSE corp_transactions.client_name END,
+			last_seen_at = excluded.last_seen_at
+		WHERE corp_transactions.date IS NOT excluded.date
+		   OR corp_transactions.type_id IS NOT excluded.type_id
+		   OR corp_transactions.quantity IS NOT excluded.quantity
+		   OR corp_transactions.unit_price IS NOT excluded.unit_price
+		   OR corp_transactions.is_buy IS NOT excluded.is_buy
+		   OR corp_transactions.location_id IS NOT excluded.location_id
+		   OR corp_transactions.client_id IS NOT excluded.client_id
+		   OR (excluded.type_name != '' AND corp_transactions.type_name IS NOT excluded.type_name)
+		   OR (excluded.location_name != '' AND corp_transactions.location_name IS NOT excluded.location_name)
+		   OR (excluded.client_name != '' AND corp_transactions.client_name IS NOT excluded.client_name)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	lastID, newRows := watermark, 0
+	for _, row := range txns {
+		if row.TransactionID == 0 || strings.TrimSpace(row.Date) == "" {
+			continue
+		}
+		if row.TransactionID > watermark {
+			newRows++
+			lastID = max(lastID, row.TransactionID)
+		}
+		if _, err := stmt.Exec(
+			userID,
+			corporationID,
+			division,
+			row.TransactionID,
+			row.Date,
+			row.TypeID,
+			row.TypeName,
+			row.Quantity,
+			row.UnitPrice,
+			boolInt(row.IsBuy),
+			row.LocationID,
+			row.LocationName,
+			row.ClientID,
+			row.ClientName,
+			now,
+			now,
+		); err != nil {
+			return err
+		}
+	}
+	if err := saveIngestWatermarkTx(tx, userID, IngestSourceCorpTransactions, int64(corporationID), division, lastID, newRows, now); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// LatestCorpTransactionSyncForUser returns when transactions for the corporation
+// were last synced, or the zero time if none are stored.
+func (d *DB) LatestCorpTransactionSyncForUser(userID string, corporationID int32) (time.Time, error) {
+	userID = normalizeUserID(userID)
+	if synced, err := d.latestIngestSync(userID, IngestSourceCorpTransactions, int64(corporationID)); err != nil || !synced.IsZero() {
+		return synced, err
+	}
+	// Rows stored before ingest watermarks existed.
+	var last string
+	if err := d.sql.QueryRow(`
+		SELECT COALESCE(MAX(last_seen_at), '') FROM corp_transactions
+		WHERE user_id = ? AND corporation_id = ?
+	`, userID, corporationID).Scan(&last); err != nil {
+		return time.Time{}, err
+	}
+	if last == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, last)
+}
+
+// QueryCorpTransactionsForUser returns a filtered page of persisted corp
+// transactions, either raw (newest first) or grouped by type, member or day.
+// "member" groups by the transaction counterparty: ESI does not record which
+// corp member placed the order, so client_id is the member for internal trades.
+func (d *DB) QueryCorpTransactionsForUser(userID string, q CorpTransactionQuery) (CorpTransactionPage, error) {
+	userID = normalizeUserID(userID)
+	if q.Limit <= 0 {
+		q.Limit = corpTransactionDefaultLimit
+	}
+	if q.Limit > corpTransactionMaxLimit {
+		q.Limit = corpTransactionMaxLimit
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	page := CorpTransactionPage{
+		GroupBy:      q.GroupBy,
+		Limit:        q.Limit,
+		Offset:       q.Offset,
+		Transactions: []CorpTransactionRow{},
+		Groups:       []CorpTransactionGroup{},
+	}
+
+	where, args, err := corpTransactionWhere(userID, q)
+	if err != nil {
+		return page, err
+	}
+
+	summary, err := d.corpTransactionGroups("'all'", "'All transactions'", where, args, "key", 1, 0)
+	if err != nil {
+		return page, err
+	}
+	if len(summary) == 1 {
+		page.Summary = summary[0]
+	}
+
+	var keyExpr, labelExpr, order string
+	switch q.GroupBy {
+	case "":
+		page.Total = page.Summary.Count
+		rows, err := d.corpTransactionRows(where, args, q.Limit, q.Offset)
+		if err != nil {
+			return page, err
+		}
+		page.Transactions = rows
+		return page, nil
+	case "type":
+		keyExpr, labelExpr, order = "CAST(type_id AS TEXT)", "MAX(type_name)", "bought_isk + sold_isk DESC, key"
+	case "member":
+		keyExpr, labelExpr, order = "CAST(client_id AS TEXT)", "MAX(client_name)", "bought_isk + sold_isk DESC, key"
+	case "day":
+		keyExpr, labelExpr, order = "substr(date, 1, 10)", "substr(date, 1, 10)", "key DESC"
+	default:
+		return page, fmt.Errorf("unsupported group_by %q", q.GroupBy)
+	}
+
+	if err := d.sql.QueryRow(
+		`SELECT COUNT(DISTINCT `+keyExpr+`) FROM corp_transactions WHERE `+where,
+		args...,
+	).Scan(&page.Total); err != nil {
+		return page, err
+	}
+	groups, err := d.corpTransactionGroups(keyExpr, labelExpr, where, args, order, q.Limit, q.Offset)
+	if err != nil {
+		return page, err
+	}
+	page.Groups = groups
+	return page, nil
+}
+
+func corpTransactionWhere(userID string, q CorpTransactionQuery) (string, []interface{}, error) {
+	where := "user_id = ? AND corporation_id = ?"
+	args := []interface{}{userID, q.CorporationID}
+	if q.Division > 0 {
+		where += " AND division = ?"
+		args = append(args, q.Division)
+	}
+	if q.TypeID > 0 {
+		where += " AND type_id = ?"
+		args = append(args, q.TypeID)
+	}
+	if q.ClientID > 0 {
+		where += " AND client_id = ?"
+		args = append(args, q.ClientID)
+	}
+	switch q.Side {
+	case "":
+	case "buy":
+		where += " AND is_buy = 1"
+	case "sell":
+		where += " AND is_buy = 0"
+	default:
+		return "", nil, fmt.Errorf("unsupported side %q", q.Side)
+	}
+	if q.Since != "" {
+		bound, err := corpTransactionDateBound(q.Since, false)
+		if err != nil {
+			return "", nil, err
+		}
+		where += " AND date >= ?"
+		args = append(args, bound)
+	}
+	if q.Until != "" {
+		bound, err := corpTransactionDateBound(q.Until, true)
+		if err != nil {
+			return "", nil, err
+		}
+		where += " AND date < ?"
+		args = append(args, bound)
+	}
+	if search := strings.TrimSpace(q.Search); search != "" {
+		where += " AND (type_name LIKE ? OR client_name LIKE ?)"
+		pattern := "%" + search + "%"
+		args = append(args, pattern, pattern)
+	}
+	return where, args, nil
+}
+
+// corpTransactionDateBound converts a date or timestamp filter into an RFC3339
+// bound. Upper bounds are exclusive, so a bare date covers the whole day.
+func corpTransactionDateBound(value string, upper bool) (string, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		if upper {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t.Format(time.RFC3339), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q", value)
+	}
+	if upper {
+		t = t.Add(time.Second)
+	}
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+func (d *DB) corpTransactionRows(where string, args []interface{}, limit, offset int) ([]CorpTransactionRow, error) {
+	rows, err := d.sql.Query(`
+		SELECT division, transaction_id, date, type_id, type_name, quantity, unit_price, is_buy,
+		       location_id, location_name, client_id, client_name
+		FROM corp_transactions
+		WHERE `+where+`
+		ORDER BY date DESC, transaction_id DESC
+		LIMIT ? OFFSET ?`,
+		append(append([]interface{}{}, args...), limit, offset)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []CorpTransactionRow{}
+	for rows.Next() {
+		var row CorpTransactionRow
+		var isBuy int
+		if err := rows.Scan(
+			&row.Division,
+			&row.TransactionID,
+			&row.Date,
+			&row.TypeID,
+			&row.TypeName,
+			&row.Quantity,
+			&row.UnitPrice,
+			&isBuy,
+			&row.LocationID,
+			&row.LocationName,
+			&row.ClientID,
+			&row.ClientName,
+		); err != nil {
+			return nil, err
+		}
+		row.IsBuy = isBuy != 0
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
+
+func (d *DB) corpTransactionGroups(keyExpr, labelExpr, where string, args []interface{}, order string, limit, offset int) ([]CorpTransactionGroup, error) {
+	rows, err := d.sql.Query(`
+		SELECT `+keyExpr+` AS key,
+		       `+labelExpr+` AS label,
+		       COUNT(*),
+		       COALESCE(SUM(CASE WHEN is_buy = 1 THEN quantity ELSE 0 END), 0),
+		       COALESCE(SUM(CASE WHEN is_buy = 0 THEN quantity ELSE 0 END), 0),
+		       COALESCE(SUM(CASE WHEN is_buy = 1 THEN quantity * unit_price ELSE 0 END), 0) AS bought_isk,
+		       COALESCE(SUM(CASE WHEN is_buy = 0 THEN quantity * unit_price ELSE 0 END), 0) AS sold_isk,
+		       COALESCE(MIN(date), ''),
+		       COALESCE(MAX(date), '')
+		FROM corp_transactions
+		WHERE `+where+`
+		GROUP BY key
+		HAVING COUNT(*) > 0
+		ORDER BY `+order+`
+		LIMIT ? OFFSET ?`,
+		append(append([]interface{}{}, args...), limit, offset)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []CorpTransactionGroup{}
+	for rows.Next() {
+		var g CorpTransactionGroup
+		if err := rows.Scan(
+			&g.Key,
+			&g.Label,
+			&g.Count,
+			&g.QuantityBought,
+			&g.QuantitySold,
+			&g.BoughtISK,
+			&g.SoldISK,
+			&g.FirstDate,
+			&g.LastDate,
+		); err != nil {
+			return nil, err
+		}
+		g.NetISK = g.SoldISK - g.BoughtISK
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
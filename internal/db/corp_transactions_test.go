@@ -0,0 +1,95 @@
+package db
+
+import (
+	"testing"
+
+	"eve-flipper/internal/corp"
+)
+
+func TestCorpTransactionsGroupFilterAndPage(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	userID := "corp-tx-user"
+	corpID := int32(98000042)
+	if err := d.UpsertCorpTransactionsForUser(userID, corpID, 1, []corp.CorpTransaction{
+		{TransactionID: 1, Date: "2026-05-01T10:00:00Z", TypeID: 34, TypeName: "Tritanium", Quantity: 100, UnitPrice: 5, IsBuy: true, ClientID: 7, ClientName: "Alice"},
+		{TransactionID: 2, Date: "2026-05-01T12:00:00Z", TypeID: 34, TypeName: "Tritanium", Quantity: 50, UnitPrice: 6, ClientID: 8, ClientName: "Bob"},
+		{TransactionID: 3, Date: "2026-05-02T09:00:00Z", TypeID: 35, TypeName: "Pyerite", Quantity: 10, UnitPrice: 20, ClientID: 7, ClientName: "Alice"},
+	}); err != nil {
+		t.Fatalf("UpsertCorpTransactionsForUser: %v", err)
+	}
+	// Re-upserting the same page must not duplicate rows; another division is separate.
+	if err := d.UpsertCorpTransactionsForUser(userID, corpID, 1, []corp.CorpTransaction{
+		{TransactionID: 1, Date: "2026-05-01T10:00:00Z", TypeID: 34, Quantity: 100, UnitPrice: 5, IsBuy: true, ClientID: 7},
+	}); err != nil {
+		t.Fatalf("re-upsert: %v", err)
+	}
+	if err := d.UpsertCorpTransactionsForUser(userID, corpID, 2, []corp.CorpTransaction{
+		{TransactionID: 1, Date: "2026-05-03T10:00:00Z", TypeID: 36, TypeName: "Mexallon", Quantity: 1, UnitPrice: 100, ClientID: 8, ClientName: "Bob"},
+	}); err != nil {
+		t.Fatalf("division 2 upsert: %v", err)
+	}
+
+	page, err := d.QueryCorpTransactionsForUser(userID, CorpTransactionQuery{CorporationID: corpID, Limit: 2})
+	if err != nil {
+		t.Fatalf("raw query: %v", err)
+	}
+	if page.Total != 4 || len(page.Transactions) != 2 {
+		t.Fatalf("raw page total=%d rows=%d, want 4/2", page.Total, len(page.Transactions))
+	}
+	if page.Transactions[0].Division != 2 || page.Transactions[0].TypeName != "Mexallon" {
+		t.Fatalf("newest row = %+v, want division 2 Mexallon", page.Transactions[0])
+	}
+	if first := page.Transactions[1]; first.TypeName != "Pyerite" {
+		t.Fatalf("second row = %+v, want Pyerite", first)
+	}
+	if page.Summary.BoughtISK != 500 || page.Summary.SoldISK != 600 || page.Summary.NetISK != 100 {
+		t.Fatalf("summary = %+v, want bought 500 sold 600 net 100", page.Summary)
+	}
+
+	byType, err := d.QueryCorpTransactionsForUser(userID, CorpTransactionQuery{CorporationID: corpID, GroupBy: "type"})
+	if err != nil {
+		t.Fatalf("type query: %v", err)
+	}
+	if byType.Total != 3 || len(byType.Groups) != 3 {
+		t.Fatalf("type groups total=%d len=%d, want 3", byType.Total, len(byType.Groups))
+	}
+	// Ordered by turnover: Tritanium 800, Pyerite 200, Mexallon 100.
+	if g := byType.Groups[0]; g.Key != "34" || g.Label != "Tritanium" || g.Count != 2 || g.NetISK != -200 {
+		t.Fatalf("top type group = %+v", g)
+	}
+
+	byMember, err := d.QueryCorpTransactionsForUser(userID, CorpTransactionQuery{CorporationID: corpID, GroupBy: "member", Division: 1})
+	if err != nil {
+		t.Fatalf("member query: %v", err)
+	}
+	if len(byMember.Groups) != 2 || byMember.Groups[0].Label != "Alice" || byMember.Groups[0].Count != 2 {
+		t.Fatalf("member groups = %+v", byMember.Groups)
+	}
+
+	byDay, err := d.QueryCorpTransactionsForUser(userID, CorpTransactionQuery{
+		CorporationID: corpID,
+		GroupBy:       "day",
+		Side:          "sell",
+		Since:         "2026-05-01",
+		Until:         "2026-05-02",
+	})
+	if err != nil {
+		t.Fatalf("day query: %v", err)
+	}
+	if len(byDay.Groups) != 2 || byDay.Groups[0].Key != "2026-05-02" || byDay.Groups[1].SoldISK != 300 {
+		t.Fatalf("day groups = %+v", byDay.Groups)
+	}
+
+	if _, err := d.QueryCorpTransactionsForUser(userID, CorpTransactionQuery{CorporationID: corpID, GroupBy: "station"}); err == nil {
+		t.Fatal("expected error for unsupported group_by")
+	}
+	other, err := d.QueryCorpTransactionsForUser("someone-else", CorpTransactionQuery{CorporationID: corpID})
+	if err != nil {
+		t.Fatalf("other user query: %v", err)
+	}
+	if other.Total != 0 {
+		t.Fatalf("other user saw %d rows", other.Total)
+	}
+}
@@ -1649,6 +1649,471 @@ func (d *DB) migrate() error {
 		logger.Info("DB", "Applied migration v39 (private wallet balance and SP metrics)")
 	}
 
+	if version < 40 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS corp_buyback_boards (
+				id           INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id      TEXT NOT NULL,
+				percent      REAL NOT NULL,
+				source       TEXT NOT NULL DEFAULT '',
+				changed      INTEGER NOT NULL DEFAULT 0,
+				payload_json TEXT NOT NULL,
+				created_at   TEXT NOT NULL
+			);
+			CREATE INDEX IF NOT EXISTS idx_corp_buyback_boards_user_time ON corp_buyback_boards(user_id, id DESC);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (40);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v40: %w", err)
+		}
+		logger.Info("DB", "Applied migration v40 (corp buyback price boards)")
+	}
+
+	if version < 41 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS corp_transactions (
+				user_id         TEXT NOT NULL,
+				corporation_id  INTEGER NOT NULL,
+				division        INTEGER NOT NULL,
+				transaction_id  INTEGER NOT NULL,
+				date            TEXT NOT NULL,
+				type_id         INTEGER NOT NULL DEFAULT 0,
+				type_name       TEXT NOT NULL DEFAULT '',
+				quantity        INTEGER NOT NULL DEFAULT 0,
+				unit_price      REAL NOT NULL DEFAULT 0,
+				is_buy          INTEGER NOT NULL DEFAULT 0,
+				location_id     INTEGER NOT NULL DEFAULT 0,
+				location_name   TEXT NOT NULL DEFAULT '',
+				client_id       INTEGER NOT NULL DEFAULT 0,
+				client_name     TEXT NOT NULL DEFAULT '',
+				first_seen_at   TEXT NOT NULL,
+				last_seen_at    TEXT NOT NULL,
+				PRIMARY KEY (user_id, corporation_id, division, transaction_id)
+			);
+			CREATE INDEX IF NOT EXISTS idx_corp_tx_user_date
+				ON corp_transactions(user_id, corporation_id, date DESC);
+			CREATE INDEX IF NOT EXISTS idx_corp_tx_type
+				ON corp_transactions(user_id, corporation_id, type_id, date DESC);
+			CREATE INDEX IF NOT EXISTS idx_corp_tx_client
+				ON corp_transactions(user_id, corporation_id, client_id, date DESC);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (41);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v41: %w", err)
+		}
+		logger.Info("DB", "Applied migration v41 (corp transactions archive)")
+	}
+
+	if version < 42 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS name_cache (
+				id         INTEGER PRIMARY KEY,
+				name       TEXT NOT NULL,
+				category   TEXT NOT NULL DEFAULT '',
+				updated_at TEXT NOT NULL
+			);
+			CREATE INDEX IF NOT EXISTS idx_name_cache_updated ON name_cache(updated_at);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (42);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v42: %w", err)
+		}
+		logger.Info("DB", "Applied migration v42 (character/corp name cache)")
+	}
+
+	if version < 43 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS esi_page_cache (
+				url        TEXT PRIMARY KEY,
+				etag       TEXT NOT NULL,
+				expires_at TEXT NOT NULL,
+				pages      INTEGER NOT NULL DEFAULT 1,
+				body       BLOB NOT NULL,
+				fetched_at TEXT NOT NULL
+			);
+			CREATE INDEX IF NOT EXISTS idx_esi_page_cache_fetched ON esi_page_cache(fetched_at);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (43);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v43: %w", err)
+		}
+		logger.Info("DB", "Applied migration v43 (ESI market order page cache)")
+	}
+
+	if version < 44 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS usage_events (
+				id         INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id    TEXT NOT NULL DEFAULT 'default',
+				category   TEXT NOT NULL,
+				name       TEXT NOT NULL,
+				detail     TEXT NOT NULL DEFAULT '',
+				created_at TEXT NOT NULL
+			);
+			CREATE INDEX IF NOT EXISTS idx_usage_events_user_time ON usage_events(user_id, created_at DESC);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (44);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v44: %w", err)
+		}
+		logger.Info("DB", "Applied migration v44 (local usage statistics)")
+	}
+
+	if version < 45 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS ansiblex_gates (
+				user_id        TEXT NOT NULL DEFAULT 'default',
+				from_system_id INTEGER NOT NULL,
+				to_system_id   INTEGER NOT NULL,
+				structure_id   INTEGER NOT NULL DEFAULT 0,
+				name           TEXT NOT NULL DEFAULT '',
+				source         TEXT NOT NULL DEFAULT 'manual',
+				updated_at     TEXT NOT NULL,
+				PRIMARY KEY (user_id, from_system_id, to_system_id)
+			);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (45);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v45: %w", err)
+		}
+		logger.Info("DB", "Applied migration v45 (Ansiblex jump gates)")
+	}
+
+	if version < 46 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS scan_accuracy_runs (
+				scan_id    INTEGER PRIMARY KEY,
+				tab        TEXT NOT NULL,
+				checked_at TEXT NOT NULL
+			);
+
+			CREATE TABLE IF NOT EXISTS scan_accuracy_checks (
+				id              INTEGER PRIMARY KEY AUTOINCREMENT,
+				scan_id         INTEGER NOT NULL,
+				tab             TEXT NOT NULL,
+				type_id         INTEGER NOT NULL,
+				buy_system_id   INTEGER NOT NULL DEFAULT 0,
+				sell_system_id  INTEGER NOT NULL DEFAULT 0,
+				scanned_at      TEXT NOT NULL,
+				checked_at      TEXT NOT NULL,
+				scan_buy_price  REAL NOT NULL DEFAULT 0,
+				scan_sell_price REAL NOT NULL DEFAULT 0,
+				now_buy_price   REAL NOT NULL DEFAULT 0,
+				now_sell_price  REAL NOT NULL DEFAULT 0,
+				spread_held     INTEGER NOT NULL DEFAULT 0,
+				spread_retained REAL NOT NULL DEFAULT 0
+			);
+			CREATE INDEX IF NOT EXISTS idx_scan_accuracy_checks_scan ON scan_accuracy_checks(scan_id);
+			CREATE INDEX IF NOT EXISTS idx_scan_accuracy_checks_checked ON scan_accuracy_checks(checked_at);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (46);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v46: %w", err)
+		}
+		logger.Info("DB", "Applied migration v46 (scan accuracy checks)")
+	}
+
+	if version < 47 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS background_jobs (
+				name        TEXT PRIMARY KEY,
+				last_run_at TEXT NOT NULL DEFAULT '',
+				next_run_at TEXT NOT NULL DEFAULT '',
+				state       TEXT NOT NULL DEFAULT '',
+				updated_at  TEXT NOT NULL
+			);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (47);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v47: %w", err)
+		}
+		logger.Info("DB", "Applied migration v47 (background job schedule)")
+	}
+
+	if version < 48 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS ingest_watermarks (
+				user_id    TEXT NOT NULL,
+				source     TEXT NOT NULL,
+				scope_id   INTEGER NOT NULL,
+				division   INTEGER NOT NULL DEFAULT 0,
+				last_id    INTEGER NOT NULL DEFAULT 0,
+				new_rows   INTEGER NOT NULL DEFAULT 0,
+				synced_at  TEXT NOT NULL,
+				PRIMARY KEY (user_id, source, scope_id, division)
+			);
+
+			CREATE TABLE IF NOT EXISTS corp_journal (
+				user_id            TEXT NOT NULL,
+				corporation_id     INTEGER NOT NULL,
+				division           INTEGER NOT NULL,
+				entry_id           INTEGER NOT NULL,
+				date               TEXT NOT NULL,
+				ref_type           TEXT NOT NULL DEFAULT '',
+				amount             REAL NOT NULL DEFAULT 0,
+				balance            REAL NOT NULL DEFAULT 0,
+				description        TEXT NOT NULL DEFAULT '',
+				first_party_id     INTEGER NOT NULL DEFAULT 0,
+				first_party_name   TEXT NOT NULL DEFAULT '',
+				second_party_id    INTEGER NOT NULL DEFAULT 0,
+				second_party_name  TEXT NOT NULL DEFAULT '',
+				first_seen_at      TEXT NOT NULL,
+				last_seen_at       TEXT NOT NULL,
+				PRIMARY KEY (user_id, corporation_id, division, entry_id)
+			);
+			CREATE INDEX IF NOT EXISTS idx_corp_journal_user_date
+				ON corp_journal(user_id, corporation_id, date DESC);
+			CREATE INDEX IF NOT EXISTS idx_corp_journal_ref
+				ON corp_journal(user_id, corporation_id, ref_type, date DESC);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (48);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v48: %w", err)
+		}
+		logger.Info("DB", "Applied migration v48 (ingest watermarks, corp journal archive)")
+	}
+
+	if version < 49 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS api_keys (
+				id               INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id          TEXT NOT NULL,
+				name             TEXT NOT NULL DEFAULT '',
+				key_hash         TEXT NOT NULL UNIQUE,
+				prefix           TEXT NOT NULL,
+				access           TEXT NOT NULL DEFAULT 'read',
+				rate_per_minute  INTEGER NOT NULL DEFAULT 60,
+				created_at       TEXT NOT NULL,
+				last_used_at     TEXT NOT NULL DEFAULT '',
+				revoked          INTEGER NOT NULL DEFAULT 0
+			);
+			CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys(user_id, id);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (49);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v49: %w", err)
+		}
+		logger.Info("DB", "Applied migration v49 (api keys)")
+	}
+
+	if version < 50 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS corp_buyback_rules (
+				user_id     TEXT PRIMARY KEY,
+				rules_json  TEXT NOT NULL,
+				updated_at  TEXT NOT NULL
+			);
+
+			CREATE TABLE IF NOT EXISTS corp_buyback_quotes (
+				id                INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id           TEXT NOT NULL,
+				corporation_id    INTEGER NOT NULL DEFAULT 0,
+				seller_id         INTEGER NOT NULL DEFAULT 0,
+				seller_name       TEXT NOT NULL DEFAULT '',
+				total             REAL NOT NULL,
+				source            TEXT NOT NULL DEFAULT '',
+				status            TEXT NOT NULL DEFAULT 'quoted',
+				quote_json        TEXT NOT NULL,
+				created_at        TEXT NOT NULL,
+				accepted_at       TEXT NOT NULL DEFAULT '',
+				journal_division  INTEGER NOT NULL DEFAULT 0,
+				journal_entry_id  INTEGER NOT NULL DEFAULT 0,
+				reconciled_at     TEXT NOT NULL DEFAULT ''
+			);
+			CREATE INDEX IF NOT EXISTS idx_corp_buyback_quotes_user ON corp_buyback_quotes(user_id, status, id);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (50);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v50: %w", err)
+		}
+		logger.Info("DB", "Applied migration v50 (corp buyback rules and quotes)")
+	}
+
+	if version < 51 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS corp_dashboard_snapshots (
+				user_id         TEXT NOT NULL,
+				corporation_id  INTEGER NOT NULL,
+				date            TEXT NOT NULL,
+				captured        INTEGER NOT NULL DEFAULT 0,
+				total_balance   REAL NOT NULL DEFAULT 0,
+				members         INTEGER NOT NULL DEFAULT 0,
+				active_members  INTEGER NOT NULL DEFAULT 0,
+				has_pnl         INTEGER NOT NULL DEFAULT 0,
+				revenue         REAL NOT NULL DEFAULT 0,
+				expenses        REAL NOT NULL DEFAULT 0,
+				net_income      REAL NOT NULL DEFAULT 0,
+				transactions    INTEGER NOT NULL DEFAULT 0,
+				updated_at      TEXT NOT NULL,
+				PRIMARY KEY (user_id, corporation_id, date)
+			);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (51);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v51: %w", err)
+		}
+		logger.Info("DB", "Applied migration v51 (corp dashboard snapshots)")
+	}
+
+	if version < 52 {
+		if err := d.ensureTableColumn("watchlist", "group_name", "TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("migration v52 add watchlist.group_name: %w", err)
+		}
+		_, err := d.sql.Exec(`
+			CREATE INDEX IF NOT EXISTS idx_watchlist_user_group ON watchlist(user_id, group_name);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (52);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v52: %w", err)
+		}
+		logger.Info("DB", "Applied migration v52 (watchlist groups)")
+	}
+
+	if version < 53 {
+		for _, col := range []string{"target_buy_below", "target_sell_above"} {
+			if err := d.ensureTableColumn("watchlist", col, "REAL NOT NULL DEFAULT 0"); err != nil {
+				return fmt.Errorf("migration v53 add watchlist.%s: %w", col, err)
+			}
+		}
+		if _, err := d.sql.Exec(`INSERT OR IGNORE INTO schema_version (version) VALUES (53);`); err != nil {
+			return fmt.Errorf("migration v53: %w", err)
+		}
+		logger.Info("DB", "Applied migration v53 (watchlist price bands)")
+	}
+
+	if version < 54 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS speculation_baskets (
+				id               INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id          TEXT NOT NULL,
+				name             TEXT NOT NULL,
+				region_id        INTEGER NOT NULL,
+				alert_pct        REAL NOT NULL DEFAULT 0,
+				items_json       TEXT NOT NULL,
+				created_at       TEXT NOT NULL,
+				updated_at       TEXT NOT NULL,
+				last_alert_date  TEXT NOT NULL DEFAULT ''
+			);
+			CREATE INDEX IF NOT EXISTS idx_speculation_baskets_user ON speculation_baskets(user_id, id);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (54);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v54: %w", err)
+		}
+		logger.Info("DB", "Applied migration v54 (speculation baskets)")
+	}
+
+	if version < 55 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS competitor_watch (
+				user_id      TEXT NOT NULL,
+				type_id      INTEGER NOT NULL,
+				location_id  INTEGER NOT NULL,
+				region_id    INTEGER NOT NULL,
+				created_at   TEXT NOT NULL,
+				PRIMARY KEY (user_id, type_id, location_id)
+			);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (55);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v55: %w", err)
+		}
+		logger.Info("DB", "Applied migration v55 (competitor book watches)")
+	}
+
+	if version < 56 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS impact_coefficients (
+				region_id         INTEGER NOT NULL,
+				type_id           INTEGER NOT NULL,
+				amihud            REAL NOT NULL,
+				sigma             REAL NOT NULL,
+				avg_daily_volume  REAL NOT NULL,
+				range_slope       REAL NOT NULL,
+				range_fit_r2      REAL NOT NULL,
+				days_used         INTEGER NOT NULL,
+				calibrated_at     TEXT NOT NULL,
+				PRIMARY KEY (region_id, type_id)
+			);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (56);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v56: %w", err)
+		}
+		logger.Info("DB", "Applied migration v56 (impact coefficients)")
+	}
+
+	if version < 57 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS station_fees (
+				user_id             TEXT NOT NULL,
+				location_id         INTEGER NOT NULL,
+				location_name       TEXT NOT NULL DEFAULT '',
+				broker_fee_percent  REAL NOT NULL,
+				updated_at          TEXT NOT NULL,
+				PRIMARY KEY (user_id, location_id)
+			);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (57);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v57: %w", err)
+		}
+		logger.Info("DB", "Applied migration v57 (station fee overrides)")
+	}
+
+	if version < 58 {
+		_, err := d.sql.Exec(`
+			CREATE TABLE IF NOT EXISTS ship_profiles (
+				id             INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id        TEXT NOT NULL,
+				name           TEXT NOT NULL,
+				ship_class     TEXT NOT NULL DEFAULT '',
+				cargo_m3       REAL NOT NULL DEFAULT 0,
+				align_seconds  REAL NOT NULL DEFAULT 0,
+				warp_speed_au  REAL NOT NULL DEFAULT 0,
+				ehp            REAL NOT NULL DEFAULT 0,
+				updated_at     TEXT NOT NULL
+			);
+			CREATE INDEX IF NOT EXISTS idx_ship_profiles_user ON ship_profiles(user_id, id);
+
+			INSERT OR IGNORE INTO schema_version (version) VALUES (58);
+		`)
+		if err != nil {
+			return fmt.Errorf("migration v58: %w", err)
+		}
+		logger.Info("DB", "Applied migration v58 (ship profiles)")
+	}
+
+	if version < 59 {
+		if err := d.ensureTableColumn("station_fees", "access_fee_isk", "REAL NOT NULL DEFAULT 0"); err != nil {
+			return fmt.Errorf("migration v59 add station_fees.access_fee_isk: %w", err)
+		}
+		if err := d.migrateStructureFeesToStationFees(); err != nil {
+			return fmt.Errorf("migration v59: %w", err)
+		}
+		if _, err := d.sql.Exec(`INSERT OR IGNORE INTO schema_version (version) VALUES (59);`); err != nil {
+			return fmt.Errorf("migration v59: %w", err)
+		}
+		logger.Info("DB", "Applied migration v59 (structure fees moved to station fees)")
+	}
+
 	return nil
 }
 
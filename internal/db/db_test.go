@@ -777,6 +777,63 @@ func TestDB_WatchlistAlertSettingsRoundTrip(t *testing.T) {
 	}
 }
 
+func TestDB_WatchlistGroupsAndBulkDelete(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	for _, it := range []config.WatchlistItem{
+		{TypeID: 34, TypeName: "Tritanium", AddedAt: "2026-02-13T00:00:00Z", Group: "Minerals"},
+		{TypeID: 35, TypeName: "Pyerite", AddedAt: "2026-02-13T00:00:01Z"},
+		{TypeID: 36, TypeName: "Mexallon", AddedAt: "2026-02-13T00:00:02Z"},
+	} {
+		if !d.AddWatchlistItemForUser("alice", it) {
+			t.Fatalf("add %d failed", it.TypeID)
+		}
+	}
+	if n, err := d.SetWatchlistGroupForUser("alice", []int32{35, 36, 999}, "Minerals"); err != nil || n != 2 {
+		t.Fatalf("set group = %d, %v", n, err)
+	}
+	if n, err := d.RenameWatchlistGroupForUser("alice", "Minerals", "Ore"); err != nil || n != 3 {
+		t.Fatalf("rename = %d, %v", n, err)
+	}
+	if n, _ := d.RenameWatchlistGroupForUser("bob", "Ore", "Stolen"); n != 0 {
+		t.Fatalf("bob renamed alice's group")
+	}
+	if n, err := d.DeleteWatchlistItemsForUser("alice", []int32{34, 36}); err != nil || n != 2 {
+		t.Fatalf("bulk delete = %d, %v", n, err)
+	}
+	items := d.GetWatchlistForUser("alice")
+	if len(items) != 1 || items[0].TypeID != 35 || items[0].Group != "Ore" {
+		t.Fatalf("items = %+v", items)
+	}
+}
+
+func TestDB_WatchlistPriceBands(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	d.AddWatchlistItemForUser("alice", config.WatchlistItem{TypeID: 34, TypeName: "Tritanium", TargetBuyBelow: 3.5})
+	d.AddWatchlistItemForUser("bob", config.WatchlistItem{TypeID: 34, TypeName: "Tritanium"})
+	if err := d.UpdateWatchlistPriceBandForUser("bob", 34, -1, 6); err != nil {
+		t.Fatal(err)
+	}
+	items := d.GetWatchlistForUser("bob")
+	if len(items) != 1 || items[0].TargetBuyBelow != 0 || items[0].TargetSellAbove != 6 {
+		t.Fatalf("bob = %+v", items)
+	}
+	if items := d.GetWatchlistForUser("alice"); items[0].TargetBuyBelow != 3.5 {
+		t.Fatalf("alice = %+v", items)
+	}
+	users, err := d.ListWatchlistPriceBandUserIDs()
+	if err != nil || len(users) != 2 {
+		t.Fatalf("users = %v, %v", users, err)
+	}
+	d.UpdateWatchlistPriceBandForUser("alice", 34, 0, 0)
+	if users, _ := d.ListWatchlistPriceBandUserIDs(); len(users) != 1 || users[0] != "bob" {
+		t.Fatalf("users after clearing alice = %v", users)
+	}
+}
+
 func TestDB_UserScopedDataIsolation(t *testing.T) {
 	d := openTestDB(t)
 	defer d.Close()
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"log"
+	"time"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/metrics"
+)
+
+// pageCacheRetention bounds how long unrevalidated order pages are kept.
+// Market pages change within minutes, so older ETags rarely still match.
+const pageCacheRetention = 24 * time.Hour
+
+// GetPage loads a cached ESI market order page by request URL.
+func (d *DB) GetPage(url string) (esi.CachedPage, bool) {
+	defer metrics.ObserveDBQuery("get_esi_page", time.Now())
+	var (
+		page               esi.CachedPage
+		expires, fetchedAt string
+		body               []byte
+	)
+	err := d.sql.QueryRow(
+		"SELECT url, etag, expires_at, pages, body, fetched_at FROM esi_page_cache WHERE url = ?", url,
+	).Scan(&page.URL, &page.ETag, &expires, &page.Pages, &body, &fetchedAt)
+	if err != nil {
+		return esi.CachedPage{}, false
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		log.Printf("[DB] GetPage %s: %v", url, err)
+		return esi.CachedPage{}, false
+	}
+	page.Body, err = io.ReadAll(zr)
+	if err != nil {
+		log.Printf("[DB] GetPage %s: %v", url, err)
+		return esi.CachedPage{}, false
+	}
+	page.Expires, _ = time.Parse(time.RFC3339, expires)
+	page.FetchedAt, _ = time.Parse(time.RFC3339, fetchedAt)
+	return page, true
+}
+
+// SetPage stores a page body (gzip-compressed) with its ETag and expiry.
+func (d *DB) SetPage(page esi.CachedPage) {
+	defer metrics.ObserveDBQuery("set_esi_page", time.Now())
+	if page.URL == "" || page.ETag == "" || len(page.Body) == 0 {
+		return
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(page.Body); err != nil {
+		log.Printf("[DB] SetPage: %v", err)
+		return
+	}
+	if err := zw.Close(); err != nil {
+		log.Printf("[DB] SetPage: %v", err)
+		return
+	}
+	fetchedAt := page.FetchedAt
+	if fetchedAt.IsZero() {
+		fetchedAt = time.Now()
+	}
+	_, err := d.sql.Exec(
+		"INSERT OR REPLACE INTO esi_page_cache (url, etag, expires_at, pages, body, fetched_at) VALUES (?, ?, ?, ?, ?, ?)",
+		page.URL, page.ETag, utcRFC3339(page.Expires), page.Pages, buf.Bytes(), utcRFC3339(fetchedAt),
+	)
+	if err != nil {
+		log.Printf("[DB] SetPage: %v", err)
+	}
+}
+
+// TouchPage extends a cached page after ESI answered 304 Not Modified.
+func (d *DB) TouchPage(url string, expires time.Time, fetchedAt time.Time) {
+	_, err := d.sql.Exec(
+		"UPDATE esi_page_cache SET expires_at = ?, fetched_at = ? WHERE url = ?",
+		utcRFC3339(expires), utcRFC3339(fetchedAt), url,
+	)
+	if err != nil {
+		log.Printf("[DB] TouchPage: %v", err)
+	}
+}
+
+// CleanupPageCache removes order pages not revalidated within the retention window.
+func (d *DB) CleanupPageCache() (int64, error) {
+	cutoff := time.Now().UTC().Add(-pageCacheRetention).Format(time.RFC3339)
+	res, err := d.sql.Exec("DELETE FROM esi_page_cache WHERE fetched_at < ?", cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+func TestPageCache_RoundTripTouchAndCleanup(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	const url = "https://esi.evetech.net/latest/markets/10000002/orders/?order_type=sell&page=1"
+	expires := time.Now().UTC().Add(5 * time.Minute).Truncate(time.Second)
+	body := []byte(`[{"order_id":1,"type_id":34}]`)
+	d.SetPage(esi.CachedPage{URL: url, ETag: `"abc"`, Expires: expires, Pages: 3, Body: body})
+
+	got, ok := d.GetPage(url)
+	if !ok || got.ETag != `"abc"` || got.Pages != 3 || string(got.Body) != string(body) || !got.Expires.Equal(expires) {
+		t.Fatalf("GetPage = %+v, %v", got, ok)
+	}
+
+	later := expires.Add(5 * time.Minute)
+	d.TouchPage(url, later, time.Now())
+	if got, _ := d.GetPage(url); !got.Expires.Equal(later) || string(got.Body) != string(body) {
+		t.Fatalf("after TouchPage = %+v", got)
+	}
+
+	d.TouchPage(url, later, time.Now().Add(-pageCacheRetention-time.Hour))
+	if removed, err := d.CleanupPageCache(); err != nil || removed != 1 {
+		t.Fatalf("CleanupPageCache = %d, %v; want 1", removed, err)
+	}
+	if _, ok := d.GetPage(url); ok {
+		t.Fatal("page still cached after cleanup")
+	}
+}
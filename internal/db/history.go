@@ -3,6 +3,8 @@ package db
 import (
 	"encoding/json"
 	"time"
+
+	"eve-flipper/internal/metrics"
 )
 
 // ScanRecord represents a scan history entry.
@@ -33,6 +35,7 @@ func (d *DB) InsertHistory(tab, system string, count int, topProfit float64) int
 
 // InsertHistoryFull inserts a scan history record with all fields.
 func (d *DB) InsertHistoryFull(tab, system string, count int, topProfit, totalProfit float64, durationMs int64, params interface{}) int64 {
+	defer metrics.ObserveDBQuery("insert_scan_history", time.Now())
 	paramsJSON, _ := json.Marshal(params)
 	result, err := d.sql.Exec(
 		"INSERT INTO scan_history (timestamp, tab, system, count, top_profit, total_profit, duration_ms, params_json) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
@@ -47,6 +50,7 @@ func (d *DB) InsertHistoryFull(tab, system string, count int, topProfit, totalPr
 
 // GetHistory returns the last N scan history records (newest first).
 func (d *DB) GetHistory(limit int) []ScanRecord {
+	defer metrics.ObserveDBQuery("get_scan_history", time.Now())
 	if limit <= 0 {
 		limit = 50
 	}
@@ -92,6 +96,26 @@ func (d *DB) GetHistoryByID(id int64) *ScanRecord {
 	return &r
 }
 
+// LatestHistoryIDByTab returns the newest scan history ID for any of the given
+// tabs, or 0 when there is none.
+func (d *DB) LatestHistoryIDByTab(tabs ...string) int64 {
+	if len(tabs) == 0 {
+		return 0
+	}
+	args := make([]interface{}, len(tabs))
+	for i, tab := range tabs {
+		args[i] = tab
+	}
+	var id int64
+	if err := d.sql.QueryRow(
+		"SELECT id FROM scan_history WHERE tab IN ("+placeholders(len(tabs))+") ORDER BY id DESC LIMIT 1",
+		args...,
+	).Scan(&id); err != nil {
+		return 0
+	}
+	return id
+}
+
 // DeleteHistory deletes a scan history record and its associated results.
 func (d *DB) DeleteHistory(id int64) error {
 	tx, err := d.sql.Begin()
@@ -103,6 +127,8 @@ func (d *DB) DeleteHistory(id int64) error {
 	tx.Exec("DELETE FROM contract_results WHERE scan_id = ?", id)
 	tx.Exec("DELETE FROM station_results WHERE scan_id = ?", id)
 	tx.Exec("DELETE FROM route_results WHERE scan_id = ?", id)
+	tx.Exec("DELETE FROM scan_accuracy_checks WHERE scan_id = ?", id)
+	tx.Exec("DELETE FROM scan_accuracy_runs WHERE scan_id = ?", id)
 	tx.Exec("DELETE FROM scan_history WHERE id = ?", id)
 	return tx.Commit()
 }
@@ -138,6 +164,8 @@ func (d *DB) ClearHistory(olderThanDays int) (int64, error) {
 		tx.Exec("DELETE FROM contract_results WHERE scan_id = ?", id)
 		tx.Exec("DELETE FROM station_results WHERE scan_id = ?", id)
 		tx.Exec("DELETE FROM route_results WHERE scan_id = ?", id)
+		tx.Exec("DELETE FROM scan_accuracy_checks WHERE scan_id = ?", id)
+		tx.Exec("DELETE FROM scan_accuracy_runs WHERE scan_id = ?", id)
 	}
 	result, err := tx.Exec("DELETE FROM scan_history WHERE timestamp < ?", cutoff)
 	if err != nil {
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"time"
+
+	"eve-flipper/internal/engine"
+)
+
+// SaveImpactCoefficients stores the fitted impact parameters of a type in a
+// region, replacing any earlier fit.
+func (d *DB) SaveImpactCoefficients(regionID, typeID int32, p engine.ImpactParams, at time.Time) error {
+	_, err := d.sql.Exec(`
+		INSERT OR REPLACE INTO impact_coefficients
+			(region_id, type_id, amihud, sigma, avg_daily_volume, range_slope, range_fit_r2, days_used, calibrated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, regionID, typeID, p.Amihud, p.Sigma, p.AvgDailyVolume, p.RangeSlope, p.RangeFitR2, p.DaysUsed,
+		at.UTC().Format(time.RFC3339))
+	return err
+}
+
+// GetImpactCoefficients returns the stored fit of a type in a region, or
+// false if it was never calibrated.
+func (d *DB) GetImpactCoefficients(regionID, typeID int32) (engine.ImpactParams, bool) {
+	var p engine.ImpactParams
+	err := d.sql.QueryRow(`
+		SELECT amihud, sigma, avg_daily_volume, range_slope, range_fit_r2, days_used, calibrated_at
+		FROM impact_coefficients WHERE region_id = ? AND type_id = ?
+	`, regionID, typeID).Scan(&p.Amihud, &p.Sigma, &p.AvgDailyVolume, &p.RangeSlope, &p.RangeFitR2, &p.DaysUsed, &p.CalibratedAt)
+	if err != nil {
+		return engine.ImpactParams{}, false
+	}
+	p.SigmaSq = p.Sigma * p.Sigma
+	p.Valid = true
+	return p, true
+}
+
+// PruneImpactCoefficients removes fits made before the cutoff and returns
+// how many were removed.
+func (d *DB) PruneImpactCoefficients(before time.Time) (int64, error) {
+	res, err := d.sql.Exec(`DELETE FROM impact_coefficients WHERE calibrated_at < ?`, before.UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"eve-flipper/internal/engine"
+	"eve-flipper/internal/esi"
+)
+
+func TestImpactCoefficientsRoundTripAndPrune(t *testing.T) {
+	d := openTestDB(t)
+
+	if _, ok := d.GetImpactCoefficients(10000002, 34); ok {
+		t.Fatalf("expected no coefficients before calibration")
+	}
+	at := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	in := engine.ImpactParams{Amihud: 1e-6, Sigma: 0.03, AvgDailyVolume: 5000, RangeSlope: 0.04, RangeFitR2: 0.8, DaysUsed: 90}
+	if err := d.SaveImpactCoefficients(10000002, 34, in, at); err != nil {
+		t.Fatalf("SaveImpactCoefficients: %v", err)
+	}
+	got, ok := d.GetImpactCoefficients(10000002, 34)
+	if !ok || !got.Valid {
+		t.Fatalf("GetImpactCoefficients = %+v, %v", got, ok)
+	}
+	if got.RangeSlope != 0.04 || got.AvgDailyVolume != 5000 || got.DaysUsed != 90 || got.CalibratedAt != "2026-05-01T12:00:00Z" {
+		t.Fatalf("round trip = %+v", got)
+	}
+
+	n, err := d.PruneImpactCoefficients(at.Add(time.Hour))
+	if err != nil || n != 1 {
+		t.Fatalf("PruneImpactCoefficients = %d, %v; want 1", n, err)
+	}
+	if _, ok := d.GetImpactCoefficients(10000002, 34); ok {
+		t.Fatalf("expected coefficients pruned")
+	}
+}
+
+func TestListMarketHistoryKeysAndStoredHistory(t *testing.T) {
+	d := openTestDB(t)
+	today := time.Now().UTC().Format("2006-01-02")
+	d.SetMarketHistory(10000002, 34, []esi.HistoryEntry{{Date: today, Average: 5, Highest: 6, Lowest: 4, Volume: 100}})
+
+	keys, err := d.ListMarketHistoryKeys(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListMarketHistoryKeys: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != (MarketHistoryKey{RegionID: 10000002, TypeID: 34}) {
+		t.Fatalf("keys = %+v", keys)
+	}
+	if keys, _ := d.ListMarketHistoryKeys(time.Now().Add(time.Hour)); len(keys) != 0 {
+		t.Fatalf("expected no keys refreshed in the future, got %+v", keys)
+	}
+	if got := d.StoredMarketHistory(10000002, 34); len(got) != 1 || got[0].Volume != 100 {
+		t.Fatalf("StoredMarketHistory = %+v", got)
+	}
+}
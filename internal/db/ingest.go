@@ -0,0 +1,169 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"eve-flipper/internal/corp"
+)
+
+// Wallet and journal pulls overlap heavily: ESI returns the same recent rows
+// on every request. Rows are keyed by their ESI IDs and only rewritten when
+// something changed, so repeated pulls never duplicate or churn rows. Each
+// ingest source also keeps a watermark (the highest row ID stored); ESI row
+// IDs grow monotonically, so rows above it are the new ones.
+const (
+	IngestSourceWalletTransactions = "wallet_transactions"
+	IngestSourceWalletJournal      = "wallet_journal"
+	IngestSourceCorpTransactions   = "corp_transactions"
+	IngestSourceCorpJournal        = "corp_journal"
+)
+
+// IngestWatermark is the ingest position of one source: a character's
+// wallet or a corporation wallet division.
+type IngestWatermark struct {
+	Source   string `json:"source"`
+	ScopeID  int64  `json:"scope_id"` // character or corporation ID
+	Division int    `json:"division"` // corp wallet division, 0 for characters
+	LastID   int64  `json:"last_id"`
+	NewRows  int    `json:"new_rows"` // rows above the previous watermark in the last sync
+	SyncedAt string `json:"synced_at"`
+}
+
+func ingestWatermarkTx(tx *sql.Tx, userID, source string, scopeID int64, division int) (int64, error) {
+	var lastID int64
+	err := tx.QueryRow(`
+		SELECT last_id FROM ingest_watermarks
+		WHERE user_id = ? AND source = ? AND scope_id = ? AND division = ?
+	`, userID, source, scopeID, division).Scan(&lastID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return lastID, err
+}
+
+func saveIngestWatermarkTx(tx *sql.Tx, userID, source string, scopeID int64, division int, lastID int64, newRows int, now string) error {
+	_, err := tx.Exec(`
+		INSERT INTO ingest_watermarks (user_id, source, scope_id, division, last_id, new_rows, synced_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, source, scope_id, division) DO UPDATE SET
+			last_id = MAX(ingest_watermarks.last_id, excluded.last_id),
+			new_rows = excluded.new_rows,
+			synced_at = excluded.synced_at
+	`, userID, source, scopeID, division, lastID, newRows, now)
+	return err
+}
+
+// ListIngestWatermarksForUser returns the user's ingest positions.
+func (d *DB) ListIngestWatermarksForUser(userID string) ([]IngestWatermark, error) {
+	rows, err := d.sql.Query(`
+		SELECT source, scope_id, division, last_id, new_rows, synced_at
+		FROM ingest_watermarks WHERE user_id = ?
+		ORDER BY source, scope_id, division
+	`, normalizeUserID(userID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []IngestWatermark{}
+	for rows.Next() {
+		var w IngestWatermark
+		if err := rows.Scan(&w.Source, &w.ScopeID, &w.Division, &w.LastID, &w.NewRows, &w.SyncedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+// latestIngestSync returns the newest sync time of source for scopeID across
+// divisions, or the zero time if it was never synced.
+func (d *DB) latestIngestSync(userID, source string, scopeID int64) (time.Time, error) {
+	var last string
+	if err := d.sql.QueryRow(`
+		SELECT COALESCE(MAX(synced_at), '') FROM ingest_watermarks
+		WHERE user_id = ? AND source = ? AND scope_id = ?
+	`, userID, source, scopeID).Scan(&last); err != nil {
+		return time.Time{}, err
+	}
+	if last == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, last)
+}
+
+// UpsertCorpJournalForUser stores one division's journal entries from the
+// corp provider and returns how many are new since the last ingest.
+func (d *DB) UpsertCorpJournalForUser(userID string, corporationID int32, division int, entries []corp.CorpJournalEntry) (int, error) {
+	userID = normalizeUserID(userID)
+	if corporationID <= 0 || division < 1 || division > 7 {
+		return 0, fmt.Errorf("invalid corp journal archive scope")
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	tx, err := d.sql.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	watermark, err := ingestWatermarkTx(tx, userID, IngestSourceCorpJournal, int64(corporationID), division)
+	if err != nil {
+		return 0, err
+	}
+	stmt, err := tx.Prepare(`
+		INSERT INTO corp_journal (
+			user_id, corporation_id, division, entry_id, date, ref_type, amount, balance,
+			description, first_party_id, first_party_name, second_party_id, second_party_name,
+			first_seen_at, last_seen_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, corporation_id, division, entry_id) DO UPDATE SET
+			date = excluded.date,
+			ref_type = excluded.ref_type,
+			amount = excluded.amount,
+			balance = excluded.balance,
+			description = excluded.description,
+			first_party_id = excluded.first_party_id,
+			second_party_id = excluded.second_party_id,
+			first_party_name = CASE WHEN excluded.first_party_name != '' THEN excluded.first_party_name ELSE corp_journal.first_party_name END,
+			second_party_name = CASE WHEN excluded.second_party_name != '' THEN excluded.second_party_name ELSE corp_journal.second_party_name END,
+			last_seen_at = excluded.last_seen_at
+		WHERE corp_journal.date IS NOT excluded.date
+		   OR corp_journal.ref_type IS NOT excluded.ref_type
+		   OR corp_journal.amount IS NOT excluded.amount
+		   OR corp_journal.balance IS NOT excluded.balance
+		   OR corp_journal.description IS NOT excluded.description
+		   OR corp_journal.first_party_id IS NOT excluded.first_party_id
+		   OR corp_journal.second_party_id IS NOT excluded.second_party_id
+		   OR (excluded.first_party_name != '' AND corp_journal.first_party_name IS NOT excluded.first_party_name)
+		   OR (excluded.second_party_name != '' AND corp_journal.second_party_name IS NOT excluded.second_party_name)
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	lastID, newRows := watermark, 0
+	for _, e := range entries {
+		if e.ID == 0 || strings.TrimSpace(e.Date) == "" {
+			continue
+		}
+		if e.ID > watermark {
+			newRows++
+			lastID = max(lastID, e.ID)
+		}
+		if _, err := stmt.Exec(
+			userID, corporationID, division, e.ID, e.Date, e.RefType, e.Amount, e.Balance,
+			e.Description, e.FirstPartyID, e.FirstPartyName, e.SecondPartyID, e.SecondPartyName,
+			now, now,
+		); err != nil {
+			return 0, err
+		}
+	}
+	if err := saveIngestWatermarkTx(tx, userID, IngestSourceCorpJournal, int64(corporationID), division, lastID, newRows, now); err != nil {
+		return 0, err
+	}
+	return newRows, tx.Commit()
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"testing"
+
+	"eve-flipper/internal/corp"
+	"eve-flipper/internal/esi"
+)
+
+func TestUpsertCorpJournalIsIdempotentAndTracksWatermark(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	userID := "corp-journal-user"
+	corpID := int32(98000042)
+	first := []corp.CorpJournalEntry{
+		{ID: 10, Date: "2026-05-01T10:00:00Z", RefType: "bounty_prizes", Amount: 1000, FirstPartyID: 7},
+		{ID: 11, Date: "2026-05-01T11:00:00Z", RefType: "market_transaction", Amount: -50, FirstPartyID: 8, FirstPartyName: "Bob"},
+	}
+	n, err := d.UpsertCorpJournalForUser(userID, corpID, 1, first)
+	if err != nil || n != 2 {
+		t.Fatalf("first ingest = %d, %v; want 2 new", n, err)
+	}
+
+	// The next pull overlaps: entry 10 now has its party name, 12 is new.
+	n, err = d.UpsertCorpJournalForUser(userID, corpID, 1, []corp.CorpJournalEntry{
+		{ID: 12, Date: "2026-05-01T12:00:00Z", RefType: "bounty_prizes", Amount: 500},
+		{ID: 11, Date: "2026-05-01T11:00:00Z", RefType: "market_transaction", Amount: -50, FirstPartyID: 8, FirstPartyName: "Bob"},
+		{ID: 10, Date: "2026-05-01T10:00:00Z", RefType: "bounty_prizes", Amount: 1000, FirstPartyID: 7, FirstPartyName: "Alice"},
+	})
+	if err != nil || n != 1 {
+		t.Fatalf("second ingest = %d, %v; want 1 new", n, err)
+	}
+
+	var rows int
+	var name string
+	if err := d.sql.QueryRow(`SELECT COUNT(*) FROM corp_journal WHERE user_id = ?`, userID).Scan(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.sql.QueryRow(`SELECT first_party_name FROM corp_journal WHERE user_id = ? AND entry_id = 10`, userID).Scan(&name); err != nil {
+		t.Fatal(err)
+	}
+	if rows != 3 || name != "Alice" {
+		t.Fatalf("rows = %d name = %q, want 3 rows and backfilled name", rows, name)
+	}
+
+	marks, err := d.ListIngestWatermarksForUser(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(marks) != 1 || marks[0].Source != IngestSourceCorpJournal || marks[0].LastID != 12 || marks[0].NewRows != 1 {
+		t.Fatalf("watermarks = %+v", marks)
+	}
+}
+
+func TestUpsertWalletTransactionsCountsNewRows(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	userID := "wallet-ingest-user"
+	page := []esi.WalletTransaction{
+		{TransactionID: 100, Date: "2026-05-01T10:00:00Z", TypeID: 34, Quantity: 10, UnitPrice: 5},
+		{TransactionID: 101, Date: "2026-05-01T11:00:00Z", TypeID: 35, Quantity: 5, UnitPrice: 9},
+	}
+	stats, err := d.UpsertWalletTransactionsForUser(userID, 9001, page)
+	if err != nil || stats.NewRows != 2 {
+		t.Fatalf("first ingest = %+v, %v; want 2 new", stats, err)
+	}
+	stats, err = d.UpsertWalletTransactionsForUser(userID, 9001, page)
+	if err != nil || stats.NewRows != 0 {
+		t.Fatalf("repeat ingest = %+v, %v; want 0 new", stats, err)
+	}
+
+	// A row below the watermark that an earlier partial pull missed is
+	// still stored.
+	stats, err = d.UpsertWalletTransactionsForUser(userID, 9001, append(page,
+		esi.WalletTransaction{TransactionID: 99, Date: "2026-05-01T09:00:00Z", TypeID: 36, Quantity: 1, UnitPrice: 50}))
+	if err != nil || stats.NewRows != 0 {
+		t.Fatalf("backfill ingest = %+v, %v; want 0 new", stats, err)
+	}
+	var rows int
+	if err := d.sql.QueryRow(`SELECT COUNT(*) FROM wallet_transactions_archive WHERE user_id = ?`, userID).Scan(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if rows != 3 {
+		t.Fatalf("rows = %d, want 3", rows)
+	}
+}
@@ -69,6 +69,24 @@ func (d *DB) CleanupStartupCaches() {
 		}
 	}
 
+	if removed, err := d.CleanupNameCache(); err != nil {
+		log.Printf("[DB] CleanupStartupCaches: name cache cleanup error: %v", err)
+	} else if removed > 0 {
+		log.Printf("[DB] CleanupStartupCaches: removed %d stale cached names", removed)
+	}
+
+	if removed, err := d.CleanupPageCache(); err != nil {
+		log.Printf("[DB] CleanupStartupCaches: ESI page cache cleanup error: %v", err)
+	} else if removed > 0 {
+		log.Printf("[DB] CleanupStartupCaches: removed %d stale ESI order pages", removed)
+	}
+
+	if removed, err := d.CleanupUsageEvents(); err != nil {
+		log.Printf("[DB] CleanupStartupCaches: usage events cleanup error: %v", err)
+	} else if removed > 0 {
+		log.Printf("[DB] CleanupStartupCaches: removed %d old usage events", removed)
+	}
+
 	if _, err := d.sql.Exec(`PRAGMA wal_checkpoint(TRUNCATE)`); err != nil {
 		log.Printf("[DB] CleanupStartupCaches: wal checkpoint error: %v", err)
 	}
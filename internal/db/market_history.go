@@ -5,11 +5,19 @@ import (
 	"time"
 
 	"eve-flipper/internal/esi"
+	"eve-flipper/internal/metrics"
 )
 
 // GetHistory retrieves cached market history for a region/type pair.
 // Returns nil, false if not cached or if cache is older than 24 hours.
 func (d *DB) GetMarketHistory(regionID int32, typeID int32) ([]esi.HistoryEntry, bool) {
+	defer metrics.ObserveDBQuery("get_market_history", time.Now())
+	entries, ok := d.loadMarketHistory(regionID, typeID)
+	metrics.ObserveCache("market_history", ok)
+	return entries, ok
+}
+
+func (d *DB) loadMarketHistory(regionID int32, typeID int32) ([]esi.HistoryEntry, bool) {
 	var updatedAt string
 	err := d.sql.QueryRow(
 		"SELECT updated_at FROM market_history_meta WHERE region_id=? AND type_id=?",
@@ -25,12 +33,22 @@ func (d *DB) GetMarketHistory(regionID int32, typeID int32) ([]esi.HistoryEntry,
 		return nil, false
 	}
 
+	entries := d.StoredMarketHistory(regionID, typeID)
+	if len(entries) == 0 {
+		return nil, false
+	}
+	return entries, true
+}
+
+// StoredMarketHistory returns the cached history rows for a region/type pair
+// regardless of cache age.
+func (d *DB) StoredMarketHistory(regionID int32, typeID int32) []esi.HistoryEntry {
 	rows, err := d.sql.Query(
 		"SELECT date, average, highest, lowest, volume, order_count FROM market_history WHERE region_id=? AND type_id=? ORDER BY date",
 		regionID, typeID,
 	)
 	if err != nil {
-		return nil, false
+		return nil
 	}
 	defer rows.Close()
 
@@ -42,15 +60,42 @@ func (d *DB) GetMarketHistory(regionID int32, typeID int32) ([]esi.HistoryEntry,
 		}
 		entries = append(entries, e)
 	}
-	if len(entries) == 0 {
-		return nil, false
+	return entries
+}
+
+// MarketHistoryKey is one cached region/type history series.
+type MarketHistoryKey struct {
+	RegionID int32
+	TypeID   int32
+}
+
+// ListMarketHistoryKeys returns the region/type pairs with cached history
+// refreshed since the given time.
+func (d *DB) ListMarketHistoryKeys(since time.Time) ([]MarketHistoryKey, error) {
+	rows, err := d.sql.Query(
+		"SELECT region_id, type_id FROM market_history_meta WHERE updated_at >= ? ORDER BY region_id, type_id",
+		since.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return nil, err
 	}
-	return entries, true
+	defer rows.Close()
+
+	var keys []MarketHistoryKey
+	for rows.Next() {
+		var k MarketHistoryKey
+		if err := rows.Scan(&k.RegionID, &k.TypeID); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
 }
 
 // SetMarketHistory stores market history entries in the cache.
 // Only entries from the last 90 days are stored to bound database growth.
 func (d *DB) SetMarketHistory(regionID int32, typeID int32, entries []esi.HistoryEntry) {
+	defer metrics.ObserveDBQuery("set_market_history", time.Now())
 	tx, err := d.sql.Begin()
 	if err != nil {
 		return
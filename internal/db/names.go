@@ -0,0 +1,86 @@
+package db
+
+import (
+	"log"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+// nameCacheRetention bounds how long stale names are kept before cleanup.
+const nameCacheRetention = 30 * 24 * time.Hour
+
+// GetNames loads cached id→name mappings updated within maxAge.
+func (d *DB) GetNames(ids []int64, maxAge time.Duration) map[int64]esi.ResolvedName {
+	out := make(map[int64]esi.ResolvedName, len(ids))
+	cutoff := time.Now().UTC().Add(-maxAge).Format(time.RFC3339)
+	// Stay well below SQLite's bound-parameter limit.
+	const chunk = 500
+	for start := 0; start < len(ids); start += chunk {
+		batch := ids[start:min(start+chunk, len(ids))]
+		args := make([]interface{}, 0, len(batch)+1)
+		for _, id := range batch {
+			args = append(args, id)
+		}
+		args = append(args, cutoff)
+		rows, err := d.sql.Query(
+			"SELECT id, name, category FROM name_cache WHERE id IN ("+placeholders(len(batch))+") AND updated_at >= ?",
+			args...,
+		)
+		if err != nil {
+			log.Printf("[DB] GetNames: %v", err)
+			return out
+		}
+		for rows.Next() {
+			var n esi.ResolvedName
+			if err := rows.Scan(&n.ID, &n.Name, &n.Category); err == nil {
+				out[n.ID] = n
+			}
+		}
+		rows.Close()
+	}
+	return out
+}
+
+// SetNames upserts resolved names with the current time.
+func (d *DB) SetNames(names []esi.ResolvedName) {
+	if len(names) == 0 {
+		return
+	}
+	tx, err := d.sql.Begin()
+	if err != nil {
+		log.Printf("[DB] SetNames: %v", err)
+		return
+	}
+	stmt, err := tx.Prepare("INSERT OR REPLACE INTO name_cache (id, name, category, updated_at) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		log.Printf("[DB] SetNames: %v", err)
+		return
+	}
+	defer stmt.Close()
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, n := range names {
+		if n.ID <= 0 || n.Name == "" {
+			continue
+		}
+		if _, err := stmt.Exec(n.ID, n.Name, n.Category, now); err != nil {
+			tx.Rollback()
+			log.Printf("[DB] SetNames: %v", err)
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("[DB] SetNames: %v", err)
+	}
+}
+
+// CleanupNameCache removes names not refreshed within the retention window.
+func (d *DB) CleanupNameCache() (int64, error) {
+	cutoff := time.Now().UTC().Add(-nameCacheRetention).Format(time.RFC3339)
+	res, err := d.sql.Exec("DELETE FROM name_cache WHERE updated_at < ?", cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
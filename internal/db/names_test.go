@@ -0,0 +1,39 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+func TestNameCache_RoundTripAndTTL(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	d.SetNames([]esi.ResolvedName{
+		{ID: 90000001, Name: "Pilot One", Category: "character"},
+		{ID: 98000001, Name: "Some Corp", Category: "corporation"},
+		{ID: 5, Name: ""}, // ignored
+	})
+	got := d.GetNames([]int64{90000001, 98000001, 5, 7}, time.Hour)
+	if len(got) != 2 || got[90000001].Name != "Pilot One" || got[98000001].Category != "corporation" {
+		t.Fatalf("GetNames = %+v", got)
+	}
+
+	if _, err := d.sql.Exec("UPDATE name_cache SET updated_at = ? WHERE id = ?",
+		time.Now().UTC().Add(-2*time.Hour).Format(time.RFC3339), 90000001); err != nil {
+		t.Fatal(err)
+	}
+	if got := d.GetNames([]int64{90000001}, time.Hour); len(got) != 0 {
+		t.Errorf("stale name returned: %+v", got)
+	}
+
+	if _, err := d.sql.Exec("UPDATE name_cache SET updated_at = ? WHERE id = ?",
+		time.Now().UTC().Add(-nameCacheRetention-time.Hour).Format(time.RFC3339), 90000001); err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := d.CleanupNameCache(); err != nil || removed != 1 {
+		t.Errorf("CleanupNameCache = %d, %v; want 1", removed, err)
+	}
+}
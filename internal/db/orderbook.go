@@ -553,6 +553,62 @@ func (d *DB) ListOrderBookReplayBooks(filter OrderBookReplayFilter) ([]OrderBook
 	return out, nil
 }
 
+// OrderBookTop is the best price of one side of a location's book in one
+// snapshot.
+type OrderBookTop struct {
+	SnapshotID int64     `json:"snapshot_id"`
+	CapturedAt time.Time `json:"captured_at"`
+	Price      float64   `json:"price"`
+	OrderCount int       `json:"order_count"` // orders at the best price
+}
+
+// ListOrderBookTops returns the best sell (lowest) or buy (highest) price of
+// a type at a location in every stored snapshot since from, oldest first.
+// Identical books are stored once, so consecutive rows differ somewhere in
+// the snapshot, though not necessarily at this location's top.
+func (d *DB) ListOrderBookTops(typeID int32, locationID int64, side string, from time.Time) ([]OrderBookTop, error) {
+	side = normalizeOrderBookSide(side)
+	if d == nil || d.sql == nil || typeID <= 0 || locationID <= 0 || side == "" {
+		return nil, nil
+	}
+	best := "MIN(l.price)"
+	if side == "buy" {
+		best = "MAX(l.price)"
+	}
+	rows, err := d.sql.Query(`
+		SELECT s.id, s.captured_at, `+best+` AS best,
+		       (SELECT SUM(b.order_count) FROM orderbook_levels b
+		         WHERE b.snapshot_id = s.id AND b.type_id = l.type_id AND b.location_id = l.location_id
+		           AND b.side = l.side AND b.price = `+best+`)
+		  FROM orderbook_snapshots s
+		  JOIN orderbook_levels l ON l.snapshot_id = s.id
+		 WHERE l.type_id = ? AND l.location_id = ? AND l.side = ?
+		   AND (s.order_type = 'all' OR s.order_type = ?)
+		   AND s.captured_at >= ?
+		 GROUP BY s.id
+		 ORDER BY s.captured_at ASC, s.id ASC
+		 LIMIT 5000
+	`, typeID, locationID, side, side, utcRFC3339(from))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []OrderBookTop{}
+	for rows.Next() {
+		var top OrderBookTop
+		var capturedAt string
+		var count sql.NullInt64
+		if err := rows.Scan(&top.SnapshotID, &capturedAt, &top.Price, &count); err != nil {
+			return nil, err
+		}
+		top.CapturedAt, _ = time.Parse(time.RFC3339, capturedAt)
+		top.OrderCount = int(count.Int64)
+		out = append(out, top)
+	}
+	return out, rows.Err()
+}
+
 func nullableStringValue(v sql.NullString) string {
 	if v.Valid {
 		return v.String
@@ -341,3 +341,40 @@ func TestCleanupOrderBookSnapshotsBatches(t *testing.T) {
 		t.Fatalf("stats after cleanup batches = %#v, want only fresh snapshot", stats)
 	}
 }
+
+func TestListOrderBookTops(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	const jita = 60003760
+	t0 := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	record := func(at time.Time, orders ...esi.MarketOrder) {
+		t.Helper()
+		if err := d.RecordMarketOrderSnapshot(esi.MarketOrderSnapshot{
+			RegionID: 10000002, OrderType: "all", Source: "region_type", TypeID: 34, CapturedAt: at, Orders: orders,
+		}); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+	record(t0,
+		esi.MarketOrder{OrderID: 1, TypeID: 34, LocationID: jita, Price: 5.00, VolumeRemain: 10},
+		esi.MarketOrder{OrderID: 2, TypeID: 34, LocationID: jita, Price: 5.00, VolumeRemain: 10},
+		esi.MarketOrder{OrderID: 3, TypeID: 34, LocationID: jita, Price: 4.00, VolumeRemain: 10, IsBuyOrder: true},
+		esi.MarketOrder{OrderID: 4, TypeID: 34, LocationID: 1, Price: 3.00, VolumeRemain: 10})
+	record(t0.Add(10*time.Minute),
+		esi.MarketOrder{OrderID: 1, TypeID: 34, LocationID: jita, Price: 5.00, VolumeRemain: 10},
+		esi.MarketOrder{OrderID: 2, TypeID: 34, LocationID: jita, Price: 4.99, VolumeRemain: 10},
+		esi.MarketOrder{OrderID: 3, TypeID: 34, LocationID: jita, Price: 4.01, VolumeRemain: 10, IsBuyOrder: true})
+
+	sells, err := d.ListOrderBookTops(34, jita, "sell", t0)
+	if err != nil || len(sells) != 2 {
+		t.Fatalf("sell tops = %+v, %v", sells, err)
+	}
+	if sells[0].Price != 5 || sells[0].OrderCount != 2 || sells[1].Price != 4.99 || sells[1].OrderCount != 1 || !sells[1].CapturedAt.Equal(t0.Add(10*time.Minute)) {
+		t.Fatalf("sell tops = %+v", sells)
+	}
+	buys, err := d.ListOrderBookTops(34, jita, "buy", t0.Add(time.Minute))
+	if err != nil || len(buys) != 1 || buys[0].Price != 4.01 {
+		t.Fatalf("buy tops = %+v, %v", buys, err)
+	}
+}
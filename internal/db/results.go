@@ -3,12 +3,15 @@ package db
 import (
 	"encoding/json"
 	"eve-flipper/internal/engine"
+	"eve-flipper/internal/metrics"
 	"log"
 	"strings"
+	"time"
 )
 
 // InsertFlipResults bulk-inserts flip results linked to a scan history record.
 func (d *DB) InsertFlipResults(scanID int64, results []engine.FlipResult) {
+	defer metrics.ObserveDBQuery("insert_flip_results", time.Now())
 	if scanID == 0 || len(results) == 0 {
 		return
 	}
@@ -80,6 +83,7 @@ func (d *DB) InsertFlipResults(scanID int64, results []engine.FlipResult) {
 
 // GetFlipResults retrieves flip results for a scan.
 func (d *DB) GetFlipResults(scanID int64) []engine.FlipResult {
+	defer metrics.ObserveDBQuery("get_flip_results", time.Now())
 	rows, err := d.sql.Query(`
 		SELECT type_id, type_name, volume,
 			buy_price, best_ask_price, best_ask_qty, buy_station, buy_system_name, buy_system_id,
@@ -141,6 +145,7 @@ func (d *DB) GetFlipResults(scanID int64) []engine.FlipResult {
 
 // InsertContractResults bulk-inserts contract results linked to a scan history record.
 func (d *DB) InsertContractResults(scanID int64, results []engine.ContractResult) {
+	defer metrics.ObserveDBQuery("insert_contract_results", time.Now())
 	if scanID == 0 || len(results) == 0 {
 		return
 	}
@@ -188,6 +193,7 @@ func (d *DB) InsertContractResults(scanID int64, results []engine.ContractResult
 
 // GetContractResults retrieves contract results for a scan.
 func (d *DB) GetContractResults(scanID int64) []engine.ContractResult {
+	defer metrics.ObserveDBQuery("get_contract_results", time.Now())
 	rows, err := d.sql.Query(`
 		SELECT contract_id, title, price, market_value,
 			profit, margin_percent, expected_profit, expected_margin_percent,
@@ -223,6 +229,7 @@ func (d *DB) GetContractResults(scanID int64) []engine.ContractResult {
 
 // InsertStationResults bulk-inserts station trading results.
 func (d *DB) InsertStationResults(scanID int64, results []engine.StationTrade) {
+	defer metrics.ObserveDBQuery("insert_station_results", time.Now())
 	if scanID == 0 || len(results) == 0 {
 		return
 	}
@@ -306,6 +313,7 @@ func (d *DB) InsertStationResults(scanID int64, results []engine.StationTrade) {
 
 // GetStationResults retrieves station trading results for a scan.
 func (d *DB) GetStationResults(scanID int64) []engine.StationTrade {
+	defer metrics.ObserveDBQuery("get_station_results", time.Now())
 	rows, err := d.sql.Query(`
 		SELECT type_id, type_name, buy_price, sell_price,
 			margin, margin_pct,
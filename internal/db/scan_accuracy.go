@@ -0,0 +1,153 @@
+package db
+
+import (
+	"sort"
+	"time"
+)
+
+// ScanAccuracyCheck records whether a persisted scan result's spread was still
+// there when prices were re-checked some hours after the scan.
+type ScanAccuracyCheck struct {
+	TypeID         int32   `json:"type_id"`
+	BuySystemID    int32   `json:"buy_system_id"`
+	SellSystemID   int32   `json:"sell_system_id"`
+	ScannedAt      string  `json:"scanned_at"`
+	ScanBuyPrice   float64 `json:"scan_buy_price"`
+	ScanSellPrice  float64 `json:"scan_sell_price"`
+	NowBuyPrice    float64 `json:"now_buy_price"`
+	NowSellPrice   float64 `json:"now_sell_price"`
+	SpreadHeld     bool    `json:"spread_held"`
+	SpreadRetained float64 `json:"spread_retained"` // now spread / scan spread, 0 when gone
+}
+
+// ScanModeAccuracy aggregates accuracy checks for one scan mode (history tab).
+type ScanModeAccuracy struct {
+	Tab            string  `json:"tab"`
+	Scans          int     `json:"scans"`
+	Checks         int     `json:"checks"`
+	Held           int     `json:"held"`
+	AccuracyPct    float64 `json:"accuracy_pct"`     // Held / Checks × 100
+	AvgRetainedPct float64 `json:"avg_retained_pct"` // mean spread retained × 100
+	LastCheckedAt  string  `json:"last_checked_at"`
+}
+
+// PendingAccuracyScans returns scans of the given tabs taken between
+// notBefore and dueBy that have not been evaluated yet, oldest first.
+func (d *DB) PendingAccuracyScans(tabs []string, notBefore, dueBy time.Time, limit int) []ScanRecord {
+	if len(tabs) == 0 {
+		return nil
+	}
+	args := make([]interface{}, 0, len(tabs))
+	for _, tab := range tabs {
+		args = append(args, tab)
+	}
+	rows, err := d.sql.Query(`
+		SELECT id, timestamp, tab, system, count, top_profit
+		FROM scan_history
+		WHERE tab IN (`+placeholders(len(tabs))+`)
+		  AND count > 0
+		  AND id NOT IN (SELECT scan_id FROM scan_accuracy_runs)
+		ORDER BY id DESC LIMIT 500`,
+		args...,
+	)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var out []ScanRecord
+	for rows.Next() {
+		var r ScanRecord
+		if err := rows.Scan(&r.ID, &r.Timestamp, &r.Tab, &r.System, &r.Count, &r.TopProfit); err != nil {
+			continue
+		}
+		// Timestamps carry the local offset, so compare parsed times.
+		ts, err := time.Parse(time.RFC3339, r.Timestamp)
+		if err != nil || ts.Before(notBefore) || ts.After(dueBy) {
+			continue
+		}
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// SaveScanAccuracyChecks stores the checks for a scan and marks it evaluated,
+// even when checks is empty, so it is not picked up again.
+func (d *DB) SaveScanAccuracyChecks(scanID int64, tab string, checks []ScanAccuracyCheck) error {
+	tx, err := d.sql.Begin()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, c := range checks {
+		held := 0
+		if c.SpreadHeld {
+			held = 1
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO scan_accuracy_checks (
+				scan_id, tab, type_id, buy_system_id, sell_system_id, scanned_at, checked_at,
+				scan_buy_price, scan_sell_price, now_buy_price, now_sell_price, spread_held, spread_retained
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			scanID, tab, c.TypeID, c.BuySystemID, c.SellSystemID, c.ScannedAt, now,
+			c.ScanBuyPrice, c.ScanSellPrice, c.NowBuyPrice, c.NowSellPrice, held, c.SpreadRetained,
+		); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if _, err := tx.Exec(
+		"INSERT OR REPLACE INTO scan_accuracy_runs (scan_id, tab, checked_at) VALUES (?, ?, ?)",
+		scanID, tab, now,
+	); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetScanAccuracy aggregates accuracy checks made in the last days by scan
+// mode, most checked modes first.
+func (d *DB) GetScanAccuracy(days int) []ScanModeAccuracy {
+	if days <= 0 {
+		days = 30
+	}
+	cutoff := time.Now().UTC().AddDate(0, 0, -days).Format(time.RFC3339)
+	rows, err := d.sql.Query(`
+		SELECT tab, COUNT(DISTINCT scan_id), COUNT(*), SUM(spread_held),
+		       AVG(spread_retained), MAX(checked_at)
+		FROM scan_accuracy_checks
+		WHERE checked_at >= ?
+		GROUP BY tab`,
+		cutoff,
+	)
+	if err != nil {
+		return []ScanModeAccuracy{}
+	}
+	defer rows.Close()
+
+	out := []ScanModeAccuracy{}
+	for rows.Next() {
+		var m ScanModeAccuracy
+		var avgRetained float64
+		if err := rows.Scan(&m.Tab, &m.Scans, &m.Checks, &m.Held, &avgRetained, &m.LastCheckedAt); err != nil {
+			continue
+		}
+		if m.Checks > 0 {
+			m.AccuracyPct = float64(m.Held) / float64(m.Checks) * 100
+		}
+		m.AvgRetainedPct = avgRetained * 100
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Checks != out[j].Checks {
+			return out[i].Checks > out[j].Checks
+		}
+		return out[i].Tab < out[j].Tab
+	})
+	return out
+}
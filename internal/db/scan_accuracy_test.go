@@ -0,0 +1,54 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScanAccuracy_PendingSaveAndAggregate(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	old := d.InsertHistory("radius", "Jita", 3, 100)
+	stations := d.InsertHistory("station", "Jita", 3, 100)
+	if old == 0 || stations == 0 {
+		t.Fatal("InsertHistory failed")
+	}
+
+	now := time.Now()
+	// Scans are "due" once they are older than dueBy.
+	if got := d.PendingAccuracyScans([]string{"radius"}, now.Add(-time.Hour), now.Add(-time.Minute), 10); len(got) != 0 {
+		t.Fatalf("scan not yet due returned: %+v", got)
+	}
+	pending := d.PendingAccuracyScans([]string{"radius"}, now.Add(-time.Hour), now.Add(time.Minute), 10)
+	if len(pending) != 1 || pending[0].ID != old {
+		t.Fatalf("pending = %+v, want scan %d", pending, old)
+	}
+
+	checks := []ScanAccuracyCheck{
+		{TypeID: 34, ScanBuyPrice: 5, ScanSellPrice: 6, NowBuyPrice: 5, NowSellPrice: 6, SpreadHeld: true, SpreadRetained: 1},
+		{TypeID: 35, ScanBuyPrice: 5, ScanSellPrice: 6},
+	}
+	if err := d.SaveScanAccuracyChecks(old, "radius", checks); err != nil {
+		t.Fatalf("SaveScanAccuracyChecks: %v", err)
+	}
+	if got := d.PendingAccuracyScans([]string{"radius"}, now.Add(-time.Hour), now.Add(time.Minute), 10); len(got) != 0 {
+		t.Fatalf("evaluated scan still pending: %+v", got)
+	}
+
+	modes := d.GetScanAccuracy(30)
+	if len(modes) != 1 {
+		t.Fatalf("modes = %+v", modes)
+	}
+	m := modes[0]
+	if m.Tab != "radius" || m.Scans != 1 || m.Checks != 2 || m.Held != 1 || m.AccuracyPct != 50 || m.AvgRetainedPct != 50 {
+		t.Fatalf("accuracy = %+v", m)
+	}
+
+	if err := d.DeleteHistory(old); err != nil {
+		t.Fatalf("DeleteHistory: %v", err)
+	}
+	if got := d.GetScanAccuracy(30); len(got) != 0 {
+		t.Fatalf("checks survived history delete: %+v", got)
+	}
+}
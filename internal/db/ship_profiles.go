@@ -0,0 +1,107 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ShipProfile is a hauler a user flies: hold size, travel speed and tank.
+// ShipClass is the hull class the profile was based on; EHP 0 means the
+// tank is unknown.
+type ShipProfile struct {
+	ID           int64   `json:"id"`
+	Name         string  `json:"name"`
+	ShipClass    string  `json:"ship_class"`
+	CargoM3      float64 `json:"cargo_m3"`
+	AlignSeconds float64 `json:"align_seconds"`
+	WarpSpeedAU  float64 `json:"warp_speed_au"`
+	EHP          float64 `json:"ehp"`
+	UpdatedAt    string  `json:"updated_at"`
+}
+
+const shipProfileColumns = `id, name, ship_class, cargo_m3, align_seconds, warp_speed_au, ehp, updated_at`
+
+func scanShipProfile(row interface{ Scan(...interface{}) error }) (ShipProfile, error) {
+	var p ShipProfile
+	err := row.Scan(&p.ID, &p.Name, &p.ShipClass, &p.CargoM3, &p.AlignSeconds, &p.WarpSpeedAU, &p.EHP, &p.UpdatedAt)
+	return p, err
+}
+
+// ListShipProfilesForUser returns the user's ship profiles, oldest first.
+func (d *DB) ListShipProfilesForUser(userID string) ([]ShipProfile, error) {
+	userID = normalizeUserID(userID)
+	rows, err := d.sql.Query(`SELECT `+shipProfileColumns+` FROM ship_profiles WHERE user_id = ? ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []ShipProfile{}
+	for rows.Next() {
+		p, err := scanShipProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// GetShipProfileForUser returns one profile, or nil if the user has no
+// profile with that ID.
+func (d *DB) GetShipProfileForUser(userID string, id int64) (*ShipProfile, error) {
+	userID = normalizeUserID(userID)
+	p, err := scanShipProfile(d.sql.QueryRow(
+		`SELECT `+shipProfileColumns+` FROM ship_profiles WHERE user_id = ? AND id = ?`, userID, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// SaveShipProfileForUser inserts the profile when its ID is 0 and updates it
+// otherwise, returning the stored row. Updating a profile the user does not
+// own returns (nil, nil).
+func (d *DB) SaveShipProfileForUser(userID string, p ShipProfile) (*ShipProfile, error) {
+	userID = normalizeUserID(userID)
+	now := time.Now().UTC().Format(time.RFC3339)
+	if p.ID == 0 {
+		res, err := d.sql.Exec(`
+			INSERT INTO ship_profiles (user_id, name, ship_class, cargo_m3, align_seconds, warp_speed_au, ehp, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, userID, p.Name, p.ShipClass, p.CargoM3, p.AlignSeconds, p.WarpSpeedAU, p.EHP, now)
+		if err != nil {
+			return nil, err
+		}
+		if p.ID, err = res.LastInsertId(); err != nil {
+			return nil, err
+		}
+	} else {
+		res, err := d.sql.Exec(`
+			UPDATE ship_profiles
+			SET name = ?, ship_class = ?, cargo_m3 = ?, align_seconds = ?, warp_speed_au = ?, ehp = ?, updated_at = ?
+			WHERE user_id = ? AND id = ?
+		`, p.Name, p.ShipClass, p.CargoM3, p.AlignSeconds, p.WarpSpeedAU, p.EHP, now, userID, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return nil, err
+		}
+	}
+	return d.GetShipProfileForUser(userID, p.ID)
+}
+
+// DeleteShipProfileForUser removes a profile. It reports false if the user
+// has no profile with that ID.
+func (d *DB) DeleteShipProfileForUser(userID string, id int64) (bool, error) {
+	userID = normalizeUserID(userID)
+	res, err := d.sql.Exec(`DELETE FROM ship_profiles WHERE user_id = ? AND id = ?`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,42 @@
+package db
+
+import "testing"
+
+func TestShipProfilesCRUD(t *testing.T) {
+	d := openTestDB(t)
+
+	dst, err := d.SaveShipProfileForUser("u1", ShipProfile{Name: "Impel", ShipClass: "deep_space_transport", CargoM3: 62_500, EHP: 180_000})
+	if err != nil || dst == nil || dst.ID == 0 || dst.UpdatedAt == "" {
+		t.Fatalf("SaveShipProfileForUser = %+v, %v", dst, err)
+	}
+	if _, err := d.SaveShipProfileForUser("u1", ShipProfile{Name: "Sunesis", ShipClass: "sunesis", CargoM3: 1_800, AlignSeconds: 3.2}); err != nil {
+		t.Fatalf("SaveShipProfileForUser: %v", err)
+	}
+
+	dst.CargoM3 = 65_000
+	updated, err := d.SaveShipProfileForUser("u1", *dst)
+	if err != nil || updated == nil || updated.CargoM3 != 65_000 || updated.EHP != 180_000 {
+		t.Fatalf("update = %+v, %v", updated, err)
+	}
+	if other, err := d.SaveShipProfileForUser("u2", *dst); err != nil || other != nil {
+		t.Fatalf("updating another user's profile = %+v, %v; want nil, nil", other, err)
+	}
+	if got, _ := d.GetShipProfileForUser("u2", dst.ID); got != nil {
+		t.Fatalf("other user should not see the profile: %+v", got)
+	}
+
+	profiles, err := d.ListShipProfilesForUser("u1")
+	if err != nil {
+		t.Fatalf("ListShipProfilesForUser: %v", err)
+	}
+	if len(profiles) != 2 || profiles[0].Name != "Impel" || profiles[1].AlignSeconds != 3.2 {
+		t.Fatalf("profiles = %+v", profiles)
+	}
+
+	if ok, err := d.DeleteShipProfileForUser("u1", dst.ID); err != nil || !ok {
+		t.Fatalf("DeleteShipProfileForUser = %v, %v", ok, err)
+	}
+	if ok, _ := d.DeleteShipProfileForUser("u1", dst.ID); ok {
+		t.Fatalf("second delete should report false")
+	}
+}
@@ -0,0 +1,158 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SpeculationBasketItem is one tracked type of a basket.
+type SpeculationBasketItem struct {
+	TypeID   int32  `json:"type_id"`
+	TypeName string `json:"type_name"`
+	Quantity int64  `json:"quantity"`
+}
+
+// SpeculationBasket is a user-tagged set of items whose combined price is
+// tracked in one region. AlertPercent > 0 alerts on a daily move at least
+// that large.
+type SpeculationBasket struct {
+	ID            int64                   `json:"id"`
+	Name          string                  `json:"name"`
+	RegionID      int32                   `json:"region_id"`
+	AlertPercent  float64                 `json:"alert_percent"`
+	Items         []SpeculationBasketItem `json:"items"`
+	CreatedAt     string                  `json:"created_at"`
+	UpdatedAt     string                  `json:"updated_at"`
+	LastAlertDate string                  `json:"last_alert_date"`
+}
+
+const speculationBasketColumns = `id, name, region_id, alert_pct, items_json, created_at, updated_at, last_alert_date`
+
+func scanSpeculationBasket(row interface{ Scan(...interface{}) error }) (SpeculationBasket, error) {
+	var b SpeculationBasket
+	var itemsJSON string
+	if err := row.Scan(&b.ID, &b.Name, &b.RegionID, &b.AlertPercent, &itemsJSON, &b.CreatedAt, &b.UpdatedAt, &b.LastAlertDate); err != nil {
+		return b, err
+	}
+	if err := json.Unmarshal([]byte(itemsJSON), &b.Items); err != nil || b.Items == nil {
+		b.Items = []SpeculationBasketItem{}
+	}
+	return b, nil
+}
+
+// ListSpeculationBasketsForUser returns the user's baskets, oldest first.
+func (d *DB) ListSpeculationBasketsForUser(userID string) ([]SpeculationBasket, error) {
+	userID = normalizeUserID(userID)
+	rows, err := d.sql.Query(`SELECT `+speculationBasketColumns+` FROM speculation_baskets WHERE user_id = ? ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []SpeculationBasket{}
+	for rows.Next() {
+		b, err := scanSpeculationBasket(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+// GetSpeculationBasketForUser returns one basket, or nil if the user has no
+// basket with that ID.
+func (d *DB) GetSpeculationBasketForUser(userID string, id int64) (*SpeculationBasket, error) {
+	userID = normalizeUserID(userID)
+	b, err := scanSpeculationBasket(d.sql.QueryRow(
+		`SELECT `+speculationBasketColumns+` FROM speculation_baskets WHERE user_id = ? AND id = ?`, userID, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// SaveSpeculationBasketForUser inserts the basket when its ID is 0 and
+// updates it otherwise, returning the stored row. Updating a basket the
+// user does not own returns (nil, nil).
+func (d *DB) SaveSpeculationBasketForUser(userID string, b SpeculationBasket) (*SpeculationBasket, error) {
+	userID = normalizeUserID(userID)
+	if b.Name == "" || b.RegionID <= 0 {
+		return nil, fmt.Errorf("name and region are required")
+	}
+	if b.Items == nil {
+		b.Items = []SpeculationBasketItem{}
+	}
+	itemsJSON, err := json.Marshal(b.Items)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	if b.ID == 0 {
+		res, err := d.sql.Exec(`
+			INSERT INTO speculation_baskets (user_id, name, region_id, alert_pct, items_json, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, userID, b.Name, b.RegionID, b.AlertPercent, string(itemsJSON), now, now)
+		if err != nil {
+			return nil, err
+		}
+		if b.ID, err = res.LastInsertId(); err != nil {
+			return nil, err
+		}
+	} else {
+		res, err := d.sql.Exec(`
+			UPDATE speculation_baskets
+			SET name = ?, region_id = ?, alert_pct = ?, items_json = ?, updated_at = ?
+			WHERE user_id = ? AND id = ?
+		`, b.Name, b.RegionID, b.AlertPercent, string(itemsJSON), now, userID, b.ID)
+		if err != nil {
+			return nil, err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return nil, err
+		}
+	}
+	return d.GetSpeculationBasketForUser(userID, b.ID)
+}
+
+// DeleteSpeculationBasketForUser removes a basket. It reports false if the
+// user has no basket with that ID.
+func (d *DB) DeleteSpeculationBasketForUser(userID string, id int64) (bool, error) {
+	userID = normalizeUserID(userID)
+	res, err := d.sql.Exec(`DELETE FROM speculation_baskets WHERE user_id = ? AND id = ?`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// MarkSpeculationBasketAlerted records the history date a basket last
+// alerted for, so each daily move alerts once.
+func (d *DB) MarkSpeculationBasketAlerted(id int64, date string) error {
+	_, err := d.sql.Exec(`UPDATE speculation_baskets SET last_alert_date = ? WHERE id = ?`, date, id)
+	return err
+}
+
+// ListSpeculationBasketAlertUserIDs returns the users with at least one
+// basket that has a move alert set.
+func (d *DB) ListSpeculationBasketAlertUserIDs() ([]string, error) {
+	rows, err := d.sql.Query(`SELECT DISTINCT user_id FROM speculation_baskets WHERE alert_pct > 0 ORDER BY user_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
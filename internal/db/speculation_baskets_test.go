@@ -0,0 +1,47 @@
+package db
+
+import "testing"
+
+func TestSpeculationBaskets_CRUD(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	b, err := d.SaveSpeculationBasketForUser("alice", SpeculationBasket{
+		Name:     "Faction BS hulls",
+		RegionID: 10000002,
+		Items:    []SpeculationBasketItem{{TypeID: 17738, TypeName: "Machariel", Quantity: 1}},
+	})
+	if err != nil || b == nil || b.ID == 0 || len(b.Items) != 1 {
+		t.Fatalf("insert = %+v, %v", b, err)
+	}
+
+	b.AlertPercent = 10
+	b.Items = append(b.Items, SpeculationBasketItem{TypeID: 17736, TypeName: "Nightmare", Quantity: 2})
+	if got, err := d.SaveSpeculationBasketForUser("alice", *b); err != nil || got == nil || got.AlertPercent != 10 || len(got.Items) != 2 {
+		t.Fatalf("update = %+v, %v", got, err)
+	}
+	if got, err := d.SaveSpeculationBasketForUser("bob", *b); err != nil || got != nil {
+		t.Fatalf("foreign update = %+v, %v", got, err)
+	}
+
+	if ids, err := d.ListSpeculationBasketAlertUserIDs(); err != nil || len(ids) != 1 || ids[0] != "alice" {
+		t.Fatalf("alert users = %v, %v", ids, err)
+	}
+	if err := d.MarkSpeculationBasketAlerted(b.ID, "2026-06-01"); err != nil {
+		t.Fatalf("mark: %v", err)
+	}
+	list, err := d.ListSpeculationBasketsForUser("alice")
+	if err != nil || len(list) != 1 || list[0].LastAlertDate != "2026-06-01" {
+		t.Fatalf("list = %+v, %v", list, err)
+	}
+
+	if ok, _ := d.DeleteSpeculationBasketForUser("bob", b.ID); ok {
+		t.Fatal("bob deleted alice's basket")
+	}
+	if ok, err := d.DeleteSpeculationBasketForUser("alice", b.ID); err != nil || !ok {
+		t.Fatalf("delete = %v, %v", ok, err)
+	}
+	if got, err := d.GetSpeculationBasketForUser("alice", b.ID); err != nil || got != nil {
+		t.Fatalf("get after delete = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// StationFee is the broker fee a user recorded at a station or structure.
+type StationFee struct {
+	LocationID       int64   `json:"location_id"`
+	LocationName     string  `json:"location_name"`
+	BrokerFeePercent float64 `json:"broker_fee_percent"`
+	AccessFeeISK     float64 `json:"access_fee_isk"` // per visit to a structure, 0 = none
+	UpdatedAt        string  `json:"updated_at"`
+}
+
+// ListStationFeesForUser returns the user's recorded fees by location name.
+func (d *DB) ListStationFeesForUser(userID string) ([]StationFee, error) {
+	userID = normalizeUserID(userID)
+	rows, err := d.sql.Query(`
+		SELECT location_id, location_name, broker_fee_percent, access_fee_isk, updated_at
+		FROM station_fees WHERE user_id = ? ORDER BY location_name, location_id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []StationFee{}
+	for rows.Next() {
+		var f StationFee
+		if err := rows.Scan(&f.LocationID, &f.LocationName, &f.BrokerFeePercent, &f.AccessFeeISK, &f.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
+// SaveStationFeeForUser records or replaces the user's fee at a location.
+func (d *DB) SaveStationFeeForUser(userID string, f StationFee) (StationFee, error) {
+	userID = normalizeUserID(userID)
+	f.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	_, err := d.sql.Exec(`
+		INSERT INTO station_fees (user_id, location_id, location_name, broker_fee_percent, access_fee_isk, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, location_id) DO UPDATE SET
+			location_name = excluded.location_name,
+			broker_fee_percent = excluded.broker_fee_percent,
+			access_fee_isk = excluded.access_fee_isk,
+			updated_at = excluded.updated_at
+	`, userID, f.LocationID, f.LocationName, f.BrokerFeePercent, f.AccessFeeISK, f.UpdatedAt)
+	return f, err
+}
+
+// DeleteStationFeeForUser removes the user's fee at a location. It reports
+// false if none was recorded.
+func (d *DB) DeleteStationFeeForUser(userID string, locationID int64) (bool, error) {
+	userID = normalizeUserID(userID)
+	res, err := d.sql.Exec(`DELETE FROM station_fees WHERE user_id = ? AND location_id = ?`, userID, locationID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// migrateStructureFeesToStationFees moves the structure fees users kept in
+// the structure_fees config key into station_fees, so recorded fees have
+// one home. A fee already recorded for the same location wins.
+func (d *DB) migrateStructureFeesToStationFees() error {
+	rows, err := d.sql.Query(`SELECT user_id, value FROM config WHERE key = 'structure_fees'`)
+	if err != nil {
+		return err
+	}
+	type legacyFee struct {
+		StructureID      int64   `json:"structure_id"`
+		Name             string  `json:"name"`
+		BrokerFeePercent float64 `json:"broker_fee_percent"`
+		AccessFeeISK     float64 `json:"access_fee_isk"`
+	}
+	byUser := make(map[string][]legacyFee)
+	for rows.Next() {
+		var userID, value string
+		if err := rows.Scan(&userID, &value); err != nil {
+			rows.Close()
+			return err
+		}
+		var fees []legacyFee
+		if json.Unmarshal([]byte(value), &fees) == nil {
+			byUser[userID] = fees
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	tx, err := d.sql.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	now := time.Now().UTC().Format(time.RFC3339)
+	for userID, fees := range byUser {
+		for _, f := range fees {
+			if f.StructureID <= 0 {
+				continue
+			}
+			if _, err := tx.Exec(`
+				INSERT OR IGNORE INTO station_fees (user_id, location_id, location_name, broker_fee_percent, access_fee_isk, updated_at)
+				VALUES (?, ?, ?, ?, ?, ?)
+			`, userID, f.StructureID, f.Name, f.BrokerFeePercent, max(0, f.AccessFeeISK), now); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM config WHERE key = 'structure_fees'`); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,72 @@
+package db
+
+import "testing"
+
+func TestStationFeesCRUD(t *testing.T) {
+	d := openTestDB(t)
+
+	if _, err := d.SaveStationFeeForUser("u1", StationFee{LocationID: 60003760, LocationName: "Jita IV - Moon 4", BrokerFeePercent: 1.2}); err != nil {
+		t.Fatalf("SaveStationFeeForUser: %v", err)
+	}
+	if _, err := d.SaveStationFeeForUser("u1", StationFee{LocationID: 1_035_466_617_946, LocationName: "Perimeter - Tranquility", BrokerFeePercent: 0.5}); err != nil {
+		t.Fatalf("SaveStationFeeForUser: %v", err)
+	}
+	if _, err := d.SaveStationFeeForUser("u1", StationFee{LocationID: 60003760, LocationName: "Jita IV - Moon 4", BrokerFeePercent: 1.1}); err != nil {
+		t.Fatalf("SaveStationFeeForUser update: %v", err)
+	}
+	if _, err := d.SaveStationFeeForUser("u2", StationFee{LocationID: 60003760, BrokerFeePercent: 3}); err != nil {
+		t.Fatalf("SaveStationFeeForUser other user: %v", err)
+	}
+
+	fees, err := d.ListStationFeesForUser("u1")
+	if err != nil {
+		t.Fatalf("ListStationFeesForUser: %v", err)
+	}
+	if len(fees) != 2 || fees[0].LocationID != 60003760 || fees[0].BrokerFeePercent != 1.1 || fees[0].UpdatedAt == "" {
+		t.Fatalf("fees = %+v", fees)
+	}
+
+	if ok, err := d.DeleteStationFeeForUser("u1", 60003760); err != nil || !ok {
+		t.Fatalf("DeleteStationFeeForUser = %v, %v", ok, err)
+	}
+	if ok, _ := d.DeleteStationFeeForUser("u1", 60003760); ok {
+		t.Fatalf("second delete should report false")
+	}
+	if fees, _ := d.ListStationFeesForUser("u2"); len(fees) != 1 || fees[0].BrokerFeePercent != 3 {
+		t.Fatalf("other user's fees = %+v", fees)
+	}
+}
+
+func TestMigrateStructureFeesToStationFees(t *testing.T) {
+	d := openTestDB(t)
+	const keepstar, fortizar = int64(1_035_466_617_946), int64(1_022_734_985_679)
+
+	if _, err := d.SaveStationFeeForUser("u1", StationFee{LocationID: fortizar, BrokerFeePercent: 0.7}); err != nil {
+		t.Fatalf("SaveStationFeeForUser: %v", err)
+	}
+	if _, err := d.sql.Exec(`INSERT INTO config (user_id, key, value) VALUES (?, 'structure_fees', ?)`, "u1",
+		`[{"structure_id":1035466617946,"name":"Perimeter - Tranquility","broker_fee_percent":0.5,"access_fee_isk":100000},
+		  {"structure_id":1022734985679,"broker_fee_percent":2}]`); err != nil {
+		t.Fatalf("insert legacy config: %v", err)
+	}
+	if err := d.migrateStructureFeesToStationFees(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	fees, err := d.ListStationFeesForUser("u1")
+	if err != nil || len(fees) != 2 {
+		t.Fatalf("fees = %+v, %v", fees, err)
+	}
+	byID := map[int64]StationFee{fees[0].LocationID: fees[0], fees[1].LocationID: fees[1]}
+	if f := byID[keepstar]; f.BrokerFeePercent != 0.5 || f.AccessFeeISK != 100000 || f.LocationName != "Perimeter - Tranquility" {
+		t.Fatalf("migrated fee = %+v", f)
+	}
+	if f := byID[fortizar]; f.BrokerFeePercent != 0.7 {
+		t.Fatalf("recorded fee overwritten: %+v", f)
+	}
+	var n int
+	d.sql.QueryRow(`SELECT COUNT(*) FROM config WHERE key = 'structure_fees'`).Scan(&n)
+	if n != 0 {
+		t.Fatalf("legacy config rows left: %d", n)
+	}
+}
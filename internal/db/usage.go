@@ -0,0 +1,176 @@
+package db
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Usage event categories. Usage statistics are kept in the local database
+// only and are never uploaded.
+const (
+	UsageCategoryScan   = "scan"
+	UsageCategoryAlert  = "alert"
+	UsageCategoryExport = "export"
+)
+
+const (
+	usageEventRetention  = 180 * 24 * time.Hour
+	usageTimelineLimit   = 50
+	usageFieldMaxLength  = 64
+	usageDetailMaxLength = 256
+)
+
+// UsageEvent is one recorded feature use.
+type UsageEvent struct {
+	Category  string `json:"category"`
+	Name      string `json:"name"`
+	Detail    string `json:"detail,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// UsageCount is the number of uses of one feature.
+type UsageCount struct {
+	Category string `json:"category"`
+	Name     string `json:"name"`
+	Count    int    `json:"count"`
+	LastUsed string `json:"last_used"`
+}
+
+// UsageDay is the number of events on one UTC day.
+type UsageDay struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Count int    `json:"count"`
+}
+
+// UsageStats summarizes a user's recorded usage over a window.
+type UsageStats struct {
+	Days       int            `json:"days"`
+	Total      int            `json:"total"`
+	Categories map[string]int `json:"categories"`
+	Features   []UsageCount   `json:"features"`
+	Daily      []UsageDay     `json:"daily"`
+	Hourly     [24]int        `json:"hourly"` // by UTC hour
+	Timeline   []UsageEvent   `json:"timeline"`
+}
+
+func cleanUsageField(value string, maxLen int) string {
+	value = strings.TrimSpace(value)
+	if len(value) > maxLen {
+		value = value[:maxLen]
+	}
+	return value
+}
+
+// RecordUsageEventForUser stores a feature use for userID.
+func (d *DB) RecordUsageEventForUser(userID, category, name, detail string) error {
+	category = strings.ToLower(cleanUsageField(category, usageFieldMaxLength))
+	name = strings.ToLower(cleanUsageField(name, usageFieldMaxLength))
+	if category == "" || name == "" {
+		return nil
+	}
+	_, err := d.sql.Exec(`
+		INSERT INTO usage_events (user_id, category, name, detail, created_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		normalizeUserID(userID),
+		category,
+		name,
+		cleanUsageField(detail, usageDetailMaxLength),
+		time.Now().UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// GetUsageStatsForUser aggregates userID's usage events from the last days days.
+func (d *DB) GetUsageStatsForUser(userID string, days int) (*UsageStats, error) {
+	if days <= 0 {
+		days = 30
+	}
+	since := time.Now().UTC().AddDate(0, 0, -days).Format(time.RFC3339)
+	rows, err := d.sql.Query(`
+		SELECT category, name, detail, created_at
+		FROM usage_events
+		WHERE user_id = ? AND created_at >= ?
+		ORDER BY created_at DESC, id DESC`,
+		normalizeUserID(userID), since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := &UsageStats{
+		Days:       days,
+		Categories: map[string]int{},
+		Features:   []UsageCount{},
+		Daily:      []UsageDay{},
+		Timeline:   []UsageEvent{},
+	}
+	features := make(map[[2]string]*UsageCount)
+	daily := make(map[string]int)
+	for rows.Next() {
+		var ev UsageEvent
+		if err := rows.Scan(&ev.Category, &ev.Name, &ev.Detail, &ev.CreatedAt); err != nil {
+			return nil, err
+		}
+		stats.Total++
+		stats.Categories[ev.Category]++
+		key := [2]string{ev.Category, ev.Name}
+		fc := features[key]
+		if fc == nil {
+			// Rows are newest first, so the first one seen is the last use.
+			fc = &UsageCount{Category: ev.Category, Name: ev.Name, LastUsed: ev.CreatedAt}
+			features[key] = fc
+		}
+		fc.Count++
+		if t, err := time.Parse(time.RFC3339, ev.CreatedAt); err == nil {
+			t = t.UTC()
+			daily[t.Format("2006-01-02")]++
+			stats.Hourly[t.Hour()]++
+		}
+		if len(stats.Timeline) < usageTimelineLimit {
+			stats.Timeline = append(stats.Timeline, ev)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, fc := range features {
+		stats.Features = append(stats.Features, *fc)
+	}
+	sort.Slice(stats.Features, func(i, j int) bool {
+		a, b := stats.Features[i], stats.Features[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.Name < b.Name
+	})
+	for date, n := range daily {
+		stats.Daily = append(stats.Daily, UsageDay{Date: date, Count: n})
+	}
+	sort.Slice(stats.Daily, func(i, j int) bool { return stats.Daily[i].Date < stats.Daily[j].Date })
+	return stats, nil
+}
+
+// ClearUsageEventsForUser deletes all of userID's usage events.
+func (d *DB) ClearUsageEventsForUser(userID string) (int64, error) {
+	res, err := d.sql.Exec("DELETE FROM usage_events WHERE user_id = ?", normalizeUserID(userID))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// CleanupUsageEvents removes usage events past retention.
+func (d *DB) CleanupUsageEvents() (int64, error) {
+	cutoff := time.Now().UTC().Add(-usageEventRetention).Format(time.RFC3339)
+	res, err := d.sql.Exec("DELETE FROM usage_events WHERE created_at < ?", cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUsageStats_AggregatesPerUser(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	for _, ev := range [][3]string{
+		{UsageCategoryScan, "radius", ""},
+		{UsageCategoryScan, "radius", ""},
+		{UsageCategoryScan, "station", ""},
+		{UsageCategoryAlert, "margin_percent", "Tritanium"},
+		{UsageCategoryExport, "csv", "station"},
+	} {
+		if err := d.RecordUsageEventForUser("alice", ev[0], ev[1], ev[2]); err != nil {
+			t.Fatalf("RecordUsageEventForUser: %v", err)
+		}
+	}
+	if err := d.RecordUsageEventForUser("bob", UsageCategoryScan, "route", ""); err != nil {
+		t.Fatalf("RecordUsageEventForUser: %v", err)
+	}
+
+	stats, err := d.GetUsageStatsForUser("alice", 30)
+	if err != nil {
+		t.Fatalf("GetUsageStatsForUser: %v", err)
+	}
+	if stats.Total != 5 {
+		t.Fatalf("Total = %d, want 5", stats.Total)
+	}
+	if stats.Categories[UsageCategoryScan] != 3 || stats.Categories[UsageCategoryAlert] != 1 || stats.Categories[UsageCategoryExport] != 1 {
+		t.Fatalf("Categories = %v", stats.Categories)
+	}
+	if len(stats.Features) != 4 || stats.Features[0].Name != "radius" || stats.Features[0].Count != 2 {
+		t.Fatalf("Features = %+v", stats.Features)
+	}
+	today := time.Now().UTC().Format("2006-01-02")
+	if len(stats.Daily) != 1 || stats.Daily[0].Date != today || stats.Daily[0].Count != 5 {
+		t.Fatalf("Daily = %+v", stats.Daily)
+	}
+	if len(stats.Timeline) != 5 {
+		t.Fatalf("Timeline len = %d, want 5", len(stats.Timeline))
+	}
+
+	removed, err := d.ClearUsageEventsForUser("alice")
+	if err != nil || removed != 5 {
+		t.Fatalf("ClearUsageEventsForUser = %d, %v; want 5", removed, err)
+	}
+	bob, err := d.GetUsageStatsForUser("bob", 30)
+	if err != nil || bob.Total != 1 {
+		t.Fatalf("bob stats = %+v, %v", bob, err)
+	}
+}
+
+func TestUsageEvents_CleanupDropsOldRows(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	old := time.Now().UTC().Add(-usageEventRetention - time.Hour).Format(time.RFC3339)
+	if _, err := d.sql.Exec(`INSERT INTO usage_events (user_id, category, name, created_at) VALUES ('default', 'scan', 'radius', ?)`, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.RecordUsageEventForUser("", UsageCategoryScan, "radius", ""); err != nil {
+		t.Fatal(err)
+	}
+	removed, err := d.CleanupUsageEvents()
+	if err != nil || removed != 1 {
+		t.Fatalf("CleanupUsageEvents = %d, %v; want 1", removed, err)
+	}
+}
@@ -17,6 +17,7 @@ const walletArchiveESIJournalSoftLimit = 2500
 type WalletArchiveWriteStats struct {
 	CharacterID int64
 	LiveRows    int
+	NewRows     int // rows above the previous ingest watermark
 	LimitHit    bool
 	SyncedAt    string
 }
@@ -75,6 +76,11 @@ func (d *DB) UpsertWalletTransactionsForUser(userID string, characterID int64, t
 	}
 	defer tx.Rollback()
 
+	watermark, err := ingestWatermarkTx(tx, userID, IngestSourceWalletTransactions, characterID, 0)
+	if err != nil {
+		return stats, err
+	}
+	// Unchanged rows are left alone so overlapping pulls do not rewrite them.
 	stmt, err := tx.Prepare(`
 		INSERT INTO wallet_transactions_archive (
 			user_id, character_id, transaction_id, date, type_id, location_id,
@@ -90,16 +96,29 @@ func (d *DB) UpsertWalletTransactionsForUser(userID string, characterID int64, t
 			type_name = CASE WHEN excluded.type_name != '' THEN excluded.type_name ELSE wallet_transactions_archive.type_name END,
 			location_name = CASE WHEN excluded.location_name != '' THEN excluded.location_name ELSE wallet_transactions_archive.location_name END,
 			last_seen_at = excluded.last_seen_at
+		WHERE wallet_transactions_archive.date IS NOT excluded.date
+		   OR wallet_transactions_archive.type_id IS NOT excluded.type_id
+		   OR wallet_transactions_archive.location_id IS NOT excluded.location_id
+		   OR wallet_transactions_archive.unit_price IS NOT excluded.unit_price
+		   OR wallet_transactions_archive.quantity IS NOT excluded.quantity
+		   OR wallet_transactions_archive.is_buy IS NOT excluded.is_buy
+		   OR (excluded.type_name != '' AND wallet_transactions_archive.type_name IS NOT excluded.type_name)
+		   OR (excluded.location_name != '' AND wallet_transactions_archive.location_name IS NOT excluded.location_name)
 	`)
 	if err != nil {
 		return stats, err
 	}
 	defer stmt.Close()
 
+	lastID := watermark
 	for _, row := range txns {
 		if row.TransactionID == 0 || strings.TrimSpace(row.Date) == "" {
 			continue
 		}
+		if row.TransactionID > watermark {
+			stats.NewRows++
+			lastID = max(lastID, row.TransactionID)
+		}
 		isBuy := 0
 		if row.IsBuy {
 			isBuy = 1
@@ -136,6 +155,9 @@ func (d *DB) UpsertWalletTransactionsForUser(userID string, characterID int64, t
 	`, userID, characterID, now, len(txns), boolInt(stats.LimitHit), now); err != nil {
 		return stats, err
 	}
+	if err := saveIngestWatermarkTx(tx, userID, IngestSourceWalletTransactions, characterID, 0, lastID, stats.NewRows, now); err != nil {
+		return stats, err
+	}
 
 	return stats, tx.Commit()
 }
@@ -183,6 +205,13 @@ func (d *DB) UpsertWalletJournalForUser(userID string, characterID int64, entrie
 	}
 	defer tx.Rollback()
 
+	watermark, err := ingestWatermarkTx(tx, userID, IngestSourceWalletJournal, characterID, 0)
+	if err != nil {
+		return stats, err
+	}
+	// Unchanged rows are left alone so overlapping pulls do not rewrite them.
+	// The private columns are sealed with a fresh nonce on every write, so
+	// they cannot be compared and are only rewritten with the rest of a row.
 	stmt, err := tx.Prepare(`
 		INSERT INTO wallet_journal_archive (
 			user_id, character_id, entry_id, date, ref_type, first_party_id,
@@ -203,13 +232,27 @@ func (d *DB) UpsertWalletJournalForUser(userID string, characterID int64, entrie
 			context_id = excluded.context_id,
 			context_id_type = excluded.context_id_type,
 			last_seen_at = excluded.last_seen_at
+		WHERE wallet_journal_archive.date IS NOT excluded.date
+		   OR wallet_journal_archive.ref_type IS NOT excluded.ref_type
+		   OR wallet_journal_archive.first_party_id IS NOT excluded.first_party_id
+		   OR wallet_journal_archive.second_party_id IS NOT excluded.second_party_id
+		   OR wallet_journal_archive.amount IS NOT excluded.amount
+		   OR wallet_journal_archive.balance IS NOT excluded.balance
+		   OR wallet_journal_archive.tax IS NOT excluded.tax
+		   OR wallet_journal_archive.tax_receiver_id IS NOT excluded.tax_receiver_id
+		   OR wallet_journal_archive.context_id IS NOT excluded.context_id
 	`)
 	if err != nil {
 		return stats, err
 	}
 	defer stmt.Close()
 
+	lastID := watermark
 	for _, row := range storedEntries {
+		if row.ID > watermark {
+			stats.NewRows++
+			lastID = max(lastID, row.ID)
+		}
 		if _, err := stmt.Exec(
 			userID,
 			characterID,
@@ -246,6 +289,9 @@ func (d *DB) UpsertWalletJournalForUser(userID string, characterID int64, entrie
 	`, userID, characterID, now, len(entries), boolInt(stats.LimitHit), now); err != nil {
 		return stats, err
 	}
+	if err := saveIngestWatermarkTx(tx, userID, IngestSourceWalletJournal, characterID, 0, lastID, stats.NewRows, now); err != nil {
+		return stats, err
+	}
 
 	return stats, tx.Commit()
 }
@@ -14,7 +14,8 @@ func (d *DB) GetWatchlistForUser(userID string) []config.WatchlistItem {
 	userID = normalizeUserID(userID)
 
 	rows, err := d.sql.Query(`
-		SELECT type_id, type_name, added_at, alert_min_margin, alert_enabled, alert_metric, alert_threshold
+		SELECT type_id, type_name, added_at, alert_min_margin, alert_enabled, alert_metric, alert_threshold, group_name,
+		       target_buy_below, target_sell_above
 		  FROM watchlist
 		 WHERE user_id = ?
 		 ORDER BY added_at DESC
@@ -35,6 +36,9 @@ func (d *DB) GetWatchlistForUser(userID string) []config.WatchlistItem {
 			&item.AlertEnabled,
 			&item.AlertMetric,
 			&item.AlertThreshold,
+			&item.Group,
+			&item.TargetBuyBelow,
+			&item.TargetSellAbove,
 		)
 		if item.AlertMetric == "" {
 			item.AlertMetric = "margin_percent"
@@ -90,8 +94,9 @@ func (d *DB) AddWatchlistItemForUser(userID string, item config.WatchlistItem) b
 	}
 	res, err := d.sql.Exec(
 		`INSERT OR IGNORE INTO watchlist
-		   (user_id, type_id, type_name, added_at, alert_min_margin, alert_enabled, alert_metric, alert_threshold)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		   (user_id, type_id, type_name, added_at, alert_min_margin, alert_enabled, alert_metric, alert_threshold, group_name,
+		    target_buy_below, target_sell_above)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		userID,
 		item.TypeID,
 		item.TypeName,
@@ -100,6 +105,9 @@ func (d *DB) AddWatchlistItemForUser(userID string, item config.WatchlistItem) b
 		item.AlertEnabled,
 		item.AlertMetric,
 		item.AlertThreshold,
+		item.Group,
+		max(item.TargetBuyBelow, 0),
+		max(item.TargetSellAbove, 0),
 	)
 	if err != nil {
 		return false
@@ -151,3 +159,114 @@ func (d *DB) UpdateWatchlistItemForUser(userID string, typeID int32, alertMinMar
 		typeID,
 	)
 }
+
+// DeleteWatchlistItemsForUser removes several watchlist items at once and
+// returns how many were deleted.
+func (d *DB) DeleteWatchlistItemsForUser(userID string, typeIDs []int32) (int64, error) {
+	userID = normalizeUserID(userID)
+	if len(typeIDs) == 0 {
+		return 0, nil
+	}
+	tx, err := d.sql.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	var total int64
+	for _, typeID := range typeIDs {
+		res, err := tx.Exec("DELETE FROM watchlist WHERE user_id = ? AND type_id = ?", userID, typeID)
+		if err != nil {
+			return 0, err
+		}
+		n, _ := res.RowsAffected()
+		total += n
+	}
+	return total, tx.Commit()
+}
+
+// SetWatchlistGroupForUser moves items into a named group ("" ungroups them)
+// and returns how many rows changed.
+func (d *DB) SetWatchlistGroupForUser(userID string, typeIDs []int32, group string) (int64, error) {
+	userID = normalizeUserID(userID)
+	if len(typeIDs) == 0 {
+		return 0, nil
+	}
+	tx, err := d.sql.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	var total int64
+	for _, typeID := range typeIDs {
+		res, err := tx.Exec("UPDATE watchlist SET group_name = ? WHERE user_id = ? AND type_id = ?", group, userID, typeID)
+		if err != nil {
+			return 0, err
+		}
+		n, _ := res.RowsAffected()
+		total += n
+	}
+	return total, tx.Commit()
+}
+
+// RenameWatchlistGroupForUser renames a group; renaming to "" ungroups its
+// items.
+func (d *DB) RenameWatchlistGroupForUser(userID, from, to string) (int64, error) {
+	userID = normalizeUserID(userID)
+	res, err := d.sql.Exec("UPDATE watchlist SET group_name = ? WHERE user_id = ? AND group_name = ?", to, userID, from)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// UpdateWatchlistPriceBandForUser sets an item's target buy-below and
+// sell-above prices; 0 turns either side off.
+func (d *DB) UpdateWatchlistPriceBandForUser(userID string, typeID int32, buyBelow, sellAbove float64) error {
+	userID = normalizeUserID(userID)
+	_, err := d.sql.Exec(
+		"UPDATE watchlist SET target_buy_below = ?, target_sell_above = ? WHERE user_id = ? AND type_id = ?",
+		max(buyBelow, 0), max(sellAbove, 0), userID, typeID,
+	)
+	return err
+}
+
+// ListWatchlistUserIDs returns every user with at least one watchlist item.
+func (d *DB) ListWatchlistUserIDs() ([]string, error) {
+	rows, err := d.sql.Query(`SELECT DISTINCT user_id FROM watchlist ORDER BY user_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ListWatchlistPriceBandUserIDs returns the users with at least one price
+// band set.
+func (d *DB) ListWatchlistPriceBandUserIDs() ([]string, error) {
+	rows, err := d.sql.Query(`
+		SELECT DISTINCT user_id FROM watchlist
+		 WHERE target_buy_below > 0 OR target_sell_above > 0
+		 ORDER BY user_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
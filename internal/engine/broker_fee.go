@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"math"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/sde"
+)
+
+// Trade skill type IDs.
+const (
+	SkillBrokerRelations int32 = 3446
+	SkillAccounting      int32 = 16622
+)
+
+// Sales tax: 7.5% base, reduced 11% per Accounting level.
+const (
+	salesTaxBasePercent       = 7.5
+	salesTaxReductionPerLevel = 0.11
+)
+
+// NPC station broker fee: 3% base, minus 0.3% per Broker Relations level,
+// 0.03% per point of standing toward the owner's faction and 0.02% per point
+// toward the owner corporation.
+const (
+	npcBrokerFeeBase             = 3.0
+	npcBrokerFeePerSkillLevel    = 0.3
+	npcBrokerFeePerFactionStand  = 0.03
+	npcBrokerFeePerCorpStand     = 0.02
+	npcBrokerFeeMinimumPercent   = 1.0 // Broker Relations V with 10.0 standings
+	brokerFeeStandingMaxAbsValue = 10.0
+)
+
+// NPCBrokerFeePercent returns the broker fee at an NPC station.
+func NPCBrokerFeePercent(brokerRelations int, factionStanding, corpStanding float64) float64 {
+	brokerRelations = max(0, min(5, brokerRelations))
+	factionStanding = math.Max(-brokerFeeStandingMaxAbsValue, math.Min(brokerFeeStandingMaxAbsValue, factionStanding))
+	corpStanding = math.Max(-brokerFeeStandingMaxAbsValue, math.Min(brokerFeeStandingMaxAbsValue, corpStanding))
+	fee := npcBrokerFeeBase -
+		npcBrokerFeePerSkillLevel*float64(brokerRelations) -
+		npcBrokerFeePerFactionStand*factionStanding -
+		npcBrokerFeePerCorpStand*corpStanding
+	return math.Max(npcBrokerFeeMinimumPercent, fee)
+}
+
+// SalesTaxPercent returns the sales tax for an Accounting level.
+func SalesTaxPercent(accounting int) float64 {
+	accounting = max(0, min(5, accounting))
+	return salesTaxBasePercent * (1 - salesTaxReductionPerLevel*float64(accounting))
+}
+
+// SkillLevel returns the active level of skillID, or 0 when untrained.
+func SkillLevel(skills *esi.SkillSheet, skillID int32) int {
+	if skills == nil {
+		return 0
+	}
+	for _, sk := range skills.Skills {
+		if sk.SkillID == skillID {
+			return sk.ActiveLevel
+		}
+	}
+	return 0
+}
+
+// BrokerFeeSchedule resolves the broker fee a character pays at each NPC
+// station from their Broker Relations level and standings toward the
+// station owner. StationPercents are fees the user recorded at any
+// location and win over the NPC rate; player structures set their own fee,
+// so only recorded ones are covered. Set on scan parameters, it replaces the
+// configured broker fee wherever it has a rate.
+type BrokerFeeSchedule struct {
+	BrokerRelations  int
+	FactionStandings map[int32]float64
+	CorpStandings    map[int32]float64
+	StationPercents  map[int64]float64
+
+	stations map[int64]*sde.Station
+	factions map[int32]int32
+}
+
+// NewBrokerFeeSchedule builds a schedule from the character's skills and
+// standings. Station owners come from the SDE.
+func NewBrokerFeeSchedule(data *sde.Data, skills *esi.SkillSheet, standings []esi.Standing) *BrokerFeeSchedule {
+	b := &BrokerFeeSchedule{
+		FactionStandings: make(map[int32]float64),
+		CorpStandings:    make(map[int32]float64),
+	}
+	if data != nil {
+		b.stations = data.Stations
+		b.factions = data.CorporationFactions
+	}
+	b.BrokerRelations = SkillLevel(skills, SkillBrokerRelations)
+	for _, st := range standings {
+		switch st.FromType {
+		case "faction":
+			b.FactionStandings[st.FromID] = st.Standing
+		case "npc_corp":
+			b.CorpStandings[st.FromID] = st.Standing
+		}
+	}
+	return b
+}
+
+// WithStationFees returns a copy of the schedule (an empty one for nil)
+// that charges the given recorded broker fee percent per location.
+func (b *BrokerFeeSchedule) WithStationFees(percents map[int64]float64) *BrokerFeeSchedule {
+	if len(percents) == 0 {
+		return b
+	}
+	out := &BrokerFeeSchedule{}
+	if b != nil {
+		*out = *b
+	}
+	out.StationPercents = percents
+	return out
+}
+
+// StationPercent returns the broker fee at locationID, or false when it is
+// neither a recorded location nor a known NPC station.
+func (b *BrokerFeeSchedule) StationPercent(locationID int64) (float64, bool) {
+	if b == nil {
+		return 0, false
+	}
+	if fee, ok := b.StationPercents[locationID]; ok {
+		return fee, true
+	}
+	if isPlayerStructureID(locationID) {
+		return 0, false
+	}
+	st, ok := b.stations[locationID]
+	if !ok || st.OwnerID == 0 {
+		return 0, false
+	}
+	faction := b.FactionStandings[b.factions[st.OwnerID]]
+	return NPCBrokerFeePercent(b.BrokerRelations, faction, b.CorpStandings[st.OwnerID]), true
+}
+
+// MinPercent is the lowest fee any NPC station or recorded location could
+// charge this character.
+func (b *BrokerFeeSchedule) MinPercent() float64 {
+	if b == nil {
+		return npcBrokerFeeBase
+	}
+	bestFaction, bestCorp := 0.0, 0.0
+	for _, v := range b.FactionStandings {
+		bestFaction = math.Max(bestFaction, v)
+	}
+	for _, v := range b.CorpStandings {
+		bestCorp = math.Max(bestCorp, v)
+	}
+	floor := NPCBrokerFeePercent(b.BrokerRelations, bestFaction, bestCorp)
+	for _, v := range b.StationPercents {
+		floor = math.Min(floor, v)
+	}
+	return floor
+}
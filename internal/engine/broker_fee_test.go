@@ -0,0 +1,168 @@
+package engine
+
+import (
+	"math"
+	"testing"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/sde"
+)
+
+func TestNPCBrokerFeePercent(t *testing.T) {
+	cases := []struct {
+		skill         int
+		faction, corp float64
+		want          float64
+	}{
+		{0, 0, 0, 3.0},
+		{5, 0, 0, 1.5},
+		{4, 5, 2, 3.0 - 1.2 - 0.15 - 0.04},
+		{5, 10, 10, 1.0},
+		{0, -10, -10, 3.5},
+		{9, 20, 20, 1.0}, // clamped
+	}
+	for _, tc := range cases {
+		got := NPCBrokerFeePercent(tc.skill, tc.faction, tc.corp)
+		if math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("NPCBrokerFeePercent(%d, %v, %v) = %v, want %v", tc.skill, tc.faction, tc.corp, got, tc.want)
+		}
+	}
+}
+
+func testBrokerFeeSchedule() *BrokerFeeSchedule {
+	data := &sde.Data{
+		Stations: map[int64]*sde.Station{
+			60003760: {ID: 60003760, OwnerID: 1000035},
+			60008494: {ID: 60008494, OwnerID: 1000086},
+		},
+		CorporationFactions: map[int32]int32{
+			1000035: 500001,
+			1000086: 500003,
+		},
+	}
+	skills := &esi.SkillSheet{Skills: []esi.SkillEntry{{SkillID: SkillBrokerRelations, ActiveLevel: 5}}}
+	standings := []esi.Standing{
+		{FromID: 500001, FromType: "faction", Standing: 5},
+		{FromID: 1000035, FromType: "npc_corp", Standing: 5},
+		{FromID: 500003, FromType: "faction", Standing: -2},
+	}
+	return NewBrokerFeeSchedule(data, skills, standings)
+}
+
+func TestBrokerFeeSchedule_StationPercent(t *testing.T) {
+	b := testBrokerFeeSchedule()
+
+	if got, ok := b.StationPercent(60003760); !ok || math.Abs(got-1.25) > 1e-9 {
+		t.Fatalf("Jita fee = %v, %v; want 1.25", got, ok)
+	}
+	if got, ok := b.StationPercent(60008494); !ok || math.Abs(got-1.56) > 1e-9 {
+		t.Fatalf("Amarr fee = %v, %v; want 1.56", got, ok)
+	}
+	if _, ok := b.StationPercent(1_035_466_617_946); ok {
+		t.Fatal("player structure should not have an NPC fee")
+	}
+	if _, ok := b.StationPercent(60000001); ok {
+		t.Fatal("unknown station should not have an NPC fee")
+	}
+	if got := b.MinPercent(); math.Abs(got-1.25) > 1e-9 {
+		t.Fatalf("MinPercent = %v, want 1.25", got)
+	}
+}
+
+func TestBrokerFeeSchedule_StructureFees(t *testing.T) {
+	const structure = int64(1_035_466_617_946)
+	base := testBrokerFeeSchedule()
+	if _, ok := base.StationPercent(structure); ok {
+		t.Fatal("structure without a recorded fee should keep the configured fee")
+	}
+	b := base.WithStationFees(map[int64]float64{structure: 0.5})
+
+	if got, ok := b.StationPercent(structure); !ok || got != 0.5 {
+		t.Fatalf("structure fee = %v, %v; want 0.5", got, ok)
+	}
+	if got, ok := b.StationPercent(60003760); !ok || math.Abs(got-1.25) > 1e-9 {
+		t.Fatalf("Jita fee = %v, %v; want 1.25", got, ok)
+	}
+	if got := b.MinPercent(); got != 0.5 {
+		t.Fatalf("MinPercent = %v, want 0.5", got)
+	}
+	if _, ok := base.StationPercent(structure); ok {
+		t.Fatal("WithStationFees must not modify the shared schedule")
+	}
+
+	// Without a character the schedule only covers structures.
+	var none *BrokerFeeSchedule
+	b = none.WithStationFees(map[int64]float64{structure: 5})
+	if got, ok := b.StationPercent(structure); !ok || got != 5 {
+		t.Fatalf("structure fee = %v, %v; want 5", got, ok)
+	}
+	if _, ok := b.StationPercent(60003760); ok {
+		t.Fatal("NPC station should keep the configured fee without a character")
+	}
+	if none.WithStationFees(nil) != nil {
+		t.Fatal("no structure fees should keep a nil schedule")
+	}
+}
+
+func TestTradeFees_InputsAt(t *testing.T) {
+	f := tradeFees{
+		in:      tradeFeeInputs{BrokerFeePercent: 3, SalesTaxPercent: 4.5},
+		brokers: testBrokerFeeSchedule(),
+	}
+	in := f.inputsAt(60003760, 1_035_466_617_946)
+	if math.Abs(in.BuyBrokerFeePercent-1.25) > 1e-9 {
+		t.Fatalf("buy broker = %v, want 1.25", in.BuyBrokerFeePercent)
+	}
+	if math.Abs(in.SellBrokerFeePercent-3) > 1e-9 {
+		t.Fatalf("structure sell broker = %v, want configured 3", in.SellBrokerFeePercent)
+	}
+
+	// Instant trades configured with no broker fee stay at zero.
+	f.in = tradeFeeInputs{
+		SplitTradeFees:       true,
+		BuyBrokerFeePercent:  0,
+		SellBrokerFeePercent: 3,
+		SellSalesTaxPercent:  4.5,
+	}
+	in = f.inputsAt(60003760, 60003760)
+	if in.BuyBrokerFeePercent != 0 {
+		t.Fatalf("instant buy broker = %v, want 0", in.BuyBrokerFeePercent)
+	}
+	if math.Abs(in.SellBrokerFeePercent-1.25) > 1e-9 {
+		t.Fatalf("sell broker = %v, want 1.25", in.SellBrokerFeePercent)
+	}
+}
+
+func TestSalesTaxPercent(t *testing.T) {
+	for level, want := range map[int]float64{0: 7.5, 3: 5.025, 5: 3.375, 7: 3.375} {
+		if got := SalesTaxPercent(level); math.Abs(got-want) > 1e-9 {
+			t.Errorf("SalesTaxPercent(%d) = %v, want %v", level, got, want)
+		}
+	}
+}
+
+func TestBrokerFeeSchedule_StationFeesOverride(t *testing.T) {
+	const structure = int64(1_035_466_617_946)
+	base := testBrokerFeeSchedule()
+	b := base.WithStationFees(map[int64]float64{60003760: 0.9, structure: 0.8})
+
+	if got, ok := b.StationPercent(60003760); !ok || got != 0.9 {
+		t.Fatalf("recorded Jita fee = %v, %v; want 0.9", got, ok)
+	}
+	if got, ok := b.StationPercent(structure); !ok || got != 0.8 {
+		t.Fatalf("recorded structure fee = %v, %v; want 0.8", got, ok)
+	}
+	if got, ok := base.StationPercent(60003760); !ok || math.Abs(got-1.25) > 1e-9 {
+		t.Fatal("WithStationFees must not modify the shared schedule")
+	}
+
+	// Without a character a recorded NPC station fee still applies.
+	var none *BrokerFeeSchedule
+	b = none.WithStationFees(map[int64]float64{60003760: 2.1})
+	if got, ok := b.StationPercent(60003760); !ok || got != 2.1 {
+		t.Fatalf("recorded fee without character = %v, %v; want 2.1", got, ok)
+	}
+	if got := b.MinPercent(); got != 2.1 {
+		t.Fatalf("MinPercent = %v, want 2.1", got)
+	}
+}
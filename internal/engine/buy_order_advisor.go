@@ -0,0 +1,175 @@
+package engine
+
+import (
+	"math"
+	"sort"
+)
+
+// Buy order advisor defaults.
+const (
+	DefaultBuyAdvisorHorizonDays  = 2.0
+	DefaultBuyAdvisorMaxOrders    = 20
+	DefaultBuyAdvisorMaxItemPct   = 20.0 // of capital
+	DefaultBuyAdvisorFillSharePct = 50.0 // of sells into buy orders
+)
+
+// BuyOrderAdvisorParams configures AdviseBuyOrders.
+type BuyOrderAdvisorParams struct {
+	Capital float64
+	// HorizonDays is how many days of expected fills an order is sized for.
+	HorizonDays float64
+	// MaxOrders caps the number of orders, e.g. to the free order slots.
+	MaxOrders int
+	// MaxItemPct caps one item's share of the capital.
+	MaxItemPct float64
+	// FillSharePct is the share of the daily sells into buy orders an order
+	// at the top of the book is expected to catch; competitors relisting
+	// above it take the rest.
+	FillSharePct float64
+	// MinCTS skips trades scoring below it; 0 keeps all.
+	MinCTS           float64
+	SalesTaxPercent  float64
+	BrokerFeePercent float64
+}
+
+// BuyOrderSuggestion is one buy order to place.
+type BuyOrderSuggestion struct {
+	TypeID         int32   `json:"type_id"`
+	TypeName       string  `json:"type_name"`
+	Price          float64 `json:"price"` // one tick above the best buy order
+	Quantity       int64   `json:"quantity"`
+	Escrow         float64 `json:"escrow"`
+	BrokerFee      float64 `json:"broker_fee"`
+	BestBuy        float64 `json:"best_buy"`
+	BestSell       float64 `json:"best_sell"`
+	SpreadPct      float64 `json:"spread_pct"` // of the order price
+	CTS            float64 `json:"cts"`
+	FillPerDay     float64 `json:"fill_per_day"`
+	DaysToFill     float64 `json:"days_to_fill"`
+	ProfitPerUnit  float64 `json:"profit_per_unit"` // relisted one tick under the best sell, after fees
+	ExpectedProfit float64 `json:"expected_profit"`
+	DailyROIPct    float64 `json:"daily_roi_pct"` // profit per day over the capital tied up
+}
+
+// BuyOrderPlan is the advised set of buy orders at one station.
+type BuyOrderPlan struct {
+	StationID       int64                `json:"station_id"`
+	StationName     string               `json:"station_name"`
+	Capital         float64              `json:"capital"`
+	TotalEscrow     float64              `json:"total_escrow"`
+	TotalBrokerFees float64              `json:"total_broker_fees"`
+	TotalCapital    float64              `json:"total_capital"` // escrow plus broker fees
+	Unallocated     float64              `json:"unallocated"`
+	ExpectedProfit  float64              `json:"expected_profit"`
+	Candidates      int                  `json:"candidates"`
+	Orders          []BuyOrderSuggestion `json:"orders"`
+}
+
+// AdviseBuyOrders picks the station trades worth a buy order now and splits
+// the capital across them. Each order outbids the best buy by one tick and
+// is sized to the fills expected over HorizonDays: FillSharePct of the sells
+// into buy orders per day. Candidates are ranked by expected profit per day
+// per ISK tied up, weighted by CTS, and funded in that order until the
+// capital, MaxOrders or the per-item cap runs out.
+func AdviseBuyOrders(trades []StationTrade, p BuyOrderAdvisorParams) BuyOrderPlan {
+	p = p.withDefaults()
+	plan := BuyOrderPlan{Capital: p.Capital, Orders: []BuyOrderSuggestion{}}
+	if len(trades) > 0 {
+		plan.StationID, plan.StationName = trades[0].StationID, trades[0].StationName
+	}
+	buyFee := p.BrokerFeePercent / 100
+	sellFee := (p.SalesTaxPercent + p.BrokerFeePercent) / 100
+
+	type candidate struct {
+		s     BuyOrderSuggestion
+		score float64
+	}
+	var cands []candidate
+	for _, t := range trades {
+		if t.BuyPrice <= 0 || t.SellPrice <= 0 || t.IsHighRiskFlag || t.IsExtremePriceFlag || t.CTS < p.MinCTS {
+			continue
+		}
+		fill := t.S2BPerDay * p.FillSharePct / 100
+		if fill <= 0 {
+			continue
+		}
+		price := TickBeat(t.BuyPrice, true)
+		if price >= t.SellPrice {
+			continue
+		}
+		profit := TickBeat(t.SellPrice, false)*(1-sellFee) - price*(1+buyFee)
+		if profit <= 0 {
+			continue
+		}
+		unitCost := price * (1 + buyFee)
+		s := BuyOrderSuggestion{
+			TypeID:        t.TypeID,
+			TypeName:      t.TypeName,
+			Price:         price,
+			BestBuy:       t.BuyPrice,
+			BestSell:      t.SellPrice,
+			SpreadPct:     sanitizeFloat(math.Round((t.SellPrice-price)/price*10000) / 100),
+			CTS:           t.CTS,
+			FillPerDay:    sanitizeFloat(math.Round(fill*100) / 100),
+			ProfitPerUnit: sanitizeFloat(profit),
+			Quantity:      int64(math.Floor(fill * p.HorizonDays)),
+			// Orders are sized to HorizonDays of fills, so the capital
+			// turns over once per horizon.
+			DailyROIPct: sanitizeFloat(math.Round(profit/(unitCost*p.HorizonDays)*10000) / 100),
+		}
+		if s.Quantity <= 0 {
+			continue
+		}
+		cands = append(cands, candidate{s: s, score: s.DailyROIPct * t.CTS / 100})
+	}
+	plan.Candidates = len(cands)
+	sort.SliceStable(cands, func(i, j int) bool {
+		if cands[i].score != cands[j].score {
+			return cands[i].score > cands[j].score
+		}
+		return cands[i].s.TypeID < cands[j].s.TypeID
+	})
+
+	left := p.Capital
+	itemCap := p.Capital * p.MaxItemPct / 100
+	for _, c := range cands {
+		if len(plan.Orders) >= p.MaxOrders {
+			break
+		}
+		s := c.s
+		unitCost := s.Price * (1 + buyFee)
+		qty := min(s.Quantity, int64(math.Floor(math.Min(left, itemCap)/unitCost)))
+		if qty <= 0 {
+			continue
+		}
+		s.Quantity = qty
+		s.Escrow = sanitizeFloat(math.Round(s.Price*float64(qty)*100) / 100)
+		s.BrokerFee = sanitizeFloat(math.Round(s.Escrow*buyFee*100) / 100)
+		s.DaysToFill = sanitizeFloat(math.Round(float64(qty)/s.FillPerDay*10) / 10)
+		s.ExpectedProfit = sanitizeFloat(math.Round(s.ProfitPerUnit*float64(qty)*100) / 100)
+		left -= s.Escrow + s.BrokerFee
+		plan.TotalEscrow += s.Escrow
+		plan.TotalBrokerFees += s.BrokerFee
+		plan.ExpectedProfit += s.ExpectedProfit
+		plan.Orders = append(plan.Orders, s)
+	}
+	plan.TotalCapital = plan.TotalEscrow + plan.TotalBrokerFees
+	plan.Unallocated = math.Max(0, p.Capital-plan.TotalCapital)
+	return plan
+}
+
+func (p BuyOrderAdvisorParams) withDefaults() BuyOrderAdvisorParams {
+	if p.HorizonDays <= 0 {
+		p.HorizonDays = DefaultBuyAdvisorHorizonDays
+	}
+	if p.MaxOrders <= 0 {
+		p.MaxOrders = DefaultBuyAdvisorMaxOrders
+	}
+	if p.MaxItemPct <= 0 || p.MaxItemPct > 100 {
+		p.MaxItemPct = DefaultBuyAdvisorMaxItemPct
+	}
+	if p.FillSharePct <= 0 || p.FillSharePct > 100 {
+		p.FillSharePct = DefaultBuyAdvisorFillSharePct
+	}
+	return p
+}
@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAdviseBuyOrders_AllocatesCapitalByScore(t *testing.T) {
+	trades := []StationTrade{
+		// 10% spread, fast fills.
+		{TypeID: 1, TypeName: "A", StationID: 60003760, StationName: "Jita", BuyPrice: 100, SellPrice: 110, S2BPerDay: 100, CTS: 80},
+		// Wider spread but barely trades: two days of fills round to 0.
+		{TypeID: 2, TypeName: "B", StationID: 60003760, BuyPrice: 100, SellPrice: 150, S2BPerDay: 0.4, CTS: 90},
+		// No margin once outbid and undercut.
+		{TypeID: 3, TypeName: "C", StationID: 60003760, BuyPrice: 100, SellPrice: 100.02, S2BPerDay: 100, CTS: 90},
+		// Flagged as a likely scam.
+		{TypeID: 4, TypeName: "D", StationID: 60003760, BuyPrice: 100, SellPrice: 200, S2BPerDay: 100, CTS: 90, IsHighRiskFlag: true},
+		// 5% spread.
+		{TypeID: 5, TypeName: "E", StationID: 60003760, BuyPrice: 1000, SellPrice: 1050, S2BPerDay: 10, CTS: 80},
+	}
+	plan := AdviseBuyOrders(trades, BuyOrderAdvisorParams{Capital: 50000, MaxItemPct: 50})
+
+	if plan.StationID != 60003760 || plan.Candidates != 2 || len(plan.Orders) != 2 {
+		t.Fatalf("plan = %+v, want 2 candidates and orders at 60003760", plan)
+	}
+	a, e := plan.Orders[0], plan.Orders[1]
+	if a.TypeID != 1 || e.TypeID != 5 {
+		t.Fatalf("order types = %d,%d, want 1,5", a.TypeID, e.TypeID)
+	}
+	// Outbid by one tick, sized to 2 days at half of 100/day.
+	if math.Abs(a.Price-100.1) > 1e-9 || a.Quantity != 100 {
+		t.Fatalf("A = %v x %d, want 100.1 x 100", a.Price, a.Quantity)
+	}
+	// Ranked after A on the narrower spread; 10 units fit under the cap.
+	if math.Abs(e.Price-1001) > 1e-9 || e.Quantity != 10 {
+		t.Fatalf("E = %v x %d, want 1001 x 10", e.Price, e.Quantity)
+	}
+	if math.Abs(plan.TotalEscrow-(100.1*100+1001*10)) > 1e-6 {
+		t.Fatalf("total escrow = %v", plan.TotalEscrow)
+	}
+	if math.Abs(plan.Unallocated-(50000-plan.TotalCapital)) > 1e-6 {
+		t.Fatalf("unallocated = %v, total capital %v", plan.Unallocated, plan.TotalCapital)
+	}
+}
+
+func TestAdviseBuyOrders_CapitalAndOrderCaps(t *testing.T) {
+	trades := []StationTrade{
+		{TypeID: 1, BuyPrice: 100, SellPrice: 120, S2BPerDay: 1000, CTS: 50},
+		{TypeID: 2, BuyPrice: 100, SellPrice: 115, S2BPerDay: 1000, CTS: 50},
+	}
+	plan := AdviseBuyOrders(trades, BuyOrderAdvisorParams{Capital: 10000, MaxItemPct: 100, MaxOrders: 1, BrokerFeePercent: 1})
+	if len(plan.Orders) != 1 || plan.Orders[0].TypeID != 1 {
+		t.Fatalf("orders = %+v, want only type 1", plan.Orders)
+	}
+	o := plan.Orders[0]
+	// 10,000 / (100.1 * 1.01) = 98.9 units.
+	if o.Quantity != 98 {
+		t.Fatalf("quantity = %d, want 98", o.Quantity)
+	}
+	if plan.TotalCapital > plan.Capital {
+		t.Fatalf("total capital %v exceeds %v", plan.TotalCapital, plan.Capital)
+	}
+}
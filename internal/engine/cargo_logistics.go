@@ -0,0 +1,30 @@
+package engine
+
+// cargoLeftoverM3 is the hold space left free on the last of trips runs
+// moving cargoM3; zero without a cargo capacity.
+func cargoLeftoverM3(cargoM3, capacity float64, trips int) float64 {
+	if capacity <= 0 || cargoM3 <= 0 {
+		return 0
+	}
+	return sanitizeFloat(max(float64(trips)*capacity-cargoM3, 0))
+}
+
+// ApplyCargoLogistics sets the m³ each result moves, the trips that takes
+// in a hold of cargoCapacity m³ and the space left on the last trip, so
+// multi-trip flips are visible. A capacity <= 0 counts everything as one
+// trip.
+func ApplyCargoLogistics(results []FlipResult, cargoCapacity float64) {
+	for i := range results {
+		r := &results[i]
+		units := r.UnitsToBuy
+		if r.FilledQty > 0 && r.FilledQty < units {
+			units = r.FilledQty
+		}
+		if units <= 0 || r.Volume <= 0 {
+			continue
+		}
+		r.CargoM3 = sanitizeFloat(float64(units) * r.Volume)
+		r.CargoTrips = routeCargoTrips(r.CargoM3, cargoCapacity)
+		r.CargoLeftoverM3 = cargoLeftoverM3(r.CargoM3, cargoCapacity, r.CargoTrips)
+	}
+}
@@ -0,0 +1,28 @@
+package engine
+
+import "testing"
+
+func TestApplyCargoLogistics(t *testing.T) {
+	results := []FlipResult{
+		{TypeID: 1, Volume: 10, UnitsToBuy: 1200},              // 12 000 m³
+		{TypeID: 2, Volume: 5, UnitsToBuy: 100, FilledQty: 60}, // only 60 fill
+		{TypeID: 3, Volume: 0, UnitsToBuy: 100},                // unknown volume
+	}
+	ApplyCargoLogistics(results, 5000)
+
+	if r := results[0]; r.CargoM3 != 12000 || r.CargoTrips != 3 || r.CargoLeftoverM3 != 3000 {
+		t.Fatalf("multi-trip = %v m³ / %d trips / %v free", r.CargoM3, r.CargoTrips, r.CargoLeftoverM3)
+	}
+	if r := results[1]; r.CargoM3 != 300 || r.CargoTrips != 1 || r.CargoLeftoverM3 != 4700 {
+		t.Fatalf("partial fill = %v m³ / %d trips / %v free", r.CargoM3, r.CargoTrips, r.CargoLeftoverM3)
+	}
+	if r := results[2]; r.CargoM3 != 0 || r.CargoTrips != 0 {
+		t.Fatalf("zero volume annotated: %+v", r)
+	}
+
+	// Without a hold size everything is one trip and nothing is left over.
+	ApplyCargoLogistics(results, 0)
+	if r := results[0]; r.CargoTrips != 1 || r.CargoLeftoverM3 != 0 {
+		t.Fatalf("unlimited cargo = %d trips / %v free", r.CargoTrips, r.CargoLeftoverM3)
+	}
+}
@@ -0,0 +1,327 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"eve-flipper/internal/graph"
+)
+
+const (
+	// maxCargoCandidates bounds the knapsack to the most profitable rows.
+	maxCargoCandidates = 100
+	// Knapsack grid resolution. Weights are rounded up to whole cells, so any
+	// DP solution is feasible; the leftover slack is refilled greedily.
+	cargoVolumeCells = 500
+	cargoBudgetCells = 50
+)
+
+// CargoOptimizeParams configures the cargo basket optimizer.
+type CargoOptimizeParams struct {
+	StartSystemName  string
+	CargoCapacity    float64 // m3, required
+	Budget           float64 // ISK available for purchases; <=0 = unlimited
+	MinRouteSecurity float64 // 0 = all space
+	Flips            []FlipResult
+	// RoutePreferences are the user's avoided systems and pinned paths.
+	RoutePreferences graph.RoutePreferences
+}
+
+// CargoBasketItem is one flip packed into the hold.
+type CargoBasketItem struct {
+	TypeID         int32
+	TypeName       string
+	Units          int32
+	MaxUnits       int32 // units available after order-remain limits
+	UnitVolume     float64
+	VolumeM3       float64
+	BuyPrice       float64
+	SellPrice      float64
+	CostISK        float64
+	Profit         float64
+	BuyStation     string
+	BuySystemName  string
+	SellStation    string
+	SellSystemName string
+}
+
+// CargoBasket is the most profitable combination of flips for one hold.
+type CargoBasket struct {
+	Items           []CargoBasketItem
+	TotalProfit     float64
+	CapitalRequired float64
+	CargoM3         float64
+	CargoCapacity   float64
+	CargoUsedPct    float64
+	Candidates      int
+	Route           *MultiStopRoute // pickup/drop-off plan for the basket
+}
+
+// cargoItem is a knapsack candidate with its unit bound.
+type cargoItem struct {
+	row      FlipResult
+	maxUnits int32
+}
+
+// cargoChunk is a binary-split group of units of one candidate (0/1 item).
+type cargoChunk struct {
+	item   int
+	units  int32
+	weight int // volume cells
+	cost   int // budget cells
+	value  float64
+}
+
+// OptimizeCargo picks the unit counts across flips that maximize total profit
+// within the cargo hold and budget, then plans the pickup/drop-off route.
+func (s *Scanner) OptimizeCargo(params CargoOptimizeParams) (*CargoBasket, error) {
+	s = s.withRoutePreferences(params.RoutePreferences)
+	if params.CargoCapacity <= 0 {
+		return nil, fmt.Errorf("cargo capacity is required")
+	}
+	startName := strings.TrimSpace(params.StartSystemName)
+	startID, ok := s.SDE.SystemByName[strings.ToLower(startName)]
+	if !ok {
+		return nil, fmt.Errorf("system not found: %s", params.StartSystemName)
+	}
+	dist := func(from, to int32) int {
+		return s.jumpsBetweenWithSecurity(from, to, params.MinRouteSecurity)
+	}
+
+	items := cargoCandidates(startID, params, dist)
+	basket, units := packCargo(items, params.CargoCapacity, params.Budget)
+	basket.Candidates = len(items)
+	if len(basket.Items) == 0 {
+		return nil, fmt.Errorf("no flips fit the cargo capacity and budget")
+	}
+
+	selected := make([]multiStopCandidate, 0, len(basket.Items))
+	for i, it := range items {
+		if units[i] > 0 {
+			selected = append(selected, multiStopCandidate{row: it.row, units: units[i]})
+		}
+	}
+	route := sequenceMultiStopRoute(startID, selected, dist)
+	route.StartSystemName = s.systemName(startID)
+	for i := range route.Stops {
+		if route.Stops[i].SystemName == "" {
+			route.Stops[i].SystemName = s.systemName(route.Stops[i].SystemID)
+		}
+	}
+	basket.Route = route
+	return basket, nil
+}
+
+// cargoCandidates filters rows to profitable, reachable flips, bounds units by
+// order remain, keeps the best row per (type, buy location) since those rows
+// compete for the same sell-side stock, and caps the set by total profit.
+func cargoCandidates(startID int32, params CargoOptimizeParams, dist func(from, to int32) int) []cargoItem {
+	best := make(map[[2]int64]cargoItem)
+	for _, row := range params.Flips {
+		if row.ProfitPerUnit <= 0 || row.UnitsToBuy <= 0 || row.BuyPrice <= 0 {
+			continue
+		}
+		if row.BuySystemID == 0 || row.SellSystemID == 0 {
+			continue
+		}
+		units := row.UnitsToBuy
+		if row.BuyOrderRemain > 0 {
+			units = min(units, row.BuyOrderRemain)
+		}
+		if row.SellOrderRemain > 0 {
+			units = min(units, row.SellOrderRemain)
+		}
+		if row.Volume > 0 {
+			units = min(units, int32(math.Floor(params.CargoCapacity/row.Volume)))
+		}
+		if params.Budget > 0 {
+			units = min(units, int32(math.Floor(params.Budget/row.BuyPrice)))
+		}
+		if units <= 0 {
+			continue
+		}
+		if dist(startID, row.BuySystemID) >= UnreachableJumps || dist(row.BuySystemID, row.SellSystemID) >= UnreachableJumps {
+			continue
+		}
+		key := [2]int64{int64(row.TypeID), row.BuyLocationID}
+		if row.BuyLocationID == 0 {
+			key[1] = int64(row.BuySystemID)
+		}
+		if cur, ok := best[key]; ok && cur.row.ProfitPerUnit*float64(cur.maxUnits) >= row.ProfitPerUnit*float64(units) {
+			continue
+		}
+		best[key] = cargoItem{row: row, maxUnits: units}
+	}
+
+	items := make([]cargoItem, 0, len(best))
+	for _, it := range best {
+		items = append(items, it)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		pi := items[i].row.ProfitPerUnit * float64(items[i].maxUnits)
+		pj := items[j].row.ProfitPerUnit * float64(items[j].maxUnits)
+		if pi != pj {
+			return pi > pj
+		}
+		if items[i].row.TypeID != items[j].row.TypeID {
+			return items[i].row.TypeID < items[j].row.TypeID
+		}
+		return items[i].row.BuySystemID < items[j].row.BuySystemID ||
+			(items[i].row.BuySystemID == items[j].row.BuySystemID && items[i].row.BuyLocationID < items[j].row.BuyLocationID)
+	})
+	if len(items) > maxCargoCandidates {
+		items = items[:maxCargoCandidates]
+	}
+	return items
+}
+
+// packCargo solves the bounded knapsack over volume (and budget, when set)
+// with binary splitting of unit counts, then greedily tops up the slack left
+// by rounding weights up to grid cells. units[i] is the count packed of items[i].
+func packCargo(items []cargoItem, capacity, budget float64) (basket *CargoBasket, units []int32) {
+	basket = &CargoBasket{Items: []CargoBasketItem{}, CargoCapacity: capacity}
+	units = make([]int32, len(items))
+	if len(items) == 0 || capacity <= 0 {
+		return basket, units
+	}
+
+	volCell := capacity / cargoVolumeCells
+	budgetCells := 0
+	budgetCell := 0.0
+	if budget > 0 {
+		budgetCells = cargoBudgetCells
+		budgetCell = budget / cargoBudgetCells
+	}
+	cells := func(x, cell float64) int {
+		if cell <= 0 {
+			return 0
+		}
+		return int(math.Ceil(x/cell - 1e-9))
+	}
+
+	var chunks []cargoChunk
+	for i, it := range items {
+		for left, k := it.maxUnits, int32(1); left > 0; k *= 2 {
+			n := min(k, left)
+			left -= n
+			c := cargoChunk{
+				item:   i,
+				units:  n,
+				weight: cells(float64(n)*it.row.Volume, volCell),
+				cost:   cells(float64(n)*it.row.BuyPrice, budgetCell),
+				value:  float64(n) * it.row.ProfitPerUnit,
+			}
+			if c.weight > cargoVolumeCells || c.cost > budgetCells {
+				continue
+			}
+			chunks = append(chunks, c)
+		}
+	}
+
+	// dp[w*(B+1)+b] = best profit using at most w volume cells and b budget cells.
+	stride := budgetCells + 1
+	states := (cargoVolumeCells + 1) * stride
+	dp := make([]float64, states)
+	take := make([][]uint64, len(chunks))
+	for ci, c := range chunks {
+		bits := make([]uint64, (states+63)/64)
+		for w := cargoVolumeCells; w >= c.weight; w-- {
+			for b := budgetCells; b >= c.cost; b-- {
+				idx := w*stride + b
+				if v := dp[(w-c.weight)*stride+b-c.cost] + c.value; v > dp[idx] {
+					dp[idx] = v
+					bits[idx/64] |= 1 << (idx % 64)
+				}
+			}
+		}
+		take[ci] = bits
+	}
+
+	w, b := cargoVolumeCells, budgetCells
+	for ci := len(chunks) - 1; ci >= 0; ci-- {
+		idx := w*stride + b
+		if take[ci][idx/64]&(1<<(idx%64)) == 0 {
+			continue
+		}
+		c := chunks[ci]
+		units[c.item] += c.units
+		w -= c.weight
+		b -= c.cost
+	}
+
+	volLeft, budgetLeft := capacity, budget
+	for i, it := range items {
+		volLeft -= float64(units[i]) * it.row.Volume
+		budgetLeft -= float64(units[i]) * it.row.BuyPrice
+	}
+
+	// Greedy top-up by profit per m3 into the exact remaining slack.
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return cargoDensity(items[order[a]].row) > cargoDensity(items[order[b]].row)
+	})
+	for _, i := range order {
+		it := items[i]
+		extra := it.maxUnits - units[i]
+		if it.row.Volume > 0 {
+			extra = min(extra, int32(math.Floor(volLeft/it.row.Volume+1e-9)))
+		}
+		if budget > 0 {
+			extra = min(extra, int32(math.Floor(budgetLeft/it.row.BuyPrice+1e-9)))
+		}
+		if extra <= 0 {
+			continue
+		}
+		units[i] += extra
+		volLeft -= float64(extra) * it.row.Volume
+		budgetLeft -= float64(extra) * it.row.BuyPrice
+	}
+
+	for i, it := range items {
+		if units[i] <= 0 {
+			continue
+		}
+		n := float64(units[i])
+		item := CargoBasketItem{
+			TypeID:         it.row.TypeID,
+			TypeName:       it.row.TypeName,
+			Units:          units[i],
+			MaxUnits:       it.maxUnits,
+			UnitVolume:     it.row.Volume,
+			VolumeM3:       sanitizeFloat(n * it.row.Volume),
+			BuyPrice:       it.row.BuyPrice,
+			SellPrice:      it.row.SellPrice,
+			CostISK:        sanitizeFloat(n * it.row.BuyPrice),
+			Profit:         sanitizeFloat(n * it.row.ProfitPerUnit),
+			BuyStation:     it.row.BuyStation,
+			BuySystemName:  it.row.BuySystemName,
+			SellStation:    it.row.SellStation,
+			SellSystemName: it.row.SellSystemName,
+		}
+		basket.Items = append(basket.Items, item)
+		basket.TotalProfit += item.Profit
+		basket.CapitalRequired += item.CostISK
+		basket.CargoM3 += item.VolumeM3
+	}
+	sort.SliceStable(basket.Items, func(i, j int) bool {
+		return basket.Items[i].Profit > basket.Items[j].Profit
+	})
+	basket.TotalProfit = sanitizeFloat(basket.TotalProfit)
+	basket.CapitalRequired = sanitizeFloat(basket.CapitalRequired)
+	basket.CargoM3 = sanitizeFloat(basket.CargoM3)
+	basket.CargoUsedPct = sanitizeFloat(basket.CargoM3 / capacity * 100)
+	return basket, units
+}
+
+// cargoDensity is profit per m3; volume-less items sort first.
+func cargoDensity(row FlipResult) float64 {
+	if row.Volume <= 0 {
+		return math.Inf(1)
+	}
+	return row.ProfitPerUnit / row.Volume
+}
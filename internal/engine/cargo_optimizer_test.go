@@ -0,0 +1,60 @@
+package engine
+
+import "testing"
+
+func TestPackCargo_BeatsDensityGreedy(t *testing.T) {
+	// Greedy by profit/m3 takes A (6 m3) and then cannot fit B; 2×B fills the hold.
+	items := []cargoItem{
+		{row: FlipResult{TypeID: 1, Volume: 6, BuyPrice: 1, ProfitPerUnit: 7}, maxUnits: 1},
+		{row: FlipResult{TypeID: 2, Volume: 5, BuyPrice: 1, ProfitPerUnit: 5}, maxUnits: 2},
+	}
+	basket, units := packCargo(items, 10, 0)
+	if basket.TotalProfit != 10 {
+		t.Fatalf("TotalProfit = %v, want 10", basket.TotalProfit)
+	}
+	if units[0] != 0 || units[1] != 2 {
+		t.Errorf("units = %v, want [0 2]", units)
+	}
+	if basket.CargoM3 != 10 || basket.CargoUsedPct != 100 {
+		t.Errorf("cargo=%v used=%v%%, want 10/100", basket.CargoM3, basket.CargoUsedPct)
+	}
+}
+
+func TestPackCargo_RespectsBudget(t *testing.T) {
+	items := []cargoItem{
+		{row: FlipResult{TypeID: 1, Volume: 1, BuyPrice: 100, ProfitPerUnit: 30}, maxUnits: 50},
+		{row: FlipResult{TypeID: 2, Volume: 1, BuyPrice: 10, ProfitPerUnit: 5}, maxUnits: 50},
+	}
+	basket, _ := packCargo(items, 1000, 1000)
+	if basket.CapitalRequired > 1000 {
+		t.Fatalf("CapitalRequired = %v exceeds budget 1000", basket.CapitalRequired)
+	}
+	// Best per ISK is B (0.5 profit/ISK): 50×B = 500 ISK → 250, then 5×A = 500 ISK → 150.
+	if basket.TotalProfit != 400 {
+		t.Errorf("TotalProfit = %v, want 400", basket.TotalProfit)
+	}
+}
+
+func TestCargoCandidates_BoundsUnitsAndDedupes(t *testing.T) {
+	flips := []FlipResult{
+		{TypeID: 1, BuySystemID: 2, SellSystemID: 3, BuyPrice: 10, ProfitPerUnit: 5, UnitsToBuy: 100, BuyOrderRemain: 40, SellOrderRemain: 30, Volume: 1},
+		// Same type and buy system, worse destination: dropped.
+		{TypeID: 1, BuySystemID: 2, SellSystemID: 4, BuyPrice: 10, ProfitPerUnit: 1, UnitsToBuy: 100, Volume: 1},
+		{TypeID: 2, BuySystemID: 2, SellSystemID: 3, BuyPrice: 10, ProfitPerUnit: -1, UnitsToBuy: 100, Volume: 1},
+	}
+	items := cargoCandidates(1, CargoOptimizeParams{CargoCapacity: 1000, Flips: flips}, lineDist)
+	if len(items) != 1 {
+		t.Fatalf("candidates = %d, want 1", len(items))
+	}
+	if items[0].maxUnits != 30 || items[0].row.SellSystemID != 3 {
+		t.Errorf("candidate = %+v, want 30 units to system 3", items[0])
+	}
+}
+
+func TestPackCargo_EmptyWhenNothingFits(t *testing.T) {
+	items := []cargoItem{{row: FlipResult{TypeID: 1, Volume: 20, BuyPrice: 1, ProfitPerUnit: 5}, maxUnits: 3}}
+	basket, _ := packCargo(items, 10, 0)
+	if len(basket.Items) != 0 || basket.TotalProfit != 0 {
+		t.Errorf("basket = %+v, want empty", basket)
+	}
+}
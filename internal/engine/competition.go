@@ -0,0 +1,158 @@
+package engine
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+// competitionBandEdges are the price bands, in percent behind the best
+// price, that the book's orders are clustered into.
+var competitionBandEdges = []float64{0, 0.5, 1, 2, 5, 10}
+
+const (
+	competitionNearBestPct   = 0.5
+	competitionMaxSeats      = 10
+	competitionRelistCapHour = 4.0 // top-of-book changes per hour scored as fully aggressive
+)
+
+// CompetitionBand is the slice of the book within a price band.
+type CompetitionBand struct {
+	FromPct        float64 `json:"from_pct"`
+	ToPct          float64 `json:"to_pct"` // 0 = open-ended
+	Orders         int     `json:"orders"`
+	Volume         int64   `json:"volume"`
+	VolumeSharePct float64 `json:"volume_share_pct"`
+}
+
+// CompetitorSeat is one live order near the top of the book. ESI does not
+// expose order owners, so each order stands for one competitor.
+type CompetitorSeat struct {
+	OrderID        int64   `json:"order_id"`
+	Price          float64 `json:"price"`
+	VolumeRemain   int32   `json:"volume_remain"`
+	VolumeSharePct float64 `json:"volume_share_pct"`
+	BehindBestPct  float64 `json:"behind_best_pct"`
+}
+
+// CompetitionAnalysis describes one side of a type's book at a location.
+type CompetitionAnalysis struct {
+	Side              string            `json:"side"`
+	BestPrice         float64           `json:"best_price"`
+	Orders            int               `json:"orders"`
+	TotalVolume       int64             `json:"total_volume"`
+	Bands             []CompetitionBand `json:"bands"`
+	Seats             []CompetitorSeat  `json:"seats"`
+	TopSeatSharePct   float64           `json:"top_seat_share_pct"` // largest order's share of volume
+	HHI               float64           `json:"hhi"`                // Herfindahl index of volume shares, 0-10000
+	NearBestOrders    int               `json:"near_best_orders"`
+	Cadence           RelistCadence     `json:"cadence"` // top-of-book changes over the stored snapshots
+	CI                int               `json:"ci"`
+	Aggressiveness    float64           `json:"aggressiveness"` // 0-100
+	AggressivenessTag string            `json:"aggressiveness_tag"`
+}
+
+// AnalyzeCompetition scores one side ("sell" or "buy") of a location's book
+// for a type. live is the current book at the location; tops is the best
+// price of the same side across stored order book snapshots, used to infer
+// how often the top is repriced.
+//
+// Aggressiveness weights top-of-book changes per hour (45%), the share of
+// orders within 0.5% of the best price (30%) and the share locked in
+// 0.01 ISK wars (25%). Until an hour of snapshots exists the relist term is
+// left out and the other two are rescaled.
+func AnalyzeCompetition(live []esi.MarketOrder, tops []TopOfBookPoint, side string, now time.Time) CompetitionAnalysis {
+	buy := side == "buy"
+	a := CompetitionAnalysis{Side: side, Bands: []CompetitionBand{}, Seats: []CompetitorSeat{}}
+	a.Cadence = EstimateRelistCadence(tops, side, now)
+	var orders []esi.MarketOrder
+	for _, o := range live {
+		if o.IsBuyOrder == buy && o.Price > 0 && o.VolumeRemain > 0 {
+			orders = append(orders, o)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if orders[i].Price != orders[j].Price {
+			if buy {
+				return orders[i].Price > orders[j].Price
+			}
+			return orders[i].Price < orders[j].Price
+		}
+		return orders[i].OrderID < orders[j].OrderID
+	})
+	a.Orders = len(orders)
+	if a.Orders == 0 {
+		return a
+	}
+	a.BestPrice = orders[0].Price
+	for _, o := range orders {
+		a.TotalVolume += int64(o.VolumeRemain)
+	}
+
+	for i := range competitionBandEdges {
+		b := CompetitionBand{FromPct: competitionBandEdges[i]}
+		if i+1 < len(competitionBandEdges) {
+			b.ToPct = competitionBandEdges[i+1]
+		}
+		a.Bands = append(a.Bands, b)
+	}
+	var volShares []float64
+	for i, o := range orders {
+		behind := math.Abs(o.Price-a.BestPrice) / a.BestPrice * 100
+		band := len(a.Bands) - 1
+		for j := range a.Bands {
+			if behind >= a.Bands[j].FromPct && (a.Bands[j].ToPct == 0 || behind < a.Bands[j].ToPct) {
+				band = j
+				break
+			}
+		}
+		a.Bands[band].Orders++
+		a.Bands[band].Volume += int64(o.VolumeRemain)
+		if behind < competitionNearBestPct {
+			a.NearBestOrders++
+		}
+		share := float64(o.VolumeRemain) / float64(a.TotalVolume) * 100
+		volShares = append(volShares, share)
+		a.HHI += share * share
+		if i < competitionMaxSeats {
+			a.Seats = append(a.Seats, CompetitorSeat{
+				OrderID:        o.OrderID,
+				Price:          o.Price,
+				VolumeRemain:   o.VolumeRemain,
+				VolumeSharePct: math.Round(share*10) / 10,
+				BehindBestPct:  math.Round(behind*100) / 100,
+			})
+		}
+	}
+	for i := range a.Bands {
+		a.Bands[i].VolumeSharePct = math.Round(float64(a.Bands[i].Volume)/float64(a.TotalVolume)*1000) / 10
+	}
+	sort.Float64s(volShares)
+	a.TopSeatSharePct = math.Round(volShares[len(volShares)-1]*10) / 10
+	a.HHI = math.Round(a.HHI)
+
+	a.CI = CalcCI(orders)
+	n := float64(a.Orders)
+	tight := math.Min(float64(countTightSpreadOrders(orders))/n, 1)
+	score := 0.3*float64(a.NearBestOrders)/n + 0.25*tight
+	if a.Cadence.ObservedHours >= 1 {
+		score += 0.45 * math.Min(a.Cadence.ChangesPerHour/competitionRelistCapHour, 1)
+	} else {
+		score /= 0.55
+	}
+	score *= 100
+	a.Aggressiveness = sanitizeFloat(math.Round(score*10) / 10)
+	switch {
+	case a.Aggressiveness >= 75:
+		a.AggressivenessTag = "cutthroat"
+	case a.Aggressiveness >= 50:
+		a.AggressivenessTag = "contested"
+	case a.Aggressiveness >= 25:
+		a.AggressivenessTag = "active"
+	default:
+		a.AggressivenessTag = "calm"
+	}
+	return a
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+func TestAnalyzeCompetition(t *testing.T) {
+	now := time.Date(2026, 6, 10, 12, 0, 0, 0, time.UTC)
+	live := []esi.MarketOrder{
+		{OrderID: 1, Price: 100.00, VolumeRemain: 10},
+		{OrderID: 2, Price: 100.01, VolumeRemain: 10},
+		{OrderID: 3, Price: 101.50, VolumeRemain: 30},
+		{OrderID: 4, Price: 120.00, VolumeRemain: 50},
+		{OrderID: 5, Price: 90, VolumeRemain: 100, IsBuyOrder: true},
+	}
+	// A day of snapshots every ten minutes, the top undercut by a tick each time.
+	var tops []TopOfBookPoint
+	for i := 0; i <= 144; i++ {
+		tops = append(tops, TopOfBookPoint{At: now.Add(-24 * time.Hour).Add(time.Duration(i) * 10 * time.Minute), Price: 101.44 - float64(i)*0.01})
+	}
+
+	a := AnalyzeCompetition(live, tops, "sell", now)
+	if a.Orders != 4 || a.BestPrice != 100 || a.TotalVolume != 100 {
+		t.Fatalf("book = %+v", a)
+	}
+	// 0-0.5%: two orders, 1-2%: one, 10%+: one.
+	if a.Bands[0].Orders != 2 || a.Bands[0].VolumeSharePct != 20 || a.Bands[2].Orders != 1 || a.Bands[5].Orders != 1 {
+		t.Fatalf("bands = %+v", a.Bands)
+	}
+	if a.TopSeatSharePct != 50 || a.HHI != 100+100+900+2500 {
+		t.Fatalf("concentration = %v / %v", a.TopSeatSharePct, a.HHI)
+	}
+	if a.NearBestOrders != 2 {
+		t.Fatalf("near best = %d", a.NearBestOrders)
+	}
+	if a.Cadence.Changes != 144 || a.Cadence.ObservedHours != 24 || !a.Cadence.PennyWar {
+		t.Fatalf("cadence = %+v", a.Cadence)
+	}
+	if s := a.Seats[0]; s.OrderID != 1 || s.VolumeSharePct != 10 {
+		t.Fatalf("seat = %+v", s)
+	}
+	if a.Aggressiveness <= 50 || a.AggressivenessTag != "contested" {
+		t.Fatalf("aggressiveness = %v (%s)", a.Aggressiveness, a.AggressivenessTag)
+	}
+
+	if b := AnalyzeCompetition(live, nil, "buy", now); b.Orders != 1 || b.BestPrice != 90 || b.Cadence.Samples != 0 {
+		t.Fatalf("buy side = %+v", b)
+	}
+}
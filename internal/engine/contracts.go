@@ -8,7 +8,6 @@ import (
 	"sort"
 	"strings"
 	"sync"
-	"sync/atomic"
 
 	"eve-flipper/internal/esi"
 )
@@ -483,6 +482,7 @@ func (s *Scanner) ScanContractsWithContext(ctx context.Context, params ScanParam
 	if err := checkContextCanceled(ctx); err != nil {
 		return nil, err
 	}
+	s = s.withRouteShortcuts(ctx, params.UseWormholes, params.ansiblexEdges(), progress).withRoutePreferences(params.RoutePreferences)
 	emitProgress := func(msg string) {
 		if progress == nil {
 			return
@@ -511,6 +511,7 @@ func (s *Scanner) ScanContractsWithContext(ctx context.Context, params ScanParam
 	}
 	buyRegions := s.SDE.Universe.RegionsInSet(buySystems)
 	contractInstant := params.ContractInstantLiquidation
+	travel := params.ShipTravel()
 
 	var sellSystems map[int32]int
 	var sellRegions map[int32]bool
@@ -537,61 +538,91 @@ func (s *Scanner) ScanContractsWithContext(ctx context.Context, params ScanParam
 	var allContracts []esi.PublicContract
 	var contractsMu sync.Mutex
 	var wg sync.WaitGroup
-	var failedContractRegions int32
+	if params.FetchReport == nil {
+		params.FetchReport = &RegionFetchReport{}
+	}
+	fetchCtx := ctx
+	if params.FetchTimeout > 0 {
+		var cancel context.CancelFunc
+		fetchCtx, cancel = context.WithTimeout(ctx, params.FetchTimeout)
+		defer cancel()
+	}
 
 	emitProgress(fmt.Sprintf("Fetching market orders + contracts from %d regions...", len(buyRegions)))
 
-	wg.Add(2)
+	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		sellOrders = s.fetchOrders(buyRegions, "sell", buySystems)
+		sellOrders = s.fetchOrders(fetchCtx, buyRegions, "sell", buySystems, params.FetchReport)
 	}()
 	if contractInstant {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			buyOrdersForLiquidation = s.fetchOrders(sellRegions, "buy", sellSystems)
+			buyOrdersForLiquidation = s.fetchOrders(fetchCtx, sellRegions, "buy", sellSystems, params.FetchReport)
 		}()
 	}
+	// Fetch contracts from ALL regions in PARALLEL (with caching). Contract
+	// fetches cannot be aborted, so regions still pending when the fetch
+	// timeout expires are recorded as failed and their late results dropped.
+	pendingContractRegions := make(map[int32]bool, len(buyRegions))
+	contractsClosed := false
+	var contractsWg sync.WaitGroup
+	for rid := range buyRegions {
+		pendingContractRegions[rid] = true
+		contractsWg.Add(1)
+		go func(regionID int32) {
+			defer contractsWg.Done()
+			contracts, err := s.ESI.FetchRegionContractsCached(s.ContractsCache, regionID)
+			contractsMu.Lock()
+			defer contractsMu.Unlock()
+			if contractsClosed {
+				return
+			}
+			delete(pendingContractRegions, regionID)
+			if err != nil {
+				log.Printf("[DEBUG] failed to fetch contracts for region %d: %v", regionID, err)
+				params.FetchReport.add(regionID, "contracts", err)
+				return
+			}
+			allContracts = append(allContracts, contracts...)
+		}(rid)
+	}
+	ordersDone := make(chan struct{})
 	go func() {
-		defer wg.Done()
-		// Fetch contracts from ALL regions in PARALLEL (with caching)
-		var contractsWg sync.WaitGroup
-		for rid := range buyRegions {
-			contractsWg.Add(1)
-			go func(regionID int32) {
-				defer contractsWg.Done()
-				contracts, err := s.ESI.FetchRegionContractsCached(s.ContractsCache, regionID)
-				if err != nil {
-					atomic.AddInt32(&failedContractRegions, 1)
-					log.Printf("[DEBUG] failed to fetch contracts for region %d: %v", regionID, err)
-					return
-				}
-				contractsMu.Lock()
-				allContracts = append(allContracts, contracts...)
-				contractsMu.Unlock()
-			}(rid)
-		}
-		contractsWg.Wait()
+		wg.Wait()
+		close(ordersDone)
 	}()
-	fetchDone := make(chan struct{})
+	contractsDone := make(chan struct{})
 	go func() {
-		wg.Wait()
-		close(fetchDone)
+		contractsWg.Wait()
+		close(contractsDone)
 	}()
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
-	case <-fetchDone:
+	case <-ordersDone:
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-contractsDone:
+	case <-fetchCtx.Done():
+	}
+	contractsMu.Lock()
+	contractsClosed = true
+	for regionID := range pendingContractRegions {
+		params.FetchReport.add(regionID, "contracts", context.DeadlineExceeded)
+	}
+	contractsMu.Unlock()
+	if err := checkRegionFailures(params, emitProgress); err != nil {
+		return nil, err
 	}
 
 	log.Printf("[DEBUG] ScanContracts: %d sell orders, %d contracts total", len(sellOrders), len(allContracts))
 	if contractInstant {
 		log.Printf("[DEBUG] ScanContracts: instant liquidation enabled, %d buy orders in sell radius", len(buyOrdersForLiquidation))
 	}
-	if failed := atomic.LoadInt32(&failedContractRegions); failed > 0 {
-		emitProgress(fmt.Sprintf("Warning: contracts missing in %d regions due to ESI errors", failed))
-	}
 
 	// Build location -> system map from market orders (covers player structures
 	// that are not present in SDE.Stations).
@@ -679,6 +710,9 @@ func (s *Scanner) ScanContractsWithContext(ctx context.Context, params ScanParam
 		if c.Price < minContractPrice {
 			continue // skip scam/bait contracts with very low prices
 		}
+		if params.MaxBudget > 0 && c.Price > params.MaxBudget {
+			continue // cannot afford
+		}
 		// Pre-filter: skip contracts in unknown or unreachable locations.
 		// If we can't map location -> system, we can't verify accessibility.
 		sysID := s.locationToSystem(c.StartLocationID, marketLocationSystems)
@@ -1149,6 +1183,8 @@ func (s *Scanner) ScanContractsWithContext(ctx context.Context, params ScanParam
 		if jumps > 0 {
 			profitPerJump = kpiProfit / float64(jumps)
 		}
+		executionMinutes := math.Round(travel.HaulMinutes(pickupJumps, liquidationJumps,
+			routeCargoTrips(contract.Volume, params.CargoCapacity))*10) / 10
 
 		results = append(results, ContractResult{
 			ContractID:            contract.ContractID,
@@ -1178,6 +1214,9 @@ func (s *Scanner) ScanContractsWithContext(ctx context.Context, params ScanParam
 			LiquidationJumps:      liquidationJumps,
 			Jumps:                 jumps,
 			ProfitPerJump:         sanitizeFloat(profitPerJump),
+			ExecutionMinutes:      sanitizeFloat(executionMinutes),
+			ProfitPerHour:         ISKPerHour(kpiProfit, executionMinutes),
+			includedItems:         includedQtyByType,
 		})
 	}
 
@@ -1242,16 +1281,9 @@ func (s *Scanner) fetchContractItemsHistory(typeIDs map[int32]bool, priceData ma
 			defer wg.Done()
 			defer func() { <-sem }()
 
-			// Try cache first
-			entries, ok := s.History.GetMarketHistory(regionID, tid)
-			if !ok {
-				// Fetch from ESI
-				var err error
-				entries, err = s.ESI.FetchMarketHistory(regionID, tid)
-				if err != nil {
-					return
-				}
-				s.History.SetMarketHistory(regionID, tid, entries)
+			entries, err := s.MarketHistory(regionID, tid)
+			if err != nil {
+				return
 			}
 
 			if len(entries) == 0 {
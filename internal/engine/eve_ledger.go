@@ -28,6 +28,7 @@ type EveLedgerDashboard struct {
 	Archive    EveLedgerArchiveInfo     `json:"archive"`
 	Warnings   []string                 `json:"warnings,omitempty"`
 	Portfolio  *PortfolioPnL            `json:"portfolio,omitempty"`
+	Fees       *FeesPaidReport          `json:"fees,omitempty"`
 }
 
 type EveLedgerSettings struct {
@@ -303,6 +304,7 @@ func ComputeEveLedgerDashboard(
 			BrokerFeePercent: opt.BrokerFeePercent,
 		},
 		Portfolio: portfolio,
+		Fees:      ComputeFeesPaid(journal, txns, orders, start, opt.SalesTaxPercent, opt.BrokerFeePercent),
 	}
 }
 
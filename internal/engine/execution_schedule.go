@@ -0,0 +1,321 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+// Execution schedule profiles: TWAP expects the same volume every day, VWAP
+// weights days by the item's weekday volume pattern.
+const (
+	ScheduleProfileTWAP = "twap"
+	ScheduleProfileVWAP = "vwap"
+)
+
+const (
+	// DefaultScheduleParticipation is the default max share of a day's volume.
+	DefaultScheduleParticipation = 0.2
+	scheduleMaxDays              = 365
+	scheduleOnTrackSlack         = 0.9 // filled ≥ 90% of plan counts as on track
+)
+
+// ScheduleRequest describes a position to work over several days.
+type ScheduleRequest struct {
+	Quantity         int64
+	IsBuy            bool
+	MaxParticipation float64 // max share of expected daily volume, 0-1
+	DeadlineDays     int     // 0 = as fast as participation allows
+	Profile          string  // twap | vwap
+	Start            time.Time
+	RefPrice         float64 // current best price on the side being hit
+}
+
+// ScheduleDay is one day of the plan.
+type ScheduleDay struct {
+	Day              int     `json:"day"`
+	Date             string  `json:"date"`
+	ExpectedVolume   float64 `json:"expected_volume"`
+	Quantity         int64   `json:"quantity"`
+	CumulativeQty    int64   `json:"cumulative_qty"`
+	ParticipationPct float64 `json:"participation_pct"`
+	ImpactPct        float64 `json:"impact_pct"`
+	ExpectedPrice    float64 `json:"expected_price"`
+	PastDeadline     bool    `json:"past_deadline,omitempty"`
+	ActualQty        int64   `json:"actual_qty"`
+}
+
+// ScheduleFill is one executed trade counted against the schedule.
+type ScheduleFill struct {
+	At       time.Time
+	Quantity int64
+	Price    float64
+}
+
+// ScheduleProgress compares fills since the start with the plan.
+type ScheduleProgress struct {
+	FilledQty     int64   `json:"filled_qty"`
+	FilledPct     float64 `json:"filled_pct"`
+	AvgFillPrice  float64 `json:"avg_fill_price"`
+	ExpectedByNow int64   `json:"expected_by_now"`
+	BehindQty     int64   `json:"behind_qty"` // negative = ahead of plan
+	Status        string  `json:"status"`     // ahead | on_track | behind | done
+}
+
+// ExecutionSchedule is a day-by-day plan for a large position.
+type ExecutionSchedule struct {
+	Quantity         int64             `json:"quantity"`
+	IsBuy            bool              `json:"is_buy"`
+	Profile          string            `json:"profile"`
+	MaxParticipation float64           `json:"max_participation_pct"`
+	DeadlineDays     int               `json:"deadline_days"`
+	AvgDailyVolume   float64           `json:"avg_daily_volume"`
+	RefPrice         float64           `json:"ref_price"`
+	TotalDays        int               `json:"total_days"`
+	DeadlineMet      bool              `json:"deadline_met"`
+	AvgImpactPct     float64           `json:"avg_impact_pct"`
+	ExpectedValue    float64           `json:"expected_value"` // ISK paid (buy) or received (sell)
+	Days             []ScheduleDay     `json:"days"`
+	Progress         *ScheduleProgress `json:"progress,omitempty"`
+	Warnings         []string          `json:"warnings,omitempty"`
+}
+
+// BuildExecutionSchedule splits req.Quantity across days so that no day
+// takes more than MaxParticipation of its expected volume. With a deadline
+// the quantity is spread over the deadline in proportion to expected volume,
+// which keeps each day's impact low; whatever still does not fit runs past
+// the deadline at full participation. Without one, every day runs at full
+// participation until done. Each day's impact follows the square-root law
+// with the calibrated prefactor (RangeSlope, else σ).
+func BuildExecutionSchedule(params ImpactParams, history []esi.HistoryEntry, req ScheduleRequest) ExecutionSchedule {
+	if req.MaxParticipation <= 0 || req.MaxParticipation > 1 {
+		req.MaxParticipation = DefaultScheduleParticipation
+	}
+	if req.Profile != ScheduleProfileVWAP {
+		req.Profile = ScheduleProfileTWAP
+	}
+	out := ExecutionSchedule{
+		Quantity:         req.Quantity,
+		IsBuy:            req.IsBuy,
+		Profile:          req.Profile,
+		MaxParticipation: math.Round(req.MaxParticipation*1000) / 10,
+		DeadlineDays:     req.DeadlineDays,
+		AvgDailyVolume:   sanitizeFloat(math.Round(params.AvgDailyVolume*10) / 10),
+		RefPrice:         req.RefPrice,
+		Days:             []ScheduleDay{},
+	}
+	if req.Quantity <= 0 {
+		return out
+	}
+	if params.AvgDailyVolume <= 0 {
+		out.Warnings = append(out.Warnings, "no daily volume history; cannot schedule")
+		return out
+	}
+
+	weekday := [7]float64{1, 1, 1, 1, 1, 1, 1}
+	if req.Profile == ScheduleProfileVWAP {
+		weekday = weekdayVolumeFactors(history)
+	}
+	start := req.Start.UTC().Truncate(24 * time.Hour)
+	expected := func(day int) float64 {
+		return params.AvgDailyVolume * weekday[start.AddDate(0, 0, day).Weekday()]
+	}
+	capacity := func(day int) int64 {
+		c := int64(math.Floor(req.MaxParticipation * expected(day)))
+		if c < 1 {
+			c = 1
+		}
+		return c
+	}
+
+	var alloc []int64
+	if req.DeadlineDays > 0 {
+		alloc = spreadOverDeadline(req.Quantity, req.DeadlineDays, expected, capacity)
+	}
+	var placed int64
+	for _, q := range alloc {
+		placed += q
+	}
+	for placed < req.Quantity && len(alloc) < scheduleMaxDays {
+		q := capacity(len(alloc))
+		if q > req.Quantity-placed {
+			q = req.Quantity - placed
+		}
+		alloc = append(alloc, q)
+		placed += q
+	}
+	// Drop empty trailing days a deadline spread may leave.
+	for len(alloc) > 0 && alloc[len(alloc)-1] == 0 {
+		alloc = alloc[:len(alloc)-1]
+	}
+
+	prefactor := params.Sigma
+	if params.RangeSlope > 0 {
+		prefactor = params.RangeSlope
+	}
+	var cum int64
+	var impactSum float64
+	for i, q := range alloc {
+		vol := expected(i)
+		impact := ImpactSqrtPct(prefactor, float64(q), vol)
+		price := req.RefPrice * (1 - impact/100)
+		if req.IsBuy {
+			price = req.RefPrice * (1 + impact/100)
+		}
+		cum += q
+		out.Days = append(out.Days, ScheduleDay{
+			Day:              i + 1,
+			Date:             start.AddDate(0, 0, i).Format("2006-01-02"),
+			ExpectedVolume:   math.Round(vol*10) / 10,
+			Quantity:         q,
+			CumulativeQty:    cum,
+			ParticipationPct: sanitizeFloat(math.Round(float64(q)/vol*1000) / 10),
+			ImpactPct:        sanitizeFloat(math.Round(impact*100) / 100),
+			ExpectedPrice:    sanitizeFloat(price),
+			PastDeadline:     req.DeadlineDays > 0 && i >= req.DeadlineDays,
+		})
+		impactSum += impact * float64(q)
+		out.ExpectedValue += price * float64(q)
+	}
+	out.TotalDays = len(out.Days)
+	out.ExpectedValue = sanitizeFloat(math.Round(out.ExpectedValue))
+	if cum > 0 {
+		out.AvgImpactPct = sanitizeFloat(math.Round(impactSum/float64(cum)*100) / 100)
+	}
+	out.DeadlineMet = cum == req.Quantity && (req.DeadlineDays == 0 || out.TotalDays <= req.DeadlineDays)
+	if cum < req.Quantity {
+		out.Warnings = append(out.Warnings, fmt.Sprintf("%d units left after %d days at %.0f%% participation", req.Quantity-cum, scheduleMaxDays, req.MaxParticipation*100))
+	} else if !out.DeadlineMet {
+		out.Warnings = append(out.Warnings, fmt.Sprintf("deadline needs more than %.0f%% participation; plan runs %d days", req.MaxParticipation*100, out.TotalDays))
+	}
+	if req.RefPrice <= 0 {
+		out.Warnings = append(out.Warnings, "no live price; expected prices are zero")
+	}
+	return out
+}
+
+// spreadOverDeadline allocates quantity over days in proportion to expected
+// volume, capping each day at its capacity and handing the excess to the
+// uncapped days until nothing changes.
+func spreadOverDeadline(quantity int64, days int, expected func(int) float64, capacity func(int) int64) []int64 {
+	alloc := make([]int64, days)
+	capped := make([]bool, days)
+	remaining := quantity
+	for remaining > 0 {
+		var weight float64
+		for d := 0; d < days; d++ {
+			if !capped[d] {
+				weight += expected(d)
+			}
+		}
+		if weight <= 0 {
+			break
+		}
+		changed := false
+		left := remaining
+		for d := 0; d < days && left > 0; d++ {
+			if capped[d] {
+				continue
+			}
+			share := int64(math.Ceil(float64(remaining) * expected(d) / weight))
+			if room := capacity(d) - alloc[d]; share >= room {
+				share = room
+				capped[d] = true
+				changed = true
+			}
+			if share > left {
+				share = left
+			}
+			alloc[d] += share
+			left -= share
+		}
+		if left == remaining && !changed {
+			break
+		}
+		remaining = left
+	}
+	return alloc
+}
+
+// weekdayVolumeFactors returns each weekday's average volume relative to the
+// overall daily average over the last 90 days; weekdays without data are 1.
+func weekdayVolumeFactors(history []esi.HistoryEntry) [7]float64 {
+	var sum [7]float64
+	var n [7]int
+	var total float64
+	var days int
+	for _, h := range filterLastNDays(history, 90) {
+		t, err := time.Parse("2006-01-02", h.Date)
+		if err != nil {
+			continue
+		}
+		sum[t.Weekday()] += float64(h.Volume)
+		n[t.Weekday()]++
+		total += float64(h.Volume)
+		days++
+	}
+	out := [7]float64{1, 1, 1, 1, 1, 1, 1}
+	if days == 0 || total <= 0 {
+		return out
+	}
+	avg := total / float64(days)
+	for d := range out {
+		if n[d] > 0 {
+			out[d] = sum[d] / float64(n[d]) / avg
+		}
+	}
+	return out
+}
+
+// TrackScheduleProgress books fills onto the plan's days and compares the
+// total with what the plan expected by now (days fully elapsed plus the
+// elapsed share of today).
+func TrackScheduleProgress(s *ExecutionSchedule, fills []ScheduleFill, now time.Time) {
+	p := &ScheduleProgress{}
+	var notional float64
+	byDate := make(map[string]int, len(s.Days))
+	for i, d := range s.Days {
+		byDate[d.Date] = i
+	}
+	for _, f := range fills {
+		if f.Quantity <= 0 {
+			continue
+		}
+		p.FilledQty += f.Quantity
+		notional += f.Price * float64(f.Quantity)
+		if i, ok := byDate[f.At.UTC().Format("2006-01-02")]; ok {
+			s.Days[i].ActualQty += f.Quantity
+		}
+	}
+	if p.FilledQty > 0 {
+		p.AvgFillPrice = sanitizeFloat(notional / float64(p.FilledQty))
+	}
+	if s.Quantity > 0 {
+		p.FilledPct = math.Round(float64(p.FilledQty)/float64(s.Quantity)*1000) / 10
+	}
+	for _, d := range s.Days {
+		dayStart, err := time.Parse("2006-01-02", d.Date)
+		if err != nil {
+			continue
+		}
+		elapsed := now.Sub(dayStart).Hours() / 24
+		if elapsed <= 0 {
+			break
+		}
+		p.ExpectedByNow += int64(math.Round(float64(d.Quantity) * math.Min(elapsed, 1)))
+	}
+	p.BehindQty = p.ExpectedByNow - p.FilledQty
+	switch {
+	case p.FilledQty >= s.Quantity:
+		p.Status = "done"
+	case float64(p.FilledQty) < scheduleOnTrackSlack*float64(p.ExpectedByNow):
+		p.Status = "behind"
+	case p.FilledQty > p.ExpectedByNow:
+		p.Status = "ahead"
+	default:
+		p.Status = "on_track"
+	}
+	s.Progress = p
+}
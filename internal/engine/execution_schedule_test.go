@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+func TestBuildExecutionSchedule_ParticipationAndDeadline(t *testing.T) {
+	params := ImpactParams{Sigma: 0.03, AvgDailyVolume: 40, Valid: true}
+	start := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	req := ScheduleRequest{Quantity: 500, MaxParticipation: 0.2, Start: start, RefPrice: 200e6}
+
+	// No deadline: 8 units a day (20% of 40) until done.
+	s := BuildExecutionSchedule(params, nil, req)
+	if s.TotalDays != 63 || !s.DeadlineMet {
+		t.Fatalf("TotalDays = %d, DeadlineMet = %v; want 63, true", s.TotalDays, s.DeadlineMet)
+	}
+	if s.Days[0].Quantity != 8 || s.Days[62].Quantity != 4 || s.Days[62].CumulativeQty != 500 {
+		t.Fatalf("first/last day = %+v / %+v", s.Days[0], s.Days[62])
+	}
+	if s.Days[0].ExpectedPrice >= req.RefPrice {
+		t.Fatalf("sell price %v should be below ref %v", s.Days[0].ExpectedPrice, req.RefPrice)
+	}
+
+	// A loose deadline spreads the order thinner than the cap.
+	req.DeadlineDays = 100
+	s = BuildExecutionSchedule(params, nil, req)
+	if s.TotalDays != 100 || !s.DeadlineMet || s.Days[0].Quantity != 5 {
+		t.Fatalf("loose deadline: days %d met %v first %d; want 100 true 5", s.TotalDays, s.DeadlineMet, s.Days[0].Quantity)
+	}
+	if s.AvgImpactPct >= BuildExecutionSchedule(params, nil, ScheduleRequest{Quantity: 500, MaxParticipation: 0.2, Start: start, RefPrice: 200e6}).AvgImpactPct {
+		t.Fatalf("spreading over the deadline should lower impact")
+	}
+
+	// A tight deadline runs over at full participation and says so.
+	req.DeadlineDays = 30
+	s = BuildExecutionSchedule(params, nil, req)
+	if s.DeadlineMet || s.TotalDays != 63 || !s.Days[30].PastDeadline || len(s.Warnings) == 0 {
+		t.Fatalf("tight deadline: met %v days %d warnings %v", s.DeadlineMet, s.TotalDays, s.Warnings)
+	}
+}
+
+func TestBuildExecutionSchedule_VWAPFollowsWeekdays(t *testing.T) {
+	var history []esi.HistoryEntry
+	day := time.Now().UTC().AddDate(0, 0, -56)
+	for i := 0; i < 56; i++ {
+		d := day.AddDate(0, 0, i)
+		vol := int64(100)
+		if d.Weekday() == time.Sunday {
+			vol = 400
+		}
+		history = append(history, esi.HistoryEntry{Date: d.Format("2006-01-02"), Average: 10, Volume: vol})
+	}
+	params := ImpactParams{Sigma: 0.02, AvgDailyVolume: 1000, Valid: true}
+	start := time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC) // Saturday
+	s := BuildExecutionSchedule(params, history, ScheduleRequest{Quantity: 600, DeadlineDays: 2, Profile: ScheduleProfileVWAP, Start: start, RefPrice: 10})
+	if len(s.Days) != 2 || s.Days[1].Quantity <= s.Days[0].Quantity*3 {
+		t.Fatalf("VWAP days = %+v; want Sunday to carry ~4x Saturday", s.Days)
+	}
+}
+
+func TestTrackScheduleProgress(t *testing.T) {
+	start := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	s := BuildExecutionSchedule(ImpactParams{Sigma: 0.03, AvgDailyVolume: 40, Valid: true}, nil,
+		ScheduleRequest{Quantity: 500, MaxParticipation: 0.2, DeadlineDays: 100, Start: start, RefPrice: 100})
+	fills := []ScheduleFill{
+		{At: start.Add(2 * time.Hour), Quantity: 12, Price: 99},
+		{At: start.AddDate(0, 0, 3), Quantity: 8, Price: 101},
+	}
+	TrackScheduleProgress(&s, fills, start.AddDate(0, 0, 10).Add(12*time.Hour))
+
+	p := s.Progress
+	if p.FilledQty != 20 || p.AvgFillPrice != 99.8 || p.FilledPct != 4 {
+		t.Fatalf("progress = %+v", p)
+	}
+	if p.ExpectedByNow != 53 || p.BehindQty != 33 || p.Status != "behind" {
+		t.Fatalf("progress vs plan = %+v; want 53 expected, 33 behind", p)
+	}
+	if s.Days[0].ActualQty != 12 || s.Days[3].ActualQty != 8 {
+		t.Fatalf("actual qty not booked onto days: %d, %d", s.Days[0].ActualQty, s.Days[3].ActualQty)
+	}
+}
@@ -60,3 +60,47 @@ func tradeFeeMultipliers(in tradeFeeInputs) (buyCostMult, sellRevenueMult float6
 	}
 	return
 }
+
+// tradeFees resolves fee multipliers per trade. With a broker fee schedule the
+// broker fee at NPC stations is the character's standings-based rate instead
+// of the configured one. A side configured with no broker fee (instant
+// trades) stays at zero.
+type tradeFees struct {
+	in      tradeFeeInputs
+	brokers *BrokerFeeSchedule
+}
+
+func (f tradeFees) inputsAt(buyLocationID, sellLocationID int64) tradeFeeInputs {
+	if f.brokers == nil {
+		return f.in
+	}
+	in := normalizeTradeFees(f.in)
+	in.SplitTradeFees = true
+	if fee, ok := f.brokers.StationPercent(buyLocationID); ok && in.BuyBrokerFeePercent > 0 {
+		in.BuyBrokerFeePercent = fee
+	}
+	if fee, ok := f.brokers.StationPercent(sellLocationID); ok && in.SellBrokerFeePercent > 0 {
+		in.SellBrokerFeePercent = fee
+	}
+	return in
+}
+
+// multipliers returns fee multipliers for buying at buyLocationID and
+// selling at sellLocationID.
+func (f tradeFees) multipliers(buyLocationID, sellLocationID int64) (buyCostMult, sellRevenueMult float64) {
+	return tradeFeeMultipliers(f.inputsAt(buyLocationID, sellLocationID))
+}
+
+// floorMultipliers are the most favorable multipliers any location could
+// give, for pre-filters that run before locations are known.
+func (f tradeFees) floorMultipliers() (buyCostMult, sellRevenueMult float64) {
+	if f.brokers == nil {
+		return tradeFeeMultipliers(f.in)
+	}
+	in := normalizeTradeFees(f.in)
+	in.SplitTradeFees = true
+	floor := f.brokers.MinPercent()
+	in.BuyBrokerFeePercent = min(in.BuyBrokerFeePercent, floor)
+	in.SellBrokerFeePercent = min(in.SellBrokerFeePercent, floor)
+	return tradeFeeMultipliers(in)
+}
@@ -0,0 +1,291 @@
+package engine
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+// Fee categories tracked by ComputeFeesPaid.
+const (
+	FeeBroker   = "broker"
+	FeeRelist   = "relist"
+	FeeSalesTax = "sales_tax"
+	FeeContract = "contract"
+)
+
+var feeLabels = map[string]string{
+	FeeBroker:   "Broker fees",
+	FeeRelist:   "Relist fees",
+	FeeSalesTax: "Sales tax",
+	FeeContract: "Contract fees",
+}
+
+// Reference rates for savings estimates (NPC stations; player structures set
+// their own broker fee). Sales tax is 7.5% reduced 11% per Accounting level;
+// broker fee is 3% minus 0.3% per Broker Relations level and 0.03%/0.02% per
+// point of faction/corp standing.
+const (
+	salesTaxAccountingV        = 3.375
+	brokerFeeBrokerRelationsV  = 1.5
+	brokerFeeMaxStandings      = 1.0
+	feesPaidItemLimit          = 25
+	feesPaidContextTransaction = "market_transaction_id"
+	feesPaidRelistMarker       = "modif"
+)
+
+// FeesPaidReport aggregates the ISK a character lost to trading fees.
+type FeesPaidReport struct {
+	TotalISK        float64            `json:"total_isk"`
+	UnattributedISK float64            `json:"unattributed_isk"` // fees not tied to an item
+	Categories      []FeesPaidCategory `json:"categories"`
+	Monthly         []FeesPaidMonth    `json:"monthly"`
+	Items           []FeesPaidItem     `json:"items"`
+	Savings         []FeeSavings       `json:"savings"`
+}
+
+type FeesPaidCategory struct {
+	Key     string  `json:"key"`
+	Label   string  `json:"label"`
+	ISK     float64 `json:"isk"`
+	Entries int     `json:"entries"`
+}
+
+type FeesPaidMonth struct {
+	Month       string  `json:"month"` // YYYY-MM
+	BrokerISK   float64 `json:"broker_isk"`
+	RelistISK   float64 `json:"relist_isk"`
+	SalesTaxISK float64 `json:"sales_tax_isk"`
+	ContractISK float64 `json:"contract_isk"`
+	TotalISK    float64 `json:"total_isk"`
+}
+
+type FeesPaidItem struct {
+	TypeID      int32   `json:"type_id"`
+	TypeName    string  `json:"type_name,omitempty"`
+	BrokerISK   float64 `json:"broker_isk"` // includes relists
+	SalesTaxISK float64 `json:"sales_tax_isk"`
+	TotalISK    float64 `json:"total_isk"`
+}
+
+// FeeSavings estimates what fees would have been at a better rate.
+type FeeSavings struct {
+	Key                string  `json:"key"`
+	Label              string  `json:"label"`
+	CurrentRatePercent float64 `json:"current_rate_percent"`
+	TargetRatePercent  float64 `json:"target_rate_percent"`
+	PaidISK            float64 `json:"paid_isk"`
+	SavedISK           float64 `json:"saved_isk"`
+}
+
+// feeCategory maps a journal ref type to a fee category, or "".
+func feeCategory(entry esi.WalletJournalEntry) string {
+	switch strings.ToLower(strings.TrimSpace(entry.RefType)) {
+	case "brokers_fee":
+		if strings.Contains(strings.ToLower(entry.Description), feesPaidRelistMarker) {
+			return FeeRelist
+		}
+		return FeeBroker
+	case "transaction_tax":
+		return FeeSalesTax
+	case "contract_brokers_fee", "contract_sales_tax":
+		return FeeContract
+	}
+	return ""
+}
+
+// ComputeFeesPaid totals broker, relist, sales tax and contract fees from the
+// wallet journal since start, by month and by item. Fees are attributed to
+// items through the journal context (market transaction or order ID) or,
+// for broker fees, an open order issued at the same second. Repeat broker fees
+// on the same order are counted as relists.
+func ComputeFeesPaid(
+	journal []esi.WalletJournalEntry,
+	txns []esi.WalletTransaction,
+	orders []esi.CharacterOrder,
+	start time.Time,
+	salesTaxPercent float64,
+	brokerFeePercent float64,
+) *FeesPaidReport {
+	txByID := make(map[int64]esi.WalletTransaction, len(txns))
+	for _, t := range txns {
+		txByID[t.TransactionID] = t
+	}
+	orderByID := make(map[int64]esi.CharacterOrder, len(orders))
+	orderByIssued := make(map[string]esi.CharacterOrder, len(orders))
+	for _, o := range orders {
+		orderByID[o.OrderID] = o
+		if t, err := time.Parse(time.RFC3339, o.Issued); err == nil {
+			orderByIssued[t.UTC().Format(time.RFC3339)] = o
+		}
+	}
+
+	type feeRow struct {
+		entry    esi.WalletJournalEntry
+		at       time.Time
+		category string
+		amount   float64
+	}
+	var rows []feeRow
+	for _, e := range journal {
+		cat := feeCategory(e)
+		if cat == "" || e.Amount >= 0 {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, e.Date)
+		if err != nil || t.Before(start) {
+			continue
+		}
+		rows = append(rows, feeRow{entry: e, at: t.UTC(), category: cat, amount: -e.Amount})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].at.Equal(rows[j].at) {
+			return rows[i].at.Before(rows[j].at)
+		}
+		return rows[i].entry.ID < rows[j].entry.ID
+	})
+
+	report := &FeesPaidReport{
+		Categories: []FeesPaidCategory{},
+		Monthly:    []FeesPaidMonth{},
+		Items:      []FeesPaidItem{},
+		Savings:    []FeeSavings{},
+	}
+	categories := make(map[string]*FeesPaidCategory)
+	months := make(map[string]*FeesPaidMonth)
+	items := make(map[int32]*FeesPaidItem)
+	brokerOrdersSeen := make(map[int64]bool)
+	var taxedTurnover, taxOnTurnover float64
+
+	for _, r := range rows {
+		cat := r.category
+		var typeID int32
+		var typeName string
+		switch cat {
+		case FeeSalesTax:
+			if t, ok := txByID[r.entry.ContextID]; ok && r.entry.ContextIDType == feesPaidContextTransaction {
+				typeID, typeName = t.TypeID, t.TypeName
+				taxedTurnover += t.UnitPrice * float64(t.Quantity)
+				taxOnTurnover += r.amount
+			}
+		case FeeBroker, FeeRelist:
+			o, ok := orderByID[r.entry.ContextID]
+			if !ok || r.entry.ContextID == 0 {
+				o, ok = orderByIssued[r.at.Format(time.RFC3339)]
+			}
+			if ok {
+				typeID, typeName = o.TypeID, o.TypeName
+				if brokerOrdersSeen[o.OrderID] {
+					cat = FeeRelist
+				}
+				brokerOrdersSeen[o.OrderID] = true
+			}
+		}
+
+		report.TotalISK += r.amount
+		c := categories[cat]
+		if c == nil {
+			c = &FeesPaidCategory{Key: cat, Label: feeLabels[cat]}
+			categories[cat] = c
+		}
+		c.ISK += r.amount
+		c.Entries++
+
+		key := r.at.Format("2006-01")
+		m := months[key]
+		if m == nil {
+			m = &FeesPaidMonth{Month: key}
+			months[key] = m
+		}
+		switch cat {
+		case FeeBroker:
+			m.BrokerISK += r.amount
+		case FeeRelist:
+			m.RelistISK += r.amount
+		case FeeSalesTax:
+			m.SalesTaxISK += r.amount
+		case FeeContract:
+			m.ContractISK += r.amount
+		}
+		m.TotalISK += r.amount
+
+		if typeID == 0 {
+			report.UnattributedISK += r.amount
+			continue
+		}
+		it := items[typeID]
+		if it == nil {
+			it = &FeesPaidItem{TypeID: typeID}
+			items[typeID] = it
+		}
+		if it.TypeName == "" {
+			it.TypeName = typeName
+		}
+		if cat == FeeSalesTax {
+			it.SalesTaxISK += r.amount
+		} else {
+			it.BrokerISK += r.amount
+		}
+		it.TotalISK += r.amount
+	}
+
+	for _, key := range []string{FeeBroker, FeeRelist, FeeSalesTax, FeeContract} {
+		if c := categories[key]; c != nil {
+			report.Categories = append(report.Categories, *c)
+		}
+	}
+	for _, m := range months {
+		report.Monthly = append(report.Monthly, *m)
+	}
+	sort.Slice(report.Monthly, func(i, j int) bool { return report.Monthly[i].Month < report.Monthly[j].Month })
+	for _, it := range items {
+		report.Items = append(report.Items, *it)
+	}
+	sort.Slice(report.Items, func(i, j int) bool {
+		if report.Items[i].TotalISK != report.Items[j].TotalISK {
+			return report.Items[i].TotalISK > report.Items[j].TotalISK
+		}
+		return report.Items[i].TypeID < report.Items[j].TypeID
+	})
+	if len(report.Items) > feesPaidItemLimit {
+		report.Items = report.Items[:feesPaidItemLimit]
+	}
+
+	// Prefer the sales tax rate observed on matched transactions over config.
+	taxRate := salesTaxPercent
+	if taxedTurnover > 0 {
+		taxRate = taxOnTurnover / taxedTurnover * 100
+	}
+	brokerPaid := categoryISK(categories, FeeBroker) + categoryISK(categories, FeeRelist)
+	report.Savings = appendFeeSavings(report.Savings, "accounting_v", "Accounting V",
+		taxRate, salesTaxAccountingV, categoryISK(categories, FeeSalesTax))
+	report.Savings = appendFeeSavings(report.Savings, "broker_relations_v", "Broker Relations V",
+		brokerFeePercent, brokerFeeBrokerRelationsV, brokerPaid)
+	report.Savings = appendFeeSavings(report.Savings, "max_standings", "Broker Relations V + 10.0 standings",
+		brokerFeePercent, brokerFeeMaxStandings, brokerPaid)
+	return report
+}
+
+func categoryISK(categories map[string]*FeesPaidCategory, key string) float64 {
+	if c := categories[key]; c != nil {
+		return c.ISK
+	}
+	return 0
+}
+
+// appendFeeSavings adds an estimate when paid fees were charged above target.
+func appendFeeSavings(out []FeeSavings, key, label string, current, target, paid float64) []FeeSavings {
+	if paid <= 0 || current <= target {
+		return out
+	}
+	return append(out, FeeSavings{
+		Key:                key,
+		Label:              label,
+		CurrentRatePercent: current,
+		TargetRatePercent:  target,
+		PaidISK:            paid,
+		SavedISK:           paid * (1 - target/current),
+	})
+}
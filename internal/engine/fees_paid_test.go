@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+func TestComputeFeesPaid_CategoriesMonthsAndItems(t *testing.T) {
+	journal := []esi.WalletJournalEntry{
+		{ID: 1, Date: "2026-08-03T10:00:00Z", RefType: "brokers_fee", Amount: -300, ContextID: 20, ContextIDType: "market_transaction_id"},
+		{ID: 2, Date: "2026-08-04T10:00:00Z", RefType: "brokers_fee", Amount: -100, ContextID: 20, ContextIDType: "market_transaction_id"},
+		{ID: 3, Date: "2026-09-01T12:00:00Z", RefType: "transaction_tax", Amount: -45, ContextID: 11, ContextIDType: "market_transaction_id"},
+		{ID: 4, Date: "2026-09-02T12:00:00Z", RefType: "contract_brokers_fee", Amount: -50},
+		{ID: 5, Date: "2026-09-05T08:30:00Z", RefType: "brokers_fee", Amount: -20},
+		{ID: 6, Date: "2026-09-06T08:30:00Z", RefType: "market_transaction", Amount: 1000},
+		{ID: 7, Date: "2025-01-01T00:00:00Z", RefType: "brokers_fee", Amount: -999},
+	}
+	txns := []esi.WalletTransaction{
+		{TransactionID: 11, TypeID: 34, TypeName: "Tritanium", UnitPrice: 100, Quantity: 10},
+	}
+	orders := []esi.CharacterOrder{
+		{OrderID: 20, TypeID: 34, TypeName: "Tritanium", Issued: "2026-08-04T10:00:00Z"},
+		{OrderID: 21, TypeID: 35, TypeName: "Pyerite", Issued: "2026-09-05T08:30:00Z"},
+	}
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	r := ComputeFeesPaid(journal, txns, orders, start, 7.5, 3)
+
+	if r.TotalISK != 515 {
+		t.Fatalf("TotalISK = %v, want 515", r.TotalISK)
+	}
+	if r.UnattributedISK != 50 {
+		t.Errorf("UnattributedISK = %v, want 50 (contract fee)", r.UnattributedISK)
+	}
+	want := map[string]float64{FeeBroker: 320, FeeRelist: 100, FeeSalesTax: 45, FeeContract: 50}
+	for _, c := range r.Categories {
+		if c.ISK != want[c.Key] {
+			t.Errorf("category %s = %v, want %v", c.Key, c.ISK, want[c.Key])
+		}
+	}
+	if len(r.Categories) != 4 {
+		t.Errorf("categories = %d, want 4", len(r.Categories))
+	}
+
+	if len(r.Monthly) != 2 || r.Monthly[0].Month != "2026-08" || r.Monthly[1].Month != "2026-09" {
+		t.Fatalf("monthly = %+v", r.Monthly)
+	}
+	if r.Monthly[0].BrokerISK != 300 || r.Monthly[0].RelistISK != 100 {
+		t.Errorf("august = %+v", r.Monthly[0])
+	}
+	if r.Monthly[1].TotalISK != 115 {
+		t.Errorf("september total = %v, want 115", r.Monthly[1].TotalISK)
+	}
+
+	if len(r.Items) != 2 || r.Items[0].TypeID != 34 {
+		t.Fatalf("items = %+v", r.Items)
+	}
+	if r.Items[0].BrokerISK != 400 || r.Items[0].SalesTaxISK != 45 {
+		t.Errorf("tritanium = %+v", r.Items[0])
+	}
+	if r.Items[1].TypeID != 35 || r.Items[1].TotalISK != 20 {
+		t.Errorf("pyerite = %+v (matched by issued time)", r.Items[1])
+	}
+}
+
+func TestComputeFeesPaid_Savings(t *testing.T) {
+	journal := []esi.WalletJournalEntry{
+		{ID: 1, Date: "2026-09-01T12:00:00Z", RefType: "transaction_tax", Amount: -75, ContextID: 11, ContextIDType: "market_transaction_id"},
+		{ID: 2, Date: "2026-09-01T12:00:00Z", RefType: "brokers_fee", Amount: -300},
+	}
+	txns := []esi.WalletTransaction{
+		{TransactionID: 11, TypeID: 34, UnitPrice: 100, Quantity: 10},
+	}
+	r := ComputeFeesPaid(journal, txns, nil, time.Time{}, 4, 3)
+
+	got := make(map[string]FeeSavings)
+	for _, s := range r.Savings {
+		got[s.Key] = s
+	}
+	// Observed 7.5% sales tax overrides the configured 4%.
+	if s := got["accounting_v"]; s.CurrentRatePercent != 7.5 || math.Abs(s.SavedISK-41.25) > 1e-9 {
+		t.Errorf("accounting_v = %+v", s)
+	}
+	if s := got["broker_relations_v"]; math.Abs(s.SavedISK-150) > 1e-9 {
+		t.Errorf("broker_relations_v = %+v", s)
+	}
+	if s := got["max_standings"]; math.Abs(s.SavedISK-200) > 1e-9 {
+		t.Errorf("max_standings = %+v", s)
+	}
+
+	r = ComputeFeesPaid(journal, txns, nil, time.Time{}, 3, 1)
+	for _, s := range r.Savings {
+		if s.Key != "accounting_v" {
+			t.Errorf("unexpected savings at minimum broker fee: %+v", s)
+		}
+	}
+}
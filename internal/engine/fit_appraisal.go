@@ -0,0 +1,226 @@
+package engine
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"eve-flipper/internal/pricing"
+	"eve-flipper/internal/sde"
+)
+
+// FitItem is one type of a fit with its count per fit.
+type FitItem struct {
+	TypeID   int32   `json:"type_id"`
+	Name     string  `json:"name"`
+	Quantity int64   `json:"quantity"`
+	Volume   float64 `json:"volume"` // packaged m³ per unit
+}
+
+// DoctrineFit is a parsed EFT fit: the hull first, then modules, rigs,
+// drones and cargo in paste order.
+type DoctrineFit struct {
+	Name       string    `json:"name"`
+	ShipTypeID int32     `json:"ship_type_id"`
+	ShipName   string    `json:"ship_name"`
+	Items      []FitItem `json:"items"`
+}
+
+var eftQuantitySuffix = regexp.MustCompile(`^(.*\S)\s+x(\d[\d,]*)$`)
+
+// ParseEFTFits reads one or more fits in EFT format as the fitting window
+// copies them:
+//
+//	[Muninn, Doctrine Muninn]
+//	Damage Control II
+//	720mm Howitzer Artillery II, Republic Fleet EMP M
+//	[Empty High slot]
+//	Hornet EC-300 x5
+//
+// Loaded charges are dropped (doctrines carry ammo as cargo lines), as are
+// empty slots and the /OFFLINE marker. Lines naming no known type are
+// returned as unknown; a fit whose hull is unknown is skipped whole.
+func ParseEFTFits(data *sde.Data, text string) (fits []DoctrineFit, unknown []string) {
+	fits = []DoctrineFit{}
+	unknown = []string{}
+	if data == nil {
+		return fits, unknown
+	}
+	var cur *DoctrineFit
+	var index map[int32]int
+	flush := func() {
+		if cur != nil {
+			fits = append(fits, *cur)
+		}
+		cur = nil
+	}
+	add := func(name string, qty int64) {
+		typeID, ok := data.TypeIDByName(name)
+		t := data.Types[typeID]
+		if !ok || t == nil {
+			unknown = append(unknown, name)
+			return
+		}
+		if i, ok := index[typeID]; ok {
+			cur.Items[i].Quantity += qty
+			return
+		}
+		index[typeID] = len(cur.Items)
+		cur.Items = append(cur.Items, FitItem{TypeID: typeID, Name: t.Name, Quantity: qty, Volume: t.Volume})
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			header := strings.TrimSpace(line[1 : len(line)-1])
+			if strings.HasPrefix(strings.ToLower(header), "empty ") {
+				continue
+			}
+			flush()
+			ship, name, _ := strings.Cut(header, ",")
+			ship, name = strings.TrimSpace(ship), strings.TrimSpace(name)
+			typeID, ok := data.TypeIDByName(ship)
+			if !ok || data.Types[typeID] == nil {
+				unknown = append(unknown, ship)
+				continue
+			}
+			if name == "" {
+				name = data.Types[typeID].Name
+			}
+			cur = &DoctrineFit{Name: name, ShipTypeID: typeID, ShipName: data.Types[typeID].Name}
+			index = make(map[int32]int)
+			add(ship, 1)
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimSuffix(line, "/OFFLINE"))
+		qty := int64(1)
+		if m := eftQuantitySuffix.FindStringSubmatch(line); m != nil {
+			n, err := strconv.ParseInt(strings.ReplaceAll(m[2], ",", ""), 10, 64)
+			if err != nil || n <= 0 {
+				continue
+			}
+			line, qty = m[1], n
+		}
+		module, _, _ := strings.Cut(line, ",")
+		add(strings.TrimSpace(module), qty)
+	}
+	flush()
+	return fits, unknown
+}
+
+// FitAppraisalParams configures AppraiseFits.
+type FitAppraisalParams struct {
+	Fits                     int     // fits to seed; sizes the totals
+	SalesTaxPercent          float64 // paid on target sales
+	BrokerFeePercent         float64 // paid on target sell orders
+	Jumps                    int     // Jita to target; -1 when unknown
+	FreightISKPerM3Jump      float64
+	FreightCollateralPercent float64
+}
+
+// FitAppraisalLine prices one type of a fit.
+type FitAppraisalLine struct {
+	TypeID      int32   `json:"type_id"`
+	Name        string  `json:"name"`
+	PerFit      int64   `json:"per_fit"`
+	Quantity    int64   `json:"quantity"` // PerFit × fits
+	Volume      float64 `json:"volume"`   // m³ for Quantity
+	JitaPrice   float64 `json:"jita_price"`
+	TargetPrice float64 `json:"target_price"`
+	JitaCost    float64 `json:"jita_cost"`    // per fit
+	TargetValue float64 `json:"target_value"` // per fit
+}
+
+// FitAppraisal is the cost of a fit in Jita, its value at the target market
+// and the seeding margin between them. Per-fit figures ignore Fits; the
+// totals scale by it.
+type FitAppraisal struct {
+	Name          string             `json:"name"`
+	ShipTypeID    int32              `json:"ship_type_id"`
+	ShipName      string             `json:"ship_name"`
+	Lines         []FitAppraisalLine `json:"lines"`
+	JitaCost      float64            `json:"jita_cost"`
+	TargetValue   float64            `json:"target_value"`
+	Volume        float64            `json:"volume"` // m³ per fit
+	Freight       float64            `json:"freight"`
+	Fees          float64            `json:"fees"`
+	Profit        float64            `json:"profit"`
+	MarginPercent float64            `json:"margin_percent"` // profit over Jita cost plus freight
+	MissingJita   []string           `json:"missing_jita"`
+	MissingTarget []string           `json:"missing_target"`
+	Complete      bool               `json:"complete"` // every line has both prices
+	Fits          int                `json:"fits"`
+	TotalCost     float64            `json:"total_cost"`
+	TotalProfit   float64            `json:"total_profit"`
+	TotalVolume   float64            `json:"total_volume"`
+}
+
+// AppraiseFits buys each fit at Jita sell prices, hauls it (when a freight
+// rate and the jump count are known) and lists it at the target's lowest
+// sell. A line missing a price counts zero on that side and marks the
+// appraisal incomplete.
+func AppraiseFits(fits []DoctrineFit, jita, target map[int32]pricing.Quote, p FitAppraisalParams) []FitAppraisal {
+	if p.Fits <= 0 {
+		p.Fits = 1
+	}
+	feeRate := (p.SalesTaxPercent + p.BrokerFeePercent) / 100
+	out := make([]FitAppraisal, 0, len(fits))
+	for _, f := range fits {
+		a := FitAppraisal{
+			Name:          f.Name,
+			ShipTypeID:    f.ShipTypeID,
+			ShipName:      f.ShipName,
+			Lines:         make([]FitAppraisalLine, 0, len(f.Items)),
+			MissingJita:   []string{},
+			MissingTarget: []string{},
+			Fits:          p.Fits,
+		}
+		for _, it := range f.Items {
+			line := FitAppraisalLine{
+				TypeID:      it.TypeID,
+				Name:        it.Name,
+				PerFit:      it.Quantity,
+				Quantity:    it.Quantity * int64(p.Fits),
+				Volume:      sanitizeFloat(it.Volume * float64(it.Quantity*int64(p.Fits))),
+				JitaPrice:   jita[it.TypeID].Sell,
+				TargetPrice: target[it.TypeID].Sell,
+			}
+			line.JitaCost = sanitizeFloat(line.JitaPrice * float64(it.Quantity))
+			line.TargetValue = sanitizeFloat(line.TargetPrice * float64(it.Quantity))
+			if line.JitaPrice <= 0 {
+				a.MissingJita = append(a.MissingJita, it.Name)
+			}
+			if line.TargetPrice <= 0 {
+				a.MissingTarget = append(a.MissingTarget, it.Name)
+			}
+			a.JitaCost += line.JitaCost
+			a.TargetValue += line.TargetValue
+			a.Volume += it.Volume * float64(it.Quantity)
+			a.Lines = append(a.Lines, line)
+		}
+		a.Complete = len(a.MissingJita) == 0 && len(a.MissingTarget) == 0
+		if p.Jumps >= 0 {
+			a.Freight = freightReward(a.Volume, courierJumps(p.Jumps), a.JitaCost, p.FreightISKPerM3Jump, p.FreightCollateralPercent)
+		}
+		a.Fees = sanitizeFloat(a.TargetValue * feeRate)
+		a.Profit = sanitizeFloat(a.TargetValue - a.Fees - a.JitaCost - a.Freight)
+		if basis := a.JitaCost + a.Freight; basis > 0 {
+			a.MarginPercent = sanitizeFloat(math.Round(a.Profit/basis*1000) / 10)
+		}
+		a.JitaCost = sanitizeFloat(a.JitaCost)
+		a.TargetValue = sanitizeFloat(a.TargetValue)
+		a.Volume = sanitizeFloat(a.Volume)
+		a.TotalCost = sanitizeFloat((a.JitaCost + a.Freight) * float64(p.Fits))
+		a.TotalProfit = sanitizeFloat(a.Profit * float64(p.Fits))
+		a.TotalVolume = sanitizeFloat(a.Volume * float64(p.Fits))
+		out = append(out, a)
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+package engine
+
+import (
+	"math"
+	"testing"
+
+	"eve-flipper/internal/pricing"
+	"eve-flipper/internal/sde"
+)
+
+func fitTestData() *sde.Data {
+	return &sde.Data{Types: map[int32]*sde.ItemType{
+		12015: {ID: 12015, Name: "Muninn", Volume: 10000},
+		2048:  {ID: 2048, Name: "Damage Control II", Volume: 5},
+		2929:  {ID: 2929, Name: "720mm Howitzer Artillery II", Volume: 20},
+		21906: {ID: 21906, Name: "Republic Fleet EMP M", Volume: 0.0125},
+		2488:  {ID: 2488, Name: "Hornet EC-300", Volume: 5},
+	}}
+}
+
+const testMuninnEFT = `[Muninn, Doctrine Muninn]
+Damage Control II
+720mm Howitzer Artillery II, Republic Fleet EMP M
+720mm Howitzer Artillery II, Republic Fleet EMP M /OFFLINE
+[Empty Low slot]
+Warp Scrambler III
+
+Hornet EC-300 x5
+Republic Fleet EMP M x1,000
+
+[Zealot, Unknown hull fit]
+Damage Control II
+`
+
+func TestParseEFTFits(t *testing.T) {
+	fits, unknown := ParseEFTFits(fitTestData(), testMuninnEFT)
+	if len(fits) != 1 {
+		t.Fatalf("fits = %+v", fits)
+	}
+	f := fits[0]
+	if f.Name != "Doctrine Muninn" || f.ShipTypeID != 12015 || f.Items[0].TypeID != 12015 {
+		t.Fatalf("fit = %+v", f)
+	}
+	got := make(map[int32]int64)
+	for _, it := range f.Items {
+		got[it.TypeID] = it.Quantity
+	}
+	// Loaded charges are dropped; cargo ammo counts.
+	want := map[int32]int64{12015: 1, 2048: 1, 2929: 2, 2488: 5, 21906: 1000}
+	if len(got) != len(want) {
+		t.Fatalf("items = %+v", f.Items)
+	}
+	for id, q := range want {
+		if got[id] != q {
+			t.Fatalf("type %d = %d, want %d (%+v)", id, got[id], q, f.Items)
+		}
+	}
+	if len(unknown) != 2 || unknown[0] != "Warp Scrambler III" || unknown[1] != "Zealot" {
+		t.Fatalf("unknown = %v", unknown)
+	}
+}
+
+func TestAppraiseFits(t *testing.T) {
+	fit := DoctrineFit{Name: "Doctrine", ShipTypeID: 12015, ShipName: "Muninn", Items: []FitItem{
+		{TypeID: 12015, Name: "Muninn", Quantity: 1, Volume: 10000},
+		{TypeID: 2929, Name: "720mm Howitzer Artillery II", Quantity: 2, Volume: 20},
+		{TypeID: 2488, Name: "Hornet EC-300", Quantity: 5, Volume: 5},
+	}}
+	jita := map[int32]pricing.Quote{12015: {Sell: 100e6}, 2929: {Sell: 1e6}, 2488: {Sell: 10e3}}
+	target := map[int32]pricing.Quote{12015: {Sell: 130e6}, 2929: {Sell: 1.5e6}}
+	p := FitAppraisalParams{Fits: 3, SalesTaxPercent: 4, BrokerFeePercent: 1, Jumps: 10, FreightISKPerM3Jump: 100}
+
+	a := AppraiseFits([]DoctrineFit{fit}, jita, target, p)[0]
+	if a.JitaCost != 102.05e6 || a.TargetValue != 133e6 || a.Volume != 10065 {
+		t.Fatalf("appraisal = %+v", a)
+	}
+	if a.Complete || len(a.MissingTarget) != 1 || a.MissingTarget[0] != "Hornet EC-300" {
+		t.Fatalf("missing target = %v", a.MissingTarget)
+	}
+	// 10065 m³ × 10 jumps × 100 ISK; 5% of 133M in fees.
+	wantProfit := 133e6 - 6.65e6 - 102.05e6 - 10.065e6
+	if a.Freight != 10.065e6 || math.Abs(a.Profit-wantProfit) > 1e-3 {
+		t.Fatalf("freight = %v, profit = %v, want %v", a.Freight, a.Profit, wantProfit)
+	}
+	if math.Abs(a.TotalProfit-3*wantProfit) > 1e-3 || a.Lines[1].Quantity != 6 || a.TotalVolume != 30195 {
+		t.Fatalf("totals = %+v", a)
+	}
+	if a.MarginPercent != 12.7 {
+		t.Fatalf("margin = %v", a.MarginPercent)
+	}
+}
@@ -0,0 +1,52 @@
+package engine
+
+// freightEnabled reports whether courier pricing was requested.
+func (p ScanParams) freightEnabled() bool {
+	return p.FreightISKPerM3Jump > 0 || p.FreightCollateralPercent > 0
+}
+
+// freightReward prices a courier contract the way Red Frog-style haulers
+// quote: a volume × jumps rate plus a percentage of the collateral.
+func freightReward(volumeM3 float64, jumps int, collateral, iskPerM3Jump, collateralPct float64) float64 {
+	reward := 0.0
+	if iskPerM3Jump > 0 && volumeM3 > 0 && jumps > 0 {
+		reward += iskPerM3Jump * volumeM3 * float64(jumps)
+	}
+	if collateralPct > 0 && collateral > 0 {
+		reward += collateral * collateralPct / 100
+	}
+	return sanitizeFloat(reward)
+}
+
+// courierJumps is the jump count a courier contract is priced at: a haul
+// between two stations of one system still counts one jump.
+func courierJumps(jumps int) int {
+	return max(jumps, 1)
+}
+
+// applyFreightCosts annotates each result with the courier reward for moving
+// its units buy→sell and the profit left after paying it. Profit fields are
+// left unchanged so traders can compare hauling themselves vs contracting.
+// Jump-drive fuel is added back: a contracted courier replaces it.
+func applyFreightCosts(results []FlipResult, params ScanParams) {
+	for i := range results {
+		r := &results[i]
+		if r.UnitsToBuy <= 0 || (r.BuyLocationID != 0 && r.BuyLocationID == r.SellLocationID) {
+			continue
+		}
+		units := float64(r.UnitsToBuy)
+		buyPrice := r.BuyPrice
+		if r.ExpectedBuyPrice > 0 {
+			buyPrice = r.ExpectedBuyPrice
+		}
+		r.FreightCollateral = sanitizeFloat(buyPrice * units)
+		r.FreightCost = freightReward(r.Volume*units, courierJumps(r.SellJumps), r.FreightCollateral,
+			params.FreightISKPerM3Jump, params.FreightCollateralPercent)
+
+		profit := r.TotalProfit
+		if r.RealProfit != 0 {
+			profit = r.RealProfit
+		}
+		r.NetProfitAfterFreight = sanitizeFloat(profit + r.JumpFuelISK - r.FreightCost)
+	}
+}
@@ -0,0 +1,45 @@
+package engine
+
+import "testing"
+
+func TestFreightReward(t *testing.T) {
+	// 1000 m3 × 5 jumps × 200 ISK + 1% of 100M collateral.
+	if got := freightReward(1000, 5, 100e6, 200, 1); got != 2_000_000 {
+		t.Errorf("freightReward = %v, want 2000000", got)
+	}
+	if got := freightReward(1000, 5, 100e6, 0, 0); got != 0 {
+		t.Errorf("disabled freightReward = %v, want 0", got)
+	}
+}
+
+func TestApplyFreightCosts(t *testing.T) {
+	results := []FlipResult{
+		{BuyLocationID: 1, SellLocationID: 2, SellJumps: 4, Volume: 10, UnitsToBuy: 50,
+			BuyPrice: 1000, ExpectedBuyPrice: 1100, TotalProfit: 90_000, RealProfit: 80_000},
+		// Same system, different station: still one courier jump.
+		{BuyLocationID: 1, SellLocationID: 3, SellJumps: 0, Volume: 1, UnitsToBuy: 100, BuyPrice: 10, TotalProfit: 500},
+		// Same station: nothing to haul.
+		{BuyLocationID: 1, SellLocationID: 1, Volume: 1, UnitsToBuy: 100, BuyPrice: 10, TotalProfit: 500},
+		// Jump-drive fuel already deducted is added back before freight.
+		{BuyLocationID: 1, SellLocationID: 4, SellJumps: 10, Volume: 1, UnitsToBuy: 10, BuyPrice: 100,
+			TotalProfit: 1000, JumpFuelISK: 300},
+	}
+	applyFreightCosts(results, ScanParams{FreightISKPerM3Jump: 2, FreightCollateralPercent: 1})
+
+	// 500 m3 × 4 × 2 = 4000, + 1% of 55000 = 550.
+	if r := results[0]; r.FreightCollateral != 55_000 || r.FreightCost != 4550 || r.NetProfitAfterFreight != 75_450 {
+		t.Errorf("row 0 = collateral %v cost %v net %v", r.FreightCollateral, r.FreightCost, r.NetProfitAfterFreight)
+	}
+	if r := results[1]; r.FreightCost != 210 || r.NetProfitAfterFreight != 290 {
+		t.Errorf("row 1 = cost %v net %v, want 210/290", r.FreightCost, r.NetProfitAfterFreight)
+	}
+	if r := results[2]; r.FreightCost != 0 || r.NetProfitAfterFreight != 0 {
+		t.Errorf("same-station row priced: %+v", r)
+	}
+	if r := results[3]; r.FreightCost != 210 || r.NetProfitAfterFreight != 1090 {
+		t.Errorf("row 3 = cost %v net %v, want 210/1090", r.FreightCost, r.NetProfitAfterFreight)
+	}
+	if results[0].TotalProfit != 90_000 || results[0].RealProfit != 80_000 {
+		t.Error("freight must not change profit fields")
+	}
+}
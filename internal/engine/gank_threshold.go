@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"math"
+	"strings"
+)
+
+// gankThresholdsISK is the cargo value per load at which suicide-ganking a
+// ship class on a quiet highsec route typically pays for the gank fleet,
+// with half the cargo dropping. Blockade runners cloak and deep space
+// transports and freighters need large fleets, so their thresholds sit far
+// above a tech 1 hauler's.
+var gankThresholdsISK = map[string]float64{
+	"fast_frigate":         300_000_000,
+	"sunesis":              500_000_000,
+	"industrial":           1_500_000_000,
+	"blockade_runner":      3_000_000_000,
+	"deep_space_transport": 5_000_000_000,
+	"freighter":            8_000_000_000,
+}
+
+// defaultGankThresholdISK is used for unknown classes: a tech 1 hauler.
+const defaultGankThresholdISK = 1_500_000_000
+
+// shipClassEHP is the buffer-fitted tank each class threshold assumes.
+var shipClassEHP = map[string]float64{
+	"fast_frigate":         4_000,
+	"sunesis":              8_000,
+	"industrial":           40_000,
+	"blockade_runner":      20_000,
+	"deep_space_transport": 120_000,
+	"freighter":            300_000,
+}
+
+// defaultShipClassEHP is used for unknown classes: a tech 1 hauler.
+const defaultShipClassEHP = 40_000
+
+// GankThresholdISK is the per-load cargo value above which class is a
+// profitable gank target on a route with hauling safety multiplier
+// safetyMult (1 = quiet, up to 3 on red routes). Dangerous routes lower the
+// threshold in proportion, since gankers are already camping them.
+func GankThresholdISK(class string, safetyMult float64) float64 {
+	threshold, ok := gankThresholdsISK[strings.ToLower(strings.TrimSpace(class))]
+	if !ok {
+		threshold = defaultGankThresholdISK
+	}
+	if isPositiveFinite(safetyMult) && safetyMult > 1 {
+		threshold /= safetyMult
+	}
+	return math.Round(threshold)
+}
+
+// GankThresholdForEHP is GankThresholdISK for a ship with ehp effective hit
+// points instead of the class's usual tank. The gank fleet, and so the
+// cargo value that pays for it, grows with the tank; ehp <= 0 keeps the
+// class threshold.
+func GankThresholdForEHP(class string, ehp, safetyMult float64) float64 {
+	threshold := GankThresholdISK(class, safetyMult)
+	if !isPositiveFinite(ehp) {
+		return threshold
+	}
+	base, ok := shipClassEHP[strings.ToLower(strings.TrimSpace(class))]
+	if !ok {
+		base = defaultShipClassEHP
+	}
+	return math.Round(threshold * ehp / base)
+}
+
+// gankSafeTrips is how many loads keep each one at or under threshold.
+func gankSafeTrips(cargoValueISK, threshold float64) int {
+	if cargoValueISK <= 0 || !isPositiveFinite(threshold) {
+		return 1
+	}
+	return max(int(math.Ceil(cargoValueISK/threshold)), 1)
+}
+
+// ApplyFlipGankThresholds flags flips whose ISK per load, at the buy price
+// over CargoTrips loads, exceeds the gank threshold of class with ehp (see
+// GankThresholdForEHP) on the flip's route (RouteSafetyMultiplier when
+// known). GankSafeTrips is the number of
+// loads that keeps every load under it. With split, flagged flips are
+// raised to that many trips and no longer flagged; run ApplyFlipISKPerHour
+// afterwards so travel time covers the extra trips.
+func ApplyFlipGankThresholds(results []FlipResult, class string, ehp float64, split bool) {
+	for i := range results {
+		r := &results[i]
+		units := r.UnitsToBuy
+		if r.FilledQty > 0 && r.FilledQty < units {
+			units = r.FilledQty
+		}
+		price := r.BuyPrice
+		if r.ExpectedBuyPrice > 0 {
+			price = r.ExpectedBuyPrice
+		}
+		value := float64(units) * price
+		if units <= 0 || !isPositiveFinite(value) {
+			continue
+		}
+		trips := max(r.CargoTrips, 1)
+		r.GankThresholdISK = GankThresholdForEHP(class, ehp, r.RouteSafetyMultiplier)
+		r.GankSafeTrips = max(gankSafeTrips(value, r.GankThresholdISK), trips)
+		r.GankLoadValueISK = sanitizeFloat(value / float64(trips))
+		r.GankRisk = r.GankLoadValueISK > r.GankThresholdISK
+		if split && r.GankRisk {
+			r.CargoTrips = r.GankSafeTrips
+			r.GankLoadValueISK = sanitizeFloat(value / float64(r.CargoTrips))
+			r.GankRisk = false
+			r.GankSplit = true
+		}
+	}
+}
+
+// applyRouteGankThreshold flags a route when any hop carries more ISK per
+// load than the gank threshold of class at the route's hauling risk.
+func applyRouteGankThreshold(route *RouteResult, class string) {
+	route.GankThresholdISK = GankThresholdISK(class, route.HaulingSafetyMultiplier)
+	route.GankLoadValueISK = 0
+	route.GankSafeTrips = 0
+	for _, hop := range route.Hops {
+		value := float64(hop.Units) * hop.BuyPrice
+		if value <= 0 || !isPositiveFinite(value) {
+			continue
+		}
+		trips := max(hop.CargoTrips, 1)
+		route.GankLoadValueISK = math.Max(route.GankLoadValueISK, sanitizeFloat(value/float64(trips)))
+		route.GankSafeTrips = max(route.GankSafeTrips, gankSafeTrips(value, route.GankThresholdISK), trips)
+	}
+	route.GankRisk = route.GankLoadValueISK > route.GankThresholdISK
+}
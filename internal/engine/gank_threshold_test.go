@@ -0,0 +1,81 @@
+package engine
+
+import "testing"
+
+func TestGankThresholdISK(t *testing.T) {
+	if got := GankThresholdISK("industrial", 0); got != 1_500_000_000 {
+		t.Fatalf("industrial threshold = %v, want 1.5B", got)
+	}
+	if got := GankThresholdISK("mystery", 1); got != defaultGankThresholdISK {
+		t.Fatalf("unknown class threshold = %v, want default", got)
+	}
+	if got := GankThresholdISK("Freighter", 2); got != 4_000_000_000 {
+		t.Fatalf("freighter on a 2x route = %v, want 4B", got)
+	}
+}
+
+func TestGankThresholdForEHP(t *testing.T) {
+	if got := GankThresholdForEHP("industrial", 0, 1); got != 1_500_000_000 {
+		t.Fatalf("class tank threshold = %v, want 1.5B", got)
+	}
+	if got := GankThresholdForEHP("industrial", 80_000, 2); got != 1_500_000_000 {
+		t.Fatalf("double tank on a 2x route = %v, want 1.5B", got)
+	}
+	if got := GankThresholdForEHP("mystery", 20_000, 1); got != 750_000_000 {
+		t.Fatalf("unknown class with half tank = %v, want 750M", got)
+	}
+}
+
+func TestApplyFlipGankThresholdsFlagsAndSplits(t *testing.T) {
+	rows := []FlipResult{
+		{TypeID: 1, UnitsToBuy: 100, BuyPrice: 40_000_000, CargoTrips: 1},                               // 4B in one load
+		{TypeID: 2, UnitsToBuy: 100, BuyPrice: 10_000_000, CargoTrips: 1},                               // 1B
+		{TypeID: 3, UnitsToBuy: 100, BuyPrice: 10_000_000, CargoTrips: 1, RouteSafetyMultiplier: 2},     // 1B on a red route
+		{TypeID: 4, UnitsToBuy: 100, FilledQty: 10, BuyPrice: 40_000_000, ExpectedBuyPrice: 41_000_000}, // 410M filled
+	}
+	ApplyFlipGankThresholds(rows, "industrial", 0, false)
+	if !rows[0].GankRisk || rows[0].GankSafeTrips != 3 || rows[0].GankLoadValueISK != 4_000_000_000 {
+		t.Fatalf("4B load = %+v", rows[0])
+	}
+	if rows[1].GankRisk || rows[1].GankSafeTrips != 1 {
+		t.Fatalf("1B load should be safe: %+v", rows[1])
+	}
+	if !rows[2].GankRisk || rows[2].GankThresholdISK != 750_000_000 || rows[2].GankSafeTrips != 2 {
+		t.Fatalf("1B on a dangerous route = %+v", rows[2])
+	}
+	if rows[3].GankRisk || rows[3].GankLoadValueISK != 410_000_000 {
+		t.Fatalf("filled quantity at the expected price = %+v", rows[3])
+	}
+
+	split := []FlipResult{{TypeID: 1, UnitsToBuy: 100, BuyPrice: 40_000_000, CargoTrips: 1, BuyJumps: 2, SellJumps: 5}}
+	ApplyFlipGankThresholds(split, "industrial", 0, true)
+	if split[0].GankRisk || !split[0].GankSplit || split[0].CargoTrips != 3 {
+		t.Fatalf("split row = %+v", split[0])
+	}
+	if split[0].GankLoadValueISK > split[0].GankThresholdISK {
+		t.Fatalf("split load %v still above threshold %v", split[0].GankLoadValueISK, split[0].GankThresholdISK)
+	}
+	one := ShipTravelFor("industrial", 0, 0).HaulMinutes(2, 5, 1)
+	ApplyFlipISKPerHour(split, ShipTravelFor("industrial", 0, 0))
+	if split[0].ExecutionMinutes <= one {
+		t.Fatalf("split trips should take longer than one load: %v <= %v", split[0].ExecutionMinutes, one)
+	}
+}
+
+func TestRouteExecutionFlagsGankLoads(t *testing.T) {
+	routes := []RouteResult{{
+		Hops: []RouteHop{
+			{Units: 10, BuyPrice: 100_000_000, VolumeM3: 10, Jumps: 5},  // 1B, 100 m³
+			{Units: 10, BuyPrice: 300_000_000, VolumeM3: 100, Jumps: 3}, // 3B, 1000 m³
+		},
+		HaulingSafetyMultiplier: 1,
+	}}
+	EnrichRouteExecutionEstimatesWithProfile(routes, RouteExecutionProfile{ShipProfile: "deep_space_transport"})
+	if routes[0].GankRisk || routes[0].GankThresholdISK != 5_000_000_000 {
+		t.Fatalf("DST route = risk %v threshold %v", routes[0].GankRisk, routes[0].GankThresholdISK)
+	}
+	EnrichRouteExecutionEstimatesWithProfile(routes, RouteExecutionProfile{ShipProfile: "sunesis"})
+	if !routes[0].GankRisk || routes[0].GankLoadValueISK != 3_000_000_000 || routes[0].GankSafeTrips != 6 {
+		t.Fatalf("Sunesis route = %+v", routes[0])
+	}
+}
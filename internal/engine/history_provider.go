@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"fmt"
+
+	"eve-flipper/internal/esi"
+)
+
+// HistoryProvider is the market history cache consulted before any remote
+// source: the SQLite DB in production, an in-memory map in tests.
+type HistoryProvider interface {
+	GetMarketHistory(regionID int32, typeID int32) ([]esi.HistoryEntry, bool)
+	SetMarketHistory(regionID int32, typeID int32, entries []esi.HistoryEntry)
+}
+
+// HistorySource fetches market history from a remote backend such as ESI or
+// an imported third-party dataset. *esi.Client implements it.
+type HistorySource interface {
+	FetchMarketHistory(regionID int32, typeID int32) ([]esi.HistoryEntry, error)
+}
+
+// HistorySourceFunc adapts a function to HistorySource.
+type HistorySourceFunc func(regionID int32, typeID int32) ([]esi.HistoryEntry, error)
+
+// FetchMarketHistory calls f.
+func (f HistorySourceFunc) FetchMarketHistory(regionID int32, typeID int32) ([]esi.HistoryEntry, error) {
+	return f(regionID, typeID)
+}
+
+// LoadMarketHistory returns cached history when available, otherwise the
+// first successful result from sources (tried in order), which is written back
+// to cache. cache may be nil.
+func LoadMarketHistory(cache HistoryProvider, sources []HistorySource, regionID int32, typeID int32) ([]esi.HistoryEntry, error) {
+	if cache != nil {
+		if entries, ok := cache.GetMarketHistory(regionID, typeID); ok {
+			return entries, nil
+		}
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no market history source for region %d type %d", regionID, typeID)
+	}
+	var lastErr error
+	for _, src := range sources {
+		entries, err := src.FetchMarketHistory(regionID, typeID)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if cache != nil {
+			cache.SetMarketHistory(regionID, typeID, entries)
+		}
+		return entries, nil
+	}
+	return nil, lastErr
+}
+
+// historySources returns the remote backends behind s.History: HistorySources
+// when configured, otherwise ESI.
+func (s *Scanner) historySources() []HistorySource {
+	if len(s.HistorySources) > 0 {
+		return s.HistorySources
+	}
+	if s.ESI != nil {
+		return []HistorySource{s.ESI}
+	}
+	return nil
+}
+
+// MarketHistory is the engine's single access path for market history:
+// the History cache, then each history source.
+func (s *Scanner) MarketHistory(regionID int32, typeID int32) ([]esi.HistoryEntry, error) {
+	return LoadMarketHistory(s.History, s.historySources(), regionID, typeID)
+}
@@ -0,0 +1,102 @@
+package engine
+
+import "eve-flipper/internal/pricing"
+
+// AnnotateHubReference sets the Jita reference prices on each result and
+// expresses its prices against them: BuyPrice as % of Jita sell (what buying
+// at the hub would cost) and SellPrice as % of Jita buy (what dumping at the
+// hub would pay). Types missing from quotes are left unannotated.
+func AnnotateHubReference(results []FlipResult, quotes map[int32]pricing.Quote) {
+	for i := range results {
+		q, ok := quotes[results[i].TypeID]
+		if !ok {
+			continue
+		}
+		results[i].JitaSellPrice = q.Sell
+		results[i].JitaBuyPrice = q.Buy
+		if q.Sell > 0 && results[i].BuyPrice > 0 {
+			results[i].BuyVsJitaPct = sanitizeFloat(results[i].BuyPrice / q.Sell * 100)
+		}
+		if q.Buy > 0 && results[i].SellPrice > 0 {
+			results[i].SellVsJitaPct = sanitizeFloat(results[i].SellPrice / q.Buy * 100)
+		}
+	}
+}
+
+// referenceDelta is how far price sits above (+) or below (−) the reference
+// station's lowest sell, in percent.
+func referenceDelta(price, referenceSell float64) float64 {
+	if price <= 0 || referenceSell <= 0 {
+		return 0
+	}
+	return sanitizeFloat((price - referenceSell) / referenceSell * 100)
+}
+
+// AnnotateReferenceStation compares each result's SellPrice against the
+// lowest sell at the user's reference station (Jita 4-4 unless changed).
+// Types missing from quotes are left unannotated.
+func AnnotateReferenceStation(results []FlipResult, stationID int64, quotes map[int32]pricing.Quote) {
+	for i := range results {
+		q, ok := quotes[results[i].TypeID]
+		if !ok || q.Sell <= 0 {
+			continue
+		}
+		results[i].ReferenceStationID = stationID
+		results[i].ReferenceSellPrice = q.Sell
+		results[i].ReferenceDelta = referenceDelta(results[i].SellPrice, q.Sell)
+	}
+}
+
+// AnnotateContractReference values each contract's included items at the
+// lowest sell of the user's reference station and compares MarketValue
+// against that. Contracts with any item missing from quotes are left
+// unannotated.
+func AnnotateContractReference(results []ContractResult, stationID int64, quotes map[int32]pricing.Quote) {
+	for i := range results {
+		value := 0.0
+		for typeID, qty := range results[i].includedItems {
+			q, ok := quotes[typeID]
+			if !ok || q.Sell <= 0 {
+				value = 0
+				break
+			}
+			value += q.Sell * float64(qty)
+		}
+		if value <= 0 {
+			continue
+		}
+		results[i].ReferenceStationID = stationID
+		results[i].ReferenceValue = sanitizeFloat(value)
+		results[i].ReferenceDelta = referenceDelta(results[i].MarketValue, value)
+	}
+}
+
+// ContractResultTypeIDs returns the distinct included item types of the
+// contracts.
+func ContractResultTypeIDs(results []ContractResult) []int32 {
+	seen := make(map[int32]bool)
+	typeIDs := []int32{}
+	for _, r := range results {
+		for typeID := range r.includedItems {
+			if !seen[typeID] {
+				seen[typeID] = true
+				typeIDs = append(typeIDs, typeID)
+			}
+		}
+	}
+	return typeIDs
+}
+
+// AnnotateStationTradeReference is AnnotateReferenceStation for station
+// trades, whose sell side is SellPrice (the lowest sell we list against).
+func AnnotateStationTradeReference(results []StationTrade, stationID int64, quotes map[int32]pricing.Quote) {
+	for i := range results {
+		q, ok := quotes[results[i].TypeID]
+		if !ok || q.Sell <= 0 {
+			continue
+		}
+		results[i].ReferenceStationID = stationID
+		results[i].ReferenceSellPrice = q.Sell
+		results[i].ReferenceDelta = referenceDelta(results[i].SellPrice, q.Sell)
+	}
+}
@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"eve-flipper/internal/pricing"
+)
+
+func TestAnnotateHubReference(t *testing.T) {
+	results := []FlipResult{
+		{TypeID: 34, BuyPrice: 9, SellPrice: 12},
+		{TypeID: 35, BuyPrice: 5, SellPrice: 6},
+	}
+	AnnotateHubReference(results, map[int32]pricing.Quote{34: {Buy: 10, Sell: 10}})
+
+	r := results[0]
+	if r.JitaSellPrice != 10 || r.JitaBuyPrice != 10 {
+		t.Fatalf("Jita prices = %v/%v, want 10/10", r.JitaSellPrice, r.JitaBuyPrice)
+	}
+	if r.BuyVsJitaPct != 90 || r.SellVsJitaPct != 120 {
+		t.Fatalf("vs Jita = %v%%/%v%%, want 90/120", r.BuyVsJitaPct, r.SellVsJitaPct)
+	}
+	if results[1].JitaSellPrice != 0 || results[1].BuyVsJitaPct != 0 {
+		t.Fatalf("unquoted type annotated: %+v", results[1])
+	}
+}
+
+func TestAnnotateReferenceStation(t *testing.T) {
+	const amarr = int64(60008494)
+	flips := []FlipResult{{TypeID: 34, SellPrice: 11}, {TypeID: 35, SellPrice: 6}}
+	AnnotateReferenceStation(flips, amarr, map[int32]pricing.Quote{34: {Sell: 10}, 35: {Buy: 5}})
+	if r := flips[0]; r.ReferenceStationID != amarr || r.ReferenceSellPrice != 10 || math.Abs(r.ReferenceDelta-10) > 1e-9 {
+		t.Fatalf("flip reference = %+v", r)
+	}
+	if flips[1].ReferenceStationID != 0 {
+		t.Fatalf("type without a reference sell annotated: %+v", flips[1])
+	}
+
+	trades := []StationTrade{{TypeID: 34, SellPrice: 8}}
+	AnnotateStationTradeReference(trades, amarr, map[int32]pricing.Quote{34: {Sell: 10}})
+	if math.Abs(trades[0].ReferenceDelta+20) > 1e-9 {
+		t.Fatalf("station trade delta = %v, want -20", trades[0].ReferenceDelta)
+	}
+
+	contracts := []ContractResult{
+		{ContractID: 1, MarketValue: 300, includedItems: map[int32]int32{34: 10, 35: 2}},
+		{ContractID: 2, MarketValue: 50, includedItems: map[int32]int32{34: 1, 36: 1}},
+	}
+	AnnotateContractReference(contracts, amarr, map[int32]pricing.Quote{34: {Sell: 10}, 35: {Sell: 100}})
+	if c := contracts[0]; c.ReferenceStationID != amarr || c.ReferenceValue != 300 || c.ReferenceDelta != 0 {
+		t.Fatalf("contract reference = %+v", c)
+	}
+	if contracts[1].ReferenceStationID != 0 {
+		t.Fatalf("contract with an unquoted item annotated: %+v", contracts[1])
+	}
+	// A price level with the reference is a real 0% delta and must survive encoding.
+	if raw, _ := json.Marshal(contracts[0]); !strings.Contains(string(raw), `"ReferenceDelta":0`) {
+		t.Fatalf("zero delta dropped: %s", raw)
+	}
+}
@@ -22,6 +22,14 @@ type ImpactParams struct {
 	DaysUsed int `json:"days_used"`
 	// Valid is true if calibration succeeded (enough data).
 	Valid bool `json:"valid"`
+	// RangeSlope: fitted b in dailyRange% = a + b × √(V_day / V_avg), i.e. the
+	// fraction of price a full day's volume moves the range beyond the
+	// baseline spread. 0 = not fitted; the σ-based square-root law is used.
+	RangeSlope float64 `json:"range_slope,omitempty"`
+	// RangeFitR2 is the R² of the range fit.
+	RangeFitR2 float64 `json:"range_fit_r2,omitempty"`
+	// CalibratedAt is when the persisted range fit was made (RFC3339).
+	CalibratedAt string `json:"calibrated_at,omitempty"`
 }
 
 // ImpactEstimate is the result of applying the impact model for a given quantity.
@@ -38,6 +46,11 @@ type ImpactEstimate struct {
 	// OptimalSlices: suggested number of slices for TWAP execution.
 	// Based on participation rate: each slice ≤ targetPct of daily volume.
 	OptimalSlices int `json:"optimal_slices"`
+	// SliceParticipationPct: share of daily volume per slice, in percent.
+	SliceParticipationPct float64 `json:"slice_participation_pct"`
+	// TWAPHorizonDays: days to spread the slices over so the order stays
+	// under DefaultTWAPMaxDailyPct of daily volume.
+	TWAPHorizonDays int `json:"twap_horizon_days"`
 	// Params used for this estimate.
 	Params ImpactParams `json:"params"`
 }
@@ -49,6 +62,16 @@ const (
 	DefaultTWAPHorizonDays = 1
 	// DefaultTWAPTargetPct is the max fraction of daily volume per slice (5%).
 	DefaultTWAPTargetPct = 0.05
+	// DefaultTWAPMaxDailyPct is the max fraction of daily volume the whole
+	// order should take per day (25%).
+	DefaultTWAPMaxDailyPct = 0.25
+	// DefaultSliceImpactPct is the price impact budget per slice (1%) used to
+	// size slices when a range fit is available.
+	DefaultSliceImpactPct = 0.01
+	// ImpactFitDays is the history window used for the persisted range fit.
+	ImpactFitDays = 90
+	// impactFitMinDays is the minimum number of usable days for a range fit.
+	impactFitMinDays = 10
 )
 
 // CalibrateImpact calibrates impact parameters from market history.
@@ -100,6 +123,74 @@ func CalibrateImpact(history []esi.HistoryEntry, days int) ImpactParams {
 	return out
 }
 
+// FitImpactParams calibrates impact parameters and fits the range slope:
+// each day's (highest−lowest)/average is regressed on √(volume/avg volume).
+// The intercept absorbs the bid/ask spread every day pays; the slope is how
+// much further the price travels as a day's flow grows, which is the
+// square-root law's prefactor measured from this item's own history. The
+// slope is left 0 when the fit is not positive or has too few days.
+func FitImpactParams(history []esi.HistoryEntry, days int) ImpactParams {
+	out := CalibrateImpact(history, days)
+	if !out.Valid || out.AvgDailyVolume <= 0 {
+		return out
+	}
+	var xs, ys []float64
+	for _, h := range filterLastNDays(history, days) {
+		if h.Average <= 0 || h.Volume <= 0 || h.Highest < h.Lowest {
+			continue
+		}
+		xs = append(xs, math.Sqrt(float64(h.Volume)/out.AvgDailyVolume))
+		ys = append(ys, (h.Highest-h.Lowest)/h.Average)
+	}
+	if len(xs) < impactFitMinDays {
+		return out
+	}
+	slope, r2 := fitLine(xs, ys)
+	if slope > 0 {
+		out.RangeSlope = sanitizeFloat(slope)
+		out.RangeFitR2 = sanitizeFloat(math.Round(r2*1000) / 1000)
+	}
+	return out
+}
+
+// fitLine is ordinary least squares of y on x; it returns the slope and R².
+func fitLine(xs, ys []float64) (float64, float64) {
+	n := float64(len(xs))
+	var sx, sy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+	}
+	mx, my := sx/n, sy/n
+	var sxx, sxy, syy float64
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	if sxx == 0 {
+		return 0, 0
+	}
+	slope := sxy / sxx
+	if syy == 0 {
+		return slope, 0
+	}
+	return slope, sxy * sxy / (sxx * syy)
+}
+
+// SliceParticipation is the share of daily volume one slice may take. With
+// a range fit, a slice's impact RangeSlope × √p is held to maxImpact, so
+// p = (maxImpact / RangeSlope)², clamped to 1%–25%; without one it is
+// DefaultTWAPTargetPct.
+func SliceParticipation(params ImpactParams, maxImpact float64) float64 {
+	if params.RangeSlope <= 0 || maxImpact <= 0 {
+		return DefaultTWAPTargetPct
+	}
+	p := math.Pow(maxImpact/params.RangeSlope, 2)
+	return math.Max(0.01, math.Min(p, DefaultTWAPMaxDailyPct))
+}
+
 func median(x []float64) float64 {
 	if len(x) == 0 {
 		return 0
@@ -185,6 +276,10 @@ func EstimateImpact(params ImpactParams, quantity float64, refPrice float64) Imp
 
 	out.LinearImpactPct = ImpactLinearPct(params.Amihud, quantity)
 	out.SqrtImpactPct = ImpactSqrtPct(params.Sigma, quantity, params.AvgDailyVolume)
+	if params.RangeSlope > 0 {
+		// The fitted slope replaces σ as the square-root law's prefactor.
+		out.SqrtImpactPct = ImpactSqrtPct(params.RangeSlope, quantity, params.AvgDailyVolume)
+	}
 
 	// Choose recommendation: sqrt law for large orders (>1% of daily volume),
 	// linear for small orders.
@@ -199,10 +294,18 @@ func EstimateImpact(params ImpactParams, quantity float64, refPrice float64) Imp
 		out.RecommendedImpactISK = refPrice * out.RecommendedImpactPct / 100
 	}
 
-	out.OptimalSlices = OptimalSlicesVolume(quantity, params.AvgDailyVolume, DefaultTWAPTargetPct)
+	participation := SliceParticipation(params, DefaultSliceImpactPct)
+	out.SliceParticipationPct = math.Round(participation*1000) / 10
+	out.OptimalSlices = OptimalSlicesVolume(quantity, params.AvgDailyVolume, participation)
 	if out.OptimalSlices < 1 {
 		out.OptimalSlices = 1
 	}
+	out.TWAPHorizonDays = 1
+	if params.AvgDailyVolume > 0 {
+		if d := int(math.Ceil(quantity / (DefaultTWAPMaxDailyPct * params.AvgDailyVolume))); d > 1 {
+			out.TWAPHorizonDays = d
+		}
+	}
 
 	return out
 }
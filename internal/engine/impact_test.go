@@ -166,3 +166,67 @@ func TestEstimateImpact_SmallOrder(t *testing.T) {
 			est.RecommendedImpactPct, est.LinearImpactPct)
 	}
 }
+
+func TestFitImpactParams_RangeSlope(t *testing.T) {
+	// Range = 2% spread + 4% × √(V/V̄): heavy days move the price further.
+	var history []esi.HistoryEntry
+	volumes := []int64{500, 1000, 2000, 4000, 1000, 250, 3000, 1500, 1000, 2000, 500, 1000}
+	var sum float64
+	for _, v := range volumes {
+		sum += float64(v)
+	}
+	avgVol := sum / float64(len(volumes))
+	for i, v := range volumes {
+		avg := 100.0 + float64(i%3)
+		rng := avg * (0.02 + 0.04*math.Sqrt(float64(v)/avgVol))
+		history = append(history, esi.HistoryEntry{
+			Date:    time.Now().AddDate(0, 0, i-len(volumes)).Format("2006-01-02"),
+			Average: avg,
+			Highest: avg + rng/2,
+			Lowest:  avg - rng/2,
+			Volume:  v,
+		})
+	}
+	params := FitImpactParams(history, ImpactFitDays)
+	if math.Abs(params.RangeSlope-0.04) > 1e-6 {
+		t.Fatalf("RangeSlope = %v, want 0.04", params.RangeSlope)
+	}
+	if params.RangeFitR2 < 0.99 {
+		t.Fatalf("RangeFitR2 = %v, want ~1", params.RangeFitR2)
+	}
+
+	// Slices sized so each moves the price at most 1%: p = (0.01/0.04)² = 6.25%.
+	est := EstimateImpact(params, params.AvgDailyVolume, 100)
+	if est.SliceParticipationPct != 6.3 {
+		t.Errorf("SliceParticipationPct = %v, want 6.3", est.SliceParticipationPct)
+	}
+	if est.OptimalSlices != 16 {
+		t.Errorf("OptimalSlices = %d, want 16", est.OptimalSlices)
+	}
+	if est.TWAPHorizonDays != 4 {
+		t.Errorf("TWAPHorizonDays = %d, want 4", est.TWAPHorizonDays)
+	}
+	if math.Abs(est.SqrtImpactPct-4) > 1e-6 {
+		t.Errorf("SqrtImpactPct = %v, want 4 (slope × √1)", est.SqrtImpactPct)
+	}
+}
+
+func TestFitImpactParams_TooFewDaysKeepsDefaults(t *testing.T) {
+	var history []esi.HistoryEntry
+	for i := 0; i < 6; i++ {
+		history = append(history, esi.HistoryEntry{
+			Date:    time.Now().AddDate(0, 0, i-6).Format("2006-01-02"),
+			Average: 100 + float64(i),
+			Highest: 105 + float64(i),
+			Lowest:  95 + float64(i),
+			Volume:  int64(1000 * (i + 1)),
+		})
+	}
+	params := FitImpactParams(history, ImpactFitDays)
+	if !params.Valid || params.RangeSlope != 0 {
+		t.Fatalf("params = %+v, want valid calibration without a range fit", params)
+	}
+	if got := SliceParticipation(params, DefaultSliceImpactPct); got != DefaultTWAPTargetPct {
+		t.Fatalf("SliceParticipation = %v, want default %v", got, DefaultTWAPTargetPct)
+	}
+}
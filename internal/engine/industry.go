@@ -33,6 +33,14 @@ type IndustryParams struct {
 	InventionChance     float64 // Optional invention chance override in percent (0 = SDE probability)
 	DecryptorCost       float64 // Optional per-attempt decryptor cost
 	InventionOutputRuns int32   // Optional successful BPC runs override
+
+	// Invention modifiers (ActivityMode "invention"). Skill levels of 0
+	// leave the SDE probability unchanged.
+	DecryptorTypeID  int32 // Optional decryptor (see Decryptors); priced from the market unless DecryptorCost is set
+	EncryptionSkill  int32 // Encryption Methods level
+	ScienceSkill1    int32 // First datacore science skill level
+	ScienceSkill2    int32 // Second datacore science skill level
+	UseInventedStats bool  // Build the target from the invented BPC (ME 2/TE 4 + decryptor) instead of ME/TE
 }
 
 // MaterialNode represents a node in the production tree.
@@ -80,6 +88,11 @@ type IndustryActivityStep struct {
 	Probability      float64 `json:"probability,omitempty"`
 	ExpectedAttempts float64 `json:"expected_attempts,omitempty"`
 	Reason           string  `json:"reason,omitempty"`
+
+	// Invention only: decryptor used and the resulting BPC stats.
+	DecryptorName string `json:"decryptor_name,omitempty"`
+	InventedME    int32  `json:"invented_me,omitempty"`
+	InventedTE    int32  `json:"invented_te,omitempty"`
 }
 
 // IndustryAnalysis is the result of analyzing a production chain.
@@ -278,7 +291,7 @@ func (a *IndustryAnalyzer) Analyze(params IndustryParams, progress func(string))
 	// FIX #1: Treat params.Runs as actual blueprint runs.
 	// Calculate total items produced: runs × productQuantity.
 	totalQuantity := params.Runs
-	if bp, ok := a.SDE.Industry.GetBlueprintForProduct(params.TypeID); ok {
+	if bp, ok := a.SDE.IndustryData().GetBlueprintForProduct(params.TypeID); ok {
 		activity := a.activityForProduct(bp, params.TypeID, params.ActivityMode)
 		productQty, _ := blueprintProductForActivity(bp, params.TypeID, activity)
 		if productQty <= 0 {
@@ -443,7 +456,7 @@ func (a *IndustryAnalyzer) buildMaterialTree(typeID int32, quantity int32, param
 	}
 
 	// Check if we can build this item
-	bp, hasBP := a.SDE.Industry.GetBlueprintForProduct(typeID)
+	bp, hasBP := a.SDE.IndustryData().GetBlueprintForProduct(typeID)
 	if !hasBP || depth >= params.MaxDepth {
 		node.IsBase = true
 		return node
@@ -466,12 +479,13 @@ func (a *IndustryAnalyzer) buildMaterialTree(typeID int32, quantity int32, param
 	node.Activity = activity
 	node.Runs = runsNeeded
 
+	me, te := blueprintEfficiency(params, depth)
 	node.Blueprint = &BlueprintInfo{
 		BlueprintTypeID: bp.BlueprintTypeID,
 		ProductQuantity: productQuantity,
-		ME:              params.MaterialEfficiency,
-		TE:              params.TimeEfficiency,
-		Time:            calculateActivityTime(bp, activity, runsNeeded, params.TimeEfficiency),
+		ME:              me,
+		TE:              te,
+		Time:            calculateActivityTime(bp, activity, runsNeeded, te),
 		Activity:        activity,
 		Probability:     probability,
 	}
@@ -479,7 +493,7 @@ func (a *IndustryAnalyzer) buildMaterialTree(typeID int32, quantity int32, param
 	// FIX #5: Apply ME and structure bonus in a single step before ceiling
 	// to avoid rounding errors from intermediate truncation.
 	// EVE formula: max(runs, ceil(base × runs × (1-ME/100) × (1-structureBonus/100)))
-	materials := calculateActivityMaterials(bp, activity, runsNeeded, params.MaterialEfficiency, params.StructureBonus)
+	materials := calculateActivityMaterials(bp, activity, runsNeeded, me, params.StructureBonus)
 
 	// Build children recursively
 	for _, mat := range materials {
@@ -534,7 +548,7 @@ func (a *IndustryAnalyzer) calculateCosts(node *MaterialNode, costIndex float64,
 // FIX #2: EVE uses BASE material quantities (before ME) for EIV, not ME-reduced.
 // Formula: EIV = sum(adjusted_price × base_quantity × runs)
 func (a *IndustryAnalyzer) calculateEIV(node *MaterialNode) float64 {
-	bp, ok := a.SDE.Industry.GetBlueprintForProduct(node.TypeID)
+	bp, ok := a.SDE.IndustryData().GetBlueprintForProduct(node.TypeID)
 	if !ok || bp == nil {
 		return 0
 	}
@@ -739,7 +753,13 @@ func (a *IndustryAnalyzer) calculateInventionStep(params IndustryParams, tree *M
 	if !ok || sourceBP == nil || product.TypeID == 0 {
 		return IndustryActivityStep{}, false
 	}
-	chance := normalizeProbability(product.Probability)
+	decryptor, hasDecryptor := DecryptorByTypeID(params.DecryptorTypeID)
+	chance := normalizeProbability(product.Probability) *
+		InventionSkillMultiplier(params.EncryptionSkill, params.ScienceSkill1, params.ScienceSkill2)
+	if hasDecryptor {
+		chance *= decryptor.ProbabilityMultiplier
+	}
+	chance = math.Min(chance, 1)
 	if params.InventionChance > 0 {
 		chance = normalizeProbability(params.InventionChance)
 	}
@@ -747,6 +767,9 @@ func (a *IndustryAnalyzer) calculateInventionStep(params IndustryParams, tree *M
 		return IndustryActivityStep{}, false
 	}
 	outputRuns := product.Quantity
+	if hasDecryptor {
+		outputRuns += decryptor.RunModifier
+	}
 	if params.InventionOutputRuns > 0 {
 		outputRuns = params.InventionOutputRuns
 	}
@@ -766,7 +789,11 @@ func (a *IndustryAnalyzer) calculateInventionStep(params IndustryParams, tree *M
 		eivPerAttempt += a.adjustedPrices[mat.TypeID] * float64(mat.Quantity)
 	}
 	jobCostPerAttempt := eivPerAttempt * a.costIndexForActivity("invention", fallbackCostIndex) * (1 + params.FacilityTax/100)
-	totalPerAttempt := materialCostPerAttempt + jobCostPerAttempt + params.DecryptorCost
+	decryptorCost := params.DecryptorCost
+	if hasDecryptor && decryptorCost <= 0 {
+		decryptorCost = a.marketBuyCost(decryptor.TypeID, 1)
+	}
+	totalPerAttempt := materialCostPerAttempt + jobCostPerAttempt + decryptorCost
 	step := IndustryActivityStep{
 		Activity:         "invention",
 		BlueprintTypeID:  sourceBP.BlueprintTypeID,
@@ -783,14 +810,18 @@ func (a *IndustryAnalyzer) calculateInventionStep(params IndustryParams, tree *M
 		ExpectedAttempts: expectedAttempts,
 		Reason:           "expected_bpc_cost",
 	}
+	step.InventedME, step.InventedTE = inventedBlueprintStats(params)
+	if hasDecryptor {
+		step.DecryptorName = decryptor.Name
+	}
 	return step, true
 }
 
 func (a *IndustryAnalyzer) findInventionForBlueprint(blueprintTypeID int32) (*sde.Blueprint, sde.BlueprintProduct, bool) {
-	if a == nil || a.SDE == nil || a.SDE.Industry == nil {
+	if a == nil || a.SDE == nil || a.SDE.IndustryData() == nil {
 		return nil, sde.BlueprintProduct{}, false
 	}
-	for _, bp := range a.SDE.Industry.Blueprints {
+	for _, bp := range a.SDE.IndustryData().Blueprints {
 		act := bp.Activities["invention"]
 		if act == nil {
 			continue
@@ -1080,7 +1111,7 @@ func (a *IndustryAnalyzer) fetchMarketPrices(params IndustryParams) (map[int32]f
 
 // GetBlueprintInfo returns blueprint information for a type.
 func (a *IndustryAnalyzer) GetBlueprintInfo(typeID int32) (*sde.Blueprint, bool) {
-	return a.SDE.Industry.GetBlueprintForProduct(typeID)
+	return a.SDE.IndustryData().GetBlueprintForProduct(typeID)
 }
 
 // SearchResult holds a search result with relevance score.
@@ -1124,8 +1155,8 @@ func (a *IndustryAnalyzer) SearchBuildableItems(query string, limit int) []Searc
 
 		// Check if this item has a blueprint (safely)
 		hasBlueprint := false
-		if a.SDE.Industry != nil {
-			_, hasBlueprint = a.SDE.Industry.ProductToBlueprint[typeID]
+		if industry := a.SDE.IndustryData(); industry != nil {
+			_, hasBlueprint = industry.ProductToBlueprint[typeID]
 		}
 
 		results = append(results, SearchResult{
@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"sort"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/sde"
+)
+
+// CostIndexSystem is a system near the origin ranked for an industry activity.
+type CostIndexSystem struct {
+	SystemID      int32   `json:"system_id"`
+	SystemName    string  `json:"system_name"`
+	RegionName    string  `json:"region_name"`
+	Security      float64 `json:"security"`
+	Jumps         int     `json:"jumps"`
+	CostIndex     float64 `json:"cost_index"` // for the ranked activity
+	Manufacturing float64 `json:"manufacturing"`
+	Reaction      float64 `json:"reaction"`
+	Invention     float64 `json:"invention"`
+	Copying       float64 `json:"copying"`
+	Score         float64 `json:"score"` // cost index % + jump_weight × jumps; lower is better
+}
+
+// CostIndexActivity returns the index of activity ("manufacturing",
+// "reaction", "invention" or "copying").
+func CostIndexActivity(idx esi.SystemCostIndices, activity string) (float64, bool) {
+	switch activity {
+	case "manufacturing":
+		return idx.Manufacturing, true
+	case "reaction":
+		return idx.Reaction, true
+	case "invention":
+		return idx.Invention, true
+	case "copying":
+		return idx.Copying, true
+	}
+	return 0, false
+}
+
+// RankCostIndexSystems ranks systems within radius jumps of origin by their
+// cost index for activity plus jumpWeight percentage points per jump, so a
+// slightly dearer system next door can beat a cheap one across the region.
+// Reactions are only possible outside highsec, so highsec systems are
+// dropped for them. Systems without industry activity are skipped.
+func RankCostIndexSystems(data *sde.Data, indices map[int32]esi.SystemCostIndices, origin int32, radius int, activity string, jumpWeight float64, limit int) []CostIndexSystem {
+	if data == nil || data.Universe == nil {
+		return nil
+	}
+	out := []CostIndexSystem{}
+	for systemID, jumps := range data.Universe.SystemsWithinRadius(origin, radius) {
+		idx, ok := indices[systemID]
+		if !ok {
+			continue
+		}
+		ci, _ := CostIndexActivity(idx, activity)
+		if ci <= 0 {
+			continue
+		}
+		sys := data.Systems[systemID]
+		if sys == nil {
+			continue
+		}
+		if activity == "reaction" && sys.Security >= 0.45 {
+			continue
+		}
+		regionName := ""
+		if r := data.Regions[sys.RegionID]; r != nil {
+			regionName = r.Name
+		}
+		out = append(out, CostIndexSystem{
+			SystemID:      systemID,
+			SystemName:    sys.Name,
+			RegionName:    regionName,
+			Security:      sys.Security,
+			Jumps:         jumps,
+			CostIndex:     ci,
+			Manufacturing: idx.Manufacturing,
+			Reaction:      idx.Reaction,
+			Invention:     idx.Invention,
+			Copying:       idx.Copying,
+			Score:         ci*100 + jumpWeight*float64(jumps),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score < out[j].Score
+		}
+		if out[i].Jumps != out[j].Jumps {
+			return out[i].Jumps < out[j].Jumps
+		}
+		return out[i].SystemID < out[j].SystemID
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
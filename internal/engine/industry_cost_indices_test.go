@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"testing"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/graph"
+	"eve-flipper/internal/sde"
+)
+
+func TestRankCostIndexSystems(t *testing.T) {
+	// 1 - 2 - 3 - 4 in a line; 4 is lowsec.
+	u := graph.NewUniverse()
+	for _, e := range [][2]int32{{1, 2}, {2, 3}, {3, 4}} {
+		u.AddGate(e[0], e[1])
+		u.AddGate(e[1], e[0])
+	}
+	data := &sde.Data{
+		Universe: u,
+		Regions:  map[int32]*sde.Region{10: {ID: 10, Name: "Test Region"}},
+		Systems: map[int32]*sde.SolarSystem{
+			1: {ID: 1, Name: "Origin", RegionID: 10, Security: 0.9},
+			2: {ID: 2, Name: "Near", RegionID: 10, Security: 0.7},
+			3: {ID: 3, Name: "Far", RegionID: 10, Security: 0.5},
+			4: {ID: 4, Name: "Low", RegionID: 10, Security: 0.3},
+		},
+	}
+	indices := map[int32]esi.SystemCostIndices{
+		1: {Manufacturing: 0.080, Reaction: 0.01},
+		2: {Manufacturing: 0.030, Reaction: 0.01},
+		3: {Manufacturing: 0.025},
+		4: {Manufacturing: 0.050, Reaction: 0.02},
+	}
+
+	// Without a jump penalty the cheapest index wins.
+	got := RankCostIndexSystems(data, indices, 1, 5, "manufacturing", 0, 0)
+	if len(got) != 4 || got[0].SystemID != 3 || got[1].SystemID != 2 {
+		t.Fatalf("ranking without jump weight = %+v", got)
+	}
+	// 1 point per jump: Near (3% + 1) beats Far (2.5% + 2).
+	got = RankCostIndexSystems(data, indices, 1, 5, "manufacturing", 1, 2)
+	if len(got) != 2 || got[0].SystemID != 2 || got[0].Jumps != 1 || got[0].RegionName != "Test Region" {
+		t.Fatalf("ranking with jump weight = %+v", got)
+	}
+	// Reactions skip highsec.
+	got = RankCostIndexSystems(data, indices, 1, 5, "reaction", 0, 0)
+	if len(got) != 1 || got[0].SystemID != 4 || got[0].CostIndex != 0.02 {
+		t.Fatalf("reaction ranking = %+v", got)
+	}
+	// Radius limits the search.
+	if got := RankCostIndexSystems(data, indices, 1, 1, "manufacturing", 0, 0); len(got) != 2 {
+		t.Fatalf("radius 1 = %+v, want origin and neighbour", got)
+	}
+}
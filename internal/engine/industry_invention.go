@@ -0,0 +1,76 @@
+package engine
+
+// Decryptor is an invention decryptor and its effect on the invented BPC.
+type Decryptor struct {
+	TypeID                int32   `json:"type_id"`
+	Name                  string  `json:"name"`
+	ProbabilityMultiplier float64 `json:"probability_multiplier"`
+	RunModifier           int32   `json:"run_modifier"`
+	MEModifier            int32   `json:"me_modifier"`
+	TEModifier            int32   `json:"te_modifier"`
+}
+
+// Decryptors are the T2 invention decryptors (dogma attributes 1112-1114,
+// 1124 of the decryptor types).
+var Decryptors = []Decryptor{
+	{TypeID: 34201, Name: "Accelerant Decryptor", ProbabilityMultiplier: 1.2, RunModifier: 1, MEModifier: 2, TEModifier: 10},
+	{TypeID: 34202, Name: "Attainment Decryptor", ProbabilityMultiplier: 1.8, RunModifier: 4, MEModifier: -1, TEModifier: 4},
+	{TypeID: 34203, Name: "Augmentation Decryptor", ProbabilityMultiplier: 0.6, RunModifier: 9, MEModifier: -2, TEModifier: 2},
+	{TypeID: 34204, Name: "Parity Decryptor", ProbabilityMultiplier: 1.5, RunModifier: 3, MEModifier: 1, TEModifier: -2},
+	{TypeID: 34205, Name: "Process Decryptor", ProbabilityMultiplier: 1.1, RunModifier: 0, MEModifier: 3, TEModifier: 6},
+	{TypeID: 34206, Name: "Symmetry Decryptor", ProbabilityMultiplier: 1.0, RunModifier: 2, MEModifier: 1, TEModifier: 8},
+	{TypeID: 34207, Name: "Optimized Attainment Decryptor", ProbabilityMultiplier: 1.9, RunModifier: 2, MEModifier: 1, TEModifier: -2},
+	{TypeID: 34208, Name: "Optimized Augmentation Decryptor", ProbabilityMultiplier: 0.9, RunModifier: 7, MEModifier: 2, TEModifier: 0},
+}
+
+// DecryptorByTypeID looks up a decryptor.
+func DecryptorByTypeID(typeID int32) (Decryptor, bool) {
+	for _, d := range Decryptors {
+		if d.TypeID == typeID {
+			return d, true
+		}
+	}
+	return Decryptor{}, false
+}
+
+// Invented T2 BPCs start at ME 2 / TE 4 before decryptor modifiers.
+const (
+	inventedBaseME int32 = 2
+	inventedBaseTE int32 = 4
+)
+
+// InventionSkillMultiplier is the success chance multiplier from skills:
+// 1 + Encryption Methods/40 + (science skill 1 + science skill 2)/30.
+func InventionSkillMultiplier(encryption, science1, science2 int32) float64 {
+	return 1 + float64(clampSkillLevel(encryption))/40 + float64(clampSkillLevel(science1)+clampSkillLevel(science2))/30
+}
+
+func clampSkillLevel(level int32) int32 {
+	if level < 0 {
+		return 0
+	}
+	if level > 5 {
+		return 5
+	}
+	return level
+}
+
+// inventedBlueprintStats returns the ME/TE of a BPC invented with params'
+// decryptor (if any).
+func inventedBlueprintStats(params IndustryParams) (me, te int32) {
+	me, te = inventedBaseME, inventedBaseTE
+	if d, ok := DecryptorByTypeID(params.DecryptorTypeID); ok {
+		me += d.MEModifier
+		te += d.TEModifier
+	}
+	return max(me, 0), max(te, 0)
+}
+
+// blueprintEfficiency is the ME/TE used for a tree node. In invention mode
+// with UseInventedStats the target blueprint is the invented BPC.
+func blueprintEfficiency(params IndustryParams, depth int) (me, te int32) {
+	if depth == 0 && params.ActivityMode == "invention" && params.UseInventedStats {
+		return inventedBlueprintStats(params)
+	}
+	return params.MaterialEfficiency, params.TimeEfficiency
+}
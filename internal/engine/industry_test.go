@@ -576,6 +576,112 @@ func TestAnalyze_InventionAddsExpectedBPCCost(t *testing.T) {
 	}
 }
 
+// newInventionTestAnalyzer has a T2 module invented from a T1 blueprint with
+// 40% base chance and 10-run BPCs.
+func newInventionTestAnalyzer() *IndustryAnalyzer {
+	ind := sde.NewIndustryData()
+	ind.Blueprints[5001] = &sde.Blueprint{
+		BlueprintTypeID: 5001,
+		ProductTypeID:   5000,
+		ProductQuantity: 1,
+		Time:            1000,
+		Materials:       []sde.BlueprintMaterial{{TypeID: 34, Quantity: 10}},
+		Activities: map[string]*sde.ActivityData{
+			"manufacturing": {
+				Time:      1000,
+				Materials: []sde.BlueprintMaterial{{TypeID: 34, Quantity: 10}},
+				Products:  []sde.BlueprintProduct{{TypeID: 5000, Quantity: 1}},
+			},
+		},
+	}
+	ind.ProductToBlueprint[5000] = 5001
+	ind.Blueprints[5100] = &sde.Blueprint{
+		BlueprintTypeID: 5100,
+		Activities: map[string]*sde.ActivityData{
+			"invention": {
+				Time:      100,
+				Materials: []sde.BlueprintMaterial{{TypeID: 6001, Quantity: 2}},
+				Products:  []sde.BlueprintProduct{{TypeID: 5001, Quantity: 10, Probability: 0.4}},
+			},
+		},
+	}
+	return &IndustryAnalyzer{
+		SDE: &sde.Data{
+			Types: map[int32]*sde.ItemType{
+				34:   {ID: 34, Name: "Tritanium"},
+				5000: {ID: 5000, Name: "T2 Module"},
+				5001: {ID: 5001, Name: "T2 Module Blueprint"},
+				5100: {ID: 5100, Name: "T1 Module Blueprint"},
+				6001: {ID: 6001, Name: "Datacore"},
+			},
+			Systems:  map[int32]*sde.SolarSystem{30000142: {ID: 30000142, Name: "Jita", RegionID: 10000002}},
+			Regions:  map[int32]*sde.Region{10000002: {ID: 10000002, Name: "The Forge"}},
+			Industry: ind,
+		},
+		IndustryCache: esi.NewIndustryCache(),
+		getAllAdjustedPrices: func(_ *esi.IndustryCache) (map[int32]float64, error) {
+			return map[int32]float64{34: 1, 6001: 50}, nil
+		},
+		getSystemCostIndex: func(_ *esi.IndustryCache, _ int32) (*esi.SystemCostIndices, error) {
+			return &esi.SystemCostIndices{Manufacturing: 0, Invention: 0.1}, nil
+		},
+		fetchMarketPricesFn: func(_ IndustryParams) (map[int32]float64, error) {
+			return map[int32]float64{34: 5, 5000: 1000, 6001: 100, 34204: 300}, nil
+		},
+		fetchMarketBooksFn: func(_ IndustryParams) (map[int32][]esi.MarketOrder, map[int32][]esi.MarketOrder, error) {
+			return nil, nil, nil
+		},
+	}
+}
+
+func TestAnalyze_InventionDecryptorAndSkills(t *testing.T) {
+	a := newInventionTestAnalyzer()
+	result, err := a.Analyze(IndustryParams{
+		TypeID:           5000,
+		Runs:             20,
+		ActivityMode:     "invention",
+		SystemID:         30000142,
+		DecryptorTypeID:  34204, // Parity: x1.5 chance, +3 runs, ME +1, TE -2
+		EncryptionSkill:  4,
+		ScienceSkill1:    4,
+		ScienceSkill2:    4,
+		UseInventedStats: true,
+	}, func(string) {})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	// 0.4 x (1 + 4/40 + 8/30) x 1.5 = 0.82; 13-run BPCs need 2 successes.
+	if !industryAlmostEqual(result.InventionProbability, 0.82) {
+		t.Fatalf("InventionProbability = %v, want 0.82", result.InventionProbability)
+	}
+	attempts := 2 / 0.82
+	if !industryAlmostEqual(result.InventionAttempts, attempts) {
+		t.Fatalf("InventionAttempts = %v, want %v", result.InventionAttempts, attempts)
+	}
+	// Per attempt: datacores 200 + job 10 + decryptor 300 from the market.
+	if !industryAlmostEqual(result.InventionCost, 510*attempts) {
+		t.Fatalf("InventionCost = %v, want %v", result.InventionCost, 510*attempts)
+	}
+	step := result.ActivityPlan[0]
+	if step.DecryptorName != "Parity Decryptor" || step.InventedME != 3 || step.InventedTE != 2 {
+		t.Fatalf("invention step = %+v", step)
+	}
+	// Invented ME 3: ceil(10 x 20 x 0.97) = 194 Tritanium at 5 ISK.
+	if result.MaterialTree.Blueprint.ME != 3 || !industryAlmostEqual(result.MaterialTree.MaterialCost, 970) {
+		t.Fatalf("target blueprint ME = %d, material cost = %v, want ME 3 and 970",
+			result.MaterialTree.Blueprint.ME, result.MaterialTree.MaterialCost)
+	}
+}
+
+func TestInventionSkillMultiplier(t *testing.T) {
+	if got := InventionSkillMultiplier(0, 0, 0); got != 1 {
+		t.Fatalf("no skills = %v, want 1", got)
+	}
+	if got := InventionSkillMultiplier(5, 5, 9); !industryAlmostEqual(got, 1+5.0/40+10.0/30) {
+		t.Fatalf("max skills = %v", got)
+	}
+}
+
 func TestAnalyze_TypeNotFound(t *testing.T) {
 	a := &IndustryAnalyzer{
 		SDE: &sde.Data{
@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"sort"
+
+	"eve-flipper/internal/esi"
+)
+
+// Windows and depth for the item inspector.
+const (
+	ItemDetailLadderDepth = 20
+	itemDetailMetricDays  = 30
+)
+
+// LadderLevel is one aggregated price level of an order book side.
+type LadderLevel struct {
+	Price  float64 `json:"price"`
+	Volume int64   `json:"volume"`
+	Orders int     `json:"orders"`
+}
+
+// ItemMarketDetail is the order book ladder and station-trading metrics of one
+// type in one market (a station, or a whole region).
+type ItemMarketDetail struct {
+	Bids        []LadderLevel `json:"bids"` // best (highest) first
+	Asks        []LadderLevel `json:"asks"` // best (lowest) first
+	BestBid     float64       `json:"best_bid"`
+	BestAsk     float64       `json:"best_ask"`
+	Spread      float64       `json:"spread"`
+	SpreadPct   float64       `json:"spread_pct"` // of best ask
+	BidVolume   int64         `json:"bid_volume"`
+	AskVolume   int64         `json:"ask_volume"`
+	DailyVolume int64         `json:"daily_volume"` // 7-day average
+	VWAP        float64       `json:"vwap"`
+	DRVI        float64       `json:"drvi"`
+	SpreadROI   float64       `json:"spread_roi"`
+	OBDS        float64       `json:"obds"`
+	SDS         int           `json:"sds"`
+	CI          int           `json:"ci"`
+	CTS         float64       `json:"cts"`
+	HistoryDays int           `json:"history_days"`
+}
+
+// buildLadder aggregates orders by price, best first, keeping depth levels.
+func buildLadder(orders []esi.MarketOrder, bids bool, depth int) []LadderLevel {
+	byPrice := make(map[float64]*LadderLevel)
+	for _, o := range orders {
+		l := byPrice[o.Price]
+		if l == nil {
+			l = &LadderLevel{Price: o.Price}
+			byPrice[o.Price] = l
+		}
+		l.Volume += int64(o.VolumeRemain)
+		l.Orders++
+	}
+	ladder := make([]LadderLevel, 0, len(byPrice))
+	for _, l := range byPrice {
+		ladder = append(ladder, *l)
+	}
+	sort.Slice(ladder, func(i, j int) bool {
+		if bids {
+			return ladder[i].Price > ladder[j].Price
+		}
+		return ladder[i].Price < ladder[j].Price
+	})
+	if depth > 0 && len(ladder) > depth {
+		ladder = ladder[:depth]
+	}
+	return ladder
+}
+
+// BuildItemMarketDetail computes the ladder and the station-trading metrics
+// (VWAP, DRVI, spread ROI, OBDS, SDS, CI, CTS) for orders of one type.
+// stationID > 0 restricts the book to that location.
+func BuildItemMarketDetail(orders []esi.MarketOrder, history []esi.HistoryEntry, stationID int64) ItemMarketDetail {
+	var buys, sells []esi.MarketOrder
+	for _, o := range orders {
+		if o.VolumeRemain <= 0 || o.Price <= 0 {
+			continue
+		}
+		if stationID > 0 && o.LocationID != stationID {
+			continue
+		}
+		if o.IsBuyOrder {
+			buys = append(buys, o)
+		} else {
+			sells = append(sells, o)
+		}
+	}
+
+	d := ItemMarketDetail{
+		Bids:        buildLadder(buys, true, ItemDetailLadderDepth),
+		Asks:        buildLadder(sells, false, ItemDetailLadderDepth),
+		BestBid:     maxBuyPrice(buys),
+		BestAsk:     minSellPrice(sells),
+		HistoryDays: len(history),
+	}
+	for _, o := range buys {
+		d.BidVolume += int64(o.VolumeRemain)
+	}
+	for _, o := range sells {
+		d.AskVolume += int64(o.VolumeRemain)
+	}
+	if d.BestBid > 0 && d.BestAsk > 0 {
+		d.Spread = sanitizeFloat(d.BestAsk - d.BestBid)
+		d.SpreadPct = sanitizeFloat(d.Spread / d.BestAsk * 100)
+	}
+
+	d.DailyVolume = esi.ComputeMarketStats(history, 0).DailyVolume
+	d.VWAP = sanitizeFloat(CalcVWAP(history, itemDetailMetricDays))
+	d.DRVI = sanitizeFloat(CalcDRVI(history, itemDetailMetricDays))
+	d.SpreadROI = sanitizeFloat(CalcSpreadROI(history, itemDetailMetricDays))
+	d.CI = CalcCI(append(append([]esi.MarketOrder(nil), buys...), sells...))
+	if tradable := min(d.BidVolume, d.AskVolume); tradable > 0 && d.BestBid > 0 {
+		d.OBDS = sanitizeFloat(CalcOBDS(buys, sells, d.BestBid*float64(tradable)))
+	}
+	d.SDS = CalcSDS(buys, sells, history, d.VWAP)
+	d.CTS = sanitizeFloat(CalcCTS(d.SpreadROI, d.OBDS, d.DRVI, d.CI, d.SDS, float64(d.DailyVolume)))
+	return d
+}
@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"testing"
+
+	"eve-flipper/internal/esi"
+)
+
+func TestBuildItemMarketDetailLadder(t *testing.T) {
+	orders := []esi.MarketOrder{
+		{IsBuyOrder: true, Price: 90, VolumeRemain: 10, LocationID: 1},
+		{IsBuyOrder: true, Price: 90, VolumeRemain: 5, LocationID: 1},
+		{IsBuyOrder: true, Price: 95, VolumeRemain: 1, LocationID: 1},
+		{IsBuyOrder: true, Price: 99, VolumeRemain: 1, LocationID: 2}, // other station
+		{Price: 100, VolumeRemain: 3, LocationID: 1},
+		{Price: 110, VolumeRemain: 7, LocationID: 1},
+		{Price: 105, VolumeRemain: 0, LocationID: 1}, // empty order
+	}
+	d := BuildItemMarketDetail(orders, nil, 1)
+
+	if len(d.Bids) != 2 || d.Bids[0].Price != 95 || d.Bids[1].Volume != 15 || d.Bids[1].Orders != 2 {
+		t.Fatalf("bids = %+v", d.Bids)
+	}
+	if len(d.Asks) != 2 || d.Asks[0].Price != 100 || d.Asks[1].Price != 110 {
+		t.Fatalf("asks = %+v", d.Asks)
+	}
+	if d.BestBid != 95 || d.BestAsk != 100 || d.Spread != 5 || d.SpreadPct != 5 {
+		t.Fatalf("top of book = %v/%v spread %v (%v%%)", d.BestBid, d.BestAsk, d.Spread, d.SpreadPct)
+	}
+	if d.BidVolume != 16 || d.AskVolume != 10 {
+		t.Fatalf("volumes = %d/%d, want 16/10", d.BidVolume, d.AskVolume)
+	}
+
+	region := BuildItemMarketDetail(orders, nil, 0)
+	if region.BestBid != 99 {
+		t.Fatalf("region best bid = %v, want 99", region.BestBid)
+	}
+}
@@ -0,0 +1,233 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+
+	"eve-flipper/internal/graph"
+)
+
+// Jump drive defaults for a jump freighter.
+const (
+	DefaultJumpRangeLY          = 10.0
+	DefaultJumpFatigueReduction = 0.90 // jump freighters: 90% reduction in effective distance
+	// DefaultJumpIsotopesPerLY is a typical well-skilled jump freighter
+	// (Jump Fuel Conservation IV, Jump Freighters IV).
+	DefaultJumpIsotopesPerLY = 3300.0
+	// HeliumIsotopesTypeID fuels the Ark; other racial JFs burn the other isotopes.
+	HeliumIsotopesTypeID int32 = 16274
+
+	maxJumpFatigueMinutes      = 300.0 // 5h cap
+	maxJumpReactivationMinutes = 30.0
+	minJumpFatigueMinutes      = 10.0
+	// maxJumpGateDetour bounds gate travel to reach/leave jump-capable space.
+	maxJumpGateDetour = 10
+)
+
+// JumpRouteParams configures jump-drive routing.
+type JumpRouteParams struct {
+	RangeLY          float64 // max LY per jump (<=0 = default)
+	FatigueReduction float64 // 0..1 reduction of effective LY for fatigue (<0 = default)
+	IsotopesPerLY    float64 // isotopes burned per LY (<=0 = default)
+	IsotopePrice     float64 // ISK per isotope unit (0 = fuel ISK not priced)
+	// RoutePreferences are the user's avoided systems and pinned paths.
+	RoutePreferences graph.RoutePreferences
+}
+
+func (p JumpRouteParams) normalized() JumpRouteParams {
+	if p.RangeLY <= 0 {
+		p.RangeLY = DefaultJumpRangeLY
+	}
+	if p.FatigueReduction < 0 || p.FatigueReduction >= 1 {
+		p.FatigueReduction = DefaultJumpFatigueReduction
+	}
+	if p.IsotopesPerLY <= 0 {
+		p.IsotopesPerLY = DefaultJumpIsotopesPerLY
+	}
+	if p.IsotopePrice < 0 {
+		p.IsotopePrice = 0
+	}
+	return p
+}
+
+// JumpLeg is one jump-drive activation.
+type JumpLeg struct {
+	FromSystemID        int32
+	FromSystemName      string
+	ToSystemID          int32
+	ToSystemName        string
+	LightYears          float64
+	IsotopeUnits        int64
+	FuelISK             float64
+	WaitMinutes         float64 // reactivation wait before this jump
+	FatigueAfterMinutes float64
+	ReactivationMinutes float64 // jump activation timer set by this jump
+}
+
+// JumpRoute is a jump-drive chain between two systems, with optional gate
+// legs to leave/enter highsec where jump drives cannot operate.
+type JumpRoute struct {
+	FromSystemID        int32
+	FromSystemName      string
+	ToSystemID          int32
+	ToSystemName        string
+	GateJumpsBefore     int // gate jumps from origin to the first jump-capable system
+	GateJumpsAfter      int // gate jumps from the last jump landing to destination
+	Legs                []JumpLeg
+	JumpCount           int
+	TotalLY             float64
+	IsotopeUnits        int64
+	FuelISK             float64
+	TotalWaitMinutes    float64
+	FinalFatigueMinutes float64
+}
+
+// jumpFatigueStep applies one jump to the fatigue model (TQ rules):
+//   - effective LY = LY × (1 − reduction)
+//   - activation timer = max(1 + effLY, fatigue/10) minutes, capped at 30
+//   - fatigue = max(fatigue, 10) × (1 + effLY) minutes, capped at 5h
+func jumpFatigueStep(fatigue, ly, reduction float64) (newFatigue, reactivation float64) {
+	eff := ly * (1 - reduction)
+	reactivation = math.Min(math.Max(1+eff, fatigue/10), maxJumpReactivationMinutes)
+	newFatigue = math.Min(math.Max(fatigue, minJumpFatigueMinutes)*(1+eff), maxJumpFatigueMinutes)
+	return newFatigue, reactivation
+}
+
+// nearestJumpCapableSystem returns the closest system by gates that a jump
+// drive can operate from/to (the system itself when already eligible).
+func (s *Scanner) nearestJumpCapableSystem(systemID int32) (int32, int) {
+	u := s.SDE.Universe
+	if u.IsJumpTarget(systemID) {
+		return systemID, 0
+	}
+	best, bestDist := int32(0), 0
+	for id, d := range u.SystemsWithinRadius(systemID, maxJumpGateDetour) {
+		if !u.IsJumpTarget(id) {
+			continue
+		}
+		if best == 0 || d < bestDist || (d == bestDist && id < best) {
+			best, bestDist = id, d
+		}
+	}
+	return best, bestDist
+}
+
+// PlanJumpRoute builds a jump chain from one system to another. Highsec
+// endpoints are connected by gates to the nearest low/null system.
+// planner may be nil; pass a shared planner when routing many pairs.
+func (s *Scanner) PlanJumpRoute(from, to int32, params JumpRouteParams, planner *graph.JumpPlanner) (*JumpRoute, error) {
+	params = params.normalized()
+	s = s.withRoutePreferences(params.RoutePreferences)
+	if planner == nil || planner.RangeLY() != params.RangeLY {
+		planner = s.SDE.Universe.NewJumpPlanner(params.RangeLY)
+	}
+	route := &JumpRoute{
+		FromSystemID:   from,
+		FromSystemName: s.systemName(from),
+		ToSystemID:     to,
+		ToSystemName:   s.systemName(to),
+		Legs:           []JumpLeg{},
+	}
+	if from == to {
+		return route, nil
+	}
+
+	start, gateBefore := s.nearestJumpCapableSystem(from)
+	end, gateAfter := s.nearestJumpCapableSystem(to)
+	if start == 0 || end == 0 {
+		return nil, fmt.Errorf("no jump-capable system within %d gates of %s", maxJumpGateDetour, route.FromSystemName)
+	}
+	route.GateJumpsBefore = gateBefore
+	route.GateJumpsAfter = gateAfter
+
+	path := planner.JumpPath(start, end)
+	if path == nil {
+		return nil, fmt.Errorf("%s is not reachable from %s with %.1f LY range",
+			s.systemName(end), s.systemName(start), params.RangeLY)
+	}
+
+	fatigue := 0.0
+	reactivation := 0.0
+	for i := 1; i < len(path); i++ {
+		ly, _ := s.SDE.Universe.LightYearsBetween(path[i-1], path[i])
+		wait := reactivation
+		// Fatigue decays 1:1 in real time while waiting out the timer.
+		fatigue = math.Max(fatigue-wait, 0)
+		fatigue, reactivation = jumpFatigueStep(fatigue, ly, params.FatigueReduction)
+
+		units := int64(math.Ceil(ly * params.IsotopesPerLY))
+		leg := JumpLeg{
+			FromSystemID:        path[i-1],
+			FromSystemName:      s.systemName(path[i-1]),
+			ToSystemID:          path[i],
+			ToSystemName:        s.systemName(path[i]),
+			LightYears:          sanitizeFloat(ly),
+			IsotopeUnits:        units,
+			FuelISK:             sanitizeFloat(float64(units) * params.IsotopePrice),
+			WaitMinutes:         sanitizeFloat(wait),
+			FatigueAfterMinutes: sanitizeFloat(fatigue),
+			ReactivationMinutes: sanitizeFloat(reactivation),
+		}
+		route.Legs = append(route.Legs, leg)
+		route.TotalLY += ly
+		route.IsotopeUnits += units
+		route.FuelISK += leg.FuelISK
+		route.TotalWaitMinutes += wait
+	}
+	route.JumpCount = len(route.Legs)
+	route.TotalLY = sanitizeFloat(route.TotalLY)
+	route.FuelISK = sanitizeFloat(route.FuelISK)
+	route.TotalWaitMinutes = sanitizeFloat(route.TotalWaitMinutes)
+	route.FinalFatigueMinutes = sanitizeFloat(fatigue)
+	return route, nil
+}
+
+// applyJumpFuelCosts routes each result buy→sell by jump drive and deducts
+// isotope fuel from its profit. Results that become unprofitable or whose
+// endpoints cannot be connected are dropped.
+func (s *Scanner) applyJumpFuelCosts(results []FlipResult, params ScanParams) []FlipResult {
+	jp := JumpRouteParams{
+		RangeLY:          params.JumpRangeLY,
+		FatigueReduction: params.JumpFatigueReduction,
+		IsotopesPerLY:    params.JumpIsotopesPerLY,
+		IsotopePrice:     params.JumpIsotopePrice,
+	}.normalized()
+	planner := s.SDE.Universe.NewJumpPlanner(jp.RangeLY)
+
+	type pairKey struct{ from, to int32 }
+	cache := make(map[pairKey]*JumpRoute)
+	out := results[:0]
+	for _, r := range results {
+		key := pairKey{r.BuySystemID, r.SellSystemID}
+		route, ok := cache[key]
+		if !ok {
+			route, _ = s.PlanJumpRoute(r.BuySystemID, r.SellSystemID, jp, planner)
+			cache[key] = route
+		}
+		if route == nil {
+			continue
+		}
+		r.JumpCount = route.JumpCount
+		r.JumpLY = route.TotalLY
+		r.JumpFuelISK = route.FuelISK
+		r.JumpWaitMinutes = route.TotalWaitMinutes
+		r.JumpFatigueMinutes = route.FinalFatigueMinutes
+		if route.FuelISK > 0 {
+			r.TotalProfit = sanitizeFloat(r.TotalProfit - route.FuelISK)
+			if r.RealProfit != 0 {
+				r.RealProfit = sanitizeFloat(r.RealProfit - route.FuelISK)
+				r.ExpectedProfit = r.RealProfit
+			}
+			if r.TotalProfit <= 0 {
+				continue
+			}
+		}
+		// Capital haulers pay per activation + gate legs, not per gate jump.
+		legs := r.BuyJumps + route.GateJumpsBefore + route.JumpCount + route.GateJumpsAfter
+		if legs > 0 {
+			r.ProfitPerJump = sanitizeFloat(r.TotalProfit / float64(legs))
+		}
+		out = append(out, r)
+	}
+	return out
+}
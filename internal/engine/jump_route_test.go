@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"math"
+	"testing"
+
+	"eve-flipper/internal/graph"
+	"eve-flipper/internal/sde"
+)
+
+func TestJumpFatigueStep(t *testing.T) {
+	// First 5 LY jump in a JF (90% reduction): eff 0.5 LY.
+	fatigue, reactivation := jumpFatigueStep(0, 5, 0.9)
+	if math.Abs(fatigue-15) > 1e-9 || math.Abs(reactivation-1.5) > 1e-9 {
+		t.Fatalf("first jump fatigue=%v reactivation=%v, want 15/1.5", fatigue, reactivation)
+	}
+	// Long jumps without reduction hit both caps.
+	fatigue, reactivation = jumpFatigueStep(300, 40, 0)
+	if fatigue != maxJumpFatigueMinutes || reactivation != maxJumpReactivationMinutes {
+		t.Fatalf("capped fatigue=%v reactivation=%v", fatigue, reactivation)
+	}
+}
+
+func TestPlanJumpRoute_GatesOutOfHighsecAndPricesFuel(t *testing.T) {
+	u := graph.NewUniverse()
+	secs := []float64{0.9, 0.3, 0.2, 0.1}
+	for i, sec := range secs {
+		id := int32(i + 1)
+		u.SetSecurity(id, sec)
+		u.SetRegion(id, 10000001)
+		u.SetPosition(id, float64(i)*4*graph.MetersPerLightYear, 0, 0)
+	}
+	u.AddGate(1, 2)
+	u.AddGate(2, 1)
+	s := &Scanner{SDE: &sde.Data{Universe: u, Systems: map[int32]*sde.SolarSystem{}}}
+
+	route, err := s.PlanJumpRoute(1, 4, JumpRouteParams{
+		RangeLY:          5,
+		FatigueReduction: 0.9,
+		IsotopesPerLY:    1000,
+		IsotopePrice:     500,
+	}, nil)
+	if err != nil {
+		t.Fatalf("PlanJumpRoute: %v", err)
+	}
+	if route.GateJumpsBefore != 1 || route.JumpCount != 2 {
+		t.Fatalf("gates=%d jumps=%d, want 1 gate + 2 jumps", route.GateJumpsBefore, route.JumpCount)
+	}
+	if math.Abs(route.TotalLY-8) > 1e-6 || route.IsotopeUnits != 8000 || route.FuelISK != 4_000_000 {
+		t.Fatalf("LY=%v isotopes=%d fuel=%v", route.TotalLY, route.IsotopeUnits, route.FuelISK)
+	}
+	if route.Legs[0].WaitMinutes != 0 || route.Legs[1].WaitMinutes <= 0 {
+		t.Errorf("expected reactivation wait only before the second jump: %+v", route.Legs)
+	}
+}
+
+func TestPlanJumpRoute_NeverLandsInAvoidedSystems(t *testing.T) {
+	u := graph.NewUniverse()
+	for i, sec := range []float64{0.9, 0.3, 0.2, 0.1} {
+		id := int32(i + 1)
+		u.SetSecurity(id, sec)
+		u.SetRegion(id, 10000001)
+		u.SetPosition(id, float64(i)*4*graph.MetersPerLightYear, 0, 0)
+	}
+	u.AddGate(1, 2)
+	u.AddGate(2, 1)
+	s := &Scanner{SDE: &sde.Data{Universe: u, Systems: map[int32]*sde.SolarSystem{}}}
+
+	params := JumpRouteParams{RangeLY: 5}
+	if _, err := s.PlanJumpRoute(2, 4, params, nil); err != nil {
+		t.Fatalf("PlanJumpRoute: %v", err)
+	}
+	params.RoutePreferences = graph.RoutePreferences{AvoidSystems: []int32{3}}
+	if route, err := s.PlanJumpRoute(2, 4, params, nil); err == nil {
+		t.Fatalf("route through avoided system 3: %+v", route)
+	}
+}
@@ -30,6 +30,31 @@ func estimateCycleFillTimeDays(units int32, s2bPerDay, bfsPerDay float64) float6
 	return estimateFillTimeDaysFromFlow(int64(units), math.Min(s2bPerDay, bfsPerDay))
 }
 
+// estimateLegFillDays estimates days to fill units on one leg of a flip.
+// Units covered by bookDepth fill immediately; the shortfall waits on
+// dailyFlow. ok is false when there is a shortfall but no known flow.
+func estimateLegFillDays(units, bookDepth int32, dailyFlow float64) (days float64, ok bool) {
+	shortfall := int64(units) - int64(max(bookDepth, 0))
+	if shortfall <= 0 {
+		return 0, true
+	}
+	if dailyFlow <= 0 {
+		return 0, false
+	}
+	return estimateFillTimeDaysFromFlow(shortfall, dailyFlow), true
+}
+
+// flipLegFillDays returns the buy and sell leg fill estimates for r.
+func flipLegFillDays(r FlipResult) (buyDays, sellDays float64, ok bool) {
+	units := r.FilledQty
+	if units <= 0 {
+		units = r.UnitsToBuy
+	}
+	buyDays, buyOK := estimateLegFillDays(units, r.SellOrderRemain, r.BfSPerDay)
+	sellDays, sellOK := estimateLegFillDays(units, r.BuyOrderRemain, r.S2BPerDay)
+	return buyDays, sellDays, buyOK && sellOK
+}
+
 func liquidityScoreFromFillTime(fillTimeDays float64, historyAvailable bool) (float64, string) {
 	if fillTimeDays <= 0 || !historyAvailable {
 		return 0, "unknown"
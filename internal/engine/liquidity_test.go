@@ -18,6 +18,25 @@ func TestEstimateCycleFillTimeDaysRequiresBothFlows(t *testing.T) {
 	}
 }
 
+func TestFlipLegFillDays(t *testing.T) {
+	r := FlipResult{
+		UnitsToBuy:      100,
+		SellOrderRemain: 100, // source asks cover the buy leg
+		BuyOrderRemain:  40,  // destination bids cover 40 of 100
+		S2BPerDay:       30,
+		BfSPerDay:       10,
+	}
+	buyDays, sellDays, ok := flipLegFillDays(r)
+	if !ok || buyDays != 0 || sellDays != 2 {
+		t.Fatalf("flipLegFillDays = %v, %v, %v; want 0, 2, true", buyDays, sellDays, ok)
+	}
+
+	r.S2BPerDay = 0
+	if _, _, ok := flipLegFillDays(r); ok {
+		t.Fatal("shortfall without flow should be unknown")
+	}
+}
+
 func TestLiquidityScoreFromFillTime(t *testing.T) {
 	score, label := liquidityScoreFromFillTime(2.5, true)
 	if score != 80 || label != "high" {
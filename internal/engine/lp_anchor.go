@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/pricing"
+)
+
+// FactionWarfareLPCorporations are the militia corporations whose LP stores
+// anchor faction warfare item prices.
+var FactionWarfareLPCorporations = []int32{
+	1000179, // 24th Imperial Crusade
+	1000180, // State Protectorate
+	1000181, // Federal Defense Union
+	1000182, // Tribal Liberation Force
+}
+
+// Default ISK/LP rates bounding the LP-anchored price band. Below the floor
+// rate nobody converts LP, so supply dries up; above the ceiling rate
+// converters flood the market.
+const (
+	DefaultLPFloorISKPerLP   = 500.0
+	DefaultLPCeilingISKPerLP = 1500.0
+)
+
+// LP signals set on FlipResult.LPSignal.
+const (
+	LPSignalBelowFloor   = "below_floor"   // buy price is under the conversion cost at the floor rate
+	LPSignalAboveCeiling = "above_ceiling" // sell price is over the conversion cost at the ceiling rate
+)
+
+// LPAnchor is the per-unit price band of an item produced by an LP store.
+type LPAnchor struct {
+	TypeID  int32
+	Floor   float64 // unit cost at the floor ISK/LP rate
+	Ceiling float64 // unit cost at the ceiling ISK/LP rate
+}
+
+// BuildLPAnchors prices every offer at the floor and ceiling ISK/LP rates,
+// buying required items at quotes' sell price. When several offers produce
+// the same type, the cheapest conversion sets the band. Offers with unpriced
+// required items are skipped.
+func BuildLPAnchors(offers []esi.LoyaltyOffer, quotes map[int32]pricing.Quote, floorISKPerLP, ceilingISKPerLP float64) map[int32]LPAnchor {
+	if floorISKPerLP <= 0 {
+		floorISKPerLP = DefaultLPFloorISKPerLP
+	}
+	if ceilingISKPerLP < floorISKPerLP {
+		ceilingISKPerLP = floorISKPerLP
+	}
+	anchors := make(map[int32]LPAnchor)
+	for _, o := range offers {
+		if o.Quantity <= 0 || o.LPCost <= 0 || o.AKCost > 0 {
+			continue
+		}
+		itemCost := o.ISKCost
+		priced := true
+		for _, req := range o.RequiredItems {
+			q := quotes[req.TypeID]
+			if q.Sell <= 0 {
+				priced = false
+				break
+			}
+			itemCost += q.Sell * float64(req.Quantity)
+		}
+		if !priced {
+			continue
+		}
+		qty := float64(o.Quantity)
+		anchor := LPAnchor{
+			TypeID:  o.TypeID,
+			Floor:   sanitizeFloat((itemCost + float64(o.LPCost)*floorISKPerLP) / qty),
+			Ceiling: sanitizeFloat((itemCost + float64(o.LPCost)*ceilingISKPerLP) / qty),
+		}
+		if prev, ok := anchors[o.TypeID]; ok && prev.Floor <= anchor.Floor {
+			continue
+		}
+		anchors[o.TypeID] = anchor
+	}
+	return anchors
+}
+
+// LPAnchorInputTypes returns the required item types the offers consume, for
+// fetching the quotes BuildLPAnchors needs.
+func LPAnchorInputTypes(offers []esi.LoyaltyOffer) []int32 {
+	seen := make(map[int32]bool)
+	var out []int32
+	for _, o := range offers {
+		for _, req := range o.RequiredItems {
+			if !seen[req.TypeID] {
+				seen[req.TypeID] = true
+				out = append(out, req.TypeID)
+			}
+		}
+	}
+	return out
+}
+
+// AnnotateLPAnchors sets the LP band on results for LP store items and flags
+// flips whose prices have left it.
+func AnnotateLPAnchors(results []FlipResult, anchors map[int32]LPAnchor) {
+	for i := range results {
+		a, ok := anchors[results[i].TypeID]
+		if !ok {
+			continue
+		}
+		results[i].LPAnchorFloor = a.Floor
+		results[i].LPAnchorCeiling = a.Ceiling
+		switch {
+		case results[i].BuyPrice > 0 && results[i].BuyPrice < a.Floor:
+			results[i].LPSignal = LPSignalBelowFloor
+		case results[i].SellPrice > a.Ceiling:
+			results[i].LPSignal = LPSignalAboveCeiling
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"testing"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/pricing"
+)
+
+func TestBuildLPAnchors(t *testing.T) {
+	offers := []esi.LoyaltyOffer{
+		// 1000 LP + 1M ISK + 2×type 50 for 10 units.
+		{TypeID: 100, Quantity: 10, LPCost: 1000, ISKCost: 1_000_000, RequiredItems: []esi.LoyaltyOfferItem{{TypeID: 50, Quantity: 2}}},
+		// Pricier conversion of the same type is ignored.
+		{TypeID: 100, Quantity: 1, LPCost: 1000, ISKCost: 1_000_000},
+		// Unpriced required item: skipped.
+		{TypeID: 200, Quantity: 1, LPCost: 10, RequiredItems: []esi.LoyaltyOfferItem{{TypeID: 51, Quantity: 1}}},
+	}
+	quotes := map[int32]pricing.Quote{50: {Sell: 500_000}}
+	anchors := BuildLPAnchors(offers, quotes, 1000, 2000)
+
+	a, ok := anchors[100]
+	if !ok {
+		t.Fatal("missing anchor for type 100")
+	}
+	// (1M + 1M items + 1000 LP × rate) / 10
+	if a.Floor != 300_000 || a.Ceiling != 400_000 {
+		t.Fatalf("band = %v..%v, want 300000..400000", a.Floor, a.Ceiling)
+	}
+	if _, ok := anchors[200]; ok {
+		t.Fatal("offer with unpriced inputs should be skipped")
+	}
+
+	results := []FlipResult{
+		{TypeID: 100, BuyPrice: 250_000, SellPrice: 350_000},
+		{TypeID: 100, BuyPrice: 320_000, SellPrice: 450_000},
+		{TypeID: 100, BuyPrice: 320_000, SellPrice: 350_000},
+	}
+	AnnotateLPAnchors(results, anchors)
+	for i, want := range []string{LPSignalBelowFloor, LPSignalAboveCeiling, ""} {
+		if results[i].LPSignal != want {
+			t.Fatalf("results[%d].LPSignal = %q, want %q", i, results[i].LPSignal, want)
+		}
+	}
+}
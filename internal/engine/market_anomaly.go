@@ -0,0 +1,155 @@
+package engine
+
+import (
+	"math"
+	"sort"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/pricing"
+	"eve-flipper/internal/sde"
+)
+
+// Market anomaly defaults.
+const (
+	DefaultAnomalySigma           = 3.0
+	DefaultAnomalyMinDeviationPct = 15.0
+	anomalyHistoryDays            = 90
+	anomalyMinHistoryDays         = 20
+)
+
+// MarketAnomalyParams configures anomaly screening and detection.
+type MarketAnomalyParams struct {
+	Sigma           float64 // standard deviations from the 90-day VWAP
+	MinDeviationPct float64 // and at least this far off, in percent
+}
+
+// MarketAnomalyCandidate is a type whose live price is off CCP's average
+// enough to be worth a history fetch.
+type MarketAnomalyCandidate struct {
+	TypeID        int32
+	AdjustedPrice float64
+	AveragePrice  float64
+	Quote         pricing.Quote
+	DeviationPct  float64 // best sell vs AveragePrice
+}
+
+// MarketAnomaly is one flagged price move.
+type MarketAnomaly struct {
+	TypeID        int32   `json:"type_id"`
+	TypeName      string  `json:"type_name"`
+	Direction     string  `json:"direction"` // spike | crash
+	Price         float64 `json:"price"`     // best sell
+	BestBuy       float64 `json:"best_buy"`
+	VWAP          float64 `json:"vwap"` // 90-day volume-weighted average
+	StdDev        float64 `json:"std_dev"`
+	ZScore        float64 `json:"z_score"`
+	DeviationPct  float64 `json:"deviation_pct"`
+	DRVI          float64 `json:"drvi"` // day-range volatility, %
+	AdjustedPrice float64 `json:"adjusted_price"`
+	AveragePrice  float64 `json:"average_price"`
+	DailyVolume   float64 `json:"daily_volume"` // 90-day average
+	DetectedAt    string  `json:"detected_at"`
+}
+
+// ScreenMarketAnomalies returns up to limit types whose best sell is more
+// than MinDeviationPct off the /markets/prices average, furthest first.
+// Types without a live sell order or a CCP average are skipped.
+func ScreenMarketAnomalies(prices []esi.IndustryPrice, quotes map[int32]pricing.Quote, p MarketAnomalyParams, limit int) []MarketAnomalyCandidate {
+	p = p.withDefaults()
+	out := []MarketAnomalyCandidate{}
+	for _, mp := range prices {
+		q, ok := quotes[mp.TypeID]
+		if !ok || q.Sell <= 0 || mp.AveragePrice <= 0 || IsMarketDisabledTypeID(mp.TypeID) {
+			continue
+		}
+		if !IsExtremePrice(q.Sell, mp.AveragePrice, p.MinDeviationPct) {
+			continue
+		}
+		out = append(out, MarketAnomalyCandidate{
+			TypeID:        mp.TypeID,
+			AdjustedPrice: mp.AdjustedPrice,
+			AveragePrice:  mp.AveragePrice,
+			Quote:         q,
+			DeviationPct:  sanitizeFloat((q.Sell - mp.AveragePrice) / mp.AveragePrice * 100),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := math.Abs(out[i].DeviationPct), math.Abs(out[j].DeviationPct)
+		if a != b {
+			return a > b
+		}
+		return out[i].TypeID < out[j].TypeID
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// DetectMarketAnomaly confirms a candidate against its regional history:
+// the best sell must sit at least Sigma standard deviations (of the daily
+// averages) from the 90-day VWAP, and further off it than both
+// MinDeviationPct and the item's DRVI, so items whose daily range is that
+// erratic anyway are not flagged.
+func DetectMarketAnomaly(data *sde.Data, c MarketAnomalyCandidate, history []esi.HistoryEntry, p MarketAnomalyParams) (MarketAnomaly, bool) {
+	p = p.withDefaults()
+	entries := filterLastNDays(history, anomalyHistoryDays)
+	if len(entries) < anomalyMinHistoryDays || c.Quote.Sell <= 0 {
+		return MarketAnomaly{}, false
+	}
+	vwap := CalcVWAP(history, anomalyHistoryDays)
+	if vwap <= 0 {
+		return MarketAnomaly{}, false
+	}
+	averages := make([]float64, 0, len(entries))
+	var volume int64
+	for _, h := range entries {
+		if h.Average > 0 {
+			averages = append(averages, h.Average)
+		}
+		volume += h.Volume
+	}
+	sd := stdDev(averages)
+	if sd <= 0 {
+		return MarketAnomaly{}, false
+	}
+	z := (c.Quote.Sell - vwap) / sd
+	drvi := CalcDRVI(history, anomalyHistoryDays)
+	if math.Abs(z) < p.Sigma || !IsExtremePrice(c.Quote.Sell, vwap, math.Max(p.MinDeviationPct, drvi)) {
+		return MarketAnomaly{}, false
+	}
+
+	a := MarketAnomaly{
+		TypeID:        c.TypeID,
+		Direction:     "spike",
+		Price:         c.Quote.Sell,
+		BestBuy:       c.Quote.Buy,
+		VWAP:          sanitizeFloat(vwap),
+		StdDev:        sanitizeFloat(sd),
+		ZScore:        sanitizeFloat(math.Round(z*100) / 100),
+		DeviationPct:  sanitizeFloat(math.Round((c.Quote.Sell/vwap-1)*1000) / 10),
+		DRVI:          sanitizeFloat(math.Round(drvi*10) / 10),
+		AdjustedPrice: c.AdjustedPrice,
+		AveragePrice:  c.AveragePrice,
+		DailyVolume:   sanitizeFloat(float64(volume) / anomalyHistoryDays),
+	}
+	if z < 0 {
+		a.Direction = "crash"
+	}
+	if data != nil {
+		if t := data.Types[c.TypeID]; t != nil {
+			a.TypeName = t.Name
+		}
+	}
+	return a, true
+}
+
+func (p MarketAnomalyParams) withDefaults() MarketAnomalyParams {
+	if p.Sigma <= 0 {
+		p.Sigma = DefaultAnomalySigma
+	}
+	if p.MinDeviationPct <= 0 {
+		p.MinDeviationPct = DefaultAnomalyMinDeviationPct
+	}
+	return p
+}
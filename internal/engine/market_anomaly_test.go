@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/pricing"
+	"eve-flipper/internal/sde"
+)
+
+// anomalyHistory returns 60 days alternating around 100 ISK with a tight
+// daily range.
+func anomalyHistory() []esi.HistoryEntry {
+	var h []esi.HistoryEntry
+	today := time.Now().UTC()
+	for i := 60; i >= 1; i-- {
+		avg := 98.0
+		if i%2 == 0 {
+			avg = 102
+		}
+		h = append(h, esi.HistoryEntry{
+			Date:    today.AddDate(0, 0, -i).Format("2006-01-02"),
+			Average: avg, Highest: avg + 1, Lowest: avg - 1, Volume: 1000,
+		})
+	}
+	return h
+}
+
+func TestScreenMarketAnomalies(t *testing.T) {
+	prices := []esi.IndustryPrice{
+		{TypeID: 34, AveragePrice: 100},
+		{TypeID: 35, AveragePrice: 100},
+		{TypeID: 36, AveragePrice: 100},
+		{TypeID: 37, AveragePrice: 0},
+	}
+	quotes := map[int32]pricing.Quote{
+		34: {Sell: 105},
+		35: {Sell: 150},
+		36: {Sell: 40},
+		37: {Sell: 500},
+	}
+	got := ScreenMarketAnomalies(prices, quotes, MarketAnomalyParams{}, 0)
+	if len(got) != 2 || got[0].TypeID != 36 || got[1].TypeID != 35 {
+		t.Fatalf("candidates = %+v", got)
+	}
+	if got[0].DeviationPct != -60 {
+		t.Fatalf("deviation = %v", got[0].DeviationPct)
+	}
+	if got := ScreenMarketAnomalies(prices, quotes, MarketAnomalyParams{}, 1); len(got) != 1 {
+		t.Fatalf("limit ignored: %+v", got)
+	}
+}
+
+func TestDetectMarketAnomaly(t *testing.T) {
+	data := &sde.Data{Types: map[int32]*sde.ItemType{35: {ID: 35, Name: "Pyerite"}}}
+	history := anomalyHistory()
+
+	a, ok := DetectMarketAnomaly(data, MarketAnomalyCandidate{TypeID: 35, Quote: pricing.Quote{Sell: 150, Buy: 140}}, history, MarketAnomalyParams{})
+	if !ok {
+		t.Fatal("spike not flagged")
+	}
+	if a.Direction != "spike" || a.TypeName != "Pyerite" || a.VWAP != 100 || a.DeviationPct != 50 || a.ZScore < 3 {
+		t.Fatalf("anomaly = %+v", a)
+	}
+
+	if a, ok := DetectMarketAnomaly(data, MarketAnomalyCandidate{TypeID: 35, Quote: pricing.Quote{Sell: 60}}, history, MarketAnomalyParams{}); !ok || a.Direction != "crash" {
+		t.Fatalf("crash = %+v, %v", a, ok)
+	}
+
+	// Within normal noise: many σ out but under the minimum deviation.
+	if _, ok := DetectMarketAnomaly(data, MarketAnomalyCandidate{TypeID: 35, Quote: pricing.Quote{Sell: 110}}, history, MarketAnomalyParams{}); ok {
+		t.Fatal("10% move flagged")
+	}
+
+	// An item whose daily range jumps between 0% and 120% is not anomalous
+	// at +50%.
+	volatile := anomalyHistory()
+	for i := range volatile {
+		volatile[i].Highest, volatile[i].Lowest = volatile[i].Average, volatile[i].Average
+		if i%2 == 0 {
+			volatile[i].Highest = volatile[i].Average * 1.6
+			volatile[i].Lowest = volatile[i].Average * 0.4
+		}
+	}
+	if _, ok := DetectMarketAnomaly(data, MarketAnomalyCandidate{TypeID: 35, Quote: pricing.Quote{Sell: 150}}, volatile, MarketAnomalyParams{}); ok {
+		t.Fatal("volatile item flagged")
+	}
+
+	if _, ok := DetectMarketAnomaly(data, MarketAnomalyCandidate{TypeID: 35, Quote: pricing.Quote{Sell: 150}}, history[:10], MarketAnomalyParams{}); ok {
+		t.Fatal("short history flagged")
+	}
+}
@@ -0,0 +1,245 @@
+package engine
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/sde"
+)
+
+// Market coverage flags; a line can carry several.
+const (
+	CoverageFlagMissing      = "missing"               // nothing on sale at the target
+	CoverageFlagUnderstocked = "understocked"          // fewer units listed than the target needs
+	CoverageFlagUnderpriced  = "underpriced_elsewhere" // the reference hub sells well below the target
+)
+
+// Market coverage line statuses, most urgent first.
+const (
+	CoverageStatusMissing      = "missing"
+	CoverageStatusUnderstocked = "understocked"
+	CoverageStatusUnderpriced  = "underpriced_elsewhere"
+	CoverageStatusOK           = "ok"
+)
+
+// coverageDemandDays is the history window weekly demand is averaged over.
+const coverageDemandDays = 28
+
+// MarketCoverageItem is one type the target market should carry. Quantity
+// is the doctrine stock to hold; 0 leaves the target to weekly demand.
+type MarketCoverageItem struct {
+	TypeID   int32
+	Quantity int64
+}
+
+// MarketCoverageParams configures BuildMarketCoverage.
+type MarketCoverageParams struct {
+	CoverDays     float64 // stock to hold in days of target demand
+	MarkupPercent float64 // target prices above reference*(1+markup) are flagged
+}
+
+// MarketCoverageInput is the books and history the report is built from.
+// Orders are sell orders already filtered to the target and reference
+// locations; History is the target region's history per type.
+type MarketCoverageInput struct {
+	Items           []MarketCoverageItem
+	TargetOrders    []esi.MarketOrder
+	ReferenceOrders []esi.MarketOrder
+	History         map[int32][]esi.HistoryEntry
+}
+
+// MarketCoverageLine is the coverage of one type at the target.
+type MarketCoverageLine struct {
+	TypeID          int32    `json:"type_id"`
+	TypeName        string   `json:"type_name"`
+	Volume          float64  `json:"volume"` // m³ per unit
+	DoctrineQty     int64    `json:"doctrine_qty"`
+	WeeklyDemand    float64  `json:"weekly_demand"` // target region units per 7 days
+	TargetStock     int64    `json:"target_stock"`
+	TargetPrice     float64  `json:"target_price"` // lowest sell, 0 when missing
+	ReferencePrice  float64  `json:"reference_price"`
+	ReferenceStock  int64    `json:"reference_stock"`
+	MarkupPercent   float64  `json:"markup_percent"` // target over reference; 0 when either is missing
+	Flags           []string `json:"flags"`
+	Status          string   `json:"status"`
+	RestockUnits    int64    `json:"restock_units"`
+	RestockUnitCost float64  `json:"restock_unit_cost"` // average over the units bought at the reference
+	RestockCost     float64  `json:"restock_cost"`
+	HaulVolume      float64  `json:"haul_volume"` // m³
+}
+
+// MarketCoverageManifestItem is one purchase at the reference hub.
+type MarketCoverageManifestItem struct {
+	TypeID    int32   `json:"type_id"`
+	TypeName  string  `json:"type_name"`
+	Units     int64   `json:"units"`
+	UnitPrice float64 `json:"unit_price"`
+	Cost      float64 `json:"cost"`
+	Volume    float64 `json:"volume"` // m³ for all units
+}
+
+// MarketCoverageReport is the coverage of a target market against a
+// doctrine list or reference hub, plus the restock manifest to haul.
+type MarketCoverageReport struct {
+	Lines        []MarketCoverageLine         `json:"lines"`
+	Manifest     []MarketCoverageManifestItem `json:"manifest"`
+	Missing      int                          `json:"missing"`
+	Understocked int                          `json:"understocked"`
+	Underpriced  int                          `json:"underpriced_elsewhere"`
+	ManifestCost float64                      `json:"manifest_cost"`
+	HaulVolume   float64                      `json:"haul_volume"` // m³
+}
+
+// BuildMarketCoverage flags items missing from the target market, listed
+// in fewer units than max(doctrine quantity, CoverDays of demand), or
+// priced more than MarkupPercent above the reference hub, and sizes a
+// restock bought off the reference sell book. Lines are ordered most
+// urgent first; the manifest by haul volume.
+func BuildMarketCoverage(data *sde.Data, in MarketCoverageInput, p MarketCoverageParams, now time.Time) MarketCoverageReport {
+	if p.CoverDays <= 0 {
+		p.CoverDays = 7
+	}
+	target := groupSellOrders(in.TargetOrders)
+	reference := groupSellOrders(in.ReferenceOrders)
+	cutoff := now.UTC().AddDate(0, 0, -coverageDemandDays).Format("2006-01-02")
+
+	report := MarketCoverageReport{Lines: []MarketCoverageLine{}, Manifest: []MarketCoverageManifestItem{}}
+	for _, it := range in.Items {
+		line := MarketCoverageLine{TypeID: it.TypeID, DoctrineQty: max(it.Quantity, 0), Flags: []string{}, Status: CoverageStatusOK}
+		if data != nil {
+			if t := data.Types[it.TypeID]; t != nil {
+				line.TypeName = t.Name
+				line.Volume = t.Volume
+			}
+		}
+		var sold int64
+		for _, h := range in.History[it.TypeID] {
+			if h.Date >= cutoff {
+				sold += h.Volume
+			}
+		}
+		daily := float64(sold) / coverageDemandDays
+		line.WeeklyDemand = sanitizeFloat(math.Round(daily*7*10) / 10)
+
+		tgt := target[it.TypeID]
+		for _, o := range tgt {
+			line.TargetStock += int64(o.VolumeRemain)
+		}
+		if len(tgt) > 0 {
+			line.TargetPrice = tgt[0].Price
+		}
+		ref := reference[it.TypeID]
+		for _, o := range ref {
+			line.ReferenceStock += int64(o.VolumeRemain)
+		}
+		if len(ref) > 0 {
+			line.ReferencePrice = ref[0].Price
+		}
+
+		need := max(line.DoctrineQty, int64(math.Ceil(daily*p.CoverDays)))
+		if line.TargetStock <= 0 {
+			line.Flags = append(line.Flags, CoverageFlagMissing)
+			line.Status = CoverageStatusMissing
+			report.Missing++
+		} else if line.TargetStock < need {
+			line.Flags = append(line.Flags, CoverageFlagUnderstocked)
+			line.Status = CoverageStatusUnderstocked
+			report.Understocked++
+		}
+		if line.TargetPrice > 0 && line.ReferencePrice > 0 {
+			line.MarkupPercent = sanitizeFloat(math.Round((line.TargetPrice/line.ReferencePrice-1)*1000) / 10)
+			if line.TargetPrice > line.ReferencePrice*(1+p.MarkupPercent/100) {
+				line.Flags = append(line.Flags, CoverageFlagUnderpriced)
+				if line.Status == CoverageStatusOK {
+					line.Status = CoverageStatusUnderpriced
+				}
+				report.Underpriced++
+			}
+		}
+
+		if units := need - line.TargetStock; units > 0 && len(ref) > 0 {
+			bought, cost := walkSellBook(ref, units)
+			if bought > 0 {
+				line.RestockUnits = bought
+				line.RestockCost = sanitizeFloat(cost)
+				line.RestockUnitCost = sanitizeFloat(cost / float64(bought))
+				line.HaulVolume = sanitizeFloat(line.Volume * float64(bought))
+				report.ManifestCost += line.RestockCost
+				report.HaulVolume += line.HaulVolume
+				report.Manifest = append(report.Manifest, MarketCoverageManifestItem{
+					TypeID:    line.TypeID,
+					TypeName:  line.TypeName,
+					Units:     bought,
+					UnitPrice: line.RestockUnitCost,
+					Cost:      line.RestockCost,
+					Volume:    line.HaulVolume,
+				})
+			}
+		}
+		report.Lines = append(report.Lines, line)
+	}
+
+	sort.SliceStable(report.Lines, func(i, j int) bool {
+		a, b := report.Lines[i], report.Lines[j]
+		if ra, rb := coverageUrgency(a.Status), coverageUrgency(b.Status); ra != rb {
+			return ra < rb
+		}
+		if a.WeeklyDemand != b.WeeklyDemand {
+			return a.WeeklyDemand > b.WeeklyDemand
+		}
+		return a.TypeID < b.TypeID
+	})
+	sort.SliceStable(report.Manifest, func(i, j int) bool {
+		a, b := report.Manifest[i], report.Manifest[j]
+		if a.Volume != b.Volume {
+			return a.Volume > b.Volume
+		}
+		return a.TypeID < b.TypeID
+	})
+	report.ManifestCost = sanitizeFloat(report.ManifestCost)
+	report.HaulVolume = sanitizeFloat(report.HaulVolume)
+	return report
+}
+
+func coverageUrgency(status string) int {
+	switch status {
+	case CoverageStatusMissing:
+		return 0
+	case CoverageStatusUnderstocked:
+		return 1
+	case CoverageStatusUnderpriced:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// groupSellOrders buckets live sell orders by type, cheapest first.
+func groupSellOrders(orders []esi.MarketOrder) map[int32][]esi.MarketOrder {
+	byType := make(map[int32][]esi.MarketOrder)
+	for _, o := range orders {
+		if o.IsBuyOrder || o.VolumeRemain <= 0 || o.Price <= 0 {
+			continue
+		}
+		byType[o.TypeID] = append(byType[o.TypeID], o)
+	}
+	for _, list := range byType {
+		sort.Slice(list, func(i, j int) bool { return list[i].Price < list[j].Price })
+	}
+	return byType
+}
+
+// walkSellBook buys up to units off a cheapest-first sell book.
+func walkSellBook(book []esi.MarketOrder, units int64) (bought int64, cost float64) {
+	for _, o := range book {
+		if bought >= units {
+			break
+		}
+		take := min(int64(o.VolumeRemain), units-bought)
+		bought += take
+		cost += float64(take) * o.Price
+	}
+	return bought, cost
+}
@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/sde"
+)
+
+func TestBuildMarketCoverage(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	data := &sde.Data{Types: map[int32]*sde.ItemType{
+		2048: {ID: 2048, Name: "Damage Control II", Volume: 5},
+		3841: {ID: 3841, Name: "Large Shield Extender II", Volume: 10},
+		2281: {ID: 2281, Name: "Adaptive Invulnerability Field II", Volume: 5},
+	}}
+	in := MarketCoverageInput{
+		Items: []MarketCoverageItem{
+			{TypeID: 2048, Quantity: 20}, // missing at the target
+			{TypeID: 3841},               // listed below weekly demand
+			{TypeID: 2281, Quantity: 5},  // stocked but marked up
+		},
+		TargetOrders: []esi.MarketOrder{
+			{TypeID: 3841, Price: 2000, VolumeRemain: 10},
+			{TypeID: 2281, Price: 1500, VolumeRemain: 8},
+			{TypeID: 2281, Price: 900, VolumeRemain: 10, IsBuyOrder: true},
+		},
+		ReferenceOrders: []esi.MarketOrder{
+			{TypeID: 2048, Price: 110, VolumeRemain: 100},
+			{TypeID: 2048, Price: 100, VolumeRemain: 15},
+			{TypeID: 3841, Price: 1800, VolumeRemain: 500},
+			{TypeID: 2281, Price: 1000, VolumeRemain: 50},
+		},
+		History: map[int32][]esi.HistoryEntry{
+			3841: {
+				{Date: "2026-06-20", Volume: 60},
+				{Date: "2026-06-10", Volume: 52},
+				{Date: "2026-04-01", Volume: 9999}, // outside the demand window
+			},
+		},
+	}
+
+	report := BuildMarketCoverage(data, in, MarketCoverageParams{CoverDays: 14, MarkupPercent: 25}, now)
+	if len(report.Lines) != 3 || report.Missing != 1 || report.Understocked != 1 || report.Underpriced != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	missing, under, marked := report.Lines[0], report.Lines[1], report.Lines[2]
+
+	// 20 doctrine units walk the reference book: 15 at 100, 5 at 110.
+	if missing.TypeID != 2048 || missing.Status != CoverageStatusMissing || missing.RestockUnits != 20 ||
+		missing.RestockCost != 2050 || missing.HaulVolume != 100 {
+		t.Fatalf("missing = %+v", missing)
+	}
+	// 112 units over 28 days is 4/day: 28 per week, 56 for 14 days less 10 listed.
+	if under.TypeID != 3841 || under.Status != CoverageStatusUnderstocked || under.WeeklyDemand != 28 || under.RestockUnits != 46 {
+		t.Fatalf("understocked = %+v", under)
+	}
+	if len(under.Flags) != 1 || under.MarkupPercent != 11.1 {
+		t.Fatalf("a markup inside the threshold is flagged: %+v", under)
+	}
+	if marked.TypeID != 2281 || marked.Status != CoverageStatusUnderpriced || marked.MarkupPercent != 50 || marked.RestockUnits != 0 {
+		t.Fatalf("marked up = %+v", marked)
+	}
+
+	if len(report.Manifest) != 2 || report.Manifest[0].TypeID != 3841 {
+		t.Fatalf("manifest = %+v", report.Manifest)
+	}
+	if math.Abs(report.HaulVolume-560) > 1e-9 || math.Abs(report.ManifestCost-(2050+46*1800)) > 1e-9 {
+		t.Fatalf("totals = %v m³, %v ISK", report.HaulVolume, report.ManifestCost)
+	}
+}
@@ -1,5 +1,11 @@
 package engine
 
+import (
+	"time"
+
+	"eve-flipper/internal/graph"
+)
+
 // FlipResult represents a single profitable flip opportunity (buy low at one station, sell high at another).
 type FlipResult struct {
 	TypeID          int32
@@ -63,6 +69,7 @@ type FlipResult struct {
 	ExecutionQuote        *ExecutionQuote `json:"ExecutionQuote,omitempty"` // unified execution snapshot for downstream UX/API
 	SlippageBuyPct        float64         `json:"SlippageBuyPct,omitempty"`
 	SlippageSellPct       float64         `json:"SlippageSellPct,omitempty"`
+	SlippagePct           float64         `json:"SlippagePct,omitempty"`           // SlippageBuyPct + SlippageSellPct
 	FillTimeDays          float64         `json:"FillTimeDays,omitempty"`          // estimated days to complete the full cycle
 	LiquidityScore        float64         `json:"LiquidityScore,omitempty"`        // 0-100 score from fill time and history confidence
 	LiquidityLabel        string          `json:"LiquidityLabel,omitempty"`        // high | medium | low | thin | unknown
@@ -72,10 +79,56 @@ type FlipResult struct {
 	CharacterAssets       int64           `json:"CharacterAssets,omitempty"`       // owned asset units for this type in selected scope
 	CharacterBuyOrders    int64           `json:"CharacterBuyOrders,omitempty"`    // active buy-order units for this type in selected scope
 	CharacterSellOrders   int64           `json:"CharacterSellOrders,omitempty"`   // active sell-order units for this type in selected scope
-	RouteSafetyMultiplier float64         `json:"RouteSafetyMultiplier,omitempty"` // backtest route-time safety multiplier from gank risk
+	RouteSafetyMultiplier float64         `json:"RouteSafetyMultiplier,omitempty"` // buy→sell route safety multiplier from gank risk
 	RouteSafetyDanger     string          `json:"RouteSafetyDanger,omitempty"`     // green | yellow | red
 	RouteSafetyKills      int             `json:"RouteSafetyKills,omitempty"`
 	RouteSafetyISK        float64         `json:"RouteSafetyISK,omitempty"`
+	// Per-leg fill estimates: days to buy UnitsToBuy at the source and to sell
+	// them at the destination. Book depth fills instantly; the rest waits on
+	// the 7-day average flow (BfSPerDay / S2BPerDay).
+	BuyFillDays  float64 `json:"BuyFillDays,omitempty"`
+	SellFillDays float64 `json:"SellFillDays,omitempty"`
+	// Hauling logistics (see ApplyCargoLogistics): m³ to move, trips in the
+	// scan's cargo hold and free space left on the last trip.
+	CargoM3         float64 `json:"CargoM3,omitempty"`
+	CargoTrips      int     `json:"CargoTrips,omitempty"`
+	CargoLeftoverM3 float64 `json:"CargoLeftoverM3,omitempty"`
+	// Jita 4-4 reference prices (see AnnotateHubReference).
+	JitaSellPrice float64 `json:"JitaSellPrice,omitempty"`
+	JitaBuyPrice  float64 `json:"JitaBuyPrice,omitempty"`
+	BuyVsJitaPct  float64 `json:"BuyVsJitaPct,omitempty"`  // BuyPrice as % of Jita sell
+	SellVsJitaPct float64 `json:"SellVsJitaPct,omitempty"` // SellPrice as % of Jita buy
+	// User's reference "sell at" station (see AnnotateReferenceStation).
+	ReferenceStationID int64   `json:"ReferenceStationID,omitempty"`
+	ReferenceSellPrice float64 `json:"ReferenceSellPrice,omitempty"`
+	ReferenceDelta     float64 `json:"ReferenceDelta"` // SellPrice vs reference sell, %
+	// LP-store price band for faction warfare LP items (see AnnotateLPAnchors).
+	LPAnchorFloor   float64 `json:"LPAnchorFloor,omitempty"`
+	LPAnchorCeiling float64 `json:"LPAnchorCeiling,omitempty"`
+	LPSignal        string  `json:"LPSignal,omitempty"` // below_floor | above_ceiling
+	// Jump-drive hauling (set when ScanParams.JumpRangeLY > 0).
+	JumpCount          int     `json:"JumpCount,omitempty"`          // jump-drive activations buy→sell
+	JumpLY             float64 `json:"JumpLY,omitempty"`             // total light years jumped
+	JumpFuelISK        float64 `json:"JumpFuelISK,omitempty"`        // isotope cost, already deducted from profit
+	JumpWaitMinutes    float64 `json:"JumpWaitMinutes,omitempty"`    // reactivation waits along the chain
+	JumpFatigueMinutes float64 `json:"JumpFatigueMinutes,omitempty"` // jump fatigue on arrival
+	// Access fees of player structures on either leg, already deducted from profit.
+	StructureAccessFee float64 `json:"StructureAccessFee,omitempty"`
+	// Courier contract pricing (set when ScanParams freight rates are > 0).
+	FreightCollateral     float64 `json:"FreightCollateral,omitempty"`     // buy cost of the units, used as contract collateral
+	FreightCost           float64 `json:"FreightCost,omitempty"`           // courier reward: m3 × jumps × rate + collateral %
+	NetProfitAfterFreight float64 `json:"NetProfitAfterFreight,omitempty"` // profit if the haul is contracted out
+	// Travel time to buy and haul every trip, and profit per hour of it
+	// (see ApplyFlipISKPerHour).
+	ExecutionMinutes float64 `json:"ExecutionMinutes,omitempty"`
+	ProfitPerHour    float64 `json:"ProfitPerHour,omitempty"`
+	// Gank exposure (see ApplyFlipGankThresholds): ISK carried per load
+	// against the ship class threshold, and loads needed to stay under it.
+	GankLoadValueISK float64 `json:"GankLoadValueISK,omitempty"`
+	GankThresholdISK float64 `json:"GankThresholdISK,omitempty"`
+	GankRisk         bool    `json:"GankRisk,omitempty"`
+	GankSafeTrips    int     `json:"GankSafeTrips,omitempty"`
+	GankSplit        bool    `json:"GankSplit,omitempty"` // CargoTrips raised to GankSafeTrips
 
 	// Regional day-trader enrichments (EVE Guru-style grouped region view).
 	DaySecurity           float64   `json:"DaySecurity,omitempty"`
@@ -136,6 +189,14 @@ type ContractResult struct {
 	LiquidationJumps      int // jumps from pickup system to liquidation system (instant mode)
 	Jumps                 int
 	ProfitPerJump         float64
+	ExecutionMinutes      float64 `json:"ExecutionMinutes,omitempty"` // fly to pickup, haul to liquidation
+	ProfitPerHour         float64 `json:"ProfitPerHour,omitempty"`
+	// User's reference "sell at" station (see AnnotateContractReference).
+	ReferenceStationID int64   `json:"ReferenceStationID,omitempty"`
+	ReferenceValue     float64 `json:"ReferenceValue,omitempty"` // included items at the reference station's lowest sell
+	ReferenceDelta     float64 `json:"ReferenceDelta"`           // MarketValue vs ReferenceValue, %
+
+	includedItems map[int32]int32 // included quantity per type
 }
 
 // RouteHop represents a single buy-haul-sell leg within a multi-hop trade route.
@@ -160,6 +221,7 @@ type RouteHop struct {
 	VolumeM3         float64 `json:"VolumeM3,omitempty"`
 	CargoM3          float64 `json:"CargoM3,omitempty"`
 	CargoTrips       int     `json:"CargoTrips,omitempty"`
+	CargoLeftoverM3  float64 `json:"CargoLeftoverM3,omitempty"` // free hold space on the hop's last trip
 	ExecutionMinutes float64 `json:"ExecutionMinutes,omitempty"`
 	ProfitPerHour    float64 `json:"ProfitPerHour,omitempty"`
 	DailyVolume      int64   `json:"DailyVolume,omitempty"`
@@ -197,6 +259,10 @@ type RouteResult struct {
 	CourierProfitAfterRewardISK float64 `json:"CourierProfitAfterRewardISK,omitempty"`
 	CourierRiskPremiumPercent   float64 `json:"CourierRiskPremiumPercent,omitempty"`
 	CourierViable               bool    `json:"CourierViable,omitempty"`
+	GankLoadValueISK            float64 `json:"GankLoadValueISK,omitempty"`
+	GankThresholdISK            float64 `json:"GankThresholdISK,omitempty"`
+	GankRisk                    bool    `json:"GankRisk,omitempty"`
+	GankSafeTrips               int     `json:"GankSafeTrips,omitempty"`
 }
 
 // RouteParams holds the input parameters for multi-hop route search.
@@ -212,11 +278,16 @@ type RouteParams struct {
 	RouteMinutesPerJump     float64
 	RouteDockMinutes        float64
 	RouteSafetyDelayPercent float64
-	RouteMode               string
-	MinMargin               float64
-	MinISKPerJump           float64
-	SalesTaxPercent         float64
-	BrokerFeePercent        float64
+	// RouteAlignSeconds / RouteWarpSpeedAU derive the per-jump and dock
+	// minutes from warp time when those are not set (see ShipTravel).
+	RouteAlignSeconds float64
+	RouteWarpSpeedAU  float64
+	RouteMode         string
+	MinMargin         float64
+	MinISKPerJump     float64
+	MaxBudget         float64 // ISK available per hop purchase; 0 = unlimited
+	SalesTaxPercent   float64
+	BrokerFeePercent  float64
 	// SplitTradeFees enables side-specific fee model.
 	// When false, legacy fields above are used.
 	SplitTradeFees       bool
@@ -229,6 +300,40 @@ type RouteParams struct {
 	MinRouteSecurity     float64 // 0 = all space; 0.45 = highsec only; 0.7 = min 0.7
 	AllowEmptyHops       bool    // allow empty travel legs between trade hops
 	IncludeStructures    bool    // true = allow Upwell structure orders; false = NPC stations only
+	// BrokerFees is the character's broker fee schedule (see BrokerFeeSchedule).
+	BrokerFees *BrokerFeeSchedule
+	// UseWormholes adds live Thera/Turnur connections to the jump graph.
+	UseWormholes bool
+	// UseAnsiblex adds AnsiblexGates (jump bridges) to the jump graph.
+	UseAnsiblex   bool
+	AnsiblexGates []graph.Edge
+	// RoutePreferences are the user's avoided systems and pinned paths,
+	// applied to every path search.
+	RoutePreferences graph.RoutePreferences
+}
+
+// ansiblexEdges returns the jump bridges to route through, if enabled.
+func (p RouteParams) ansiblexEdges() []graph.Edge {
+	if !p.UseAnsiblex {
+		return nil
+	}
+	return p.AnsiblexGates
+}
+
+func (p ScanParams) ansiblexEdges() []graph.Edge {
+	if !p.UseAnsiblex {
+		return nil
+	}
+	return p.AnsiblexGates
+}
+
+// capitalLimit is the tighter of MaxInvestment and MaxBudget (0 = no limit).
+func (p ScanParams) capitalLimit() float64 {
+	limit := p.MaxInvestment
+	if p.MaxBudget > 0 && (limit <= 0 || p.MaxBudget < limit) {
+		limit = p.MaxBudget
+	}
+	return limit
 }
 
 // ScanParams holds the input parameters for radius and region scans.
@@ -248,9 +353,14 @@ type ScanParams struct {
 	SellBrokerFeePercent float64
 	BuySalesTaxPercent   float64
 	SellSalesTaxPercent  float64
+	// BrokerFees is the character's broker fee schedule (see BrokerFeeSchedule).
+	BrokerFees *BrokerFeeSchedule
 	// Advanced filters
-	MinDailyVolume  int64   // 0 = no filter
-	MaxInvestment   float64 // 0 = no filter (max ISK per position)
+	MinDailyVolume int64   // 0 = no filter
+	MaxInvestment  float64 // 0 = no filter (max ISK per position)
+	// MaxBudget is the ISK actually available (e.g. live wallet balance);
+	// UnitsToBuy is capped to what it can afford. 0 = unlimited.
+	MaxBudget       float64
 	MinItemProfit   float64 // 0 = no filter (min ISK profit per position for regional day trader)
 	MinPeriodROI    float64 // 0 = no filter (min period ROI % for regional day trader)
 	MaxDOS          float64 // 0 = no filter (max days-of-supply at target for regional day trader)
@@ -262,6 +372,7 @@ type ScanParams struct {
 	MinBfSPerDay       float64 // 0 = no filter
 	MinS2BBfSRatio     float64 // 0 = no filter
 	MaxS2BBfSRatio     float64 // 0 = no filter
+	MaxFillDays        float64 // 0 = no filter (max of buy/sell leg fill days)
 	AvgPricePeriod     int     // 0 = default period (14 days for regional day trader)
 	// Heuristic hauling cost model: ISK per (m3 * jump) used by regional day trader scoring.
 	ShippingCostPerM3Jump float64 // 0 = disabled
@@ -283,14 +394,62 @@ type ScanParams struct {
 	// instead of TargetBuyOrderPrice (highest bid). Reflects listing a sell order
 	// rather than instantly hitting a buy order. Higher profit, higher risk.
 	SellOrderMode bool
+	// NaivePricing prices flips at the best bid/ask only, skipping the
+	// order-book walk for UnitsToBuy. Faster, but ignores slippage.
+	NaivePricing bool
 	// RegionalDiagnosticMode returns regional-day rows rejected by filters with
 	// reason/status metadata. It is capped and not intended as recommendations.
 	RegionalDiagnosticMode bool
 	// IncludeStructures keeps Upwell structure orders in scope.
 	IncludeStructures bool
+	// UseWormholes adds live Thera/Turnur connections to the jump graph.
+	UseWormholes bool
+	// UseAnsiblex adds AnsiblexGates (jump bridges) to the jump graph.
+	UseAnsiblex   bool
+	AnsiblexGates []graph.Edge
+	// RoutePreferences are the user's avoided systems and pinned paths,
+	// applied to every path search.
+	RoutePreferences graph.RoutePreferences
+	// --- Jump-drive hauling (capital haulers) ---
+	// JumpRangeLY > 0 routes buy→sell by jump drive and deducts isotope fuel
+	// (JumpIsotopesPerLY × JumpIsotopePrice per LY) from profit.
+	JumpRangeLY          float64
+	JumpFatigueReduction float64 // 0..1; <=0 = jump freighter default (0.9)
+	JumpIsotopesPerLY    float64 // <=0 = default
+	JumpIsotopePrice     float64 // ISK per isotope unit
+	// --- Courier freight (Red Frog-style contract pricing) ---
+	// Annotates results with FreightCost / NetProfitAfterFreight; profit
+	// itself is not reduced. Both 0 = disabled.
+	FreightISKPerM3Jump      float64 // reward per m3 per jump buy→sell
+	FreightCollateralPercent float64 // reward as % of collateral (buy cost)
+	// --- Travel time (ISK/hour) ---
+	// ShipClass picks align time and warp speed (see ShipTravelFor);
+	// positive ShipAlignSeconds / ShipWarpSpeedAU override the class.
+	ShipClass        string
+	ShipAlignSeconds float64
+	ShipWarpSpeedAU  float64
+	// ShipEHP is the hauler's tank for gank thresholds; 0 = class default.
+	ShipEHP float64
+	// GankSplitTrips raises the trips of flips carrying more ISK per load
+	// than the ship class gank threshold (see ApplyFlipGankThresholds).
+	GankSplitTrips bool
+	// StructureAccessFees is the ISK a player structure charges per visit,
+	// by structure ID; deducted from the profit of results buying or selling
+	// there.
+	StructureAccessFees map[int64]float64
 	// AccessToken is used for authenticated structure-market reads.
 	// Runtime-only: must never be persisted.
 	AccessToken string
+	// --- Region fetch limits ---
+	// FetchTimeout bounds the order fetch stage; regions still loading when
+	// it expires count as failed. 0 = no timeout.
+	FetchTimeout time.Duration
+	// MaxRegionFailures is the number of failed region fetches tolerated
+	// before the scan errors out. nil = no limit.
+	MaxRegionFailures *int
+	// FetchReport, when set, collects failed region fetches so callers can
+	// flag results as partial. Runtime-only.
+	FetchReport *RegionFetchReport
 
 	// --- Contract-specific filters ---
 	MinContractPrice           float64 // Minimum contract price in ISK (0 = use default 10M)
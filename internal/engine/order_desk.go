@@ -22,6 +22,7 @@ type OrderDeskOptions struct {
 	BrokerFeePercent float64
 	TargetETADays    float64
 	WarnExpiryDays   int
+	RepriceStrategy  string // penny (default) | tick | smart
 }
 
 // OrderDeskSettings are echoed in the response.
@@ -30,6 +31,7 @@ type OrderDeskSettings struct {
 	BrokerFeePercent float64 `json:"broker_fee_percent"`
 	TargetETADays    float64 `json:"target_eta_days"`
 	WarnExpiryDays   int     `json:"warn_expiry_days"`
+	RepriceStrategy  string  `json:"reprice_strategy"`
 }
 
 // OrderDeskSummary aggregates order health for quick triage.
@@ -44,6 +46,8 @@ type OrderDeskSummary struct {
 	AvgETADays      float64 `json:"avg_eta_days"`
 	WorstETADays    float64 `json:"worst_eta_days"`
 	UnknownETACount int     `json:"unknown_eta_count"`
+	MaxOrders       int     `json:"max_orders,omitempty"` // 0 = character skills unknown
+	FreeOrderSlots  int     `json:"free_order_slots"`
 }
 
 // OrderDeskOrder is one actionable row in the execution desk.
@@ -66,6 +70,8 @@ type OrderDeskOrder struct {
 	BookAvailable       bool    `json:"book_available"`
 	BestPrice           float64 `json:"best_price"`
 	SuggestedPrice      float64 `json:"suggested_price"`
+	SuggestedQueueAhead int64   `json:"suggested_queue_ahead"`
+	SuggestedETADays    float64 `json:"suggested_eta_days"` // -1 = unknown
 	UndercutAmount      float64 `json:"undercut_amount"`
 	UndercutPct         float64 `json:"undercut_pct"`
 	QueueAheadQty       int64   `json:"queue_ahead_qty"`
@@ -82,9 +88,21 @@ type OrderDeskOrder struct {
 
 // OrderDeskResponse is the full API payload for the order desk tab.
 type OrderDeskResponse struct {
-	Summary  OrderDeskSummary  `json:"summary"`
-	Orders   []OrderDeskOrder  `json:"orders"`
-	Settings OrderDeskSettings `json:"settings"`
+	Summary       OrderDeskSummary  `json:"summary"`
+	Orders        []OrderDeskOrder  `json:"orders"`
+	Settings      OrderDeskSettings `json:"settings"`
+	TradingLimits []TradingLimits   `json:"trading_limits,omitempty"`
+}
+
+// SetTradingLimits attaches per-character order slot limits and totals them
+// into the summary.
+func (r *OrderDeskResponse) SetTradingLimits(limits []TradingLimits) {
+	r.TradingLimits = limits
+	r.Summary.MaxOrders, r.Summary.FreeOrderSlots = 0, 0
+	for _, l := range limits {
+		r.Summary.MaxOrders += l.MaxOrders
+		r.Summary.FreeOrderSlots += l.FreeSlots
+	}
 }
 
 func normalizeOrderDeskOptions(opt OrderDeskOptions) OrderDeskOptions {
@@ -106,6 +124,7 @@ func normalizeOrderDeskOptions(opt OrderDeskOptions) OrderDeskOptions {
 	if opt.WarnExpiryDays <= 0 {
 		opt.WarnExpiryDays = 2
 	}
+	opt.RepriceStrategy = NormalizeRepriceStrategy(opt.RepriceStrategy)
 	return opt
 }
 
@@ -127,6 +146,7 @@ func ComputeOrderDesk(
 			BrokerFeePercent: opt.BrokerFeePercent,
 			TargetETADays:    opt.TargetETADays,
 			WarnExpiryDays:   opt.WarnExpiryDays,
+			RepriceStrategy:  opt.RepriceStrategy,
 		},
 	}
 	if len(playerOrders) == 0 {
@@ -150,23 +170,24 @@ func ComputeOrderDesk(
 
 	for _, po := range playerOrders {
 		row := OrderDeskOrder{
-			OrderID:        po.OrderID,
-			TypeID:         po.TypeID,
-			TypeName:       po.TypeName,
-			LocationID:     po.LocationID,
-			LocationName:   po.LocationName,
-			RegionID:       po.RegionID,
-			IsBuyOrder:     po.IsBuyOrder,
-			Price:          po.Price,
-			VolumeRemain:   po.VolumeRemain,
-			VolumeTotal:    po.VolumeTotal,
-			Notional:       po.Price * float64(po.VolumeRemain),
-			IssuedAt:       po.Issued,
-			DaysToExpire:   -1,
-			ETADays:        -1,
-			BookAvailable:  true,
-			Recommendation: "hold",
-			Reason:         "on track",
+			OrderID:          po.OrderID,
+			TypeID:           po.TypeID,
+			TypeName:         po.TypeName,
+			LocationID:       po.LocationID,
+			LocationName:     po.LocationName,
+			RegionID:         po.RegionID,
+			IsBuyOrder:       po.IsBuyOrder,
+			Price:            po.Price,
+			VolumeRemain:     po.VolumeRemain,
+			VolumeTotal:      po.VolumeTotal,
+			Notional:         po.Price * float64(po.VolumeRemain),
+			IssuedAt:         po.Issued,
+			DaysToExpire:     -1,
+			ETADays:          -1,
+			SuggestedETADays: -1,
+			BookAvailable:    true,
+			Recommendation:   "hold",
+			Reason:           "on track",
 		}
 
 		if po.IsBuyOrder {
@@ -189,6 +210,7 @@ func ComputeOrderDesk(
 		}
 
 		hk := NewOrderDeskHistoryKey(po.RegionID, po.TypeID)
+		var sortedBook []esi.MarketOrder
 		if unavailableBooks != nil && unavailableBooks[hk] {
 			row.BookAvailable = false
 			row.Position = 0
@@ -217,6 +239,7 @@ func ComputeOrderDesk(
 					})
 				}
 
+				sortedBook = sorted
 				row.BestPrice = sorted[0].Price
 				for _, o := range sorted {
 					if o.Price != row.BestPrice {
@@ -290,6 +313,7 @@ func ComputeOrderDesk(
 			row.ETADays = (float64(row.QueueAheadQty) + float64(row.VolumeRemain)) / row.EstimatedFillPerDay
 			etaKnown = append(etaKnown, row.ETADays)
 		}
+		applyRepriceStrategy(&row, sortedBook, po.OrderID, opt)
 
 		row.Recommendation, row.Reason = orderDeskRecommendation(row, opt)
 		out.Orders = append(out.Orders, row)
@@ -347,6 +371,31 @@ func ComputeOrderDesk(
 	return out
 }
 
+// applyRepriceStrategy replaces the penny suggestion of an order behind
+// the top of the book per opt.RepriceStrategy and fills in the suggested
+// price's queue and ETA.
+func applyRepriceStrategy(row *OrderDeskOrder, sortedBook []esi.MarketOrder, orderID int64, opt OrderDeskOptions) {
+	if len(sortedBook) == 0 {
+		return
+	}
+	if row.Position == 1 {
+		row.SuggestedETADays = row.ETADays
+		return
+	}
+	switch opt.RepriceStrategy {
+	case RepriceStrategyTick:
+		row.SuggestedPrice = TickBeat(row.BestPrice, row.IsBuyOrder)
+	case RepriceStrategySmart:
+		levels := bookLevels(sortedBook, orderID)
+		row.SuggestedPrice, row.SuggestedQueueAhead, row.SuggestedETADays = smartRepricePrice(
+			levels, row.Price, row.IsBuyOrder, row.VolumeRemain, row.EstimatedFillPerDay, opt.TargetETADays)
+		return
+	}
+	if row.EstimatedFillPerDay > 0 {
+		row.SuggestedETADays = float64(row.VolumeRemain) / row.EstimatedFillPerDay
+	}
+}
+
 func orderDeskBetterPrice(isBuy bool, a, b float64) bool {
 	if isBuy {
 		return a > b
@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RepriceAction is one order to modify in game.
+type RepriceAction struct {
+	OrderID      int64   `json:"order_id"`
+	TypeID       int32   `json:"type_id"`
+	TypeName     string  `json:"type_name"`
+	IsBuyOrder   bool    `json:"is_buy_order"`
+	VolumeRemain int32   `json:"volume_remain"`
+	OldPrice     float64 `json:"old_price"`
+	NewPrice     float64 `json:"new_price"`
+}
+
+// RepriceStation groups the reprice actions at one station, so a repricing
+// session can be done station by station.
+type RepriceStation struct {
+	LocationID   int64           `json:"location_id"`
+	LocationName string          `json:"location_name"`
+	Actions      []RepriceAction `json:"actions"`
+}
+
+// BuildRepriceActions collects the reprice recommendations of an order desk
+// grouped by station: stations with the most actions first, actions by item
+// name.
+func BuildRepriceActions(orders []OrderDeskOrder) []RepriceStation {
+	byLoc := make(map[int64]*RepriceStation)
+	for _, o := range orders {
+		if o.Recommendation != "reprice" || o.SuggestedPrice <= 0 {
+			continue
+		}
+		st := byLoc[o.LocationID]
+		if st == nil {
+			st = &RepriceStation{LocationID: o.LocationID, LocationName: o.LocationName}
+			byLoc[o.LocationID] = st
+		}
+		st.Actions = append(st.Actions, RepriceAction{
+			OrderID:      o.OrderID,
+			TypeID:       o.TypeID,
+			TypeName:     o.TypeName,
+			IsBuyOrder:   o.IsBuyOrder,
+			VolumeRemain: o.VolumeRemain,
+			OldPrice:     o.Price,
+			NewPrice:     o.SuggestedPrice,
+		})
+	}
+
+	out := make([]RepriceStation, 0, len(byLoc))
+	for _, st := range byLoc {
+		sort.Slice(st.Actions, func(i, j int) bool {
+			if st.Actions[i].TypeName != st.Actions[j].TypeName {
+				return st.Actions[i].TypeName < st.Actions[j].TypeName
+			}
+			return st.Actions[i].OrderID < st.Actions[j].OrderID
+		})
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if len(out[i].Actions) != len(out[j].Actions) {
+			return len(out[i].Actions) > len(out[j].Actions)
+		}
+		return out[i].LocationName < out[j].LocationName
+	})
+	return out
+}
+
+// RenderRepriceActionsText renders reprice actions as plain text for EVE
+// chat or notes. Prices have no thousands separators so they can be pasted
+// into the modify-order price field.
+func RenderRepriceActionsText(stations []RepriceStation) string {
+	var sb strings.Builder
+	for i, st := range stations {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		name := st.LocationName
+		if name == "" {
+			name = fmt.Sprintf("Location %d", st.LocationID)
+		}
+		fmt.Fprintf(&sb, "%s (%d)\n", name, len(st.Actions))
+		for _, a := range st.Actions {
+			side := "SELL"
+			if a.IsBuyOrder {
+				side = "BUY"
+			}
+			fmt.Fprintf(&sb, "%s %s x%d: %.2f -> %.2f\n", side, a.TypeName, a.VolumeRemain, a.OldPrice, a.NewPrice)
+		}
+	}
+	return sb.String()
+}
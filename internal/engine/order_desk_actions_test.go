@@ -0,0 +1,31 @@
+package engine
+
+import "testing"
+
+func TestBuildRepriceActions(t *testing.T) {
+	orders := []OrderDeskOrder{
+		{OrderID: 1, TypeName: "Tritanium", LocationID: 60003760, LocationName: "Jita IV - Moon 4", Price: 5.1, SuggestedPrice: 5.01, VolumeRemain: 1000, Recommendation: "reprice"},
+		{OrderID: 2, TypeName: "Pyerite", LocationID: 60003760, LocationName: "Jita IV - Moon 4", Price: 10, SuggestedPrice: 10.01, VolumeRemain: 50, IsBuyOrder: true, Recommendation: "reprice"},
+		{OrderID: 3, TypeName: "Mexallon", LocationID: 60008494, LocationName: "Amarr VIII", Price: 70, SuggestedPrice: 69.5, VolumeRemain: 5, Recommendation: "reprice"},
+		{OrderID: 4, TypeName: "Isogen", LocationID: 60008494, LocationName: "Amarr VIII", Price: 90, Recommendation: "hold"},
+		{OrderID: 5, TypeName: "Nocxium", LocationID: 60008494, LocationName: "Amarr VIII", Price: 900, Recommendation: "cancel"},
+	}
+
+	stations := BuildRepriceActions(orders)
+	if len(stations) != 2 || stations[0].LocationID != 60003760 || len(stations[0].Actions) != 2 || len(stations[1].Actions) != 1 {
+		t.Fatalf("stations = %+v", stations)
+	}
+	if stations[0].Actions[0].TypeName != "Pyerite" {
+		t.Fatalf("actions should be sorted by item name, got %+v", stations[0].Actions)
+	}
+
+	want := "Jita IV - Moon 4 (2)\n" +
+		"BUY Pyerite x50: 10.00 -> 10.01\n" +
+		"SELL Tritanium x1000: 5.10 -> 5.01\n" +
+		"\n" +
+		"Amarr VIII (1)\n" +
+		"SELL Mexallon x5: 70.00 -> 69.50\n"
+	if got := RenderRepriceActionsText(stations); got != want {
+		t.Fatalf("text =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,476 @@
+package engine
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"eve-flipper/internal/sde"
+)
+
+// asteroidCategoryID is the SDE category of ores (and their compressed forms).
+const asteroidCategoryID int32 = 25
+
+// OreBasketParams describes a mineral basket to cover.
+type OreBasketParams struct {
+	Minerals       map[int32]int64 // mineral typeID -> quantity needed
+	Yield          float64         // reprocessing efficiency (0-1, e.g., 0.50 for 50%)
+	CompressedOnly bool            // only consider compressed ores
+	AllowMinerals  bool            // allow buying minerals outright when cheaper
+}
+
+// OreBasketLine is one item to buy at the hub.
+type OreBasketLine struct {
+	TypeID    int32   `json:"type_id"`
+	TypeName  string  `json:"type_name"`
+	IsMineral bool    `json:"is_mineral"` // bought as-is, not reprocessed
+	Quantity  int64   `json:"quantity"`
+	Batches   int64   `json:"batches"` // reprocessing batches (portion size units each)
+	UnitPrice float64 `json:"unit_price"`
+	Cost      float64 `json:"cost"`
+	Volume    float64 `json:"volume"`
+}
+
+// OreBasketMineral is the coverage of one refined material.
+type OreBasketMineral struct {
+	TypeID        int32   `json:"type_id"`
+	TypeName      string  `json:"type_name"`
+	Needed        int64   `json:"needed"`
+	Produced      int64   `json:"produced"` // refined plus bought outright
+	Leftover      int64   `json:"leftover"`
+	UnitPrice     float64 `json:"unit_price"`
+	LeftoverValue float64 `json:"leftover_value"`
+}
+
+// OreBasketResult is the cheapest purchase found for a mineral basket.
+type OreBasketResult struct {
+	Lines         []OreBasketLine    `json:"lines"`
+	Minerals      []OreBasketMineral `json:"minerals"`
+	Uncovered     []int32            `json:"uncovered"` // needed minerals nothing priced at the hub provides
+	Yield         float64            `json:"yield"`
+	TotalCost     float64            `json:"total_cost"`
+	TotalVolume   float64            `json:"total_volume"`
+	LeftoverValue float64            `json:"leftover_value"`
+	DirectCost    float64            `json:"direct_cost"` // buying every mineral outright; 0 if any is unpriced
+	Savings       float64            `json:"savings"`     // direct_cost - total_cost
+}
+
+// oreCandidate is something that can be bought to produce minerals, in
+// whole batches of portion units.
+type oreCandidate struct {
+	typeID    int32
+	name      string
+	isMineral bool
+	portion   int64
+	unitPrice float64
+	unitVol   float64
+	yields    []sde.MaterialYield // per batch, before efficiency
+}
+
+func (c *oreCandidate) batchCost() float64 { return c.unitPrice * float64(c.portion) }
+
+// produced returns how much of each material batches of c refine into.
+// Like the game, the efficiency is applied to the whole stack and floored.
+func (c *oreCandidate) produced(batches int64, yield float64, out map[int32]int64) {
+	if batches <= 0 {
+		return
+	}
+	if c.isMineral {
+		out[c.typeID] += batches
+		return
+	}
+	for _, y := range c.yields {
+		out[y.TypeID] += int64(math.Floor(float64(batches) * float64(y.Quantity) * yield))
+	}
+}
+
+// OreBasket prices a mineral basket at the station (or region) of the given
+// system and returns the cheapest ore mix covering it.
+func (a *IndustryAnalyzer) OreBasket(params OreBasketParams, systemID int32, stationID int64) (*OreBasketResult, error) {
+	prices, err := a.fetchMarketPrices(IndustryParams{SystemID: systemID, StationID: stationID})
+	if err != nil {
+		return nil, err
+	}
+	return SolveOreBasket(a.SDE, prices, params), nil
+}
+
+// SolveOreBasket finds the cheapest combination of ores (and, if allowed,
+// raw minerals) whose reprocessed output covers params.Minerals. It solves
+// the LP relaxation exactly, rounds up to whole batches and then drops
+// batches that are not needed for coverage.
+func SolveOreBasket(data *sde.Data, prices map[int32]float64, params OreBasketParams) *OreBasketResult {
+	yield := params.Yield
+	if yield <= 0 || yield > 1 {
+		yield = 0.50
+	}
+	result := &OreBasketResult{Yield: yield, Lines: []OreBasketLine{}, Minerals: []OreBasketMineral{}, Uncovered: []int32{}}
+
+	var needIDs []int32
+	for id, qty := range params.Minerals {
+		if qty > 0 {
+			needIDs = append(needIDs, id)
+		}
+	}
+	sort.Slice(needIDs, func(i, j int) bool { return needIDs[i] < needIDs[j] })
+
+	candidates := oreBasketCandidates(data, prices, params, needIDs, yield)
+
+	// Minerals nothing provides would make the LP infeasible; report them.
+	var covered []int32
+	for _, id := range needIDs {
+		provided := false
+		for _, c := range candidates {
+			if c.isMineral && c.typeID == id || !c.isMineral && yieldOf(c, id) > 0 {
+				provided = true
+				break
+			}
+		}
+		if provided {
+			covered = append(covered, id)
+		} else {
+			result.Uncovered = append(result.Uncovered, id)
+		}
+	}
+
+	batches := make([]int64, len(candidates))
+	if len(covered) > 0 {
+		a := make([][]float64, len(candidates))
+		costs := make([]float64, len(candidates))
+		for i, c := range candidates {
+			a[i] = make([]float64, len(covered))
+			for j, id := range covered {
+				if c.isMineral {
+					if c.typeID == id {
+						a[i][j] = 1
+					}
+				} else {
+					a[i][j] = float64(yieldOf(c, id)) * yield
+				}
+			}
+			costs[i] = c.batchCost()
+		}
+		b := make([]float64, len(covered))
+		for j, id := range covered {
+			b[j] = float64(params.Minerals[id])
+		}
+		for i, x := range minCostCover(a, b, costs) {
+			batches[i] = int64(math.Ceil(x - 1e-6))
+		}
+		need := make(map[int32]int64, len(covered))
+		for _, id := range covered {
+			need[id] = params.Minerals[id]
+		}
+		fillOreShortfall(candidates, batches, need, yield)
+		pruneOreBatches(candidates, batches, need, yield)
+	}
+
+	produced := make(map[int32]int64)
+	for i, c := range candidates {
+		if batches[i] <= 0 {
+			continue
+		}
+		c.produced(batches[i], yield, produced)
+		qty := batches[i] * c.portion
+		line := OreBasketLine{
+			TypeID:    c.typeID,
+			TypeName:  c.name,
+			IsMineral: c.isMineral,
+			Quantity:  qty,
+			Batches:   batches[i],
+			UnitPrice: c.unitPrice,
+			Cost:      float64(qty) * c.unitPrice,
+			Volume:    float64(qty) * c.unitVol,
+		}
+		result.Lines = append(result.Lines, line)
+		result.TotalCost += line.Cost
+		result.TotalVolume += line.Volume
+	}
+	sort.Slice(result.Lines, func(i, j int) bool {
+		if result.Lines[i].Cost != result.Lines[j].Cost {
+			return result.Lines[i].Cost > result.Lines[j].Cost
+		}
+		return result.Lines[i].TypeID < result.Lines[j].TypeID
+	})
+
+	for _, id := range needIDs {
+		if _, ok := produced[id]; !ok {
+			produced[id] = 0
+		}
+	}
+	for id, qty := range produced {
+		m := OreBasketMineral{
+			TypeID:    id,
+			TypeName:  oreBasketTypeName(data, id),
+			Needed:    max(params.Minerals[id], 0),
+			Produced:  qty,
+			UnitPrice: prices[id],
+		}
+		m.Leftover = max(m.Produced-m.Needed, 0)
+		m.LeftoverValue = float64(m.Leftover) * m.UnitPrice
+		result.LeftoverValue += m.LeftoverValue
+		result.Minerals = append(result.Minerals, m)
+	}
+	sort.Slice(result.Minerals, func(i, j int) bool { return result.Minerals[i].TypeID < result.Minerals[j].TypeID })
+
+	direct := 0.0
+	for _, id := range needIDs {
+		p := prices[id]
+		if p <= 0 {
+			direct = 0
+			break
+		}
+		direct += p * float64(params.Minerals[id])
+	}
+	if direct > 0 && len(result.Uncovered) == 0 {
+		result.DirectCost = direct
+		result.Savings = direct - result.TotalCost
+	}
+	return result
+}
+
+// oreBasketCandidates lists priced ores yielding at least one needed
+// mineral, plus the needed minerals themselves when buying them is allowed.
+func oreBasketCandidates(data *sde.Data, prices map[int32]float64, params OreBasketParams, needIDs []int32, yield float64) []*oreCandidate {
+	var out []*oreCandidate
+	needed := make(map[int32]bool, len(needIDs))
+	for _, id := range needIDs {
+		needed[id] = true
+	}
+	if data != nil {
+		if ind := data.IndustryData(); ind != nil {
+			for typeID, rm := range ind.Reprocessing {
+				t := data.Types[typeID]
+				if t == nil || t.CategoryID != asteroidCategoryID || prices[typeID] <= 0 {
+					continue
+				}
+				if params.CompressedOnly && !strings.HasPrefix(t.Name, "Compressed ") {
+					continue
+				}
+				useful := false
+				for _, y := range rm.Yields {
+					if needed[y.TypeID] && float64(y.Quantity)*yield >= 1 {
+						useful = true
+						break
+					}
+				}
+				if !useful {
+					continue
+				}
+				out = append(out, &oreCandidate{
+					typeID:    typeID,
+					name:      t.Name,
+					portion:   int64(max(t.PortionSize, 1)),
+					unitPrice: prices[typeID],
+					unitVol:   t.Volume,
+					yields:    rm.Yields,
+				})
+			}
+		}
+	}
+	if params.AllowMinerals {
+		for _, id := range needIDs {
+			if prices[id] <= 0 {
+				continue
+			}
+			c := &oreCandidate{typeID: id, name: oreBasketTypeName(data, id), isMineral: true, portion: 1, unitPrice: prices[id]}
+			if data != nil {
+				if t := data.Types[id]; t != nil {
+					c.unitVol = t.Volume
+				}
+			}
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].typeID < out[j].typeID })
+	return out
+}
+
+func yieldOf(c *oreCandidate, mineralID int32) int32 {
+	for _, y := range c.yields {
+		if y.TypeID == mineralID {
+			return y.Quantity
+		}
+	}
+	return 0
+}
+
+func oreBasketTypeName(data *sde.Data, typeID int32) string {
+	if data != nil {
+		if t := data.Types[typeID]; t != nil {
+			return t.Name
+		}
+	}
+	return ""
+}
+
+func oreCoverageMet(candidates []*oreCandidate, batches []int64, need map[int32]int64, yield float64) bool {
+	produced := make(map[int32]int64, len(need))
+	for i, c := range candidates {
+		c.produced(batches[i], yield, produced)
+	}
+	for id, qty := range need {
+		if produced[id] < qty {
+			return false
+		}
+	}
+	return true
+}
+
+// fillOreShortfall tops up minerals left short by flooring, using the
+// cheapest source per unit of each short mineral.
+func fillOreShortfall(candidates []*oreCandidate, batches []int64, need map[int32]int64, yield float64) {
+	for range 64 {
+		produced := make(map[int32]int64, len(need))
+		for i, c := range candidates {
+			c.produced(batches[i], yield, produced)
+		}
+		short := false
+		for id, qty := range need {
+			missing := qty - produced[id]
+			if missing <= 0 {
+				continue
+			}
+			short = true
+			best, bestPerUnit := -1, math.Inf(1)
+			var bestPerBatch float64
+			for i, c := range candidates {
+				perBatch := float64(yieldOf(c, id)) * yield
+				if c.isMineral {
+					perBatch = 0
+					if c.typeID == id {
+						perBatch = 1
+					}
+				}
+				if perBatch <= 0 {
+					continue
+				}
+				if perUnit := c.batchCost() / perBatch; perUnit < bestPerUnit {
+					best, bestPerUnit, bestPerBatch = i, perUnit, perBatch
+				}
+			}
+			if best >= 0 {
+				batches[best] += max(int64(math.Ceil(float64(missing)/bestPerBatch)), 1)
+			}
+		}
+		if !short {
+			return
+		}
+	}
+}
+
+// pruneOreBatches removes batches the rounded solution does not need,
+// dearest candidates first.
+func pruneOreBatches(candidates []*oreCandidate, batches []int64, need map[int32]int64, yield float64) {
+	order := make([]int, 0, len(candidates))
+	for i := range candidates {
+		if batches[i] > 0 {
+			order = append(order, i)
+		}
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return candidates[order[a]].batchCost() > candidates[order[b]].batchCost()
+	})
+	for _, i := range order {
+		// Coverage is monotone in the batch count, so binary search the
+		// largest removable amount.
+		orig := batches[i]
+		lo, hi := int64(0), orig
+		for lo < hi {
+			mid := (lo + hi + 1) / 2
+			batches[i] = orig - mid
+			if oreCoverageMet(candidates, batches, need, yield) {
+				lo = mid
+			} else {
+				hi = mid - 1
+			}
+		}
+		batches[i] = orig - lo
+	}
+}
+
+// minCostCover solves min cᵀx subject to Ax ≥ b, x ≥ 0 (a is indexed
+// [candidate][mineral]) through its dual, max bᵀy subject to Aᵀy ≤ c,
+// y ≥ 0. With c ≥ 0 the slack basis is feasible from the start, and the
+// optimal x is read off the slack columns of the objective row. Bland's
+// rule keeps the simplex from cycling.
+func minCostCover(a [][]float64, b, c []float64) []float64 {
+	m, n := len(c), len(b)
+	x := make([]float64, m)
+	if m == 0 || n == 0 {
+		return x
+	}
+	// Scale demand to ~1 for numerical stability; x scales linearly with b.
+	scale := 0.0
+	for _, v := range b {
+		scale = max(scale, v)
+	}
+	if scale <= 0 {
+		return x
+	}
+
+	const eps = 1e-9
+	cols := n + m + 1
+	t := make([][]float64, m+1)
+	for i := 0; i < m; i++ {
+		t[i] = make([]float64, cols)
+		copy(t[i], a[i])
+		t[i][n+i] = 1
+		t[i][cols-1] = c[i]
+	}
+	obj := make([]float64, cols)
+	for j := 0; j < n; j++ {
+		obj[j] = -b[j] / scale
+	}
+	t[m] = obj
+	basis := make([]int, m)
+	for i := range basis {
+		basis[i] = n + i
+	}
+
+	for iter := 0; iter < 50*(m+n); iter++ {
+		enter := -1
+		for j := 0; j < cols-1; j++ {
+			if obj[j] < -eps {
+				enter = j
+				break
+			}
+		}
+		if enter < 0 {
+			break
+		}
+		leave := -1
+		best := math.Inf(1)
+		for i := 0; i < m; i++ {
+			if t[i][enter] <= eps {
+				continue
+			}
+			ratio := t[i][cols-1] / t[i][enter]
+			if ratio < best-eps || (ratio <= best+eps && leave >= 0 && basis[i] < basis[leave]) {
+				best, leave = ratio, i
+			}
+		}
+		if leave < 0 {
+			// Unbounded dual: some demand cannot be met. Callers filter
+			// those minerals out beforehand.
+			return x
+		}
+		pv := t[leave][enter]
+		for j := range t[leave] {
+			t[leave][j] /= pv
+		}
+		for i := 0; i <= m; i++ {
+			if i == leave || t[i][enter] == 0 {
+				continue
+			}
+			f := t[i][enter]
+			for j := range t[i] {
+				t[i][j] -= f * t[leave][j]
+			}
+		}
+		basis[leave] = enter
+	}
+	for i := 0; i < m; i++ {
+		x[i] = max(obj[n+i], 0) * scale
+	}
+	return x
+}
@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"math"
+	"testing"
+
+	"eve-flipper/internal/sde"
+)
+
+func newOreBasketTestData() *sde.Data {
+	ind := sde.NewIndustryData()
+	ind.Reprocessing[1001] = &sde.ReprocessingMaterial{TypeID: 1001, Yields: []sde.MaterialYield{{TypeID: 34, Quantity: 400}}}
+	ind.Reprocessing[1002] = &sde.ReprocessingMaterial{TypeID: 1002, Yields: []sde.MaterialYield{{TypeID: 34, Quantity: 150}, {TypeID: 35, Quantity: 90}}}
+	ind.Reprocessing[1003] = &sde.ReprocessingMaterial{TypeID: 1003, Yields: []sde.MaterialYield{{TypeID: 34, Quantity: 400}}}
+	return &sde.Data{
+		Types: map[int32]*sde.ItemType{
+			34:   {ID: 34, Name: "Tritanium", Volume: 0.01, PortionSize: 1},
+			35:   {ID: 35, Name: "Pyerite", Volume: 0.01, PortionSize: 1},
+			36:   {ID: 36, Name: "Mexallon", Volume: 0.01, PortionSize: 1},
+			1001: {ID: 1001, Name: "Compressed Veldspar", CategoryID: asteroidCategoryID, Volume: 0.1, PortionSize: 1},
+			1002: {ID: 1002, Name: "Compressed Scordite", CategoryID: asteroidCategoryID, Volume: 0.19, PortionSize: 1},
+			1003: {ID: 1003, Name: "Veldspar", CategoryID: asteroidCategoryID, Volume: 0.1, PortionSize: 100},
+		},
+		Industry: ind,
+	}
+}
+
+func TestMinCostCover(t *testing.T) {
+	// min 2x1 + 3x2 s.t. x1 + x2 >= 4, x1 + 3x2 >= 6 -> x = (3, 1).
+	x := minCostCover([][]float64{{1, 1}, {1, 3}}, []float64{4, 6}, []float64{2, 3})
+	if math.Abs(x[0]-3) > 1e-6 || math.Abs(x[1]-1) > 1e-6 {
+		t.Fatalf("x = %v, want [3 1]", x)
+	}
+}
+
+func TestSolveOreBasketCoversMinerals(t *testing.T) {
+	data := newOreBasketTestData()
+	prices := map[int32]float64{34: 6, 35: 20, 1001: 1000, 1002: 800, 1003: 1}
+	res := SolveOreBasket(data, prices, OreBasketParams{
+		Minerals:       map[int32]int64{34: 10000, 35: 2000},
+		Yield:          0.5,
+		CompressedOnly: true,
+		AllowMinerals:  true,
+	})
+
+	for _, m := range res.Minerals {
+		if m.Produced < m.Needed {
+			t.Fatalf("%s produced %d < needed %d", m.TypeName, m.Produced, m.Needed)
+		}
+	}
+	bought := map[int32]int64{}
+	for _, l := range res.Lines {
+		bought[l.TypeID] = l.Quantity
+	}
+	if bought[1003] != 0 {
+		t.Fatalf("uncompressed ore bought with CompressedOnly: %+v", res.Lines)
+	}
+	// Scordite is the cheapest pyerite source (800/45 < 20 per unit).
+	if bought[1002] != 45 || bought[35] != 0 {
+		t.Fatalf("scordite = %d, pyerite = %d, want 45 and 0", bought[1002], bought[35])
+	}
+	if res.DirectCost != 10000*6+2000*20 {
+		t.Fatalf("direct cost = %v", res.DirectCost)
+	}
+	if res.TotalCost >= res.DirectCost || res.Savings <= 0 {
+		t.Fatalf("total %v should beat direct %v", res.TotalCost, res.DirectCost)
+	}
+}
+
+func TestSolveOreBasketUncompressedPortions(t *testing.T) {
+	data := newOreBasketTestData()
+	prices := map[int32]float64{34: 6, 1003: 1}
+	res := SolveOreBasket(data, prices, OreBasketParams{
+		Minerals: map[int32]int64{34: 500},
+		Yield:    0.5,
+	})
+	if len(res.Lines) != 1 || res.Lines[0].TypeID != 1003 {
+		t.Fatalf("lines = %+v, want only Veldspar", res.Lines)
+	}
+	// 200 tritanium per 100-unit batch at 50%: three batches.
+	if got := res.Lines[0]; got.Batches != 3 || got.Quantity != 300 {
+		t.Fatalf("batches = %d quantity = %d, want 3 and 300", got.Batches, got.Quantity)
+	}
+	if res.Minerals[0].Leftover != 100 {
+		t.Fatalf("leftover = %d, want 100", res.Minerals[0].Leftover)
+	}
+}
+
+func TestSolveOreBasketUncovered(t *testing.T) {
+	data := newOreBasketTestData()
+	res := SolveOreBasket(data, map[int32]float64{1001: 1000}, OreBasketParams{
+		Minerals:       map[int32]int64{34: 100, 36: 50},
+		CompressedOnly: true,
+	})
+	if len(res.Uncovered) != 1 || res.Uncovered[0] != 36 {
+		t.Fatalf("uncovered = %v, want [36]", res.Uncovered)
+	}
+	if res.DirectCost != 0 {
+		t.Fatalf("direct cost = %v, want 0 with unpriced minerals", res.DirectCost)
+	}
+	if len(res.Lines) != 1 || res.Lines[0].Quantity != 1 {
+		t.Fatalf("lines = %+v, want one Compressed Veldspar", res.Lines)
+	}
+}
@@ -0,0 +1,322 @@
+package engine
+
+import (
+	"sort"
+
+	"eve-flipper/internal/graph"
+	"eve-flipper/internal/pricing"
+	"eve-flipper/internal/sde"
+)
+
+// TradeHub is a major NPC market hub.
+type TradeHub struct {
+	Name      string `json:"name"`
+	RegionID  int32  `json:"region_id"`
+	SystemID  int32  `json:"system_id"`
+	StationID int64  `json:"station_id"`
+}
+
+// MajorTradeHubs are the empire trade hubs, Jita first.
+var MajorTradeHubs = []TradeHub{
+	{Name: "Jita", RegionID: JitaRegionID, SystemID: JitaSystemID, StationID: JitaStationID},
+	{Name: "Amarr", RegionID: 10000043, SystemID: 30002187, StationID: 60008494},
+	{Name: "Dodixie", RegionID: 10000032, SystemID: 30002659, StationID: 60011866},
+	{Name: "Rens", RegionID: 10000030, SystemID: 30002510, StationID: 60004588},
+	{Name: "Hek", RegionID: 10000042, SystemID: 30002053, StationID: 60005686},
+}
+
+// piCustomsBaseValue is the customs office base value per unit by tier
+// (P0-P4); export tax is base value × tax rate, import tax half of that.
+var piCustomsBaseValue = [...]float64{5, 400, 7200, 60000, 1200000}
+
+// PIHub is a hub's PI quotes for the arbitrage scan.
+type PIHub struct {
+	Name     string
+	SystemID int32
+	Quotes   map[int32]pricing.Quote
+}
+
+// PIArbitrageParams configures ScanPIArbitrage.
+type PIArbitrageParams struct {
+	Hubs             []PIHub
+	POCOTaxPercent   float64 // customs office export tax; imports pay half
+	SalesTaxPercent  float64
+	BrokerFeePercent float64
+	SellToBuyOrders  bool // sell outputs into buy orders instead of listing them
+	MinTier          int  // lowest factory output tier (1-4)
+	// RoutePreferences are the user's avoided systems and pinned paths.
+	RoutePreferences graph.RoutePreferences
+}
+
+// PIInputLine is one input of a factory cycle.
+type PIInputLine struct {
+	TypeID    int32   `json:"type_id"`
+	TypeName  string  `json:"type_name"`
+	Tier      int     `json:"tier"`
+	Quantity  int64   `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	Cost      float64 `json:"cost"`
+}
+
+// PIFactoryResult is a factory planet setup: buy the inputs at one hub,
+// import them, run the schematic, export and sell the output at another.
+type PIFactoryResult struct {
+	SchematicID    int32         `json:"schematic_id"`
+	SchematicName  string        `json:"schematic_name"`
+	Tier           int           `json:"tier"`
+	OutputTypeID   int32         `json:"output_type_id"`
+	OutputName     string        `json:"output_name"`
+	OutputQuantity int64         `json:"output_quantity"`
+	CycleSeconds   int32         `json:"cycle_seconds"`
+	InputHub       string        `json:"input_hub"`
+	OutputHub      string        `json:"output_hub"`
+	Inputs         []PIInputLine `json:"inputs"`
+	InputCost      float64       `json:"input_cost"`
+	ImportTax      float64       `json:"import_tax"`
+	ExportTax      float64       `json:"export_tax"`
+	Revenue        float64       `json:"revenue"` // before sales tax and broker fee
+	Fees           float64       `json:"fees"`
+	ProfitPerCycle float64       `json:"profit_per_cycle"`
+	ProfitPerDay   float64       `json:"profit_per_day"` // one factory running continuously
+	MarginPercent  float64       `json:"margin_percent"`
+}
+
+// PIFlipResult is a PI commodity bought from sell orders at one hub and
+// sold into buy orders at another.
+type PIFlipResult struct {
+	TypeID        int32   `json:"type_id"`
+	TypeName      string  `json:"type_name"`
+	Tier          int     `json:"tier"`
+	BuyHub        string  `json:"buy_hub"`
+	SellHub       string  `json:"sell_hub"`
+	BuyPrice      float64 `json:"buy_price"`
+	SellPrice     float64 `json:"sell_price"`
+	ProfitPerUnit float64 `json:"profit_per_unit"` // after sales tax
+	MarginPercent float64 `json:"margin_percent"`
+	Jumps         int     `json:"jumps"`
+}
+
+// PIArbitrageResult holds profitable factory setups and hub-to-hub flips,
+// best first.
+type PIArbitrageResult struct {
+	Factories []PIFactoryResult `json:"factories"`
+	Flips     []PIFlipResult    `json:"flips"`
+}
+
+// PITiers returns the tier (0 = raw, 1-4 = P1-P4) of every type appearing
+// in a PI schematic.
+func PITiers(data *sde.Data) map[int32]int {
+	tiers := make(map[int32]int)
+	if data == nil {
+		return tiers
+	}
+	ind := data.IndustryData()
+	if ind == nil {
+		return tiers
+	}
+	producedBy := make(map[int32]*sde.PlanetSchematic)
+	for _, s := range ind.PlanetSchematics {
+		for _, out := range s.Outputs {
+			if prev := producedBy[out.TypeID]; prev == nil || s.ID < prev.ID {
+				producedBy[out.TypeID] = s
+			}
+		}
+	}
+	var tierOf func(typeID int32, depth int) int
+	tierOf = func(typeID int32, depth int) int {
+		if t, ok := tiers[typeID]; ok {
+			return t
+		}
+		s := producedBy[typeID]
+		if s == nil || depth > 8 {
+			tiers[typeID] = 0
+			return 0
+		}
+		t := 0
+		for _, in := range s.Inputs {
+			t = max(t, tierOf(in.TypeID, depth+1)+1)
+		}
+		t = min(t, len(piCustomsBaseValue)-1)
+		tiers[typeID] = t
+		return t
+	}
+	for _, s := range ind.PlanetSchematics {
+		for _, in := range s.Inputs {
+			tierOf(in.TypeID, 0)
+		}
+		for _, out := range s.Outputs {
+			tierOf(out.TypeID, 0)
+		}
+	}
+	return tiers
+}
+
+// ScanPIArbitrage compares PI schematic input costs against output prices
+// across hubs (including customs office taxes) and finds PI commodities
+// that can be flipped between hubs.
+func ScanPIArbitrage(data *sde.Data, p PIArbitrageParams) PIArbitrageResult {
+	res := PIArbitrageResult{Factories: []PIFactoryResult{}, Flips: []PIFlipResult{}}
+	if data == nil || len(p.Hubs) == 0 {
+		return res
+	}
+	ind := data.IndustryData()
+	if ind == nil {
+		return res
+	}
+	tiers := PITiers(data)
+	pocoRate := p.POCOTaxPercent / 100
+	saleFee := p.SalesTaxPercent / 100
+	if !p.SellToBuyOrders {
+		saleFee += p.BrokerFeePercent / 100
+	}
+	name := func(typeID int32) string {
+		if t := data.Types[typeID]; t != nil {
+			return t.Name
+		}
+		return ""
+	}
+	// sellPrice is what a unit fetches at hub before fees.
+	sellPrice := func(hub PIHub, typeID int32) float64 {
+		q := hub.Quotes[typeID]
+		if p.SellToBuyOrders {
+			return q.Buy
+		}
+		return q.Sell
+	}
+
+	for _, s := range ind.PlanetSchematics {
+		if len(s.Outputs) != 1 || len(s.Inputs) == 0 || s.CycleTime <= 0 {
+			continue
+		}
+		out := s.Outputs[0]
+		tier := tiers[out.TypeID]
+		if tier < max(p.MinTier, 1) {
+			continue
+		}
+
+		bestIn, bestInCost := -1, 0.0
+		for i, hub := range p.Hubs {
+			cost := 0.0
+			priced := true
+			for _, in := range s.Inputs {
+				price := hub.Quotes[in.TypeID].Sell
+				if price <= 0 {
+					priced = false
+					break
+				}
+				cost += price * float64(in.Quantity)
+			}
+			if priced && (bestIn < 0 || cost < bestInCost) {
+				bestIn, bestInCost = i, cost
+			}
+		}
+		bestOut, bestOutPrice := -1, 0.0
+		for i, hub := range p.Hubs {
+			if price := sellPrice(hub, out.TypeID); price > bestOutPrice {
+				bestOut, bestOutPrice = i, price
+			}
+		}
+		if bestIn < 0 || bestOut < 0 {
+			continue
+		}
+
+		r := PIFactoryResult{
+			SchematicID:    s.ID,
+			SchematicName:  s.Name,
+			Tier:           tier,
+			OutputTypeID:   out.TypeID,
+			OutputName:     name(out.TypeID),
+			OutputQuantity: out.Quantity,
+			CycleSeconds:   s.CycleTime,
+			InputHub:       p.Hubs[bestIn].Name,
+			OutputHub:      p.Hubs[bestOut].Name,
+			InputCost:      bestInCost,
+		}
+		for _, in := range s.Inputs {
+			unit := p.Hubs[bestIn].Quotes[in.TypeID].Sell
+			inTier := tiers[in.TypeID]
+			r.Inputs = append(r.Inputs, PIInputLine{
+				TypeID:    in.TypeID,
+				TypeName:  name(in.TypeID),
+				Tier:      inTier,
+				Quantity:  in.Quantity,
+				UnitPrice: unit,
+				Cost:      unit * float64(in.Quantity),
+			})
+			r.ImportTax += piCustomsBaseValue[inTier] * pocoRate / 2 * float64(in.Quantity)
+		}
+		r.ExportTax = piCustomsBaseValue[tier] * pocoRate * float64(out.Quantity)
+		r.Revenue = bestOutPrice * float64(out.Quantity)
+		r.Fees = r.Revenue * saleFee
+		r.ProfitPerCycle = r.Revenue - r.Fees - r.InputCost - r.ImportTax - r.ExportTax
+		if r.ProfitPerCycle <= 0 {
+			continue
+		}
+		r.ProfitPerDay = r.ProfitPerCycle * 86400 / float64(s.CycleTime)
+		r.MarginPercent = r.ProfitPerCycle / (r.InputCost + r.ImportTax + r.ExportTax) * 100
+		res.Factories = append(res.Factories, r)
+	}
+	sort.Slice(res.Factories, func(i, j int) bool {
+		if res.Factories[i].ProfitPerDay != res.Factories[j].ProfitPerDay {
+			return res.Factories[i].ProfitPerDay > res.Factories[j].ProfitPerDay
+		}
+		return res.Factories[i].SchematicID < res.Factories[j].SchematicID
+	})
+
+	typeIDs := make([]int32, 0, len(tiers))
+	for id := range tiers {
+		typeIDs = append(typeIDs, id)
+	}
+	sort.Slice(typeIDs, func(i, j int) bool { return typeIDs[i] < typeIDs[j] })
+	// Hub-to-hub jumps, computed once per scan rather than per commodity.
+	hubJumps := make([][]int, len(p.Hubs))
+	var universe *graph.Universe
+	if data.Universe != nil {
+		universe = data.Universe.WithRoutePreferences(p.RoutePreferences)
+	}
+	for i, from := range p.Hubs {
+		hubJumps[i] = make([]int, len(p.Hubs))
+		for j, to := range p.Hubs {
+			if i != j && universe != nil {
+				hubJumps[i][j] = universe.ShortestPath(from.SystemID, to.SystemID)
+			}
+		}
+	}
+	for _, typeID := range typeIDs {
+		for i, from := range p.Hubs {
+			buy := from.Quotes[typeID].Sell
+			if buy <= 0 {
+				continue
+			}
+			for j, to := range p.Hubs {
+				if i == j {
+					continue
+				}
+				sell := to.Quotes[typeID].Buy
+				profit := sell*(1-p.SalesTaxPercent/100) - buy
+				if sell <= 0 || profit <= 0 {
+					continue
+				}
+				res.Flips = append(res.Flips, PIFlipResult{
+					TypeID:        typeID,
+					TypeName:      name(typeID),
+					Tier:          tiers[typeID],
+					BuyHub:        from.Name,
+					SellHub:       to.Name,
+					BuyPrice:      buy,
+					SellPrice:     sell,
+					ProfitPerUnit: profit,
+					MarginPercent: profit / buy * 100,
+					Jumps:         hubJumps[i][j],
+				})
+			}
+		}
+	}
+	sort.Slice(res.Flips, func(i, j int) bool {
+		if res.Flips[i].MarginPercent != res.Flips[j].MarginPercent {
+			return res.Flips[i].MarginPercent > res.Flips[j].MarginPercent
+		}
+		return res.Flips[i].TypeID < res.Flips[j].TypeID
+	})
+	return res
+}
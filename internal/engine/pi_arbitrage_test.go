@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"math"
+	"testing"
+
+	"eve-flipper/internal/pricing"
+	"eve-flipper/internal/sde"
+)
+
+func newPIArbitrageTestData() *sde.Data {
+	ind := sde.NewIndustryData()
+	ind.PlanetSchematics[121] = &sde.PlanetSchematic{
+		ID: 121, Name: "Water", CycleTime: 1800,
+		Inputs:  []sde.PlanetSchematicMaterial{{TypeID: 2268, Quantity: 3000}},
+		Outputs: []sde.PlanetSchematicMaterial{{TypeID: 3645, Quantity: 20}},
+	}
+	ind.PlanetSchematics[122] = &sde.PlanetSchematic{
+		ID: 122, Name: "Oxygen", CycleTime: 1800,
+		Inputs:  []sde.PlanetSchematicMaterial{{TypeID: 2309, Quantity: 3000}},
+		Outputs: []sde.PlanetSchematicMaterial{{TypeID: 3683, Quantity: 20}},
+	}
+	ind.PlanetSchematics[66] = &sde.PlanetSchematic{
+		ID: 66, Name: "Coolant", CycleTime: 3600,
+		Inputs:  []sde.PlanetSchematicMaterial{{TypeID: 3645, Quantity: 40}, {TypeID: 3683, Quantity: 40}},
+		Outputs: []sde.PlanetSchematicMaterial{{TypeID: 9832, Quantity: 5}},
+	}
+	return &sde.Data{
+		Types: map[int32]*sde.ItemType{
+			3645: {ID: 3645, Name: "Water"},
+			3683: {ID: 3683, Name: "Oxygen"},
+			9832: {ID: 9832, Name: "Coolant"},
+		},
+		Industry: ind,
+	}
+}
+
+func TestPITiers(t *testing.T) {
+	tiers := PITiers(newPIArbitrageTestData())
+	for typeID, want := range map[int32]int{2268: 0, 3645: 1, 9832: 2} {
+		if tiers[typeID] != want {
+			t.Fatalf("tier(%d) = %d, want %d", typeID, tiers[typeID], want)
+		}
+	}
+}
+
+func TestScanPIArbitrage(t *testing.T) {
+	res := ScanPIArbitrage(newPIArbitrageTestData(), PIArbitrageParams{
+		Hubs: []PIHub{
+			{Name: "Jita", Quotes: map[int32]pricing.Quote{
+				3645: {Sell: 500, Buy: 450}, 3683: {Sell: 400}, 9832: {Sell: 12000, Buy: 11000},
+			}},
+			{Name: "Amarr", Quotes: map[int32]pricing.Quote{
+				3645: {Sell: 600, Buy: 700}, 3683: {Sell: 380}, 9832: {Sell: 15000},
+			}},
+		},
+		POCOTaxPercent:  10,
+		SalesTaxPercent: 8,
+	})
+
+	// Water and Oxygen need unpriced P0 inputs, so only Coolant qualifies.
+	if len(res.Factories) != 1 {
+		t.Fatalf("factories = %+v, want Coolant only", res.Factories)
+	}
+	f := res.Factories[0]
+	if f.InputHub != "Jita" || f.OutputHub != "Amarr" {
+		t.Fatalf("hubs = %s -> %s, want Jita -> Amarr", f.InputHub, f.OutputHub)
+	}
+	// 80 P1 imported at 400×10%/2, 5 P2 exported at 7200×10%.
+	if f.InputCost != 36000 || f.ImportTax != 1600 || f.ExportTax != 3600 {
+		t.Fatalf("costs = %v / %v / %v", f.InputCost, f.ImportTax, f.ExportTax)
+	}
+	if math.Abs(f.ProfitPerCycle-27800) > 1e-6 || math.Abs(f.ProfitPerDay-27800*24) > 1e-6 {
+		t.Fatalf("profit = %v per cycle, %v per day", f.ProfitPerCycle, f.ProfitPerDay)
+	}
+
+	if len(res.Flips) != 1 {
+		t.Fatalf("flips = %+v, want Water Jita -> Amarr", res.Flips)
+	}
+	if fl := res.Flips[0]; fl.TypeID != 3645 || fl.BuyHub != "Jita" || fl.SellHub != "Amarr" || math.Abs(fl.ProfitPerUnit-144) > 1e-6 {
+		t.Fatalf("flip = %+v", fl)
+	}
+}
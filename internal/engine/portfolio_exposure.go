@@ -0,0 +1,439 @@
+package engine
+
+import (
+	"math"
+	"sort"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/sde"
+)
+
+// Exposure holding sources.
+const (
+	ExposureAsset     = "asset"
+	ExposureSellOrder = "sell_order"
+	ExposureBuyOrder  = "buy_order" // escrow committed to buying the type
+)
+
+// Rebalancing actions.
+const (
+	RebalanceTrim        = "trim"        // one item is too large a share
+	RebalanceDiversify   = "diversify"   // one category is too large a share
+	RebalanceLiquidate   = "liquidate"   // stock would take too long to sell
+	RebalanceDeRisk      = "de_risk"     // large position in a volatile item
+	RebalanceCancelBuys  = "cancel_buys" // buy orders add to an oversized position
+	RebalanceDecorrelate = "decorrelate" // correlated items act as one position
+)
+
+// Portfolio exposure defaults.
+const (
+	DefaultExposureMarketSharePct   = 20.0 // of daily volume sold when liquidating
+	DefaultExposureVolatileDRVI     = 10.0
+	DefaultExposureMaxItemPct       = 25.0
+	DefaultExposureMaxCategoryPct   = 50.0
+	DefaultExposureSlowDays         = 30.0
+	DefaultExposureCorrelation      = 0.7
+	exposureHistoryDays             = 30
+	exposureCorrelationDays         = 90
+	exposureCorrelationMinDays      = 20
+	exposureCorrelationMaxItems     = 10
+	exposureMaxLiquidationDays      = 365.0
+	exposureMinActionSharePct       = 5.0
+	exposureUncategorized           = "Uncategorized"
+	exposureMarketGroupMaxTreeDepth = 32
+)
+
+// ExposureHolding is a stack of assets or an open order. UnitPrice is the
+// order price; assets are valued at ExposureInput.Prices.
+type ExposureHolding struct {
+	TypeID    int32
+	Quantity  int64
+	Source    string
+	UnitPrice float64
+}
+
+// ExposureInput holds the portfolio and the market data to value it.
+type ExposureInput struct {
+	Holdings []ExposureHolding
+	// Prices is the reference unit value by type (e.g. Jita best sell).
+	Prices map[int32]float64
+	// History is the market history by type in the reference region.
+	History map[int32][]esi.HistoryEntry
+}
+
+// ExposureParams configures AnalyzePortfolioExposure; zero values use the
+// defaults.
+type ExposureParams struct {
+	MarketSharePct float64
+	VolatileDRVI   float64
+	MaxItemPct     float64
+	MaxCategoryPct float64
+	SlowDays       float64
+	Correlation    float64
+}
+
+// ExposureItem is the portfolio's position in one type.
+type ExposureItem struct {
+	TypeID          int32   `json:"type_id"`
+	TypeName        string  `json:"type_name"`
+	Category        string  `json:"category"`
+	Units           int64   `json:"units"` // assets plus listed units
+	AssetValue      float64 `json:"asset_value"`
+	ListedValue     float64 `json:"listed_value"`
+	BuyEscrow       float64 `json:"buy_escrow"`
+	Value           float64 `json:"value"`
+	SharePct        float64 `json:"share_pct"`
+	DailyVolume     float64 `json:"daily_volume"`
+	LiquidationDays float64 `json:"liquidation_days"` // -1 = no trade history
+	DRVI            float64 `json:"drvi"`
+	Volatile        bool    `json:"volatile"`
+}
+
+// ExposureCategory is the portfolio's value in one top-level market group.
+type ExposureCategory struct {
+	Name     string  `json:"name"`
+	Items    int     `json:"items"`
+	Value    float64 `json:"value"`
+	SharePct float64 `json:"share_pct"`
+}
+
+// ExposureCorrelation is a pair of holdings whose daily prices move together.
+type ExposureCorrelation struct {
+	TypeA            int32   `json:"type_a"`
+	TypeB            int32   `json:"type_b"`
+	NameA            string  `json:"name_a"`
+	NameB            string  `json:"name_b"`
+	Correlation      float64 `json:"correlation"`
+	Days             int     `json:"days"`
+	CombinedSharePct float64 `json:"combined_share_pct"`
+}
+
+// RebalanceAction is one suggested change, with the ISK to move.
+type RebalanceAction struct {
+	Action   string  `json:"action"`
+	TypeID   int32   `json:"type_id,omitempty"`
+	TypeName string  `json:"type_name,omitempty"`
+	Category string  `json:"category,omitempty"`
+	Value    float64 `json:"value"`
+	Reason   string  `json:"reason"`
+}
+
+// PortfolioExposure is the concentration, liquidity and volatility profile
+// of a character's assets and open orders.
+type PortfolioExposure struct {
+	TotalValue              float64               `json:"total_value"`
+	AssetValue              float64               `json:"asset_value"`
+	ListedValue             float64               `json:"listed_value"`
+	BuyEscrow               float64               `json:"buy_escrow"`
+	HHI                     float64               `json:"hhi"` // of item value shares, 0-10000
+	TopItemSharePct         float64               `json:"top_item_share_pct"`
+	TopCategorySharePct     float64               `json:"top_category_share_pct"`
+	WeightedLiquidationDays float64               `json:"weighted_liquidation_days"`
+	IlliquidValuePct        float64               `json:"illiquid_value_pct"` // slower than SlowDays or no history
+	VolatileValuePct        float64               `json:"volatile_value_pct"`
+	WeightedDRVI            float64               `json:"weighted_drvi"`
+	Items                   []ExposureItem        `json:"items"`
+	Categories              []ExposureCategory    `json:"categories"`
+	Correlations            []ExposureCorrelation `json:"correlations"`
+	Actions                 []RebalanceAction     `json:"actions"`
+}
+
+// AnalyzePortfolioExposure values the holdings per type and reports how
+// concentrated they are by item and by top-level market group, how long
+// the stock takes to sell at MarketSharePct of the daily volume, how much
+// sits in volatile (high DRVI) items and which of the largest holdings are
+// correlated, with rebalancing actions for each breach, largest first.
+func AnalyzePortfolioExposure(data *sde.Data, in ExposureInput, p ExposureParams) PortfolioExposure {
+	p = p.withDefaults()
+	out := PortfolioExposure{
+		Items:        []ExposureItem{},
+		Categories:   []ExposureCategory{},
+		Correlations: []ExposureCorrelation{},
+		Actions:      []RebalanceAction{},
+	}
+
+	byType := make(map[int32]*ExposureItem)
+	var order []int32
+	for _, h := range in.Holdings {
+		if h.TypeID <= 0 || h.Quantity <= 0 {
+			continue
+		}
+		it := byType[h.TypeID]
+		if it == nil {
+			it = &ExposureItem{TypeID: h.TypeID, Category: exposureUncategorized}
+			if data != nil {
+				if t := data.Types[h.TypeID]; t != nil {
+					it.TypeName = t.Name
+					it.Category = exposureCategory(data, t.MarketGroup)
+				}
+			}
+			byType[h.TypeID] = it
+			order = append(order, h.TypeID)
+		}
+		switch h.Source {
+		case ExposureSellOrder:
+			it.Units += h.Quantity
+			it.ListedValue += h.UnitPrice * float64(h.Quantity)
+		case ExposureBuyOrder:
+			it.BuyEscrow += h.UnitPrice * float64(h.Quantity)
+		default:
+			it.Units += h.Quantity
+			it.AssetValue += in.Prices[h.TypeID] * float64(h.Quantity)
+		}
+	}
+
+	for _, typeID := range order {
+		it := byType[typeID]
+		it.Value = it.AssetValue + it.ListedValue + it.BuyEscrow
+		if it.Value <= 0 {
+			continue
+		}
+		history := in.History[typeID]
+		it.DailyVolume = sanitizeFloat(math.Round(avgDailyVolume(history, exposureHistoryDays)*100) / 100)
+		it.LiquidationDays = -1
+		if sell := it.DailyVolume * p.MarketSharePct / 100; sell > 0 {
+			it.LiquidationDays = math.Min(math.Round(float64(it.Units)/sell*10)/10, exposureMaxLiquidationDays)
+		}
+		it.DRVI = sanitizeFloat(math.Round(CalcDRVI(history, exposureHistoryDays)*10) / 10)
+		it.Volatile = it.DRVI >= p.VolatileDRVI
+		out.AssetValue += it.AssetValue
+		out.ListedValue += it.ListedValue
+		out.BuyEscrow += it.BuyEscrow
+		out.TotalValue += it.Value
+		out.Items = append(out.Items, *it)
+	}
+	if out.TotalValue <= 0 {
+		return out
+	}
+	sort.SliceStable(out.Items, func(i, j int) bool { return out.Items[i].Value > out.Items[j].Value })
+
+	categories := make(map[string]*ExposureCategory)
+	var stockValue, liquidationWeighted, illiquid, volatile, drviWeighted float64
+	for i := range out.Items {
+		it := &out.Items[i]
+		share := it.Value / out.TotalValue * 100
+		it.SharePct = math.Round(share*100) / 100
+		out.HHI += share * share
+
+		c := categories[it.Category]
+		if c == nil {
+			c = &ExposureCategory{Name: it.Category}
+			categories[it.Category] = c
+		}
+		c.Items++
+		c.Value += it.Value
+
+		stock := it.AssetValue + it.ListedValue
+		if it.LiquidationDays >= 0 {
+			stockValue += stock
+			liquidationWeighted += stock * it.LiquidationDays
+		}
+		if it.LiquidationDays < 0 || it.LiquidationDays > p.SlowDays {
+			illiquid += stock
+		}
+		if it.Volatile {
+			volatile += it.Value
+		}
+		drviWeighted += it.Value * it.DRVI
+	}
+	out.HHI = math.Round(out.HHI)
+	out.TopItemSharePct = out.Items[0].SharePct
+	if stockValue > 0 {
+		out.WeightedLiquidationDays = math.Round(liquidationWeighted/stockValue*10) / 10
+	}
+	if stock := out.AssetValue + out.ListedValue; stock > 0 {
+		out.IlliquidValuePct = math.Round(illiquid/stock*1000) / 10
+	}
+	out.VolatileValuePct = math.Round(volatile/out.TotalValue*1000) / 10
+	out.WeightedDRVI = math.Round(drviWeighted/out.TotalValue*10) / 10
+
+	for _, c := range categories {
+		c.SharePct = math.Round(c.Value/out.TotalValue*10000) / 100
+		out.Categories = append(out.Categories, *c)
+	}
+	sort.Slice(out.Categories, func(i, j int) bool {
+		if out.Categories[i].Value != out.Categories[j].Value {
+			return out.Categories[i].Value > out.Categories[j].Value
+		}
+		return out.Categories[i].Name < out.Categories[j].Name
+	})
+	out.TopCategorySharePct = out.Categories[0].SharePct
+
+	out.Correlations = exposureCorrelations(out.Items, in.History, p.Correlation)
+	out.Actions = rebalanceActions(out, p)
+	return out
+}
+
+// exposureCategory names the top-level market group of a type's leaf group.
+func exposureCategory(data *sde.Data, marketGroupID int32) string {
+	name := exposureUncategorized
+	for id, depth := marketGroupID, 0; id != 0 && depth < exposureMarketGroupMaxTreeDepth; depth++ {
+		g := data.MarketGroups[id]
+		if g == nil {
+			break
+		}
+		name = g.Name
+		id = g.ParentID
+	}
+	return name
+}
+
+// exposureCorrelations returns the pairs among the largest holdings whose
+// daily log returns correlate at minCorr or above, most correlated first.
+func exposureCorrelations(items []ExposureItem, history map[int32][]esi.HistoryEntry, minCorr float64) []ExposureCorrelation {
+	out := []ExposureCorrelation{}
+	n := min(len(items), exposureCorrelationMaxItems)
+	returns := make([]map[string]float64, n)
+	for i := 0; i < n; i++ {
+		returns[i] = dailyLogReturns(history[items[i].TypeID])
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			var a, b []float64
+			for date, ra := range returns[i] {
+				if rb, ok := returns[j][date]; ok {
+					a = append(a, ra)
+					b = append(b, rb)
+				}
+			}
+			if len(a) < exposureCorrelationMinDays {
+				continue
+			}
+			corr := pearsonCorrelation(a, b)
+			if corr < minCorr {
+				continue
+			}
+			out = append(out, ExposureCorrelation{
+				TypeA:            items[i].TypeID,
+				TypeB:            items[j].TypeID,
+				NameA:            items[i].TypeName,
+				NameB:            items[j].TypeName,
+				Correlation:      math.Round(corr*100) / 100,
+				Days:             len(a),
+				CombinedSharePct: math.Round((items[i].SharePct+items[j].SharePct)*100) / 100,
+			})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Correlation > out[j].Correlation })
+	return out
+}
+
+// dailyLogReturns maps each date to the log change of the daily average
+// from the previous entry, over the correlation window.
+func dailyLogReturns(history []esi.HistoryEntry) map[string]float64 {
+	entries := filterLastNDays(history, exposureCorrelationDays)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Date < entries[j].Date })
+	out := make(map[string]float64, len(entries))
+	for i := 1; i < len(entries); i++ {
+		prev, cur := entries[i-1].Average, entries[i].Average
+		if prev > 0 && cur > 0 {
+			out[entries[i].Date] = math.Log(cur / prev)
+		}
+	}
+	return out
+}
+
+func pearsonCorrelation(a, b []float64) float64 {
+	ma, mb := mean(a), mean(b)
+	var cov, va, vb float64
+	for i := range a {
+		da, db := a[i]-ma, b[i]-mb
+		cov += da * db
+		va += da * da
+		vb += db * db
+	}
+	if va <= 0 || vb <= 0 {
+		return 0
+	}
+	return cov / math.Sqrt(va*vb)
+}
+
+// rebalanceActions turns each limit breach into an action with the ISK to
+// move, largest first.
+func rebalanceActions(e PortfolioExposure, p ExposureParams) []RebalanceAction {
+	actions := []RebalanceAction{}
+	maxItem := e.TotalValue * p.MaxItemPct / 100
+	for _, it := range e.Items {
+		if it.Value > maxItem {
+			actions = append(actions, RebalanceAction{
+				Action: RebalanceTrim, TypeID: it.TypeID, TypeName: it.TypeName,
+				Value:  it.Value - maxItem,
+				Reason: "position is over the per-item limit of the portfolio",
+			})
+			if it.BuyEscrow > 0 {
+				actions = append(actions, RebalanceAction{
+					Action: RebalanceCancelBuys, TypeID: it.TypeID, TypeName: it.TypeName,
+					Value:  math.Min(it.BuyEscrow, it.Value-maxItem),
+					Reason: "open buy orders add to an oversized position",
+				})
+			}
+		}
+		if it.SharePct < exposureMinActionSharePct {
+			continue
+		}
+		if it.LiquidationDays < 0 || it.LiquidationDays > p.SlowDays {
+			reason := "no recent trade history to sell into"
+			if it.LiquidationDays > 0 {
+				reason = "stock takes longer than the slow-liquidation limit to sell"
+			}
+			actions = append(actions, RebalanceAction{
+				Action: RebalanceLiquidate, TypeID: it.TypeID, TypeName: it.TypeName,
+				Value: it.AssetValue + it.ListedValue, Reason: reason,
+			})
+		}
+		if it.Volatile {
+			// Halve volatile positions over the action threshold.
+			actions = append(actions, RebalanceAction{
+				Action: RebalanceDeRisk, TypeID: it.TypeID, TypeName: it.TypeName,
+				Value:  it.Value / 2,
+				Reason: "large position in an item with a high daily price range (DRVI)",
+			})
+		}
+	}
+	maxCategory := e.TotalValue * p.MaxCategoryPct / 100
+	for _, c := range e.Categories {
+		if c.Value > maxCategory && len(e.Categories) > 1 {
+			actions = append(actions, RebalanceAction{
+				Action: RebalanceDiversify, Category: c.Name,
+				Value:  c.Value - maxCategory,
+				Reason: "market group is over the per-category limit of the portfolio",
+			})
+		}
+	}
+	for _, c := range e.Correlations {
+		if excess := (c.CombinedSharePct - p.MaxItemPct) / 100 * e.TotalValue; excess > 0 {
+			actions = append(actions, RebalanceAction{
+				Action: RebalanceDecorrelate, TypeID: c.TypeA, TypeName: c.NameA + " / " + c.NameB,
+				Value:  excess,
+				Reason: "correlated items together exceed the per-item limit",
+			})
+		}
+	}
+	for i := range actions {
+		actions[i].Value = sanitizeFloat(math.Round(actions[i].Value))
+	}
+	sort.SliceStable(actions, func(i, j int) bool { return actions[i].Value > actions[j].Value })
+	return actions
+}
+
+func (p ExposureParams) withDefaults() ExposureParams {
+	if p.MarketSharePct <= 0 || p.MarketSharePct > 100 {
+		p.MarketSharePct = DefaultExposureMarketSharePct
+	}
+	if p.VolatileDRVI <= 0 {
+		p.VolatileDRVI = DefaultExposureVolatileDRVI
+	}
+	if p.MaxItemPct <= 0 || p.MaxItemPct > 100 {
+		p.MaxItemPct = DefaultExposureMaxItemPct
+	}
+	if p.MaxCategoryPct <= 0 || p.MaxCategoryPct > 100 {
+		p.MaxCategoryPct = DefaultExposureMaxCategoryPct
+	}
+	if p.SlowDays <= 0 {
+		p.SlowDays = DefaultExposureSlowDays
+	}
+	if p.Correlation <= 0 || p.Correlation > 1 {
+		p.Correlation = DefaultExposureCorrelation
+	}
+	return p
+}
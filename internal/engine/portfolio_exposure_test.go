@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/sde"
+)
+
+func exposureTestHistory(days int, volume int64, price func(i int) float64, rangePct float64) []esi.HistoryEntry {
+	now := time.Now().UTC()
+	out := make([]esi.HistoryEntry, 0, days)
+	for i := 0; i < days; i++ {
+		p, r := price(i), rangePct*float64(i%4)
+		out = append(out, esi.HistoryEntry{
+			Date:    now.AddDate(0, 0, i-days).Format("2006-01-02"),
+			Average: p,
+			Highest: p * (1 + r/200),
+			Lowest:  p * (1 - r/200),
+			Volume:  volume,
+		})
+	}
+	return out
+}
+
+func TestAnalyzePortfolioExposure(t *testing.T) {
+	data := &sde.Data{
+		Types: map[int32]*sde.ItemType{
+			1: {ID: 1, Name: "PLEX", MarketGroup: 11},
+			2: {ID: 2, Name: "Skill Injector", MarketGroup: 11},
+			3: {ID: 3, Name: "Tritanium", MarketGroup: 21},
+		},
+		MarketGroups: map[int32]*sde.MarketGroup{
+			10: {ID: 10, Name: "Special Edition Assets"},
+			11: {ID: 11, Name: "Pilot Services", ParentID: 10},
+			20: {ID: 20, Name: "Manufacture & Research"},
+			21: {ID: 21, Name: "Minerals", ParentID: 20},
+		},
+	}
+	wave := func(base float64) func(int) float64 {
+		return func(i int) float64 { return base * (1 + 0.05*math.Sin(float64(i))) }
+	}
+	in := ExposureInput{
+		Holdings: []ExposureHolding{
+			{TypeID: 1, Quantity: 100, Source: ExposureAsset},
+			{TypeID: 1, Quantity: 50, Source: ExposureSellOrder, UnitPrice: 5e6},
+			{TypeID: 2, Quantity: 10, Source: ExposureBuyOrder, UnitPrice: 1e6},
+			{TypeID: 3, Quantity: 1_000_000, Source: ExposureAsset},
+		},
+		Prices: map[int32]float64{1: 4e6, 3: 5},
+		History: map[int32][]esi.HistoryEntry{
+			// PLEX and injectors move together; PLEX swings wildly.
+			1: exposureTestHistory(60, 10, wave(4e6), 10),
+			2: exposureTestHistory(60, 100, wave(1e6), 2),
+			3: exposureTestHistory(60, 10_000_000, func(int) float64 { return 5 }, 1),
+		},
+	}
+	got := AnalyzePortfolioExposure(data, in, ExposureParams{})
+
+	// PLEX 400M + 250M listed, injector 10M escrow, tritanium 5M.
+	if got.TotalValue != 665e6 || got.BuyEscrow != 10e6 || got.ListedValue != 250e6 {
+		t.Fatalf("values = %v / %v / %v", got.TotalValue, got.BuyEscrow, got.ListedValue)
+	}
+	plex := got.Items[0]
+	if plex.TypeID != 1 || plex.Units != 150 || plex.Category != "Special Edition Assets" {
+		t.Fatalf("top item = %+v", plex)
+	}
+	// 150 units at 20% of 10/day.
+	if plex.LiquidationDays != 75 || !plex.Volatile {
+		t.Fatalf("plex liquidation/volatile = %v/%v", plex.LiquidationDays, plex.Volatile)
+	}
+	if got.Categories[0].Name != "Special Edition Assets" || got.TopCategorySharePct < 99 {
+		t.Fatalf("categories = %+v", got.Categories)
+	}
+	if len(got.Correlations) != 1 || got.Correlations[0].Correlation < 0.99 {
+		t.Fatalf("correlations = %+v", got.Correlations)
+	}
+
+	actions := make(map[string]bool)
+	for _, a := range got.Actions {
+		actions[a.Action] = true
+	}
+	for _, want := range []string{RebalanceTrim, RebalanceLiquidate, RebalanceDeRisk, RebalanceDiversify, RebalanceDecorrelate} {
+		if !actions[want] {
+			t.Fatalf("missing %s action in %+v", want, got.Actions)
+		}
+	}
+	if actions[RebalanceCancelBuys] {
+		t.Fatalf("injector buy orders are not oversized: %+v", got.Actions)
+	}
+	for i := 1; i < len(got.Actions); i++ {
+		if got.Actions[i].Value > got.Actions[i-1].Value {
+			t.Fatalf("actions not sorted by value: %+v", got.Actions)
+		}
+	}
+}
+
+func TestAnalyzePortfolioExposureEmpty(t *testing.T) {
+	got := AnalyzePortfolioExposure(nil, ExposureInput{}, ExposureParams{})
+	if got.TotalValue != 0 || got.Items == nil || got.Actions == nil {
+		t.Fatalf("empty exposure = %+v", got)
+	}
+}
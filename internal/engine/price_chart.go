@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+// DefaultChartPeriodWeeks is the indicator window used when none is given.
+const DefaultChartPeriodWeeks = 4
+
+// PriceCandle is one week of market history. Open and Close are the first and
+// last daily averages of the week.
+type PriceCandle struct {
+	Week   string  `json:"week"` // Monday, YYYY-MM-DD
+	Open   float64 `json:"open"`
+	High   float64 `json:"high"`
+	Low    float64 `json:"low"`
+	Close  float64 `json:"close"`
+	Volume int64   `json:"volume"`
+	VWAP   float64 `json:"vwap"`
+	Days   int     `json:"days"`
+}
+
+// ChartPoint is one value of an indicator series, keyed by candle week.
+type ChartPoint struct {
+	Week  string  `json:"week"`
+	Value float64 `json:"value"`
+}
+
+// PriceChart is market history resampled to weekly candles with indicator
+// series over a trailing window of PeriodWeeks candles.
+type PriceChart struct {
+	PeriodWeeks int           `json:"period_weeks"`
+	Candles     []PriceCandle `json:"candles"`
+	SMA         []ChartPoint  `json:"sma"`  // simple moving average of closes
+	EMA         []ChartPoint  `json:"ema"`  // exponential moving average of closes
+	VWAP        []ChartPoint  `json:"vwap"` // volume-weighted average price over the window
+	DRVI        []ChartPoint  `json:"drvi"` // StdDev of daily range % over the window's days
+}
+
+// weekStart returns the Monday of t's ISO week.
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset)
+}
+
+// BuildPriceChart resamples daily history into weekly candles and computes
+// SMA, EMA, VWAP and DRVI over periodWeeks candles. Series start at the first
+// full window; EMA is seeded with the first SMA.
+func BuildPriceChart(history []esi.HistoryEntry, periodWeeks int) PriceChart {
+	if periodWeeks <= 0 {
+		periodWeeks = DefaultChartPeriodWeeks
+	}
+	chart := PriceChart{
+		PeriodWeeks: periodWeeks,
+		Candles:     []PriceCandle{},
+		SMA:         []ChartPoint{},
+		EMA:         []ChartPoint{},
+		VWAP:        []ChartPoint{},
+		DRVI:        []ChartPoint{},
+	}
+
+	sorted := make([]esi.HistoryEntry, 0, len(history))
+	for _, h := range history {
+		if h.Average > 0 {
+			sorted = append(sorted, h)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+
+	// Daily range % per candle, for DRVI windows.
+	var ranges [][]float64
+	var priceVol []float64
+	for _, h := range sorted {
+		t, err := time.Parse("2006-01-02", h.Date)
+		if err != nil {
+			continue
+		}
+		week := weekStart(t).Format("2006-01-02")
+		n := len(chart.Candles)
+		if n == 0 || chart.Candles[n-1].Week != week {
+			chart.Candles = append(chart.Candles, PriceCandle{
+				Week: week, Open: h.Average, High: h.Highest, Low: h.Lowest,
+			})
+			ranges = append(ranges, nil)
+			priceVol = append(priceVol, 0)
+			n++
+		}
+		c := &chart.Candles[n-1]
+		c.High = math.Max(c.High, h.Highest)
+		if h.Lowest > 0 && (c.Low <= 0 || h.Lowest < c.Low) {
+			c.Low = h.Lowest
+		}
+		c.Close = h.Average
+		c.Volume += h.Volume
+		c.Days++
+		priceVol[n-1] += h.Average * float64(h.Volume)
+		ranges[n-1] = append(ranges[n-1], (h.Highest-h.Lowest)/h.Average*100)
+	}
+	for i := range chart.Candles {
+		if chart.Candles[i].Volume > 0 {
+			chart.Candles[i].VWAP = sanitizeFloat(priceVol[i] / float64(chart.Candles[i].Volume))
+		}
+	}
+
+	alpha := 2 / float64(periodWeeks+1)
+	ema := 0.0
+	for i := periodWeeks - 1; i < len(chart.Candles); i++ {
+		week := chart.Candles[i].Week
+		var sumClose, sumPV, sumVol float64
+		var windowRanges []float64
+		for j := i - periodWeeks + 1; j <= i; j++ {
+			sumClose += chart.Candles[j].Close
+			sumPV += priceVol[j]
+			sumVol += float64(chart.Candles[j].Volume)
+			windowRanges = append(windowRanges, ranges[j]...)
+		}
+		sma := sumClose / float64(periodWeeks)
+		if i == periodWeeks-1 {
+			ema = sma
+		} else {
+			ema = alpha*chart.Candles[i].Close + (1-alpha)*ema
+		}
+		chart.SMA = append(chart.SMA, ChartPoint{Week: week, Value: sanitizeFloat(sma)})
+		chart.EMA = append(chart.EMA, ChartPoint{Week: week, Value: sanitizeFloat(ema)})
+		if sumVol > 0 {
+			chart.VWAP = append(chart.VWAP, ChartPoint{Week: week, Value: sanitizeFloat(sumPV / sumVol)})
+		}
+		chart.DRVI = append(chart.DRVI, ChartPoint{Week: week, Value: sanitizeFloat(stdDev(windowRanges))})
+	}
+	return chart
+}
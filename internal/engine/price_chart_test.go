@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+func TestBuildPriceChart(t *testing.T) {
+	// Three full weeks starting Monday 2026-01-05; the average steps up by 10
+	// each week, with a constant 10% daily range and 100 units a day.
+	start := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	var history []esi.HistoryEntry
+	for d := 0; d < 21; d++ {
+		avg := 100 + float64(d/7)*10
+		history = append(history, esi.HistoryEntry{
+			Date:    start.AddDate(0, 0, d).Format("2006-01-02"),
+			Average: avg,
+			Highest: avg * 1.05,
+			Lowest:  avg * 0.95,
+			Volume:  100,
+		})
+	}
+
+	chart := BuildPriceChart(history, 2)
+	if len(chart.Candles) != 3 {
+		t.Fatalf("candles = %d, want 3", len(chart.Candles))
+	}
+	c := chart.Candles[1]
+	if c.Week != "2026-01-12" || c.Open != 110 || c.Close != 110 || c.Volume != 700 || c.Days != 7 || c.VWAP != 110 {
+		t.Fatalf("week 2 candle = %+v", c)
+	}
+	if math.Abs(c.High-115.5) > 1e-9 || math.Abs(c.Low-104.5) > 1e-9 {
+		t.Fatalf("week 2 high/low = %v/%v", c.High, c.Low)
+	}
+
+	if len(chart.SMA) != 2 || chart.SMA[0].Value != 105 || chart.SMA[1].Value != 115 {
+		t.Fatalf("SMA = %+v", chart.SMA)
+	}
+	// EMA seeded with SMA 105, then alpha 2/3 toward close 120.
+	if len(chart.EMA) != 2 || chart.EMA[0].Value != 105 || math.Abs(chart.EMA[1].Value-115) > 1e-9 {
+		t.Fatalf("EMA = %+v", chart.EMA)
+	}
+	if len(chart.VWAP) != 2 || chart.VWAP[1].Value != 115 {
+		t.Fatalf("VWAP = %+v", chart.VWAP)
+	}
+	if len(chart.DRVI) != 2 || chart.DRVI[0].Value > 1e-9 {
+		t.Fatalf("DRVI = %+v, want ~0 for constant ranges", chart.DRVI)
+	}
+}
@@ -0,0 +1,141 @@
+package engine
+
+import "sort"
+
+// RadiusScanSample is one stored radius scan: its radii and results.
+type RadiusScanSample struct {
+	BuyRadius  int
+	SellRadius int
+	Results    []FlipResult
+}
+
+// RadiusSuggestParams configures SuggestScanRadius.
+type RadiusSuggestParams struct {
+	MinutesPerJump float64 // travel time per jump
+	DockMinutes    float64 // per dock, two per trip
+	TopN           int     // best trips per scan a pilot would realistically fly
+	// ShareOfBest is how close to the best ISK/hour a smaller radius must
+	// come to be preferred over it.
+	ShareOfBest float64
+}
+
+// RadiusStat is the historical yield of one radius pair.
+type RadiusStat struct {
+	BuyRadius  int     `json:"buy_radius"`
+	SellRadius int     `json:"sell_radius"`
+	Scans      int     `json:"scans"`
+	AvgResults float64 `json:"avg_results"`
+	ISKPerHour float64 `json:"isk_per_hour"`
+	// MarginalGainPercent is the ISK/hour change over the next smaller radius.
+	MarginalGainPercent float64 `json:"marginal_gain_percent"`
+}
+
+// RadiusSuggestion is the diminishing-returns analysis of stored scans.
+type RadiusSuggestion struct {
+	Stats     []RadiusStat `json:"stats"` // smallest radius first
+	Best      *RadiusStat  `json:"best"`
+	Suggested *RadiusStat  `json:"suggested"`
+}
+
+// SuggestScanRadius estimates the ISK/hour each radius pair yielded in
+// stored scans (mean of the top trips, at jump and dock times) and
+// suggests the smallest radius within ShareOfBest of the best one: past
+// that point a wider radius adds travel without adding much profit.
+func SuggestScanRadius(samples []RadiusScanSample, p RadiusSuggestParams) RadiusSuggestion {
+	if p.MinutesPerJump <= 0 {
+		p.MinutesPerJump = 2
+	}
+	if p.DockMinutes < 0 {
+		p.DockMinutes = 0
+	}
+	if p.TopN <= 0 {
+		p.TopN = 5
+	}
+	if p.ShareOfBest <= 0 || p.ShareOfBest > 1 {
+		p.ShareOfBest = 0.95
+	}
+
+	type agg struct {
+		scans   int
+		results int
+		iskHour float64
+	}
+	byRadius := make(map[[2]int]*agg)
+	for _, s := range samples {
+		k := [2]int{s.BuyRadius, s.SellRadius}
+		a := byRadius[k]
+		if a == nil {
+			a = &agg{}
+			byRadius[k] = a
+		}
+		a.scans++
+		a.results += len(s.Results)
+		a.iskHour += topTripsISKPerHour(s.Results, p)
+	}
+
+	out := RadiusSuggestion{Stats: make([]RadiusStat, 0, len(byRadius))}
+	for k, a := range byRadius {
+		out.Stats = append(out.Stats, RadiusStat{
+			BuyRadius:  k[0],
+			SellRadius: k[1],
+			Scans:      a.scans,
+			AvgResults: float64(a.results) / float64(a.scans),
+			ISKPerHour: sanitizeFloat(a.iskHour / float64(a.scans)),
+		})
+	}
+	sort.Slice(out.Stats, func(i, j int) bool {
+		a, b := out.Stats[i], out.Stats[j]
+		if sa, sb := a.BuyRadius+a.SellRadius, b.BuyRadius+b.SellRadius; sa != sb {
+			return sa < sb
+		}
+		return a.BuyRadius < b.BuyRadius
+	})
+	best := -1
+	for i := range out.Stats {
+		st := &out.Stats[i]
+		if i > 0 && out.Stats[i-1].ISKPerHour > 0 {
+			prev := out.Stats[i-1].ISKPerHour
+			st.MarginalGainPercent = sanitizeFloat((st.ISKPerHour - prev) / prev * 100)
+		}
+		if best < 0 || st.ISKPerHour > out.Stats[best].ISKPerHour {
+			best = i
+		}
+	}
+	if best < 0 || out.Stats[best].ISKPerHour <= 0 {
+		return out
+	}
+	out.Best = &out.Stats[best]
+	for i := range out.Stats {
+		if out.Stats[i].ISKPerHour >= out.Best.ISKPerHour*p.ShareOfBest {
+			out.Suggested = &out.Stats[i]
+			break
+		}
+	}
+	return out
+}
+
+// topTripsISKPerHour is the mean ISK/hour of the TopN best trips of a scan.
+func topTripsISKPerHour(results []FlipResult, p RadiusSuggestParams) float64 {
+	rates := make([]float64, 0, len(results))
+	for _, r := range results {
+		profit := FlipResultKPIProfit(r)
+		if profit <= 0 {
+			continue
+		}
+		minutes := float64(max(r.TotalJumps, 0))*p.MinutesPerJump + 2*p.DockMinutes
+		if minutes <= 0 {
+			minutes = p.MinutesPerJump
+		}
+		rates = append(rates, profit/minutes*60)
+	}
+	if len(rates) == 0 {
+		return 0
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(rates)))
+	n := min(p.TopN, len(rates))
+	sum := 0.0
+	for _, v := range rates[:n] {
+		sum += v
+	}
+	return sum / float64(n)
+}
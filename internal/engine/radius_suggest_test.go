@@ -0,0 +1,40 @@
+package engine
+
+import "testing"
+
+func TestSuggestScanRadius(t *testing.T) {
+	trip := func(profit float64, jumps int) FlipResult {
+		return FlipResult{RealProfit: profit, TotalJumps: jumps}
+	}
+	samples := []RadiusScanSample{
+		// 1M over 3 jumps at 2 min/jump + 2 docks of 1 min = 8 min -> 7.5M/h.
+		{BuyRadius: 3, SellRadius: 3, Results: []FlipResult{trip(1_000_000, 3)}},
+		{BuyRadius: 3, SellRadius: 3, Results: []FlipResult{trip(1_000_000, 3), trip(-5, 1)}},
+		// 2.1M over 6 jumps = 14 min -> 9M/h.
+		{BuyRadius: 5, SellRadius: 5, Results: []FlipResult{trip(2_100_000, 6)}},
+		// 3.6M over 12 jumps = 26 min -> ~8.3M/h: wider, longer, not better.
+		{BuyRadius: 10, SellRadius: 10, Results: []FlipResult{trip(3_600_000, 12)}},
+	}
+	p := RadiusSuggestParams{MinutesPerJump: 2, DockMinutes: 1, TopN: 1, ShareOfBest: 0.95}
+
+	got := SuggestScanRadius(samples, p)
+	if len(got.Stats) != 3 || got.Stats[0].Scans != 2 || got.Stats[0].ISKPerHour != 7_500_000 {
+		t.Fatalf("stats = %+v", got.Stats)
+	}
+	if got.Stats[1].MarginalGainPercent != 20 || got.Stats[2].MarginalGainPercent >= 0 {
+		t.Fatalf("marginal gains = %+v", got.Stats)
+	}
+	if got.Best == nil || got.Best.BuyRadius != 5 || got.Suggested == nil || got.Suggested.BuyRadius != 5 {
+		t.Fatalf("best = %+v, suggested = %+v", got.Best, got.Suggested)
+	}
+
+	// Within 80% of the best, the smallest radius wins.
+	p.ShareOfBest = 0.8
+	if got = SuggestScanRadius(samples, p); got.Suggested == nil || got.Suggested.BuyRadius != 3 {
+		t.Fatalf("suggested = %+v", got.Suggested)
+	}
+
+	if got = SuggestScanRadius(nil, p); got.Suggested != nil || len(got.Stats) != 0 {
+		t.Fatalf("empty suggestion = %+v", got)
+	}
+}
@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// RegionFetchFailure is one region order fetch that did not complete.
+type RegionFetchFailure struct {
+	RegionID  int32  `json:"region_id"`
+	OrderType string `json:"order_type"`
+	Error     string `json:"error"`
+}
+
+// RegionFetchReport collects failed region fetches of one scan. Safe for
+// concurrent use.
+type RegionFetchReport struct {
+	mu       sync.Mutex
+	failures []RegionFetchFailure
+}
+
+func (r *RegionFetchReport) add(regionID int32, orderType string, err error) {
+	if r == nil {
+		return
+	}
+	msg := err.Error()
+	if errors.Is(err, context.DeadlineExceeded) {
+		msg = "timed out"
+	}
+	r.mu.Lock()
+	r.failures = append(r.failures, RegionFetchFailure{RegionID: regionID, OrderType: orderType, Error: msg})
+	r.mu.Unlock()
+}
+
+// Failures returns the recorded failures.
+func (r *RegionFetchReport) Failures() []RegionFetchFailure {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RegionFetchFailure{}, r.failures...)
+}
+
+// FailedRegions returns the distinct regions with at least one failed fetch,
+// sorted by ID.
+func (r *RegionFetchReport) FailedRegions() []int32 {
+	seen := make(map[int32]bool)
+	var out []int32
+	for _, f := range r.Failures() {
+		if !seen[f.RegionID] {
+			seen[f.RegionID] = true
+			out = append(out, f.RegionID)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// Partial reports whether any region failed to load.
+func (r *RegionFetchReport) Partial() bool {
+	return len(r.Failures()) > 0
+}
+
+// checkRegionFailures returns an error when more regions failed than
+// params.MaxRegionFailures allows, and reports tolerated failures through
+// progress.
+func checkRegionFailures(params ScanParams, progress func(string)) error {
+	return params.FetchReport.Check(params.MaxRegionFailures, progress)
+}
+
+// Check returns an error when more regions failed than maxFailures allows
+// (nil = no limit), and reports tolerated failures through progress.
+func (r *RegionFetchReport) Check(maxFailures *int, progress func(string)) error {
+	failed := r.FailedRegions()
+	if len(failed) == 0 {
+		return nil
+	}
+	if maxFailures != nil && len(failed) > *maxFailures {
+		return fmt.Errorf("%d region(s) failed to load (tolerance %d): %v", len(failed), *maxFailures, failed)
+	}
+	if progress != nil {
+		progress(fmt.Sprintf("Warning: %d region(s) failed to load, results are partial", len(failed)))
+	}
+	return nil
+}
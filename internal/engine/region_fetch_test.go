@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFetchOrdersStream_RecordsTimedOutRegions(t *testing.T) {
+	scanner := &Scanner{}
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	report := &RegionFetchReport{}
+	stream := scanner.fetchOrdersStream(ctx, map[int32]bool{10000043: true, 10000002: true}, "sell", nil, report)
+	for range stream {
+	}
+
+	got := report.FailedRegions()
+	if len(got) != 2 || got[0] != 10000002 || got[1] != 10000043 {
+		t.Fatalf("FailedRegions = %v, want [10000002 10000043]", got)
+	}
+	for _, f := range report.Failures() {
+		if f.Error != "timed out" || f.OrderType != "sell" {
+			t.Fatalf("unexpected failure: %+v", f)
+		}
+	}
+}
+
+func TestCheckRegionFailures(t *testing.T) {
+	report := &RegionFetchReport{}
+	report.add(10000002, "sell", errors.New("502"))
+	report.add(10000002, "buy", errors.New("502"))
+	report.add(10000043, "sell", errors.New("502"))
+
+	var warned string
+	progress := func(msg string) { warned = msg }
+
+	if err := checkRegionFailures(ScanParams{FetchReport: report}, progress); err != nil {
+		t.Fatalf("no limit: unexpected error %v", err)
+	}
+	if warned == "" {
+		t.Fatalf("tolerated failures should be reported through progress")
+	}
+
+	two, one := 2, 1
+	if err := checkRegionFailures(ScanParams{FetchReport: report, MaxRegionFailures: &two}, progress); err != nil {
+		t.Fatalf("2 failed regions within tolerance 2: %v", err)
+	}
+	if err := checkRegionFailures(ScanParams{FetchReport: report, MaxRegionFailures: &one}, progress); err == nil {
+		t.Fatalf("2 failed regions over tolerance 1 should fail the scan")
+	}
+
+	if (&RegionFetchReport{}).Partial() || !report.Partial() {
+		t.Fatalf("Partial mismatch")
+	}
+}
@@ -126,21 +126,10 @@ func (s *Scanner) historyEntries(regionID int32, typeID int32) []esi.HistoryEntr
 	if regionID <= 0 || typeID <= 0 {
 		return nil
 	}
-	if s.History != nil {
-		if entries, ok := s.History.GetMarketHistory(regionID, typeID); ok {
-			return entries
-		}
-	}
-	if s.ESI == nil {
-		return nil
-	}
-	entries, err := s.ESI.FetchMarketHistory(regionID, typeID)
+	entries, err := s.MarketHistory(regionID, typeID)
 	if err != nil {
 		return nil
 	}
-	if s.History != nil {
-		s.History.SetMarketHistory(regionID, typeID, entries)
-	}
 	return entries
 }
 
@@ -168,15 +157,18 @@ func (s *Scanner) BuildRegionalDayTrader(
 		targetRegionName = s.regionName(targetRegionID)
 	}
 
-	buyCostMult, sellRevenueMult := tradeFeeMultipliers(tradeFeeInputs{
-		SplitTradeFees:       params.SplitTradeFees,
-		BrokerFeePercent:     params.BrokerFeePercent,
-		SalesTaxPercent:      params.SalesTaxPercent,
-		BuyBrokerFeePercent:  params.BuyBrokerFeePercent,
-		SellBrokerFeePercent: params.SellBrokerFeePercent,
-		BuySalesTaxPercent:   params.BuySalesTaxPercent,
-		SellSalesTaxPercent:  params.SellSalesTaxPercent,
-	})
+	fees := tradeFees{
+		in: tradeFeeInputs{
+			SplitTradeFees:       params.SplitTradeFees,
+			BrokerFeePercent:     params.BrokerFeePercent,
+			SalesTaxPercent:      params.SalesTaxPercent,
+			BuyBrokerFeePercent:  params.BuyBrokerFeePercent,
+			SellBrokerFeePercent: params.SellBrokerFeePercent,
+			BuySalesTaxPercent:   params.BuySalesTaxPercent,
+			SellSalesTaxPercent:  params.SellSalesTaxPercent,
+		},
+		brokers: params.BrokerFees,
+	}
 
 	needed := make(map[regionalHistoryKey]bool)
 	for _, row := range flips {
@@ -334,6 +326,7 @@ func (s *Scanner) BuildRegionalDayTrader(
 		if row.TypeID <= 0 || row.UnitsToBuy <= 0 {
 			continue
 		}
+		buyCostMult, sellRevenueMult := fees.multipliers(row.BuyLocationID, row.SellLocationID)
 
 		rejectionReason := ""
 		setRejection := func(reason string) {
@@ -482,10 +475,10 @@ func (s *Scanner) BuildRegionalDayTrader(
 				}
 			}
 		}
-		if params.MaxInvestment > 0 {
+		if capital := params.capitalLimit(); capital > 0 {
 			effectiveUnitCost := sourceAvgPrice * buyCostMult
 			if effectiveUnitCost > 0 {
-				maxByCapital := int32(params.MaxInvestment / effectiveUnitCost)
+				maxByCapital := int32(capital / effectiveUnitCost)
 				if maxByCapital <= 0 {
 					if !params.RegionalDiagnosticMode {
 						continue
@@ -664,7 +657,7 @@ func (s *Scanner) BuildRegionalDayTrader(
 			MinDemandPerDay:     params.MinDemandPerDay,
 			MinItemProfit:       params.MinItemProfit,
 			MaxDOS:              params.MaxDOS,
-			MaxInvestment:       params.MaxInvestment,
+			MaxInvestment:       params.capitalLimit(),
 		})
 
 		tradeScore := computeTradeScore(regionalTradeScoreInput{
@@ -248,6 +248,44 @@ func TestBuildRegionalDayTrader_PurchaseUnitsBehaviorByRevenueMode(t *testing.T)
 			t.Fatalf("purchase_units = %d, want 10 with cargo cap in sell-order mode", got)
 		}
 	})
+
+	t.Run("max_budget_caps_units_without_rejecting", func(t *testing.T) {
+		hubs, totalItems, _, _ := scanner.BuildRegionalDayTrader(
+			ScanParams{
+				AvgPricePeriod:     14,
+				PurchaseDemandDays: 0.5,
+				SellOrderMode:      true,
+				MaxBudget:          1_500, // source avg 100 => max 15 units
+			},
+			flips,
+			nil,
+			nil,
+		)
+		if len(hubs) != 1 || totalItems != 1 {
+			t.Fatalf("unexpected shape: hubs=%d items=%d", len(hubs), totalItems)
+		}
+		if got := hubs[0].Items[0].PurchaseUnits; got != 15 {
+			t.Fatalf("purchase_units = %d, want 15 with max budget", got)
+		}
+	})
+}
+
+func TestScanParamsCapitalLimit(t *testing.T) {
+	cases := []struct {
+		investment, budget, want float64
+	}{
+		{0, 0, 0},
+		{500, 0, 500},
+		{0, 300, 300},
+		{500, 300, 300},
+		{200, 300, 200},
+	}
+	for _, tc := range cases {
+		got := ScanParams{MaxInvestment: tc.investment, MaxBudget: tc.budget}.capitalLimit()
+		if got != tc.want {
+			t.Errorf("capitalLimit(investment=%v, budget=%v) = %v, want %v", tc.investment, tc.budget, got, tc.want)
+		}
+	}
 }
 
 func TestBuildRegionalDayTrader_MinItemProfitFiltersRows(t *testing.T) {
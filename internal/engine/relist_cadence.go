@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Relist cadence attention levels, from most to least demanding.
+const (
+	CadenceBabysit = "babysit" // top of book changes more than twice an hour
+	CadenceHourly  = "hourly"
+	CadenceDaily   = "daily"
+	CadenceIdle    = "idle"
+)
+
+// relistCadenceMinChanges is how many top-of-book changes are needed before
+// an interval median means anything.
+const relistCadenceMinChanges = 3
+
+// TopOfBookPoint is the best price of one side of a book in one snapshot.
+type TopOfBookPoint struct {
+	At    time.Time
+	Price float64
+}
+
+// RelistCadence summarises how often the best price of a book moves.
+type RelistCadence struct {
+	Side           string  `json:"side"`
+	Samples        int     `json:"samples"`
+	ObservedHours  float64 `json:"observed_hours"`
+	Changes        int     `json:"changes"`
+	PennyChanges   int     `json:"penny_changes"` // undercuts/outbids by one price tick
+	MedianMinutes  float64 `json:"median_minutes"`
+	P25Minutes     float64 `json:"p25_minutes"`
+	ChangesPerHour float64 `json:"changes_per_hour"`
+	PennyWar       bool    `json:"penny_war"`
+	Attention      string  `json:"attention"`
+	LastChangeAt   string  `json:"last_change_at,omitempty"`
+}
+
+// PriceTick returns the smallest price step EVE allows at price: four
+// significant digits, never below 0.01 ISK.
+func PriceTick(price float64) float64 {
+	if price <= 0 {
+		return 0.01
+	}
+	tick := math.Pow(10, math.Floor(math.Log10(price))-3)
+	if tick < 0.01 {
+		tick = 0.01
+	}
+	return tick
+}
+
+// EstimateRelistCadence measures the minutes between top-of-book changes
+// across snapshots of one side ("sell" or "buy"). A change that beats the
+// previous best by a single tick is a penny change; a book where most
+// changes are pennies and the median gap is under an hour is a 0.01 ISK
+// war.
+func EstimateRelistCadence(points []TopOfBookPoint, side string, now time.Time) RelistCadence {
+	c := RelistCadence{Side: side, Samples: len(points), Attention: CadenceIdle}
+	if len(points) == 0 {
+		return c
+	}
+	sorted := append([]TopOfBookPoint(nil), points...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].At.Before(sorted[j].At) })
+	c.ObservedHours = math.Round(now.Sub(sorted[0].At).Hours()*10) / 10
+
+	var changes []time.Time
+	for i := 1; i < len(sorted); i++ {
+		prev, cur := sorted[i-1].Price, sorted[i].Price
+		if cur == prev {
+			continue
+		}
+		changes = append(changes, sorted[i].At)
+		better := cur < prev
+		if side == "buy" {
+			better = cur > prev
+		}
+		if better && math.Abs(cur-prev) <= PriceTick(prev)*1.0001 {
+			c.PennyChanges++
+		}
+	}
+	c.Changes = len(changes)
+	if c.Changes > 0 {
+		c.LastChangeAt = changes[len(changes)-1].UTC().Format(time.RFC3339)
+	}
+	if c.ObservedHours > 0 {
+		c.ChangesPerHour = math.Round(float64(c.Changes)/c.ObservedHours*100) / 100
+	}
+	if c.Changes < relistCadenceMinChanges {
+		if c.Changes > 0 {
+			c.Attention = CadenceDaily
+		}
+		return c
+	}
+
+	gaps := make([]float64, 0, len(changes)-1)
+	for i := 1; i < len(changes); i++ {
+		gaps = append(gaps, changes[i].Sub(changes[i-1]).Minutes())
+	}
+	sort.Float64s(gaps)
+	c.MedianMinutes = math.Round(percentile(gaps, 50)*10) / 10
+	c.P25Minutes = math.Round(percentile(gaps, 25)*10) / 10
+	c.PennyWar = c.PennyChanges*2 >= c.Changes && c.MedianMinutes < 60
+
+	switch {
+	case c.MedianMinutes < 30:
+		c.Attention = CadenceBabysit
+	case c.MedianMinutes < 180:
+		c.Attention = CadenceHourly
+	default:
+		c.Attention = CadenceDaily
+	}
+	return c
+}
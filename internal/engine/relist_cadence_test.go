@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPriceTick(t *testing.T) {
+	for _, tt := range []struct{ price, want float64 }{
+		{4.5, 0.01}, {123.45, 0.1}, {1234567, 1000}, {0, 0.01},
+	} {
+		if got := PriceTick(tt.price); got < tt.want*0.999 || got > tt.want*1.001 {
+			t.Fatalf("PriceTick(%v) = %v, want %v", tt.price, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateRelistCadence(t *testing.T) {
+	t0 := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(min int, price float64) TopOfBookPoint {
+		return TopOfBookPoint{At: t0.Add(time.Duration(min) * time.Minute), Price: price}
+	}
+
+	// Penny undercuts every 10-20 minutes, one snapshot without a change.
+	war := []TopOfBookPoint{at(0, 100), at(10, 99.99), at(20, 99.99), at(30, 99.98), at(40, 99.97), at(60, 99.96), at(70, 95)}
+	c := EstimateRelistCadence(war, "sell", t0.Add(2*time.Hour))
+	if c.Changes != 5 || c.PennyChanges != 4 || c.MedianMinutes != 15 || c.P25Minutes != 10 {
+		t.Fatalf("cadence = %+v", c)
+	}
+	if !c.PennyWar || c.Attention != CadenceBabysit || c.ObservedHours != 2 || c.ChangesPerHour != 2.5 {
+		t.Fatalf("cadence = %+v", c)
+	}
+	if c.LastChangeAt != "2026-06-01T13:10:00Z" {
+		t.Fatalf("last change = %s", c.LastChangeAt)
+	}
+
+	// On the buy side the same moves are price drops, not outbids.
+	if b := EstimateRelistCadence(war, "buy", t0.Add(2*time.Hour)); b.PennyChanges != 0 || b.PennyWar {
+		t.Fatalf("buy cadence = %+v", b)
+	}
+
+	quiet := []TopOfBookPoint{at(0, 100), at(300, 98)}
+	if q := EstimateRelistCadence(quiet, "sell", t0.Add(10*time.Hour)); q.Attention != CadenceDaily || q.MedianMinutes != 0 {
+		t.Fatalf("quiet cadence = %+v", q)
+	}
+	if e := EstimateRelistCadence(nil, "sell", t0); e.Attention != CadenceIdle {
+		t.Fatalf("empty cadence = %+v", e)
+	}
+}
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"math"
+
+	"eve-flipper/internal/esi"
+)
+
+// Order desk repricing strategies.
+const (
+	// RepriceStrategyPenny suggests best ±0.01 ISK, the legacy behaviour.
+	RepriceStrategyPenny = "penny"
+	// RepriceStrategyTick suggests one valid price tick past the best price.
+	RepriceStrategyTick = "tick"
+	// RepriceStrategySmart suggests the least aggressive tick-valid price
+	// whose queue still fills within the target ETA.
+	RepriceStrategySmart = "smart"
+)
+
+// NormalizeRepriceStrategy returns a known strategy, defaulting to penny.
+func NormalizeRepriceStrategy(s string) string {
+	switch s {
+	case RepriceStrategyTick, RepriceStrategySmart:
+		return s
+	}
+	return RepriceStrategyPenny
+}
+
+// TickBeat returns the closest valid price that beats price by one tick: the
+// next lower tick for a sell order, the next higher for a buy order. The
+// tick is taken on the side of the new price, so beating a sell at 1,000 ISK
+// gives 999.9, not 999. A price off the tick grid is snapped to it first.
+func TickBeat(price float64, isBuy bool) float64 {
+	if isBuy {
+		tick := PriceTick(price)
+		base := math.Floor(price/tick+1e-9) * tick
+		return roundCents(base + tick)
+	}
+	tick := PriceTick(math.Max(price-0.01, 0.01))
+	base := math.Ceil(price/tick-1e-9) * tick
+	return math.Max(0.01, roundCents(base-tick))
+}
+
+func roundCents(price float64) float64 {
+	return math.Round(price*100) / 100
+}
+
+// priceLevel is the volume resting at one price.
+type priceLevel struct {
+	price  float64
+	volume int64
+}
+
+// bookLevels groups a book sorted best-first into price levels, leaving out
+// the order with skipOrderID.
+func bookLevels(sorted []esi.MarketOrder, skipOrderID int64) []priceLevel {
+	var levels []priceLevel
+	for _, o := range sorted {
+		if o.OrderID == skipOrderID {
+			continue
+		}
+		if n := len(levels); n > 0 && levels[n-1].price == o.Price {
+			levels[n-1].volume += int64(o.VolumeRemain)
+			continue
+		}
+		levels = append(levels, priceLevel{price: o.Price, volume: int64(o.VolumeRemain)})
+	}
+	return levels
+}
+
+// smartRepricePrice picks a price for an order not at the top of the book.
+// Each candidate beats one of the levels ahead of the order by a tick and
+// queues behind the levels better than it; its ETA is that queue plus the
+// order's own volume over fillPerDay. The least aggressive candidate within
+// targetETA wins, so a thin level is jumped only when the queue behind it
+// would be too slow; if none is fast enough the top of the book is taken.
+// It returns the price, its queue ahead and ETA (-1 when fillPerDay is 0).
+func smartRepricePrice(levels []priceLevel, current float64, isBuy bool, remain int32, fillPerDay, targetETA float64) (float64, int64, float64) {
+	if len(levels) == 0 {
+		return current, 0, -1
+	}
+	eta := func(ahead int64) float64 {
+		if fillPerDay <= 0 {
+			return -1
+		}
+		return (float64(ahead) + float64(remain)) / fillPerDay
+	}
+	bestPrice, bestAhead := TickBeat(levels[0].price, isBuy), int64(0)
+	if fillPerDay <= 0 {
+		return bestPrice, 0, -1
+	}
+	var ahead int64
+	for k, lvl := range levels {
+		if !orderDeskBetterPrice(isBuy, lvl.price, current) {
+			break
+		}
+		if k > 0 {
+			ahead += levels[k-1].volume
+		}
+		if eta(ahead) > targetETA {
+			break
+		}
+		bestPrice, bestAhead = TickBeat(lvl.price, isBuy), ahead
+	}
+	return bestPrice, bestAhead, eta(bestAhead)
+}
@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"math"
+	"testing"
+
+	"eve-flipper/internal/esi"
+)
+
+func TestTickBeat(t *testing.T) {
+	cases := []struct {
+		price float64
+		buy   bool
+		want  float64
+	}{
+		{100, false, 99.99},
+		{1000, false, 999.9},
+		{1234567, false, 1234000},
+		{999.9, true, 1000},
+		{1234567, true, 1235000},
+		{0.01, false, 0.01},
+	}
+	for _, c := range cases {
+		if got := TickBeat(c.price, c.buy); math.Abs(got-c.want) > 1e-6 {
+			t.Errorf("TickBeat(%v, %v) = %v, want %v", c.price, c.buy, got, c.want)
+		}
+	}
+}
+
+func TestComputeOrderDesk_SmartRepriceSkipsThinLevel(t *testing.T) {
+	player := []esi.CharacterOrder{{
+		OrderID: 1001, TypeID: 34, LocationID: 60003760, RegionID: 10000002,
+		Price: 1200, VolumeRemain: 10, VolumeTotal: 10, Duration: 90,
+	}}
+	regional := []esi.MarketOrder{
+		{OrderID: 2001, TypeID: 34, LocationID: 60003760, Price: 1000, VolumeRemain: 2},
+		{OrderID: 2002, TypeID: 34, LocationID: 60003760, Price: 1100, VolumeRemain: 50},
+		{OrderID: 2003, TypeID: 34, LocationID: 60003760, Price: 1150, VolumeRemain: 5},
+		{OrderID: 1001, TypeID: 34, LocationID: 60003760, Price: 1200, VolumeRemain: 10},
+	}
+	history := map[OrderDeskHistoryKey][]esi.HistoryEntry{
+		NewOrderDeskHistoryKey(10000002, 34): {
+			{Date: "2026-02-01", Volume: 10},
+			{Date: "2026-02-02", Volume: 10},
+			{Date: "2026-02-03", Volume: 10},
+			{Date: "2026-02-04", Volume: 10},
+			{Date: "2026-02-05", Volume: 10},
+			{Date: "2026-02-06", Volume: 10},
+			{Date: "2026-02-07", Volume: 10},
+		},
+	}
+	opt := OrderDeskOptions{TargetETADays: 2, RepriceStrategy: RepriceStrategySmart}
+
+	row := ComputeOrderDesk(player, regional, history, nil, opt).Orders[0]
+	// Beating 1100 still queues behind 2 units; 1.2 days is within target,
+	// so the 50-unit wall at 1100 is jumped but the 2 units at 1000 are not.
+	if math.Abs(row.SuggestedPrice-1099) > 1e-6 {
+		t.Fatalf("suggested_price = %v, want 1099", row.SuggestedPrice)
+	}
+	if row.SuggestedQueueAhead != 2 || math.Abs(row.SuggestedETADays-1.2) > 1e-6 {
+		t.Fatalf("suggested queue/eta = %d/%v, want 2/1.2", row.SuggestedQueueAhead, row.SuggestedETADays)
+	}
+
+	opt.RepriceStrategy = RepriceStrategyTick
+	row = ComputeOrderDesk(player, regional, history, nil, opt).Orders[0]
+	if math.Abs(row.SuggestedPrice-999.9) > 1e-6 {
+		t.Fatalf("tick suggested_price = %v, want 999.9", row.SuggestedPrice)
+	}
+
+	opt.RepriceStrategy = ""
+	got := ComputeOrderDesk(player, regional, history, nil, opt)
+	if got.Settings.RepriceStrategy != RepriceStrategyPenny || math.Abs(got.Orders[0].SuggestedPrice-999.99) > 1e-6 {
+		t.Fatalf("penny = %q/%v, want penny/999.99", got.Settings.RepriceStrategy, got.Orders[0].SuggestedPrice)
+	}
+}
@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math"
@@ -263,15 +264,18 @@ func (s *Scanner) findBestTradesFromSources(
 	params RouteParams,
 	topN int,
 ) []RouteHop {
-	buyCostMult, sellRevenueMult := tradeFeeMultipliers(tradeFeeInputs{
-		SplitTradeFees:       params.SplitTradeFees,
-		BrokerFeePercent:     params.BrokerFeePercent,
-		SalesTaxPercent:      params.SalesTaxPercent,
-		BuyBrokerFeePercent:  params.BuyBrokerFeePercent,
-		SellBrokerFeePercent: params.SellBrokerFeePercent,
-		BuySalesTaxPercent:   params.BuySalesTaxPercent,
-		SellSalesTaxPercent:  params.SellSalesTaxPercent,
-	})
+	fees := tradeFees{
+		in: tradeFeeInputs{
+			SplitTradeFees:       params.SplitTradeFees,
+			BrokerFeePercent:     params.BrokerFeePercent,
+			SalesTaxPercent:      params.SalesTaxPercent,
+			BuyBrokerFeePercent:  params.BuyBrokerFeePercent,
+			SellBrokerFeePercent: params.SellBrokerFeePercent,
+			BuySalesTaxPercent:   params.BuySalesTaxPercent,
+			SellSalesTaxPercent:  params.SellSalesTaxPercent,
+		},
+		brokers: params.BrokerFees,
+	}
 
 	type candidate struct {
 		hop   RouteHop
@@ -310,6 +314,17 @@ func (s *Scanner) findBestTradesFromSources(
 					continue
 				}
 			}
+			// Budget cap uses the buy-side fee; the sell side varies per destination.
+			buyCostMult, _ := fees.multipliers(sell.LocationID, 0)
+			if params.MaxBudget > 0 {
+				maxAfford := math.Floor(params.MaxBudget / (sell.Price * buyCostMult))
+				if maxAfford < 1 {
+					continue
+				}
+				if maxAfford < float64(units) {
+					units = int32(maxAfford)
+				}
+			}
 			askBook := idx.sellOrdersByLocation[routeBookKey{
 				systemID:   source.systemID,
 				typeID:     typeID,
@@ -347,6 +362,7 @@ func (s *Scanner) findBestTradesFromSources(
 					if len(bidBook) == 0 {
 						continue
 					}
+					buyCostMult, sellRevenueMult := fees.multipliers(sell.LocationID, buy.LocationID)
 
 					safeQty, planBuy, planSell, expectedProfit := findSafeExecutionQuantity(
 						askBook,
@@ -439,6 +455,15 @@ func (s *Scanner) findBestTradesFromSources(
 
 // FindRoutes finds the most profitable multi-hop trade routes using beam search.
 func (s *Scanner) FindRoutes(params RouteParams, progress func(string)) ([]RouteResult, error) {
+	return s.FindRoutesWithContext(context.Background(), params, progress)
+}
+
+// FindRoutesWithContext is FindRoutes with cancellation between search stages.
+func (s *Scanner) FindRoutesWithContext(ctx context.Context, params RouteParams, progress func(string)) ([]RouteResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	s = s.withRouteShortcuts(ctx, params.UseWormholes, params.ansiblexEdges(), progress).withRoutePreferences(params.RoutePreferences)
 	startName := strings.TrimSpace(params.SystemName)
 	systemID, ok := s.SDE.SystemByName[strings.ToLower(startName)]
 	if !ok {
@@ -526,10 +551,14 @@ func (s *Scanner) FindRoutes(params RouteParams, progress func(string)) ([]Route
 			wg.Add(1)
 			go func(rid int32) {
 				defer wg.Done()
-				sem <- struct{}{}
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
 				defer func() { <-sem }()
 
-				orders, err := s.ESI.FetchRegionOrders(rid, orderType)
+				orders, err := s.ESI.FetchRegionOrdersContext(ctx, rid, orderType)
 				if err != nil {
 					log.Printf("[Route] FetchRegionOrders(%d,%s) error: %v", rid, orderType, err)
 					return
@@ -566,6 +595,10 @@ func (s *Scanner) FindRoutes(params RouteParams, progress func(string)) ([]Route
 	}()
 	wg.Wait()
 
+	if err := checkContextCanceled(ctx); err != nil {
+		return nil, err
+	}
+
 	log.Printf("[Route] Fetched %d sell, %d buy orders across %d regions (%d systems in envelope)",
 		len(sellOrders), len(buyOrders), len(regions), len(searchSystems))
 	progress("Building order index...")
@@ -745,6 +778,9 @@ func (s *Scanner) FindRoutes(params RouteParams, progress func(string)) ([]Route
 		if len(beam) == 0 {
 			break
 		}
+		if err := checkContextCanceled(ctx); err != nil {
+			return nil, err
+		}
 
 		// Collect completed routes (if we've reached min depth)
 		if depth >= params.MinHops {
@@ -830,6 +866,9 @@ func (s *Scanner) FindRoutes(params RouteParams, progress func(string)) ([]Route
 		completedRoutes = completedRoutes[:MaxUnlimitedResults]
 	}
 
+	if err := checkContextCanceled(ctx); err != nil {
+		return nil, err
+	}
 	s.enrichRoutesWithLiquidity(completedRoutes, progress)
 	EnrichRouteExecutionEstimatesWithProfile(completedRoutes, RouteExecutionProfileFromParams(params))
 	SortRouteResultsByMode(completedRoutes, params.RouteMode)
@@ -19,13 +19,25 @@ type RouteExecutionProfile struct {
 }
 
 func RouteExecutionProfileFromParams(params RouteParams) RouteExecutionProfile {
-	return normalizeRouteExecutionProfile(RouteExecutionProfile{
+	profile := RouteExecutionProfile{
 		ShipProfile:        params.RouteShipProfile,
 		CargoCapacity:      params.EffectiveRouteCargoCapacity(),
 		MinutesPerJump:     params.RouteMinutesPerJump,
 		DockMinutes:        params.RouteDockMinutes,
 		SafetyDelayPercent: params.RouteSafetyDelayPercent,
-	})
+	}
+	// A configured align time or warp speed replaces the class minutes
+	// with warp-time estimates; explicit minutes still win.
+	if isPositiveFinite(params.RouteAlignSeconds) || isPositiveFinite(params.RouteWarpSpeedAU) {
+		travel := ShipTravelFor(params.RouteShipProfile, params.RouteAlignSeconds, params.RouteWarpSpeedAU)
+		if !isPositiveFinite(profile.MinutesPerJump) {
+			profile.MinutesPerJump = travel.MinutesPerJump()
+		}
+		if !isPositiveFinite(profile.DockMinutes) {
+			profile.DockMinutes = travel.DockMinutes()
+		}
+	}
+	return normalizeRouteExecutionProfile(profile)
 }
 
 func (params RouteParams) EffectiveRouteCargoCapacity() float64 {
@@ -79,6 +91,7 @@ func enrichRouteExecutionEstimate(route *RouteResult, profile RouteExecutionProf
 		hop.CargoM3 = sanitizeFloat(float64(hop.Units) * hop.VolumeM3)
 		cargoValueISK += float64(hop.Units) * hop.BuyPrice
 		hop.CargoTrips = routeCargoTrips(hop.CargoM3, profile.CargoCapacity)
+		hop.CargoLeftoverM3 = cargoLeftoverM3(hop.CargoM3, profile.CargoCapacity, hop.CargoTrips)
 		if hop.CargoTrips > cargoTrips {
 			cargoTrips = hop.CargoTrips
 		}
@@ -116,6 +129,7 @@ func enrichRouteExecutionEstimate(route *RouteResult, profile RouteExecutionProf
 		route.HaulingSafetyMultiplier = safetyMult
 	}
 	enrichRouteCourierCollateral(route, cargoValueISK)
+	applyRouteGankThreshold(route, profile.ShipProfile)
 }
 
 func enrichRouteCourierCollateral(route *RouteResult, cargoValueISK float64) {
@@ -207,6 +221,12 @@ func normalizeRouteExecutionProfile(profile RouteExecutionProfile) RouteExecutio
 	return profile
 }
 
+// ShipClassCargoM3 is the typical cargo hold of a route ship class, or 0 for
+// classes without one.
+func ShipClassCargoM3(class string) float64 {
+	return routeShipProfileDefaults(class).CargoCapacity
+}
+
 func routeShipProfileDefaults(profile string) RouteExecutionProfile {
 	switch normalizeRouteShipProfile(profile) {
 	case "fast_frigate":
@@ -0,0 +1,430 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"eve-flipper/internal/graph"
+)
+
+const (
+	defaultMultiStopMaxFlips = 8
+	maxMultiStopFlips        = 12
+	multiStopMaxTwoOptPasses = 50
+)
+
+// MultiStopParams configures the multi-stop hauling route optimizer.
+type MultiStopParams struct {
+	StartSystemName  string
+	CargoCapacity    float64 // m3; <=0 = unlimited
+	Budget           float64 // ISK available for purchases; <=0 = unlimited
+	MaxFlips         int     // max flips combined into one route (default 8, max 12)
+	MinRouteSecurity float64 // 0 = all space
+	Flips            []FlipResult
+	// RoutePreferences are the user's avoided systems and pinned paths.
+	RoutePreferences graph.RoutePreferences
+}
+
+// MultiStopAction is a single buy or sell performed at a stop.
+type MultiStopAction struct {
+	Kind     string // "buy" | "sell"
+	TypeID   int32
+	TypeName string
+	Units    int32
+	Price    float64
+	ISK      float64
+	VolumeM3 float64
+}
+
+// MultiStopStop is one docking stop on the shopping + delivery route.
+type MultiStopStop struct {
+	SystemID      int32
+	SystemName    string
+	StationName   string
+	LocationID    int64 `json:"LocationID,omitempty"`
+	JumpsFromPrev int
+	CargoAfterM3  float64
+	Actions       []MultiStopAction
+}
+
+// MultiStopFlip is a flip selected for the route, sized to fit cargo and budget.
+type MultiStopFlip struct {
+	TypeID       int32
+	TypeName     string
+	Units        int32
+	BuySystemID  int32
+	SellSystemID int32
+	CostISK      float64
+	Profit       float64
+	VolumeM3     float64
+}
+
+// MultiStopRoute is the optimized pickup/drop-off sequence for a set of flips.
+type MultiStopRoute struct {
+	StartSystemID   int32
+	StartSystemName string
+	Flips           []MultiStopFlip
+	Stops           []MultiStopStop
+	// Waypoints lists solar systems in visiting order (start excluded,
+	// consecutive duplicates collapsed) for pushing via SetWaypoint.
+	Waypoints       []int32
+	TotalProfit     float64
+	CapitalRequired float64
+	CargoM3         float64
+	PeakCargoM3     float64
+	TotalJumps      int
+	SequentialJumps int // jumps when hauling each flip buy→sell one after another
+	ProfitPerJump   float64
+}
+
+// multiStopNode is a pickup or drop-off of one selected flip.
+type multiStopNode struct {
+	flip   int
+	pickup bool
+	system int32
+}
+
+// OptimizeMultiStopRoute selects flips that fit the cargo hold and budget and
+// orders their pickups and drop-offs to minimize total jumps.
+func (s *Scanner) OptimizeMultiStopRoute(params MultiStopParams) (*MultiStopRoute, error) {
+	s = s.withRoutePreferences(params.RoutePreferences)
+	startName := strings.TrimSpace(params.StartSystemName)
+	startID, ok := s.SDE.SystemByName[strings.ToLower(startName)]
+	if !ok {
+		return nil, fmt.Errorf("system not found: %s", params.StartSystemName)
+	}
+	dist := func(from, to int32) int {
+		return s.jumpsBetweenWithSecurity(from, to, params.MinRouteSecurity)
+	}
+
+	route := planMultiStopRoute(startID, params, dist)
+	route.StartSystemName = s.systemName(startID)
+	for i := range route.Stops {
+		if route.Stops[i].SystemName == "" {
+			route.Stops[i].SystemName = s.systemName(route.Stops[i].SystemID)
+		}
+	}
+	if len(route.Flips) == 0 {
+		return nil, fmt.Errorf("no flips fit the cargo capacity and budget")
+	}
+	return route, nil
+}
+
+// planMultiStopRoute runs flip selection, nearest-neighbor construction and
+// 2-opt improvement with the given jump distance function.
+func planMultiStopRoute(startID int32, params MultiStopParams, dist func(from, to int32) int) *MultiStopRoute {
+	return sequenceMultiStopRoute(startID, selectMultiStopFlips(startID, params, dist), dist)
+}
+
+// sequenceMultiStopRoute orders the pickups and drop-offs of already sized
+// flips and builds the stop list. Every selected volume is assumed to fit at once.
+func sequenceMultiStopRoute(startID int32, selected []multiStopCandidate, dist func(from, to int32) int) *MultiStopRoute {
+	route := &MultiStopRoute{
+		StartSystemID: startID,
+		Flips:         make([]MultiStopFlip, 0, len(selected)),
+		Stops:         []MultiStopStop{},
+		Waypoints:     []int32{},
+	}
+	if len(selected) == 0 {
+		return route
+	}
+
+	cache := make(map[[2]int32]int)
+	jumps := func(from, to int32) int {
+		if from == to {
+			return 0
+		}
+		key := [2]int32{from, to}
+		if from > to {
+			key = [2]int32{to, from}
+		}
+		if d, ok := cache[key]; ok {
+			return d
+		}
+		d := dist(from, to)
+		cache[key] = d
+		return d
+	}
+
+	nodes := make([]multiStopNode, 0, 2*len(selected))
+	for i, sf := range selected {
+		nodes = append(nodes,
+			multiStopNode{flip: i, pickup: true, system: sf.row.BuySystemID},
+			multiStopNode{flip: i, pickup: false, system: sf.row.SellSystemID},
+		)
+	}
+
+	seq := nearestNeighborMultiStop(startID, nodes, selected, jumps)
+	seq = twoOptMultiStop(startID, seq, jumps)
+
+	sequential := 0
+	prev := startID
+	for _, sf := range selected {
+		sequential += jumps(prev, sf.row.BuySystemID) + jumps(sf.row.BuySystemID, sf.row.SellSystemID)
+		prev = sf.row.SellSystemID
+	}
+	route.SequentialJumps = sequential
+
+	for _, sf := range selected {
+		route.Flips = append(route.Flips, MultiStopFlip{
+			TypeID:       sf.row.TypeID,
+			TypeName:     sf.row.TypeName,
+			Units:        sf.units,
+			BuySystemID:  sf.row.BuySystemID,
+			SellSystemID: sf.row.SellSystemID,
+			CostISK:      sanitizeFloat(float64(sf.units) * sf.row.BuyPrice),
+			Profit:       sanitizeFloat(float64(sf.units) * sf.row.ProfitPerUnit),
+			VolumeM3:     sanitizeFloat(float64(sf.units) * sf.row.Volume),
+		})
+		route.TotalProfit += float64(sf.units) * sf.row.ProfitPerUnit
+		route.CapitalRequired += float64(sf.units) * sf.row.BuyPrice
+		route.CargoM3 += float64(sf.units) * sf.row.Volume
+	}
+
+	cargo := 0.0
+	prev = startID
+	for _, n := range seq {
+		sf := selected[n.flip]
+		action := MultiStopAction{
+			TypeID:   sf.row.TypeID,
+			TypeName: sf.row.TypeName,
+			Units:    sf.units,
+			VolumeM3: sanitizeFloat(float64(sf.units) * sf.row.Volume),
+		}
+		station, locationID := sf.row.SellStation, sf.row.SellLocationID
+		systemName := sf.row.SellSystemName
+		if n.pickup {
+			action.Kind = "buy"
+			action.Price = sf.row.BuyPrice
+			station, locationID = sf.row.BuyStation, sf.row.BuyLocationID
+			systemName = sf.row.BuySystemName
+			cargo += action.VolumeM3
+		} else {
+			action.Kind = "sell"
+			action.Price = sf.row.SellPrice
+			cargo -= action.VolumeM3
+		}
+		action.ISK = sanitizeFloat(float64(sf.units) * action.Price)
+		if cargo > route.PeakCargoM3 {
+			route.PeakCargoM3 = cargo
+		}
+
+		last := len(route.Stops) - 1
+		if last >= 0 && route.Stops[last].SystemID == n.system &&
+			(route.Stops[last].LocationID == locationID || locationID == 0) {
+			route.Stops[last].Actions = append(route.Stops[last].Actions, action)
+			route.Stops[last].CargoAfterM3 = sanitizeFloat(math.Max(cargo, 0))
+			continue
+		}
+		step := jumps(prev, n.system)
+		route.TotalJumps += step
+		route.Stops = append(route.Stops, MultiStopStop{
+			SystemID:      n.system,
+			SystemName:    systemName,
+			StationName:   station,
+			LocationID:    locationID,
+			JumpsFromPrev: step,
+			CargoAfterM3:  sanitizeFloat(math.Max(cargo, 0)),
+			Actions:       []MultiStopAction{action},
+		})
+		if n.system != prev {
+			route.Waypoints = append(route.Waypoints, n.system)
+		}
+		prev = n.system
+	}
+
+	route.TotalProfit = sanitizeFloat(route.TotalProfit)
+	route.CapitalRequired = sanitizeFloat(route.CapitalRequired)
+	route.CargoM3 = sanitizeFloat(route.CargoM3)
+	route.PeakCargoM3 = sanitizeFloat(route.PeakCargoM3)
+	if route.TotalJumps > 0 {
+		route.ProfitPerJump = sanitizeFloat(route.TotalProfit / float64(route.TotalJumps))
+	} else {
+		route.ProfitPerJump = route.TotalProfit
+	}
+	return route
+}
+
+type multiStopCandidate struct {
+	row   FlipResult
+	units int32
+	score float64
+}
+
+// selectMultiStopFlips greedily picks flips by profit density against the
+// tighter of the cargo and budget constraints, shrinking the last fits.
+// The total selected volume never exceeds cargo, so any stop order is loadable.
+func selectMultiStopFlips(startID int32, params MultiStopParams, dist func(from, to int32) int) []multiStopCandidate {
+	maxFlips := params.MaxFlips
+	if maxFlips <= 0 {
+		maxFlips = defaultMultiStopMaxFlips
+	}
+	if maxFlips > maxMultiStopFlips {
+		maxFlips = maxMultiStopFlips
+	}
+
+	candidates := make([]multiStopCandidate, 0, len(params.Flips))
+	for _, row := range params.Flips {
+		if row.ProfitPerUnit <= 0 || row.UnitsToBuy <= 0 || row.BuyPrice <= 0 {
+			continue
+		}
+		if row.BuySystemID == 0 || row.SellSystemID == 0 {
+			continue
+		}
+		if dist(startID, row.BuySystemID) >= UnreachableJumps || dist(row.BuySystemID, row.SellSystemID) >= UnreachableJumps {
+			continue
+		}
+		profit := float64(row.UnitsToBuy) * row.ProfitPerUnit
+		share := 0.0
+		if params.CargoCapacity > 0 && row.Volume > 0 {
+			share = math.Max(share, float64(row.UnitsToBuy)*row.Volume/params.CargoCapacity)
+		}
+		if params.Budget > 0 {
+			share = math.Max(share, float64(row.UnitsToBuy)*row.BuyPrice/params.Budget)
+		}
+		score := profit
+		if share > 0 {
+			score = profit / share
+		}
+		candidates = append(candidates, multiStopCandidate{row: row, units: row.UnitsToBuy, score: score})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	cargoLeft := params.CargoCapacity
+	budgetLeft := params.Budget
+	seen := make(map[[2]int64]bool)
+	selected := make([]multiStopCandidate, 0, maxFlips)
+	for _, c := range candidates {
+		if len(selected) >= maxFlips {
+			break
+		}
+		key := [2]int64{int64(c.row.TypeID), c.row.BuyLocationID}
+		if c.row.BuyLocationID == 0 {
+			key[1] = int64(c.row.BuySystemID)
+		}
+		if seen[key] {
+			continue
+		}
+		units := c.units
+		if params.CargoCapacity > 0 && c.row.Volume > 0 {
+			units = min(units, int32(math.Floor(cargoLeft/c.row.Volume)))
+		}
+		if params.Budget > 0 {
+			units = min(units, int32(math.Floor(budgetLeft/c.row.BuyPrice)))
+		}
+		if units <= 0 {
+			continue
+		}
+		seen[key] = true
+		c.units = units
+		cargoLeft -= float64(units) * c.row.Volume
+		budgetLeft -= float64(units) * c.row.BuyPrice
+		selected = append(selected, c)
+	}
+	return selected
+}
+
+// nearestNeighborMultiStop builds an initial tour: always travel to the
+// closest stop that is currently allowed (any pickup, or a drop-off whose
+// cargo is already aboard). Ties prefer drop-offs, then higher profit.
+func nearestNeighborMultiStop(startID int32, nodes []multiStopNode, selected []multiStopCandidate, jumps func(from, to int32) int) []multiStopNode {
+	visited := make([]bool, len(nodes))
+	picked := make([]bool, len(selected))
+	seq := make([]multiStopNode, 0, len(nodes))
+	cur := startID
+	for len(seq) < len(nodes) {
+		best := -1
+		bestDist := 0
+		for i, n := range nodes {
+			if visited[i] || (!n.pickup && !picked[n.flip]) {
+				continue
+			}
+			d := jumps(cur, n.system)
+			if best < 0 || d < bestDist {
+				best, bestDist = i, d
+				continue
+			}
+			if d > bestDist {
+				continue
+			}
+			b := nodes[best]
+			if !n.pickup && b.pickup {
+				best = i
+			} else if n.pickup == b.pickup &&
+				float64(selected[n.flip].units)*selected[n.flip].row.ProfitPerUnit >
+					float64(selected[b.flip].units)*selected[b.flip].row.ProfitPerUnit {
+				best = i
+			}
+		}
+		n := nodes[best]
+		visited[best] = true
+		if n.pickup {
+			picked[n.flip] = true
+		}
+		seq = append(seq, n)
+		cur = n.system
+	}
+	return seq
+}
+
+// twoOptMultiStop reverses tour segments while that shortens the route and
+// keeps every pickup ahead of its drop-off. The tour is open (no return leg).
+func twoOptMultiStop(startID int32, seq []multiStopNode, jumps func(from, to int32) int) []multiStopNode {
+	if len(seq) < 3 {
+		return seq
+	}
+	at := func(i int) int32 {
+		if i < 0 {
+			return startID
+		}
+		return seq[i].system
+	}
+	for pass := 0; pass < multiStopMaxTwoOptPasses; pass++ {
+		improved := false
+		for i := 0; i < len(seq)-1; i++ {
+			for k := i + 1; k < len(seq); k++ {
+				before := jumps(at(i-1), at(i))
+				after := jumps(at(i-1), at(k))
+				if k+1 < len(seq) {
+					before += jumps(at(k), at(k+1))
+					after += jumps(at(i), at(k+1))
+				}
+				if after >= before {
+					continue
+				}
+				if !multiStopSegmentReversible(seq[i : k+1]) {
+					continue
+				}
+				for l, r := i, k; l < r; l, r = l+1, r-1 {
+					seq[l], seq[r] = seq[r], seq[l]
+				}
+				improved = true
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+	return seq
+}
+
+// multiStopSegmentReversible reports whether reversing the segment keeps
+// precedence: it must not contain both the pickup and drop-off of one flip.
+func multiStopSegmentReversible(segment []multiStopNode) bool {
+	pickups := make(map[int]bool, len(segment))
+	for _, n := range segment {
+		if n.pickup {
+			pickups[n.flip] = true
+		}
+	}
+	for _, n := range segment {
+		if !n.pickup && pickups[n.flip] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"testing"
+
+	"eve-flipper/internal/graph"
+	"eve-flipper/internal/sde"
+)
+
+// Linear chain 1-2-3-4-5: jumps = |a-b|.
+func lineDist(from, to int32) int {
+	d := int(from - to)
+	if d < 0 {
+		d = -d
+	}
+	return d
+}
+
+func TestPlanMultiStopRoute_InterleavesPickupsAndDrops(t *testing.T) {
+	flips := []FlipResult{
+		{TypeID: 1, TypeName: "A", BuySystemID: 2, SellSystemID: 4, BuyPrice: 10, SellPrice: 15, ProfitPerUnit: 5, UnitsToBuy: 10, Volume: 1},
+		{TypeID: 2, TypeName: "B", BuySystemID: 3, SellSystemID: 5, BuyPrice: 10, SellPrice: 14, ProfitPerUnit: 4, UnitsToBuy: 10, Volume: 1},
+	}
+	route := planMultiStopRoute(1, MultiStopParams{Flips: flips}, lineDist)
+
+	if len(route.Flips) != 2 {
+		t.Fatalf("flips = %d, want 2", len(route.Flips))
+	}
+	// 1→2 (buy A) →3 (buy B) →4 (sell A) →5 (sell B) = 4 jumps.
+	if route.TotalJumps != 4 {
+		t.Errorf("TotalJumps = %d, want 4", route.TotalJumps)
+	}
+	// Sequential: 1→2→4, 4→3→5 = 1+2+1+2 = 6.
+	if route.SequentialJumps != 6 {
+		t.Errorf("SequentialJumps = %d, want 6", route.SequentialJumps)
+	}
+	want := []int32{2, 3, 4, 5}
+	if len(route.Waypoints) != len(want) {
+		t.Fatalf("Waypoints = %v, want %v", route.Waypoints, want)
+	}
+	for i := range want {
+		if route.Waypoints[i] != want[i] {
+			t.Fatalf("Waypoints = %v, want %v", route.Waypoints, want)
+		}
+	}
+	if route.TotalProfit != 90 || route.PeakCargoM3 != 20 {
+		t.Errorf("profit=%v peak=%v, want 90/20", route.TotalProfit, route.PeakCargoM3)
+	}
+}
+
+func TestPlanMultiStopRoute_RespectsCargoAndBudget(t *testing.T) {
+	flips := []FlipResult{
+		{TypeID: 1, BuySystemID: 2, SellSystemID: 3, BuyPrice: 100, ProfitPerUnit: 50, UnitsToBuy: 100, Volume: 10},
+		{TypeID: 2, BuySystemID: 2, SellSystemID: 3, BuyPrice: 10, ProfitPerUnit: 2, UnitsToBuy: 100, Volume: 1},
+	}
+	route := planMultiStopRoute(1, MultiStopParams{Flips: flips, CargoCapacity: 250, Budget: 2000}, lineDist)
+
+	if route.CargoM3 > 250 {
+		t.Errorf("CargoM3 = %v exceeds cargo 250", route.CargoM3)
+	}
+	if route.CapitalRequired > 2000 {
+		t.Errorf("CapitalRequired = %v exceeds budget 2000", route.CapitalRequired)
+	}
+	if len(route.Flips) == 0 || route.Flips[0].TypeID != 1 || route.Flips[0].Units != 20 {
+		t.Fatalf("first flip = %+v, want type 1 capped at 20 units by budget", route.Flips)
+	}
+}
+
+func TestPlanMultiStopRoute_DropNeverBeforePickup(t *testing.T) {
+	// Drop-off of B sits next to start, pickup far away.
+	flips := []FlipResult{
+		{TypeID: 1, BuySystemID: 5, SellSystemID: 2, BuyPrice: 1, ProfitPerUnit: 1, UnitsToBuy: 1, Volume: 1},
+		{TypeID: 2, BuySystemID: 4, SellSystemID: 1, BuyPrice: 1, ProfitPerUnit: 1, UnitsToBuy: 1, Volume: 1},
+	}
+	route := planMultiStopRoute(1, MultiStopParams{Flips: flips}, lineDist)
+	picked := map[int32]bool{}
+	for _, stop := range route.Stops {
+		for _, a := range stop.Actions {
+			if a.Kind == "buy" {
+				picked[a.TypeID] = true
+			} else if !picked[a.TypeID] {
+				t.Fatalf("sold type %d before buying it: %+v", a.TypeID, route.Stops)
+			}
+		}
+	}
+}
+
+func TestOptimizeMultiStopRoute_UnknownSystem(t *testing.T) {
+	s := &Scanner{SDE: &sde.Data{Universe: graph.NewUniverse(), SystemByName: map[string]int32{}}}
+	if _, err := s.OptimizeMultiStopRoute(MultiStopParams{StartSystemName: "Nowhere"}); err == nil {
+		t.Fatal("expected error for unknown start system")
+	}
+}
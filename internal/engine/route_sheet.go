@@ -0,0 +1,182 @@
+package engine
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// RouteSheetLine is one item bought or sold at a stop.
+type RouteSheetLine struct {
+	TypeName string  `json:"type_name"`
+	Units    int32   `json:"units"`
+	Price    float64 `json:"price"`
+	ISK      float64 `json:"isk"`
+	VolumeM3 float64 `json:"volume_m3"`
+}
+
+// RouteSheetStop is one station visit of a route: what to sell and buy there
+// and the cargo carried when leaving.
+type RouteSheetStop struct {
+	Step          int              `json:"step"`
+	SystemName    string           `json:"system_name"`
+	StationName   string           `json:"station_name"`
+	Jumps         int              `json:"jumps"` // from the previous stop
+	Sell          []RouteSheetLine `json:"sell"`
+	Buy           []RouteSheetLine `json:"buy"`
+	ProceedsISK   float64          `json:"proceeds_isk"`
+	SpendISK      float64          `json:"spend_isk"`
+	CargoM3       float64          `json:"cargo_m3"`        // on departure
+	CargoValueISK float64          `json:"cargo_value_isk"` // buy cost of cargo on departure
+	RunningProfit float64          `json:"running_profit"`
+}
+
+// BuildRouteSheet turns a route's hops into station stops. A hop that starts
+// where the previous one sold is merged into that stop.
+func BuildRouteSheet(route RouteResult) []RouteSheetStop {
+	var stops []RouteSheetStop
+	var cargoM3, cargoValue, profit float64
+	stopAt := func(system, station string, jumps int) *RouteSheetStop {
+		if n := len(stops); n > 0 && jumps == 0 &&
+			stops[n-1].SystemName == system && stops[n-1].StationName == station {
+			return &stops[n-1]
+		}
+		stops = append(stops, RouteSheetStop{
+			Step:        len(stops) + 1,
+			SystemName:  system,
+			StationName: station,
+			Jumps:       jumps,
+		})
+		return &stops[len(stops)-1]
+	}
+
+	for _, hop := range route.Hops {
+		m3 := hop.VolumeM3 * float64(hop.Units)
+		buyISK := hop.BuyPrice * float64(hop.Units)
+		sellISK := hop.SellPrice * float64(hop.Units)
+
+		buy := stopAt(hop.SystemName, hop.StationName, hop.EmptyJumps)
+		buy.Buy = append(buy.Buy, RouteSheetLine{
+			TypeName: hop.TypeName, Units: hop.Units, Price: hop.BuyPrice, ISK: buyISK, VolumeM3: m3,
+		})
+		buy.SpendISK += buyISK
+		cargoM3 += m3
+		cargoValue += buyISK
+		buy.CargoM3, buy.CargoValueISK, buy.RunningProfit = cargoM3, cargoValue, profit
+
+		sell := stopAt(hop.DestSystemName, hop.DestStationName, hop.Jumps)
+		sell.Sell = append(sell.Sell, RouteSheetLine{
+			TypeName: hop.TypeName, Units: hop.Units, Price: hop.SellPrice, ISK: sellISK, VolumeM3: m3,
+		})
+		sell.ProceedsISK += sellISK
+		cargoM3 = max(cargoM3-m3, 0)
+		cargoValue = max(cargoValue-buyISK, 0)
+		profit += hop.Profit
+		sell.CargoM3, sell.CargoValueISK, sell.RunningProfit = cargoM3, cargoValue, profit
+	}
+	return stops
+}
+
+// formatSheetISK formats ISK with thousands separators and no decimals.
+func formatSheetISK(v float64) string {
+	return groupThousands(fmt.Sprintf("%.0f", v))
+}
+
+// formatSheetPrice formats a per-unit price with thousands separators.
+func formatSheetPrice(v float64) string {
+	return groupThousands(fmt.Sprintf("%.2f", v))
+}
+
+// groupThousands inserts thousands separators into a formatted number.
+func groupThousands(s string) string {
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i:]
+	}
+	sign := ""
+	if strings.HasPrefix(intPart, "-") {
+		sign, intPart = "-", intPart[1:]
+	}
+	var out []byte
+	for i := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			out = append(out, ',')
+		}
+		out = append(out, intPart[i])
+	}
+	return sign + string(out) + frac
+}
+
+func formatSheetM3(v float64) string {
+	return fmt.Sprintf("%.1f", v)
+}
+
+// RenderRouteSheetMarkdown renders a route as a compact Markdown checklist.
+func RenderRouteSheetMarkdown(title string, route RouteResult) string {
+	stops := BuildRouteSheet(route)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	fmt.Fprintf(&sb, "%d stops · %d jumps · profit %s ISK\n", len(stops), route.TotalJumps, formatSheetISK(route.TotalProfit))
+	for _, st := range stops {
+		fmt.Fprintf(&sb, "\n## %d. %s — %s", st.Step, st.SystemName, st.StationName)
+		if st.Jumps > 0 {
+			fmt.Fprintf(&sb, " (%d jumps)", st.Jumps)
+		}
+		sb.WriteString("\n\n")
+		for _, l := range st.Sell {
+			fmt.Fprintf(&sb, "- [ ] SELL %s × %d @ %s = %s ISK\n", l.TypeName, l.Units, formatSheetPrice(l.Price), formatSheetISK(l.ISK))
+		}
+		for _, l := range st.Buy {
+			fmt.Fprintf(&sb, "- [ ] BUY %s × %d @ %s = %s ISK (%s m³)\n", l.TypeName, l.Units, formatSheetPrice(l.Price), formatSheetISK(l.ISK), formatSheetM3(l.VolumeM3))
+		}
+		fmt.Fprintf(&sb, "\nProceeds %s · Spend %s · Cargo %s m³ (%s ISK) · Profit so far %s\n",
+			formatSheetISK(st.ProceedsISK), formatSheetISK(st.SpendISK),
+			formatSheetM3(st.CargoM3), formatSheetISK(st.CargoValueISK), formatSheetISK(st.RunningProfit))
+	}
+	return sb.String()
+}
+
+var routeSheetHTML = template.Must(template.New("sheet").Funcs(template.FuncMap{
+	"isk":   formatSheetISK,
+	"price": formatSheetPrice,
+	"m3":    formatSheetM3,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>{{.Title}}</title>
+<style>
+body{font:13px/1.35 -apple-system,"Segoe UI",Helvetica,Arial,sans-serif;margin:1.5rem;color:#111}
+h1{font-size:1.2rem;margin:0 0 .25rem}
+.meta{color:#555;margin-bottom:1rem}
+.stop{border-top:1px solid #999;padding:.5rem 0;page-break-inside:avoid}
+.stop h2{font-size:1rem;margin:0 0 .25rem}
+table{border-collapse:collapse;width:100%}
+td{padding:1px 6px 1px 0}
+td.n{text-align:right;white-space:nowrap}
+.sell{color:#0a5}.buy{color:#a40}
+.sum{color:#333;margin-top:.25rem}
+</style></head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="meta">{{len .Stops}} stops · {{.Route.TotalJumps}} jumps · profit {{isk .Route.TotalProfit}} ISK</div>
+{{range .Stops}}<div class="stop">
+<h2>{{.Step}}. {{.SystemName}} — {{.StationName}}{{if gt .Jumps 0}} ({{.Jumps}} jumps){{end}}</h2>
+<table>
+{{range .Sell}}<tr><td>☐</td><td class="sell">SELL</td><td>{{.TypeName}}</td><td class="n">× {{.Units}}</td><td class="n">@ {{price .Price}}</td><td class="n">{{isk .ISK}} ISK</td><td></td></tr>
+{{end}}{{range .Buy}}<tr><td>☐</td><td class="buy">BUY</td><td>{{.TypeName}}</td><td class="n">× {{.Units}}</td><td class="n">@ {{price .Price}}</td><td class="n">{{isk .ISK}} ISK</td><td class="n">{{m3 .VolumeM3}} m³</td></tr>
+{{end}}</table>
+<div class="sum">Proceeds {{isk .ProceedsISK}} · Spend {{isk .SpendISK}} · Cargo {{m3 .CargoM3}} m³ ({{isk .CargoValueISK}} ISK) · Profit so far {{isk .RunningProfit}}</div>
+</div>
+{{end}}</body>
+</html>
+`))
+
+// RenderRouteSheetHTML renders a route as a printable HTML page.
+func RenderRouteSheetHTML(title string, route RouteResult) (string, error) {
+	var sb strings.Builder
+	err := routeSheetHTML.Execute(&sb, struct {
+		Title string
+		Route RouteResult
+		Stops []RouteSheetStop
+	}{title, route, BuildRouteSheet(route)})
+	return sb.String(), err
+}
@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildRouteSheetMergesChainedStops(t *testing.T) {
+	route := RouteResult{
+		TotalJumps:  7,
+		TotalProfit: 3000,
+		Hops: []RouteHop{
+			{SystemName: "Jita", StationName: "Jita 4-4", DestSystemName: "Amarr", DestStationName: "Amarr VIII",
+				TypeName: "Tritanium", BuyPrice: 5, SellPrice: 7, Units: 1000, Profit: 2000, Jumps: 4, VolumeM3: 0.01},
+			{SystemName: "Amarr", StationName: "Amarr VIII", DestSystemName: "Dodixie", DestStationName: "Dodixie IX",
+				TypeName: "<Pyerite>", BuyPrice: 10, SellPrice: 11, Units: 1000, Profit: 1000, Jumps: 3, VolumeM3: 0.01},
+		},
+	}
+	stops := BuildRouteSheet(route)
+	if len(stops) != 3 {
+		t.Fatalf("stops = %d, want 3 (Amarr sell and buy merged)", len(stops))
+	}
+	amarr := stops[1]
+	if len(amarr.Sell) != 1 || len(amarr.Buy) != 1 || amarr.Jumps != 4 {
+		t.Fatalf("Amarr stop = %+v", amarr)
+	}
+	if amarr.ProceedsISK != 7000 || amarr.SpendISK != 10000 || amarr.CargoM3 != 10 || amarr.RunningProfit != 2000 {
+		t.Fatalf("Amarr totals = %+v", amarr)
+	}
+	last := stops[2]
+	if last.CargoM3 != 0 || last.CargoValueISK != 0 || last.RunningProfit != 3000 {
+		t.Fatalf("final stop = %+v", last)
+	}
+
+	md := RenderRouteSheetMarkdown("Route", route)
+	if !strings.Contains(md, "- [ ] BUY Tritanium × 1000 @ 5.00 = 5,000 ISK") {
+		t.Fatalf("markdown missing buy line:\n%s", md)
+	}
+	page, err := RenderRouteSheetHTML("Route", route)
+	if err != nil {
+		t.Fatalf("RenderRouteSheetHTML: %v", err)
+	}
+	if strings.Contains(page, "<Pyerite>") || !strings.Contains(page, "&lt;Pyerite&gt;") {
+		t.Fatal("HTML sheet does not escape item names")
+	}
+}
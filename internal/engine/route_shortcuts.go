@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"eve-flipper/internal/graph"
+)
+
+// WormholeSource lists live wormhole connections (Thera, Turnur).
+// *evescout.Client implements it.
+type WormholeSource interface {
+	WormholeConnections(ctx context.Context) ([]graph.WormholeConnection, error)
+}
+
+// withRouteShortcuts returns a scanner whose universe also has the live
+// wormhole connections (when useWormholes) and the given Ansiblex jump
+// bridges, or s itself when there are none. The shared universe and its path
+// cache are left untouched.
+func (s *Scanner) withRouteShortcuts(ctx context.Context, useWormholes bool, ansiblex []graph.Edge, progress func(string)) *Scanner {
+	if s.SDE == nil || s.SDE.Universe == nil {
+		return s
+	}
+	edges := append([]graph.Edge(nil), ansiblex...)
+	wormholes := 0
+	if useWormholes && s.Wormholes != nil {
+		conns, err := s.Wormholes.WormholeConnections(ctx)
+		if err != nil {
+			log.Printf("[WH] Wormhole connections unavailable, routing by gates only: %v", err)
+		}
+		now := time.Now()
+		for _, c := range conns {
+			if !c.Expired(now) {
+				edges = append(edges, graph.Edge{From: c.HubSystemID, To: c.SystemID})
+				wormholes++
+			}
+		}
+	}
+	universe := s.SDE.Universe.WithEdges(edges)
+	if universe == s.SDE.Universe {
+		return s
+	}
+	if progress != nil {
+		progress(fmt.Sprintf("Routing with %d Ansiblex bridges and %d Thera/Turnur connections...", len(ansiblex), wormholes))
+	}
+	data := *s.SDE
+	data.Universe = universe
+	out := *s
+	out.SDE = &data
+	return &out
+}
+
+// withRoutePreferences returns a scanner whose universe never enters the
+// user's avoided systems and follows their pinned paths, or s itself when
+// there are none. Apply it after withRouteShortcuts so shortcuts into
+// avoided systems are dropped too. The filtered universe is cached on the
+// SDE universe, so repeated scans keep its path cache and jump tables.
+func (s *Scanner) withRoutePreferences(p graph.RoutePreferences) *Scanner {
+	if s.SDE == nil || s.SDE.Universe == nil {
+		return s
+	}
+	universe := s.SDE.Universe.WithRoutePreferences(p)
+	if universe == s.SDE.Universe {
+		return s
+	}
+	data := *s.SDE
+	data.Universe = universe
+	out := *s
+	out.SDE = &data
+	return &out
+}
@@ -0,0 +1,114 @@
+package engine
+
+import "sort"
+
+// ScanPresetStats summarises one set of flip results.
+type ScanPresetStats struct {
+	Count       int     `json:"count"`
+	TotalProfit float64 `json:"total_profit"`
+	AvgJumps    float64 `json:"avg_jumps"`
+	// ISKPerJump is total profit over total jumps; a same-system flip counts
+	// as one jump.
+	ISKPerJump float64 `json:"isk_per_jump"`
+}
+
+// ScanComparison contrasts the results of the same scan run with two
+// parameter presets, A (usually the narrower one) and B.
+type ScanComparison struct {
+	A      ScanPresetStats `json:"a"`
+	B      ScanPresetStats `json:"b"`
+	Common int             `json:"common"`
+	// OnlyB are the opportunities the B preset adds.
+	OnlyB        ScanPresetStats `json:"only_b"`
+	OnlyBResults []FlipResult    `json:"only_b_results"`
+	OnlyAResults []FlipResult    `json:"only_a_results"`
+	// ExtraJumps is how much longer the added opportunities are than A's
+	// on average.
+	ExtraJumps float64 `json:"extra_jumps"`
+	// ExtraWorthIt is true when the added opportunities earn at least A's
+	// ISK per jump, i.e. the extra travel pays as well as staying close.
+	ExtraWorthIt bool `json:"extra_worth_it"`
+}
+
+type flipCompareKey struct {
+	typeID         int32
+	buySystemID    int32
+	sellSystemID   int32
+	buyLocationID  int64
+	sellLocationID int64
+}
+
+func flipCompareKeyOf(r FlipResult) flipCompareKey {
+	return flipCompareKey{r.TypeID, r.BuySystemID, r.SellSystemID, r.BuyLocationID, r.SellLocationID}
+}
+
+// FlipResultKPIProfit is the profit figure scans rank and total by: depth
+// aware real profit, then expected profit, then the top-of-book total.
+func FlipResultKPIProfit(r FlipResult) float64 {
+	if r.RealProfit > 0 {
+		return r.RealProfit
+	}
+	if r.ExpectedProfit > 0 {
+		return r.ExpectedProfit
+	}
+	return r.TotalProfit
+}
+
+func scanPresetStats(results []FlipResult) ScanPresetStats {
+	st := ScanPresetStats{Count: len(results)}
+	if len(results) == 0 {
+		return st
+	}
+	jumps := 0
+	for _, r := range results {
+		st.TotalProfit += FlipResultKPIProfit(r)
+		jumps += max(r.TotalJumps, 1)
+	}
+	st.AvgJumps = float64(jumps) / float64(len(results))
+	st.ISKPerJump = sanitizeFloat(st.TotalProfit / float64(jumps))
+	return st
+}
+
+// CompareScans matches results of two scans by item and buy/sell location
+// and reports what each preset finds that the other does not. Unique
+// results are ordered by profit, best first.
+func CompareScans(a, b []FlipResult) ScanComparison {
+	inA := make(map[flipCompareKey]bool, len(a))
+	for _, r := range a {
+		inA[flipCompareKeyOf(r)] = true
+	}
+	inB := make(map[flipCompareKey]bool, len(b))
+	for _, r := range b {
+		inB[flipCompareKeyOf(r)] = true
+	}
+	cmp := ScanComparison{
+		A:            scanPresetStats(a),
+		B:            scanPresetStats(b),
+		OnlyAResults: []FlipResult{},
+		OnlyBResults: []FlipResult{},
+	}
+	for _, r := range a {
+		if !inB[flipCompareKeyOf(r)] {
+			cmp.OnlyAResults = append(cmp.OnlyAResults, r)
+		}
+	}
+	for _, r := range b {
+		if inA[flipCompareKeyOf(r)] {
+			cmp.Common++
+		} else {
+			cmp.OnlyBResults = append(cmp.OnlyBResults, r)
+		}
+	}
+	byProfit := func(rs []FlipResult) {
+		sort.SliceStable(rs, func(i, j int) bool { return FlipResultKPIProfit(rs[i]) > FlipResultKPIProfit(rs[j]) })
+	}
+	byProfit(cmp.OnlyAResults)
+	byProfit(cmp.OnlyBResults)
+
+	cmp.OnlyB = scanPresetStats(cmp.OnlyBResults)
+	if cmp.OnlyB.Count > 0 {
+		cmp.ExtraJumps = cmp.OnlyB.AvgJumps - cmp.A.AvgJumps
+		cmp.ExtraWorthIt = cmp.OnlyB.ISKPerJump >= cmp.A.ISKPerJump
+	}
+	return cmp
+}
@@ -0,0 +1,32 @@
+package engine
+
+import "testing"
+
+func TestCompareScans(t *testing.T) {
+	near := FlipResult{TypeID: 34, BuyLocationID: 1, SellLocationID: 2, TotalJumps: 2, RealProfit: 2_000_000}
+	nearOnly := FlipResult{TypeID: 35, BuyLocationID: 1, SellLocationID: 3, TotalJumps: 0, RealProfit: 500_000}
+	far := FlipResult{TypeID: 36, BuyLocationID: 1, SellLocationID: 9, TotalJumps: 8, RealProfit: 4_000_000}
+	farPoor := FlipResult{TypeID: 37, BuyLocationID: 1, SellLocationID: 9, TotalJumps: 8, TotalProfit: 100_000}
+
+	cmp := CompareScans([]FlipResult{near, nearOnly}, []FlipResult{near, farPoor, far})
+	if cmp.Common != 1 || len(cmp.OnlyAResults) != 1 || len(cmp.OnlyBResults) != 2 {
+		t.Fatalf("comparison = %+v", cmp)
+	}
+	if cmp.OnlyBResults[0].TypeID != 36 || cmp.OnlyAResults[0].TypeID != 35 {
+		t.Fatalf("unique results = %+v / %+v", cmp.OnlyAResults, cmp.OnlyBResults)
+	}
+	// A: 2.5M over 3 jumps (same-system flip counts one); extra: 4.1M over 16.
+	if cmp.A.TotalProfit != 2_500_000 || cmp.A.AvgJumps != 1.5 {
+		t.Fatalf("A = %+v", cmp.A)
+	}
+	if cmp.OnlyB.ISKPerJump != 256_250 || cmp.ExtraJumps != 6.5 {
+		t.Fatalf("only B = %+v, extra jumps %v", cmp.OnlyB, cmp.ExtraJumps)
+	}
+	if cmp.A.ISKPerJump <= cmp.OnlyB.ISKPerJump || cmp.ExtraWorthIt {
+		t.Fatalf("extra travel should not pay: A %v/jump vs %v/jump", cmp.A.ISKPerJump, cmp.OnlyB.ISKPerJump)
+	}
+
+	if cmp = CompareScans(nil, []FlipResult{far}); !cmp.ExtraWorthIt {
+		t.Fatalf("additions over an empty preset should pay: %+v", cmp)
+	}
+}
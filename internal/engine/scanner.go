@@ -1,6 +1,8 @@
 package engine
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -8,6 +10,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"eve-flipper/internal/esi"
 	"eve-flipper/internal/sde"
@@ -21,19 +24,17 @@ const (
 	UnreachableJumps = 999
 )
 
-// HistoryProvider is an interface for fetching and caching market history.
-type HistoryProvider interface {
-	GetMarketHistory(regionID int32, typeID int32) ([]esi.HistoryEntry, bool)
-	SetMarketHistory(regionID int32, typeID int32, entries []esi.HistoryEntry)
-}
-
 // Scanner orchestrates market scans using SDE data and the ESI client.
 type Scanner struct {
 	SDE                *sde.Data
 	ESI                *esi.Client
 	History            HistoryProvider
+	HistorySources     []HistorySource         // fetched on History miss, in order; defaults to ESI
 	ContractsCache     *esi.ContractsCache     // Cache for contracts (5 min TTL)
 	ContractItemsCache *esi.ContractItemsCache // Cache for contract items (immutable)
+
+	// Wormholes supplies Thera/Turnur connections for scans with UseWormholes.
+	Wormholes WormholeSource
 }
 
 // NewScanner creates a Scanner with the given static data and ESI client.
@@ -91,6 +92,16 @@ func (s *Scanner) resolveStructureSystemID(locationID int64, fallbackSystemID in
 
 // Scan finds profitable flip opportunities based on the given parameters.
 func (s *Scanner) Scan(params ScanParams, progress func(string)) ([]FlipResult, error) {
+	return s.ScanWithContext(context.Background(), params, progress)
+}
+
+// ScanWithContext is Scan with cancellation: region fetches stop and ctx.Err()
+// is returned once ctx is done.
+func (s *Scanner) ScanWithContext(ctx context.Context, params ScanParams, progress func(string)) ([]FlipResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	s = s.withRouteShortcuts(ctx, params.UseWormholes, params.ansiblexEdges(), progress).withRoutePreferences(params.RoutePreferences)
 	progress("Finding systems within radius...")
 	var buySystems, sellSystems map[int32]int
 	var wg sync.WaitGroup
@@ -127,13 +138,33 @@ func (s *Scanner) Scan(params ScanParams, progress func(string)) ([]FlipResult,
 	log.Printf("[DEBUG] Scan: buySystems=%d, sellSystems=%d, buyRegions=%d, sellRegions=%d",
 		len(buySystems), len(sellSystems), len(buyRegions), len(sellRegions))
 
-	progress(fmt.Sprintf("Fetching orders from %d+%d regions...", len(buyRegions), len(sellRegions)))
-	idx := s.fetchAndIndex(params, buyRegions, buySystems, sellRegions, sellSystems)
+	progress(fmt.Sprintf("Fetching orders from %d+%d regions%s...", len(buyRegions), len(sellRegions), s.orderCacheNote(buyRegions, sellRegions)))
+	stopPageProgress := s.reportPageProgress(progress, buyRegions, sellRegions)
+	if params.FetchReport == nil {
+		params.FetchReport = &RegionFetchReport{}
+	}
+	idx := s.fetchAndIndex(ctx, params, buyRegions, buySystems, sellRegions, sellSystems)
+	stopPageProgress()
+	if err := checkContextCanceled(ctx); err != nil {
+		return nil, err
+	}
+	if err := checkRegionFailures(params, progress); err != nil {
+		return nil, err
+	}
 	return s.calculateResults(params, idx, buySystems, progress)
 }
 
 // ScanMultiRegion finds profitable flip opportunities across whole regions.
 func (s *Scanner) ScanMultiRegion(params ScanParams, progress func(string)) ([]FlipResult, error) {
+	return s.ScanMultiRegionWithContext(context.Background(), params, progress)
+}
+
+// ScanMultiRegionWithContext is ScanMultiRegion with cancellation.
+func (s *Scanner) ScanMultiRegionWithContext(ctx context.Context, params ScanParams, progress func(string)) ([]FlipResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	s = s.withRouteShortcuts(ctx, params.UseWormholes, params.ansiblexEdges(), progress).withRoutePreferences(params.RoutePreferences)
 	minSec := params.MinRouteSecurity
 	ignored := ignoredSystemSetFromIDs(params.IgnoredSystemIDs)
 
@@ -197,11 +228,90 @@ func (s *Scanner) ScanMultiRegion(params ScanParams, progress func(string)) ([]F
 	buyRegions = s.SDE.Universe.RegionsInSet(buySystems)
 	sellRegions = s.SDE.Universe.RegionsInSet(sellSystems)
 
-	progress(fmt.Sprintf("Fetching orders: buy from %d region(s), sell from %d region(s)...", len(buyRegions), len(sellRegions)))
-	idx := s.fetchAndIndex(params, buyRegions, buySystems, sellRegions, sellSystems)
+	progress(fmt.Sprintf("Fetching orders: buy from %d region(s), sell from %d region(s)%s...", len(buyRegions), len(sellRegions), s.orderCacheNote(buyRegions, sellRegions)))
+	stopPageProgress := s.reportPageProgress(progress, buyRegions, sellRegions)
+	if params.FetchReport == nil {
+		params.FetchReport = &RegionFetchReport{}
+	}
+	idx := s.fetchAndIndex(ctx, params, buyRegions, buySystems, sellRegions, sellSystems)
+	stopPageProgress()
+	if err := checkContextCanceled(ctx); err != nil {
+		return nil, err
+	}
+	if err := checkRegionFailures(params, progress); err != nil {
+		return nil, err
+	}
 	return s.calculateResults(params, idx, buySystemsRadius, progress)
 }
 
+// orderCacheNote describes the age of cached region order books for scan
+// progress messages, e.g. " (cached orders 2m10s old)". Empty when nothing
+// for these regions is cached yet.
+func (s *Scanner) orderCacheNote(regionSets ...map[int32]bool) string {
+	if s.ESI == nil {
+		return ""
+	}
+	var ids []int32
+	for _, set := range regionSets {
+		for rid := range set {
+			ids = append(ids, rid)
+		}
+	}
+	w := s.ESI.OrderCacheWindow(ids, "")
+	if w.Entries == 0 || w.OldestRefreshAt.IsZero() {
+		return ""
+	}
+	age := time.Since(w.OldestRefreshAt).Round(time.Second)
+	if w.Stale {
+		return fmt.Sprintf(" (cached orders %s old, refreshing expired regions)", age)
+	}
+	return fmt.Sprintf(" (cached orders %s old)", age)
+}
+
+// orderPageProgressInterval is how often page progress is polled during fetches.
+const orderPageProgressInterval = time.Second
+
+// reportPageProgress emits "N/M pages" progress for region order fetches in
+// flight until the returned stop function is called. progress is never called
+// after stop returns.
+func (s *Scanner) reportPageProgress(progress func(string), regionSets ...map[int32]bool) (stop func()) {
+	if s.ESI == nil || progress == nil {
+		return func() {}
+	}
+	regions := make(map[int32]bool)
+	for _, set := range regionSets {
+		for rid := range set {
+			regions[rid] = true
+		}
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(orderPageProgressInterval)
+		defer ticker.Stop()
+		var last esi.PageFetchProgress
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				p := s.ESI.OrderPageProgress(regions)
+				if p.Total == 0 || p == last {
+					continue
+				}
+				last = p
+				progress(fmt.Sprintf("Fetching orders: %d/%d pages from %d region(s)...", p.Fetched, p.Total, p.Regions))
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
 // --- Streaming order index types ---
 
 type sellInfo struct {
@@ -270,11 +380,15 @@ var hubRegionPriority = map[int32]int{
 // fetchOrdersStream starts fetching orders for all regions concurrently and
 // streams batches of filtered orders through the returned channel.
 // Hub regions are launched first so the pipeline starts building maps from
-// the largest data sets sooner.
+// the largest data sets sooner. Regions not yet fetched when ctx is done are
+// skipped and in-flight page requests are aborted. Failed regions are
+// recorded in report (may be nil).
 func (s *Scanner) fetchOrdersStream(
+	ctx context.Context,
 	regions map[int32]bool,
 	orderType string,
 	validSystems map[int32]int,
+	report *RegionFetchReport,
 ) <-chan []esi.MarketOrder {
 	ch := make(chan []esi.MarketOrder, len(regions))
 
@@ -303,8 +417,15 @@ func (s *Scanner) fetchOrdersStream(
 		wg.Add(1)
 		go func(rid int32) {
 			defer wg.Done()
-			orders, err := s.ESI.FetchRegionOrders(rid, orderType)
+			if err := ctx.Err(); err != nil {
+				if errors.Is(err, context.DeadlineExceeded) {
+					report.add(rid, orderType, err)
+				}
+				return
+			}
+			orders, err := s.ESI.FetchRegionOrdersContext(ctx, rid, orderType)
 			if err != nil {
+				report.add(rid, orderType, err)
 				return
 			}
 			// Filter to valid systems
@@ -335,19 +456,26 @@ func (s *Scanner) fetchOrdersStream(
 // fetchAndIndex launches parallel streaming fetches for sell and buy orders,
 // building the scanIndex incrementally as regions complete.
 func (s *Scanner) fetchAndIndex(
+	ctx context.Context,
 	params ScanParams,
 	buyRegions map[int32]bool, buySystems map[int32]int,
 	sellRegions map[int32]bool, sellSystems map[int32]int,
 ) *scanIndex {
-	sellCh := s.fetchOrdersStream(buyRegions, "sell", buySystems)
-	buyCh := s.fetchOrdersStream(sellRegions, "buy", sellSystems)
+	if params.FetchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, params.FetchTimeout)
+		defer cancel()
+	}
+	report := params.FetchReport
+	sellCh := s.fetchOrdersStream(ctx, buyRegions, "sell", buySystems, report)
+	buyCh := s.fetchOrdersStream(ctx, sellRegions, "buy", sellSystems, report)
 	// Additional sell-side sell-book stream for mathematically consistent S2B/BfS split.
-	sellSideSellCh := s.fetchOrdersStream(sellRegions, "sell", sellSystems)
+	sellSideSellCh := s.fetchOrdersStream(ctx, sellRegions, "sell", sellSystems, report)
 	var sourceBuyCh <-chan []esi.MarketOrder
 	enablePrivateStructureFetch := params.IncludeStructures && strings.TrimSpace(params.AccessToken) != ""
 	if enablePrivateStructureFetch {
 		// Source-side buy orders help discover structure IDs when source sell book is hidden in region endpoint.
-		sourceBuyCh = s.fetchOrdersStream(buyRegions, "buy", buySystems)
+		sourceBuyCh = s.fetchOrdersStream(ctx, buyRegions, "buy", buySystems, report)
 	} else if params.IncludeStructures {
 		log.Printf(
 			"[DEBUG] fetchAndIndex: include_structures=true but access token is missing; private structure sell fetch disabled",
@@ -489,7 +617,7 @@ func (s *Scanner) fetchAndIndex(
 			len(sourceStructureSystemIDs),
 		)
 	}
-	if enablePrivateStructureFetch && len(sourceStructureSystemIDs) > 0 {
+	if enablePrivateStructureFetch && len(sourceStructureSystemIDs) > 0 && ctx.Err() == nil {
 		s.mergeSourceStructureSellOrders(idx, sourceStructureSystemIDs, buySystems, params.AccessToken)
 	}
 
@@ -638,15 +766,21 @@ func (s *Scanner) calculateResults(
 	buyOrders := idx.buyOrders
 
 	progress("Calculating profits...")
-	buyCostMult, sellRevenueMult := tradeFeeMultipliers(tradeFeeInputs{
-		SplitTradeFees:       params.SplitTradeFees,
-		BrokerFeePercent:     params.BrokerFeePercent,
-		SalesTaxPercent:      params.SalesTaxPercent,
-		BuyBrokerFeePercent:  params.BuyBrokerFeePercent,
-		SellBrokerFeePercent: params.SellBrokerFeePercent,
-		BuySalesTaxPercent:   params.BuySalesTaxPercent,
-		SellSalesTaxPercent:  params.SellSalesTaxPercent,
-	})
+	fees := tradeFees{
+		in: tradeFeeInputs{
+			SplitTradeFees:       params.SplitTradeFees,
+			BrokerFeePercent:     params.BrokerFeePercent,
+			SalesTaxPercent:      params.SalesTaxPercent,
+			BuyBrokerFeePercent:  params.BuyBrokerFeePercent,
+			SellBrokerFeePercent: params.SellBrokerFeePercent,
+			BuySalesTaxPercent:   params.BuySalesTaxPercent,
+			SellSalesTaxPercent:  params.SellSalesTaxPercent,
+		},
+		brokers: params.BrokerFees,
+	}
+	// Per-pair fees never undercut the floor, so the per-type pre-filter
+	// cannot drop a pair that would be profitable.
+	floorBuyCostMult, floorSellRevenueMult := fees.floorMultipliers()
 
 	// For each (typeID, sellLocationID, buyLocationID) keep only the best-profit pair.
 	// This deduplicates multiple orders at the same location while preserving
@@ -778,8 +912,8 @@ func (s *Scanner) calculateResults(
 				expensiveBuy = buy.Price
 			}
 		}
-		bestEffBuy := cheapestSell * buyCostMult
-		bestEffSell := expensiveBuy * sellRevenueMult
+		bestEffBuy := cheapestSell * floorBuyCostMult
+		bestEffSell := expensiveBuy * floorSellRevenueMult
 		if bestEffSell <= bestEffBuy {
 			continue
 		}
@@ -800,6 +934,7 @@ func (s *Scanner) calculateResults(
 				if sellLocID == buyLocID {
 					continue
 				}
+				buyCostMult, sellRevenueMult := fees.multipliers(sellLocID, buyLocID)
 
 				targetSellSupply := int64(0)
 				targetLowestSell := 0.0
@@ -849,9 +984,9 @@ func (s *Scanner) calculateResults(
 					units = buy.VolumeRemain
 				}
 
-				// MaxInvestment filter
-				if params.MaxInvestment > 0 {
-					maxAfford := int32(params.MaxInvestment / effectiveBuyPrice)
+				// MaxInvestment / MaxBudget cap
+				if capital := params.capitalLimit(); capital > 0 {
+					maxAfford := int32(capital / effectiveBuyPrice)
 					if maxAfford <= 0 {
 						continue
 					}
@@ -955,7 +1090,12 @@ func (s *Scanner) calculateResults(
 
 	// Enrich with execution-plan expected prices (same order book, no extra ESI).
 	// Filter orders by location_id for more accurate slippage estimates.
-	if len(results) > 0 {
+	// NaivePricing skips the book walk and keeps top-of-book economics.
+	if len(results) > 0 && params.NaivePricing {
+		for i := range results {
+			results[i].RealProfit = results[i].TotalProfit
+		}
+	} else if len(results) > 0 {
 		progress("Expected fill prices...")
 		type locTypeKey struct {
 			locationID int64
@@ -976,6 +1116,7 @@ func (s *Scanner) calculateResults(
 		filtered := make([]FlipResult, 0, len(results))
 		for i := range results {
 			r := &results[i]
+			buyCostMult, sellRevenueMult := fees.multipliers(r.BuyLocationID, r.SellLocationID)
 			requestedQty := r.UnitsToBuy
 			var safeQty int32
 			var planBuy, planSell ExecutionPlanResult
@@ -1027,9 +1168,9 @@ func (s *Scanner) calculateResults(
 				continue
 			}
 			// Slippage can move actual required buy-side capital above pre-filter estimate.
-			if params.MaxInvestment > 0 {
+			if capital := params.capitalLimit(); capital > 0 {
 				execBuyCost := planBuy.TotalCost * buyCostMult
-				if execBuyCost > params.MaxInvestment {
+				if execBuyCost > capital {
 					continue
 				}
 			}
@@ -1053,6 +1194,7 @@ func (s *Scanner) calculateResults(
 			r.ExpectedSellPrice = planSell.ExpectedPrice
 			r.SlippageBuyPct = planBuy.SlippagePercent
 			r.SlippageSellPct = planSell.SlippagePercent
+			r.SlippagePct = sanitizeFloat(planBuy.SlippagePercent + planSell.SlippagePercent)
 			r.ExpectedProfit = expectedProfit
 			r.RealProfit = expectedProfit
 			filtered = append(filtered, *r)
@@ -1068,6 +1210,30 @@ func (s *Scanner) calculateResults(
 		})
 	}
 
+	// Jump-drive hauling: deduct isotope fuel for the buy→sell jump chain.
+	if params.JumpRangeLY > 0 && len(results) > 0 {
+		progress("Planning jump-drive routes...")
+		results = s.applyJumpFuelCosts(results, params)
+		sort.Slice(results, func(i, j int) bool {
+			if results[i].RealProfit == results[j].RealProfit {
+				return results[i].TotalProfit > results[j].TotalProfit
+			}
+			return results[i].RealProfit > results[j].RealProfit
+		})
+	}
+
+	if len(params.StructureAccessFees) > 0 {
+		results = applyStructureAccessFees(results, params.StructureAccessFees)
+	}
+
+	// Courier freight: price contracting the haul out (informational).
+	if params.freightEnabled() {
+		applyFreightCosts(results, params)
+	}
+	ApplyCargoLogistics(results, params.CargoCapacity)
+	ApplyFlipGankThresholds(results, params.ShipTravel().Class, params.ShipEHP, params.GankSplitTrips)
+	ApplyFlipISKPerHour(results, params.ShipTravel())
+
 	// OPT: prefetch station names in parallel (only for top N)
 	if len(results) > 0 {
 		progress("Fetching station names...")
@@ -1124,6 +1290,7 @@ func (s *Scanner) calculateResults(
 			results[i].FillTimeDays,
 			results[i].HistoryAvailable,
 		)
+		results[i].BuyFillDays, results[i].SellFillDays, _ = flipLegFillDays(results[i])
 	}
 
 	// Compute DailyProfit using cycle-constrained daily executable units.
@@ -1147,7 +1314,8 @@ func (s *Scanner) calculateResults(
 		params.MinS2BPerDay > 0 ||
 		params.MinBfSPerDay > 0 ||
 		params.MinS2BBfSRatio > 0 ||
-		params.MaxS2BBfSRatio > 0
+		params.MaxS2BBfSRatio > 0 ||
+		params.MaxFillDays > 0
 	if needsHistory {
 		filtered := make([]FlipResult, 0, len(results))
 		for _, r := range results {
@@ -1203,13 +1371,24 @@ func (s *Scanner) calculateResults(
 		results = filtered
 	}
 
+	if params.MaxFillDays > 0 {
+		filtered := make([]FlipResult, 0, len(results))
+		for _, r := range results {
+			buyDays, sellDays, ok := flipLegFillDays(r)
+			if ok && math.Max(buyDays, sellDays) <= params.MaxFillDays {
+				filtered = append(filtered, r)
+			}
+		}
+		results = filtered
+	}
+
 	progress(fmt.Sprintf("Found %d profitable trades", len(results)))
 	return results, nil
 }
 
-// fetchOrders is the legacy blocking version, kept for non-scan callers.
-func (s *Scanner) fetchOrders(regions map[int32]bool, orderType string, validSystems map[int32]int) []esi.MarketOrder {
-	ch := s.fetchOrdersStream(regions, orderType, validSystems)
+// fetchOrders is the blocking version of fetchOrdersStream.
+func (s *Scanner) fetchOrders(ctx context.Context, regions map[int32]bool, orderType string, validSystems map[int32]int, report *RegionFetchReport) []esi.MarketOrder {
+	ch := s.fetchOrdersStream(ctx, regions, orderType, validSystems, report)
 	var all []esi.MarketOrder
 	for batch := range ch {
 		all = append(all, batch...)
@@ -1564,18 +1743,12 @@ func (s *Scanner) enrichWithHistory(results []FlipResult, progress func(string))
 		go func(k historyKey, ns []historyNeed) {
 			defer func() { <-sem }()
 
-			// Try cache first
-			entries, ok := s.History.GetMarketHistory(k.regionID, k.typeID)
-			if !ok {
-				var err error
-				entries, err = s.ESI.FetchMarketHistory(k.regionID, k.typeID)
-				if err != nil {
-					for _, n := range ns {
-						ch <- histResult{idx: n.idx}
-					}
-					return
+			entries, err := s.MarketHistory(k.regionID, k.typeID)
+			if err != nil {
+				for _, n := range ns {
+					ch <- histResult{idx: n.idx}
 				}
-				s.History.SetMarketHistory(k.regionID, k.typeID, entries)
+				return
 			}
 			historyAvailable := len(entries) > 0
 
@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"math/rand"
@@ -430,6 +431,21 @@ func TestCalculateResults_TotalProfitUsesDepthAwareProfit(t *testing.T) {
 	if r.TotalProfit >= 10_000 {
 		t.Fatalf("TotalProfit still uses top-book fantasy profit: %f", r.TotalProfit)
 	}
+	if r.SlippagePct <= 0 || math.Abs(r.SlippagePct-(r.SlippageBuyPct+r.SlippageSellPct)) > 1e-9 {
+		t.Fatalf("SlippagePct = %f (buy %f, sell %f)", r.SlippagePct, r.SlippageBuyPct, r.SlippageSellPct)
+	}
+
+	naive, err := scanner.calculateResults(ScanParams{
+		CurrentSystemID: currentSys,
+		CargoCapacity:   100,
+		NaivePricing:    true,
+	}, idx, map[int32]int{currentSys: 0}, func(string) {})
+	if err != nil || len(naive) != 1 {
+		t.Fatalf("naive results = %+v, %v", naive, err)
+	}
+	if n := naive[0]; n.RealProfit != n.TotalProfit || n.ExpectedBuyPrice != 0 || n.SlippagePct != 0 || n.TotalProfit <= wantProfit {
+		t.Fatalf("naive result = profit %f real %f expected buy %f slippage %f", n.TotalProfit, n.RealProfit, n.ExpectedBuyPrice, n.SlippagePct)
+	}
 }
 
 func TestCalculateResults_SellOrderModePricesFullSourceDepth(t *testing.T) {
@@ -1029,17 +1045,18 @@ func TestFetchOrdersAndIndex_EmptyRegions(t *testing.T) {
 	regions := map[int32]bool{}
 	validSystems := map[int32]int{}
 
-	stream := scanner.fetchOrdersStream(regions, "sell", validSystems)
+	stream := scanner.fetchOrdersStream(context.Background(), regions, "sell", validSystems, nil)
 	if batch, ok := <-stream; ok {
 		t.Fatalf("expected closed stream for empty regions, got batch: %+v", batch)
 	}
 
-	orders := scanner.fetchOrders(regions, "buy", validSystems)
+	orders := scanner.fetchOrders(context.Background(), regions, "buy", validSystems, nil)
 	if len(orders) != 0 {
 		t.Fatalf("fetchOrders with empty regions returned %d orders, want 0", len(orders))
 	}
 
 	idx := scanner.fetchAndIndex(
+		context.Background(),
 		ScanParams{},
 		regions, validSystems,
 		regions, validSystems,
@@ -0,0 +1,198 @@
+package engine
+
+import (
+	"sort"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/pricing"
+	"eve-flipper/internal/sde"
+)
+
+// Sell advisor actions.
+const (
+	SellActionList    = "list"     // place a sell order
+	SellActionSellNow = "sell_now" // sell into the best buy order
+)
+
+// sellAdvisorHistoryDays is the history window for days-to-sell estimates.
+const sellAdvisorHistoryDays = 30
+
+// SellAdvisorStack is an asset stack to liquidate, located at a station or
+// structure (the root location of nested containers).
+type SellAdvisorStack struct {
+	TypeID       int32
+	Quantity     int64
+	LocationID   int64
+	LocationName string
+	SystemID     int32
+	RegionID     int32
+}
+
+// SellAdvisorInput holds the stacks and the market data gathered for them.
+type SellAdvisorInput struct {
+	Stacks []SellAdvisorStack
+	// Quotes by location: each stack's own station plus Jita.
+	Quotes map[int64]map[int32]pricing.Quote
+	// History is the market history by region and type.
+	History map[int32]map[int32][]esi.HistoryEntry
+	// Jumps returns the gate jumps between two systems, -1 if unreachable.
+	Jumps func(from, to int32) int
+}
+
+// SellAdvisorParams configures AdviseAssetSales.
+type SellAdvisorParams struct {
+	SalesTaxPercent  float64
+	BrokerFeePercent float64
+	// MarketSharePercent is the share of daily volume a listing is expected
+	// to capture when estimating days to sell.
+	MarketSharePercent float64
+	// HoldingCostPercentPerDay discounts slow listings: capital tied up in a
+	// stack that has not sold yet.
+	HoldingCostPercentPerDay float64
+	// MaxDays caps days to sell (and the holding cost) for types with little
+	// or no trade history.
+	MaxDays float64
+	// Courier pricing for moving a stack to Jita.
+	FreightISKPerM3Jump      float64
+	FreightCollateralPercent float64
+}
+
+// SellOption is one way to liquidate a stack.
+type SellOption struct {
+	Venue      string  `json:"venue"` // "local" or "jita"
+	LocationID int64   `json:"location_id"`
+	Action     string  `json:"action"`
+	UnitPrice  float64 `json:"unit_price"`
+	Net        float64 `json:"net"` // after sales tax and broker fee
+	HaulCost   float64 `json:"haul_cost"`
+	Jumps      int     `json:"jumps"`
+	DaysToSell float64 `json:"days_to_sell"`
+	Score      float64 `json:"score"` // net minus hauling and holding cost
+}
+
+// SellAdvice is the recommendation for one stack.
+type SellAdvice struct {
+	TypeID       int32        `json:"type_id"`
+	TypeName     string       `json:"type_name"`
+	Quantity     int64        `json:"quantity"`
+	VolumeM3     float64      `json:"volume_m3"`
+	LocationID   int64        `json:"location_id"`
+	LocationName string       `json:"location_name"`
+	LocalSell    float64      `json:"local_sell"`
+	JitaSell     float64      `json:"jita_sell"`
+	DailyVolume  float64      `json:"daily_volume"` // in the stack's region
+	Recommended  *SellOption  `json:"recommended"`  // nil when nothing quotes the type
+	Options      []SellOption `json:"options"`
+}
+
+// AdviseAssetSales compares listing each stack where it sits, selling it
+// into local buy orders and hauling it to Jita, and recommends the option
+// with the highest score, best first.
+func AdviseAssetSales(data *sde.Data, in SellAdvisorInput, p SellAdvisorParams) []SellAdvice {
+	if p.MaxDays <= 0 {
+		p.MaxDays = 90
+	}
+	share := p.MarketSharePercent / 100
+	if share <= 0 {
+		share = 1
+	}
+	listFee := (p.SalesTaxPercent + p.BrokerFeePercent) / 100
+	sellNowFee := p.SalesTaxPercent / 100
+
+	dailyVolume := func(regionID, typeID int32) float64 {
+		return avgDailyVolume(in.History[regionID][typeID], sellAdvisorHistoryDays)
+	}
+	daysToSell := func(regionID, typeID int32, qty int64) float64 {
+		daily := dailyVolume(regionID, typeID) * share
+		if daily <= 0 {
+			return p.MaxDays
+		}
+		return min(float64(qty)/daily, p.MaxDays)
+	}
+
+	out := make([]SellAdvice, 0, len(in.Stacks))
+	for _, st := range in.Stacks {
+		if st.TypeID <= 0 || st.Quantity <= 0 {
+			continue
+		}
+		a := SellAdvice{
+			TypeID:       st.TypeID,
+			Quantity:     st.Quantity,
+			LocationID:   st.LocationID,
+			LocationName: st.LocationName,
+			DailyVolume:  dailyVolume(st.RegionID, st.TypeID),
+			Options:      []SellOption{},
+		}
+		if t := data.Types[st.TypeID]; t != nil {
+			a.TypeName = t.Name
+			a.VolumeM3 = t.Volume * float64(st.Quantity)
+		}
+		qty := float64(st.Quantity)
+		local := in.Quotes[st.LocationID][st.TypeID]
+		jita := in.Quotes[JitaStationID][st.TypeID]
+		a.LocalSell, a.JitaSell = local.Sell, jita.Sell
+
+		if local.Sell > 0 {
+			a.Options = append(a.Options, SellOption{
+				Venue: "local", LocationID: st.LocationID, Action: SellActionList,
+				UnitPrice: local.Sell, Net: local.Sell * qty * (1 - listFee),
+				DaysToSell: daysToSell(st.RegionID, st.TypeID, st.Quantity),
+			})
+		}
+		if local.Buy > 0 {
+			a.Options = append(a.Options, SellOption{
+				Venue: "local", LocationID: st.LocationID, Action: SellActionSellNow,
+				UnitPrice: local.Buy, Net: local.Buy * qty * (1 - sellNowFee),
+			})
+		}
+		if st.LocationID != JitaStationID {
+			jumps := -1
+			if in.Jumps != nil {
+				jumps = in.Jumps(st.SystemID, JitaSystemID)
+			}
+			if jumps >= 0 {
+				haul := func(unit float64) float64 {
+					return freightReward(a.VolumeM3, courierJumps(jumps), unit*qty,
+						p.FreightISKPerM3Jump, p.FreightCollateralPercent)
+				}
+				if jita.Sell > 0 {
+					a.Options = append(a.Options, SellOption{
+						Venue: "jita", LocationID: JitaStationID, Action: SellActionList,
+						UnitPrice: jita.Sell, Net: jita.Sell * qty * (1 - listFee),
+						HaulCost: haul(jita.Sell), Jumps: jumps,
+						DaysToSell: daysToSell(JitaRegionID, st.TypeID, st.Quantity),
+					})
+				}
+				if jita.Buy > 0 {
+					a.Options = append(a.Options, SellOption{
+						Venue: "jita", LocationID: JitaStationID, Action: SellActionSellNow,
+						UnitPrice: jita.Buy, Net: jita.Buy * qty * (1 - sellNowFee),
+						HaulCost: haul(jita.Buy), Jumps: jumps,
+					})
+				}
+			}
+		}
+
+		for i := range a.Options {
+			o := &a.Options[i]
+			o.Score = sanitizeFloat(o.Net - o.HaulCost - o.Net*p.HoldingCostPercentPerDay/100*o.DaysToSell)
+		}
+		sort.SliceStable(a.Options, func(i, j int) bool { return a.Options[i].Score > a.Options[j].Score })
+		if len(a.Options) > 0 {
+			best := a.Options[0]
+			a.Recommended = &best
+		}
+		out = append(out, a)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return sellAdviceScore(out[i]) > sellAdviceScore(out[j])
+	})
+	return out
+}
+
+func sellAdviceScore(a SellAdvice) float64 {
+	if a.Recommended == nil {
+		return 0
+	}
+	return a.Recommended.Score
+}
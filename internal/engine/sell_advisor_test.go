@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/pricing"
+	"eve-flipper/internal/sde"
+)
+
+func sellAdvisorTestHistory(volume int64) []esi.HistoryEntry {
+	entries := make([]esi.HistoryEntry, 0, sellAdvisorHistoryDays)
+	for d := sellAdvisorHistoryDays - 1; d >= 0; d-- {
+		entries = append(entries, esi.HistoryEntry{
+			Date:   time.Now().UTC().AddDate(0, 0, -d).Format("2006-01-02"),
+			Volume: volume,
+		})
+	}
+	return entries
+}
+
+func TestAdviseAssetSales(t *testing.T) {
+	const amarr = int64(60008494)
+	data := &sde.Data{Types: map[int32]*sde.ItemType{34: {ID: 34, Name: "Tritanium", Volume: 0.01}}}
+	in := SellAdvisorInput{
+		Stacks: []SellAdvisorStack{{TypeID: 34, Quantity: 1000, LocationID: amarr, SystemID: 30002187, RegionID: 10000043}},
+		Quotes: map[int64]map[int32]pricing.Quote{
+			amarr:         {34: {Sell: 5, Buy: 4.5}},
+			JitaStationID: {34: {Sell: 6, Buy: 5.5}},
+		},
+		History: map[int32]map[int32][]esi.HistoryEntry{
+			10000043:     {34: sellAdvisorTestHistory(100)},
+			JitaRegionID: {34: sellAdvisorTestHistory(10000)},
+		},
+		Jumps: func(from, to int32) int { return 9 },
+	}
+	p := SellAdvisorParams{
+		SalesTaxPercent:          8,
+		BrokerFeePercent:         3,
+		MarketSharePercent:       100,
+		HoldingCostPercentPerDay: 1,
+	}
+
+	advice := AdviseAssetSales(data, in, p)
+	if len(advice) != 1 || len(advice[0].Options) != 4 {
+		t.Fatalf("advice = %+v", advice)
+	}
+	a := advice[0]
+	// Listing locally takes 10 days at 100/day; Jita sells out the same day.
+	if r := a.Recommended; r == nil || r.Venue != "jita" || r.Action != SellActionList {
+		t.Fatalf("recommended = %+v, want list in Jita", r)
+	}
+	for _, o := range a.Options {
+		if o.Venue == "local" && o.Action == SellActionList {
+			if o.DaysToSell != 10 || math.Abs(o.Score-(4450-445)) > 1e-6 {
+				t.Fatalf("local listing = %+v", o)
+			}
+		}
+	}
+
+	// Expensive freight makes selling into local buy orders the best exit.
+	p.FreightISKPerM3Jump = 100
+	a = AdviseAssetSales(data, in, p)[0]
+	if r := a.Recommended; r.Venue != "local" || r.Action != SellActionSellNow || math.Abs(r.Score-4140) > 1e-6 {
+		t.Fatalf("recommended = %+v, want sell now locally", r)
+	}
+}
@@ -0,0 +1,140 @@
+package engine
+
+import (
+	"math"
+	"sort"
+
+	"eve-flipper/internal/esi"
+)
+
+// BasketItem is one leg of a speculation basket: Quantity units of TypeID.
+type BasketItem struct {
+	TypeID   int32 `json:"type_id"`
+	Quantity int64 `json:"quantity"`
+}
+
+// BasketPoint is the basket's value on one day.
+type BasketPoint struct {
+	Date      string  `json:"date"`
+	Value     float64 `json:"value"`      // Σ quantity × daily average
+	ISKVolume float64 `json:"isk_volume"` // Σ units traded × daily average
+	Index     float64 `json:"index"`      // value relative to the first point, 100 = unchanged
+}
+
+// BasketSummary is the basket's recent movement.
+type BasketSummary struct {
+	Value             float64 `json:"value"`
+	Change1DPct       float64 `json:"change_1d_pct"`
+	Change7DPct       float64 `json:"change_7d_pct"`
+	Change30DPct      float64 `json:"change_30d_pct"`
+	VolumeChange7DPct float64 `json:"volume_change_7d_pct"` // last 7 days vs the 7 before
+	PricedItems       int     `json:"priced_items"`
+	Items             int     `json:"items"`
+}
+
+// BuildBasketSeries sums the items' daily history into one value per day over
+// the last days. A day with no trades in an item carries its last average
+// forward; the series starts once every item with history has a price, so
+// a late-listed item does not show up as a jump. Items without any history
+// are left out.
+func BuildBasketSeries(items []BasketItem, history map[int32][]esi.HistoryEntry, days int) []BasketPoint {
+	type dayEntry struct {
+		avg    float64
+		volume int64
+	}
+	byDate := make(map[string]map[int32]dayEntry)
+	priced := make(map[int32]bool)
+	for _, it := range items {
+		for _, h := range filterLastNDays(history[it.TypeID], days) {
+			if h.Average <= 0 {
+				continue
+			}
+			if byDate[h.Date] == nil {
+				byDate[h.Date] = make(map[int32]dayEntry)
+			}
+			byDate[h.Date][it.TypeID] = dayEntry{avg: h.Average, volume: h.Volume}
+			priced[it.TypeID] = true
+		}
+	}
+	dates := make([]string, 0, len(byDate))
+	for d := range byDate {
+		dates = append(dates, d)
+	}
+	sort.Strings(dates)
+
+	out := []BasketPoint{}
+	last := make(map[int32]float64, len(priced))
+	for _, date := range dates {
+		var p BasketPoint
+		p.Date = date
+		for _, it := range items {
+			if e, ok := byDate[date][it.TypeID]; ok {
+				last[it.TypeID] = e.avg
+				p.ISKVolume += float64(e.volume) * e.avg
+			}
+		}
+		if len(last) < len(priced) {
+			continue
+		}
+		for _, it := range items {
+			p.Value += float64(basketQuantity(it)) * last[it.TypeID]
+		}
+		out = append(out, p)
+	}
+	if len(out) > 0 && out[0].Value > 0 {
+		base := out[0].Value
+		for i := range out {
+			out[i].Index = sanitizeFloat(math.Round(out[i].Value/base*10000) / 100)
+		}
+	}
+	return out
+}
+
+// SummarizeBasket reports the latest value and its change over 1, 7 and 30
+// points of the series.
+func SummarizeBasket(items []BasketItem, history map[int32][]esi.HistoryEntry, series []BasketPoint) BasketSummary {
+	s := BasketSummary{Items: len(items)}
+	for _, it := range items {
+		if len(history[it.TypeID]) > 0 {
+			s.PricedItems++
+		}
+	}
+	n := len(series)
+	if n == 0 {
+		return s
+	}
+	s.Value = series[n-1].Value
+	s.Change1DPct = basketChangePct(series, 1)
+	s.Change7DPct = basketChangePct(series, 7)
+	s.Change30DPct = basketChangePct(series, 30)
+	if n >= 14 {
+		var recent, prior float64
+		for _, p := range series[n-7:] {
+			recent += p.ISKVolume
+		}
+		for _, p := range series[n-14 : n-7] {
+			prior += p.ISKVolume
+		}
+		if prior > 0 {
+			s.VolumeChange7DPct = sanitizeFloat(math.Round((recent/prior-1)*1000) / 10)
+		}
+	}
+	return s
+}
+
+// basketChangePct is the percent change of the last point against the one
+// back points earlier, or 0 when the series is too short.
+func basketChangePct(series []BasketPoint, back int) float64 {
+	n := len(series)
+	if n <= back || series[n-1-back].Value <= 0 {
+		return 0
+	}
+	return sanitizeFloat(math.Round((series[n-1].Value/series[n-1-back].Value-1)*1000) / 10)
+}
+
+func basketQuantity(it BasketItem) int64 {
+	if it.Quantity <= 0 {
+		return 1
+	}
+	return it.Quantity
+}
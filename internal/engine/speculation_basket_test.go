@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+func TestBuildBasketSeries(t *testing.T) {
+	day := func(n int) string { return time.Now().UTC().AddDate(0, 0, -n).Format("2006-01-02") }
+	history := map[int32][]esi.HistoryEntry{
+		// Listed a day late: the series starts on day 3.
+		34: {
+			{Date: day(3), Average: 10, Volume: 100},
+			{Date: day(1), Average: 12, Volume: 50},
+		},
+		35: {
+			{Date: day(4), Average: 99, Volume: 1},
+			{Date: day(3), Average: 100, Volume: 1},
+			{Date: day(2), Average: 110, Volume: 2},
+			{Date: day(1), Average: 110, Volume: 1},
+		},
+	}
+	items := []BasketItem{{TypeID: 34, Quantity: 10}, {TypeID: 35}, {TypeID: 36, Quantity: 5}}
+
+	series := BuildBasketSeries(items, history, 30)
+	if len(series) != 3 || series[0].Date != day(3) {
+		t.Fatalf("series = %+v", series)
+	}
+	// Day 2 carries Tritanium's 10 ISK forward.
+	want := []float64{200, 210, 230}
+	for i, v := range want {
+		if series[i].Value != v {
+			t.Fatalf("point %d = %+v, want value %v", i, series[i], v)
+		}
+	}
+	if series[2].Index != 115 || series[1].ISKVolume != 220 {
+		t.Fatalf("series = %+v", series)
+	}
+
+	s := SummarizeBasket(items, history, series)
+	if s.Value != 230 || s.Change1DPct != 9.5 || s.Change7DPct != 0 || s.PricedItems != 2 || s.Items != 3 {
+		t.Fatalf("summary = %+v", s)
+	}
+}
@@ -8,8 +8,10 @@ import (
 	"sort"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"eve-flipper/internal/esi"
+	"eve-flipper/internal/graph"
 )
 
 const (
@@ -21,17 +23,11 @@ const (
 )
 
 var (
-	stationFetchRegionOrders = func(c *esi.Client, regionID int32, orderType string) ([]esi.MarketOrder, error) {
+	stationFetchRegionOrders = func(ctx context.Context, c *esi.Client, regionID int32, orderType string) ([]esi.MarketOrder, error) {
 		if c == nil {
 			return nil, fmt.Errorf("nil ESI client")
 		}
-		return c.FetchRegionOrders(regionID, orderType)
-	}
-	stationFetchMarketHistory = func(c *esi.Client, regionID int32, typeID int32) ([]esi.HistoryEntry, error) {
-		if c == nil {
-			return nil, fmt.Errorf("nil ESI client")
-		}
-		return c.FetchMarketHistory(regionID, typeID)
+		return c.FetchRegionOrdersContext(ctx, regionID, orderType)
 	}
 	stationPrefetchNPCNames = func(c *esi.Client, ids map[int64]bool) {
 		if c == nil {
@@ -94,6 +90,11 @@ type StationTrade struct {
 	CharacterBuyOrders  int64   `json:"CharacterBuyOrders,omitempty"`
 	CharacterSellOrders int64   `json:"CharacterSellOrders,omitempty"`
 
+	// User's reference "sell at" station (see AnnotateStationTradeReference).
+	ReferenceStationID int64   `json:"ReferenceStationID,omitempty"`
+	ReferenceSellPrice float64 `json:"ReferenceSellPrice,omitempty"`
+	ReferenceDelta     float64 `json:"ReferenceDelta"` // SellPrice vs reference sell, %
+
 	// --- EVE Guru style metrics ---
 	CapitalRequired float64 `json:"CapitalRequired"` // Cycle capital: effectiveBuy * tradableUnits
 	NowROI          float64 `json:"NowROI"`          // Execution-aware ROI (slippage-aware when available; falls back to margin)
@@ -134,6 +135,9 @@ type StationTrade struct {
 	CanFill           bool    `json:"CanFill"`                  // whether target quantity is fully fillable
 	SlippageBuyPct    float64 `json:"SlippageBuyPct,omitempty"`
 	SlippageSellPct   float64 `json:"SlippageSellPct,omitempty"`
+
+	// tradableUnits is the book depth one cycle trades, capped by MaxBudget.
+	tradableUnits int64
 }
 
 // stationSortProxy returns a pre-history ranking score for a StationTrade.
@@ -376,6 +380,14 @@ type StationTradeParams struct {
 	BuySalesTaxPercent   float64
 	SellSalesTaxPercent  float64
 	MinDailyVolume       int64 // 0 = no filter
+	// MaxBudget is the ISK actually available (e.g. live wallet balance);
+	// cycle capital and total profit are capped to what it can buy, and
+	// items it cannot afford a unit of are skipped. 0 = unlimited.
+	MaxBudget float64
+	// BrokerFees is the character's broker fee schedule (see BrokerFeeSchedule).
+	BrokerFees *BrokerFeeSchedule
+	// RoutePreferences are the user's avoided systems and pinned paths.
+	RoutePreferences graph.RoutePreferences
 
 	// --- EVE Guru Profit Filters ---
 	MinItemProfit   float64 // Min profit per unit ISK (e.g. 1,000,000)
@@ -403,6 +415,13 @@ type StationTradeParams struct {
 
 	// Ctx allows cooperative cancellation for long-running station scans.
 	Ctx context.Context
+
+	// FetchTimeout bounds the region order fetch. 0 = no timeout.
+	FetchTimeout time.Duration
+	// FetchReport, when set, records a failed region fetch and the scan
+	// returns no rows instead of an error, so a multi-region scan can
+	// continue with partial results.
+	FetchReport *RegionFetchReport
 }
 
 // ScanStationTrades finds profitable same-station trading opportunities.
@@ -413,6 +432,7 @@ func isPlayerStructureID(id int64) bool {
 }
 
 func (s *Scanner) ScanStationTrades(params StationTradeParams, progress func(string)) ([]StationTrade, error) {
+	s = s.withRoutePreferences(params.RoutePreferences)
 	checkCanceled := func() error {
 		if params.Ctx == nil {
 			return nil
@@ -429,8 +449,24 @@ func (s *Scanner) ScanStationTrades(params StationTradeParams, progress func(str
 	progress("Fetching all region orders...")
 
 	// Fetch all orders for the region
-	allOrders, err := stationFetchRegionOrders(s.ESI, params.RegionID, "all")
+	fetchCtx := params.Ctx
+	if fetchCtx == nil {
+		fetchCtx = context.Background()
+	}
+	if params.FetchTimeout > 0 {
+		var cancel context.CancelFunc
+		fetchCtx, cancel = context.WithTimeout(fetchCtx, params.FetchTimeout)
+		defer cancel()
+	}
+	allOrders, err := stationFetchRegionOrders(fetchCtx, s.ESI, params.RegionID, "all")
 	if err != nil {
+		if cerr := checkCanceled(); cerr != nil {
+			return nil, cerr
+		}
+		if params.FetchReport != nil {
+			params.FetchReport.add(params.RegionID, "all", err)
+			return []StationTrade{}, nil
+		}
 		return nil, fmt.Errorf("fetch orders: %w", err)
 	}
 	if err := checkCanceled(); err != nil {
@@ -503,15 +539,18 @@ func (s *Scanner) ScanStationTrades(params StationTradeParams, progress func(str
 
 	progress(fmt.Sprintf("Analyzing %d items...", len(groups)))
 
-	buyCostMult, sellRevenueMult := tradeFeeMultipliers(tradeFeeInputs{
-		SplitTradeFees:       params.SplitTradeFees,
-		BrokerFeePercent:     params.BrokerFee,
-		SalesTaxPercent:      params.SalesTaxPercent,
-		BuyBrokerFeePercent:  params.BuyBrokerFeePercent,
-		SellBrokerFeePercent: params.SellBrokerFeePercent,
-		BuySalesTaxPercent:   params.BuySalesTaxPercent,
-		SellSalesTaxPercent:  params.SellSalesTaxPercent,
-	})
+	fees := tradeFees{
+		in: tradeFeeInputs{
+			SplitTradeFees:       params.SplitTradeFees,
+			BrokerFeePercent:     params.BrokerFee,
+			SalesTaxPercent:      params.SalesTaxPercent,
+			BuyBrokerFeePercent:  params.BuyBrokerFeePercent,
+			SellBrokerFeePercent: params.SellBrokerFeePercent,
+			BuySalesTaxPercent:   params.BuySalesTaxPercent,
+			SellSalesTaxPercent:  params.SellSalesTaxPercent,
+		},
+		brokers: params.BrokerFees,
+	}
 
 	var results []StationTrade
 	// Store order groups for advanced metrics calculation
@@ -525,6 +564,7 @@ func (s *Scanner) ScanStationTrades(params StationTradeParams, progress func(str
 		if len(g.buyOrders) == 0 || len(g.sellOrders) == 0 {
 			continue
 		}
+		buyCostMult, sellRevenueMult := fees.multipliers(key.locationID, key.locationID)
 
 		// Find highest buy and lowest sell
 		var highestBuy esi.MarketOrder
@@ -599,6 +639,12 @@ func (s *Scanner) ScanStationTrades(params StationTradeParams, progress func(str
 		// OBDS denominator should reflect actionable cycle capital, not full
 		// long-tail book not touched by this strategy.
 		tradableUnits := minInt64(totalBuyVol, totalSellVol)
+		if params.MaxBudget > 0 {
+			tradableUnits = minInt64(tradableUnits, int64(params.MaxBudget/effectiveBuy))
+			if tradableUnits <= 0 {
+				continue
+			}
+		}
 		// Cycle capital: ISK required to place the buy side of the trade
 		// for all tradable units (minimum of buy/sell depth).
 		capitalRequired := effectiveBuy * float64(tradableUnits)
@@ -636,6 +682,7 @@ func (s *Scanner) ScanStationTrades(params StationTradeParams, progress func(str
 			NowROI:          sanitizeFloat(margin), // initial fallback; refined from execution plans below
 			CI:              ci,
 			OBDS:            sanitizeFloat(obds),
+			tradableUnits:   tradableUnits,
 			// History-dependent fields will be calculated in enrichStationWithHistory
 		})
 
@@ -667,6 +714,7 @@ func (s *Scanner) ScanStationTrades(params StationTradeParams, progress func(str
 	for i := range results {
 		r := &results[i]
 		key := stationTypeKey{r.StationID, r.TypeID}
+		buyCostMult, sellRevenueMult := fees.multipliers(r.StationID, r.StationID)
 		if g, ok := orderGroups[key]; ok {
 			qty := stationExecutionDesiredQty(0, r.BuyVolume, r.SellVolume)
 			if qty > 0 {
@@ -895,15 +943,18 @@ func (s *Scanner) enrichStationWithHistory(results []StationTrade, regionID int3
 		avgPeriod = 90
 	}
 	ctsWeights := CTSWeightsForProfile(params.CTSProfile)
-	buyCostMult, sellRevenueMult := tradeFeeMultipliers(tradeFeeInputs{
-		SplitTradeFees:       params.SplitTradeFees,
-		BrokerFeePercent:     params.BrokerFee,
-		SalesTaxPercent:      params.SalesTaxPercent,
-		BuyBrokerFeePercent:  params.BuyBrokerFeePercent,
-		SellBrokerFeePercent: params.SellBrokerFeePercent,
-		BuySalesTaxPercent:   params.BuySalesTaxPercent,
-		SellSalesTaxPercent:  params.SellSalesTaxPercent,
-	})
+	fees := tradeFees{
+		in: tradeFeeInputs{
+			SplitTradeFees:       params.SplitTradeFees,
+			BrokerFeePercent:     params.BrokerFee,
+			SalesTaxPercent:      params.SalesTaxPercent,
+			BuyBrokerFeePercent:  params.BuyBrokerFeePercent,
+			SellBrokerFeePercent: params.SellBrokerFeePercent,
+			BuySalesTaxPercent:   params.BuySalesTaxPercent,
+			SellSalesTaxPercent:  params.SellSalesTaxPercent,
+		},
+		brokers: params.BrokerFees,
+	}
 
 	// Deduplicate history fetches by typeID (all results share the same regionID).
 	// This prevents N+1 / thundering-herd when multiple station+type rows map
@@ -933,15 +984,10 @@ func (s *Scanner) enrichStationWithHistory(results []StationTrade, regionID int3
 					fetchCh <- fetchResult{typeID, historyData{}}
 					return
 				}
-				entries, ok := s.History.GetMarketHistory(regionID, typeID)
-				if !ok {
-					var err error
-					entries, err = stationFetchMarketHistory(s.ESI, regionID, typeID)
-					if err != nil {
-						fetchCh <- fetchResult{typeID, historyData{}}
-						return
-					}
-					s.History.SetMarketHistory(regionID, typeID, entries)
+				entries, err := s.MarketHistory(regionID, typeID)
+				if err != nil {
+					fetchCh <- fetchResult{typeID, historyData{}}
+					return
 				}
 				fetchCh <- fetchResult{typeID, historyData{entries: entries, historyAvailable: len(entries) > 0}}
 			}(tid)
@@ -962,6 +1008,7 @@ func (s *Scanner) enrichStationWithHistory(results []StationTrade, regionID int3
 		if hd == nil {
 			hd = &historyData{}
 		}
+		buyCostMult, sellRevenueMult := fees.multipliers(results[idx].StationID, results[idx].StationID)
 
 		results[idx].HistoryAvailable = hd.historyAvailable
 		resetExecutionDerivedFields(&results[idx])
@@ -1123,10 +1170,14 @@ func (s *Scanner) enrichStationWithHistory(results []StationTrade, regionID int3
 			results[idx].RealizableDailyProfit = 0
 		}
 		results[idx].DailyProfit = sanitizeFloat(results[idx].RealizableDailyProfit)
-		// TotalProfit: full book spread profit (not daily). Gives the user a
-		// sense of total addressable opportunity on this item/station.
-		tradableUnits := float64(minInt64(results[idx].BuyVolume, results[idx].SellVolume))
-		results[idx].TotalProfit = sanitizeFloat(results[idx].ProfitPerUnit * tradableUnits)
+		// TotalProfit: full book spread profit (not daily), within MaxBudget.
+		// Gives the user a sense of total addressable opportunity on this
+		// item/station.
+		tradableUnits := results[idx].tradableUnits
+		if tradableUnits <= 0 {
+			tradableUnits = minInt64(results[idx].BuyVolume, results[idx].SellVolume)
+		}
+		results[idx].TotalProfit = sanitizeFloat(results[idx].ProfitPerUnit * float64(tradableUnits))
 
 		// Calculate CTS (Composite Trading Score)
 		results[idx].CTS = sanitizeFloat(CalcCTSWithWeights(
@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -22,13 +23,11 @@ func TestScanStationTrades_UsesFullRegionDepthWhenStationFiltered(t *testing.T)
 	origPrefetchNPC := stationPrefetchNPCNames
 	origPrefetchStr := stationPrefetchStructureNames
 	origResolveName := stationResolveName
-	origFetchHistory := stationFetchMarketHistory
 	defer func() {
 		stationFetchRegionOrders = origFetchOrders
 		stationPrefetchNPCNames = origPrefetchNPC
 		stationPrefetchStructureNames = origPrefetchStr
 		stationResolveName = origResolveName
-		stationFetchMarketHistory = origFetchHistory
 	}()
 
 	orders := []esi.MarketOrder{
@@ -40,7 +39,7 @@ func TestScanStationTrades_UsesFullRegionDepthWhenStationFiltered(t *testing.T)
 		{TypeID: typeID, LocationID: otherStation, SystemID: otherSystemID, Price: 101, VolumeRemain: 450, IsBuyOrder: false},
 	}
 
-	stationFetchRegionOrders = func(_ *esi.Client, rid int32, orderType string) ([]esi.MarketOrder, error) {
+	stationFetchRegionOrders = func(_ context.Context, _ *esi.Client, rid int32, orderType string) ([]esi.MarketOrder, error) {
 		if rid != regionID || orderType != "all" {
 			return nil, fmt.Errorf("unexpected region/orderType: %d/%s", rid, orderType)
 		}
@@ -54,13 +53,6 @@ func TestScanStationTrades_UsesFullRegionDepthWhenStationFiltered(t *testing.T)
 		}
 		return "Other Station"
 	}
-	stationFetchMarketHistory = func(_ *esi.Client, rid int32, tid int32) ([]esi.HistoryEntry, error) {
-		if rid != regionID || tid != typeID {
-			return nil, fmt.Errorf("unexpected history key: %d/%d", rid, tid)
-		}
-		return testHistoryFixedDailyVolume(100), nil // regionFlowPerDay = 100
-	}
-
 	scanner := &Scanner{
 		SDE: &sde.Data{
 			Types: map[int32]*sde.ItemType{
@@ -68,10 +60,14 @@ func TestScanStationTrades_UsesFullRegionDepthWhenStationFiltered(t *testing.T)
 			},
 		},
 		History: &testHistoryProvider{
-			store: map[string][]esi.HistoryEntry{
-				fmt.Sprintf("%d:%d", regionID, typeID): testHistoryFixedDailyVolume(100),
-			},
+			store: map[string][]esi.HistoryEntry{},
 		},
+		HistorySources: []HistorySource{HistorySourceFunc(func(rid int32, tid int32) ([]esi.HistoryEntry, error) {
+			if rid != regionID || tid != typeID {
+				return nil, fmt.Errorf("unexpected history key: %d/%d", rid, tid)
+			}
+			return testHistoryFixedDailyVolume(100), nil // regionFlowPerDay = 100
+		})},
 	}
 
 	results, err := scanner.ScanStationTrades(StationTradeParams{
@@ -100,4 +96,46 @@ func TestScanStationTrades_UsesFullRegionDepthWhenStationFiltered(t *testing.T)
 	if row.RegionID != regionID || row.SystemID != targetSystemID {
 		t.Fatalf("RegionID/SystemID = %d/%d, want %d/%d", row.RegionID, row.SystemID, regionID, targetSystemID)
 	}
+
+	budgeted, err := scanner.ScanStationTrades(StationTradeParams{
+		StationIDs: map[int64]bool{targetStation: true},
+		RegionID:   regionID,
+		MinMargin:  0.1,
+		MaxBudget:  900,
+	}, func(string) {})
+	if err != nil {
+		t.Fatalf("budgeted ScanStationTrades returned error: %v", err)
+	}
+	if len(budgeted) != 1 || budgeted[0].CapitalRequired <= 0 || budgeted[0].CapitalRequired > 900 {
+		t.Fatalf("budgeted rows = %+v, want capital within 900", budgeted)
+	}
+	if budgeted[0].TotalProfit >= row.TotalProfit {
+		t.Fatalf("budgeted TotalProfit = %v, want below unbudgeted %v", budgeted[0].TotalProfit, row.TotalProfit)
+	}
+}
+
+func TestScanStationTrades_RecordsFetchFailureInReport(t *testing.T) {
+	origFetchOrders := stationFetchRegionOrders
+	defer func() { stationFetchRegionOrders = origFetchOrders }()
+	stationFetchRegionOrders = func(_ context.Context, _ *esi.Client, _ int32, _ string) ([]esi.MarketOrder, error) {
+		return nil, fmt.Errorf("esi unavailable")
+	}
+	scanner := &Scanner{SDE: &sde.Data{}}
+
+	if _, err := scanner.ScanStationTrades(StationTradeParams{RegionID: 10000002}, func(string) {}); err == nil {
+		t.Fatal("fetch failure without report: want error")
+	}
+
+	report := &RegionFetchReport{}
+	results, err := scanner.ScanStationTrades(StationTradeParams{RegionID: 10000002, FetchReport: report}, func(string) {})
+	if err != nil || len(results) != 0 {
+		t.Fatalf("results, err = %v, %v; want no rows and no error", results, err)
+	}
+	if got := report.FailedRegions(); len(got) != 1 || got[0] != 10000002 {
+		t.Fatalf("failed regions = %v, want [10000002]", got)
+	}
+	zero := 0
+	if err := report.Check(&zero, nil); err == nil {
+		t.Fatal("Check with zero tolerance: want error")
+	}
 }
@@ -0,0 +1,248 @@
+package engine
+
+import (
+	"math"
+	"sort"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/sde"
+)
+
+// Stock line statuses.
+const (
+	StockStatusOK   = "ok"   // covered beyond the restock threshold
+	StockStatusLow  = "low"  // coverage below StockParams.LowDays
+	StockStatusOut  = "out"  // nothing left to sell
+	StockStatusIdle = "idle" // no sales in the velocity window
+)
+
+// StockPosition is one (station, type) a trader sells from: units waiting
+// in the hangar, units on open sell orders and own sales over the window.
+type StockPosition struct {
+	LocationID int64
+	SystemID   int32
+	RegionID   int32
+	TypeID     int32
+	InHangar   int64
+	Listed     int64
+	ListPrice  float64 // lowest own sell order, 0 when nothing is listed
+	SoldUnits  int64   // own sales at the station over StockParams.VelocityDays
+}
+
+// StockParams configures BuildStockReport.
+type StockParams struct {
+	VelocityDays int     // sales window for the daily velocity
+	LowDays      float64 // restock when coverage drops below this
+	CoverDays    float64 // restock up to this many days of sales
+}
+
+// StockSource is where to buy a restock.
+type StockSource struct {
+	LocationID int64   `json:"location_id"`
+	SystemID   int32   `json:"system_id"`
+	Jumps      int     `json:"jumps"`
+	Available  int64   `json:"available"`  // units on sale at or below the fill price
+	UnitPrice  float64 `json:"unit_price"` // average over the units bought
+}
+
+// StockSourceFinder returns the cheapest source of units near systemID.
+type StockSourceFinder func(typeID int32, systemID, regionID int32, units int64) (StockSource, bool)
+
+// StockLine is the coverage of one (station, type).
+type StockLine struct {
+	LocationID    int64        `json:"location_id"`
+	LocationName  string       `json:"location_name"`
+	TypeID        int32        `json:"type_id"`
+	TypeName      string       `json:"type_name"`
+	InHangar      int64        `json:"in_hangar"`
+	Listed        int64        `json:"listed"`
+	OnHand        int64        `json:"on_hand"`
+	ListPrice     float64      `json:"list_price"`
+	DailySales    float64      `json:"daily_sales"`
+	DaysCovered   float64      `json:"days_covered"`    // -1 without sales
+	RestockInDays float64      `json:"restock_in_days"` // until coverage hits LowDays; -1 without sales
+	RestockUnits  int64        `json:"restock_units"`
+	Status        string       `json:"status"`
+	Source        *StockSource `json:"source,omitempty"`
+	RestockCost   float64      `json:"restock_cost"`
+}
+
+// StockShoppingItem is one purchase on the restock shopping list.
+type StockShoppingItem struct {
+	TypeID           int32   `json:"type_id"`
+	TypeName         string  `json:"type_name"`
+	Units            int64   `json:"units"`
+	SourceLocationID int64   `json:"source_location_id"`
+	SourceSystemID   int32   `json:"source_system_id"`
+	Jumps            int     `json:"jumps"`
+	UnitPrice        float64 `json:"unit_price"`
+	Cost             float64 `json:"cost"`
+	ForLocationID    int64   `json:"for_location_id"`
+}
+
+// StockReport is the coverage view and the restock shopping list grouped
+// by source station.
+type StockReport struct {
+	Lines        []StockLine         `json:"lines"`
+	ShoppingList []StockShoppingItem `json:"shopping_list"`
+	RestockCost  float64             `json:"restock_cost"`
+}
+
+// BuildStockReport computes days of sales left for each position at the
+// trader's own sales velocity, flags the ones to restock and prices their
+// restock at the source findSource returns (may be nil). Lines are ordered
+// most urgent first.
+func BuildStockReport(data *sde.Data, positions []StockPosition, p StockParams, findSource StockSourceFinder) StockReport {
+	if p.VelocityDays <= 0 {
+		p.VelocityDays = 30
+	}
+	if p.CoverDays < p.LowDays {
+		p.CoverDays = p.LowDays
+	}
+	report := StockReport{Lines: []StockLine{}, ShoppingList: []StockShoppingItem{}}
+	for _, pos := range positions {
+		line := StockLine{
+			LocationID:    pos.LocationID,
+			TypeID:        pos.TypeID,
+			InHangar:      pos.InHangar,
+			Listed:        pos.Listed,
+			OnHand:        pos.InHangar + pos.Listed,
+			ListPrice:     pos.ListPrice,
+			DailySales:    sanitizeFloat(float64(pos.SoldUnits) / float64(p.VelocityDays)),
+			DaysCovered:   -1,
+			RestockInDays: -1,
+			Status:        StockStatusIdle,
+		}
+		if data != nil {
+			if t := data.Types[pos.TypeID]; t != nil {
+				line.TypeName = t.Name
+			}
+		}
+		if line.DailySales > 0 {
+			line.DaysCovered = sanitizeFloat(float64(line.OnHand) / line.DailySales)
+			line.RestockInDays = sanitizeFloat(max(line.DaysCovered-p.LowDays, 0))
+			switch {
+			case line.OnHand <= 0:
+				line.Status = StockStatusOut
+			case line.DaysCovered < p.LowDays:
+				line.Status = StockStatusLow
+			default:
+				line.Status = StockStatusOK
+			}
+			if line.Status != StockStatusOK {
+				line.RestockUnits = max(int64(math.Ceil(p.CoverDays*line.DailySales))-line.OnHand, 0)
+			}
+		}
+		if line.RestockUnits > 0 && findSource != nil {
+			if src, ok := findSource(pos.TypeID, pos.SystemID, pos.RegionID, line.RestockUnits); ok {
+				line.Source = &src
+				units := min(line.RestockUnits, src.Available)
+				line.RestockCost = sanitizeFloat(src.UnitPrice * float64(units))
+				report.RestockCost += line.RestockCost
+				report.ShoppingList = append(report.ShoppingList, StockShoppingItem{
+					TypeID:           pos.TypeID,
+					TypeName:         line.TypeName,
+					Units:            units,
+					SourceLocationID: src.LocationID,
+					SourceSystemID:   src.SystemID,
+					Jumps:            src.Jumps,
+					UnitPrice:        src.UnitPrice,
+					Cost:             line.RestockCost,
+					ForLocationID:    pos.LocationID,
+				})
+			}
+		}
+		report.Lines = append(report.Lines, line)
+	}
+	sort.SliceStable(report.Lines, func(i, j int) bool {
+		a, b := report.Lines[i], report.Lines[j]
+		if ra, rb := stockUrgency(a), stockUrgency(b); ra != rb {
+			return ra < rb
+		}
+		if a.DaysCovered != b.DaysCovered {
+			return a.DaysCovered < b.DaysCovered
+		}
+		return a.TypeID < b.TypeID
+	})
+	sort.SliceStable(report.ShoppingList, func(i, j int) bool {
+		a, b := report.ShoppingList[i], report.ShoppingList[j]
+		if a.SourceLocationID != b.SourceLocationID {
+			return a.SourceLocationID < b.SourceLocationID
+		}
+		return a.TypeName < b.TypeName
+	})
+	report.RestockCost = sanitizeFloat(report.RestockCost)
+	return report
+}
+
+func stockUrgency(l StockLine) int {
+	switch l.Status {
+	case StockStatusOut:
+		return 0
+	case StockStatusLow:
+		return 1
+	case StockStatusOK:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// CheapestStockSource picks the station among systems (system ID -> jumps)
+// that sells units of a type for the lowest average price, preferring
+// stations that can fill the whole quantity.
+func CheapestStockSource(orders []esi.MarketOrder, systems map[int32]int, units int64) (StockSource, bool) {
+	byLocation := make(map[int64][]esi.MarketOrder)
+	for _, o := range orders {
+		if o.IsBuyOrder || o.VolumeRemain <= 0 || o.Price <= 0 {
+			continue
+		}
+		if _, ok := systems[o.SystemID]; !ok {
+			continue
+		}
+		byLocation[o.LocationID] = append(byLocation[o.LocationID], o)
+	}
+	var best StockSource
+	found := false
+	for locationID, book := range byLocation {
+		sort.Slice(book, func(i, j int) bool { return book[i].Price < book[j].Price })
+		var filled int64
+		var cost float64
+		for _, o := range book {
+			take := min(int64(o.VolumeRemain), units-filled)
+			filled += take
+			cost += float64(take) * o.Price
+			if filled >= units {
+				break
+			}
+		}
+		src := StockSource{
+			LocationID: locationID,
+			SystemID:   book[0].SystemID,
+			Jumps:      systems[book[0].SystemID],
+			Available:  filled,
+			UnitPrice:  sanitizeFloat(cost / float64(filled)),
+		}
+		if !found || stockSourceBetter(src, best, units) {
+			best, found = src, true
+		}
+	}
+	return best, found
+}
+
+func stockSourceBetter(a, b StockSource, units int64) bool {
+	aFull, bFull := a.Available >= units, b.Available >= units
+	if aFull != bFull {
+		return aFull
+	}
+	if !aFull && a.Available != b.Available {
+		return a.Available > b.Available
+	}
+	if a.UnitPrice != b.UnitPrice {
+		return a.UnitPrice < b.UnitPrice
+	}
+	if a.Jumps != b.Jumps {
+		return a.Jumps < b.Jumps
+	}
+	return a.LocationID < b.LocationID
+}
@@ -0,0 +1,74 @@
+package engine
+
+import (
+	"math"
+	"testing"
+
+	"eve-flipper/internal/esi"
+	"eve-flipper/internal/sde"
+)
+
+func TestBuildStockReport(t *testing.T) {
+	data := &sde.Data{Types: map[int32]*sde.ItemType{
+		34: {ID: 34, Name: "Tritanium"},
+		35: {ID: 35, Name: "Pyerite"},
+		36: {ID: 36, Name: "Mexallon"},
+	}}
+	positions := []StockPosition{
+		{LocationID: 1, SystemID: 10, TypeID: 34, InHangar: 200, Listed: 100, SoldUnits: 300}, // 10/day, 30 days
+		{LocationID: 1, SystemID: 10, TypeID: 35, Listed: 50, SoldUnits: 300},                 // 10/day, 5 days
+		{LocationID: 1, SystemID: 10, TypeID: 36, InHangar: 10},                               // no sales
+	}
+	p := StockParams{VelocityDays: 30, LowDays: 7, CoverDays: 14}
+	var asked int64
+	find := func(typeID, systemID, regionID int32, units int64) (StockSource, bool) {
+		asked = units
+		return StockSource{LocationID: 2, SystemID: 11, Jumps: 1, Available: units, UnitPrice: 4}, true
+	}
+
+	report := BuildStockReport(data, positions, p, find)
+	if len(report.Lines) != 3 {
+		t.Fatalf("lines = %+v", report.Lines)
+	}
+	low, ok, idle := report.Lines[0], report.Lines[1], report.Lines[2]
+	if low.TypeID != 35 || low.Status != StockStatusLow || low.DaysCovered != 5 || low.RestockInDays != 0 {
+		t.Fatalf("low = %+v", low)
+	}
+	// 14 days at 10/day minus the 50 still listed.
+	if low.RestockUnits != 90 || asked != 90 || math.Abs(low.RestockCost-360) > 1e-9 {
+		t.Fatalf("restock = %d units (asked %d), cost %v", low.RestockUnits, asked, low.RestockCost)
+	}
+	if ok.TypeID != 34 || ok.Status != StockStatusOK || ok.DaysCovered != 30 || ok.RestockInDays != 23 || ok.RestockUnits != 0 {
+		t.Fatalf("ok = %+v", ok)
+	}
+	if idle.TypeID != 36 || idle.Status != StockStatusIdle || idle.DaysCovered != -1 {
+		t.Fatalf("idle = %+v", idle)
+	}
+	if len(report.ShoppingList) != 1 || report.ShoppingList[0].TypeName != "Pyerite" || report.RestockCost != 360 {
+		t.Fatalf("shopping list = %+v, cost %v", report.ShoppingList, report.RestockCost)
+	}
+}
+
+func TestCheapestStockSource(t *testing.T) {
+	orders := []esi.MarketOrder{
+		{LocationID: 1, SystemID: 10, Price: 5, VolumeRemain: 100},
+		{LocationID: 2, SystemID: 11, Price: 3, VolumeRemain: 20},
+		{LocationID: 2, SystemID: 11, Price: 4, VolumeRemain: 80},
+		{LocationID: 3, SystemID: 12, Price: 1, VolumeRemain: 10}, // out of range
+		{LocationID: 4, SystemID: 10, Price: 2, VolumeRemain: 30}, // cannot fill
+		{LocationID: 1, SystemID: 10, Price: 1, VolumeRemain: 500, IsBuyOrder: true},
+	}
+	systems := map[int32]int{10: 0, 11: 2}
+
+	src, ok := CheapestStockSource(orders, systems, 100)
+	// 20 @ 3 + 80 @ 4 = 380 beats 100 @ 5.
+	if !ok || src.LocationID != 2 || src.Jumps != 2 || src.Available != 100 || math.Abs(src.UnitPrice-3.8) > 1e-9 {
+		t.Fatalf("source = %+v", src)
+	}
+	if src, ok = CheapestStockSource(orders, systems, 25); !ok || src.LocationID != 4 || src.UnitPrice != 2 {
+		t.Fatalf("small restock source = %+v", src)
+	}
+	if _, ok = CheapestStockSource(nil, systems, 10); ok {
+		t.Fatal("expected no source without orders")
+	}
+}
@@ -0,0 +1,30 @@
+package engine
+
+// applyStructureAccessFees deducts the per-visit access fee of the buy and
+// sell structures from each result's profit. Results left without profit
+// are dropped.
+func applyStructureAccessFees(results []FlipResult, fees map[int64]float64) []FlipResult {
+	out := results[:0]
+	for _, r := range results {
+		fee := fees[r.BuyLocationID]
+		if r.SellLocationID != r.BuyLocationID {
+			fee += fees[r.SellLocationID]
+		}
+		if fee > 0 {
+			r.StructureAccessFee = fee
+			r.TotalProfit = sanitizeFloat(r.TotalProfit - fee)
+			if r.RealProfit != 0 {
+				r.RealProfit = sanitizeFloat(r.RealProfit - fee)
+				r.ExpectedProfit = r.RealProfit
+			}
+			if r.TotalProfit <= 0 {
+				continue
+			}
+			if r.TotalJumps > 0 {
+				r.ProfitPerJump = sanitizeFloat(FlipResultKPIProfit(r) / float64(r.TotalJumps))
+			}
+		}
+		out = append(out, r)
+	}
+	return out
+}
@@ -0,0 +1,23 @@
+package engine
+
+import "testing"
+
+func TestApplyStructureAccessFees(t *testing.T) {
+	const structure = int64(1_035_466_617_946)
+	results := []FlipResult{
+		{TypeID: 1, BuyLocationID: 60003760, SellLocationID: structure, TotalProfit: 3_000_000, RealProfit: 2_500_000, TotalJumps: 5},
+		{TypeID: 2, BuyLocationID: structure, SellLocationID: structure, TotalProfit: 800_000, TotalJumps: 0},
+		{TypeID: 3, BuyLocationID: 60003760, SellLocationID: 60008494, TotalProfit: 100, TotalJumps: 9},
+	}
+	out := applyStructureAccessFees(results, map[int64]float64{structure: 1_000_000})
+	if len(out) != 2 {
+		t.Fatalf("results = %+v", out)
+	}
+	if r := out[0]; r.StructureAccessFee != 1_000_000 || r.TotalProfit != 2_000_000 || r.RealProfit != 1_500_000 || r.ProfitPerJump != 300_000 {
+		t.Fatalf("structure sell = %+v", r)
+	}
+	// A result the fees wipe out is dropped; NPC-only trades are untouched.
+	if r := out[1]; r.TypeID != 3 || r.StructureAccessFee != 0 || r.TotalProfit != 100 {
+		t.Fatalf("npc trade = %+v", r)
+	}
+}
@@ -0,0 +1,111 @@
+package engine
+
+import (
+	"fmt"
+
+	"eve-flipper/internal/esi"
+)
+
+// Order-slot and remote-trading skill type IDs.
+const (
+	SkillTrade       int32 = 3443
+	SkillRetail      int32 = 3444
+	SkillWholesale   int32 = 16596
+	SkillTycoon      int32 = 18580
+	SkillMarketing   int32 = 16598
+	SkillProcurement int32 = 16594
+	SkillDaytrading  int32 = 16595
+)
+
+// Open order slots: 5 base, plus 4 per Trade, 8 per Retail, 16 per
+// Wholesale and 32 per Tycoon level (305 with all at V).
+const (
+	orderSlotsBase         = 5
+	orderSlotsPerTrade     = 4
+	orderSlotsPerRetail    = 8
+	orderSlotsPerWholesale = 16
+	orderSlotsPerTycoon    = 32
+)
+
+// remoteOrderRanges is the range Marketing, Procurement and Daytrading allow
+// per level: the character's station at 0, the whole region at V.
+var remoteOrderRanges = [6]string{"station", "system", "5 jumps", "10 jumps", "20 jumps", "region"}
+
+// TradingLimits are the order slots and remote trading ranges a character's
+// skills allow.
+type TradingLimits struct {
+	CharacterID       int64  `json:"character_id,omitempty"`
+	CharacterName     string `json:"character_name,omitempty"`
+	MaxOrders         int    `json:"max_orders"`
+	OpenOrders        int    `json:"open_orders"`
+	FreeSlots         int    `json:"free_slots"`
+	Trade             int    `json:"trade"`
+	Retail            int    `json:"retail"`
+	Wholesale         int    `json:"wholesale"`
+	Tycoon            int    `json:"tycoon"`
+	RemoteSellRange   string `json:"remote_sell_range"`   // Marketing
+	RemoteBuyRange    string `json:"remote_buy_range"`    // Procurement
+	RemoteModifyRange string `json:"remote_modify_range"` // Daytrading
+}
+
+// ComputeTradingLimits reads the order-slot and remote range skills from
+// the sheet. openOrders is the character's own open order count.
+func ComputeTradingLimits(skills *esi.SkillSheet, openOrders int) TradingLimits {
+	l := TradingLimits{
+		Trade:      SkillLevel(skills, SkillTrade),
+		Retail:     SkillLevel(skills, SkillRetail),
+		Wholesale:  SkillLevel(skills, SkillWholesale),
+		Tycoon:     SkillLevel(skills, SkillTycoon),
+		OpenOrders: openOrders,
+	}
+	l.MaxOrders = orderSlotsBase +
+		orderSlotsPerTrade*l.Trade +
+		orderSlotsPerRetail*l.Retail +
+		orderSlotsPerWholesale*l.Wholesale +
+		orderSlotsPerTycoon*l.Tycoon
+	l.FreeSlots = max(0, l.MaxOrders-openOrders)
+	l.RemoteSellRange = remoteOrderRange(SkillLevel(skills, SkillMarketing))
+	l.RemoteBuyRange = remoteOrderRange(SkillLevel(skills, SkillProcurement))
+	l.RemoteModifyRange = remoteOrderRange(SkillLevel(skills, SkillDaytrading))
+	return l
+}
+
+func remoteOrderRange(level int) string {
+	return remoteOrderRanges[max(0, min(5, level))]
+}
+
+// OrderSlotCheck compares the orders a scan's rows would need with the free
+// slots.
+type OrderSlotCheck struct {
+	MaxOrders    int    `json:"max_orders"`
+	OpenOrders   int    `json:"open_orders"`
+	FreeSlots    int    `json:"free_slots"`
+	OrdersPerRow int    `json:"orders_per_row"`
+	RowsThatFit  int    `json:"rows_that_fit"`
+	Exceeds      bool   `json:"exceeds"`
+	Warning      string `json:"warning,omitempty"`
+}
+
+// CheckOrderSlots reports how many of rows, each needing ordersPerRow open
+// orders (2 for a station trade, 1 for a hauled flip's sell order), fit in
+// the free slots.
+func CheckOrderSlots(l TradingLimits, rows, ordersPerRow int) OrderSlotCheck {
+	ordersPerRow = max(1, ordersPerRow)
+	c := OrderSlotCheck{
+		MaxOrders:    l.MaxOrders,
+		OpenOrders:   l.OpenOrders,
+		FreeSlots:    l.FreeSlots,
+		OrdersPerRow: ordersPerRow,
+		RowsThatFit:  min(rows, l.FreeSlots/ordersPerRow),
+	}
+	if rows*ordersPerRow > l.FreeSlots {
+		c.Exceeds = true
+		switch {
+		case l.FreeSlots == 0:
+			c.Warning = fmt.Sprintf("all %d order slots are in use", l.MaxOrders)
+		default:
+			c.Warning = fmt.Sprintf("%d free order slots cover the top %d of %d results", l.FreeSlots, c.RowsThatFit, rows)
+		}
+	}
+	return c
+}
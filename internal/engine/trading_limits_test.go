@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"testing"
+
+	"eve-flipper/internal/esi"
+)
+
+func TestComputeTradingLimits(t *testing.T) {
+	skills := &esi.SkillSheet{Skills: []esi.SkillEntry{
+		{SkillID: SkillTrade, ActiveLevel: 5},
+		{SkillID: SkillRetail, ActiveLevel: 5},
+		{SkillID: SkillWholesale, ActiveLevel: 4},
+		{SkillID: SkillMarketing, ActiveLevel: 3},
+		{SkillID: SkillProcurement, ActiveLevel: 5},
+	}}
+	l := ComputeTradingLimits(skills, 100)
+	// 5 + 20 + 40 + 64 = 129
+	if l.MaxOrders != 129 || l.FreeSlots != 29 {
+		t.Fatalf("slots = %d max / %d free, want 129 / 29", l.MaxOrders, l.FreeSlots)
+	}
+	if l.RemoteSellRange != "10 jumps" || l.RemoteBuyRange != "region" || l.RemoteModifyRange != "station" {
+		t.Fatalf("ranges = %s / %s / %s", l.RemoteSellRange, l.RemoteBuyRange, l.RemoteModifyRange)
+	}
+
+	if got := ComputeTradingLimits(nil, 9); got.MaxOrders != 5 || got.FreeSlots != 0 {
+		t.Fatalf("untrained = %+v, want 5 max, 0 free", got)
+	}
+}
+
+func TestCheckOrderSlots(t *testing.T) {
+	l := TradingLimits{MaxOrders: 129, OpenOrders: 100, FreeSlots: 29}
+
+	c := CheckOrderSlots(l, 40, 2)
+	if !c.Exceeds || c.RowsThatFit != 14 || c.Warning == "" {
+		t.Fatalf("station rows = %+v, want 14 fitting with a warning", c)
+	}
+	if c := CheckOrderSlots(l, 20, 1); c.Exceeds || c.RowsThatFit != 20 {
+		t.Fatalf("flip rows = %+v, want all 20 fitting", c)
+	}
+	if c := CheckOrderSlots(TradingLimits{MaxOrders: 5, OpenOrders: 5}, 3, 1); !c.Exceeds || c.RowsThatFit != 0 {
+		t.Fatalf("full = %+v", c)
+	}
+}
+
+func TestOrderDeskSetTradingLimits(t *testing.T) {
+	desk := ComputeOrderDesk(nil, nil, nil, nil, OrderDeskOptions{})
+	desk.SetTradingLimits([]TradingLimits{
+		{CharacterID: 1, MaxOrders: 129, OpenOrders: 100, FreeSlots: 29},
+		{CharacterID: 2, MaxOrders: 305, OpenOrders: 5, FreeSlots: 300},
+	})
+	if desk.Summary.MaxOrders != 434 || desk.Summary.FreeOrderSlots != 329 || len(desk.TradingLimits) != 2 {
+		t.Fatalf("summary = %+v, limits = %d", desk.Summary, len(desk.TradingLimits))
+	}
+}
@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"math"
+	"strings"
+)
+
+// Travel heuristics for one gate jump and one station stop.
+const (
+	metersPerAU = 149_597_870_700.0
+	// travelGateWarpAU is a typical gate-to-gate warp across a system.
+	travelGateWarpAU = 15.0
+	// travelDockWarpAU is a typical gate-to-station warp.
+	travelDockWarpAU = 5.0
+	// travelGateSeconds covers the jump, session change and gate cloak.
+	travelGateSeconds = 20.0
+	// travelDockSeconds covers docking, trading and undocking.
+	travelDockSeconds = 60.0
+	// travelSubwarpMS is the speed at which a ship drops out of warp.
+	travelSubwarpMS = 100.0
+)
+
+// ShipTravel is how fast a ship class gets through a system: align time
+// before each warp and maximum warp speed.
+type ShipTravel struct {
+	Class        string  `json:"class"`
+	AlignSeconds float64 `json:"align_seconds"`
+	WarpSpeedAU  float64 `json:"warp_speed_au"` // AU/s
+}
+
+// shipTravelClasses are typical fitted values per hauling class; the names
+// match the route ship profiles.
+var shipTravelClasses = map[string]ShipTravel{
+	"fast_frigate":         {Class: "fast_frigate", AlignSeconds: 2.5, WarpSpeedAU: 8},
+	"sunesis":              {Class: "sunesis", AlignSeconds: 4, WarpSpeedAU: 5},
+	"industrial":           {Class: "industrial", AlignSeconds: 9, WarpSpeedAU: 4.5},
+	"blockade_runner":      {Class: "blockade_runner", AlignSeconds: 4.5, WarpSpeedAU: 6},
+	"deep_space_transport": {Class: "deep_space_transport", AlignSeconds: 12, WarpSpeedAU: 4.5},
+	"freighter":            {Class: "freighter", AlignSeconds: 40, WarpSpeedAU: 1.37},
+}
+
+// defaultShipTravel is used for unknown classes: a cruiser-sized hauler.
+var defaultShipTravel = ShipTravel{Class: "custom", AlignSeconds: 8, WarpSpeedAU: 3}
+
+// ShipTravelFor returns the travel profile of class with positive
+// alignSeconds and warpSpeedAU overriding the class values.
+func ShipTravelFor(class string, alignSeconds, warpSpeedAU float64) ShipTravel {
+	t, ok := shipTravelClasses[strings.ToLower(strings.TrimSpace(class))]
+	if !ok {
+		t = defaultShipTravel
+	}
+	if isPositiveFinite(alignSeconds) {
+		t.AlignSeconds = alignSeconds
+	}
+	if isPositiveFinite(warpSpeedAU) {
+		t.WarpSpeedAU = warpSpeedAU
+	}
+	return t
+}
+
+// WarpSeconds is the time to warp distanceAU at a maximum warp speed of
+// warpSpeedAU. Warp accelerates exponentially at k = warp speed, and
+// decelerates at min(k/3, 2) down to subwarp speed; on short warps the ship
+// never reaches full speed.
+func WarpSeconds(distanceAU, warpSpeedAU float64) float64 {
+	if !isPositiveFinite(distanceAU) || !isPositiveFinite(warpSpeedAU) {
+		return 0
+	}
+	ka := warpSpeedAU
+	kd := math.Min(ka/3, 2)
+	vmax := warpSpeedAU * metersPerAU
+	dist := distanceAU * metersPerAU
+	// Accelerating to v covers v/ka metres, decelerating from it v/kd.
+	if reach := vmax/ka + vmax/kd; reach > dist {
+		vmax = dist * ka * kd / (ka + kd)
+	}
+	cruise := (dist - vmax/ka - vmax/kd) / (warpSpeedAU * metersPerAU)
+	accel := math.Log(math.Max(vmax/ka, 1)) / ka
+	decel := math.Log(math.Max(vmax/travelSubwarpMS, 1)) / kd
+	return accel + math.Max(cruise, 0) + decel
+}
+
+// MinutesPerJump is one gate jump: align, warp to the next gate, jump.
+func (t ShipTravel) MinutesPerJump() float64 {
+	return (t.AlignSeconds + WarpSeconds(travelGateWarpAU, t.WarpSpeedAU) + travelGateSeconds) / 60
+}
+
+// DockMinutes is one station stop: align, warp to the station, dock and
+// trade.
+func (t ShipTravel) DockMinutes() float64 {
+	return (t.AlignSeconds + WarpSeconds(travelDockWarpAU, t.WarpSpeedAU) + travelDockSeconds) / 60
+}
+
+// HaulMinutes is the time to fly emptyJumps to the pickup, then carry the
+// cargo haulJumps in trips loads (returning empty between loads), docking
+// at both ends of every load.
+func (t ShipTravel) HaulMinutes(emptyJumps, haulJumps, trips int) float64 {
+	trips = max(trips, 1)
+	jumps := max(emptyJumps, 0) + (2*trips-1)*max(haulJumps, 0)
+	return float64(jumps)*t.MinutesPerJump() + float64(2*trips)*t.DockMinutes()
+}
+
+// ISKPerHour is profit over minutes of flying, or 0 without either.
+func ISKPerHour(profit, minutes float64) float64 {
+	if profit <= 0 || !isPositiveFinite(minutes) {
+		return 0
+	}
+	return sanitizeFloat(profit / (minutes / 60))
+}
+
+// ApplyFlipISKPerHour sets the travel time and ISK/hour of each flip: fly
+// to the buy station, haul CargoTrips loads to the sell station, plus any
+// jump-drive waits.
+func ApplyFlipISKPerHour(results []FlipResult, t ShipTravel) {
+	for i := range results {
+		r := &results[i]
+		minutes := t.HaulMinutes(r.BuyJumps, r.SellJumps, r.CargoTrips) + r.JumpWaitMinutes
+		r.ExecutionMinutes = sanitizeFloat(math.Round(minutes*10) / 10)
+		r.ProfitPerHour = ISKPerHour(FlipResultKPIProfit(*r), r.ExecutionMinutes)
+	}
+}
+
+// ShipTravel returns the scan's ship travel profile.
+func (p ScanParams) ShipTravel() ShipTravel {
+	return ShipTravelFor(p.ShipClass, p.ShipAlignSeconds, p.ShipWarpSpeedAU)
+}
@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWarpSeconds(t *testing.T) {
+	frigate := WarpSeconds(15, 8)
+	freighter := WarpSeconds(15, 1.37)
+	if frigate <= 0 || freighter <= frigate {
+		t.Fatalf("warp 15 AU: frigate %.1fs, freighter %.1fs", frigate, freighter)
+	}
+	// A freighter spends most of a 15 AU warp accelerating and braking.
+	if freighter < 60 || freighter > 90 {
+		t.Fatalf("freighter warp = %.1fs, want ~74s", freighter)
+	}
+	// Short warps never reach full speed but still take time.
+	short := WarpSeconds(0.5, 8)
+	if short <= 0 || short >= frigate {
+		t.Fatalf("short warp = %.1fs, full warp %.1fs", short, frigate)
+	}
+	if WarpSeconds(0, 5) != 0 || WarpSeconds(10, 0) != 0 {
+		t.Fatalf("degenerate warps should be 0")
+	}
+}
+
+func TestShipTravelForOverrides(t *testing.T) {
+	tr := ShipTravelFor("Freighter", 0, 0)
+	if tr.Class != "freighter" || tr.AlignSeconds != 40 {
+		t.Fatalf("freighter = %+v", tr)
+	}
+	tr = ShipTravelFor("freighter", 30, 2)
+	if tr.AlignSeconds != 30 || tr.WarpSpeedAU != 2 {
+		t.Fatalf("overridden = %+v", tr)
+	}
+	if ShipTravelFor("titan", 0, 0).Class != "custom" {
+		t.Fatalf("unknown class should fall back to custom")
+	}
+	if ShipTravelFor("freighter", 0, 0).MinutesPerJump() <= ShipTravelFor("fast_frigate", 0, 0).MinutesPerJump() {
+		t.Fatalf("freighter jumps should be slower than frigate jumps")
+	}
+}
+
+func TestApplyFlipISKPerHour(t *testing.T) {
+	tr := ShipTravel{AlignSeconds: 10, WarpSpeedAU: 3}
+	results := []FlipResult{
+		{TotalProfit: 10_000_000, BuyJumps: 2, SellJumps: 5, CargoTrips: 2},
+		{TotalProfit: 10_000_000, BuyJumps: 0, SellJumps: 1, CargoTrips: 1},
+		{TotalProfit: -5},
+	}
+	ApplyFlipISKPerHour(results, tr)
+
+	// 2 + 3×5 jumps and 4 dock stops.
+	want := 17*tr.MinutesPerJump() + 4*tr.DockMinutes()
+	if math.Abs(results[0].ExecutionMinutes-want) > 0.06 {
+		t.Fatalf("minutes = %v, want %.2f", results[0].ExecutionMinutes, want)
+	}
+	if math.Abs(results[0].ProfitPerHour-10_000_000/(results[0].ExecutionMinutes/60)) > 1 {
+		t.Fatalf("profit/hour = %v", results[0].ProfitPerHour)
+	}
+	if results[1].ProfitPerHour <= results[0].ProfitPerHour {
+		t.Fatalf("shorter haul should earn more per hour: %v vs %v", results[1].ProfitPerHour, results[0].ProfitPerHour)
+	}
+	if results[2].ProfitPerHour != 0 {
+		t.Fatalf("losing flip profit/hour = %v, want 0", results[2].ProfitPerHour)
+	}
+}
+
+func TestRouteExecutionProfileFromWarpParams(t *testing.T) {
+	base := RouteExecutionProfileFromParams(RouteParams{RouteShipProfile: "freighter"})
+	if base.MinutesPerJump != 3.6 {
+		t.Fatalf("class minutes = %v, want 3.6", base.MinutesPerJump)
+	}
+	warp := RouteExecutionProfileFromParams(RouteParams{RouteShipProfile: "freighter", RouteAlignSeconds: 20})
+	want := ShipTravelFor("freighter", 20, 0)
+	if math.Abs(warp.MinutesPerJump-want.MinutesPerJump()) > 1e-9 || math.Abs(warp.DockMinutes-want.DockMinutes()) > 1e-9 {
+		t.Fatalf("warp profile = %+v", warp)
+	}
+	explicit := RouteExecutionProfileFromParams(RouteParams{RouteShipProfile: "freighter", RouteAlignSeconds: 20, RouteMinutesPerJump: 5})
+	if explicit.MinutesPerJump != 5 {
+		t.Fatalf("explicit minutes = %v, want 5", explicit.MinutesPerJump)
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	neturl "net/url"
 	"strconv"
+	"time"
 )
 
 // CharacterOrder represents a character's market order.
@@ -167,37 +170,55 @@ func (c *Client) AuthGetJSONContext(ctx context.Context, url, accessToken string
 	if err := c.ensureLightweightHTTP(); err != nil {
 		return err
 	}
-	if err := acquireSemaphore(ctx, c.sem); err != nil {
-		return err
-	}
+	var lastErr error
+	var retryWait time.Duration
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			if retryWait <= 0 {
+				retryWait = retryBackoff(attempt)
+			}
+			if err := sleepWithContext(ctx, retryWait); err != nil {
+				return err
+			}
+		}
+		if err := acquireSemaphore(ctx, c.sem); err != nil {
+			return err
+		}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		<-c.sem
-		return err
-	}
-	req.Header.Set("Authorization", "Bearer "+accessToken)
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "eve-flipper/1.0 (github.com)")
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			<-c.sem
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("User-Agent", "eve-flipper/1.0 (github.com)")
+
+		resp, err := c.do(req)
+		if err != nil {
+			<-c.sem
+			return err
+		}
 
-	resp, err := c.http.Do(req)
-	if err != nil {
-		<-c.sem
-		return err
-	}
+		if resp.StatusCode == 200 {
+			c.noteRouteWarning(url, resp.Header)
+			decErr := json.NewDecoder(resp.Body).Decode(dst)
+			resp.Body.Close()
+			<-c.sem
+			return decErr
+		}
 
-	statusCode := resp.StatusCode
-	if statusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
+		retryWait = esiRetryDelay(resp, retryBackoff(attempt+1))
 		resp.Body.Close()
 		<-c.sem
-		return fmt.Errorf("ESI %d: %s", statusCode, string(body))
+		lastErr = fmt.Errorf("ESI %d: %s", resp.StatusCode, string(body))
+		if !isRetryable(resp.StatusCode) {
+			return lastErr
+		}
+		log.Printf("[ESI] Auth retryable error %d (attempt %d/%d)", resp.StatusCode, attempt+1, maxRetries+1)
 	}
-
-	decErr := json.NewDecoder(resp.Body).Decode(dst)
-	resp.Body.Close()
-	<-c.sem
-	return decErr
+	return lastErr
 }
 
 // GetCharacterOrders fetches a character's active market orders.
@@ -230,6 +251,38 @@ func (c *Client) GetSkills(characterID int64, accessToken string) (*SkillSheet,
 	return &sheet, nil
 }
 
+// Standing is a character's standing toward an NPC agent, corporation or faction.
+type Standing struct {
+	FromID   int32   `json:"from_id"`
+	FromType string  `json:"from_type"` // agent, npc_corp, faction
+	Standing float64 `json:"standing"`
+}
+
+// GetStandings fetches a character's NPC standings.
+func (c *Client) GetStandings(characterID int64, accessToken string) ([]Standing, error) {
+	url := fmt.Sprintf("%s/characters/%d/standings/?datasource=tranquility", baseURL, characterID)
+	var standings []Standing
+	if err := c.AuthGetJSON(url, accessToken, &standings); err != nil {
+		return nil, fmt.Errorf("standings: %w", err)
+	}
+	return standings, nil
+}
+
+// SearchStructures returns the IDs of structures whose name contains search
+// and that the character can see (docking access or ACL). Requires the
+// esi-search.search_structures.v1 scope.
+func (c *Client) SearchStructures(characterID int64, search string, accessToken string) ([]int64, error) {
+	url := fmt.Sprintf("%s/characters/%d/search/?datasource=tranquility&categories=structure&strict=false&search=%s",
+		baseURL, characterID, neturl.QueryEscape(search))
+	var result struct {
+		Structure []int64 `json:"structure"`
+	}
+	if err := c.AuthGetJSON(url, accessToken, &result); err != nil {
+		return nil, fmt.Errorf("structure search: %w", err)
+	}
+	return result.Structure, nil
+}
+
 // GetOrderHistory fetches all pages of a character's completed/cancelled/expired orders.
 // ESI may return multiple pages via X-Pages header; this fetches them all concurrently.
 func (c *Client) GetOrderHistory(characterID int64, accessToken string) ([]HistoricalOrder, error) {
@@ -247,7 +300,7 @@ func (c *Client) GetOrderHistory(characterID int64, accessToken string) ([]Histo
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "eve-flipper/1.0 (github.com)")
 
-	resp, err := c.http.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		<-c.sem
 		return nil, fmt.Errorf("order history page 1: %w", err)
@@ -336,7 +389,7 @@ func (c *Client) GetWalletJournal(characterID int64, accessToken string) ([]Wall
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "eve-flipper/1.0 (github.com)")
 
-	resp, err := c.http.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		<-c.sem
 		return nil, fmt.Errorf("wallet journal page 1: %w", err)
@@ -414,7 +467,7 @@ func (c *Client) GetCharacterAssets(characterID int64, accessToken string) ([]Ch
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "eve-flipper/1.0 (github.com)")
 
-	resp, err := c.http.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		<-c.sem
 		return nil, fmt.Errorf("assets page 1: %w", err)
@@ -492,7 +545,7 @@ func (c *Client) GetCharacterBlueprints(characterID int64, accessToken string) (
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "eve-flipper/1.0 (github.com)")
 
-	resp, err := c.http.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		<-c.sem
 		return nil, fmt.Errorf("blueprints page 1: %w", err)
@@ -52,6 +52,21 @@ type Client struct {
 	structureSystems sync.Map // int64 -> int32
 	// Negative cache for inaccessible/throttled structure name lookups.
 	structureNameFailures sync.Map // int64 -> structureNameFailure
+	// Resolved character/corp/alliance names: L1 in-memory, L2 persistent.
+	nameCache sync.Map // int64 -> cachedName
+	nameStore NameStore
+
+	// ESI error-limit tracker applied by the HTTP transport.
+	limiter *errorLimiter
+	// Warning headers (deprecations) seen per normalized route.
+	routeWarnings sync.Map // string -> RouteWarning
+
+	// In-flight paginated order fetches, for page progress reporting.
+	pageFetches sync.Map // *pageFetchTracker -> struct{}
+
+	// Persistent per-page ETag cache for paginated market orders.
+	pageStore          PageCacheStore
+	pageCacheNotBefore time.Time // pages fetched earlier are revalidated, never served fresh
 
 	// Health check cache
 	healthMu      sync.RWMutex
@@ -91,8 +106,13 @@ func NewClient(store StationStore) *Client {
 		MaxConnsPerHost:     0,   // unlimited
 		IdleConnTimeout:     120 * time.Second,
 	}
+	limiter := newErrorLimiter()
 	c := &Client{
-		http:         &http.Client{Timeout: 30 * time.Second, Transport: transport},
+		http: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &limitedTransport{base: transport, limiter: limiter, match: isESIHost},
+		},
+		limiter:      limiter,
 		sem:          make(chan struct{}, 50), // for GetJSON (history, stations, auth)
 		scanSem:      make(chan struct{}, 50), // for GetPaginatedDirect (market order pages)
 		stationStore: store,
@@ -101,6 +121,12 @@ func NewClient(store StationStore) *Client {
 	if recorder, ok := store.(MarketOrderRecorder); ok {
 		c.orderRecorder = recorder
 	}
+	if names, ok := store.(NameStore); ok {
+		c.nameStore = names
+	}
+	if pages, ok := store.(PageCacheStore); ok {
+		c.pageStore = pages
+	}
 	return c
 }
 
@@ -188,7 +214,7 @@ func (c *Client) LoadEVERefStructures() {
 		req.Header.Set("User-Agent", "eve-flipper/1.0 (github.com)")
 		req.Header.Set("Accept", "application/json")
 
-		resp, err := c.http.Do(req)
+		resp, err := c.do(req)
 		if err != nil {
 			log.Printf("[ESI] EVERef structures: fetch error: %v", err)
 			return
@@ -302,7 +328,7 @@ func (c *Client) HealthCheck() bool {
 		return false
 	}
 	req.Header.Set("User-Agent", "eve-flipper/1.0 (github.com)")
-	resp, err := c.http.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		c.healthOK = false
 		c.healthChecked = time.Now()
@@ -647,14 +673,14 @@ func (c *Client) PrefetchStructureNames(locationIDs map[int64]bool, accessToken
 
 // isRetryable returns true if the HTTP status code indicates a transient error worth retrying.
 func isRetryable(statusCode int) bool {
-	return statusCode == 420 || statusCode == 429 || statusCode == 502 || statusCode == 503 || statusCode == 504 || statusCode == 520
+	return statusCode == 420 || statusCode == 429 || statusCode == 500 || statusCode == 502 || statusCode == 503 || statusCode == 504 || statusCode == 520
 }
 
 func retryBackoff(attempt int) time.Duration {
 	if attempt <= 0 {
 		return 0
 	}
-	return retryBaseWait * time.Duration(1<<(attempt-1))
+	return withJitter(retryBaseWait * time.Duration(1<<(attempt-1)))
 }
 
 func esiRetryDelay(resp *http.Response, fallback time.Duration) time.Duration {
@@ -723,8 +749,7 @@ func (c *Client) PostJSON(url string, body interface{}, dst interface{}) error {
 	var lastErr error
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
-			wait := retryBaseWait * time.Duration(1<<(attempt-1))
-			time.Sleep(wait)
+			time.Sleep(retryBackoff(attempt))
 		}
 
 		c.sem <- struct{}{}
@@ -740,7 +765,7 @@ func (c *Client) PostJSON(url string, body interface{}, dst interface{}) error {
 		req.Header.Set("Accept", "application/json")
 		req.Header.Set("Content-Type", "application/json")
 
-		resp, err := c.http.Do(req)
+		resp, err := c.do(req)
 		if err != nil {
 			<-c.sem
 			lastErr = err
@@ -749,6 +774,7 @@ func (c *Client) PostJSON(url string, body interface{}, dst interface{}) error {
 		}
 
 		if resp.StatusCode == 200 {
+			c.noteRouteWarning(url, resp.Header)
 			decErr := json.NewDecoder(resp.Body).Decode(dst)
 			resp.Body.Close()
 			<-c.sem
@@ -822,7 +848,7 @@ func (c *Client) GetJSONContext(ctx context.Context, url string, dst interface{}
 		req.Header.Set("User-Agent", "eve-flipper/1.0 (github.com)")
 		req.Header.Set("Accept", "application/json")
 
-		resp, err := c.http.Do(req)
+		resp, err := c.do(req)
 		if err != nil {
 			<-c.sem
 			lastErr = err
@@ -903,7 +929,7 @@ func (c *Client) getPaginatedInternalContext(ctx context.Context, url, accessTok
 		req.Header.Set("Authorization", "Bearer "+accessToken)
 	}
 
-	resp, err := c.http.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		<-c.sem
 		return nil, err
@@ -916,6 +942,7 @@ func (c *Client) getPaginatedInternalContext(ctx context.Context, url, accessTok
 		return nil, fmt.Errorf("ESI paginated %d: %s", resp.StatusCode, string(body))
 	}
 
+	c.noteRouteWarning(url, resp.Header)
 	totalPages := 1
 	if p := resp.Header.Get("X-Pages"); p != "" {
 		totalPages, _ = strconv.Atoi(p)
@@ -997,7 +1024,20 @@ func (c *Client) getPaginatedDirectWithHeadersContext(ctx context.Context, url s
 	var lastErr error
 	var retryWait time.Duration
 
-	for attempt := 0; attempt <= maxRetries; attempt++ {
+	// Pages persisted by the page store are served while fresh and
+	// revalidated with If-None-Match once expired.
+	page1URL := url + "&page=1"
+	cached1, fresh1, haveCached1 := c.cachedOrderPage(page1URL)
+	if fresh1 {
+		if orders, ok := freshOrderPage(cached1); ok {
+			page1, totalPages = orders, cached1.Pages
+			respEtag, respExpires = cached1.ETag, cached1.Expires
+		} else {
+			fresh1 = false
+		}
+	}
+
+	for attempt := 0; attempt <= maxRetries && !fresh1; attempt++ {
 		if attempt > 0 {
 			if retryWait <= 0 {
 				retryWait = retryBackoff(attempt)
@@ -1011,13 +1051,16 @@ func (c *Client) getPaginatedDirectWithHeadersContext(ctx context.Context, url s
 			return nil, "", time.Time{}, err
 		}
 
-		req, err := newESIRequestContext(ctx, url+"&page=1")
+		req, err := newESIRequestContext(ctx, page1URL)
 		if err != nil {
 			<-c.scanSem
 			return nil, "", time.Time{}, err
 		}
+		if haveCached1 {
+			req.Header.Set("If-None-Match", cached1.ETag)
+		}
 
-		resp, err := c.http.Do(req)
+		resp, err := c.do(req)
 		if err != nil {
 			<-c.scanSem
 			lastErr = err
@@ -1025,7 +1068,8 @@ func (c *Client) getPaginatedDirectWithHeadersContext(ctx context.Context, url s
 			continue
 		}
 
-		if resp.StatusCode != 200 {
+		notModified := resp.StatusCode == http.StatusNotModified && haveCached1
+		if resp.StatusCode != 200 && !notModified {
 			retryWait = esiRetryDelay(resp, retryBackoff(attempt+1))
 			resp.Body.Close()
 			<-c.scanSem
@@ -1037,20 +1081,21 @@ func (c *Client) getPaginatedDirectWithHeadersContext(ctx context.Context, url s
 			continue
 		}
 
-		totalPages = 1
-		if p := resp.Header.Get("X-Pages"); p != "" {
-			totalPages, _ = strconv.Atoi(p)
-		}
 		respEtag = resp.Header.Get("Etag")
+		if respEtag == "" && notModified {
+			respEtag = cached1.ETag
+		}
 		respExpires = parseExpires(resp)
 
-		if err := json.NewDecoder(resp.Body).Decode(&page1); err != nil {
+		orders, pages, err := c.decodeOrderPage(resp, page1URL, cached1)
+		if err != nil {
 			resp.Body.Close()
 			<-c.scanSem
 			lastErr = fmt.Errorf("decode page 1: %w", err)
 			log.Printf("[ESI] Page 1 decode failed (attempt %d/%d): %v", attempt+1, maxRetries+1, err)
 			continue
 		}
+		page1, totalPages = orders, max(pages, 1)
 		resp.Body.Close()
 		<-c.scanSem
 		lastErr = nil
@@ -1069,88 +1114,31 @@ func (c *Client) getPaginatedDirectWithHeadersContext(ctx context.Context, url s
 		return page1, respEtag, respExpires, nil
 	}
 
-	type pageResult struct {
-		data []MarketOrder
-		err  error
-	}
-
-	results := make(chan pageResult, totalPages-1)
-	for p := 2; p <= totalPages; p++ {
-		go func(pageNum int) {
-			var data []MarketOrder
-			pageURL := fmt.Sprintf("%s&page=%d", url, pageNum)
-			var retryWait time.Duration
-
-			for attempt := 0; attempt <= maxRetries; attempt++ {
-				if attempt > 0 {
-					if retryWait <= 0 {
-						retryWait = retryBackoff(attempt)
-					}
-					if err := sleepWithContext(ctx, retryWait); err != nil {
-						results <- pageResult{err: err}
-						return
-					}
+	tracker := c.trackPageFetch(regionID, totalPages)
+	defer c.untrackPageFetch(tracker)
+
+	// A bounded pool of workers pulls page numbers; scanSem still caps
+	// requests in flight across all regions being fetched concurrently.
+	pageNums := make(chan int)
+	results := make(chan orderPageResult, totalPages-1)
+	workers := min(totalPages-1, cap(c.scanSem))
+	for w := 0; w < workers; w++ {
+		go func() {
+			for pageNum := range pageNums {
+				r := c.fetchOrderPage(ctx, url, pageNum, regionID)
+				if r.err == nil {
+					tracker.fetched.Add(1)
 				}
-
-				if err := acquireSemaphore(ctx, c.scanSem); err != nil {
-					results <- pageResult{err: err}
-					return
-				}
-
-				pageReq, err := newESIRequestContext(ctx, pageURL)
-				if err != nil {
-					<-c.scanSem
-					results <- pageResult{err: err}
-					return
-				}
-
-				pageResp, err := c.http.Do(pageReq)
-				if err != nil {
-					<-c.scanSem
-					if attempt == maxRetries {
-						log.Printf("[ESI] Page %d failed after %d attempts: %v", pageNum, maxRetries+1, err)
-						results <- pageResult{err: err}
-						return
-					}
-					continue
-				}
-
-				if pageResp.StatusCode != 200 {
-					wait := esiRetryDelay(pageResp, retryBackoff(attempt+1))
-					pageResp.Body.Close()
-					<-c.scanSem
-					if !isRetryable(pageResp.StatusCode) || attempt == maxRetries {
-						log.Printf("[ESI] Page %d error %d after %d attempts", pageNum, pageResp.StatusCode, attempt+1)
-						results <- pageResult{err: fmt.Errorf("ESI %d", pageResp.StatusCode)}
-						return
-					}
-					retryWait = wait
-					continue
-				}
-
-				if err := json.NewDecoder(pageResp.Body).Decode(&data); err != nil {
-					pageResp.Body.Close()
-					<-c.scanSem
-					if attempt == maxRetries {
-						log.Printf("[ESI] Page %d decode failed after %d attempts: %v", pageNum, maxRetries+1, err)
-						results <- pageResult{err: fmt.Errorf("decode page %d: %w", pageNum, err)}
-						return
-					}
-					log.Printf("[ESI] Page %d decode retry (attempt %d/%d): %v", pageNum, attempt+1, maxRetries+1, err)
-					continue
-				}
-				pageResp.Body.Close()
-				<-c.scanSem
-				for i := range data {
-					data[i].RegionID = regionID
-				}
-				results <- pageResult{data: data}
-				return
+				results <- r
 			}
-
-			results <- pageResult{err: fmt.Errorf("ESI page %d: exhausted retries", pageNum)}
-		}(p)
+		}()
 	}
+	go func() {
+		defer close(pageNums)
+		for p := 2; p <= totalPages; p++ {
+			pageNums <- p
+		}
+	}()
 
 	all := make([]MarketOrder, 0, len(page1)*totalPages)
 	all = append(all, page1...)
@@ -1172,6 +1160,98 @@ func (c *Client) getPaginatedDirectWithHeadersContext(ctx context.Context, url s
 	return all, respEtag, respExpires, nil
 }
 
+// orderPageResult is one decoded market order page from fetchOrderPage.
+type orderPageResult struct {
+	data []MarketOrder
+	err  error
+}
+
+// fetchOrderPage fetches page pageNum of a paginated market order URL,
+// retrying transient errors and revalidating against the page store.
+func (c *Client) fetchOrderPage(ctx context.Context, url string, pageNum int, regionID int32) orderPageResult {
+	var data []MarketOrder
+	pageURL := fmt.Sprintf("%s&page=%d", url, pageNum)
+	var retryWait time.Duration
+
+	cached, fresh, haveCached := c.cachedOrderPage(pageURL)
+	if fresh {
+		if orders, ok := freshOrderPage(cached); ok {
+			for i := range orders {
+				orders[i].RegionID = regionID
+			}
+			return orderPageResult{data: orders}
+		}
+	}
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			if retryWait <= 0 {
+				retryWait = retryBackoff(attempt)
+			}
+			if err := sleepWithContext(ctx, retryWait); err != nil {
+				return orderPageResult{err: err}
+			}
+		}
+
+		if err := acquireSemaphore(ctx, c.scanSem); err != nil {
+			return orderPageResult{err: err}
+		}
+
+		pageReq, err := newESIRequestContext(ctx, pageURL)
+		if err != nil {
+			<-c.scanSem
+			return orderPageResult{err: err}
+		}
+
+		if haveCached {
+			pageReq.Header.Set("If-None-Match", cached.ETag)
+		}
+
+		pageResp, err := c.do(pageReq)
+		if err != nil {
+			<-c.scanSem
+			if attempt == maxRetries {
+				log.Printf("[ESI] Page %d failed after %d attempts: %v", pageNum, maxRetries+1, err)
+				return orderPageResult{err: err}
+			}
+			continue
+		}
+
+		notModified := pageResp.StatusCode == http.StatusNotModified && haveCached
+		if pageResp.StatusCode != 200 && !notModified {
+			wait := esiRetryDelay(pageResp, retryBackoff(attempt+1))
+			pageResp.Body.Close()
+			<-c.scanSem
+			if !isRetryable(pageResp.StatusCode) || attempt == maxRetries {
+				log.Printf("[ESI] Page %d error %d after %d attempts", pageNum, pageResp.StatusCode, attempt+1)
+				return orderPageResult{err: fmt.Errorf("ESI %d", pageResp.StatusCode)}
+			}
+			retryWait = wait
+			continue
+		}
+
+		data, _, err = c.decodeOrderPage(pageResp, pageURL, cached)
+		if err != nil {
+			pageResp.Body.Close()
+			<-c.scanSem
+			if attempt == maxRetries {
+				log.Printf("[ESI] Page %d decode failed after %d attempts: %v", pageNum, maxRetries+1, err)
+				return orderPageResult{err: fmt.Errorf("decode page %d: %w", pageNum, err)}
+			}
+			log.Printf("[ESI] Page %d decode retry (attempt %d/%d): %v", pageNum, attempt+1, maxRetries+1, err)
+			continue
+		}
+		pageResp.Body.Close()
+		<-c.scanSem
+		for i := range data {
+			data[i].RegionID = regionID
+		}
+		return orderPageResult{data: data}
+	}
+
+	return orderPageResult{err: fmt.Errorf("ESI page %d: exhausted retries", pageNum)}
+}
+
 // PrefetchStationNames fetches station names concurrently for a set of location IDs.
 func (c *Client) PrefetchStationNames(locationIDs map[int64]bool) {
 	var toFetch []int64
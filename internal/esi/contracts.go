@@ -75,7 +75,7 @@ func (c *Client) FetchRegionContracts(regionID int32) ([]PublicContract, error)
 	req.Header.Set("User-Agent", "eve-flipper/1.0 (github.com)")
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := c.http.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		<-c.sem
 		return nil, err
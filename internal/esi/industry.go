@@ -117,8 +117,38 @@ func (c *Client) GetSystemCostIndex(cache *IndustryCache, systemID int32) (*Syst
 		return nil, err
 	}
 
-	// Update cache
-	cache.costIndices = make(map[int32]*SystemCostIndices)
+	cache.costIndices = costIndicesBySystem(systems)
+	cache.costIndicesTime = time.Now()
+
+	if idx, ok := cache.costIndices[systemID]; ok {
+		return idx, nil
+	}
+	// System not found in industry data, return zeros
+	return &SystemCostIndices{}, nil
+}
+
+// GetAllSystemCostIndices returns the cost indices of every system with
+// industry activity, from the same hourly cache as GetSystemCostIndex.
+func (c *Client) GetAllSystemCostIndices(cache *IndustryCache) (map[int32]SystemCostIndices, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if time.Since(cache.costIndicesTime) >= time.Hour || len(cache.costIndices) == 0 {
+		systems, err := c.FetchIndustrySystems()
+		if err != nil {
+			return nil, err
+		}
+		cache.costIndices = costIndicesBySystem(systems)
+		cache.costIndicesTime = time.Now()
+	}
+	result := make(map[int32]SystemCostIndices, len(cache.costIndices))
+	for id, idx := range cache.costIndices {
+		result[id] = *idx
+	}
+	return result, nil
+}
+
+func costIndicesBySystem(systems []IndustryCostIndex) map[int32]*SystemCostIndices {
+	out := make(map[int32]*SystemCostIndices, len(systems))
 	for _, sys := range systems {
 		idx := &SystemCostIndices{}
 		for _, ci := range sys.CostIndices {
@@ -137,15 +167,9 @@ func (c *Client) GetSystemCostIndex(cache *IndustryCache, systemID int32) (*Syst
 				idx.TEResearch = ci.CostIndex
 			}
 		}
-		cache.costIndices[sys.SolarSystemID] = idx
-	}
-	cache.costIndicesTime = time.Now()
-
-	if idx, ok := cache.costIndices[systemID]; ok {
-		return idx, nil
+		out[sys.SolarSystemID] = idx
 	}
-	// System not found in industry data, return zeros
-	return &SystemCostIndices{}, nil
+	return out
 }
 
 // GetAdjustedPrice returns the adjusted price for a type, fetching if needed.
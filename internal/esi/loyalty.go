@@ -0,0 +1,30 @@
+package esi
+
+import "fmt"
+
+// LoyaltyOffer is one LP store offer of an NPC corporation.
+type LoyaltyOffer struct {
+	OfferID       int32              `json:"offer_id"`
+	TypeID        int32              `json:"type_id"`
+	Quantity      int32              `json:"quantity"`
+	LPCost        int64              `json:"lp_cost"`
+	ISKCost       float64            `json:"isk_cost"`
+	AKCost        int64              `json:"ak_cost,omitempty"`
+	RequiredItems []LoyaltyOfferItem `json:"required_items"`
+}
+
+// LoyaltyOfferItem is an item an LP offer consumes in addition to LP and ISK.
+type LoyaltyOfferItem struct {
+	TypeID   int32 `json:"type_id"`
+	Quantity int32 `json:"quantity"`
+}
+
+// FetchLoyaltyOffers fetches the LP store offers of an NPC corporation.
+func (c *Client) FetchLoyaltyOffers(corporationID int32) ([]LoyaltyOffer, error) {
+	url := fmt.Sprintf("%s/loyalty/stores/%d/offers/?datasource=tranquility", baseURL, corporationID)
+	var offers []LoyaltyOffer
+	if err := c.GetJSON(url, &offers); err != nil {
+		return nil, fmt.Errorf("loyalty offers %d: %w", corporationID, err)
+	}
+	return offers, nil
+}
@@ -47,6 +47,11 @@ func (c *Client) FetchRegionOrders(regionID int32, orderType string) ([]MarketOr
 	return c.FetchRegionOrdersCached(regionID, orderType)
 }
 
+// FetchRegionOrdersContext is FetchRegionOrders with cancellation.
+func (c *Client) FetchRegionOrdersContext(ctx context.Context, regionID int32, orderType string) ([]MarketOrder, error) {
+	return c.FetchRegionOrdersCachedContext(ctx, regionID, orderType)
+}
+
 // FetchRegionOrdersByType fetches all market orders for a specific type in a region.
 func (c *Client) FetchRegionOrdersByType(regionID int32, typeID int32) ([]MarketOrder, error) {
 	return c.FetchRegionOrdersByTypeContext(context.Background(), regionID, typeID)
@@ -69,6 +74,10 @@ func (c *Client) FetchRegionOrdersByTypeContext(ctx context.Context, regionID in
 	cacheKey := orderCacheKey{RegionID: regionID, OrderType: "all", Scope: "region_type", TypeID: typeID}
 	sfKey := fmt.Sprintf("region_type:%d:%d", regionID, typeID)
 	result, err, _ := cache.Do(sfKey, func() (interface{}, error) {
+		if orders, ok := cache.TypeOrders(regionID, typeID); ok {
+			log.Printf("[ESI] OrderCache HIT region=%d type_id=%d from region book (%d orders)", regionID, typeID, len(orders))
+			return orders, nil
+		}
 		orders, etag, hit := cache.GetScoped(cacheKey)
 		if hit {
 			log.Printf("[ESI] OrderCache HIT region=%d type_id=%d (%d orders)", regionID, typeID, len(orders))
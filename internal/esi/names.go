@@ -0,0 +1,100 @@
+package esi
+
+import (
+	"time"
+)
+
+// NameCacheTTL is how long a resolved id→name stays fresh. Character and
+// corporation names change rarely (name changes, corp renames), so a week
+// keeps dashboards from re-resolving the same members on every build.
+const NameCacheTTL = 7 * 24 * time.Hour
+
+// namesBatchSize is the ESI POST /universe/names/ limit per call.
+const namesBatchSize = 1000
+
+// ResolvedName is an id→name mapping from /universe/names/.
+type ResolvedName struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"` // character, corporation, alliance, ...
+}
+
+// NameStore is a persistent L2 cache for resolved names. NewClient uses
+// its store when it implements NameStore.
+type NameStore interface {
+	// GetNames returns cached names for ids updated within maxAge.
+	GetNames(ids []int64, maxAge time.Duration) map[int64]ResolvedName
+	SetNames(names []ResolvedName)
+}
+
+type cachedName struct {
+	name    ResolvedName
+	fetched time.Time
+}
+
+// ResolveNames maps character/corporation/alliance IDs to names, using the
+// in-memory cache, then the persistent store, then ESI for the rest. IDs ESI
+// cannot resolve are absent from the result.
+func (c *Client) ResolveNames(ids []int64) map[int64]ResolvedName {
+	out := make(map[int64]ResolvedName)
+	if c == nil || len(ids) == 0 {
+		return out
+	}
+	now := time.Now()
+
+	seen := make(map[int64]bool, len(ids))
+	var missing []int64
+	for _, id := range ids {
+		if id <= 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if v, ok := c.nameCache.Load(id); ok {
+			if cn := v.(cachedName); now.Sub(cn.fetched) < NameCacheTTL {
+				out[id] = cn.name
+				continue
+			}
+		}
+		missing = append(missing, id)
+	}
+
+	c.mu.Lock()
+	store := c.nameStore
+	c.mu.Unlock()
+	if store != nil && len(missing) > 0 {
+		stored := store.GetNames(missing, NameCacheTTL)
+		rest := missing[:0]
+		for _, id := range missing {
+			if n, ok := stored[id]; ok {
+				out[id] = n
+				c.nameCache.Store(id, cachedName{name: n, fetched: now})
+				continue
+			}
+			rest = append(rest, id)
+		}
+		missing = rest
+	}
+
+	var fetched []ResolvedName
+	for start := 0; start < len(missing); start += namesBatchSize {
+		batch := missing[start:min(start+namesBatchSize, len(missing))]
+		// The endpoint takes int32 IDs (safe for character/corp/alliance IDs).
+		body := make([]int32, len(batch))
+		for i, id := range batch {
+			body[i] = int32(id)
+		}
+		var results []ResolvedName
+		if err := c.PostJSON(baseURL+"/universe/names/?datasource=tranquility", body, &results); err != nil {
+			continue
+		}
+		for _, r := range results {
+			out[r.ID] = r
+			c.nameCache.Store(r.ID, cachedName{name: r, fetched: now})
+		}
+		fetched = append(fetched, results...)
+	}
+	if store != nil && len(fetched) > 0 {
+		store.SetNames(fetched)
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package esi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type memNameStore struct {
+	names map[int64]ResolvedName
+	sets  int
+}
+
+func (m *memNameStore) GetNames(ids []int64, _ time.Duration) map[int64]ResolvedName {
+	out := map[int64]ResolvedName{}
+	for _, id := range ids {
+		if n, ok := m.names[id]; ok {
+			out[id] = n
+		}
+	}
+	return out
+}
+
+func (m *memNameStore) SetNames(names []ResolvedName) {
+	m.sets++
+	for _, n := range names {
+		m.names[n.ID] = n
+	}
+}
+
+// rewriteTransport sends every request to the test server.
+type rewriteTransport struct{ target string }
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "http"
+	req.URL.Host = strings.TrimPrefix(rt.target, "http://")
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestResolveNames_UsesCachesBeforeESI(t *testing.T) {
+	var posts int32
+	var lastBody []int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&posts, 1)
+		_ = json.NewDecoder(r.Body).Decode(&lastBody)
+		var out []ResolvedName
+		for _, id := range lastBody {
+			out = append(out, ResolvedName{ID: id, Name: "Fetched", Category: "character"})
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	store := &memNameStore{names: map[int64]ResolvedName{2: {ID: 2, Name: "Stored", Category: "corporation"}}}
+	c := NewClient(nil)
+	c.http = &http.Client{Transport: rewriteTransport{target: srv.URL}}
+	c.nameStore = store
+
+	got := c.ResolveNames([]int64{1, 2, 1, 0})
+	if got[1].Name != "Fetched" || got[2].Name != "Stored" || len(got) != 2 {
+		t.Fatalf("ResolveNames = %+v", got)
+	}
+	if posts != 1 || len(lastBody) != 1 || lastBody[0] != 1 {
+		t.Errorf("ESI posts=%d body=%v, want one call for id 1 only", posts, lastBody)
+	}
+	if store.sets != 1 || store.names[1].Name != "Fetched" {
+		t.Errorf("fetched names not persisted: %+v", store.names)
+	}
+
+	// Second call is served from memory.
+	if got := c.ResolveNames([]int64{1, 2}); len(got) != 2 || posts != 1 {
+		t.Errorf("second call hit ESI: posts=%d got=%+v", posts, got)
+	}
+}
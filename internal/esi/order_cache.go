@@ -2,13 +2,18 @@ package esi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"eve-flipper/internal/metrics"
+
 	"golang.org/x/sync/singleflight"
 )
 
@@ -28,6 +33,22 @@ type orderCacheEntry struct {
 	etag    string    // ETag from ESI response (page 1)
 	expires time.Time // parsed Expires header
 	updated time.Time // when entry was last refreshed (MISS or 304)
+
+	lastUsed atomic.Int64 // unix nanos of the last scan request; drives background refresh
+
+	typeOnce sync.Once
+	byType   map[int32][]MarketOrder // orders grouped by type, built on first use
+}
+
+// typeIndex returns the entry's orders grouped by type ID.
+func (e *orderCacheEntry) typeIndex() map[int32][]MarketOrder {
+	e.typeOnce.Do(func() {
+		e.byType = make(map[int32][]MarketOrder)
+		for _, o := range e.orders {
+			e.byType[o.TypeID] = append(e.byType[o.TypeID], o)
+		}
+	})
+	return e.byType
 }
 
 // OrderCache is a thread-safe in-memory cache for region market orders.
@@ -47,11 +68,20 @@ type OrderCacheWindow struct {
 	NextExpiryAt    time.Time
 	MinTTLSeconds   int64
 	MaxTTLSeconds   int64
+	OldestRefreshAt time.Time
 	Regions         int
 	Entries         int
 	Stale           bool
 }
 
+// Background refresh policy: region books a scan requested within
+// orderCacheRefreshIdle are re-fetched once they expire, so the next scan
+// from the UI finds them warm.
+const (
+	orderCacheRefreshIdle     = 15 * time.Minute
+	OrderCacheRefreshInterval = 30 * time.Second
+)
+
 // NewOrderCache creates an empty order cache.
 func NewOrderCache() *OrderCache {
 	return &OrderCache{
@@ -153,12 +183,120 @@ func (oc *OrderCache) PutScoped(key orderCacheKey, orders []MarketOrder, etag st
 		}
 	}
 
-	oc.entries[key] = &orderCacheEntry{
+	entry := &orderCacheEntry{
 		orders:  orders,
 		etag:    etag,
 		expires: expires,
 		updated: time.Now().UTC(),
 	}
+	// A background refresh must not count as use, or books would be kept
+	// warm forever.
+	lastUsed := entry.updated.UnixNano()
+	if prev, ok := oc.entries[key]; ok {
+		lastUsed = prev.lastUsed.Load()
+	}
+	entry.lastUsed.Store(lastUsed)
+	oc.entries[key] = entry
+}
+
+// markUsed records that a scan requested key.
+func (oc *OrderCache) markUsed(key orderCacheKey) {
+	oc.mu.RLock()
+	defer oc.mu.RUnlock()
+	if e, ok := oc.entries[key]; ok {
+		e.lastUsed.Store(time.Now().UnixNano())
+	}
+}
+
+// TypeOrders returns the orders for one type from fresh region-wide books:
+// the "all" book, or the "sell" and "buy" books together. ok is false when
+// neither is cached and unexpired.
+func (oc *OrderCache) TypeOrders(regionID int32, typeID int32) ([]MarketOrder, bool) {
+	oc.mu.RLock()
+	defer oc.mu.RUnlock()
+
+	now := time.Now()
+	fresh := func(orderType string) (*orderCacheEntry, bool) {
+		e, ok := oc.entries[orderCacheKey{RegionID: regionID, OrderType: orderType}]
+		return e, ok && now.Before(e.expires)
+	}
+	if e, ok := fresh("all"); ok {
+		return append([]MarketOrder(nil), e.typeIndex()[typeID]...), true
+	}
+	sell, okSell := fresh("sell")
+	buy, okBuy := fresh("buy")
+	if !okSell || !okBuy {
+		return nil, false
+	}
+	out := append([]MarketOrder(nil), sell.typeIndex()[typeID]...)
+	return append(out, buy.typeIndex()[typeID]...), true
+}
+
+// OrderCount is the number of buy and sell orders of one type.
+type OrderCount struct {
+	Buy  int `json:"buy"`
+	Sell int `json:"sell"`
+}
+
+// RegionOrderCounts counts the orders per type in the region-wide books
+// cached for regionID, expired or not: the "all" book, or the "sell" and
+// "buy" books. updated is the refresh time of the oldest book used; ok is
+// false when no region-wide book is cached.
+func (oc *OrderCache) RegionOrderCounts(regionID int32) (counts map[int32]OrderCount, updated time.Time, ok bool) {
+	oc.mu.RLock()
+	defer oc.mu.RUnlock()
+
+	books := []*orderCacheEntry{oc.entries[orderCacheKey{RegionID: regionID, OrderType: "all"}]}
+	if books[0] == nil {
+		books = []*orderCacheEntry{
+			oc.entries[orderCacheKey{RegionID: regionID, OrderType: "sell"}],
+			oc.entries[orderCacheKey{RegionID: regionID, OrderType: "buy"}],
+		}
+	}
+	counts = make(map[int32]OrderCount)
+	for _, e := range books {
+		if e == nil {
+			continue
+		}
+		if !ok || e.updated.Before(updated) {
+			updated = e.updated
+		}
+		ok = true
+		for typeID, orders := range e.typeIndex() {
+			c := counts[typeID]
+			for _, o := range orders {
+				if o.IsBuyOrder {
+					c.Buy++
+				} else {
+					c.Sell++
+				}
+			}
+			counts[typeID] = c
+		}
+	}
+	return counts, updated, ok
+}
+
+// refreshCandidates returns region-wide books used within idle that have expired.
+func (oc *OrderCache) refreshCandidates(now time.Time, idle time.Duration) []orderCacheKey {
+	oc.mu.RLock()
+	defer oc.mu.RUnlock()
+	var keys []orderCacheKey
+	for key, e := range oc.entries {
+		if key.Scope != "" || !now.After(e.expires) {
+			continue
+		}
+		if now.Sub(time.Unix(0, e.lastUsed.Load())) <= idle {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].RegionID != keys[j].RegionID {
+			return keys[i].RegionID < keys[j].RegionID
+		}
+		return keys[i].OrderType < keys[j].OrderType
+	})
+	return keys
 }
 
 // Touch updates the expiry of an existing cache entry (used on 304 Not Modified).
@@ -212,9 +350,13 @@ func (oc *OrderCache) WindowForRegions(regionIDs []int32, orderType string) Orde
 				found = true
 				window.NextExpiryAt = entry.expires
 				window.LastRefreshAt = entry.updated
+				window.OldestRefreshAt = entry.updated
 				maxExpiry = entry.expires
 				continue
 			}
+			if entry.updated.Before(window.OldestRefreshAt) {
+				window.OldestRefreshAt = entry.updated
+			}
 			if entry.expires.Before(window.NextExpiryAt) {
 				window.NextExpiryAt = entry.expires
 			}
@@ -258,15 +400,54 @@ func (c *Client) OrderCacheWindow(regionIDs []int32, orderType string) OrderCach
 	return c.orderCache.WindowForRegions(regionIDs, orderType)
 }
 
-// ClearOrderCache clears all region order cache entries.
+// RegionOrderCounts returns per-type order counts from the cached books of
+// regionID (see OrderCache.RegionOrderCounts).
+func (c *Client) RegionOrderCounts(regionID int32) (map[int32]OrderCount, time.Time, bool) {
+	if c == nil || c.orderCache == nil {
+		return nil, time.Time{}, false
+	}
+	return c.orderCache.RegionOrderCounts(regionID)
+}
+
+// ClearOrderCache clears all region order cache entries and forces persisted
+// order pages to be revalidated with ESI before reuse.
 // Returns number of entries removed.
 func (c *Client) ClearOrderCache() int {
-	if c == nil || c.orderCache == nil {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	c.pageCacheNotBefore = time.Now()
+	c.mu.Unlock()
+	if c.orderCache == nil {
 		return 0
 	}
 	return c.orderCache.Clear()
 }
 
+// RefreshOrderCache re-fetches expired region books that scans requested
+// recently. With persisted page ETags only changed pages are downloaded.
+// Returns the number of books refreshed.
+func (c *Client) RefreshOrderCache() int {
+	cache := c.ensureOrderCache()
+	if cache == nil {
+		return 0
+	}
+	refreshed := 0
+	for _, key := range cache.refreshCandidates(time.Now(), orderCacheRefreshIdle) {
+		sfKey := fmt.Sprintf("%d:%s", key.RegionID, key.OrderType)
+		_, err, _ := cache.Do(sfKey, func() (interface{}, error) {
+			return c.fetchRegionOrdersWithCache(context.Background(), key.RegionID, key.OrderType)
+		})
+		if err != nil {
+			log.Printf("[ESI] OrderCache refresh region=%d type=%s failed: %v", key.RegionID, key.OrderType, err)
+			continue
+		}
+		refreshed++
+	}
+	return refreshed
+}
+
 func (c *Client) ensureOrderCache() *OrderCache {
 	if c == nil {
 		return nil
@@ -291,25 +472,45 @@ func (c *Client) ensureOrderCache() *OrderCache {
 //
 // Uses singleflight to coalesce concurrent requests for the same region+orderType.
 func (c *Client) FetchRegionOrdersCached(regionID int32, orderType string) ([]MarketOrder, error) {
+	return c.FetchRegionOrdersCachedContext(context.Background(), regionID, orderType)
+}
+
+// FetchRegionOrdersCachedContext is FetchRegionOrdersCached with cancellation.
+// A coalesced fetch runs under the context of the caller that started it; if
+// that caller cancels, other callers still waiting retry under their own.
+func (c *Client) FetchRegionOrdersCachedContext(ctx context.Context, regionID int32, orderType string) ([]MarketOrder, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	cache := c.ensureOrderCache()
 	if cache == nil {
 		return nil, fmt.Errorf("esi client is nil")
 	}
+	cache.markUsed(orderCacheKey{RegionID: regionID, OrderType: orderType})
 	sfKey := fmt.Sprintf("%d:%s", regionID, orderType)
 
-	result, err, _ := cache.Do(sfKey, func() (interface{}, error) {
-		return c.fetchRegionOrdersWithCache(regionID, orderType)
-	})
-	if err != nil {
-		return nil, err
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err, _ := cache.Do(sfKey, func() (interface{}, error) {
+			return c.fetchRegionOrdersWithCache(ctx, regionID, orderType)
+		})
+		if err != nil {
+			if errors.Is(err, context.Canceled) && ctx.Err() == nil {
+				continue
+			}
+			return nil, err
+		}
+		return result.([]MarketOrder), nil
 	}
-	return result.([]MarketOrder), nil
 }
 
 // fetchRegionOrdersWithCache is the actual implementation behind singleflight.
-func (c *Client) fetchRegionOrdersWithCache(regionID int32, orderType string) ([]MarketOrder, error) {
+func (c *Client) fetchRegionOrdersWithCache(ctx context.Context, regionID int32, orderType string) ([]MarketOrder, error) {
 	// 1. Check cache
 	orders, etag, hit := c.orderCache.Get(regionID, orderType)
+	metrics.ObserveCache("region_orders", hit)
 	if hit {
 		log.Printf("[ESI] OrderCache HIT region=%d type=%s (%d orders)", regionID, orderType, len(orders))
 		return orders, nil
@@ -320,7 +521,7 @@ func (c *Client) fetchRegionOrdersWithCache(regionID int32, orderType string) ([
 
 	// 2. If we have an ETag, try conditional request on page 1
 	if etag != "" {
-		notModified, newExpires, err := c.conditionalCheck(url+"&page=1", etag)
+		notModified, newExpires, err := c.conditionalCheckContext(ctx, url+"&page=1", etag)
 		if err == nil && notModified {
 			// 304 — data unchanged, refresh expiry
 			c.orderCache.Touch(regionID, orderType, newExpires)
@@ -334,7 +535,7 @@ func (c *Client) fetchRegionOrdersWithCache(regionID int32, orderType string) ([
 	}
 
 	// 3. Full fetch
-	allOrders, respEtag, respExpires, err := c.getPaginatedDirectWithHeaders(url, regionID)
+	allOrders, respEtag, respExpires, err := c.getPaginatedDirectWithHeadersContext(ctx, url, regionID)
 	if err != nil {
 		return nil, err
 	}
@@ -380,7 +581,7 @@ func (c *Client) conditionalCheckContext(ctx context.Context, pageURL, etag stri
 	}
 	req.Header.Set("If-None-Match", etag)
 
-	resp, err := c.http.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return false, time.Time{}, err
 	}
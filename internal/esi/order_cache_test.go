@@ -119,3 +119,66 @@ func TestOrderCacheClearResetsSingleflightGroup(t *testing.T) {
 		t.Fatal("original singleflight call did not finish")
 	}
 }
+
+func TestOrderCacheTypeOrdersFromRegionBooks(t *testing.T) {
+	oc := NewOrderCache()
+	now := time.Now().UTC()
+	sell := []MarketOrder{{OrderID: 1, TypeID: 34}, {OrderID: 2, TypeID: 35}}
+	buy := []MarketOrder{{OrderID: 3, TypeID: 34, IsBuyOrder: true}}
+
+	oc.Put(10000002, "sell", sell, "s1", now.Add(5*time.Minute))
+	if _, ok := oc.TypeOrders(10000002, 34); ok {
+		t.Fatal("TypeOrders hit with only the sell book cached")
+	}
+	oc.Put(10000002, "buy", buy, "b1", now.Add(5*time.Minute))
+	got, ok := oc.TypeOrders(10000002, 34)
+	if !ok || len(got) != 2 || got[0].OrderID != 1 || got[1].OrderID != 3 {
+		t.Fatalf("TypeOrders = %+v, %v", got, ok)
+	}
+
+	oc.Put(10000002, "buy", buy, "b2", now.Add(-time.Minute))
+	if _, ok := oc.TypeOrders(10000002, 34); ok {
+		t.Fatal("TypeOrders hit with an expired buy book")
+	}
+}
+
+func TestOrderCacheRegionOrderCounts(t *testing.T) {
+	oc := NewOrderCache()
+	if _, _, ok := oc.RegionOrderCounts(10000002); ok {
+		t.Fatal("RegionOrderCounts hit on an empty cache")
+	}
+	// Expired books still count: they are the last snapshot we have.
+	oc.Put(10000002, "sell", []MarketOrder{{OrderID: 1, TypeID: 34}, {OrderID: 2, TypeID: 34}}, "s1", time.Now().Add(-time.Minute))
+	oc.Put(10000002, "buy", []MarketOrder{{OrderID: 3, TypeID: 34, IsBuyOrder: true}, {OrderID: 4, TypeID: 35, IsBuyOrder: true}}, "b1", time.Now().Add(time.Minute))
+	counts, updated, ok := oc.RegionOrderCounts(10000002)
+	if !ok || updated.IsZero() {
+		t.Fatalf("RegionOrderCounts ok = %v updated = %v", ok, updated)
+	}
+	if counts[34] != (OrderCount{Buy: 1, Sell: 2}) || counts[35] != (OrderCount{Buy: 1}) {
+		t.Fatalf("counts = %+v", counts)
+	}
+}
+
+func TestOrderCacheRefreshCandidates(t *testing.T) {
+	oc := NewOrderCache()
+	now := time.Now()
+
+	oc.Put(10000002, "sell", nil, "s1", now.Add(-time.Minute)) // used now, expired
+	oc.Put(10000043, "sell", nil, "s2", now.Add(time.Minute))  // still fresh
+	oc.Put(10000032, "sell", nil, "s3", now.Add(-time.Minute)) // expired, idle
+	oc.mu.RLock()
+	oc.entries[orderCacheKey{RegionID: 10000032, OrderType: "sell"}].lastUsed.Store(now.Add(-time.Hour).UnixNano())
+	oc.mu.RUnlock()
+	oc.PutScoped(orderCacheKey{RegionID: 10000002, OrderType: "all", Scope: "region_type", TypeID: 34}, nil, "t", now.Add(-time.Minute))
+
+	keys := oc.refreshCandidates(now, orderCacheRefreshIdle)
+	if len(keys) != 1 || keys[0].RegionID != 10000002 || keys[0].OrderType != "sell" {
+		t.Fatalf("refreshCandidates = %+v", keys)
+	}
+
+	// Refreshing keeps the previous use time so idle books age out.
+	oc.Put(10000032, "sell", nil, "s4", now.Add(-time.Minute))
+	if keys := oc.refreshCandidates(now, orderCacheRefreshIdle); len(keys) != 1 {
+		t.Fatalf("refreshed idle book became a candidate: %+v", keys)
+	}
+}
@@ -0,0 +1,109 @@
+package esi
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// CachedPage is one market order page with its HTTP caching metadata.
+// Body is the raw JSON returned by ESI.
+type CachedPage struct {
+	URL       string
+	ETag      string
+	Expires   time.Time
+	Pages     int // X-Pages reported with the page
+	Body      []byte
+	FetchedAt time.Time // last 200 or 304 for this page
+}
+
+// PageCacheStore persists market order pages across restarts so paginated
+// fetches can revalidate each page with If-None-Match instead of downloading
+// the whole region again. NewClient uses its store when it implements
+// PageCacheStore.
+type PageCacheStore interface {
+	GetPage(url string) (CachedPage, bool)
+	SetPage(page CachedPage)
+	// TouchPage records a 304 revalidation.
+	TouchPage(url string, expires time.Time, fetchedAt time.Time)
+}
+
+// cachedOrderPage returns the stored page for pageURL and whether it is still
+// within its Expires window. Pages fetched before the last ClearOrderCache are
+// never treated as fresh, only revalidated.
+func (c *Client) cachedOrderPage(pageURL string) (page CachedPage, fresh bool, ok bool) {
+	c.mu.Lock()
+	store := c.pageStore
+	notBefore := c.pageCacheNotBefore
+	c.mu.Unlock()
+	if store == nil {
+		return CachedPage{}, false, false
+	}
+	page, ok = store.GetPage(pageURL)
+	if !ok || page.ETag == "" || len(page.Body) == 0 {
+		return CachedPage{}, false, false
+	}
+	fresh = time.Now().Before(page.Expires) && page.FetchedAt.After(notBefore)
+	return page, fresh, true
+}
+
+// decodeOrderPage decodes a 200 or 304 market order page. A 304 is served from
+// cached; a 200 body is stored for later revalidation. pages is X-Pages, falling
+// back to the cached value when a 304 omits it.
+func (c *Client) decodeOrderPage(resp *http.Response, pageURL string, cached CachedPage) (orders []MarketOrder, pages int, err error) {
+	now := time.Now().UTC()
+	pages = cached.Pages
+	if p, perr := strconv.Atoi(resp.Header.Get("X-Pages")); perr == nil && p > 0 {
+		pages = p
+	}
+	expires := parseExpires(resp)
+
+	c.mu.Lock()
+	store := c.pageStore
+	c.mu.Unlock()
+
+	if resp.StatusCode == http.StatusNotModified {
+		if err := json.Unmarshal(cached.Body, &orders); err != nil {
+			return nil, 0, err
+		}
+		if store != nil {
+			store.TouchPage(pageURL, expires, now)
+		}
+		return orders, pages, nil
+	}
+
+	if store == nil {
+		err = json.NewDecoder(resp.Body).Decode(&orders)
+		return orders, pages, err
+	}
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, 0, err
+	}
+	if err := json.Unmarshal(body, &orders); err != nil {
+		return nil, 0, err
+	}
+	if etag := resp.Header.Get("Etag"); etag != "" {
+		store.SetPage(CachedPage{
+			URL:       pageURL,
+			ETag:      etag,
+			Expires:   expires,
+			Pages:     pages,
+			Body:      body,
+			FetchedAt: now,
+		})
+	}
+	return orders, pages, nil
+}
+
+// freshOrderPage decodes a stored page that is still within Expires.
+func freshOrderPage(cached CachedPage) ([]MarketOrder, bool) {
+	var orders []MarketOrder
+	if err := json.Unmarshal(cached.Body, &orders); err != nil {
+		log.Printf("[ESI] Cached page %s unreadable: %v", cached.URL, err)
+		return nil, false
+	}
+	return orders, true
+}
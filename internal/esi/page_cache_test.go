@@ -0,0 +1,119 @@
+package esi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type memPageStore struct {
+	mu    sync.Mutex
+	pages map[string]CachedPage
+}
+
+func (m *memPageStore) GetPage(url string) (CachedPage, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.pages[url]
+	return p, ok
+}
+
+func (m *memPageStore) SetPage(page CachedPage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pages[page.URL] = page
+}
+
+func (m *memPageStore) TouchPage(url string, expires, fetchedAt time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if p, ok := m.pages[url]; ok {
+		p.Expires, p.FetchedAt = expires, fetchedAt
+		m.pages[url] = p
+	}
+}
+
+func TestPaginatedOrders_RevalidatesPagesWithETag(t *testing.T) {
+	var full, notModified int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		etag := `"p` + page + `"`
+		w.Header().Set("X-Pages", "2")
+		// Already expired, so every call revalidates.
+		w.Header().Set("Expires", time.Now().Add(-time.Minute).UTC().Format(time.RFC1123))
+		if r.Header.Get("If-None-Match") == etag {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&full, 1)
+		w.Header().Set("Etag", etag)
+		_, _ = w.Write([]byte(`[{"order_id":` + page + `,"type_id":34,"location_id":60003760,"price":5,"volume_remain":1}]`))
+	}))
+	defer srv.Close()
+
+	store := &memPageStore{pages: make(map[string]CachedPage)}
+	c := NewClient(nil)
+	c.http = srv.Client()
+	c.pageStore = store
+	url := srv.URL + "/orders?datasource=tranquility&order_type=sell"
+
+	for round := 0; round < 2; round++ {
+		orders, etag, _, err := c.getPaginatedDirectWithHeaders(url, 10000002)
+		if err != nil {
+			t.Fatalf("round %d: %v", round, err)
+		}
+		if len(orders) != 2 || orders[1].RegionID != 10000002 {
+			t.Fatalf("round %d: orders = %+v", round, orders)
+		}
+		if etag != `"p1"` {
+			t.Fatalf("round %d: etag = %q", round, etag)
+		}
+	}
+	if full != 2 || notModified != 2 {
+		t.Fatalf("full=%d notModified=%d, want 2 and 2", full, notModified)
+	}
+}
+
+func TestPaginatedOrders_ServesFreshPagesUntilCleared(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Etag", `"v1"`)
+		w.Header().Set("Expires", time.Now().Add(5*time.Minute).UTC().Format(time.RFC1123))
+		_, _ = w.Write([]byte(`[{"order_id":1,"type_id":34,"location_id":60003760,"price":5,"volume_remain":1}]`))
+	}))
+	defer srv.Close()
+
+	store := &memPageStore{pages: make(map[string]CachedPage)}
+	c := NewClient(nil)
+	c.http = srv.Client()
+	c.pageStore = store
+	url := srv.URL + "/orders?datasource=tranquility&order_type=sell"
+
+	fetch := func() {
+		t.Helper()
+		orders, _, _, err := c.getPaginatedDirectWithHeaders(url, 10000002)
+		if err != nil || len(orders) != 1 {
+			t.Fatalf("fetch = %d orders, %v", len(orders), err)
+		}
+	}
+	fetch()
+	fetch()
+	if requests != 1 {
+		t.Fatalf("requests = %d, want fresh page served from store", requests)
+	}
+
+	c.ClearOrderCache()
+	fetch()
+	if requests != 2 {
+		t.Fatalf("requests = %d, want revalidation after ClearOrderCache", requests)
+	}
+}
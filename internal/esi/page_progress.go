@@ -0,0 +1,56 @@
+package esi
+
+import "sync/atomic"
+
+// pageFetchTracker counts pages of one paginated region order fetch.
+type pageFetchTracker struct {
+	regionID int32
+	total    int
+	fetched  atomic.Int32
+}
+
+// PageFetchProgress sums the in-flight paginated order fetches for a set of
+// regions, for scan progress messages.
+type PageFetchProgress struct {
+	Regions int // regions with a fetch in flight
+	Fetched int
+	Total   int
+}
+
+// trackPageFetch registers a fetch whose first page already arrived. Each
+// fetch has its own tracker, so concurrent fetches of one URL (e.g. after a
+// cache miss in two scans) are counted separately.
+func (c *Client) trackPageFetch(regionID int32, totalPages int) *pageFetchTracker {
+	t := &pageFetchTracker{regionID: regionID, total: totalPages}
+	t.fetched.Store(1)
+	c.pageFetches.Store(t, struct{}{})
+	return t
+}
+
+func (c *Client) untrackPageFetch(t *pageFetchTracker) {
+	c.pageFetches.Delete(t)
+}
+
+// OrderPageProgress reports pages fetched so far for in-flight region order
+// fetches in regionIDs. Fetches shared with other scans are included.
+func (c *Client) OrderPageProgress(regionIDs map[int32]bool) PageFetchProgress {
+	var p PageFetchProgress
+	if c == nil {
+		return p
+	}
+	seen := make(map[int32]bool)
+	c.pageFetches.Range(func(k, _ interface{}) bool {
+		t := k.(*pageFetchTracker)
+		if !regionIDs[t.regionID] {
+			return true
+		}
+		if !seen[t.regionID] {
+			seen[t.regionID] = true
+			p.Regions++
+		}
+		p.Fetched += int(t.fetched.Load())
+		p.Total += t.total
+		return true
+	})
+	return p
+}
@@ -0,0 +1,74 @@
+package esi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPaginatedOrders_BoundedWorkersAndProgress(t *testing.T) {
+	const totalPages = 12
+	var inFlight, maxInFlight int32
+	var c *Client
+	var midProgress atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		page := r.URL.Query().Get("page")
+		if page == "6" {
+			midProgress.Store(c.OrderPageProgress(map[int32]bool{10000002: true}))
+		}
+		w.Header().Set("X-Pages", strconv.Itoa(totalPages))
+		_, _ = w.Write([]byte(`[{"order_id":` + page + `,"type_id":34}]`))
+	}))
+	defer srv.Close()
+
+	c = NewClient(nil)
+	c.http = srv.Client()
+	c.scanSem = make(chan struct{}, 3)
+
+	orders, err := c.GetPaginatedDirect(srv.URL+"/orders?datasource=tranquility&order_type=sell", 10000002)
+	if err != nil {
+		t.Fatalf("GetPaginatedDirect: %v", err)
+	}
+	if len(orders) != totalPages {
+		t.Fatalf("orders = %d, want %d", len(orders), totalPages)
+	}
+	if maxInFlight > 3 {
+		t.Errorf("max in-flight = %d, want <= 3", maxInFlight)
+	}
+	p, _ := midProgress.Load().(PageFetchProgress)
+	if p.Regions != 1 || p.Total != totalPages || p.Fetched < 1 || p.Fetched >= totalPages {
+		t.Errorf("mid-fetch progress = %+v", p)
+	}
+	if after := c.OrderPageProgress(map[int32]bool{10000002: true}); after.Total != 0 {
+		t.Errorf("progress after fetch = %+v, want none", after)
+	}
+}
+
+func TestOrderPageProgress_ConcurrentFetchesOfOneURL(t *testing.T) {
+	c := NewClient(nil)
+	first := c.trackPageFetch(10000002, 10)
+	second := c.trackPageFetch(10000002, 10)
+	second.fetched.Add(4)
+
+	if p := c.OrderPageProgress(map[int32]bool{10000002: true}); p.Regions != 1 || p.Fetched != 6 || p.Total != 20 {
+		t.Fatalf("progress with two fetches = %+v, want 6/20 in 1 region", p)
+	}
+	c.untrackPageFetch(first)
+	if p := c.OrderPageProgress(map[int32]bool{10000002: true}); p.Fetched != 5 || p.Total != 10 {
+		t.Fatalf("progress after first finished = %+v, want the second fetch's 5/10", p)
+	}
+	c.untrackPageFetch(second)
+	if p := c.OrderPageProgress(map[int32]bool{10000002: true}); p.Total != 0 {
+		t.Fatalf("progress after both finished = %+v, want none", p)
+	}
+}
@@ -0,0 +1,280 @@
+package esi
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"eve-flipper/internal/metrics"
+)
+
+const (
+	// ESI allows 100 non-2xx/3xx responses per rolling window before it
+	// answers everything with 420 (error limited) until the window resets.
+	// Below esiErrorSlowdownBelow remaining errors requests are paced across
+	// the rest of the window; below esiErrorStopBelow they wait for the reset.
+	esiErrorSlowdownBelow = 50
+	esiErrorStopBelow     = 10
+	// esiErrorLimitedWait is used when a 420 or 429 arrives without a
+	// Retry-After or reset header.
+	esiErrorLimitedWait = 60 * time.Second
+	// esiMaxPaceDelay caps a single paced wait in the slowdown band.
+	esiMaxPaceDelay = 5 * time.Second
+)
+
+// RateLimitState is a snapshot of the ESI error-limit tracker, exposed on
+// /api/status to debug stuck scans.
+type RateLimitState struct {
+	ErrorLimitRemain   int     `json:"error_limit_remain"` // -1 until ESI reports it
+	ErrorLimitResetSec float64 `json:"error_limit_reset_sec"`
+	BlockedForSec      float64 `json:"blocked_for_sec"` // hard pause after a 420/429
+	Throttling         bool    `json:"throttling"`      // requests are currently delayed
+	ThrottledRequests  int64   `json:"throttled_requests"`
+	ErrorLimited       int64   `json:"error_limited"`  // 420 responses seen
+	RateLimited        int64   `json:"rate_limited"`   // 429 responses seen
+	ServerErrors       int64   `json:"server_errors"`  // 5xx responses seen
+	InFlight           int     `json:"in_flight"`      // lightweight semaphore slots in use
+	ScanInFlight       int     `json:"scan_in_flight"` // bulk scan semaphore slots in use
+	LastErrorAt        string  `json:"last_error_at,omitempty"`
+	// Cool-down in effect after a 420/429: why, and when it lifts.
+	CooldownReason string `json:"cooldown_reason,omitempty"` // error_limited | rate_limited
+	CooldownUntil  string `json:"cooldown_until,omitempty"`
+}
+
+// errorLimiter tracks the ESI error budget from response headers and delays
+// outgoing requests when it runs low.
+type errorLimiter struct {
+	mu           sync.Mutex
+	now          func() time.Time
+	remain       int
+	resetAt      time.Time
+	blockedUntil time.Time
+	blockReason  string
+	throttled    int64
+	limited      int64
+	rateLimited  int64
+	serverErrors int64
+	lastErrorAt  time.Time
+}
+
+func newErrorLimiter() *errorLimiter {
+	return &errorLimiter{now: time.Now, remain: -1}
+}
+
+// delay returns how long the next request should wait.
+func (l *errorLimiter) delay() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if now.Before(l.blockedUntil) {
+		return l.blockedUntil.Sub(now)
+	}
+	if l.remain < 0 || !now.Before(l.resetAt) || l.remain >= esiErrorSlowdownBelow {
+		return 0
+	}
+	untilReset := l.resetAt.Sub(now)
+	if l.remain <= esiErrorStopBelow {
+		return untilReset
+	}
+	// Spread the remaining budget over the rest of the window.
+	return minDuration(untilReset/time.Duration(l.remain), esiMaxPaceDelay)
+}
+
+// wait blocks until the error budget allows another request.
+func (l *errorLimiter) wait(ctx context.Context) error {
+	d := l.delay()
+	if d <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	l.throttled++
+	l.mu.Unlock()
+	return sleepWithContext(ctx, withJitter(d))
+}
+
+// observe records the error-limit headers and status of an ESI response.
+func (l *errorLimiter) observe(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	remain, remainErr := strconv.Atoi(strings.TrimSpace(resp.Header.Get("X-Esi-Error-Limit-Remain")))
+	reset, resetErr := strconv.Atoi(strings.TrimSpace(resp.Header.Get("X-Esi-Error-Limit-Reset")))
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if remainErr == nil {
+		l.remain = remain
+	}
+	if resetErr == nil {
+		l.resetAt = now.Add(time.Duration(reset) * time.Second)
+	}
+	switch {
+	case resp.StatusCode == 420 || resp.StatusCode == 429:
+		reason := "error_limited"
+		if resp.StatusCode == 429 {
+			reason = "rate_limited"
+			l.rateLimited++
+		} else {
+			l.limited++
+		}
+		l.lastErrorAt = now
+		wait := esiErrorLimitedWait
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+			wait = d
+		} else if resetErr == nil && reset > 0 {
+			wait = time.Duration(reset) * time.Second
+		}
+		// Never shorten a cool-down already in effect.
+		if until := now.Add(wait); until.After(l.blockedUntil) {
+			l.blockedUntil = until
+			l.blockReason = reason
+		}
+		log.Printf("[ESI] %s (%d); pausing requests for %s", reason, resp.StatusCode, wait)
+	case resp.StatusCode >= 500:
+		l.serverErrors++
+		l.lastErrorAt = now
+	}
+}
+
+func (l *errorLimiter) snapshot() RateLimitState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	st := RateLimitState{
+		ErrorLimitRemain:  l.remain,
+		ThrottledRequests: l.throttled,
+		ErrorLimited:      l.limited,
+		RateLimited:       l.rateLimited,
+		ServerErrors:      l.serverErrors,
+	}
+	if now.Before(l.resetAt) {
+		st.ErrorLimitResetSec = l.resetAt.Sub(now).Seconds()
+	}
+	if now.Before(l.blockedUntil) {
+		st.BlockedForSec = l.blockedUntil.Sub(now).Seconds()
+		st.CooldownReason = l.blockReason
+		st.CooldownUntil = l.blockedUntil.UTC().Format(time.RFC3339)
+	}
+	st.Throttling = st.BlockedForSec > 0 || (l.remain >= 0 && l.remain < esiErrorSlowdownBelow && st.ErrorLimitResetSec > 0)
+	if !l.lastErrorAt.IsZero() {
+		st.LastErrorAt = l.lastErrorAt.UTC().Format(time.RFC3339)
+	}
+	return st
+}
+
+// cooldown returns how long requests stay paused after a 420/429.
+func (l *errorLimiter) cooldown() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if d := l.blockedUntil.Sub(l.now()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// parseRetryAfter reads a Retry-After header given as seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil && at.After(now) {
+		return at.Sub(now), true
+	}
+	return 0, false
+}
+
+// limitedTransport records the error-limit headers of responses from
+// matching hosts. Waiting happens in Client.do, before the request starts,
+// so a pause longer than the client timeout does not fail the request.
+type limitedTransport struct {
+	base    http.RoundTripper
+	limiter *errorLimiter
+	match   func(host string) bool
+}
+
+func (t *limitedTransport) limits(host string) bool {
+	return t.match == nil || t.match(host)
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	limited := t.limits(req.URL.Hostname())
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if limited {
+		observeESIRequest(req, resp, err, time.Since(start))
+	}
+	if err == nil && limited {
+		t.limiter.observe(resp)
+	}
+	return resp, err
+}
+
+// do sends req once the error limiter allows it. The wait runs outside
+// http.Client.Timeout, which then only covers the request itself.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if t, ok := c.http.Transport.(*limitedTransport); ok && t.limits(req.URL.Hostname()) {
+		if err := t.limiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	return c.http.Do(req)
+}
+
+// observeESIRequest records request metrics; a conditional request counts
+// as an ETag cache hit when ESI answers 304.
+func observeESIRequest(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	route := NormalizeRoute(req.URL.String())
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	metrics.ESIRequests.Inc(route, status)
+	metrics.ESIRequestDuration.Observe(elapsed.Seconds(), route)
+	if err == nil && req.Header.Get("If-None-Match") != "" {
+		metrics.ObserveCache("esi_etag", resp.StatusCode == http.StatusNotModified)
+	}
+}
+
+func isESIHost(host string) bool {
+	return strings.EqualFold(host, "esi.evetech.net")
+}
+
+// withJitter spreads d by up to +25% so concurrent waiters don't retry in lockstep.
+func withJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/4+1))
+}
+
+// RateLimitState returns the current ESI error-limit tracker state.
+func (c *Client) RateLimitState() RateLimitState {
+	var st RateLimitState
+	if c.limiter != nil {
+		st = c.limiter.snapshot()
+	} else {
+		st.ErrorLimitRemain = -1
+	}
+	st.InFlight = len(c.sem)
+	st.ScanInFlight = len(c.scanSem)
+	return st
+}
+
+// Cooldown returns how long ESI requests stay paused after an error-limited
+// (420) or rate-limited (429) response; 0 when not cooling down.
+func (c *Client) Cooldown() time.Duration {
+	if c.limiter == nil {
+		return 0
+	}
+	return c.limiter.cooldown()
+}
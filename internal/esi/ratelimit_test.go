@@ -0,0 +1,142 @@
+package esi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func limiterResponse(status, remain, reset int) *http.Response {
+	h := http.Header{}
+	h.Set("X-Esi-Error-Limit-Remain", strconv.Itoa(remain))
+	h.Set("X-Esi-Error-Limit-Reset", strconv.Itoa(reset))
+	return &http.Response{StatusCode: status, Header: h}
+}
+
+func TestErrorLimiterDelay(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newErrorLimiter()
+	l.now = func() time.Time { return now }
+
+	if d := l.delay(); d != 0 {
+		t.Fatalf("unknown budget delay = %v, want 0", d)
+	}
+	l.observe(limiterResponse(200, 90, 40))
+	if d := l.delay(); d != 0 {
+		t.Errorf("healthy budget delay = %v, want 0", d)
+	}
+	l.observe(limiterResponse(404, 20, 40))
+	if d := l.delay(); d != 2*time.Second {
+		t.Errorf("slowdown delay = %v, want 2s (40s / 20 errors)", d)
+	}
+	l.observe(limiterResponse(404, 5, 30))
+	if d := l.delay(); d != 30*time.Second {
+		t.Errorf("near-exhausted delay = %v, want wait for reset", d)
+	}
+	now = now.Add(31 * time.Second)
+	if d := l.delay(); d != 0 {
+		t.Errorf("delay after reset = %v, want 0", d)
+	}
+
+	l.observe(limiterResponse(420, 0, 12))
+	st := l.snapshot()
+	if st.ErrorLimited != 1 || st.BlockedForSec != 12 || !st.Throttling {
+		t.Errorf("state after 420 = %+v", st)
+	}
+	if d := l.delay(); d != 12*time.Second {
+		t.Errorf("delay after 420 = %v, want 12s", d)
+	}
+	l.observe(limiterResponse(503, 0, 12))
+	if st := l.snapshot(); st.ServerErrors != 1 || st.LastErrorAt == "" {
+		t.Errorf("5xx not counted: %+v", st)
+	}
+}
+
+func TestLimitedTransportObservesAndRetries(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Esi-Error-Limit-Remain", "99")
+		w.Header().Set("X-Esi-Error-Limit-Reset", "30")
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(nil)
+	c.http = &http.Client{Transport: &limitedTransport{base: srv.Client().Transport, limiter: c.limiter}}
+
+	var out struct{ OK bool }
+	if err := c.AuthGetJSON(srv.URL+"/corporations/1/wallets/", "token", &out); err != nil || !out.OK {
+		t.Fatalf("AuthGetJSON = %v (%+v), want retry after 500", err, out)
+	}
+	st := c.RateLimitState()
+	if st.ErrorLimitRemain != 99 || st.ServerErrors != 1 || st.InFlight != 0 {
+		t.Errorf("state = %+v", st)
+	}
+}
+
+func TestErrorLimiterRetryAfterCooldown(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newErrorLimiter()
+	l.now = func() time.Time { return now }
+
+	resp := limiterResponse(429, 80, 50)
+	resp.Header.Set("Retry-After", "25")
+	l.observe(resp)
+	st := l.snapshot()
+	if st.RateLimited != 1 || st.BlockedForSec != 25 || st.CooldownReason != "rate_limited" || st.CooldownUntil == "" {
+		t.Fatalf("state after 429 = %+v", st)
+	}
+	if d := l.cooldown(); d != 25*time.Second {
+		t.Fatalf("cooldown = %v, want 25s", d)
+	}
+
+	// A shorter Retry-After does not cut the pause short.
+	resp = limiterResponse(420, 0, 50)
+	resp.Header.Set("Retry-After", now.Add(10*time.Second).Format(http.TimeFormat))
+	l.observe(resp)
+	if d := l.cooldown(); d != 25*time.Second {
+		t.Fatalf("cooldown after shorter 420 = %v, want 25s", d)
+	}
+
+	now = now.Add(26 * time.Second)
+	if d := l.cooldown(); d != 0 {
+		t.Fatalf("cooldown after expiry = %v, want 0", d)
+	}
+	if st := l.snapshot(); st.CooldownReason != "" || st.ErrorLimited != 1 {
+		t.Fatalf("state after expiry = %+v", st)
+	}
+}
+
+func TestClientWaitsOutsideHTTPTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(nil)
+	c.http = &http.Client{
+		Timeout:   100 * time.Millisecond,
+		Transport: &limitedTransport{base: srv.Client().Transport, limiter: c.limiter},
+	}
+	// A cool-down longer than the client timeout pauses the request
+	// instead of failing it.
+	c.limiter.mu.Lock()
+	c.limiter.blockedUntil = time.Now().Add(300 * time.Millisecond)
+	c.limiter.mu.Unlock()
+
+	start := time.Now()
+	var out struct{ OK bool }
+	if err := c.AuthGetJSON(srv.URL+"/characters/1/wallet/", "token", &out); err != nil || !out.OK {
+		t.Fatalf("AuthGetJSON = %v (%+v), want success after the cool-down", err, out)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Fatalf("request finished after %v, before the cool-down ended", elapsed)
+	}
+}
@@ -0,0 +1,152 @@
+package esi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SwaggerSpec is the subset of the ESI swagger 2.0 document needed to check
+// that the routes and response fields we decode still exist.
+type SwaggerSpec struct {
+	BasePath    string                                 `json:"basePath"`
+	Paths       map[string]map[string]SwaggerOperation `json:"paths"`
+	Definitions map[string]SwaggerSchema               `json:"definitions"`
+}
+
+// SwaggerOperation is one method on a swagger path.
+type SwaggerOperation struct {
+	OperationID string                     `json:"operationId"`
+	Deprecated  bool                       `json:"deprecated"`
+	Responses   map[string]SwaggerResponse `json:"responses"`
+}
+
+// SwaggerResponse is one status code entry of an operation.
+type SwaggerResponse struct {
+	Schema *SwaggerSchema `json:"schema"`
+}
+
+// SwaggerSchema is a (possibly nested) JSON schema node.
+type SwaggerSchema struct {
+	Ref        string                   `json:"$ref"`
+	Type       string                   `json:"type"`
+	Items      *SwaggerSchema           `json:"items"`
+	Properties map[string]SwaggerSchema `json:"properties"`
+}
+
+// FetchSwaggerSpec downloads the swagger document for the "latest" ESI routes.
+func (c *Client) FetchSwaggerSpec(ctx context.Context) (*SwaggerSpec, error) {
+	var spec SwaggerSpec
+	if err := c.GetJSONContext(ctx, baseURL+"/swagger.json?datasource=tranquility", &spec); err != nil {
+		return nil, fmt.Errorf("esi swagger: %w", err)
+	}
+	if len(spec.Paths) == 0 {
+		return nil, fmt.Errorf("esi swagger: no paths in spec")
+	}
+	return &spec, nil
+}
+
+// ResponseFields returns the property names of the 200 response of GET path.
+// For array responses the element properties are returned. ok is false when
+// the route or its GET operation is missing. Scalar responses yield no fields.
+func (s *SwaggerSpec) ResponseFields(path string) (fields map[string]bool, op SwaggerOperation, ok bool) {
+	methods, found := s.Paths[path]
+	if !found {
+		return nil, SwaggerOperation{}, false
+	}
+	op, found = methods["get"]
+	if !found {
+		return nil, SwaggerOperation{}, false
+	}
+	fields = make(map[string]bool)
+	resp, found := op.Responses["200"]
+	if !found || resp.Schema == nil {
+		return fields, op, true
+	}
+	schema := s.resolve(resp.Schema)
+	if schema.Type == "array" && schema.Items != nil {
+		schema = s.resolve(schema.Items)
+	}
+	for name := range schema.Properties {
+		fields[name] = true
+	}
+	return fields, op, true
+}
+
+// resolve follows local "#/definitions/..." references.
+func (s *SwaggerSpec) resolve(schema *SwaggerSchema) *SwaggerSchema {
+	for depth := 0; schema != nil && schema.Ref != "" && depth < 8; depth++ {
+		def, ok := s.Definitions[strings.TrimPrefix(schema.Ref, "#/definitions/")]
+		if !ok {
+			return &SwaggerSchema{}
+		}
+		schema = &def
+	}
+	if schema == nil {
+		return &SwaggerSchema{}
+	}
+	return schema
+}
+
+// RouteWarning is a Warning header ESI attached to a response, usually a
+// deprecation notice ("199 - This route is deprecated") or a newer version
+// being available ("299 - This route has an update").
+type RouteWarning struct {
+	Route    string    `json:"route"` // path with IDs replaced by {id}
+	Warning  string    `json:"warning"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// NormalizeRoute strips scheme, host, version prefix and query, and replaces
+// numeric segments and swagger path parameters with {id}, so live URLs and
+// swagger templates compare equal.
+func NormalizeRoute(raw string) string {
+	path := raw
+	if u, err := url.Parse(raw); err == nil && u.Path != "" {
+		path = u.Path
+	}
+	for _, prefix := range []string{"/latest", "/legacy", "/dev", "/v1", "/v2", "/v3", "/v4", "/v5", "/v6"} {
+		if strings.HasPrefix(path, prefix+"/") {
+			path = strings.TrimPrefix(path, prefix)
+			break
+		}
+	}
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	for i, seg := range segs {
+		isParam := strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")
+		if isParam || (seg != "" && strings.Trim(seg, "0123456789") == "") {
+			segs[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segs, "/") + "/"
+}
+
+// noteRouteWarning records the Warning header of a response, logging the
+// first occurrence per route.
+func (c *Client) noteRouteWarning(rawURL string, header http.Header) {
+	warning := strings.TrimSpace(header.Get("Warning"))
+	if warning == "" {
+		return
+	}
+	route := NormalizeRoute(rawURL)
+	prev, loaded := c.routeWarnings.Swap(route, RouteWarning{Route: route, Warning: warning, LastSeen: time.Now().UTC()})
+	if !loaded || prev.(RouteWarning).Warning != warning {
+		log.Printf("[ESI] Route %s warning: %s", route, warning)
+	}
+}
+
+// RouteWarnings returns the Warning headers seen since startup, by route.
+func (c *Client) RouteWarnings() []RouteWarning {
+	var out []RouteWarning
+	c.routeWarnings.Range(func(_, v interface{}) bool {
+		out = append(out, v.(RouteWarning))
+		return true
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].Route < out[j].Route })
+	return out
+}
@@ -0,0 +1,57 @@
+package esi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestNormalizeRoute(t *testing.T) {
+	cases := map[string]string{
+		"https://esi.evetech.net/latest/corporations/98000001/wallets/3/journal/?datasource=tranquility&page=2": "/corporations/{id}/wallets/{id}/journal/",
+		"/corporations/{corporation_id}/wallets/{division}/journal/":                                            "/corporations/{id}/wallets/{id}/journal/",
+		"/v2/corporation/{corporation_id}/mining/observers":                                                     "/corporation/{id}/mining/observers/",
+	}
+	for in, want := range cases {
+		if got := NormalizeRoute(in); got != want {
+			t.Errorf("NormalizeRoute(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSwaggerSpecResponseFields(t *testing.T) {
+	var spec SwaggerSpec
+	raw := `{
+		"paths": {
+			"/a/{id}/": {"get": {"deprecated": true, "responses": {"200": {"schema": {"type": "array", "items": {"$ref": "#/definitions/row"}}}}}},
+			"/b/": {"post": {}}
+		},
+		"definitions": {"row": {"type": "object", "properties": {"x": {}, "y": {}}}}
+	}`
+	if err := json.Unmarshal([]byte(raw), &spec); err != nil {
+		t.Fatal(err)
+	}
+	fields, op, ok := spec.ResponseFields("/a/{id}/")
+	if !ok || !op.Deprecated || !fields["x"] || !fields["y"] || len(fields) != 2 {
+		t.Errorf("fields=%v op=%+v ok=%v", fields, op, ok)
+	}
+	if _, _, ok := spec.ResponseFields("/b/"); ok {
+		t.Error("POST-only path reported as GET")
+	}
+}
+
+func TestNoteRouteWarning(t *testing.T) {
+	c := &Client{}
+	c.noteRouteWarning("https://esi.evetech.net/latest/corporations/1/orders/?page=1", http.Header{})
+	if len(c.RouteWarnings()) != 0 {
+		t.Fatal("recorded a response without Warning header")
+	}
+	h := http.Header{}
+	h.Set("Warning", "199 - This route is deprecated")
+	c.noteRouteWarning("https://esi.evetech.net/latest/corporations/1/orders/?page=1", h)
+	c.noteRouteWarning("https://esi.evetech.net/latest/corporations/2/orders/", h)
+	got := c.RouteWarnings()
+	if len(got) != 1 || got[0].Route != "/corporations/{id}/orders/" {
+		t.Errorf("warnings = %+v", got)
+	}
+}
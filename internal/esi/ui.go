@@ -24,7 +24,7 @@ func (c *Client) OpenMarketWindow(typeID int64, accessToken string) error {
 	req.Header.Set("User-Agent", "eve-flipper/1.0 (github.com)")
 
 	log.Printf("[ESI] Sending OpenMarketWindow: type_id=%d, url=%s", typeID, url)
-	resp, err := c.http.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		log.Printf("[ESI] OpenMarketWindow HTTP error: type_id=%d, err=%v", typeID, err)
 		return fmt.Errorf("http request: %w", err)
@@ -63,7 +63,7 @@ func (c *Client) SetWaypoint(solarSystemID int64, clearOtherWaypoints, addToBegi
 
 	log.Printf("[ESI] Sending SetWaypoint: system_id=%d, clear=%t, add_to_beginning=%t, url=%s",
 		solarSystemID, clearOtherWaypoints, addToBeginning, url)
-	resp, err := c.http.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		log.Printf("[ESI] SetWaypoint HTTP error: system_id=%d, err=%v", solarSystemID, err)
 		return fmt.Errorf("http request: %w", err)
@@ -100,7 +100,7 @@ func (c *Client) OpenContractWindow(contractID int64, accessToken string) error
 	req.Header.Set("User-Agent", "eve-flipper/1.0 (github.com)")
 
 	log.Printf("[ESI] Sending OpenContractWindow: contract_id=%d, url=%s", contractID, url)
-	resp, err := c.http.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		log.Printf("[ESI] OpenContractWindow HTTP error: contract_id=%d, err=%v", contractID, err)
 		return fmt.Errorf("http request: %w", err)
@@ -0,0 +1,131 @@
+// Package evescout reads live Thera and Turnur wormhole connections from the
+// EVE-Scout public API.
+package evescout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"eve-flipper/internal/graph"
+)
+
+const (
+	baseURL = "https://api.eve-scout.com/v2/public"
+	// cacheTTL matches how often scouts update the map in practice.
+	cacheTTL = 5 * time.Minute
+)
+
+// Client fetches and caches wormhole connections.
+type Client struct {
+	HTTP    *http.Client
+	BaseURL string
+
+	mu      sync.Mutex
+	conns   []graph.WormholeConnection
+	fetched time.Time
+}
+
+// NewClient creates a client with default settings.
+func NewClient() *Client {
+	return &Client{
+		HTTP:    &http.Client{Timeout: 20 * time.Second},
+		BaseURL: baseURL,
+	}
+}
+
+// signature is one entry of /signatures.
+type signature struct {
+	SignatureType string    `json:"signature_type"`
+	OutSystemID   int32     `json:"out_system_id"`
+	OutSignature  string    `json:"out_signature"`
+	InSystemID    int32     `json:"in_system_id"`
+	InSystemName  string    `json:"in_system_name"`
+	InSignature   string    `json:"in_signature"`
+	MaxShipSize   string    `json:"max_ship_size"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// WormholeConnections returns the unexpired Thera and Turnur connections.
+// Results are cached for a few minutes; when a refresh fails the previous
+// connections are returned, minus any that expired meanwhile.
+func (c *Client) WormholeConnections(ctx context.Context) ([]graph.WormholeConnection, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.fetched.IsZero() || now.Sub(c.fetched) >= cacheTTL {
+		conns, err := c.fetch(ctx)
+		if err != nil {
+			if c.fetched.IsZero() {
+				return nil, err
+			}
+			log.Printf("[EVE-Scout] Refresh failed, using connections from %s: %v", c.fetched.Format("15:04:05"), err)
+		} else {
+			c.conns, c.fetched = conns, now
+		}
+	}
+
+	out := make([]graph.WormholeConnection, 0, len(c.conns))
+	for _, conn := range c.conns {
+		if !conn.Expired(now) {
+			out = append(out, conn)
+		}
+	}
+	return out, nil
+}
+
+func (c *Client) fetch(ctx context.Context) ([]graph.WormholeConnection, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	url := strings.TrimRight(c.BaseURL, "/") + "/signatures"
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "eve-flipper/1.0 (github.com)")
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("eve-scout: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("eve-scout %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var sigs []signature
+	if err := json.NewDecoder(resp.Body).Decode(&sigs); err != nil {
+		return nil, fmt.Errorf("eve-scout: decode: %w", err)
+	}
+	return connectionsFromSignatures(sigs), nil
+}
+
+func connectionsFromSignatures(sigs []signature) []graph.WormholeConnection {
+	out := make([]graph.WormholeConnection, 0, len(sigs))
+	for _, s := range sigs {
+		if !strings.EqualFold(s.SignatureType, "wormhole") || s.InSystemID == 0 {
+			continue
+		}
+		if s.OutSystemID != graph.TheraSystemID && s.OutSystemID != graph.TurnurSystemID {
+			continue
+		}
+		out = append(out, graph.WormholeConnection{
+			HubSystemID:     s.OutSystemID,
+			SystemID:        s.InSystemID,
+			SystemName:      s.InSystemName,
+			MaxShipSize:     s.MaxShipSize,
+			ExpiresAt:       s.ExpiresAt,
+			HubSignature:    s.OutSignature,
+			SystemSignature: s.InSignature,
+		})
+	}
+	return out
+}
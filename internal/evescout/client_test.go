@@ -0,0 +1,49 @@
+package evescout
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"eve-flipper/internal/graph"
+)
+
+func TestWormholeConnections_ParsesAndCaches(t *testing.T) {
+	future := time.Now().Add(4 * time.Hour).UTC().Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`[
+			{"signature_type":"wormhole","out_system_id":31000005,"out_signature":"ABC-123","in_system_id":30000142,"in_system_name":"Jita","in_signature":"XYZ-987","max_ship_size":"large","expires_at":"` + future + `"},
+			{"signature_type":"wormhole","out_system_id":30002086,"in_system_id":30002187,"in_system_name":"Amarr","expires_at":"` + past + `"},
+			{"signature_type":"combat","out_system_id":31000005,"in_system_id":30002659,"expires_at":"` + future + `"},
+			{"signature_type":"wormhole","out_system_id":31000001,"in_system_id":30002659,"expires_at":"` + future + `"}
+		]`))
+	}))
+	defer srv.Close()
+
+	c := NewClient()
+	c.BaseURL = srv.URL
+	conns, err := c.WormholeConnections(context.Background())
+	if err != nil {
+		t.Fatalf("WormholeConnections: %v", err)
+	}
+	if len(conns) != 1 {
+		t.Fatalf("got %d connections, want 1: %+v", len(conns), conns)
+	}
+	got := conns[0]
+	if got.HubSystemID != graph.TheraSystemID || got.SystemID != 30000142 || got.SystemName != "Jita" || got.MaxShipSize != "large" {
+		t.Fatalf("unexpected connection %+v", got)
+	}
+
+	if _, err := c.WormholeConnections(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("API called %d times, want 1 (cached)", n)
+	}
+}
@@ -108,9 +108,18 @@ func dangerLevel(kills int) string {
 
 // CheckRoute returns danger info for every system along the route from->to.
 func (c *Checker) CheckRoute(from, to int32, minSec float64) ([]SystemDanger, error) {
-	path := c.universe.GetPath(from, to, minSec)
+	return c.CheckRouteOn(c.universe, from, to, minSec)
+}
+
+// CheckRouteOn is CheckRoute with the path taken through u, such as a
+// universe with the user's route preferences. A nil u uses the checker's.
+func (c *Checker) CheckRouteOn(u *graph.Universe, from, to int32, minSec float64) ([]SystemDanger, error) {
+	if u == nil {
+		u = c.universe
+	}
+	path := u.GetPath(from, to, minSec)
 	if path == nil {
-		path = c.universe.GetPath(from, to, 0)
+		path = u.GetPath(from, to, 0)
 	}
 	if path == nil {
 		return nil, fmt.Errorf("no path from %d to %d", from, to)
@@ -1,6 +1,10 @@
 package graph
 
-import "sync"
+import (
+	"sync"
+
+	"eve-flipper/internal/metrics"
+)
 
 // pathCacheKey identifies a cached shortest-path query.
 type pathCacheKey struct {
@@ -21,6 +25,12 @@ type pathCache struct {
 
 const defaultPathCacheSize = 50_000
 
+// Path cache lookups are counted once per ShortestPath call.
+var (
+	pathCacheHits   = metrics.CacheLookups.With("path", "hit")
+	pathCacheMisses = metrics.CacheLookups.With("path", "miss")
+)
+
 func newPathCache(maxSize int) *pathCache {
 	if maxSize <= 0 {
 		maxSize = defaultPathCacheSize
@@ -129,13 +139,18 @@ func (u *Universe) ShortestPath(origin, dest int32) int {
 }
 
 // ShortestPathMinSecurity returns the shortest jump count using only systems with
-// security >= minSecurity. Uses BFS (all edges are unit weight).
-// Results are cached in an LRU cache (up to 50k entries).
+// security >= minSecurity. Uses BFS (all edges are unit weight); a pinned path
+// between the two systems is used as is when it passes the filters. Queries covered by PrecomputeRoutes
+// tables are answered from them; the rest are cached in an LRU cache (up to
+// 50k entries).
 // Use minSecurity <= 0 for no filter. Returns -1 if no path exists.
 func (u *Universe) ShortestPathMinSecurity(origin, dest int32, minSecurity float64) int {
 	if origin == dest {
 		return 0
 	}
+	if pin, ok := u.pinnedPath(origin, dest, minSecurity); ok {
+		return len(pin) - 1
+	}
 	if minSecurity > 0 {
 		if sec, ok := u.SystemSecurity[origin]; ok && sec < minSecurity {
 			return -1
@@ -145,18 +160,28 @@ func (u *Universe) ShortestPathMinSecurity(origin, dest int32, minSecurity float
 		}
 	}
 
-	// Check cache
 	tier := securityTier(minSecurity)
+	if d, ok := u.precomputedJumps(origin, dest, tier); ok {
+		return d
+	}
+
+	// Check cache
 	cacheKey := pathCacheKey{from: origin, to: dest, minSecTier: tier}
 	if u.pathCacheMu != nil {
 		if d, ok := u.pathCacheMu.get(cacheKey); ok {
+			pathCacheHits.Inc()
 			return d
 		}
-		// Also check reverse direction (undirected graph)
-		reverseKey := pathCacheKey{from: dest, to: origin, minSecTier: tier}
-		if d, ok := u.pathCacheMu.get(reverseKey); ok {
-			return d
+		// Also check reverse direction, unless avoided systems make the
+		// graph directed.
+		if len(u.avoided) == 0 {
+			reverseKey := pathCacheKey{from: dest, to: origin, minSecTier: tier}
+			if d, ok := u.pathCacheMu.get(reverseKey); ok {
+				pathCacheHits.Inc()
+				return d
+			}
 		}
+		pathCacheMisses.Inc()
 	}
 
 	d := u.bfs(origin, dest, minSecurity)
@@ -254,11 +279,15 @@ func (u *Universe) SystemsInRegions(regions map[int32]bool) map[int32]int {
 }
 
 // GetPath returns the list of system IDs from origin to dest (inclusive),
-// using only systems with security >= minSecurity. Returns nil if no path exists.
+// using only systems with security >= minSecurity, or the pinned path between
+// them when it passes the filters. Returns nil if no path exists.
 func (u *Universe) GetPath(from, to int32, minSecurity float64) []int32 {
 	if from == to {
 		return []int32{from}
 	}
+	if pin, ok := u.pinnedPath(from, to, minSecurity); ok {
+		return append([]int32(nil), pin...)
+	}
 	parent := make(map[int32]int32, 256)
 	parent[from] = from
 
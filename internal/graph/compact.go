@@ -0,0 +1,40 @@
+package graph
+
+import "sort"
+
+// Compact packs the adjacency lists into one backing array, dropping
+// duplicate neighbours and the spare capacity left by AddGate's appends.
+// Each list is capped at its length, so later appends (WithEdges) copy
+// instead of writing into a neighbour's list. Call once after loading.
+func (u *Universe) Compact() {
+	total := 0
+	for _, next := range u.Adj {
+		total += len(next)
+	}
+	ids := make([]int32, 0, len(u.Adj))
+	for id := range u.Adj {
+		ids = append(ids, id)
+	}
+	// Deterministic layout keeps neighbouring systems' lists close together.
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	backing := make([]int32, 0, total)
+	adj := make(map[int32][]int32, len(u.Adj))
+	for _, id := range ids {
+		start := len(backing)
+		for _, n := range u.Adj[id] {
+			dup := false
+			for _, seen := range backing[start:] {
+				if seen == n {
+					dup = true
+					break
+				}
+			}
+			if !dup {
+				backing = append(backing, n)
+			}
+		}
+		adj[id] = backing[start:len(backing):len(backing)]
+	}
+	u.Adj = adj
+}
@@ -0,0 +1,29 @@
+package graph
+
+import "testing"
+
+func TestCompactPacksAdjacency(t *testing.T) {
+	u := NewUniverse()
+	u.AddGate(1, 2)
+	u.AddGate(1, 3)
+	u.AddGate(1, 2) // duplicate gate
+	u.AddGate(2, 1)
+	u.AddGate(3, 1)
+	u.Compact()
+
+	if got := u.Adj[1]; len(got) != 2 || cap(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Fatalf("Adj[1] = %v (cap %d), want [2 3] with no spare capacity", got, cap(got))
+	}
+	if d := u.ShortestPath(2, 3); d != 2 {
+		t.Fatalf("ShortestPath(2, 3) = %d, want 2", d)
+	}
+
+	// Adding an edge to a packed list must not overwrite the next system's list.
+	w := u.WithEdges([]Edge{{From: 1, To: 4}})
+	if got := w.Adj[1]; len(got) != 3 || got[2] != 4 {
+		t.Fatalf("WithEdges Adj[1] = %v", got)
+	}
+	if got := u.Adj[2]; len(got) != 1 || got[0] != 1 {
+		t.Fatalf("Adj[2] = %v after WithEdges, want [1]", got)
+	}
+}
@@ -0,0 +1,58 @@
+package graph
+
+// Edge is an extra two-way, one-jump link between systems, such as a
+// wormhole or an Ansiblex jump bridge.
+type Edge struct {
+	From int32 `json:"from"`
+	To   int32 `json:"to"`
+}
+
+// WithEdges returns a copy of the universe with the edges added. The
+// receiver is not modified; system metadata is shared and only the adjacency
+// of linked systems is copied. The copy has its own path cache when the
+// receiver has one. Returns the receiver when no edge applies.
+func (u *Universe) WithEdges(edges []Edge) *Universe {
+	valid := edges[:0:0]
+	for _, e := range edges {
+		if e.From != 0 && e.To != 0 && e.From != e.To {
+			valid = append(valid, e)
+		}
+	}
+	if len(valid) == 0 {
+		return u
+	}
+
+	out := &Universe{
+		Adj:            make(map[int32][]int32, len(u.Adj)+1),
+		SystemRegion:   u.SystemRegion,
+		SystemSecurity: u.SystemSecurity,
+		SystemPosition: u.SystemPosition,
+		pins:           u.pins,
+		avoided:        u.avoided,
+	}
+	for id, next := range u.Adj {
+		out.Adj[id] = next
+	}
+	copied := make(map[int32]bool)
+	link := func(from, to int32) {
+		next := out.Adj[from]
+		for _, n := range next {
+			if n == to {
+				return
+			}
+		}
+		if !copied[from] {
+			next = append([]int32(nil), next...)
+			copied[from] = true
+		}
+		out.Adj[from] = append(next, to)
+	}
+	for _, e := range valid {
+		link(e.From, e.To)
+		link(e.To, e.From)
+	}
+	if u.pathCacheMu != nil {
+		out.InitPathCache()
+	}
+	return out
+}
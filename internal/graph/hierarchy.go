@@ -0,0 +1,303 @@
+package graph
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// witnessSettleLimit bounds each witness search while contracting. A search
+// that gives up early only adds a shortcut that was not needed, never a
+// wrong distance.
+const witnessSettleLimit = 64
+
+// hierarchy is a contraction hierarchy over the gate graph: nodes are
+// contracted least important first, adding shortcut edges that keep
+// distances between the remaining nodes. A query then searches only edges
+// to higher-ranked nodes from both ends and meets at the top.
+type hierarchy struct {
+	// up holds each node's edges to higher-ranked nodes, shortcuts included.
+	up [][]hierarchyEdge
+	// scratch pools distanceSearches so concurrent queries do not allocate.
+	scratch sync.Pool
+}
+
+type hierarchyEdge struct {
+	to     int32
+	weight int16
+}
+
+// buildHierarchy contracts the allowed nodes of the undirected graph adj.
+// Order is by edge difference (shortcuts added minus edges removed) plus
+// contracted neighbours, updated lazily.
+func buildHierarchy(adj [][]int32, allowed []bool) *hierarchy {
+	n := len(adj)
+	// remaining[v] maps each uncontracted neighbour to the edge weight.
+	remaining := make([]map[int32]int16, n)
+	for v := range remaining {
+		if allowed[v] {
+			remaining[v] = make(map[int32]int16, len(adj[v]))
+		}
+	}
+	for v, next := range adj {
+		if !allowed[v] {
+			continue
+		}
+		for _, w := range next {
+			if allowed[w] && int32(v) != w {
+				remaining[v][w] = 1
+				remaining[w][int32(v)] = 1
+			}
+		}
+	}
+
+	h := &hierarchy{up: make([][]hierarchyEdge, n)}
+	h.scratch.New = func() interface{} { return newDistanceSearch(n) }
+	contractedNeighbours := make([]int32, n)
+	witness := newDistanceSearch(n)
+	priority := func(v int32) int {
+		return len(witnessShortcuts(witness, remaining, v)) - len(remaining[v]) + int(contractedNeighbours[v])
+	}
+	queue := &contractionQueue{}
+	for v := range remaining {
+		if allowed[v] {
+			heap.Push(queue, contractionItem{node: int32(v), priority: priority(int32(v))})
+		}
+	}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(contractionItem)
+		v := item.node
+		// Lazy update: contract v only if it is still the least important.
+		if p := priority(v); queue.Len() > 0 && p > (*queue)[0].priority {
+			heap.Push(queue, contractionItem{node: v, priority: p})
+			continue
+		}
+		shortcuts := witnessShortcuts(witness, remaining, v)
+		up := make([]hierarchyEdge, 0, len(remaining[v]))
+		for u, weight := range remaining[v] {
+			up = append(up, hierarchyEdge{to: u, weight: weight})
+			delete(remaining[u], v)
+			contractedNeighbours[u]++
+		}
+		h.up[v] = up
+		remaining[v] = nil
+		for _, s := range shortcuts {
+			if old, ok := remaining[s.from][s.to]; !ok || s.weight < old {
+				remaining[s.from][s.to] = s.weight
+				remaining[s.to][s.from] = s.weight
+			}
+		}
+	}
+	return h
+}
+
+// distance returns the jumps between two nodes, or -1 when they are not
+// connected.
+func (h *hierarchy) distance(from, to int32) int {
+	if from == to {
+		return 0
+	}
+	forward := h.scratch.Get().(*distanceSearch)
+	backward := h.scratch.Get().(*distanceSearch)
+	defer h.scratch.Put(forward)
+	defer h.scratch.Put(backward)
+	h.search(forward, from)
+	h.search(backward, to)
+	best := -1
+	for _, v := range forward.touched {
+		if db := backward.dist[v]; db >= 0 {
+			if d := int(forward.dist[v] + db); best < 0 || d < best {
+				best = d
+			}
+		}
+	}
+	forward.reset()
+	backward.reset()
+	return best
+}
+
+// search runs Dijkstra from origin along upward edges only.
+func (h *hierarchy) search(s *distanceSearch, origin int32) {
+	s.visit(origin, 0)
+	for len(s.queue) > 0 {
+		item := s.queue.pop()
+		if item.dist > s.dist[item.node] || h.stalled(s, item) {
+			continue
+		}
+		for _, e := range h.up[item.node] {
+			s.relax(e.to, item.dist+e.weight)
+		}
+	}
+}
+
+// stalled reports whether a higher node already reached reaches item's
+// node by a shorter path, so nothing found through it can be shortest.
+// Edges are undirected, so the up edges also lead back down to the node.
+func (h *hierarchy) stalled(s *distanceSearch, item distanceItem) bool {
+	for _, e := range h.up[item.node] {
+		if d := s.dist[e.to]; d >= 0 && d+e.weight < item.dist {
+			return true
+		}
+	}
+	return false
+}
+
+// distanceSearch is the reusable state of one Dijkstra over dense nodes.
+type distanceSearch struct {
+	dist    []int16 // -1 = not reached
+	touched []int32
+	queue   distanceQueue
+}
+
+func newDistanceSearch(n int) *distanceSearch {
+	s := &distanceSearch{dist: make([]int16, n)}
+	for i := range s.dist {
+		s.dist[i] = -1
+	}
+	return s
+}
+
+func (s *distanceSearch) visit(node int32, dist int16) {
+	s.dist[node] = dist
+	s.touched = append(s.touched, node)
+	s.queue.push(distanceItem{node: node, dist: dist})
+}
+
+// relax records dist to node when it is shorter than the known one.
+func (s *distanceSearch) relax(node int32, dist int16) {
+	if d := s.dist[node]; d < 0 {
+		s.visit(node, dist)
+	} else if dist < d {
+		s.dist[node] = dist
+		s.queue.push(distanceItem{node: node, dist: dist})
+	}
+}
+
+func (s *distanceSearch) reset() {
+	for _, x := range s.touched {
+		s.dist[x] = -1
+	}
+	s.touched = s.touched[:0]
+	s.queue = s.queue[:0]
+}
+
+type shortcut struct {
+	from, to int32
+	weight   int16
+}
+
+// witnessShortcuts returns the edges needed between v's neighbours when v
+// is removed: one per pair whose only short path runs through v.
+func witnessShortcuts(s *distanceSearch, remaining []map[int32]int16, v int32) []shortcut {
+	var out []shortcut
+	for u, wu := range remaining[v] {
+		var limit int16
+		for x, wx := range remaining[v] {
+			if x > u && wu+wx > limit {
+				limit = wu + wx
+			}
+		}
+		if limit == 0 {
+			continue
+		}
+		witnessSearch(s, remaining, u, v, limit)
+		for x, wx := range remaining[v] {
+			if x <= u {
+				continue
+			}
+			if d := s.dist[x]; d < 0 || d > wu+wx {
+				out = append(out, shortcut{from: u, to: x, weight: wu + wx})
+			}
+		}
+		s.reset()
+	}
+	return out
+}
+
+// witnessSearch is a Dijkstra from origin that skips via and stops past
+// limit or after witnessSettleLimit nodes.
+func witnessSearch(s *distanceSearch, remaining []map[int32]int16, origin, via int32, limit int16) {
+	s.visit(origin, 0)
+	for settled := 0; len(s.queue) > 0 && settled < witnessSettleLimit; settled++ {
+		item := s.queue.pop()
+		if item.dist > s.dist[item.node] {
+			continue
+		}
+		if item.dist >= limit {
+			return
+		}
+		for x, weight := range remaining[item.node] {
+			if x != via {
+				s.relax(x, item.dist+weight)
+			}
+		}
+	}
+}
+
+type distanceItem struct {
+	node int32
+	dist int16
+}
+
+// distanceQueue is a binary min-heap of distanceItems.
+type distanceQueue []distanceItem
+
+func (q *distanceQueue) push(item distanceItem) {
+	*q = append(*q, item)
+	h := *q
+	for i := len(h) - 1; i > 0; {
+		parent := (i - 1) / 2
+		if h[parent].dist <= h[i].dist {
+			break
+		}
+		h[parent], h[i] = h[i], h[parent]
+		i = parent
+	}
+}
+
+func (q *distanceQueue) pop() distanceItem {
+	h := *q
+	top := h[0]
+	last := len(h) - 1
+	h[0] = h[last]
+	h = h[:last]
+	for i := 0; ; {
+		smallest := i
+		if l := 2*i + 1; l < len(h) && h[l].dist < h[smallest].dist {
+			smallest = l
+		}
+		if r := 2*i + 2; r < len(h) && h[r].dist < h[smallest].dist {
+			smallest = r
+		}
+		if smallest == i {
+			break
+		}
+		h[i], h[smallest] = h[smallest], h[i]
+		i = smallest
+	}
+	*q = h
+	return top
+}
+
+type contractionItem struct {
+	node     int32
+	priority int
+}
+
+// contractionQueue is a min-heap of nodes by contraction priority.
+type contractionQueue []contractionItem
+
+func (q contractionQueue) Len() int { return len(q) }
+func (q contractionQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority < q[j].priority
+	}
+	return q[i].node < q[j].node
+}
+func (q contractionQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *contractionQueue) Push(x interface{}) { *q = append(*q, x.(contractionItem)) }
+func (q *contractionQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
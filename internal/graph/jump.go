@@ -0,0 +1,205 @@
+package graph
+
+import (
+	"math"
+	"sync"
+)
+
+// MetersPerLightYear converts SDE map coordinates (meters) to light years.
+const MetersPerLightYear = 9_460_730_472_580_800.0
+
+// Jump drives cannot target high-security space.
+const jumpHighsecThreshold = 0.45
+
+// Regions a jump drive cannot reach: wormhole space, Pochven and the Jove regions.
+var jumpExcludedRegions = map[int32]bool{
+	10000004: true, // UUA-F4
+	10000017: true, // J7HZ-F
+	10000019: true, // A821-A
+	10000070: true, // Pochven
+}
+
+// SetPosition stores a system's galactic coordinates (meters).
+func (u *Universe) SetPosition(systemID int32, x, y, z float64) {
+	u.SystemPosition[systemID] = [3]float64{x, y, z}
+}
+
+// LightYearsBetween returns the straight-line distance between two systems.
+// ok is false when either system has no known position.
+func (u *Universe) LightYearsBetween(a, b int32) (float64, bool) {
+	pa, okA := u.SystemPosition[a]
+	pb, okB := u.SystemPosition[b]
+	if !okA || !okB {
+		return 0, false
+	}
+	dx, dy, dz := pa[0]-pb[0], pa[1]-pb[1], pa[2]-pb[2]
+	return math.Sqrt(dx*dx+dy*dy+dz*dz) / MetersPerLightYear, true
+}
+
+// IsJumpTarget reports whether a jump drive can land in the system:
+// known position, security below highsec, not in wormhole/Pochven/Jove space
+// and not avoided (see WithRoutePreferences).
+func (u *Universe) IsJumpTarget(systemID int32) bool {
+	if u.avoided[systemID] {
+		return false
+	}
+	if _, ok := u.SystemPosition[systemID]; !ok {
+		return false
+	}
+	if sec, ok := u.SystemSecurity[systemID]; !ok || sec >= jumpHighsecThreshold {
+		return false
+	}
+	region := u.SystemRegion[systemID]
+	if region >= 11000000 || jumpExcludedRegions[region] {
+		return false
+	}
+	return true
+}
+
+// JumpPlanner finds jump-drive chains between systems for a fixed range.
+// Neighbor lookups use a uniform spatial grid with cell size = range, so each
+// query only inspects the 27 surrounding cells. Single-source search trees are
+// cached per origin; a planner is safe for concurrent use.
+type JumpPlanner struct {
+	u       *Universe
+	rangeLY float64
+	grid    map[[3]int64][]int32
+
+	mu    sync.Mutex
+	trees map[int32]*jumpTree
+}
+
+type jumpTree struct {
+	parent map[int32]int32
+	ly     map[int32]float64
+}
+
+// NewJumpPlanner indexes every valid jump target for the given range in LY.
+func (u *Universe) NewJumpPlanner(rangeLY float64) *JumpPlanner {
+	p := &JumpPlanner{
+		u:       u,
+		rangeLY: rangeLY,
+		grid:    make(map[[3]int64][]int32),
+		trees:   make(map[int32]*jumpTree),
+	}
+	if rangeLY <= 0 {
+		return p
+	}
+	for id := range u.SystemPosition {
+		if !u.IsJumpTarget(id) {
+			continue
+		}
+		cell := p.cellOf(id)
+		p.grid[cell] = append(p.grid[cell], id)
+	}
+	return p
+}
+
+// RangeLY returns the planner's maximum single-jump distance.
+func (p *JumpPlanner) RangeLY() float64 { return p.rangeLY }
+
+func (p *JumpPlanner) cellOf(systemID int32) [3]int64 {
+	pos := p.u.SystemPosition[systemID]
+	size := p.rangeLY * MetersPerLightYear
+	return [3]int64{
+		int64(math.Floor(pos[0] / size)),
+		int64(math.Floor(pos[1] / size)),
+		int64(math.Floor(pos[2] / size)),
+	}
+}
+
+// neighbors returns jump targets within range of systemID (excluding itself).
+func (p *JumpPlanner) neighbors(systemID int32) []int32 {
+	if _, ok := p.u.SystemPosition[systemID]; !ok {
+		return nil
+	}
+	c := p.cellOf(systemID)
+	var out []int32
+	for dx := int64(-1); dx <= 1; dx++ {
+		for dy := int64(-1); dy <= 1; dy++ {
+			for dz := int64(-1); dz <= 1; dz++ {
+				for _, id := range p.grid[[3]int64{c[0] + dx, c[1] + dy, c[2] + dz}] {
+					if id == systemID {
+						continue
+					}
+					if d, ok := p.u.LightYearsBetween(systemID, id); ok && d <= p.rangeLY {
+						out = append(out, id)
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+// JumpPath returns the chain of systems from origin to dest (inclusive) with
+// the fewest jumps; among equal-jump chains the shortest total distance wins.
+// The origin may be any system with a position (e.g. highsec cyno-less
+// departure is the caller's concern); every later system must be a jump target.
+// Returns nil if dest is unreachable.
+func (p *JumpPlanner) JumpPath(origin, dest int32) []int32 {
+	if origin == dest {
+		return []int32{origin}
+	}
+	if p.rangeLY <= 0 || !p.u.IsJumpTarget(dest) {
+		return nil
+	}
+	tree := p.tree(origin)
+	if _, ok := tree.parent[dest]; !ok {
+		return nil
+	}
+	path := []int32{dest}
+	for cur := dest; cur != origin; {
+		cur = tree.parent[cur]
+		path = append(path, cur)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// tree runs (or reuses) a level-order search from origin. Within a level,
+// parents are re-pointed to whichever predecessor gives the lowest cumulative LY.
+func (p *JumpPlanner) tree(origin int32) *jumpTree {
+	p.mu.Lock()
+	if t, ok := p.trees[origin]; ok {
+		p.mu.Unlock()
+		return t
+	}
+	p.mu.Unlock()
+
+	t := &jumpTree{
+		parent: map[int32]int32{origin: origin},
+		ly:     map[int32]float64{origin: 0},
+	}
+	level := []int32{origin}
+	for len(level) > 0 {
+		next := make([]int32, 0)
+		inNext := make(map[int32]bool)
+		for _, cur := range level {
+			for _, nb := range p.neighbors(cur) {
+				d, _ := p.u.LightYearsBetween(cur, nb)
+				total := t.ly[cur] + d
+				if _, seen := t.parent[nb]; seen && !inNext[nb] {
+					continue
+				}
+				if inNext[nb] && total >= t.ly[nb] {
+					continue
+				}
+				t.parent[nb] = cur
+				t.ly[nb] = total
+				if !inNext[nb] {
+					inNext[nb] = true
+					next = append(next, nb)
+				}
+			}
+		}
+		level = next
+	}
+
+	p.mu.Lock()
+	p.trees[origin] = t
+	p.mu.Unlock()
+	return t
+}
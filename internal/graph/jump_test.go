@@ -0,0 +1,61 @@
+package graph
+
+import "testing"
+
+// Systems laid out on the x axis, 1 LY apart: 1(hs) 2 3 4 5, plus 6 at 1.5 LY off-axis from 3.
+func makeJumpUniverse() *Universe {
+	u := NewUniverse()
+	for i, sec := range []float64{0.9, 0.3, 0.2, 0.1, -0.5} {
+		id := int32(i + 1)
+		u.SetSecurity(id, sec)
+		u.SetRegion(id, 10000001)
+		u.SetPosition(id, float64(i)*MetersPerLightYear, 0, 0)
+	}
+	u.SetSecurity(6, 0.4)
+	u.SetRegion(6, 10000001)
+	u.SetPosition(6, 2*MetersPerLightYear, 1.5*MetersPerLightYear, 0)
+	return u
+}
+
+func TestLightYearsBetween(t *testing.T) {
+	u := makeJumpUniverse()
+	d, ok := u.LightYearsBetween(1, 5)
+	if !ok || d < 3.999 || d > 4.001 {
+		t.Fatalf("LightYearsBetween(1,5) = %v,%v; want 4", d, ok)
+	}
+	if _, ok := u.LightYearsBetween(1, 99); ok {
+		t.Fatal("expected ok=false for unknown system")
+	}
+}
+
+func TestIsJumpTarget(t *testing.T) {
+	u := makeJumpUniverse()
+	if u.IsJumpTarget(1) {
+		t.Error("highsec system must not be a jump target")
+	}
+	if !u.IsJumpTarget(2) {
+		t.Error("lowsec system should be a jump target")
+	}
+	u.SetRegion(2, 10000070)
+	if u.IsJumpTarget(2) {
+		t.Error("Pochven must not be a jump target")
+	}
+}
+
+func TestJumpPath_FewestJumpsThenShortest(t *testing.T) {
+	u := makeJumpUniverse()
+	p := u.NewJumpPlanner(2.1)
+	path := p.JumpPath(2, 5)
+	// 2→4→5 or 2→3→5 are both 2 jumps; both are 3 LY — either is valid,
+	// but the detour via 6 (2.5 + 2.5 LY) must not win.
+	if len(path) != 3 || path[0] != 2 || path[2] != 5 || path[1] == 6 {
+		t.Fatalf("JumpPath(2,5) = %v", path)
+	}
+	if p.JumpPath(2, 1) != nil {
+		t.Error("highsec destination should be unreachable by jump")
+	}
+	short := u.NewJumpPlanner(0.5)
+	if short.JumpPath(2, 5) != nil {
+		t.Error("expected no path with 0.5 LY range")
+	}
+}
@@ -0,0 +1,200 @@
+package graph
+
+import "sort"
+
+// RoutePrecompute selects the jump tables built by PrecomputeRoutes.
+type RoutePrecompute struct {
+	// Hubs get a distance table to every system, so any query with a hub at
+	// either end is a single lookup.
+	Hubs []int32
+	// MinSecurities are the security filters to build tables for; 0 is all
+	// space. Queries at other filters fall back to BFS.
+	MinSecurities []float64
+	// Hierarchy also builds a contraction hierarchy per filter, answering
+	// queries between any two systems by searching a few hundred nodes.
+	Hierarchy bool
+}
+
+// routeTables are the precomputed jump tables of one adjacency.
+type routeTables struct {
+	spec RoutePrecompute
+	// index maps systemID -> dense index into ids and the tables.
+	index map[int32]int32
+	ids   []int32
+	// allowed marks the systems each security tier routes through.
+	allowed map[int8][]bool
+	// hubs maps (hub, tier) -> jumps from the hub by dense index, -1 when
+	// unreachable.
+	hubs        map[hubKey][]int16
+	hierarchies map[int8]*hierarchy
+}
+
+type hubKey struct {
+	hub  int32
+	tier int8
+}
+
+// PrecomputeRoutes builds jump tables for ShortestPathMinSecurity: a
+// distance table from each hub and, with p.Hierarchy, a contraction
+// hierarchy, for each of p.MinSecurities. Tables describe the current
+// adjacency, so call once after loading (after Compact) and before queries;
+// WithEdges copies do not inherit them. Calling again replaces the tables.
+func (u *Universe) PrecomputeRoutes(p RoutePrecompute) {
+	u.routes = buildRouteTables(u, p, nil)
+}
+
+// buildRouteTables builds u's tables. With base, the tables of a universe
+// u was filtered from by avoiding systems, the index is shared and tiers in
+// which no avoided system is routable are reused as they are. Affected tiers
+// only get their hub tables rebuilt, a BFS per hub: contracting a hierarchy
+// takes far longer, and such universes are built on the first request with
+// the preferences, so other queries fall back to cached BFS.
+func buildRouteTables(u *Universe, p RoutePrecompute, base *routeTables) *routeTables {
+	t := &routeTables{
+		spec:        p,
+		allowed:     make(map[int8][]bool),
+		hubs:        make(map[hubKey][]int16),
+		hierarchies: make(map[int8]*hierarchy),
+	}
+	if base != nil {
+		t.index, t.ids = base.index, base.ids
+	} else {
+		seen := make(map[int32]bool, len(u.Adj))
+		for id, next := range u.Adj {
+			if !seen[id] {
+				seen[id] = true
+				t.ids = append(t.ids, id)
+			}
+			for _, n := range next {
+				if !seen[n] {
+					seen[n] = true
+					t.ids = append(t.ids, n)
+				}
+			}
+		}
+		sort.Slice(t.ids, func(i, j int) bool { return t.ids[i] < t.ids[j] })
+		t.index = make(map[int32]int32, len(t.ids))
+		for i, id := range t.ids {
+			t.index[id] = int32(i)
+		}
+	}
+	var adj [][]int32
+	denseAdj := func() [][]int32 {
+		if adj == nil {
+			adj = make([][]int32, len(t.ids))
+			for id, next := range u.Adj {
+				from, ok := t.index[id]
+				if !ok {
+					continue
+				}
+				for _, n := range next {
+					if to, ok := t.index[n]; ok {
+						adj[from] = append(adj[from], to)
+					}
+				}
+			}
+		}
+		return adj
+	}
+
+	for _, minSec := range p.MinSecurities {
+		tier := securityTier(minSec)
+		if _, done := t.allowed[tier]; done {
+			continue
+		}
+		allowed := make([]bool, len(t.ids))
+		affected := false
+		for i, id := range t.ids {
+			if minSec > 0 {
+				if sec, ok := u.SystemSecurity[id]; !ok || sec < minSec {
+					continue
+				}
+			}
+			if u.avoided[id] {
+				affected = true
+				continue
+			}
+			allowed[i] = true
+		}
+		if reused, ok := base.tier(tier); ok && !affected {
+			t.allowed[tier] = reused
+			for _, hub := range p.Hubs {
+				if dist, ok := base.hubs[hubKey{hub, tier}]; ok {
+					t.hubs[hubKey{hub, tier}] = dist
+				}
+			}
+			if h := base.hierarchies[tier]; h != nil {
+				t.hierarchies[tier] = h
+			}
+			continue
+		}
+		t.allowed[tier] = allowed
+		for _, hub := range p.Hubs {
+			if i, ok := t.index[hub]; ok && allowed[i] {
+				t.hubs[hubKey{hub, tier}] = denseBFS(denseAdj(), allowed, i)
+			}
+		}
+		if p.Hierarchy && base == nil {
+			t.hierarchies[tier] = buildHierarchy(denseAdj(), allowed)
+		}
+	}
+	return t
+}
+
+// tier returns the routable systems of a precomputed tier; t may be nil.
+func (t *routeTables) tier(tier int8) ([]bool, bool) {
+	if t == nil {
+		return nil, false
+	}
+	allowed, ok := t.allowed[tier]
+	return allowed, ok
+}
+
+// denseBFS returns the jumps from origin to every allowed node.
+func denseBFS(adj [][]int32, allowed []bool, origin int32) []int16 {
+	dist := make([]int16, len(adj))
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[origin] = 0
+	queue := make([]int32, 0, len(adj))
+	queue = append(queue, origin)
+	for head := 0; head < len(queue); head++ {
+		current := queue[head]
+		for _, n := range adj[current] {
+			if allowed[n] && dist[n] < 0 {
+				dist[n] = dist[current] + 1
+				queue = append(queue, n)
+			}
+		}
+	}
+	return dist
+}
+
+// precomputedJumps answers a jump query from the tables, if they cover it:
+// both systems must be routable at the tier.
+func (u *Universe) precomputedJumps(origin, dest int32, tier int8) (int, bool) {
+	t := u.routes
+	if t == nil {
+		return 0, false
+	}
+	allowed, ok := t.allowed[tier]
+	if !ok {
+		return 0, false
+	}
+	from, okFrom := t.index[origin]
+	to, okTo := t.index[dest]
+	if !okFrom || !okTo || !allowed[from] || !allowed[to] {
+		return 0, false
+	}
+	if dist, ok := t.hubs[hubKey{origin, tier}]; ok {
+		return int(dist[to]), true
+	}
+	if dist, ok := t.hubs[hubKey{dest, tier}]; ok {
+		return int(dist[from]), true
+	}
+	if h := t.hierarchies[tier]; h != nil {
+		return h.distance(from, to), true
+	}
+	return 0, false
+}
@@ -0,0 +1,156 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// makeRandomUniverse builds a connected chain of n systems with extra
+// random gates and random security, like a small cluster of regions.
+func makeRandomUniverse(n int, seed int64) *Universe {
+	rng := rand.New(rand.NewSource(seed))
+	u := NewUniverse()
+	link := func(a, b int32) {
+		u.AddGate(a, b)
+		u.AddGate(b, a)
+	}
+	for i := int32(1); i <= int32(n); i++ {
+		u.SetSecurity(i, float64(rng.Intn(11))/10)
+		if i > 1 {
+			link(i-1, i)
+		}
+	}
+	for i := 0; i < n/2; i++ {
+		a, b := int32(rng.Intn(n)+1), int32(rng.Intn(n)+1)
+		if a != b {
+			link(a, b)
+		}
+	}
+	u.Compact()
+	return u
+}
+
+func TestPrecomputeRoutes_MatchesBFS(t *testing.T) {
+	const n = 120
+	u := makeRandomUniverse(n, 7)
+	u.PrecomputeRoutes(RoutePrecompute{Hubs: []int32{1, 60}, MinSecurities: []float64{0, 0.45}, Hierarchy: true})
+
+	for _, minSec := range []float64{0, 0.45} {
+		for from := int32(1); from <= n; from++ {
+			for to := int32(1); to <= n; to++ {
+				if from == to {
+					continue
+				}
+				if sec := u.SystemSecurity[from]; minSec > 0 && sec < minSec {
+					continue
+				}
+				if sec := u.SystemSecurity[to]; minSec > 0 && sec < minSec {
+					continue
+				}
+				want := u.bfs(from, to, minSec)
+				got, ok := u.precomputedJumps(from, to, securityTier(minSec))
+				if !ok {
+					t.Fatalf("minSec %.2f %d->%d not covered by the tables", minSec, from, to)
+				}
+				if got != want {
+					t.Fatalf("minSec %.2f %d->%d = %d, BFS says %d", minSec, from, to, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestPrecomputeRoutes_HubsWithoutHierarchy(t *testing.T) {
+	u := makeTestUniverse()
+	u.PrecomputeRoutes(RoutePrecompute{Hubs: []int32{4}, MinSecurities: []float64{0}})
+
+	if d, ok := u.precomputedJumps(1, 4, 0); !ok || d != 2 {
+		t.Fatalf("1->4 from hub table = %d (ok=%v), want 2", d, ok)
+	}
+	if d, ok := u.precomputedJumps(4, 2, 0); !ok || d != 2 {
+		t.Fatalf("4->2 from hub table = %d (ok=%v), want 2", d, ok)
+	}
+	if _, ok := u.precomputedJumps(1, 2, 0); ok {
+		t.Fatal("1->2 has no hub at either end and no hierarchy")
+	}
+	if _, ok := u.precomputedJumps(1, 4, securityTier(0.5)); ok {
+		t.Fatal("tier 0.5 was not precomputed")
+	}
+}
+
+func TestPrecomputeRoutes_DerivedUniverses(t *testing.T) {
+	u := makeTestUniverse()
+	u.PrecomputeRoutes(RoutePrecompute{Hubs: []int32{1}, MinSecurities: []float64{0}, Hierarchy: true})
+
+	bridged := u.WithEdges([]Edge{{From: 1, To: 4}})
+	if d := bridged.ShortestPath(1, 4); d != 1 {
+		t.Fatalf("bridged 1->4 = %d, want 1: tables must not outlive the adjacency", d)
+	}
+	avoid := u.WithRoutePreferences(RoutePreferences{AvoidSystems: []int32{3}})
+	if d := avoid.ShortestPath(1, 4); d != -1 {
+		t.Fatalf("1->4 avoiding 3 = %d, want -1", d)
+	}
+	pinned := u.WithRoutePreferences(RoutePreferences{Pins: [][]int32{{1, 2, 3, 4}}})
+	if pinned.routes != u.routes {
+		t.Fatal("pins alone should keep the precomputed tables")
+	}
+	if d := pinned.ShortestPath(4, 1); d != 3 {
+		t.Fatalf("pinned 4->1 = %d, want 3", d)
+	}
+	if d := pinned.ShortestPath(2, 4); d != 2 {
+		t.Fatalf("2->4 = %d, want 2", d)
+	}
+}
+
+func TestPrecomputeRoutes_AvoidedSystemsMatchBFS(t *testing.T) {
+	const n = 120
+	u := makeRandomUniverse(n, 11)
+	u.PrecomputeRoutes(RoutePrecompute{Hubs: []int32{1, 60}, MinSecurities: []float64{0, 0.45}, Hierarchy: true})
+
+	// Avoid only lowsec systems: the highsec tier is reused as is.
+	var avoid []int32
+	for id := int32(2); id <= n && len(avoid) < 5; id++ {
+		if u.SystemSecurity[id] < 0.45 {
+			avoid = append(avoid, id)
+		}
+	}
+	filtered := u.WithRoutePreferences(RoutePreferences{AvoidSystems: avoid})
+	if filtered.routes == nil || filtered.routes.hierarchies[securityTier(0.45)] != u.routes.hierarchies[securityTier(0.45)] {
+		t.Fatal("highsec tables should be reused when no avoided system is highsec")
+	}
+	if filtered.routes.hierarchies[0] != nil || filtered.routes.hubs[hubKey{1, 0}] == nil {
+		t.Fatal("all-space tier should keep only hub tables rebuilt around avoided systems")
+	}
+	for _, minSec := range []float64{0, 0.45} {
+		for from := int32(1); from <= n; from++ {
+			for to := int32(1); to <= n; to++ {
+				if from == to {
+					continue
+				}
+				if minSec > 0 && (filtered.SystemSecurity[from] < minSec || filtered.SystemSecurity[to] < minSec) {
+					continue
+				}
+				want := filtered.bfs(from, to, minSec)
+				if got := filtered.ShortestPathMinSecurity(from, to, minSec); got != want {
+					t.Fatalf("minSec %.2f %d->%d = %d, BFS says %d", minSec, from, to, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestWithRoutePreferences_CachedPerPreferences(t *testing.T) {
+	u := makeTestUniverse()
+	prefs := RoutePreferences{AvoidSystems: []int32{3}}
+	first := u.WithRoutePreferences(prefs)
+	if again := u.WithRoutePreferences(RoutePreferences{AvoidSystems: []int32{3}}); again != first {
+		t.Fatal("same preferences should return the cached copy")
+	}
+	if other := u.WithRoutePreferences(RoutePreferences{AvoidSystems: []int32{2}}); other == first {
+		t.Fatal("different preferences share a copy")
+	}
+	u.ForgetRoutePreferences(prefs)
+	if again := u.WithRoutePreferences(prefs); again == first {
+		t.Fatal("forgotten preferences should build a new copy")
+	}
+}
@@ -0,0 +1,195 @@
+package graph
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RoutePreferences are a user's routing rules: systems routes never enter
+// (gank chokepoints, war target staging) and preferred paths between
+// system pairs.
+type RoutePreferences struct {
+	AvoidSystems []int32
+	// Pins are paths of gate-connected systems, origin first. Each one
+	// replaces the computed path between its two ends, in either direction.
+	Pins [][]int32
+}
+
+// Empty reports whether the preferences change no route.
+func (p RoutePreferences) Empty() bool {
+	return len(p.AvoidSystems) == 0 && len(p.Pins) == 0
+}
+
+type pinKey [2]int32
+
+// maxCachedRoutePreferences bounds the derived universes kept per receiver.
+const maxCachedRoutePreferences = 16
+
+// key identifies the preferences in the derived universe cache.
+func (p RoutePreferences) key() string {
+	var b strings.Builder
+	for _, id := range p.AvoidSystems {
+		b.WriteString(strconv.Itoa(int(id)))
+		b.WriteByte(',')
+	}
+	for _, pin := range p.Pins {
+		b.WriteByte('|')
+		for _, id := range pin {
+			b.WriteString(strconv.Itoa(int(id)))
+			b.WriteByte(',')
+		}
+	}
+	return b.String()
+}
+
+// preferredUniverse is a cached WithRoutePreferences result, built once.
+type preferredUniverse struct {
+	once sync.Once
+	u    *Universe
+}
+
+// WithRoutePreferences returns a copy of the universe that never enters the
+// avoided systems and routes pinned pairs along their pins. Avoided systems
+// keep their own outgoing gates, so a route may still start in one. System
+// metadata is shared and adjacency lists are only copied where filtered;
+// the copy has its own path cache when the receiver has one. Precomputed
+// tables are kept for security tiers no avoided system is in; the others
+// only get their hub tables rebuilt. Copies are cached per preferences on
+// the receiver (see ForgetRoutePreferences). Returns the receiver when p is
+// empty.
+func (u *Universe) WithRoutePreferences(p RoutePreferences) *Universe {
+	if p.Empty() {
+		return u
+	}
+	key := p.key()
+	u.prefMu.Lock()
+	entry, ok := u.prefCache[key]
+	if !ok {
+		if u.prefCache == nil {
+			u.prefCache = make(map[string]*preferredUniverse)
+		}
+		for len(u.prefOrder) >= maxCachedRoutePreferences {
+			delete(u.prefCache, u.prefOrder[0])
+			u.prefOrder = u.prefOrder[1:]
+		}
+		entry = &preferredUniverse{}
+		u.prefCache[key] = entry
+		u.prefOrder = append(u.prefOrder, key)
+	}
+	u.prefMu.Unlock()
+	entry.once.Do(func() { entry.u = u.withRoutePreferences(p) })
+	return entry.u
+}
+
+// ForgetRoutePreferences drops the cached copy for p, e.g. after the user
+// who had them changes their settings.
+func (u *Universe) ForgetRoutePreferences(p RoutePreferences) {
+	if p.Empty() {
+		return
+	}
+	key := p.key()
+	u.prefMu.Lock()
+	defer u.prefMu.Unlock()
+	if _, ok := u.prefCache[key]; !ok {
+		return
+	}
+	delete(u.prefCache, key)
+	for i, k := range u.prefOrder {
+		if k == key {
+			u.prefOrder = append(u.prefOrder[:i:i], u.prefOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+func (u *Universe) withRoutePreferences(p RoutePreferences) *Universe {
+	out := &Universe{
+		Adj:            u.Adj,
+		SystemRegion:   u.SystemRegion,
+		SystemSecurity: u.SystemSecurity,
+		SystemPosition: u.SystemPosition,
+		pins:           u.pins,
+		avoided:        u.avoided,
+	}
+	if len(p.AvoidSystems) == 0 {
+		// Pins are checked before the tables, which still match Adj.
+		out.routes = u.routes
+	} else {
+		avoid := make(map[int32]bool, len(p.AvoidSystems)+len(u.avoided))
+		for id := range u.avoided {
+			avoid[id] = true
+		}
+		for _, id := range p.AvoidSystems {
+			avoid[id] = true
+		}
+		out.avoided = avoid
+		out.Adj = make(map[int32][]int32, len(u.Adj))
+		for id, next := range u.Adj {
+			kept := next
+			for i, n := range next {
+				if !avoid[n] {
+					continue
+				}
+				// Copy on the first avoided neighbour only.
+				kept = append(make([]int32, 0, len(next)-1), next[:i]...)
+				for _, m := range next[i+1:] {
+					if !avoid[m] {
+						kept = append(kept, m)
+					}
+				}
+				break
+			}
+			out.Adj[id] = kept
+		}
+	}
+	if len(p.Pins) > 0 {
+		out.pins = make(map[pinKey][]int32, len(u.pins)+2*len(p.Pins))
+		for k, v := range u.pins {
+			out.pins[k] = v
+		}
+		for _, pin := range p.Pins {
+			if len(pin) < 2 || pin[0] == pin[len(pin)-1] {
+				continue
+			}
+			path := append([]int32(nil), pin...)
+			reversed := make([]int32, len(path))
+			for i, id := range path {
+				reversed[len(path)-1-i] = id
+			}
+			out.pins[pinKey{path[0], path[len(path)-1]}] = path
+			out.pins[pinKey{reversed[0], reversed[len(reversed)-1]}] = reversed
+		}
+	}
+	if len(p.AvoidSystems) > 0 && u.routes != nil {
+		out.routes = buildRouteTables(out, u.routes.spec, u.routes)
+	}
+	if u.pathCacheMu != nil {
+		out.InitPathCache()
+	}
+	return out
+}
+
+// pinnedPath returns the pinned path from origin to dest, if any. A pin that
+// enters an avoided system, or a system below minSecurity, is not used: the
+// filters win and the route is searched as if there were no pin.
+func (u *Universe) pinnedPath(origin, dest int32, minSecurity float64) ([]int32, bool) {
+	if len(u.pins) == 0 {
+		return nil, false
+	}
+	path, ok := u.pins[pinKey{origin, dest}]
+	if !ok {
+		return nil, false
+	}
+	for _, id := range path[1:] {
+		if u.avoided[id] {
+			return nil, false
+		}
+		if minSecurity > 0 {
+			if sec, ok := u.SystemSecurity[id]; !ok || sec < minSecurity {
+				return nil, false
+			}
+		}
+	}
+	return path, true
+}
@@ -0,0 +1,106 @@
+package graph
+
+import "testing"
+
+func TestWithRoutePreferences_AvoidsSystems(t *testing.T) {
+	u := makeTestUniverse()
+	u.InitPathCache()
+
+	avoid := u.WithRoutePreferences(RoutePreferences{AvoidSystems: []int32{3}})
+	if d := avoid.ShortestPath(1, 4); d != -1 {
+		t.Fatalf("1->4 avoiding 3 = %d, want -1", d)
+	}
+	if d := avoid.ShortestPath(1, 2); d != 1 {
+		t.Fatalf("1->2 = %d, want 1", d)
+	}
+	if d := avoid.ShortestPath(3, 2); d != 1 {
+		t.Fatalf("a route may start in an avoided system: 3->2 = %d", d)
+	}
+	if got := avoid.SystemsWithinRadius(1, 5); len(got) != 2 {
+		t.Fatalf("radius from 1 avoiding 3 = %v, want {1,2}", got)
+	}
+	if d := u.ShortestPath(1, 4); d != 2 {
+		t.Fatalf("base universe was modified: 1->4 = %d", d)
+	}
+	if len(u.Adj[1]) != 2 || len(u.Adj[2]) != 2 {
+		t.Fatalf("base adjacency was modified: %v", u.Adj)
+	}
+}
+
+func TestWithRoutePreferences_PinsOverrideShortestPath(t *testing.T) {
+	u := makeTestUniverse()
+	u.InitPathCache()
+
+	pinned := u.WithRoutePreferences(RoutePreferences{Pins: [][]int32{{1, 2, 3, 4}}})
+	if d := pinned.ShortestPath(1, 4); d != 3 {
+		t.Fatalf("pinned 1->4 = %d, want 3", d)
+	}
+	if path := pinned.GetPath(4, 1, 0); len(path) != 4 || path[0] != 4 || path[1] != 3 || path[2] != 2 {
+		t.Fatalf("reverse pinned path = %v, want [4 3 2 1]", path)
+	}
+	if d := pinned.ShortestPath(1, 3); d != 1 {
+		t.Fatalf("unpinned pair 1->3 = %d, want 1", d)
+	}
+	if d := u.ShortestPath(1, 4); d != 2 {
+		t.Fatalf("base universe was modified: 1->4 = %d", d)
+	}
+
+	withEdges := pinned.WithEdges([]Edge{{From: 2, To: 4}})
+	if d := withEdges.ShortestPath(1, 4); d != 3 {
+		t.Fatalf("pins should survive WithEdges: 1->4 = %d", d)
+	}
+	if u.WithRoutePreferences(RoutePreferences{}) != u {
+		t.Fatal("empty preferences should return the receiver")
+	}
+}
+
+func TestWithRoutePreferences_AvoidedQueriesIgnoreOrder(t *testing.T) {
+	u := makeTestUniverse()
+	u.InitPathCache()
+
+	// Fresh universe: 1->3 first, then 3->1.
+	avoid := u.WithRoutePreferences(RoutePreferences{AvoidSystems: []int32{3}})
+	if d := avoid.ShortestPath(1, 3); d != -1 {
+		t.Fatalf("1->3 into an avoided system = %d, want -1", d)
+	}
+	if d := avoid.ShortestPath(3, 1); d != 1 {
+		t.Fatalf("3->1 out of an avoided system = %d, want 1", d)
+	}
+
+	// Fresh universe: 3->1 first must not answer 1->3 from the cache.
+	u.ForgetRoutePreferences(RoutePreferences{AvoidSystems: []int32{3}})
+	avoid = u.WithRoutePreferences(RoutePreferences{AvoidSystems: []int32{3}})
+	if d := avoid.ShortestPath(3, 1); d != 1 {
+		t.Fatalf("3->1 out of an avoided system = %d, want 1", d)
+	}
+	if d := avoid.ShortestPath(1, 3); d != -1 {
+		t.Fatalf("1->3 after 3->1 = %d, want -1", d)
+	}
+}
+
+func TestWithRoutePreferences_FiltersOverridePins(t *testing.T) {
+	u := makeTestUniverse()
+	u.SystemSecurity = map[int32]float64{1: 0.9, 2: 0.3, 3: 0.9, 4: 0.9}
+	u.InitPathCache()
+
+	// The pin detours through lowsec 2; highsec queries take 1-3-4 instead.
+	pinned := u.WithRoutePreferences(RoutePreferences{Pins: [][]int32{{1, 2, 3, 4}}})
+	if d := pinned.ShortestPath(1, 4); d != 3 {
+		t.Fatalf("pinned 1->4 = %d, want 3", d)
+	}
+	if d := pinned.ShortestPathMinSecurity(1, 4, 0.5); d != 2 {
+		t.Fatalf("highsec 1->4 = %d, want 2 around the lowsec pin", d)
+	}
+	if path := pinned.GetPath(4, 1, 0.5); len(path) != 3 {
+		t.Fatalf("highsec path 4->1 = %v, want [4 3 1]", path)
+	}
+
+	// A pin made before a system on it was avoided is not used either.
+	both := u.WithRoutePreferences(RoutePreferences{AvoidSystems: []int32{2}, Pins: [][]int32{{1, 2, 3, 4}}})
+	if path := both.GetPath(1, 4, 0); len(path) != 3 || path[1] != 3 {
+		t.Fatalf("path 1->4 avoiding 2 = %v, want [1 3 4]", path)
+	}
+	if d := both.ShortestPath(1, 4); d != 2 {
+		t.Fatalf("1->4 avoiding 2 = %d, want 2", d)
+	}
+}
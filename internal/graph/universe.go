@@ -1,5 +1,7 @@
 package graph
 
+import "sync"
+
 // Universe holds the adjacency list of solar systems connected by stargates,
 // plus mappings from system to region/constellation and security.
 type Universe struct {
@@ -9,9 +11,24 @@ type Universe struct {
 	SystemRegion map[int32]int32
 	// SystemSecurity maps systemID -> security (0.0 null to 1.0 highsec); highsec >= 0.45
 	SystemSecurity map[int32]float64
+	// SystemPosition maps systemID -> galactic x/y/z in meters (for jump-drive range)
+	SystemPosition map[int32][3]float64
+	// pins maps an (origin, dest) pair to a user-pinned path (see
+	// WithRoutePreferences).
+	pins map[pinKey][]int32
+	// avoided are systems routes may leave but not enter (see
+	// WithRoutePreferences), which makes Adj directed.
+	avoided map[int32]bool
+	// routes are jump tables from PrecomputeRoutes, nil until built.
+	routes *routeTables
 	// pathCacheMu is an LRU cache for ShortestPath results.
 	// Initialized lazily via InitPathCache().
 	pathCacheMu *pathCache
+	// prefCache holds WithRoutePreferences copies by preferences, oldest
+	// first in prefOrder.
+	prefMu    sync.Mutex
+	prefCache map[string]*preferredUniverse
+	prefOrder []string
 }
 
 // NewUniverse creates an empty Universe with initialized maps.
@@ -20,6 +37,7 @@ func NewUniverse() *Universe {
 		Adj:            make(map[int32][]int32),
 		SystemRegion:   make(map[int32]int32),
 		SystemSecurity: make(map[int32]float64),
+		SystemPosition: make(map[int32][3]float64),
 	}
 }
 
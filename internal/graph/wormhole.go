@@ -0,0 +1,40 @@
+package graph
+
+import "time"
+
+// Wormhole hubs with public connection maps (EVE-Scout).
+const (
+	TheraSystemID  int32 = 31000005
+	TurnurSystemID int32 = 30002086
+)
+
+// WormholeConnection is a temporary two-way link between a wormhole hub and a
+// k-space system.
+type WormholeConnection struct {
+	HubSystemID     int32     `json:"hub_system_id"`
+	SystemID        int32     `json:"system_id"`
+	SystemName      string    `json:"system_name,omitempty"`
+	MaxShipSize     string    `json:"max_ship_size,omitempty"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	HubSignature    string    `json:"hub_signature,omitempty"`
+	SystemSignature string    `json:"system_signature,omitempty"`
+}
+
+// Expired reports whether the connection has collapsed at now. A zero
+// ExpiresAt never expires.
+func (c WormholeConnection) Expired(now time.Time) bool {
+	return !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt)
+}
+
+// WithWormholes returns a copy of the universe with the unexpired wormhole
+// connections added as one-jump edges (see WithEdges).
+func (u *Universe) WithWormholes(conns []WormholeConnection, now time.Time) *Universe {
+	var edges []Edge
+	for _, c := range conns {
+		if c.Expired(now) {
+			continue
+		}
+		edges = append(edges, Edge{From: c.HubSystemID, To: c.SystemID})
+	}
+	return u.WithEdges(edges)
+}
@@ -0,0 +1,44 @@
+package graph
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithWormholes_AddsUnexpiredEdges(t *testing.T) {
+	u := makeTestUniverse()
+	u.Adj[TheraSystemID] = nil
+	u.InitPathCache()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	wh := u.WithWormholes([]WormholeConnection{
+		{HubSystemID: TheraSystemID, SystemID: 1, ExpiresAt: now.Add(time.Hour)},
+		{HubSystemID: TheraSystemID, SystemID: 4, ExpiresAt: now.Add(time.Hour)},
+		{HubSystemID: TheraSystemID, SystemID: 2, ExpiresAt: now.Add(-time.Minute)},
+	}, now)
+
+	if d := wh.ShortestPath(1, 4); d != 2 {
+		t.Fatalf("1->4 via Thera = %d, want 2", d)
+	}
+	if d := wh.ShortestPath(2, TheraSystemID); d != 2 {
+		t.Fatalf("expired 2<->Thera should not be used, got %d", d)
+	}
+	if d := u.ShortestPath(1, TheraSystemID); d != -1 {
+		t.Fatalf("base universe was modified: 1->Thera = %d", d)
+	}
+	if len(u.Adj[1]) != 2 {
+		t.Fatalf("base adjacency was modified: %v", u.Adj[1])
+	}
+	if path := wh.GetPath(TheraSystemID, 4, 0); len(path) != 2 || path[1] != 4 {
+		t.Fatalf("GetPath(Thera,4) = %v, want direct", path)
+	}
+}
+
+func TestWithWormholes_NoConnectionsReturnsReceiver(t *testing.T) {
+	u := makeTestUniverse()
+	now := time.Now()
+	expired := []WormholeConnection{{HubSystemID: TurnurSystemID, SystemID: 1, ExpiresAt: now.Add(-time.Hour)}}
+	if got := u.WithWormholes(expired, now); got != u {
+		t.Fatal("expected the receiver when no connection applies")
+	}
+}
@@ -0,0 +1,275 @@
+// Package metrics keeps process-wide counters and histograms and renders
+// them in the Prometheus text exposition format for GET /metrics.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics exposed by the server. Label values must stay low-cardinality:
+// ESI routes are normalized, scan modes and cache names are fixed strings.
+var (
+	ESIRequests = NewCounterVec("eveflipper_esi_requests_total",
+		"ESI requests by normalized route and HTTP status (\"error\" for transport errors).", "route", "status")
+	ESIRequestDuration = NewHistogramVec("eveflipper_esi_request_duration_seconds",
+		"ESI request latency by normalized route.", []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}, "route")
+	ScanDuration = NewHistogramVec("eveflipper_scan_duration_seconds",
+		"Scan run time by mode.", []float64{1, 2, 5, 10, 30, 60, 120, 300, 600}, "mode")
+	CacheLookups = NewCounterVec("eveflipper_cache_lookups_total",
+		"Cache lookups by cache and result (hit or miss).", "cache", "result")
+	DBQueryDuration = NewHistogramVec("eveflipper_db_query_duration_seconds",
+		"SQLite query time by operation.", []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5}, "query")
+)
+
+// ObserveCache counts one lookup of the named cache.
+func ObserveCache(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	CacheLookups.Inc(cache, result)
+}
+
+// ObserveDBQuery records the time since start for a DB operation; use as
+// defer metrics.ObserveDBQuery("op", time.Now()).
+func ObserveDBQuery(query string, start time.Time) {
+	DBQueryDuration.Observe(time.Since(start).Seconds(), query)
+}
+
+type collector interface {
+	write(w io.Writer)
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []collector
+)
+
+func register(c collector) {
+	registryMu.Lock()
+	registry = append(registry, c)
+	registryMu.Unlock()
+}
+
+// WritePrometheus writes every registered metric in the text format.
+func WritePrometheus(w io.Writer) {
+	registryMu.Lock()
+	collectors := append([]collector(nil), registry...)
+	registryMu.Unlock()
+	for _, c := range collectors {
+		c.write(w)
+	}
+}
+
+// labelKey joins label values into a map key.
+func labelKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+func formatLabels(names []string, key string, extra ...string) string {
+	pairs := make([]string, 0, len(names)+len(extra)/2)
+	if len(names) > 0 {
+		values := strings.Split(key, "\xff")
+		for i, name := range names {
+			v := ""
+			if i < len(values) {
+				v = values[i]
+			}
+			pairs = append(pairs, name+`="`+escapeLabelValue(v)+`"`)
+		}
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+escapeLabelValue(extra[i+1])+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func checkLabels(name string, labels []string, values []string) {
+	if len(values) != len(labels) {
+		panic(fmt.Sprintf("metrics: %s wants %d label values, got %d", name, len(labels), len(values)))
+	}
+}
+
+// Counter is one counter series, safe for concurrent use without locking
+// the vector; hot paths keep the *Counter from CounterVec.With.
+type Counter struct {
+	bits atomic.Uint64 // float64 bits
+}
+
+// Inc adds one.
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add adds v.
+func (c *Counter) Add(v float64) {
+	for {
+		old := c.bits.Load()
+		if c.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+// Value returns the current count.
+func (c *Counter) Value() float64 {
+	return math.Float64frombits(c.bits.Load())
+}
+
+// CounterVec is a counter partitioned by label values.
+type CounterVec struct {
+	name, help string
+	labels     []string
+	mu         sync.Mutex
+	values     map[string]*Counter
+}
+
+// NewCounterVec creates and registers a counter.
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{name: name, help: help, labels: labels, values: make(map[string]*Counter)}
+	register(c)
+	return c
+}
+
+// With returns the series with the given label values.
+func (c *CounterVec) With(values ...string) *Counter {
+	checkLabels(c.name, c.labels, values)
+	key := labelKey(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counter := c.values[key]
+	if counter == nil {
+		counter = &Counter{}
+		c.values[key] = counter
+	}
+	return counter
+}
+
+// Inc adds one to the series with the given label values.
+func (c *CounterVec) Inc(values ...string) {
+	c.With(values...).Inc()
+}
+
+// Add adds v to the series with the given label values.
+func (c *CounterVec) Add(v float64, values ...string) {
+	c.With(values...).Add(v)
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.Lock()
+	keys := make([]string, 0, len(c.values))
+	for k := range c.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labels, k), formatValue(c.values[k].Value()))
+	}
+	c.mu.Unlock()
+}
+
+type histogramSeries struct {
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// HistogramVec is a histogram partitioned by label values.
+type HistogramVec struct {
+	name, help string
+	labels     []string
+	buckets    []float64 // upper bounds, ascending
+	mu         sync.Mutex
+	series     map[string]*histogramSeries
+}
+
+// NewHistogramVec creates and registers a histogram with the given bucket
+// upper bounds.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	b := append([]float64(nil), buckets...)
+	sort.Float64s(b)
+	h := &HistogramVec{name: name, help: help, labels: labels, buckets: b, series: make(map[string]*histogramSeries)}
+	register(h)
+	return h
+}
+
+// Observe records one value in the series with the given label values.
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	checkLabels(h.name, h.labels, values)
+	key := labelKey(values)
+	i := sort.SearchFloat64s(h.buckets, v) // first bucket with bound >= v
+	h.mu.Lock()
+	s := h.series[key]
+	if s == nil {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	if i < len(s.counts) {
+		s.counts[i]++
+	}
+	s.sum += v
+	s.count++
+	h.mu.Unlock()
+}
+
+// Count returns how many values the series with the given label values has.
+func (h *HistogramVec) Count(values ...string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s := h.series[labelKey(values)]; s != nil {
+		return s.count
+	}
+	return 0
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.mu.Lock()
+	keys := make([]string, 0, len(h.series))
+	for k := range h.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	for _, k := range keys {
+		s := h.series[k]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, k, "le", formatValue(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, k, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labels, k), formatValue(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labels, k), s.count)
+	}
+	h.mu.Unlock()
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCounterVec_Write(t *testing.T) {
+	c := &CounterVec{name: "test_total", help: "Test.", labels: []string{"route"}, values: map[string]*Counter{}}
+	c.Inc(`/markets/{id}/orders/`)
+	c.Add(2, `/markets/{id}/orders/`)
+	c.Inc(`say "hi"`)
+
+	var buf bytes.Buffer
+	c.write(&buf)
+	want := `# HELP test_total Test.
+# TYPE test_total counter
+test_total{route="/markets/{id}/orders/"} 3
+test_total{route="say \"hi\""} 1
+`
+	if buf.String() != want {
+		t.Fatalf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestHistogramVec_CumulativeBuckets(t *testing.T) {
+	h := &HistogramVec{name: "test_seconds", help: "Test.", labels: []string{"mode"}, buckets: []float64{1, 5}, series: map[string]*histogramSeries{}}
+	for _, v := range []float64{0.5, 1, 3, 10} {
+		h.Observe(v, "radius")
+	}
+	if got := h.Count("radius"); got != 4 {
+		t.Fatalf("count = %d, want 4", got)
+	}
+
+	var buf bytes.Buffer
+	h.write(&buf)
+	for _, line := range []string{
+		`test_seconds_bucket{mode="radius",le="1"} 2`,
+		`test_seconds_bucket{mode="radius",le="5"} 3`,
+		`test_seconds_bucket{mode="radius",le="+Inf"} 4`,
+		`test_seconds_sum{mode="radius"} 14.5`,
+		`test_seconds_count{mode="radius"} 4`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("missing %q in\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestCounterVec_PanicsOnLabelMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	c := &CounterVec{name: "test_total", labels: []string{"a", "b"}, values: map[string]*Counter{}}
+	c.Inc("only-one")
+}
@@ -0,0 +1,84 @@
+package pricing
+
+import (
+	"log"
+	"sync"
+
+	"eve-flipper/internal/esi"
+)
+
+// ESIOrderBook quotes from live ESI region order books, one type at a time.
+// Accurate to the current book but costs a request per type.
+type ESIOrderBook struct {
+	Client      *esi.Client
+	Concurrency int // parallel type lookups; default 6
+}
+
+func (s *ESIOrderBook) Name() string { return SourceESI }
+
+func (s *ESIOrderBook) Quotes(hub Hub, typeIDs []int32) (map[int32]Quote, error) {
+	out := make(map[int32]Quote, len(typeIDs))
+	if len(typeIDs) == 0 {
+		return out, nil
+	}
+	workers := s.Concurrency
+	if workers <= 0 {
+		workers = 6
+	}
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		failed   int
+	)
+	sem := make(chan struct{}, workers)
+	for _, typeID := range typeIDs {
+		wg.Add(1)
+		go func(typeID int32) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			orders, err := s.Client.FetchRegionOrdersByType(hub.RegionID, typeID)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			if q := bestQuote(orders, hub); q != (Quote{}) {
+				out[typeID] = q
+			}
+		}(typeID)
+	}
+	wg.Wait()
+
+	if failed == len(typeIDs) && firstErr != nil {
+		return nil, firstErr
+	}
+	if failed > 0 {
+		log.Printf("[PRICING] ESI: %d/%d type lookups failed: %v", failed, len(typeIDs), firstErr)
+	}
+	return out, nil
+}
+
+// bestQuote returns the highest buy and lowest sell among orders at hub.
+func bestQuote(orders []esi.MarketOrder, hub Hub) Quote {
+	var q Quote
+	for _, o := range orders {
+		if hub.StationID != 0 && o.LocationID != hub.StationID {
+			continue
+		}
+		if o.IsBuyOrder {
+			if o.Price > q.Buy {
+				q.Buy = o.Price
+			}
+		} else if q.Sell == 0 || o.Price < q.Sell {
+			q.Sell = o.Price
+		}
+	}
+	return q
+}
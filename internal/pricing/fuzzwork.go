@@ -0,0 +1,125 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	fuzzworkBaseURL = "https://market.fuzzwork.co.uk"
+	// fuzzworkBatchSize keeps the types= query string at a sane length.
+	fuzzworkBatchSize = 200
+)
+
+// Fuzzwork quotes from the Fuzzwork market aggregates endpoint, which
+// summarizes whole books for many types in a single request. Aggregates lag
+// the live book by a few minutes.
+type Fuzzwork struct {
+	HTTP    *http.Client
+	BaseURL string
+}
+
+// NewFuzzwork creates a Fuzzwork source with default settings.
+func NewFuzzwork() *Fuzzwork {
+	return &Fuzzwork{
+		HTTP:    &http.Client{Timeout: 30 * time.Second},
+		BaseURL: fuzzworkBaseURL,
+	}
+}
+
+func (f *Fuzzwork) Name() string { return SourceFuzzwork }
+
+// fuzzworkNumber accepts both JSON numbers and numeric strings; the endpoint
+// returns strings.
+type fuzzworkNumber float64
+
+func (n *fuzzworkNumber) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*n = fuzzworkNumber(v)
+	return nil
+}
+
+type fuzzworkSide struct {
+	Max        fuzzworkNumber `json:"max"`
+	Min        fuzzworkNumber `json:"min"`
+	OrderCount fuzzworkNumber `json:"orderCount"`
+}
+
+type fuzzworkAggregate struct {
+	Buy  fuzzworkSide `json:"buy"`
+	Sell fuzzworkSide `json:"sell"`
+}
+
+func (f *Fuzzwork) Quotes(hub Hub, typeIDs []int32) (map[int32]Quote, error) {
+	out := make(map[int32]Quote, len(typeIDs))
+	for start := 0; start < len(typeIDs); start += fuzzworkBatchSize {
+		batch := typeIDs[start:min(start+fuzzworkBatchSize, len(typeIDs))]
+		aggs, err := f.fetch(hub, batch)
+		if err != nil {
+			return nil, err
+		}
+		for key, agg := range aggs {
+			id, err := strconv.ParseInt(key, 10, 32)
+			if err != nil {
+				continue
+			}
+			var q Quote
+			if agg.Buy.OrderCount > 0 {
+				q.Buy = float64(agg.Buy.Max)
+			}
+			if agg.Sell.OrderCount > 0 {
+				q.Sell = float64(agg.Sell.Min)
+			}
+			if q != (Quote{}) {
+				out[int32(id)] = q
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *Fuzzwork) fetch(hub Hub, typeIDs []int32) (map[string]fuzzworkAggregate, error) {
+	ids := make([]string, len(typeIDs))
+	for i, id := range typeIDs {
+		ids[i] = strconv.Itoa(int(id))
+	}
+	scope := fmt.Sprintf("region=%d", hub.RegionID)
+	if hub.StationID != 0 {
+		scope = fmt.Sprintf("station=%d", hub.StationID)
+	}
+	url := fmt.Sprintf("%s/aggregates/?%s&types=%s", strings.TrimRight(f.BaseURL, "/"), scope, strings.Join(ids, ","))
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "eve-flipper/1.0 (github.com)")
+	req.Header.Set("Accept", "application/json")
+	resp, err := f.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fuzzwork: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("fuzzwork %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var aggs map[string]fuzzworkAggregate
+	if err := json.NewDecoder(resp.Body).Decode(&aggs); err != nil {
+		return nil, fmt.Errorf("fuzzwork: decode: %w", err)
+	}
+	return aggs, nil
+}
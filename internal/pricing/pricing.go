@@ -0,0 +1,146 @@
+// Package pricing provides interchangeable price sources: live ESI order
+// books, third-party aggregate endpoints and static or cached snapshots.
+// Features pick a source by name; aggregates are far cheaper than full book
+// fetches for appraisal-style lookups.
+package pricing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Source names accepted by Registry.Get.
+const (
+	SourceESI      = "esi"
+	SourceFuzzwork = "fuzzwork"
+)
+
+// Hub is the market a quote is taken from. StationID 0 means region-wide.
+type Hub struct {
+	RegionID  int32
+	StationID int64
+}
+
+// Quote is the top of book for one type at a hub. Zero means no orders.
+type Quote struct {
+	Buy  float64 `json:"buy"`  // highest buy order
+	Sell float64 `json:"sell"` // lowest sell order
+}
+
+// PriceSource returns quotes for a set of types at a hub. Types without any
+// orders may be absent from the result.
+type PriceSource interface {
+	Name() string
+	Quotes(hub Hub, typeIDs []int32) (map[int32]Quote, error)
+}
+
+// Registry maps source names to implementations.
+type Registry map[string]PriceSource
+
+// Get returns the source for name, falling back to def when name is empty.
+func (r Registry) Get(name, def string) (PriceSource, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = def
+	}
+	if src, ok := r[name]; ok && src != nil {
+		return src, nil
+	}
+	names := make([]string, 0, len(r))
+	for n := range r {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("unknown price source %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// Static serves fixed quotes, e.g. demo data or an imported snapshot. The hub
+// is ignored.
+type Static struct {
+	Label  string
+	Prices map[int32]Quote
+}
+
+func (s Static) Name() string { return s.Label }
+
+func (s Static) Quotes(_ Hub, typeIDs []int32) (map[int32]Quote, error) {
+	out := make(map[int32]Quote, len(typeIDs))
+	for _, id := range typeIDs {
+		if q, ok := s.Prices[id]; ok {
+			out[id] = q
+		}
+	}
+	return out, nil
+}
+
+type cachedQuote struct {
+	quote   Quote
+	fetched time.Time
+}
+
+type cacheKey struct {
+	hub    Hub
+	typeID int32
+}
+
+// Cached keeps quotes from another source for TTL, so repeated lookups of the
+// same types only fetch what is missing or stale.
+type Cached struct {
+	Source PriceSource
+	TTL    time.Duration
+
+	mu      sync.Mutex
+	entries map[cacheKey]cachedQuote
+	now     func() time.Time
+}
+
+// NewCached wraps src with a TTL cache.
+func NewCached(src PriceSource, ttl time.Duration) *Cached {
+	return &Cached{Source: src, TTL: ttl, entries: make(map[cacheKey]cachedQuote), now: time.Now}
+}
+
+func (c *Cached) Name() string { return c.Source.Name() }
+
+func (c *Cached) Quotes(hub Hub, typeIDs []int32) (map[int32]Quote, error) {
+	out := make(map[int32]Quote, len(typeIDs))
+	var missing []int32
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[cacheKey]cachedQuote)
+	}
+	if c.now == nil {
+		c.now = time.Now
+	}
+	now := c.now()
+	for _, id := range typeIDs {
+		if e, ok := c.entries[cacheKey{hub, id}]; ok && now.Sub(e.fetched) < c.TTL {
+			if e.quote != (Quote{}) {
+				out[id] = e.quote
+			}
+			continue
+		}
+		missing = append(missing, id)
+	}
+	c.mu.Unlock()
+	if len(missing) == 0 {
+		return out, nil
+	}
+
+	fetched, err := c.Source.Quotes(hub, missing)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	for _, id := range missing {
+		q := fetched[id] // absent types are cached as "no orders"
+		c.entries[cacheKey{hub, id}] = cachedQuote{quote: q, fetched: now}
+		if q != (Quote{}) {
+			out[id] = q
+		}
+	}
+	c.mu.Unlock()
+	return out, nil
+}
@@ -0,0 +1,101 @@
+package pricing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"eve-flipper/internal/esi"
+)
+
+func TestFuzzworkQuotes_ParsesStringAggregates(t *testing.T) {
+	var gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		_, _ = w.Write([]byte(`{
+			"34": {"buy": {"max": "5.01", "min": "0.01", "orderCount": "12"}, "sell": {"max": "9", "min": "5.2", "orderCount": "30"}},
+			"35": {"buy": {"max": "0", "min": "0", "orderCount": "0"}, "sell": {"max": 12.5, "min": 11, "orderCount": 3}},
+			"36": {"buy": {"max": "0", "min": "0", "orderCount": "0"}, "sell": {"max": "0", "min": "0", "orderCount": "0"}}
+		}`))
+	}))
+	defer srv.Close()
+
+	f := &Fuzzwork{HTTP: srv.Client(), BaseURL: srv.URL}
+	quotes, err := f.Quotes(Hub{RegionID: 10000002, StationID: 60003760}, []int32{34, 35, 36})
+	if err != nil {
+		t.Fatalf("Quotes: %v", err)
+	}
+	if gotQuery != "station=60003760&types=34,35,36" {
+		t.Errorf("query = %q", gotQuery)
+	}
+	if quotes[34] != (Quote{Buy: 5.01, Sell: 5.2}) {
+		t.Errorf("quote 34 = %+v", quotes[34])
+	}
+	if quotes[35] != (Quote{Sell: 11}) {
+		t.Errorf("quote 35 = %+v", quotes[35])
+	}
+	if _, ok := quotes[36]; ok {
+		t.Errorf("type without orders should be absent: %+v", quotes[36])
+	}
+}
+
+type countingSource struct {
+	calls  int
+	prices map[int32]Quote
+}
+
+func (c *countingSource) Name() string { return "counting" }
+
+func (c *countingSource) Quotes(hub Hub, typeIDs []int32) (map[int32]Quote, error) {
+	c.calls++
+	return Static{Prices: c.prices}.Quotes(hub, typeIDs)
+}
+
+func TestCached_FetchesOnlyMissingOrStale(t *testing.T) {
+	src := &countingSource{prices: map[int32]Quote{34: {Buy: 5}}}
+	c := NewCached(src, time.Minute)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	hub := Hub{RegionID: 10000002}
+
+	if q, _ := c.Quotes(hub, []int32{34, 35}); q[34].Buy != 5 || len(q) != 1 {
+		t.Fatalf("first Quotes = %+v", q)
+	}
+	if _, _ = c.Quotes(hub, []int32{34, 35}); src.calls != 1 {
+		t.Fatalf("calls = %d, want cached second lookup", src.calls)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, _ = c.Quotes(hub, []int32{34}); src.calls != 2 {
+		t.Fatalf("calls = %d, want refetch after TTL", src.calls)
+	}
+}
+
+func TestRegistryGet(t *testing.T) {
+	r := Registry{SourceESI: Static{Label: SourceESI}, SourceFuzzwork: Static{Label: SourceFuzzwork}}
+	if src, err := r.Get("", SourceESI); err != nil || src.Name() != SourceESI {
+		t.Fatalf("default = %v, %v", src, err)
+	}
+	if src, err := r.Get(" Fuzzwork ", SourceESI); err != nil || src.Name() != SourceFuzzwork {
+		t.Fatalf("fuzzwork = %v, %v", src, err)
+	}
+	if _, err := r.Get("evemarketer", SourceESI); err == nil {
+		t.Fatal("unknown source accepted")
+	}
+}
+
+func TestBestQuote_FiltersStation(t *testing.T) {
+	orders := []esi.MarketOrder{
+		{LocationID: 60003760, IsBuyOrder: true, Price: 4},
+		{LocationID: 60003760, IsBuyOrder: true, Price: 4.5},
+		{LocationID: 60008494, IsBuyOrder: true, Price: 9},
+		{LocationID: 60003760, Price: 6},
+		{LocationID: 60003760, Price: 5.5},
+	}
+	if q := bestQuote(orders, Hub{RegionID: 10000002, StationID: 60003760}); q != (Quote{Buy: 4.5, Sell: 5.5}) {
+		t.Errorf("station quote = %+v", q)
+	}
+	if q := bestQuote(orders, Hub{RegionID: 10000002}); q.Buy != 9 {
+		t.Errorf("region quote = %+v", q)
+	}
+}
@@ -0,0 +1,654 @@
+package sde
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"eve-flipper/internal/logger"
+)
+
+// fuzzworkURL serves Fuzzwork's per-table CSV conversions of the SDE.
+const fuzzworkURL = "https://www.fuzzwork.co.uk/dump/latest/"
+
+// fuzzworkTables are the Fuzzwork tables the loader needs, so only these
+// are downloaded rather than the whole dump. The required ones back
+// requiredSDEJSONLFiles; a local copy may leave out the others.
+var fuzzworkTables = []struct {
+	name     string
+	required bool
+}{
+	{"mapRegions", true},
+	{"mapSolarSystems", true},
+	{"mapSolarSystemJumps", true},
+	{"invGroups", true},
+	{"invTypes", true},
+	{"staStations", true},
+	{"invVolumes", false},
+	{"invMarketGroups", false},
+	{"invContrabandTypes", false},
+	{"crpNPCCorporations", false},
+	{"industryActivity", false},
+	{"industryActivityMaterials", false},
+	{"industryActivityProducts", false},
+	{"industryActivityProbabilities", false},
+	{"industryActivitySkills", false},
+	{"invTypeMaterials", false},
+	{"planetSchematics", false},
+	{"planetSchematicsTypeMap", false},
+	{"planetSchematicsPinMap", false},
+}
+
+// fuzzworkActivities maps industry activity IDs to the activity names of
+// the JSONL blueprints file.
+var fuzzworkActivities = map[int64]string{
+	1:  "manufacturing",
+	8:  "invention",
+	11: "reaction",
+}
+
+// ensureFuzzworkConverted converts the Fuzzwork tables of src into JSONL in
+// extractDir unless a valid conversion exists.
+func ensureFuzzworkConverted(src Source, extractDir string, p *progressReporter) error {
+	if err := validateSDEExtractDir(extractDir); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		logger.Warn("SDE", fmt.Sprintf("Existing Fuzzwork conversion is incomplete: %v", err))
+	}
+	tempDir, _, err := buildFuzzworkTemp(src, extractDir, p)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	if _, err := os.Stat(extractDir); err == nil {
+		if err := os.RemoveAll(extractDir); err != nil {
+			return fmt.Errorf("remove incomplete Fuzzwork conversion: %w", err)
+		}
+	}
+	if err := os.Rename(tempDir, extractDir); err != nil {
+		return fmt.Errorf("activate Fuzzwork conversion: %w", err)
+	}
+	return nil
+}
+
+// updateFuzzwork re-converts src and replaces the JSONL files of extractDir
+// whose contents changed. Fuzzwork publishes no checksums, so every table
+// is downloaded again; they are a fraction of the JSONL export.
+func updateFuzzwork(src Source, extractDir string, res *UpdateResult, p *progressReporter) error {
+	if err := validateSDEExtractDir(extractDir); err != nil {
+		return ensureFuzzworkConverted(src, extractDir, p)
+	}
+	tempDir, downloaded, err := buildFuzzworkTemp(src, extractDir, p)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+	res.BytesDownloaded = downloaded
+
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+			continue
+		}
+		fresh := filepath.Join(tempDir, e.Name())
+		dst := filepath.Join(extractDir, e.Name())
+		newCRC, err := fileCRC32(fresh)
+		if err != nil {
+			return err
+		}
+		if oldCRC, err := fileCRC32(dst); err == nil && oldCRC == newCRC {
+			res.Unchanged++
+			continue
+		}
+		if err := os.Rename(fresh, dst); err != nil {
+			return fmt.Errorf("update %s: %w", e.Name(), err)
+		}
+		res.Changed = append(res.Changed, e.Name())
+	}
+	logger.Info("SDE", fmt.Sprintf("SDE update: %d file(s) changed, %d unchanged", len(res.Changed), res.Unchanged))
+	return nil
+}
+
+// buildFuzzworkTemp converts src into a new temp directory next to
+// extractDir and validates it. It returns the directory and the bytes
+// downloaded; the caller removes the directory.
+func buildFuzzworkTemp(src Source, extractDir string, p *progressReporter) (string, int64, error) {
+	parent := filepath.Dir(extractDir)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return "", 0, err
+	}
+	tempDir, err := os.MkdirTemp(parent, ".sde-fuzzwork-*")
+	if err != nil {
+		return "", 0, err
+	}
+	csvDir := src.Path
+	var downloaded int64
+	if src.Path == "" {
+		csvDir = filepath.Join(tempDir, "csv")
+		logger.Info("SDE", "Downloading Fuzzwork tables...")
+		p.stage("download")
+		downloaded, err = downloadFuzzworkTables(src.URL, csvDir, p)
+		if err != nil {
+			os.RemoveAll(tempDir)
+			return "", 0, err
+		}
+	}
+
+	logger.Info("SDE", "Converting Fuzzwork tables...")
+	p.stage("extract")
+	err = convertFuzzwork(csvDir, tempDir)
+	if err == nil {
+		err = validateSDEExtractDir(tempDir)
+	}
+	if src.Path == "" {
+		os.RemoveAll(csvDir)
+	}
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return "", 0, fmt.Errorf("convert Fuzzwork SDE: %w", err)
+	}
+	return tempDir, downloaded, nil
+}
+
+// downloadFuzzworkTables downloads the bzip2 CSV of every fuzzworkTables
+// entry from baseURL into dir.
+func downloadFuzzworkTables(baseURL, dir string, p *progressReporter) (int64, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/") + "/"
+	var total int64
+	for i, table := range fuzzworkTables {
+		dst := filepath.Join(dir, table.name+".csv.bz2")
+		if err := downloadFile(dst, baseURL+table.name+".csv.bz2", nil); err != nil {
+			return total, fmt.Errorf("download %s: %w", table.name, err)
+		}
+		if info, err := os.Stat(dst); err == nil {
+			total += info.Size()
+		}
+		p.bytes("download", int64(i+1), int64(len(fuzzworkTables)))
+	}
+	return total, nil
+}
+
+// fuzzworkRow is one CSV row, read by column name.
+type fuzzworkRow struct {
+	cols   map[string]int
+	values []string
+}
+
+func (r fuzzworkRow) str(col string) string {
+	i, ok := r.cols[col]
+	if !ok || i >= len(r.values) {
+		return ""
+	}
+	v := strings.TrimSpace(r.values[i])
+	if v == "None" {
+		return ""
+	}
+	return v
+}
+
+func (r fuzzworkRow) int(col string) int64 {
+	v, _ := strconv.ParseInt(r.str(col), 10, 64)
+	return v
+}
+
+func (r fuzzworkRow) float(col string) float64 {
+	v, _ := strconv.ParseFloat(r.str(col), 64)
+	return v
+}
+
+func (r fuzzworkRow) bool(col string) bool {
+	v, _ := strconv.ParseBool(r.str(col))
+	return v
+}
+
+// readFuzzworkCSV calls fn for each row of table in dir, read from
+// table.csv or table.csv.bz2. A missing table is an error only when
+// required.
+func readFuzzworkCSV(dir, table string, required bool, fn func(fuzzworkRow)) error {
+	var in io.Reader
+	f, err := os.Open(filepath.Join(dir, table+".csv"))
+	if os.IsNotExist(err) {
+		f, err = os.Open(filepath.Join(dir, table+".csv.bz2"))
+		if err == nil {
+			in = bzip2.NewReader(bufio.NewReader(f))
+		}
+	} else if err == nil {
+		in = bufio.NewReader(f)
+	}
+	if os.IsNotExist(err) {
+		if required {
+			return fmt.Errorf("required Fuzzwork table %s is missing", table)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(in)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	header, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("read %s header: %w", table, err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		cols[strings.TrimPrefix(strings.TrimSpace(name), "\ufeff")] = i
+	}
+	for {
+		values, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read %s: %w", table, err)
+		}
+		fn(fuzzworkRow{cols: cols, values: values})
+	}
+}
+
+// jsonlWriter writes one JSON value per line.
+type jsonlWriter struct {
+	f   *os.File
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func createJSONL(dir, baseName string) (*jsonlWriter, error) {
+	f, err := os.Create(filepath.Join(dir, baseName+".jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	w := bufio.NewWriter(f)
+	return &jsonlWriter{f: f, w: w, enc: json.NewEncoder(w)}, nil
+}
+
+func (w *jsonlWriter) write(v any) error {
+	return w.enc.Encode(v)
+}
+
+func (w *jsonlWriter) Close() error {
+	err := w.w.Flush()
+	if closeErr := w.f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// convertFuzzworkTable writes baseName.jsonl in outDir from one Fuzzwork
+// table, with convert turning each row into a record (nil skips it).
+func convertFuzzworkTable(csvDir, outDir, table string, required bool, baseName string, convert func(fuzzworkRow) any) error {
+	w, err := createJSONL(outDir, baseName)
+	if err != nil {
+		return err
+	}
+	var writeErr error
+	err = readFuzzworkCSV(csvDir, table, required, func(row fuzzworkRow) {
+		if writeErr != nil {
+			return
+		}
+		if rec := convert(row); rec != nil {
+			writeErr = w.write(rec)
+		}
+	})
+	if err == nil {
+		err = writeErr
+	}
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+type jsonlName struct {
+	EN string `json:"en"`
+}
+
+type jsonlMaterial struct {
+	TypeID      int32   `json:"typeID"`
+	Quantity    int32   `json:"quantity"`
+	Probability float64 `json:"probability,omitempty"`
+}
+
+type jsonlSkill struct {
+	TypeID int32 `json:"typeID"`
+	Level  int32 `json:"level"`
+}
+
+type jsonlActivity struct {
+	Time      int32           `json:"time"`
+	Materials []jsonlMaterial `json:"materials,omitempty"`
+	Products  []jsonlMaterial `json:"products,omitempty"`
+	Skills    []jsonlSkill    `json:"skills,omitempty"`
+}
+
+// convertFuzzwork writes the JSONL files the loader reads into outDir from
+// the Fuzzwork CSV tables in csvDir, in the shape of CCP's export.
+func convertFuzzwork(csvDir, outDir string) error {
+	start := time.Now()
+	err := convertFuzzworkTable(csvDir, outDir, "mapRegions", true, "mapRegions", func(r fuzzworkRow) any {
+		return struct {
+			Key  int64     `json:"_key"`
+			Name jsonlName `json:"name"`
+		}{r.int("regionID"), jsonlName{r.str("regionName")}}
+	})
+	if err != nil {
+		return err
+	}
+	err = convertFuzzworkTable(csvDir, outDir, "mapSolarSystems", true, "mapSolarSystems", func(r fuzzworkRow) any {
+		type position struct {
+			X float64 `json:"x"`
+			Y float64 `json:"y"`
+			Z float64 `json:"z"`
+		}
+		return struct {
+			Key            int64     `json:"_key"`
+			Name           jsonlName `json:"name"`
+			RegionID       int64     `json:"regionID"`
+			SecurityStatus float64   `json:"securityStatus"`
+			Position       position  `json:"position"`
+		}{
+			r.int("solarSystemID"), jsonlName{r.str("solarSystemName")}, r.int("regionID"), r.float("security"),
+			position{r.float("x"), r.float("y"), r.float("z")},
+		}
+	})
+	if err != nil {
+		return err
+	}
+	err = convertFuzzworkTable(csvDir, outDir, "mapSolarSystemJumps", true, "mapStargates", func(r fuzzworkRow) any {
+		type destination struct {
+			SolarSystemID int64 `json:"solarSystemID"`
+		}
+		return struct {
+			SolarSystemID int64       `json:"solarSystemID"`
+			Destination   destination `json:"destination"`
+		}{r.int("fromSolarSystemID"), destination{r.int("toSolarSystemID")}}
+	})
+	if err != nil {
+		return err
+	}
+	err = convertFuzzworkTable(csvDir, outDir, "invGroups", true, "groups", func(r fuzzworkRow) any {
+		return struct {
+			Key        int64     `json:"_key"`
+			Name       jsonlName `json:"name"`
+			CategoryID int64     `json:"categoryID"`
+		}{r.int("groupID"), jsonlName{r.str("groupName")}, r.int("categoryID")}
+	})
+	if err != nil {
+		return err
+	}
+	err = convertFuzzworkTable(csvDir, outDir, "invMarketGroups", false, "marketGroups", func(r fuzzworkRow) any {
+		return struct {
+			Key           int64     `json:"_key"`
+			Name          jsonlName `json:"name"`
+			ParentGroupID int64     `json:"parentGroupID,omitempty"`
+			HasTypes      bool      `json:"hasTypes"`
+		}{r.int("marketGroupID"), jsonlName{r.str("marketGroupName")}, r.int("parentGroupID"), r.bool("hasTypes")}
+	})
+	if err != nil {
+		return err
+	}
+
+	packaged := make(map[int64]float64)
+	err = readFuzzworkCSV(csvDir, "invVolumes", false, func(r fuzzworkRow) {
+		packaged[r.int("typeID")] = r.float("volume")
+	})
+	if err != nil {
+		return err
+	}
+	err = convertFuzzworkTable(csvDir, outDir, "invTypes", true, "types", func(r fuzzworkRow) any {
+		var marketGroupID *int64
+		if id := r.int("marketGroupID"); id > 0 {
+			marketGroupID = &id
+		}
+		typeID := r.int("typeID")
+		return struct {
+			Key            int64     `json:"_key"`
+			Name           jsonlName `json:"name"`
+			GroupID        int64     `json:"groupID"`
+			Volume         float64   `json:"volume"`
+			PackagedVolume float64   `json:"packagedVolume,omitempty"`
+			PortionSize    int64     `json:"portionSize"`
+			Published      bool      `json:"published"`
+			MarketGroupID  *int64    `json:"marketGroupID,omitempty"`
+		}{
+			typeID, jsonlName{r.str("typeName")}, r.int("groupID"), r.float("volume"), packaged[typeID],
+			r.int("portionSize"), r.bool("published"), marketGroupID,
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	contraband := make(map[int64]bool)
+	err = convertFuzzworkTable(csvDir, outDir, "invContrabandTypes", false, "contrabandTypes", func(r fuzzworkRow) any {
+		typeID := r.int("typeID")
+		if typeID <= 0 || contraband[typeID] {
+			return nil
+		}
+		contraband[typeID] = true
+		return struct {
+			Key int64 `json:"_key"`
+		}{typeID}
+	})
+	if err != nil {
+		return err
+	}
+	err = convertFuzzworkTable(csvDir, outDir, "staStations", true, "npcStations", func(r fuzzworkRow) any {
+		return struct {
+			Key           int64 `json:"_key"`
+			SolarSystemID int64 `json:"solarSystemID"`
+			OwnerID       int64 `json:"ownerID"`
+		}{r.int("stationID"), r.int("solarSystemID"), r.int("corporationID")}
+	})
+	if err != nil {
+		return err
+	}
+	err = convertFuzzworkTable(csvDir, outDir, "crpNPCCorporations", false, "npcCorporations", func(r fuzzworkRow) any {
+		return struct {
+			Key       int64 `json:"_key"`
+			FactionID int64 `json:"factionID,omitempty"`
+		}{r.int("corporationID"), r.int("factionID")}
+	})
+	if err != nil {
+		return err
+	}
+	if err := convertFuzzworkBlueprints(csvDir, outDir); err != nil {
+		return err
+	}
+	if err := convertFuzzworkTypeMaterials(csvDir, outDir); err != nil {
+		return err
+	}
+	if err := convertFuzzworkSchematics(csvDir, outDir); err != nil {
+		return err
+	}
+	logger.Info("SDE", fmt.Sprintf("Converted Fuzzwork tables in %s", time.Since(start).Round(time.Millisecond)))
+	return nil
+}
+
+// convertFuzzworkBlueprints joins the industryActivity* tables into one
+// blueprints record per blueprint type.
+func convertFuzzworkBlueprints(csvDir, outDir string) error {
+	blueprints := make(map[int64]map[string]*jsonlActivity)
+	activity := func(r fuzzworkRow) *jsonlActivity {
+		name, ok := fuzzworkActivities[r.int("activityID")]
+		if !ok {
+			return nil
+		}
+		bpID := r.int("typeID")
+		if blueprints[bpID] == nil {
+			blueprints[bpID] = make(map[string]*jsonlActivity)
+		}
+		a := blueprints[bpID][name]
+		if a == nil {
+			a = &jsonlActivity{}
+			blueprints[bpID][name] = a
+		}
+		return a
+	}
+	probabilities := make(map[[3]int64]float64)
+	steps := []struct {
+		table string
+		fn    func(fuzzworkRow)
+	}{
+		{"industryActivity", func(r fuzzworkRow) {
+			if a := activity(r); a != nil {
+				a.Time = int32(r.int("time"))
+			}
+		}},
+		{"industryActivityMaterials", func(r fuzzworkRow) {
+			if a := activity(r); a != nil {
+				a.Materials = append(a.Materials, jsonlMaterial{TypeID: int32(r.int("materialTypeID")), Quantity: int32(r.int("quantity"))})
+			}
+		}},
+		{"industryActivityProbabilities", func(r fuzzworkRow) {
+			probabilities[[3]int64{r.int("typeID"), r.int("activityID"), r.int("productTypeID")}] = r.float("probability")
+		}},
+		{"industryActivityProducts", func(r fuzzworkRow) {
+			if a := activity(r); a != nil {
+				key := [3]int64{r.int("typeID"), r.int("activityID"), r.int("productTypeID")}
+				a.Products = append(a.Products, jsonlMaterial{
+					TypeID:      int32(r.int("productTypeID")),
+					Quantity:    int32(r.int("quantity")),
+					Probability: probabilities[key],
+				})
+			}
+		}},
+		{"industryActivitySkills", func(r fuzzworkRow) {
+			if a := activity(r); a != nil {
+				a.Skills = append(a.Skills, jsonlSkill{TypeID: int32(r.int("skillID")), Level: int32(r.int("level"))})
+			}
+		}},
+	}
+	for _, step := range steps {
+		if err := readFuzzworkCSV(csvDir, step.table, false, step.fn); err != nil {
+			return err
+		}
+	}
+
+	w, err := createJSONL(outDir, "blueprints")
+	if err != nil {
+		return err
+	}
+	for _, bpID := range sortedKeys(blueprints) {
+		rec := struct {
+			Key        int64                     `json:"_key"`
+			Activities map[string]*jsonlActivity `json:"activities"`
+		}{bpID, blueprints[bpID]}
+		if err = w.write(rec); err != nil {
+			break
+		}
+	}
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// convertFuzzworkTypeMaterials groups invTypeMaterials into one
+// typeMaterials record per reprocessable type.
+func convertFuzzworkTypeMaterials(csvDir, outDir string) error {
+	materials := make(map[int64][]jsonlMaterial)
+	err := readFuzzworkCSV(csvDir, "invTypeMaterials", false, func(r fuzzworkRow) {
+		typeID := r.int("typeID")
+		materials[typeID] = append(materials[typeID], jsonlMaterial{TypeID: int32(r.int("materialTypeID")), Quantity: int32(r.int("quantity"))})
+	})
+	if err != nil {
+		return err
+	}
+	w, err := createJSONL(outDir, "typeMaterials")
+	if err != nil {
+		return err
+	}
+	for _, typeID := range sortedKeys(materials) {
+		rec := struct {
+			Key       int64           `json:"_key"`
+			Materials []jsonlMaterial `json:"materials"`
+		}{typeID, materials[typeID]}
+		if err = w.write(rec); err != nil {
+			break
+		}
+	}
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// convertFuzzworkSchematics joins the planetSchematics* tables into one
+// planetSchematics record per schematic.
+func convertFuzzworkSchematics(csvDir, outDir string) error {
+	type schematicType struct {
+		Key      int64 `json:"_key"`
+		IsInput  bool  `json:"isInput"`
+		Quantity int64 `json:"quantity"`
+	}
+	type schematic struct {
+		Key       int64           `json:"_key"`
+		CycleTime int64           `json:"cycleTime"`
+		Name      jsonlName       `json:"name"`
+		Pins      []int64         `json:"pins,omitempty"`
+		Types     []schematicType `json:"types"`
+	}
+	schematics := make(map[int64]*schematic)
+	err := readFuzzworkCSV(csvDir, "planetSchematics", false, func(r fuzzworkRow) {
+		id := r.int("schematicID")
+		schematics[id] = &schematic{Key: id, CycleTime: r.int("cycleTime"), Name: jsonlName{r.str("schematicName")}}
+	})
+	if err != nil {
+		return err
+	}
+	err = readFuzzworkCSV(csvDir, "planetSchematicsTypeMap", false, func(r fuzzworkRow) {
+		if s := schematics[r.int("schematicID")]; s != nil {
+			s.Types = append(s.Types, schematicType{Key: r.int("typeID"), IsInput: r.bool("isInput"), Quantity: r.int("quantity")})
+		}
+	})
+	if err != nil {
+		return err
+	}
+	err = readFuzzworkCSV(csvDir, "planetSchematicsPinMap", false, func(r fuzzworkRow) {
+		if s := schematics[r.int("schematicID")]; s != nil {
+			s.Pins = append(s.Pins, r.int("pinTypeID"))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	w, err := createJSONL(outDir, "planetSchematics")
+	if err != nil {
+		return err
+	}
+	for _, id := range sortedKeys(schematics) {
+		if err = w.write(schematics[id]); err != nil {
+			break
+		}
+	}
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func sortedKeys[V any](m map[int64]V) []int64 {
+	keys := make([]int64, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,125 @@
+package sde
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFuzzworkFixture(t *testing.T, dir string, tables map[string]string) {
+	t.Helper()
+	for name, body := range tables {
+		if err := os.WriteFile(filepath.Join(dir, name+".csv"), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func fuzzworkFixtureTables() map[string]string {
+	return map[string]string{
+		"mapRegions":                    "regionID,regionName,x,y,z\n10000002,The Forge,0,0,0\n",
+		"mapSolarSystems":               "regionID,constellationID,solarSystemID,solarSystemName,x,y,z,security\n10000002,20000020,30000142,Jita,1,2,3,0.9459\n10000002,20000020,30000144,Perimeter,4,5,6,0.9\n",
+		"mapSolarSystemJumps":           "fromRegionID,fromConstellationID,fromSolarSystemID,toSolarSystemID,toConstellationID,toRegionID\n10000002,20000020,30000142,30000144,20000020,10000002\n10000002,20000020,30000144,30000142,20000020,10000002\n",
+		"invGroups":                     "groupID,categoryID,groupName,iconID,published\n18,4,Mineral,None,1\n28,6,Industrial,None,1\n",
+		"invTypes":                      "typeID,groupID,typeName,description,mass,volume,capacity,portionSize,raceID,basePrice,published,marketGroupID,iconID\n34,18,Tritanium,\"A mineral, quite common\",0,0.01,0,1,None,2,1,1857,None\n648,28,Badger,,0,250000,3900,1,1,0,1,1614,None\n1000,18,Widget Blueprint,,0,0.01,0,1,None,0,1,None,None\n",
+		"invVolumes":                    "typeID,volume\n648,20000\n",
+		"staStations":                   "stationID,security,stationTypeID,corporationID,solarSystemID,constellationID,regionID,stationName\n60003760,0.9459,1531,1000035,30000142,20000020,10000002,Jita IV - Moon 4\n",
+		"crpNPCCorporations":            "corporationID,size,extent,solarSystemID,factionID\n1000035,H,G,30000142,500001\n",
+		"invContrabandTypes":            "factionID,typeID,standingLoss\n500001,34,0.1\n500002,34,0.1\n",
+		"industryActivity":              "typeID,activityID,time\n1000,1,60\n1000,8,120\n",
+		"industryActivityMaterials":     "typeID,activityID,materialTypeID,quantity\n1000,1,34,10\n1000,8,20410,2\n",
+		"industryActivityProducts":      "typeID,activityID,productTypeID,quantity\n1000,1,1001,1\n1000,8,1002,1\n",
+		"industryActivityProbabilities": "typeID,activityID,productTypeID,probability\n1000,8,1002,0.34\n",
+		"industryActivitySkills":        "typeID,activityID,skillID,level\n1000,1,3380,1\n",
+		"invTypeMaterials":              "typeID,materialTypeID,quantity\n1230,34,400\n",
+		"planetSchematics":              "schematicID,schematicName,cycleTime\n65,Water,1800\n",
+		"planetSchematicsTypeMap":       "schematicID,typeID,quantity,isInput\n65,2268,3000,1\n65,3645,20,0\n",
+		"planetSchematicsPinMap":        "schematicID,pinTypeID\n65,2469\n",
+	}
+}
+
+func TestLoadFromFuzzworkTables(t *testing.T) {
+	csvDir := t.TempDir()
+	writeFuzzworkFixture(t, csvDir, fuzzworkFixtureTables())
+
+	src, err := ParseSource("fuzzwork:" + csvDir)
+	if err != nil || !src.Fuzzwork || src.Path != csvDir {
+		t.Fatalf("ParseSource = %+v, %v", src, err)
+	}
+	data, err := LoadFrom(t.TempDir(), LoadOptions{Source: src})
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if data.RegionByName["the forge"] != 10000002 {
+		t.Fatalf("regions = %+v", data.Regions)
+	}
+	jita := data.Systems[30000142]
+	if jita == nil || jita.Security != 0.9459 || jita.RegionID != 10000002 || jita.Z != 3 {
+		t.Fatalf("Jita = %+v", jita)
+	}
+	if got := data.Universe.Adj[30000142]; len(got) != 1 || got[0] != 30000144 {
+		t.Fatalf("Jita gates = %v", got)
+	}
+	trit := data.Types[34]
+	if trit == nil || trit.Name != "Tritanium" || trit.CategoryID != 4 || !trit.IsContraband {
+		t.Fatalf("Tritanium = %+v", trit)
+	}
+	if badger := data.Types[648]; badger == nil || badger.Volume != 20000 {
+		t.Fatalf("Badger should use its packaged volume: %+v", badger)
+	}
+	if _, ok := data.Types[1000]; ok {
+		t.Fatalf("types without a market group should be skipped")
+	}
+	if st := data.Stations[60003760]; st == nil || st.SystemID != 30000142 || st.OwnerID != 1000035 {
+		t.Fatalf("station = %+v", st)
+	}
+	if data.CorporationFactions[1000035] != 500001 {
+		t.Fatalf("corporation factions = %v", data.CorporationFactions)
+	}
+
+	bp := data.Industry.Blueprints[1000]
+	if bp == nil || bp.ProductTypeID != 1001 || bp.Time != 60 || len(bp.Materials) != 1 {
+		t.Fatalf("blueprint = %+v", bp)
+	}
+	inv := bp.Activities["invention"]
+	if inv == nil || len(inv.Products) != 1 || inv.Products[0].Probability != 0.34 {
+		t.Fatalf("invention = %+v", inv)
+	}
+	if skills := bp.Activities["manufacturing"].Skills; len(skills) != 1 || skills[0].Level != 1 {
+		t.Fatalf("manufacturing skills = %+v", skills)
+	}
+	if rm := data.Industry.Reprocessing[1230]; rm == nil || len(rm.Yields) != 1 || rm.Yields[0].Quantity != 400 {
+		t.Fatalf("reprocessing = %+v", rm)
+	}
+	if len(data.Industry.PlanetSchematics) != 1 {
+		t.Fatalf("planet schematics = %+v", data.Industry.PlanetSchematics)
+	}
+}
+
+func TestUpdateFuzzworkReplacesChangedTables(t *testing.T) {
+	csvDir := t.TempDir()
+	tables := fuzzworkFixtureTables()
+	writeFuzzworkFixture(t, csvDir, tables)
+	src := Source{Path: csvDir, Fuzzwork: true}
+	dataDir := t.TempDir()
+	if _, err := LoadFrom(dataDir, LoadOptions{Source: src}); err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+
+	tables["invTypes"] += "35,18,Pyerite,,0,0.01,0,1,None,2,1,1857,None\n"
+	writeFuzzworkFixture(t, csvDir, tables)
+	res, err := Update(dataDir, src, nil)
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if len(res.Changed) != 1 || res.Changed[0] != "types.jsonl" || res.Unchanged == 0 {
+		t.Fatalf("result = %+v, want only types.jsonl changed", res)
+	}
+	data, err := LoadFrom(dataDir, LoadOptions{Source: src})
+	if err != nil {
+		t.Fatalf("LoadFrom after update: %v", err)
+	}
+	if data.Types[35] == nil {
+		t.Fatalf("updated type missing")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sync"
 
 	"eve-flipper/internal/logger"
 )
@@ -93,6 +94,35 @@ func NewIndustryData() *IndustryData {
 	}
 }
 
+// IndustryData returns the industry tables, parsing them on first use when
+// the SDE was loaded with LazyIndustry. Nil if they failed to load.
+func (d *Data) IndustryData() *IndustryData {
+	if d.Industry != nil || d.lazyIndustry == nil {
+		return d.Industry
+	}
+	return d.lazyIndustry.get(d)
+}
+
+// lazyIndustry parses the industry tables once, on first use.
+type lazyIndustry struct {
+	dir      string
+	once     sync.Once
+	industry *IndustryData
+}
+
+func (l *lazyIndustry) get(d *Data) *IndustryData {
+	l.once.Do(func() {
+		logger.Info("SDE", "Loading industry data...")
+		industry, err := d.LoadIndustry(l.dir)
+		if err != nil {
+			logger.Error("SDE", fmt.Sprintf("Load industry: %v", err))
+			return
+		}
+		l.industry = industry
+	})
+	return l.industry
+}
+
 // LoadIndustry loads industry-related data from the SDE.
 func (d *Data) LoadIndustry(extractDir string) (*IndustryData, error) {
 	ind := NewIndustryData()
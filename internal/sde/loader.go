@@ -35,13 +35,22 @@ type Data struct {
 	Regions      map[int32]*Region      // regionID -> region
 	RegionByName map[string]int32       // lowercase name -> regionID
 	Types        map[int32]*ItemType    // typeID -> type
+	TypeByName   map[string]int32       // lowercase name -> typeID (market types)
 	Groups       map[int32]*ItemGroup   // groupID -> group metadata
+	MarketGroups map[int32]*MarketGroup // marketGroupID -> market browse tree node
 	Contraband   map[int32]bool         // typeID -> listed in contrabandTypes
 	Stations     map[int64]*Station     // stationID -> station
 	Universe     *graph.Universe
 	Industry     *IndustryData // blueprints, reprocessing, etc.
 
+	// CorporationFactions maps NPC corporation ID -> faction ID (station owners).
+	CorporationFactions map[int32]int32
+
 	shipTypesMissingPackagedVolume map[int32]bool
+
+	// Industry tables loaded on first use (see LoadOptions.LazyIndustry).
+	// A pointer so shallow copies of Data share one load.
+	lazyIndustry *lazyIndustry
 }
 
 // Region represents an EVE region from the SDE.
@@ -56,6 +65,8 @@ type SolarSystem struct {
 	Name     string
 	RegionID int32
 	Security float64 // 0.0 (null) to 1.0 (highsec); highsec >= 0.45
+	// Galactic coordinates in meters (used for jump-drive distances).
+	X, Y, Z float64
 }
 
 // ItemType represents a market-tradeable item type from the SDE.
@@ -67,6 +78,8 @@ type ItemType struct {
 	CategoryID   int32   // item category (6=Ships, 7=Modules, 20=Implants, etc.)
 	IsRig        bool    // derived from group metadata
 	IsContraband bool    // listed in contrabandTypes
+	PortionSize  int32   // units per reprocessing batch (1 for most items, 100 for ores)
+	MarketGroup  int32   // leaf node of the market browse tree
 }
 
 // ItemGroup represents group-level SDE metadata used for type classification.
@@ -77,19 +90,74 @@ type ItemGroup struct {
 	IsRig      bool
 }
 
+// MarketGroup is a node of the in-game market browse tree.
+type MarketGroup struct {
+	ID       int32
+	Name     string
+	ParentID int32 // 0 for top-level groups
+	HasTypes bool  // leaf groups list types directly
+}
+
 // Station represents an NPC station from the SDE.
 type Station struct {
 	ID       int64
 	Name     string
 	SystemID int32
+	OwnerID  int32 // NPC corporation that owns the station
 }
 
 // Load downloads (if needed) and parses the SDE.
 func Load(dataDir string) (*Data, error) {
-	zipPath := filepath.Join(dataDir, "sde.zip")
-	extractDir := filepath.Join(dataDir, "sde")
+	return LoadWithProgress(dataDir, nil)
+}
+
+// LoadWithProgress is Load reporting download, extract and parse progress
+// to progress (may be nil).
+func LoadWithProgress(dataDir string, progress ProgressFunc) (*Data, error) {
+	return LoadFrom(dataDir, LoadOptions{Progress: progress})
+}
+
+// LoadOptions configures LoadFrom.
+type LoadOptions struct {
+	Source   Source       // zero value: DefaultSource
+	Progress ProgressFunc // optional
+
+	// LazyIndustry defers parsing blueprints, reprocessing and PI schematics
+	// (the largest tables) until IndustryData is first called, for machines
+	// short on memory that never open the industry tools.
+	LazyIndustry bool
+
+	// RouteHubs get precomputed jump tables to every system (see
+	// graph.Universe.PrecomputeRoutes), for all-space and highsec routing.
+	RouteHubs []int32
+	// RouteHierarchy also builds contraction hierarchies, speeding up jump
+	// queries between any two systems for a few MB and a fraction of a
+	// second at load.
+	RouteHierarchy bool
+}
+
+// precomputedRouteSecurities are the route security filters scans use most:
+// all space and highsec only.
+var precomputedRouteSecurities = []float64{0, 0.45}
+
+// LoadFrom is LoadWithProgress with a configurable source and options.
+func LoadFrom(dataDir string, opts LoadOptions) (*Data, error) {
+	src := opts.Source
+	if src == (Source{}) {
+		src = DefaultSource()
+	}
+	zipPath, extractDir := src.locations(dataDir)
+	p := newProgressReporter(opts.Progress)
 
-	if err := ensureSDEExtracted(dataDir, zipPath, extractDir); err != nil {
+	if src.Fuzzwork {
+		if err := ensureFuzzworkConverted(src, extractDir, p); err != nil {
+			return nil, err
+		}
+	} else if zipPath == "" {
+		if err := validateSDEExtractDir(extractDir); err != nil {
+			return nil, fmt.Errorf("SDE directory %s: %w", extractDir, err)
+		}
+	} else if err := ensureSDEExtracted(zipPath, extractDir, src.URL, p); err != nil {
 		return nil, err
 	}
 
@@ -99,31 +167,45 @@ func Load(dataDir string) (*Data, error) {
 		Regions:      make(map[int32]*Region),
 		RegionByName: make(map[string]int32),
 		Types:        make(map[int32]*ItemType),
+		TypeByName:   make(map[string]int32),
 		Groups:       make(map[int32]*ItemGroup),
+		MarketGroups: make(map[int32]*MarketGroup),
 		Contraband:   make(map[int32]bool),
 		Stations:     make(map[int64]*Station),
 		Universe:     graph.NewUniverse(),
 
+		CorporationFactions: make(map[int32]int32),
+
 		shipTypesMissingPackagedVolume: make(map[int32]bool),
 	}
 
 	logger.Info("SDE", "Loading regions...")
+	p.stage("regions")
 	if err := data.loadRegions(extractDir); err != nil {
 		return nil, err
 	}
 	logger.Info("SDE", "Loading solar systems...")
+	p.stage("systems")
 	if err := data.loadSystems(extractDir); err != nil {
 		return nil, err
 	}
 	logger.Info("SDE", "Loading item types...")
+	p.stage("types")
 	if err := data.loadTypes(extractDir); err != nil {
 		return nil, err
 	}
 	logger.Info("SDE", "Loading stations...")
+	p.stage("stations")
 	if err := data.loadStations(extractDir); err != nil {
 		return nil, err
 	}
+	logger.Info("SDE", "Loading NPC corporations...")
+	p.stage("corporations")
+	if err := data.loadNPCCorporations(extractDir); err != nil {
+		return nil, err
+	}
 	logger.Info("SDE", "Loading stargates...")
+	p.stage("stargates")
 	if err := data.loadStargates(extractDir); err != nil {
 		return nil, err
 	}
@@ -138,22 +220,41 @@ func Load(dataDir string) (*Data, error) {
 	}
 
 	// Load industry data (blueprints, reprocessing)
-	logger.Info("SDE", "Loading industry data...")
-	industry, err := data.LoadIndustry(extractDir)
-	if err != nil {
-		return nil, fmt.Errorf("load industry: %w", err)
+	if opts.LazyIndustry {
+		data.lazyIndustry = &lazyIndustry{dir: extractDir}
+	} else {
+		logger.Info("SDE", "Loading industry data...")
+		p.stage("industry")
+		industry, err := data.LoadIndustry(extractDir)
+		if err != nil {
+			return nil, fmt.Errorf("load industry: %w", err)
+		}
+		data.Industry = industry
 	}
-	data.Industry = industry
 
 	// Initialize BFS path cache now that the universe graph is fully loaded.
+	data.Universe.Compact()
 	data.Universe.InitPathCache()
+	if len(opts.RouteHubs) > 0 || opts.RouteHierarchy {
+		p.stage("routes")
+		start := time.Now()
+		data.Universe.PrecomputeRoutes(graph.RoutePrecompute{
+			Hubs:          opts.RouteHubs,
+			MinSecurities: precomputedRouteSecurities,
+			Hierarchy:     opts.RouteHierarchy,
+		})
+		logger.Info("SDE", fmt.Sprintf("Precomputed routes for %d hubs in %s", len(opts.RouteHubs), time.Since(start).Round(time.Millisecond)))
+	}
 
 	logger.Section("SDE Statistics")
 	logger.Stats("Regions", len(data.Regions))
 	logger.Stats("Systems", len(data.Systems))
 	logger.Stats("Item types", len(data.Types))
 	logger.Stats("Stations", len(data.Stations))
-	logger.Stats("Blueprints", len(data.Industry.Blueprints))
+	if data.Industry != nil {
+		logger.Stats("Blueprints", len(data.Industry.Blueprints))
+	}
+	p.done()
 	return data, nil
 }
 
@@ -166,16 +267,19 @@ var requiredSDEJSONLFiles = []string{
 	"mapStargates",
 }
 
-func ensureSDEExtracted(dataDir, zipPath, extractDir string) error {
+// ensureSDEExtracted extracts zipPath into extractDir unless a valid extract
+// exists, downloading the zip from url first when it is missing.
+func ensureSDEExtracted(zipPath, extractDir, url string, p *progressReporter) error {
 	if err := validateSDEExtractDir(extractDir); err == nil {
 		return nil
 	} else if !os.IsNotExist(err) {
 		logger.Warn("SDE", fmt.Sprintf("Existing SDE extract is incomplete: %v", err))
 	}
 
-	if _, err := os.Stat(zipPath); os.IsNotExist(err) {
+	if _, err := os.Stat(zipPath); os.IsNotExist(err) && url != "" {
 		logger.Info("SDE", "Downloading data... first launch can take a few minutes")
-		if err := downloadFile(zipPath, sdeURL); err != nil {
+		p.stage("download")
+		if err := downloadFile(zipPath, url, p); err != nil {
 			return fmt.Errorf("download SDE: %w", err)
 		}
 	} else if err != nil {
@@ -183,7 +287,8 @@ func ensureSDEExtracted(dataDir, zipPath, extractDir string) error {
 	}
 
 	logger.Info("SDE", "Extracting data...")
-	return extractSDEAtomically(zipPath, extractDir)
+	p.stage("extract")
+	return extractSDEAtomically(zipPath, extractDir, p)
 }
 
 func validateSDEExtractDir(extractDir string) error {
@@ -206,7 +311,7 @@ func validateSDEExtractDir(extractDir string) error {
 	return nil
 }
 
-func extractSDEAtomically(zipPath, extractDir string) error {
+func extractSDEAtomically(zipPath, extractDir string, p *progressReporter) error {
 	parent := filepath.Dir(extractDir)
 	if err := os.MkdirAll(parent, 0755); err != nil {
 		return err
@@ -217,7 +322,7 @@ func extractSDEAtomically(zipPath, extractDir string) error {
 	}
 	defer os.RemoveAll(tempDir)
 
-	if err := extractZip(zipPath, tempDir); err != nil {
+	if err := extractZip(zipPath, tempDir, p); err != nil {
 		return fmt.Errorf("extract SDE: %w", err)
 	}
 	if err := validateSDEExtractDir(tempDir); err != nil {
@@ -279,6 +384,11 @@ func (d *Data) loadSystems(dir string) error {
 			RegionID       int32             `json:"regionID"`
 			Security       float64           `json:"security"`
 			SecurityStatus float64           `json:"securityStatus"` // alternate SDE field name
+			Position       *struct {
+				X float64 `json:"x"`
+				Y float64 `json:"y"`
+				Z float64 `json:"z"`
+			} `json:"position"`
 		}
 		if err := json.Unmarshal(raw, &s); err != nil {
 			return err
@@ -291,13 +401,18 @@ func (d *Data) loadSystems(dir string) error {
 		if sec == 0 && s.SecurityStatus != 0 {
 			sec = s.SecurityStatus
 		}
-		d.Systems[s.Key] = &SolarSystem{
+		sys := &SolarSystem{
 			ID: s.Key, Name: name, RegionID: s.RegionID, Security: sec,
 		}
+		d.Systems[s.Key] = sys
 		d.SystemByName[strings.ToLower(name)] = s.Key
 		d.SystemNames = append(d.SystemNames, name)
 		d.Universe.SetRegion(s.Key, s.RegionID)
 		d.Universe.SetSecurity(s.Key, sec)
+		if s.Position != nil {
+			sys.X, sys.Y, sys.Z = s.Position.X, s.Position.Y, s.Position.Z
+			d.Universe.SetPosition(s.Key, s.Position.X, s.Position.Y, s.Position.Z)
+		}
 		return nil
 	})
 }
@@ -346,6 +461,30 @@ func (d *Data) loadTypes(dir string) error {
 		return fmt.Errorf("load groups: %w", err)
 	}
 
+	_, err = readOptionalJSONL(dir, "marketGroups", func(raw json.RawMessage) error {
+		var g struct {
+			Key           int32             `json:"_key"`
+			Name          map[string]string `json:"name"`
+			ParentGroupID int32             `json:"parentGroupID"`
+			HasTypes      bool              `json:"hasTypes"`
+		}
+		if err := json.Unmarshal(raw, &g); err != nil {
+			return err
+		}
+		if g.Key > 0 {
+			d.MarketGroups[g.Key] = &MarketGroup{
+				ID:       g.Key,
+				Name:     strings.TrimSpace(g.Name["en"]),
+				ParentID: g.ParentGroupID,
+				HasTypes: g.HasTypes,
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("load market groups: %w", err)
+	}
+
 	// Then load types
 	return readJSONL(dir, "types", func(raw json.RawMessage) error {
 		var t struct {
@@ -356,6 +495,7 @@ func (d *Data) loadTypes(dir string) error {
 			Published      bool              `json:"published"`
 			MarketGroupID  *int32            `json:"marketGroupID"`
 			GroupID        int32             `json:"groupID"`
+			PortionSize    int32             `json:"portionSize"`
 		}
 		if err := json.Unmarshal(raw, &t); err != nil {
 			return err
@@ -383,11 +523,33 @@ func (d *Data) loadTypes(dir string) error {
 			CategoryID:   categoryID,
 			IsRig:        groupRig[t.GroupID],
 			IsContraband: d.Contraband[t.Key],
+			PortionSize:  max(t.PortionSize, 1),
+			MarketGroup:  *t.MarketGroupID,
 		}
+		d.TypeByName[strings.ToLower(name)] = t.Key
 		return nil
 	})
 }
 
+// TypeIDByName resolves a market type by its English name, ignoring case
+// and surrounding whitespace.
+func (d *Data) TypeIDByName(name string) (int32, bool) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if key == "" {
+		return 0, false
+	}
+	if d.TypeByName != nil {
+		id, ok := d.TypeByName[key]
+		return id, ok
+	}
+	for id, t := range d.Types {
+		if strings.ToLower(t.Name) == key {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
 func isRigGroupName(categoryID int32, groupName string) bool {
 	if categoryID != 7 {
 		return false
@@ -404,16 +566,36 @@ func (d *Data) loadStations(dir string) error {
 		var s struct {
 			Key           int64 `json:"_key"`
 			SolarSystemID int32 `json:"solarSystemID"`
+			OwnerID       int32 `json:"ownerID"`
 		}
 		if err := json.Unmarshal(raw, &s); err != nil {
 			return err
 		}
 		// Name will be resolved later from system name
 		d.Stations[s.Key] = &Station{
-			ID: s.Key, Name: "", SystemID: s.SolarSystemID,
+			ID: s.Key, Name: "", SystemID: s.SolarSystemID, OwnerID: s.OwnerID,
+		}
+		return nil
+	})
+}
+
+// loadNPCCorporations reads the faction of each NPC corporation, used to
+// apply faction standings to broker fees at stations they own.
+func (d *Data) loadNPCCorporations(dir string) error {
+	_, err := readOptionalJSONL(dir, "npcCorporations", func(raw json.RawMessage) error {
+		var c struct {
+			Key       int32 `json:"_key"`
+			FactionID int32 `json:"factionID"`
+		}
+		if err := json.Unmarshal(raw, &c); err != nil {
+			return err
+		}
+		if c.FactionID > 0 {
+			d.CorporationFactions[c.Key] = c.FactionID
 		}
 		return nil
 	})
+	return err
 }
 
 func (d *Data) loadStargates(dir string) error {
@@ -498,7 +680,7 @@ func readJSONLFile(filePath string, fn func(json.RawMessage) error) error {
 	return scanner.Err()
 }
 
-func downloadFile(dst, url string) error {
+func downloadFile(dst, url string, p *progressReporter) error {
 	os.MkdirAll(filepath.Dir(dst), 0755)
 
 	client := &http.Client{
@@ -524,7 +706,7 @@ func downloadFile(dst, url string) error {
 			logger.Warn("SDE", fmt.Sprintf("Retrying SDE download in %s (attempt %d/%d)", delay, attempt, sdeDownloadAttempts))
 			time.Sleep(delay)
 		}
-		if err := downloadFileOnce(client, dst, url); err != nil {
+		if err := downloadFileOnce(client, dst, url, p); err != nil {
 			lastErr = err
 			logger.Warn("SDE", fmt.Sprintf("SDE download attempt %d/%d failed: %v", attempt, sdeDownloadAttempts, err))
 			continue
@@ -537,7 +719,7 @@ func downloadFile(dst, url string) error {
 	return fmt.Errorf("%w; retry later or download the SDE manually into %s", lastErr, dst)
 }
 
-func downloadFileOnce(client *http.Client, dst, url string) error {
+func downloadFileOnce(client *http.Client, dst, url string, p *progressReporter) error {
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
 		return err
@@ -559,7 +741,7 @@ func downloadFileOnce(client *http.Client, dst, url string) error {
 	if err != nil {
 		return err
 	}
-	_, copyErr := io.Copy(f, resp.Body)
+	_, copyErr := io.Copy(f, io.TeeReader(resp.Body, &progressWriter{p: p, stage: "download", total: resp.ContentLength}))
 	closeErr := f.Close()
 	if copyErr != nil {
 		_ = os.Remove(tmp)
@@ -576,7 +758,7 @@ func downloadFileOnce(client *http.Client, dst, url string) error {
 	return nil
 }
 
-func extractZip(src, dst string) error {
+func extractZip(src, dst string, p *progressReporter) error {
 	r, err := zip.OpenReader(src)
 	if err != nil {
 		return err
@@ -589,6 +771,12 @@ func extractZip(src, dst string) error {
 		return fmt.Errorf("resolve extract dir: %w", err)
 	}
 
+	var total int64
+	for _, f := range r.File {
+		total += int64(f.UncompressedSize64)
+	}
+	pw := &progressWriter{p: p, stage: "extract", total: total}
+
 	for _, f := range r.File {
 		fpath := filepath.Join(dstAbs, f.Name)
 
@@ -611,7 +799,7 @@ func extractZip(src, dst string) error {
 			rc.Close()
 			return err
 		}
-		_, err = io.Copy(out, rc)
+		_, err = io.Copy(io.MultiWriter(out, pw), rc)
 		rc.Close()
 		out.Close()
 		if err != nil {
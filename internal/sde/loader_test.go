@@ -23,7 +23,7 @@ func TestEnsureSDEExtractedQuarantinesIncompleteExtract(t *testing.T) {
 		t.Fatalf("write sde zip: %v", err)
 	}
 
-	if err := ensureSDEExtracted(dataDir, zipPath, extractDir); err != nil {
+	if err := ensureSDEExtracted(zipPath, extractDir, "", nil); err != nil {
 		t.Fatalf("ensure SDE extracted: %v", err)
 	}
 	if err := validateSDEExtractDir(extractDir); err != nil {
@@ -80,3 +80,68 @@ func writeMinimalSDEZip(path string) error {
 	}
 	return zw.Close()
 }
+
+func TestEnsureSDEExtractedReportsExtractProgress(t *testing.T) {
+	dataDir := t.TempDir()
+	zipPath := filepath.Join(dataDir, "sde.zip")
+	if err := writeMinimalSDEZip(zipPath); err != nil {
+		t.Fatalf("write sde zip: %v", err)
+	}
+
+	var events []LoadProgress
+	p := newProgressReporter(func(lp LoadProgress) { events = append(events, lp) })
+	if err := ensureSDEExtracted(zipPath, filepath.Join(dataDir, "sde"), "", p); err != nil {
+		t.Fatalf("ensure SDE extracted: %v", err)
+	}
+	if len(events) == 0 || events[0].Stage != "extract" || events[0].Percent != 55 {
+		t.Fatalf("first event = %+v, want extract stage at 55%%", events)
+	}
+	last := events[len(events)-1]
+	if last.Stage != "extract" || last.BytesTotal <= 0 || last.Percent > 70 {
+		t.Fatalf("last event = %+v", last)
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].Percent < events[i-1].Percent {
+			t.Fatalf("progress went backwards: %+v", events)
+		}
+	}
+}
+
+func TestLoadFromLazyIndustry(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"mapRegions.jsonl":      `{"_key":10000002,"name":{"en":"The Forge"}}`,
+		"mapSolarSystems.jsonl": `{"_key":30000142,"name":{"en":"Jita"},"regionID":10000002,"security":0.9}` + "\n" + `{"_key":30000144,"name":{"en":"Perimeter"},"regionID":10000002,"security":0.9}`,
+		"mapStargates.jsonl":    `{"_key":1,"solarSystemID":30000142,"destination":{"solarSystemID":30000144}}` + "\n" + `{"_key":2,"solarSystemID":30000144,"destination":{"solarSystemID":30000142}}`,
+		"npcStations.jsonl":     `{"_key":60003760,"solarSystemID":30000142,"ownerID":1000035}`,
+		"groups.jsonl":          `{"_key":18,"name":{"en":"Mineral"},"categoryID":4}`,
+		"types.jsonl":           `{"_key":34,"name":{"en":"Tritanium"},"volume":0.01,"published":true,"marketGroupID":1857,"groupID":18}` + "\n" + `{"_key":1000,"name":{"en":"Widget Blueprint"},"published":true,"groupID":18}`,
+		"blueprints.jsonl":      `{"_key":1000,"blueprintTypeID":1000,"activities":{"manufacturing":{"time":60,"materials":[{"typeID":34,"quantity":10}],"products":[{"typeID":1001,"quantity":1}]}}}`,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := LoadFrom(t.TempDir(), LoadOptions{Source: Source{Path: dir}, LazyIndustry: true})
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if data.Industry != nil {
+		t.Fatalf("industry tables loaded eagerly")
+	}
+	if got := data.Universe.Adj[30000142]; len(got) != 1 || cap(got) != 1 {
+		t.Fatalf("adjacency not compacted: %v (cap %d)", got, cap(got))
+	}
+
+	// Shallow copies (as route planning makes) share the lazy load.
+	copied := *data
+	industry := copied.IndustryData()
+	if industry == nil || len(industry.Blueprints) != 1 {
+		t.Fatalf("IndustryData = %+v", industry)
+	}
+	if data.IndustryData() != industry {
+		t.Fatalf("copies of Data loaded industry tables twice")
+	}
+}
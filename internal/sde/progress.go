@@ -0,0 +1,127 @@
+package sde
+
+import (
+	"sync"
+	"time"
+)
+
+// LoadProgress is one progress update of LoadWithProgress.
+type LoadProgress struct {
+	Stage        string  `json:"stage"`         // download, extract, regions, systems, types, stations, corporations, stargates, industry, routes, done
+	Percent      float64 `json:"percent"`       // overall 0-100
+	StagePercent float64 `json:"stage_percent"` // 0-100 within Stage
+	BytesDone    int64   `json:"bytes_done,omitempty"`
+	BytesTotal   int64   `json:"bytes_total,omitempty"`
+}
+
+// ProgressFunc receives load progress. It is called from the loading
+// goroutine and must not block.
+type ProgressFunc func(LoadProgress)
+
+// loadStage is a step of Load and its share of the overall percentage.
+// Download and extract dominate on first run; parsing is a few seconds.
+type loadStage struct {
+	name       string
+	start, end float64
+}
+
+var loadStages = []loadStage{
+	{"download", 0, 55},
+	{"extract", 55, 70},
+	{"regions", 70, 71},
+	{"systems", 71, 74},
+	{"types", 74, 85},
+	{"stations", 85, 87},
+	{"corporations", 87, 88},
+	{"stargates", 88, 90},
+	{"industry", 90, 98},
+	{"routes", 98, 100},
+}
+
+// progressReporter maps stage-local progress onto the overall percentage and
+// throttles byte-level updates.
+type progressReporter struct {
+	fn ProgressFunc
+
+	mu       sync.Mutex
+	lastSent time.Time
+	lastPct  float64
+}
+
+const progressMinInterval = 250 * time.Millisecond
+
+func newProgressReporter(fn ProgressFunc) *progressReporter {
+	return &progressReporter{fn: fn}
+}
+
+// stage reports the start of a stage.
+func (p *progressReporter) stage(name string) {
+	p.report(name, 0, 0, 0, true)
+}
+
+// bytes reports byte progress within a stage; total <= 0 means unknown.
+func (p *progressReporter) bytes(name string, done, total int64) {
+	frac := 0.0
+	if total > 0 {
+		frac = float64(done) / float64(total)
+	}
+	p.report(name, frac, done, total, false)
+}
+
+// done reports completion.
+func (p *progressReporter) done() {
+	if p == nil || p.fn == nil {
+		return
+	}
+	p.fn(LoadProgress{Stage: "done", Percent: 100, StagePercent: 100})
+}
+
+func (p *progressReporter) report(name string, frac float64, done, total int64, force bool) {
+	if p == nil || p.fn == nil {
+		return
+	}
+	if frac < 0 {
+		frac = 0
+	} else if frac > 1 {
+		frac = 1
+	}
+	start, end := 0.0, 100.0
+	for _, st := range loadStages {
+		if st.name == name {
+			start, end = st.start, st.end
+			break
+		}
+	}
+	pct := start + (end-start)*frac
+
+	p.mu.Lock()
+	now := time.Now()
+	if !force && now.Sub(p.lastSent) < progressMinInterval && pct-p.lastPct < 1 {
+		p.mu.Unlock()
+		return
+	}
+	p.lastSent, p.lastPct = now, pct
+	p.mu.Unlock()
+
+	p.fn(LoadProgress{
+		Stage:        name,
+		Percent:      pct,
+		StagePercent: frac * 100,
+		BytesDone:    done,
+		BytesTotal:   total,
+	})
+}
+
+// progressWriter counts bytes written towards a stage's byte progress.
+type progressWriter struct {
+	p     *progressReporter
+	stage string
+	done  int64
+	total int64
+}
+
+func (w *progressWriter) Write(b []byte) (int, error) {
+	w.done += int64(len(b))
+	w.p.bytes(w.stage, w.done, w.total)
+	return len(b), nil
+}
@@ -0,0 +1,103 @@
+package sde
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Source is where the SDE is loaded from: the official JSONL export (or a
+// mirror of it) by URL, or a local JSONL zip or extracted directory. With
+// Fuzzwork, URL or Path holds Fuzzwork's CSV tables instead, which are
+// converted to JSONL (see convertFuzzwork).
+type Source struct {
+	URL      string
+	Path     string
+	Fuzzwork bool
+}
+
+// DefaultSource is CCP's official JSONL export.
+func DefaultSource() Source {
+	return Source{URL: sdeURL}
+}
+
+// ParseSource parses an SDE source setting:
+//
+//	""  / "ccp" / "official"   CCP's JSONL export (default)
+//	"fuzzwork"                 Fuzzwork's CSV tables
+//	fuzzwork:http(s)://...     a mirror of the Fuzzwork tables
+//	fuzzwork:<dir>             a local directory of Fuzzwork CSV (or .csv.bz2) tables
+//	http(s)://...              a mirror of the JSONL zip
+//	anything else              a local JSONL zip or extracted directory
+func ParseSource(s string) (Source, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "", "ccp", "official":
+		return DefaultSource(), nil
+	case "fuzzwork":
+		return Source{URL: fuzzworkURL, Fuzzwork: true}, nil
+	}
+	if len(s) > len("fuzzwork:") && strings.EqualFold(s[:len("fuzzwork:")], "fuzzwork:") {
+		src, err := ParseSource(s[len("fuzzwork:"):])
+		if err != nil {
+			return Source{}, err
+		}
+		if src == DefaultSource() || src.Fuzzwork || (src.Path != "" && !src.isDir()) {
+			return Source{}, fmt.Errorf("fuzzwork source must be a URL or a directory of CSV tables")
+		}
+		src.Fuzzwork = true
+		return src, nil
+	}
+	lower := strings.ToLower(s)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+		return Source{URL: s}, nil
+	}
+	abs, err := filepath.Abs(s)
+	if err != nil {
+		return Source{}, fmt.Errorf("resolve SDE path: %w", err)
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return Source{}, fmt.Errorf("SDE path: %w", err)
+	}
+	return Source{Path: abs}, nil
+}
+
+func (s Source) String() string {
+	loc := s.URL
+	if s.Path != "" {
+		loc = s.Path
+	}
+	if s.Fuzzwork {
+		return "fuzzwork:" + loc
+	}
+	return loc
+}
+
+// isDir reports whether the source is an already extracted directory.
+func (s Source) isDir() bool {
+	if s.Path == "" || s.Fuzzwork {
+		return false
+	}
+	info, err := os.Stat(s.Path)
+	return err == nil && info.IsDir()
+}
+
+// locations returns the zip and extract paths Load uses for the source.
+// A directory source is read in place; a local zip is extracted into the
+// data directory like a downloaded one. Fuzzwork sources have no zip and
+// are converted into their own directory.
+func (s Source) locations(dataDir string) (zipPath, extractDir string) {
+	if s.Fuzzwork {
+		return "", filepath.Join(dataDir, "sde-fuzzwork")
+	}
+	zipPath = filepath.Join(dataDir, "sde.zip")
+	extractDir = filepath.Join(dataDir, "sde")
+	if s.isDir() {
+		return "", s.Path
+	}
+	if s.Path != "" {
+		zipPath = s.Path
+	}
+	return zipPath, extractDir
+}
@@ -0,0 +1,281 @@
+package sde
+
+import (
+	"archive/zip"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"eve-flipper/internal/logger"
+)
+
+// UpdateResult summarises an SDE update.
+type UpdateResult struct {
+	Source          string   `json:"source"`
+	Changed         []string `json:"changed"`
+	Unchanged       int      `json:"unchanged"`
+	BytesDownloaded int64    `json:"bytes_downloaded"`
+	Delta           bool     `json:"delta"` // false when the whole archive was downloaded
+}
+
+// Update refreshes the extracted SDE in dataDir from src, replacing only the
+// files whose CRC32 differs from the archive's. For URL sources the archive
+// is read with HTTP range requests, so only the central directory and the
+// changed entries (usually types and blueprints after a patch) are
+// downloaded; servers without range support fall back to a full download.
+// Directory sources are read in place and need no update; Fuzzwork sources
+// are converted again (see updateFuzzwork).
+func Update(dataDir string, src Source, progress ProgressFunc) (*UpdateResult, error) {
+	zipPath, extractDir := src.locations(dataDir)
+	res := &UpdateResult{Source: src.String(), Changed: []string{}}
+	p := newProgressReporter(progress)
+	if src.Fuzzwork {
+		if err := updateFuzzwork(src, extractDir, res, p); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+	if zipPath == "" {
+		return res, nil
+	}
+	if err := validateSDEExtractDir(extractDir); err != nil {
+		// Nothing to diff against: a normal extract is the update.
+		if err := ensureSDEExtracted(zipPath, extractDir, src.URL, p); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	if src.URL == "" {
+		f, err := zip.OpenReader(zipPath)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return res, applyZipDelta(&f.Reader, extractDir, res, p)
+	}
+
+	client := &http.Client{Timeout: sdeDownloadTimeout}
+	ra, err := newHTTPRangeReader(client, src.URL)
+	if err != nil {
+		logger.Warn("SDE", fmt.Sprintf("Range requests unavailable (%v), downloading full SDE", err))
+		p.stage("download")
+		if err := downloadFile(zipPath, src.URL, p); err != nil {
+			return nil, fmt.Errorf("download SDE: %w", err)
+		}
+		if info, err := os.Stat(zipPath); err == nil {
+			res.BytesDownloaded = info.Size()
+		}
+		f, err := zip.OpenReader(zipPath)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return res, applyZipDelta(&f.Reader, extractDir, res, p)
+	}
+
+	zr, err := zip.NewReader(ra, ra.size)
+	if err != nil {
+		return nil, fmt.Errorf("read remote SDE directory: %w", err)
+	}
+	res.Delta = true
+	err = applyZipDelta(zr, extractDir, res, p)
+	res.BytesDownloaded = ra.downloaded()
+	if err != nil {
+		return nil, err
+	}
+	// The local zip is now older than the extract; drop it so a broken
+	// extract is re-downloaded rather than rolled back.
+	if len(res.Changed) > 0 {
+		_ = os.Remove(zipPath)
+	}
+	return res, nil
+}
+
+// applyZipDelta writes the entries of zr whose CRC32 differs from the file
+// in extractDir. Each file is written to a temp file and renamed over the
+// old one, so an interrupted update leaves every file either old or new.
+func applyZipDelta(zr *zip.Reader, extractDir string, res *UpdateResult, p *progressReporter) error {
+	dstAbs, err := filepath.Abs(extractDir)
+	if err != nil {
+		return fmt.Errorf("resolve extract dir: %w", err)
+	}
+	var changed []*zip.File
+	var total int64
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		fpath := filepath.Join(dstAbs, f.Name)
+		if rel, err := filepath.Rel(dstAbs, fpath); err != nil || strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("illegal zip entry path: %s", f.Name)
+		}
+		if crc, err := fileCRC32(fpath); err == nil && crc == f.CRC32 {
+			res.Unchanged++
+			continue
+		}
+		changed = append(changed, f)
+		total += int64(f.UncompressedSize64)
+	}
+
+	p.stage("download")
+	pw := &progressWriter{p: p, stage: "download", total: total}
+	for _, f := range changed {
+		fpath := filepath.Join(dstAbs, f.Name)
+		if err := replaceFromZip(f, fpath, pw); err != nil {
+			return fmt.Errorf("update %s: %w", f.Name, err)
+		}
+		res.Changed = append(res.Changed, f.Name)
+	}
+	logger.Info("SDE", fmt.Sprintf("SDE update: %d file(s) changed, %d unchanged", len(res.Changed), res.Unchanged))
+	return nil
+}
+
+func replaceFromZip(f *zip.File, dst string, pw io.Writer) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".sde-update-*")
+	if err != nil {
+		return err
+	}
+	// zip.File verifies the CRC32 when the entry is read to EOF.
+	_, copyErr := io.Copy(io.MultiWriter(tmp, pw), rc)
+	closeErr := tmp.Close()
+	if copyErr == nil {
+		copyErr = closeErr
+	}
+	if copyErr != nil {
+		_ = os.Remove(tmp.Name())
+		return copyErr
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+func fileCRC32(path string) (uint32, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	h := crc32.NewIEEE()
+	if _, err := io.Copy(h, f); err != nil {
+		return 0, err
+	}
+	return h.Sum32(), nil
+}
+
+// httpRangeBlock is the read-ahead size of httpRangeReader; zip decoding
+// reads a few KB at a time, which would otherwise be one request each.
+const httpRangeBlock = 1 << 20
+
+// httpRangeReader is an io.ReaderAt over a remote file using HTTP range
+// requests, caching the last fetched block.
+type httpRangeReader struct {
+	client *http.Client
+	url    string
+	size   int64
+
+	mu         sync.Mutex
+	blockStart int64
+	block      []byte
+	fetched    int64
+}
+
+// newHTTPRangeReader probes url for range support and its size.
+func newHTTPRangeReader(client *http.Client, url string) (*httpRangeReader, error) {
+	r := &httpRangeReader{client: client, url: url}
+	resp, err := r.get(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	// Content-Range: bytes 0-0/123456
+	cr := resp.Header.Get("Content-Range")
+	slash := strings.LastIndexByte(cr, '/')
+	if slash < 0 {
+		return nil, fmt.Errorf("missing Content-Range")
+	}
+	size, err := strconv.ParseInt(cr[slash+1:], 10, 64)
+	if err != nil || size <= 0 {
+		return nil, fmt.Errorf("unknown size in Content-Range %q", cr)
+	}
+	r.size = size
+	return r, nil
+}
+
+func (r *httpRangeReader) get(start, end int64) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "eve-flipper/1.0 (github.com)")
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, fmt.Errorf("range request: HTTP %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func (r *httpRangeReader) ReadAt(b []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for n < len(b) {
+		pos := off + int64(n)
+		if pos >= r.size {
+			return n, io.EOF
+		}
+		if pos < r.blockStart || pos >= r.blockStart+int64(len(r.block)) {
+			if err := r.fetch(pos, int64(len(b)-n)); err != nil {
+				return n, err
+			}
+		}
+		n += copy(b[n:], r.block[pos-r.blockStart:])
+	}
+	return n, nil
+}
+
+// fetch loads the block starting at pos, at least want bytes long.
+func (r *httpRangeReader) fetch(pos, want int64) error {
+	length := max(want, httpRangeBlock)
+	end := min(pos+length, r.size) - 1
+	resp, err := r.get(pos, end)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	block := make([]byte, end-pos+1)
+	if _, err := io.ReadFull(resp.Body, block); err != nil {
+		return err
+	}
+	r.blockStart, r.block = pos, block
+	r.fetched += int64(len(block))
+	return nil
+}
+
+func (r *httpRangeReader) downloaded() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.fetched
+}
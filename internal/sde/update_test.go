@@ -0,0 +1,135 @@
+package sde
+
+import (
+	"archive/zip"
+	"bytes"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// buildSDEZip returns a stored (uncompressed) zip of files.
+func buildSDEZip(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func sdeFixtureFiles() map[string][]byte {
+	files := map[string][]byte{}
+	for _, name := range requiredSDEJSONLFiles {
+		files[name+".jsonl"] = []byte("{}\n")
+	}
+	// A large unchanged file, so a delta download is visibly smaller.
+	big := make([]byte, 3<<20)
+	rand.New(rand.NewSource(1)).Read(big)
+	files["mapStargates.jsonl"] = big
+	return files
+}
+
+func TestUpdateDownloadsOnlyChangedEntries(t *testing.T) {
+	dataDir := t.TempDir()
+	v1 := sdeFixtureFiles()
+	if err := extractZipBytes(t, buildSDEZip(t, v1), filepath.Join(dataDir, "sde")); err != nil {
+		t.Fatal(err)
+	}
+
+	v2 := sdeFixtureFiles()
+	v2["types.jsonl"] = []byte(`{"_key":34,"name":{"en":"Tritanium"}}` + "\n")
+	archive := buildSDEZip(t, v2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "sde.zip", time.Time{}, bytes.NewReader(archive))
+	}))
+	defer srv.Close()
+
+	res, err := Update(dataDir, Source{URL: srv.URL}, nil)
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if !res.Delta || len(res.Changed) != 1 || res.Changed[0] != "types.jsonl" {
+		t.Fatalf("result = %+v, want delta update of types.jsonl", res)
+	}
+	if res.BytesDownloaded >= int64(len(archive)) {
+		t.Fatalf("downloaded %d of %d bytes, want a partial download", res.BytesDownloaded, len(archive))
+	}
+	got, _ := os.ReadFile(filepath.Join(dataDir, "sde", "types.jsonl"))
+	if !bytes.Equal(got, v2["types.jsonl"]) {
+		t.Fatalf("types.jsonl not updated: %q", got)
+	}
+
+	// Second run finds nothing to do.
+	res, err = Update(dataDir, Source{URL: srv.URL}, nil)
+	if err != nil || len(res.Changed) != 0 || res.Unchanged != len(v2) {
+		t.Fatalf("second update = %+v, %v", res, err)
+	}
+}
+
+func TestUpdateFallsBackWithoutRangeSupport(t *testing.T) {
+	dataDir := t.TempDir()
+	if err := extractZipBytes(t, buildSDEZip(t, sdeFixtureFiles()), filepath.Join(dataDir, "sde")); err != nil {
+		t.Fatal(err)
+	}
+	v2 := sdeFixtureFiles()
+	v2["groups.jsonl"] = []byte(`{"_key":18}` + "\n")
+	archive := buildSDEZip(t, v2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer srv.Close()
+
+	res, err := Update(dataDir, Source{URL: srv.URL}, nil)
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if res.Delta || len(res.Changed) != 1 || res.Changed[0] != "groups.jsonl" {
+		t.Fatalf("result = %+v, want full download changing groups.jsonl", res)
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	if src, err := ParseSource(""); err != nil || src != DefaultSource() {
+		t.Fatalf("empty source = %+v, %v", src, err)
+	}
+	if src, err := ParseSource("https://mirror.example/sde.zip"); err != nil || src.URL != "https://mirror.example/sde.zip" {
+		t.Fatalf("url source = %+v, %v", src, err)
+	}
+	dir := t.TempDir()
+	if src, err := ParseSource(dir); err != nil || src.Path != dir || !src.isDir() {
+		t.Fatalf("dir source = %+v, %v", src, err)
+	}
+	if _, err := ParseSource(filepath.Join(dir, "missing.zip")); err == nil {
+		t.Fatalf("missing path should fail")
+	}
+	if src, err := ParseSource("fuzzwork"); err != nil || !src.Fuzzwork || src.URL != fuzzworkURL {
+		t.Fatalf("fuzzwork source = %+v, %v", src, err)
+	}
+	if _, err := ParseSource("fuzzwork:" + filepath.Join(dir, "missing")); err == nil {
+		t.Fatalf("missing fuzzwork path should fail")
+	}
+}
+
+func extractZipBytes(t *testing.T, archive []byte, dst string) error {
+	t.Helper()
+	zipPath := filepath.Join(t.TempDir(), "sde.zip")
+	if err := os.WriteFile(zipPath, archive, 0644); err != nil {
+		return err
+	}
+	return extractZip(zipPath, dst, nil)
+}
@@ -22,7 +22,6 @@ import (
 	"eve-flipper/internal/db"
 	"eve-flipper/internal/esi"
 	"eve-flipper/internal/logger"
-	"eve-flipper/internal/sde"
 	"eve-flipper/internal/telemetry"
 )
 
@@ -109,6 +108,11 @@ func main() {
 
 	port := flag.Int("port", 13370, "HTTP server port")
 	host := flag.String("host", "127.0.0.1", "Host to bind to (use 0.0.0.0 to allow LAN/remote access)")
+	sdeSource := flag.String("sde-source", envOrDefault("SDE_SOURCE", "ccp"), "SDE source: ccp, fuzzwork (or fuzzwork:<url or CSV directory>), a JSONL zip URL, or a local JSONL zip/directory")
+	sdeUpdate := flag.Bool("sde-update", os.Getenv("SDE_UPDATE") == "1", "Download changed SDE files before loading")
+	lowMemory := flag.Bool("low-memory", os.Getenv("LOW_MEMORY") == "1", "Load industry SDE tables on first use to reduce memory")
+	trustedProxies := flag.String("trusted-proxies", os.Getenv("TRUSTED_PROXIES"), "Reverse proxy IPs/CIDRs (comma separated) whose forwarded client IP headers are used for rate limiting")
+	apiMode := flag.Bool("api-mode", os.Getenv("API_MODE") == "1", "Accept API keys for external tools; non-local and reverse-proxied clients then need one")
 	flag.Parse()
 
 	logger.Banner(version)
@@ -158,8 +162,8 @@ func main() {
 			ClientSecret: clientSecret,
 			CallbackURL:  callbackURL,
 			Scopes: "esi-location.read_location.v1 esi-skills.read_skills.v1 esi-skills.read_skillqueue.v1 esi-wallet.read_character_wallet.v1 esi-assets.read_assets.v1 esi-characters.read_blueprints.v1 esi-industry.read_character_jobs.v1 esi-planets.manage_planets.v1 esi-markets.structure_markets.v1 esi-universe.read_structures.v1 esi-markets.read_character_orders.v1" +
-				" esi-characters.read_corporation_roles.v1 esi-wallet.read_corporation_wallets.v1 esi-corporations.read_corporation_membership.v1 esi-industry.read_corporation_jobs.v1 esi-industry.read_corporation_mining.v1 esi-markets.read_corporation_orders.v1 esi-corporations.read_divisions.v1 esi-corporations.track_members.v1" +
-				" esi-ui.open_window.v1 esi-ui.write_waypoint.v1",
+				" esi-characters.read_corporation_roles.v1 esi-wallet.read_corporation_wallets.v1 esi-corporations.read_corporation_membership.v1 esi-industry.read_corporation_jobs.v1 esi-industry.read_corporation_mining.v1 esi-markets.read_corporation_orders.v1 esi-corporations.read_divisions.v1 esi-corporations.track_members.v1 esi-contracts.read_corporation_contracts.v1 esi-corporations.read_structures.v1 esi-assets.read_corporation_assets.v1" +
+				" esi-ui.open_window.v1 esi-ui.write_waypoint.v1 esi-characters.read_standings.v1 esi-search.search_structures.v1",
 		}
 	} else {
 		logger.Info("SSO", "EVE SSO not configured (missing ESI_CLIENT_ID / ESI_CLIENT_SECRET)")
@@ -170,13 +174,18 @@ func main() {
 	srv := api.NewServer(cfg, esiClient, database, ssoConfig, sessions)
 	srv.SetAppVersion(version)
 	srv.SetAppFlavor("web")
+	srv.SetBindHost(*host)
+	srv.SetTrustedProxies(*trustedProxies)
+	srv.SetAPIMode(*apiMode)
 	srv.SetTelemetry(telemetry.NewFromEnv())
+	go srv.CheckCorpESICompat() // report corp ESI route drift / deprecations
 
 	// Load SDE in background
 	go func() {
-		data, err := sde.Load(dataDir)
+		data, err := loadSDE(dataDir, *sdeSource, *sdeUpdate, *lowMemory, srv.SetSDELoadProgress)
 		if err != nil {
 			logger.Error("SDE", fmt.Sprintf("Load failed: %v", err))
+			srv.SetSDELoadError(err)
 			return
 		}
 		missingShipVolumes := prepareShipPackagedVolumes(dataDir, data)
@@ -188,26 +197,16 @@ func main() {
 	// Combine API + embedded frontend into a single handler
 	apiHandler := srv.Handler()
 	frontendContent, _ := fs.Sub(frontendFS, "frontend/dist")
-	fileServer := http.FileServer(http.FS(frontendContent))
+	staticHandler := api.NewStaticHandler(frontendContent)
 
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// API routes
-		if strings.HasPrefix(r.URL.Path, "/api/") {
+		if strings.HasPrefix(r.URL.Path, "/api/") || r.URL.Path == "/metrics" {
 			apiHandler.ServeHTTP(w, r)
 			return
 		}
-		// Try static file, fall back to index.html (SPA)
-		path := strings.TrimPrefix(r.URL.Path, "/")
-		if path == "" {
-			path = "index.html"
-		}
-		if _, err := fs.Stat(frontendContent, path); err == nil {
-			fileServer.ServeHTTP(w, r)
-			return
-		}
-		// SPA fallback
-		r.URL.Path = "/"
-		fileServer.ServeHTTP(w, r)
+		// Static files with cache headers, SPA fallback to index.html
+		staticHandler.ServeHTTP(w, r)
 	})
 
 	addr := fmt.Sprintf("%s:%d", *host, *port)
@@ -8,6 +8,7 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"io/fs"
 	"net"
 	"net/http"
 	"net/http/httputil"
@@ -24,7 +25,6 @@ import (
 	"eve-flipper/internal/db"
 	"eve-flipper/internal/esi"
 	"eve-flipper/internal/logger"
-	"eve-flipper/internal/sde"
 	"eve-flipper/internal/telemetry"
 
 	"github.com/wailsapp/wails/v2"
@@ -88,8 +88,7 @@ func main() {
 		MinHeight:        600,
 		WindowStartState: options.Maximised,
 		AssetServer: &assetserver.Options{
-			Assets:  wailsFrontendFS,
-			Handler: newBackendProxy(backend.baseURL),
+			Handler: newWailsAssetHandler(backend.baseURL),
 		},
 		BackgroundColour: &options.RGBA{R: 13, G: 13, B: 13, A: 255},
 		DisableResize:    false,
@@ -182,8 +181,8 @@ func startBackend(host string, preferredPort int) (*backendRuntime, error) {
 			ClientSecret: clientSecret,
 			CallbackURL:  callbackURL,
 			Scopes: "esi-location.read_location.v1 esi-skills.read_skills.v1 esi-skills.read_skillqueue.v1 esi-wallet.read_character_wallet.v1 esi-assets.read_assets.v1 esi-characters.read_blueprints.v1 esi-industry.read_character_jobs.v1 esi-planets.manage_planets.v1 esi-markets.structure_markets.v1 esi-universe.read_structures.v1 esi-markets.read_character_orders.v1" +
-				" esi-characters.read_corporation_roles.v1 esi-wallet.read_corporation_wallets.v1 esi-corporations.read_corporation_membership.v1 esi-industry.read_corporation_jobs.v1 esi-industry.read_corporation_mining.v1 esi-markets.read_corporation_orders.v1 esi-corporations.read_divisions.v1 esi-corporations.track_members.v1" +
-				" esi-ui.open_window.v1 esi-ui.write_waypoint.v1",
+				" esi-characters.read_corporation_roles.v1 esi-wallet.read_corporation_wallets.v1 esi-corporations.read_corporation_membership.v1 esi-industry.read_corporation_jobs.v1 esi-industry.read_corporation_mining.v1 esi-markets.read_corporation_orders.v1 esi-corporations.read_divisions.v1 esi-corporations.track_members.v1 esi-contracts.read_corporation_contracts.v1 esi-corporations.read_structures.v1 esi-assets.read_corporation_assets.v1" +
+				" esi-ui.open_window.v1 esi-ui.write_waypoint.v1 esi-characters.read_standings.v1 esi-search.search_structures.v1",
 		}
 	} else {
 		logger.Info("SSO", "EVE SSO not configured (missing ESI_CLIENT_ID / ESI_CLIENT_SECRET)")
@@ -195,12 +194,14 @@ func startBackend(host string, preferredPort int) (*backendRuntime, error) {
 	srv.SetAppVersion(version)
 	srv.SetAppFlavor("desktop")
 	srv.SetTelemetry(telemetry.NewFromEnv())
+	go srv.CheckCorpESICompat() // report corp ESI route drift / deprecations
 
 	// Load SDE in background.
 	go func() {
-		data, err := sde.Load(dataDir)
+		data, err := loadSDE(dataDir, envOrDefault("SDE_SOURCE", "ccp"), os.Getenv("SDE_UPDATE") == "1", os.Getenv("LOW_MEMORY") == "1", srv.SetSDELoadProgress)
 		if err != nil {
 			logger.Error("SDE", fmt.Sprintf("Load failed: %v", err))
+			srv.SetSDELoadError(err)
 			return
 		}
 		missingShipVolumes := prepareShipPackagedVolumes(dataDir, data)
@@ -284,6 +285,22 @@ func listenOnPreferredOrFreePort(host string, preferredPort int) (net.Listener,
 	return fallback, fallbackPort, nil
 }
 
+// newWailsAssetHandler serves the embedded frontend through the same static
+// handler as the web build, so the webview gets cache headers and the
+// precompressed encodings, and proxies API calls to the backend.
+func newWailsAssetHandler(baseURL string) http.Handler {
+	frontendContent, _ := fs.Sub(wailsFrontendFS, "frontend/dist")
+	staticHandler := api.NewStaticHandler(frontendContent)
+	proxy := newBackendProxy(baseURL)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/") || r.URL.Path == "/metrics" {
+			proxy.ServeHTTP(w, r)
+			return
+		}
+		staticHandler.ServeHTTP(w, r)
+	})
+}
+
 func newBackendProxy(baseURL string) http.Handler {
 	target, err := url.Parse(baseURL)
 	if err != nil {
@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"fmt"
+	"runtime/debug"
 	"time"
 
+	"eve-flipper/internal/engine"
 	"eve-flipper/internal/esi"
 	"eve-flipper/internal/logger"
 	"eve-flipper/internal/sde"
@@ -73,3 +75,45 @@ func refreshShipPackagedVolumesInBackground(dataDir string, missing []int32, esi
 		}
 	}()
 }
+
+// loadSDE loads the SDE from source (see sde.ParseSource), first applying a
+// delta update of changed files when update is set. Jump tables from the
+// major trade hubs are precomputed; lowMemory defers the industry tables
+// until the industry tools are used and skips the contraction hierarchies.
+func loadSDE(dataDir, source string, update, lowMemory bool, progress sde.ProgressFunc) (*sde.Data, error) {
+	src, err := sde.ParseSource(source)
+	if err != nil {
+		return nil, err
+	}
+	if src != sde.DefaultSource() {
+		logger.Info("SDE", "Using SDE source "+src.String())
+	}
+	if update {
+		result, err := sde.Update(dataDir, src, progress)
+		if err != nil {
+			// A stale SDE is still usable; load what is on disk.
+			logger.Warn("SDE", fmt.Sprintf("SDE update failed: %v", err))
+		} else if len(result.Changed) > 0 {
+			logger.Success("SDE", fmt.Sprintf("SDE updated: %d file(s), %.1f MB downloaded",
+				len(result.Changed), float64(result.BytesDownloaded)/(1<<20)))
+		}
+	}
+	hubs := make([]int32, 0, len(engine.MajorTradeHubs))
+	for _, hub := range engine.MajorTradeHubs {
+		hubs = append(hubs, hub.SystemID)
+	}
+	data, err := sde.LoadFrom(dataDir, sde.LoadOptions{
+		Source:         src,
+		Progress:       progress,
+		LazyIndustry:   lowMemory,
+		RouteHubs:      hubs,
+		RouteHierarchy: !lowMemory,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Parsing leaves hundreds of MB of garbage; hand it back to the OS
+	// rather than keeping it as heap the game client could use.
+	debug.FreeOSMemory()
+	return data, nil
+}